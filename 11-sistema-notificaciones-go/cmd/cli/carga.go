@@ -0,0 +1,366 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// intervaloGeneracion es cada cuánto el generador emite las solicitudes adeudadas según la tasa
+const intervaloGeneracion = 10 * time.Millisecond
+
+// opcionesCarga son los parámetros del comando carga
+type opcionesCarga struct {
+	url          string
+	claveAPI     string
+	rps          float64
+	duracion     time.Duration
+	concurrencia int
+	tipos        string
+	prioridades  string
+	usuarios     string
+	distribucion string
+	plantilla    string
+	simular      bool
+	timeout      time.Duration
+	semilla      int64
+	salidaJSON   bool
+}
+
+// opcionPonderada es un valor de una mezcla con su peso relativo
+type opcionPonderada struct {
+	valor string
+	peso  int
+}
+
+// mezcla sortea valores según sus pesos ("email=6,sms=3,push=1")
+type mezcla struct {
+	opciones []opcionPonderada
+	total    int
+}
+
+// trabajoCarga es una solicitud generada, con el instante en que debía salir. La latencia se
+// mide desde ese instante para no ocultar la espera cuando el servidor se atrasa.
+type trabajoCarga struct {
+	programado time.Time
+	tipo       string
+	cuerpo     []byte
+}
+
+// ReporteCarga resume una corrida
+type ReporteCarga struct {
+	Corrida     string           `json:"corrida"`
+	Duracion    float64          `json:"duracion_segundos"`
+	RPSObjetivo float64          `json:"rps_objetivo"`
+	RPSLogrado  float64          `json:"rps_logrado"`
+	Solicitudes int              `json:"solicitudes"`
+	Exitosas    int              `json:"exitosas"`
+	Errores     map[string]int   `json:"errores"`
+	Omitidas    int              `json:"omitidas"`
+	PorTipo     map[string]int   `json:"por_tipo"`
+	LatenciaMS  map[string]int64 `json:"latencia_ms"`
+}
+
+// registroCarga acumula los resultados de los trabajadores
+type registroCarga struct {
+	mu          sync.Mutex
+	latencias   []time.Duration
+	exitosas    int
+	errores     map[string]int
+	porTipo     map[string]int
+	solicitudes int
+}
+
+func ejecutarCarga(args []string) error {
+	var o opcionesCarga
+	banderas := flag.NewFlagSet("carga", flag.ContinueOnError)
+	banderas.StringVar(&o.url, "url", "http://localhost:8080", "URL base del entorno")
+	banderas.StringVar(&o.claveAPI, "clave", os.Getenv("NOTIFICACIONES_CLAVE_API"), "clave de API (X-API-Key); por defecto NOTIFICACIONES_CLAVE_API")
+	banderas.Float64Var(&o.rps, "rps", 50, "solicitudes por segundo")
+	banderas.DurationVar(&o.duracion, "duracion", time.Minute, "duración de la corrida")
+	banderas.IntVar(&o.concurrencia, "concurrencia", 64, "solicitudes simultáneas como máximo")
+	banderas.StringVar(&o.tipos, "tipos", "email=6,sms=2,push=1,websocket=1", "mezcla de tipos con sus pesos")
+	banderas.StringVar(&o.prioridades, "prioridades", "normal=80,alta=15,critica=3,baja=2", "mezcla de prioridades con sus pesos")
+	banderas.StringVar(&o.usuarios, "usuarios", "1-1000", "rango de IDs de usuario destinatarios")
+	banderas.StringVar(&o.distribucion, "distribucion", "zipf", "reparto de destinatarios: zipf (pocos usuarios concentran el tráfico) o uniforme")
+	banderas.StringVar(&o.plantilla, "plantilla", "", "plantilla a renderizar en lugar de título y mensaje")
+	banderas.BoolVar(&o.simular, "simular", false, "usar el envío simulado (/notificaciones/simular): recorre el flujo sin enviar ni guardar")
+	banderas.DurationVar(&o.timeout, "timeout", 10*time.Second, "plazo de cada solicitud")
+	banderas.Int64Var(&o.semilla, "semilla", time.Now().UnixNano(), "semilla del sorteo, para repetir una corrida")
+	banderas.BoolVar(&o.salidaJSON, "json", false, "emitir el reporte en JSON")
+	if err := banderas.Parse(args); err != nil {
+		return err
+	}
+
+	if o.rps <= 0 || o.duracion <= 0 || o.concurrencia <= 0 {
+		return fmt.Errorf("rps, duracion y concurrencia deben ser positivos")
+	}
+	tipos, err := parsearMezcla(o.tipos)
+	if err != nil {
+		return fmt.Errorf("tipos: %w", err)
+	}
+	prioridades, err := parsearMezcla(o.prioridades)
+	if err != nil {
+		return fmt.Errorf("prioridades: %w", err)
+	}
+	sortearUsuario, err := nuevoSorteoUsuarios(o.usuarios, o.distribucion, o.semilla)
+	if err != nil {
+		return err
+	}
+
+	ctx, detener := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer detener()
+	ctx, cancelar := context.WithTimeout(ctx, o.duracion)
+	defer cancelar()
+
+	destino := strings.TrimRight(o.url, "/") + "/api/v1/notificaciones"
+	if o.simular {
+		destino += "/simular"
+	}
+	corrida := strconv.FormatInt(time.Now().Unix(), 36)
+	cliente := &http.Client{
+		Timeout:   o.timeout,
+		Transport: &http.Transport{MaxIdleConnsPerHost: o.concurrencia, MaxConnsPerHost: o.concurrencia},
+	}
+	registro := &registroCarga{errores: make(map[string]int), porTipo: make(map[string]int)}
+
+	trabajos := make(chan trabajoCarga, o.concurrencia)
+	var trabajadores sync.WaitGroup
+	for i := 0; i < o.concurrencia; i++ {
+		trabajadores.Add(1)
+		go func() {
+			defer trabajadores.Done()
+			for trabajo := range trabajos {
+				registro.registrar(trabajo, enviarTrabajo(cliente, destino, o.claveAPI, trabajo))
+			}
+		}()
+	}
+
+	fmt.Fprintf(os.Stderr, "Corrida %s: %.0f rps durante %s contra %s\n", corrida, o.rps, o.duracion, destino)
+	azar := rand.New(rand.NewSource(o.semilla))
+	inicio := time.Now()
+	emitidas, omitidas := 0, 0
+	ticker := time.NewTicker(intervaloGeneracion)
+	progreso := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+	defer progreso.Stop()
+
+generar:
+	for {
+		select {
+		case <-ctx.Done():
+			break generar
+		case <-progreso.C:
+			fmt.Fprintf(os.Stderr, "  %s: %d emitidas, %d omitidas\n", time.Since(inicio).Round(time.Second), emitidas, omitidas)
+		case ahora := <-ticker.C:
+			adeudadas := int(o.rps*ahora.Sub(inicio).Seconds()) - emitidas - omitidas
+			for i := 0; i < adeudadas; i++ {
+				trabajo := trabajoCarga{programado: ahora, tipo: tipos.sortear(azar)}
+				trabajo.cuerpo = cuerpoCarga(corrida, emitidas+omitidas, trabajo.tipo, prioridades.sortear(azar), sortearUsuario(), o.plantilla)
+				select {
+				case trabajos <- trabajo:
+					emitidas++
+				default:
+					// Todos los trabajadores ocupados y la cola llena: el cliente no da abasto
+					omitidas++
+				}
+			}
+		}
+	}
+	close(trabajos)
+	trabajadores.Wait()
+
+	reporte := registro.reporte(corrida, time.Since(inicio), o.rps, omitidas)
+	if o.salidaJSON {
+		codificador := json.NewEncoder(os.Stdout)
+		codificador.SetIndent("", "  ")
+		return codificador.Encode(reporte)
+	}
+	imprimirReporte(reporte)
+	return nil
+}
+
+// enviarTrabajo hace la solicitud y retorna la clave de error, vacía si fue exitosa
+func enviarTrabajo(cliente *http.Client, destino, claveAPI string, trabajo trabajoCarga) string {
+	solicitud, err := http.NewRequest(http.MethodPost, destino, bytes.NewReader(trabajo.cuerpo))
+	if err != nil {
+		return "cliente"
+	}
+	solicitud.Header.Set("Content-Type", "application/json")
+	if claveAPI != "" {
+		solicitud.Header.Set("X-API-Key", claveAPI)
+	}
+	respuesta, err := cliente.Do(solicitud)
+	if err != nil {
+		return "red"
+	}
+	_, _ = io.Copy(io.Discard, respuesta.Body)
+	respuesta.Body.Close()
+	if respuesta.StatusCode >= 300 {
+		return strconv.Itoa(respuesta.StatusCode)
+	}
+	return ""
+}
+
+// cuerpoCarga arma una solicitud de envío; los metadatos identifican la corrida para poder
+// filtrar o limpiar después lo generado
+func cuerpoCarga(corrida string, secuencia int, tipo, prioridad string, usuarioID uint64, plantilla string) []byte {
+	solicitud := map[string]any{
+		"usuario_id": usuarioID,
+		"tipo":       tipo,
+		"prioridad":  prioridad,
+		"metadatos":  map[string]any{"carga": corrida, "secuencia": secuencia},
+	}
+	if plantilla != "" {
+		solicitud["plantilla"] = plantilla
+		solicitud["datos"] = map[string]any{"Nombre": fmt.Sprintf("Usuario %d", usuarioID), "Codigo": secuencia}
+	} else {
+		solicitud["titulo"] = fmt.Sprintf("Prueba de carga %s #%d", corrida, secuencia)
+		solicitud["mensaje"] = fmt.Sprintf("Mensaje de prueba de carga para el usuario %d (corrida %s).", usuarioID, corrida)
+	}
+	cuerpo, _ := json.Marshal(solicitud)
+	return cuerpo
+}
+
+func (r *registroCarga) registrar(trabajo trabajoCarga, errorClave string) {
+	latencia := time.Since(trabajo.programado)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.solicitudes++
+	r.porTipo[trabajo.tipo]++
+	if errorClave != "" {
+		r.errores[errorClave]++
+		return
+	}
+	r.exitosas++
+	r.latencias = append(r.latencias, latencia)
+}
+
+// reporte calcula los percentiles sobre las solicitudes exitosas
+func (r *registroCarga) reporte(corrida string, duracion time.Duration, rpsObjetivo float64, omitidas int) ReporteCarga {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sort.Slice(r.latencias, func(i, j int) bool { return r.latencias[i] < r.latencias[j] })
+	percentil := func(p float64) int64 {
+		if len(r.latencias) == 0 {
+			return 0
+		}
+		indice := int(p/100*float64(len(r.latencias))+0.5) - 1
+		indice = min(max(indice, 0), len(r.latencias)-1)
+		return r.latencias[indice].Milliseconds()
+	}
+
+	return ReporteCarga{
+		Corrida:     corrida,
+		Duracion:    duracion.Seconds(),
+		RPSObjetivo: rpsObjetivo,
+		RPSLogrado:  float64(r.solicitudes) / duracion.Seconds(),
+		Solicitudes: r.solicitudes,
+		Exitosas:    r.exitosas,
+		Errores:     r.errores,
+		Omitidas:    omitidas,
+		PorTipo:     r.porTipo,
+		LatenciaMS: map[string]int64{
+			"p50": percentil(50), "p90": percentil(90), "p95": percentil(95), "p99": percentil(99), "max": percentil(100),
+		},
+	}
+}
+
+func imprimirReporte(r ReporteCarga) {
+	fmt.Printf("\nCorrida %s (%.1fs)\n", r.Corrida, r.Duracion)
+	fmt.Printf("  Solicitudes:  %d (%.1f/s, objetivo %.1f/s)\n", r.Solicitudes, r.RPSLogrado, r.RPSObjetivo)
+	fmt.Printf("  Exitosas:     %d\n", r.Exitosas)
+	fmt.Printf("  Errores:      %s\n", formatearConteos(r.Errores))
+	if r.Omitidas > 0 {
+		fmt.Printf("  Omitidas:     %d (el cliente no dio abasto; aumente -concurrencia)\n", r.Omitidas)
+	}
+	fmt.Printf("  Por tipo:     %s\n", formatearConteos(r.PorTipo))
+	fmt.Printf("  Latencia ms:  p50=%d p90=%d p95=%d p99=%d max=%d\n",
+		r.LatenciaMS["p50"], r.LatenciaMS["p90"], r.LatenciaMS["p95"], r.LatenciaMS["p99"], r.LatenciaMS["max"])
+}
+
+func formatearConteos(conteos map[string]int) string {
+	if len(conteos) == 0 {
+		return "ninguno"
+	}
+	claves := make([]string, 0, len(conteos))
+	for clave := range conteos {
+		claves = append(claves, clave)
+	}
+	sort.Strings(claves)
+	partes := make([]string, len(claves))
+	for i, clave := range claves {
+		partes[i] = fmt.Sprintf("%s=%d", clave, conteos[clave])
+	}
+	return strings.Join(partes, " ")
+}
+
+// parsearMezcla interpreta "valor=peso,..."; un valor sin peso vale 1
+func parsearMezcla(valor string) (*mezcla, error) {
+	m := &mezcla{}
+	for _, parte := range strings.Split(valor, ",") {
+		nombre, pesoTexto, conPeso := strings.Cut(strings.TrimSpace(parte), "=")
+		if nombre == "" {
+			continue
+		}
+		peso := 1
+		if conPeso {
+			var err error
+			if peso, err = strconv.Atoi(pesoTexto); err != nil || peso < 0 {
+				return nil, fmt.Errorf("peso inválido para %s: %q", nombre, pesoTexto)
+			}
+		}
+		m.opciones = append(m.opciones, opcionPonderada{valor: nombre, peso: peso})
+		m.total += peso
+	}
+	if m.total == 0 {
+		return nil, fmt.Errorf("la mezcla %q no tiene pesos positivos", valor)
+	}
+	return m, nil
+}
+
+func (m *mezcla) sortear(azar *rand.Rand) string {
+	n := azar.Intn(m.total)
+	for _, opcion := range m.opciones {
+		if n < opcion.peso {
+			return opcion.valor
+		}
+		n -= opcion.peso
+	}
+	return m.opciones[len(m.opciones)-1].valor
+}
+
+// nuevoSorteoUsuarios retorna el sorteo de destinatarios en el rango "desde-hasta". Con zipf
+// unos pocos usuarios reciben la mayor parte del tráfico, como en producción.
+func nuevoSorteoUsuarios(rango, distribucion string, semilla int64) (func() uint64, error) {
+	desdeTexto, hastaTexto, _ := strings.Cut(rango, "-")
+	desde, errDesde := strconv.ParseUint(strings.TrimSpace(desdeTexto), 10, 64)
+	hasta, errHasta := strconv.ParseUint(strings.TrimSpace(hastaTexto), 10, 64)
+	if errDesde != nil || errHasta != nil || desde == 0 || hasta < desde {
+		return nil, fmt.Errorf("rango de usuarios inválido: %q (formato desde-hasta)", rango)
+	}
+
+	azar := rand.New(rand.NewSource(semilla + 1))
+	switch distribucion {
+	case "uniforme":
+		return func() uint64 { return desde + uint64(azar.Int63n(int64(hasta-desde+1))) }, nil
+	case "zipf":
+		zipf := rand.NewZipf(azar, 1.1, 1, hasta-desde)
+		return func() uint64 { return desde + zipf.Uint64() }, nil
+	default:
+		return nil, fmt.Errorf("distribución desconocida: %q", distribucion)
+	}
+}
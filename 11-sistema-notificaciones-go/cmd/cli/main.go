@@ -0,0 +1,60 @@
+// Comando notificaciones reúne herramientas de línea de comandos para operar el servicio.
+//
+//	notificaciones carga [opciones]   genera tráfico de envío y mide latencias
+//	notificaciones migrar [-estado]   aplica o muestra las migraciones pendientes del esquema
+//	notificaciones vapid              genera un par de claves VAPID para Web Push
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// comando es un subcomando de la CLI
+type comando struct {
+	descripcion string
+	ejecutar    func(args []string) error
+}
+
+var comandos = map[string]comando{
+	"carga":  {"genera tráfico de envío contra un entorno y reporta percentiles de latencia", ejecutarCarga},
+	"migrar": {"aplica las migraciones pendientes del esquema (-estado solo las muestra)", ejecutarMigrar},
+	"vapid":  {"genera un par de claves VAPID para el envío Web Push", ejecutarVAPID},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		uso()
+		os.Exit(2)
+	}
+	cmd, existe := comandos[os.Args[1]]
+	if !existe {
+		fmt.Fprintf(os.Stderr, "comando desconocido: %s\n\n", os.Args[1])
+		uso()
+		os.Exit(2)
+	}
+	if err := cmd.ejecutar(os.Args[2:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func uso() {
+	fmt.Fprintln(os.Stderr, "Uso: notificaciones <comando> [opciones]")
+	fmt.Fprintln(os.Stderr, "\nComandos:")
+	nombres := make([]string, 0, len(comandos))
+	for nombre := range comandos {
+		nombres = append(nombres, nombre)
+	}
+	sort.Strings(nombres)
+	for _, nombre := range nombres {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", nombre, comandos[nombre].descripcion)
+	}
+	fmt.Fprintln(os.Stderr, "\nUse \"notificaciones <comando> -h\" para ver las opciones de un comando.")
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+	"sistema-notificaciones-go/internal/infraestructura/persistencia"
+)
+
+// ejecutarMigrar aplica las migraciones pendientes con la configuración del entorno (MODO, DB_*,
+// REGIONES). Es el paso previo al despliegue cuando el servicio corre con DB_MIGRACION=requerida.
+func ejecutarMigrar(args []string) error {
+	banderas := flag.NewFlagSet("migrar", flag.ContinueOnError)
+	soloEstado := banderas.Bool("estado", false, "solo muestra la versión y las migraciones pendientes, sin aplicarlas")
+	if err := banderas.Parse(args); err != nil {
+		return err
+	}
+
+	config, err := configuracion.CargarConfiguracion()
+	if err != nil {
+		return err
+	}
+	db, err := persistencia.NuevaConexion(config.BaseDatos)
+	if err != nil {
+		return err
+	}
+	basesRegionales := make(map[string]configuracion.ConfiguracionBaseDatos, len(config.Regiones))
+	for nombre, region := range config.Regiones {
+		basesRegionales[nombre] = region.BaseDatos
+	}
+	// El comando es el paso explícito de migración: aplica aunque el servicio corra en modo
+	// requerida o solo_lectura
+	if !*soloEstado {
+		config.BaseDatos.Migracion = configuracion.MigracionAutomatica
+	}
+	migrador := persistencia.NuevoMigrador(db, persistencia.NuevoRegistroEsquemas(config.BaseDatos, basesRegionales),
+		persistencia.NuevoRepositorioInquilinoPostgres(db), config.BaseDatos.Migracion)
+
+	ctx := context.Background()
+	if !*soloEstado {
+		if err := migrador.Migrar(ctx); err != nil {
+			return err
+		}
+	}
+	estado, err := migrador.Estado(ctx)
+	if err != nil {
+		return err
+	}
+	salida := json.NewEncoder(os.Stdout)
+	salida.SetIndent("", "  ")
+	if err := salida.Encode(estado); err != nil {
+		return err
+	}
+	if !estado.AlDia {
+		return fmt.Errorf("quedan migraciones pendientes")
+	}
+	return nil
+}
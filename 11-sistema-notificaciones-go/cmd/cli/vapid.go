@@ -0,0 +1,25 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"sistema-notificaciones-go/internal/infraestructura/webPush"
+)
+
+// ejecutarVAPID imprime un par de claves nuevo en el formato de las variables de entorno.
+// Cambiar las claves invalida las suscripciones existentes: los navegadores deben suscribirse de nuevo.
+func ejecutarVAPID(args []string) error {
+	banderas := flag.NewFlagSet("vapid", flag.ContinueOnError)
+	if err := banderas.Parse(args); err != nil {
+		return err
+	}
+
+	publica, privada, err := webPush.GenerarClavesVAPID()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("WEB_PUSH_VAPID_CLAVE_PUBLICA=%s\n", publica)
+	fmt.Printf("WEB_PUSH_VAPID_CLAVE_PRIVADA=%s\n", privada)
+	return nil
+}
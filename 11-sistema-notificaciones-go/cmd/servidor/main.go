@@ -1,13 +1,53 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/infraestructura/almacenamiento"
+	"sistema-notificaciones-go/internal/infraestructura/avisos"
+	"sistema-notificaciones-go/internal/infraestructura/cache"
+	"sistema-notificaciones-go/internal/infraestructura/caos"
+	"sistema-notificaciones-go/internal/infraestructura/clienteHTTP"
 	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+	"sistema-notificaciones-go/internal/infraestructura/correo"
+	"sistema-notificaciones-go/internal/infraestructura/eco"
+	"sistema-notificaciones-go/internal/infraestructura/kafka"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/internal/infraestructura/persistencia"
+	"sistema-notificaciones-go/internal/infraestructura/proveedores"
+	"sistema-notificaciones-go/internal/infraestructura/rabbitmq"
+	"sistema-notificaciones-go/internal/infraestructura/redisStreams"
+	"sistema-notificaciones-go/internal/infraestructura/simulacion"
+	"sistema-notificaciones-go/internal/infraestructura/slack"
+	"sistema-notificaciones-go/internal/infraestructura/smpp"
+	"sistema-notificaciones-go/internal/infraestructura/telegram"
+	"sistema-notificaciones-go/internal/infraestructura/trabajador"
+	"sistema-notificaciones-go/internal/infraestructura/twilio"
+	"sistema-notificaciones-go/internal/infraestructura/webPush"
+	"sistema-notificaciones-go/internal/infraestructura/websocket"
 	"sistema-notificaciones-go/internal/presentacion/controlador"
+	"sistema-notificaciones-go/internal/presentacion/ingesta"
 	"sistema-notificaciones-go/internal/presentacion/middleware"
+	"sistema-notificaciones-go/internal/presentacion/panel"
+	"sistema-notificaciones-go/internal/presentacion/problema"
+	"sistema-notificaciones-go/internal/presentacion/validacion"
+	"sistema-notificaciones-go/pkg/cifrado"
 	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
 )
 
 // @title Sistema de Notificaciones API
@@ -25,12 +65,49 @@ func main() {
 	if err != nil {
 		logger.Fatal("Error cargando configuración", "error", err)
 	}
+	if err := config.JWT.Validar(); err != nil {
+		logger.Fatal("Configuración de JWT inválida", "error", err)
+	}
+
+	// Aplicar niveles de log configurados
+	if err := logger.Niveles().Aplicar(config.Log.Nivel, config.Log.NivelesComponentes); err != nil {
+		logger.Fatal("Error configurando niveles de log", "error", err)
+	}
+
+	// Conectar base de datos
+	db, err := persistencia.NuevaConexion(config.BaseDatos)
+	if err != nil {
+		logger.Fatal("Error conectando a la base de datos", "error", err)
+	}
+
+	// Conectar Redis
+	clienteRedis := cache.NuevoClienteRedis(config.Redis)
+
+	// Reglas de validación de solicitudes
+	if err := validacion.Registrar(); err != nil {
+		logger.Fatal("Error registrando validaciones", "error", err)
+	}
 
 	// Configurar Gin
 	if config.Modo == "produccion" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	// Caos para pruebas de resiliencia: la configuración lo rechaza en producción
+	var inyectorCaos *caos.Inyector
+	if config.Caos.Habilitado {
+		inyectorCaos = caos.NuevoInyector(config.Caos)
+		if err := inyectorCaos.RegistrarEnBaseDatos(db); err != nil {
+			logger.Fatal("Error registrando el caos en la base de datos", "error", err)
+		}
+		logger.Warn("Inyección de fallas habilitada",
+			"tasa_latencia", config.Caos.TasaLatencia,
+			"latencia_maxima", config.Caos.LatenciaMaxima,
+			"tasa_descarte_ws", config.Caos.TasaDescarteWebSocket,
+			"tasa_error_bd", config.Caos.TasaErrorBaseDatos,
+		)
+	}
+
 	// Crear router
 	router := gin.New()
 
@@ -38,9 +115,16 @@ func main() {
 	router.Use(middleware.Logger(logger))
 	router.Use(middleware.Recovery(logger))
 	router.Use(middleware.CORS())
+	if inyectorCaos != nil {
+		router.Use(middleware.Caos(inyectorCaos))
+	}
+	router.NoRoute(func(c *gin.Context) {
+		problema.Responder(c, http.StatusNotFound, "ruta_no_encontrada", "Ruta no encontrada")
+	})
 
 	// Configurar rutas
-	configurarRutas(router, config, logger)
+	tareas := casoUso.NuevasTareasSegundoPlano()
+	configurarRutas(router, config, db, clienteRedis, inyectorCaos, tareas, logger)
 
 	// Iniciar servidor
 	puerto := config.Puerto
@@ -48,13 +132,40 @@ func main() {
 		puerto = "8080"
 	}
 
-	logger.Info("Servidor iniciado", "puerto", puerto)
-	if err := router.Run(":" + puerto); err != nil {
-		log.Fatal("Error iniciando servidor:", err)
+	// Al recibir la señal deja de aceptar peticiones, completa las activas y los avisos pendientes
+	ctx, detener := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer detener()
+	servidor := &http.Server{Addr: ":" + puerto, Handler: router}
+	go func() {
+		logger.Info("Servidor iniciado", "puerto", puerto)
+		if err := servidor.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Error iniciando servidor:", err)
+		}
+	}()
+
+	<-ctx.Done()
+	logger.Info("Deteniendo servidor: completando las peticiones en curso")
+	ctxApagado, cancelar := context.WithTimeout(context.Background(), tiempoApagado)
+	defer cancelar()
+	if err := servidor.Shutdown(ctxApagado); err != nil {
+		logger.Error("Error deteniendo el servidor", "error", err)
 	}
+	tareas.Esperar()
+	logger.Info("Servidor detenido")
 }
 
-func configurarRutas(router *gin.Engine, config *configuracion.Configuracion, logger *logger.Logger) {
+// tiempoApagado acota la espera de las peticiones en curso al detener el servidor
+const tiempoApagado = 30 * time.Second
+
+func configurarRutas(router *gin.Engine, config *configuracion.Configuracion, db *gorm.DB, clienteRedis *redis.Client, inyectorCaos *caos.Inyector, tareas *casoUso.TareasSegundoPlano, logger *logger.Logger) {
+	// Métricas de Prometheus
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	// Panel de administración embebido; sus datos se piden a /api/v1/admin con el token
+	if err := panel.Registrar(router); err != nil {
+		logger.Fatal("Error registrando el panel de administración", "error", err)
+	}
+
 	// Grupo de API v1
 	v1 := router.Group("/api/v1")
 
@@ -66,20 +177,900 @@ func configurarRutas(router *gin.Engine, config *configuracion.Configuracion, lo
 		})
 	})
 
+	// Reloj compartido por la lógica dependiente del tiempo
+	relojSistema := reloj.NuevoRelojSistema()
+
+	// Caches con invalidación entre instancias
+	cacheCanales := cache.NuevoCacheDosNiveles("canales", clienteRedis, config.Cache, relojSistema, logger)
+	cachePreferencias := cache.NuevoCacheDosNiveles("preferencias", clienteRedis, config.Cache, relojSistema, logger)
+	cacheInquilinos := cache.NuevoCacheDosNiveles("inquilinos", clienteRedis, config.Cache, relojSistema, logger)
+	go cache.EscucharInvalidaciones(context.Background(), clienteRedis, cacheCanales, cachePreferencias, cacheInquilinos)
+
+	// Repositorios
+	unidadTrabajo := persistencia.NuevaUnidadTrabajoPostgres(db)
+	repositorioNoLeidas := cache.NuevoRepositorioNotificacionNoLeidas(persistencia.NuevoRepositorioNotificacionPostgres(db), clienteRedis, config.NoLeidas.TTL, logger)
+	var repositorioNotificacion repositorio.RepositorioNotificacion = repositorioNoLeidas
+	if len(config.Kafka.Brokers) > 0 {
+		publicadorCicloVida := kafka.NuevoPublicadorCicloVida(config.Kafka, relojSistema, logger)
+		repositorioNotificacion = kafka.NuevoRepositorioNotificacionEventos(repositorioNoLeidas, publicadorCicloVida)
+		logger.Info("Eventos del ciclo de vida publicados en Kafka", "tema", config.Kafka.TemaEventos)
+	}
+	repositorioCanal := cache.NuevoRepositorioCanalCacheado(persistencia.NuevoRepositorioCanalPostgres(db), cacheCanales)
+	repositorioPreferencia := cache.NuevoRepositorioPreferenciaCacheado(persistencia.NuevoRepositorioPreferenciaPostgres(db), cachePreferencias)
+	repositorioUsuario := persistencia.NuevoRepositorioUsuarioPostgres(db)
+	repositorioDispositivo := persistencia.NuevoRepositorioDispositivoPostgres(db)
+	repositorioInquilino := cache.NuevoRepositorioInquilinoCacheado(persistencia.NuevoRepositorioInquilinoPostgres(db), cacheInquilinos)
+	repositorioClaveAPI := persistencia.NuevoRepositorioClaveAPIPostgres(db)
+	repositorioPlantilla := persistencia.NuevoRepositorioPlantillaPostgres(db)
+	repositorioConsentimiento := persistencia.NuevoRepositorioConsentimientoPostgres(db)
+
+	// Inquilinos aislados y con residencia de datos: un pool por región y esquema, elegido según el inquilino del contexto
+	basesRegionales := make(map[string]configuracion.ConfiguracionBaseDatos, len(config.Regiones))
+	backendsRegionales := make(servicio.BackendsRegionales, len(config.Regiones))
+	for nombre, region := range config.Regiones {
+		basesRegionales[nombre] = region.BaseDatos
+		backendsRegionales[nombre] = region.Proveedores
+	}
+	registroEsquemas := persistencia.NuevoRegistroEsquemas(config.BaseDatos, basesRegionales)
+	if inyectorCaos != nil {
+		// Las fallas alcanzan también a las regiones y a los esquemas de los inquilinos aislados
+		if err := registroEsquemas.AlConectar(inyectorCaos.RegistrarEnBaseDatos); err != nil {
+			logger.Fatal("Error registrando el caos en las bases regionales", "error", err)
+		}
+	}
+	persistencia.HabilitarAislamiento(registroEsquemas)
+	migrador := persistencia.NuevoMigrador(db, registroEsquemas, repositorioInquilino, config.BaseDatos.Migracion)
+	prepararEsquema(migrador, logger)
+
+	// Hub de WebSocket fragmentado por usuario
+	hub := websocket.NuevoHub(config.WebSocket, logger)
+	if inyectorCaos != nil {
+		hub.InyectarDescartes(inyectorCaos.DescartarTrama)
+	}
+
+	// Clientes HTTP salientes con un pool de conexiones compartido
+	fabricaClientes := clienteHTTP.NuevaFabricaClientes(config.HTTP)
+
+	// Códigos QR de entradas y pases: adjuntos en el correo y con URL firmada para in-app y push
+	casoUsoCodigosQR := casoUso.NuevoCasoUsoCodigosQR(
+		repositorioNotificacion,
+		repositorioInquilino,
+		config.CodigosQR.Campo,
+		config.CodigosQR.URLBase,
+		config.CodigosQR.Secreto,
+		config.CodigosQR.Vigencia,
+		config.CodigosQR.Escala,
+		relojSistema,
+		logger,
+	)
+
+	// Adjuntos en el almacén S3: los sube la API y los lee el enviador de correo
+	almacenAdjuntos, analizadorAdjuntos := crearAlmacenAdjuntos(config, fabricaClientes, relojSistema, logger)
+	casoUsoAdjuntos := casoUso.NuevoCasoUsoAdjuntos(
+		persistencia.NuevoRepositorioAdjuntoPostgres(db),
+		repositorioNotificacion,
+		almacenAdjuntos,
+		analizadorAdjuntos,
+		config.Adjuntos.MaxBytes,
+		config.Adjuntos.Tipos,
+		config.Adjuntos.VigenciaURL,
+		relojSistema,
+		logger,
+	)
+
+	// Despacho asíncrono con trabajadores por prioridad
+	// Cada canal registra su proveedor; el despacho lo busca por el tipo de la notificación
+	registroProveedores := proveedores.NuevoRegistroProveedores()
+	registroProveedores.Registrar(
+		websocket.NuevoEnviadorWebSocket(hub),
+		websocket.NuevoEnviadorBandeja(hub),
+		// Los canales con incoming webhook no necesitan token, por eso Slack siempre está disponible
+		slack.NuevoEnviadorSlack(config.Slack, repositorioCanal, fabricaClientes.Cliente(slack.ProveedorSlack)),
+	)
+	var enviadorTelegram *telegram.EnviadorTelegram
+	if config.Telegram.Token != "" {
+		enviadorTelegram = telegram.NuevoEnviadorTelegram(config.Telegram, repositorioUsuario, fabricaClientes.Cliente(telegram.ProveedorTelegram))
+		registroProveedores.Registrar(enviadorTelegram)
+	}
+	if config.Correo.Host != "" {
+		registroProveedores.Registrar(correo.NuevoEnviadorSMTP(config.Correo, repositorioUsuario, casoUsoCodigosQR, casoUsoAdjuntos, relojSistema))
+	}
+	if config.WebPush.ClavePrivada != "" {
+		enviadorWebPush, err := webPush.NuevoEnviadorWebPush(config.WebPush, repositorioDispositivo, fabricaClientes.Cliente(webPush.ProveedorWebPush), relojSistema, logger)
+		if err != nil {
+			logger.Fatal("Error configurando Web Push", "error", err)
+		}
+		registroProveedores.Registrar(enviadorWebPush)
+	}
+	casoUsoEnlaces := casoUso.NuevoCasoUsoEnlacesCortos(
+		persistencia.NuevoRepositorioEnlaceCortoPostgres(db),
+		repositorioNotificacion,
+		config.EnlacesCortos.URLBase,
+		config.EnlacesCortos.Vigencia,
+		config.EnlacesCortos.LongitudSlug,
+		relojSistema,
+		logger,
+	)
+	// Los juegos de credenciales de la plataforma se cachean con los inquilinos: una rotación
+	// invalida el activo en todas las instancias
+	cifradorCredenciales := crearCifrador(config, logger)
+	casoUsoCredencialesPlataforma := casoUso.NuevoCasoUsoCredencialesPlataforma(
+		cache.NuevoRepositorioCredencialPlataformaCacheado(persistencia.NuevoRepositorioCredencialPlataformaPostgres(db), cacheInquilinos),
+		cifradorCredenciales,
+		relojSistema,
+	)
+	casoUsoCredenciales := casoUso.NuevoCasoUsoCredencialesProveedor(repositorioInquilino, casoUsoCredencialesPlataforma, cifradorCredenciales)
+	// Los acuses de entrega de los SMS pueden llegar a cualquier instancia
+	registroMensajes := cache.NuevoRegistroMensajesRedis(clienteRedis)
+	casoUsoAcuses := casoUso.NuevoCasoUsoAcusesEntrega(registroMensajes, repositorioNotificacion, repositorioInquilino, max(config.SMPP.VigenciaAcuses, config.Twilio.VigenciaAcuses), logger)
+	var enviadorTwilio *twilio.EnviadorTwilio
+	if config.Twilio.SIDCuenta != "" {
+		enviadorTwilio = twilio.NuevoEnviadorTwilio(config.Twilio, repositorioUsuario, registroMensajes, casoUsoAcuses, casoUsoCredenciales, casoUsoEnlaces, fabricaClientes.Cliente(twilio.ProveedorTwilio), logger)
+		// Las llamadas de voz de las notificaciones críticas salen por la misma cuenta
+		registroProveedores.Registrar(enviadorTwilio, twilio.NuevoEnviadorVozTwilio(enviadorTwilio))
+	}
+	// La ruta SMS directa al SMSC del operador tiene prioridad sobre Twilio: se registra después
+	if config.SMPP.Host != "" {
+		registroProveedores.Registrar(smpp.NuevoEnviadorSMPP(config.SMPP, repositorioUsuario, registroMensajes, casoUsoAcuses, casoUsoEnlaces, logger))
+	}
+	avisadorLectura := avisos.NuevoAvisadorLectura(config.AvisosLectura, avisos.ClienteWebhooks(config.AvisosLectura, fabricaClientes), clienteRedis)
+	casoUsoEstado := casoUso.NuevoCasoUsoCambiarEstadoNotificacion(repositorioNotificacion, avisadorLectura, tareas, relojSistema, logger)
+	casoUsoSupresion := casoUso.NuevoCasoUsoListaSupresion(persistencia.NuevoRepositorioSupresionPostgres(db), repositorioUsuario, relojSistema)
+	casoUsoEventosCorreo := casoUso.NuevoCasoUsoEventosCorreo(repositorioNotificacion, repositorioInquilino, casoUsoEstado, casoUsoSupresion, logger)
+	// SendGrid tiene prioridad sobre el servidor SMTP: sus eventos informan entregas, rebotes y aperturas
+	var enviadorSendGrid *correo.EnviadorSendGrid
+	if config.SendGrid.ClaveAPI != "" {
+		var err error
+		enviadorSendGrid, err = correo.NuevoEnviadorSendGrid(
+			config.SendGrid,
+			config.Correo,
+			repositorioUsuario,
+			casoUsoCodigosQR,
+			casoUsoAdjuntos,
+			casoUsoEventosCorreo,
+			casoUsoCredenciales,
+			fabricaClientes.Cliente(correo.ProveedorSendGrid),
+			relojSistema,
+			logger,
+		)
+		if err != nil {
+			logger.Fatal("Error configurando SendGrid", "error", err)
+		}
+		registroProveedores.Registrar(enviadorSendGrid)
+	}
+	// SES, configurado junto a SendGrid, tiene prioridad; sus rebotes y quejas llegan por SNS
+	var enviadorSES *correo.EnviadorSES
+	if config.SES.Region != "" {
+		enviadorSES = correo.NuevoEnviadorSES(
+			config.SES,
+			config.Correo,
+			repositorioUsuario,
+			casoUsoCodigosQR,
+			casoUsoAdjuntos,
+			registroMensajes,
+			casoUsoEventosCorreo,
+			fabricaClientes.Cliente(correo.ProveedorSES),
+			relojSistema,
+			logger,
+		)
+		registroProveedores.Registrar(enviadorSES)
+	}
+	// Proveedores simulados con fallas configurables, solo fuera de producción; en modo sandbox
+	// reemplazan a todos los proveedores externos
+	if config.Simulacion.Sandbox {
+		logger.Warn("Modo sandbox: las notificaciones externas se registran en memoria sin enviarse", "tipos", config.Simulacion.Proveedores)
+	}
+	proveedoresSimulados := make(map[entidad.TipoNotificacion]*simulacion.ProveedorSimulado, len(config.Simulacion.Proveedores))
+	for _, tipo := range config.Simulacion.Proveedores {
+		simulado := simulacion.NuevoProveedorSimulado(entidad.TipoNotificacion(tipo))
+		proveedoresSimulados[entidad.TipoNotificacion(tipo)] = simulado
+		registroProveedores.Registrar(simulado)
+	}
+	logger.Info("Proveedores de notificación registrados", "tipos", registroProveedores.Tipos())
+	repositorioIntento := persistencia.NuevoRepositorioIntentoEnvioPostgres(db)
+	repositorioEnvio := persistencia.NuevoRepositorioEnvioMultiCanalPostgres(db)
+	repositorioConsumo := persistencia.NuevoRepositorioConsumoPostgres(db)
+	repositorioColaMuerta := persistencia.NuevoRepositorioColaMuertaPostgres(db)
+	casoUsoConsentimiento := casoUso.NuevoCasoUsoConsentimiento(repositorioConsentimiento, repositorioUsuario, repositorioCanal, relojSistema)
+	casoUsoSilenciamiento := casoUso.NuevoCasoUsoSilenciamiento(persistencia.NuevoRepositorioSilenciamientoPostgres(db), repositorioUsuario, repositorioCanal, relojSistema)
+	casoUsoDespachar := casoUso.NuevoCasoUsoDespacharNotificacion(repositorioNotificacion, repositorioIntento, repositorioConsumo, repositorioColaMuerta, registroProveedores, casoUsoCredenciales, casoUsoConsentimiento, casoUsoSupresion, casoUsoSilenciamiento, casoUsoCodigosQR, backendsRegionales, config.Reintentos.Politica(), relojSistema, logger)
+	casoUsoOrquestar := casoUso.NuevoCasoUsoOrquestarEnvio(casoUsoDespachar, repositorioEnvio, repositorioNotificacion, repositorioInquilino, logger)
+	var procesador trabajador.Procesador = casoUsoOrquestar
+	if inyectorCaos != nil {
+		procesador = inyectorCaos.EnvolverProcesador(casoUsoOrquestar)
+	}
+	// Sin broker la cola de despacho es el pool: la entrada del buzón de salida se borra al
+	// terminar el despacho y no al encolar, así una caída del proceso no pierde lo encolado
+	repositorioBuzon := persistencia.NuevoRepositorioBuzonSalidaPostgres(db)
+	colaEnMemoria := config.RabbitMQ.URL == "" && !config.RedisStreams.Habilitado
+	if colaEnMemoria {
+		procesador = trabajador.NuevoProcesadorBuzonSalida(procesador, repositorioBuzon, repositorioInquilino, logger)
+	}
+	poolTrabajadores := trabajador.NuevoPoolPrioridades(config.Trabajadores, procesador, logger)
+	poolTrabajadores.Iniciar(context.Background())
+	// Con RabbitMQ o Redis Streams los envíos a proveedores externos los entregan los procesos de cmd/trabajador;
+	// el pool queda para los tipos que van por las conexiones de este servidor
+	var colaDespacho repositorio.ColaMensajes = poolTrabajadores
+	if config.RabbitMQ.URL != "" {
+		colaRabbitMQ, err := rabbitmq.NuevaColaRabbitMQ(config.RabbitMQ, poolTrabajadores, logger)
+		if err != nil {
+			logger.Fatal("Error configurando RabbitMQ", "error", err)
+		}
+		colaDespacho = colaRabbitMQ
+		logger.Info("Despacho por RabbitMQ", "cola", config.RabbitMQ.Cola)
+	}
+	if config.RedisStreams.Habilitado {
+		colaStreams, err := redisStreams.NuevaColaRedisStreams(config.RedisStreams, clienteRedis, poolTrabajadores, logger)
+		if err != nil {
+			logger.Fatal("Error configurando Redis Streams", "error", err)
+		}
+		colaDespacho = colaStreams
+		logger.Info("Despacho por Redis Streams", "prefijo", config.RedisStreams.Prefijo)
+	}
+
+	// Elección de la instancia líder, la única que ejecuta los trabajos únicos
+	electorLider := trabajador.NuevoElectorLider(cache.NuevoArrendamientoRedis(clienteRedis), config.Liderazgo, relojSistema, logger)
+	electorLider.Iniciar(context.Background())
+
+	// Reintentos persistidos: se reconstruyen desde la base de datos al arrancar
+	planificadorReintentos := trabajador.NuevoPlanificadorReintentos(repositorioNotificacion, repositorioInquilino, colaDespacho, electorLider, config.Reintentos, relojSistema, logger)
+	planificadorReintentos.Iniciar(context.Background())
+
+	// Programadas: se encolan al llegar su fecha, también las que vencieron con el servicio detenido
+	planificadorProgramadas := trabajador.NuevoPlanificadorProgramadas(repositorioNotificacion, repositorioInquilino, colaDespacho, electorLider, config.Programadas, relojSistema, logger)
+	planificadorProgramadas.Iniciar(context.Background())
+
+	// Buzón de salida: las notificaciones se anotan al crearlas y el relevo publica las que la cola
+	// no aceptó en ese momento
+	casoUsoBuzon := casoUso.NuevoCasoUsoBuzonSalida(repositorioBuzon, colaDespacho, colaEnMemoria, relojSistema, logger)
+	relevoBuzon := trabajador.NuevoRelevoBuzonSalida(repositorioBuzon, repositorioNotificacion, repositorioInquilino, colaDespacho, colaEnMemoria, electorLider, config.BuzonSalida, relojSistema, logger)
+	relevoBuzon.Iniciar(context.Background())
+
+	// Casos de uso
+	contadorUso := cache.NuevoContadorUsoRedis(clienteRedis)
+	casoUsoCuotas := casoUso.NuevoCasoUsoControlarCuotas(repositorioInquilino, contadorUso, relojSistema, logger)
+	casoUsoLimite := casoUso.NuevoCasoUsoLimitarSolicitudes(contadorUso, int64(config.LimiteAPI.Solicitudes), config.LimiteAPI.Ventana, relojSistema, logger)
+	casoUsoMarca := casoUso.NuevoCasoUsoMarcaInquilino(repositorioInquilino, repositorioPlantilla)
+	casoUsoEsquemas := casoUso.NuevoCasoUsoEsquemaMetadatos(repositorioCanal)
+	casoUsoGuardias := casoUso.NuevoCasoUsoRotacionGuardia(persistencia.NuevoRepositorioGuardiaPostgres(db), repositorioCanal, repositorioUsuario, relojSistema)
+	ventanaDeduplicacion := cache.NuevaVentanaDeduplicacionRedis(clienteRedis, logger)
+	casoUsoEnviar := casoUso.NuevoCasoUsoEnviarNotificacion(
+		unidadTrabajo,
+		repositorioNotificacion,
+		casoUsoBuzon,
+		casoUsoCuotas,
+		casoUsoMarca,
+		casoUsoEsquemas,
+		casoUsoGuardias,
+		casoUsoAdjuntos,
+		ventanaDeduplicacion,
+		config.Envio.VentanaDeduplicacion,
+		relojSistema,
+	)
+	casoUsoReenvio := casoUso.NuevoCasoUsoReenviarFallidas(unidadTrabajo, repositorioNotificacion, repositorioInquilino, casoUsoBuzon, relojSistema, logger)
+	casoUsoColaMuerta := casoUso.NuevoCasoUsoColaMuerta(repositorioColaMuerta, repositorioNotificacion, repositorioInquilino, relojSistema, logger)
+	casoUsoSimular := casoUso.NuevoCasoUsoSimularEnvio(repositorioUsuario, repositorioPreferencia, casoUsoMarca, casoUsoEsquemas, casoUsoGuardias, casoUsoCuotas, casoUsoConsentimiento, casoUsoSupresion, casoUsoSilenciamiento, casoUsoDespachar, relojSistema)
+	casoUsoRuteo := casoUso.NuevoCasoUsoExplicarRuteo(repositorioUsuario, repositorioInquilino, repositorioPreferencia, repositorioDispositivo, casoUsoSupresion, casoUsoDespachar, relojSistema)
+	casoUsoSuscripcion := casoUso.NuevoCasoUsoSuscripcionCanal(
+		repositorioCanal,
+		repositorioUsuario,
+		repositorioInquilino,
+		casoUsoEnviar,
+		casoUsoConsentimiento,
+		config.Suscripciones.Secreto,
+		config.Suscripciones.URLConfirmacion,
+		config.Suscripciones.URLInvitacion,
+		config.Suscripciones.VigenciaEnlace,
+		entidad.TipoNotificacion(config.Suscripciones.TipoConfirmacion),
+		relojSistema,
+	)
+	casoUsoDifundir := casoUso.NuevoCasoUsoDifundirCanal(
+		unidadTrabajo,
+		repositorioNotificacion,
+		repositorioCanal,
+		repositorioInquilino,
+		casoUsoBuzon,
+		casoUsoCuotas,
+		config.Envio.TamanoLote,
+		int64(config.Envio.UmbralAprobacionDifusion),
+		config.Envio.RetencionDifusiones,
+		config.Envio.MaximoDifusiones,
+		relojSistema,
+		logger,
+	)
+	repositorioBorradorDifusion := persistencia.NuevoRepositorioBorradorDifusionPostgres(db)
+	casoUsoAprobacionDifusion := casoUso.NuevoCasoUsoAprobacionDifusion(repositorioBorradorDifusion, repositorioCanal, casoUsoDifundir, relojSistema)
+	casoUsoModeracion := casoUso.NuevoCasoUsoModeracionCanal(repositorioBorradorDifusion, repositorioCanal, casoUsoAprobacionDifusion, relojSistema)
+	casoUsoMultiCanal := casoUso.NuevoCasoUsoEnviarMultiCanal(unidadTrabajo, repositorioEnvio, repositorioNotificacion, casoUsoBuzon, casoUsoCuotas, relojSistema)
+	casoUsoClaves := casoUso.NuevoCasoUsoClavesAPI(repositorioClaveAPI, repositorioInquilino, relojSistema)
+	casoUsoFacturacion := casoUso.NuevoCasoUsoGenerarFacturacion(repositorioConsumo)
+	casoUsoSLA := casoUso.NuevoCasoUsoEvaluarSLA(repositorioInquilino, repositorioNotificacion, relojSistema)
+	casoUsoAprovisionar := casoUso.NuevoCasoUsoAprovisionarInquilino(
+		unidadTrabajo,
+		repositorioInquilino,
+		repositorioCanal,
+		repositorioUsuario,
+		repositorioPreferencia,
+		repositorioPlantilla,
+		repositorioClaveAPI,
+		registroEsquemas,
+	)
+	casoUsoExportar := casoUso.NuevoCasoUsoExportarInquilino(
+		repositorioInquilino,
+		persistencia.NuevoLectorDatosInquilinoPostgres(db),
+		crearAlmacenExportaciones(config, logger),
+		relojSistema,
+		logger,
+	)
+
+	// Corrección periódica de los contadores de no leídas contra la base de datos
+	reconciliadorNoLeidas := trabajador.NuevoReconciliadorNoLeidas(repositorioNoLeidas, config.NoLeidas.IntervaloReconciliacion, logger)
+	reconciliadorNoLeidas.Iniciar(context.Background())
+
+	// Solicitudes de envío publicadas en Kafka por otros servicios
+	if config.Kafka.ConsumirSolicitudes {
+		consumidorSolicitudes := ingesta.NuevoConsumidorSolicitudesKafka(config.Kafka, casoUsoEnviar, casoUsoClaves, repositorioInquilino, logger)
+		consumidorSolicitudes.Iniciar(context.Background())
+	}
+
+	// Evaluación continua de SLA por inquilino
+	monitorSLA := trabajador.NuevoMonitorSLA(casoUsoSLA, electorLider, config.SLA.IntervaloEvaluacion, logger)
+	monitorSLA.Iniciar(context.Background())
+
+	// Carga de despacho publicada como métricas para el autoescalado
+	casoUsoCarga := casoUso.NuevoCasoUsoCargaDespacho(repositorioNotificacion, repositorioInquilino, relojSistema)
+	monitorCarga := trabajador.NuevoMonitorCarga(casoUsoCarga, electorLider, config.Trabajadores.IntervaloCarga, logger)
+	monitorCarga.Iniciar(context.Background())
+
+	// Reporte operativo diario o semanal a los administradores de plataforma
+	frecuenciasReporte := make([]entidad.FrecuenciaReporte, 0, len(config.Reportes.Frecuencias))
+	for _, frecuencia := range config.Reportes.Frecuencias {
+		frecuenciasReporte = append(frecuenciasReporte, entidad.FrecuenciaReporte(frecuencia))
+	}
+	casoUsoReporte := casoUso.NuevoCasoUsoReporteOperativo(
+		repositorioNotificacion,
+		repositorioIntento,
+		repositorioInquilino,
+		repositorioUsuario,
+		repositorioPlantilla,
+		casoUsoSLA,
+		casoUsoEnviar,
+		ventanaDeduplicacion,
+		config.Reportes.Destinatarios,
+		frecuenciasReporte,
+		config.Reportes.Hora,
+		entidad.TipoNotificacion(config.Reportes.Tipo),
+		relojSistema,
+		logger,
+	)
+	if len(config.Reportes.Destinatarios) > 0 {
+		programadorReportes := trabajador.NuevoProgramadorReportes(casoUsoReporte, config.Reportes.Intervalo, logger)
+		programadorReportes.Iniciar(context.Background())
+	}
+
+	// Escalamientos de guardia de los canales de seguridad, hasta que se reconocen
+	casoUsoEscalamiento := casoUso.NuevoCasoUsoEscalamiento(
+		persistencia.NuevoRepositorioEscalamientoPostgres(db),
+		repositorioCanal,
+		repositorioUsuario,
+		repositorioInquilino,
+		casoUsoEnviar,
+		relojSistema,
+		logger,
+	)
+	ejecutorEscalamientos := trabajador.NuevoEjecutorEscalamientos(casoUsoEscalamiento, config.Escalamientos.Intervalo, logger)
+	ejecutorEscalamientos.Iniciar(context.Background())
+
+	// Purga periódica según las políticas de retención
+	casoUsoRetencion := casoUso.NuevoCasoUsoAplicarRetencion(
+		unidadTrabajo,
+		persistencia.NuevoRepositorioPoliticaRetencionPostgres(db),
+		repositorioNotificacion,
+		repositorioInquilino,
+		config.Retencion.TamanoLote,
+		config.Retencion.SecretoAnonimizacion,
+		relojSistema,
+	)
+	purgadorRetencion := trabajador.NuevoPurgadorRetencion(casoUsoRetencion, electorLider, config.Retencion.Intervalo, logger)
+	purgadorRetencion.Iniciar(context.Background())
+
+	// Servicios de dominio
+	servicioEliminacion := servicio.NuevoServicioEliminacionUsuario(
+		unidadTrabajo,
+		repositorioUsuario,
+		repositorioNotificacion,
+		repositorioPreferencia,
+		repositorioCanal,
+		repositorioDispositivo,
+	)
+
+	casoUsoOlvidar := casoUso.NuevoCasoUsoOlvidarUsuario(
+		unidadTrabajo,
+		repositorioUsuario,
+		repositorioNotificacion,
+		repositorioIntento,
+		repositorioPreferencia,
+		repositorioCanal,
+		repositorioDispositivo,
+		persistencia.NuevoRepositorioCertificadoEliminacionPostgres(db),
+		config.Envio.TamanoLote,
+		relojSistema,
+		logger,
+	)
+
 	// Configurar controladores
-	controladorNotificacion := controlador.NuevoControladorNotificacion(config, logger)
-	controladorWebSocket := controlador.NuevoControladorWebSocket(config, logger)
+	casoUsoAccesos := casoUso.NuevoCasoUsoRegistrarAccesoPersonal(
+		persistencia.NuevoRepositorioAccesoNotificacionPostgres(db, []byte(config.Auditoria.ClaveSello)),
+		relojSistema,
+		[]byte(config.Auditoria.ClaveSello),
+	)
+	controladorNotificacion := controlador.NuevoControladorNotificacion(casoUsoEnviar, casoUsoEstado, casoUsoSimular, casoUsoAccesos, repositorioNotificacion, logger)
+	controladorWebSocket := controlador.NuevoControladorWebSocket(hub, config, logger)
+	controladorDifusion := controlador.NuevoControladorDifusion(casoUsoDifundir)
+	controladorBorradorDifusion := controlador.NuevoControladorBorradorDifusion(casoUsoAprobacionDifusion)
+	controladorModeracion := controlador.NuevoControladorModeracion(casoUsoModeracion)
+	controladorCanal := controlador.NuevoControladorCanal(casoUsoEsquemas)
+	controladorEscalamiento := controlador.NuevoControladorEscalamiento(casoUsoEscalamiento)
+	controladorGuardia := controlador.NuevoControladorGuardia(casoUsoGuardias)
+	controladorPreferencia := controlador.NuevoControladorPreferencia(repositorioPreferencia, repositorioUsuario)
+	controladorConsentimiento := controlador.NuevoControladorConsentimiento(casoUsoConsentimiento)
+	controladorSuscripcion := controlador.NuevoControladorSuscripcion(casoUsoSuscripcion)
+	controladorBandeja := controlador.NuevoControladorBandeja(casoUso.NuevoCasoUsoBandeja(repositorioNotificacion, repositorioUsuario, casoUsoCodigosQR))
+	controladorWebPush := controlador.NuevoControladorWebPush(casoUso.NuevoCasoUsoWebPush(repositorioDispositivo, repositorioUsuario, config.WebPush.ClavePublica))
+	controladorSilenciamiento := controlador.NuevoControladorSilenciamiento(casoUsoSilenciamiento)
+	publicadorEventos := websocket.NuevoPublicadorEventos(hub)
+	controladorReaccion := controlador.NuevoControladorReaccion(casoUso.NuevoCasoUsoReacciones(
+		persistencia.NuevoRepositorioReaccionPostgres(db),
+		repositorioNotificacion,
+		repositorioCanal,
+		publicadorEventos,
+		relojSistema,
+		logger,
+	))
+	controladorRespuesta := controlador.NuevoControladorRespuesta(casoUso.NuevoCasoUsoRespuestasNotificacion(
+		persistencia.NuevoRepositorioRespuestaPostgres(db),
+		repositorioNotificacion,
+		repositorioCanal,
+		publicadorEventos,
+		logger,
+	))
+	controladorEnvio := controlador.NuevoControladorEnvio(casoUsoMultiCanal)
+	controladorUsuario := controlador.NuevoControladorUsuario(servicioEliminacion, casoUsoOlvidar)
+	controladorInquilino := controlador.NuevoControladorInquilino(casoUsoCuotas, casoUsoCredenciales, casoUsoSLA, casoUsoAprovisionar, casoUsoMarca)
+	controladorClaveAPI := controlador.NuevoControladorClaveAPI(casoUsoClaves)
+	controladorFacturacion := controlador.NuevoControladorFacturacion(casoUsoFacturacion, relojSistema, logger)
+	controladorExportacion := controlador.NuevoControladorExportacion(casoUsoExportar, logger)
+	controladorRetencion := controlador.NuevoControladorRetencion(casoUsoRetencion)
+	controladorCredencialPlataforma := controlador.NuevoControladorCredencialPlataforma(casoUsoCredencialesPlataforma)
+	controladorReporte := controlador.NuevoControladorReporte(casoUsoReporte)
+	controladorAcceso := controlador.NuevoControladorAcceso(casoUsoAccesos, logger)
+	controladorSupresion := controlador.NuevoControladorSupresion(casoUsoSupresion)
+	controladorEnlaceCorto := controlador.NuevoControladorEnlaceCorto(casoUsoEnlaces)
+	controladorCodigoQR := controlador.NuevoControladorCodigoQR(casoUsoCodigosQR)
+	controladorSlack := controlador.NuevoControladorSlack(casoUso.NuevoCasoUsoSlackCanal(repositorioCanal))
+	var controladorTelegram *controlador.ControladorTelegram
+	if enviadorTelegram != nil {
+		controladorTelegram = controlador.NuevoControladorTelegram(casoUso.NuevoCasoUsoVinculoTelegram(
+			repositorioUsuario,
+			repositorioInquilino,
+			enviadorTelegram,
+			config.Telegram.Bot,
+			config.Telegram.SecretoEnlace,
+			config.Telegram.VigenciaEnlace,
+			relojSistema,
+			logger,
+		), config.Telegram.SecretoWebhook)
+	}
+	controladorAdjunto := controlador.NuevoControladorAdjunto(casoUsoAdjuntos, config.Adjuntos.MaxBytes)
+	controladorCorreoEntrante := controlador.NuevoControladorCorreoEntrante(casoUso.NuevoCasoUsoRespuestasCorreo(
+		repositorioNotificacion,
+		repositorioCanal,
+		repositorioInquilino,
+		casoUsoEstado,
+		avisadorLectura,
+		config.Correo.DireccionRespuestas,
+		config.Correo.SecretoRespuestas,
+		relojSistema,
+		logger,
+	), config.Correo.TokenEntrante, config.Correo.MaxBytesEntrante)
+
+	// Enlaces de confirmación del doble opt-in y de invitación a canales privados: los abre el usuario, sin clave de API
+	v1.GET("/suscripciones/confirmar", controladorSuscripcion.ConfirmarSuscripcion)
+	v1.GET("/invitaciones/aceptar", controladorSuscripcion.AceptarInvitacion)
+
+	// Clave VAPID pública para pushManager.subscribe; la usa el navegador antes de registrar la suscripción
+	v1.GET("/web-push/clave-publica", controladorWebPush.ObtenerClavePublica)
+
+	// Redirección de los enlaces cortos de los SMS, fuera de /api para que el enlace sea breve;
+	// ENLACES_CORTOS_URL_BASE debe apuntar a /e
+	if config.EnlacesCortos.URLBase != "" {
+		router.GET("/e/:slug", controladorEnlaceCorto.Redirigir)
+	}
+
+	// Imagen del código QR de las URL firmadas; la abre el cliente in-app o push, sin clave de API
+	if config.CodigosQR.Campo != "" && config.CodigosQR.URLBase != "" {
+		v1.GET("/qr/:token", controladorCodigoQR.Imagen)
+	}
+
+	// Respuestas por correo que reenvía el proveedor del buzón de respuestas; se autentica con
+	// su propio token porque no tiene clave de API
+	if config.Correo.DireccionRespuestas != "" {
+		v1.POST("/correo/entrante", controladorCorreoEntrante.Recibir)
+	}
+
+	// Acuses de entrega de Twilio; se autentican con la firma de Twilio porque no tienen clave de API
+	if enviadorTwilio != nil && config.Twilio.URLAcuses != "" {
+		v1.POST("/twilio/acuses", controlador.NuevoControladorTwilio(enviadorTwilio).RecibirAcuse)
+	}
+
+	// Eventos de SendGrid; se autentican con la firma del Event Webhook porque no tienen clave de API
+	if enviadorSendGrid != nil {
+		v1.POST("/sendgrid/eventos", controlador.NuevoControladorSendGrid(enviadorSendGrid).RecibirEventos)
+	}
+
+	// Rebotes y quejas de SES publicados por SNS; se autentican con la firma de SNS y el tema
+	if enviadorSES != nil && len(config.SES.TemasSNS) > 0 {
+		v1.POST("/ses/sns", controlador.NuevoControladorSES(enviadorSES).RecibirNotificacion)
+	}
+
+	// Acuses y eventos de entrega de todos los proveedores en una sola ruta; cada uno se autentica
+	// con su propia firma. Mailgun siempre está: un inquilino puede usar su relay con su propia clave.
+	receptoresWebhook := map[string]controlador.ReceptorWebhookProveedor{
+		correo.ProveedorMailgun: correo.NuevoReceptorMailgun(config.Mailgun, casoUsoEventosCorreo, casoUsoCredenciales, relojSistema),
+	}
+	if enviadorTwilio != nil && config.Twilio.URLAcuses != "" {
+		receptoresWebhook[twilio.ProveedorTwilio] = enviadorTwilio
+	}
+	if enviadorSendGrid != nil {
+		receptoresWebhook[correo.ProveedorSendGrid] = enviadorSendGrid
+	}
+	if enviadorSES != nil && len(config.SES.TemasSNS) > 0 {
+		receptoresWebhook[correo.ProveedorSES] = enviadorSES
+	}
+	v1.POST("/webhooks/proveedores/:proveedor", controlador.NuevoControladorWebhooksProveedores(receptoresWebhook).Recibir)
+
+	// Webhook del bot de Telegram; se autentica con el secret_token registrado en setWebhook
+	if controladorTelegram != nil {
+		v1.POST("/telegram/webhook", controladorTelegram.RecibirActualizacion)
+	}
+
+	// Receptor de webhooks de prueba para integradores, solo fuera de producción y sin clave de API:
+	// quien envía los webhooks no la conoce
+	if config.Eco.Habilitado {
+		controladorEco := controlador.NuevoControladorEco(eco.NuevoRegistro(config.Eco), relojSistema)
+		v1.Any("/eco/:buzon", controladorEco.RecibirEntrega)
+		v1.GET("/eco/:buzon/entregas", controladorEco.ListarEntregas)
+		v1.DELETE("/eco/:buzon/entregas", controladorEco.VaciarBuzon)
+	}
+
+	// WebSocket para notificaciones en tiempo real; se autentica con el JWT del usuario, no con
+	// una clave de API
+	v1.GET("/ws", controladorWebSocket.ManejarWebSocket)
+
+	// Autenticación por clave de API e inquilino de la solicitud para las rutas siguientes; v2
+	// comparte la cadena, así el límite de solicitudes cuenta ambas versiones juntas
+	autenticacion := []gin.HandlerFunc{
+		middleware.AutenticacionClaveAPI(casoUsoClaves, config.Admin.Token),
+		middleware.IdentificarInquilino(),
+		middleware.AislarInquilino(repositorioInquilino),
+		middleware.IdentificarActor(repositorioUsuario, config.JWT.Secreto),
+		middleware.LimitarSolicitudes(casoUsoLimite, relojSistema),
+	}
+	v1.Use(autenticacion...)
 
 	// Rutas de notificaciones
 	notificaciones := v1.Group("/notificaciones")
+	if !config.VersionesAPI.ObsoletaV1.IsZero() {
+		notificaciones.Use(middleware.Obsoleta(config.VersionesAPI.ObsoletaV1, config.VersionesAPI.RetiroV1, "/api/v1", "/api/v2"))
+	}
+	aplicarCompresion(notificaciones, "notificaciones", config)
 	{
 		notificaciones.POST("", controladorNotificacion.EnviarNotificacion)
+		notificaciones.POST("/simular", controladorNotificacion.SimularNotificacion)
 		notificaciones.GET("", controladorNotificacion.ObtenerNotificaciones)
+		notificaciones.GET("/no-leidas", controladorNotificacion.ContarNoLeidas)
+		notificaciones.GET("/inbox", controladorBandeja.ObtenerInbox)
 		notificaciones.GET("/:id", controladorNotificacion.ObtenerNotificacionPorID)
 		notificaciones.PUT("/:id/marcar-leida", controladorNotificacion.MarcarComoLeida)
+		notificaciones.POST("/:id/cancelar", controladorNotificacion.CancelarNotificacion)
+		notificaciones.POST("/:id/reprogramar", controladorNotificacion.ReprogramarNotificacion)
+		notificaciones.POST("/:id/mover", controladorBandeja.MoverNotificacion)
+		notificaciones.POST("/:id/destacada", controladorBandeja.DestacarNotificacion)
+		notificaciones.DELETE("/:id/destacada", controladorBandeja.QuitarDestacada)
+		notificaciones.GET("/:id/reacciones", controladorReaccion.ListarReacciones)
+		notificaciones.POST("/:id/reacciones", controladorReaccion.Reaccionar)
+		notificaciones.DELETE("/:id/reacciones/:reaccion", controladorReaccion.QuitarReaccion)
+		notificaciones.GET("/:id/respuestas", controladorRespuesta.ObtenerHilo)
+		notificaciones.POST("/:id/respuestas", controladorRespuesta.Responder)
+		notificaciones.GET("/:id/enlaces", controladorEnlaceCorto.ListarEnlaces)
+		notificaciones.GET("/:id/adjuntos", controladorAdjunto.ListarDeNotificacion)
 		notificaciones.DELETE("/:id", controladorNotificacion.EliminarNotificacion)
 	}
 
-	// WebSocket para notificaciones en tiempo real
-	v1.GET("/ws", controladorWebSocket.ManejarWebSocket)
+	// Grupo de API v2: mismos servicios que v1 con los DTO corregidos; las rutas de v1 con
+	// reemplazo aquí se anuncian obsoletas
+	v2 := router.Group("/api/v2")
+	v2.Use(autenticacion...)
+	controladorNotificacionV2 := controladorNotificacion.V2()
+	notificacionesV2 := v2.Group("/notificaciones")
+	aplicarCompresion(notificacionesV2, "notificaciones", config)
+	{
+		notificacionesV2.POST("", controladorNotificacionV2.EnviarNotificacion)
+		notificacionesV2.POST("/simular", controladorNotificacionV2.SimularNotificacion)
+		notificacionesV2.GET("", controladorNotificacionV2.ObtenerNotificaciones)
+		notificacionesV2.GET("/no-leidas", controladorNotificacionV2.ContarNoLeidas)
+		notificacionesV2.GET("/:id", controladorNotificacionV2.ObtenerNotificacionPorID)
+		notificacionesV2.POST("/:id/marcar-leida", controladorNotificacionV2.MarcarComoLeida)
+		notificacionesV2.POST("/:id/cancelar", controladorNotificacionV2.CancelarNotificacion)
+		notificacionesV2.POST("/:id/reprogramar", controladorNotificacionV2.ReprogramarNotificacion)
+		notificacionesV2.DELETE("/:id", controladorNotificacionV2.EliminarNotificacion)
+	}
+
+	// Rutas de envíos multicanal
+	envios := v1.Group("/envios")
+	{
+		envios.POST("", controladorEnvio.CrearEnvio)
+		envios.GET("/:id", controladorEnvio.ObtenerEnvio)
+	}
+
+	// Adjuntos: se suben antes de crear la notificación que los lleva en adjunto_ids
+	if config.Adjuntos.Bucket != "" {
+		adjuntos := v1.Group("/adjuntos")
+		{
+			adjuntos.POST("", controladorAdjunto.Subir)
+			adjuntos.GET("/:id", controladorAdjunto.Obtener)
+			adjuntos.DELETE("/:id", controladorAdjunto.Eliminar)
+		}
+	}
+
+	// Rutas de canales
+	canales := v1.Group("/canales")
+	{
+		canales.POST("/:id/difusiones", controladorDifusion.Difundir)
+		canales.GET("/:id/difusiones/:difusionId", controladorDifusion.ObtenerProgreso)
+		canales.POST("/:id/borradores-difusion", controladorBorradorDifusion.Crear)
+		canales.GET("/:id/borradores-difusion", controladorBorradorDifusion.Listar)
+		canales.GET("/:id/borradores-difusion/:borradorId", controladorBorradorDifusion.Obtener)
+		canales.PUT("/:id/borradores-difusion/:borradorId", controladorBorradorDifusion.Editar)
+		canales.POST("/:id/borradores-difusion/:borradorId/solicitar-aprobacion", controladorBorradorDifusion.SolicitarAprobacion)
+		canales.POST("/:id/borradores-difusion/:borradorId/aprobar", controladorBorradorDifusion.Aprobar)
+		canales.POST("/:id/borradores-difusion/:borradorId/rechazar", controladorBorradorDifusion.Rechazar)
+		canales.POST("/:id/borradores-difusion/:borradorId/difundir", controladorBorradorDifusion.Difundir)
+		canales.GET("/:id/esquema-metadatos", controladorCanal.ObtenerEsquemaMetadatos)
+		canales.PUT("/:id/esquema-metadatos", controladorCanal.GuardarEsquemaMetadatos)
+		canales.DELETE("/:id/esquema-metadatos", controladorCanal.EliminarEsquemaMetadatos)
+		canales.GET("/:id/politica-escalamiento", controladorEscalamiento.ObtenerPolitica)
+		canales.PUT("/:id/politica-escalamiento", controladorEscalamiento.GuardarPolitica)
+		canales.DELETE("/:id/politica-escalamiento", controladorEscalamiento.EliminarPolitica)
+		canales.POST("/:id/escalamientos", controladorEscalamiento.IniciarEscalamiento)
+		canales.GET("/:id/rotacion-guardia", controladorGuardia.ObtenerRotacion)
+		canales.PUT("/:id/rotacion-guardia", controladorGuardia.GuardarRotacion)
+		canales.DELETE("/:id/rotacion-guardia", controladorGuardia.EliminarRotacion)
+		canales.GET("/:id/rotacion-guardia/reemplazos", controladorGuardia.ListarReemplazos)
+		canales.POST("/:id/rotacion-guardia/reemplazos", controladorGuardia.CrearReemplazo)
+		canales.DELETE("/:id/rotacion-guardia/reemplazos/:reemplazoId", controladorGuardia.EliminarReemplazo)
+		canales.GET("/:id/guardia", controladorGuardia.ObtenerGuardia)
+		canales.PUT("/:id/privacidad", controladorSuscripcion.CambiarPrivacidad)
+		canales.POST("/:id/invitaciones", controladorSuscripcion.Invitar)
+		canales.GET("/:id/membresias", controladorSuscripcion.ListarMembresias)
+		canales.POST("/:id/membresias/:usuarioId/aprobar", controladorSuscripcion.AprobarIngreso)
+		canales.POST("/:id/membresias/:usuarioId/rechazar", controladorSuscripcion.RechazarIngreso)
+		canales.PUT("/:id/publicacion", controladorModeracion.CambiarPublicacion)
+		canales.POST("/:id/publicaciones", controladorModeracion.Publicar)
+		canales.GET("/:id/publicaciones", controladorModeracion.Listar)
+		canales.POST("/:id/publicaciones/:publicacionId/aprobar", controladorModeracion.Aprobar)
+		canales.POST("/:id/publicaciones/:publicacionId/rechazar", controladorModeracion.Rechazar)
+		canales.GET("/:id/reacciones", controladorReaccion.ResumirCanal)
+		canales.PUT("/:id/respuestas", controladorRespuesta.CambiarRespuestas)
+		canales.PUT("/:id/respuestas-correo", controladorCorreoEntrante.ConfigurarWebhook)
+		canales.PUT("/:id/slack", controladorSlack.Configurar)
+	}
+
+	// Rutas de escalamientos de guardia
+	escalamientos := v1.Group("/escalamientos")
+	{
+		escalamientos.GET("/:id", controladorEscalamiento.ObtenerEscalamiento)
+		escalamientos.POST("/:id/reconocer", controladorEscalamiento.ReconocerEscalamiento)
+	}
+
+	// Rutas de usuarios
+	usuarios := v1.Group("/usuarios")
+	aplicarCompresion(usuarios, "usuarios", config)
+	{
+		usuarios.DELETE("/:id", controladorUsuario.EliminarUsuario)
+		usuarios.DELETE("/:id/datos-personales", controladorUsuario.EliminarDatosPersonales)
+		usuarios.GET("/:id/datos-personales/certificado", controladorUsuario.ObtenerCertificadoEliminacion)
+		usuarios.GET("/:id/preferencias", controladorPreferencia.ObtenerPreferencias)
+		usuarios.PUT("/:id/preferencias", controladorPreferencia.GuardarPreferencia)
+		usuarios.GET("/:id/consentimientos", controladorConsentimiento.ListarConsentimientos)
+		usuarios.POST("/:id/consentimientos", controladorConsentimiento.OtorgarConsentimiento)
+		usuarios.POST("/:id/consentimientos/revocar", controladorConsentimiento.RevocarConsentimiento)
+		usuarios.GET("/:id/suscripciones", controladorSuscripcion.ListarSuscripciones)
+		usuarios.POST("/:id/suscripciones", controladorSuscripcion.Suscribir)
+		usuarios.DELETE("/:id/suscripciones/:canalId", controladorSuscripcion.Desuscribir)
+		usuarios.POST("/:id/solicitudes-ingreso", controladorSuscripcion.SolicitarIngreso)
+		usuarios.GET("/:id/bandeja", controladorBandeja.ListarBandeja)
+		usuarios.GET("/:id/bandeja/conteos", controladorBandeja.ContarBandeja)
+		usuarios.POST("/:id/web-push", controladorWebPush.Suscribir)
+		usuarios.DELETE("/:id/web-push", controladorWebPush.Desuscribir)
+		usuarios.GET("/:id/silenciamientos", controladorSilenciamiento.Listar)
+		usuarios.POST("/:id/silenciamientos", controladorSilenciamiento.Silenciar)
+		usuarios.DELETE("/:id/silenciamientos/:silenciamientoId", controladorSilenciamiento.Eliminar)
+		if controladorTelegram != nil {
+			usuarios.POST("/:id/telegram/enlace", controladorTelegram.CrearEnlace)
+			usuarios.DELETE("/:id/telegram", controladorTelegram.Desvincular)
+		}
+	}
+
+	// Rutas administrativas
+	controladorLog := controlador.NuevoControladorLog(logger)
+	controladorMigracion := controlador.NuevoControladorMigracion(migrador)
+	controladorSimulacion := controlador.NuevoControladorSimulacion(proveedoresSimulados)
+	controladorReenvio := controlador.NuevoControladorReenvio(casoUsoReenvio)
+	controladorColaMuerta := controlador.NuevoControladorColaMuerta(casoUsoColaMuerta)
+	controladorRuteo := controlador.NuevoControladorRuteo(casoUsoRuteo)
+	controladorPanel := controlador.NuevoControladorPanel(poolTrabajadores, repositorioNotificacion, repositorioIntento, casoUsoMarca, casoUsoCarga)
+	var buzonCaptura *correo.BuzonCaptura
+	if config.Correo.BuzonCaptura != "" {
+		buzonCaptura = correo.NuevoBuzonCaptura(config.Correo.BuzonCaptura, fabricaClientes.Cliente("buzon_captura"))
+	}
+	controladorCorreo := controlador.NuevoControladorCorreo(buzonCaptura)
+
+	admin := v1.Group("/admin")
+	admin.Use(middleware.AutenticacionAdmin(config.Admin.Token))
+	aplicarCompresion(admin, "admin", config)
+	{
+		admin.GET("/claves", controladorClaveAPI.ListarClaves)
+		admin.POST("/claves", controladorClaveAPI.CrearClave)
+		admin.DELETE("/claves/:id", controladorClaveAPI.RevocarClave)
+		admin.GET("/inquilinos/:id/uso", controladorInquilino.ObtenerUso)
+		admin.GET("/inquilinos/:id/sla", controladorInquilino.ObtenerSLA)
+		admin.GET("/inquilinos/:id/marca", controladorInquilino.ObtenerMarca)
+		admin.PUT("/inquilinos/:id/marca", controladorInquilino.GuardarMarca)
+		admin.POST("/inquilinos/:id/exportaciones", controladorExportacion.IniciarExportacion)
+		admin.GET("/inquilinos/:id/exportaciones/:exportacionId", controladorExportacion.ObtenerExportacion)
+		admin.GET("/inquilinos/:id/exportaciones/:exportacionId/archivo", controladorExportacion.DescargarExportacion)
+		admin.GET("/facturacion", controladorFacturacion.ExportarFacturacion)
+		admin.GET("/accesos", controladorAcceso.ListarAccesos)
+		admin.GET("/inquilinos/:id/credenciales", controladorInquilino.ListarCredenciales)
+		admin.PUT("/inquilinos/:id/credenciales/:proveedor", controladorInquilino.GuardarCredencial)
+		admin.DELETE("/inquilinos/:id/credenciales/:proveedor", controladorInquilino.EliminarCredencial)
+		admin.GET("/supresiones", controladorSupresion.BuscarSupresiones)
+		admin.POST("/supresiones", controladorSupresion.AgregarSupresion)
+		admin.POST("/supresiones/importar", controladorSupresion.ImportarSupresiones)
+		admin.DELETE("/supresiones/:id", controladorSupresion.EliminarSupresion)
+		admin.POST("/reenvios", controladorReenvio.ReenviarFallidas)
+		admin.GET("/dlq", controladorColaMuerta.ListarEntradas)
+		admin.POST("/dlq/:id/reintentar", controladorColaMuerta.ReintentarEntrada)
+		admin.GET("/cola", controladorPanel.ObtenerCola)
+		admin.GET("/notificaciones/fallidas", controladorPanel.ListarFallidas)
+		admin.POST("/plantillas/vista-previa", controladorPanel.VistaPreviaPlantilla)
+		admin.POST("/plantillas/vista-previa/canales", controladorPanel.VistaPreviaCanales)
+		admin.POST("/notificaciones", controladorNotificacion.EnviarNotificacion)
+		admin.POST("/notificaciones/simular", controladorNotificacion.SimularNotificacion)
+		admin.GET("/usuarios/:id/ruteo", controladorRuteo.ExplicarRuteo)
+	}
+
+	// Rutas exclusivas del administrador de plataforma
+	plataforma := admin.Group("")
+	plataforma.Use(middleware.RequerirAdminPlataforma())
+	{
+		plataforma.GET("/log/niveles", controladorLog.ObtenerNiveles)
+		plataforma.PUT("/log/niveles", controladorLog.ActualizarNivel)
+		plataforma.DELETE("/log/niveles/:componente", controladorLog.RestablecerNivel)
+		plataforma.GET("/migraciones", controladorMigracion.ObtenerEstado)
+		plataforma.GET("/cola/carga", controladorPanel.ObtenerCarga)
+		plataforma.GET("/notificaciones/exportar", controladorNotificacion.ExportarNotificaciones)
+		plataforma.GET("/accesos/exportar", controladorAcceso.ExportarAccesos)
+		plataforma.POST("/inquilinos", controladorInquilino.AprovisionarInquilino)
+		plataforma.PUT("/inquilinos/:id/cuotas", controladorInquilino.GuardarCuota)
+		plataforma.PUT("/inquilinos/:id/sla", controladorInquilino.GuardarObjetivoSLA)
+		plataforma.GET("/retencion", controladorRetencion.ListarPoliticas)
+		plataforma.PUT("/retencion", controladorRetencion.GuardarPolitica)
+		plataforma.DELETE("/retencion/:id", controladorRetencion.EliminarPolitica)
+		plataforma.GET("/reportes/operativo", controladorReporte.ObtenerReporteOperativo)
+		plataforma.GET("/credenciales", controladorCredencialPlataforma.Listar)
+		plataforma.PUT("/credenciales/:proveedor/:nombre", controladorCredencialPlataforma.Guardar)
+		plataforma.POST("/credenciales/:proveedor/rotar", controladorCredencialPlataforma.Rotar)
+		plataforma.POST("/credenciales/:proveedor/:nombre/activar", controladorCredencialPlataforma.Activar)
+		plataforma.DELETE("/credenciales/:proveedor/:nombre", controladorCredencialPlataforma.Eliminar)
+	}
+
+	// Inyección de fallas en los proveedores simulados
+	if len(proveedoresSimulados) > 0 {
+		plataforma.GET("/simulacion/proveedores", controladorSimulacion.ListarProveedores)
+		plataforma.PUT("/simulacion/proveedores/:tipo/fallas", controladorSimulacion.ConfigurarFallas)
+		plataforma.DELETE("/simulacion/proveedores/:tipo", controladorSimulacion.ReiniciarProveedor)
+	}
+
+	// Envíos registrados en modo sandbox; cada inquilino ve los suyos con su clave de API
+	if config.Simulacion.Sandbox {
+		v1.GET("/sandbox/envios", controladorSimulacion.ListarEnvios)
+	}
+
+	// Correos capturados por MailHog o Mailpit en desarrollo
+	if buzonCaptura != nil {
+		plataforma.GET("/correo/capturados", controladorCorreo.ListarCapturados)
+	}
+}
+
+// prepararEsquema aplica las migraciones pendientes o verifica que no las haya, según el modo
+// configurado: en modo requerida el servicio no arranca con el esquema desactualizado
+func prepararEsquema(migrador *persistencia.Migrador, logger *logger.Logger) {
+	ctx := context.Background()
+	if migrador.Modo() == configuracion.MigracionAutomatica {
+		if err := migrador.Migrar(ctx); err != nil {
+			logger.Fatal("Error aplicando migraciones", "error", err)
+		}
+		logger.Info("Migraciones aplicadas")
+		return
+	}
+
+	estado, err := migrador.Estado(ctx)
+	if err != nil {
+		logger.Fatal("Error consultando el estado de las migraciones", "error", err)
+	}
+	if estado.AlDia {
+		return
+	}
+	pendientes := 0
+	for _, base := range estado.Bases {
+		pendientes += len(base.Pendientes)
+	}
+	if migrador.Modo() == configuracion.MigracionRequerida {
+		logger.Fatal("Esquema desactualizado: aplique las migraciones con \"notificaciones migrar\"",
+			"version_esperada", estado.VersionEsperada,
+			"pendientes", pendientes,
+		)
+	}
+	logger.Warn("Esquema desactualizado en modo solo lectura",
+		"version_esperada", estado.VersionEsperada,
+		"pendientes", pendientes,
+	)
+}
+
+// crearAlmacenExportaciones prepara el directorio donde quedan los archivos exportados
+func crearAlmacenExportaciones(config *configuracion.Configuracion, logger *logger.Logger) *almacenamiento.AlmacenLocal {
+	almacen, err := almacenamiento.NuevoAlmacenLocal(config.Exportacion.Directorio)
+	if err != nil {
+		logger.Fatal("Error preparando el almacén de exportaciones", "error", err)
+	}
+	return almacen
+}
+
+// crearAlmacenAdjuntos prepara el almacén S3 de los adjuntos y, si hay clamd, su antivirus; sin
+// bucket configurado los adjuntos quedan deshabilitados
+func crearAlmacenAdjuntos(config *configuracion.Configuracion, fabricaClientes *clienteHTTP.FabricaClientes, rel reloj.Reloj, logger *logger.Logger) (repositorio.AlmacenObjetos, repositorio.AnalizadorArchivos) {
+	if config.Adjuntos.Bucket == "" {
+		return nil, nil
+	}
+	almacen, err := almacenamiento.NuevoAlmacenS3(config.Adjuntos, fabricaClientes.Cliente(almacenamiento.ProveedorS3), rel)
+	if err != nil {
+		logger.Fatal("Error configurando el almacén de adjuntos", "error", err)
+	}
+	if config.Adjuntos.ClamAV == "" {
+		return almacen, nil
+	}
+	return almacen, almacenamiento.NuevoAnalizadorClamAV(config.Adjuntos.ClamAV)
+}
+
+// crearCifrador construye el cifrador de credenciales; sin clave configurada los inquilinos
+// usan siempre las credenciales de la plataforma
+func crearCifrador(config *configuracion.Configuracion, logger *logger.Logger) servicio.Cifrador {
+	if config.Cifrado.Clave == "" {
+		logger.Warn("CIFRADO_CLAVE no configurada, credenciales por inquilino deshabilitadas")
+		return nil
+	}
+	cifrador, err := cifrado.NuevoCifradorAES(config.Cifrado.Clave)
+	if err != nil {
+		logger.Fatal("Error configurando el cifrado", "error", err)
+	}
+	return cifrador
+}
+
+// aplicarCompresion agrega el middleware de compresión si el grupo está habilitado en la configuración
+func aplicarCompresion(grupo *gin.RouterGroup, nombre string, config *configuracion.Configuracion) {
+	if config.Compresion.AplicaA(nombre) {
+		grupo.Use(middleware.Compresion(config.Compresion))
+	}
 }
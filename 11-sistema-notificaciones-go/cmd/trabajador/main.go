@@ -0,0 +1,299 @@
+// Comando trabajador consume de RabbitMQ o de Redis Streams las notificaciones que publica el
+// servidor, las entrega por los proveedores externos y actualiza su estado. Se escala agregando
+// procesos: la cola reparte los mensajes entre ellos. Requiere RABBITMQ_URL o
+// REDIS_STREAMS_HABILITADO y la configuración de proveedores del servidor; las notificaciones
+// websocket e in_app siguen en el servidor, que tiene las conexiones.
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/internal/infraestructura/almacenamiento"
+	"sistema-notificaciones-go/internal/infraestructura/avisos"
+	"sistema-notificaciones-go/internal/infraestructura/cache"
+	"sistema-notificaciones-go/internal/infraestructura/clienteHTTP"
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+	"sistema-notificaciones-go/internal/infraestructura/correo"
+	"sistema-notificaciones-go/internal/infraestructura/kafka"
+	"sistema-notificaciones-go/internal/infraestructura/persistencia"
+	"sistema-notificaciones-go/internal/infraestructura/proveedores"
+	"sistema-notificaciones-go/internal/infraestructura/rabbitmq"
+	"sistema-notificaciones-go/internal/infraestructura/redisStreams"
+	"sistema-notificaciones-go/internal/infraestructura/simulacion"
+	"sistema-notificaciones-go/internal/infraestructura/slack"
+	"sistema-notificaciones-go/internal/infraestructura/smpp"
+	"sistema-notificaciones-go/internal/infraestructura/telegram"
+	"sistema-notificaciones-go/internal/infraestructura/twilio"
+	"sistema-notificaciones-go/internal/infraestructura/webPush"
+	"sistema-notificaciones-go/pkg/cifrado"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// consumidorCola consume la cola configurada hasta que termina el contexto de Iniciar
+type consumidorCola interface {
+	Iniciar(ctx context.Context)
+	Esperar()
+}
+
+func main() {
+	logger := logger.NuevoLogger()
+	logger.Info("Iniciando trabajador de notificaciones")
+
+	config, err := configuracion.CargarConfiguracion()
+	if err != nil {
+		logger.Fatal("Error cargando configuración", "error", err)
+	}
+	if err := logger.Niveles().Aplicar(config.Log.Nivel, config.Log.NivelesComponentes); err != nil {
+		logger.Fatal("Error configurando niveles de log", "error", err)
+	}
+	if !config.DespachoExterno() {
+		logger.Fatal("El trabajador requiere RABBITMQ_URL o REDIS_STREAMS_HABILITADO")
+	}
+
+	db, err := persistencia.NuevaConexion(config.BaseDatos)
+	if err != nil {
+		logger.Fatal("Error conectando a la base de datos", "error", err)
+	}
+	clienteRedis := cache.NuevoClienteRedis(config.Redis)
+
+	// Los cambios a enviada y fallida ocurren aquí: sin publicarlos, los eventos quedarían incompletos
+	var publicadorCicloVida *kafka.PublicadorCicloVida
+	if len(config.Kafka.Brokers) > 0 {
+		publicadorCicloVida = kafka.NuevoPublicadorCicloVida(config.Kafka, reloj.NuevoRelojSistema(), logger)
+	}
+
+	// El esquema lo migra el servidor o "notificaciones migrar"; el trabajador solo lo usa
+	tareas := casoUso.NuevasTareasSegundoPlano()
+	procesador := crearProcesador(config, db, clienteRedis, publicadorCicloVida, tareas, logger)
+
+	// Métricas de Prometheus de los envíos del proceso
+	go func() {
+		servidorMetricas := http.NewServeMux()
+		servidorMetricas.Handle("/metrics", promhttp.Handler())
+		if err := http.ListenAndServe(":"+config.RabbitMQ.PuertoMetricas, servidorMetricas); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("Error sirviendo métricas", "error", err)
+		}
+	}()
+
+	// Al recibir la señal deja de consumir y completa las entregas en curso
+	ctx, detener := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer detener()
+	var consumidor consumidorCola
+	if config.RedisStreams.Habilitado {
+		consumidor = redisStreams.NuevoConsumidorRedisStreams(config.RedisStreams, clienteRedis, procesador, logger)
+	} else {
+		consumidor = rabbitmq.NuevoConsumidorRabbitMQ(config.RabbitMQ, procesador, logger)
+	}
+	consumidor.Iniciar(ctx)
+
+	<-ctx.Done()
+	logger.Info("Deteniendo trabajador: completando las entregas en curso")
+	consumidor.Esperar()
+	tareas.Esperar()
+	if publicadorCicloVida != nil {
+		if err := publicadorCicloVida.Cerrar(); err != nil {
+			logger.Error("Error enviando los eventos pendientes a Kafka", "error", err)
+		}
+	}
+	logger.Info("Trabajador detenido")
+}
+
+// crearProcesador arma el despacho con los mismos proveedores externos que el servidor. Con
+// publicadorCicloVida, los cambios de estado se publican en Kafka.
+func crearProcesador(config *configuracion.Configuracion, db *gorm.DB, clienteRedis *redis.Client, publicadorCicloVida *kafka.PublicadorCicloVida, tareas *casoUso.TareasSegundoPlano, logger *logger.Logger) *casoUso.CasoUsoOrquestarEnvio {
+	relojSistema := reloj.NuevoRelojSistema()
+
+	// Caches con invalidación entre instancias: los cambios hechos en el servidor llegan aquí
+	cacheCanales := cache.NuevoCacheDosNiveles("canales", clienteRedis, config.Cache, relojSistema, logger)
+	cachePreferencias := cache.NuevoCacheDosNiveles("preferencias", clienteRedis, config.Cache, relojSistema, logger)
+	cacheInquilinos := cache.NuevoCacheDosNiveles("inquilinos", clienteRedis, config.Cache, relojSistema, logger)
+	go cache.EscucharInvalidaciones(context.Background(), clienteRedis, cacheCanales, cachePreferencias, cacheInquilinos)
+
+	// Repositorios
+	var repositorioNotificacion repositorio.RepositorioNotificacion = cache.NuevoRepositorioNotificacionNoLeidas(persistencia.NuevoRepositorioNotificacionPostgres(db), clienteRedis, config.NoLeidas.TTL, logger)
+	if publicadorCicloVida != nil {
+		repositorioNotificacion = kafka.NuevoRepositorioNotificacionEventos(repositorioNotificacion, publicadorCicloVida)
+	}
+	repositorioCanal := cache.NuevoRepositorioCanalCacheado(persistencia.NuevoRepositorioCanalPostgres(db), cacheCanales)
+	repositorioUsuario := persistencia.NuevoRepositorioUsuarioPostgres(db)
+	repositorioDispositivo := persistencia.NuevoRepositorioDispositivoPostgres(db)
+	repositorioInquilino := cache.NuevoRepositorioInquilinoCacheado(persistencia.NuevoRepositorioInquilinoPostgres(db), cacheInquilinos)
+
+	// Inquilinos aislados y con residencia de datos, igual que en el servidor
+	basesRegionales := make(map[string]configuracion.ConfiguracionBaseDatos, len(config.Regiones))
+	backendsRegionales := make(servicio.BackendsRegionales, len(config.Regiones))
+	for nombre, region := range config.Regiones {
+		basesRegionales[nombre] = region.BaseDatos
+		backendsRegionales[nombre] = region.Proveedores
+	}
+	persistencia.HabilitarAislamiento(persistencia.NuevoRegistroEsquemas(config.BaseDatos, basesRegionales))
+
+	fabricaClientes := clienteHTTP.NuevaFabricaClientes(config.HTTP)
+	casoUsoCodigosQR := casoUso.NuevoCasoUsoCodigosQR(
+		repositorioNotificacion,
+		repositorioInquilino,
+		config.CodigosQR.Campo,
+		config.CodigosQR.URLBase,
+		config.CodigosQR.Secreto,
+		config.CodigosQR.Vigencia,
+		config.CodigosQR.Escala,
+		relojSistema,
+		logger,
+	)
+	casoUsoAdjuntos := casoUso.NuevoCasoUsoAdjuntos(
+		persistencia.NuevoRepositorioAdjuntoPostgres(db),
+		repositorioNotificacion,
+		crearAlmacenAdjuntos(config, fabricaClientes, relojSistema, logger),
+		nil,
+		config.Adjuntos.MaxBytes,
+		config.Adjuntos.Tipos,
+		config.Adjuntos.VigenciaURL,
+		relojSistema,
+		logger,
+	)
+
+	// Proveedores externos; el orden de registro es el del servidor, el último registrado gana
+	registroProveedores := proveedores.NuevoRegistroProveedores()
+	registroProveedores.Registrar(slack.NuevoEnviadorSlack(config.Slack, repositorioCanal, fabricaClientes.Cliente(slack.ProveedorSlack)))
+	if config.Telegram.Token != "" {
+		registroProveedores.Registrar(telegram.NuevoEnviadorTelegram(config.Telegram, repositorioUsuario, fabricaClientes.Cliente(telegram.ProveedorTelegram)))
+	}
+	if config.Correo.Host != "" {
+		registroProveedores.Registrar(correo.NuevoEnviadorSMTP(config.Correo, repositorioUsuario, casoUsoCodigosQR, casoUsoAdjuntos, relojSistema))
+	}
+	if config.WebPush.ClavePrivada != "" {
+		enviadorWebPush, err := webPush.NuevoEnviadorWebPush(config.WebPush, repositorioDispositivo, fabricaClientes.Cliente(webPush.ProveedorWebPush), relojSistema, logger)
+		if err != nil {
+			logger.Fatal("Error configurando Web Push", "error", err)
+		}
+		registroProveedores.Registrar(enviadorWebPush)
+	}
+	casoUsoEnlaces := casoUso.NuevoCasoUsoEnlacesCortos(
+		persistencia.NuevoRepositorioEnlaceCortoPostgres(db),
+		repositorioNotificacion,
+		config.EnlacesCortos.URLBase,
+		config.EnlacesCortos.Vigencia,
+		config.EnlacesCortos.LongitudSlug,
+		relojSistema,
+		logger,
+	)
+	cifradorCredenciales := crearCifrador(config, logger)
+	casoUsoCredencialesPlataforma := casoUso.NuevoCasoUsoCredencialesPlataforma(
+		cache.NuevoRepositorioCredencialPlataformaCacheado(persistencia.NuevoRepositorioCredencialPlataformaPostgres(db), cacheInquilinos),
+		cifradorCredenciales,
+		relojSistema,
+	)
+	casoUsoCredenciales := casoUso.NuevoCasoUsoCredencialesProveedor(repositorioInquilino, casoUsoCredencialesPlataforma, cifradorCredenciales)
+	// Los acuses llegan al servidor: el registro de mensajes en Redis los une con lo enviado aquí
+	registroMensajes := cache.NuevoRegistroMensajesRedis(clienteRedis)
+	casoUsoAcuses := casoUso.NuevoCasoUsoAcusesEntrega(registroMensajes, repositorioNotificacion, repositorioInquilino, max(config.SMPP.VigenciaAcuses, config.Twilio.VigenciaAcuses), logger)
+	if config.Twilio.SIDCuenta != "" {
+		enviadorTwilio := twilio.NuevoEnviadorTwilio(config.Twilio, repositorioUsuario, registroMensajes, casoUsoAcuses, casoUsoCredenciales, casoUsoEnlaces, fabricaClientes.Cliente(twilio.ProveedorTwilio), logger)
+		registroProveedores.Registrar(enviadorTwilio, twilio.NuevoEnviadorVozTwilio(enviadorTwilio))
+	}
+	if config.SMPP.Host != "" {
+		registroProveedores.Registrar(smpp.NuevoEnviadorSMPP(config.SMPP, repositorioUsuario, registroMensajes, casoUsoAcuses, casoUsoEnlaces, logger))
+	}
+	avisadorLectura := avisos.NuevoAvisadorLectura(config.AvisosLectura, avisos.ClienteWebhooks(config.AvisosLectura, fabricaClientes), clienteRedis)
+	casoUsoEstado := casoUso.NuevoCasoUsoCambiarEstadoNotificacion(repositorioNotificacion, avisadorLectura, tareas, relojSistema, logger)
+	casoUsoSupresion := casoUso.NuevoCasoUsoListaSupresion(persistencia.NuevoRepositorioSupresionPostgres(db), repositorioUsuario, relojSistema)
+	casoUsoEventosCorreo := casoUso.NuevoCasoUsoEventosCorreo(repositorioNotificacion, repositorioInquilino, casoUsoEstado, casoUsoSupresion, logger)
+	if config.SendGrid.ClaveAPI != "" {
+		enviadorSendGrid, err := correo.NuevoEnviadorSendGrid(
+			config.SendGrid,
+			config.Correo,
+			repositorioUsuario,
+			casoUsoCodigosQR,
+			casoUsoAdjuntos,
+			casoUsoEventosCorreo,
+			casoUsoCredenciales,
+			fabricaClientes.Cliente(correo.ProveedorSendGrid),
+			relojSistema,
+			logger,
+		)
+		if err != nil {
+			logger.Fatal("Error configurando SendGrid", "error", err)
+		}
+		registroProveedores.Registrar(enviadorSendGrid)
+	}
+	if config.SES.Region != "" {
+		registroProveedores.Registrar(correo.NuevoEnviadorSES(
+			config.SES,
+			config.Correo,
+			repositorioUsuario,
+			casoUsoCodigosQR,
+			casoUsoAdjuntos,
+			registroMensajes,
+			casoUsoEventosCorreo,
+			fabricaClientes.Cliente(correo.ProveedorSES),
+			relojSistema,
+			logger,
+		))
+	}
+	for _, tipo := range config.Simulacion.Proveedores {
+		registroProveedores.Registrar(simulacion.NuevoProveedorSimulado(entidad.TipoNotificacion(tipo)))
+	}
+	logger.Info("Proveedores de notificación registrados", "tipos", registroProveedores.Tipos())
+
+	// Despacho
+	repositorioConsentimiento := persistencia.NuevoRepositorioConsentimientoPostgres(db)
+	casoUsoConsentimiento := casoUso.NuevoCasoUsoConsentimiento(repositorioConsentimiento, repositorioUsuario, repositorioCanal, relojSistema)
+	casoUsoSilenciamiento := casoUso.NuevoCasoUsoSilenciamiento(persistencia.NuevoRepositorioSilenciamientoPostgres(db), repositorioUsuario, repositorioCanal, relojSistema)
+	casoUsoDespachar := casoUso.NuevoCasoUsoDespacharNotificacion(
+		repositorioNotificacion,
+		persistencia.NuevoRepositorioIntentoEnvioPostgres(db),
+		persistencia.NuevoRepositorioConsumoPostgres(db),
+		persistencia.NuevoRepositorioColaMuertaPostgres(db),
+		registroProveedores,
+		casoUsoCredenciales,
+		casoUsoConsentimiento,
+		casoUsoSupresion,
+		casoUsoSilenciamiento,
+		casoUsoCodigosQR,
+		backendsRegionales,
+		config.Reintentos.Politica(),
+		relojSistema,
+		logger,
+	)
+	return casoUso.NuevoCasoUsoOrquestarEnvio(casoUsoDespachar, persistencia.NuevoRepositorioEnvioMultiCanalPostgres(db), repositorioNotificacion, repositorioInquilino, logger)
+}
+
+// crearAlmacenAdjuntos prepara el almacén S3 de donde el correo lee los adjuntos; el antivirus
+// solo corre al subirlos, en el servidor
+func crearAlmacenAdjuntos(config *configuracion.Configuracion, fabricaClientes *clienteHTTP.FabricaClientes, rel reloj.Reloj, logger *logger.Logger) repositorio.AlmacenObjetos {
+	if config.Adjuntos.Bucket == "" {
+		return nil
+	}
+	almacen, err := almacenamiento.NuevoAlmacenS3(config.Adjuntos, fabricaClientes.Cliente(almacenamiento.ProveedorS3), rel)
+	if err != nil {
+		logger.Fatal("Error configurando el almacén de adjuntos", "error", err)
+	}
+	return almacen
+}
+
+// crearCifrador construye el cifrador de credenciales; sin clave configurada los inquilinos
+// usan siempre las credenciales de la plataforma
+func crearCifrador(config *configuracion.Configuracion, logger *logger.Logger) servicio.Cifrador {
+	if config.Cifrado.Clave == "" {
+		return nil
+	}
+	cifrador, err := cifrado.NuevoCifradorAES(config.Cifrado.Clave)
+	if err != nil {
+		logger.Fatal("Error configurando el cifrado", "error", err)
+	}
+	return cifrador
+}
@@ -0,0 +1,91 @@
+package casoUso
+
+import (
+	"context"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/logger"
+)
+
+// CasoUsoAcusesEntrega aplica a las notificaciones enviadas los acuses de entrega que informan
+// los proveedores, p. ej. los delivery receipts de SMPP
+type CasoUsoAcusesEntrega struct {
+	registro                repositorio.RegistroMensajesProveedor
+	repositorioNotificacion repositorio.RepositorioNotificacion
+	repositorioInquilino    repositorio.RepositorioInquilino
+	vigencia                time.Duration
+	logger                  *logger.Logger
+}
+
+// NuevoCasoUsoAcusesEntrega crea una nueva instancia del caso de uso; vigencia es cuánto se
+// esperan los acuses de un mensaje
+func NuevoCasoUsoAcusesEntrega(
+	registro repositorio.RegistroMensajesProveedor,
+	repositorioNotificacion repositorio.RepositorioNotificacion,
+	repositorioInquilino repositorio.RepositorioInquilino,
+	vigencia time.Duration,
+	log *logger.Logger,
+) *CasoUsoAcusesEntrega {
+	return &CasoUsoAcusesEntrega{
+		registro:                registro,
+		repositorioNotificacion: repositorioNotificacion,
+		repositorioInquilino:    repositorioInquilino,
+		vigencia:                vigencia,
+		logger:                  log,
+	}
+}
+
+// Acusar marca la notificación como entregada cuando llegaron todas sus partes, o como fallida
+// si una no pudo entregarse. No programa reintento: el proveedor ya reintentó hasta rendirse.
+// Los acuses de mensajes sin registrar o vencidos se descartan.
+func (c *CasoUsoAcusesEntrega) Acusar(ctx context.Context, acuse entidad.AcuseEntrega) error {
+	referencia, err := c.registro.Resolver(ctx, acuse.Proveedor, acuse.IDMensaje)
+	if err != nil {
+		return err
+	}
+	if referencia == nil {
+		c.logger.Debug("Acuse de un mensaje sin registrar", "proveedor", acuse.Proveedor, "id_mensaje", acuse.IDMensaje)
+		return nil
+	}
+	if acuse.Entregado {
+		entregadas, err := c.registro.MarcarEntregado(ctx, acuse.Proveedor, acuse.IDMensaje, *referencia, c.vigencia)
+		if err != nil {
+			return err
+		}
+		if entregadas < int64(referencia.Partes) {
+			return nil
+		}
+	}
+
+	ctxDatos, err := servicio.ContextoDeInquilino(ctx, c.repositorioInquilino, referencia.InquilinoID)
+	if err != nil {
+		return err
+	}
+	notificacion, err := c.repositorioNotificacion.ObtenerPorID(ctxDatos, referencia.NotificacionID)
+	if err != nil {
+		return err
+	}
+	if notificacion.Estado != entidad.EstadoEnviada {
+		// Ya se leyó, expiró o la marcó otro acuse
+		return nil
+	}
+
+	if acuse.Entregado {
+		err = notificacion.MarcarComoEntregada()
+	} else {
+		c.logger.Warn("El proveedor no pudo entregar la notificación",
+			"notificacion_id", notificacion.ID,
+			"proveedor", acuse.Proveedor,
+			"estado", acuse.Estado,
+			"codigo_error", acuse.CodigoError,
+		)
+		err = notificacion.MarcarComoFallida()
+	}
+	if err != nil {
+		return err
+	}
+	return c.repositorioNotificacion.Actualizar(ctxDatos, notificacion)
+}
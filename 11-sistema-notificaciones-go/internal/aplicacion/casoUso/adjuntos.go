@@ -0,0 +1,260 @@
+package casoUso
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"slices"
+	"time"
+
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// CasoUsoAdjuntos guarda los archivos adjuntos de las notificaciones en el almacén de objetos,
+// con límites de tamaño y tipo y un análisis antivirus opcional, y firma sus URL de descarga
+type CasoUsoAdjuntos struct {
+	repositorioAdjunto      repositorio.RepositorioAdjunto
+	repositorioNotificacion repositorio.RepositorioNotificacion
+	almacen                 repositorio.AlmacenObjetos
+	analizador              repositorio.AnalizadorArchivos
+	maxBytes                int64
+	tipos                   []string
+	vigenciaURL             time.Duration
+	reloj                   reloj.Reloj
+	logger                  *logger.Logger
+}
+
+// NuevoCasoUsoAdjuntos crea el caso de uso. Sin almacén los adjuntos quedan deshabilitados;
+// sin analizador los archivos se guardan sin analizar.
+func NuevoCasoUsoAdjuntos(
+	repositorioAdjunto repositorio.RepositorioAdjunto,
+	repositorioNotificacion repositorio.RepositorioNotificacion,
+	almacen repositorio.AlmacenObjetos,
+	analizador repositorio.AnalizadorArchivos,
+	maxBytes int64,
+	tipos []string,
+	vigenciaURL time.Duration,
+	rel reloj.Reloj,
+	log *logger.Logger,
+) *CasoUsoAdjuntos {
+	return &CasoUsoAdjuntos{
+		repositorioAdjunto:      repositorioAdjunto,
+		repositorioNotificacion: repositorioNotificacion,
+		almacen:                 almacen,
+		analizador:              analizador,
+		maxBytes:                maxBytes,
+		tipos:                   tipos,
+		vigenciaURL:             vigenciaURL,
+		reloj:                   rel,
+		logger:                  log,
+	}
+}
+
+// Subir valida el archivo, lo analiza y lo guarda en el almacén a nombre del inquilino. Queda
+// sin notificación hasta que una lo incluya en adjunto_ids.
+func (c *CasoUsoAdjuntos) Subir(ctx context.Context, archivo dto.ArchivoSubido) (*entidad.Adjunto, error) {
+	if c.almacen == nil {
+		return nil, entidad.NewErrorValidacion("los adjuntos no están habilitados")
+	}
+	if archivo.Tamano <= 0 {
+		return nil, entidad.NewErrorValidacion("el archivo está vacío")
+	}
+	if archivo.Tamano > c.maxBytes {
+		return nil, entidad.NewErrorValidacion(fmt.Sprintf("el archivo supera el máximo de %d bytes", c.maxBytes))
+	}
+	tipo, _, err := mime.ParseMediaType(archivo.TipoContenido)
+	if err != nil || !slices.Contains(c.tipos, tipo) {
+		return nil, entidad.NewErrorValidacion(fmt.Sprintf("el tipo de archivo %s no está admitido", archivo.TipoContenido))
+	}
+
+	resumen := sha256.New()
+	if _, err := io.Copy(resumen, archivo.Contenido); err != nil {
+		return nil, err
+	}
+	if c.analizador != nil {
+		if _, err := archivo.Contenido.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		amenaza, err := c.analizador.Analizar(ctx, archivo.Contenido)
+		if err != nil {
+			return nil, fmt.Errorf("analizando el archivo: %w", err)
+		}
+		if amenaza != "" {
+			c.logger.Warn("Adjunto rechazado por el antivirus", "inquilino_id", servicio.InquilinoDesdeContexto(ctx), "amenaza", amenaza)
+			return nil, entidad.ErrArchivoInfectado
+		}
+	}
+
+	adjunto := &entidad.Adjunto{
+		InquilinoID:   servicio.InquilinoDesdeContexto(ctx),
+		Nombre:        entidad.NombreAdjunto(archivo.Nombre),
+		TipoContenido: archivo.TipoContenido,
+		Tamano:        archivo.Tamano,
+		SHA256:        hex.EncodeToString(resumen.Sum(nil)),
+	}
+	if adjunto.Clave, err = claveAdjunto(adjunto.InquilinoID, c.reloj.Ahora()); err != nil {
+		return nil, err
+	}
+	if _, err := archivo.Contenido.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if err := c.almacen.Subir(ctx, adjunto.Clave, adjunto.TipoContenido, archivo.Contenido, archivo.Tamano); err != nil {
+		return nil, fmt.Errorf("guardando el adjunto: %w", err)
+	}
+	if err := c.repositorioAdjunto.Crear(ctx, adjunto); err != nil {
+		c.eliminarObjeto(ctx, adjunto.Clave)
+		return nil, err
+	}
+	return adjunto, c.firmarURL(adjunto)
+}
+
+// Obtener retorna el adjunto con una URL de descarga firmada
+func (c *CasoUsoAdjuntos) Obtener(ctx context.Context, id uint) (*entidad.Adjunto, error) {
+	adjunto, err := c.autorizado(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return adjunto, c.firmarURL(adjunto)
+}
+
+// Listar retorna los adjuntos de la notificación con sus URL de descarga, p. ej. para el
+// cliente in-app
+func (c *CasoUsoAdjuntos) Listar(ctx context.Context, notificacionID uint) ([]entidad.Adjunto, error) {
+	notificacion, err := c.repositorioNotificacion.ObtenerPorID(ctx, notificacionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := servicio.AutorizarInquilino(ctx, notificacion.InquilinoID); err != nil {
+		return nil, err
+	}
+	adjuntos, err := c.ListarDeNotificacion(ctx, notificacion)
+	if err != nil {
+		return nil, err
+	}
+	for i := range adjuntos {
+		if err := c.firmarURL(&adjuntos[i]); err != nil {
+			return nil, err
+		}
+	}
+	return adjuntos, nil
+}
+
+// ListarDeNotificacion retorna los adjuntos de la notificación sin URL; lo usa el enviador de
+// correo, que los lee del almacén con Abrir
+func (c *CasoUsoAdjuntos) ListarDeNotificacion(ctx context.Context, notificacion *entidad.Notificacion) ([]entidad.Adjunto, error) {
+	if c.almacen == nil {
+		return nil, nil
+	}
+	return c.repositorioAdjunto.ListarPorNotificacion(ctx, notificacion.ID)
+}
+
+// Abrir lee el contenido del adjunto desde el almacén
+func (c *CasoUsoAdjuntos) Abrir(ctx context.Context, adjunto *entidad.Adjunto) (io.ReadCloser, error) {
+	return c.almacen.Abrir(ctx, adjunto.Clave)
+}
+
+// Validar comprueba antes de crear la notificación que los adjuntos existan, sean del inquilino
+// y no pertenezcan a otra notificación
+func (c *CasoUsoAdjuntos) Validar(ctx context.Context, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if c.almacen == nil {
+		return entidad.NewErrorValidacion("los adjuntos no están habilitados")
+	}
+	adjuntos, err := c.repositorioAdjunto.ListarPorIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	if len(adjuntos) != len(ids) {
+		return entidad.ErrAdjuntoNoEncontrado
+	}
+	for i := range adjuntos {
+		if err := servicio.AutorizarInquilino(ctx, adjuntos[i].InquilinoID); err != nil {
+			return err
+		}
+		if adjuntos[i].Vinculado() {
+			return entidad.ErrAdjuntoVinculado
+		}
+	}
+	return nil
+}
+
+// Vincular asigna los adjuntos a la notificación recién creada. Si otra notificación tomó
+// alguno entre Validar y Vincular, la nueva sale con los que quedaron libres.
+func (c *CasoUsoAdjuntos) Vincular(ctx context.Context, ids []uint, notificacion *entidad.Notificacion) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	vinculados, err := c.repositorioAdjunto.Vincular(ctx, notificacion.InquilinoID, ids, notificacion.ID)
+	if err != nil {
+		return err
+	}
+	if vinculados != int64(len(ids)) {
+		c.logger.Warn("Adjuntos tomados por otra notificación", "notificacion_id", notificacion.ID, "pedidos", len(ids), "vinculados", vinculados)
+	}
+	return nil
+}
+
+// Eliminar borra un adjunto que todavía no pertenece a ninguna notificación
+func (c *CasoUsoAdjuntos) Eliminar(ctx context.Context, id uint) error {
+	adjunto, err := c.autorizado(ctx, id)
+	if err != nil {
+		return err
+	}
+	if adjunto.Vinculado() {
+		return entidad.ErrAdjuntoVinculado
+	}
+	if err := c.almacen.Eliminar(ctx, adjunto.Clave); err != nil {
+		return err
+	}
+	return c.repositorioAdjunto.Eliminar(ctx, adjunto.ID)
+}
+
+func (c *CasoUsoAdjuntos) autorizado(ctx context.Context, id uint) (*entidad.Adjunto, error) {
+	adjunto, err := c.repositorioAdjunto.ObtenerPorID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := servicio.AutorizarInquilino(ctx, adjunto.InquilinoID); err != nil {
+		return nil, err
+	}
+	return adjunto, nil
+}
+
+// firmarURL completa la URL de descarga directa del almacén y su vencimiento
+func (c *CasoUsoAdjuntos) firmarURL(adjunto *entidad.Adjunto) error {
+	direccion, err := c.almacen.URLDescarga(adjunto.Clave, adjunto.Nombre, adjunto.TipoContenido, c.vigenciaURL)
+	if err != nil {
+		return err
+	}
+	vence := c.reloj.Ahora().Add(c.vigenciaURL)
+	adjunto.URLDescarga, adjunto.VenceURL = direccion, &vence
+	return nil
+}
+
+// eliminarObjeto borra un objeto que quedó sin datos en la base; si falla solo se registra
+func (c *CasoUsoAdjuntos) eliminarObjeto(ctx context.Context, clave string) {
+	if err := c.almacen.Eliminar(ctx, clave); err != nil {
+		c.logger.Warn("Error eliminando adjunto huérfano del almacén", "clave", clave, "error", err)
+	}
+}
+
+// claveAdjunto arma la clave del objeto: aleatoria, así no se puede adivinar ni revela el nombre
+// del archivo, y agrupada por inquilino y mes
+func claveAdjunto(inquilinoID uint, ahora time.Time) (string, error) {
+	aleatorio := make([]byte, 16)
+	if _, err := rand.Read(aleatorio); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("adjuntos/%d/%s/%s", inquilinoID, ahora.UTC().Format("2006/01"), hex.EncodeToString(aleatorio)), nil
+}
@@ -0,0 +1,152 @@
+package casoUso
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// CasoUsoAplicarRetencion administra las políticas de retención y purga las notificaciones
+// vencidas, eliminándolas o anonimizándolas según la acción de la política. Cada política se
+// aplica por separado, así una notificación alcanzada por varias sigue a la más restrictiva.
+type CasoUsoAplicarRetencion struct {
+	unidadTrabajo           repositorio.UnidadTrabajo
+	repositorioPolitica     repositorio.RepositorioPoliticaRetencion
+	repositorioNotificacion repositorio.RepositorioNotificacion
+	repositorioInquilino    repositorio.RepositorioInquilino
+	tamanoLote              int
+	secretoAnonimizacion    []byte
+	reloj                   reloj.Reloj
+}
+
+// NuevoCasoUsoAplicarRetencion crea una nueva instancia del caso de uso
+func NuevoCasoUsoAplicarRetencion(
+	unidadTrabajo repositorio.UnidadTrabajo,
+	repositorioPolitica repositorio.RepositorioPoliticaRetencion,
+	repositorioNotificacion repositorio.RepositorioNotificacion,
+	repositorioInquilino repositorio.RepositorioInquilino,
+	tamanoLote int,
+	secretoAnonimizacion string,
+	rel reloj.Reloj,
+) *CasoUsoAplicarRetencion {
+	return &CasoUsoAplicarRetencion{
+		unidadTrabajo:           unidadTrabajo,
+		repositorioPolitica:     repositorioPolitica,
+		repositorioNotificacion: repositorioNotificacion,
+		repositorioInquilino:    repositorioInquilino,
+		tamanoLote:              tamanoLote,
+		secretoAnonimizacion:    []byte(secretoAnonimizacion),
+		reloj:                   rel,
+	}
+}
+
+// ListarPoliticas retorna todas las políticas de retención
+func (c *CasoUsoAplicarRetencion) ListarPoliticas(ctx context.Context) ([]entidad.PoliticaRetencion, error) {
+	return c.repositorioPolitica.Listar(ctx)
+}
+
+// GuardarPolitica valida y crea o actualiza la política de su alcance; sin acción, elimina
+func (c *CasoUsoAplicarRetencion) GuardarPolitica(ctx context.Context, politica *entidad.PoliticaRetencion) error {
+	if politica.Accion == "" {
+		politica.Accion = entidad.AccionEliminar
+	}
+	if err := politica.Validar(); err != nil {
+		return err
+	}
+	if politica.Accion == entidad.AccionAnonimizar && len(c.secretoAnonimizacion) == 0 {
+		return entidad.ErrSinSecretoAnonimizacion
+	}
+	if politica.InquilinoID != 0 {
+		if _, err := c.repositorioInquilino.ObtenerPorID(ctx, politica.InquilinoID); err != nil {
+			return err
+		}
+	}
+	return c.repositorioPolitica.Guardar(ctx, politica)
+}
+
+// EliminarPolitica elimina una política de retención
+func (c *CasoUsoAplicarRetencion) EliminarPolitica(ctx context.Context, id uint) error {
+	return c.repositorioPolitica.Eliminar(ctx, id)
+}
+
+// PurgarVencidas aplica cada política en las tablas comunes de la base principal y de cada
+// región, y en el esquema de cada inquilino aislado. Retorna lo purgado por política y esquema
+// como constancia; un error en una política no impide aplicar las demás.
+func (c *CasoUsoAplicarRetencion) PurgarVencidas(ctx context.Context) ([]entidad.ResultadoPurga, error) {
+	politicas, err := c.repositorioPolitica.Listar(ctx)
+	if err != nil || len(politicas) == 0 {
+		return nil, err
+	}
+	contextos, err := servicio.ContextosDeDatos(ctx, c.repositorioInquilino)
+	if err != nil {
+		return nil, err
+	}
+
+	ahora := c.reloj.Ahora()
+	var resultados []entidad.ResultadoPurga
+	var errs []error
+	for i := range politicas {
+		politica := &politicas[i]
+		for _, ctxDatos := range contextos {
+			// El esquema de un inquilino aislado solo tiene sus propios datos
+			if inquilinoID := servicio.InquilinoDesdeContexto(ctxDatos); politica.InquilinoID != 0 && inquilinoID != 0 && inquilinoID != politica.InquilinoID {
+				continue
+			}
+			resultado, err := c.purgar(ctxDatos, politica, politica.Limite(ahora))
+			if resultado.Notificaciones > 0 {
+				resultados = append(resultados, resultado)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("política %d en región %q, esquema %q: %w", politica.ID, resultado.Region, resultado.Esquema, err))
+			}
+		}
+	}
+	return resultados, errors.Join(errs...)
+}
+
+// purgar elimina o anonimiza por lotes, cada uno en su transacción, hasta agotar las vencidas
+func (c *CasoUsoAplicarRetencion) purgar(ctx context.Context, politica *entidad.PoliticaRetencion, limite time.Time) (entidad.ResultadoPurga, error) {
+	resultado := entidad.ResultadoPurga{
+		Politica: *politica,
+		Region:   servicio.RegionDesdeContexto(ctx),
+		Esquema:  "public",
+		Limite:   limite,
+	}
+	if esquema := servicio.EsquemaDesdeContexto(ctx); esquema != "" {
+		resultado.Esquema = esquema
+	}
+	if politica.Accion == entidad.AccionAnonimizar && len(c.secretoAnonimizacion) == 0 {
+		return resultado, entidad.ErrSinSecretoAnonimizacion
+	}
+
+	for {
+		var notificaciones, intentos int64
+		err := c.unidadTrabajo.Ejecutar(ctx, func(ctx context.Context) error {
+			var err error
+			if politica.Accion == entidad.AccionAnonimizar {
+				notificaciones, intentos, err = c.repositorioNotificacion.AnonimizarPorRetencion(ctx, politica, limite, c.tamanoLote, c.huella)
+			} else {
+				notificaciones, intentos, err = c.repositorioNotificacion.PurgarPorRetencion(ctx, politica, limite, c.tamanoLote)
+			}
+			return err
+		})
+		if err != nil {
+			return resultado, err
+		}
+		resultado.Notificaciones += notificaciones
+		resultado.Intentos += intentos
+		if notificaciones < int64(c.tamanoLote) {
+			return resultado, nil
+		}
+	}
+}
+
+func (c *CasoUsoAplicarRetencion) huella(usuarioID uint) string {
+	return servicio.HuellaUsuario(c.secretoAnonimizacion, usuarioID)
+}
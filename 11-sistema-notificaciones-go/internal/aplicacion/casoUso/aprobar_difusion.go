@@ -0,0 +1,239 @@
+package casoUso
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// errBorradorModificado indica que otra solicitud cambió el estado del borrador entre la lectura y la escritura
+var errBorradorModificado = entidad.NewErrorDominio("la difusión cambió de estado, vuelva a consultarla")
+
+// CasoUsoAprobacionDifusion lleva una difusión de borrador a pendiente de aprobación, aprobada
+// y enviada. Quien la aprueba debe ser una persona distinta de quien la envió a aprobación;
+// ambas deben autenticarse con su propio token, no basta con X-Actor-ID, y quien aprueba o
+// rechaza debe ser administrador o moderador.
+type CasoUsoAprobacionDifusion struct {
+	repositorioBorrador repositorio.RepositorioBorradorDifusion
+	repositorioCanal    repositorio.RepositorioCanal
+	difundir            *CasoUsoDifundirCanal
+	reloj               reloj.Reloj
+}
+
+// NuevoCasoUsoAprobacionDifusion crea una nueva instancia del caso de uso
+func NuevoCasoUsoAprobacionDifusion(
+	repositorioBorrador repositorio.RepositorioBorradorDifusion,
+	repositorioCanal repositorio.RepositorioCanal,
+	difundir *CasoUsoDifundirCanal,
+	rel reloj.Reloj,
+) *CasoUsoAprobacionDifusion {
+	return &CasoUsoAprobacionDifusion{
+		repositorioBorrador: repositorioBorrador,
+		repositorioCanal:    repositorioCanal,
+		difundir:            difundir,
+		reloj:               rel,
+	}
+}
+
+// Crear guarda la difusión como borrador del canal
+func (c *CasoUsoAprobacionDifusion) Crear(ctx context.Context, canalID uint, solicitud dto.SolicitudDifusion) (*entidad.BorradorDifusion, error) {
+	canal, err := c.repositorioCanal.ObtenerPorID(ctx, canalID)
+	if err != nil {
+		return nil, err
+	}
+	if err := servicio.AutorizarInquilino(ctx, canal.InquilinoID); err != nil {
+		return nil, err
+	}
+	borrador := &entidad.BorradorDifusion{
+		InquilinoID: canal.InquilinoID,
+		CanalID:     canal.ID,
+		Titulo:      solicitud.Titulo,
+		Mensaje:     solicitud.Mensaje,
+		Tipo:        solicitud.Tipo,
+		Prioridad:   solicitud.Prioridad,
+		Metadatos:   solicitud.Metadatos,
+		Estado:      entidad.EstadoBorradorEditable,
+	}
+	if actor, ok := servicio.ActorDesdeContexto(ctx); ok {
+		borrador.CreadoPor = actor.ID
+	}
+	if err := borrador.Validar(); err != nil {
+		return nil, err
+	}
+	if err := c.repositorioBorrador.Crear(ctx, borrador); err != nil {
+		return nil, err
+	}
+	return borrador, nil
+}
+
+// Obtener retorna un borrador del canal
+func (c *CasoUsoAprobacionDifusion) Obtener(ctx context.Context, canalID, id uint) (*entidad.BorradorDifusion, error) {
+	borrador, err := c.repositorioBorrador.ObtenerPorID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if borrador.CanalID != canalID {
+		return nil, entidad.ErrBorradorDifusionNoEncontrado
+	}
+	if err := servicio.AutorizarInquilino(ctx, borrador.InquilinoID); err != nil {
+		return nil, err
+	}
+	return borrador, nil
+}
+
+// Listar retorna los borradores del canal, opcionalmente en un estado
+func (c *CasoUsoAprobacionDifusion) Listar(ctx context.Context, canalID uint, estado entidad.EstadoBorradorDifusion) ([]entidad.BorradorDifusion, error) {
+	canal, err := c.repositorioCanal.ObtenerPorID(ctx, canalID)
+	if err != nil {
+		return nil, err
+	}
+	if err := servicio.AutorizarInquilino(ctx, canal.InquilinoID); err != nil {
+		return nil, err
+	}
+	return c.repositorioBorrador.ListarPorCanal(ctx, canalID, estado)
+}
+
+// Editar reemplaza el contenido de un borrador que no está en aprobación
+func (c *CasoUsoAprobacionDifusion) Editar(ctx context.Context, canalID, id uint, solicitud dto.SolicitudDifusion) (*entidad.BorradorDifusion, error) {
+	borrador, err := c.Obtener(ctx, canalID, id)
+	if err != nil {
+		return nil, err
+	}
+	anterior := borrador.Estado
+	if err := borrador.Editar(solicitud.Titulo, solicitud.Mensaje, solicitud.Tipo, solicitud.Prioridad, solicitud.Metadatos); err != nil {
+		return nil, err
+	}
+	return c.actualizar(ctx, borrador, anterior)
+}
+
+// SolicitarAprobacion envía el borrador a aprobación en nombre del actor autenticado
+func (c *CasoUsoAprobacionDifusion) SolicitarAprobacion(ctx context.Context, canalID, id uint) (*entidad.BorradorDifusion, error) {
+	actor, ok := servicio.ActorAutenticadoDesdeContexto(ctx)
+	if !ok {
+		return nil, entidad.ErrActorNoAutenticado
+	}
+	borrador, err := c.Obtener(ctx, canalID, id)
+	if err != nil {
+		return nil, err
+	}
+	anterior := borrador.Estado
+	if err := borrador.SolicitarAprobacion(actor.ID, c.reloj.Ahora()); err != nil {
+		return nil, err
+	}
+	return c.actualizar(ctx, borrador, anterior)
+}
+
+// Aprobar aprueba el borrador en nombre del actor y lo difunde. Si la difusión no puede
+// iniciarse, p. ej. por la cuota, el borrador queda aprobado para difundirlo más tarde.
+func (c *CasoUsoAprobacionDifusion) Aprobar(ctx context.Context, canalID, id uint) (*entidad.BorradorDifusion, *ProgresoDifusion, error) {
+	actor, err := aprobador(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	borrador, err := c.Obtener(ctx, canalID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	anterior := borrador.Estado
+	if err := borrador.Aprobar(actor.ID, c.reloj.Ahora()); err != nil {
+		return nil, nil, err
+	}
+	if borrador, err = c.actualizar(ctx, borrador, anterior); err != nil {
+		return nil, nil, err
+	}
+	progreso, err := c.enviar(ctx, borrador)
+	return borrador, progreso, err
+}
+
+// Rechazar devuelve el borrador a edición con el motivo, en nombre del actor
+func (c *CasoUsoAprobacionDifusion) Rechazar(ctx context.Context, canalID, id uint, motivo string) (*entidad.BorradorDifusion, error) {
+	actor, err := aprobador(ctx)
+	if err != nil {
+		return nil, err
+	}
+	borrador, err := c.Obtener(ctx, canalID, id)
+	if err != nil {
+		return nil, err
+	}
+	anterior := borrador.Estado
+	if err := borrador.Rechazar(actor.ID, motivo, c.reloj.Ahora()); err != nil {
+		return nil, err
+	}
+	return c.actualizar(ctx, borrador, anterior)
+}
+
+// Difundir envía un borrador aprobado, o uno sin aprobar si el canal no supera el umbral
+func (c *CasoUsoAprobacionDifusion) Difundir(ctx context.Context, canalID, id uint) (*entidad.BorradorDifusion, *ProgresoDifusion, error) {
+	borrador, err := c.Obtener(ctx, canalID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	progreso, err := c.enviar(ctx, borrador)
+	if err != nil {
+		return nil, nil, err
+	}
+	return borrador, progreso, nil
+}
+
+// enviar reclama el borrador marcándolo enviado antes de lanzar la difusión, así dos
+// solicitudes simultáneas no difunden dos veces; si no se puede lanzar, lo restaura
+func (c *CasoUsoAprobacionDifusion) enviar(ctx context.Context, borrador *entidad.BorradorDifusion) (*ProgresoDifusion, error) {
+	if err := borrador.ValidarEnvio(); err != nil {
+		return nil, err
+	}
+	anterior := *borrador
+	borrador.MarcarEnviada("", c.reloj.Ahora())
+	if _, err := c.actualizar(ctx, borrador, anterior.Estado); err != nil {
+		*borrador = anterior
+		return nil, err
+	}
+
+	progreso, err := c.difundir.iniciar(ctx, borrador.CanalID, dto.SolicitudDifusion{
+		Titulo:    borrador.Titulo,
+		Mensaje:   borrador.Mensaje,
+		Tipo:      borrador.Tipo,
+		Prioridad: borrador.Prioridad,
+		Metadatos: borrador.Metadatos,
+	}, anterior.Estado == entidad.EstadoBorradorAprobado)
+	if err != nil {
+		if _, errRestaurar := c.repositorioBorrador.Actualizar(ctx, &anterior, entidad.EstadoBorradorEnviado); errRestaurar != nil {
+			return nil, errRestaurar
+		}
+		*borrador = anterior
+		return nil, err
+	}
+
+	borrador.DifusionID = progreso.ID
+	if _, err := c.repositorioBorrador.Actualizar(ctx, borrador, entidad.EstadoBorradorEnviado); err != nil {
+		return nil, err
+	}
+	return progreso, nil
+}
+
+// aprobador retorna el actor autenticado de la solicitud si puede resolver aprobaciones
+func aprobador(ctx context.Context) (*entidad.Usuario, error) {
+	actor, ok := servicio.ActorAutenticadoDesdeContexto(ctx)
+	if !ok {
+		return nil, entidad.ErrActorNoAutenticado
+	}
+	if !actor.EsAdministrador() && !actor.EsModerador() {
+		return nil, entidad.ErrRolInsuficiente
+	}
+	return actor, nil
+}
+
+// actualizar guarda el borrador si su estado sigue siendo el leído
+func (c *CasoUsoAprobacionDifusion) actualizar(ctx context.Context, borrador *entidad.BorradorDifusion, anterior entidad.EstadoBorradorDifusion) (*entidad.BorradorDifusion, error) {
+	actualizado, err := c.repositorioBorrador.Actualizar(ctx, borrador, anterior)
+	if err != nil {
+		return nil, err
+	}
+	if !actualizado {
+		return nil, errBorradorModificado
+	}
+	return borrador, nil
+}
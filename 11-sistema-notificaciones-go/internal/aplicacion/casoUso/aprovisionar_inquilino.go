@@ -0,0 +1,194 @@
+package casoUso
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+)
+
+// canalPredeterminado describe un canal creado para todo inquilino nuevo
+type canalPredeterminado struct {
+	nombre      string
+	descripcion string
+	tipo        entidad.TipoCanal
+}
+
+// canalesPredeterminados son los canales con los que arranca un inquilino
+var canalesPredeterminados = []canalPredeterminado{
+	{"General", "Comunicaciones generales", entidad.TipoCanalGeneral},
+	{"Sistema", "Avisos del sistema", entidad.TipoCanalSistema},
+	{"Seguridad", "Alertas de seguridad de la cuenta", entidad.TipoCanalSeguridad},
+}
+
+// plantillasPredeterminadas son las plantillas con las que arranca un inquilino
+var plantillasPredeterminadas = []entidad.Plantilla{
+	{
+		Nombre: "bienvenida",
+		Tipo:   entidad.TipoEmail,
+		Asunto: "Bienvenido/a, {{.Nombre}}",
+		Cuerpo: "Hola {{.Nombre}}, tu cuenta ya está activa.",
+	},
+	{
+		Nombre: "alerta_seguridad",
+		Tipo:   entidad.TipoInApp,
+		Asunto: "Actividad inusual en tu cuenta",
+		Cuerpo: "Detectamos un acceso desde {{.Origen}}. Si no fuiste tú, cambia tu contraseña.",
+	},
+}
+
+// tiposPreferenciaPredeterminados se habilitan para el administrador inicial
+var tiposPreferenciaPredeterminados = []entidad.TipoNotificacion{
+	entidad.TipoEmail,
+	entidad.TipoInApp,
+	entidad.TipoWebSocket,
+}
+
+// ResultadoAprovisionamiento contiene todo lo creado para el inquilino.
+// ValorClaveAPI se entrega solo en esta respuesta.
+type ResultadoAprovisionamiento struct {
+	Inquilino     *entidad.Inquilino                 `json:"inquilino"`
+	Canales       []*entidad.Canal                   `json:"canales"`
+	Administrador *entidad.Usuario                   `json:"administrador"`
+	Preferencias  []*entidad.PreferenciaNotificacion `json:"preferencias"`
+	Plantillas    []*entidad.Plantilla               `json:"plantillas"`
+	ClaveAPI      *entidad.ClaveAPI                  `json:"clave_api"`
+	ValorClaveAPI string                             `json:"valor_clave_api"`
+}
+
+// CasoUsoAprovisionarInquilino da de alta un inquilino con todo lo necesario para operar
+type CasoUsoAprovisionarInquilino struct {
+	unidadTrabajo          repositorio.UnidadTrabajo
+	repositorioInquilino   repositorio.RepositorioInquilino
+	repositorioCanal       repositorio.RepositorioCanal
+	repositorioUsuario     repositorio.RepositorioUsuario
+	repositorioPreferencia repositorio.RepositorioPreferencia
+	repositorioPlantilla   repositorio.RepositorioPlantilla
+	repositorioClave       repositorio.RepositorioClaveAPI
+	aprovisionador         repositorio.AprovisionadorEsquemas
+}
+
+// NuevoCasoUsoAprovisionarInquilino crea una nueva instancia del caso de uso
+func NuevoCasoUsoAprovisionarInquilino(
+	unidadTrabajo repositorio.UnidadTrabajo,
+	repositorioInquilino repositorio.RepositorioInquilino,
+	repositorioCanal repositorio.RepositorioCanal,
+	repositorioUsuario repositorio.RepositorioUsuario,
+	repositorioPreferencia repositorio.RepositorioPreferencia,
+	repositorioPlantilla repositorio.RepositorioPlantilla,
+	repositorioClave repositorio.RepositorioClaveAPI,
+	aprovisionador repositorio.AprovisionadorEsquemas,
+) *CasoUsoAprovisionarInquilino {
+	return &CasoUsoAprovisionarInquilino{
+		unidadTrabajo:          unidadTrabajo,
+		repositorioInquilino:   repositorioInquilino,
+		repositorioCanal:       repositorioCanal,
+		repositorioUsuario:     repositorioUsuario,
+		repositorioPreferencia: repositorioPreferencia,
+		repositorioPlantilla:   repositorioPlantilla,
+		repositorioClave:       repositorioClave,
+		aprovisionador:         aprovisionador,
+	}
+}
+
+// Ejecutar crea inquilino, canales, administrador, preferencias, plantillas y clave de API
+// en una unidad de trabajo: si algo falla no queda un inquilino a medio crear.
+// Un inquilino aislado recibe además su esquema, donde se crean sus datos; los de un inquilino
+// con región se crean en la base de esa región.
+func (c *CasoUsoAprovisionarInquilino) Ejecutar(ctx context.Context, solicitud dto.SolicitudAprovisionarInquilino) (*ResultadoAprovisionamiento, error) {
+	inquilino := entidad.NuevoInquilino(solicitud.Nombre)
+	if solicitud.Aislamiento != "" {
+		inquilino.Aislamiento = entidad.ModoAislamiento(solicitud.Aislamiento)
+	}
+	inquilino.Region = solicitud.Region
+	if err := inquilino.Validar(); err != nil {
+		return nil, err
+	}
+	if inquilino.Aislamiento == entidad.AislamientoEsquema && c.aprovisionador == nil {
+		return nil, entidad.NewErrorValidacion("El aislamiento por esquema no está habilitado")
+	}
+	if inquilino.Region != "" && (c.aprovisionador == nil || !c.aprovisionador.RegionConfigurada(inquilino.Region)) {
+		return nil, entidad.NewErrorValidacion("Region no configurada")
+	}
+
+	datosAdmin := solicitud.Administrador
+	administrador := entidad.NuevoUsuario(datosAdmin.NombreUsuario, datosAdmin.CorreoElectronico, datosAdmin.Nombre, datosAdmin.Apellido)
+	administrador.CambiarRol(entidad.RolAdministrador)
+	if err := administrador.Validar(); err != nil {
+		return nil, err
+	}
+
+	resultado := &ResultadoAprovisionamiento{Inquilino: inquilino, Administrador: administrador}
+	err := c.unidadTrabajo.Ejecutar(ctx, func(ctx context.Context) error {
+		if err := c.repositorioInquilino.Crear(ctx, inquilino); err != nil {
+			return err
+		}
+
+		clave, valor, err := entidad.NuevaClaveAPI("Administración "+inquilino.Nombre, entidad.RolAdminInquilino, inquilino.ID)
+		if err != nil {
+			return err
+		}
+		if err := c.repositorioClave.Crear(ctx, clave); err != nil {
+			return err
+		}
+		resultado.ClaveAPI, resultado.ValorClaveAPI = clave, valor
+
+		if !inquilino.FueraDeBasePrincipal() {
+			return c.crearDatos(ctx, inquilino, resultado)
+		}
+
+		// El esquema (o las tablas de la región) se crea fuera de la transacción (DDL en su
+		// propio pool); si los datos fallan, el inquilino no se confirma y el esquema vacío
+		// se reutiliza o descarta
+		esquema := inquilino.Esquema()
+		ctxDatos := servicio.ContextoConRegion(ctx, inquilino.Region)
+		if err := c.aprovisionador.Aprovisionar(ctxDatos, esquema); err != nil {
+			return err
+		}
+		return c.unidadTrabajo.Ejecutar(servicio.ContextoConEsquema(ctxDatos, esquema), func(ctx context.Context) error {
+			return c.crearDatos(ctx, inquilino, resultado)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resultado, nil
+}
+
+// crearDatos crea canales, administrador, preferencias y plantillas del inquilino
+func (c *CasoUsoAprovisionarInquilino) crearDatos(ctx context.Context, inquilino *entidad.Inquilino, resultado *ResultadoAprovisionamiento) error {
+	for _, predeterminado := range canalesPredeterminados {
+		canal := entidad.NuevoCanal(predeterminado.nombre, predeterminado.descripcion, predeterminado.tipo)
+		canal.InquilinoID = inquilino.ID
+		if err := c.repositorioCanal.Crear(ctx, canal); err != nil {
+			return err
+		}
+		resultado.Canales = append(resultado.Canales, canal)
+	}
+
+	administrador := resultado.Administrador
+	administrador.InquilinoID = inquilino.ID
+	administrador.Region = inquilino.Region
+	if err := c.repositorioUsuario.Crear(ctx, administrador); err != nil {
+		return err
+	}
+
+	for _, tipo := range tiposPreferenciaPredeterminados {
+		preferencia := entidad.NuevaPreferenciaNotificacion(administrador.ID, tipo)
+		if err := c.repositorioPreferencia.Guardar(ctx, preferencia); err != nil {
+			return err
+		}
+		resultado.Preferencias = append(resultado.Preferencias, preferencia)
+	}
+
+	for _, predeterminada := range plantillasPredeterminadas {
+		plantilla := entidad.NuevaPlantilla(inquilino.ID, predeterminada.Nombre, predeterminada.Tipo, predeterminada.Asunto, predeterminada.Cuerpo)
+		if err := c.repositorioPlantilla.Crear(ctx, plantilla); err != nil {
+			return err
+		}
+		resultado.Plantillas = append(resultado.Plantillas, plantilla)
+	}
+	return nil
+}
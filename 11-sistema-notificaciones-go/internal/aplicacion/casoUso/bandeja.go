@@ -0,0 +1,88 @@
+package casoUso
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+)
+
+// CasoUsoBandeja administra la bandeja in-app de los usuarios: carpetas, destacadas y conteos,
+// así cada cliente no tiene que reimplementarla
+type CasoUsoBandeja struct {
+	repositorioNotificacion repositorio.RepositorioNotificacion
+	repositorioUsuario      repositorio.RepositorioUsuario
+	codigosQR               *CasoUsoCodigosQR
+}
+
+// NuevoCasoUsoBandeja crea una nueva instancia del caso de uso
+func NuevoCasoUsoBandeja(repositorioNotificacion repositorio.RepositorioNotificacion, repositorioUsuario repositorio.RepositorioUsuario, codigosQR *CasoUsoCodigosQR) *CasoUsoBandeja {
+	return &CasoUsoBandeja{repositorioNotificacion: repositorioNotificacion, repositorioUsuario: repositorioUsuario, codigosQR: codigosQR}
+}
+
+// Listar retorna una página de la carpeta o vista, las más recientes primero. cursor es el ID
+// de la última notificación de la página anterior. Incluye la URL del código QR de cada una.
+func (c *CasoUsoBandeja) Listar(ctx context.Context, usuarioID uint, carpeta entidad.CarpetaBandeja, cursor uint, limite int) ([]entidad.Notificacion, error) {
+	if !carpeta.EsValida() {
+		return nil, entidad.NewErrorValidacion("carpeta debe ser recibidas, archivadas o destacadas")
+	}
+	if _, err := servicio.AutorizarUsuario(ctx, c.repositorioUsuario, usuarioID); err != nil {
+		return nil, err
+	}
+	notificaciones, err := c.repositorioNotificacion.Listar(ctx, repositorio.FiltroNotificaciones{
+		UsuarioID: usuarioID,
+		Bandeja:   carpeta,
+		Cursor:    cursor,
+		Limite:    limite,
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.codigosQR.PrepararTodas(notificaciones)
+	return notificaciones, nil
+}
+
+// Contar retorna el total y las no leídas de cada carpeta y vista
+func (c *CasoUsoBandeja) Contar(ctx context.Context, usuarioID uint) (map[entidad.CarpetaBandeja]entidad.ConteoCarpeta, error) {
+	if _, err := servicio.AutorizarUsuario(ctx, c.repositorioUsuario, usuarioID); err != nil {
+		return nil, err
+	}
+	return c.repositorioNotificacion.ContarBandeja(ctx, usuarioID)
+}
+
+// Mover cambia la notificación de carpeta
+func (c *CasoUsoBandeja) Mover(ctx context.Context, id uint, carpeta entidad.CarpetaBandeja) (*entidad.Notificacion, error) {
+	return c.aplicar(ctx, id, func(notificacion *entidad.Notificacion) error {
+		return notificacion.MoverACarpeta(carpeta)
+	})
+}
+
+// Destacar marca o desmarca la notificación como destacada
+func (c *CasoUsoBandeja) Destacar(ctx context.Context, id uint, destacada bool) (*entidad.Notificacion, error) {
+	return c.aplicar(ctx, id, func(notificacion *entidad.Notificacion) error {
+		return notificacion.Destacar(destacada)
+	})
+}
+
+// aplicar carga la notificación del inquilino, aplica el cambio y persiste con control de versión
+func (c *CasoUsoBandeja) aplicar(ctx context.Context, id uint, cambio func(*entidad.Notificacion) error) (*entidad.Notificacion, error) {
+	notificacion, err := c.repositorioNotificacion.ObtenerPorID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := servicio.AutorizarInquilino(ctx, notificacion.InquilinoID); err != nil {
+		return nil, err
+	}
+	carpeta, destacada := notificacion.Carpeta, notificacion.Destacada
+	if err := cambio(notificacion); err != nil {
+		return nil, err
+	}
+	if notificacion.Carpeta == carpeta && notificacion.Destacada == destacada {
+		return notificacion, nil
+	}
+	if err := c.repositorioNotificacion.Actualizar(ctx, notificacion); err != nil {
+		return nil, err
+	}
+	return notificacion, nil
+}
@@ -0,0 +1,72 @@
+package casoUso
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// CasoUsoBuzonSalida encola las notificaciones nuevas a través del buzón de salida: se anotan en
+// la transacción que las crea y se publican tras el commit. Si la cola no las acepta (p. ej. el
+// broker está caído) quedan anotadas y el relevo del buzón las publica después, así una
+// notificación creada no se pierde.
+type CasoUsoBuzonSalida struct {
+	repositorio repositorio.RepositorioBuzonSalida
+	cola        repositorio.ColaMensajes
+	// retener indica que la cola es el pool en memoria del proceso: lo que acepta se pierde si
+	// el proceso cae, así que la entrada la borra el trabajador al terminar el despacho
+	retener bool
+	reloj   reloj.Reloj
+	logger  *logger.Logger
+}
+
+// NuevoCasoUsoBuzonSalida crea una nueva instancia del caso de uso
+func NuevoCasoUsoBuzonSalida(repo repositorio.RepositorioBuzonSalida, cola repositorio.ColaMensajes, retener bool, rel reloj.Reloj, log *logger.Logger) *CasoUsoBuzonSalida {
+	return &CasoUsoBuzonSalida{repositorio: repo, cola: cola, retener: retener, reloj: rel, logger: log}
+}
+
+// Anotar agrega las notificaciones al buzón. Debe llamarse dentro de la unidad de trabajo que
+// las crea, después de crearlas.
+func (c *CasoUsoBuzonSalida) Anotar(ctx context.Context, notificaciones ...*entidad.Notificacion) error {
+	ahora := c.reloj.Ahora()
+	entradas := make([]*entidad.EntradaBuzonSalida, 0, len(notificaciones))
+	for _, notificacion := range notificaciones {
+		entradas = append(entradas, &entidad.EntradaBuzonSalida{NotificacionID: notificacion.ID, Fecha: ahora})
+	}
+	return c.repositorio.Agregar(ctx, entradas)
+}
+
+// Publicar encola las notificaciones ya confirmadas y, si la cola es durable, las quita del
+// buzón; indica si la cola las aceptó. Las notificaciones ya existen, así que un error se registra en el log sin
+// propagarse: lo que quede en el buzón lo publica el relevo.
+func (c *CasoUsoBuzonSalida) Publicar(ctx context.Context, notificaciones ...*entidad.Notificacion) bool {
+	if len(notificaciones) == 0 {
+		return true
+	}
+	if err := c.cola.PublicarLote(ctx, notificaciones); err != nil {
+		c.logger.Warn("No se pudieron encolar las notificaciones, las publicará el relevo del buzón de salida",
+			"notificaciones", len(notificaciones),
+			"error", err,
+		)
+		return false
+	}
+	if c.retener {
+		return true
+	}
+
+	ids := make([]uint, 0, len(notificaciones))
+	for _, notificacion := range notificaciones {
+		ids = append(ids, notificacion.ID)
+	}
+	if err := c.repositorio.Eliminar(ctx, ids); err != nil {
+		// El relevo volverá a publicarlas; el despacho omite las que ya no están pendientes
+		c.logger.Error("Error quitando notificaciones publicadas del buzón de salida",
+			"notificaciones", len(ids),
+			"error", err,
+		)
+	}
+	return true
+}
@@ -0,0 +1,98 @@
+package casoUso
+
+import (
+	"context"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// CasoUsoCambiarEstadoNotificacion aplica transiciones de estado solicitadas por usuarios u operadores
+type CasoUsoCambiarEstadoNotificacion struct {
+	repositorioNotificacion repositorio.RepositorioNotificacion
+	avisador                repositorio.AvisadorLectura
+	tareas                  *TareasSegundoPlano
+	reloj                   reloj.Reloj
+	logger                  *logger.Logger
+}
+
+// NuevoCasoUsoCambiarEstadoNotificacion crea una nueva instancia del caso de uso
+func NuevoCasoUsoCambiarEstadoNotificacion(
+	repositorioNotificacion repositorio.RepositorioNotificacion,
+	avisador repositorio.AvisadorLectura,
+	tareas *TareasSegundoPlano,
+	rel reloj.Reloj,
+	logger *logger.Logger,
+) *CasoUsoCambiarEstadoNotificacion {
+	return &CasoUsoCambiarEstadoNotificacion{repositorioNotificacion: repositorioNotificacion, avisador: avisador, tareas: tareas, reloj: rel, logger: logger}
+}
+
+// MarcarComoLeida marca la notificación como leída. La primera lectura avisa al servicio de
+// origen si declaró un destino al enviar; el aviso no demora la respuesta y el apagado lo espera.
+func (c *CasoUsoCambiarEstadoNotificacion) MarcarComoLeida(ctx context.Context, id uint) (*entidad.Notificacion, error) {
+	var recienLeida bool
+	notificacion, err := c.aplicar(ctx, id, func(notificacion *entidad.Notificacion) error {
+		recienLeida = notificacion.Estado != entidad.EstadoLeida
+		return notificacion.MarcarComoLeida(c.reloj.Ahora())
+	})
+	if err != nil {
+		return nil, err
+	}
+	if recienLeida && notificacion.TieneAvisoLectura() {
+		aviso := *notificacion
+		ctxAviso := context.WithoutCancel(ctx)
+		c.tareas.Lanzar(func() { c.avisarLectura(ctxAviso, &aviso) })
+	}
+	return notificacion, nil
+}
+
+// Cancelar cancela una notificación pendiente o fallida
+func (c *CasoUsoCambiarEstadoNotificacion) Cancelar(ctx context.Context, id uint) (*entidad.Notificacion, error) {
+	return c.aplicar(ctx, id, (*entidad.Notificacion).Cancelar)
+}
+
+// Reprogramar mueve una notificación programada a una nueva fecha futura, siempre que el
+// planificador aún no la haya encolado
+func (c *CasoUsoCambiarEstadoNotificacion) Reprogramar(ctx context.Context, id uint, fecha time.Time) (*entidad.Notificacion, error) {
+	notificacion, err := c.repositorioNotificacion.ObtenerPorID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := notificacion.Reprogramar(fecha, c.reloj.Ahora()); err != nil {
+		return nil, err
+	}
+	if err := c.repositorioNotificacion.Actualizar(ctx, notificacion); err != nil {
+		return nil, err
+	}
+	return notificacion, nil
+}
+
+// aplicar carga la notificación, valida la transición en la entidad y persiste con control de versión
+func (c *CasoUsoCambiarEstadoNotificacion) aplicar(ctx context.Context, id uint, transicion func(*entidad.Notificacion) error) (*entidad.Notificacion, error) {
+	notificacion, err := c.repositorioNotificacion.ObtenerPorID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	estadoAnterior := notificacion.Estado
+	if err := transicion(notificacion); err != nil {
+		return nil, err
+	}
+	if notificacion.Estado == estadoAnterior {
+		return notificacion, nil
+	}
+
+	if err := c.repositorioNotificacion.Actualizar(ctx, notificacion); err != nil {
+		return nil, err
+	}
+	return notificacion, nil
+}
+
+func (c *CasoUsoCambiarEstadoNotificacion) avisarLectura(ctx context.Context, notificacion *entidad.Notificacion) {
+	if err := c.avisador.Avisar(ctx, notificacion); err != nil {
+		c.logger.Error("Error avisando la lectura al servicio de origen", "notificacion_id", notificacion.ID, "error", err)
+	}
+}
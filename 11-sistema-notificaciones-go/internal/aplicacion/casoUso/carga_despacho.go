@@ -0,0 +1,50 @@
+package casoUso
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// CasoUsoCargaDespacho mide cuánto trabajo espera despacho en todas las bases. A diferencia de
+// la ocupación de las colas, que es de cada instancia, la carga es la misma para todo el servicio.
+type CasoUsoCargaDespacho struct {
+	repositorioNotificacion repositorio.RepositorioNotificacion
+	repositorioInquilino    repositorio.RepositorioInquilino
+	reloj                   reloj.Reloj
+}
+
+// NuevoCasoUsoCargaDespacho crea una nueva instancia del caso de uso
+func NuevoCasoUsoCargaDespacho(
+	repositorioNotificacion repositorio.RepositorioNotificacion,
+	repositorioInquilino repositorio.RepositorioInquilino,
+	rel reloj.Reloj,
+) *CasoUsoCargaDespacho {
+	return &CasoUsoCargaDespacho{
+		repositorioNotificacion: repositorioNotificacion,
+		repositorioInquilino:    repositorioInquilino,
+		reloj:                   rel,
+	}
+}
+
+// Medir cuenta por prioridad las notificaciones listas para despachar en la base principal, las
+// regiones y los esquemas aislados, con la espera de la más antigua
+func (c *CasoUsoCargaDespacho) Medir(ctx context.Context) (*entidad.CargaDespacho, error) {
+	ahora := c.reloj.Ahora()
+	contextos, err := servicio.ContextosDeDatos(ctx, c.repositorioInquilino)
+	if err != nil {
+		return nil, err
+	}
+	var pendientes []entidad.PendientesPrioridad
+	for _, ctxDatos := range contextos {
+		parcial, err := c.repositorioNotificacion.ContarListasParaDespacho(ctxDatos, ahora)
+		if err != nil {
+			return nil, err
+		}
+		pendientes = append(pendientes, parcial...)
+	}
+	return entidad.NuevaCargaDespacho(ahora, pendientes), nil
+}
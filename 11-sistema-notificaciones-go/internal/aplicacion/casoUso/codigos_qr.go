@@ -0,0 +1,129 @@
+package casoUso
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/qr"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// CasoUsoCodigosQR genera el código QR de las notificaciones con el metadato configurado, p. ej.
+// entradas o pases de acceso: lo adjunta el enviador de correo y los clientes in-app y push lo
+// descargan desde una URL firmada
+type CasoUsoCodigosQR struct {
+	repositorioNotificacion repositorio.RepositorioNotificacion
+	repositorioInquilino    repositorio.RepositorioInquilino
+	campo                   string
+	urlBase                 string
+	secreto                 []byte
+	vigencia                time.Duration
+	escala                  int
+	reloj                   reloj.Reloj
+	logger                  *logger.Logger
+}
+
+// NuevoCasoUsoCodigosQR crea el caso de uso; con campo vacío no se generan códigos y con
+// urlBase vacía solo se adjuntan en los correos
+func NuevoCasoUsoCodigosQR(
+	repositorioNotificacion repositorio.RepositorioNotificacion,
+	repositorioInquilino repositorio.RepositorioInquilino,
+	campo, urlBase, secreto string,
+	vigencia time.Duration,
+	escala int,
+	rel reloj.Reloj,
+	log *logger.Logger,
+) *CasoUsoCodigosQR {
+	return &CasoUsoCodigosQR{
+		repositorioNotificacion: repositorioNotificacion,
+		repositorioInquilino:    repositorioInquilino,
+		campo:                   campo,
+		urlBase:                 urlBase,
+		secreto:                 []byte(secreto),
+		vigencia:                vigencia,
+		escala:                  escala,
+		reloj:                   rel,
+		logger:                  log,
+	}
+}
+
+// Preparar completa la URL firmada de la imagen si la notificación tiene código QR
+func (c *CasoUsoCodigosQR) Preparar(notificacion *entidad.Notificacion) {
+	if c.urlBase == "" {
+		return
+	}
+	if _, ok := notificacion.ContenidoQR(c.campo); !ok {
+		return
+	}
+	token := servicio.FirmarImagenQR(c.secreto, notificacion.InquilinoID, notificacion.ID, c.reloj.Ahora().Add(c.vigencia))
+	notificacion.URLCodigoQR = c.urlBase + "/api/v1/qr/" + token
+}
+
+// PrepararTodas completa la URL de la imagen de cada notificación de una página
+func (c *CasoUsoCodigosQR) PrepararTodas(notificaciones []entidad.Notificacion) {
+	for i := range notificaciones {
+		c.Preparar(&notificaciones[i])
+	}
+}
+
+// ImagenQR retorna el PNG del código QR de la notificación, o false si no tiene. Un contenido
+// que no entra en un código QR se registra y la notificación se envía sin él.
+func (c *CasoUsoCodigosQR) ImagenQR(notificacion *entidad.Notificacion) ([]byte, bool, error) {
+	contenido, ok := notificacion.ContenidoQR(c.campo)
+	if !ok {
+		return nil, false, nil
+	}
+	imagen, err := c.generar(contenido)
+	if errors.Is(err, qr.ErrDatosDemasiadoLargos) {
+		c.logger.Warn("Contenido demasiado largo para el código QR", "notificacion_id", notificacion.ID, "campo", c.campo, "bytes", len(contenido))
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return imagen, true, nil
+}
+
+// Imagen retorna el PNG del código QR de la URL firmada. No requiere clave de API: la firma
+// identifica la notificación y vence con la vigencia configurada.
+func (c *CasoUsoCodigosQR) Imagen(ctx context.Context, token string) ([]byte, error) {
+	inquilinoID, notificacionID, err := servicio.VerificarImagenQR(c.secreto, token, c.reloj.Ahora())
+	if err != nil {
+		return nil, err
+	}
+	if ctx, err = servicio.ContextoDeInquilino(ctx, c.repositorioInquilino, inquilinoID); err != nil {
+		return nil, err
+	}
+	notificacion, err := c.repositorioNotificacion.ObtenerPorID(ctx, notificacionID)
+	if errors.Is(err, entidad.ErrNotificacionNoEncontrada) {
+		return nil, entidad.ErrImagenQRInvalida
+	}
+	if err != nil {
+		return nil, err
+	}
+	if notificacion.InquilinoID != inquilinoID {
+		return nil, entidad.ErrImagenQRInvalida
+	}
+
+	imagen, ok, err := c.ImagenQR(notificacion)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, entidad.ErrImagenQRInvalida
+	}
+	return imagen, nil
+}
+
+func (c *CasoUsoCodigosQR) generar(contenido string) ([]byte, error) {
+	codigo, err := qr.Codificar([]byte(contenido))
+	if err != nil {
+		return nil, err
+	}
+	return codigo.PNG(c.escala)
+}
@@ -0,0 +1,103 @@
+package casoUso
+
+import (
+	"context"
+	"errors"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// intentosReintentoColaMuerta son los intentos que recibe una notificación al reintentarla desde
+// la cola de mensajes muertos: los mismos que tiene una notificación nueva
+const intentosReintentoColaMuerta = 3
+
+// CasoUsoColaMuerta permite a los operadores revisar las notificaciones que agotaron sus
+// intentos y reintentarlas una vez resuelta la causa (p. ej. credenciales vencidas)
+type CasoUsoColaMuerta struct {
+	repositorio             repositorio.RepositorioColaMuerta
+	repositorioNotificacion repositorio.RepositorioNotificacion
+	repositorioInquilino    repositorio.RepositorioInquilino
+	reloj                   reloj.Reloj
+	logger                  *logger.Logger
+}
+
+// NuevoCasoUsoColaMuerta crea una nueva instancia del caso de uso
+func NuevoCasoUsoColaMuerta(
+	repo repositorio.RepositorioColaMuerta,
+	repositorioNotificacion repositorio.RepositorioNotificacion,
+	repositorioInquilino repositorio.RepositorioInquilino,
+	rel reloj.Reloj,
+	log *logger.Logger,
+) *CasoUsoColaMuerta {
+	return &CasoUsoColaMuerta{
+		repositorio:             repo,
+		repositorioNotificacion: repositorioNotificacion,
+		repositorioInquilino:    repositorioInquilino,
+		reloj:                   rel,
+		logger:                  log,
+	}
+}
+
+// Listar obtiene una página de la cola; una clave de inquilino solo ve sus entradas
+func (c *CasoUsoColaMuerta) Listar(ctx context.Context, filtro repositorio.FiltroColaMuerta) ([]entidad.EntradaColaMuerta, error) {
+	if inquilinoID := servicio.InquilinoDesdeContexto(ctx); inquilinoID != 0 {
+		if filtro.InquilinoID != 0 && filtro.InquilinoID != inquilinoID {
+			return nil, entidad.ErrAccesoDenegado
+		}
+		filtro.InquilinoID = inquilinoID
+	}
+	return c.repositorio.Listar(ctx, filtro)
+}
+
+// Reintentar concede nuevos intentos a la notificación de la entrada y la saca de la cola. El
+// planificador de reintentos la encola en su próxima pasada; si vuelve a agotar los intentos,
+// regresa a la cola. Si la notificación ya no existe (p. ej. la borró la retención), la entrada
+// se descarta.
+func (c *CasoUsoColaMuerta) Reintentar(ctx context.Context, id uint) (*entidad.Notificacion, error) {
+	entrada, err := c.repositorio.ObtenerPorID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	inquilinoID := servicio.InquilinoDesdeContexto(ctx)
+	if inquilinoID != 0 && inquilinoID != entrada.InquilinoID {
+		// No se revela que la entrada existe en otro inquilino
+		return nil, entidad.ErrEntradaColaMuertaNoEncontrada
+	}
+	if inquilinoID == 0 {
+		// Los datos de un inquilino aislado se leen de su esquema
+		if ctx, err = servicio.ContextoDeInquilino(ctx, c.repositorioInquilino, entrada.InquilinoID); err != nil {
+			return nil, err
+		}
+	}
+
+	notificacion, err := c.repositorioNotificacion.ObtenerPorID(ctx, entrada.NotificacionID)
+	if errors.Is(err, entidad.ErrNotificacionNoEncontrada) {
+		if errEliminar := c.repositorio.Eliminar(ctx, entrada.ID); errEliminar != nil {
+			return nil, errEliminar
+		}
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := notificacion.Reactivar(intentosReintentoColaMuerta, c.reloj.Ahora()); err != nil {
+		return nil, err
+	}
+	if err := c.repositorioNotificacion.Actualizar(ctx, notificacion); err != nil {
+		return nil, err
+	}
+	if err := c.repositorio.Eliminar(ctx, entrada.ID); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Notificación reintentada desde la cola de mensajes muertos",
+		"entrada_id", entrada.ID,
+		"notificacion_id", notificacion.ID,
+		"inquilino_id", entrada.InquilinoID,
+	)
+	return notificacion, nil
+}
@@ -0,0 +1,108 @@
+package casoUso
+
+import (
+	"context"
+	"errors"
+
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// CasoUsoConsentimiento registra los consentimientos de los usuarios y decide si una
+// notificación puede enviarse según el propósito de su canal
+type CasoUsoConsentimiento struct {
+	repositorioConsentimiento repositorio.RepositorioConsentimiento
+	repositorioUsuario        repositorio.RepositorioUsuario
+	repositorioCanal          repositorio.RepositorioCanal
+	reloj                     reloj.Reloj
+}
+
+// NuevoCasoUsoConsentimiento crea una nueva instancia del caso de uso
+func NuevoCasoUsoConsentimiento(
+	repositorioConsentimiento repositorio.RepositorioConsentimiento,
+	repositorioUsuario repositorio.RepositorioUsuario,
+	repositorioCanal repositorio.RepositorioCanal,
+	rel reloj.Reloj,
+) *CasoUsoConsentimiento {
+	return &CasoUsoConsentimiento{
+		repositorioConsentimiento: repositorioConsentimiento,
+		repositorioUsuario:        repositorioUsuario,
+		repositorioCanal:          repositorioCanal,
+		reloj:                     rel,
+	}
+}
+
+// Otorgar registra que el usuario dio su consentimiento
+func (c *CasoUsoConsentimiento) Otorgar(ctx context.Context, usuarioID uint, solicitud dto.SolicitudConsentimiento) (*entidad.Consentimiento, error) {
+	return c.registrar(ctx, usuarioID, solicitud, true)
+}
+
+// Revocar registra que el usuario retiró su consentimiento; rige desde ese momento
+func (c *CasoUsoConsentimiento) Revocar(ctx context.Context, usuarioID uint, solicitud dto.SolicitudConsentimiento) (*entidad.Consentimiento, error) {
+	return c.registrar(ctx, usuarioID, solicitud, false)
+}
+
+// Listar retorna el historial de consentimientos del usuario
+func (c *CasoUsoConsentimiento) Listar(ctx context.Context, usuarioID uint) ([]entidad.Consentimiento, error) {
+	if _, err := servicio.AutorizarUsuario(ctx, c.repositorioUsuario, usuarioID); err != nil {
+		return nil, err
+	}
+	return c.repositorioConsentimiento.ListarPorUsuario(ctx, usuarioID)
+}
+
+// Permite verifica si la notificación puede enviarse: los canales cuyo tipo exige un
+// propósito (p. ej. marketing) requieren que la decisión vigente del usuario sea otorgarlo.
+// Si el canal ya no existe no se sabe qué propósito exigía, así que no se envía.
+func (c *CasoUsoConsentimiento) Permite(ctx context.Context, notificacion *entidad.Notificacion) (bool, error) {
+	if notificacion.CanalID == 0 {
+		return true, nil
+	}
+	canal, err := c.repositorioCanal.ObtenerPorID(ctx, notificacion.CanalID)
+	if errors.Is(err, entidad.ErrCanalNoEncontrado) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	proposito, requiere := canal.Tipo.PropositoConsentimiento()
+	if !requiere {
+		return true, nil
+	}
+
+	vigente, err := c.repositorioConsentimiento.ObtenerVigente(ctx, notificacion.UsuarioID, proposito, canal.ID)
+	if err != nil {
+		return false, err
+	}
+	return vigente != nil && vigente.Otorgado, nil
+}
+
+func (c *CasoUsoConsentimiento) registrar(ctx context.Context, usuarioID uint, solicitud dto.SolicitudConsentimiento, otorgado bool) (*entidad.Consentimiento, error) {
+	usuario, err := servicio.AutorizarUsuario(ctx, c.repositorioUsuario, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	if solicitud.CanalID != 0 {
+		canal, err := c.repositorioCanal.ObtenerPorID(ctx, solicitud.CanalID)
+		if err != nil {
+			return nil, err
+		}
+		if canal.InquilinoID != 0 {
+			if err := servicio.AutorizarInquilino(ctx, canal.InquilinoID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	consentimiento := entidad.NuevoConsentimiento(usuario, solicitud.Proposito, solicitud.CanalID, otorgado,
+		solicitud.Fuente, solicitud.VersionTexto, c.reloj.Ahora())
+	if err := consentimiento.Validar(); err != nil {
+		return nil, err
+	}
+	if err := c.repositorioConsentimiento.Registrar(ctx, consentimiento); err != nil {
+		return nil, err
+	}
+	return consentimiento, nil
+}
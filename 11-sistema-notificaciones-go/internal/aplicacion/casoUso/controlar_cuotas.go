@@ -0,0 +1,250 @@
+package casoUso
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// Expiración de los contadores: el mensual sobrevive al mes en curso, el de tasa a su segundo
+const (
+	expiracionContadorMensual = 35 * 24 * time.Hour
+	expiracionContadorTasa    = 2 * time.Second
+)
+
+// tiposNotificacion se reportan en el uso aunque no tengan cuota configurada
+var tiposNotificacion = []entidad.TipoNotificacion{
+	entidad.TipoEmail,
+	entidad.TipoSMS,
+	entidad.TipoPush,
+	entidad.TipoWebSocket,
+	entidad.TipoInApp,
+	entidad.TipoSlack,
+	entidad.TipoTelegram,
+	entidad.TipoVoz,
+}
+
+// UsoTipo es el consumo del mes en curso para un tipo de notificación
+type UsoTipo struct {
+	Tipo             entidad.TipoNotificacion `json:"tipo"`
+	Enviadas         int64                    `json:"enviadas"`
+	LimiteMensual    int64                    `json:"limite_mensual"`
+	LimitePorSegundo int64                    `json:"limite_por_segundo"`
+}
+
+// UsoInquilino es el consumo mensual de un inquilino
+type UsoInquilino struct {
+	InquilinoID uint      `json:"inquilino_id"`
+	Periodo     string    `json:"periodo"`
+	Tipos       []UsoTipo `json:"tipos"`
+}
+
+// CasoUsoControlarCuotas aplica las cuotas mensuales y límites por segundo de cada inquilino
+type CasoUsoControlarCuotas struct {
+	repositorioInquilino repositorio.RepositorioInquilino
+	contador             repositorio.ContadorUso
+	reloj                reloj.Reloj
+	logger               *logger.Logger
+}
+
+// NuevoCasoUsoControlarCuotas crea una nueva instancia del caso de uso
+func NuevoCasoUsoControlarCuotas(
+	repositorioInquilino repositorio.RepositorioInquilino,
+	contador repositorio.ContadorUso,
+	rel reloj.Reloj,
+	log *logger.Logger,
+) *CasoUsoControlarCuotas {
+	return &CasoUsoControlarCuotas{
+		repositorioInquilino: repositorioInquilino,
+		contador:             contador,
+		reloj:                rel,
+		logger:               log,
+	}
+}
+
+// Consumir descuenta cantidad envíos del límite por segundo y de la cuota mensual.
+// Las notificaciones sin inquilino (ID 0) no tienen límites.
+func (c *CasoUsoControlarCuotas) Consumir(ctx context.Context, inquilinoID uint, tipo entidad.TipoNotificacion, cantidad int64) error {
+	return c.consumir(ctx, inquilinoID, tipo, cantidad, true)
+}
+
+// ConsumirMensual descuenta solo de la cuota mensual; para envíos masivos que se encolan en lotes
+func (c *CasoUsoControlarCuotas) ConsumirMensual(ctx context.Context, inquilinoID uint, tipo entidad.TipoNotificacion, cantidad int64) error {
+	return c.consumir(ctx, inquilinoID, tipo, cantidad, false)
+}
+
+// DevolverMensual reintegra envíos reservados con ConsumirMensual que no llegaron a crearse. Se
+// devuelven al mes de reservados, que es el que los descontó.
+func (c *CasoUsoControlarCuotas) DevolverMensual(ctx context.Context, inquilinoID uint, tipo entidad.TipoNotificacion, cantidad int64, reservados time.Time) error {
+	if inquilinoID == 0 || cantidad <= 0 {
+		return nil
+	}
+	_, err := c.contador.Incrementar(ctx, claveMensual(inquilinoID, tipo, reservados.UTC()), -cantidad, expiracionContadorMensual)
+	return err
+}
+
+func (c *CasoUsoControlarCuotas) consumir(ctx context.Context, inquilinoID uint, tipo entidad.TipoNotificacion, cantidad int64, conTasa bool) error {
+	if inquilinoID == 0 {
+		return nil
+	}
+
+	cuota, err := c.cuota(ctx, inquilinoID, tipo)
+	if err != nil {
+		return err
+	}
+	ahora := c.reloj.Ahora().UTC()
+
+	if conTasa && cuota.LimitePorSegundo > 0 {
+		clave := claveTasa(inquilinoID, tipo, ahora)
+		total, err := c.contador.Incrementar(ctx, clave, cantidad, expiracionContadorTasa)
+		if err != nil {
+			return err
+		}
+		servicio.RegistrarLimite(ctx, entidad.NuevoEstadoLimite(cuota.LimitePorSegundo, total, ahora.Truncate(time.Second).Add(time.Second)))
+		if total > cuota.LimitePorSegundo {
+			c.alertar(inquilinoID, tipo, "por_segundo", cuota.LimitePorSegundo)
+			return entidad.ErrLimiteTasaExcedido
+		}
+	}
+
+	if cuota.LimiteMensual > 0 {
+		clave := claveMensual(inquilinoID, tipo, ahora)
+		total, err := c.contador.Incrementar(ctx, clave, cantidad, expiracionContadorMensual)
+		if err != nil {
+			return err
+		}
+		servicio.RegistrarLimite(ctx, entidad.NuevoEstadoLimite(cuota.LimiteMensual, total, inicioMesSiguiente(ahora)))
+		if total > cuota.LimiteMensual {
+			// Devolver lo reservado: el envío rechazado no consume cuota
+			if _, err := c.contador.Incrementar(ctx, clave, -cantidad, expiracionContadorMensual); err != nil {
+				return err
+			}
+			c.alertar(inquilinoID, tipo, "mensual", cuota.LimiteMensual)
+			return entidad.ErrCuotaMensualExcedida
+		}
+	} else {
+		// Sin límite igualmente se lleva la cuenta para reportar el uso
+		if _, err := c.contador.Incrementar(ctx, claveMensual(inquilinoID, tipo, ahora), cantidad, expiracionContadorMensual); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ObtenerUso retorna el consumo del mes en curso por tipo, con sus límites
+func (c *CasoUsoControlarCuotas) ObtenerUso(ctx context.Context, inquilinoID uint) (*UsoInquilino, error) {
+	if err := servicio.AutorizarInquilino(ctx, inquilinoID); err != nil {
+		return nil, err
+	}
+	if _, err := c.repositorioInquilino.ObtenerPorID(ctx, inquilinoID); err != nil {
+		return nil, err
+	}
+	cuotas, err := c.repositorioInquilino.ListarCuotas(ctx, inquilinoID)
+	if err != nil {
+		return nil, err
+	}
+	porTipo := make(map[entidad.TipoNotificacion]entidad.CuotaInquilino, len(cuotas))
+	for _, cuota := range cuotas {
+		porTipo[cuota.Tipo] = cuota
+	}
+
+	ahora := c.reloj.Ahora().UTC()
+	claves := make([]string, len(tiposNotificacion))
+	for i, tipo := range tiposNotificacion {
+		claves[i] = claveMensual(inquilinoID, tipo, ahora)
+	}
+	enviadas, err := c.contador.Obtener(ctx, claves...)
+	if err != nil {
+		return nil, err
+	}
+
+	uso := &UsoInquilino{InquilinoID: inquilinoID, Periodo: ahora.Format("2006-01")}
+	for i, tipo := range tiposNotificacion {
+		uso.Tipos = append(uso.Tipos, UsoTipo{
+			Tipo:             tipo,
+			Enviadas:         enviadas[i],
+			LimiteMensual:    porTipo[tipo].LimiteMensual,
+			LimitePorSegundo: porTipo[tipo].LimitePorSegundo,
+		})
+	}
+	return uso, nil
+}
+
+// Disponible retorna el consumo del mes y los límites del tipo sin descontar nada, para
+// anticipar si un envío sería aceptado
+func (c *CasoUsoControlarCuotas) Disponible(ctx context.Context, inquilinoID uint, tipo entidad.TipoNotificacion) (*UsoTipo, error) {
+	uso := &UsoTipo{Tipo: tipo}
+	if inquilinoID == 0 {
+		return uso, nil
+	}
+	cuota, err := c.cuota(ctx, inquilinoID, tipo)
+	if err != nil {
+		return nil, err
+	}
+	enviadas, err := c.contador.Obtener(ctx, claveMensual(inquilinoID, tipo, c.reloj.Ahora().UTC()))
+	if err != nil {
+		return nil, err
+	}
+	uso.Enviadas = enviadas[0]
+	uso.LimiteMensual = cuota.LimiteMensual
+	uso.LimitePorSegundo = cuota.LimitePorSegundo
+	return uso, nil
+}
+
+// GuardarCuota configura la cuota de un tipo para un inquilino existente
+func (c *CasoUsoControlarCuotas) GuardarCuota(ctx context.Context, cuota *entidad.CuotaInquilino) error {
+	if err := cuota.Validar(); err != nil {
+		return err
+	}
+	if err := servicio.AutorizarInquilino(ctx, cuota.InquilinoID); err != nil {
+		return err
+	}
+	if _, err := c.repositorioInquilino.ObtenerPorID(ctx, cuota.InquilinoID); err != nil {
+		return err
+	}
+	return c.repositorioInquilino.GuardarCuota(ctx, cuota)
+}
+
+func (c *CasoUsoControlarCuotas) cuota(ctx context.Context, inquilinoID uint, tipo entidad.TipoNotificacion) (*entidad.CuotaInquilino, error) {
+	cuotas, err := c.repositorioInquilino.ListarCuotas(ctx, inquilinoID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range cuotas {
+		if cuotas[i].Tipo == tipo {
+			return &cuotas[i], nil
+		}
+	}
+	// Sin cuota configurada no hay límites, pero el uso se contabiliza igual
+	return &entidad.CuotaInquilino{InquilinoID: inquilinoID, Tipo: tipo}, nil
+}
+
+// alertar deja un registro de nivel error que dispara la alerta de administración
+func (c *CasoUsoControlarCuotas) alertar(inquilinoID uint, tipo entidad.TipoNotificacion, limite string, valor int64) {
+	c.logger.Error("Límite de inquilino excedido",
+		"alerta", "cuota_inquilino",
+		"inquilino_id", inquilinoID,
+		"tipo", tipo,
+		"limite", limite,
+		"valor", valor,
+	)
+}
+
+// inicioMesSiguiente es cuándo se renueva la cuota mensual
+func inicioMesSiguiente(instante time.Time) time.Time {
+	return time.Date(instante.Year(), instante.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func claveMensual(inquilinoID uint, tipo entidad.TipoNotificacion, instante time.Time) string {
+	return fmt.Sprintf("%d:mes:%s:%s", inquilinoID, instante.Format("200601"), tipo)
+}
+
+func claveTasa(inquilinoID uint, tipo entidad.TipoNotificacion, instante time.Time) string {
+	return fmt.Sprintf("%d:seg:%d:%s", inquilinoID, instante.Unix(), tipo)
+}
@@ -0,0 +1,135 @@
+package casoUso
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// ResumenCredencialPlataforma describe un juego de credenciales de la plataforma sin revelar sus valores
+type ResumenCredencialPlataforma struct {
+	Proveedor          string     `json:"proveedor"`
+	Nombre             string     `json:"nombre"`
+	Campos             []string   `json:"campos"`
+	Activa             bool       `json:"activa"`
+	FechaActivacion    *time.Time `json:"fecha_activacion,omitempty"`
+	FechaActualizacion time.Time  `json:"fecha_actualizacion"`
+}
+
+// CasoUsoCredencialesPlataforma administra los juegos de credenciales de la plataforma por
+// proveedor y resuelve el activo de cada uno
+type CasoUsoCredencialesPlataforma struct {
+	repositorio repositorio.RepositorioCredencialPlataforma
+	cifrador    servicio.Cifrador
+	reloj       reloj.Reloj
+}
+
+// NuevoCasoUsoCredencialesPlataforma crea una nueva instancia del caso de uso.
+// Con cifrador nil no se aceptan juegos y los envíos usan las credenciales de la configuración.
+func NuevoCasoUsoCredencialesPlataforma(repo repositorio.RepositorioCredencialPlataforma, cifrador servicio.Cifrador, rel reloj.Reloj) *CasoUsoCredencialesPlataforma {
+	return &CasoUsoCredencialesPlataforma{repositorio: repo, cifrador: cifrador, reloj: rel}
+}
+
+// Guardar cifra y persiste un juego sin activarlo; si ya existía reemplaza sus datos y, si es el
+// activo, los nuevos rigen desde el próximo envío
+func (c *CasoUsoCredencialesPlataforma) Guardar(ctx context.Context, proveedor, nombre string, credenciales map[string]string) (*ResumenCredencialPlataforma, error) {
+	if c.cifrador == nil {
+		return nil, entidad.ErrCifradoNoConfigurado
+	}
+	if len(credenciales) == 0 {
+		return nil, entidad.NewErrorValidacion("Las credenciales son requeridas")
+	}
+	texto, err := json.Marshal(credenciales)
+	if err != nil {
+		return nil, err
+	}
+	datos, err := c.cifrador.Cifrar(texto)
+	if err != nil {
+		return nil, fmt.Errorf("cifrando credenciales: %w", err)
+	}
+
+	credencial := &entidad.CredencialPlataforma{Proveedor: proveedor, Nombre: nombre, DatosCifrados: datos}
+	if err := credencial.Validar(); err != nil {
+		return nil, err
+	}
+	if err := c.repositorio.Guardar(ctx, credencial); err != nil {
+		return nil, err
+	}
+	return c.resumen(ctx, proveedor, nombre)
+}
+
+// Rotar guarda el juego y lo activa en lugar del activo, que se conserva para poder volver a él
+func (c *CasoUsoCredencialesPlataforma) Rotar(ctx context.Context, proveedor, nombre string, credenciales map[string]string) (*ResumenCredencialPlataforma, error) {
+	if _, err := c.Guardar(ctx, proveedor, nombre, credenciales); err != nil {
+		return nil, err
+	}
+	return c.Activar(ctx, proveedor, nombre)
+}
+
+// Activar activa un juego ya guardado; el que estaba activo queda inactivo
+func (c *CasoUsoCredencialesPlataforma) Activar(ctx context.Context, proveedor, nombre string) (*ResumenCredencialPlataforma, error) {
+	if err := c.repositorio.Activar(ctx, proveedor, nombre, c.reloj.Ahora()); err != nil {
+		return nil, err
+	}
+	return c.resumen(ctx, proveedor, nombre)
+}
+
+// Eliminar borra un juego inactivo; el activo debe reemplazarse antes
+func (c *CasoUsoCredencialesPlataforma) Eliminar(ctx context.Context, proveedor, nombre string) error {
+	return c.repositorio.Eliminar(ctx, proveedor, nombre)
+}
+
+// Listar retorna los juegos de todos los proveedores con los nombres de sus campos
+func (c *CasoUsoCredencialesPlataforma) Listar(ctx context.Context) ([]ResumenCredencialPlataforma, error) {
+	guardadas, err := c.repositorio.Listar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resumenes := make([]ResumenCredencialPlataforma, 0, len(guardadas))
+	for _, guardada := range guardadas {
+		credenciales, err := descifrarCredenciales(c.cifrador, guardada.Proveedor, guardada.DatosCifrados)
+		if err != nil {
+			return nil, err
+		}
+		resumenes = append(resumenes, ResumenCredencialPlataforma{
+			Proveedor:          guardada.Proveedor,
+			Nombre:             guardada.Nombre,
+			Campos:             resumir(guardada.Proveedor, credenciales).Campos,
+			Activa:             guardada.Activa,
+			FechaActivacion:    guardada.FechaActivacion,
+			FechaActualizacion: guardada.FechaActualizacion,
+		})
+	}
+	return resumenes, nil
+}
+
+// Activas retorna las credenciales del juego activo del proveedor, o nil si no tiene
+func (c *CasoUsoCredencialesPlataforma) Activas(ctx context.Context, proveedor string) (map[string]string, error) {
+	if c.cifrador == nil {
+		return nil, nil
+	}
+	activa, err := c.repositorio.ObtenerActiva(ctx, proveedor)
+	if err != nil || activa == nil {
+		return nil, err
+	}
+	return descifrarCredenciales(c.cifrador, proveedor, activa.DatosCifrados)
+}
+
+func (c *CasoUsoCredencialesPlataforma) resumen(ctx context.Context, proveedor, nombre string) (*ResumenCredencialPlataforma, error) {
+	resumenes, err := c.Listar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, resumen := range resumenes {
+		if resumen.Proveedor == proveedor && resumen.Nombre == nombre {
+			return &resumen, nil
+		}
+	}
+	return nil, entidad.ErrCredencialPlataformaNoEncontrada
+}
@@ -0,0 +1,173 @@
+package casoUso
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+)
+
+// ResumenCredencial describe una credencial configurada sin revelar sus valores
+type ResumenCredencial struct {
+	Proveedor string   `json:"proveedor"`
+	Campos    []string `json:"campos"`
+}
+
+// CasoUsoCredencialesProveedor administra y resuelve las credenciales propias de cada inquilino
+type CasoUsoCredencialesProveedor struct {
+	repositorioInquilino repositorio.RepositorioInquilino
+	plataforma           *CasoUsoCredencialesPlataforma
+	cifrador             servicio.Cifrador
+}
+
+// NuevoCasoUsoCredencialesProveedor crea una nueva instancia del caso de uso; plataforma aporta
+// el juego activo de cada proveedor. Con cifrador nil no se aceptan credenciales y todos los
+// envíos usan las de la configuración.
+func NuevoCasoUsoCredencialesProveedor(repositorioInquilino repositorio.RepositorioInquilino, plataforma *CasoUsoCredencialesPlataforma, cifrador servicio.Cifrador) *CasoUsoCredencialesProveedor {
+	return &CasoUsoCredencialesProveedor{
+		repositorioInquilino: repositorioInquilino,
+		plataforma:           plataforma,
+		cifrador:             cifrador,
+	}
+}
+
+// Guardar cifra y persiste las credenciales del inquilino para un proveedor
+func (c *CasoUsoCredencialesProveedor) Guardar(ctx context.Context, inquilinoID uint, proveedor string, credenciales map[string]string) (*ResumenCredencial, error) {
+	if c.cifrador == nil {
+		return nil, entidad.ErrCifradoNoConfigurado
+	}
+	if len(credenciales) == 0 {
+		return nil, entidad.NewErrorValidacion("Las credenciales son requeridas")
+	}
+	if err := servicio.AutorizarInquilino(ctx, inquilinoID); err != nil {
+		return nil, err
+	}
+	if _, err := c.repositorioInquilino.ObtenerPorID(ctx, inquilinoID); err != nil {
+		return nil, err
+	}
+
+	texto, err := json.Marshal(credenciales)
+	if err != nil {
+		return nil, err
+	}
+	datos, err := c.cifrador.Cifrar(texto)
+	if err != nil {
+		return nil, fmt.Errorf("cifrando credenciales: %w", err)
+	}
+
+	credencial := &entidad.CredencialProveedor{InquilinoID: inquilinoID, Proveedor: proveedor, DatosCifrados: datos}
+	if err := credencial.Validar(); err != nil {
+		return nil, err
+	}
+	if err := c.repositorioInquilino.GuardarCredencial(ctx, credencial); err != nil {
+		return nil, err
+	}
+	return resumir(proveedor, credenciales), nil
+}
+
+// Eliminar borra las credenciales del proveedor; el inquilino vuelve a las de la plataforma
+func (c *CasoUsoCredencialesProveedor) Eliminar(ctx context.Context, inquilinoID uint, proveedor string) error {
+	if err := servicio.AutorizarInquilino(ctx, inquilinoID); err != nil {
+		return err
+	}
+	return c.repositorioInquilino.EliminarCredencial(ctx, inquilinoID, proveedor)
+}
+
+// Listar retorna los proveedores configurados con los nombres de sus campos
+func (c *CasoUsoCredencialesProveedor) Listar(ctx context.Context, inquilinoID uint) ([]ResumenCredencial, error) {
+	if err := servicio.AutorizarInquilino(ctx, inquilinoID); err != nil {
+		return nil, err
+	}
+	if _, err := c.repositorioInquilino.ObtenerPorID(ctx, inquilinoID); err != nil {
+		return nil, err
+	}
+	guardadas, err := c.repositorioInquilino.ListarCredenciales(ctx, inquilinoID)
+	if err != nil {
+		return nil, err
+	}
+
+	resumenes := make([]ResumenCredencial, 0, len(guardadas))
+	for _, guardada := range guardadas {
+		credenciales, err := c.descifrar(guardada)
+		if err != nil {
+			return nil, err
+		}
+		resumenes = append(resumenes, *resumir(guardada.Proveedor, credenciales))
+	}
+	return resumenes, nil
+}
+
+// Resolver retorna las credenciales del envío para el proveedor: las del juego activo de la
+// plataforma reemplazadas campo a campo por las propias del inquilino, o nil si el enviador debe
+// usar solo las de la configuración
+func (c *CasoUsoCredencialesProveedor) Resolver(ctx context.Context, inquilinoID uint, proveedor string) (map[string]string, error) {
+	if c.cifrador == nil {
+		return nil, nil
+	}
+	var credenciales map[string]string
+	if c.plataforma != nil {
+		activas, err := c.plataforma.Activas(ctx, proveedor)
+		if err != nil {
+			return nil, err
+		}
+		credenciales = activas
+	}
+	propias, err := c.Propias(ctx, inquilinoID, proveedor)
+	if err != nil || credenciales == nil {
+		return propias, err
+	}
+	for clave, valor := range propias {
+		credenciales[clave] = valor
+	}
+	return credenciales, nil
+}
+
+// Propias retorna solo las credenciales propias del inquilino para el proveedor, o nil si no las configuró
+func (c *CasoUsoCredencialesProveedor) Propias(ctx context.Context, inquilinoID uint, proveedor string) (map[string]string, error) {
+	if inquilinoID == 0 || c.cifrador == nil {
+		return nil, nil
+	}
+	guardadas, err := c.repositorioInquilino.ListarCredenciales(ctx, inquilinoID)
+	if err != nil {
+		return nil, err
+	}
+	for _, guardada := range guardadas {
+		if guardada.Proveedor == proveedor {
+			return c.descifrar(guardada)
+		}
+	}
+	return nil, nil
+}
+
+func (c *CasoUsoCredencialesProveedor) descifrar(guardada entidad.CredencialProveedor) (map[string]string, error) {
+	return descifrarCredenciales(c.cifrador, guardada.Proveedor, guardada.DatosCifrados)
+}
+
+// descifrarCredenciales descifra los datos de un juego de credenciales guardado como JSON
+func descifrarCredenciales(cifrador servicio.Cifrador, proveedor string, datos []byte) (map[string]string, error) {
+	if cifrador == nil {
+		return nil, entidad.ErrCifradoNoConfigurado
+	}
+	texto, err := cifrador.Descifrar(datos)
+	if err != nil {
+		return nil, fmt.Errorf("descifrando credenciales de %s: %w", proveedor, err)
+	}
+	var credenciales map[string]string
+	if err := json.Unmarshal(texto, &credenciales); err != nil {
+		return nil, err
+	}
+	return credenciales, nil
+}
+
+func resumir(proveedor string, credenciales map[string]string) *ResumenCredencial {
+	resumen := &ResumenCredencial{Proveedor: proveedor, Campos: make([]string, 0, len(credenciales))}
+	for campo := range credenciales {
+		resumen.Campos = append(resumen.Campos, campo)
+	}
+	sort.Strings(resumen.Campos)
+	return resumen
+}
@@ -0,0 +1,384 @@
+package casoUso
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// CasoUsoDespacharNotificacion entrega una notificación pendiente con el proveedor de su tipo
+type CasoUsoDespacharNotificacion struct {
+	repositorioNotificacion repositorio.RepositorioNotificacion
+	repositorioIntento      repositorio.RepositorioIntentoEnvio
+	repositorioConsumo      repositorio.RepositorioConsumo
+	repositorioColaMuerta   repositorio.RepositorioColaMuerta
+	proveedores             servicio.CatalogoProveedores
+	credenciales            *CasoUsoCredencialesProveedor
+	consentimientos         *CasoUsoConsentimiento
+	supresiones             *CasoUsoListaSupresion
+	silenciamientos         *CasoUsoSilenciamiento
+	codigosQR               *CasoUsoCodigosQR
+	backendsRegionales      servicio.BackendsRegionales
+	politicaReintento       entidad.PoliticaReintento
+	reloj                   reloj.Reloj
+	logger                  *logger.Logger
+}
+
+// NuevoCasoUsoDespacharNotificacion crea una nueva instancia del caso de uso
+func NuevoCasoUsoDespacharNotificacion(
+	repositorioNotificacion repositorio.RepositorioNotificacion,
+	repositorioIntento repositorio.RepositorioIntentoEnvio,
+	repositorioConsumo repositorio.RepositorioConsumo,
+	repositorioColaMuerta repositorio.RepositorioColaMuerta,
+	proveedores servicio.CatalogoProveedores,
+	credenciales *CasoUsoCredencialesProveedor,
+	consentimientos *CasoUsoConsentimiento,
+	supresiones *CasoUsoListaSupresion,
+	silenciamientos *CasoUsoSilenciamiento,
+	codigosQR *CasoUsoCodigosQR,
+	backendsRegionales servicio.BackendsRegionales,
+	politicaReintento entidad.PoliticaReintento,
+	rel reloj.Reloj,
+	log *logger.Logger,
+) *CasoUsoDespacharNotificacion {
+	return &CasoUsoDespacharNotificacion{
+		repositorioNotificacion: repositorioNotificacion,
+		repositorioIntento:      repositorioIntento,
+		repositorioConsumo:      repositorioConsumo,
+		repositorioColaMuerta:   repositorioColaMuerta,
+		proveedores:             proveedores,
+		credenciales:            credenciales,
+		consentimientos:         consentimientos,
+		supresiones:             supresiones,
+		silenciamientos:         silenciamientos,
+		codigosQR:               codigosQR,
+		backendsRegionales:      backendsRegionales,
+		politicaReintento:       politicaReintento,
+		reloj:                   rel,
+		logger:                  log.Componente(logger.ComponenteTrabajadores),
+	}
+}
+
+// Procesar implementa trabajador.Procesador
+func (c *CasoUsoDespacharNotificacion) Procesar(ctx context.Context, notificacion *entidad.Notificacion) error {
+	return c.Ejecutar(ctx, notificacion)
+}
+
+// Ejecutar envía la notificación y persiste el resultado (enviada o fallida)
+func (c *CasoUsoDespacharNotificacion) Ejecutar(ctx context.Context, notificacion *entidad.Notificacion) error {
+	if notificacion.Estado != entidad.EstadoPendiente && !notificacion.PuedeReintentar() {
+		return nil
+	}
+
+	permitida, err := c.consentimientos.Permite(ctx, notificacion)
+	if err != nil {
+		return err
+	}
+	if !permitida {
+		return c.cancelarSinEnviar(ctx, notificacion, "sin_consentimiento")
+	}
+	suprimida, err := c.supresiones.Bloqueo(ctx, notificacion)
+	if err != nil {
+		return err
+	}
+	if suprimida != nil {
+		return c.cancelarSinEnviar(ctx, notificacion, "supresion_"+string(suprimida.Motivo))
+	}
+	silenciamiento, err := c.silenciamientos.Silenciamiento(ctx, notificacion)
+	if err != nil {
+		return err
+	}
+	if silenciamiento != nil {
+		return c.entregarEnSilencio(ctx, notificacion, silenciamiento)
+	}
+
+	enviador, err := c.enviador(notificacion.Tipo)
+	if err != nil {
+		return c.fallarSinEnviar(ctx, notificacion, err)
+	}
+
+	ctxRegional, err := c.conBackendRegional(ctx, enviador)
+	if err != nil {
+		return c.fallarSinEnviar(ctx, notificacion, err)
+	}
+	ctx, err = c.conCredenciales(ctxRegional, enviador, notificacion)
+	if err != nil {
+		return err
+	}
+
+	c.codigosQR.Preparar(notificacion)
+	errEnvio, err := c.enviarConRegistro(ctx, enviador, notificacion)
+	if err != nil {
+		return err
+	}
+	if errEnvio != nil {
+		if err := notificacion.MarcarComoFallida(); err != nil {
+			return err
+		}
+		notificacion.ProgramarReintento(c.reloj.Ahora(), c.politicaReintento, rand.Float64())
+	} else if err := notificacion.MarcarComoEnviada(c.reloj.Ahora()); err != nil {
+		return err
+	}
+
+	if err := c.repositorioNotificacion.Actualizar(ctx, notificacion); err != nil {
+		if errors.Is(err, entidad.ErrConflictoVersion) {
+			// Otro proceso (p. ej. una cancelación manual) cambió la notificación mientras se enviaba
+			c.logger.Warn("Conflicto de versión al registrar el envío",
+				"notificacion_id", notificacion.ID,
+				"estado_envio", notificacion.Estado,
+			)
+			return nil
+		}
+		return err
+	}
+
+	if errEnvio == nil {
+		c.medir(ctx, enviador, notificacion)
+	} else if !notificacion.PuedeReintentar() {
+		c.archivarEnColaMuerta(ctx, notificacion, errEnvio)
+	}
+
+	c.logger.Debug("Notificación despachada",
+		"notificacion_id", notificacion.ID,
+		"tipo", notificacion.Tipo,
+		"estado", notificacion.Estado,
+		"intentos", notificacion.IntentosEnvio,
+	)
+	if errEnvio != nil {
+		return fmt.Errorf("%w: %w", entidad.ErrEnvioFallido, errEnvio)
+	}
+	return nil
+}
+
+// RutaEnvio describe por dónde saldría una notificación
+type RutaEnvio struct {
+	Proveedor string `json:"proveedor"`
+	Region    string `json:"region,omitempty"`
+	// Endpoint es el del backend regional; vacío usa el predeterminado del proveedor
+	Endpoint            string `json:"endpoint,omitempty"`
+	CredencialesPropias bool   `json:"credenciales_propias"`
+}
+
+// Enrutar resuelve el enviador, el backend regional y las credenciales con que se enviaría la
+// notificación, sin enviarla
+func (c *CasoUsoDespacharNotificacion) Enrutar(ctx context.Context, notificacion *entidad.Notificacion) (*RutaEnvio, error) {
+	enviador, err := c.enviador(notificacion.Tipo)
+	if err != nil {
+		return nil, err
+	}
+	ctxRegional, err := c.conBackendRegional(ctx, enviador)
+	if err != nil {
+		return nil, err
+	}
+	ctxEnvio, err := c.conCredenciales(ctxRegional, enviador, notificacion)
+	if err != nil {
+		return nil, err
+	}
+
+	ruta := &RutaEnvio{Proveedor: string(notificacion.Tipo), Region: servicio.RegionDesdeContexto(ctx)}
+	if conProveedor, ok := enviador.(servicio.EnviadorConCredenciales); ok {
+		ruta.Proveedor = conProveedor.Proveedor()
+		// El juego activo de la plataforma también viaja en el contexto: no son propias
+		propias, err := c.credenciales.Propias(ctx, notificacion.InquilinoID, ruta.Proveedor)
+		if err != nil {
+			return nil, err
+		}
+		ruta.CredencialesPropias = propias != nil
+	}
+	ruta.Endpoint, _ = servicio.EndpointProveedorDesdeContexto(ctxEnvio)
+	return ruta, nil
+}
+
+func (c *CasoUsoDespacharNotificacion) enviador(tipo entidad.TipoNotificacion) (servicio.ProveedorNotificacion, error) {
+	enviador, existe := c.proveedores.Proveedor(tipo)
+	if !existe {
+		return nil, fmt.Errorf("sin enviador configurado para el tipo %s", tipo)
+	}
+	return enviador, nil
+}
+
+// entregarEnSilencio deja la notificación en la bandeja in-app sin pasar por ningún enviador:
+// el usuario la encuentra al abrirla, pero no recibe push, email ni evento WebSocket
+func (c *CasoUsoDespacharNotificacion) entregarEnSilencio(ctx context.Context, notificacion *entidad.Notificacion, silenciamiento *entidad.Silenciamiento) error {
+	if err := notificacion.EntregarEnSilencio(c.reloj.Ahora()); err != nil {
+		return err
+	}
+	if err := c.repositorioNotificacion.Actualizar(ctx, notificacion); err != nil {
+		if errors.Is(err, entidad.ErrConflictoVersion) {
+			return nil
+		}
+		return err
+	}
+
+	c.logger.Debug("Notificación entregada en silencio",
+		"notificacion_id", notificacion.ID,
+		"silenciamiento_id", silenciamiento.ID,
+	)
+	return nil
+}
+
+// cancelarSinEnviar descarta definitivamente la notificación: reintentar no sirve porque un
+// consentimiento otorgado después no habilita mensajes creados antes, y una dirección suprimida
+// no debe recibirlos aunque se la quite de la lista más tarde
+func (c *CasoUsoDespacharNotificacion) cancelarSinEnviar(ctx context.Context, notificacion *entidad.Notificacion, motivo string) error {
+	if err := notificacion.Cancelar(); err != nil {
+		return err
+	}
+	notificacion.EstablecerMetadato("motivo_cancelacion", motivo)
+	if err := c.repositorioNotificacion.Actualizar(ctx, notificacion); err != nil {
+		if errors.Is(err, entidad.ErrConflictoVersion) {
+			return nil
+		}
+		return err
+	}
+
+	c.logger.Info("Notificación cancelada antes del envío",
+		"notificacion_id", notificacion.ID,
+		"usuario_id", notificacion.UsuarioID,
+		"canal_id", notificacion.CanalID,
+		"motivo", motivo,
+	)
+	return nil
+}
+
+// archivarEnColaMuerta agrega a la cola de mensajes muertos la notificación que agotó sus
+// intentos. La notificación ya quedó fallida, así que un error al archivarla se registra en el
+// log sin propagarse: sigue visible entre las fallidas del panel.
+func (c *CasoUsoDespacharNotificacion) archivarEnColaMuerta(ctx context.Context, notificacion *entidad.Notificacion, errEnvio error) {
+	entrada := entidad.NuevaEntradaColaMuerta(notificacion, errEnvio, c.reloj.Ahora())
+	if err := c.repositorioColaMuerta.Agregar(ctx, entrada); err != nil {
+		c.logger.Error("Error agregando la notificación a la cola de mensajes muertos",
+			"notificacion_id", notificacion.ID,
+			"inquilino_id", notificacion.InquilinoID,
+			"error", err,
+		)
+		return
+	}
+	c.logger.Warn("Notificación sin intentos restantes, movida a la cola de mensajes muertos",
+		"notificacion_id", notificacion.ID,
+		"entrada_id", entrada.ID,
+		"intentos", notificacion.IntentosEnvio,
+	)
+}
+
+// medir registra el envío para facturación. La notificación ya se entregó, así que un
+// fallo al medir se registra en el log sin propagarse.
+func (c *CasoUsoDespacharNotificacion) medir(ctx context.Context, enviador servicio.ProveedorNotificacion, notificacion *entidad.Notificacion) {
+	proveedor := string(notificacion.Tipo)
+	if conProveedor, ok := enviador.(servicio.EnviadorConCredenciales); ok {
+		proveedor = conProveedor.Proveedor()
+	}
+
+	registro := entidad.NuevoRegistroConsumo(notificacion, proveedor, c.reloj.Ahora())
+	if err := c.repositorioConsumo.Registrar(ctx, registro); err != nil {
+		c.logger.Error("Error registrando consumo del envío",
+			"notificacion_id", notificacion.ID,
+			"inquilino_id", notificacion.InquilinoID,
+			"error", err,
+		)
+	}
+}
+
+// fallarSinEnviar marca la notificación como fallida sin programar reintento: la causa no se
+// resuelve reintentando
+func (c *CasoUsoDespacharNotificacion) fallarSinEnviar(ctx context.Context, notificacion *entidad.Notificacion, causa error) error {
+	if err := notificacion.MarcarComoFallida(); err != nil {
+		return err
+	}
+	if err := c.repositorioNotificacion.Actualizar(ctx, notificacion); err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %w", entidad.ErrEnvioFallido, causa)
+}
+
+// conBackendRegional dirige el envío al endpoint del proveedor en la región del inquilino.
+// Si el proveedor no está habilitado en la región no se envía: los datos no pueden salir de ella.
+// Un enviador sin backends regionales solo envía para una región si entrega localmente.
+func (c *CasoUsoDespacharNotificacion) conBackendRegional(ctx context.Context, enviador servicio.ProveedorNotificacion) (context.Context, error) {
+	region := servicio.RegionDesdeContexto(ctx)
+	if region == "" {
+		return ctx, nil
+	}
+	if local, ok := enviador.(servicio.EnviadorLocal); ok && local.EntregaLocal() {
+		return ctx, nil
+	}
+	conProveedor, ok := enviador.(servicio.EnviadorConCredenciales)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s en %s", entidad.ErrSinBackendRegional, enviador.Tipo(), region)
+	}
+	endpoint, existe := c.backendsRegionales.Endpoint(region, conProveedor.Proveedor())
+	if !existe {
+		return nil, fmt.Errorf("%w: %s en %s", entidad.ErrSinBackendRegional, conProveedor.Proveedor(), region)
+	}
+	return servicio.ContextoConEndpointProveedor(ctx, endpoint), nil
+}
+
+// conCredenciales adjunta al contexto el juego activo de la plataforma y las credenciales
+// propias del inquilino si el enviador las admite; sin ninguno, el enviador usa las de la
+// configuración
+func (c *CasoUsoDespacharNotificacion) conCredenciales(ctx context.Context, enviador servicio.ProveedorNotificacion, notificacion *entidad.Notificacion) (context.Context, error) {
+	conProveedor, ok := enviador.(servicio.EnviadorConCredenciales)
+	if !ok {
+		return ctx, nil
+	}
+	credenciales, err := c.credenciales.Resolver(ctx, notificacion.InquilinoID, conProveedor.Proveedor())
+	if err != nil {
+		return nil, err
+	}
+	if credenciales == nil {
+		return ctx, nil
+	}
+	return servicio.ContextoConCredenciales(ctx, credenciales), nil
+}
+
+// enviarConRegistro consulta el registro de intentos antes de llamar al proveedor para no
+// duplicar envíos tras una caída. Retorna el error del proveedor y, aparte, errores de registro.
+func (c *CasoUsoDespacharNotificacion) enviarConRegistro(ctx context.Context, enviador servicio.ProveedorNotificacion, notificacion *entidad.Notificacion) (errEnvio error, err error) {
+	ultimo, err := c.repositorioIntento.ObtenerUltimo(ctx, notificacion.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var intento *entidad.IntentoEnvio
+	switch {
+	case ultimo != nil && ultimo.Estado == entidad.EstadoIntentoExitoso:
+		// El proveedor aceptó el envío pero el estado no llegó a persistirse
+		c.logger.Info("Envío ya registrado como exitoso, se omite el reenvío", "notificacion_id", notificacion.ID)
+		return nil, nil
+
+	case ultimo != nil && ultimo.ResultadoIncierto():
+		if idempotente, ok := enviador.(servicio.EnviadorIdempotente); !ok || !idempotente.SoportaIdempotencia() {
+			// Sin deduplicación en el proveedor no hay forma segura de reenviar: se prefiere
+			// a lo sumo una entrega antes que un mensaje duplicado
+			c.logger.Warn("Resultado de envío incierto y proveedor sin idempotencia, no se reenvía",
+				"notificacion_id", notificacion.ID,
+				"intento", ultimo.Numero,
+			)
+			ultimo.Finalizar(nil, c.reloj.Ahora())
+			return nil, c.repositorioIntento.Actualizar(ctx, ultimo)
+		}
+		// Reintentar con el mismo token: el proveedor descarta el duplicado si ya lo recibió
+		intento = ultimo
+
+	default:
+		notificacion.IncrementarIntentos()
+		intento = entidad.NuevoIntentoEnvio(notificacion.ID, notificacion.IntentosEnvio, c.reloj.Ahora())
+		if err := c.repositorioIntento.Crear(ctx, intento); err != nil {
+			return nil, err
+		}
+	}
+
+	errEnvio = enviador.Enviar(servicio.ContextoConTokenIdempotencia(ctx, intento.TokenIdempotencia), notificacion)
+	intento.Finalizar(errEnvio, c.reloj.Ahora())
+	if err := c.repositorioIntento.Actualizar(ctx, intento); err != nil {
+		return errEnvio, err
+	}
+	return errEnvio, nil
+}
@@ -0,0 +1,307 @@
+package casoUso
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// EstadoDifusion define los estados de una difusión
+type EstadoDifusion string
+
+const (
+	EstadoDifusionEnCurso    EstadoDifusion = "en_curso"
+	EstadoDifusionCompletada EstadoDifusion = "completada"
+	EstadoDifusionFallida    EstadoDifusion = "fallida"
+)
+
+// ProgresoDifusion refleja el avance de una difusión en curso
+type ProgresoDifusion struct {
+	ID          string `json:"id"`
+	InquilinoID uint   `json:"inquilino_id,omitempty"`
+	CanalID     uint   `json:"canal_id"`
+	Total       int64  `json:"total"`
+	Creadas     int64  `json:"creadas"`
+	// Publicadas son las que la cola aceptó; el resto queda en el buzón de salida y las publica el relevo
+	Publicadas int64          `json:"publicadas"`
+	Estado     EstadoDifusion `json:"estado"`
+	Error      string         `json:"error,omitempty"`
+	Inicio     time.Time      `json:"inicio"`
+	Fin        *time.Time     `json:"fin,omitempty"`
+}
+
+// CasoUsoDifundirCanal crea y publica una notificación por suscriptor en lotes
+type CasoUsoDifundirCanal struct {
+	unidadTrabajo           repositorio.UnidadTrabajo
+	repositorioNotificacion repositorio.RepositorioNotificacion
+	repositorioCanal        repositorio.RepositorioCanal
+	repositorioInquilino    repositorio.RepositorioInquilino
+	buzon                   *CasoUsoBuzonSalida
+	cuotas                  *CasoUsoControlarCuotas
+	tamanoLote              int
+	umbralAprobacion        int64
+	reloj                   reloj.Reloj
+	logger                  *logger.Logger
+
+	// retencion y maximoTerminadas acotan cuánto se consulta el progreso de las terminadas
+	retencion        time.Duration
+	maximoTerminadas int
+
+	mu         sync.RWMutex
+	difusiones map[string]*ProgresoDifusion
+	secuencia  atomic.Uint64
+}
+
+// NuevoCasoUsoDifundirCanal crea una nueva instancia del caso de uso. Los canales con más de
+// umbralAprobacion suscriptores solo difunden borradores aprobados (0 no exige aprobación). El
+// progreso de una difusión terminada se conserva durante retencion, y de a lo sumo
+// maximoTerminadas.
+func NuevoCasoUsoDifundirCanal(
+	unidadTrabajo repositorio.UnidadTrabajo,
+	repositorioNotificacion repositorio.RepositorioNotificacion,
+	repositorioCanal repositorio.RepositorioCanal,
+	repositorioInquilino repositorio.RepositorioInquilino,
+	buzon *CasoUsoBuzonSalida,
+	cuotas *CasoUsoControlarCuotas,
+	tamanoLote int,
+	umbralAprobacion int64,
+	retencion time.Duration,
+	maximoTerminadas int,
+	rel reloj.Reloj,
+	log *logger.Logger,
+) *CasoUsoDifundirCanal {
+	return &CasoUsoDifundirCanal{
+		unidadTrabajo:           unidadTrabajo,
+		repositorioNotificacion: repositorioNotificacion,
+		repositorioCanal:        repositorioCanal,
+		repositorioInquilino:    repositorioInquilino,
+		buzon:                   buzon,
+		cuotas:                  cuotas,
+		tamanoLote:              tamanoLote,
+		umbralAprobacion:        umbralAprobacion,
+		retencion:               retencion,
+		maximoTerminadas:        maximoTerminadas,
+		reloj:                   rel,
+		logger:                  log,
+		difusiones:              make(map[string]*ProgresoDifusion),
+	}
+}
+
+// Iniciar valida el canal y lanza la difusión en segundo plano. Los canales que superan el
+// umbral de aprobación retornan ErrDifusionRequiereAprobacion: se difunden como borrador aprobado.
+func (c *CasoUsoDifundirCanal) Iniciar(ctx context.Context, canalID uint, solicitud dto.SolicitudDifusion) (*ProgresoDifusion, error) {
+	return c.iniciar(ctx, canalID, solicitud, false)
+}
+
+// RequiereAprobacion indica si una difusión a esa cantidad de suscriptores debe aprobarse
+func (c *CasoUsoDifundirCanal) RequiereAprobacion(suscriptores int64) bool {
+	return c.umbralAprobacion > 0 && suscriptores > c.umbralAprobacion
+}
+
+func (c *CasoUsoDifundirCanal) iniciar(ctx context.Context, canalID uint, solicitud dto.SolicitudDifusion, aprobada bool) (*ProgresoDifusion, error) {
+	canal, err := c.repositorioCanal.ObtenerPorID(ctx, canalID)
+	if err != nil {
+		return nil, err
+	}
+	if err := servicio.AutorizarInquilino(ctx, canal.InquilinoID); err != nil {
+		return nil, err
+	}
+	if !canal.EstaActivo() {
+		return nil, entidad.ErrCanalInactivo
+	}
+
+	// La difusión es del inquilino del canal aunque la inicie una clave de plataforma: con él se
+	// cobra la cuota y se guardan las notificaciones, en su región y esquema
+	inquilinoID := canal.InquilinoID
+	ctx, err = servicio.ContextoDeInquilino(ctx, c.repositorioInquilino, inquilinoID)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := c.repositorioCanal.ContarSuscriptores(ctx, canalID)
+	if err != nil {
+		return nil, err
+	}
+	if !aprobada && c.RequiereAprobacion(total) {
+		return nil, entidad.ErrDifusionRequiereAprobacion
+	}
+
+	// La cuota mensual se reserva completa antes de empezar y al terminar se devuelve lo que no se
+	// creó; el límite por segundo no aplica a lotes
+	if err := c.cuotas.ConsumirMensual(ctx, inquilinoID, solicitud.Tipo, total); err != nil {
+		return nil, err
+	}
+
+	progreso := &ProgresoDifusion{
+		ID:          strconv.FormatUint(c.secuencia.Add(1), 10),
+		InquilinoID: inquilinoID,
+		CanalID:     canalID,
+		Total:       total,
+		Estado:      EstadoDifusionEnCurso,
+		Inicio:      c.reloj.Ahora(),
+	}
+
+	c.mu.Lock()
+	c.purgar(progreso.Inicio)
+	c.difusiones[progreso.ID] = progreso
+	c.mu.Unlock()
+
+	// La difusión sobrevive a la petición HTTP que la inició, pero conserva su inquilino y esquema
+	go c.ejecutar(context.WithoutCancel(ctx), progreso, solicitud)
+
+	return c.copiar(progreso), nil
+}
+
+// ObtenerProgreso retorna una copia del progreso de una difusión del inquilino de la solicitud
+func (c *CasoUsoDifundirCanal) ObtenerProgreso(ctx context.Context, id string) (*ProgresoDifusion, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	progreso, existe := c.difusiones[id]
+	if !existe || c.vencida(progreso, c.reloj.Ahora()) || servicio.AutorizarInquilino(ctx, progreso.InquilinoID) != nil {
+		return nil, false
+	}
+	copia := *progreso
+	return &copia, true
+}
+
+func (c *CasoUsoDifundirCanal) copiar(progreso *ProgresoDifusion) *ProgresoDifusion {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	copia := *progreso
+	return &copia
+}
+
+func (c *CasoUsoDifundirCanal) ejecutar(ctx context.Context, progreso *ProgresoDifusion, solicitud dto.SolicitudDifusion) {
+	err := c.difundir(ctx, progreso, solicitud)
+
+	c.mu.Lock()
+	ahora := c.reloj.Ahora()
+	progreso.Fin = &ahora
+	if err != nil {
+		progreso.Estado = EstadoDifusionFallida
+		progreso.Error = err.Error()
+	} else {
+		progreso.Estado = EstadoDifusionCompletada
+	}
+	final := *progreso
+	c.purgar(ahora)
+	c.mu.Unlock()
+
+	if faltantes := final.Total - final.Creadas; faltantes > 0 {
+		if errCuota := c.cuotas.DevolverMensual(ctx, final.InquilinoID, solicitud.Tipo, faltantes, final.Inicio); errCuota != nil {
+			c.logger.Error("Error devolviendo la cuota de la difusión", "difusion_id", final.ID, "faltantes", faltantes, "error", errCuota)
+		}
+	}
+
+	if err != nil {
+		c.logger.Error("Difusión fallida", "difusion_id", final.ID, "canal_id", final.CanalID, "error", err)
+		return
+	}
+	c.logger.Info("Difusión completada",
+		"difusion_id", final.ID,
+		"canal_id", final.CanalID,
+		"creadas", final.Creadas,
+		"duracion_ms", ahora.Sub(final.Inicio).Milliseconds(),
+	)
+}
+
+// purgar descarta las difusiones terminadas hace más de la retención y, si aun así sobran, las
+// que terminaron primero. Las en curso no se descartan. Debe llamarse con c.mu tomado.
+func (c *CasoUsoDifundirCanal) purgar(ahora time.Time) {
+	var terminadas []*ProgresoDifusion
+	for id, progreso := range c.difusiones {
+		switch {
+		case progreso.Fin == nil:
+		case c.vencida(progreso, ahora):
+			delete(c.difusiones, id)
+		default:
+			terminadas = append(terminadas, progreso)
+		}
+	}
+
+	exceso := len(terminadas) - c.maximoTerminadas
+	if exceso <= 0 {
+		return
+	}
+	sort.Slice(terminadas, func(i, j int) bool { return terminadas[i].Fin.Before(*terminadas[j].Fin) })
+	for _, progreso := range terminadas[:exceso] {
+		delete(c.difusiones, progreso.ID)
+	}
+}
+
+func (c *CasoUsoDifundirCanal) vencida(progreso *ProgresoDifusion, ahora time.Time) bool {
+	return progreso.Fin != nil && ahora.Sub(*progreso.Fin) > c.retencion
+}
+
+// difundir recorre los suscriptores por cursor. Cada página se inserta y se anota en el buzón de
+// salida en una transacción, y se publica tras el commit: si la cola falla, el relevo la publica.
+func (c *CasoUsoDifundirCanal) difundir(ctx context.Context, progreso *ProgresoDifusion, solicitud dto.SolicitudDifusion) error {
+	var cursor uint
+	for {
+		ids, err := c.repositorioCanal.ListarIDsSuscriptores(ctx, progreso.CanalID, cursor, c.tamanoLote)
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		cursor = ids[len(ids)-1]
+
+		lote := make([]*entidad.Notificacion, 0, len(ids))
+		for _, usuarioID := range ids {
+			lote = append(lote, c.construir(usuarioID, progreso, solicitud))
+		}
+
+		err = c.unidadTrabajo.Ejecutar(ctx, func(ctx context.Context) error {
+			if err := c.repositorioNotificacion.CrearLote(ctx, lote, c.tamanoLote, nil); err != nil {
+				return err
+			}
+			return c.buzon.Anotar(ctx, lote...)
+		})
+		if err != nil {
+			return err
+		}
+		c.avanzar(&progreso.Creadas, len(lote))
+
+		if c.buzon.Publicar(ctx, lote...) {
+			c.avanzar(&progreso.Publicadas, len(lote))
+		}
+
+		actual := c.copiar(progreso)
+		c.logger.Debug("Lote de difusión procesado",
+			"difusion_id", actual.ID,
+			"creadas", actual.Creadas,
+			"total", actual.Total,
+		)
+	}
+}
+
+func (c *CasoUsoDifundirCanal) avanzar(contador *int64, cantidad int) {
+	c.mu.Lock()
+	*contador += int64(cantidad)
+	c.mu.Unlock()
+}
+
+func (c *CasoUsoDifundirCanal) construir(usuarioID uint, progreso *ProgresoDifusion, solicitud dto.SolicitudDifusion) *entidad.Notificacion {
+	notificacion := entidad.NuevaNotificacion(usuarioID, solicitud.Titulo, solicitud.Mensaje, solicitud.Tipo)
+	notificacion.InquilinoID = progreso.InquilinoID
+	notificacion.CanalID = progreso.CanalID
+	if solicitud.Prioridad != "" {
+		notificacion.Prioridad = solicitud.Prioridad
+	}
+	for clave, valor := range solicitud.Metadatos {
+		notificacion.EstablecerMetadato(clave, valor)
+	}
+	return notificacion
+}
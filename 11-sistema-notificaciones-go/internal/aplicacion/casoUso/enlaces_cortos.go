@@ -0,0 +1,156 @@
+package casoUso
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+const (
+	// intentosSlug acota los reintentos ante un slug ya usado, improbable con 7 o más caracteres
+	intentosSlug = 5
+	// maxURLEnlace es el tamaño de la columna; una URL más larga se envía sin acortar
+	maxURLEnlace = 2048
+)
+
+// urlEnTexto reconoce las URL http y https de un mensaje hasta el primer espacio o delimitador
+var urlEnTexto = regexp.MustCompile(`https?://[^\s<>"']+`)
+
+// CasoUsoEnlacesCortos reemplaza las URL largas de los SMS por enlaces de la redirección propia,
+// para que el mensaje entre en menos segmentos, y cuenta los clics de cada enlace
+type CasoUsoEnlacesCortos struct {
+	repositorioEnlace       repositorio.RepositorioEnlaceCorto
+	repositorioNotificacion repositorio.RepositorioNotificacion
+	urlBase                 string
+	vigencia                time.Duration
+	longitudSlug            int
+	reloj                   reloj.Reloj
+	logger                  *logger.Logger
+}
+
+// NuevoCasoUsoEnlacesCortos crea el caso de uso; con urlBase vacía los mensajes no se modifican
+func NuevoCasoUsoEnlacesCortos(
+	repositorioEnlace repositorio.RepositorioEnlaceCorto,
+	repositorioNotificacion repositorio.RepositorioNotificacion,
+	urlBase string,
+	vigencia time.Duration,
+	longitudSlug int,
+	rel reloj.Reloj,
+	log *logger.Logger,
+) *CasoUsoEnlacesCortos {
+	return &CasoUsoEnlacesCortos{
+		repositorioEnlace:       repositorioEnlace,
+		repositorioNotificacion: repositorioNotificacion,
+		urlBase:                 urlBase,
+		vigencia:                vigencia,
+		longitudSlug:            longitudSlug,
+		reloj:                   rel,
+		logger:                  log,
+	}
+}
+
+// Acortar retorna el mensaje de la notificación con sus URL acortadas. Solo reemplaza las que
+// son más largas que el enlace corto; una URL repetida, también entre reintentos del envío,
+// usa el mismo enlace mientras esté vigente.
+func (c *CasoUsoEnlacesCortos) Acortar(ctx context.Context, notificacion *entidad.Notificacion) (string, error) {
+	if c.urlBase == "" || !urlEnTexto.MatchString(notificacion.Mensaje) {
+		return notificacion.Mensaje, nil
+	}
+	existentes, err := c.repositorioEnlace.ListarPorNotificacion(ctx, notificacion.InquilinoID, notificacion.ID)
+	if err != nil {
+		return notificacion.Mensaje, err
+	}
+	ahora := c.reloj.Ahora()
+	acortadas := make(map[string]string, len(existentes))
+	for i := range existentes {
+		if !existentes[i].Vencido(ahora) {
+			acortadas[existentes[i].URL] = c.urlBase + "/" + existentes[i].Slug
+		}
+	}
+
+	largoEnlace := len(c.urlBase) + 1 + c.longitudSlug
+	var errAcortar error
+	mensaje := urlEnTexto.ReplaceAllStringFunc(notificacion.Mensaje, func(coincidencia string) string {
+		// La puntuación final suele ser parte de la oración y no de la URL
+		direccion := strings.TrimRight(coincidencia, ".,;:!?)]}")
+		resto := coincidencia[len(direccion):]
+		if len(direccion) <= largoEnlace || len(direccion) > maxURLEnlace || errAcortar != nil {
+			return coincidencia
+		}
+		if corta, ok := acortadas[direccion]; ok {
+			return corta + resto
+		}
+		corta, err := c.crear(ctx, notificacion, direccion)
+		if err != nil {
+			errAcortar = err
+			return coincidencia
+		}
+		acortadas[direccion] = corta
+		return corta + resto
+	})
+	if errAcortar != nil {
+		return notificacion.Mensaje, errAcortar
+	}
+	return mensaje, nil
+}
+
+// Resolver retorna la URL del enlace y cuenta el clic. El clic se registra sin demorar la
+// redirección más que un UPDATE; si falla, solo se registra en el log.
+func (c *CasoUsoEnlacesCortos) Resolver(ctx context.Context, slug string) (string, error) {
+	enlace, err := c.repositorioEnlace.ObtenerPorSlug(ctx, slug)
+	if err != nil {
+		return "", err
+	}
+	ahora := c.reloj.Ahora()
+	if enlace.Vencido(ahora) {
+		return "", entidad.ErrEnlaceCortoVencido
+	}
+	if err := c.repositorioEnlace.RegistrarClic(ctx, enlace.ID, ahora); err != nil {
+		c.logger.Warn("No se pudo registrar el clic del enlace corto", "enlace_id", enlace.ID, "error", err)
+	}
+	return enlace.URL, nil
+}
+
+// Listar retorna los enlaces de la notificación con sus clics
+func (c *CasoUsoEnlacesCortos) Listar(ctx context.Context, notificacionID uint) ([]entidad.EnlaceCorto, error) {
+	notificacion, err := c.repositorioNotificacion.ObtenerPorID(ctx, notificacionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := servicio.AutorizarInquilino(ctx, notificacion.InquilinoID); err != nil {
+		return nil, err
+	}
+	return c.repositorioEnlace.ListarPorNotificacion(ctx, notificacion.InquilinoID, notificacion.ID)
+}
+
+func (c *CasoUsoEnlacesCortos) crear(ctx context.Context, notificacion *entidad.Notificacion, direccion string) (string, error) {
+	for intento := 0; intento < intentosSlug; intento++ {
+		slug, err := entidad.NuevoSlugEnlace(c.longitudSlug)
+		if err != nil {
+			return "", err
+		}
+		enlace := &entidad.EnlaceCorto{
+			InquilinoID:      notificacion.InquilinoID,
+			NotificacionID:   notificacion.ID,
+			Slug:             slug,
+			URL:              direccion,
+			FechaVencimiento: c.reloj.Ahora().Add(c.vigencia),
+		}
+		creado, err := c.repositorioEnlace.Crear(ctx, enlace)
+		if err != nil {
+			return "", err
+		}
+		if creado {
+			return c.urlBase + "/" + slug, nil
+		}
+	}
+	return "", fmt.Errorf("no se encontró un slug libre en %d intentos", intentosSlug)
+}
@@ -0,0 +1,119 @@
+package casoUso
+
+import (
+	"context"
+	"time"
+
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// CasoUsoEnviarMultiCanal crea un envío multicanal con una notificación por canal
+type CasoUsoEnviarMultiCanal struct {
+	unidadTrabajo           repositorio.UnidadTrabajo
+	repositorioEnvio        repositorio.RepositorioEnvioMultiCanal
+	repositorioNotificacion repositorio.RepositorioNotificacion
+	buzon                   *CasoUsoBuzonSalida
+	cuotas                  *CasoUsoControlarCuotas
+	reloj                   reloj.Reloj
+}
+
+// NuevoCasoUsoEnviarMultiCanal crea una nueva instancia del caso de uso
+func NuevoCasoUsoEnviarMultiCanal(
+	unidadTrabajo repositorio.UnidadTrabajo,
+	repositorioEnvio repositorio.RepositorioEnvioMultiCanal,
+	repositorioNotificacion repositorio.RepositorioNotificacion,
+	buzon *CasoUsoBuzonSalida,
+	cuotas *CasoUsoControlarCuotas,
+	rel reloj.Reloj,
+) *CasoUsoEnviarMultiCanal {
+	return &CasoUsoEnviarMultiCanal{
+		unidadTrabajo:           unidadTrabajo,
+		repositorioEnvio:        repositorioEnvio,
+		repositorioNotificacion: repositorioNotificacion,
+		buzon:                   buzon,
+		cuotas:                  cuotas,
+		reloj:                   rel,
+	}
+}
+
+// Ejecutar registra el envío y sus notificaciones; los pasos con retraso quedan programados
+func (c *CasoUsoEnviarMultiCanal) Ejecutar(ctx context.Context, solicitud dto.SolicitudEnvioMultiCanal) (*entidad.EnvioMultiCanal, error) {
+	pasos := make([]entidad.PasoEnvio, 0, len(solicitud.Pasos))
+	for _, paso := range solicitud.Pasos {
+		pasos = append(pasos, entidad.PasoEnvio{
+			Tipo:            paso.Tipo,
+			RetrasoSegundos: paso.RetrasoSegundos,
+			OmitirSiLeida:   paso.OmitirSiLeida,
+		})
+	}
+	envio := entidad.NuevoEnvioMultiCanal(solicitud.UsuarioID, pasos)
+	if err := envio.Validar(); err != nil {
+		return nil, err
+	}
+
+	ahora := c.reloj.Ahora()
+	inquilinoID := servicio.InquilinoDesdeContexto(ctx)
+	notificaciones := make([]*entidad.Notificacion, 0, len(envio.Pasos))
+	for _, paso := range envio.Pasos {
+		notificacion := entidad.NuevaNotificacion(solicitud.UsuarioID, solicitud.Titulo, solicitud.Mensaje, paso.Tipo)
+		notificacion.InquilinoID = inquilinoID
+		notificacion.CanalID = solicitud.CanalID
+		if solicitud.Prioridad != "" {
+			notificacion.Prioridad = solicitud.Prioridad
+		}
+		for clave, valor := range solicitud.Metadatos {
+			notificacion.EstablecerMetadato(clave, valor)
+		}
+		if paso.RetrasoSegundos > 0 {
+			programada := ahora.Add(time.Duration(paso.RetrasoSegundos) * time.Second)
+			notificacion.FechaProgramada = &programada
+		}
+		if err := notificacion.Validar(); err != nil {
+			return nil, err
+		}
+		notificaciones = append(notificaciones, notificacion)
+	}
+
+	for _, notificacion := range notificaciones {
+		if err := c.cuotas.Consumir(ctx, inquilinoID, notificacion.Tipo, 1); err != nil {
+			return nil, err
+		}
+	}
+
+	inmediatas := make([]*entidad.Notificacion, 0, len(notificaciones))
+	for _, notificacion := range notificaciones {
+		if !notificacion.EstaProgramada(ahora) {
+			inmediatas = append(inmediatas, notificacion)
+		}
+	}
+
+	// El envío, sus notificaciones y las entradas del buzón de las inmediatas se crean juntos; la
+	// publicación ocurre tras el commit
+	err := c.unidadTrabajo.Ejecutar(ctx, func(ctx context.Context) error {
+		if err := c.repositorioEnvio.Crear(ctx, envio); err != nil {
+			return err
+		}
+		for _, notificacion := range notificaciones {
+			notificacion.EnvioID = &envio.ID
+		}
+		if err := c.repositorioNotificacion.CrearLote(ctx, notificaciones, len(notificaciones), nil); err != nil {
+			return err
+		}
+		return c.buzon.Anotar(ctx, inmediatas...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.buzon.Publicar(ctx, inmediatas...)
+
+	return envio, nil
+}
+
+// ObtenerEnvio retorna el envío con el estado de cada canal
+func (c *CasoUsoEnviarMultiCanal) ObtenerEnvio(ctx context.Context, id uint) (*entidad.EnvioMultiCanal, error) {
+	return c.repositorioEnvio.ObtenerPorID(ctx, id)
+}
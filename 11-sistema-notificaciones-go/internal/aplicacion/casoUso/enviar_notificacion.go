@@ -0,0 +1,194 @@
+package casoUso
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// CasoUsoEnviarNotificacion valida, registra y encola una notificación para su envío
+type CasoUsoEnviarNotificacion struct {
+	unidadTrabajo           repositorio.UnidadTrabajo
+	repositorioNotificacion repositorio.RepositorioNotificacion
+	buzon                   *CasoUsoBuzonSalida
+	cuotas                  *CasoUsoControlarCuotas
+	marca                   *CasoUsoMarcaInquilino
+	esquemas                *CasoUsoEsquemaMetadatos
+	guardias                *CasoUsoRotacionGuardia
+	adjuntos                *CasoUsoAdjuntos
+	deduplicacion           repositorio.VentanaDeduplicacion
+	ventana                 time.Duration
+	reloj                   reloj.Reloj
+}
+
+// NuevoCasoUsoEnviarNotificacion crea una nueva instancia del caso de uso.
+// Con ventana en 0 no se deduplica.
+func NuevoCasoUsoEnviarNotificacion(
+	unidadTrabajo repositorio.UnidadTrabajo,
+	repositorioNotificacion repositorio.RepositorioNotificacion,
+	buzon *CasoUsoBuzonSalida,
+	cuotas *CasoUsoControlarCuotas,
+	marca *CasoUsoMarcaInquilino,
+	esquemas *CasoUsoEsquemaMetadatos,
+	guardias *CasoUsoRotacionGuardia,
+	adjuntos *CasoUsoAdjuntos,
+	deduplicacion repositorio.VentanaDeduplicacion,
+	ventana time.Duration,
+	rel reloj.Reloj,
+) *CasoUsoEnviarNotificacion {
+	return &CasoUsoEnviarNotificacion{
+		unidadTrabajo:           unidadTrabajo,
+		repositorioNotificacion: repositorioNotificacion,
+		buzon:                   buzon,
+		cuotas:                  cuotas,
+		marca:                   marca,
+		esquemas:                esquemas,
+		guardias:                guardias,
+		adjuntos:                adjuntos,
+		deduplicacion:           deduplicacion,
+		ventana:                 ventana,
+		reloj:                   rel,
+	}
+}
+
+// Ejecutar crea la notificación en estado pendiente y la encola si no está programada.
+// Si una idéntica se creó dentro de la ventana retorna la original y nueva en false.
+func (c *CasoUsoEnviarNotificacion) Ejecutar(ctx context.Context, solicitud dto.SolicitudEnviarNotificacion) (notificacion *entidad.Notificacion, nueva bool, err error) {
+	if solicitud.GuardiaCanalID != 0 {
+		turno, err := c.guardias.Guardia(ctx, solicitud.GuardiaCanalID, instanteEnvio(solicitud, c.reloj.Ahora()))
+		if err != nil {
+			return nil, false, err
+		}
+		solicitud.UsuarioID = turno.UsuarioID
+	}
+	notificacion = entidad.NuevaNotificacion(solicitud.UsuarioID, solicitud.Titulo, solicitud.Mensaje, solicitud.Tipo)
+	notificacion.InquilinoID = servicio.InquilinoDesdeContexto(ctx)
+	notificacion.CanalID = solicitud.CanalID
+	notificacion.Origen = solicitud.Origen
+	notificacion.FechaProgramada = solicitud.FechaProgramada
+	if solicitud.AvisoLectura != nil {
+		notificacion.AvisoLecturaWebhook = solicitud.AvisoLectura.Webhook
+		notificacion.AvisoLecturaTema = solicitud.AvisoLectura.Tema
+	}
+	if solicitud.Prioridad != "" {
+		notificacion.Prioridad = solicitud.Prioridad
+	}
+	if err := c.esquemas.Validar(ctx, solicitud.CanalID, solicitud.Metadatos); err != nil {
+		return nil, false, err
+	}
+	for clave, valor := range solicitud.Metadatos {
+		notificacion.EstablecerMetadato(clave, valor)
+	}
+	if solicitud.GuardiaCanalID != 0 {
+		notificacion.EstablecerMetadato("guardia_canal_id", solicitud.GuardiaCanalID)
+	}
+	if solicitud.Plantilla != "" {
+		contenido, err := c.marca.RenderizarPlantilla(ctx, notificacion.InquilinoID, solicitud.Plantilla, solicitud.Tipo, solicitud.Datos)
+		if err != nil {
+			return nil, false, err
+		}
+		notificacion.Titulo, notificacion.Mensaje = contenido.Asunto, contenido.Cuerpo
+		for clave, valor := range contenido.Metadatos {
+			notificacion.EstablecerMetadato(clave, valor)
+		}
+	}
+
+	if err := notificacion.Validar(); err != nil {
+		return nil, false, err
+	}
+	if err := c.adjuntos.Validar(ctx, solicitud.AdjuntoIDs); err != nil {
+		return nil, false, err
+	}
+
+	// Las programadas las encola su planificador al llegar la fecha; no pasan por el buzón
+	inmediata := !notificacion.EstaProgramada(c.reloj.Ahora())
+	if c.ventana > 0 {
+		original, err := c.crearDeduplicada(ctx, notificacion, solicitud.AdjuntoIDs, inmediata)
+		if err != nil || original != nil {
+			return original, false, err
+		}
+	} else if err := c.crear(ctx, notificacion, solicitud.AdjuntoIDs, inmediata); err != nil {
+		return nil, false, err
+	}
+
+	// Tras el commit nada falla: si la cola no la acepta, la publica el relevo del buzón
+	if inmediata {
+		c.buzon.Publicar(ctx, notificacion)
+	}
+
+	return notificacion, true, nil
+}
+
+// crear inserta la notificación, le vincula los adjuntos, anota en el buzón de salida la
+// inmediata y consume la cuota, todo en la misma unidad de trabajo: si algo falla no queda
+// nada creado. Los adjuntos se vinculan antes de que el relevo pueda encolarla, así el enviador
+// de correo ya los encuentra.
+func (c *CasoUsoEnviarNotificacion) crear(ctx context.Context, notificacion *entidad.Notificacion, adjuntoIDs []uint, inmediata bool) error {
+	return c.unidadTrabajo.Ejecutar(ctx, func(ctx context.Context) error {
+		if err := c.repositorioNotificacion.Crear(ctx, notificacion); err != nil {
+			return err
+		}
+		if err := c.adjuntos.Vincular(ctx, adjuntoIDs, notificacion); err != nil {
+			return err
+		}
+		if inmediata {
+			if err := c.buzon.Anotar(ctx, notificacion); err != nil {
+				return err
+			}
+		}
+		return c.cuotas.Consumir(ctx, notificacion.InquilinoID, notificacion.Tipo, 1)
+	})
+}
+
+// instanteEnvio es cuándo se enviará la notificación: la fecha programada o ahora. La guardia
+// se resuelve para ese instante, así una programada llega a quien esté de turno entonces.
+func instanteEnvio(solicitud dto.SolicitudEnviarNotificacion, ahora time.Time) time.Time {
+	if solicitud.FechaProgramada != nil && solicitud.FechaProgramada.After(ahora) {
+		return *solicitud.FechaProgramada
+	}
+	return ahora
+}
+
+// crearDeduplicada reclama la huella antes de crear la notificación: si ya estaba, retorna la
+// original sin crear nada. La huella se asocia a la notificación tras el commit y solo se libera
+// si la creación se revierte. La cuota se consume al crear, así los duplicados no la gastan.
+func (c *CasoUsoEnviarNotificacion) crearDeduplicada(ctx context.Context, notificacion *entidad.Notificacion, adjuntoIDs []uint, inmediata bool) (*entidad.Notificacion, error) {
+	huella := notificacion.Huella()
+	originalID, reclamada, err := c.deduplicacion.Reclamar(ctx, huella, c.ventana)
+	if err != nil {
+		return nil, err
+	}
+	if !reclamada {
+		return c.original(ctx, huella, originalID, notificacion, adjuntoIDs, inmediata)
+	}
+
+	if err := c.crear(ctx, notificacion, adjuntoIDs, inmediata); err != nil {
+		// Nada se creó: la huella queda libre para reintentar
+		_ = c.deduplicacion.Liberar(ctx, huella, 0)
+		return nil, err
+	}
+	_ = c.deduplicacion.Confirmar(ctx, huella, notificacion.ID)
+	return nil, nil
+}
+
+// original retorna la notificación que reclamó la huella, o ErrNotificacionEnCreacion si aún no
+// se confirmó. Si la original se eliminó, libera la huella y crea la nueva.
+func (c *CasoUsoEnviarNotificacion) original(ctx context.Context, huella string, originalID uint, notificacion *entidad.Notificacion, adjuntoIDs []uint, inmediata bool) (*entidad.Notificacion, error) {
+	if originalID == 0 {
+		return nil, entidad.ErrNotificacionEnCreacion
+	}
+	original, err := c.repositorioNotificacion.ObtenerPorID(ctx, originalID)
+	if errors.Is(err, entidad.ErrNotificacionNoEncontrada) {
+		if err := c.deduplicacion.Liberar(ctx, huella, originalID); err != nil {
+			return nil, err
+		}
+		return c.crearDeduplicada(ctx, notificacion, adjuntoIDs, inmediata)
+	}
+	return original, err
+}
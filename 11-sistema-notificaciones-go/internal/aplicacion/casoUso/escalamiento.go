@@ -0,0 +1,248 @@
+package casoUso
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// tamanoLoteEscalamientos acota los escalamientos vencidos que se procesan por ubicación en cada pasada
+const tamanoLoteEscalamientos = 100
+
+// CasoUsoEscalamiento administra las políticas de escalamiento de los canales de seguridad y
+// avisa a cada paso de los incidentes en curso hasta que alguien los reconoce
+type CasoUsoEscalamiento struct {
+	repositorioEscalamiento repositorio.RepositorioEscalamiento
+	repositorioCanal        repositorio.RepositorioCanal
+	repositorioUsuario      repositorio.RepositorioUsuario
+	repositorioInquilino    repositorio.RepositorioInquilino
+	enviar                  *CasoUsoEnviarNotificacion
+	reloj                   reloj.Reloj
+	logger                  *logger.Logger
+}
+
+// NuevoCasoUsoEscalamiento crea una nueva instancia del caso de uso
+func NuevoCasoUsoEscalamiento(
+	repositorioEscalamiento repositorio.RepositorioEscalamiento,
+	repositorioCanal repositorio.RepositorioCanal,
+	repositorioUsuario repositorio.RepositorioUsuario,
+	repositorioInquilino repositorio.RepositorioInquilino,
+	enviar *CasoUsoEnviarNotificacion,
+	rel reloj.Reloj,
+	log *logger.Logger,
+) *CasoUsoEscalamiento {
+	return &CasoUsoEscalamiento{
+		repositorioEscalamiento: repositorioEscalamiento,
+		repositorioCanal:        repositorioCanal,
+		repositorioUsuario:      repositorioUsuario,
+		repositorioInquilino:    repositorioInquilino,
+		enviar:                  enviar,
+		reloj:                   rel,
+		logger:                  log,
+	}
+}
+
+// ObtenerPolitica retorna la política del canal
+func (c *CasoUsoEscalamiento) ObtenerPolitica(ctx context.Context, canalID uint) (*entidad.PoliticaEscalamiento, error) {
+	if _, err := c.autorizarCanal(ctx, canalID); err != nil {
+		return nil, err
+	}
+	return c.repositorioEscalamiento.ObtenerPolitica(ctx, canalID)
+}
+
+// GuardarPolitica reemplaza los pasos de la política del canal. Los destinatarios deben ser
+// usuarios del inquilino del canal.
+func (c *CasoUsoEscalamiento) GuardarPolitica(ctx context.Context, canalID uint, pasos []entidad.PasoEscalamiento) (*entidad.PoliticaEscalamiento, error) {
+	canal, err := c.autorizarCanal(ctx, canalID)
+	if err != nil {
+		return nil, err
+	}
+	politica := &entidad.PoliticaEscalamiento{InquilinoID: canal.InquilinoID, CanalID: canal.ID, Pasos: pasos}
+	if err := politica.Validar(); err != nil {
+		return nil, err
+	}
+	for _, paso := range pasos {
+		usuario, err := c.repositorioUsuario.ObtenerPorID(ctx, paso.UsuarioID)
+		if err != nil {
+			return nil, err
+		}
+		if usuario.InquilinoID != canal.InquilinoID {
+			return nil, entidad.NewErrorValidacion(fmt.Sprintf("El usuario %d no pertenece al inquilino del canal", usuario.ID))
+		}
+	}
+	if err := c.repositorioEscalamiento.GuardarPolitica(ctx, politica); err != nil {
+		return nil, err
+	}
+	return politica, nil
+}
+
+// EliminarPolitica quita la política del canal; los incidentes en curso siguen con sus pasos
+func (c *CasoUsoEscalamiento) EliminarPolitica(ctx context.Context, canalID uint) error {
+	if _, err := c.autorizarCanal(ctx, canalID); err != nil {
+		return err
+	}
+	return c.repositorioEscalamiento.EliminarPolitica(ctx, canalID)
+}
+
+// Iniciar dispara un incidente en el canal: crea el escalamiento con los pasos de su política
+// y avisa en el momento los que no tienen demora
+func (c *CasoUsoEscalamiento) Iniciar(ctx context.Context, canalID uint, titulo, mensaje string, metadatos map[string]interface{}) (*entidad.Escalamiento, error) {
+	canal, err := c.autorizarCanal(ctx, canalID)
+	if err != nil {
+		return nil, err
+	}
+	if !canal.EstaActivo() {
+		return nil, entidad.ErrCanalInactivo
+	}
+	politica, err := c.repositorioEscalamiento.ObtenerPolitica(ctx, canalID)
+	if err != nil {
+		return nil, err
+	}
+
+	escalamiento := entidad.NuevoEscalamiento(politica, titulo, mensaje, metadatos, c.reloj.Ahora())
+	if err := c.repositorioEscalamiento.Crear(ctx, escalamiento); err != nil {
+		return nil, err
+	}
+	if err := c.avisarVencidos(ctx, escalamiento); err != nil {
+		return nil, err
+	}
+	return escalamiento, nil
+}
+
+// Obtener retorna el escalamiento con los avisos enviados hasta el momento
+func (c *CasoUsoEscalamiento) Obtener(ctx context.Context, id uint) (*entidad.Escalamiento, error) {
+	escalamiento, err := c.repositorioEscalamiento.ObtenerPorID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := servicio.AutorizarInquilino(ctx, escalamiento.InquilinoID); err != nil {
+		return nil, err
+	}
+	return escalamiento, nil
+}
+
+// Reconocer detiene el escalamiento en nombre de uno de sus destinatarios
+func (c *CasoUsoEscalamiento) Reconocer(ctx context.Context, id, usuarioID uint) (*entidad.Escalamiento, error) {
+	escalamiento, err := c.Obtener(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := escalamiento.Reconocer(usuarioID, c.reloj.Ahora()); err != nil {
+		return nil, err
+	}
+	reconocido, err := c.repositorioEscalamiento.Reconocer(ctx, escalamiento)
+	if err != nil {
+		return nil, err
+	}
+	if !reconocido {
+		return nil, entidad.ErrEscalamientoFinalizado
+	}
+	return escalamiento, nil
+}
+
+// EjecutarVencidos avisa los pasos vencidos de los escalamientos activos de todos los inquilinos.
+// Cada paso se reclama antes de avisarlo, así varias instancias no envían el mismo aviso.
+func (c *CasoUsoEscalamiento) EjecutarVencidos(ctx context.Context) error {
+	contextos, err := servicio.ContextosDeDatos(ctx, c.repositorioInquilino)
+	var errs []error
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, ctxDatos := range contextos {
+		vencidos, err := c.repositorioEscalamiento.ListarVencidos(ctxDatos, c.reloj.Ahora(), tamanoLoteEscalamientos)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for i := range vencidos {
+			escalamiento := &vencidos[i]
+			// El aviso se envía con la región, el esquema y las credenciales del inquilino
+			ctxInquilino, err := servicio.ContextoDeInquilino(ctxDatos, c.repositorioInquilino, escalamiento.InquilinoID)
+			if err == nil {
+				err = c.avisarVencidos(ctxInquilino, escalamiento)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("escalamiento %d: %w", escalamiento.ID, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// avisarVencidos avisa en orden los pasos cuya demora ya pasó
+func (c *CasoUsoEscalamiento) avisarVencidos(ctx context.Context, escalamiento *entidad.Escalamiento) error {
+	for {
+		paso, vencido := escalamiento.PasoVencido(c.reloj.Ahora())
+		if !vencido {
+			return nil
+		}
+		indice := escalamiento.SiguientePaso
+		escalamiento.Avanzar()
+		reclamado, err := c.repositorioEscalamiento.Avanzar(ctx, escalamiento, indice)
+		if err != nil || !reclamado {
+			return err
+		}
+		if err := c.avisar(ctx, escalamiento, indice, *paso); err != nil {
+			return err
+		}
+	}
+}
+
+// avisar envía la notificación del paso ya reclamado. Si el envío falla el paso no se
+// reintenta: queda registrado con su error y el escalamiento sigue con el siguiente.
+func (c *CasoUsoEscalamiento) avisar(ctx context.Context, escalamiento *entidad.Escalamiento, indice int, paso entidad.PasoEscalamiento) error {
+	metadatos := make(map[string]interface{}, len(escalamiento.Metadatos)+3)
+	for clave, valor := range escalamiento.Metadatos {
+		metadatos[clave] = valor
+	}
+	metadatos["escalamiento_id"] = escalamiento.ID
+	metadatos["paso_escalamiento"] = indice + 1
+	metadatos["canal_escalamiento_id"] = escalamiento.CanalID
+
+	aviso := entidad.AvisoEscalamiento{Paso: indice + 1, UsuarioID: paso.UsuarioID, Tipo: paso.Tipo, Fecha: c.reloj.Ahora()}
+	notificacion, _, err := c.enviar.Ejecutar(ctx, dto.SolicitudEnviarNotificacion{
+		UsuarioID: paso.UsuarioID,
+		Titulo:    escalamiento.Titulo,
+		Mensaje:   escalamiento.Mensaje,
+		Tipo:      paso.Tipo,
+		Prioridad: entidad.PrioridadCritica,
+		Metadatos: metadatos,
+	})
+	if err != nil {
+		aviso.Error = err.Error()
+		c.logger.Warn("Error avisando un paso de escalamiento",
+			"escalamiento_id", escalamiento.ID,
+			"paso", aviso.Paso,
+			"usuario_id", paso.UsuarioID,
+			"error", err,
+		)
+	} else {
+		aviso.NotificacionID = notificacion.ID
+	}
+
+	escalamiento.Avisos = append(escalamiento.Avisos, aviso)
+	return c.repositorioEscalamiento.AgregarAviso(ctx, escalamiento.ID, aviso)
+}
+
+// autorizarCanal carga el canal, verifica que pertenezca al inquilino de la solicitud y que
+// sea de seguridad: las guardias solo escalan incidentes
+func (c *CasoUsoEscalamiento) autorizarCanal(ctx context.Context, canalID uint) (*entidad.Canal, error) {
+	canal, err := c.repositorioCanal.ObtenerPorID(ctx, canalID)
+	if err != nil {
+		return nil, err
+	}
+	if err := servicio.AutorizarInquilino(ctx, canal.InquilinoID); err != nil {
+		return nil, err
+	}
+	if canal.Tipo != entidad.TipoCanalSeguridad {
+		return nil, entidad.NewErrorValidacion("Las políticas de escalamiento solo aplican a canales de seguridad")
+	}
+	return canal, nil
+}
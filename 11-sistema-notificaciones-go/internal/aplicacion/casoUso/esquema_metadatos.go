@@ -0,0 +1,138 @@
+package casoUso
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/esquema"
+)
+
+// CasoUsoEsquemaMetadatos administra el JSON Schema de los metadatos de cada canal y valida
+// contra él los metadatos de las notificaciones, p. ej. los datos de un push que la app espera
+type CasoUsoEsquemaMetadatos struct {
+	repositorioCanal repositorio.RepositorioCanal
+
+	mu sync.Mutex
+	// compilados guarda por canal el esquema ya compilado y la versión del canal de la que salió
+	compilados map[uint]esquemaCompilado
+}
+
+type esquemaCompilado struct {
+	version time.Time
+	esquema *esquema.Esquema
+}
+
+// NuevoCasoUsoEsquemaMetadatos crea una nueva instancia del caso de uso
+func NuevoCasoUsoEsquemaMetadatos(repositorioCanal repositorio.RepositorioCanal) *CasoUsoEsquemaMetadatos {
+	return &CasoUsoEsquemaMetadatos{repositorioCanal: repositorioCanal, compilados: make(map[uint]esquemaCompilado)}
+}
+
+// Obtener retorna el esquema del canal; nil si no declaró ninguno
+func (c *CasoUsoEsquemaMetadatos) Obtener(ctx context.Context, canalID uint) (map[string]interface{}, error) {
+	canal, err := c.autorizarCanal(ctx, canalID)
+	if err != nil {
+		return nil, err
+	}
+	return canal.EsquemaMetadatos, nil
+}
+
+// Guardar reemplaza el esquema del canal; rechaza los esquemas que no compilan
+func (c *CasoUsoEsquemaMetadatos) Guardar(ctx context.Context, canalID uint, definicion map[string]interface{}) (*entidad.Canal, error) {
+	if _, err := esquema.Compilar(definicion); err != nil {
+		return nil, entidad.NewErrorValidacion(err.Error())
+	}
+	return c.actualizar(ctx, canalID, definicion)
+}
+
+// Eliminar quita el esquema: los metadatos del canal vuelven a ser libres
+func (c *CasoUsoEsquemaMetadatos) Eliminar(ctx context.Context, canalID uint) error {
+	_, err := c.actualizar(ctx, canalID, nil)
+	return err
+}
+
+// Validar verifica los metadatos contra el esquema del canal. Sin canal o sin esquema no
+// hay restricciones; las violaciones se retornan juntas en un *entidad.ErrorMetadatos.
+func (c *CasoUsoEsquemaMetadatos) Validar(ctx context.Context, canalID uint, metadatos map[string]interface{}) error {
+	if canalID == 0 {
+		return nil
+	}
+	canal, err := c.repositorioCanal.ObtenerPorID(ctx, canalID)
+	if err != nil {
+		return err
+	}
+	if len(canal.EsquemaMetadatos) == 0 {
+		return nil
+	}
+	compilado, err := c.compilar(canal)
+	if err != nil {
+		return err
+	}
+
+	documento := make(map[string]interface{}, len(metadatos))
+	for clave, valor := range metadatos {
+		documento[clave] = valor
+	}
+	violaciones := compilado.Validar(documento)
+	if len(violaciones) == 0 {
+		return nil
+	}
+	campos := make([]entidad.CampoInvalido, len(violaciones))
+	for i, violacion := range violaciones {
+		campos[i] = entidad.CampoInvalido{Campo: "metadatos", Regla: violacion.Regla, Mensaje: violacion.Mensaje}
+		if violacion.Ruta != "" {
+			campos[i].Campo += "." + violacion.Ruta
+		}
+	}
+	return &entidad.ErrorMetadatos{Campos: campos}
+}
+
+// compilar retorna el esquema compilado del canal. Se compila una vez por versión: cualquier
+// cambio del canal actualiza FechaActualizacion y el siguiente envío lo vuelve a compilar.
+func (c *CasoUsoEsquemaMetadatos) compilar(canal *entidad.Canal) (*esquema.Esquema, error) {
+	c.mu.Lock()
+	guardado, existe := c.compilados[canal.ID]
+	c.mu.Unlock()
+	if existe && guardado.version.Equal(canal.FechaActualizacion) {
+		return guardado.esquema, nil
+	}
+
+	compilado, err := esquema.Compilar(canal.EsquemaMetadatos)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.compilados[canal.ID] = esquemaCompilado{version: canal.FechaActualizacion, esquema: compilado}
+	c.mu.Unlock()
+	return compilado, nil
+}
+
+func (c *CasoUsoEsquemaMetadatos) actualizar(ctx context.Context, canalID uint, definicion map[string]interface{}) (*entidad.Canal, error) {
+	canal, err := c.autorizarCanal(ctx, canalID)
+	if err != nil {
+		return nil, err
+	}
+	canal.EsquemaMetadatos = definicion
+	if err := c.repositorioCanal.Actualizar(ctx, canal); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	delete(c.compilados, canalID)
+	c.mu.Unlock()
+	return canal, nil
+}
+
+// autorizarCanal carga el canal y verifica que pertenezca al inquilino de la solicitud
+func (c *CasoUsoEsquemaMetadatos) autorizarCanal(ctx context.Context, canalID uint) (*entidad.Canal, error) {
+	canal, err := c.repositorioCanal.ObtenerPorID(ctx, canalID)
+	if err != nil {
+		return nil, err
+	}
+	if err := servicio.AutorizarInquilino(ctx, canal.InquilinoID); err != nil {
+		return nil, err
+	}
+	return canal, nil
+}
@@ -0,0 +1,103 @@
+package casoUso
+
+import (
+	"context"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// CasoUsoEvaluarSLA mide el cumplimiento de los tiempos de envío al proveedor comprometidos con
+// cada inquilino
+type CasoUsoEvaluarSLA struct {
+	repositorioInquilino    repositorio.RepositorioInquilino
+	repositorioNotificacion repositorio.RepositorioNotificacion
+	reloj                   reloj.Reloj
+}
+
+// NuevoCasoUsoEvaluarSLA crea una nueva instancia del caso de uso
+func NuevoCasoUsoEvaluarSLA(
+	repositorioInquilino repositorio.RepositorioInquilino,
+	repositorioNotificacion repositorio.RepositorioNotificacion,
+	rel reloj.Reloj,
+) *CasoUsoEvaluarSLA {
+	return &CasoUsoEvaluarSLA{
+		repositorioInquilino:    repositorioInquilino,
+		repositorioNotificacion: repositorioNotificacion,
+		reloj:                   rel,
+	}
+}
+
+// GuardarObjetivo crea o actualiza el objetivo de una prioridad para un inquilino existente
+func (c *CasoUsoEvaluarSLA) GuardarObjetivo(ctx context.Context, objetivo *entidad.ObjetivoSLA) error {
+	if err := objetivo.Validar(); err != nil {
+		return err
+	}
+	if _, err := c.repositorioInquilino.ObtenerPorID(ctx, objetivo.InquilinoID); err != nil {
+		return err
+	}
+	return c.repositorioInquilino.GuardarObjetivoSLA(ctx, objetivo)
+}
+
+// Cumplimiento evalúa los objetivos del inquilino en el periodo "AAAA-MM" (vacío: mes en curso)
+func (c *CasoUsoEvaluarSLA) Cumplimiento(ctx context.Context, inquilinoID uint, periodo string) ([]entidad.CumplimientoSLA, error) {
+	if err := servicio.AutorizarInquilino(ctx, inquilinoID); err != nil {
+		return nil, err
+	}
+	if _, err := c.repositorioInquilino.ObtenerPorID(ctx, inquilinoID); err != nil {
+		return nil, err
+	}
+	desde, err := c.inicioPeriodo(periodo)
+	if err != nil {
+		return nil, err
+	}
+	return c.evaluar(ctx, inquilinoID, desde)
+}
+
+// EvaluarPeriodoActual evalúa los objetivos de todos los inquilinos en el mes en curso
+func (c *CasoUsoEvaluarSLA) EvaluarPeriodoActual(ctx context.Context) ([]entidad.CumplimientoSLA, error) {
+	desde, err := c.inicioPeriodo("")
+	if err != nil {
+		return nil, err
+	}
+	return c.evaluar(ctx, 0, desde)
+}
+
+func (c *CasoUsoEvaluarSLA) evaluar(ctx context.Context, inquilinoID uint, desde time.Time) ([]entidad.CumplimientoSLA, error) {
+	objetivos, err := c.repositorioInquilino.ListarObjetivosSLA(ctx, inquilinoID)
+	if err != nil {
+		return nil, err
+	}
+
+	hasta := desde.AddDate(0, 1, 0)
+	periodo := desde.Format("2006-01")
+	cumplimientos := make([]entidad.CumplimientoSLA, 0, len(objetivos))
+	for _, objetivo := range objetivos {
+		// Las notificaciones de un inquilino aislado están en su esquema
+		ctxInquilino, err := servicio.ContextoDeInquilino(ctx, c.repositorioInquilino, objetivo.InquilinoID)
+		if err != nil {
+			return nil, err
+		}
+		enviadas, dentro, err := c.repositorioNotificacion.MedirEnvios(ctxInquilino, objetivo.InquilinoID, objetivo.Prioridad, objetivo.Umbral(), desde, hasta)
+		if err != nil {
+			return nil, err
+		}
+		cumplimientos = append(cumplimientos, entidad.NuevoCumplimientoSLA(objetivo, periodo, enviadas, dentro))
+	}
+	return cumplimientos, nil
+}
+
+func (c *CasoUsoEvaluarSLA) inicioPeriodo(periodo string) (time.Time, error) {
+	if periodo == "" {
+		ahora := c.reloj.Ahora().UTC()
+		return time.Date(ahora.Year(), ahora.Month(), 1, 0, 0, 0, 0, time.UTC), nil
+	}
+	desde, err := time.Parse("2006-01", periodo)
+	if err != nil {
+		return time.Time{}, entidad.NewErrorValidacion("periodo debe tener formato AAAA-MM")
+	}
+	return desde, nil
+}
@@ -0,0 +1,143 @@
+package casoUso
+
+import (
+	"context"
+	"errors"
+
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/logger"
+)
+
+// CasoUsoEventosCorreo aplica a las notificaciones email los eventos que informa el proveedor:
+// entregas, rebotes, quejas y aperturas
+type CasoUsoEventosCorreo struct {
+	repositorioNotificacion repositorio.RepositorioNotificacion
+	repositorioInquilino    repositorio.RepositorioInquilino
+	estado                  *CasoUsoCambiarEstadoNotificacion
+	supresion               *CasoUsoListaSupresion
+	logger                  *logger.Logger
+}
+
+// NuevoCasoUsoEventosCorreo crea una nueva instancia del caso de uso
+func NuevoCasoUsoEventosCorreo(
+	repositorioNotificacion repositorio.RepositorioNotificacion,
+	repositorioInquilino repositorio.RepositorioInquilino,
+	estado *CasoUsoCambiarEstadoNotificacion,
+	supresion *CasoUsoListaSupresion,
+	log *logger.Logger,
+) *CasoUsoEventosCorreo {
+	return &CasoUsoEventosCorreo{
+		repositorioNotificacion: repositorioNotificacion,
+		repositorioInquilino:    repositorioInquilino,
+		estado:                  estado,
+		supresion:               supresion,
+		logger:                  log,
+	}
+}
+
+// Aplicar lleva la notificación a entregada, fallida o leída. Los eventos llegan sin orden y
+// pueden repetirse: los que ya no corresponden al estado de la notificación se descartan. Un
+// rebote permanente o una queja con destinatario lo suprime aunque la notificación ya no exista.
+func (c *CasoUsoEventosCorreo) Aplicar(ctx context.Context, evento entidad.EventoCorreo) error {
+	ctxDatos, err := servicio.ContextoDeInquilino(ctx, c.repositorioInquilino, evento.InquilinoID)
+	if err != nil {
+		return err
+	}
+	if evento.Destinatario != "" {
+		if err := c.suprimir(ctxDatos, evento); err != nil {
+			return err
+		}
+	}
+	notificacion, err := c.repositorioNotificacion.ObtenerPorID(ctxDatos, evento.NotificacionID)
+	if errors.Is(err, entidad.ErrNotificacionNoEncontrada) {
+		c.logger.Debug("Evento de correo de una notificación inexistente", "proveedor", evento.Proveedor, "notificacion_id", evento.NotificacionID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if notificacion.InquilinoID != evento.InquilinoID || notificacion.Tipo != entidad.TipoEmail {
+		return nil
+	}
+
+	switch evento.Tipo {
+	case entidad.EventoCorreoEntregado:
+		if notificacion.Estado != entidad.EstadoEnviada {
+			return nil
+		}
+		if err := notificacion.MarcarComoEntregada(); err != nil {
+			return err
+		}
+	case entidad.EventoCorreoRebotado:
+		if notificacion.Estado != entidad.EstadoEnviada {
+			// Un rebote tardío de un correo ya entregado no cambia la notificación
+			return nil
+		}
+		c.logger.Warn("El proveedor no pudo entregar el correo",
+			"notificacion_id", notificacion.ID,
+			"proveedor", evento.Proveedor,
+			"motivo", evento.Motivo,
+		)
+		if err := notificacion.MarcarComoFallida(); err != nil {
+			return err
+		}
+	case entidad.EventoCorreoAbierto:
+		if !notificacion.EstaNoLeida() {
+			return nil
+		}
+		// Se marca como cualquier lectura, con su aviso al servicio de origen
+		_, err := c.estado.MarcarComoLeida(ctxDatos, notificacion.ID)
+		return err
+	case entidad.EventoCorreoQueja:
+		// El correo llegó: la queja solo suprime la dirección
+		return nil
+	default:
+		return nil
+	}
+	return c.repositorioNotificacion.Actualizar(ctxDatos, notificacion)
+}
+
+// suprimir agrega el destinatario a la lista de supresión del inquilino. Una dirección que la
+// lista no admite se descarta: el proveedor repetiría el evento sin que pudiera cargarse nunca.
+func (c *CasoUsoEventosCorreo) suprimir(ctx context.Context, evento entidad.EventoCorreo) error {
+	var motivo entidad.MotivoSupresion
+	switch evento.Tipo {
+	case entidad.EventoCorreoRebotado:
+		motivo = entidad.MotivoRebote
+	case entidad.EventoCorreoQueja:
+		motivo = entidad.MotivoQueja
+	default:
+		return nil
+	}
+	detalle := evento.Proveedor
+	if evento.Motivo != "" {
+		detalle += ": " + evento.Motivo
+	}
+	if len(detalle) > largoMaximoDetalleSupresion {
+		detalle = detalle[:largoMaximoDetalleSupresion]
+	}
+
+	_, err := c.supresion.Agregar(ctx, dto.SolicitudSupresion{
+		Valor:       evento.Destinatario,
+		Motivo:      motivo,
+		Detalle:     detalle,
+		InquilinoID: evento.InquilinoID,
+	})
+	var errValidacion *entidad.ErrorValidacion
+	if errors.As(err, &errValidacion) {
+		c.logger.Warn("Destinatario del proveedor no suprimible", "proveedor", evento.Proveedor, "error", err)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	c.logger.Info("Dirección suprimida por el proveedor",
+		"inquilino_id", evento.InquilinoID,
+		"proveedor", evento.Proveedor,
+		"motivo", motivo,
+	)
+	return nil
+}
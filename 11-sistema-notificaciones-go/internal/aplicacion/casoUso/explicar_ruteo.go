@@ -0,0 +1,234 @@
+package casoUso
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// HorarioSilencioRuteo es el horario de silencio del usuario y si rige en este momento
+type HorarioSilencioRuteo struct {
+	Desde       string `json:"desde"`
+	Hasta       string `json:"hasta"`
+	ZonaHoraria string `json:"zona_horaria"`
+	Activo      bool   `json:"activo"`
+}
+
+// EscenarioRuteo es la notificación hipotética que se explica. Canal, origen y prioridad deciden
+// el consentimiento y los silenciamientos; sin prioridad se asume la normal.
+type EscenarioRuteo struct {
+	Tipo      entidad.TipoNotificacion
+	CanalID   uint
+	Origen    string
+	Prioridad entidad.PrioridadNotificacion
+}
+
+// DecisionRuteo explica qué pasaría hoy con una notificación del tipo para el usuario
+type DecisionRuteo struct {
+	UsuarioID   uint                          `json:"usuario_id"`
+	InquilinoID uint                          `json:"inquilino_id"`
+	Tipo        entidad.TipoNotificacion      `json:"tipo"`
+	CanalID     uint                          `json:"canal_id,omitempty"`
+	Origen      string                        `json:"origen,omitempty"`
+	Prioridad   entidad.PrioridadNotificacion `json:"prioridad"`
+	EvaluadaEn  time.Time                     `json:"evaluada_en"`
+	// Consentida es falsa si el canal requiere un consentimiento que el usuario no otorgó
+	Consentida     bool                    `json:"consentida"`
+	Silenciamiento *entidad.Silenciamiento `json:"silenciamiento,omitempty"`
+	// Preferencia es la guardada por el usuario; sin ella el tipo está habilitado
+	Preferencia     *entidad.PreferenciaNotificacion `json:"preferencia,omitempty"`
+	Habilitada      bool                             `json:"habilitada"`
+	HorarioSilencio *HorarioSilencioRuteo            `json:"horario_silencio,omitempty"`
+	Supresion       *entidad.EntradaSupresion        `json:"supresion,omitempty"`
+	// Dispositivos son los tokens push del usuario; solo se listan para el tipo push
+	Dispositivos []entidad.DispositivoPush `json:"dispositivos,omitempty"`
+	Ruta         *RutaEnvio                `json:"ruta,omitempty"`
+	ErrorRuta    string                    `json:"error_ruta,omitempty"`
+	Enviaria     bool                      `json:"enviaria"`
+	// Resultado es el estado en que el despacho dejaría la notificación
+	Resultado entidad.EstadoNotificacion `json:"resultado"`
+	// Bloqueos son los motivos por los que el despacho no enviaría, en el orden en que los
+	// evalúa: el primero es el que decide el resultado
+	Bloqueos []string `json:"bloqueos"`
+	// Advertencias explican lo que podría sorprender al usuario sin impedir el envío
+	Advertencias []string `json:"advertencias"`
+}
+
+// CasoUsoExplicarRuteo arma la decisión de entrega efectiva para un usuario y un tipo, para
+// responder consultas del tipo "¿por qué no me llegó la notificación?". Solo lee.
+type CasoUsoExplicarRuteo struct {
+	repositorioUsuario     repositorio.RepositorioUsuario
+	repositorioInquilino   repositorio.RepositorioInquilino
+	repositorioPreferencia repositorio.RepositorioPreferencia
+	repositorioDispositivo repositorio.RepositorioDispositivo
+	supresiones            *CasoUsoListaSupresion
+	despachar              *CasoUsoDespacharNotificacion
+	reloj                  reloj.Reloj
+}
+
+// NuevoCasoUsoExplicarRuteo crea una nueva instancia del caso de uso
+func NuevoCasoUsoExplicarRuteo(
+	repositorioUsuario repositorio.RepositorioUsuario,
+	repositorioInquilino repositorio.RepositorioInquilino,
+	repositorioPreferencia repositorio.RepositorioPreferencia,
+	repositorioDispositivo repositorio.RepositorioDispositivo,
+	supresiones *CasoUsoListaSupresion,
+	despachar *CasoUsoDespacharNotificacion,
+	rel reloj.Reloj,
+) *CasoUsoExplicarRuteo {
+	return &CasoUsoExplicarRuteo{
+		repositorioUsuario:     repositorioUsuario,
+		repositorioInquilino:   repositorioInquilino,
+		repositorioPreferencia: repositorioPreferencia,
+		repositorioDispositivo: repositorioDispositivo,
+		supresiones:            supresiones,
+		despachar:              despachar,
+		reloj:                  rel,
+	}
+}
+
+// Explicar evalúa consentimiento, supresión, silenciamiento y proveedor en el orden del despacho,
+// y agrega preferencias, horario de silencio y dispositivos como advertencias. La plataforma
+// indica inquilinoID cuando los datos del usuario están en una región o esquema propios; si no,
+// se usa el inquilino del usuario en la base principal.
+func (c *CasoUsoExplicarRuteo) Explicar(ctx context.Context, usuarioID, inquilinoID uint, escenario EscenarioRuteo) (*DecisionRuteo, error) {
+	var err error
+	if inquilinoID != 0 {
+		if err = servicio.AutorizarInquilino(ctx, inquilinoID); err != nil {
+			return nil, err
+		}
+		if ctx, err = servicio.ContextoDeInquilino(ctx, c.repositorioInquilino, inquilinoID); err != nil {
+			return nil, err
+		}
+	}
+
+	usuario, err := c.repositorioUsuario.ObtenerPorID(ctx, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	if err := servicio.AutorizarInquilino(ctx, usuario.InquilinoID); err != nil {
+		return nil, err
+	}
+	// El enrutamiento depende de la región y las credenciales del inquilino del usuario
+	if servicio.InquilinoDesdeContexto(ctx) == 0 && usuario.InquilinoID != 0 {
+		if ctx, err = servicio.ContextoDeInquilino(ctx, c.repositorioInquilino, usuario.InquilinoID); err != nil {
+			return nil, err
+		}
+	}
+
+	if escenario.Prioridad == "" {
+		escenario.Prioridad = entidad.PrioridadNormal
+	}
+	ahora := c.reloj.Ahora()
+	decision := &DecisionRuteo{
+		UsuarioID:    usuario.ID,
+		InquilinoID:  usuario.InquilinoID,
+		Tipo:         escenario.Tipo,
+		CanalID:      escenario.CanalID,
+		Origen:       escenario.Origen,
+		Prioridad:    escenario.Prioridad,
+		EvaluadaEn:   ahora,
+		Habilitada:   true,
+		Bloqueos:     []string{},
+		Advertencias: []string{},
+	}
+	notificacion := &entidad.Notificacion{
+		InquilinoID: usuario.InquilinoID,
+		UsuarioID:   usuario.ID,
+		Tipo:        escenario.Tipo,
+		CanalID:     escenario.CanalID,
+		Origen:      escenario.Origen,
+		Prioridad:   escenario.Prioridad,
+	}
+
+	// Mismos pasos y orden que CasoUsoDespacharNotificacion.Ejecutar
+	if decision.Consentida, err = c.despachar.consentimientos.Permite(ctx, notificacion); err != nil {
+		return nil, err
+	}
+	if !decision.Consentida {
+		c.bloquear(decision, entidad.EstadoCancelada, fmt.Sprintf("sin consentimiento vigente para el canal %d", escenario.CanalID))
+	}
+	if decision.Supresion, err = c.supresiones.Bloqueo(ctx, notificacion); err != nil {
+		return nil, err
+	}
+	if decision.Supresion != nil {
+		c.bloquear(decision, entidad.EstadoCancelada, fmt.Sprintf("contacto en la lista de supresión (entrada %d, motivo %s)", decision.Supresion.ID, decision.Supresion.Motivo))
+	}
+	if decision.Silenciamiento, err = c.despachar.silenciamientos.Silenciamiento(ctx, notificacion); err != nil {
+		return nil, err
+	}
+	if decision.Silenciamiento != nil {
+		c.bloquear(decision, entidad.EstadoEntregada, fmt.Sprintf("silenciada por el usuario (silenciamiento %d): queda en la bandeja sin avisar", decision.Silenciamiento.ID))
+	}
+
+	if err := c.explicarPreferencia(ctx, decision, ahora); err != nil {
+		return nil, err
+	}
+	if escenario.Tipo == entidad.TipoPush {
+		if decision.Dispositivos, err = c.repositorioDispositivo.ListarPorUsuario(ctx, usuario.ID); err != nil {
+			return nil, err
+		}
+		if len(decision.Dispositivos) == 0 {
+			decision.Advertencias = append(decision.Advertencias, "el usuario no tiene dispositivos push registrados")
+		}
+	}
+
+	if decision.Ruta, err = c.despachar.Enrutar(ctx, notificacion); err != nil {
+		decision.ErrorRuta = err.Error()
+		c.bloquear(decision, entidad.EstadoFallida, "sin proveedor: "+err.Error())
+	}
+
+	decision.Enviaria = len(decision.Bloqueos) == 0
+	if decision.Enviaria {
+		decision.Resultado = entidad.EstadoEnviada
+	}
+	return decision, nil
+}
+
+// bloquear agrega el motivo; el resultado lo fija el primer bloqueo, donde el despacho se detiene
+func (c *CasoUsoExplicarRuteo) bloquear(decision *DecisionRuteo, resultado entidad.EstadoNotificacion, motivo string) {
+	if len(decision.Bloqueos) == 0 {
+		decision.Resultado = resultado
+	}
+	decision.Bloqueos = append(decision.Bloqueos, motivo)
+}
+
+// explicarPreferencia agrega la preferencia y el horario de silencio del tipo. El despacho no
+// los aplica, así que solo generan advertencias.
+func (c *CasoUsoExplicarRuteo) explicarPreferencia(ctx context.Context, decision *DecisionRuteo, ahora time.Time) error {
+	preferencias, err := c.repositorioPreferencia.ListarPorUsuario(ctx, decision.UsuarioID)
+	if err != nil {
+		return err
+	}
+	for i := range preferencias {
+		if preferencias[i].Tipo == decision.Tipo {
+			decision.Preferencia = &preferencias[i]
+			break
+		}
+	}
+	if decision.Preferencia == nil {
+		return nil
+	}
+
+	decision.Habilitada = decision.Preferencia.Habilitada
+	if !decision.Habilitada {
+		decision.Advertencias = append(decision.Advertencias, fmt.Sprintf("%s deshabilitado por el usuario, pero el despacho no aplica preferencias", decision.Tipo))
+	}
+	if decision.Preferencia.TieneSilencio() {
+		decision.HorarioSilencio = &HorarioSilencioRuteo{
+			Desde:       decision.Preferencia.SilencioDesde,
+			Hasta:       decision.Preferencia.SilencioHasta,
+			ZonaHoraria: decision.Preferencia.ZonaHoraria,
+			Activo:      decision.Preferencia.EnSilencio(ahora),
+		}
+		if decision.HorarioSilencio.Activo {
+			decision.Advertencias = append(decision.Advertencias, "dentro del horario de silencio; el despacho no lo difiere")
+		}
+	}
+	return nil
+}
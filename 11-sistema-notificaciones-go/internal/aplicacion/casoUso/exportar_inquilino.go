@@ -0,0 +1,248 @@
+package casoUso
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// EstadoExportacion define los estados de una exportación
+type EstadoExportacion string
+
+const (
+	EstadoExportacionEnCurso    EstadoExportacion = "en_curso"
+	EstadoExportacionCompletada EstadoExportacion = "completada"
+	EstadoExportacionFallida    EstadoExportacion = "fallida"
+)
+
+// ExportacionInquilino refleja el avance de la exportación de los datos de un inquilino
+type ExportacionInquilino struct {
+	ID          string            `json:"id"`
+	InquilinoID uint              `json:"inquilino_id"`
+	Estado      EstadoExportacion `json:"estado"`
+	// Registros cuenta los registros escritos por sección del archivo
+	Registros map[string]int64 `json:"registros"`
+	Archivo   string           `json:"archivo"`
+	Error     string           `json:"error,omitempty"`
+	Inicio    time.Time        `json:"inicio"`
+	Fin       *time.Time       `json:"fin,omitempty"`
+}
+
+// manifiestoExportacion describe el contenido del archivo exportado
+type manifiestoExportacion struct {
+	Inquilino *entidad.Inquilino `json:"inquilino"`
+	Generado  time.Time          `json:"generado"`
+	Registros map[string]int64   `json:"registros"`
+}
+
+// CasoUsoExportarInquilino genera en segundo plano un archivo ZIP con todos los datos de un
+// inquilino (un JSON Lines por sección) para su portabilidad al darlo de baja
+type CasoUsoExportarInquilino struct {
+	repositorioInquilino repositorio.RepositorioInquilino
+	lector               repositorio.LectorDatosInquilino
+	almacen              repositorio.AlmacenArchivos
+	reloj                reloj.Reloj
+	logger               *logger.Logger
+
+	mu            sync.RWMutex
+	exportaciones map[string]*ExportacionInquilino
+	secuencia     atomic.Uint64
+}
+
+// NuevoCasoUsoExportarInquilino crea una nueva instancia del caso de uso
+func NuevoCasoUsoExportarInquilino(
+	repositorioInquilino repositorio.RepositorioInquilino,
+	lector repositorio.LectorDatosInquilino,
+	almacen repositorio.AlmacenArchivos,
+	rel reloj.Reloj,
+	log *logger.Logger,
+) *CasoUsoExportarInquilino {
+	return &CasoUsoExportarInquilino{
+		repositorioInquilino: repositorioInquilino,
+		lector:               lector,
+		almacen:              almacen,
+		reloj:                rel,
+		logger:               log,
+		exportaciones:        make(map[string]*ExportacionInquilino),
+	}
+}
+
+// Iniciar valida el inquilino y lanza la exportación en segundo plano
+func (c *CasoUsoExportarInquilino) Iniciar(ctx context.Context, inquilinoID uint) (*ExportacionInquilino, error) {
+	if err := servicio.AutorizarInquilino(ctx, inquilinoID); err != nil {
+		return nil, err
+	}
+	inquilino, err := c.repositorioInquilino.ObtenerPorID(ctx, inquilinoID)
+	if err != nil {
+		return nil, err
+	}
+	// Los datos de un inquilino aislado se leen de su esquema
+	ctxInquilino, err := servicio.ContextoDeInquilino(context.WithoutCancel(ctx), c.repositorioInquilino, inquilinoID)
+	if err != nil {
+		return nil, err
+	}
+
+	inicio := c.reloj.Ahora()
+	id := strconv.FormatUint(c.secuencia.Add(1), 10)
+	exportacion := &ExportacionInquilino{
+		ID:          id,
+		InquilinoID: inquilinoID,
+		Estado:      EstadoExportacionEnCurso,
+		Registros:   make(map[string]int64),
+		Archivo:     fmt.Sprintf("inquilino_%d_%s_%s.zip", inquilinoID, inicio.UTC().Format("20060102T150405"), id),
+		Inicio:      inicio,
+	}
+
+	c.mu.Lock()
+	c.exportaciones[id] = exportacion
+	c.mu.Unlock()
+
+	// La exportación sobrevive a la petición HTTP que la inició
+	go c.ejecutar(ctxInquilino, exportacion, inquilino)
+
+	return c.copiar(exportacion), nil
+}
+
+// Obtener retorna una copia del avance de una exportación del inquilino
+func (c *CasoUsoExportarInquilino) Obtener(ctx context.Context, inquilinoID uint, id string) (*ExportacionInquilino, error) {
+	c.mu.RLock()
+	exportacion, existe := c.exportaciones[id]
+	c.mu.RUnlock()
+
+	if !existe || exportacion.InquilinoID != inquilinoID || servicio.AutorizarInquilino(ctx, inquilinoID) != nil {
+		return nil, entidad.ErrExportacionNoEncontrada
+	}
+	return c.copiar(exportacion), nil
+}
+
+// Abrir retorna el archivo de una exportación completada junto a su nombre
+func (c *CasoUsoExportarInquilino) Abrir(ctx context.Context, inquilinoID uint, id string) (io.ReadCloser, string, error) {
+	exportacion, err := c.Obtener(ctx, inquilinoID, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if exportacion.Estado != EstadoExportacionCompletada {
+		return nil, "", entidad.ErrExportacionEnCurso
+	}
+	archivo, err := c.almacen.Abrir(ctx, exportacion.Archivo)
+	if err != nil {
+		return nil, "", err
+	}
+	return archivo, exportacion.Archivo, nil
+}
+
+func (c *CasoUsoExportarInquilino) copiar(exportacion *ExportacionInquilino) *ExportacionInquilino {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	copia := *exportacion
+	copia.Registros = make(map[string]int64, len(exportacion.Registros))
+	for seccion, cantidad := range exportacion.Registros {
+		copia.Registros[seccion] = cantidad
+	}
+	return &copia
+}
+
+func (c *CasoUsoExportarInquilino) ejecutar(ctx context.Context, exportacion *ExportacionInquilino, inquilino *entidad.Inquilino) {
+	err := c.exportar(ctx, exportacion, inquilino)
+
+	c.mu.Lock()
+	ahora := c.reloj.Ahora()
+	exportacion.Fin = &ahora
+	if err != nil {
+		exportacion.Estado = EstadoExportacionFallida
+		exportacion.Error = err.Error()
+	} else {
+		exportacion.Estado = EstadoExportacionCompletada
+	}
+	c.mu.Unlock()
+
+	if err != nil {
+		c.logger.Error("Exportación de inquilino fallida", "exportacion_id", exportacion.ID, "inquilino_id", exportacion.InquilinoID, "error", err)
+		return
+	}
+	c.logger.Info("Exportación de inquilino completada",
+		"exportacion_id", exportacion.ID,
+		"inquilino_id", exportacion.InquilinoID,
+		"duracion_ms", ahora.Sub(exportacion.Inicio).Milliseconds(),
+	)
+}
+
+// exportar escribe cada sección como JSON Lines dentro del ZIP y al final el manifiesto
+func (c *CasoUsoExportarInquilino) exportar(ctx context.Context, exportacion *ExportacionInquilino, inquilino *entidad.Inquilino) (err error) {
+	destino, err := c.almacen.Crear(ctx, exportacion.Archivo)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if errCerrar := destino.Close(); err == nil {
+			err = errCerrar
+		}
+	}()
+
+	archivo := zip.NewWriter(destino)
+	id := inquilino.ID
+	secciones := []struct {
+		nombre   string
+		recorrer func(escribir func(any) error) error
+	}{
+		{"usuarios", func(escribir func(any) error) error {
+			return c.lector.RecorrerUsuarios(ctx, id, func(u *entidad.Usuario) error { return escribir(u) })
+		}},
+		{"canales", func(escribir func(any) error) error {
+			return c.lector.RecorrerCanales(ctx, id, func(canal *entidad.Canal) error { return escribir(canal) })
+		}},
+		{"notificaciones", func(escribir func(any) error) error {
+			return c.lector.RecorrerNotificaciones(ctx, id, func(n *entidad.Notificacion) error { return escribir(n) })
+		}},
+		{"preferencias", func(escribir func(any) error) error {
+			return c.lector.RecorrerPreferencias(ctx, id, func(p *entidad.PreferenciaNotificacion) error { return escribir(p) })
+		}},
+		// La auditoría de la plataforma es el registro de intentos de envío a proveedores
+		{"auditoria", func(escribir func(any) error) error {
+			return c.lector.RecorrerIntentos(ctx, id, func(i *entidad.IntentoEnvio) error { return escribir(i) })
+		}},
+	}
+
+	for _, seccion := range secciones {
+		escritor, err := archivo.Create(seccion.nombre + ".jsonl")
+		if err != nil {
+			return err
+		}
+		codificador := json.NewEncoder(escritor)
+		nombre := seccion.nombre
+		err = seccion.recorrer(func(registro any) error {
+			if err := codificador.Encode(registro); err != nil {
+				return err
+			}
+			c.mu.Lock()
+			exportacion.Registros[nombre]++
+			c.mu.Unlock()
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("exportando %s: %w", nombre, err)
+		}
+	}
+
+	escritor, err := archivo.Create("manifiesto.json")
+	if err != nil {
+		return err
+	}
+	manifiesto := manifiestoExportacion{Inquilino: inquilino, Generado: c.reloj.Ahora(), Registros: c.copiar(exportacion).Registros}
+	if err := json.NewEncoder(escritor).Encode(manifiesto); err != nil {
+		return err
+	}
+	return archivo.Close()
+}
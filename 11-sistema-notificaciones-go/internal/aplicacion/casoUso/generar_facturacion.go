@@ -0,0 +1,46 @@
+package casoUso
+
+import (
+	"context"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+)
+
+// ReporteFacturacion es el consumo de un mes calendario (UTC) listo para facturar
+type ReporteFacturacion struct {
+	Periodo string                     `json:"periodo"`
+	Desde   time.Time                  `json:"desde"`
+	Hasta   time.Time                  `json:"hasta"`
+	Lineas  []entidad.LineaFacturacion `json:"lineas"`
+}
+
+// CasoUsoGenerarFacturacion arma el reporte mensual de facturación a partir de la medición de envíos
+type CasoUsoGenerarFacturacion struct {
+	repositorioConsumo repositorio.RepositorioConsumo
+}
+
+// NuevoCasoUsoGenerarFacturacion crea una nueva instancia del caso de uso
+func NuevoCasoUsoGenerarFacturacion(repositorioConsumo repositorio.RepositorioConsumo) *CasoUsoGenerarFacturacion {
+	return &CasoUsoGenerarFacturacion{repositorioConsumo: repositorioConsumo}
+}
+
+// Ejecutar genera el reporte del periodo "AAAA-MM"; un inquilino solo ve su propio consumo
+func (c *CasoUsoGenerarFacturacion) Ejecutar(ctx context.Context, periodo string) (*ReporteFacturacion, error) {
+	desde, err := time.Parse("2006-01", periodo)
+	if err != nil {
+		return nil, entidad.NewErrorValidacion("periodo debe tener formato AAAA-MM")
+	}
+	hasta := desde.AddDate(0, 1, 0)
+
+	lineas, err := c.repositorioConsumo.Resumir(ctx, desde, hasta, servicio.InquilinoDesdeContexto(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if lineas == nil {
+		lineas = []entidad.LineaFacturacion{}
+	}
+	return &ReporteFacturacion{Periodo: periodo, Desde: desde, Hasta: hasta, Lineas: lineas}, nil
+}
@@ -0,0 +1,103 @@
+package casoUso
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// CasoUsoClavesAPI emite, revoca y verifica claves de API ligadas a inquilinos
+type CasoUsoClavesAPI struct {
+	repositorioClave     repositorio.RepositorioClaveAPI
+	repositorioInquilino repositorio.RepositorioInquilino
+	reloj                reloj.Reloj
+}
+
+// NuevoCasoUsoClavesAPI crea una nueva instancia del caso de uso
+func NuevoCasoUsoClavesAPI(
+	repositorioClave repositorio.RepositorioClaveAPI,
+	repositorioInquilino repositorio.RepositorioInquilino,
+	rel reloj.Reloj,
+) *CasoUsoClavesAPI {
+	return &CasoUsoClavesAPI{
+		repositorioClave:     repositorioClave,
+		repositorioInquilino: repositorioInquilino,
+		reloj:                rel,
+	}
+}
+
+// Crear emite una clave y retorna su valor completo, que no vuelve a poder consultarse.
+// Un administrador de inquilino solo puede emitir claves de su propio inquilino.
+func (c *CasoUsoClavesAPI) Crear(ctx context.Context, solicitud dto.SolicitudClaveAPI) (*entidad.ClaveAPI, string, error) {
+	if propio := servicio.InquilinoDesdeContexto(ctx); propio != 0 {
+		if solicitud.Rol != entidad.RolAdminInquilino {
+			return nil, "", entidad.ErrAccesoDenegado
+		}
+		if solicitud.InquilinoID == 0 {
+			solicitud.InquilinoID = propio
+		}
+	}
+	if err := servicio.AutorizarInquilino(ctx, solicitud.InquilinoID); err != nil {
+		return nil, "", err
+	}
+
+	clave, valor, err := entidad.NuevaClaveAPI(solicitud.Nombre, solicitud.Rol, solicitud.InquilinoID)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := clave.Validar(); err != nil {
+		return nil, "", err
+	}
+	if clave.InquilinoID != 0 {
+		if _, err := c.repositorioInquilino.ObtenerPorID(ctx, clave.InquilinoID); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := c.repositorioClave.Crear(ctx, clave); err != nil {
+		return nil, "", err
+	}
+	return clave, valor, nil
+}
+
+// Listar retorna las claves visibles para la solicitud: las del inquilino o todas para la plataforma
+func (c *CasoUsoClavesAPI) Listar(ctx context.Context) ([]entidad.ClaveAPI, error) {
+	return c.repositorioClave.Listar(ctx, servicio.InquilinoDesdeContexto(ctx))
+}
+
+// Revocar invalida una clave del inquilino de la solicitud
+func (c *CasoUsoClavesAPI) Revocar(ctx context.Context, id uint) (*entidad.ClaveAPI, error) {
+	clave, err := c.repositorioClave.ObtenerPorID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := servicio.AutorizarInquilino(ctx, clave.InquilinoID); err != nil {
+		return nil, err
+	}
+
+	clave.Revocar(c.reloj.Ahora())
+	if err := c.repositorioClave.Actualizar(ctx, clave); err != nil {
+		return nil, err
+	}
+	return clave, nil
+}
+
+// Autenticar retorna la clave activa correspondiente al valor "prefijo.secreto"
+func (c *CasoUsoClavesAPI) Autenticar(ctx context.Context, valor string) (*entidad.ClaveAPI, error) {
+	prefijo, secreto, ok := entidad.SepararClaveAPI(valor)
+	if !ok {
+		return nil, entidad.ErrClaveAPINoEncontrada
+	}
+	clave, err := c.repositorioClave.ObtenerPorPrefijo(ctx, prefijo)
+	if err != nil {
+		return nil, err
+	}
+	if !clave.Verificar(secreto) || !clave.EstaActiva() {
+		return nil, entidad.ErrClaveAPINoEncontrada
+	}
+	return clave, nil
+}
@@ -0,0 +1,62 @@
+package casoUso
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// CasoUsoLimitarSolicitudes limita las solicitudes a la API de cada cliente por ventana fija,
+// con contadores compartidos entre instancias
+type CasoUsoLimitarSolicitudes struct {
+	contador repositorio.ContadorUso
+	limite   int64
+	ventana  time.Duration
+	reloj    reloj.Reloj
+	logger   *logger.Logger
+}
+
+// NuevoCasoUsoLimitarSolicitudes crea una nueva instancia del caso de uso. Con limite en 0
+// no se limitan las solicitudes.
+func NuevoCasoUsoLimitarSolicitudes(
+	contador repositorio.ContadorUso,
+	limite int64,
+	ventana time.Duration,
+	rel reloj.Reloj,
+	log *logger.Logger,
+) *CasoUsoLimitarSolicitudes {
+	return &CasoUsoLimitarSolicitudes{
+		contador: contador,
+		limite:   limite,
+		ventana:  ventana,
+		reloj:    rel,
+		logger:   log,
+	}
+}
+
+// Consumir descuenta una solicitud del cliente y registra el estado del límite en el contexto.
+// Si el contador no responde la solicitud se admite: el límite protege, no debe tumbar la API.
+func (c *CasoUsoLimitarSolicitudes) Consumir(ctx context.Context, cliente string) error {
+	if c.limite <= 0 {
+		return nil
+	}
+	inicio := c.reloj.Ahora().UTC().Truncate(c.ventana)
+	clave := fmt.Sprintf("api:%s:%d", cliente, inicio.Unix())
+	total, err := c.contador.Incrementar(ctx, clave, 1, c.ventana+time.Second)
+	if err != nil {
+		c.logger.Warn("Error contando la solicitud para el límite de la API", "cliente", cliente, "error", err)
+		return nil
+	}
+
+	servicio.RegistrarLimite(ctx, entidad.NuevoEstadoLimite(c.limite, total, inicio.Add(c.ventana)))
+	if total > c.limite {
+		return entidad.ErrLimiteSolicitudesExcedido
+	}
+	return nil
+}
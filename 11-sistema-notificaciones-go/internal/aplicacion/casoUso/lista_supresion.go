@@ -0,0 +1,214 @@
+package casoUso
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+const (
+	// tamanoLoteImportacion es la cantidad de entradas por INSERT al importar
+	tamanoLoteImportacion = 500
+	// maxErroresImportacion acota los errores por línea informados en la respuesta
+	maxErroresImportacion = 100
+	// largoMaximoDetalleSupresion es el largo que admite el detalle de una entrada
+	largoMaximoDetalleSupresion = 500
+)
+
+// ErrorLineaImportacion describe una línea del archivo importado que no se pudo cargar
+type ErrorLineaImportacion struct {
+	Linea int    `json:"linea"`
+	Error string `json:"error"`
+}
+
+// ResultadoImportacionSupresion resume una importación a la lista de supresión
+type ResultadoImportacionSupresion struct {
+	Importadas int64                   `json:"importadas"`
+	Existentes int64                   `json:"existentes"`
+	Invalidas  int                     `json:"invalidas"`
+	Errores    []ErrorLineaImportacion `json:"errores,omitempty"`
+}
+
+// CasoUsoListaSupresion mantiene la lista de no contactar y decide si una notificación va a
+// una dirección suprimida. Cada inquilino administra la suya; las entradas de la plataforma
+// aplican a todos.
+type CasoUsoListaSupresion struct {
+	repositorioSupresion repositorio.RepositorioSupresion
+	repositorioUsuario   repositorio.RepositorioUsuario
+	reloj                reloj.Reloj
+}
+
+// NuevoCasoUsoListaSupresion crea una nueva instancia del caso de uso
+func NuevoCasoUsoListaSupresion(
+	repositorioSupresion repositorio.RepositorioSupresion,
+	repositorioUsuario repositorio.RepositorioUsuario,
+	rel reloj.Reloj,
+) *CasoUsoListaSupresion {
+	return &CasoUsoListaSupresion{
+		repositorioSupresion: repositorioSupresion,
+		repositorioUsuario:   repositorioUsuario,
+		reloj:                rel,
+	}
+}
+
+// Agregar suprime una dirección. Sin inquilino en la solicitud (plataforma) la entrada es
+// global salvo que se indique uno.
+func (c *CasoUsoListaSupresion) Agregar(ctx context.Context, solicitud dto.SolicitudSupresion) (*entidad.EntradaSupresion, error) {
+	inquilinoID, err := c.inquilinoDestino(ctx, solicitud.InquilinoID)
+	if err != nil {
+		return nil, err
+	}
+	entrada := entidad.NuevaEntradaSupresion(inquilinoID, solicitud.Valor, solicitud.Motivo, solicitud.Detalle, c.reloj.Ahora())
+	if err := entrada.Validar(); err != nil {
+		return nil, err
+	}
+	if err := c.repositorioSupresion.Agregar(ctx, entrada); err != nil {
+		return nil, err
+	}
+	return entrada, nil
+}
+
+// Eliminar quita una entrada; un inquilino solo puede quitar las suyas
+func (c *CasoUsoListaSupresion) Eliminar(ctx context.Context, id uint) error {
+	entrada, err := c.repositorioSupresion.ObtenerPorID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := servicio.AutorizarInquilino(ctx, entrada.InquilinoID); err != nil {
+		return err
+	}
+	return c.repositorioSupresion.Eliminar(ctx, id)
+}
+
+// Buscar retorna una página de entradas; un inquilino solo ve las suyas
+func (c *CasoUsoListaSupresion) Buscar(ctx context.Context, filtro repositorio.FiltroSupresion) ([]entidad.EntradaSupresion, error) {
+	if inquilinoID := servicio.InquilinoDesdeContexto(ctx); inquilinoID != 0 {
+		if filtro.InquilinoID != 0 && filtro.InquilinoID != inquilinoID {
+			return nil, entidad.ErrAccesoDenegado
+		}
+		filtro.InquilinoID = inquilinoID
+	}
+	if filtro.Valor != "" {
+		_, filtro.Valor = entidad.NormalizarContacto(filtro.Valor)
+	}
+	return c.repositorioSupresion.Buscar(ctx, filtro)
+}
+
+// Importar carga un CSV con columnas valor, motivo y detalle (las dos últimas opcionales; sin
+// motivo se usa motivoPredeterminado). Una primera línea que empieza con "valor" se toma como
+// encabezado. Las líneas inválidas se informan sin detener la importación.
+func (c *CasoUsoListaSupresion) Importar(ctx context.Context, inquilinoSolicitado uint, archivo io.Reader, motivoPredeterminado entidad.MotivoSupresion) (*ResultadoImportacionSupresion, error) {
+	inquilinoID, err := c.inquilinoDestino(ctx, inquilinoSolicitado)
+	if err != nil {
+		return nil, err
+	}
+
+	lector := csv.NewReader(archivo)
+	lector.FieldsPerRecord = -1
+	lector.TrimLeadingSpace = true
+
+	resultado := &ResultadoImportacionSupresion{}
+	lote := make([]*entidad.EntradaSupresion, 0, tamanoLoteImportacion)
+	guardar := func() error {
+		insertadas, err := c.repositorioSupresion.AgregarLote(ctx, lote)
+		if err != nil {
+			return err
+		}
+		resultado.Importadas += insertadas
+		resultado.Existentes += int64(len(lote)) - insertadas
+		lote = lote[:0]
+		return nil
+	}
+
+	ahora := c.reloj.Ahora()
+	for linea := 1; ; linea++ {
+		registro, err := lector.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, entidad.NewErrorValidacion(fmt.Sprintf("CSV inválido en la línea %d: %v", linea, err))
+		}
+		if linea == 1 && strings.EqualFold(strings.TrimSpace(registro[0]), "valor") {
+			continue
+		}
+
+		motivo, detalle := motivoPredeterminado, ""
+		if len(registro) > 1 && strings.TrimSpace(registro[1]) != "" {
+			motivo = entidad.MotivoSupresion(strings.TrimSpace(registro[1]))
+		}
+		if len(registro) > 2 {
+			detalle = strings.TrimSpace(registro[2])
+		}
+		entrada := entidad.NuevaEntradaSupresion(inquilinoID, registro[0], motivo, detalle, ahora)
+		if err := entrada.Validar(); err != nil {
+			resultado.Invalidas++
+			if len(resultado.Errores) < maxErroresImportacion {
+				resultado.Errores = append(resultado.Errores, ErrorLineaImportacion{Linea: linea, Error: err.Error()})
+			}
+			continue
+		}
+
+		lote = append(lote, entrada)
+		if len(lote) == tamanoLoteImportacion {
+			if err := guardar(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := guardar(); err != nil {
+		return nil, err
+	}
+	return resultado, nil
+}
+
+// Bloqueo retorna la entrada que impide enviar la notificación a su usuario, o nil si puede
+// enviarse. Se consulta antes de cada envío, sin importar las preferencias del usuario.
+func (c *CasoUsoListaSupresion) Bloqueo(ctx context.Context, notificacion *entidad.Notificacion) (*entidad.EntradaSupresion, error) {
+	usuario, err := c.repositorioUsuario.ObtenerPorID(ctx, notificacion.UsuarioID)
+	if errors.Is(err, entidad.ErrUsuarioNoEncontrado) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var valores []string
+	for _, contacto := range []string{usuario.CorreoElectronico, usuario.Telefono} {
+		if tipo, valor := entidad.NormalizarContacto(contacto); tipo != "" {
+			valores = append(valores, valor)
+		}
+	}
+	entradas, err := c.repositorioSupresion.Coincidencias(ctx, notificacion.InquilinoID, valores)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entradas {
+		if entradas[i].Bloquea(notificacion.Tipo) {
+			return &entradas[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// inquilinoDestino resuelve a qué lista va una entrada: la del inquilino de la solicitud o,
+// para la plataforma, la del inquilino indicado (0 es la lista global)
+func (c *CasoUsoListaSupresion) inquilinoDestino(ctx context.Context, solicitado uint) (uint, error) {
+	inquilinoID := servicio.InquilinoDesdeContexto(ctx)
+	if inquilinoID == 0 {
+		return solicitado, nil
+	}
+	if solicitado != 0 && solicitado != inquilinoID {
+		return 0, entidad.ErrAccesoDenegado
+	}
+	return inquilinoID, nil
+}
@@ -0,0 +1,100 @@
+package casoUso
+
+import (
+	"context"
+	"errors"
+
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+)
+
+// CasoUsoMarcaInquilino administra la marca de cada inquilino y la aplica al renderizar plantillas
+type CasoUsoMarcaInquilino struct {
+	repositorioInquilino repositorio.RepositorioInquilino
+	repositorioPlantilla repositorio.RepositorioPlantilla
+}
+
+// NuevoCasoUsoMarcaInquilino crea una nueva instancia del caso de uso
+func NuevoCasoUsoMarcaInquilino(repositorioInquilino repositorio.RepositorioInquilino, repositorioPlantilla repositorio.RepositorioPlantilla) *CasoUsoMarcaInquilino {
+	return &CasoUsoMarcaInquilino{
+		repositorioInquilino: repositorioInquilino,
+		repositorioPlantilla: repositorioPlantilla,
+	}
+}
+
+// Obtener retorna la marca configurada del inquilino
+func (c *CasoUsoMarcaInquilino) Obtener(ctx context.Context, inquilinoID uint) (*entidad.MarcaInquilino, error) {
+	if err := servicio.AutorizarInquilino(ctx, inquilinoID); err != nil {
+		return nil, err
+	}
+	return c.repositorioInquilino.ObtenerMarca(ctx, inquilinoID)
+}
+
+// Guardar crea o reemplaza la marca del inquilino
+func (c *CasoUsoMarcaInquilino) Guardar(ctx context.Context, inquilinoID uint, solicitud dto.SolicitudMarca) (*entidad.MarcaInquilino, error) {
+	if err := servicio.AutorizarInquilino(ctx, inquilinoID); err != nil {
+		return nil, err
+	}
+	if _, err := c.repositorioInquilino.ObtenerPorID(ctx, inquilinoID); err != nil {
+		return nil, err
+	}
+
+	marca := &entidad.MarcaInquilino{
+		InquilinoID:     inquilinoID,
+		LogoURL:         solicitud.LogoURL,
+		ColorPrimario:   solicitud.ColorPrimario,
+		ColorSecundario: solicitud.ColorSecundario,
+		NombreRemitente: solicitud.NombreRemitente,
+		CorreoRemitente: solicitud.CorreoRemitente,
+		PiePagina:       solicitud.PiePagina,
+	}
+	if err := marca.Validar(); err != nil {
+		return nil, err
+	}
+	if err := c.repositorioInquilino.GuardarMarca(ctx, marca); err != nil {
+		return nil, err
+	}
+	return marca, nil
+}
+
+// RenderizarPlantilla renderiza la plantilla del inquilino para el tipo de notificación dado
+// y le aplica su marca. La marca también está disponible en la plantilla como {{.Marca}}.
+func (c *CasoUsoMarcaInquilino) RenderizarPlantilla(ctx context.Context, inquilinoID uint, nombre string, tipo entidad.TipoNotificacion, datos map[string]interface{}) (*servicio.ContenidoRenderizado, error) {
+	asunto, cuerpo, marca, err := c.renderizar(ctx, inquilinoID, nombre, datos)
+	if err != nil {
+		return nil, err
+	}
+	return servicio.AplicarMarca(tipo, asunto, cuerpo, marca)
+}
+
+// renderizar ejecuta la plantilla con los datos y retorna también la marca, nil si el inquilino no tiene
+func (c *CasoUsoMarcaInquilino) renderizar(ctx context.Context, inquilinoID uint, nombre string, datos map[string]interface{}) (string, string, *entidad.MarcaInquilino, error) {
+	plantilla, err := c.repositorioPlantilla.ObtenerPorNombre(ctx, inquilinoID, nombre)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	marca, err := c.repositorioInquilino.ObtenerMarca(ctx, inquilinoID)
+	if errors.Is(err, entidad.ErrMarcaNoEncontrada) {
+		marca, err = nil, nil
+	}
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	valores := make(map[string]interface{}, len(datos)+1)
+	for clave, valor := range datos {
+		valores[clave] = valor
+	}
+	if _, existe := valores["Marca"]; !existe && marca != nil {
+		valores["Marca"] = marca
+	}
+
+	asunto, cuerpo, err := plantilla.Renderizar(valores)
+	if err != nil {
+		return "", "", nil, err
+	}
+	return asunto, cuerpo, marca, nil
+}
@@ -0,0 +1,302 @@
+package casoUso
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+)
+
+// versionTextoInvitacion identifica el texto de la invitación; aceptarla a un canal de marketing
+// registra el consentimiento con esta versión como evidencia
+const versionTextoInvitacion = "invitacion_canal_v1"
+
+// CambiarPrivacidad marca el canal como privado o público en nombre de un administrador.
+// Las membresías confirmadas se conservan en ambos sentidos.
+func (c *CasoUsoSuscripcionCanal) CambiarPrivacidad(ctx context.Context, canalID uint, privado bool) (*entidad.Canal, error) {
+	if _, err := actorConRol(ctx, entidad.RolAdministrador); err != nil {
+		return nil, err
+	}
+	canal, err := c.autorizarCanal(ctx, canalID)
+	if err != nil {
+		return nil, err
+	}
+	if canal.Privado == privado {
+		return canal, nil
+	}
+	canal.Privado = privado
+	if err := c.repositorioCanal.Actualizar(ctx, canal); err != nil {
+		return nil, err
+	}
+	return canal, nil
+}
+
+// Invitar invita al usuario a un canal privado en nombre de un administrador y le envía el
+// enlace firmado para aceptar. Reemplaza un pedido pendiente o rechazado; volver a invitar
+// reenvía el enlace.
+func (c *CasoUsoSuscripcionCanal) Invitar(ctx context.Context, canalID uint, solicitud dto.SolicitudInvitacionCanal) (*entidad.SuscripcionCanal, error) {
+	administrador, err := actorConRol(ctx, entidad.RolAdministrador)
+	if err != nil {
+		return nil, err
+	}
+	canal, err := c.autorizarCanal(ctx, canalID)
+	if err != nil {
+		return nil, err
+	}
+	if !canal.Privado {
+		return nil, entidad.NewErrorValidacion("Solo se invita a canales privados; a los públicos se suscribe directamente")
+	}
+	if !canal.EstaActivo() {
+		return nil, entidad.ErrCanalInactivo
+	}
+	if len(c.secreto) == 0 || c.urlInvitacion == "" {
+		return nil, entidad.ErrDobleOptInNoConfigurado
+	}
+	usuario, err := servicio.AutorizarUsuario(ctx, c.repositorioUsuario, solicitud.UsuarioID)
+	if err != nil {
+		return nil, err
+	}
+	if canal.InquilinoID != 0 && canal.InquilinoID != usuario.InquilinoID {
+		return nil, entidad.ErrAccesoDenegado
+	}
+
+	suscripcion, err := c.repositorioCanal.ObtenerSuscripcion(ctx, usuario.ID, canal.ID)
+	switch {
+	case errors.Is(err, entidad.ErrSuscripcionNoEncontrada):
+		suscripcion = entidad.NuevaInvitacionCanal(usuario.ID, canal.ID, administrador.ID)
+	case err != nil:
+		return nil, err
+	case suscripcion.EstaConfirmada():
+		return suscripcion, nil
+	default:
+		if err := suscripcion.Invitar(administrador.ID); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.repositorioCanal.GuardarSuscripcion(ctx, suscripcion); err != nil {
+		return nil, err
+	}
+	if err := c.enviarInvitacion(ctx, usuario, canal); err != nil {
+		return nil, err
+	}
+	return suscripcion, nil
+}
+
+// AceptarInvitacion confirma la membresía desde el enlace de la invitación. Como en la
+// confirmación del doble opt-in, el enlace se abre sin autenticación y aceptar la invitación a
+// un canal de marketing registra el consentimiento.
+func (c *CasoUsoSuscripcionCanal) AceptarInvitacion(ctx context.Context, token string) (*entidad.SuscripcionCanal, error) {
+	if len(c.secreto) == 0 {
+		return nil, entidad.ErrDobleOptInNoConfigurado
+	}
+	invitacion, err := servicio.VerificarConfirmacion(c.claveInvitacion(), token, c.reloj.Ahora())
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = servicio.ContextoDeInquilino(ctx, c.repositorioInquilino, invitacion.InquilinoID)
+	if err != nil {
+		return nil, err
+	}
+
+	suscripcion, err := c.repositorioCanal.ObtenerSuscripcion(ctx, invitacion.UsuarioID, invitacion.CanalID)
+	if err != nil {
+		return nil, err
+	}
+	if suscripcion.EstaConfirmada() {
+		return suscripcion, nil
+	}
+	if err := suscripcion.AceptarInvitacion(c.reloj.Ahora()); err != nil {
+		return nil, err
+	}
+	canal, err := c.repositorioCanal.ObtenerPorID(ctx, invitacion.CanalID)
+	if err != nil {
+		return nil, err
+	}
+	if canal.Tipo.RequiereDobleOptIn() {
+		_, err = c.consentimientos.Otorgar(ctx, invitacion.UsuarioID, dto.SolicitudConsentimiento{
+			Proposito:    entidad.PropositoMarketing,
+			CanalID:      canal.ID,
+			Fuente:       "invitacion",
+			VersionTexto: versionTextoInvitacion,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := c.repositorioCanal.GuardarSuscripcion(ctx, suscripcion); err != nil {
+		return nil, err
+	}
+	return suscripcion, nil
+}
+
+// SolicitarIngreso pide el ingreso del usuario a un canal privado; queda a la espera de un
+// moderador. Si ya tiene una invitación o un pedido en curso se retorna sin cambios.
+func (c *CasoUsoSuscripcionCanal) SolicitarIngreso(ctx context.Context, usuarioID uint, solicitud dto.SolicitudSuscripcion) (*entidad.SuscripcionCanal, error) {
+	usuario, err := servicio.AutorizarUsuario(ctx, c.repositorioUsuario, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	canal, err := c.repositorioCanal.ObtenerPorID(ctx, solicitud.CanalID)
+	if err != nil {
+		return nil, err
+	}
+	if canal.InquilinoID != 0 && canal.InquilinoID != usuario.InquilinoID {
+		return nil, entidad.ErrAccesoDenegado
+	}
+	if !canal.Privado {
+		return nil, entidad.NewErrorValidacion("El canal es público; suscríbase directamente")
+	}
+	if !canal.EstaActivo() {
+		return nil, entidad.ErrCanalInactivo
+	}
+
+	suscripcion, err := c.repositorioCanal.ObtenerSuscripcion(ctx, usuario.ID, canal.ID)
+	switch {
+	case errors.Is(err, entidad.ErrSuscripcionNoEncontrada):
+		suscripcion = entidad.NuevaSolicitudIngreso(usuario.ID, canal.ID)
+	case err != nil:
+		return nil, err
+	case suscripcion.Estado != entidad.SuscripcionRechazada:
+		return suscripcion, nil
+	default:
+		if err := suscripcion.Solicitar(); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.repositorioCanal.GuardarSuscripcion(ctx, suscripcion); err != nil {
+		return nil, err
+	}
+	return suscripcion, nil
+}
+
+// ListarMembresias retorna una página de las membresías del canal, opcionalmente en un estado,
+// p. ej. los pedidos de ingreso que esperan un moderador
+func (c *CasoUsoSuscripcionCanal) ListarMembresias(ctx context.Context, canalID uint, estado entidad.EstadoSuscripcion, desdeID uint, limite int) ([]entidad.SuscripcionCanal, error) {
+	if _, err := c.autorizarCanal(ctx, canalID); err != nil {
+		return nil, err
+	}
+	switch estado {
+	case "", entidad.SuscripcionPendiente, entidad.SuscripcionConfirmada, entidad.SuscripcionInvitada,
+		entidad.SuscripcionSolicitada, entidad.SuscripcionRechazada:
+	default:
+		return nil, entidad.NewErrorValidacion("estado de membresía inválido")
+	}
+	return c.repositorioCanal.ListarMembresias(ctx, canalID, estado, desdeID, limite)
+}
+
+// AprobarIngreso admite el pedido en nombre de un moderador o administrador. En los canales
+// con doble opt-in queda pendiente y se envía el enlace de confirmación.
+func (c *CasoUsoSuscripcionCanal) AprobarIngreso(ctx context.Context, canalID, usuarioID uint) (*entidad.SuscripcionCanal, error) {
+	moderador, canal, suscripcion, err := c.pedidoIngreso(ctx, canalID, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	if canal.Tipo.RequiereDobleOptIn() && (len(c.secreto) == 0 || c.urlConfirmacion == "") {
+		return nil, entidad.ErrDobleOptInNoConfigurado
+	}
+	if err := suscripcion.Aprobar(moderador.ID, canal, c.reloj.Ahora()); err != nil {
+		return nil, err
+	}
+	if err := c.repositorioCanal.GuardarSuscripcion(ctx, suscripcion); err != nil {
+		return nil, err
+	}
+	if !suscripcion.EstaConfirmada() {
+		usuario, err := c.repositorioUsuario.ObtenerPorID(ctx, usuarioID)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.enviarConfirmacion(ctx, usuario, canal); err != nil {
+			return nil, err
+		}
+	}
+	return suscripcion, nil
+}
+
+// RechazarIngreso deniega el pedido en nombre de un moderador o administrador
+func (c *CasoUsoSuscripcionCanal) RechazarIngreso(ctx context.Context, canalID, usuarioID uint) (*entidad.SuscripcionCanal, error) {
+	moderador, _, suscripcion, err := c.pedidoIngreso(ctx, canalID, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	if err := suscripcion.Rechazar(moderador.ID, c.reloj.Ahora()); err != nil {
+		return nil, err
+	}
+	if err := c.repositorioCanal.GuardarSuscripcion(ctx, suscripcion); err != nil {
+		return nil, err
+	}
+	return suscripcion, nil
+}
+
+// pedidoIngreso carga el canal y la membresía que resuelve el moderador de la solicitud
+func (c *CasoUsoSuscripcionCanal) pedidoIngreso(ctx context.Context, canalID, usuarioID uint) (*entidad.Usuario, *entidad.Canal, *entidad.SuscripcionCanal, error) {
+	moderador, err := actorConRol(ctx, entidad.RolModerador, entidad.RolAdministrador)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	canal, err := c.autorizarCanal(ctx, canalID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	suscripcion, err := c.repositorioCanal.ObtenerSuscripcion(ctx, usuarioID, canal.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return moderador, canal, suscripcion, nil
+}
+
+// enviarInvitacion envía el enlace firmado para aceptar la invitación
+func (c *CasoUsoSuscripcionCanal) enviarInvitacion(ctx context.Context, usuario *entidad.Usuario, canal *entidad.Canal) error {
+	token := servicio.FirmarConfirmacion(c.claveInvitacion(), servicio.ConfirmacionSuscripcion{
+		InquilinoID: usuario.InquilinoID,
+		UsuarioID:   usuario.ID,
+		CanalID:     canal.ID,
+		Vence:       c.reloj.Ahora().Add(c.vigenciaEnlace),
+	})
+	enlace := c.urlInvitacion + "?token=" + url.QueryEscape(token)
+
+	_, _, err := c.enviar.Ejecutar(ctx, dto.SolicitudEnviarNotificacion{
+		UsuarioID: usuario.ID,
+		Titulo:    "Te invitaron a " + canal.Nombre,
+		Mensaje:   fmt.Sprintf("Te invitaron al canal privado %s. Para unirte, acepta la invitación desde este enlace: %s", canal.Nombre, enlace),
+		Tipo:      c.tipoConfirmacion,
+		Prioridad: entidad.PrioridadAlta,
+		Metadatos: map[string]interface{}{"enlace_invitacion": enlace, "canal_invitacion_id": canal.ID},
+	})
+	return err
+}
+
+// claveInvitacion deriva la clave de los enlaces de invitación del secreto de suscripciones,
+// así un enlace de confirmación no sirve para aceptar una invitación ni al revés
+func (c *CasoUsoSuscripcionCanal) claveInvitacion() []byte {
+	return append([]byte("invitacion:"), c.secreto...)
+}
+
+// autorizarCanal verifica que el canal exista y pertenezca al inquilino de la solicitud
+func (c *CasoUsoSuscripcionCanal) autorizarCanal(ctx context.Context, canalID uint) (*entidad.Canal, error) {
+	canal, err := c.repositorioCanal.ObtenerPorID(ctx, canalID)
+	if err != nil {
+		return nil, err
+	}
+	if err := servicio.AutorizarInquilino(ctx, canal.InquilinoID); err != nil {
+		return nil, err
+	}
+	return canal, nil
+}
+
+// actorConRol retorna el actor de la solicitud si tiene alguno de los roles
+func actorConRol(ctx context.Context, roles ...entidad.RolUsuario) (*entidad.Usuario, error) {
+	actor, ok := servicio.ActorDesdeContexto(ctx)
+	if !ok {
+		return nil, entidad.ErrActorRequerido
+	}
+	for _, rol := range roles {
+		if actor.Rol == rol {
+			return actor, nil
+		}
+	}
+	return nil, entidad.ErrRolInsuficiente
+}
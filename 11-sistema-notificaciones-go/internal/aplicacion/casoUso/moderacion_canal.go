@@ -0,0 +1,160 @@
+package casoUso
+
+import (
+	"context"
+	"errors"
+
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// CasoUsoModeracionCanal recibe las publicaciones de los miembros de los canales abiertos a
+// publicar y las deja en una cola de moderación. Solo las que aprueba un moderador o un
+// administrador se difunden a los suscriptores; las rechazadas conservan el motivo para su autor.
+type CasoUsoModeracionCanal struct {
+	repositorioBorrador repositorio.RepositorioBorradorDifusion
+	repositorioCanal    repositorio.RepositorioCanal
+	aprobacion          *CasoUsoAprobacionDifusion
+	reloj               reloj.Reloj
+}
+
+// NuevoCasoUsoModeracionCanal crea una nueva instancia del caso de uso
+func NuevoCasoUsoModeracionCanal(
+	repositorioBorrador repositorio.RepositorioBorradorDifusion,
+	repositorioCanal repositorio.RepositorioCanal,
+	aprobacion *CasoUsoAprobacionDifusion,
+	rel reloj.Reloj,
+) *CasoUsoModeracionCanal {
+	return &CasoUsoModeracionCanal{
+		repositorioBorrador: repositorioBorrador,
+		repositorioCanal:    repositorioCanal,
+		aprobacion:          aprobacion,
+		reloj:               rel,
+	}
+}
+
+// CambiarPublicacion abre o cierra el canal a las publicaciones de sus miembros en nombre de
+// un administrador. Cerrarlo no descarta las publicaciones que esperan moderación.
+func (c *CasoUsoModeracionCanal) CambiarPublicacion(ctx context.Context, canalID uint, publicanMiembros bool) (*entidad.Canal, error) {
+	if _, err := actorConRol(ctx, entidad.RolAdministrador); err != nil {
+		return nil, err
+	}
+	canal, err := c.repositorioCanal.ObtenerPorID(ctx, canalID)
+	if err != nil {
+		return nil, err
+	}
+	if err := servicio.AutorizarInquilino(ctx, canal.InquilinoID); err != nil {
+		return nil, err
+	}
+	if canal.PublicanMiembros == publicanMiembros {
+		return canal, nil
+	}
+	canal.PublicanMiembros = publicanMiembros
+	if err := c.repositorioCanal.Actualizar(ctx, canal); err != nil {
+		return nil, err
+	}
+	return canal, nil
+}
+
+// Publicar deja la publicación del actor pendiente de moderación. El actor debe ser miembro
+// confirmado del canal, o moderador o administrador.
+func (c *CasoUsoModeracionCanal) Publicar(ctx context.Context, canalID uint, solicitud dto.SolicitudDifusion) (*entidad.BorradorDifusion, error) {
+	actor, ok := servicio.ActorDesdeContexto(ctx)
+	if !ok {
+		return nil, entidad.ErrActorRequerido
+	}
+	canal, err := c.repositorioCanal.ObtenerPorID(ctx, canalID)
+	if err != nil {
+		return nil, err
+	}
+	if err := servicio.AutorizarInquilino(ctx, canal.InquilinoID); err != nil {
+		return nil, err
+	}
+	if !canal.PublicanMiembros {
+		return nil, entidad.ErrPublicacionNoPermitida
+	}
+	if !canal.EstaActivo() {
+		return nil, entidad.ErrCanalInactivo
+	}
+	if !actor.EsAdministrador() && !actor.EsModerador() {
+		suscripcion, err := c.repositorioCanal.ObtenerSuscripcion(ctx, actor.ID, canal.ID)
+		if errors.Is(err, entidad.ErrSuscripcionNoEncontrada) || (err == nil && !suscripcion.EstaConfirmada()) {
+			return nil, entidad.ErrPublicacionNoPermitida
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ahora := c.reloj.Ahora()
+	publicacion := &entidad.BorradorDifusion{
+		InquilinoID:    canal.InquilinoID,
+		CanalID:        canal.ID,
+		Titulo:         solicitud.Titulo,
+		Mensaje:        solicitud.Mensaje,
+		Tipo:           solicitud.Tipo,
+		Prioridad:      solicitud.Prioridad,
+		Metadatos:      solicitud.Metadatos,
+		Estado:         entidad.EstadoBorradorPendienteModeracion,
+		CreadoPor:      actor.ID,
+		SolicitadoPor:  actor.ID,
+		FechaSolicitud: &ahora,
+	}
+	if err := publicacion.Validar(); err != nil {
+		return nil, err
+	}
+	if err := c.repositorioBorrador.Crear(ctx, publicacion); err != nil {
+		return nil, err
+	}
+	return publicacion, nil
+}
+
+// Listar retorna las publicaciones del canal en un estado; por defecto la cola de moderación
+func (c *CasoUsoModeracionCanal) Listar(ctx context.Context, canalID uint, estado entidad.EstadoBorradorDifusion) ([]entidad.BorradorDifusion, error) {
+	if estado == "" {
+		estado = entidad.EstadoBorradorPendienteModeracion
+	}
+	return c.aprobacion.Listar(ctx, canalID, estado)
+}
+
+// Aprobar aprueba la publicación en nombre del moderador y la difunde. Si la difusión no puede
+// iniciarse, p. ej. por la cuota, queda aprobada para difundirla más tarde como un borrador.
+func (c *CasoUsoModeracionCanal) Aprobar(ctx context.Context, canalID, id uint) (*entidad.BorradorDifusion, *ProgresoDifusion, error) {
+	moderador, err := actorConRol(ctx, entidad.RolModerador, entidad.RolAdministrador)
+	if err != nil {
+		return nil, nil, err
+	}
+	publicacion, err := c.aprobacion.Obtener(ctx, canalID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	anterior := publicacion.Estado
+	if err := publicacion.AprobarPublicacion(moderador.ID, c.reloj.Ahora()); err != nil {
+		return nil, nil, err
+	}
+	if publicacion, err = c.aprobacion.actualizar(ctx, publicacion, anterior); err != nil {
+		return nil, nil, err
+	}
+	progreso, err := c.aprobacion.enviar(ctx, publicacion)
+	return publicacion, progreso, err
+}
+
+// Rechazar rechaza la publicación con el motivo en nombre del moderador
+func (c *CasoUsoModeracionCanal) Rechazar(ctx context.Context, canalID, id uint, motivo string) (*entidad.BorradorDifusion, error) {
+	moderador, err := actorConRol(ctx, entidad.RolModerador, entidad.RolAdministrador)
+	if err != nil {
+		return nil, err
+	}
+	publicacion, err := c.aprobacion.Obtener(ctx, canalID, id)
+	if err != nil {
+		return nil, err
+	}
+	anterior := publicacion.Estado
+	if err := publicacion.RechazarPublicacion(moderador.ID, motivo, c.reloj.Ahora()); err != nil {
+		return nil, err
+	}
+	return c.aprobacion.actualizar(ctx, publicacion, anterior)
+}
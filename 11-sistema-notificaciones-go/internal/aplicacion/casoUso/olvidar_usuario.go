@@ -0,0 +1,219 @@
+package casoUso
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// CasoUsoOlvidarUsuario elimina en segundo plano los datos personales de un usuario (derecho al olvido):
+//   - notificaciones: se anonimizan por lotes (se conservan para métricas, facturación y SLA)
+//   - intentos de envío (auditoría): se borra el detalle de error
+//   - tokens push y preferencias: se borran definitivamente
+//   - suscripciones a canales: se desvinculan
+//   - usuario: se anonimiza y se le aplica soft delete
+//
+// Cada paso es idempotente, así una eliminación fallida o interrumpida puede volver a solicitarse.
+// El avance queda en el certificado de eliminación.
+type CasoUsoOlvidarUsuario struct {
+	unidadTrabajo           repositorio.UnidadTrabajo
+	repositorioUsuario      repositorio.RepositorioUsuario
+	repositorioNotificacion repositorio.RepositorioNotificacion
+	repositorioIntento      repositorio.RepositorioIntentoEnvio
+	repositorioPreferencia  repositorio.RepositorioPreferencia
+	repositorioCanal        repositorio.RepositorioCanal
+	repositorioDispositivo  repositorio.RepositorioDispositivo
+	repositorioCertificado  repositorio.RepositorioCertificadoEliminacion
+	tamanoLote              int
+	reloj                   reloj.Reloj
+	logger                  *logger.Logger
+
+	mu      sync.Mutex
+	enCurso map[uint]bool
+}
+
+// NuevoCasoUsoOlvidarUsuario crea una nueva instancia del caso de uso
+func NuevoCasoUsoOlvidarUsuario(
+	unidadTrabajo repositorio.UnidadTrabajo,
+	repositorioUsuario repositorio.RepositorioUsuario,
+	repositorioNotificacion repositorio.RepositorioNotificacion,
+	repositorioIntento repositorio.RepositorioIntentoEnvio,
+	repositorioPreferencia repositorio.RepositorioPreferencia,
+	repositorioCanal repositorio.RepositorioCanal,
+	repositorioDispositivo repositorio.RepositorioDispositivo,
+	repositorioCertificado repositorio.RepositorioCertificadoEliminacion,
+	tamanoLote int,
+	rel reloj.Reloj,
+	log *logger.Logger,
+) *CasoUsoOlvidarUsuario {
+	return &CasoUsoOlvidarUsuario{
+		unidadTrabajo:           unidadTrabajo,
+		repositorioUsuario:      repositorioUsuario,
+		repositorioNotificacion: repositorioNotificacion,
+		repositorioIntento:      repositorioIntento,
+		repositorioPreferencia:  repositorioPreferencia,
+		repositorioCanal:        repositorioCanal,
+		repositorioDispositivo:  repositorioDispositivo,
+		repositorioCertificado:  repositorioCertificado,
+		tamanoLote:              tamanoLote,
+		reloj:                   rel,
+		logger:                  log,
+		enCurso:                 make(map[uint]bool),
+	}
+}
+
+// Solicitar crea el certificado y lanza la eliminación. Si ya hay una en curso para el usuario
+// retorna su certificado; si quedó interrumpida (p. ej. por un reinicio) la reanuda.
+func (c *CasoUsoOlvidarUsuario) Solicitar(ctx context.Context, usuarioID uint) (*entidad.CertificadoEliminacion, error) {
+	usuario, err := c.repositorioUsuario.ObtenerIncluyendoEliminados(ctx, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	if err := servicio.AutorizarInquilino(ctx, usuario.InquilinoID); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	certificado, err := c.repositorioCertificado.ObtenerUltimo(ctx, usuarioID)
+	switch {
+	case err == nil && certificado.EstaEnCurso():
+		if c.enCurso[usuarioID] {
+			return certificado, nil
+		}
+	case err == nil || errors.Is(err, entidad.ErrCertificadoNoEncontrado):
+		certificado = entidad.NuevoCertificadoEliminacion(usuario)
+		if err := c.repositorioCertificado.Crear(ctx, certificado); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	c.enCurso[usuarioID] = true
+	copia := *certificado
+	// La eliminación sobrevive a la petición HTTP que la inició
+	go c.ejecutar(context.WithoutCancel(ctx), certificado)
+	return &copia, nil
+}
+
+// ObtenerCertificado retorna el certificado más reciente del usuario con su avance
+func (c *CasoUsoOlvidarUsuario) ObtenerCertificado(ctx context.Context, usuarioID uint) (*entidad.CertificadoEliminacion, error) {
+	certificado, err := c.repositorioCertificado.ObtenerUltimo(ctx, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	if err := servicio.AutorizarInquilino(ctx, certificado.InquilinoID); err != nil {
+		return nil, err
+	}
+	return certificado, nil
+}
+
+func (c *CasoUsoOlvidarUsuario) ejecutar(ctx context.Context, certificado *entidad.CertificadoEliminacion) {
+	defer func() {
+		c.mu.Lock()
+		delete(c.enCurso, certificado.UsuarioID)
+		c.mu.Unlock()
+	}()
+
+	err := c.olvidar(ctx, certificado)
+	if err != nil {
+		certificado.Fallar(err, c.reloj.Ahora())
+	} else {
+		certificado.Completar(c.reloj.Ahora())
+	}
+	if errGuardar := c.repositorioCertificado.Actualizar(ctx, certificado); errGuardar != nil {
+		c.logger.Error("Error guardando certificado de eliminación", "certificado_id", certificado.ID, "error", errGuardar)
+	}
+
+	if err != nil {
+		c.logger.Error("Eliminación de datos personales fallida",
+			"certificado_id", certificado.ID, "usuario_id", certificado.UsuarioID, "fase", certificado.Fase, "error", err)
+		return
+	}
+	c.logger.Info("Datos personales eliminados",
+		"certificado_id", certificado.ID,
+		"usuario_id", certificado.UsuarioID,
+		"notificaciones", certificado.Notificaciones,
+	)
+}
+
+// olvidar aplica cada fase registrando el avance en el certificado
+func (c *CasoUsoOlvidarUsuario) olvidar(ctx context.Context, certificado *entidad.CertificadoEliminacion) error {
+	usuarioID := certificado.UsuarioID
+
+	if err := c.fase(ctx, certificado, "notificaciones"); err != nil {
+		return err
+	}
+	for {
+		anonimizadas, err := c.repositorioNotificacion.AnonimizarPorUsuario(ctx, usuarioID, c.tamanoLote)
+		if err != nil {
+			return err
+		}
+		certificado.Notificaciones += anonimizadas
+		if err := c.repositorioCertificado.Actualizar(ctx, certificado); err != nil {
+			return err
+		}
+		if anonimizadas < int64(c.tamanoLote) {
+			break
+		}
+	}
+
+	if err := c.fase(ctx, certificado, "auditoria"); err != nil {
+		return err
+	}
+	intentos, err := c.repositorioIntento.AnonimizarPorUsuario(ctx, usuarioID)
+	if err != nil {
+		return err
+	}
+	certificado.Intentos += intentos
+
+	// Las relaciones y el usuario se eliminan juntos: o queda todo o nada
+	if err := c.fase(ctx, certificado, "usuario"); err != nil {
+		return err
+	}
+	return c.unidadTrabajo.Ejecutar(ctx, func(ctx context.Context) error {
+		var err error
+		var dispositivos, preferencias, canales int64
+		if dispositivos, err = c.repositorioDispositivo.EliminarPorUsuario(ctx, usuarioID); err != nil {
+			return err
+		}
+		if preferencias, err = c.repositorioPreferencia.PurgarPorUsuario(ctx, usuarioID); err != nil {
+			return err
+		}
+		if canales, err = c.repositorioCanal.DesvincularUsuario(ctx, usuarioID); err != nil {
+			return err
+		}
+
+		usuario, err := c.repositorioUsuario.ObtenerIncluyendoEliminados(ctx, usuarioID)
+		if err != nil {
+			return err
+		}
+		usuario.Anonimizar()
+		if err := c.repositorioUsuario.Actualizar(ctx, usuario); err != nil {
+			return err
+		}
+		if !usuario.FechaEliminacion.Valid {
+			if err := c.repositorioUsuario.Eliminar(ctx, usuarioID); err != nil {
+				return err
+			}
+		}
+
+		certificado.Dispositivos += dispositivos
+		certificado.Preferencias += preferencias
+		certificado.Canales += canales
+		return nil
+	})
+}
+
+func (c *CasoUsoOlvidarUsuario) fase(ctx context.Context, certificado *entidad.CertificadoEliminacion, fase string) error {
+	certificado.Fase = fase
+	return c.repositorioCertificado.Actualizar(ctx, certificado)
+}
@@ -0,0 +1,119 @@
+package casoUso
+
+import (
+	"context"
+	"errors"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/logger"
+)
+
+// CasoUsoOrquestarEnvio envuelve el despacho para mantener el estado de los envíos multicanal:
+// aplica las compensaciones antes de enviar y registra el resultado de cada canal
+type CasoUsoOrquestarEnvio struct {
+	despachador             *CasoUsoDespacharNotificacion
+	repositorioEnvio        repositorio.RepositorioEnvioMultiCanal
+	repositorioNotificacion repositorio.RepositorioNotificacion
+	repositorioInquilino    repositorio.RepositorioInquilino
+	logger                  *logger.Logger
+}
+
+// NuevoCasoUsoOrquestarEnvio crea una nueva instancia del caso de uso
+func NuevoCasoUsoOrquestarEnvio(
+	despachador *CasoUsoDespacharNotificacion,
+	repositorioEnvio repositorio.RepositorioEnvioMultiCanal,
+	repositorioNotificacion repositorio.RepositorioNotificacion,
+	repositorioInquilino repositorio.RepositorioInquilino,
+	log *logger.Logger,
+) *CasoUsoOrquestarEnvio {
+	return &CasoUsoOrquestarEnvio{
+		despachador:             despachador,
+		repositorioEnvio:        repositorioEnvio,
+		repositorioNotificacion: repositorioNotificacion,
+		repositorioInquilino:    repositorioInquilino,
+		logger:                  log.Componente(logger.ComponenteTrabajadores),
+	}
+}
+
+// Procesar implementa trabajador.Procesador
+func (c *CasoUsoOrquestarEnvio) Procesar(ctx context.Context, notificacion *entidad.Notificacion) error {
+	// Los trabajadores no tienen contexto de petición: se reconstruye el del inquilino
+	ctx, err := servicio.ContextoDeInquilino(ctx, c.repositorioInquilino, notificacion.InquilinoID)
+	if err != nil {
+		return err
+	}
+
+	if notificacion.EnvioID == nil {
+		return c.despachador.Procesar(ctx, notificacion)
+	}
+
+	envio, err := c.repositorioEnvio.ObtenerPorID(ctx, *notificacion.EnvioID)
+	if err != nil {
+		return err
+	}
+	paso := envio.Paso(notificacion.Tipo)
+	if paso == nil || paso.Estado != entidad.EstadoPasoPendiente {
+		return c.despachador.Procesar(ctx, notificacion)
+	}
+
+	omitido, err := c.compensar(ctx, envio, paso, notificacion)
+	if err != nil || omitido {
+		return err
+	}
+
+	errEnvio := c.despachador.Procesar(ctx, notificacion)
+
+	// El despachador deja la notificación en su estado final o fallida con reintentos pendientes
+	switch {
+	case notificacion.Estado == entidad.EstadoEnviada:
+		envio.RegistrarResultado(paso, entidad.EstadoPasoEnviado)
+	case notificacion.Estado == entidad.EstadoFallida && !notificacion.PuedeReintentar():
+		envio.RegistrarResultado(paso, entidad.EstadoPasoFallido)
+	default:
+		return errEnvio
+	}
+
+	if err := c.repositorioEnvio.ActualizarPaso(ctx, envio, paso); err != nil {
+		return err
+	}
+	return errEnvio
+}
+
+// compensar cancela la notificación si el paso debe omitirse según el resto del envío
+func (c *CasoUsoOrquestarEnvio) compensar(ctx context.Context, envio *entidad.EnvioMultiCanal, paso *entidad.PasoEnvio, notificacion *entidad.Notificacion) (bool, error) {
+	if !paso.OmitirSiLeida {
+		return false, nil
+	}
+
+	hermanas, err := c.repositorioNotificacion.Listar(ctx, repositorio.FiltroNotificaciones{EnvioID: envio.ID})
+	if err != nil {
+		return false, err
+	}
+	if !envio.DebeOmitir(paso, hermanas) {
+		return false, nil
+	}
+
+	if err := notificacion.Cancelar(); err != nil {
+		return false, err
+	}
+	if err := c.repositorioNotificacion.Actualizar(ctx, notificacion); err != nil {
+		if errors.Is(err, entidad.ErrConflictoVersion) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	envio.RegistrarResultado(paso, entidad.EstadoPasoOmitido)
+	if err := c.repositorioEnvio.ActualizarPaso(ctx, envio, paso); err != nil {
+		return false, err
+	}
+
+	c.logger.Info("Canal omitido por compensación",
+		"envio_id", envio.ID,
+		"notificacion_id", notificacion.ID,
+		"tipo", notificacion.Tipo,
+	)
+	return true, nil
+}
@@ -0,0 +1,167 @@
+package casoUso
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// periodoResumenReacciones es el periodo del resumen por canal cuando no se indica desde
+const periodoResumenReacciones = 7 * 24 * time.Hour
+
+// ReaccionesNotificacion son las reacciones vigentes de una notificación
+type ReaccionesNotificacion struct {
+	NotificacionID uint                   `json:"notificacion_id"`
+	Reacciones     []entidad.TipoReaccion `json:"reacciones"`
+	// Eventos es el historial; solo se incluye al consultarlo
+	Eventos []entidad.EventoReaccion `json:"eventos,omitempty"`
+}
+
+// CasoUsoReacciones registra las reacciones de los destinatarios a sus notificaciones como
+// eventos, las avisa a las demás sesiones del usuario y las resume por canal
+type CasoUsoReacciones struct {
+	repositorioReaccion     repositorio.RepositorioReaccion
+	repositorioNotificacion repositorio.RepositorioNotificacion
+	repositorioCanal        repositorio.RepositorioCanal
+	publicador              repositorio.PublicadorEventos
+	reloj                   reloj.Reloj
+	logger                  *logger.Logger
+}
+
+// NuevoCasoUsoReacciones crea una nueva instancia del caso de uso
+func NuevoCasoUsoReacciones(
+	repositorioReaccion repositorio.RepositorioReaccion,
+	repositorioNotificacion repositorio.RepositorioNotificacion,
+	repositorioCanal repositorio.RepositorioCanal,
+	publicador repositorio.PublicadorEventos,
+	rel reloj.Reloj,
+	log *logger.Logger,
+) *CasoUsoReacciones {
+	return &CasoUsoReacciones{
+		repositorioReaccion:     repositorioReaccion,
+		repositorioNotificacion: repositorioNotificacion,
+		repositorioCanal:        repositorioCanal,
+		publicador:              publicador,
+		reloj:                   rel,
+		logger:                  log,
+	}
+}
+
+// Reaccionar agrega o quita la reacción del destinatario. Es idempotente: si la reacción ya
+// estaba en ese estado no registra otro evento.
+func (c *CasoUsoReacciones) Reaccionar(ctx context.Context, notificacionID uint, reaccion entidad.TipoReaccion, activa bool) (*ReaccionesNotificacion, error) {
+	if !reaccion.EsValida() {
+		return nil, entidad.NewErrorValidacion("reaccion debe ser acuse, 👍 o resuelta")
+	}
+	notificacion, err := c.notificacion(ctx, notificacionID)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(entidad.EstadosBandeja, notificacion.Estado) || notificacion.UsuarioID == 0 {
+		return nil, entidad.ErrReaccionNoPermitida
+	}
+
+	eventos, err := c.repositorioReaccion.Listar(ctx, notificacion.ID)
+	if err != nil {
+		return nil, err
+	}
+	activas := entidad.ReaccionesActivas(eventos)
+	if slices.Contains(activas, reaccion) == activa {
+		return &ReaccionesNotificacion{NotificacionID: notificacion.ID, Reacciones: activas}, nil
+	}
+
+	evento := &entidad.EventoReaccion{
+		InquilinoID:    notificacion.InquilinoID,
+		NotificacionID: notificacion.ID,
+		UsuarioID:      notificacion.UsuarioID,
+		CanalID:        notificacion.CanalID,
+		Reaccion:       reaccion,
+		Activa:         activa,
+		Fecha:          c.reloj.Ahora(),
+	}
+	if err := c.repositorioReaccion.Registrar(ctx, evento); err != nil {
+		return nil, err
+	}
+	// El evento ya quedó registrado: las demás sesiones lo verán al recargar aunque el aviso falle
+	if err := c.publicador.Publicar(ctx, notificacion.UsuarioID, "reaccion", evento); err != nil {
+		c.logger.Warn("No se pudo avisar la reacción a las sesiones del usuario",
+			"notificacion_id", notificacion.ID,
+			"error", err,
+		)
+	}
+
+	return &ReaccionesNotificacion{
+		NotificacionID: notificacion.ID,
+		Reacciones:     entidad.ReaccionesActivas(append(eventos, *evento)),
+	}, nil
+}
+
+// Listar retorna las reacciones vigentes de la notificación y su historial de eventos
+func (c *CasoUsoReacciones) Listar(ctx context.Context, notificacionID uint) (*ReaccionesNotificacion, error) {
+	notificacion, err := c.notificacion(ctx, notificacionID)
+	if err != nil {
+		return nil, err
+	}
+	eventos, err := c.repositorioReaccion.Listar(ctx, notificacion.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &ReaccionesNotificacion{
+		NotificacionID: notificacion.ID,
+		Reacciones:     entidad.ReaccionesActivas(eventos),
+		Eventos:        eventos,
+	}, nil
+}
+
+// ResumirCanal cuenta cuántos destinatarios de las notificaciones del canal creadas en
+// [desde, hasta) reaccionaron a ellas. Sin hasta se usa el momento actual y sin desde los
+// siete días anteriores a hasta.
+func (c *CasoUsoReacciones) ResumirCanal(ctx context.Context, canalID uint, desde, hasta *time.Time) (*entidad.ResumenReaccionesCanal, error) {
+	canal, err := c.repositorioCanal.ObtenerPorID(ctx, canalID)
+	if err != nil {
+		return nil, err
+	}
+	if err := servicio.AutorizarInquilino(ctx, canal.InquilinoID); err != nil {
+		return nil, err
+	}
+
+	fin := c.reloj.Ahora()
+	if hasta != nil {
+		fin = *hasta
+	}
+	inicio := fin.Add(-periodoResumenReacciones)
+	if desde != nil {
+		inicio = *desde
+	}
+	if !inicio.Before(fin) {
+		return nil, entidad.NewErrorValidacion("desde debe ser anterior a hasta")
+	}
+
+	destinatarios, reacciones, err := c.repositorioReaccion.ContarPorCanal(ctx, canal.ID, inicio, fin)
+	if err != nil {
+		return nil, err
+	}
+	return entidad.NuevoResumenReaccionesCanal(canal.ID, inicio, fin, destinatarios, reacciones), nil
+}
+
+// notificacion carga la notificación verificando el inquilino y, si la solicitud trae un
+// actor, que sea su destinatario
+func (c *CasoUsoReacciones) notificacion(ctx context.Context, id uint) (*entidad.Notificacion, error) {
+	notificacion, err := c.repositorioNotificacion.ObtenerPorID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := servicio.AutorizarInquilino(ctx, notificacion.InquilinoID); err != nil {
+		return nil, err
+	}
+	if actor, ok := servicio.ActorDesdeContexto(ctx); ok && actor.ID != notificacion.UsuarioID {
+		return nil, entidad.ErrAccesoDenegado
+	}
+	return notificacion, nil
+}
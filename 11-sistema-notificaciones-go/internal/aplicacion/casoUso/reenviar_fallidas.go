@@ -0,0 +1,247 @@
+package casoUso
+
+import (
+	"context"
+	"errors"
+
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+const (
+	// metadatoReenvioDe lleva en la copia el ID de la notificación fallida que reenvía
+	metadatoReenvioDe = "reenvio_de"
+	// metadatoReenviadaComo marca la original con el ID de su copia; evita reenviarla dos veces
+	metadatoReenviadaComo = "reenviada_como"
+	// maxFallidasReenvio acota cuántas fallidas se examinan por solicitud
+	maxFallidasReenvio = 10000
+	// maxPosterioresRecuperacion acota las notificaciones posteriores revisadas por cada fallida
+	maxPosterioresRecuperacion = 100
+)
+
+// errOriginalModificada revierte un reenvío cuando la original cambió mientras se copiaba
+var errOriginalModificada = errors.New("la notificación original cambió durante el reenvío")
+
+// ResultadoReenvio resume un reenvío de fallidas
+type ResultadoReenvio struct {
+	Simulado bool `json:"simulado"`
+	// Reenviadas son las fallidas copiadas y encoladas (o que lo serían, si se simula)
+	Reenviadas int `json:"reenviadas"`
+	// YaReenviadas son las fallidas que un reenvío anterior ya copió
+	YaReenviadas int `json:"ya_reenviadas"`
+	// Recuperadas son las fallidas con una idéntica enviada después, p. ej. porque el cliente reintentó
+	Recuperadas int `json:"recuperadas"`
+	// EnReintento son las fallidas con reintentos automáticos pendientes
+	EnReintento int `json:"en_reintento"`
+	// Invalidas son las que no pueden copiarse, p. ej. por estar anonimizadas
+	Invalidas int `json:"invalidas"`
+	// Copias asocia cada fallida reenviada con su copia
+	Copias map[uint]uint `json:"copias,omitempty"`
+}
+
+// CasoUsoReenviarFallidas vuelve a enviar las notificaciones que agotaron sus reintentos durante
+// un incidente (p. ej. la caída de un proveedor). Cada fallida se copia en una notificación nueva
+// enlazada a la original, así el historial del incidente queda intacto. Los reenvíos no consumen
+// cuota: el inquilino ya la pagó con la original.
+type CasoUsoReenviarFallidas struct {
+	unidadTrabajo           repositorio.UnidadTrabajo
+	repositorioNotificacion repositorio.RepositorioNotificacion
+	repositorioInquilino    repositorio.RepositorioInquilino
+	buzon                   *CasoUsoBuzonSalida
+	reloj                   reloj.Reloj
+	logger                  *logger.Logger
+}
+
+// NuevoCasoUsoReenviarFallidas crea una nueva instancia del caso de uso
+func NuevoCasoUsoReenviarFallidas(
+	unidadTrabajo repositorio.UnidadTrabajo,
+	repositorioNotificacion repositorio.RepositorioNotificacion,
+	repositorioInquilino repositorio.RepositorioInquilino,
+	buzon *CasoUsoBuzonSalida,
+	rel reloj.Reloj,
+	log *logger.Logger,
+) *CasoUsoReenviarFallidas {
+	return &CasoUsoReenviarFallidas{
+		unidadTrabajo:           unidadTrabajo,
+		repositorioNotificacion: repositorioNotificacion,
+		repositorioInquilino:    repositorioInquilino,
+		buzon:                   buzon,
+		reloj:                   rel,
+		logger:                  log,
+	}
+}
+
+// Reenviar copia y encola las fallidas creadas en la ventana de la solicitud. Se omiten las que
+// ya se reenviaron, las que se recuperaron por otro camino y las que todavía se reintentan solas,
+// así repetir la solicitud no duplica envíos.
+func (c *CasoUsoReenviarFallidas) Reenviar(ctx context.Context, solicitud dto.SolicitudReenvio) (*ResultadoReenvio, error) {
+	if !solicitud.Hasta.After(solicitud.Desde) {
+		return nil, entidad.NewErrorValidacion("hasta debe ser posterior a desde")
+	}
+	inquilinoID := servicio.InquilinoDesdeContexto(ctx)
+	if inquilinoID == 0 && solicitud.InquilinoID != 0 {
+		// Los datos de un inquilino aislado se leen de su esquema
+		var err error
+		if ctx, err = servicio.ContextoDeInquilino(ctx, c.repositorioInquilino, solicitud.InquilinoID); err != nil {
+			return nil, err
+		}
+		inquilinoID = solicitud.InquilinoID
+	} else if solicitud.InquilinoID != 0 && solicitud.InquilinoID != inquilinoID {
+		return nil, entidad.ErrAccesoDenegado
+	}
+
+	limite := solicitud.Limite
+	if limite == 0 {
+		limite = maxFallidasReenvio
+	}
+	var fallidas []*entidad.Notificacion
+	err := c.repositorioNotificacion.Recorrer(ctx, repositorio.FiltroNotificaciones{
+		InquilinoID: inquilinoID,
+		Estado:      entidad.EstadoFallida,
+		Tipo:        solicitud.Tipo,
+		Desde:       &solicitud.Desde,
+		Hasta:       &solicitud.Hasta,
+		Limite:      limite,
+	}, func(notificacion *entidad.Notificacion) error {
+		fallidas = append(fallidas, notificacion)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resultado := &ResultadoReenvio{Simulado: solicitud.Simular, Copias: make(map[uint]uint)}
+	for _, original := range fallidas {
+		// Sin inquilino en la solicitud (plataforma) solo se reenvían las notificaciones sin inquilino
+		if original.InquilinoID != inquilinoID {
+			continue
+		}
+		if _, reenviada := original.ObtenerMetadato(metadatoReenviadaComo); reenviada {
+			resultado.YaReenviadas++
+			continue
+		}
+		if original.PuedeReintentar() {
+			resultado.EnReintento++
+			continue
+		}
+		copia := copiarParaReenvio(original)
+		if err := copia.Validar(); err != nil {
+			resultado.Invalidas++
+			continue
+		}
+		recuperada, err := c.recuperada(ctx, original)
+		if err != nil {
+			return nil, err
+		}
+		if recuperada {
+			resultado.Recuperadas++
+			continue
+		}
+
+		if solicitud.Simular {
+			resultado.Reenviadas++
+			continue
+		}
+		err = c.reenviar(ctx, original, copia)
+		if errors.Is(err, errOriginalModificada) {
+			resultado.YaReenviadas++
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		resultado.Reenviadas++
+		resultado.Copias[original.ID] = copia.ID
+	}
+
+	c.logger.Info("Reenvío de fallidas",
+		"inquilino_id", inquilinoID,
+		"desde", solicitud.Desde,
+		"hasta", solicitud.Hasta,
+		"simulado", resultado.Simulado,
+		"reenviadas", resultado.Reenviadas,
+		"ya_reenviadas", resultado.YaReenviadas,
+		"recuperadas", resultado.Recuperadas,
+	)
+	return resultado, nil
+}
+
+// reenviar crea la copia, la anota en el buzón de salida y marca la original en la misma unidad
+// de trabajo: o quedan las tres cosas o ninguna, y la copia ya anotada la publica el relevo si la
+// cola no la acepta. La versión de la original impide que dos reenvíos simultáneos la copien a
+// la vez. La marca se escribe sobre una copia en memoria, así una reversión no la deja en la
+// original.
+func (c *CasoUsoReenviarFallidas) reenviar(ctx context.Context, original, copia *entidad.Notificacion) error {
+	marcada := *original
+	marcada.Metadatos = make(map[string]interface{}, len(original.Metadatos)+1)
+	for clave, valor := range original.Metadatos {
+		marcada.Metadatos[clave] = valor
+	}
+	err := c.unidadTrabajo.Ejecutar(ctx, func(ctx context.Context) error {
+		if err := c.repositorioNotificacion.Crear(ctx, copia); err != nil {
+			return err
+		}
+		if err := c.buzon.Anotar(ctx, copia); err != nil {
+			return err
+		}
+		marcada.EstablecerMetadato(metadatoReenviadaComo, copia.ID)
+		err := c.repositorioNotificacion.Actualizar(ctx, &marcada)
+		if errors.Is(err, entidad.ErrConflictoVersion) {
+			return errOriginalModificada
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	*original = marcada
+	c.buzon.Publicar(ctx, copia)
+	return nil
+}
+
+// recuperada indica si después de la fallida se envió una notificación idéntica al mismo usuario
+func (c *CasoUsoReenviarFallidas) recuperada(ctx context.Context, original *entidad.Notificacion) (bool, error) {
+	posteriores, err := c.repositorioNotificacion.Listar(ctx, repositorio.FiltroNotificaciones{
+		UsuarioID: original.UsuarioID,
+		Tipo:      original.Tipo,
+		Desde:     &original.FechaCreacion,
+		Limite:    maxPosterioresRecuperacion,
+	})
+	if err != nil {
+		return false, err
+	}
+	huella := original.Huella()
+	for i := range posteriores {
+		posterior := &posteriores[i]
+		if posterior.ID == original.ID || posterior.Huella() != huella {
+			continue
+		}
+		switch posterior.Estado {
+		case entidad.EstadoEnviada, entidad.EstadoEntregada, entidad.EstadoLeida:
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// copiarParaReenvio crea una notificación pendiente con el contenido de la original
+func copiarParaReenvio(original *entidad.Notificacion) *entidad.Notificacion {
+	copia := entidad.NuevaNotificacion(original.UsuarioID, original.Titulo, original.Mensaje, original.Tipo)
+	copia.InquilinoID = original.InquilinoID
+	copia.CanalID = original.CanalID
+	copia.Prioridad = original.Prioridad
+	copia.MaxIntentos = original.MaxIntentos
+	copia.AvisoLecturaWebhook = original.AvisoLecturaWebhook
+	copia.AvisoLecturaTema = original.AvisoLecturaTema
+	for clave, valor := range original.Metadatos {
+		if clave != metadatoReenviadaComo {
+			copia.EstablecerMetadato(clave, valor)
+		}
+	}
+	copia.EstablecerMetadato(metadatoReenvioDe, original.ID)
+	return copia
+}
@@ -0,0 +1,118 @@
+package casoUso
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// CasoUsoRegistrarAccesoPersonal deja constancia de cada consulta del personal a notificaciones
+// de otros usuarios. No hay forma de omitirlo desde la solicitud: si el acceso no puede
+// registrarse, la consulta falla.
+type CasoUsoRegistrarAccesoPersonal struct {
+	repositorio repositorio.RepositorioAccesoNotificacion
+	reloj       reloj.Reloj
+	// claveSello verifica los sellos HMAC de la cadena al exportar
+	claveSello []byte
+}
+
+// NuevoCasoUsoRegistrarAccesoPersonal crea una nueva instancia del caso de uso
+func NuevoCasoUsoRegistrarAccesoPersonal(repositorioAcceso repositorio.RepositorioAccesoNotificacion, rel reloj.Reloj, claveSello []byte) *CasoUsoRegistrarAccesoPersonal {
+	return &CasoUsoRegistrarAccesoPersonal{repositorio: repositorioAcceso, reloj: rel, claveSello: claveSello}
+}
+
+// RegistrarLectura registra la consulta si quien la hace es personal: un actor administrador o
+// moderador que lee notificaciones de otro usuario, o una credencial de plataforma sin actor,
+// que queda registrada como quien accedió. Sin actor, una clave de inquilino es la aplicación
+// del inquilino leyendo en nombre de su usuario. Debe llamarse antes de entregar los datos.
+func (c *CasoUsoRegistrarAccesoPersonal) RegistrarLectura(ctx context.Context, operacion entidad.OperacionAcceso, usuarioID, notificacionID uint) error {
+	actor, ok := servicio.ActorDesdeContexto(ctx)
+	if !ok {
+		clave, autenticada := servicio.ClaveAPIDesdeContexto(ctx)
+		if !autenticada {
+			return entidad.ErrActorRequerido
+		}
+		if !clave.EsAdminPlataforma() {
+			return nil
+		}
+		return c.registrar(ctx, operacion, usuarioID, notificacionID)
+	}
+	if actor.ID == usuarioID || (!actor.EsAdministrador() && !actor.EsModerador()) {
+		return nil
+	}
+	return c.registrar(ctx, operacion, usuarioID, notificacionID)
+}
+
+// RegistrarExportacion registra siempre la consulta: exportar notificaciones es una operación
+// exclusiva del personal de plataforma
+func (c *CasoUsoRegistrarAccesoPersonal) RegistrarExportacion(ctx context.Context, usuarioID uint) error {
+	return c.registrar(ctx, entidad.OperacionAccesoExportacion, usuarioID, 0)
+}
+
+// Listar consulta el registro; con una clave de inquilino solo ve los accesos de su inquilino
+func (c *CasoUsoRegistrarAccesoPersonal) Listar(ctx context.Context, filtro repositorio.FiltroAccesos) ([]entidad.AccesoNotificacion, error) {
+	if inquilinoID := servicio.InquilinoDesdeContexto(ctx); inquilinoID != 0 {
+		if filtro.InquilinoID != 0 && filtro.InquilinoID != inquilinoID {
+			return nil, entidad.ErrAccesoDenegado
+		}
+		filtro.InquilinoID = inquilinoID
+	}
+	return c.repositorio.Listar(ctx, filtro)
+}
+
+// Exportar entrega un tramo continuo de la cadena verificándola a medida que se lee. Las fechas
+// y los IDs del filtro solo ubican el primer y el último acceso: se entregan todos los que están
+// entre ambos, y el primero se verifica contra el registro que lo precede. La cadena es única
+// para toda la plataforma, por eso no se exporta por inquilino.
+func (c *CasoUsoRegistrarAccesoPersonal) Exportar(ctx context.Context, filtro repositorio.FiltroAccesos, escribir func(*entidad.AccesoNotificacion) error) (servicio.VerificacionCadena, error) {
+	if servicio.InquilinoDesdeContexto(ctx) != 0 {
+		return servicio.VerificacionCadena{}, entidad.ErrAccesoDenegado
+	}
+
+	primero, ultimo, err := c.repositorio.RangoIDs(ctx, filtro)
+	if err != nil {
+		return servicio.VerificacionCadena{}, err
+	}
+	if ultimo == 0 {
+		return servicio.NuevoVerificadorCadena(nil, c.claveSello).Resultado(), nil
+	}
+	previo, err := c.repositorio.Previo(ctx, primero)
+	if err != nil {
+		return servicio.VerificacionCadena{}, err
+	}
+
+	verificador := servicio.NuevoVerificadorCadena(previo, c.claveSello)
+	err = c.repositorio.Recorrer(ctx, primero, ultimo, func(acceso *entidad.AccesoNotificacion) error {
+		verificador.Verificar(acceso)
+		return escribir(acceso)
+	})
+	return verificador.Resultado(), err
+}
+
+func (c *CasoUsoRegistrarAccesoPersonal) registrar(ctx context.Context, operacion entidad.OperacionAcceso, usuarioID, notificacionID uint) error {
+	motivo := servicio.MotivoAccesoDesdeContexto(ctx)
+	if motivo == "" {
+		return entidad.NewErrorValidacion("X-Motivo-Acceso es requerido para consultar notificaciones de otros usuarios")
+	}
+
+	acceso := &entidad.AccesoNotificacion{
+		InquilinoID:    servicio.InquilinoDesdeContexto(ctx),
+		UsuarioID:      usuarioID,
+		NotificacionID: notificacionID,
+		Operacion:      operacion,
+		Motivo:         motivo,
+		Fecha:          c.reloj.Ahora(),
+	}
+	if actor, ok := servicio.ActorDesdeContexto(ctx); ok {
+		acceso.ActorID = actor.ID
+		acceso.ActorRol = actor.Rol
+		acceso.InquilinoID = actor.InquilinoID
+	}
+	if clave, ok := servicio.ClaveAPIDesdeContexto(ctx); ok {
+		acceso.Credencial = clave.Nombre
+	}
+	return c.repositorio.Registrar(ctx, acceso)
+}
@@ -0,0 +1,258 @@
+package casoUso
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// PlantillaReporteOperativo es la plantilla de plataforma con la que se renderiza el reporte.
+// Si no existe se crea con el contenido predeterminado; luego puede editarse como cualquier otra.
+const PlantillaReporteOperativo = "reporte_operativo"
+
+// cantidadPrincipalesErrores acota los errores listados en el reporte
+const cantidadPrincipalesErrores = 5
+
+// plantillaReportePredeterminada se crea la primera vez que se envía el reporte
+var plantillaReportePredeterminada = entidad.Plantilla{
+	Asunto: "Reporte operativo {{.Frecuencia}}: {{.Desde}} al {{.UltimoDia}}",
+	Cuerpo: `Periodo: {{.Desde}} al {{.UltimoDia}} (UTC)
+
+Volumen: {{.Total}} notificaciones
+{{range $estado, $cantidad := .PorEstado}}- {{$estado}}: {{$cantidad}}
+{{end}}
+Tasa de fallo: {{printf "%.2f" .TasaFallo}}%
+
+Errores más frecuentes:
+{{range .PrincipalesErrores}}- {{.Cantidad}} × {{.Error}}
+{{else}}- Sin intentos fallidos
+{{end}}
+SLA del mes en curso: {{.SLAIncumplidos}} de {{len .SLA}} objetivos por debajo
+{{range .SLA}}- Inquilino {{.Objetivo.InquilinoID}}, prioridad {{.Objetivo.Prioridad}}: {{printf "%.2f" .Porcentaje}}% (objetivo {{.Objetivo.PorcentajeObjetivo}}%){{if not .Cumple}} INCUMPLE{{end}}
+{{end}}`,
+}
+
+// CasoUsoReporteOperativo arma el resumen de volumen, tasa de fallo, errores más frecuentes y
+// SLA de todos los inquilinos, y lo envía a los administradores de plataforma por el propio
+// servicio, renderizado con la plantilla PlantillaReporteOperativo
+type CasoUsoReporteOperativo struct {
+	repositorioNotificacion repositorio.RepositorioNotificacion
+	repositorioIntento      repositorio.RepositorioIntentoEnvio
+	repositorioInquilino    repositorio.RepositorioInquilino
+	repositorioUsuario      repositorio.RepositorioUsuario
+	repositorioPlantilla    repositorio.RepositorioPlantilla
+	sla                     *CasoUsoEvaluarSLA
+	enviar                  *CasoUsoEnviarNotificacion
+	envios                  repositorio.VentanaDeduplicacion
+	destinatarios           []uint
+	frecuencias             []entidad.FrecuenciaReporte
+	hora                    int
+	tipo                    entidad.TipoNotificacion
+	reloj                   reloj.Reloj
+	logger                  *logger.Logger
+}
+
+// NuevoCasoUsoReporteOperativo crea una nueva instancia del caso de uso. envios registra los
+// periodos ya enviados, así una sola instancia del servicio envía cada reporte.
+func NuevoCasoUsoReporteOperativo(
+	repositorioNotificacion repositorio.RepositorioNotificacion,
+	repositorioIntento repositorio.RepositorioIntentoEnvio,
+	repositorioInquilino repositorio.RepositorioInquilino,
+	repositorioUsuario repositorio.RepositorioUsuario,
+	repositorioPlantilla repositorio.RepositorioPlantilla,
+	sla *CasoUsoEvaluarSLA,
+	enviar *CasoUsoEnviarNotificacion,
+	envios repositorio.VentanaDeduplicacion,
+	destinatarios []uint,
+	frecuencias []entidad.FrecuenciaReporte,
+	hora int,
+	tipo entidad.TipoNotificacion,
+	rel reloj.Reloj,
+	log *logger.Logger,
+) *CasoUsoReporteOperativo {
+	return &CasoUsoReporteOperativo{
+		repositorioNotificacion: repositorioNotificacion,
+		repositorioIntento:      repositorioIntento,
+		repositorioInquilino:    repositorioInquilino,
+		repositorioUsuario:      repositorioUsuario,
+		repositorioPlantilla:    repositorioPlantilla,
+		sla:                     sla,
+		enviar:                  enviar,
+		envios:                  envios,
+		destinatarios:           destinatarios,
+		frecuencias:             frecuencias,
+		hora:                    hora,
+		tipo:                    tipo,
+		reloj:                   rel,
+		logger:                  log,
+	}
+}
+
+// Generar arma el reporte del último periodo cerrado de la frecuencia
+func (c *CasoUsoReporteOperativo) Generar(ctx context.Context, frecuencia entidad.FrecuenciaReporte) (*entidad.ReporteOperativo, error) {
+	if !frecuencia.EsValida() {
+		return nil, entidad.NewErrorValidacion("frecuencia debe ser diario o semanal")
+	}
+	desde, hasta := frecuencia.PeriodoCerrado(c.reloj.Ahora())
+	return c.generar(ctx, frecuencia, desde, hasta)
+}
+
+func (c *CasoUsoReporteOperativo) generar(ctx context.Context, frecuencia entidad.FrecuenciaReporte, desde, hasta time.Time) (*entidad.ReporteOperativo, error) {
+	// Las notificaciones están repartidas entre la base principal, las regiones y los esquemas aislados
+	contextos, err := servicio.ContextosDeDatos(ctx, c.repositorioInquilino)
+	if err != nil {
+		return nil, err
+	}
+	porEstado := make(map[entidad.EstadoNotificacion]int64)
+	porError := make(map[string]int64)
+	for _, ctxDatos := range contextos {
+		conteo, err := c.repositorioNotificacion.ContarPorEstado(ctxDatos, desde, hasta)
+		if err != nil {
+			return nil, err
+		}
+		for estado, cantidad := range conteo {
+			porEstado[estado] += cantidad
+		}
+		errores, err := c.repositorioIntento.PrincipalesErrores(ctxDatos, desde, hasta, cantidadPrincipalesErrores)
+		if err != nil {
+			return nil, err
+		}
+		for _, conteoError := range errores {
+			porError[conteoError.Error] += conteoError.Cantidad
+		}
+	}
+
+	sla, err := c.sla.EvaluarPeriodoActual(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return entidad.NuevoReporteOperativo(frecuencia, desde, hasta, porEstado, principalesErrores(porError), sla), nil
+}
+
+// EnviarPendientes envía el reporte de cada frecuencia configurada cuyo periodo cerró y ya pasó
+// la hora de envío. Un periodo se envía una sola vez aunque haya varias instancias.
+func (c *CasoUsoReporteOperativo) EnviarPendientes(ctx context.Context) error {
+	if len(c.destinatarios) == 0 {
+		return nil
+	}
+	ahora := c.reloj.Ahora().UTC()
+	var errs []error
+	for _, frecuencia := range c.frecuencias {
+		desde, hasta := frecuencia.PeriodoCerrado(ahora)
+		if ahora.Before(hasta.Add(time.Duration(c.hora) * time.Hour)) {
+			continue
+		}
+		if err := c.enviarPeriodo(ctx, frecuencia, desde, hasta); err != nil {
+			errs = append(errs, fmt.Errorf("reporte %s: %w", frecuencia, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// enviarPeriodo reclama el periodo y envía el reporte; si falla antes de enviarlo lo libera
+// para reintentar en la próxima verificación
+func (c *CasoUsoReporteOperativo) enviarPeriodo(ctx context.Context, frecuencia entidad.FrecuenciaReporte, desde, hasta time.Time) error {
+	marca := fmt.Sprintf("reporte_operativo:%s:%s", frecuencia, hasta.Format("2006-01-02"))
+	// La marca dura más que el periodo más largo para no reenviar tras un reinicio
+	if _, nuevo, err := c.envios.Reclamar(ctx, marca, 8*24*time.Hour); err != nil || !nuevo {
+		return err
+	}
+
+	reporte, err := c.generar(ctx, frecuencia, desde, hasta)
+	if err == nil {
+		err = c.asegurarPlantilla(ctx)
+	}
+	if err != nil {
+		if errLiberar := c.envios.Liberar(ctx, marca, 0); errLiberar != nil {
+			c.logger.Warn("Error liberando el periodo del reporte operativo", "marca", marca, "error", errLiberar)
+		}
+		return err
+	}
+
+	datos := datosReporte(reporte)
+	var errs []error
+	for _, usuarioID := range c.destinatarios {
+		if err := c.enviarA(ctx, usuarioID, reporte, datos); err != nil {
+			errs = append(errs, fmt.Errorf("usuario %d: %w", usuarioID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// enviarA envía el reporte solo a administradores de plataforma: contiene datos de todos los inquilinos
+func (c *CasoUsoReporteOperativo) enviarA(ctx context.Context, usuarioID uint, reporte *entidad.ReporteOperativo, datos map[string]interface{}) error {
+	usuario, err := c.repositorioUsuario.ObtenerPorID(ctx, usuarioID)
+	if err != nil {
+		return err
+	}
+	if !usuario.EsAdministrador() || usuario.InquilinoID != 0 {
+		return entidad.NewErrorDominio("el destinatario no es administrador de plataforma")
+	}
+
+	_, _, err = c.enviar.Ejecutar(ctx, dto.SolicitudEnviarNotificacion{
+		UsuarioID: usuario.ID,
+		Plantilla: PlantillaReporteOperativo,
+		Datos:     datos,
+		Tipo:      c.tipo,
+		Prioridad: entidad.PrioridadNormal,
+		Metadatos: map[string]interface{}{
+			"reporte_operativo": reporte.Frecuencia,
+			"periodo_desde":     reporte.Desde,
+			"periodo_hasta":     reporte.Hasta,
+		},
+	})
+	return err
+}
+
+// asegurarPlantilla crea la plantilla de plataforma del reporte si todavía no existe
+func (c *CasoUsoReporteOperativo) asegurarPlantilla(ctx context.Context) error {
+	_, err := c.repositorioPlantilla.ObtenerPorNombre(ctx, 0, PlantillaReporteOperativo)
+	if !errors.Is(err, entidad.ErrPlantillaNoEncontrada) {
+		return err
+	}
+	predeterminada := plantillaReportePredeterminada
+	plantilla := entidad.NuevaPlantilla(0, PlantillaReporteOperativo, c.tipo, predeterminada.Asunto, predeterminada.Cuerpo)
+	return c.repositorioPlantilla.Crear(ctx, plantilla)
+}
+
+// datosReporte expone el reporte a la plantilla con las fechas ya formateadas
+func datosReporte(reporte *entidad.ReporteOperativo) map[string]interface{} {
+	return map[string]interface{}{
+		"Frecuencia":         string(reporte.Frecuencia),
+		"Desde":              reporte.Desde.Format("2006-01-02"),
+		"UltimoDia":          reporte.Hasta.AddDate(0, 0, -1).Format("2006-01-02"),
+		"Total":              reporte.Total,
+		"PorEstado":          reporte.PorEstado,
+		"TasaFallo":          reporte.TasaFallo,
+		"PrincipalesErrores": reporte.PrincipalesErrores,
+		"SLA":                reporte.SLA,
+		"SLAIncumplidos":     reporte.SLAIncumplidos,
+	}
+}
+
+// principalesErrores ordena los errores sumados de todas las ubicaciones y conserva los más frecuentes
+func principalesErrores(porError map[string]int64) []entidad.ConteoError {
+	errores := make([]entidad.ConteoError, 0, len(porError))
+	for mensaje, cantidad := range porError {
+		errores = append(errores, entidad.ConteoError{Error: mensaje, Cantidad: cantidad})
+	}
+	sort.Slice(errores, func(i, j int) bool {
+		if errores[i].Cantidad != errores[j].Cantidad {
+			return errores[i].Cantidad > errores[j].Cantidad
+		}
+		return errores[i].Error < errores[j].Error
+	})
+	if len(errores) > cantidadPrincipalesErrores {
+		errores = errores[:cantidadPrincipalesErrores]
+	}
+	return errores
+}
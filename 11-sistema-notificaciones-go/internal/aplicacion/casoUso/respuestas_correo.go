@@ -0,0 +1,155 @@
+package casoUso
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// ResultadoCorreoEntrante resume qué se hizo con un correo recibido en el buzón de respuestas
+type ResultadoCorreoEntrante struct {
+	NotificacionID uint `json:"notificacion_id"`
+	Leida          bool `json:"leida"`
+	Reenviada      bool `json:"reenviada"`
+	// Automatico indica una respuesta automática: no marca la lectura ni se reenvía
+	Automatico bool `json:"automatico,omitempty"`
+}
+
+// CasoUsoRespuestasCorreo procesa las respuestas de los destinatarios a las notificaciones de
+// email. Cada correo sale con una dirección de respuesta que lleva un token firmado con el
+// inquilino y la notificación; responderlo cuenta como lectura y, si el canal lo configuró,
+// el texto de la respuesta se reenvía a su webhook.
+type CasoUsoRespuestasCorreo struct {
+	repositorioNotificacion repositorio.RepositorioNotificacion
+	repositorioCanal        repositorio.RepositorioCanal
+	repositorioInquilino    repositorio.RepositorioInquilino
+	casoUsoEstado           *CasoUsoCambiarEstadoNotificacion
+	avisador                repositorio.AvisadorRespuestasCorreo
+	buzon                   string
+	secreto                 []byte
+	reloj                   reloj.Reloj
+	logger                  *logger.Logger
+}
+
+// NuevoCasoUsoRespuestasCorreo crea el caso de uso para el buzón de respuestas y el secreto que
+// firma sus tokens
+func NuevoCasoUsoRespuestasCorreo(
+	repositorioNotificacion repositorio.RepositorioNotificacion,
+	repositorioCanal repositorio.RepositorioCanal,
+	repositorioInquilino repositorio.RepositorioInquilino,
+	casoUsoEstado *CasoUsoCambiarEstadoNotificacion,
+	avisador repositorio.AvisadorRespuestasCorreo,
+	buzon, secreto string,
+	rel reloj.Reloj,
+	log *logger.Logger,
+) *CasoUsoRespuestasCorreo {
+	return &CasoUsoRespuestasCorreo{
+		repositorioNotificacion: repositorioNotificacion,
+		repositorioCanal:        repositorioCanal,
+		repositorioInquilino:    repositorioInquilino,
+		casoUsoEstado:           casoUsoEstado,
+		avisador:                avisador,
+		buzon:                   buzon,
+		secreto:                 []byte(secreto),
+		reloj:                   rel,
+		logger:                  log,
+	}
+}
+
+// ConfigurarWebhook fija el webhook de respuestas por correo del canal en nombre de un administrador
+func (c *CasoUsoRespuestasCorreo) ConfigurarWebhook(ctx context.Context, canalID uint, webhook string) (*entidad.Canal, error) {
+	if _, err := actorConRol(ctx, entidad.RolAdministrador); err != nil {
+		return nil, err
+	}
+	canal, err := c.repositorioCanal.ObtenerPorID(ctx, canalID)
+	if err != nil {
+		return nil, err
+	}
+	if err := servicio.AutorizarInquilino(ctx, canal.InquilinoID); err != nil {
+		return nil, err
+	}
+	if canal.WebhookRespuestasCorreo == webhook {
+		return canal, nil
+	}
+	canal.WebhookRespuestasCorreo = webhook
+	if err := c.repositorioCanal.Actualizar(ctx, canal); err != nil {
+		return nil, err
+	}
+	return canal, nil
+}
+
+// Recibir procesa un correo del buzón de respuestas. El token de la dirección es la única
+// autenticación del remitente: sin uno válido el correo se rechaza.
+func (c *CasoUsoRespuestasCorreo) Recibir(ctx context.Context, correo *entidad.CorreoEntrante) (*ResultadoCorreoEntrante, error) {
+	token, ok := servicio.TokenRespuesta(c.buzon, correo.Destinatarios)
+	if !ok {
+		return nil, entidad.ErrRespuestaCorreoInvalida
+	}
+	inquilinoID, notificacionID, err := servicio.VerificarRespuesta(c.secreto, token)
+	if err != nil {
+		return nil, err
+	}
+	if ctx, err = servicio.ContextoDeInquilino(ctx, c.repositorioInquilino, inquilinoID); err != nil {
+		return nil, err
+	}
+
+	notificacion, err := c.repositorioNotificacion.ObtenerPorID(ctx, notificacionID)
+	if err != nil {
+		return nil, err
+	}
+	if notificacion.InquilinoID != inquilinoID {
+		return nil, entidad.ErrRespuestaCorreoInvalida
+	}
+
+	resultado := &ResultadoCorreoEntrante{NotificacionID: notificacion.ID, Automatico: correo.Automatico}
+	if correo.Automatico {
+		c.logger.Info("Respuesta automática por correo ignorada", "notificacion_id", notificacion.ID)
+		return resultado, nil
+	}
+	if notificacion, err = c.casoUsoEstado.MarcarComoLeida(ctx, notificacion.ID); err != nil {
+		return nil, err
+	}
+	resultado.Leida = true
+
+	if notificacion.CanalID == 0 || correo.Texto == "" {
+		return resultado, nil
+	}
+	canal, err := c.repositorioCanal.ObtenerPorID(ctx, notificacion.CanalID)
+	if err != nil {
+		return nil, err
+	}
+	if canal.WebhookRespuestasCorreo == "" {
+		return resultado, nil
+	}
+
+	respuesta := &entidad.RespuestaCorreo{
+		NotificacionID: notificacion.ID,
+		InquilinoID:    notificacion.InquilinoID,
+		UsuarioID:      notificacion.UsuarioID,
+		CanalID:        canal.ID,
+		MessageID:      correo.MessageID,
+		Remitente:      correo.Remitente,
+		Asunto:         correo.Asunto,
+		Texto:          correo.Texto,
+		FechaRecepcion: c.reloj.Ahora(),
+		Metadatos:      notificacion.Metadatos,
+	}
+	// El webhook reintenta con espera: el proveedor de correo no tiene por qué aguardarlo
+	go c.reenviar(context.WithoutCancel(ctx), canal.WebhookRespuestasCorreo, respuesta)
+	resultado.Reenviada = true
+	return resultado, nil
+}
+
+func (c *CasoUsoRespuestasCorreo) reenviar(ctx context.Context, webhook string, respuesta *entidad.RespuestaCorreo) {
+	if err := c.avisador.AvisarRespuesta(ctx, webhook, respuesta); err != nil {
+		c.logger.Error("Error reenviando la respuesta por correo al webhook del canal",
+			"notificacion_id", respuesta.NotificacionID,
+			"canal_id", respuesta.CanalID,
+			"error", err,
+		)
+	}
+}
@@ -0,0 +1,153 @@
+package casoUso
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/logger"
+)
+
+// HiloNotificacion es una notificación con una página de sus respuestas
+type HiloNotificacion struct {
+	Notificacion *entidad.Notificacion           `json:"notificacion"`
+	Respuestas   []entidad.RespuestaNotificacion `json:"respuestas"`
+}
+
+// CasoUsoRespuestasNotificacion mantiene los hilos de respuestas de las notificaciones in-app de
+// los canales que funcionan como un feed de anuncios. En cada hilo conversan el destinatario de
+// la notificación y los moderadores del canal; todos reciben el evento "respuesta" al instante.
+type CasoUsoRespuestasNotificacion struct {
+	repositorioRespuesta    repositorio.RepositorioRespuesta
+	repositorioNotificacion repositorio.RepositorioNotificacion
+	repositorioCanal        repositorio.RepositorioCanal
+	publicador              repositorio.PublicadorEventos
+	logger                  *logger.Logger
+}
+
+// NuevoCasoUsoRespuestasNotificacion crea una nueva instancia del caso de uso
+func NuevoCasoUsoRespuestasNotificacion(
+	repositorioRespuesta repositorio.RepositorioRespuesta,
+	repositorioNotificacion repositorio.RepositorioNotificacion,
+	repositorioCanal repositorio.RepositorioCanal,
+	publicador repositorio.PublicadorEventos,
+	log *logger.Logger,
+) *CasoUsoRespuestasNotificacion {
+	return &CasoUsoRespuestasNotificacion{
+		repositorioRespuesta:    repositorioRespuesta,
+		repositorioNotificacion: repositorioNotificacion,
+		repositorioCanal:        repositorioCanal,
+		publicador:              publicador,
+		logger:                  log,
+	}
+}
+
+// CambiarRespuestas habilita o deshabilita las respuestas en el canal en nombre de un
+// administrador. Deshabilitarlas conserva los hilos existentes.
+func (c *CasoUsoRespuestasNotificacion) CambiarRespuestas(ctx context.Context, canalID uint, admite bool) (*entidad.Canal, error) {
+	if _, err := actorConRol(ctx, entidad.RolAdministrador); err != nil {
+		return nil, err
+	}
+	canal, err := c.repositorioCanal.ObtenerPorID(ctx, canalID)
+	if err != nil {
+		return nil, err
+	}
+	if err := servicio.AutorizarInquilino(ctx, canal.InquilinoID); err != nil {
+		return nil, err
+	}
+	if canal.AdmiteRespuestas == admite {
+		return canal, nil
+	}
+	canal.AdmiteRespuestas = admite
+	if err := c.repositorioCanal.Actualizar(ctx, canal); err != nil {
+		return nil, err
+	}
+	return canal, nil
+}
+
+// Responder agrega una respuesta al hilo de la notificación y la avisa a los participantes. El
+// autor es el actor de la solicitud o, sin actor, el destinatario de la notificación.
+func (c *CasoUsoRespuestasNotificacion) Responder(ctx context.Context, notificacionID uint, mensaje string) (*entidad.RespuestaNotificacion, error) {
+	notificacion, err := c.notificacion(ctx, notificacionID)
+	if err != nil {
+		return nil, err
+	}
+	if notificacion.CanalID == 0 {
+		return nil, entidad.ErrRespuestasNoPermitidas
+	}
+	canal, err := c.repositorioCanal.ObtenerPorID(ctx, notificacion.CanalID)
+	if err != nil {
+		return nil, err
+	}
+	if !notificacion.AdmiteRespuestas(canal) {
+		return nil, entidad.ErrRespuestasNoPermitidas
+	}
+
+	autorID := notificacion.UsuarioID
+	if actor, ok := servicio.ActorDesdeContexto(ctx); ok {
+		autorID = actor.ID
+	}
+	respuesta := entidad.NuevaRespuestaNotificacion(notificacion, autorID, mensaje)
+	if err := respuesta.Validar(); err != nil {
+		return nil, err
+	}
+	if err := c.repositorioRespuesta.Crear(ctx, respuesta); err != nil {
+		return nil, err
+	}
+
+	c.avisar(ctx, notificacion, respuesta)
+	return respuesta, nil
+}
+
+// Hilo retorna la notificación con las respuestas posteriores a desdeID
+func (c *CasoUsoRespuestasNotificacion) Hilo(ctx context.Context, notificacionID, desdeID uint, limite int) (*HiloNotificacion, error) {
+	notificacion, err := c.notificacion(ctx, notificacionID)
+	if err != nil {
+		return nil, err
+	}
+	respuestas, err := c.repositorioRespuesta.Listar(ctx, notificacion.ID, desdeID, limite)
+	if err != nil {
+		return nil, err
+	}
+	return &HiloNotificacion{Notificacion: notificacion, Respuestas: respuestas}, nil
+}
+
+// notificacion carga la notificación verificando el inquilino y, si la solicitud trae un actor,
+// que sea su destinatario o un moderador o administrador
+func (c *CasoUsoRespuestasNotificacion) notificacion(ctx context.Context, id uint) (*entidad.Notificacion, error) {
+	notificacion, err := c.repositorioNotificacion.ObtenerPorID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := servicio.AutorizarInquilino(ctx, notificacion.InquilinoID); err != nil {
+		return nil, err
+	}
+	if actor, ok := servicio.ActorDesdeContexto(ctx); ok && actor.ID != notificacion.UsuarioID && !actor.EsModerador() && !actor.EsAdministrador() {
+		return nil, entidad.ErrAccesoDenegado
+	}
+	return notificacion, nil
+}
+
+// avisar publica la respuesta al destinatario y a quienes ya respondieron en el hilo, incluido
+// el autor para sus otras sesiones. La respuesta ya quedó guardada: un aviso fallido solo se registra.
+func (c *CasoUsoRespuestasNotificacion) avisar(ctx context.Context, notificacion *entidad.Notificacion, respuesta *entidad.RespuestaNotificacion) {
+	autores, err := c.repositorioRespuesta.Autores(ctx, notificacion.ID)
+	if err != nil {
+		c.logger.Warn("No se pudieron obtener los participantes del hilo", "notificacion_id", notificacion.ID, "error", err)
+		autores = []uint{respuesta.AutorID}
+	}
+	participantes := map[uint]struct{}{notificacion.UsuarioID: {}}
+	for _, autor := range autores {
+		participantes[autor] = struct{}{}
+	}
+	for usuarioID := range participantes {
+		if err := c.publicador.Publicar(ctx, usuarioID, "respuesta", respuesta); err != nil {
+			c.logger.Warn("No se pudo avisar la respuesta",
+				"notificacion_id", notificacion.ID,
+				"usuario_id", usuarioID,
+				"error", err,
+			)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package casoUso
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// CasoUsoRotacionGuardia administra las rotaciones de guardia de los canales y sus reemplazos,
+// y resuelve quién está de guardia para dirigirle las notificaciones
+type CasoUsoRotacionGuardia struct {
+	repositorioGuardia repositorio.RepositorioGuardia
+	repositorioCanal   repositorio.RepositorioCanal
+	repositorioUsuario repositorio.RepositorioUsuario
+	reloj              reloj.Reloj
+}
+
+// NuevoCasoUsoRotacionGuardia crea una nueva instancia del caso de uso
+func NuevoCasoUsoRotacionGuardia(
+	repositorioGuardia repositorio.RepositorioGuardia,
+	repositorioCanal repositorio.RepositorioCanal,
+	repositorioUsuario repositorio.RepositorioUsuario,
+	rel reloj.Reloj,
+) *CasoUsoRotacionGuardia {
+	return &CasoUsoRotacionGuardia{
+		repositorioGuardia: repositorioGuardia,
+		repositorioCanal:   repositorioCanal,
+		repositorioUsuario: repositorioUsuario,
+		reloj:              rel,
+	}
+}
+
+// ObtenerRotacion retorna la rotación del canal
+func (c *CasoUsoRotacionGuardia) ObtenerRotacion(ctx context.Context, canalID uint) (*entidad.RotacionGuardia, error) {
+	if _, err := c.autorizarCanal(ctx, canalID); err != nil {
+		return nil, err
+	}
+	return c.repositorioGuardia.ObtenerRotacion(ctx, canalID)
+}
+
+// GuardarRotacion crea o reemplaza la rotación del canal. Los participantes deben ser usuarios
+// del inquilino del canal; los reemplazos existentes se conservan.
+func (c *CasoUsoRotacionGuardia) GuardarRotacion(ctx context.Context, canalID uint, rotacion *entidad.RotacionGuardia) (*entidad.RotacionGuardia, error) {
+	canal, err := c.autorizarCanal(ctx, canalID)
+	if err != nil {
+		return nil, err
+	}
+	rotacion.InquilinoID = canal.InquilinoID
+	rotacion.CanalID = canal.ID
+	if rotacion.ZonaHoraria == "" {
+		rotacion.ZonaHoraria = "UTC"
+	}
+	if err := rotacion.Validar(); err != nil {
+		return nil, err
+	}
+	for _, participante := range rotacion.Participantes {
+		if err := c.verificarUsuario(ctx, canal, participante); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.repositorioGuardia.GuardarRotacion(ctx, rotacion); err != nil {
+		return nil, err
+	}
+	return c.repositorioGuardia.ObtenerRotacion(ctx, canalID)
+}
+
+// EliminarRotacion quita la rotación del canal junto con sus reemplazos
+func (c *CasoUsoRotacionGuardia) EliminarRotacion(ctx context.Context, canalID uint) error {
+	if _, err := c.autorizarCanal(ctx, canalID); err != nil {
+		return err
+	}
+	return c.repositorioGuardia.EliminarRotacion(ctx, canalID)
+}
+
+// Guardia retorna quién está de guardia en el canal en el instante: un reemplazo vigente tiene
+// prioridad sobre el turno de la rotación. Con instante cero se resuelve para ahora.
+func (c *CasoUsoRotacionGuardia) Guardia(ctx context.Context, canalID uint, instante time.Time) (*entidad.TurnoGuardia, error) {
+	if instante.IsZero() {
+		instante = c.reloj.Ahora()
+	}
+	rotacion, err := c.ObtenerRotacion(ctx, canalID)
+	if err != nil {
+		return nil, err
+	}
+	reemplazo, err := c.repositorioGuardia.ReemplazoVigente(ctx, rotacion.ID, instante)
+	if err != nil {
+		return nil, err
+	}
+	if reemplazo != nil {
+		turno := reemplazo.Turno()
+		return &turno, nil
+	}
+	turno, err := rotacion.TurnoEn(instante)
+	if err != nil {
+		return nil, err
+	}
+	return &turno, nil
+}
+
+// CrearReemplazo asigna la guardia del canal a un usuario durante un intervalo
+func (c *CasoUsoRotacionGuardia) CrearReemplazo(ctx context.Context, canalID uint, reemplazo *entidad.ReemplazoGuardia) (*entidad.ReemplazoGuardia, error) {
+	canal, err := c.autorizarCanal(ctx, canalID)
+	if err != nil {
+		return nil, err
+	}
+	rotacion, err := c.repositorioGuardia.ObtenerRotacion(ctx, canalID)
+	if err != nil {
+		return nil, err
+	}
+	reemplazo.RotacionID = rotacion.ID
+	if err := reemplazo.Validar(); err != nil {
+		return nil, err
+	}
+	if !reemplazo.Hasta.After(c.reloj.Ahora()) {
+		return nil, entidad.NewErrorValidacion("El reemplazo ya terminó")
+	}
+	if err := c.verificarUsuario(ctx, canal, reemplazo.UsuarioID); err != nil {
+		return nil, err
+	}
+	if err := c.repositorioGuardia.CrearReemplazo(ctx, reemplazo); err != nil {
+		return nil, err
+	}
+	return reemplazo, nil
+}
+
+// ListarReemplazos retorna los reemplazos en curso y futuros del canal
+func (c *CasoUsoRotacionGuardia) ListarReemplazos(ctx context.Context, canalID uint) ([]entidad.ReemplazoGuardia, error) {
+	rotacion, err := c.ObtenerRotacion(ctx, canalID)
+	if err != nil {
+		return nil, err
+	}
+	return c.repositorioGuardia.ListarReemplazos(ctx, rotacion.ID, c.reloj.Ahora())
+}
+
+// EliminarReemplazo quita un reemplazo del canal; la guardia vuelve a la rotación
+func (c *CasoUsoRotacionGuardia) EliminarReemplazo(ctx context.Context, canalID, id uint) error {
+	rotacion, err := c.ObtenerRotacion(ctx, canalID)
+	if err != nil {
+		return err
+	}
+	return c.repositorioGuardia.EliminarReemplazo(ctx, rotacion.ID, id)
+}
+
+// verificarUsuario comprueba que el usuario exista y pertenezca al inquilino del canal
+func (c *CasoUsoRotacionGuardia) verificarUsuario(ctx context.Context, canal *entidad.Canal, usuarioID uint) error {
+	usuario, err := c.repositorioUsuario.ObtenerPorID(ctx, usuarioID)
+	if err != nil {
+		return err
+	}
+	if usuario.InquilinoID != canal.InquilinoID {
+		return entidad.NewErrorValidacion(fmt.Sprintf("El usuario %d no pertenece al inquilino del canal", usuario.ID))
+	}
+	return nil
+}
+
+// autorizarCanal carga el canal y verifica que pertenezca al inquilino de la solicitud
+func (c *CasoUsoRotacionGuardia) autorizarCanal(ctx context.Context, canalID uint) (*entidad.Canal, error) {
+	canal, err := c.repositorioCanal.ObtenerPorID(ctx, canalID)
+	if err != nil {
+		return nil, err
+	}
+	if err := servicio.AutorizarInquilino(ctx, canal.InquilinoID); err != nil {
+		return nil, err
+	}
+	return canal, nil
+}
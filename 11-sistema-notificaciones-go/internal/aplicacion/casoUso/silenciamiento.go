@@ -0,0 +1,96 @@
+package casoUso
+
+import (
+	"context"
+	"time"
+
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// CasoUsoSilenciamiento administra los canales y orígenes que cada usuario silenció y decide si
+// una notificación debe entregarse en silencio. Las notificaciones críticas nunca se silencian.
+type CasoUsoSilenciamiento struct {
+	repositorioSilenciamiento repositorio.RepositorioSilenciamiento
+	repositorioUsuario        repositorio.RepositorioUsuario
+	repositorioCanal          repositorio.RepositorioCanal
+	reloj                     reloj.Reloj
+}
+
+// NuevoCasoUsoSilenciamiento crea una nueva instancia del caso de uso
+func NuevoCasoUsoSilenciamiento(
+	repositorioSilenciamiento repositorio.RepositorioSilenciamiento,
+	repositorioUsuario repositorio.RepositorioUsuario,
+	repositorioCanal repositorio.RepositorioCanal,
+	rel reloj.Reloj,
+) *CasoUsoSilenciamiento {
+	return &CasoUsoSilenciamiento{
+		repositorioSilenciamiento: repositorioSilenciamiento,
+		repositorioUsuario:        repositorioUsuario,
+		repositorioCanal:          repositorioCanal,
+		reloj:                     rel,
+	}
+}
+
+// Listar retorna los silenciamientos en curso del usuario
+func (c *CasoUsoSilenciamiento) Listar(ctx context.Context, usuarioID uint) ([]entidad.Silenciamiento, error) {
+	if _, err := servicio.AutorizarUsuario(ctx, c.repositorioUsuario, usuarioID); err != nil {
+		return nil, err
+	}
+	return c.repositorioSilenciamiento.ListarVigentes(ctx, usuarioID, c.reloj.Ahora())
+}
+
+// Silenciar silencia el canal o el origen para el usuario. Volver a silenciar la misma fuente
+// reemplaza el vencimiento anterior.
+func (c *CasoUsoSilenciamiento) Silenciar(ctx context.Context, usuarioID uint, solicitud dto.SolicitudSilenciamiento) (*entidad.Silenciamiento, error) {
+	if _, err := servicio.AutorizarUsuario(ctx, c.repositorioUsuario, usuarioID); err != nil {
+		return nil, err
+	}
+	if solicitud.CanalID != 0 {
+		canal, err := c.repositorioCanal.ObtenerPorID(ctx, solicitud.CanalID)
+		if err != nil {
+			return nil, err
+		}
+		if err := servicio.AutorizarInquilino(ctx, canal.InquilinoID); err != nil {
+			return nil, err
+		}
+	}
+
+	ahora := c.reloj.Ahora()
+	hasta := solicitud.Hasta
+	if solicitud.Minutos > 0 {
+		vencimiento := ahora.Add(time.Duration(solicitud.Minutos) * time.Minute)
+		hasta = &vencimiento
+	}
+	silenciamiento := entidad.NuevoSilenciamiento(usuarioID, solicitud.CanalID, solicitud.Origen, hasta)
+	if err := silenciamiento.Validar(ahora); err != nil {
+		return nil, err
+	}
+	if err := c.repositorioSilenciamiento.Guardar(ctx, silenciamiento); err != nil {
+		return nil, err
+	}
+	return silenciamiento, nil
+}
+
+// Eliminar reactiva las alertas de la fuente silenciada
+func (c *CasoUsoSilenciamiento) Eliminar(ctx context.Context, usuarioID, id uint) error {
+	if _, err := servicio.AutorizarUsuario(ctx, c.repositorioUsuario, usuarioID); err != nil {
+		return err
+	}
+	return c.repositorioSilenciamiento.Eliminar(ctx, usuarioID, id)
+}
+
+// Silenciamiento retorna el silenciamiento que alcanza a la notificación, o nil si debe alertar
+// normalmente
+func (c *CasoUsoSilenciamiento) Silenciamiento(ctx context.Context, notificacion *entidad.Notificacion) (*entidad.Silenciamiento, error) {
+	if notificacion.UsuarioID == 0 || notificacion.Prioridad == entidad.PrioridadCritica {
+		return nil, nil
+	}
+	if notificacion.CanalID == 0 && notificacion.Origen == "" {
+		return nil, nil
+	}
+	return c.repositorioSilenciamiento.BuscarVigente(ctx, notificacion.UsuarioID, notificacion.CanalID, notificacion.Origen, c.reloj.Ahora())
+}
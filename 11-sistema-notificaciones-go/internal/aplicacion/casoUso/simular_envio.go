@@ -0,0 +1,303 @@
+package casoUso
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// ResultadoPaso indica cómo afecta una etapa al envío simulado
+type ResultadoPaso string
+
+const (
+	PasoAprobado    ResultadoPaso = "ok"
+	PasoBloquea     ResultadoPaso = "bloquea"
+	PasoOmitido     ResultadoPaso = "omitida"
+	PasoInformativo ResultadoPaso = "informativa"
+)
+
+// PasoSimulacion explica el resultado de una etapa del envío
+type PasoSimulacion struct {
+	Etapa     string        `json:"etapa"`
+	Resultado ResultadoPaso `json:"resultado"`
+	Detalle   string        `json:"detalle"`
+}
+
+// ResultadoSimulacion describe qué pasaría al enviar la notificación y por qué
+type ResultadoSimulacion struct {
+	Enviaria bool `json:"enviaria"`
+	// Desenlace es el estado en que terminaría la notificación o el motivo del rechazo
+	Desenlace    string                `json:"desenlace"`
+	Notificacion *entidad.Notificacion `json:"notificacion"`
+	Ruta         *RutaEnvio            `json:"ruta,omitempty"`
+	Pasos        []PasoSimulacion      `json:"pasos"`
+}
+
+// CasoUsoSimularEnvio recorre las etapas del envío sin enviar ni persistir nada: solo lee
+// cuotas, preferencias, consentimientos, supresiones, silenciamientos y la configuración de proveedores
+type CasoUsoSimularEnvio struct {
+	repositorioUsuario     repositorio.RepositorioUsuario
+	repositorioPreferencia repositorio.RepositorioPreferencia
+	marca                  *CasoUsoMarcaInquilino
+	esquemas               *CasoUsoEsquemaMetadatos
+	guardias               *CasoUsoRotacionGuardia
+	cuotas                 *CasoUsoControlarCuotas
+	consentimientos        *CasoUsoConsentimiento
+	supresiones            *CasoUsoListaSupresion
+	silenciamientos        *CasoUsoSilenciamiento
+	despachar              *CasoUsoDespacharNotificacion
+	reloj                  reloj.Reloj
+}
+
+// NuevoCasoUsoSimularEnvio crea una nueva instancia del caso de uso
+func NuevoCasoUsoSimularEnvio(
+	repositorioUsuario repositorio.RepositorioUsuario,
+	repositorioPreferencia repositorio.RepositorioPreferencia,
+	marca *CasoUsoMarcaInquilino,
+	esquemas *CasoUsoEsquemaMetadatos,
+	guardias *CasoUsoRotacionGuardia,
+	cuotas *CasoUsoControlarCuotas,
+	consentimientos *CasoUsoConsentimiento,
+	supresiones *CasoUsoListaSupresion,
+	silenciamientos *CasoUsoSilenciamiento,
+	despachar *CasoUsoDespacharNotificacion,
+	rel reloj.Reloj,
+) *CasoUsoSimularEnvio {
+	return &CasoUsoSimularEnvio{
+		repositorioUsuario:     repositorioUsuario,
+		repositorioPreferencia: repositorioPreferencia,
+		marca:                  marca,
+		esquemas:               esquemas,
+		guardias:               guardias,
+		cuotas:                 cuotas,
+		consentimientos:        consentimientos,
+		supresiones:            supresiones,
+		silenciamientos:        silenciamientos,
+		despachar:              despachar,
+		reloj:                  rel,
+	}
+}
+
+// Simular arma la notificación como lo haría el envío real y evalúa cada etapa en orden. Se
+// detiene en la primera que la rechazaría; los errores de validación son parte del resultado.
+func (c *CasoUsoSimularEnvio) Simular(ctx context.Context, solicitud dto.SolicitudEnviarNotificacion) (*ResultadoSimulacion, error) {
+	ahora := c.reloj.Ahora()
+	var turno *entidad.TurnoGuardia
+	if solicitud.GuardiaCanalID != 0 {
+		var err error
+		turno, err = c.guardias.Guardia(ctx, solicitud.GuardiaCanalID, instanteEnvio(solicitud, ahora))
+		if err != nil && !errors.Is(err, entidad.ErrSinRotacionGuardia) {
+			return nil, err
+		}
+		if turno != nil {
+			solicitud.UsuarioID = turno.UsuarioID
+		}
+	}
+	notificacion := entidad.NuevaNotificacion(solicitud.UsuarioID, solicitud.Titulo, solicitud.Mensaje, solicitud.Tipo)
+	notificacion.InquilinoID = servicio.InquilinoDesdeContexto(ctx)
+	notificacion.CanalID = solicitud.CanalID
+	notificacion.Origen = solicitud.Origen
+	notificacion.FechaProgramada = solicitud.FechaProgramada
+	if solicitud.AvisoLectura != nil {
+		notificacion.AvisoLecturaWebhook = solicitud.AvisoLectura.Webhook
+		notificacion.AvisoLecturaTema = solicitud.AvisoLectura.Tema
+	}
+	if solicitud.Prioridad != "" {
+		notificacion.Prioridad = solicitud.Prioridad
+	}
+	for clave, valor := range solicitud.Metadatos {
+		notificacion.EstablecerMetadato(clave, valor)
+	}
+	if solicitud.GuardiaCanalID != 0 {
+		notificacion.EstablecerMetadato("guardia_canal_id", solicitud.GuardiaCanalID)
+	}
+
+	resultado := &ResultadoSimulacion{Notificacion: notificacion}
+	paso := func(etapa string, res ResultadoPaso, detalle string, args ...any) {
+		resultado.Pasos = append(resultado.Pasos, PasoSimulacion{Etapa: etapa, Resultado: res, Detalle: fmt.Sprintf(detalle, args...)})
+	}
+	rechazar := func(etapa, desenlace string, err error) (*ResultadoSimulacion, error) {
+		paso(etapa, PasoBloquea, "%v", err)
+		resultado.Desenlace = desenlace
+		return resultado, nil
+	}
+
+	switch {
+	case solicitud.GuardiaCanalID == 0:
+		paso("guardia", PasoOmitido, "destinatario indicado en la solicitud")
+	case turno == nil:
+		return rechazar("guardia", "rechazada", entidad.ErrSinRotacionGuardia)
+	case turno.ReemplazoID != 0:
+		paso("guardia", PasoAprobado, "de guardia el usuario %d por el reemplazo %d hasta %s", turno.UsuarioID, turno.ReemplazoID, turno.Hasta.Format(time.RFC3339))
+	default:
+		paso("guardia", PasoAprobado, "de guardia el usuario %d según la rotación hasta %s", turno.UsuarioID, turno.Hasta.Format(time.RFC3339))
+	}
+
+	// Las preferencias, horarios y supresiones del destinatario solo se leen en su inquilino
+	if solicitud.UsuarioID != 0 {
+		usuario, err := c.repositorioUsuario.ObtenerPorID(ctx, solicitud.UsuarioID)
+		if err != nil {
+			return nil, err
+		}
+		if err := servicio.AutorizarInquilino(ctx, usuario.InquilinoID); err != nil {
+			// No se revela que el usuario existe en otro inquilino
+			return nil, entidad.ErrUsuarioNoEncontrado
+		}
+	}
+
+	if err := c.esquemas.Validar(ctx, solicitud.CanalID, solicitud.Metadatos); err != nil {
+		var errorMetadatos *entidad.ErrorMetadatos
+		if errors.As(err, &errorMetadatos) {
+			return rechazar("metadatos", "rechazada", err)
+		}
+		return nil, err
+	}
+	if solicitud.CanalID == 0 {
+		paso("metadatos", PasoOmitido, "sin canal, los metadatos son libres")
+	} else {
+		paso("metadatos", PasoAprobado, "los metadatos cumplen el esquema del canal, si declara uno")
+	}
+
+	if solicitud.Plantilla == "" {
+		paso("plantilla", PasoOmitido, "sin plantilla, se usan el título y el mensaje de la solicitud")
+	} else {
+		contenido, err := c.marca.RenderizarPlantilla(ctx, notificacion.InquilinoID, solicitud.Plantilla, solicitud.Tipo, solicitud.Datos)
+		if err != nil {
+			var errorValidacion *entidad.ErrorValidacion
+			if errors.As(err, &errorValidacion) {
+				return rechazar("plantilla", "rechazada", err)
+			}
+			return nil, err
+		}
+		notificacion.Titulo, notificacion.Mensaje = contenido.Asunto, contenido.Cuerpo
+		for clave, valor := range contenido.Metadatos {
+			notificacion.EstablecerMetadato(clave, valor)
+		}
+		paso("plantilla", PasoAprobado, "plantilla %q renderizada", solicitud.Plantilla)
+	}
+
+	if err := notificacion.Validar(); err != nil {
+		return rechazar("validacion", "rechazada", err)
+	}
+	paso("validacion", PasoAprobado, "la notificación es válida")
+
+	uso, err := c.cuotas.Disponible(ctx, notificacion.InquilinoID, notificacion.Tipo)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case notificacion.InquilinoID == 0:
+		paso("cuota", PasoOmitido, "la plataforma no tiene cuotas")
+	case uso.LimiteMensual > 0 && uso.Enviadas >= uso.LimiteMensual:
+		return rechazar("cuota", "rechazada", fmt.Errorf("%w: %d de %d en el mes", entidad.ErrCuotaMensualExcedida, uso.Enviadas, uso.LimiteMensual))
+	case uso.LimiteMensual > 0:
+		paso("cuota", PasoAprobado, "%d de %d envíos de %s usados en el mes", uso.Enviadas, uso.LimiteMensual, notificacion.Tipo)
+	default:
+		paso("cuota", PasoAprobado, "sin límite mensual para %s (%d enviadas en el mes)", notificacion.Tipo, uso.Enviadas)
+	}
+	if uso.LimitePorSegundo > 0 {
+		paso("tasa", PasoInformativo, "límite de %d envíos por segundo; depende del tráfico al momento del envío", uso.LimitePorSegundo)
+	}
+
+	if notificacion.EstaProgramada(ahora) {
+		paso("programacion", PasoInformativo, "programada para %s; se despacharía entonces", notificacion.FechaProgramada.Format("2006-01-02T15:04:05Z07:00"))
+	} else {
+		paso("programacion", PasoAprobado, "se encolaría de inmediato")
+	}
+
+	if err := c.explicarPreferencias(ctx, notificacion, paso); err != nil {
+		return nil, err
+	}
+
+	permitida, err := c.consentimientos.Permite(ctx, notificacion)
+	if err != nil {
+		return nil, err
+	}
+	if !permitida {
+		return rechazar("consentimiento", "cancelada: sin_consentimiento", errors.New("el usuario no dio su consentimiento para este propósito"))
+	}
+	paso("consentimiento", PasoAprobado, "el envío no requiere consentimiento o el usuario lo otorgó")
+
+	suprimida, err := c.supresiones.Bloqueo(ctx, notificacion)
+	if err != nil {
+		return nil, err
+	}
+	if suprimida != nil {
+		return rechazar("supresion", "cancelada: supresion_"+string(suprimida.Motivo),
+			fmt.Errorf("el destino está en la lista de supresión (entrada %d, motivo %s)", suprimida.ID, suprimida.Motivo))
+	}
+	paso("supresion", PasoAprobado, "ningún contacto del usuario está suprimido")
+
+	silenciamiento, err := c.silenciamientos.Silenciamiento(ctx, notificacion)
+	if err != nil {
+		return nil, err
+	}
+	if silenciamiento != nil {
+		paso("silenciamiento", PasoInformativo, "el usuario silenció la fuente (silenciamiento %d); llegaría solo a su bandeja in-app, sin alertas", silenciamiento.ID)
+		resultado.Enviaria = true
+		resultado.Desenlace = string(entidad.EstadoEnviada) + ": silenciada"
+		return resultado, nil
+	}
+	paso("silenciamiento", PasoAprobado, "el usuario no silenció el canal ni el origen")
+
+	ruta, err := c.despachar.Enrutar(ctx, notificacion)
+	if err != nil {
+		return rechazar("enrutamiento", string(entidad.EstadoFallida), err)
+	}
+	resultado.Ruta = ruta
+	paso("enrutamiento", PasoAprobado, "se enviaría con %s", ruta.Proveedor)
+
+	resultado.Enviaria = true
+	resultado.Desenlace = string(entidad.EstadoEnviada)
+	return resultado, nil
+}
+
+// explicarPreferencias informa la preferencia del usuario para el tipo. El despacho no filtra
+// por preferencias ni difiere por horario de silencio, así que estas etapas no bloquean.
+func (c *CasoUsoSimularEnvio) explicarPreferencias(ctx context.Context, notificacion *entidad.Notificacion, paso func(string, ResultadoPaso, string, ...any)) error {
+	preferencias, err := c.repositorioPreferencia.ListarPorUsuario(ctx, notificacion.UsuarioID)
+	if err != nil {
+		return err
+	}
+	var preferencia *entidad.PreferenciaNotificacion
+	for i := range preferencias {
+		if preferencias[i].Tipo == notificacion.Tipo {
+			preferencia = &preferencias[i]
+			break
+		}
+	}
+	if preferencia == nil {
+		paso("preferencias", PasoInformativo, "el usuario no tiene preferencia para %s", notificacion.Tipo)
+		paso("horario_silencio", PasoOmitido, "sin horario de silencio")
+		return nil
+	}
+
+	if preferencia.Habilitada {
+		paso("preferencias", PasoInformativo, "%s habilitado por el usuario", notificacion.Tipo)
+	} else {
+		paso("preferencias", PasoInformativo, "%s deshabilitado por el usuario, pero el despacho no aplica preferencias", notificacion.Tipo)
+	}
+
+	if !preferencia.TieneSilencio() {
+		paso("horario_silencio", PasoOmitido, "sin horario de silencio")
+		return nil
+	}
+	instante := c.reloj.Ahora()
+	if notificacion.EstaProgramada(instante) {
+		instante = *notificacion.FechaProgramada
+	}
+	dentro := "fuera del"
+	if preferencia.EnSilencio(instante) {
+		dentro = "dentro del"
+	}
+	paso("horario_silencio", PasoInformativo, "%s horario de silencio %s-%s (%s); el despacho no lo difiere",
+		dentro, preferencia.SilencioDesde, preferencia.SilencioHasta, preferencia.ZonaHoraria)
+	return nil
+}
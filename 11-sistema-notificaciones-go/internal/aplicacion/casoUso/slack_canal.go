@@ -0,0 +1,46 @@
+package casoUso
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+)
+
+// CasoUsoSlackCanal configura el destino de Slack de cada canal
+type CasoUsoSlackCanal struct {
+	repositorioCanal repositorio.RepositorioCanal
+}
+
+// NuevoCasoUsoSlackCanal crea una nueva instancia del caso de uso
+func NuevoCasoUsoSlackCanal(repositorioCanal repositorio.RepositorioCanal) *CasoUsoSlackCanal {
+	return &CasoUsoSlackCanal{repositorioCanal: repositorioCanal}
+}
+
+// Configurar fija o quita el destino de Slack del canal en nombre de un administrador
+func (c *CasoUsoSlackCanal) Configurar(ctx context.Context, canalID uint, solicitud dto.SolicitudSlackCanal) (*entidad.Canal, error) {
+	if _, err := actorConRol(ctx, entidad.RolAdministrador); err != nil {
+		return nil, err
+	}
+	canal, err := c.repositorioCanal.ObtenerPorID(ctx, canalID)
+	if err != nil {
+		return nil, err
+	}
+	if err := servicio.AutorizarInquilino(ctx, canal.InquilinoID); err != nil {
+		return nil, err
+	}
+
+	var destino *entidad.DestinoSlack
+	if solicitud.WebhookURL != "" || solicitud.CanalSlack != "" {
+		destino = &entidad.DestinoSlack{WebhookURL: solicitud.WebhookURL, CanalSlack: solicitud.CanalSlack}
+	}
+	if err := canal.ConfigurarSlack(destino); err != nil {
+		return nil, err
+	}
+	if err := c.repositorioCanal.Actualizar(ctx, canal); err != nil {
+		return nil, err
+	}
+	return canal, nil
+}
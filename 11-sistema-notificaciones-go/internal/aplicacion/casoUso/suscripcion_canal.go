@@ -0,0 +1,194 @@
+package casoUso
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// versionTextoConfirmacion identifica el texto del mensaje de confirmación; confirmar desde el
+// enlace registra el consentimiento de marketing con esta versión como evidencia
+const versionTextoConfirmacion = "confirmacion_suscripcion_v1"
+
+// CasoUsoSuscripcionCanal gestiona las suscripciones de los usuarios a canales. Las de canales
+// de marketing usan doble opt-in: quedan pendientes hasta que el usuario abre el enlace firmado
+// que se le envía, y solo entonces reciben difusiones. A los canales privados se ingresa por
+// invitación de un administrador o con un pedido aprobado por un moderador.
+type CasoUsoSuscripcionCanal struct {
+	repositorioCanal     repositorio.RepositorioCanal
+	repositorioUsuario   repositorio.RepositorioUsuario
+	repositorioInquilino repositorio.RepositorioInquilino
+	enviar               *CasoUsoEnviarNotificacion
+	consentimientos      *CasoUsoConsentimiento
+	secreto              []byte
+	urlConfirmacion      string
+	urlInvitacion        string
+	vigenciaEnlace       time.Duration
+	tipoConfirmacion     entidad.TipoNotificacion
+	reloj                reloj.Reloj
+}
+
+// NuevoCasoUsoSuscripcionCanal crea una nueva instancia del caso de uso.
+// Sin secreto o sin URL de confirmación no se aceptan suscripciones a canales de marketing,
+// y sin secreto o sin URL de invitación no se invita a canales privados.
+func NuevoCasoUsoSuscripcionCanal(
+	repositorioCanal repositorio.RepositorioCanal,
+	repositorioUsuario repositorio.RepositorioUsuario,
+	repositorioInquilino repositorio.RepositorioInquilino,
+	enviar *CasoUsoEnviarNotificacion,
+	consentimientos *CasoUsoConsentimiento,
+	secreto, urlConfirmacion, urlInvitacion string,
+	vigenciaEnlace time.Duration,
+	tipoConfirmacion entidad.TipoNotificacion,
+	rel reloj.Reloj,
+) *CasoUsoSuscripcionCanal {
+	return &CasoUsoSuscripcionCanal{
+		repositorioCanal:     repositorioCanal,
+		repositorioUsuario:   repositorioUsuario,
+		repositorioInquilino: repositorioInquilino,
+		enviar:               enviar,
+		consentimientos:      consentimientos,
+		secreto:              []byte(secreto),
+		urlConfirmacion:      urlConfirmacion,
+		urlInvitacion:        urlInvitacion,
+		vigenciaEnlace:       vigenciaEnlace,
+		tipoConfirmacion:     tipoConfirmacion,
+		reloj:                rel,
+	}
+}
+
+// Listar retorna las suscripciones del usuario con su estado
+func (c *CasoUsoSuscripcionCanal) Listar(ctx context.Context, usuarioID uint) ([]entidad.SuscripcionCanal, error) {
+	if _, err := servicio.AutorizarUsuario(ctx, c.repositorioUsuario, usuarioID); err != nil {
+		return nil, err
+	}
+	return c.repositorioCanal.ListarSuscripciones(ctx, usuarioID)
+}
+
+// Suscribir suscribe al usuario al canal. Si el canal exige doble opt-in la suscripción queda
+// pendiente y se envía el enlace de confirmación; volver a suscribirse reenvía el enlace.
+// En un canal privado solo se reenvía el enlace de una membresía ya aprobada.
+func (c *CasoUsoSuscripcionCanal) Suscribir(ctx context.Context, usuarioID uint, solicitud dto.SolicitudSuscripcion) (*entidad.SuscripcionCanal, error) {
+	usuario, err := servicio.AutorizarUsuario(ctx, c.repositorioUsuario, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	canal, err := c.repositorioCanal.ObtenerPorID(ctx, solicitud.CanalID)
+	if err != nil {
+		return nil, err
+	}
+	if canal.InquilinoID != 0 && canal.InquilinoID != usuario.InquilinoID {
+		return nil, entidad.ErrAccesoDenegado
+	}
+	if !canal.EstaActivo() {
+		return nil, entidad.ErrCanalInactivo
+	}
+	if canal.Tipo.RequiereDobleOptIn() && (len(c.secreto) == 0 || c.urlConfirmacion == "") {
+		return nil, entidad.ErrDobleOptInNoConfigurado
+	}
+
+	suscripcion, err := c.repositorioCanal.ObtenerSuscripcion(ctx, usuarioID, canal.ID)
+	if err != nil && !errors.Is(err, entidad.ErrSuscripcionNoEncontrada) {
+		return nil, err
+	}
+	if suscripcion != nil && suscripcion.EstaConfirmada() {
+		return suscripcion, nil
+	}
+	// Invitaciones, pedidos y rechazos se reemplazan solo si el canal dejó de ser privado
+	if suscripcion == nil || suscripcion.Estado != entidad.SuscripcionPendiente {
+		if canal.Privado {
+			return nil, entidad.ErrCanalPrivado
+		}
+		suscripcion = entidad.NuevaSuscripcionCanal(usuarioID, canal, c.reloj.Ahora())
+		if err := c.repositorioCanal.GuardarSuscripcion(ctx, suscripcion); err != nil {
+			return nil, err
+		}
+	}
+
+	if !suscripcion.EstaConfirmada() {
+		if err := c.enviarConfirmacion(ctx, usuario, canal); err != nil {
+			return nil, err
+		}
+	}
+	return suscripcion, nil
+}
+
+// Desuscribir quita al usuario del canal, esté pendiente o confirmada la suscripción
+func (c *CasoUsoSuscripcionCanal) Desuscribir(ctx context.Context, usuarioID, canalID uint) error {
+	if _, err := servicio.AutorizarUsuario(ctx, c.repositorioUsuario, usuarioID); err != nil {
+		return err
+	}
+	return c.repositorioCanal.EliminarSuscripcion(ctx, usuarioID, canalID)
+}
+
+// Confirmar activa la suscripción del enlace y registra el consentimiento de marketing del
+// usuario para el canal. El enlace se abre sin autenticación: el inquilino sale del token firmado.
+func (c *CasoUsoSuscripcionCanal) Confirmar(ctx context.Context, token string) (*entidad.SuscripcionCanal, error) {
+	if len(c.secreto) == 0 {
+		return nil, entidad.ErrDobleOptInNoConfigurado
+	}
+	confirmacion, err := servicio.VerificarConfirmacion(c.secreto, token, c.reloj.Ahora())
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = servicio.ContextoDeInquilino(ctx, c.repositorioInquilino, confirmacion.InquilinoID)
+	if err != nil {
+		return nil, err
+	}
+
+	suscripcion, err := c.repositorioCanal.ObtenerSuscripcion(ctx, confirmacion.UsuarioID, confirmacion.CanalID)
+	if err != nil {
+		return nil, err
+	}
+	if suscripcion.EstaConfirmada() {
+		return suscripcion, nil
+	}
+	if suscripcion.Estado != entidad.SuscripcionPendiente {
+		return nil, entidad.ErrEstadoMembresiaInvalido
+	}
+
+	_, err = c.consentimientos.Otorgar(ctx, confirmacion.UsuarioID, dto.SolicitudConsentimiento{
+		Proposito:    entidad.PropositoMarketing,
+		CanalID:      confirmacion.CanalID,
+		Fuente:       "doble_opt_in",
+		VersionTexto: versionTextoConfirmacion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	suscripcion.Confirmar(c.reloj.Ahora())
+	if err := c.repositorioCanal.GuardarSuscripcion(ctx, suscripcion); err != nil {
+		return nil, err
+	}
+	return suscripcion, nil
+}
+
+// enviarConfirmacion envía el enlace firmado. La notificación no lleva el canal de marketing:
+// es transaccional y debe llegar aunque el usuario todavía no haya dado su consentimiento.
+func (c *CasoUsoSuscripcionCanal) enviarConfirmacion(ctx context.Context, usuario *entidad.Usuario, canal *entidad.Canal) error {
+	token := servicio.FirmarConfirmacion(c.secreto, servicio.ConfirmacionSuscripcion{
+		InquilinoID: usuario.InquilinoID,
+		UsuarioID:   usuario.ID,
+		CanalID:     canal.ID,
+		Vence:       c.reloj.Ahora().Add(c.vigenciaEnlace),
+	})
+	enlace := c.urlConfirmacion + "?token=" + url.QueryEscape(token)
+
+	_, _, err := c.enviar.Ejecutar(ctx, dto.SolicitudEnviarNotificacion{
+		UsuarioID: usuario.ID,
+		Titulo:    "Confirma tu suscripción a " + canal.Nombre,
+		Mensaje:   fmt.Sprintf("Para empezar a recibir %s, confirma tu suscripción desde este enlace: %s", canal.Nombre, enlace),
+		Tipo:      c.tipoConfirmacion,
+		Prioridad: entidad.PrioridadAlta,
+		Metadatos: map[string]interface{}{"enlace_confirmacion": enlace, "canal_suscripcion_id": canal.ID},
+	})
+	return err
+}
@@ -0,0 +1,86 @@
+package casoUso
+
+import (
+	"context"
+	"encoding/base64"
+	"net/url"
+	"strings"
+
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+)
+
+// CasoUsoWebPush registra las suscripciones Web Push de los navegadores de cada usuario
+type CasoUsoWebPush struct {
+	repositorioDispositivo repositorio.RepositorioDispositivo
+	repositorioUsuario     repositorio.RepositorioUsuario
+	clavePublica           string
+}
+
+// NuevoCasoUsoWebPush crea una nueva instancia del caso de uso. clavePublica es la clave
+// VAPID de la plataforma; vacía deshabilita el registro de suscripciones.
+func NuevoCasoUsoWebPush(repositorioDispositivo repositorio.RepositorioDispositivo, repositorioUsuario repositorio.RepositorioUsuario, clavePublica string) *CasoUsoWebPush {
+	return &CasoUsoWebPush{
+		repositorioDispositivo: repositorioDispositivo,
+		repositorioUsuario:     repositorioUsuario,
+		clavePublica:           clavePublica,
+	}
+}
+
+// ClavePublica retorna la clave VAPID que el navegador pasa como applicationServerKey al suscribirse
+func (c *CasoUsoWebPush) ClavePublica() (string, error) {
+	if c.clavePublica == "" {
+		return "", entidad.ErrWebPushNoConfigurado
+	}
+	return c.clavePublica, nil
+}
+
+// Registrar guarda la suscripción del navegador para el usuario. Volver a registrar el mismo
+// endpoint actualiza sus claves.
+func (c *CasoUsoWebPush) Registrar(ctx context.Context, usuarioID uint, solicitud dto.SolicitudSuscripcionWebPush) (*entidad.DispositivoPush, error) {
+	if c.clavePublica == "" {
+		return nil, entidad.ErrWebPushNoConfigurado
+	}
+	if _, err := servicio.AutorizarUsuario(ctx, c.repositorioUsuario, usuarioID); err != nil {
+		return nil, err
+	}
+	endpoint, err := url.Parse(solicitud.Endpoint)
+	if err != nil || endpoint.Scheme != "https" || endpoint.Host == "" {
+		return nil, entidad.NewErrorValidacion("El endpoint debe ser una URL https")
+	}
+	p256dh, err := decodificarClaveWebPush(solicitud.Keys.P256dh)
+	if err != nil || len(p256dh) != 65 || p256dh[0] != 0x04 {
+		return nil, entidad.NewErrorValidacion("p256dh debe ser un punto P-256 sin comprimir en base64url")
+	}
+	auth, err := decodificarClaveWebPush(solicitud.Keys.Auth)
+	if err != nil || len(auth) != 16 {
+		return nil, entidad.NewErrorValidacion("auth debe ser un secreto de 16 bytes en base64url")
+	}
+
+	dispositivo := &entidad.DispositivoPush{
+		UsuarioID:   usuarioID,
+		Token:       solicitud.Endpoint,
+		Plataforma:  entidad.PlataformaWeb,
+		ClaveP256dh: base64.RawURLEncoding.EncodeToString(p256dh),
+		ClaveAuth:   base64.RawURLEncoding.EncodeToString(auth),
+	}
+	if err := c.repositorioDispositivo.Guardar(ctx, dispositivo); err != nil {
+		return nil, err
+	}
+	return dispositivo, nil
+}
+
+// Eliminar quita la suscripción del navegador del usuario
+func (c *CasoUsoWebPush) Eliminar(ctx context.Context, usuarioID uint, endpoint string) error {
+	if _, err := servicio.AutorizarUsuario(ctx, c.repositorioUsuario, usuarioID); err != nil {
+		return err
+	}
+	return c.repositorioDispositivo.EliminarPorToken(ctx, usuarioID, endpoint)
+}
+
+// decodificarClaveWebPush acepta base64url con o sin relleno; los navegadores lo omiten
+func decodificarClaveWebPush(clave string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(clave, "="))
+}
@@ -0,0 +1,42 @@
+package casoUso
+
+import "sync"
+
+// TareasSegundoPlano ejecuta el trabajo que no debe demorar la respuesta (p. ej. los avisos de
+// lectura) y permite esperarlo al apagar el proceso, así no se pierde a mitad de camino
+type TareasSegundoPlano struct {
+	mu      sync.Mutex
+	cerrado bool
+	grupo   sync.WaitGroup
+}
+
+// NuevasTareasSegundoPlano crea el grupo de tareas
+func NuevasTareasSegundoPlano() *TareasSegundoPlano {
+	return &TareasSegundoPlano{}
+}
+
+// Lanzar ejecuta la tarea en una goroutine. Después de Esperar la ejecuta en el acto, así una
+// petición que termina durante el apagado no la pierde.
+func (t *TareasSegundoPlano) Lanzar(tarea func()) {
+	t.mu.Lock()
+	if t.cerrado {
+		t.mu.Unlock()
+		tarea()
+		return
+	}
+	t.grupo.Add(1)
+	t.mu.Unlock()
+
+	go func() {
+		defer t.grupo.Done()
+		tarea()
+	}()
+}
+
+// Esperar deja de aceptar tareas en segundo plano y bloquea hasta que terminen las lanzadas
+func (t *TareasSegundoPlano) Esperar() {
+	t.mu.Lock()
+	t.cerrado = true
+	t.mu.Unlock()
+	t.grupo.Wait()
+}
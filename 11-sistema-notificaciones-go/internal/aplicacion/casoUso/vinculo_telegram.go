@@ -0,0 +1,144 @@
+package casoUso
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// Respuestas del bot a /start
+const (
+	respuestaTelegramVinculado = "Listo: recibirá sus notificaciones en este chat."
+	respuestaTelegramInvalido  = "El enlace es inválido o venció. Solicite uno nuevo desde su cuenta."
+	respuestaTelegramSinEnlace = "Para recibir notificaciones abra el enlace de vinculación desde su cuenta."
+	respuestaTelegramError     = "No se pudo vincular el chat. Intente de nuevo en unos minutos."
+)
+
+// RespondedorTelegram envía un texto al chat desde el bot; lo implementa telegram.EnviadorTelegram
+type RespondedorTelegram interface {
+	Responder(ctx context.Context, chatID int64, texto string) error
+}
+
+// EnlaceTelegram es el enlace t.me que abre el chat con el bot y vincula al usuario al iniciarlo
+type EnlaceTelegram struct {
+	URL   string    `json:"url"`
+	Vence time.Time `json:"vence"`
+}
+
+// CasoUsoVinculoTelegram vincula el chat privado de Telegram de cada usuario: el usuario abre un
+// enlace firmado que inicia el bot con /start y el webhook del bot guarda el chat
+type CasoUsoVinculoTelegram struct {
+	repositorioUsuario   repositorio.RepositorioUsuario
+	repositorioInquilino repositorio.RepositorioInquilino
+	respondedor          RespondedorTelegram
+	bot                  string
+	secreto              []byte
+	vigencia             time.Duration
+	reloj                reloj.Reloj
+	logger               *logger.Logger
+}
+
+// NuevoCasoUsoVinculoTelegram crea una nueva instancia del caso de uso. bot es el nombre de
+// usuario del bot y secreto firma los enlaces, válidos durante vigencia.
+func NuevoCasoUsoVinculoTelegram(
+	repositorioUsuario repositorio.RepositorioUsuario,
+	repositorioInquilino repositorio.RepositorioInquilino,
+	respondedor RespondedorTelegram,
+	bot, secreto string,
+	vigencia time.Duration,
+	rel reloj.Reloj,
+	log *logger.Logger,
+) *CasoUsoVinculoTelegram {
+	return &CasoUsoVinculoTelegram{
+		repositorioUsuario:   repositorioUsuario,
+		repositorioInquilino: repositorioInquilino,
+		respondedor:          respondedor,
+		bot:                  bot,
+		secreto:              []byte(secreto),
+		vigencia:             vigencia,
+		reloj:                rel,
+		logger:               log,
+	}
+}
+
+// EnlaceVinculacion arma el enlace con que el usuario vincula su chat
+func (c *CasoUsoVinculoTelegram) EnlaceVinculacion(ctx context.Context, usuarioID uint) (*EnlaceTelegram, error) {
+	usuario, err := servicio.AutorizarUsuario(ctx, c.repositorioUsuario, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	vence := c.reloj.Ahora().Add(c.vigencia)
+	token := servicio.FirmarVinculoTelegram(c.secreto, usuario.InquilinoID, usuario.ID, vence)
+	return &EnlaceTelegram{URL: "https://t.me/" + c.bot + "?start=" + token, Vence: vence}, nil
+}
+
+// Desvincular olvida el chat del usuario; sus notificaciones telegram dejan de entregarse
+func (c *CasoUsoVinculoTelegram) Desvincular(ctx context.Context, usuarioID uint) error {
+	usuario, err := servicio.AutorizarUsuario(ctx, c.repositorioUsuario, usuarioID)
+	if err != nil {
+		return err
+	}
+	if usuario.TelegramChatID == 0 {
+		return nil
+	}
+	usuario.TelegramChatID = 0
+	return c.repositorioUsuario.Actualizar(ctx, usuario)
+}
+
+// Iniciar atiende un mensaje del chat privado con el bot. "/start <token>" vincula el chat al
+// usuario del token; cualquier otro texto recibe las instrucciones. El resultado se informa en
+// el chat, por eso solo se retornan los errores que ameritan que Telegram reintente.
+func (c *CasoUsoVinculoTelegram) Iniciar(ctx context.Context, chatID int64, texto string) error {
+	comando, token, _ := strings.Cut(strings.TrimSpace(texto), " ")
+	if comando != "/start" || token == "" {
+		return c.responder(ctx, chatID, respuestaTelegramSinEnlace)
+	}
+
+	err := c.vincular(ctx, strings.TrimSpace(token), chatID)
+	switch {
+	case err == nil:
+		return c.responder(ctx, chatID, respuestaTelegramVinculado)
+	case errors.Is(err, entidad.ErrEnlaceInvalido), errors.Is(err, entidad.ErrUsuarioNoEncontrado):
+		return c.responder(ctx, chatID, respuestaTelegramInvalido)
+	default:
+		c.logger.Error("No se pudo vincular el chat de Telegram", "chat_id", chatID, "error", err)
+		_ = c.responder(ctx, chatID, respuestaTelegramError)
+		return err
+	}
+}
+
+// vincular guarda el chat en el usuario del token, en la ubicación de los datos de su inquilino
+func (c *CasoUsoVinculoTelegram) vincular(ctx context.Context, token string, chatID int64) error {
+	inquilinoID, usuarioID, err := servicio.VerificarVinculoTelegram(c.secreto, token, c.reloj.Ahora())
+	if err != nil {
+		return err
+	}
+	ctx, err = servicio.ContextoDeInquilino(ctx, c.repositorioInquilino, inquilinoID)
+	if err != nil {
+		return err
+	}
+	usuario, err := c.repositorioUsuario.ObtenerPorID(ctx, usuarioID)
+	if err != nil {
+		return err
+	}
+	if usuario.InquilinoID != inquilinoID {
+		return entidad.ErrEnlaceInvalido
+	}
+	usuario.TelegramChatID = chatID
+	return c.repositorioUsuario.Actualizar(ctx, usuario)
+}
+
+func (c *CasoUsoVinculoTelegram) responder(ctx context.Context, chatID int64, texto string) error {
+	if err := c.respondedor.Responder(ctx, chatID, texto); err != nil {
+		// Sin respuesta el usuario puede volver a abrir el enlace; no vale reintentar la actualización
+		c.logger.Warn("No se pudo responder en Telegram", "chat_id", chatID, "error", err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package casoUso
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+)
+
+// VistaCorreo es el correo tal como lo recibe el destinatario
+type VistaCorreo struct {
+	Asunto string `json:"asunto"`
+	// HTML es el cuerpo con la marca del inquilino; vacío si no tiene marca configurada
+	HTML            string `json:"html,omitempty"`
+	TextoPlano      string `json:"texto_plano"`
+	RemitenteNombre string `json:"remitente_nombre,omitempty"`
+	RemitenteCorreo string `json:"remitente_correo,omitempty"`
+}
+
+// VistaPush es la notificación push tal como la muestra una plataforma sin expandir
+type VistaPush struct {
+	Plataforma     string `json:"plataforma"`
+	Titulo         string `json:"titulo"`
+	Cuerpo         string `json:"cuerpo"`
+	TituloTruncado bool   `json:"titulo_truncado"`
+	CuerpoTruncado bool   `json:"cuerpo_truncado"`
+}
+
+// VistaInApp es el contenido que recibe el cliente de la bandeja
+type VistaInApp struct {
+	Titulo    string                 `json:"titulo"`
+	Mensaje   string                 `json:"mensaje"`
+	Metadatos map[string]interface{} `json:"metadatos"`
+}
+
+// VistaPreviaCanales muestra una plantilla renderizada en cada canal. Advertencias resume lo
+// que el autor probablemente quiera corregir: textos cortados o SMS más caros de lo esperado.
+type VistaPreviaCanales struct {
+	Correo       VistaCorreo         `json:"correo"`
+	SMS          entidad.DesgloseSMS `json:"sms"`
+	Push         []VistaPush         `json:"push"`
+	InApp        VistaInApp          `json:"in_app"`
+	Advertencias []string            `json:"advertencias"`
+}
+
+// VistaPreviaCanales renderiza la plantilla una sola vez y muestra cómo queda en cada canal
+// con la marca del inquilino, sin crear ninguna notificación
+func (c *CasoUsoMarcaInquilino) VistaPreviaCanales(ctx context.Context, inquilinoID uint, nombre string, datos map[string]interface{}) (*VistaPreviaCanales, error) {
+	asunto, cuerpo, marca, err := c.renderizar(ctx, inquilinoID, nombre, datos)
+	if err != nil {
+		return nil, err
+	}
+	vista := &VistaPreviaCanales{Advertencias: []string{}}
+
+	correo, err := servicio.AplicarMarca(entidad.TipoEmail, asunto, cuerpo, marca)
+	if err != nil {
+		return nil, err
+	}
+	vista.Correo = VistaCorreo{Asunto: correo.Asunto, TextoPlano: correo.Cuerpo}
+	vista.Correo.HTML, _ = correo.Metadatos["html"].(string)
+	vista.Correo.RemitenteNombre, _ = correo.Metadatos["remitente_nombre"].(string)
+	vista.Correo.RemitenteCorreo, _ = correo.Metadatos["remitente_correo"].(string)
+	if strings.TrimSpace(asunto) == "" {
+		vista.Advertencias = append(vista.Advertencias, "correo: el asunto quedó vacío")
+	}
+
+	vista.SMS = entidad.DesglosarSMS(cuerpo)
+	if len(vista.SMS.CaracteresNoGSM) > 0 {
+		vista.Advertencias = append(vista.Advertencias, fmt.Sprintf("sms: %s obliga a codificar en UCS-2 (%d caracteres por segmento)",
+			strings.Join(vista.SMS.CaracteresNoGSM, " "), vista.SMS.CapacidadSegmento))
+	}
+	if len(vista.SMS.Segmentos) > 1 {
+		vista.Advertencias = append(vista.Advertencias, fmt.Sprintf("sms: ocupa %d segmentos", len(vista.SMS.Segmentos)))
+	}
+
+	for _, limite := range entidad.LimitesPush {
+		push := VistaPush{Plataforma: limite.Plataforma}
+		push.Titulo, push.TituloTruncado = entidad.TruncarTexto(asunto, limite.Titulo)
+		push.Cuerpo, push.CuerpoTruncado = entidad.TruncarTexto(cuerpo, limite.Cuerpo)
+		if push.TituloTruncado {
+			vista.Advertencias = append(vista.Advertencias, fmt.Sprintf("push %s: el título se corta a %d caracteres", limite.Plataforma, limite.Titulo))
+		}
+		if push.CuerpoTruncado {
+			vista.Advertencias = append(vista.Advertencias, fmt.Sprintf("push %s: el cuerpo se corta a %d caracteres", limite.Plataforma, limite.Cuerpo))
+		}
+		vista.Push = append(vista.Push, push)
+	}
+
+	inApp, err := servicio.AplicarMarca(entidad.TipoInApp, asunto, cuerpo, marca)
+	if err != nil {
+		return nil, err
+	}
+	vista.InApp = VistaInApp{Titulo: inApp.Asunto, Mensaje: inApp.Cuerpo, Metadatos: inApp.Metadatos}
+	return vista, nil
+}
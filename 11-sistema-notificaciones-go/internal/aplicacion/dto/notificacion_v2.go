@@ -0,0 +1,126 @@
+package dto
+
+import (
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// SolicitudEnviarNotificacionV2 es la solicitud de envío de /api/v2. Difiere de la de v1 solo
+// en los nombres: ProgramadaPara reemplaza a fecha_programada.
+type SolicitudEnviarNotificacionV2 struct {
+	UsuarioID      uint                          `json:"usuario_id" binding:"required_without=GuardiaCanalID"`
+	GuardiaCanalID uint                          `json:"guardia_canal_id"`
+	Titulo         string                        `json:"titulo" binding:"required_without=Plantilla,max=255"`
+	Mensaje        string                        `json:"mensaje" binding:"required_without=Plantilla"`
+	Plantilla      string                        `json:"plantilla" binding:"max=100"`
+	Datos          map[string]interface{}        `json:"datos"`
+	Tipo           entidad.TipoNotificacion      `json:"tipo" binding:"required,tipo_notificacion"`
+	Prioridad      entidad.PrioridadNotificacion `json:"prioridad" binding:"omitempty,prioridad"`
+	CanalID        uint                          `json:"canal_id"`
+	Origen         string                        `json:"origen" binding:"max=100"`
+	Metadatos      map[string]interface{}        `json:"metadatos"`
+	ProgramadaPara *time.Time                    `json:"programada_para"`
+	AvisoLectura   *SolicitudAvisoLectura        `json:"aviso_lectura"`
+	AdjuntoIDs     []uint                        `json:"adjunto_ids" binding:"max=10,unique"`
+}
+
+// Solicitud convierte la solicitud a la que reciben los casos de uso
+func (s SolicitudEnviarNotificacionV2) Solicitud() SolicitudEnviarNotificacion {
+	return SolicitudEnviarNotificacion{
+		UsuarioID:       s.UsuarioID,
+		GuardiaCanalID:  s.GuardiaCanalID,
+		Titulo:          s.Titulo,
+		Mensaje:         s.Mensaje,
+		Plantilla:       s.Plantilla,
+		Datos:           s.Datos,
+		Tipo:            s.Tipo,
+		Prioridad:       s.Prioridad,
+		CanalID:         s.CanalID,
+		Origen:          s.Origen,
+		Metadatos:       s.Metadatos,
+		FechaProgramada: s.ProgramadaPara,
+		AvisoLectura:    s.AvisoLectura,
+		AdjuntoIDs:      s.AdjuntoIDs,
+	}
+}
+
+// SolicitudReprogramarV2 es la reprogramación de /api/v2, con el nombre de la solicitud de envío
+type SolicitudReprogramarV2 struct {
+	ProgramadaPara time.Time `json:"programada_para" binding:"required"`
+}
+
+// AvisoLecturaV2 agrupa los destinos del aviso de lectura como en la solicitud
+type AvisoLecturaV2 struct {
+	Webhook string `json:"webhook,omitempty"`
+	Tema    string `json:"tema,omitempty"`
+}
+
+// RespuestaNotificacionV2 es la notificación que responde /api/v2: las fechas se nombran por
+// el evento (creada_en, leida_en), el aviso de lectura se agrupa como en la solicitud, de los
+// reintentos solo se expone el próximo y no se expone el borrado
+type RespuestaNotificacionV2 struct {
+	ID           uint                          `json:"id"`
+	InquilinoID  uint                          `json:"inquilino_id"`
+	UsuarioID    uint                          `json:"usuario_id"`
+	Usuario      *entidad.Usuario              `json:"usuario,omitempty"`
+	Titulo       string                        `json:"titulo"`
+	Mensaje      string                        `json:"mensaje"`
+	Tipo         entidad.TipoNotificacion      `json:"tipo"`
+	Estado       entidad.EstadoNotificacion    `json:"estado"`
+	Prioridad    entidad.PrioridadNotificacion `json:"prioridad"`
+	CanalID      uint                          `json:"canal_id,omitempty"`
+	Origen       string                        `json:"origen,omitempty"`
+	Silenciada   bool                          `json:"silenciada,omitempty"`
+	Canal        *entidad.Canal                `json:"canal,omitempty"`
+	EnvioID      *uint                         `json:"envio_id,omitempty"`
+	Metadatos    map[string]interface{}        `json:"metadatos"`
+	AvisoLectura *AvisoLecturaV2               `json:"aviso_lectura,omitempty"`
+	Intentos     int                           `json:"intentos"`
+	MaxIntentos  int                           `json:"max_intentos"`
+	// ProximoIntentoEn es cuándo se reintentará una fallida que aún tiene intentos
+	ProximoIntentoEn *time.Time `json:"proximo_intento_en,omitempty"`
+	Version          uint       `json:"version"`
+	ProgramadaPara   *time.Time `json:"programada_para"`
+	EnviadaEn        *time.Time `json:"enviada_en"`
+	LeidaEn          *time.Time `json:"leida_en"`
+	CreadaEn         time.Time  `json:"creada_en"`
+	ActualizadaEn    time.Time  `json:"actualizada_en"`
+}
+
+// NuevaRespuestaNotificacionV2 convierte la notificación al formato de /api/v2
+func NuevaRespuestaNotificacionV2(n *entidad.Notificacion) RespuestaNotificacionV2 {
+	respuesta := RespuestaNotificacionV2{
+		ID:             n.ID,
+		InquilinoID:    n.InquilinoID,
+		UsuarioID:      n.UsuarioID,
+		Usuario:        n.Usuario,
+		Titulo:         n.Titulo,
+		Mensaje:        n.Mensaje,
+		Tipo:           n.Tipo,
+		Estado:         n.Estado,
+		Prioridad:      n.Prioridad,
+		CanalID:        n.CanalID,
+		Origen:         n.Origen,
+		Silenciada:     n.Silenciada,
+		Canal:          n.Canal,
+		EnvioID:        n.EnvioID,
+		Metadatos:      n.Metadatos,
+		Intentos:       n.IntentosEnvio,
+		MaxIntentos:    n.MaxIntentos,
+		Version:        n.Version,
+		ProgramadaPara: n.FechaProgramada,
+		EnviadaEn:      n.FechaEnviada,
+		LeidaEn:        n.FechaLeida,
+		CreadaEn:       n.FechaCreacion,
+		ActualizadaEn:  n.FechaActualizacion,
+	}
+	// En una pendiente la fecha es la reserva del planificador de programadas, no un reintento
+	if n.Estado == entidad.EstadoFallida {
+		respuesta.ProximoIntentoEn = n.ProximaFechaReintento
+	}
+	if n.AvisoLecturaWebhook != "" || n.AvisoLecturaTema != "" {
+		respuesta.AvisoLectura = &AvisoLecturaV2{Webhook: n.AvisoLecturaWebhook, Tema: n.AvisoLecturaTema}
+	}
+	return respuesta
+}
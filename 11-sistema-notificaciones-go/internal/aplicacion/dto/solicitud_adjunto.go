@@ -0,0 +1,12 @@
+package dto
+
+import "io"
+
+// ArchivoSubido es un archivo recibido para guardarlo como adjunto. TipoContenido es el
+// detectado por el contenido, no el que declara el cliente.
+type ArchivoSubido struct {
+	Nombre        string
+	TipoContenido string
+	Tamano        int64
+	Contenido     io.ReadSeeker
+}
@@ -0,0 +1,19 @@
+package dto
+
+// SolicitudAdministradorInquilino contiene los datos del usuario administrador inicial
+type SolicitudAdministradorInquilino struct {
+	NombreUsuario     string `json:"nombre_usuario" binding:"required,max=50"`
+	CorreoElectronico string `json:"correo_electronico" binding:"required,email,max=255"`
+	Nombre            string `json:"nombre" binding:"required,max=100"`
+	Apellido          string `json:"apellido" binding:"required,max=100"`
+}
+
+// SolicitudAprovisionarInquilino contiene los datos para dar de alta un inquilino completo
+type SolicitudAprovisionarInquilino struct {
+	Nombre        string                          `json:"nombre" binding:"required,max=100"`
+	Administrador SolicitudAdministradorInquilino `json:"administrador" binding:"required"`
+	// Aislamiento es compartido (predeterminado) o esquema para guardar sus datos en un esquema propio
+	Aislamiento string `json:"aislamiento" binding:"omitempty,oneof=compartido esquema"`
+	// Region fija la región de residencia de sus datos (p. ej. eu); vacía usa la base principal
+	Region string `json:"region" binding:"omitempty,max=20"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+import "sistema-notificaciones-go/internal/dominio/entidad"
+
+// SolicitudMoverBandeja mueve una notificación in-app de carpeta
+type SolicitudMoverBandeja struct {
+	Carpeta entidad.CarpetaBandeja `json:"carpeta" binding:"required,oneof=recibidas archivadas"`
+}
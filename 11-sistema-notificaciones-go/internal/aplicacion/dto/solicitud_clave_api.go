@@ -0,0 +1,11 @@
+package dto
+
+import "sistema-notificaciones-go/internal/dominio/entidad"
+
+// SolicitudClaveAPI contiene los datos para emitir una clave de API
+type SolicitudClaveAPI struct {
+	Nombre string              `json:"nombre" binding:"required,max=100"`
+	Rol    entidad.RolClaveAPI `json:"rol" binding:"required,oneof=admin_plataforma admin_inquilino"`
+	// InquilinoID es requerido para admin_inquilino; un administrador de inquilino solo puede indicar el suyo
+	InquilinoID uint `json:"inquilino_id"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+import "sistema-notificaciones-go/internal/dominio/entidad"
+
+// SolicitudConsentimiento registra que un usuario otorgó o revocó su consentimiento
+type SolicitudConsentimiento struct {
+	Proposito entidad.PropositoConsentimiento `json:"proposito" binding:"required,oneof=marketing"`
+	// CanalID vacío aplica la decisión a todos los canales del propósito
+	CanalID uint   `json:"canal_id"`
+	Fuente  string `json:"fuente" binding:"required,max=100"`
+	// VersionTexto es obligatoria al otorgar: identifica el texto legal mostrado
+	VersionTexto string `json:"version_texto" binding:"max=50"`
+}
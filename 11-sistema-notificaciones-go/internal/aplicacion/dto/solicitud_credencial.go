@@ -0,0 +1,14 @@
+package dto
+
+// SolicitudCredencial contiene las credenciales propias de un inquilino para un proveedor
+// (p. ej. account_sid y auth_token para Twilio)
+type SolicitudCredencial struct {
+	Credenciales map[string]string `json:"credenciales" binding:"required,min=1,dive,keys,required,endkeys,required"`
+}
+
+// SolicitudRotacionCredencial contiene el juego de credenciales de la plataforma que reemplaza
+// al activo de un proveedor
+type SolicitudRotacionCredencial struct {
+	Nombre       string            `json:"nombre" binding:"required"`
+	Credenciales map[string]string `json:"credenciales" binding:"required,min=1,dive,keys,required,endkeys,required"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+import "sistema-notificaciones-go/internal/dominio/entidad"
+
+// SolicitudCuota contiene los límites de un inquilino para un tipo de notificación (0 sin límite)
+type SolicitudCuota struct {
+	Tipo             entidad.TipoNotificacion `json:"tipo" binding:"required,tipo_notificacion"`
+	LimiteMensual    int64                    `json:"limite_mensual" binding:"min=0"`
+	LimitePorSegundo int64                    `json:"limite_por_segundo" binding:"min=0"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+import "sistema-notificaciones-go/internal/dominio/entidad"
+
+// SolicitudDifusion contiene los datos para difundir una notificación a un canal
+type SolicitudDifusion struct {
+	Titulo    string                        `json:"titulo" binding:"required,max=255"`
+	Mensaje   string                        `json:"mensaje" binding:"required"`
+	Tipo      entidad.TipoNotificacion      `json:"tipo" binding:"required,tipo_notificacion"`
+	Prioridad entidad.PrioridadNotificacion `json:"prioridad" binding:"omitempty,prioridad"`
+	Metadatos map[string]interface{}        `json:"metadatos"`
+}
+
+// SolicitudRechazarPublicacion contiene el motivo por el que un moderador rechaza una publicación
+type SolicitudRechazarPublicacion struct {
+	Motivo string `json:"motivo" binding:"required,max=500"`
+}
+
+// SolicitudRechazarDifusion contiene el motivo por el que se devuelve el borrador a edición
+type SolicitudRechazarDifusion struct {
+	Motivo string `json:"motivo" binding:"max=500"`
+}
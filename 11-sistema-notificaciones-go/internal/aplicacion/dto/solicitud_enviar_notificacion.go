@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// SolicitudEnviarNotificacion contiene los datos para crear una notificación.
+// Con Plantilla, título y mensaje se renderizan desde ella con Datos y la marca del inquilino.
+// Con GuardiaCanalID el destinatario es quien esté de guardia en ese canal al enviarse.
+// AdjuntoIDs son archivos ya subidos que todavía no pertenecen a otra notificación.
+type SolicitudEnviarNotificacion struct {
+	UsuarioID       uint                          `json:"usuario_id" binding:"required_without=GuardiaCanalID"`
+	GuardiaCanalID  uint                          `json:"guardia_canal_id"`
+	Titulo          string                        `json:"titulo" binding:"required_without=Plantilla,max=255"`
+	Mensaje         string                        `json:"mensaje" binding:"required_without=Plantilla"`
+	Plantilla       string                        `json:"plantilla" binding:"max=100"`
+	Datos           map[string]interface{}        `json:"datos"`
+	Tipo            entidad.TipoNotificacion      `json:"tipo" binding:"required,tipo_notificacion"`
+	Prioridad       entidad.PrioridadNotificacion `json:"prioridad" binding:"omitempty,prioridad"`
+	CanalID         uint                          `json:"canal_id"`
+	Origen          string                        `json:"origen" binding:"max=100"`
+	Metadatos       map[string]interface{}        `json:"metadatos"`
+	FechaProgramada *time.Time                    `json:"fecha_programada"`
+	AvisoLectura    *SolicitudAvisoLectura        `json:"aviso_lectura"`
+	AdjuntoIDs      []uint                        `json:"adjunto_ids" binding:"max=10,unique"`
+}
+
+// SolicitudAvisoLectura declara a dónde avisar cuando el usuario lea la notificación, p. ej. para
+// que el sistema de origen detenga su propia escalada: un webhook, un tema de la cola o ambos
+type SolicitudAvisoLectura struct {
+	Webhook string `json:"webhook" binding:"required_without=Tema,omitempty,url,startswith=http,max=500"`
+	Tema    string `json:"tema" binding:"required_without=Webhook,omitempty,max=100"`
+}
@@ -0,0 +1,23 @@
+package dto
+
+import "sistema-notificaciones-go/internal/dominio/entidad"
+
+// SolicitudPasoEnvio describe la entrega por un canal dentro de un envío multicanal
+type SolicitudPasoEnvio struct {
+	Tipo entidad.TipoNotificacion `json:"tipo" binding:"required,tipo_notificacion"`
+	// RetrasoSegundos difiere este canal respecto del primero (p. ej. email 10 minutos después del push)
+	RetrasoSegundos int `json:"retraso_segundos" binding:"gte=0"`
+	// OmitirSiLeida cancela este canal si el usuario ya leyó la notificación por otro
+	OmitirSiLeida bool `json:"omitir_si_leida"`
+}
+
+// SolicitudEnvioMultiCanal contiene los datos de un mensaje lógico enviado por varios canales
+type SolicitudEnvioMultiCanal struct {
+	UsuarioID uint                          `json:"usuario_id" binding:"required"`
+	Titulo    string                        `json:"titulo" binding:"required,max=255"`
+	Mensaje   string                        `json:"mensaje" binding:"required"`
+	Prioridad entidad.PrioridadNotificacion `json:"prioridad" binding:"omitempty,prioridad"`
+	CanalID   uint                          `json:"canal_id"`
+	Metadatos map[string]interface{}        `json:"metadatos"`
+	Pasos     []SolicitudPasoEnvio          `json:"pasos" binding:"required,min=1,dive"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+import "sistema-notificaciones-go/internal/dominio/entidad"
+
+// SolicitudPoliticaEscalamiento contiene los pasos, en orden, de la política de un canal de seguridad
+type SolicitudPoliticaEscalamiento struct {
+	Pasos []SolicitudPasoEscalamiento `json:"pasos" binding:"required,min=1,max=20,dive"`
+}
+
+// SolicitudPasoEscalamiento es un aviso de la política; la demora se cuenta desde el inicio del incidente
+type SolicitudPasoEscalamiento struct {
+	UsuarioID     uint                     `json:"usuario_id" binding:"required"`
+	Tipo          entidad.TipoNotificacion `json:"tipo" binding:"required,tipo_notificacion"`
+	DemoraMinutos int                      `json:"demora_minutos" binding:"gte=0"`
+}
+
+// SolicitudIniciarEscalamiento contiene el incidente que se avisa a cada paso
+type SolicitudIniciarEscalamiento struct {
+	Titulo    string                 `json:"titulo" binding:"required,max=255"`
+	Mensaje   string                 `json:"mensaje" binding:"required"`
+	Metadatos map[string]interface{} `json:"metadatos"`
+}
+
+// SolicitudReconocerEscalamiento indica qué destinatario reconoce el incidente
+type SolicitudReconocerEscalamiento struct {
+	UsuarioID uint `json:"usuario_id" binding:"required"`
+}
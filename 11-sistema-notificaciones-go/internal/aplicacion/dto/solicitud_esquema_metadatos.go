@@ -0,0 +1,6 @@
+package dto
+
+// SolicitudEsquemaMetadatos contiene el JSON Schema de los metadatos de un canal
+type SolicitudEsquemaMetadatos struct {
+	Esquema map[string]interface{} `json:"esquema" binding:"required"`
+}
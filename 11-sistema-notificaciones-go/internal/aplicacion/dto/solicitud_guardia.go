@@ -0,0 +1,20 @@
+package dto
+
+import "time"
+
+// SolicitudRotacionGuardia contiene los participantes, en orden, y el calendario de la rotación de un canal
+type SolicitudRotacionGuardia struct {
+	Participantes []uint `json:"participantes" binding:"required,min=1,max=100,dive,required"`
+	// PrimerRelevo es la fecha y hora local del primer turno, en formato AAAA-MM-DDTHH:MM
+	PrimerRelevo string `json:"primer_relevo" binding:"required"`
+	ZonaHoraria  string `json:"zona_horaria" binding:"max=64"`
+	TurnoDias    int    `json:"turno_dias" binding:"required,gte=1,lte=365"`
+}
+
+// SolicitudReemplazoGuardia asigna la guardia a un usuario durante un intervalo
+type SolicitudReemplazoGuardia struct {
+	UsuarioID uint      `json:"usuario_id" binding:"required"`
+	Desde     time.Time `json:"desde" binding:"required"`
+	Hasta     time.Time `json:"hasta" binding:"required"`
+	Motivo    string    `json:"motivo" binding:"max=255"`
+}
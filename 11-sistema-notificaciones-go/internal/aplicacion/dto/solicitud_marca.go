@@ -0,0 +1,11 @@
+package dto
+
+// SolicitudMarca contiene la identidad visual del inquilino; los campos vacíos no se aplican
+type SolicitudMarca struct {
+	LogoURL         string `json:"logo_url" binding:"omitempty,url,max=500"`
+	ColorPrimario   string `json:"color_primario" binding:"omitempty,hexcolor,len=7"`
+	ColorSecundario string `json:"color_secundario" binding:"omitempty,hexcolor,len=7"`
+	NombreRemitente string `json:"nombre_remitente" binding:"max=100"`
+	CorreoRemitente string `json:"correo_remitente" binding:"omitempty,email,max=255"`
+	PiePagina       string `json:"pie_pagina" binding:"max=2000"`
+}
@@ -0,0 +1,16 @@
+package dto
+
+// SolicitudPrivacidadCanal marca un canal como privado o público
+type SolicitudPrivacidadCanal struct {
+	Privado *bool `json:"privado" binding:"required"`
+}
+
+// SolicitudPublicacionCanal abre o cierra el canal a las publicaciones de sus miembros
+type SolicitudPublicacionCanal struct {
+	PublicanMiembros *bool `json:"publican_miembros" binding:"required"`
+}
+
+// SolicitudInvitacionCanal invita a un usuario a un canal privado
+type SolicitudInvitacionCanal struct {
+	UsuarioID uint `json:"usuario_id" binding:"required"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+import "sistema-notificaciones-go/internal/dominio/entidad"
+
+// SolicitudObjetivoSLA contiene el tiempo de envío al proveedor comprometido para una prioridad
+type SolicitudObjetivoSLA struct {
+	Prioridad          entidad.PrioridadNotificacion `json:"prioridad" binding:"required,prioridad"`
+	UmbralSegundos     int                           `json:"umbral_segundos" binding:"required,gt=0"`
+	PorcentajeObjetivo float64                       `json:"porcentaje_objetivo" binding:"required,gt=0,lte=100"`
+}
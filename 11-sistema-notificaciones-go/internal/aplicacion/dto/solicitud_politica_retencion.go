@@ -0,0 +1,15 @@
+package dto
+
+import "sistema-notificaciones-go/internal/dominio/entidad"
+
+// SolicitudPoliticaRetencion contiene los días de retención para un tipo o un canal y qué hacer
+// al vencer
+type SolicitudPoliticaRetencion struct {
+	// InquilinoID vacío aplica la política a todos los inquilinos
+	InquilinoID uint                     `json:"inquilino_id"`
+	Tipo        entidad.TipoNotificacion `json:"tipo" binding:"omitempty,tipo_notificacion,required_without=CanalID"`
+	CanalID     uint                     `json:"canal_id" binding:"required_without=Tipo"`
+	Dias        int                      `json:"dias" binding:"required,gt=0"`
+	// Accion por defecto es eliminar
+	Accion entidad.AccionRetencion `json:"accion" binding:"omitempty,oneof=eliminar anonimizar"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+import "sistema-notificaciones-go/internal/dominio/entidad"
+
+// SolicitudPreferencia contiene la preferencia de un usuario para un tipo de notificación
+type SolicitudPreferencia struct {
+	Tipo entidad.TipoNotificacion `json:"tipo" binding:"required,tipo_notificacion"`
+	// Habilitada por defecto es true
+	Habilitada    *bool  `json:"habilitada"`
+	SilencioDesde string `json:"silencio_desde" binding:"omitempty,hora,required_with=SilencioHasta"`
+	SilencioHasta string `json:"silencio_hasta" binding:"omitempty,hora,required_with=SilencioDesde"`
+	ZonaHoraria   string `json:"zona_horaria" binding:"omitempty,timezone"`
+}
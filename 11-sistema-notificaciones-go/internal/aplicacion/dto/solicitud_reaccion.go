@@ -0,0 +1,8 @@
+package dto
+
+import "sistema-notificaciones-go/internal/dominio/entidad"
+
+// SolicitudReaccion agrega una reacción a una notificación: acuse, 👍 o resuelta
+type SolicitudReaccion struct {
+	Reaccion entidad.TipoReaccion `json:"reaccion" binding:"required"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// SolicitudReenvio vuelve a enviar las notificaciones que fallaron durante un incidente
+type SolicitudReenvio struct {
+	// Desde y Hasta delimitan la creación de las notificaciones fallidas, [Desde, Hasta)
+	Desde time.Time `json:"desde" binding:"required"`
+	Hasta time.Time `json:"hasta" binding:"required,gtfield=Desde"`
+	// InquilinoID solo lo usa la plataforma; vacío reenvía las notificaciones sin inquilino
+	InquilinoID uint                     `json:"inquilino_id"`
+	Tipo        entidad.TipoNotificacion `json:"tipo"`
+	// Limite acota cuántas fallidas se examinan; 0 usa el máximo
+	Limite int `json:"limite" binding:"min=0,max=10000"`
+	// Simular informa qué se reenviaría sin crear ni encolar nada
+	Simular bool `json:"simular"`
+}
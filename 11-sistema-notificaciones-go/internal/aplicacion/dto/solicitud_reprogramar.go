@@ -0,0 +1,8 @@
+package dto
+
+import "time"
+
+// SolicitudReprogramar mueve una notificación programada a una nueva fecha
+type SolicitudReprogramar struct {
+	FechaProgramada time.Time `json:"fecha_programada" binding:"required"`
+}
@@ -0,0 +1,17 @@
+package dto
+
+// SolicitudRespuesta es una respuesta al hilo de una notificación in-app
+type SolicitudRespuesta struct {
+	Mensaje string `json:"mensaje" binding:"required,max=2000"`
+}
+
+// SolicitudRespuestasCanal habilita o deshabilita las respuestas a las notificaciones del canal
+type SolicitudRespuestasCanal struct {
+	AdmiteRespuestas *bool `json:"admite_respuestas" binding:"required"`
+}
+
+// SolicitudRespuestasCorreoCanal configura el webhook que recibe las respuestas por correo a las
+// notificaciones del canal; vacío deja de reenviarlas
+type SolicitudRespuestasCorreoCanal struct {
+	Webhook string `json:"webhook" binding:"omitempty,url,startswith=http,max=500"`
+}
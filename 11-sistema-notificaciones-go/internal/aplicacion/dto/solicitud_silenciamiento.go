@@ -0,0 +1,12 @@
+package dto
+
+import "time"
+
+// SolicitudSilenciamiento silencia un canal o un origen; sin hasta ni minutos es indefinido
+type SolicitudSilenciamiento struct {
+	CanalID uint       `json:"canal_id" binding:"required_without=Origen"`
+	Origen  string     `json:"origen" binding:"max=100"`
+	Hasta   *time.Time `json:"hasta" binding:"excluded_with=Minutos"`
+	// Minutos es una alternativa a Hasta contada desde ahora
+	Minutos int `json:"minutos" binding:"omitempty,min=1,max=525600"`
+}
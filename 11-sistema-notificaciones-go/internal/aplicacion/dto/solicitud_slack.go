@@ -0,0 +1,8 @@
+package dto
+
+// SolicitudSlackCanal configura adónde publica el canal sus notificaciones slack: un incoming
+// webhook o un canal de Slack por chat.postMessage. Ambos vacíos quitan el destino.
+type SolicitudSlackCanal struct {
+	WebhookURL string `json:"webhook_url" binding:"omitempty,url,max=500"`
+	CanalSlack string `json:"canal_slack" binding:"omitempty,max=80"`
+}
@@ -0,0 +1,12 @@
+package dto
+
+import "sistema-notificaciones-go/internal/dominio/entidad"
+
+// SolicitudSupresion agrega un correo o teléfono a la lista de no contactar
+type SolicitudSupresion struct {
+	Valor   string                  `json:"valor" binding:"required,max=255"`
+	Motivo  entidad.MotivoSupresion `json:"motivo" binding:"required,oneof=rebote queja solicitud_legal manual"`
+	Detalle string                  `json:"detalle" binding:"max=500"`
+	// InquilinoID solo lo usa la plataforma para cargar en la lista de un inquilino; vacío es global
+	InquilinoID uint `json:"inquilino_id"`
+}
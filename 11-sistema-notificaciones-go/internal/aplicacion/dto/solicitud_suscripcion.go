@@ -0,0 +1,6 @@
+package dto
+
+// SolicitudSuscripcion suscribe a un usuario a un canal
+type SolicitudSuscripcion struct {
+	CanalID uint `json:"canal_id" binding:"required"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+// SolicitudSuscripcionWebPush es la PushSubscription del navegador tal como la serializa
+// subscription.toJSON()
+type SolicitudSuscripcionWebPush struct {
+	Endpoint string                   `json:"endpoint" binding:"required,url,max=512"`
+	Keys     ClavesSuscripcionWebPush `json:"keys" binding:"required"`
+}
+
+// ClavesSuscripcionWebPush son las claves de cifrado de la suscripción, en base64url
+type ClavesSuscripcionWebPush struct {
+	P256dh string `json:"p256dh" binding:"required,max=128"`
+	Auth   string `json:"auth" binding:"required,max=64"`
+}
+
+// SolicitudBajaWebPush identifica la suscripción a eliminar, p. ej. tras subscription.unsubscribe()
+type SolicitudBajaWebPush struct {
+	Endpoint string `json:"endpoint" binding:"required,max=512"`
+}
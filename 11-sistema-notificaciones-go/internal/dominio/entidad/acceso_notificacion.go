@@ -0,0 +1,83 @@
+package entidad
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// OperacionAcceso define qué consultó el personal
+type OperacionAcceso string
+
+const (
+	OperacionAccesoDetalle     OperacionAcceso = "detalle"
+	OperacionAccesoListado     OperacionAcceso = "listado"
+	OperacionAccesoExportacion OperacionAcceso = "exportacion"
+)
+
+// AccesoNotificacion registra que un miembro del personal (administrador o moderador) consultó
+// notificaciones de otro usuario. Es un registro de cumplimiento independiente de los logs:
+// quién, qué, cuándo y por qué. Los registros forman una cadena de hashes: cada uno incluye el
+// hash del anterior, así modificar o borrar uno rompe la cadena a partir de él. Con la clave de
+// la cadena configurada, cada hash se sella además con HMAC: quien tiene acceso a la base puede
+// recalcular los hashes de un tramo alterado, pero no sus sellos.
+type AccesoNotificacion struct {
+	ID          uint `json:"id" gorm:"primaryKey"`
+	InquilinoID uint `json:"inquilino_id" gorm:"index"`
+	// ActorID es 0 cuando el acceso se hizo solo con una credencial de plataforma
+	ActorID  uint       `json:"actor_id" gorm:"index"`
+	ActorRol RolUsuario `json:"actor_rol,omitempty" gorm:"size:50"`
+	// Credencial es el nombre de la clave de API con la que se hizo la consulta
+	Credencial string `json:"credencial" gorm:"size:100"`
+	// UsuarioID es el dueño de las notificaciones consultadas; 0 si la consulta abarcó a todos
+	UsuarioID      uint            `json:"usuario_id" gorm:"index"`
+	NotificacionID uint            `json:"notificacion_id,omitempty"`
+	Operacion      OperacionAcceso `json:"operacion" gorm:"not null;size:20"`
+	Motivo         string          `json:"motivo" gorm:"not null;type:text"`
+	Fecha          time.Time       `json:"fecha" gorm:"not null;index"`
+	// HashAnterior es vacío en el primer registro de la cadena y en los previos a ella
+	HashAnterior string `json:"hash_anterior" gorm:"size:64"`
+	Hash         string `json:"hash" gorm:"size:64"`
+	// Sello es el HMAC-SHA256 de Hash; vacío en los registros previos a la clave de la cadena
+	Sello string `json:"sello,omitempty" gorm:"size:64"`
+}
+
+// Encadenar enlaza el acceso con el último registrado y calcula su hash. La fecha se lleva a la
+// precisión con que la guarda la base para poder recalcular el hash al leerla. Sin clave el
+// acceso queda sin sellar.
+func (a *AccesoNotificacion) Encadenar(hashAnterior string, clave []byte) {
+	a.Fecha = a.Fecha.UTC().Truncate(time.Microsecond)
+	a.HashAnterior = hashAnterior
+	a.Hash = a.CalcularHash()
+	a.Sello = ""
+	if len(clave) > 0 {
+		a.Sello = a.CalcularSello(clave)
+	}
+}
+
+// CalcularSello retorna el HMAC-SHA256 del hash del acceso con la clave de la cadena
+func (a *AccesoNotificacion) CalcularSello(clave []byte) string {
+	mac := hmac.New(sha256.New, clave)
+	mac.Write([]byte(a.Hash))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// CalcularHash retorna el SHA-256 del contenido del acceso junto con el hash anterior
+func (a *AccesoNotificacion) CalcularHash() string {
+	contenido := fmt.Sprintf("%s|%d|%d|%q|%q|%d|%d|%q|%q|%s",
+		a.HashAnterior,
+		a.InquilinoID,
+		a.ActorID,
+		a.ActorRol,
+		a.Credencial,
+		a.UsuarioID,
+		a.NotificacionID,
+		a.Operacion,
+		a.Motivo,
+		a.Fecha.UTC().Format(time.RFC3339Nano),
+	)
+	suma := sha256.Sum256([]byte(contenido))
+	return hex.EncodeToString(suma[:])
+}
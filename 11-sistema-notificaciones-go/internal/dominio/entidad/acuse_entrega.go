@@ -0,0 +1,20 @@
+package entidad
+
+// ReferenciaMensaje vincula los mensajes que un proveedor aceptó con la notificación de la que
+// forman parte. Un SMS largo se envía en varias partes, cada una con su propio ID.
+type ReferenciaMensaje struct {
+	InquilinoID    uint `json:"inquilino_id"`
+	NotificacionID uint `json:"notificacion_id"`
+	Partes         int  `json:"partes"`
+}
+
+// AcuseEntrega es el resultado final que un proveedor informa sobre uno de sus mensajes
+type AcuseEntrega struct {
+	Proveedor string
+	IDMensaje string
+	// Estado es el estado informado por el proveedor, p. ej. DELIVRD o UNDELIV
+	Estado    string
+	Entregado bool
+	// CodigoError es el código de error del proveedor, si lo informó
+	CodigoError string
+}
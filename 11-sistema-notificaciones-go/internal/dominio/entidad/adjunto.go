@@ -0,0 +1,61 @@
+package entidad
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxLongitudNombreAdjunto acota el nombre original del archivo
+const maxLongitudNombreAdjunto = 255
+
+// Adjunto es un archivo subido para acompañar una notificación: los correos lo adjuntan y los
+// clientes in-app lo descargan. El contenido vive en el almacén de objetos, no en la base.
+type Adjunto struct {
+	ID          uint `json:"id" gorm:"primaryKey"`
+	InquilinoID uint `json:"inquilino_id" gorm:"index"`
+	// NotificacionID queda vacío hasta que una notificación lo incluye
+	NotificacionID *uint     `json:"notificacion_id,omitempty" gorm:"index"`
+	Nombre         string    `json:"nombre" gorm:"size:255;not null"`
+	TipoContenido  string    `json:"tipo_contenido" gorm:"size:100;not null"`
+	Tamano         int64     `json:"tamano" gorm:"not null"`
+	SHA256         string    `json:"sha256" gorm:"size:64;not null"`
+	Clave          string    `json:"-" gorm:"size:300;not null;uniqueIndex"`
+	FechaCreacion  time.Time `json:"fecha_creacion" gorm:"autoCreateTime"`
+
+	// URLDescarga es la URL firmada del almacén, válida hasta VenceURL; no se guarda
+	URLDescarga string     `json:"url_descarga,omitempty" gorm:"-"`
+	VenceURL    *time.Time `json:"vence_url,omitempty" gorm:"-"`
+}
+
+// TableName fija el nombre de la tabla de adjuntos
+func (Adjunto) TableName() string {
+	return "adjuntos"
+}
+
+// Vinculado indica si el adjunto ya pertenece a una notificación
+func (a *Adjunto) Vinculado() bool {
+	return a.NotificacionID != nil
+}
+
+// NombreAdjunto limpia el nombre del archivo subido: sin ruta, sin caracteres de control y
+// acotado, para usarlo sin riesgo en Content-Disposition
+func NombreAdjunto(nombre string) string {
+	nombre = filepath.Base(strings.ReplaceAll(nombre, `\`, "/"))
+	nombre = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(nombre))
+	for len(nombre) > maxLongitudNombreAdjunto {
+		_, ancho := utf8.DecodeLastRuneInString(nombre)
+		nombre = nombre[:len(nombre)-ancho]
+	}
+	if nombre == "" || nombre == "." || nombre == "/" {
+		return "archivo"
+	}
+	return nombre
+}
@@ -0,0 +1,50 @@
+package entidad
+
+// CarpetaBandeja define las carpetas de la bandeja in-app de un usuario
+type CarpetaBandeja string
+
+const (
+	CarpetaRecibidas  CarpetaBandeja = "recibidas"
+	CarpetaArchivadas CarpetaBandeja = "archivadas"
+	// CarpetaDestacadas es una vista: reúne las destacadas de cualquier carpeta y no admite mover a ella
+	CarpetaDestacadas CarpetaBandeja = "destacadas"
+)
+
+// CarpetasBandeja son las carpetas y vistas en el orden en que se presentan
+var CarpetasBandeja = []CarpetaBandeja{CarpetaRecibidas, CarpetaArchivadas, CarpetaDestacadas}
+
+// EstadosBandeja son los estados de las notificaciones in-app que el usuario ve en su bandeja:
+// las pendientes o programadas todavía no le llegaron
+var EstadosBandeja = []EstadoNotificacion{EstadoEnviada, EstadoEntregada, EstadoLeida}
+
+// EsValida indica si es una carpeta o la vista de destacadas
+func (c CarpetaBandeja) EsValida() bool {
+	return c == CarpetaRecibidas || c == CarpetaArchivadas || c == CarpetaDestacadas
+}
+
+// ConteoCarpeta resume una carpeta de la bandeja
+type ConteoCarpeta struct {
+	Total    int64 `json:"total"`
+	NoLeidas int64 `json:"no_leidas"`
+}
+
+// MoverACarpeta mueve la notificación in-app a recibidas o archivadas; conserva si está destacada
+func (n *Notificacion) MoverACarpeta(carpeta CarpetaBandeja) error {
+	if n.Tipo != TipoInApp {
+		return NewErrorValidacion("Solo las notificaciones in_app tienen bandeja")
+	}
+	if carpeta != CarpetaRecibidas && carpeta != CarpetaArchivadas {
+		return NewErrorValidacion("carpeta debe ser recibidas o archivadas")
+	}
+	n.Carpeta = carpeta
+	return nil
+}
+
+// Destacar marca o desmarca la notificación in-app como destacada
+func (n *Notificacion) Destacar(destacada bool) error {
+	if n.Tipo != TipoInApp {
+		return NewErrorValidacion("Solo las notificaciones in_app tienen bandeja")
+	}
+	n.Destacada = destacada
+	return nil
+}
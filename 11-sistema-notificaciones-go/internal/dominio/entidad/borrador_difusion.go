@@ -0,0 +1,157 @@
+package entidad
+
+import "time"
+
+// EstadoBorradorDifusion define los estados del flujo de aprobación de una difusión
+type EstadoBorradorDifusion string
+
+const (
+	EstadoBorradorEditable            EstadoBorradorDifusion = "borrador"
+	EstadoBorradorPendienteAprobacion EstadoBorradorDifusion = "pendiente_aprobacion"
+	EstadoBorradorAprobado            EstadoBorradorDifusion = "aprobada"
+	EstadoBorradorEnviado             EstadoBorradorDifusion = "enviada"
+	// EstadoBorradorPendienteModeracion es la publicación de un miembro que espera a un moderador
+	EstadoBorradorPendienteModeracion EstadoBorradorDifusion = "pendiente_moderacion"
+	// EstadoBorradorRechazado es una publicación que un moderador rechazó; no vuelve a edición
+	EstadoBorradorRechazado EstadoBorradorDifusion = "rechazada"
+)
+
+// BorradorDifusion es una difusión a los suscriptores de un canal que se prepara antes de
+// enviarse. En los canales grandes debe aprobarla una persona distinta de quien la solicitó.
+// Las publicaciones de los miembros de un canal también son borradores: entran pendientes de
+// moderación y solo se difunden si un moderador las aprueba.
+type BorradorDifusion struct {
+	ID          uint                   `json:"id" gorm:"primaryKey"`
+	InquilinoID uint                   `json:"inquilino_id" gorm:"index"`
+	CanalID     uint                   `json:"canal_id" gorm:"not null;index"`
+	Titulo      string                 `json:"titulo" gorm:"not null;size:255"`
+	Mensaje     string                 `json:"mensaje" gorm:"type:text;not null"`
+	Tipo        TipoNotificacion       `json:"tipo" gorm:"not null;size:50"`
+	Prioridad   PrioridadNotificacion  `json:"prioridad,omitempty" gorm:"size:20"`
+	Metadatos   map[string]interface{} `json:"metadatos,omitempty" gorm:"type:jsonb;serializer:json"`
+	Estado      EstadoBorradorDifusion `json:"estado" gorm:"not null;size:30;index"`
+	CreadoPor   uint                   `json:"creado_por,omitempty"`
+	// SolicitadoPor es quien envió el borrador a aprobación; no puede aprobarlo
+	SolicitadoPor uint   `json:"solicitado_por,omitempty"`
+	AprobadoPor   uint   `json:"aprobado_por,omitempty"`
+	RechazadoPor  uint   `json:"rechazado_por,omitempty"`
+	MotivoRechazo string `json:"motivo_rechazo,omitempty" gorm:"size:500"`
+	// DifusionID identifica el progreso de la difusión lanzada al enviarse
+	DifusionID         string     `json:"difusion_id,omitempty" gorm:"size:32"`
+	FechaSolicitud     *time.Time `json:"fecha_solicitud,omitempty"`
+	FechaResolucion    *time.Time `json:"fecha_resolucion,omitempty"`
+	FechaEnvio         *time.Time `json:"fecha_envio,omitempty"`
+	FechaCreacion      time.Time  `json:"fecha_creacion" gorm:"autoCreateTime"`
+	FechaActualizacion time.Time  `json:"fecha_actualizacion" gorm:"autoUpdateTime"`
+}
+
+// Validar valida el contenido del borrador
+func (b *BorradorDifusion) Validar() error {
+	if b.Titulo == "" || b.Mensaje == "" {
+		return NewErrorValidacion("Título y mensaje son requeridos")
+	}
+	if b.Tipo == "" {
+		return NewErrorValidacion("Tipo es requerido")
+	}
+	return nil
+}
+
+// Editar reemplaza el contenido; solo se edita un borrador que no está en aprobación
+func (b *BorradorDifusion) Editar(titulo, mensaje string, tipo TipoNotificacion, prioridad PrioridadNotificacion, metadatos map[string]interface{}) error {
+	if b.Estado != EstadoBorradorEditable {
+		return NewErrorDominio("solo se edita una difusión en borrador")
+	}
+	b.Titulo, b.Mensaje, b.Tipo, b.Prioridad, b.Metadatos = titulo, mensaje, tipo, prioridad, metadatos
+	return b.Validar()
+}
+
+// SolicitarAprobacion pasa el borrador a pendiente de aprobación
+func (b *BorradorDifusion) SolicitarAprobacion(usuarioID uint, ahora time.Time) error {
+	if b.Estado != EstadoBorradorEditable {
+		return NewErrorDominio("solo se envía a aprobación una difusión en borrador")
+	}
+	b.Estado = EstadoBorradorPendienteAprobacion
+	b.SolicitadoPor = usuarioID
+	b.FechaSolicitud = &ahora
+	b.RechazadoPor, b.MotivoRechazo, b.FechaResolucion = 0, "", nil
+	return nil
+}
+
+// Aprobar aprueba el borrador pendiente; quien lo solicitó no puede aprobarlo
+func (b *BorradorDifusion) Aprobar(usuarioID uint, ahora time.Time) error {
+	if b.Estado != EstadoBorradorPendienteAprobacion {
+		return NewErrorDominio("la difusión no está pendiente de aprobación")
+	}
+	if usuarioID == b.SolicitadoPor {
+		return NewErrorDominio("la difusión debe aprobarla una persona distinta de quien la solicitó")
+	}
+	b.Estado = EstadoBorradorAprobado
+	b.AprobadoPor = usuarioID
+	b.FechaResolucion = &ahora
+	return nil
+}
+
+// Rechazar devuelve el borrador pendiente a edición con el motivo del rechazo
+func (b *BorradorDifusion) Rechazar(usuarioID uint, motivo string, ahora time.Time) error {
+	if b.Estado != EstadoBorradorPendienteAprobacion {
+		return NewErrorDominio("la difusión no está pendiente de aprobación")
+	}
+	b.Estado = EstadoBorradorEditable
+	b.RechazadoPor = usuarioID
+	b.MotivoRechazo = motivo
+	b.FechaResolucion = &ahora
+	return nil
+}
+
+// AprobarPublicacion aprueba la publicación de un miembro; su autor no puede moderarla
+func (b *BorradorDifusion) AprobarPublicacion(moderadorID uint, ahora time.Time) error {
+	if b.Estado != EstadoBorradorPendienteModeracion {
+		return NewErrorDominio("la publicación no está pendiente de moderación")
+	}
+	if moderadorID == b.SolicitadoPor {
+		return NewErrorDominio("la publicación debe moderarla una persona distinta de su autor")
+	}
+	b.Estado = EstadoBorradorAprobado
+	b.AprobadoPor = moderadorID
+	b.FechaResolucion = &ahora
+	return nil
+}
+
+// RechazarPublicacion rechaza la publicación de un miembro con el motivo, que ve su autor
+func (b *BorradorDifusion) RechazarPublicacion(moderadorID uint, motivo string, ahora time.Time) error {
+	if b.Estado != EstadoBorradorPendienteModeracion {
+		return NewErrorDominio("la publicación no está pendiente de moderación")
+	}
+	if motivo == "" {
+		return NewErrorValidacion("El motivo del rechazo es requerido")
+	}
+	b.Estado = EstadoBorradorRechazado
+	b.RechazadoPor = moderadorID
+	b.MotivoRechazo = motivo
+	b.FechaResolucion = &ahora
+	return nil
+}
+
+// ValidarEnvio verifica que el borrador pueda difundirse: aprobado, o en borrador si el canal
+// no requiere aprobación, lo que decide quien lo difunde
+func (b *BorradorDifusion) ValidarEnvio() error {
+	switch b.Estado {
+	case EstadoBorradorAprobado, EstadoBorradorEditable:
+		return nil
+	case EstadoBorradorEnviado:
+		return NewErrorDominio("la difusión ya fue enviada")
+	case EstadoBorradorRechazado:
+		return NewErrorDominio("la publicación fue rechazada")
+	case EstadoBorradorPendienteModeracion:
+		return NewErrorDominio("la publicación está pendiente de moderación")
+	default:
+		return NewErrorDominio("la difusión está pendiente de aprobación")
+	}
+}
+
+// MarcarEnviada registra la difusión lanzada
+func (b *BorradorDifusion) MarcarEnviada(difusionID string, ahora time.Time) {
+	b.Estado = EstadoBorradorEnviado
+	b.DifusionID = difusionID
+	b.FechaEnvio = &ahora
+}
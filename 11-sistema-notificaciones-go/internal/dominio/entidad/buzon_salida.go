@@ -0,0 +1,16 @@
+package entidad
+
+import "time"
+
+// EntradaBuzonSalida marca una notificación creada que todavía no se publicó en la cola de
+// despacho. Se escribe en la misma transacción que la notificación y se borra al publicarla:
+// si la cola no la acepta en ese momento, la entrada queda para el relevo del buzón.
+type EntradaBuzonSalida struct {
+	NotificacionID uint      `json:"notificacion_id" gorm:"primaryKey;autoIncrement:false"`
+	Fecha          time.Time `json:"fecha" gorm:"not null;index"`
+}
+
+// TableName fija el nombre de la tabla
+func (EntradaBuzonSalida) TableName() string {
+	return "buzon_salida"
+}
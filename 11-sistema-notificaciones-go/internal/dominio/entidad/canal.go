@@ -28,11 +28,23 @@ const (
 // Canal representa un canal de notificaciones
 type Canal struct {
 	ID                uint           `json:"id" gorm:"primaryKey"`
+	// InquilinoID es 0 para los canales de la plataforma
+	InquilinoID       uint           `json:"inquilino_id" gorm:"index"`
 	Nombre            string         `json:"nombre" gorm:"not null;size:100"`
 	Descripcion       string         `json:"descripcion" gorm:"size:500"`
 	Tipo              TipoCanal      `json:"tipo" gorm:"not null;size:50"`
 	Estado            EstadoCanal    `json:"estado" gorm:"not null;size:50;default:'activo'"`
-	Configuracion     map[string]interface{} `json:"configuracion" gorm:"type:jsonb"`
+	// Privado exige invitación de un administrador o solicitud aprobada para unirse
+	Privado           bool           `json:"privado" gorm:"not null;default:false"`
+	// PublicanMiembros permite publicar a los miembros confirmados; sus publicaciones pasan por moderación
+	PublicanMiembros  bool           `json:"publican_miembros" gorm:"not null;default:false"`
+	// AdmiteRespuestas permite a los destinatarios responder las notificaciones in-app en hilos
+	AdmiteRespuestas  bool           `json:"admite_respuestas" gorm:"not null;default:false"`
+	// WebhookRespuestasCorreo recibe las respuestas por correo a las notificaciones del canal
+	WebhookRespuestasCorreo string   `json:"webhook_respuestas_correo,omitempty" gorm:"size:500"`
+	Configuracion     map[string]interface{} `json:"configuracion" gorm:"type:jsonb;serializer:json"`
+	// EsquemaMetadatos es el JSON Schema que deben cumplir los metadatos de las notificaciones del canal
+	EsquemaMetadatos  map[string]interface{} `json:"esquema_metadatos,omitempty" gorm:"type:jsonb;serializer:json"`
 	FechaCreacion     time.Time      `json:"fecha_creacion" gorm:"autoCreateTime"`
 	FechaActualizacion time.Time     `json:"fecha_actualizacion" gorm:"autoUpdateTime"`
 	FechaEliminacion  gorm.DeletedAt `json:"fecha_eliminacion" gorm:"index"`
@@ -0,0 +1,58 @@
+package entidad
+
+import "time"
+
+// prioridadesCarga son las prioridades del resumen de carga, en orden de importancia
+var prioridadesCarga = []PrioridadNotificacion{PrioridadCritica, PrioridadAlta, PrioridadNormal, PrioridadBaja}
+
+// PendientesPrioridad es lo que una base tiene listo para despachar en una prioridad: las
+// pendientes cuya fecha programada llegó y las fallidas con el reintento vencido
+type PendientesPrioridad struct {
+	Prioridad  PrioridadNotificacion
+	Pendientes int64
+	// MasAntigua es desde cuándo espera la notificación lista más antigua
+	MasAntigua *time.Time
+}
+
+// CargaPrioridad es el atraso de despacho de una prioridad
+type CargaPrioridad struct {
+	Prioridad          PrioridadNotificacion `json:"prioridad"`
+	Pendientes         int64                 `json:"pendientes"`
+	AntiguedadSegundos float64               `json:"antiguedad_segundos"`
+}
+
+// CargaDespacho es el atraso de despacho de todas las bases: la señal para escalar los trabajadores
+type CargaDespacho struct {
+	Fecha              time.Time        `json:"fecha"`
+	Pendientes         int64            `json:"pendientes"`
+	AntiguedadSegundos float64          `json:"antiguedad_segundos"`
+	PorPrioridad       []CargaPrioridad `json:"por_prioridad"`
+}
+
+// NuevaCargaDespacho suma lo pendiente de cada base por prioridad y toma la espera más antigua.
+// Todas las prioridades figuran, aunque no tengan pendientes.
+func NuevaCargaDespacho(ahora time.Time, pendientes []PendientesPrioridad) *CargaDespacho {
+	carga := &CargaDespacho{Fecha: ahora, PorPrioridad: make([]CargaPrioridad, len(prioridadesCarga))}
+	indice := make(map[PrioridadNotificacion]int, len(prioridadesCarga))
+	for i, prioridad := range prioridadesCarga {
+		carga.PorPrioridad[i].Prioridad = prioridad
+		indice[prioridad] = i
+	}
+
+	for _, pendiente := range pendientes {
+		i, existe := indice[pendiente.Prioridad]
+		if !existe {
+			// El pool despacha como normal las prioridades desconocidas
+			i = indice[PrioridadNormal]
+		}
+		carga.PorPrioridad[i].Pendientes += pendiente.Pendientes
+		carga.Pendientes += pendiente.Pendientes
+		if pendiente.MasAntigua == nil {
+			continue
+		}
+		espera := max(ahora.Sub(*pendiente.MasAntigua).Seconds(), 0)
+		carga.PorPrioridad[i].AntiguedadSegundos = max(carga.PorPrioridad[i].AntiguedadSegundos, espera)
+		carga.AntiguedadSegundos = max(carga.AntiguedadSegundos, espera)
+	}
+	return carga
+}
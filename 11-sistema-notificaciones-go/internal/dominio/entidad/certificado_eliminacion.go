@@ -0,0 +1,73 @@
+package entidad
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// EstadoEliminacion define los estados de una eliminación de datos personales
+type EstadoEliminacion string
+
+const (
+	EstadoEliminacionEnCurso    EstadoEliminacion = "en_curso"
+	EstadoEliminacionCompletada EstadoEliminacion = "completada"
+	EstadoEliminacionFallida    EstadoEliminacion = "fallida"
+)
+
+// CertificadoEliminacion deja constancia de la eliminación de los datos personales de un usuario
+// (derecho al olvido). No guarda datos personales: el correo se conserva solo como huella SHA-256
+// para poder acreditar ante una consulta que los datos de esa persona se eliminaron.
+type CertificadoEliminacion struct {
+	ID           uint              `json:"id" gorm:"primaryKey"`
+	InquilinoID  uint              `json:"inquilino_id" gorm:"index"`
+	UsuarioID    uint              `json:"usuario_id" gorm:"not null;index"`
+	HuellaCorreo string            `json:"huella_correo" gorm:"not null;size:64;index"`
+	Estado       EstadoEliminacion `json:"estado" gorm:"not null;size:20"`
+	// Fase es el paso en curso o el último completado
+	Fase           string     `json:"fase" gorm:"size:30"`
+	Notificaciones int64      `json:"notificaciones_anonimizadas"`
+	Intentos       int64      `json:"intentos_anonimizados"`
+	Dispositivos   int64      `json:"dispositivos_eliminados"`
+	Preferencias   int64      `json:"preferencias_eliminadas"`
+	Canales        int64      `json:"canales_desvinculados"`
+	Error          string     `json:"error,omitempty" gorm:"type:text"`
+	FechaSolicitud time.Time  `json:"fecha_solicitud" gorm:"autoCreateTime"`
+	FechaFin       *time.Time `json:"fecha_fin,omitempty"`
+}
+
+// NuevoCertificadoEliminacion crea el certificado en curso para el usuario
+func NuevoCertificadoEliminacion(usuario *Usuario) *CertificadoEliminacion {
+	return &CertificadoEliminacion{
+		InquilinoID:  usuario.InquilinoID,
+		UsuarioID:    usuario.ID,
+		HuellaCorreo: HuellaCorreo(usuario.CorreoElectronico),
+		Estado:       EstadoEliminacionEnCurso,
+	}
+}
+
+// HuellaCorreo retorna el SHA-256 en hexadecimal del correo normalizado
+func HuellaCorreo(correo string) string {
+	suma := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(correo))))
+	return hex.EncodeToString(suma[:])
+}
+
+// EstaEnCurso indica si la eliminación aún no terminó
+func (c *CertificadoEliminacion) EstaEnCurso() bool {
+	return c.Estado == EstadoEliminacionEnCurso
+}
+
+// Completar marca la eliminación como completada
+func (c *CertificadoEliminacion) Completar(ahora time.Time) {
+	c.Estado = EstadoEliminacionCompletada
+	c.Error = ""
+	c.FechaFin = &ahora
+}
+
+// Fallar marca la eliminación como fallida; puede volver a solicitarse
+func (c *CertificadoEliminacion) Fallar(err error, ahora time.Time) {
+	c.Estado = EstadoEliminacionFallida
+	c.Error = err.Error()
+	c.FechaFin = &ahora
+}
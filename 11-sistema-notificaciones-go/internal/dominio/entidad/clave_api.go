@@ -0,0 +1,115 @@
+package entidad
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// RolClaveAPI define el alcance administrativo de una clave de API
+type RolClaveAPI string
+
+const (
+	// RolAdminPlataforma administra todos los inquilinos y la configuración global
+	RolAdminPlataforma RolClaveAPI = "admin_plataforma"
+	// RolAdminInquilino administra solo los recursos de su inquilino
+	RolAdminInquilino RolClaveAPI = "admin_inquilino"
+)
+
+// ClaveAPI autentica llamadas a la API. Se entrega una única vez como "prefijo.secreto";
+// solo se persiste el hash, el prefijo permite buscarla sin recorrer la tabla.
+type ClaveAPI struct {
+	ID              uint        `json:"id" gorm:"primaryKey"`
+	Nombre          string      `json:"nombre" gorm:"not null;size:100"`
+	Prefijo         string      `json:"prefijo" gorm:"uniqueIndex;not null;size:16"`
+	HashSecreto     string      `json:"-" gorm:"not null;size:64"`
+	Rol             RolClaveAPI `json:"rol" gorm:"not null;size:50"`
+	InquilinoID     uint        `json:"inquilino_id" gorm:"index"`
+	FechaCreacion   time.Time   `json:"fecha_creacion" gorm:"autoCreateTime"`
+	FechaRevocacion *time.Time  `json:"fecha_revocacion,omitempty"`
+}
+
+// NuevaClaveAPI genera una clave y retorna junto a ella el valor completo a entregar al cliente
+func NuevaClaveAPI(nombre string, rol RolClaveAPI, inquilinoID uint) (*ClaveAPI, string, error) {
+	prefijo, err := aleatorioHex(8)
+	if err != nil {
+		return nil, "", err
+	}
+	secreto, err := aleatorioHex(32)
+	if err != nil {
+		return nil, "", err
+	}
+
+	clave := &ClaveAPI{
+		Nombre:      nombre,
+		Prefijo:     prefijo,
+		HashSecreto: hashSecreto(secreto),
+		Rol:         rol,
+		InquilinoID: inquilinoID,
+	}
+	return clave, prefijo + "." + secreto, nil
+}
+
+// Validar valida la clave
+func (c *ClaveAPI) Validar() error {
+	if c.Nombre == "" {
+		return NewErrorValidacion("Nombre es requerido")
+	}
+	switch c.Rol {
+	case RolAdminPlataforma:
+		if c.InquilinoID != 0 {
+			return NewErrorValidacion("Una clave de plataforma no puede estar ligada a un inquilino")
+		}
+	case RolAdminInquilino:
+		if c.InquilinoID == 0 {
+			return NewErrorValidacion("Una clave de inquilino requiere InquilinoID")
+		}
+	default:
+		return NewErrorValidacion("Rol de clave inválido")
+	}
+	return nil
+}
+
+// Verificar compara el secreto recibido con el hash en tiempo constante
+func (c *ClaveAPI) Verificar(secreto string) bool {
+	return subtle.ConstantTimeCompare([]byte(hashSecreto(secreto)), []byte(c.HashSecreto)) == 1
+}
+
+// EstaActiva indica si la clave no fue revocada
+func (c *ClaveAPI) EstaActiva() bool {
+	return c.FechaRevocacion == nil
+}
+
+// Revocar invalida la clave de forma permanente
+func (c *ClaveAPI) Revocar(ahora time.Time) {
+	if c.FechaRevocacion == nil {
+		c.FechaRevocacion = &ahora
+	}
+}
+
+// EsAdminPlataforma indica si la clave administra todos los inquilinos
+func (c *ClaveAPI) EsAdminPlataforma() bool {
+	return c.Rol == RolAdminPlataforma
+}
+
+// SepararClaveAPI divide el valor "prefijo.secreto" recibido en la cabecera
+func SepararClaveAPI(valor string) (prefijo, secreto string, ok bool) {
+	prefijo, secreto, ok = strings.Cut(valor, ".")
+	return prefijo, secreto, ok && prefijo != "" && secreto != ""
+}
+
+func hashSecreto(secreto string) string {
+	suma := sha256.Sum256([]byte(secreto))
+	return hex.EncodeToString(suma[:])
+}
+
+func aleatorioHex(bytes int) (string, error) {
+	datos := make([]byte, bytes/2)
+	if _, err := rand.Read(datos); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(datos), nil
+}
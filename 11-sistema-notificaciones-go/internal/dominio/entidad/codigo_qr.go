@@ -0,0 +1,28 @@
+package entidad
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// ContenidoQR retorna el texto a codificar en el código QR de la notificación: el metadato
+// indicado si es un texto o un número no vacío, p. ej. el código de una entrada
+func (n *Notificacion) ContenidoQR(campo string) (string, bool) {
+	if campo == "" {
+		return "", false
+	}
+	switch valor := n.Metadatos[campo].(type) {
+	case string:
+		return valor, valor != ""
+	case float64:
+		return strconv.FormatFloat(valor, 'f', -1, 64), true
+	case json.Number:
+		return valor.String(), true
+	case int:
+		return strconv.Itoa(valor), true
+	case int64:
+		return strconv.FormatInt(valor, 10), true
+	default:
+		return "", false
+	}
+}
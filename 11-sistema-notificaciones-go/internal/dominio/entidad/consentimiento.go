@@ -0,0 +1,70 @@
+package entidad
+
+import "time"
+
+// PropositoConsentimiento define para qué se usa el dato de contacto del usuario
+type PropositoConsentimiento string
+
+const (
+	// PropositoMarketing cubre comunicaciones comerciales y promociones
+	PropositoMarketing PropositoConsentimiento = "marketing"
+)
+
+// Consentimiento registra una decisión explícita del usuario sobre un propósito, para un canal
+// concreto o para todos. Es un historial: otorgar o revocar agrega un registro y rige el más reciente.
+type Consentimiento struct {
+	ID          uint                    `json:"id" gorm:"primaryKey"`
+	InquilinoID uint                    `json:"inquilino_id" gorm:"index"`
+	UsuarioID   uint                    `json:"usuario_id" gorm:"not null;index:idx_consentimiento_usuario_proposito,priority:1"`
+	Proposito   PropositoConsentimiento `json:"proposito" gorm:"not null;size:50;index:idx_consentimiento_usuario_proposito,priority:2"`
+	// CanalID es 0 cuando la decisión aplica a todos los canales del propósito
+	CanalID  uint `json:"canal_id" gorm:"index:idx_consentimiento_usuario_proposito,priority:3"`
+	Otorgado bool `json:"otorgado"`
+	// Fuente indica dónde se tomó la decisión (formulario de registro, app, soporte...)
+	Fuente string `json:"fuente" gorm:"not null;size:100"`
+	// VersionTexto identifica el texto legal que se le mostró al usuario
+	VersionTexto string    `json:"version_texto" gorm:"size:50"`
+	Fecha        time.Time `json:"fecha" gorm:"not null"`
+}
+
+// NuevoConsentimiento crea el registro de una decisión del usuario
+func NuevoConsentimiento(usuario *Usuario, proposito PropositoConsentimiento, canalID uint, otorgado bool, fuente, versionTexto string, fecha time.Time) *Consentimiento {
+	return &Consentimiento{
+		InquilinoID:  usuario.InquilinoID,
+		UsuarioID:    usuario.ID,
+		Proposito:    proposito,
+		CanalID:      canalID,
+		Otorgado:     otorgado,
+		Fuente:       fuente,
+		VersionTexto: versionTexto,
+		Fecha:        fecha,
+	}
+}
+
+// Validar valida el consentimiento
+func (c *Consentimiento) Validar() error {
+	if c.UsuarioID == 0 {
+		return NewErrorValidacion("UsuarioID es requerido")
+	}
+	if c.Proposito != PropositoMarketing {
+		return NewErrorValidacion("Propósito de consentimiento inválido")
+	}
+	if c.Fuente == "" {
+		return NewErrorValidacion("Fuente es requerida")
+	}
+	// Otorgar sin saber qué texto se aceptó no sirve como evidencia
+	if c.Otorgado && c.VersionTexto == "" {
+		return NewErrorValidacion("VersionTexto es requerida para otorgar el consentimiento")
+	}
+	return nil
+}
+
+// PropositoConsentimiento retorna el propósito que exige consentimiento para enviar por
+// canales de este tipo; false si el tipo no lo requiere
+func (t TipoCanal) PropositoConsentimiento() (PropositoConsentimiento, bool) {
+	switch t {
+	case TipoCanalMarketing, TipoCanalPromociones:
+		return PropositoMarketing, true
+	}
+	return "", false
+}
@@ -0,0 +1,95 @@
+package entidad
+
+import (
+	"strings"
+	"time"
+)
+
+// Longitud de los segmentos SMS según la codificación del mensaje
+const (
+	caracteresSegmentoGSM          = 160
+	caracteresSegmentoGSMMultiple  = 153
+	caracteresSegmentoUCS2         = 70
+	caracteresSegmentoUCS2Multiple = 67
+)
+
+// alfabetoGSM contiene los caracteres del alfabeto GSM 03.38 básico
+const alfabetoGSM = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞ ÆæßÉ!\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+
+// extensionGSM contiene los caracteres que en GSM ocupan dos posiciones (escape + carácter)
+const extensionGSM = "^{}\\[~]|€\f"
+
+// RegistroConsumo mide un envío exitoso para facturación. Unidades es la cantidad facturable:
+// segmentos para SMS, 1 para el resto de los tipos.
+type RegistroConsumo struct {
+	ID             uint             `json:"id" gorm:"primaryKey"`
+	InquilinoID    uint             `json:"inquilino_id" gorm:"not null;index:idx_consumo_inquilino_fecha"`
+	NotificacionID uint             `json:"notificacion_id" gorm:"not null;uniqueIndex"`
+	Tipo           TipoNotificacion `json:"tipo" gorm:"not null;size:50"`
+	Proveedor      string           `json:"proveedor" gorm:"not null;size:50"`
+	Unidades       int              `json:"unidades" gorm:"not null;default:1"`
+	Fecha          time.Time        `json:"fecha" gorm:"not null;index:idx_consumo_inquilino_fecha"`
+}
+
+// NuevoRegistroConsumo mide el envío de una notificación por un proveedor
+func NuevoRegistroConsumo(notificacion *Notificacion, proveedor string, fecha time.Time) *RegistroConsumo {
+	unidades := 1
+	if notificacion.Tipo == TipoSMS {
+		unidades = SegmentosSMS(notificacion.Mensaje)
+	}
+	return &RegistroConsumo{
+		InquilinoID:    notificacion.InquilinoID,
+		NotificacionID: notificacion.ID,
+		Tipo:           notificacion.Tipo,
+		Proveedor:      proveedor,
+		Unidades:       unidades,
+		Fecha:          fecha,
+	}
+}
+
+// LineaFacturacion agrega el consumo de un periodo por inquilino, tipo y proveedor
+type LineaFacturacion struct {
+	InquilinoID uint             `json:"inquilino_id"`
+	Tipo        TipoNotificacion `json:"tipo"`
+	Proveedor   string           `json:"proveedor"`
+	Envios      int64            `json:"envios"`
+	Unidades    int64            `json:"unidades"`
+}
+
+// SegmentosSMS calcula los segmentos que ocupa un mensaje: GSM-7 si todos sus caracteres
+// pertenecen al alfabeto GSM, UCS-2 en caso contrario
+func SegmentosSMS(mensaje string) int {
+	longitud, gsm := 0, true
+	for _, caracter := range mensaje {
+		switch {
+		case strings.ContainsRune(alfabetoGSM, caracter):
+			longitud++
+		case strings.ContainsRune(extensionGSM, caracter):
+			longitud += 2
+		default:
+			gsm = false
+		}
+	}
+
+	if !gsm {
+		longitud = 0
+		for _, caracter := range mensaje {
+			// Fuera del plano básico el carácter ocupa un par sustituto
+			if caracter > 0xFFFF {
+				longitud += 2
+			} else {
+				longitud++
+			}
+		}
+		return segmentos(longitud, caracteresSegmentoUCS2, caracteresSegmentoUCS2Multiple)
+	}
+	return segmentos(longitud, caracteresSegmentoGSM, caracteresSegmentoGSMMultiple)
+}
+
+func segmentos(longitud, simple, multiple int) int {
+	if longitud <= simple {
+		return 1
+	}
+	return (longitud + multiple - 1) / multiple
+}
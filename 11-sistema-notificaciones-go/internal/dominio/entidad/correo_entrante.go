@@ -0,0 +1,34 @@
+package entidad
+
+import "time"
+
+// CorreoEntrante es un correo recibido en el buzón de respuestas, ya sin el texto citado
+type CorreoEntrante struct {
+	MessageID string
+	Remitente string
+	Asunto    string
+	// Texto es lo que escribió quien responde, sin la cita del mensaje original ni la firma
+	Texto string
+	// Automatico indica una respuesta automática, p. ej. de ausencia, que no cuenta como lectura
+	Automatico bool
+	// Destinatarios son las direcciones de To, Cc y los encabezados de entrega; una de ellas
+	// lleva el token de la notificación respondida
+	Destinatarios []string
+}
+
+// RespuestaCorreo es el evento que recibe el webhook del canal cuando un destinatario responde
+// por correo una notificación
+type RespuestaCorreo struct {
+	Evento         string    `json:"evento"`
+	NotificacionID uint      `json:"notificacion_id"`
+	InquilinoID    uint      `json:"inquilino_id"`
+	UsuarioID      uint      `json:"usuario_id"`
+	CanalID        uint      `json:"canal_id"`
+	MessageID      string    `json:"message_id,omitempty"`
+	Remitente      string    `json:"remitente"`
+	Asunto         string    `json:"asunto,omitempty"`
+	Texto          string    `json:"texto"`
+	FechaRecepcion time.Time `json:"fecha_recepcion"`
+	// Metadatos permiten al canal correlacionar la respuesta, p. ej. con el ID de su ticket
+	Metadatos map[string]interface{} `json:"metadatos,omitempty"`
+}
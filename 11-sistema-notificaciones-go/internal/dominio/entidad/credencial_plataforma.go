@@ -0,0 +1,43 @@
+package entidad
+
+import (
+	"regexp"
+	"time"
+)
+
+// nombreCredencialValido admite nombres de juego como "principal" o "2026-10"
+var nombreCredencialValido = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,99}$`)
+
+// CredencialPlataforma es un juego de credenciales de la plataforma para un proveedor, guardado
+// cifrado. Un proveedor puede tener varios juegos pero solo uno activo, que reemplaza campo a
+// campo a los de la configuración; rotar es activar otro juego, sin reiniciar el servidor.
+// Los datos se serializan cifrados (para el cache); no deben exponerse en respuestas de la API.
+type CredencialPlataforma struct {
+	ID                 uint       `json:"id" gorm:"primaryKey"`
+	Proveedor          string     `json:"proveedor" gorm:"not null;size:50;uniqueIndex:idx_credencial_plataforma_nombre"`
+	Nombre             string     `json:"nombre" gorm:"not null;size:100;uniqueIndex:idx_credencial_plataforma_nombre"`
+	DatosCifrados      []byte     `json:"datos_cifrados" gorm:"not null"`
+	Activa             bool       `json:"activa" gorm:"not null;default:false"`
+	FechaActivacion    *time.Time `json:"fecha_activacion"`
+	FechaCreacion      time.Time  `json:"fecha_creacion" gorm:"autoCreateTime"`
+	FechaActualizacion time.Time  `json:"fecha_actualizacion" gorm:"autoUpdateTime"`
+}
+
+// TableName fija el nombre de la tabla
+func (CredencialPlataforma) TableName() string {
+	return "credenciales_plataforma"
+}
+
+// Validar valida el juego de credenciales
+func (c *CredencialPlataforma) Validar() error {
+	if c.Proveedor == "" {
+		return NewErrorValidacion("Proveedor es requerido")
+	}
+	if !nombreCredencialValido.MatchString(c.Nombre) {
+		return NewErrorValidacion("El nombre debe tener minúsculas, dígitos, '.', '_' o '-' (hasta 100)")
+	}
+	if len(c.DatosCifrados) == 0 {
+		return NewErrorValidacion("Las credenciales son requeridas")
+	}
+	return nil
+}
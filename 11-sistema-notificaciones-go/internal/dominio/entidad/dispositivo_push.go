@@ -0,0 +1,31 @@
+package entidad
+
+import "time"
+
+// PlataformaDispositivo define la plataforma de un token push
+type PlataformaDispositivo string
+
+const (
+	PlataformaAndroid PlataformaDispositivo = "android"
+	PlataformaIOS     PlataformaDispositivo = "ios"
+	PlataformaWeb     PlataformaDispositivo = "web"
+)
+
+// DispositivoPush es un token de notificaciones push registrado por un usuario. En la
+// plataforma web el token es el endpoint de la PushSubscription del navegador.
+type DispositivoPush struct {
+	ID         uint                  `json:"id" gorm:"primaryKey"`
+	UsuarioID  uint                  `json:"usuario_id" gorm:"not null;index"`
+	Token      string                `json:"token" gorm:"not null;size:512;uniqueIndex"`
+	Plataforma PlataformaDispositivo `json:"plataforma" gorm:"not null;size:20"`
+	// ClaveP256dh y ClaveAuth (base64url) cifran los mensajes Web Push para el navegador
+	ClaveP256dh        string    `json:"p256dh,omitempty" gorm:"size:128"`
+	ClaveAuth          string    `json:"-" gorm:"size:64"`
+	FechaCreacion      time.Time `json:"fecha_creacion" gorm:"autoCreateTime"`
+	FechaActualizacion time.Time `json:"fecha_actualizacion" gorm:"autoUpdateTime"`
+}
+
+// EsWebPush indica si es una suscripción de navegador con sus claves de cifrado
+func (d *DispositivoPush) EsWebPush() bool {
+	return d.Plataforma == PlataformaWeb && d.ClaveP256dh != "" && d.ClaveAuth != ""
+}
@@ -0,0 +1,53 @@
+package entidad
+
+import (
+	"crypto/rand"
+	"time"
+)
+
+// alfabetoSlug son los caracteres del slug: seguros en una URL y en el alfabeto GSM de los SMS
+const alfabetoSlug = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// EnlaceCorto reemplaza una URL larga en un SMS por una de pocos caracteres que redirige a ella.
+// Vive en la base principal: la redirección la resuelve sin saber a qué inquilino pertenece.
+type EnlaceCorto struct {
+	ID             uint   `json:"id" gorm:"primaryKey"`
+	InquilinoID    uint   `json:"inquilino_id" gorm:"not null;index"`
+	NotificacionID uint   `json:"notificacion_id" gorm:"not null;index"`
+	Slug           string `json:"slug" gorm:"not null;size:16;uniqueIndex"`
+	URL            string `json:"url" gorm:"not null;size:2048"`
+	// Clics cuenta las redirecciones, incluidas las de quien abre el enlace varias veces
+	Clics            int64      `json:"clics" gorm:"not null;default:0"`
+	FechaPrimerClic  *time.Time `json:"fecha_primer_clic,omitempty"`
+	FechaUltimoClic  *time.Time `json:"fecha_ultimo_clic,omitempty"`
+	FechaCreacion    time.Time  `json:"fecha_creacion" gorm:"autoCreateTime"`
+	FechaVencimiento time.Time  `json:"fecha_vencimiento" gorm:"not null"`
+}
+
+// TableName fija el nombre de la tabla de enlaces cortos
+func (EnlaceCorto) TableName() string {
+	return "enlaces_cortos"
+}
+
+// Vencido indica si el enlace ya no redirige
+func (e *EnlaceCorto) Vencido(ahora time.Time) bool {
+	return !ahora.Before(e.FechaVencimiento)
+}
+
+// NuevoSlugEnlace genera un slug aleatorio de la longitud indicada. Descarta los bytes que
+// sesgarían la distribución, así cada carácter es igual de probable.
+func NuevoSlugEnlace(longitud int) (string, error) {
+	slug := make([]byte, 0, longitud)
+	datos := make([]byte, longitud*2)
+	for len(slug) < longitud {
+		if _, err := rand.Read(datos); err != nil {
+			return "", err
+		}
+		for _, b := range datos {
+			if int(b) < 256-256%len(alfabetoSlug) && len(slug) < longitud {
+				slug = append(slug, alfabetoSlug[int(b)%len(alfabetoSlug)])
+			}
+		}
+	}
+	return string(slug), nil
+}
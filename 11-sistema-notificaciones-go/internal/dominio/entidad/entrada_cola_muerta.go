@@ -0,0 +1,49 @@
+package entidad
+
+import (
+	"strings"
+	"time"
+)
+
+// EntradaColaMuerta registra una notificación que agotó sus intentos de envío para que un
+// operador la revise y, si corresponde, la reintente. No copia el contenido de la notificación,
+// que puede tener datos personales y sigue en su tabla: la entrada apunta a ella.
+type EntradaColaMuerta struct {
+	ID             uint                  `json:"id" gorm:"primaryKey"`
+	InquilinoID    uint                  `json:"inquilino_id" gorm:"uniqueIndex:idx_cola_muerta_notificacion"`
+	NotificacionID uint                  `json:"notificacion_id" gorm:"not null;uniqueIndex:idx_cola_muerta_notificacion"`
+	UsuarioID      uint                  `json:"usuario_id"`
+	Tipo           TipoNotificacion      `json:"tipo" gorm:"not null;size:20;index"`
+	Prioridad      PrioridadNotificacion `json:"prioridad" gorm:"size:20"`
+	Intentos       int                   `json:"intentos"`
+	// UltimoError es el error del último intento, recortado
+	UltimoError string    `json:"ultimo_error" gorm:"type:text"`
+	Fecha       time.Time `json:"fecha" gorm:"not null;index"`
+}
+
+// maxLongitudUltimoError acota el error guardado; los de algunos proveedores traen el cuerpo entero
+const maxLongitudUltimoError = 1000
+
+// TableName fija el nombre de la tabla
+func (EntradaColaMuerta) TableName() string {
+	return "cola_muerta"
+}
+
+// NuevaEntradaColaMuerta crea la entrada de una notificación que falló sin intentos restantes
+func NuevaEntradaColaMuerta(notificacion *Notificacion, errEnvio error, ahora time.Time) *EntradaColaMuerta {
+	ultimoError := errEnvio.Error()
+	if len(ultimoError) > maxLongitudUltimoError {
+		// El corte puede partir un carácter multibyte: se descarta el resto
+		ultimoError = strings.ToValidUTF8(ultimoError[:maxLongitudUltimoError], "")
+	}
+	return &EntradaColaMuerta{
+		InquilinoID:    notificacion.InquilinoID,
+		NotificacionID: notificacion.ID,
+		UsuarioID:      notificacion.UsuarioID,
+		Tipo:           notificacion.Tipo,
+		Prioridad:      notificacion.Prioridad,
+		Intentos:       notificacion.IntentosEnvio,
+		UltimoError:    ultimoError,
+		Fecha:          ahora,
+	}
+}
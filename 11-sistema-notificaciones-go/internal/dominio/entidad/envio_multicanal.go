@@ -0,0 +1,143 @@
+package entidad
+
+import (
+	"fmt"
+	"time"
+)
+
+// EstadoEnvio define el estado global de un envío multicanal
+type EstadoEnvio string
+
+const (
+	EstadoEnvioEnCurso    EstadoEnvio = "en_curso"
+	EstadoEnvioCompletado EstadoEnvio = "completado"
+	EstadoEnvioParcial    EstadoEnvio = "parcial"
+	EstadoEnvioFallido    EstadoEnvio = "fallido"
+)
+
+// EstadoPaso define el estado de un canal dentro de un envío multicanal
+type EstadoPaso string
+
+const (
+	EstadoPasoPendiente EstadoPaso = "pendiente"
+	EstadoPasoEnviado   EstadoPaso = "enviado"
+	EstadoPasoFallido   EstadoPaso = "fallido"
+	// EstadoPasoOmitido indica que el paso se compensó (p. ej. el usuario ya leyó otro canal)
+	EstadoPasoOmitido EstadoPaso = "omitido"
+)
+
+// PasoEnvio es la entrega de un envío multicanal por un tipo de notificación concreto
+type PasoEnvio struct {
+	ID      uint             `json:"id" gorm:"primaryKey"`
+	EnvioID uint             `json:"envio_id" gorm:"not null;uniqueIndex:idx_paso_envio_tipo"`
+	Tipo    TipoNotificacion `json:"tipo" gorm:"not null;size:50;uniqueIndex:idx_paso_envio_tipo"`
+	Orden   int              `json:"orden" gorm:"not null"`
+	// RetrasoSegundos difiere el paso respecto de la creación del envío
+	RetrasoSegundos int `json:"retraso_segundos" gorm:"default:0"`
+	// OmitirSiLeida cancela el paso si otra notificación del envío ya fue leída
+	OmitirSiLeida      bool       `json:"omitir_si_leida" gorm:"default:false"`
+	Estado             EstadoPaso `json:"estado" gorm:"not null;size:20;default:'pendiente'"`
+	FechaActualizacion time.Time  `json:"fecha_actualizacion" gorm:"autoUpdateTime"`
+}
+
+// EnvioMultiCanal agrupa las notificaciones de un mismo mensaje lógico por varios canales
+type EnvioMultiCanal struct {
+	ID                 uint        `json:"id" gorm:"primaryKey"`
+	UsuarioID          uint        `json:"usuario_id" gorm:"not null;index"`
+	Estado             EstadoEnvio `json:"estado" gorm:"not null;size:20;default:'en_curso'"`
+	Pasos              []PasoEnvio `json:"pasos" gorm:"foreignKey:EnvioID"`
+	FechaCreacion      time.Time   `json:"fecha_creacion" gorm:"autoCreateTime"`
+	FechaActualizacion time.Time   `json:"fecha_actualizacion" gorm:"autoUpdateTime"`
+}
+
+// NuevoEnvioMultiCanal crea una nueva instancia de EnvioMultiCanal
+func NuevoEnvioMultiCanal(usuarioID uint, pasos []PasoEnvio) *EnvioMultiCanal {
+	for i := range pasos {
+		pasos[i].Orden = i + 1
+		pasos[i].Estado = EstadoPasoPendiente
+	}
+	return &EnvioMultiCanal{
+		UsuarioID: usuarioID,
+		Estado:    EstadoEnvioEnCurso,
+		Pasos:     pasos,
+	}
+}
+
+// Validar valida el envío multicanal
+func (e *EnvioMultiCanal) Validar() error {
+	if e.UsuarioID == 0 {
+		return NewErrorValidacion("UsuarioID es requerido")
+	}
+	if len(e.Pasos) == 0 {
+		return NewErrorValidacion("Se requiere al menos un canal")
+	}
+	vistos := make(map[TipoNotificacion]bool)
+	for _, paso := range e.Pasos {
+		if paso.Tipo == "" {
+			return NewErrorValidacion("Tipo es requerido en cada canal")
+		}
+		if vistos[paso.Tipo] {
+			return NewErrorValidacion(fmt.Sprintf("Tipo duplicado en el envío: %s", paso.Tipo))
+		}
+		if paso.RetrasoSegundos < 0 {
+			return NewErrorValidacion("El retraso no puede ser negativo")
+		}
+		vistos[paso.Tipo] = true
+	}
+	return nil
+}
+
+// Paso retorna el paso del tipo indicado o nil si el envío no lo incluye
+func (e *EnvioMultiCanal) Paso(tipo TipoNotificacion) *PasoEnvio {
+	for i := range e.Pasos {
+		if e.Pasos[i].Tipo == tipo {
+			return &e.Pasos[i]
+		}
+	}
+	return nil
+}
+
+// DebeOmitir indica si el paso debe compensarse dado el estado actual de las notificaciones del envío
+func (e *EnvioMultiCanal) DebeOmitir(paso *PasoEnvio, notificaciones []Notificacion) bool {
+	if !paso.OmitirSiLeida {
+		return false
+	}
+	for _, notificacion := range notificaciones {
+		if notificacion.Tipo != paso.Tipo && notificacion.Estado == EstadoLeida {
+			return true
+		}
+	}
+	return false
+}
+
+// RegistrarResultado actualiza el estado de un paso y recalcula el estado global
+func (e *EnvioMultiCanal) RegistrarResultado(paso *PasoEnvio, estado EstadoPaso) {
+	paso.Estado = estado
+	e.Estado = e.calcularEstado()
+}
+
+// calcularEstado deriva el estado global: en curso mientras quede un paso pendiente,
+// completado si ninguno falló, fallido si ninguno se entregó y parcial en otro caso
+func (e *EnvioMultiCanal) calcularEstado() EstadoEnvio {
+	var enviados, fallidos, omitidos int
+	for _, paso := range e.Pasos {
+		switch paso.Estado {
+		case EstadoPasoPendiente:
+			return EstadoEnvioEnCurso
+		case EstadoPasoEnviado:
+			enviados++
+		case EstadoPasoFallido:
+			fallidos++
+		case EstadoPasoOmitido:
+			omitidos++
+		}
+	}
+	switch {
+	case fallidos == 0:
+		return EstadoEnvioCompletado
+	case enviados == 0 && omitidos == 0:
+		return EstadoEnvioFallido
+	default:
+		return EstadoEnvioParcial
+	}
+}
@@ -1,6 +1,9 @@
 package entidad
 
-import "errors"
+import (
+	"errors"
+	"strings"
+)
 
 // ErrorValidacion representa un error de validación
 type ErrorValidacion struct {
@@ -30,6 +33,26 @@ func NewErrorDominio(mensaje string) *ErrorDominio {
 	return &ErrorDominio{Mensaje: mensaje}
 }
 
+// CampoInvalido es un campo de los metadatos que no cumple el esquema del canal
+type CampoInvalido struct {
+	Campo   string `json:"campo"`
+	Regla   string `json:"regla"`
+	Mensaje string `json:"mensaje"`
+}
+
+// ErrorMetadatos reúne los campos de los metadatos que no cumplen el esquema del canal
+type ErrorMetadatos struct {
+	Campos []CampoInvalido
+}
+
+func (e *ErrorMetadatos) Error() string {
+	detalles := make([]string, len(e.Campos))
+	for i, campo := range e.Campos {
+		detalles[i] = campo.Campo + ": " + campo.Mensaje
+	}
+	return "los metadatos no cumplen el esquema del canal: " + strings.Join(detalles, "; ")
+}
+
 // Errores comunes del dominio
 var (
 	ErrUsuarioNoEncontrado     = errors.New("usuario no encontrado")
@@ -40,4 +63,114 @@ var (
 	ErrNotificacionYaEnviada   = errors.New("notificación ya enviada")
 	ErrNotificacionCancelada   = errors.New("notificación cancelada")
 	ErrMaxIntentosExcedidos    = errors.New("máximo de intentos excedido")
+	ErrConflictoVersion        = errors.New("la notificación fue modificada por otro proceso")
+	ErrEnvioNoEncontrado       = errors.New("envío no encontrado")
+	ErrInquilinoNoEncontrado   = errors.New("inquilino no encontrado")
+	ErrCuotaMensualExcedida    = errors.New("cuota mensual de envíos excedida")
+	ErrLimiteTasaExcedido      = errors.New("límite de envíos por segundo excedido")
+	ErrCifradoNoConfigurado    = errors.New("el cifrado de credenciales no está configurado")
+	ErrAccesoDenegado          = errors.New("el recurso pertenece a otro inquilino")
+	ErrClaveAPINoEncontrada    = errors.New("clave de API no encontrada")
+	ErrPlantillaNoEncontrada   = errors.New("plantilla no encontrada")
+	ErrMarcaNoEncontrada       = errors.New("el inquilino no tiene marca configurada")
+	ErrExportacionNoEncontrada = errors.New("exportación no encontrada")
+	ErrExportacionEnCurso      = errors.New("la exportación aún no terminó")
+	ErrCertificadoNoEncontrado = errors.New("no hay eliminación de datos personales para el usuario")
+	ErrRetencionNoEncontrada   = errors.New("política de retención no encontrada")
+	ErrSinSecretoAnonimizacion = errors.New("la anonimización no está configurada")
+	ErrRegionNoConfigurada     = errors.New("la región de residencia de datos no está configurada")
+	ErrSinBackendRegional      = errors.New("el proveedor no tiene backend en la región del inquilino")
+	ErrSuscripcionNoEncontrada = errors.New("suscripción no encontrada")
+	ErrEnlaceInvalido          = errors.New("el enlace de confirmación es inválido o venció")
+	ErrDobleOptInNoConfigurado = errors.New("la confirmación de suscripciones no está configurada")
+	ErrSupresionNoEncontrada   = errors.New("entrada de la lista de supresión no encontrada")
+)
+
+// Errores de las guardias y sus escalamientos
+var (
+	ErrSinPoliticaEscalamiento  = errors.New("el canal no tiene política de escalamiento")
+	ErrEscalamientoNoEncontrado = errors.New("escalamiento no encontrado")
+	ErrEscalamientoFinalizado   = errors.New("el escalamiento ya fue reconocido o agotó sus pasos")
+	ErrSinRotacionGuardia       = errors.New("el canal no tiene rotación de guardia")
+	ErrReemplazoNoEncontrado    = errors.New("reemplazo de guardia no encontrado")
+)
+
+// Errores del flujo de aprobación de difusiones
+var (
+	ErrBorradorDifusionNoEncontrado = errors.New("borrador de difusión no encontrado")
+	ErrDifusionRequiereAprobacion   = errors.New("la difusión al canal requiere aprobación")
+	ErrActorRequerido               = errors.New("se requiere el encabezado X-Actor-ID")
+)
+
+// ErrLimiteSolicitudesExcedido indica que el cliente agotó las solicitudes a la API de la ventana
+var ErrLimiteSolicitudesExcedido = errors.New("límite de solicitudes a la API excedido")
+
+// Errores de las suscripciones Web Push
+var (
+	ErrDispositivoNoEncontrado = errors.New("suscripción push no encontrada")
+	ErrWebPushNoConfigurado    = errors.New("el envío Web Push no está configurado")
+)
+
+// Errores de la membresía de los canales privados
+var (
+	ErrCanalPrivado            = errors.New("el canal es privado: se ingresa por invitación o con una solicitud aprobada")
+	ErrRolInsuficiente         = errors.New("el actor no tiene el rol requerido para la acción")
+	ErrEstadoMembresiaInvalido = errors.New("la membresía no está en un estado que admita la acción")
 )
+
+// ErrPublicacionNoPermitida indica que el canal no admite publicaciones del actor
+var ErrPublicacionNoPermitida = errors.New("el canal no admite publicaciones de este usuario")
+
+// ErrSilenciamientoNoEncontrado indica que el usuario no tiene el silenciamiento indicado
+var ErrSilenciamientoNoEncontrado = errors.New("silenciamiento no encontrado")
+
+// ErrReaccionNoPermitida indica que la notificación todavía no llegó al destinatario o ya no está disponible
+var ErrReaccionNoPermitida = errors.New("solo se puede reaccionar a notificaciones entregadas al destinatario")
+
+// ErrRespuestasNoPermitidas indica que la notificación no admite respuestas
+var ErrRespuestasNoPermitidas = errors.New("la notificación no admite respuestas")
+
+// ErrEsquemaSoloLectura indica que el servicio corre sin permiso para alterar el esquema de la base
+var ErrEsquemaSoloLectura = errors.New("el esquema de la base de datos es de solo lectura")
+
+// ErrRespuestaCorreoInvalida indica que el correo recibido no responde a una notificación conocida
+var ErrRespuestaCorreoInvalida = errors.New("el correo no responde a una notificación conocida")
+
+// ErrEnlaceCortoNoEncontrado indica que no existe un enlace corto con ese identificador
+var ErrEnlaceCortoNoEncontrado = errors.New("enlace corto no encontrado")
+
+// ErrEnlaceCortoVencido indica que el enlace corto superó su vigencia y ya no redirige
+var ErrEnlaceCortoVencido = errors.New("el enlace corto venció")
+
+// ErrImagenQRInvalida indica que la URL del código QR es inválida, venció o la notificación no tiene código
+var ErrImagenQRInvalida = errors.New("la URL del código QR es inválida o venció")
+
+// ErrAdjuntoNoEncontrado indica que no existe un adjunto con ese identificador
+var ErrAdjuntoNoEncontrado = errors.New("adjunto no encontrado")
+
+// ErrAdjuntoVinculado indica que el adjunto ya pertenece a una notificación
+var ErrAdjuntoVinculado = errors.New("el adjunto ya pertenece a una notificación")
+
+// ErrArchivoInfectado indica que el análisis antivirus detectó una amenaza en el archivo subido
+var ErrArchivoInfectado = errors.New("el archivo contiene una amenaza")
+
+// ErrFirmaProveedorInvalida indica que una llamada de un proveedor no trae una firma válida
+var ErrFirmaProveedorInvalida = errors.New("la firma del proveedor no es válida")
+
+// ErrCredencialPlataformaNoEncontrada indica que el proveedor no tiene un juego de credenciales con ese nombre
+var ErrCredencialPlataformaNoEncontrada = errors.New("juego de credenciales no encontrado")
+
+// ErrCredencialPlataformaActiva indica que se intentó eliminar el juego de credenciales activo del proveedor
+var ErrCredencialPlataformaActiva = errors.New("el juego de credenciales está activo")
+
+// ErrEntradaColaMuertaNoEncontrada indica que no existe una entrada de la cola de mensajes muertos con ese identificador
+var ErrEntradaColaMuertaNoEncontrada = errors.New("entrada de la cola de mensajes muertos no encontrada")
+
+// ErrActorNoAutenticado indica que la acción requiere un usuario autenticado con su propio token, no solo X-Actor-ID
+var ErrActorNoAutenticado = errors.New("se requiere un usuario autenticado con su token (Authorization: Bearer)")
+
+// ErrEnvioFallido indica que el envío falló y el fallo quedó registrado en la notificación, que lleva sus reintentos
+var ErrEnvioFallido = errors.New("el envío falló")
+
+// ErrNotificacionEnCreacion indica que una notificación idéntica, dentro de la ventana de deduplicación, aún se está creando
+var ErrNotificacionEnCreacion = errors.New("una notificación idéntica se está creando")
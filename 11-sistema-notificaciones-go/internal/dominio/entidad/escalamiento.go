@@ -0,0 +1,163 @@
+package entidad
+
+import (
+	"fmt"
+	"time"
+)
+
+// maximoPasosEscalamiento acota los pasos de una política
+const maximoPasosEscalamiento = 20
+
+// PasoEscalamiento es un aviso de la política: a quién, por qué tipo y cuándo
+type PasoEscalamiento struct {
+	UsuarioID uint             `json:"usuario_id"`
+	Tipo      TipoNotificacion `json:"tipo"`
+	// DemoraMinutos se cuenta desde el inicio del escalamiento, no desde el paso anterior
+	DemoraMinutos int `json:"demora_minutos"`
+}
+
+// PoliticaEscalamiento define a quién se avisa, en orden, cuando se dispara un incidente en un
+// canal de seguridad, p. ej. push a la guardia, a los 5 minutos SMS y a los 10 su responsable.
+// Los pasos se ejecutan hasta que alguien reconoce el incidente.
+type PoliticaEscalamiento struct {
+	ID                 uint               `json:"id" gorm:"primaryKey"`
+	InquilinoID        uint               `json:"inquilino_id" gorm:"index"`
+	CanalID            uint               `json:"canal_id" gorm:"not null;uniqueIndex"`
+	Pasos              []PasoEscalamiento `json:"pasos" gorm:"type:jsonb;serializer:json"`
+	FechaCreacion      time.Time          `json:"fecha_creacion" gorm:"autoCreateTime"`
+	FechaActualizacion time.Time          `json:"fecha_actualizacion" gorm:"autoUpdateTime"`
+}
+
+// Validar valida la política
+func (p *PoliticaEscalamiento) Validar() error {
+	if len(p.Pasos) == 0 {
+		return NewErrorValidacion("Se requiere al menos un paso")
+	}
+	if len(p.Pasos) > maximoPasosEscalamiento {
+		return NewErrorValidacion(fmt.Sprintf("La política admite como máximo %d pasos", maximoPasosEscalamiento))
+	}
+	anterior := 0
+	for i, paso := range p.Pasos {
+		if paso.UsuarioID == 0 || paso.Tipo == "" {
+			return NewErrorValidacion(fmt.Sprintf("El paso %d requiere usuario y tipo", i+1))
+		}
+		if paso.DemoraMinutos < anterior {
+			return NewErrorValidacion("Las demoras de los pasos no pueden disminuir")
+		}
+		anterior = paso.DemoraMinutos
+	}
+	return nil
+}
+
+// Participa indica si el usuario es destinatario de algún paso
+func (p *PoliticaEscalamiento) Participa(usuarioID uint) bool {
+	return participa(p.Pasos, usuarioID)
+}
+
+// EstadoEscalamiento define los estados de un escalamiento
+type EstadoEscalamiento string
+
+const (
+	EstadoEscalamientoActivo     EstadoEscalamiento = "activo"
+	EstadoEscalamientoReconocido EstadoEscalamiento = "reconocido"
+	// EstadoEscalamientoAgotado indica que se avisó a todos los pasos sin reconocimiento
+	EstadoEscalamientoAgotado EstadoEscalamiento = "agotado"
+)
+
+// AvisoEscalamiento registra la notificación enviada por un paso
+type AvisoEscalamiento struct {
+	Paso           int              `json:"paso"`
+	UsuarioID      uint             `json:"usuario_id"`
+	Tipo           TipoNotificacion `json:"tipo"`
+	NotificacionID uint             `json:"notificacion_id,omitempty"`
+	Error          string           `json:"error,omitempty"`
+	Fecha          time.Time        `json:"fecha"`
+}
+
+// Escalamiento es la ejecución de una política para un incidente. Copia los pasos al iniciar,
+// así editar la política no altera los incidentes en curso.
+type Escalamiento struct {
+	ID          uint                   `json:"id" gorm:"primaryKey"`
+	InquilinoID uint                   `json:"inquilino_id" gorm:"index"`
+	CanalID     uint                   `json:"canal_id" gorm:"not null;index"`
+	PoliticaID  uint                   `json:"politica_id" gorm:"not null"`
+	Titulo      string                 `json:"titulo" gorm:"not null;size:255"`
+	Mensaje     string                 `json:"mensaje" gorm:"type:text;not null"`
+	Metadatos   map[string]interface{} `json:"metadatos,omitempty" gorm:"type:jsonb;serializer:json"`
+	Pasos       []PasoEscalamiento     `json:"pasos" gorm:"type:jsonb;serializer:json"`
+	// SiguientePaso es el índice del próximo paso a avisar; igual a len(Pasos) al agotarse
+	SiguientePaso int                `json:"siguiente_paso" gorm:"not null;default:0"`
+	Estado        EstadoEscalamiento `json:"estado" gorm:"not null;size:20;index:idx_escalamiento_estado_aviso,priority:1"`
+	// ProximoAviso es cuándo vence el siguiente paso; nil cuando el escalamiento terminó
+	ProximoAviso        *time.Time          `json:"proximo_aviso" gorm:"index:idx_escalamiento_estado_aviso,priority:2"`
+	Avisos              []AvisoEscalamiento `json:"avisos" gorm:"type:jsonb;serializer:json"`
+	ReconocidoPor       uint                `json:"reconocido_por,omitempty"`
+	FechaReconocimiento *time.Time          `json:"fecha_reconocimiento"`
+	FechaInicio         time.Time           `json:"fecha_inicio"`
+}
+
+// NuevoEscalamiento inicia la ejecución de la política con su primer paso pendiente
+func NuevoEscalamiento(politica *PoliticaEscalamiento, titulo, mensaje string, metadatos map[string]interface{}, ahora time.Time) *Escalamiento {
+	e := &Escalamiento{
+		InquilinoID: politica.InquilinoID,
+		CanalID:     politica.CanalID,
+		PoliticaID:  politica.ID,
+		Titulo:      titulo,
+		Mensaje:     mensaje,
+		Metadatos:   metadatos,
+		Pasos:       append([]PasoEscalamiento(nil), politica.Pasos...),
+		Estado:      EstadoEscalamientoActivo,
+		Avisos:      []AvisoEscalamiento{},
+		FechaInicio: ahora,
+	}
+	e.programarSiguiente()
+	return e
+}
+
+// PasoVencido retorna el paso pendiente si ya corresponde avisarlo
+func (e *Escalamiento) PasoVencido(ahora time.Time) (*PasoEscalamiento, bool) {
+	if e.Estado != EstadoEscalamientoActivo || e.ProximoAviso == nil || e.ProximoAviso.After(ahora) {
+		return nil, false
+	}
+	return &e.Pasos[e.SiguientePaso], true
+}
+
+// Avanzar da por avisado el paso pendiente y programa el siguiente; sin más pasos, se agota
+func (e *Escalamiento) Avanzar() {
+	e.SiguientePaso++
+	e.programarSiguiente()
+}
+
+// Reconocer detiene el escalamiento; solo puede hacerlo un destinatario de sus pasos
+func (e *Escalamiento) Reconocer(usuarioID uint, ahora time.Time) error {
+	if e.Estado != EstadoEscalamientoActivo {
+		return ErrEscalamientoFinalizado
+	}
+	if !participa(e.Pasos, usuarioID) {
+		return NewErrorValidacion("Solo puede reconocer el incidente un destinatario de la política")
+	}
+	e.Estado = EstadoEscalamientoReconocido
+	e.ReconocidoPor = usuarioID
+	e.FechaReconocimiento = &ahora
+	e.ProximoAviso = nil
+	return nil
+}
+
+func (e *Escalamiento) programarSiguiente() {
+	if e.SiguientePaso >= len(e.Pasos) {
+		e.Estado = EstadoEscalamientoAgotado
+		e.ProximoAviso = nil
+		return
+	}
+	proximo := e.FechaInicio.Add(time.Duration(e.Pasos[e.SiguientePaso].DemoraMinutos) * time.Minute)
+	e.ProximoAviso = &proximo
+}
+
+func participa(pasos []PasoEscalamiento, usuarioID uint) bool {
+	for _, paso := range pasos {
+		if paso.UsuarioID == usuarioID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package entidad
+
+// TipoEventoCorreo es lo que un proveedor de email informa sobre un correo enviado
+type TipoEventoCorreo string
+
+const (
+	EventoCorreoEntregado TipoEventoCorreo = "entregado"
+	// EventoCorreoRebotado cubre los rebotes y los correos que el proveedor descartó sin enviar
+	EventoCorreoRebotado TipoEventoCorreo = "rebotado"
+	EventoCorreoAbierto  TipoEventoCorreo = "abierto"
+	// EventoCorreoQueja es un destinatario que marcó el correo como no deseado
+	EventoCorreoQueja TipoEventoCorreo = "queja"
+)
+
+// EventoCorreo es un evento del proveedor de email sobre el correo de una notificación; el
+// proveedor devuelve el inquilino y la notificación que viajaron con el correo
+type EventoCorreo struct {
+	Proveedor      string
+	InquilinoID    uint
+	NotificacionID uint
+	Tipo           TipoEventoCorreo
+	// Motivo es el detalle del proveedor en los rebotes, p. ej. la respuesta del servidor destino
+	Motivo string
+	// Destinatario es la dirección que rebotó de forma permanente o que se quejó; si el
+	// proveedor la informa, se agrega a la lista de supresión del inquilino
+	Destinatario string
+}
@@ -0,0 +1,114 @@
+package entidad
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// ModoAislamiento define dónde se guardan los datos de un inquilino
+type ModoAislamiento string
+
+const (
+	// AislamientoCompartido guarda los datos en las tablas comunes, filtrados por inquilino
+	AislamientoCompartido ModoAislamiento = "compartido"
+	// AislamientoEsquema guarda los datos en un esquema de PostgreSQL propio del inquilino
+	AislamientoEsquema ModoAislamiento = "esquema"
+)
+
+var nombreRegionValido = regexp.MustCompile(`^[a-z][a-z0-9\-]{0,19}$`)
+
+// Inquilino es una organización cliente que comparte la plataforma de notificaciones
+type Inquilino struct {
+	ID          uint            `json:"id" gorm:"primaryKey"`
+	Nombre      string          `json:"nombre" gorm:"not null;size:100"`
+	Activo      bool            `json:"activo" gorm:"default:true"`
+	Aislamiento ModoAislamiento `json:"aislamiento" gorm:"size:20;default:'compartido'"`
+	// Region es la región de residencia de sus datos (p. ej. eu); vacía usa la base principal.
+	// Se fija al aprovisionar: los datos no se mueven entre regiones.
+	Region             string    `json:"region,omitempty" gorm:"size:20"`
+	FechaCreacion      time.Time `json:"fecha_creacion" gorm:"autoCreateTime"`
+	FechaActualizacion time.Time `json:"fecha_actualizacion" gorm:"autoUpdateTime"`
+}
+
+// NuevoInquilino crea un inquilino activo con datos compartidos
+func NuevoInquilino(nombre string) *Inquilino {
+	return &Inquilino{Nombre: nombre, Activo: true, Aislamiento: AislamientoCompartido}
+}
+
+// Validar valida el inquilino
+func (i *Inquilino) Validar() error {
+	if i.Nombre == "" {
+		return NewErrorValidacion("Nombre es requerido")
+	}
+	if i.Aislamiento != AislamientoCompartido && i.Aislamiento != AislamientoEsquema {
+		return NewErrorValidacion("Aislamiento inválido")
+	}
+	if i.Region != "" && !nombreRegionValido.MatchString(i.Region) {
+		return NewErrorValidacion("Region inválida")
+	}
+	return nil
+}
+
+// FueraDeBasePrincipal indica si los datos del inquilino no están en las tablas comunes de la
+// base principal, ya sea por tener esquema propio o por residir en otra región
+func (i *Inquilino) FueraDeBasePrincipal() bool {
+	return i.Esquema() != "" || i.Region != ""
+}
+
+// Esquema retorna el esquema propio del inquilino, o vacío si comparte las tablas comunes
+func (i *Inquilino) Esquema() string {
+	if i.Aislamiento != AislamientoEsquema {
+		return ""
+	}
+	return fmt.Sprintf("inquilino_%d", i.ID)
+}
+
+// CuotaInquilino limita los envíos de un inquilino para un tipo de notificación.
+// Un límite en 0 significa sin límite.
+type CuotaInquilino struct {
+	ID                 uint             `json:"id" gorm:"primaryKey"`
+	InquilinoID        uint             `json:"inquilino_id" gorm:"not null;uniqueIndex:idx_cuota_inquilino_tipo"`
+	Tipo               TipoNotificacion `json:"tipo" gorm:"not null;size:50;uniqueIndex:idx_cuota_inquilino_tipo"`
+	LimiteMensual      int64            `json:"limite_mensual" gorm:"default:0"`
+	LimitePorSegundo   int64            `json:"limite_por_segundo" gorm:"default:0"`
+	FechaActualizacion time.Time        `json:"fecha_actualizacion" gorm:"autoUpdateTime"`
+}
+
+// Validar valida la cuota
+func (c *CuotaInquilino) Validar() error {
+	if c.InquilinoID == 0 {
+		return NewErrorValidacion("InquilinoID es requerido")
+	}
+	if c.Tipo == "" {
+		return NewErrorValidacion("Tipo es requerido")
+	}
+	if c.LimiteMensual < 0 || c.LimitePorSegundo < 0 {
+		return NewErrorValidacion("Los límites no pueden ser negativos")
+	}
+	return nil
+}
+
+// CredencialProveedor guarda cifradas las credenciales propias de un inquilino para un proveedor.
+// Los datos se serializan cifrados (para el cache); no deben exponerse en respuestas de la API.
+type CredencialProveedor struct {
+	ID                 uint      `json:"id" gorm:"primaryKey"`
+	InquilinoID        uint      `json:"inquilino_id" gorm:"not null;uniqueIndex:idx_credencial_inquilino_proveedor"`
+	Proveedor          string    `json:"proveedor" gorm:"not null;size:50;uniqueIndex:idx_credencial_inquilino_proveedor"`
+	DatosCifrados      []byte    `json:"datos_cifrados" gorm:"not null"`
+	FechaActualizacion time.Time `json:"fecha_actualizacion" gorm:"autoUpdateTime"`
+}
+
+// Validar valida la credencial
+func (c *CredencialProveedor) Validar() error {
+	if c.InquilinoID == 0 {
+		return NewErrorValidacion("InquilinoID es requerido")
+	}
+	if c.Proveedor == "" {
+		return NewErrorValidacion("Proveedor es requerido")
+	}
+	if len(c.DatosCifrados) == 0 {
+		return NewErrorValidacion("Las credenciales son requeridas")
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package entidad
+
+import (
+	"fmt"
+	"time"
+)
+
+// EstadoIntento define los estados de un intento de envío
+type EstadoIntento string
+
+const (
+	// EstadoIntentoIniciado se registra antes de llamar al proveedor; si persiste tras
+	// una caída, el resultado del envío es desconocido.
+	EstadoIntentoIniciado EstadoIntento = "iniciado"
+	EstadoIntentoExitoso  EstadoIntento = "exitoso"
+	EstadoIntentoFallido  EstadoIntento = "fallido"
+)
+
+// IntentoEnvio registra cada llamada a un proveedor para una notificación
+type IntentoEnvio struct {
+	ID                uint          `json:"id" gorm:"primaryKey"`
+	NotificacionID    uint          `json:"notificacion_id" gorm:"not null;uniqueIndex:idx_intento_notificacion_numero"`
+	Numero            int           `json:"numero" gorm:"not null;uniqueIndex:idx_intento_notificacion_numero"`
+	TokenIdempotencia string        `json:"token_idempotencia" gorm:"not null;size:100;uniqueIndex"`
+	Estado            EstadoIntento `json:"estado" gorm:"not null;size:20"`
+	Error             string        `json:"error,omitempty" gorm:"type:text"`
+	FechaInicio       time.Time     `json:"fecha_inicio"`
+	FechaFin          *time.Time    `json:"fecha_fin"`
+}
+
+// NuevoIntentoEnvio crea un intento iniciado con un token determinista por (notificación, número)
+func NuevoIntentoEnvio(notificacionID uint, numero int, ahora time.Time) *IntentoEnvio {
+	return &IntentoEnvio{
+		NotificacionID:    notificacionID,
+		Numero:            numero,
+		TokenIdempotencia: fmt.Sprintf("notificacion-%d-intento-%d", notificacionID, numero),
+		Estado:            EstadoIntentoIniciado,
+		FechaInicio:       ahora,
+	}
+}
+
+// Finalizar registra el resultado del intento
+func (i *IntentoEnvio) Finalizar(errEnvio error, ahora time.Time) {
+	i.FechaFin = &ahora
+	if errEnvio != nil {
+		i.Estado = EstadoIntentoFallido
+		i.Error = errEnvio.Error()
+		return
+	}
+	i.Estado = EstadoIntentoExitoso
+}
+
+// ResultadoIncierto indica que el proceso cayó sin registrar el resultado del proveedor
+func (i *IntentoEnvio) ResultadoIncierto() bool {
+	return i.Estado == EstadoIntentoIniciado
+}
@@ -0,0 +1,30 @@
+package entidad
+
+import "time"
+
+// EstadoLimite es el consumo de un límite de tasa después de una solicitud, para informarlo al
+// cliente y que regule su ritmo antes de recibir un 429
+type EstadoLimite struct {
+	Limite    int64
+	Restantes int64
+	// Reinicio es cuándo empieza la próxima ventana del límite
+	Reinicio time.Time
+}
+
+// NuevoEstadoLimite calcula lo que queda del límite tras usados
+func NuevoEstadoLimite(limite, usados int64, reinicio time.Time) EstadoLimite {
+	restantes := limite - usados
+	if restantes < 0 {
+		restantes = 0
+	}
+	return EstadoLimite{Limite: limite, Restantes: restantes, Reinicio: reinicio}
+}
+
+// MasRestrictivo indica si el estado deja menos margen que otro: menos restantes o, con los
+// mismos, un reinicio más lejano
+func (e EstadoLimite) MasRestrictivo(otro EstadoLimite) bool {
+	if e.Restantes != otro.Restantes {
+		return e.Restantes < otro.Restantes
+	}
+	return e.Reinicio.After(otro.Reinicio)
+}
@@ -0,0 +1,48 @@
+package entidad
+
+import (
+	"net/mail"
+	"net/url"
+	"regexp"
+	"time"
+)
+
+var colorHexadecimal = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// MarcaInquilino es la identidad visual del inquilino aplicada al contenido renderizado
+// desde plantillas. Los campos vacíos se omiten al renderizar.
+type MarcaInquilino struct {
+	ID                 uint      `json:"id" gorm:"primaryKey"`
+	InquilinoID        uint      `json:"inquilino_id" gorm:"not null;uniqueIndex"`
+	LogoURL            string    `json:"logo_url" gorm:"size:500"`
+	ColorPrimario      string    `json:"color_primario" gorm:"size:7"`
+	ColorSecundario    string    `json:"color_secundario" gorm:"size:7"`
+	NombreRemitente    string    `json:"nombre_remitente" gorm:"size:100"`
+	CorreoRemitente    string    `json:"correo_remitente" gorm:"size:255"`
+	PiePagina          string    `json:"pie_pagina" gorm:"type:text"`
+	FechaActualizacion time.Time `json:"fecha_actualizacion" gorm:"autoUpdateTime"`
+}
+
+// Validar valida la marca
+func (m *MarcaInquilino) Validar() error {
+	if m.InquilinoID == 0 {
+		return NewErrorValidacion("InquilinoID es requerido")
+	}
+	if m.LogoURL != "" {
+		logo, err := url.Parse(m.LogoURL)
+		if err != nil || (logo.Scheme != "https" && logo.Scheme != "http") || logo.Host == "" {
+			return NewErrorValidacion("LogoURL debe ser una URL http(s)")
+		}
+	}
+	for _, color := range []string{m.ColorPrimario, m.ColorSecundario} {
+		if color != "" && !colorHexadecimal.MatchString(color) {
+			return NewErrorValidacion("Los colores deben tener el formato #RRGGBB")
+		}
+	}
+	if m.CorreoRemitente != "" {
+		if _, err := mail.ParseAddress(m.CorreoRemitente); err != nil {
+			return NewErrorValidacion("CorreoRemitente inválido")
+		}
+	}
+	return nil
+}
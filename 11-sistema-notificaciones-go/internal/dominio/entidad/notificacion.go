@@ -1,6 +1,9 @@
 package entidad
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"time"
 	"gorm.io/gorm"
 )
@@ -14,6 +17,10 @@ const (
 	TipoPush         TipoNotificacion = "push"
 	TipoWebSocket    TipoNotificacion = "websocket"
 	TipoInApp        TipoNotificacion = "in_app"
+	TipoSlack        TipoNotificacion = "slack"
+	TipoTelegram     TipoNotificacion = "telegram"
+	// TipoVoz es una llamada telefónica que lee el mensaje; solo para prioridad crítica
+	TipoVoz          TipoNotificacion = "voz"
 )
 
 // EstadoNotificacion define los estados de una notificación
@@ -26,6 +33,7 @@ const (
 	EstadoLeida        EstadoNotificacion = "leida"
 	EstadoFallida      EstadoNotificacion = "fallida"
 	EstadoCancelada    EstadoNotificacion = "cancelada"
+	EstadoExpirada     EstadoNotificacion = "expirada"
 )
 
 // PrioridadNotificacion define la prioridad de una notificación
@@ -41,21 +49,45 @@ const (
 // Notificacion representa una notificación en el sistema
 type Notificacion struct {
 	ID                uint                   `json:"id" gorm:"primaryKey"`
-	UsuarioID         uint                   `json:"usuario_id" gorm:"not null;index"`
-	Usuario           Usuario                `json:"usuario" gorm:"foreignKey:UsuarioID"`
+	// InquilinoID es 0 para notificaciones de la plataforma sin inquilino
+	InquilinoID       uint                   `json:"inquilino_id" gorm:"index"`
+	UsuarioID         uint                   `json:"usuario_id" gorm:"not null;index;index:idx_notificacion_usuario_estado,priority:1;index:idx_notificacion_bandeja,priority:1"`
+	Usuario           *Usuario               `json:"usuario,omitempty" gorm:"foreignKey:UsuarioID;constraint:-"`
+	// HuellaUsuario reemplaza a UsuarioID (que queda en 0) cuando la notificación se anonimiza
+	HuellaUsuario     string                 `json:"huella_usuario,omitempty" gorm:"size:64;index"`
 	Titulo            string                 `json:"titulo" gorm:"not null;size:255"`
 	Mensaje           string                 `json:"mensaje" gorm:"not null;type:text"`
 	Tipo              TipoNotificacion       `json:"tipo" gorm:"not null;size:50"`
-	Estado            EstadoNotificacion     `json:"estado" gorm:"not null;size:50;default:'pendiente'"`
+	Estado            EstadoNotificacion     `json:"estado" gorm:"not null;size:50;default:'pendiente';index:idx_notificacion_usuario_estado,priority:2;index:idx_notificacion_estado_programada,priority:1;index:idx_notificacion_estado_reintento,priority:1"`
 	Prioridad         PrioridadNotificacion  `json:"prioridad" gorm:"not null;size:50;default:'normal'"`
 	CanalID           uint                   `json:"canal_id" gorm:"index"`
-	Canal             Canal                  `json:"canal" gorm:"foreignKey:CanalID"`
-	Metadatos         map[string]interface{} `json:"metadatos" gorm:"type:jsonb"`
-	FechaProgramada   *time.Time             `json:"fecha_programada"`
+	Canal             *Canal                 `json:"canal,omitempty" gorm:"foreignKey:CanalID"`
+	// EnvioID agrupa las notificaciones de un envío multicanal
+	EnvioID           *uint                  `json:"envio_id,omitempty" gorm:"index"`
+	// Origen identifica el sistema o remitente que generó la notificación
+	Origen            string                 `json:"origen,omitempty" gorm:"size:100"`
+	Metadatos         map[string]interface{} `json:"metadatos" gorm:"type:jsonb;serializer:json"`
+	// URLCodigoQR es la URL firmada de la imagen del código QR; se calcula al entregar y no se guarda
+	URLCodigoQR       string                 `json:"url_codigo_qr,omitempty" gorm:"-"`
+	// Carpeta y Destacada ordenan la bandeja del usuario; solo aplican a las notificaciones in_app
+	Carpeta           CarpetaBandeja         `json:"carpeta,omitempty" gorm:"size:20;default:'recibidas';index:idx_notificacion_bandeja,priority:2"`
+	Destacada         bool                   `json:"destacada,omitempty" gorm:"not null;default:false"`
+	// Silenciada indica que un silenciamiento del usuario la dejó en la bandeja sin alertarlo
+	Silenciada        bool                   `json:"silenciada,omitempty" gorm:"not null;default:false"`
+	// AvisoLecturaWebhook y AvisoLecturaTema son los destinos que el servicio de origen declaró
+	// al enviar para enterarse de que el usuario leyó la notificación
+	AvisoLecturaWebhook string               `json:"aviso_lectura_webhook,omitempty" gorm:"size:500"`
+	AvisoLecturaTema  string                 `json:"aviso_lectura_tema,omitempty" gorm:"size:100"`
+	FechaProgramada   *time.Time             `json:"fecha_programada" gorm:"index:idx_notificacion_estado_programada,priority:2"`
 	FechaEnviada      *time.Time             `json:"fecha_enviada"`
 	FechaLeida        *time.Time             `json:"fecha_leida"`
 	IntentosEnvio     int                    `json:"intentos_envio" gorm:"default:0"`
 	MaxIntentos       int                    `json:"max_intentos" gorm:"default:3"`
+	// ProximaFechaReintento persiste cuándo reintentar una notificación fallida para sobrevivir
+	// reinicios; en una programada vencida reserva su despacho mientras está encolada
+	ProximaFechaReintento *time.Time         `json:"proxima_fecha_reintento,omitempty" gorm:"index:idx_notificacion_estado_reintento,priority:2"`
+	// Version se incrementa en cada actualización para detectar escrituras concurrentes
+	Version           uint                   `json:"version" gorm:"not null;default:1"`
 	FechaCreacion     time.Time              `json:"fecha_creacion" gorm:"autoCreateTime"`
 	FechaActualizacion time.Time             `json:"fecha_actualizacion" gorm:"autoUpdateTime"`
 	FechaEliminacion  gorm.DeletedAt         `json:"fecha_eliminacion" gorm:"index"`
@@ -71,31 +103,74 @@ func NuevaNotificacion(usuarioID uint, titulo, mensaje string, tipo TipoNotifica
 		Estado:    EstadoPendiente,
 		Prioridad: PrioridadNormal,
 		MaxIntentos: 3,
+		Version:   1,
 	}
 }
 
-// MarcarComoEnviada marca la notificación como enviada
-func (n *Notificacion) MarcarComoEnviada() {
-	n.Estado = EstadoEnviada
-	ahora := time.Now()
+// MarcarComoEnviada marca la notificación como enviada en el instante indicado
+func (n *Notificacion) MarcarComoEnviada(ahora time.Time) error {
+	if err := n.transicionar(EstadoEnviada); err != nil {
+		return err
+	}
 	n.FechaEnviada = &ahora
+	return nil
 }
 
 // MarcarComoEntregada marca la notificación como entregada
-func (n *Notificacion) MarcarComoEntregada() {
-	n.Estado = EstadoEntregada
+func (n *Notificacion) MarcarComoEntregada() error {
+	return n.transicionar(EstadoEntregada)
 }
 
-// MarcarComoLeida marca la notificación como leída
-func (n *Notificacion) MarcarComoLeida() {
-	n.Estado = EstadoLeida
-	ahora := time.Now()
+// TieneAvisoLectura indica si al leerse hay que avisar al servicio de origen
+func (n *Notificacion) TieneAvisoLectura() bool {
+	return n.AvisoLecturaWebhook != "" || n.AvisoLecturaTema != ""
+}
+
+// EstaNoLeida indica si cuenta entre las no leídas del usuario: ya le llegó y aún no la leyó
+func (n *Notificacion) EstaNoLeida() bool {
+	return n.Estado == EstadoEnviada || n.Estado == EstadoEntregada
+}
+
+// MarcarComoLeida marca la notificación como leída; es idempotente si ya estaba leída
+func (n *Notificacion) MarcarComoLeida(ahora time.Time) error {
+	if n.Estado == EstadoLeida {
+		return nil
+	}
+	if err := n.transicionar(EstadoLeida); err != nil {
+		return err
+	}
 	n.FechaLeida = &ahora
+	return nil
 }
 
-// MarcarComoFallida marca la notificación como fallida
-func (n *Notificacion) MarcarComoFallida() {
-	n.Estado = EstadoFallida
+// MarcarComoFallida marca la notificación como fallida; un reintento que vuelve a fallar la deja igual
+func (n *Notificacion) MarcarComoFallida() error {
+	if n.Estado == EstadoFallida {
+		return nil
+	}
+	return n.transicionar(EstadoFallida)
+}
+
+// Cancelar cancela una notificación que aún no fue enviada
+func (n *Notificacion) Cancelar() error {
+	return n.transicionar(EstadoCancelada)
+}
+
+// Expirar marca la notificación como expirada
+func (n *Notificacion) Expirar() error {
+	return n.transicionar(EstadoExpirada)
+}
+
+// transicionar cambia el estado si la transición está permitida
+func (n *Notificacion) transicionar(destino EstadoNotificacion) error {
+	if !n.Estado.PuedeTransicionarA(destino) {
+		return NewErrorDominio(fmt.Sprintf("Transición de estado inválida: %s → %s", n.Estado, destino))
+	}
+	n.Estado = destino
+	if destino != EstadoFallida {
+		n.ProximaFechaReintento = nil
+	}
+	return nil
 }
 
 // IncrementarIntentos incrementa el contador de intentos
@@ -108,14 +183,56 @@ func (n *Notificacion) PuedeReintentar() bool {
 	return n.IntentosEnvio < n.MaxIntentos && n.Estado == EstadoFallida
 }
 
+// ProgramarReintento fija la próxima fecha de reintento con la espera de la política para los
+// intentos hechos; azar elige la variación. Sin intentos restantes no se programa ninguno.
+func (n *Notificacion) ProgramarReintento(ahora time.Time, politica PoliticaReintento, azar float64) {
+	if !n.PuedeReintentar() {
+		n.ProximaFechaReintento = nil
+		return
+	}
+	proxima := ahora.Add(politica.Espera(n.IntentosEnvio, azar))
+	n.ProximaFechaReintento = &proxima
+}
+
+// Reactivar concede intentos a una fallida que agotó los suyos y la deja lista para que el
+// planificador de reintentos la tome de inmediato
+func (n *Notificacion) Reactivar(intentos int, ahora time.Time) error {
+	if n.Estado != EstadoFallida || n.PuedeReintentar() {
+		return NewErrorDominio("Solo se puede reactivar una notificación fallida sin intentos restantes")
+	}
+	n.MaxIntentos = n.IntentosEnvio + intentos
+	n.ProximaFechaReintento = &ahora
+	return nil
+}
+
+// Reprogramar cambia la fecha de una notificación programada que aún no se despachó
+func (n *Notificacion) Reprogramar(fecha, ahora time.Time) error {
+	if n.Estado != EstadoPendiente || !n.EstaProgramada(ahora) {
+		return NewErrorDominio("Solo se puede reprogramar una notificación pendiente cuya fecha programada no llegó")
+	}
+	if !fecha.After(ahora) {
+		return NewErrorValidacion("La nueva fecha programada debe ser futura")
+	}
+	n.FechaProgramada = &fecha
+	return nil
+}
+
 // EsUrgente verifica si la notificación es urgente
 func (n *Notificacion) EsUrgente() bool {
 	return n.Prioridad == PrioridadAlta || n.Prioridad == PrioridadCritica
 }
 
-// EstaProgramada verifica si la notificación está programada para el futuro
-func (n *Notificacion) EstaProgramada() bool {
-	return n.FechaProgramada != nil && n.FechaProgramada.After(time.Now())
+// EstaProgramada verifica si la notificación está programada para después del instante indicado
+func (n *Notificacion) EstaProgramada(ahora time.Time) bool {
+	return n.FechaProgramada != nil && n.FechaProgramada.After(ahora)
+}
+
+// Huella identifica notificaciones equivalentes: mismo inquilino, usuario, tipo, título y
+// mensaje. Los IDs de usuario se repiten entre inquilinos con esquema propio.
+func (n *Notificacion) Huella() string {
+	mensaje := sha256.Sum256([]byte(n.Mensaje))
+	huella := sha256.Sum256([]byte(fmt.Sprintf("%d|%d|%s|%s|%x", n.InquilinoID, n.UsuarioID, n.Tipo, n.Titulo, mensaje)))
+	return hex.EncodeToString(huella[:])
 }
 
 // ObtenerMetadato obtiene un metadato específico
@@ -149,5 +266,8 @@ func (n *Notificacion) Validar() error {
 	if n.Tipo == "" {
 		return NewErrorValidacion("Tipo es requerido")
 	}
+	if n.Tipo == TipoVoz && n.Prioridad != PrioridadCritica {
+		return NewErrorValidacion("Las llamadas de voz solo se admiten con prioridad crítica")
+	}
 	return nil
 }
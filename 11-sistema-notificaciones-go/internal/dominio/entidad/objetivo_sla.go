@@ -0,0 +1,66 @@
+package entidad
+
+import "time"
+
+// ObjetivoSLA define el tiempo de envío comprometido con un inquilino para una prioridad,
+// p. ej. el 95% de las notificaciones críticas enviadas al proveedor en menos de 30 segundos.
+// Mide hasta que el proveedor acepta el mensaje y no hasta que llega al usuario: la mayoría de
+// los canales no confirma la recepción.
+type ObjetivoSLA struct {
+	ID                 uint                  `json:"id" gorm:"primaryKey"`
+	InquilinoID        uint                  `json:"inquilino_id" gorm:"not null;uniqueIndex:idx_objetivo_sla_inquilino_prioridad"`
+	Prioridad          PrioridadNotificacion `json:"prioridad" gorm:"not null;size:50;uniqueIndex:idx_objetivo_sla_inquilino_prioridad"`
+	UmbralSegundos     int                   `json:"umbral_segundos" gorm:"not null"`
+	PorcentajeObjetivo float64               `json:"porcentaje_objetivo" gorm:"not null"`
+	FechaActualizacion time.Time             `json:"fecha_actualizacion" gorm:"autoUpdateTime"`
+}
+
+// Validar valida el objetivo
+func (o *ObjetivoSLA) Validar() error {
+	if o.InquilinoID == 0 {
+		return NewErrorValidacion("InquilinoID es requerido")
+	}
+	if o.Prioridad == "" {
+		return NewErrorValidacion("Prioridad es requerida")
+	}
+	if o.UmbralSegundos <= 0 {
+		return NewErrorValidacion("El umbral debe ser mayor a cero")
+	}
+	if o.PorcentajeObjetivo <= 0 || o.PorcentajeObjetivo > 100 {
+		return NewErrorValidacion("El porcentaje objetivo debe estar entre 0 y 100")
+	}
+	return nil
+}
+
+// Umbral retorna el tiempo de envío comprometido
+func (o *ObjetivoSLA) Umbral() time.Duration {
+	return time.Duration(o.UmbralSegundos) * time.Second
+}
+
+// CumplimientoSLA es el resultado de evaluar un objetivo sobre un periodo
+type CumplimientoSLA struct {
+	Objetivo ObjetivoSLA `json:"objetivo"`
+	Periodo  string      `json:"periodo"`
+	// Enviadas son las notificaciones que el proveedor aceptó en el periodo; DentroUmbral las
+	// que cumplieron el tiempo
+	Enviadas     int64   `json:"enviadas"`
+	DentroUmbral int64   `json:"dentro_umbral"`
+	Porcentaje   float64 `json:"porcentaje"`
+	Cumple       bool    `json:"cumple"`
+}
+
+// NuevoCumplimientoSLA calcula el porcentaje alcanzado; sin envíos el objetivo se considera cumplido
+func NuevoCumplimientoSLA(objetivo ObjetivoSLA, periodo string, enviadas, dentroUmbral int64) CumplimientoSLA {
+	porcentaje := 100.0
+	if enviadas > 0 {
+		porcentaje = float64(dentroUmbral) * 100 / float64(enviadas)
+	}
+	return CumplimientoSLA{
+		Objetivo:     objetivo,
+		Periodo:      periodo,
+		Enviadas:     enviadas,
+		DentroUmbral: dentroUmbral,
+		Porcentaje:   porcentaje,
+		Cumple:       porcentaje >= objetivo.PorcentajeObjetivo,
+	}
+}
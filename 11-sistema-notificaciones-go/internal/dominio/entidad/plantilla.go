@@ -0,0 +1,73 @@
+package entidad
+
+import (
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Plantilla es un contenido reutilizable de un inquilino; Asunto y Cuerpo usan la sintaxis de text/template
+type Plantilla struct {
+	ID                 uint             `json:"id" gorm:"primaryKey"`
+	InquilinoID        uint             `json:"inquilino_id" gorm:"not null;uniqueIndex:idx_plantilla_inquilino_nombre"`
+	Nombre             string           `json:"nombre" gorm:"not null;size:100;uniqueIndex:idx_plantilla_inquilino_nombre"`
+	Tipo               TipoNotificacion `json:"tipo" gorm:"not null;size:50"`
+	Asunto             string           `json:"asunto" gorm:"not null;size:255"`
+	Cuerpo             string           `json:"cuerpo" gorm:"type:text;not null"`
+	FechaCreacion      time.Time        `json:"fecha_creacion" gorm:"autoCreateTime"`
+	FechaActualizacion time.Time        `json:"fecha_actualizacion" gorm:"autoUpdateTime"`
+}
+
+// NuevaPlantilla crea una nueva instancia de Plantilla
+func NuevaPlantilla(inquilinoID uint, nombre string, tipo TipoNotificacion, asunto, cuerpo string) *Plantilla {
+	return &Plantilla{
+		InquilinoID: inquilinoID,
+		Nombre:      nombre,
+		Tipo:        tipo,
+		Asunto:      asunto,
+		Cuerpo:      cuerpo,
+	}
+}
+
+// Validar valida la plantilla, incluida la sintaxis de asunto y cuerpo
+func (p *Plantilla) Validar() error {
+	if p.Nombre == "" {
+		return NewErrorValidacion("Nombre es requerido")
+	}
+	if p.Tipo == "" {
+		return NewErrorValidacion("Tipo es requerido")
+	}
+	if p.Cuerpo == "" {
+		return NewErrorValidacion("Cuerpo es requerido")
+	}
+	if _, err := template.New("asunto").Parse(p.Asunto); err != nil {
+		return NewErrorValidacion("Asunto inválido: " + err.Error())
+	}
+	if _, err := template.New("cuerpo").Parse(p.Cuerpo); err != nil {
+		return NewErrorValidacion("Cuerpo inválido: " + err.Error())
+	}
+	return nil
+}
+
+// Renderizar ejecuta asunto y cuerpo con los datos dados
+func (p *Plantilla) Renderizar(datos map[string]interface{}) (asunto, cuerpo string, err error) {
+	if asunto, err = ejecutarPlantilla("asunto", p.Asunto, datos); err != nil {
+		return "", "", err
+	}
+	if cuerpo, err = ejecutarPlantilla("cuerpo", p.Cuerpo, datos); err != nil {
+		return "", "", err
+	}
+	return asunto, cuerpo, nil
+}
+
+func ejecutarPlantilla(nombre, texto string, datos map[string]interface{}) (string, error) {
+	t, err := template.New(nombre).Option("missingkey=zero").Parse(texto)
+	if err != nil {
+		return "", NewErrorValidacion("Plantilla inválida: " + err.Error())
+	}
+	var salida strings.Builder
+	if err := t.Execute(&salida, datos); err != nil {
+		return "", NewErrorValidacion("Error renderizando la plantilla: " + err.Error())
+	}
+	return salida.String(), nil
+}
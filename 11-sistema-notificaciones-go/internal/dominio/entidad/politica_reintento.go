@@ -0,0 +1,35 @@
+package entidad
+
+import (
+	"math"
+	"time"
+)
+
+// limiteEspera acota la espera sin EsperaMaxima: con la variación máxima (1) se vuelve a
+// duplicar y no debe desbordar time.Duration
+const limiteEspera = time.Duration(math.MaxInt64 / 4)
+
+// PoliticaReintento calcula la espera antes de reintentar un envío fallido: crece en forma
+// exponencial desde EsperaBase (base, 2×base, 4×base...) hasta EsperaMaxima y varía al azar en
+// ±Variacion, así las fallidas por una misma caída del proveedor no se reintentan todas juntas.
+type PoliticaReintento struct {
+	EsperaBase   time.Duration
+	EsperaMaxima time.Duration
+	// Variacion es la fracción de la espera que se suma o resta al azar, entre 0 y 1
+	Variacion float64
+}
+
+// Espera retorna la espera tras el intento número intentos (1 para el primero); azar es un
+// número en [0, 1) que elige el punto dentro de la variación
+func (p PoliticaReintento) Espera(intentos int, azar float64) time.Duration {
+	tope := limiteEspera
+	if p.EsperaMaxima > 0 {
+		tope = min(p.EsperaMaxima, limiteEspera)
+	}
+	espera := min(p.EsperaBase, tope)
+	for i := 1; i < intentos && espera > 0 && espera < tope; i++ {
+		espera = min(espera*2, tope)
+	}
+	// El redondeo de float64 puede dejar la variación completa un poco por encima de la espera
+	return max(espera+time.Duration(float64(espera)*p.Variacion*(2*azar-1)), 0)
+}
@@ -0,0 +1,89 @@
+package entidad
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestEsperaCreceHastaElMaximo(t *testing.T) {
+	politica := PoliticaReintento{EsperaBase: time.Second, EsperaMaxima: 10 * time.Second}
+	casos := []struct {
+		intentos int
+		espera   time.Duration
+	}{
+		{-1, time.Second},
+		{0, time.Second},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 8 * time.Second},
+		{5, 10 * time.Second},
+		{6, 10 * time.Second},
+		{1000, 10 * time.Second},
+		{math.MaxInt, 10 * time.Second},
+	}
+	for _, caso := range casos {
+		// Con azar 0.5 la variación es nula
+		if espera := politica.Espera(caso.intentos, 0.5); espera != caso.espera {
+			t.Errorf("Espera(%d) = %v, se esperaba %v", caso.intentos, espera, caso.espera)
+		}
+	}
+}
+
+func TestEsperaConVariacionDentroDeLosLimites(t *testing.T) {
+	politica := PoliticaReintento{EsperaBase: time.Second, EsperaMaxima: time.Minute, Variacion: 0.2}
+	casos := []struct {
+		intentos int
+		azar     float64
+		minima   time.Duration
+		maxima   time.Duration
+	}{
+		{1, 0, 800 * time.Millisecond, 800 * time.Millisecond},
+		{1, 0.5, time.Second, time.Second},
+		{1, 0.9999, time.Second, 1200 * time.Millisecond},
+		{3, 0, 3200 * time.Millisecond, 3200 * time.Millisecond},
+		{3, 0.75, 4400 * time.Millisecond, 4400 * time.Millisecond},
+		// En el máximo la variación se aplica sobre EsperaMaxima
+		{20, 0, 48 * time.Second, 48 * time.Second},
+		{20, 0.9999, time.Minute, 72 * time.Second},
+	}
+	for _, caso := range casos {
+		if espera := politica.Espera(caso.intentos, caso.azar); espera < caso.minima || espera > caso.maxima {
+			t.Errorf("Espera(%d, %v) = %v, fuera de [%v, %v]", caso.intentos, caso.azar, espera, caso.minima, caso.maxima)
+		}
+	}
+}
+
+func TestEsperaSinMaximoNoDesborda(t *testing.T) {
+	casos := []PoliticaReintento{
+		{EsperaBase: time.Second},
+		{EsperaBase: time.Second, Variacion: 1},
+		{EsperaBase: time.Duration(math.MaxInt64), Variacion: 1},
+		{EsperaBase: time.Hour, EsperaMaxima: time.Duration(math.MaxInt64), Variacion: 1},
+	}
+	for _, politica := range casos {
+		anterior := time.Duration(0)
+		for _, intentos := range []int{1, 30, 62, 63, 64, 100, math.MaxInt} {
+			for _, azar := range []float64{0, 0.5, 0.9999} {
+				espera := politica.Espera(intentos, azar)
+				if espera < 0 {
+					t.Fatalf("%+v: Espera(%d, %v) = %v, desbordó", politica, intentos, azar, espera)
+				}
+			}
+			if espera := politica.Espera(intentos, 0.5); espera < anterior {
+				t.Errorf("%+v: Espera(%d) = %v, menor que la del intento anterior %v", politica, intentos, espera, anterior)
+			} else {
+				anterior = espera
+			}
+		}
+	}
+}
+
+func TestEsperaConBaseCero(t *testing.T) {
+	politica := PoliticaReintento{EsperaMaxima: time.Minute, Variacion: 0.5}
+
+	if espera := politica.Espera(math.MaxInt, 0.9); espera != 0 {
+		t.Errorf("Espera = %v, se esperaba 0", espera)
+	}
+}
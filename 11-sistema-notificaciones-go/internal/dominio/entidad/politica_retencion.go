@@ -0,0 +1,60 @@
+package entidad
+
+import "time"
+
+// AccionRetencion define qué se hace con una notificación al vencer su retención
+type AccionRetencion string
+
+const (
+	// AccionEliminar borra la notificación y sus intentos de envío
+	AccionEliminar AccionRetencion = "eliminar"
+	// AccionAnonimizar conserva la fila para analítica agregada sin datos personales:
+	// borra título, mensaje y metadatos y reemplaza el usuario por una huella
+	AccionAnonimizar AccionRetencion = "anonimizar"
+)
+
+// PoliticaRetencion fija cuántos días se conservan las notificaciones de un tipo o de un canal.
+// Una notificación puede estar alcanzada por varias (la de su tipo y la de su canal, la del
+// inquilino y la general): se purga al vencer la primera, es decir, rige la más restrictiva.
+// Un mismo alcance puede tener una política por acción, p. ej. anonimizar a los 90 días y
+// eliminar a los 365.
+type PoliticaRetencion struct {
+	ID uint `json:"id" gorm:"primaryKey"`
+	// InquilinoID es 0 cuando la política aplica a todos los inquilinos
+	InquilinoID uint `json:"inquilino_id" gorm:"not null;default:0;uniqueIndex:idx_politica_retencion_alcance"`
+	// Tipo o CanalID indican a qué notificaciones aplica; se define exactamente uno
+	Tipo               TipoNotificacion `json:"tipo,omitempty" gorm:"not null;default:'';size:50;uniqueIndex:idx_politica_retencion_alcance"`
+	CanalID            uint             `json:"canal_id,omitempty" gorm:"not null;default:0;uniqueIndex:idx_politica_retencion_alcance"`
+	Accion             AccionRetencion  `json:"accion" gorm:"not null;size:20;default:'eliminar';uniqueIndex:idx_politica_retencion_alcance"`
+	Dias               int              `json:"dias" gorm:"not null"`
+	FechaActualizacion time.Time        `json:"fecha_actualizacion" gorm:"autoUpdateTime"`
+}
+
+// Validar valida la política
+func (p *PoliticaRetencion) Validar() error {
+	if (p.Tipo == "") == (p.CanalID == 0) {
+		return NewErrorValidacion("La política debe definir un tipo o un canal, no ambos")
+	}
+	if p.Accion != AccionEliminar && p.Accion != AccionAnonimizar {
+		return NewErrorValidacion("Acción de retención inválida")
+	}
+	if p.Dias <= 0 {
+		return NewErrorValidacion("Los días de retención deben ser mayores a cero")
+	}
+	return nil
+}
+
+// Limite retorna la fecha de creación antes de la cual las notificaciones alcanzadas vencieron
+func (p *PoliticaRetencion) Limite(ahora time.Time) time.Time {
+	return ahora.AddDate(0, 0, -p.Dias)
+}
+
+// ResultadoPurga deja constancia de lo que una política eliminó o anonimizó en una pasada
+type ResultadoPurga struct {
+	Politica       PoliticaRetencion `json:"politica"`
+	Region         string            `json:"region,omitempty"`
+	Esquema        string            `json:"esquema"`
+	Limite         time.Time         `json:"limite"`
+	Notificaciones int64             `json:"notificaciones"`
+	Intentos       int64             `json:"intentos"`
+}
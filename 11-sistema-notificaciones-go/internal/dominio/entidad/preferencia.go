@@ -0,0 +1,84 @@
+package entidad
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PreferenciaNotificacion representa la preferencia de un usuario para un tipo de notificación
+type PreferenciaNotificacion struct {
+	ID         uint             `json:"id" gorm:"primaryKey"`
+	UsuarioID  uint             `json:"usuario_id" gorm:"not null;uniqueIndex:idx_preferencia_usuario_tipo"`
+	Tipo       TipoNotificacion `json:"tipo" gorm:"not null;size:50;uniqueIndex:idx_preferencia_usuario_tipo"`
+	Habilitada bool             `json:"habilitada" gorm:"default:true"`
+	// Horario de silencio en formato HH:MM, en la zona horaria del usuario
+	SilencioDesde      string         `json:"silencio_desde" gorm:"size:5"`
+	SilencioHasta      string         `json:"silencio_hasta" gorm:"size:5"`
+	ZonaHoraria        string         `json:"zona_horaria" gorm:"size:64;default:'UTC'"`
+	FechaCreacion      time.Time      `json:"fecha_creacion" gorm:"autoCreateTime"`
+	FechaActualizacion time.Time      `json:"fecha_actualizacion" gorm:"autoUpdateTime"`
+	FechaEliminacion   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// NuevaPreferenciaNotificacion crea una preferencia habilitada sin horario de silencio
+func NuevaPreferenciaNotificacion(usuarioID uint, tipo TipoNotificacion) *PreferenciaNotificacion {
+	return &PreferenciaNotificacion{
+		UsuarioID:   usuarioID,
+		Tipo:        tipo,
+		Habilitada:  true,
+		ZonaHoraria: "UTC",
+	}
+}
+
+// TieneSilencio verifica si la preferencia define un horario de silencio
+func (p *PreferenciaNotificacion) TieneSilencio() bool {
+	return p.SilencioDesde != "" && p.SilencioHasta != ""
+}
+
+// Validar valida la preferencia
+func (p *PreferenciaNotificacion) Validar() error {
+	if p.UsuarioID == 0 {
+		return NewErrorValidacion("UsuarioID es requerido")
+	}
+	if p.Tipo == "" {
+		return NewErrorValidacion("Tipo es requerido")
+	}
+	if (p.SilencioDesde == "") != (p.SilencioHasta == "") {
+		return NewErrorValidacion("El horario de silencio requiere inicio y fin")
+	}
+	if p.TieneSilencio() {
+		if _, err := time.Parse("15:04", p.SilencioDesde); err != nil {
+			return NewErrorValidacion("Inicio de silencio inválido, formato HH:MM")
+		}
+		if _, err := time.Parse("15:04", p.SilencioHasta); err != nil {
+			return NewErrorValidacion("Fin de silencio inválido, formato HH:MM")
+		}
+	}
+	if _, err := time.LoadLocation(p.ZonaHoraria); err != nil {
+		return NewErrorValidacion("Zona horaria inválida")
+	}
+	return nil
+}
+
+// EnSilencio indica si el instante cae dentro del horario de silencio, evaluado en la zona
+// horaria del usuario. Un horario cuyo fin es anterior al inicio abarca la medianoche.
+func (p *PreferenciaNotificacion) EnSilencio(instante time.Time) bool {
+	if !p.TieneSilencio() {
+		return false
+	}
+	desde, errDesde := time.Parse("15:04", p.SilencioDesde)
+	hasta, errHasta := time.Parse("15:04", p.SilencioHasta)
+	zona, errZona := time.LoadLocation(p.ZonaHoraria)
+	if errDesde != nil || errHasta != nil || errZona != nil {
+		return false
+	}
+
+	local := instante.In(zona)
+	minuto := local.Hour()*60 + local.Minute()
+	inicio, fin := desde.Hour()*60+desde.Minute(), hasta.Hour()*60+hasta.Minute()
+	if inicio <= fin {
+		return minuto >= inicio && minuto < fin
+	}
+	return minuto >= inicio || minuto < fin
+}
@@ -0,0 +1,85 @@
+package entidad
+
+import "time"
+
+// TipoReaccion define las reacciones que un destinatario puede dejar en una notificación
+type TipoReaccion string
+
+const (
+	// ReaccionAcuse confirma que el destinatario vio la notificación, p. ej. la de un incidente
+	ReaccionAcuse    TipoReaccion = "acuse"
+	ReaccionMeGusta  TipoReaccion = "👍"
+	ReaccionResuelta TipoReaccion = "resuelta"
+)
+
+// EsValida verifica si la reacción es una de las admitidas
+func (t TipoReaccion) EsValida() bool {
+	return t == ReaccionAcuse || t == ReaccionMeGusta || t == ReaccionResuelta
+}
+
+// EventoReaccion registra que el destinatario agregó o quitó una reacción. Los eventos no se
+// modifican: el estado de cada reacción es el de su último evento.
+type EventoReaccion struct {
+	ID          uint `json:"id" gorm:"primaryKey"`
+	InquilinoID uint `json:"inquilino_id" gorm:"index"`
+	// NotificacionID, UsuarioID y CanalID se copian de la notificación para agregar por canal
+	NotificacionID uint         `json:"notificacion_id" gorm:"not null;index"`
+	UsuarioID      uint         `json:"usuario_id" gorm:"not null"`
+	CanalID        uint         `json:"canal_id,omitempty" gorm:"index"`
+	Reaccion       TipoReaccion `json:"reaccion" gorm:"not null;size:20"`
+	// Activa es false en el evento que quita la reacción
+	Activa bool      `json:"activa" gorm:"not null"`
+	Fecha  time.Time `json:"fecha" gorm:"not null"`
+}
+
+// ReaccionesActivas retorna las reacciones vigentes, en el orden en que se agregaron, a partir
+// de los eventos en orden cronológico
+func ReaccionesActivas(eventos []EventoReaccion) []TipoReaccion {
+	activas := make([]TipoReaccion, 0, len(eventos))
+	for _, evento := range eventos {
+		for i, reaccion := range activas {
+			if reaccion == evento.Reaccion {
+				activas = append(activas[:i], activas[i+1:]...)
+				break
+			}
+		}
+		if evento.Activa {
+			activas = append(activas, evento.Reaccion)
+		}
+	}
+	return activas
+}
+
+// ResumenReaccionesCanal mide cuántos destinatarios de las notificaciones de un canal
+// reaccionaron a ellas, p. ej. cuántos acusaron recibo de un incidente
+type ResumenReaccionesCanal struct {
+	CanalID uint      `json:"canal_id"`
+	Desde   time.Time `json:"desde"`
+	Hasta   time.Time `json:"hasta"`
+	// Destinatarios cuenta los usuarios que recibieron notificaciones del canal creadas en el periodo
+	Destinatarios int64 `json:"destinatarios"`
+	// Reacciones cuenta por reacción los destinatarios que la mantienen en alguna de ellas
+	Reacciones map[TipoReaccion]int64 `json:"reacciones"`
+	// TasaAcuse es el porcentaje de destinatarios que acusaron recibo
+	TasaAcuse float64 `json:"tasa_acuse"`
+}
+
+// NuevoResumenReaccionesCanal calcula la tasa de acuse a partir de los conteos
+func NuevoResumenReaccionesCanal(canalID uint, desde, hasta time.Time, destinatarios int64, reacciones map[TipoReaccion]int64) *ResumenReaccionesCanal {
+	for _, reaccion := range []TipoReaccion{ReaccionAcuse, ReaccionMeGusta, ReaccionResuelta} {
+		if _, existe := reacciones[reaccion]; !existe {
+			reacciones[reaccion] = 0
+		}
+	}
+	resumen := &ResumenReaccionesCanal{
+		CanalID:       canalID,
+		Desde:         desde,
+		Hasta:         hasta,
+		Destinatarios: destinatarios,
+		Reacciones:    reacciones,
+	}
+	if destinatarios > 0 {
+		resumen.TasaAcuse = float64(reacciones[ReaccionAcuse]) * 100 / float64(destinatarios)
+	}
+	return resumen
+}
@@ -0,0 +1,76 @@
+package entidad
+
+import "time"
+
+// FrecuenciaReporte indica cada cuánto se envía el reporte operativo
+type FrecuenciaReporte string
+
+const (
+	FrecuenciaDiaria  FrecuenciaReporte = "diario"
+	FrecuenciaSemanal FrecuenciaReporte = "semanal"
+)
+
+// EsValida verifica si la frecuencia es válida
+func (f FrecuenciaReporte) EsValida() bool {
+	return f == FrecuenciaDiaria || f == FrecuenciaSemanal
+}
+
+// PeriodoCerrado retorna el último periodo completo antes de ahora, en UTC: el día anterior
+// o la semana anterior de lunes a domingo
+func (f FrecuenciaReporte) PeriodoCerrado(ahora time.Time) (desde, hasta time.Time) {
+	ahora = ahora.UTC()
+	hasta = time.Date(ahora.Year(), ahora.Month(), ahora.Day(), 0, 0, 0, 0, time.UTC)
+	if f == FrecuenciaSemanal {
+		// Weekday cuenta desde el domingo; se retrocede hasta el lunes de esta semana
+		hasta = hasta.AddDate(0, 0, -((int(hasta.Weekday()) + 6) % 7))
+		return hasta.AddDate(0, 0, -7), hasta
+	}
+	return hasta.AddDate(0, 0, -1), hasta
+}
+
+// ConteoError agrupa los intentos de envío fallidos con el mismo error
+type ConteoError struct {
+	Error    string `json:"error"`
+	Cantidad int64  `json:"cantidad"`
+}
+
+// ReporteOperativo resume la operación del servicio en un periodo para los administradores
+type ReporteOperativo struct {
+	Frecuencia FrecuenciaReporte `json:"frecuencia"`
+	Desde      time.Time         `json:"desde"`
+	Hasta      time.Time         `json:"hasta"`
+	// Total y PorEstado cuentan las notificaciones creadas en el periodo, en su estado actual
+	Total     int64                        `json:"total"`
+	PorEstado map[EstadoNotificacion]int64 `json:"por_estado"`
+	// TasaFallo es el porcentaje de fallidas entre las que terminaron su envío
+	TasaFallo          float64       `json:"tasa_fallo"`
+	PrincipalesErrores []ConteoError `json:"principales_errores"`
+	// SLA es el cumplimiento del mes en curso; SLAIncumplidos cuenta los objetivos por debajo
+	SLA            []CumplimientoSLA `json:"sla"`
+	SLAIncumplidos int               `json:"sla_incumplidos"`
+}
+
+// NuevoReporteOperativo calcula los totales del reporte a partir de las mediciones
+func NuevoReporteOperativo(frecuencia FrecuenciaReporte, desde, hasta time.Time, porEstado map[EstadoNotificacion]int64, errores []ConteoError, sla []CumplimientoSLA) *ReporteOperativo {
+	reporte := &ReporteOperativo{
+		Frecuencia:         frecuencia,
+		Desde:              desde,
+		Hasta:              hasta,
+		PorEstado:          porEstado,
+		PrincipalesErrores: errores,
+		SLA:                sla,
+	}
+	for _, cantidad := range porEstado {
+		reporte.Total += cantidad
+	}
+	terminadas := porEstado[EstadoEnviada] + porEstado[EstadoEntregada] + porEstado[EstadoLeida] + porEstado[EstadoFallida]
+	if terminadas > 0 {
+		reporte.TasaFallo = float64(porEstado[EstadoFallida]) * 100 / float64(terminadas)
+	}
+	for _, cumplimiento := range sla {
+		if !cumplimiento.Cumple {
+			reporte.SLAIncumplidos++
+		}
+	}
+	return reporte
+}
@@ -0,0 +1,57 @@
+package entidad
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLongitudRespuesta acota el mensaje de una respuesta
+const maxLongitudRespuesta = 2000
+
+// RespuestaNotificacion es un mensaje del hilo de una notificación in-app de un canal que
+// admite respuestas. Lo escriben su destinatario o los moderadores del canal.
+type RespuestaNotificacion struct {
+	ID                  uint      `json:"id" gorm:"primaryKey"`
+	InquilinoID         uint      `json:"inquilino_id" gorm:"index"`
+	NotificacionPadreID uint      `json:"notificacion_padre_id" gorm:"not null;index"`
+	CanalID             uint      `json:"canal_id"`
+	AutorID             uint      `json:"autor_id" gorm:"not null"`
+	Mensaje             string    `json:"mensaje" gorm:"not null;type:text"`
+	FechaCreacion       time.Time `json:"fecha_creacion" gorm:"autoCreateTime"`
+}
+
+// NuevaRespuestaNotificacion crea la respuesta del autor en el hilo de la notificación
+func NuevaRespuestaNotificacion(padre *Notificacion, autorID uint, mensaje string) *RespuestaNotificacion {
+	return &RespuestaNotificacion{
+		InquilinoID:         padre.InquilinoID,
+		NotificacionPadreID: padre.ID,
+		CanalID:             padre.CanalID,
+		AutorID:             autorID,
+		Mensaje:             strings.TrimSpace(mensaje),
+	}
+}
+
+// Validar valida la respuesta
+func (r *RespuestaNotificacion) Validar() error {
+	if r.Mensaje == "" {
+		return NewErrorValidacion("El mensaje es requerido")
+	}
+	if utf8.RuneCountInString(r.Mensaje) > maxLongitudRespuesta {
+		return NewErrorValidacion("El mensaje no puede superar los 2000 caracteres")
+	}
+	if r.AutorID == 0 {
+		return NewErrorValidacion("La respuesta requiere un autor")
+	}
+	return nil
+}
+
+// AdmiteRespuestas indica si la notificación puede recibir respuestas del canal: debe ser
+// in-app, de un canal que las admita y haber llegado a la bandeja del destinatario
+func (n *Notificacion) AdmiteRespuestas(canal *Canal) bool {
+	if n.Tipo != TipoInApp || n.UsuarioID == 0 || canal == nil || canal.ID != n.CanalID || !canal.AdmiteRespuestas {
+		return false
+	}
+	return slices.Contains(EstadosBandeja, n.Estado)
+}
@@ -0,0 +1,119 @@
+package entidad
+
+import "time"
+
+// FormatoRelevo es el formato de la fecha y hora local del primer relevo de una rotación
+const FormatoRelevo = "2006-01-02T15:04"
+
+// RotacionGuardia define quién está de guardia en un canal: los participantes se turnan en
+// orden cada TurnoDias días, con el relevo a la hora local de PrimerRelevo en su zona horaria,
+// así el cambio de horario de verano no corre el relevo
+type RotacionGuardia struct {
+	ID            uint   `json:"id" gorm:"primaryKey"`
+	InquilinoID   uint   `json:"inquilino_id" gorm:"index"`
+	CanalID       uint   `json:"canal_id" gorm:"not null;uniqueIndex"`
+	Participantes []uint `json:"participantes" gorm:"type:jsonb;serializer:json"`
+	// PrimerRelevo es la fecha y hora local (AAAA-MM-DDTHH:MM) en que empieza el primer turno
+	PrimerRelevo       string    `json:"primer_relevo" gorm:"not null;size:16"`
+	ZonaHoraria        string    `json:"zona_horaria" gorm:"size:64;default:'UTC'"`
+	TurnoDias          int       `json:"turno_dias" gorm:"not null"`
+	FechaCreacion      time.Time `json:"fecha_creacion" gorm:"autoCreateTime"`
+	FechaActualizacion time.Time `json:"fecha_actualizacion" gorm:"autoUpdateTime"`
+}
+
+// TurnoGuardia es el usuario de guardia y los límites de su turno o de su reemplazo
+type TurnoGuardia struct {
+	UsuarioID uint      `json:"usuario_id"`
+	Desde     time.Time `json:"desde"`
+	Hasta     time.Time `json:"hasta"`
+	// ReemplazoID indica que el turno lo cubre un reemplazo en lugar de la rotación
+	ReemplazoID uint `json:"reemplazo_id,omitempty"`
+}
+
+// Validar valida la rotación
+func (r *RotacionGuardia) Validar() error {
+	if len(r.Participantes) == 0 {
+		return NewErrorValidacion("Se requiere al menos un participante")
+	}
+	for _, participante := range r.Participantes {
+		if participante == 0 {
+			return NewErrorValidacion("Los participantes deben ser IDs de usuario")
+		}
+	}
+	if r.TurnoDias <= 0 {
+		return NewErrorValidacion("La duración del turno debe ser de al menos un día")
+	}
+	zona, err := time.LoadLocation(r.ZonaHoraria)
+	if err != nil {
+		return NewErrorValidacion("Zona horaria inválida")
+	}
+	if _, err := time.ParseInLocation(FormatoRelevo, r.PrimerRelevo, zona); err != nil {
+		return NewErrorValidacion("Primer relevo inválido, formato AAAA-MM-DDTHH:MM")
+	}
+	return nil
+}
+
+// TurnoEn retorna el turno de la rotación que incluye el instante. Antes del primer relevo
+// la rotación se extiende hacia atrás con el mismo orden.
+func (r *RotacionGuardia) TurnoEn(instante time.Time) (TurnoGuardia, error) {
+	if err := r.Validar(); err != nil {
+		return TurnoGuardia{}, err
+	}
+	zona, _ := time.LoadLocation(r.ZonaHoraria)
+	primero, _ := time.ParseInLocation(FormatoRelevo, r.PrimerRelevo, zona)
+
+	// Días de calendario locales desde el primer relevo; AddDate conserva la hora local del relevo
+	local := instante.In(zona)
+	dias := int(fechaCalendario(local).Sub(fechaCalendario(primero)).Hours() / 24)
+	if local.Before(primero.AddDate(0, 0, dias)) {
+		dias--
+	}
+	turno := dias / r.TurnoDias
+	if dias < 0 && dias%r.TurnoDias != 0 {
+		turno--
+	}
+
+	desde := primero.AddDate(0, 0, turno*r.TurnoDias)
+	indice := turno % len(r.Participantes)
+	if indice < 0 {
+		indice += len(r.Participantes)
+	}
+	return TurnoGuardia{
+		UsuarioID: r.Participantes[indice],
+		Desde:     desde,
+		Hasta:     desde.AddDate(0, 0, r.TurnoDias),
+	}, nil
+}
+
+// ReemplazoGuardia cubre la guardia de un canal durante un intervalo en lugar de la rotación,
+// p. ej. por vacaciones o un intercambio de turnos. Si se superponen, rige el más reciente.
+type ReemplazoGuardia struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	RotacionID    uint      `json:"rotacion_id" gorm:"not null;index"`
+	UsuarioID     uint      `json:"usuario_id" gorm:"not null"`
+	Desde         time.Time `json:"desde" gorm:"not null"`
+	Hasta         time.Time `json:"hasta" gorm:"not null"`
+	Motivo        string    `json:"motivo,omitempty" gorm:"size:255"`
+	FechaCreacion time.Time `json:"fecha_creacion" gorm:"autoCreateTime"`
+}
+
+// Validar valida el reemplazo
+func (r *ReemplazoGuardia) Validar() error {
+	if r.UsuarioID == 0 {
+		return NewErrorValidacion("UsuarioID es requerido")
+	}
+	if !r.Hasta.After(r.Desde) {
+		return NewErrorValidacion("El reemplazo debe terminar después de empezar")
+	}
+	return nil
+}
+
+// Turno retorna el reemplazo como turno de guardia
+func (r *ReemplazoGuardia) Turno() TurnoGuardia {
+	return TurnoGuardia{UsuarioID: r.UsuarioID, Desde: r.Desde, Hasta: r.Hasta, ReemplazoID: r.ID}
+}
+
+// fechaCalendario descarta la hora y la zona para contar días de calendario
+func fechaCalendario(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
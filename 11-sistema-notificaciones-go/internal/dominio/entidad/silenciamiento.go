@@ -0,0 +1,58 @@
+package entidad
+
+import (
+	"strings"
+	"time"
+)
+
+// Silenciamiento silencia para un usuario un canal o un origen (el sistema o remitente que
+// genera las notificaciones), por un período o indefinidamente. Lo silenciado llega igual a la
+// bandeja in-app pero no alerta por push, email ni WebSocket.
+type Silenciamiento struct {
+	ID        uint `json:"id" gorm:"primaryKey"`
+	UsuarioID uint `json:"usuario_id" gorm:"not null;uniqueIndex:idx_silenciamiento_usuario_fuente,priority:1"`
+	// CanalID y Origen son excluyentes: cada silenciamiento apunta a uno solo
+	CanalID uint   `json:"canal_id,omitempty" gorm:"not null;default:0;uniqueIndex:idx_silenciamiento_usuario_fuente,priority:2"`
+	Origen  string `json:"origen,omitempty" gorm:"not null;default:'';size:100;uniqueIndex:idx_silenciamiento_usuario_fuente,priority:3"`
+	// Hasta nil silencia indefinidamente
+	Hasta         *time.Time `json:"hasta"`
+	FechaCreacion time.Time  `json:"fecha_creacion" gorm:"autoCreateTime"`
+}
+
+// NuevoSilenciamiento crea el silenciamiento del canal o del origen para el usuario
+func NuevoSilenciamiento(usuarioID, canalID uint, origen string, hasta *time.Time) *Silenciamiento {
+	return &Silenciamiento{
+		UsuarioID: usuarioID,
+		CanalID:   canalID,
+		Origen:    strings.TrimSpace(origen),
+		Hasta:     hasta,
+	}
+}
+
+// Validar valida el silenciamiento
+func (s *Silenciamiento) Validar(ahora time.Time) error {
+	if (s.CanalID == 0) == (s.Origen == "") {
+		return NewErrorValidacion("Indique canal_id u origen, pero no ambos")
+	}
+	if s.Hasta != nil && !s.Hasta.After(ahora) {
+		return NewErrorValidacion("hasta debe ser una fecha futura")
+	}
+	return nil
+}
+
+// Vigente indica si el silenciamiento sigue en curso
+func (s *Silenciamiento) Vigente(ahora time.Time) bool {
+	return s.Hasta == nil || s.Hasta.After(ahora)
+}
+
+// EntregarEnSilencio deja la notificación en la bandeja in-app sin alertar al usuario. El tipo
+// con que se envió queda en los metadatos como tipo_original.
+func (n *Notificacion) EntregarEnSilencio(ahora time.Time) error {
+	if n.Tipo != TipoInApp {
+		n.EstablecerMetadato("tipo_original", string(n.Tipo))
+		n.Tipo = TipoInApp
+	}
+	n.Carpeta = CarpetaRecibidas
+	n.Silenciada = true
+	return n.MarcarComoEnviada(ahora)
+}
@@ -0,0 +1,57 @@
+package entidad
+
+import "strings"
+
+// claveConfiguracionSlack guarda en Canal.Configuracion el destino de Slack del canal
+const claveConfiguracionSlack = "slack"
+
+// prefijoWebhookSlack es el prefijo de las URL de los incoming webhooks de Slack
+const prefijoWebhookSlack = "https://hooks.slack.com/"
+
+// DestinoSlack es adónde publica un canal sus notificaciones slack: un incoming webhook, que ya
+// lleva el espacio de trabajo y el canal, o un canal de Slack por chat.postMessage con el token del bot
+type DestinoSlack struct {
+	WebhookURL string `json:"webhook_url,omitempty"`
+	// CanalSlack es el ID (C0123ABCD) o el nombre (#alertas) del canal de Slack
+	CanalSlack string `json:"canal_slack,omitempty"`
+}
+
+// Validar exige exactamente uno de los dos destinos
+func (d DestinoSlack) Validar() error {
+	if (d.WebhookURL == "") == (d.CanalSlack == "") {
+		return NewErrorValidacion("Indique webhook_url o canal_slack, no ambos")
+	}
+	if d.WebhookURL != "" && !strings.HasPrefix(d.WebhookURL, prefijoWebhookSlack) {
+		return NewErrorValidacion("webhook_url debe ser un incoming webhook de Slack (" + prefijoWebhookSlack + "...)")
+	}
+	return nil
+}
+
+// DestinoSlack retorna el destino de Slack configurado en el canal
+func (c *Canal) DestinoSlack() (DestinoSlack, bool) {
+	valor, existe := c.ObtenerConfiguracion(claveConfiguracionSlack)
+	datos, ok := valor.(map[string]interface{})
+	if !existe || !ok {
+		return DestinoSlack{}, false
+	}
+	var destino DestinoSlack
+	destino.WebhookURL, _ = datos["webhook_url"].(string)
+	destino.CanalSlack, _ = datos["canal_slack"].(string)
+	return destino, destino.Validar() == nil
+}
+
+// ConfigurarSlack fija el destino de Slack del canal; nil lo quita
+func (c *Canal) ConfigurarSlack(destino *DestinoSlack) error {
+	if destino == nil {
+		delete(c.Configuracion, claveConfiguracionSlack)
+		return nil
+	}
+	if err := destino.Validar(); err != nil {
+		return err
+	}
+	c.EstablecerConfiguracion(claveConfiguracionSlack, map[string]interface{}{
+		"webhook_url": destino.WebhookURL,
+		"canal_slack": destino.CanalSlack,
+	})
+	return nil
+}
@@ -0,0 +1,116 @@
+package entidad
+
+import (
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// TipoContacto define el medio al que pertenece una dirección suprimida
+type TipoContacto string
+
+const (
+	TipoContactoCorreo   TipoContacto = "correo"
+	TipoContactoTelefono TipoContacto = "telefono"
+)
+
+// MotivoSupresion define por qué una dirección no debe contactarse
+type MotivoSupresion string
+
+const (
+	// MotivoRebote es una dirección que el proveedor reportó como inexistente
+	MotivoRebote MotivoSupresion = "rebote"
+	// MotivoQueja es una dirección cuyo titular marcó un mensaje como no deseado
+	MotivoQueja MotivoSupresion = "queja"
+	// MotivoSolicitudLegal es un pedido formal de no contactar a la persona por ningún medio
+	MotivoSolicitudLegal MotivoSupresion = "solicitud_legal"
+	// MotivoManual es una supresión cargada por un administrador
+	MotivoManual MotivoSupresion = "manual"
+)
+
+// EntradaSupresion es una dirección de la lista de no contactar. Se consulta antes de cada
+// envío, por encima de las preferencias del usuario. InquilinoID 0 la aplica a toda la plataforma.
+type EntradaSupresion struct {
+	ID          uint            `json:"id" gorm:"primaryKey"`
+	InquilinoID uint            `json:"inquilino_id" gorm:"not null;uniqueIndex:idx_supresion_inquilino_valor,priority:1"`
+	Tipo        TipoContacto    `json:"tipo" gorm:"not null;size:20"`
+	Valor       string          `json:"valor" gorm:"not null;size:255;uniqueIndex:idx_supresion_inquilino_valor,priority:2"`
+	Motivo      MotivoSupresion `json:"motivo" gorm:"not null;size:30"`
+	Detalle     string          `json:"detalle" gorm:"size:500"`
+	Fecha       time.Time       `json:"fecha" gorm:"not null"`
+}
+
+// NuevaEntradaSupresion crea la entrada normalizando la dirección: los correos se comparan
+// en minúsculas y los teléfonos solo por sus dígitos con el prefijo +
+func NuevaEntradaSupresion(inquilinoID uint, valor string, motivo MotivoSupresion, detalle string, fecha time.Time) *EntradaSupresion {
+	tipo, normalizado := NormalizarContacto(valor)
+	return &EntradaSupresion{
+		InquilinoID: inquilinoID,
+		Tipo:        tipo,
+		Valor:       normalizado,
+		Motivo:      motivo,
+		Detalle:     detalle,
+		Fecha:       fecha,
+	}
+}
+
+// Validar valida la entrada
+func (e *EntradaSupresion) Validar() error {
+	switch e.Tipo {
+	case TipoContactoCorreo:
+		if _, err := mail.ParseAddress(e.Valor); err != nil {
+			return NewErrorValidacion("Correo inválido")
+		}
+	case TipoContactoTelefono:
+		if len(e.Valor) < 8 || len(e.Valor) > 16 {
+			return NewErrorValidacion("Teléfono inválido")
+		}
+	default:
+		return NewErrorValidacion("Valor debe ser un correo o un teléfono")
+	}
+	switch e.Motivo {
+	case MotivoRebote, MotivoQueja, MotivoSolicitudLegal, MotivoManual:
+	default:
+		return NewErrorValidacion("Motivo de supresión inválido")
+	}
+	return nil
+}
+
+// Bloquea indica si la entrada impide un envío del tipo dado. Un rebote o una queja solo
+// afectan al medio de la dirección; una solicitud legal bloquea cualquier contacto.
+func (e *EntradaSupresion) Bloquea(tipo TipoNotificacion) bool {
+	if e.Motivo == MotivoSolicitudLegal {
+		return true
+	}
+	switch e.Tipo {
+	case TipoContactoCorreo:
+		return tipo == TipoEmail
+	case TipoContactoTelefono:
+		return tipo == TipoSMS || tipo == TipoVoz
+	}
+	return false
+}
+
+// NormalizarContacto identifica si el valor es un correo o un teléfono y lo lleva a la forma
+// en que se guarda en la lista de supresión; retorna tipo vacío si no es ninguno
+func NormalizarContacto(valor string) (TipoContacto, string) {
+	valor = strings.TrimSpace(valor)
+	if strings.Contains(valor, "@") {
+		return TipoContactoCorreo, strings.ToLower(valor)
+	}
+
+	var digitos strings.Builder
+	for _, r := range valor {
+		switch {
+		case r >= '0' && r <= '9':
+			digitos.WriteRune(r)
+		case r == ' ' || r == '-' || r == '(' || r == ')' || r == '.' || (r == '+' && digitos.Len() == 0):
+		default:
+			return "", valor
+		}
+	}
+	if digitos.Len() == 0 {
+		return "", valor
+	}
+	return TipoContactoTelefono, "+" + digitos.String()
+}
@@ -0,0 +1,130 @@
+package entidad
+
+import "time"
+
+// EstadoSuscripcion define si la suscripción a un canal ya recibe notificaciones
+type EstadoSuscripcion string
+
+const (
+	// SuscripcionPendiente espera que el usuario confirme desde el enlace enviado
+	SuscripcionPendiente EstadoSuscripcion = "pendiente"
+	// SuscripcionConfirmada recibe las difusiones del canal
+	SuscripcionConfirmada EstadoSuscripcion = "confirmada"
+	// SuscripcionInvitada espera que el usuario acepte la invitación de un administrador
+	SuscripcionInvitada EstadoSuscripcion = "invitada"
+	// SuscripcionSolicitada espera que un moderador apruebe el pedido de ingreso del usuario
+	SuscripcionSolicitada EstadoSuscripcion = "solicitada"
+	// SuscripcionRechazada registra un pedido de ingreso denegado; el usuario puede volver a pedirlo
+	SuscripcionRechazada EstadoSuscripcion = "rechazada"
+)
+
+// SuscripcionCanal es la suscripción de un usuario a un canal (tabla usuario_canales).
+// Las filas previas al doble opt-in quedan confirmadas por el valor por defecto.
+type SuscripcionCanal struct {
+	UsuarioID         uint              `json:"usuario_id" gorm:"primaryKey"`
+	CanalID           uint              `json:"canal_id" gorm:"primaryKey"`
+	Estado            EstadoSuscripcion `json:"estado" gorm:"not null;size:20;default:'confirmada'"`
+	FechaCreacion     time.Time         `json:"fecha_creacion" gorm:"autoCreateTime"`
+	FechaConfirmacion *time.Time        `json:"fecha_confirmacion"`
+	// InvitadoPor es el administrador que invitó al usuario a un canal privado
+	InvitadoPor uint `json:"invitado_por,omitempty"`
+	// ResueltoPor es el moderador que aprobó o rechazó el pedido de ingreso
+	ResueltoPor     uint       `json:"resuelto_por,omitempty"`
+	FechaResolucion *time.Time `json:"fecha_resolucion,omitempty"`
+}
+
+// TableName comparte la tabla de la relación muchos a muchos entre usuarios y canales
+func (SuscripcionCanal) TableName() string {
+	return "usuario_canales"
+}
+
+// NuevaSuscripcionCanal crea la suscripción: queda pendiente si el canal exige doble opt-in
+// y confirmada en otro caso
+func NuevaSuscripcionCanal(usuarioID uint, canal *Canal, ahora time.Time) *SuscripcionCanal {
+	suscripcion := &SuscripcionCanal{UsuarioID: usuarioID, CanalID: canal.ID, Estado: SuscripcionPendiente}
+	if !canal.Tipo.RequiereDobleOptIn() {
+		suscripcion.Confirmar(ahora)
+	}
+	return suscripcion
+}
+
+// NuevaInvitacionCanal crea la membresía invitada de un canal privado
+func NuevaInvitacionCanal(usuarioID, canalID, administradorID uint) *SuscripcionCanal {
+	return &SuscripcionCanal{UsuarioID: usuarioID, CanalID: canalID, Estado: SuscripcionInvitada, InvitadoPor: administradorID}
+}
+
+// NuevaSolicitudIngreso crea el pedido de ingreso del usuario a un canal privado
+func NuevaSolicitudIngreso(usuarioID, canalID uint) *SuscripcionCanal {
+	return &SuscripcionCanal{UsuarioID: usuarioID, CanalID: canalID, Estado: SuscripcionSolicitada}
+}
+
+// EstaConfirmada indica si la suscripción ya recibe notificaciones
+func (s *SuscripcionCanal) EstaConfirmada() bool {
+	return s.Estado == SuscripcionConfirmada
+}
+
+// Confirmar activa la suscripción
+func (s *SuscripcionCanal) Confirmar(ahora time.Time) {
+	s.Estado = SuscripcionConfirmada
+	s.FechaConfirmacion = &ahora
+}
+
+// RequiereDobleOptIn indica si suscribirse a canales de este tipo exige confirmar desde un enlace
+func (t TipoCanal) RequiereDobleOptIn() bool {
+	return t == TipoCanalMarketing
+}
+
+// Invitar reemplaza un pedido pendiente o rechazado por la invitación del administrador
+func (s *SuscripcionCanal) Invitar(administradorID uint) error {
+	if s.EstaConfirmada() {
+		return ErrEstadoMembresiaInvalido
+	}
+	s.Estado = SuscripcionInvitada
+	s.InvitadoPor = administradorID
+	s.ResueltoPor, s.FechaResolucion = 0, nil
+	return nil
+}
+
+// AceptarInvitacion confirma la membresía desde el enlace de la invitación
+func (s *SuscripcionCanal) AceptarInvitacion(ahora time.Time) error {
+	if s.Estado != SuscripcionInvitada {
+		return ErrEstadoMembresiaInvalido
+	}
+	s.Confirmar(ahora)
+	return nil
+}
+
+// Solicitar vuelve a pedir el ingreso tras un rechazo
+func (s *SuscripcionCanal) Solicitar() error {
+	if s.Estado != SuscripcionRechazada {
+		return ErrEstadoMembresiaInvalido
+	}
+	s.Estado = SuscripcionSolicitada
+	s.ResueltoPor, s.FechaResolucion = 0, nil
+	return nil
+}
+
+// Aprobar admite el pedido de ingreso. Si el canal exige doble opt-in queda pendiente de que
+// el usuario confirme, igual que una suscripción a un canal público.
+func (s *SuscripcionCanal) Aprobar(moderadorID uint, canal *Canal, ahora time.Time) error {
+	if s.Estado != SuscripcionSolicitada {
+		return ErrEstadoMembresiaInvalido
+	}
+	s.ResueltoPor, s.FechaResolucion = moderadorID, &ahora
+	if canal.Tipo.RequiereDobleOptIn() {
+		s.Estado = SuscripcionPendiente
+		return nil
+	}
+	s.Confirmar(ahora)
+	return nil
+}
+
+// Rechazar deniega el pedido de ingreso
+func (s *SuscripcionCanal) Rechazar(moderadorID uint, ahora time.Time) error {
+	if s.Estado != SuscripcionSolicitada {
+		return ErrEstadoMembresiaInvalido
+	}
+	s.Estado = SuscripcionRechazada
+	s.ResueltoPor, s.FechaResolucion = moderadorID, &ahora
+	return nil
+}
@@ -0,0 +1,31 @@
+package entidad
+
+// transicionesNotificacion define los estados destino permitidos desde cada estado.
+// Flujo principal: pendiente → enviada → entregada → leida.
+// fallida admite reintento (→ enviada) y vuelta a la cola (→ pendiente).
+// leida, cancelada y expirada son estados finales.
+var transicionesNotificacion = map[EstadoNotificacion][]EstadoNotificacion{
+	EstadoPendiente: {EstadoEnviada, EstadoFallida, EstadoCancelada, EstadoExpirada},
+	EstadoEnviada:   {EstadoEntregada, EstadoLeida, EstadoFallida, EstadoExpirada},
+	EstadoEntregada: {EstadoLeida, EstadoExpirada},
+	EstadoFallida:   {EstadoEnviada, EstadoPendiente, EstadoCancelada, EstadoExpirada},
+	EstadoLeida:     {},
+	EstadoCancelada: {},
+	EstadoExpirada:  {},
+}
+
+// PuedeTransicionarA verifica si la transición al estado destino está permitida
+func (e EstadoNotificacion) PuedeTransicionarA(destino EstadoNotificacion) bool {
+	for _, permitido := range transicionesNotificacion[e] {
+		if permitido == destino {
+			return true
+		}
+	}
+	return false
+}
+
+// EsFinal verifica si el estado no admite más transiciones
+func (e EstadoNotificacion) EsFinal() bool {
+	permitidos, existe := transicionesNotificacion[e]
+	return existe && len(permitidos) == 0
+}
@@ -1,6 +1,7 @@
 package entidad
 
 import (
+	"fmt"
 	"time"
 	"gorm.io/gorm"
 )
@@ -28,6 +29,10 @@ const (
 // Usuario representa un usuario en el sistema
 type Usuario struct {
 	ID                uint           `json:"id" gorm:"primaryKey"`
+	// InquilinoID es 0 para los usuarios de la plataforma
+	InquilinoID       uint           `json:"inquilino_id" gorm:"index"`
+	// Region es la del inquilino: indica en qué región residen sus datos personales
+	Region            string         `json:"region,omitempty" gorm:"size:20"`
 	NombreUsuario     string         `json:"nombre_usuario" gorm:"uniqueIndex;not null;size:50"`
 	CorreoElectronico string         `json:"correo_electronico" gorm:"uniqueIndex;not null;size:255"`
 	Nombre            string         `json:"nombre" gorm:"not null;size:100"`
@@ -37,13 +42,16 @@ type Usuario struct {
 	Rol               RolUsuario     `json:"rol" gorm:"not null;size:50;default:'usuario'"`
 	CorreoVerificado  bool           `json:"correo_verificado" gorm:"default:false"`
 	TelefonoVerificado bool          `json:"telefono_verificado" gorm:"default:false"`
+	// TelegramChatID es el chat privado con el bot, vinculado por el enlace /start; 0 si no lo vinculó
+	TelegramChatID    int64          `json:"telegram_chat_id,omitempty"`
 	UltimoAcceso      *time.Time     `json:"ultimo_acceso"`
 	FechaCreacion     time.Time      `json:"fecha_creacion" gorm:"autoCreateTime"`
 	FechaActualizacion time.Time     `json:"fecha_actualizacion" gorm:"autoUpdateTime"`
 	FechaEliminacion  gorm.DeletedAt `json:"fecha_eliminacion" gorm:"index"`
 	
 	// Relaciones
-	Notificaciones    []Notificacion `json:"notificaciones" gorm:"foreignKey:UsuarioID"`
+	// Sin clave foránea: las notificaciones anonimizadas por retención quedan con UsuarioID 0
+	Notificaciones    []Notificacion `json:"notificaciones" gorm:"foreignKey:UsuarioID;constraint:-"`
 	Canales           []Canal        `json:"canales" gorm:"many2many:usuario_canales;"`
 }
 
@@ -82,8 +90,7 @@ func (u *Usuario) EsModerador() bool {
 }
 
 // ActualizarUltimoAcceso actualiza la fecha del último acceso
-func (u *Usuario) ActualizarUltimoAcceso() {
-	ahora := time.Now()
+func (u *Usuario) ActualizarUltimoAcceso(ahora time.Time) {
 	u.UltimoAcceso = &ahora
 }
 
@@ -117,6 +124,21 @@ func (u *Usuario) CambiarRol(nuevoRol RolUsuario) {
 	u.Rol = nuevoRol
 }
 
+// Anonimizar reemplaza de forma irreversible los datos personales del usuario.
+// Nombre de usuario y correo quedan únicos por ID para respetar sus índices.
+func (u *Usuario) Anonimizar() {
+	u.NombreUsuario = fmt.Sprintf("eliminado-%d", u.ID)
+	u.CorreoElectronico = fmt.Sprintf("eliminado-%d@anonimo.invalid", u.ID)
+	u.Nombre = ""
+	u.Apellido = ""
+	u.Telefono = ""
+	u.CorreoVerificado = false
+	u.TelefonoVerificado = false
+	u.TelegramChatID = 0
+	u.UltimoAcceso = nil
+	u.Estado = EstadoInactivo
+}
+
 // Validar valida el usuario
 func (u *Usuario) Validar() error {
 	if u.NombreUsuario == "" {
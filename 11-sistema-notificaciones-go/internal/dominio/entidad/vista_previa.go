@@ -0,0 +1,105 @@
+package entidad
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Codificaciones en que puede viajar un SMS
+const (
+	CodificacionGSM  = "GSM-7"
+	CodificacionUCS2 = "UCS-2"
+)
+
+// DesgloseSMS muestra cómo se parte un mensaje en segmentos. Unidades se mide en la
+// codificación del mensaje: septetos GSM o unidades UTF-16.
+type DesgloseSMS struct {
+	Codificacion string `json:"codificacion"`
+	Unidades     int    `json:"unidades"`
+	// CapacidadSegmento es lo que entra en cada segmento; baja si el mensaje necesita más de uno
+	CapacidadSegmento int      `json:"capacidad_segmento"`
+	Segmentos         []string `json:"segmentos"`
+	// CaracteresNoGSM son los caracteres que obligan a usar UCS-2
+	CaracteresNoGSM []string `json:"caracteres_no_gsm,omitempty"`
+}
+
+// DesglosarSMS parte el mensaje en segmentos como lo hace el operador: un carácter de la
+// extensión GSM o un par sustituto nunca queda partido entre dos segmentos
+func DesglosarSMS(mensaje string) DesgloseSMS {
+	desglose := DesgloseSMS{Codificacion: CodificacionGSM}
+	vistos := map[rune]bool{}
+	for _, caracter := range mensaje {
+		if !strings.ContainsRune(alfabetoGSM, caracter) && !strings.ContainsRune(extensionGSM, caracter) && !vistos[caracter] {
+			vistos[caracter] = true
+			desglose.Codificacion = CodificacionUCS2
+			desglose.CaracteresNoGSM = append(desglose.CaracteresNoGSM, string(caracter))
+		}
+	}
+
+	simple, multiple := caracteresSegmentoGSM, caracteresSegmentoGSMMultiple
+	if desglose.Codificacion == CodificacionUCS2 {
+		simple, multiple = caracteresSegmentoUCS2, caracteresSegmentoUCS2Multiple
+	}
+	for _, caracter := range mensaje {
+		desglose.Unidades += unidadesSMS(caracter, desglose.Codificacion)
+	}
+	if desglose.Unidades <= simple {
+		desglose.CapacidadSegmento = simple
+		desglose.Segmentos = []string{mensaje}
+		return desglose
+	}
+
+	desglose.CapacidadSegmento = multiple
+	var actual strings.Builder
+	ocupado := 0
+	for _, caracter := range mensaje {
+		largo := unidadesSMS(caracter, desglose.Codificacion)
+		if ocupado+largo > multiple {
+			desglose.Segmentos = append(desglose.Segmentos, actual.String())
+			actual.Reset()
+			ocupado = 0
+		}
+		actual.WriteRune(caracter)
+		ocupado += largo
+	}
+	desglose.Segmentos = append(desglose.Segmentos, actual.String())
+	return desglose
+}
+
+// unidadesSMS es lo que ocupa un carácter en la codificación dada
+func unidadesSMS(caracter rune, codificacion string) int {
+	if codificacion == CodificacionUCS2 {
+		if caracter > 0xFFFF {
+			return 2
+		}
+		return 1
+	}
+	if strings.ContainsRune(extensionGSM, caracter) {
+		return 2
+	}
+	return 1
+}
+
+// LimitePush es lo que muestra una plataforma de la notificación push antes de cortarla
+type LimitePush struct {
+	Plataforma string
+	Titulo     int
+	Cuerpo     int
+}
+
+// LimitesPush son los caracteres visibles en la notificación sin expandir de cada plataforma
+var LimitesPush = []LimitePush{
+	{Plataforma: "ios", Titulo: 50, Cuerpo: 178},
+	{Plataforma: "android", Titulo: 65, Cuerpo: 240},
+	{Plataforma: "web", Titulo: 50, Cuerpo: 120},
+}
+
+// TruncarTexto corta el texto a maximo caracteres terminando en puntos suspensivos, como lo
+// muestra el dispositivo; el segundo valor indica si hubo que cortarlo
+func TruncarTexto(texto string, maximo int) (string, bool) {
+	if utf8.RuneCountInString(texto) <= maximo {
+		return texto, false
+	}
+	caracteres := []rune(texto)
+	return string(caracteres[:maximo-1]) + "…", true
+}
@@ -0,0 +1,13 @@
+package repositorio
+
+import (
+	"context"
+	"io"
+)
+
+// AlmacenArchivos guarda archivos generados (p. ej. exportaciones) para descargarlos después
+type AlmacenArchivos interface {
+	// Crear abre el archivo para escritura; solo queda disponible al cerrarlo sin error
+	Crear(ctx context.Context, nombre string) (io.WriteCloser, error)
+	Abrir(ctx context.Context, nombre string) (io.ReadCloser, error)
+}
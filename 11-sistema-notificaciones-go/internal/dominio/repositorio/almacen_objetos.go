@@ -0,0 +1,23 @@
+package repositorio
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// AlmacenObjetos guarda archivos subidos (p. ej. adjuntos) en un almacén compatible con S3
+type AlmacenObjetos interface {
+	Subir(ctx context.Context, clave, tipoContenido string, contenido io.Reader, tamano int64) error
+	Abrir(ctx context.Context, clave string) (io.ReadCloser, error)
+	Eliminar(ctx context.Context, clave string) error
+	// URLDescarga firma una URL de descarga directa del almacén, válida por vigencia, que
+	// entrega el archivo con el nombre y el tipo indicados
+	URLDescarga(clave, nombre, tipoContenido string, vigencia time.Duration) (string, error)
+}
+
+// AnalizadorArchivos revisa un archivo subido antes de guardarlo, p. ej. con un antivirus
+type AnalizadorArchivos interface {
+	// Analizar retorna el nombre de la amenaza detectada, o vacío si el archivo está limpio
+	Analizar(ctx context.Context, contenido io.Reader) (string, error)
+}
@@ -0,0 +1,15 @@
+package repositorio
+
+import (
+	"context"
+	"time"
+)
+
+// Arrendamiento es un lease con vencimiento sobre una clave compartida entre instancias: solo un
+// titular lo tiene a la vez y, si deja de renovarlo, queda libre al vencer
+type Arrendamiento interface {
+	// Adquirir toma la clave si está libre o renueva su vencimiento si ya es del titular
+	Adquirir(ctx context.Context, clave, titular string, duracion time.Duration) (bool, error)
+	// Liberar suelta la clave solo si todavía es del titular
+	Liberar(ctx context.Context, clave, titular string) error
+}
@@ -0,0 +1,13 @@
+package repositorio
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// AvisadorLectura avisa al servicio de origen que el usuario leyó la notificación, por el
+// webhook o el tema de la cola que declaró al enviarla
+type AvisadorLectura interface {
+	Avisar(ctx context.Context, notificacion *entidad.Notificacion) error
+}
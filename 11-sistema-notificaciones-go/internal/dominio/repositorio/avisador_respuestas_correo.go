@@ -0,0 +1,13 @@
+package repositorio
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// AvisadorRespuestasCorreo reenvía al webhook del canal las respuestas por correo de los
+// destinatarios a sus notificaciones
+type AvisadorRespuestasCorreo interface {
+	AvisarRespuesta(ctx context.Context, webhook string, respuesta *entidad.RespuestaCorreo) error
+}
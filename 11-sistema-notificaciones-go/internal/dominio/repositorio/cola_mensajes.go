@@ -0,0 +1,13 @@
+package repositorio
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// ColaMensajes define la publicación de notificaciones para su envío asíncrono
+type ColaMensajes interface {
+	Publicar(ctx context.Context, notificacion *entidad.Notificacion) error
+	PublicarLote(ctx context.Context, notificaciones []*entidad.Notificacion) error
+}
@@ -0,0 +1,14 @@
+package repositorio
+
+import (
+	"context"
+	"time"
+)
+
+// ContadorUso mantiene contadores atómicos compartidos entre instancias
+type ContadorUso interface {
+	// Incrementar suma cantidad (puede ser negativa) y retorna el valor resultante
+	Incrementar(ctx context.Context, clave string, cantidad int64, expiracion time.Duration) (int64, error)
+	// Obtener retorna el valor de cada clave, 0 si no existe
+	Obtener(ctx context.Context, claves ...string) ([]int64, error)
+}
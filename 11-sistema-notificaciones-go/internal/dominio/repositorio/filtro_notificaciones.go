@@ -0,0 +1,91 @@
+package repositorio
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// RelacionNotificacion identifica una relación que puede precargarse
+type RelacionNotificacion string
+
+const (
+	RelacionUsuario RelacionNotificacion = "usuario"
+	RelacionCanal   RelacionNotificacion = "canal"
+)
+
+// relacionesValidas mapea cada relación a su nombre de asociación en el modelo
+var relacionesValidas = map[RelacionNotificacion]string{
+	RelacionUsuario: "Usuario",
+	RelacionCanal:   "Canal",
+}
+
+// ParsearRelaciones valida una lista separada por comas (p. ej. "canal,usuario")
+func ParsearRelaciones(valor string) ([]RelacionNotificacion, error) {
+	if strings.TrimSpace(valor) == "" {
+		return nil, nil
+	}
+
+	var relaciones []RelacionNotificacion
+	vistas := make(map[RelacionNotificacion]bool)
+	for _, parte := range strings.Split(valor, ",") {
+		relacion := RelacionNotificacion(strings.ToLower(strings.TrimSpace(parte)))
+		if _, valida := relacionesValidas[relacion]; !valida {
+			return nil, entidad.NewErrorValidacion("Relación no soportada en incluir: " + string(relacion))
+		}
+		if !vistas[relacion] {
+			vistas[relacion] = true
+			relaciones = append(relaciones, relacion)
+		}
+	}
+	return relaciones, nil
+}
+
+// Asociacion retorna el nombre de la asociación del modelo para la relación
+func (r RelacionNotificacion) Asociacion() string {
+	return relacionesValidas[r]
+}
+
+// FiltroNotificaciones define los criterios de búsqueda de notificaciones
+type FiltroNotificaciones struct {
+	// InquilinoID 0 no filtra por inquilino
+	InquilinoID uint
+	UsuarioID   uint
+	CanalID     uint
+	EnvioID     uint
+	Estado      entidad.EstadoNotificacion
+	Tipo        entidad.TipoNotificacion
+	// Agotadas retorna solo las que ya no tienen reintentos (la cola de mensajes muertos)
+	Agotadas bool
+	Desde    *time.Time
+	Hasta    *time.Time
+	// Bandeja retorna solo las notificaciones in_app que el usuario ve en esa carpeta o vista
+	Bandeja entidad.CarpetaBandeja
+	// Cursor retorna solo notificaciones con ID menor (paginación descendente por ID)
+	Cursor uint
+	// Limite 0 significa sin límite (solo para recorridos en flujo)
+	Limite int
+	// Incluir son las relaciones a precargar; por defecto ninguna
+	Incluir []RelacionNotificacion
+}
+
+// VersionPagina cambia cuando la página se modifica: una notificación nueva o eliminada mueve
+// las filas o los IDs extremos, y cualquier actualización incrementa la suma de versiones
+type VersionPagina struct {
+	Filas               int64
+	PrimerID            uint
+	UltimoID            uint
+	SumaVersiones       int64
+	UltimaActualizacion *time.Time
+}
+
+// String resume la versión en texto estable, p. ej. para derivar un ETag
+func (v *VersionPagina) String() string {
+	var actualizacion int64
+	if v.UltimaActualizacion != nil {
+		actualizacion = v.UltimaActualizacion.UnixNano()
+	}
+	return fmt.Sprintf("%d:%d:%d:%d:%d", v.Filas, v.PrimerID, v.UltimoID, v.SumaVersiones, actualizacion)
+}
@@ -0,0 +1,17 @@
+package repositorio
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// LectorDatosInquilino recorre en flujo todos los datos de un inquilino para exportarlos
+type LectorDatosInquilino interface {
+	RecorrerUsuarios(ctx context.Context, inquilinoID uint, procesar func(*entidad.Usuario) error) error
+	RecorrerCanales(ctx context.Context, inquilinoID uint, procesar func(*entidad.Canal) error) error
+	RecorrerNotificaciones(ctx context.Context, inquilinoID uint, procesar func(*entidad.Notificacion) error) error
+	RecorrerPreferencias(ctx context.Context, inquilinoID uint, procesar func(*entidad.PreferenciaNotificacion) error) error
+	// RecorrerIntentos recorre los intentos de envío de las notificaciones del inquilino
+	RecorrerIntentos(ctx context.Context, inquilinoID uint, procesar func(*entidad.IntentoEnvio) error) error
+}
@@ -0,0 +1,20 @@
+package repositorio
+
+import (
+	"context"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// RegistroMensajesProveedor recuerda a qué notificación pertenece cada mensaje aceptado por un
+// proveedor mientras se esperan sus acuses de entrega
+type RegistroMensajesProveedor interface {
+	// Registrar guarda la referencia de cada ID de mensaje durante la vigencia
+	Registrar(ctx context.Context, proveedor string, idsMensaje []string, referencia entidad.ReferenciaMensaje, vigencia time.Duration) error
+	// Resolver retorna la referencia del mensaje, o nil si no se registró o ya venció
+	Resolver(ctx context.Context, proveedor, idMensaje string) (*entidad.ReferenciaMensaje, error)
+	// MarcarEntregado anota el mensaje como entregado y retorna cuántas partes de la
+	// notificación ya se entregaron; un acuse repetido no se cuenta dos veces
+	MarcarEntregado(ctx context.Context, proveedor, idMensaje string, referencia entidad.ReferenciaMensaje, vigencia time.Duration) (int64, error)
+}
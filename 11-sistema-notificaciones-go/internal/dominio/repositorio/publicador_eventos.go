@@ -0,0 +1,9 @@
+package repositorio
+
+import "context"
+
+// PublicadorEventos publica eventos en tiempo real a las sesiones abiertas de un usuario
+type PublicadorEventos interface {
+	// Publicar envía el evento a cada sesión del usuario; sin sesiones abiertas no hace nada
+	Publicar(ctx context.Context, usuarioID uint, tipo string, datos any) error
+}
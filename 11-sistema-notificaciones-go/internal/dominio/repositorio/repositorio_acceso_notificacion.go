@@ -0,0 +1,39 @@
+package repositorio
+
+import (
+	"context"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// FiltroAccesos define los criterios de consulta del registro de accesos del personal.
+// Los campos vacíos no filtran.
+type FiltroAccesos struct {
+	InquilinoID uint
+	ActorID     uint
+	UsuarioID   uint
+	Desde       time.Time
+	Hasta       time.Time
+	// DesdeID y HastaID acotan por ID, ambos inclusivos
+	DesdeID uint
+	HastaID uint
+	// Cursor pagina por ID descendente: retorna accesos con ID menor
+	Cursor uint
+	Limite int
+}
+
+// RepositorioAccesoNotificacion define la persistencia del registro de accesos del personal.
+// Solo admite agregar y consultar: los registros no se modifican ni se borran.
+type RepositorioAccesoNotificacion interface {
+	// Registrar agrega el acceso al final de la cadena de hashes
+	Registrar(ctx context.Context, acceso *entidad.AccesoNotificacion) error
+	Listar(ctx context.Context, filtro FiltroAccesos) ([]entidad.AccesoNotificacion, error)
+	// RangoIDs retorna el menor y el mayor ID de los accesos que cumplen Desde, Hasta, DesdeID y
+	// HastaID; ceros si no hay ninguno. Ignora el resto de los campos del filtro.
+	RangoIDs(ctx context.Context, filtro FiltroAccesos) (primero, ultimo uint, err error)
+	// Previo retorna el último acceso encadenado con ID menor a id; nil si no hay
+	Previo(ctx context.Context, id uint) (*entidad.AccesoNotificacion, error)
+	// Recorrer procesa todos los accesos con ID en [primero, ultimo] en el orden de la cadena
+	Recorrer(ctx context.Context, primero, ultimo uint, procesar func(*entidad.AccesoNotificacion) error) error
+}
@@ -0,0 +1,20 @@
+package repositorio
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// RepositorioAdjunto define la persistencia de los datos de los adjuntos; el contenido queda en
+// el AlmacenObjetos
+type RepositorioAdjunto interface {
+	Crear(ctx context.Context, adjunto *entidad.Adjunto) error
+	ObtenerPorID(ctx context.Context, id uint) (*entidad.Adjunto, error)
+	ListarPorIDs(ctx context.Context, ids []uint) ([]entidad.Adjunto, error)
+	ListarPorNotificacion(ctx context.Context, notificacionID uint) ([]entidad.Adjunto, error)
+	// Vincular asigna la notificación a los adjuntos del inquilino que todavía no tienen una y
+	// retorna cuántos vinculó
+	Vincular(ctx context.Context, inquilinoID uint, ids []uint, notificacionID uint) (int64, error)
+	Eliminar(ctx context.Context, id uint) error
+}
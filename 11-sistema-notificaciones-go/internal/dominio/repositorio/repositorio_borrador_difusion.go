@@ -0,0 +1,18 @@
+package repositorio
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// RepositorioBorradorDifusion define la persistencia de las difusiones en preparación y aprobación
+type RepositorioBorradorDifusion interface {
+	Crear(ctx context.Context, borrador *entidad.BorradorDifusion) error
+	ObtenerPorID(ctx context.Context, id uint) (*entidad.BorradorDifusion, error)
+	// ListarPorCanal obtiene los borradores del canal, opcionalmente filtrados por estado
+	ListarPorCanal(ctx context.Context, canalID uint, estado entidad.EstadoBorradorDifusion) ([]entidad.BorradorDifusion, error)
+	// Actualizar guarda el borrador solo si sigue en estadoAnterior; retorna false si otra
+	// solicitud lo cambió antes, p. ej. dos aprobaciones simultáneas
+	Actualizar(ctx context.Context, borrador *entidad.BorradorDifusion, estadoAnterior entidad.EstadoBorradorDifusion) (bool, error)
+}
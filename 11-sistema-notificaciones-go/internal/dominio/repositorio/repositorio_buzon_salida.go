@@ -0,0 +1,20 @@
+package repositorio
+
+import (
+	"context"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// RepositorioBuzonSalida define la persistencia del buzón de salida (transactional outbox). Sus
+// entradas viven junto a las notificaciones, así se escriben en la misma transacción.
+type RepositorioBuzonSalida interface {
+	Agregar(ctx context.Context, entradas []*entidad.EntradaBuzonSalida) error
+	// ListarAnteriores retorna las entradas escritas hasta la fecha indicada, las más antiguas primero
+	ListarAnteriores(ctx context.Context, hasta time.Time, limite int) ([]entidad.EntradaBuzonSalida, error)
+	Eliminar(ctx context.Context, notificacionIDs []uint) error
+	// Aplazar cambia la fecha de las entradas indicadas, así el relevo vuelve a tomarlas recién
+	// pasada la antigüedad
+	Aplazar(ctx context.Context, notificacionIDs []uint, fecha time.Time) error
+}
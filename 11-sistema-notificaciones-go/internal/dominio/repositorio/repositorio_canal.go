@@ -0,0 +1,27 @@
+package repositorio
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// RepositorioCanal define la persistencia de canales
+type RepositorioCanal interface {
+	Crear(ctx context.Context, canal *entidad.Canal) error
+	ObtenerPorID(ctx context.Context, id uint) (*entidad.Canal, error)
+	Actualizar(ctx context.Context, canal *entidad.Canal) error
+	// ListarIDsSuscriptores pagina por cursor los IDs de usuarios con suscripción confirmada y ID mayor a desdeID
+	ListarIDsSuscriptores(ctx context.Context, canalID, desdeID uint, limite int) ([]uint, error)
+	// ContarSuscriptores cuenta las suscripciones confirmadas del canal
+	ContarSuscriptores(ctx context.Context, canalID uint) (int64, error)
+	ObtenerSuscripcion(ctx context.Context, usuarioID, canalID uint) (*entidad.SuscripcionCanal, error)
+	ListarSuscripciones(ctx context.Context, usuarioID uint) ([]entidad.SuscripcionCanal, error)
+	// ListarMembresias pagina por cursor de usuario las membresías del canal, opcionalmente en un estado
+	ListarMembresias(ctx context.Context, canalID uint, estado entidad.EstadoSuscripcion, desdeID uint, limite int) ([]entidad.SuscripcionCanal, error)
+	// GuardarSuscripcion crea o actualiza la suscripción del par (usuario, canal)
+	GuardarSuscripcion(ctx context.Context, suscripcion *entidad.SuscripcionCanal) error
+	EliminarSuscripcion(ctx context.Context, usuarioID, canalID uint) error
+	// DesvincularUsuario quita al usuario de todos los canales a los que está suscrito
+	DesvincularUsuario(ctx context.Context, usuarioID uint) (int64, error)
+}
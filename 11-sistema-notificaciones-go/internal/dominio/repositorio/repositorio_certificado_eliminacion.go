@@ -0,0 +1,15 @@
+package repositorio
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// RepositorioCertificadoEliminacion define la persistencia de los certificados de eliminación
+type RepositorioCertificadoEliminacion interface {
+	Crear(ctx context.Context, certificado *entidad.CertificadoEliminacion) error
+	Actualizar(ctx context.Context, certificado *entidad.CertificadoEliminacion) error
+	// ObtenerUltimo retorna el certificado más reciente del usuario
+	ObtenerUltimo(ctx context.Context, usuarioID uint) (*entidad.CertificadoEliminacion, error)
+}
@@ -0,0 +1,17 @@
+package repositorio
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// RepositorioClaveAPI define la persistencia de claves de API
+type RepositorioClaveAPI interface {
+	Crear(ctx context.Context, clave *entidad.ClaveAPI) error
+	ObtenerPorID(ctx context.Context, id uint) (*entidad.ClaveAPI, error)
+	ObtenerPorPrefijo(ctx context.Context, prefijo string) (*entidad.ClaveAPI, error)
+	// Listar retorna las claves del inquilino, o todas si inquilinoID es 0
+	Listar(ctx context.Context, inquilinoID uint) ([]entidad.ClaveAPI, error)
+	Actualizar(ctx context.Context, clave *entidad.ClaveAPI) error
+}
@@ -0,0 +1,26 @@
+package repositorio
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// FiltroColaMuerta define los criterios de consulta de la cola de mensajes muertos.
+// Los campos vacíos no filtran.
+type FiltroColaMuerta struct {
+	InquilinoID uint
+	Tipo        entidad.TipoNotificacion
+	// Cursor pagina por ID descendente: retorna entradas con ID menor
+	Cursor uint
+	Limite int
+}
+
+// RepositorioColaMuerta define la persistencia de la cola de mensajes muertos
+type RepositorioColaMuerta interface {
+	// Agregar guarda la entrada; si la notificación ya tenía una, la reemplaza
+	Agregar(ctx context.Context, entrada *entidad.EntradaColaMuerta) error
+	Listar(ctx context.Context, filtro FiltroColaMuerta) ([]entidad.EntradaColaMuerta, error)
+	ObtenerPorID(ctx context.Context, id uint) (*entidad.EntradaColaMuerta, error)
+	Eliminar(ctx context.Context, id uint) error
+}
@@ -0,0 +1,17 @@
+package repositorio
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// RepositorioConsentimiento define la persistencia del historial de consentimientos
+type RepositorioConsentimiento interface {
+	Registrar(ctx context.Context, consentimiento *entidad.Consentimiento) error
+	// ObtenerVigente retorna la decisión más reciente del usuario para el propósito que aplica
+	// al canal (la del canal o la general del propósito); nil si nunca registró una
+	ObtenerVigente(ctx context.Context, usuarioID uint, proposito entidad.PropositoConsentimiento, canalID uint) (*entidad.Consentimiento, error)
+	// ListarPorUsuario retorna el historial del usuario, del más reciente al más antiguo
+	ListarPorUsuario(ctx context.Context, usuarioID uint) ([]entidad.Consentimiento, error)
+}
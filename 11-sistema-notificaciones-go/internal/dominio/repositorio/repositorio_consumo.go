@@ -0,0 +1,16 @@
+package repositorio
+
+import (
+	"context"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// RepositorioConsumo define la persistencia de la medición de envíos
+type RepositorioConsumo interface {
+	// Registrar guarda la medición; una notificación ya medida se ignora
+	Registrar(ctx context.Context, registro *entidad.RegistroConsumo) error
+	// Resumir agrega el consumo en [desde, hasta); inquilinoID 0 incluye a todos los inquilinos
+	Resumir(ctx context.Context, desde, hasta time.Time, inquilinoID uint) ([]entidad.LineaFacturacion, error)
+}
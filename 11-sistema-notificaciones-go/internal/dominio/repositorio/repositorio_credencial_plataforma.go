@@ -0,0 +1,24 @@
+package repositorio
+
+import (
+	"context"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// RepositorioCredencialPlataforma define la persistencia de los juegos de credenciales de la
+// plataforma por proveedor
+type RepositorioCredencialPlataforma interface {
+	// Listar retorna todos los juegos, por proveedor y nombre
+	Listar(ctx context.Context) ([]entidad.CredencialPlataforma, error)
+	// ObtenerActiva retorna el juego activo del proveedor, o nil si no tiene
+	ObtenerActiva(ctx context.Context, proveedor string) (*entidad.CredencialPlataforma, error)
+	// Guardar crea el juego o reemplaza los datos del que tiene el mismo proveedor y nombre
+	Guardar(ctx context.Context, credencial *entidad.CredencialPlataforma) error
+	// Activar activa el juego desde fecha y desactiva el que estuviera activo del mismo
+	// proveedor, en una transacción
+	Activar(ctx context.Context, proveedor, nombre string, fecha time.Time) error
+	// Eliminar borra un juego inactivo
+	Eliminar(ctx context.Context, proveedor, nombre string) error
+}
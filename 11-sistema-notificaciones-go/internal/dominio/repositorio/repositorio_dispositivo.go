@@ -0,0 +1,18 @@
+package repositorio
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// RepositorioDispositivo define la persistencia de tokens push
+type RepositorioDispositivo interface {
+	ListarPorUsuario(ctx context.Context, usuarioID uint) ([]entidad.DispositivoPush, error)
+	// Guardar registra el token o, si ya existe, lo reasigna al usuario con sus nuevas claves
+	Guardar(ctx context.Context, dispositivo *entidad.DispositivoPush) error
+	// EliminarPorToken borra el token del usuario; ErrDispositivoNoEncontrado si no es suyo
+	EliminarPorToken(ctx context.Context, usuarioID uint, token string) error
+	// EliminarPorUsuario borra definitivamente los tokens del usuario
+	EliminarPorUsuario(ctx context.Context, usuarioID uint) (int64, error)
+}
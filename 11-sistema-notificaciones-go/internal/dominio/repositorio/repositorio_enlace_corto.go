@@ -0,0 +1,20 @@
+package repositorio
+
+import (
+	"context"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// RepositorioEnlaceCorto define la persistencia de los enlaces cortos de los SMS
+type RepositorioEnlaceCorto interface {
+	// Crear inserta el enlace; retorna false, sin error, si el slug ya estaba en uso
+	Crear(ctx context.Context, enlace *entidad.EnlaceCorto) (bool, error)
+	ObtenerPorSlug(ctx context.Context, slug string) (*entidad.EnlaceCorto, error)
+	// RegistrarClic suma un clic al enlace con un UPDATE atómico
+	RegistrarClic(ctx context.Context, id uint, fecha time.Time) error
+	// ListarPorNotificacion filtra también por inquilino: los IDs de notificación se repiten entre
+	// los esquemas de los inquilinos aislados
+	ListarPorNotificacion(ctx context.Context, inquilinoID, notificacionID uint) ([]entidad.EnlaceCorto, error)
+}
@@ -0,0 +1,16 @@
+package repositorio
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// RepositorioEnvioMultiCanal define la persistencia de envíos multicanal y sus pasos
+type RepositorioEnvioMultiCanal interface {
+	// Crear guarda el envío junto con sus pasos
+	Crear(ctx context.Context, envio *entidad.EnvioMultiCanal) error
+	ObtenerPorID(ctx context.Context, id uint) (*entidad.EnvioMultiCanal, error)
+	// ActualizarPaso guarda el estado del paso y el estado global del envío
+	ActualizarPaso(ctx context.Context, envio *entidad.EnvioMultiCanal, paso *entidad.PasoEnvio) error
+}
@@ -0,0 +1,28 @@
+package repositorio
+
+import (
+	"context"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// RepositorioEscalamiento define la persistencia de las políticas de escalamiento y sus ejecuciones
+type RepositorioEscalamiento interface {
+	// ObtenerPolitica retorna la política del canal o ErrSinPoliticaEscalamiento
+	ObtenerPolitica(ctx context.Context, canalID uint) (*entidad.PoliticaEscalamiento, error)
+	// GuardarPolitica crea o reemplaza los pasos de la política del canal
+	GuardarPolitica(ctx context.Context, politica *entidad.PoliticaEscalamiento) error
+	EliminarPolitica(ctx context.Context, canalID uint) error
+	Crear(ctx context.Context, escalamiento *entidad.Escalamiento) error
+	ObtenerPorID(ctx context.Context, id uint) (*entidad.Escalamiento, error)
+	// ListarVencidos obtiene los activos cuyo próximo aviso ya pasó
+	ListarVencidos(ctx context.Context, hasta time.Time, limite int) ([]entidad.Escalamiento, error)
+	// Avanzar guarda el paso siguiente solo si el escalamiento sigue activo en pasoAnterior;
+	// retorna false si otra instancia lo avanzó o alguien lo reconoció antes
+	Avanzar(ctx context.Context, escalamiento *entidad.Escalamiento, pasoAnterior int) (bool, error)
+	// AgregarAviso suma el aviso a los registrados sin pisar los de otros pasos
+	AgregarAviso(ctx context.Context, escalamientoID uint, aviso entidad.AvisoEscalamiento) error
+	// Reconocer guarda el reconocimiento solo si el escalamiento sigue activo
+	Reconocer(ctx context.Context, escalamiento *entidad.Escalamiento) (bool, error)
+}
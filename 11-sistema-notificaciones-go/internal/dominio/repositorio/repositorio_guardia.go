@@ -0,0 +1,24 @@
+package repositorio
+
+import (
+	"context"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// RepositorioGuardia define la persistencia de las rotaciones de guardia y sus reemplazos
+type RepositorioGuardia interface {
+	// ObtenerRotacion retorna la rotación del canal o ErrSinRotacionGuardia
+	ObtenerRotacion(ctx context.Context, canalID uint) (*entidad.RotacionGuardia, error)
+	// GuardarRotacion crea o reemplaza la rotación del canal
+	GuardarRotacion(ctx context.Context, rotacion *entidad.RotacionGuardia) error
+	// EliminarRotacion elimina la rotación del canal junto con sus reemplazos
+	EliminarRotacion(ctx context.Context, canalID uint) error
+	CrearReemplazo(ctx context.Context, reemplazo *entidad.ReemplazoGuardia) error
+	// ListarReemplazos obtiene los reemplazos de la rotación que terminan después de desde
+	ListarReemplazos(ctx context.Context, rotacionID uint, desde time.Time) ([]entidad.ReemplazoGuardia, error)
+	// ReemplazoVigente retorna el reemplazo más reciente que cubre el instante, o nil
+	ReemplazoVigente(ctx context.Context, rotacionID uint, instante time.Time) (*entidad.ReemplazoGuardia, error)
+	EliminarReemplazo(ctx context.Context, rotacionID, id uint) error
+}
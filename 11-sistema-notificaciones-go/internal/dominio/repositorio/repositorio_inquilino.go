@@ -0,0 +1,38 @@
+package repositorio
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// RepositorioInquilino define la persistencia de inquilinos y su configuración (cuotas, credenciales, SLA, marca)
+type RepositorioInquilino interface {
+	Crear(ctx context.Context, inquilino *entidad.Inquilino) error
+	ObtenerPorID(ctx context.Context, id uint) (*entidad.Inquilino, error)
+	// ListarAislados retorna los inquilinos cuyos datos no están en las tablas comunes de la
+	// base principal: los que tienen esquema propio o residen en otra región
+	ListarAislados(ctx context.Context) ([]entidad.Inquilino, error)
+	ListarCuotas(ctx context.Context, inquilinoID uint) ([]entidad.CuotaInquilino, error)
+	// GuardarCuota crea o actualiza la cuota del par (inquilino, tipo)
+	GuardarCuota(ctx context.Context, cuota *entidad.CuotaInquilino) error
+	ListarCredenciales(ctx context.Context, inquilinoID uint) ([]entidad.CredencialProveedor, error)
+	// GuardarCredencial crea o reemplaza la credencial del par (inquilino, proveedor)
+	GuardarCredencial(ctx context.Context, credencial *entidad.CredencialProveedor) error
+	EliminarCredencial(ctx context.Context, inquilinoID uint, proveedor string) error
+	// ListarObjetivosSLA retorna los objetivos del inquilino, o los de todos si inquilinoID es 0
+	ListarObjetivosSLA(ctx context.Context, inquilinoID uint) ([]entidad.ObjetivoSLA, error)
+	// GuardarObjetivoSLA crea o actualiza el objetivo del par (inquilino, prioridad)
+	GuardarObjetivoSLA(ctx context.Context, objetivo *entidad.ObjetivoSLA) error
+	ObtenerMarca(ctx context.Context, inquilinoID uint) (*entidad.MarcaInquilino, error)
+	// GuardarMarca crea o reemplaza la marca del inquilino
+	GuardarMarca(ctx context.Context, marca *entidad.MarcaInquilino) error
+}
+
+// AprovisionadorEsquemas crea y migra el esquema de base de datos de un inquilino aislado
+// en la base de la región del contexto; sin esquema migra las tablas comunes de esa región
+type AprovisionadorEsquemas interface {
+	Aprovisionar(ctx context.Context, esquema string) error
+	// RegionConfigurada indica si la región tiene una base de datos propia configurada
+	RegionConfigurada(region string) bool
+}
@@ -0,0 +1,22 @@
+package repositorio
+
+import (
+	"context"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// RepositorioIntentoEnvio define la persistencia del registro de intentos de envío
+type RepositorioIntentoEnvio interface {
+	Crear(ctx context.Context, intento *entidad.IntentoEnvio) error
+	Actualizar(ctx context.Context, intento *entidad.IntentoEnvio) error
+	// ObtenerUltimo retorna el intento más reciente o nil si no hay ninguno
+	ObtenerUltimo(ctx context.Context, notificacionID uint) (*entidad.IntentoEnvio, error)
+	// AnonimizarPorUsuario borra el detalle de error (puede incluir destinatarios) de los
+	// intentos de las notificaciones del usuario
+	AnonimizarPorUsuario(ctx context.Context, usuarioID uint) (int64, error)
+	// PrincipalesErrores agrupa los intentos fallidos iniciados en [desde, hasta) por error y
+	// retorna los limite más frecuentes
+	PrincipalesErrores(ctx context.Context, desde, hasta time.Time, limite int) ([]entidad.ConteoError, error)
+}
@@ -0,0 +1,57 @@
+package repositorio
+
+import (
+	"context"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// FuncionProgreso recibe el avance de una operación por lotes
+type FuncionProgreso func(procesados, total int)
+
+// RepositorioNotificacion define la persistencia de notificaciones
+type RepositorioNotificacion interface {
+	Crear(ctx context.Context, notificacion *entidad.Notificacion) error
+	// CrearLote inserta las notificaciones en lotes de tamanoLote, reportando el avance tras cada lote
+	CrearLote(ctx context.Context, notificaciones []*entidad.Notificacion, tamanoLote int, progreso FuncionProgreso) error
+	ObtenerPorID(ctx context.Context, id uint, incluir ...RelacionNotificacion) (*entidad.Notificacion, error)
+	Actualizar(ctx context.Context, notificacion *entidad.Notificacion) error
+	Eliminar(ctx context.Context, id uint) error
+	// EliminarPorUsuario aplica soft delete a todas las notificaciones del usuario
+	EliminarPorUsuario(ctx context.Context, usuarioID uint) (int64, error)
+	// AnonimizarPorUsuario borra título, mensaje y metadatos de hasta limite notificaciones
+	// del usuario (incluidas las eliminadas) que aún no se anonimizaron
+	AnonimizarPorUsuario(ctx context.Context, usuarioID uint, limite int) (int64, error)
+	// PurgarPorRetencion borra definitivamente hasta limite notificaciones alcanzadas por la
+	// política y creadas antes de hasta (incluidas las eliminadas), junto a sus intentos de envío
+	PurgarPorRetencion(ctx context.Context, politica *entidad.PoliticaRetencion, hasta time.Time, limite int) (notificaciones, intentos int64, err error)
+	// AnonimizarPorRetencion anonimiza hasta limite notificaciones alcanzadas por la política,
+	// creadas antes de hasta y aún no anonimizadas: borra su contenido y el detalle de error de
+	// sus intentos, y reemplaza el usuario por la huella que calcula huella
+	AnonimizarPorRetencion(ctx context.Context, politica *entidad.PoliticaRetencion, hasta time.Time, limite int, huella func(usuarioID uint) string) (notificaciones, intentos int64, err error)
+	Listar(ctx context.Context, filtro FiltroNotificaciones) ([]entidad.Notificacion, error)
+	// Recorrer procesa fila a fila desde un cursor de base de datos, sin materializar el resultado
+	Recorrer(ctx context.Context, filtro FiltroNotificaciones, procesar func(*entidad.Notificacion) error) error
+	// VersionPagina resume la página que retornaría el filtro sin leerla, para detectar si cambió
+	VersionPagina(ctx context.Context, filtro FiltroNotificaciones) (*VersionPagina, error)
+	// ContarNoLeidas cuenta las notificaciones enviadas o entregadas aún no leídas del usuario
+	ContarNoLeidas(ctx context.Context, usuarioID uint) (int64, error)
+	// ContarBandeja cuenta las notificaciones y las no leídas de cada carpeta y vista de la
+	// bandeja del usuario; incluye las carpetas vacías
+	ContarBandeja(ctx context.Context, usuarioID uint) (map[entidad.CarpetaBandeja]entidad.ConteoCarpeta, error)
+	// ListarProgramadasVencidas obtiene las pendientes con fecha programada hasta el instante dado
+	// que no tengan una reserva vigente, de mayor a menor prioridad
+	ListarProgramadasVencidas(ctx context.Context, hasta time.Time, limite int) ([]entidad.Notificacion, error)
+	// ListarReintentosVencidos obtiene las fallidas cuya próxima fecha de reintento ya pasó
+	ListarReintentosVencidos(ctx context.Context, hasta time.Time, limite int) ([]entidad.Notificacion, error)
+	// ContarListasParaDespacho cuenta por prioridad las pendientes y los reintentos vencidos a
+	// la fecha, con la espera de la más antigua
+	ContarListasParaDespacho(ctx context.Context, hasta time.Time) ([]entidad.PendientesPrioridad, error)
+	// MedirEnvios cuenta las enviadas del inquilino y prioridad en [desde, hasta) y cuántas
+	// tardaron como máximo umbral en llegar al proveedor desde su creación (o su fecha programada)
+	MedirEnvios(ctx context.Context, inquilinoID uint, prioridad entidad.PrioridadNotificacion, umbral time.Duration, desde, hasta time.Time) (enviadas, dentroUmbral int64, err error)
+	// ContarPorEstado cuenta las notificaciones creadas en [desde, hasta), incluidas las
+	// eliminadas, agrupadas por su estado actual
+	ContarPorEstado(ctx context.Context, desde, hasta time.Time) (map[entidad.EstadoNotificacion]int64, error)
+}
@@ -0,0 +1,14 @@
+package repositorio
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// RepositorioPlantilla define la persistencia de plantillas
+type RepositorioPlantilla interface {
+	Crear(ctx context.Context, plantilla *entidad.Plantilla) error
+	ListarPorInquilino(ctx context.Context, inquilinoID uint) ([]entidad.Plantilla, error)
+	ObtenerPorNombre(ctx context.Context, inquilinoID uint, nombre string) (*entidad.Plantilla, error)
+}
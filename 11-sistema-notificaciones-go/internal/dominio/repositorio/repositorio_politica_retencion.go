@@ -0,0 +1,15 @@
+package repositorio
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// RepositorioPoliticaRetencion define la persistencia de las políticas de retención
+type RepositorioPoliticaRetencion interface {
+	Listar(ctx context.Context) ([]entidad.PoliticaRetencion, error)
+	// Guardar crea o actualiza la política del alcance (inquilino, tipo, canal) y acción
+	Guardar(ctx context.Context, politica *entidad.PoliticaRetencion) error
+	Eliminar(ctx context.Context, id uint) error
+}
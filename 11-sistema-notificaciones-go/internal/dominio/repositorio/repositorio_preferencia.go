@@ -0,0 +1,18 @@
+package repositorio
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// RepositorioPreferencia define la persistencia de preferencias de notificación
+type RepositorioPreferencia interface {
+	ListarPorUsuario(ctx context.Context, usuarioID uint) ([]entidad.PreferenciaNotificacion, error)
+	// Guardar crea o actualiza la preferencia del par (usuario, tipo)
+	Guardar(ctx context.Context, preferencia *entidad.PreferenciaNotificacion) error
+	// EliminarPorUsuario aplica soft delete a las preferencias del usuario
+	EliminarPorUsuario(ctx context.Context, usuarioID uint) (int64, error)
+	// PurgarPorUsuario borra definitivamente las preferencias del usuario, incluidas las eliminadas
+	PurgarPorUsuario(ctx context.Context, usuarioID uint) (int64, error)
+}
@@ -0,0 +1,19 @@
+package repositorio
+
+import (
+	"context"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// RepositorioReaccion define la persistencia de los eventos de reacción. Solo admite agregar y
+// consultar: quitar una reacción es un evento más.
+type RepositorioReaccion interface {
+	Registrar(ctx context.Context, evento *entidad.EventoReaccion) error
+	// Listar retorna los eventos de la notificación en orden cronológico
+	Listar(ctx context.Context, notificacionID uint) ([]entidad.EventoReaccion, error)
+	// ContarPorCanal retorna los destinatarios de las notificaciones del canal creadas en
+	// [desde, hasta) y, por reacción, cuántos de ellos la mantienen en alguna
+	ContarPorCanal(ctx context.Context, canalID uint, desde, hasta time.Time) (int64, map[entidad.TipoReaccion]int64, error)
+}
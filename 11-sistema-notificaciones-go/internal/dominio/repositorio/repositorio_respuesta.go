@@ -0,0 +1,17 @@
+package repositorio
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// RepositorioRespuesta define la persistencia de los hilos de respuestas de las notificaciones
+type RepositorioRespuesta interface {
+	Crear(ctx context.Context, respuesta *entidad.RespuestaNotificacion) error
+	// Listar retorna hasta limite respuestas del hilo con ID mayor a desdeID, de la más antigua
+	// a la más reciente
+	Listar(ctx context.Context, notificacionPadreID, desdeID uint, limite int) ([]entidad.RespuestaNotificacion, error)
+	// Autores retorna los usuarios que respondieron en el hilo
+	Autores(ctx context.Context, notificacionPadreID uint) ([]uint, error)
+}
@@ -0,0 +1,21 @@
+package repositorio
+
+import (
+	"context"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// RepositorioSilenciamiento define la persistencia de los silenciamientos de cada usuario
+type RepositorioSilenciamiento interface {
+	// Guardar crea el silenciamiento o, si el usuario ya silenciaba ese canal u origen, actualiza hasta
+	Guardar(ctx context.Context, silenciamiento *entidad.Silenciamiento) error
+	// ListarVigentes retorna los silenciamientos en curso del usuario
+	ListarVigentes(ctx context.Context, usuarioID uint, ahora time.Time) ([]entidad.Silenciamiento, error)
+	// BuscarVigente retorna el silenciamiento en curso del usuario para el canal o el origen, o
+	// nil si no hay ninguno
+	BuscarVigente(ctx context.Context, usuarioID, canalID uint, origen string, ahora time.Time) (*entidad.Silenciamiento, error)
+	// Eliminar borra el silenciamiento del usuario; ErrSilenciamientoNoEncontrado si no es suyo
+	Eliminar(ctx context.Context, usuarioID, id uint) error
+}
@@ -0,0 +1,33 @@
+package repositorio
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// FiltroSupresion define los criterios de búsqueda en la lista de supresión.
+// Los campos vacíos no filtran.
+type FiltroSupresion struct {
+	InquilinoID uint
+	// Valor busca las direcciones que empiezan con él (ya normalizado)
+	Valor  string
+	Motivo entidad.MotivoSupresion
+	// Cursor pagina por ID descendente: retorna entradas con ID menor
+	Cursor uint
+	Limite int
+}
+
+// RepositorioSupresion define la persistencia de la lista de no contactar
+type RepositorioSupresion interface {
+	// Agregar crea la entrada o, si la dirección ya estaba, actualiza motivo y detalle
+	Agregar(ctx context.Context, entrada *entidad.EntradaSupresion) error
+	// AgregarLote inserta las entradas nuevas ignorando las direcciones ya suprimidas;
+	// retorna cuántas se insertaron
+	AgregarLote(ctx context.Context, entradas []*entidad.EntradaSupresion) (int64, error)
+	ObtenerPorID(ctx context.Context, id uint) (*entidad.EntradaSupresion, error)
+	Eliminar(ctx context.Context, id uint) error
+	Buscar(ctx context.Context, filtro FiltroSupresion) ([]entidad.EntradaSupresion, error)
+	// Coincidencias retorna las entradas del inquilino y de la plataforma para los valores dados
+	Coincidencias(ctx context.Context, inquilinoID uint, valores []string) ([]entidad.EntradaSupresion, error)
+}
@@ -0,0 +1,19 @@
+package repositorio
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// RepositorioUsuario define la persistencia de usuarios
+type RepositorioUsuario interface {
+	Crear(ctx context.Context, usuario *entidad.Usuario) error
+	ObtenerPorID(ctx context.Context, id uint) (*entidad.Usuario, error)
+	// ObtenerIncluyendoEliminados obtiene el usuario aunque tenga soft delete
+	ObtenerIncluyendoEliminados(ctx context.Context, id uint) (*entidad.Usuario, error)
+	// Actualizar guarda los campos del usuario (sin tocar sus relaciones), aunque tenga soft delete
+	Actualizar(ctx context.Context, usuario *entidad.Usuario) error
+	// Eliminar aplica soft delete al usuario (sin tocar sus relaciones)
+	Eliminar(ctx context.Context, id uint) error
+}
@@ -0,0 +1,10 @@
+package repositorio
+
+import "context"
+
+// UnidadTrabajo ejecuta operaciones de varios repositorios de forma atómica.
+// Los repositorios que reciben el contexto entregado a la operación participan
+// de la misma transacción; si la operación retorna error, nada se persiste.
+type UnidadTrabajo interface {
+	Ejecutar(ctx context.Context, operacion func(ctx context.Context) error) error
+}
@@ -0,0 +1,20 @@
+package repositorio
+
+import (
+	"context"
+	"time"
+)
+
+// VentanaDeduplicacion recuerda las huellas de notificaciones creadas recientemente. La huella
+// se reclama antes de crear la notificación, así dos solicitudes idénticas concurrentes no
+// crean dos.
+type VentanaDeduplicacion interface {
+	// Reclamar reserva la huella si no había otra dentro de la ventana. Si la había, retorna el
+	// ID de la original, 0 mientras su creación no se confirme, y reclamada en false.
+	Reclamar(ctx context.Context, huella string, ventana time.Duration) (originalID uint, reclamada bool, err error)
+	// Confirmar asocia la huella reclamada a la notificación ya creada, sin extender la ventana
+	Confirmar(ctx context.Context, huella string, notificacionID uint) error
+	// Liberar elimina la huella si sigue asociada a notificacionID, 0 si solo estaba reclamada
+	// (p. ej. la creación se revirtió o la original ya no existe)
+	Liberar(ctx context.Context, huella string, notificacionID uint) error
+}
@@ -0,0 +1,47 @@
+package servicio
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+type claveActor struct{}
+
+type claveActorAutenticado struct{}
+
+type claveMotivoAcceso struct{}
+
+// ContextoConActor adjunta al contexto la persona que hace la solicitud a través de la aplicación cliente
+func ContextoConActor(ctx context.Context, actor *entidad.Usuario) context.Context {
+	return context.WithValue(ctx, claveActor{}, actor)
+}
+
+// ActorDesdeContexto retorna la persona que hace la solicitud, si la aplicación la informó
+func ActorDesdeContexto(ctx context.Context) (*entidad.Usuario, bool) {
+	actor, existe := ctx.Value(claveActor{}).(*entidad.Usuario)
+	return actor, existe && actor != nil
+}
+
+// ContextoConActorAutenticado adjunta al contexto la persona que hace la solicitud identificada
+// por su propio token, no solo por el encabezado que envía la aplicación cliente
+func ContextoConActorAutenticado(ctx context.Context, actor *entidad.Usuario) context.Context {
+	return context.WithValue(ContextoConActor(ctx, actor), claveActorAutenticado{}, actor)
+}
+
+// ActorAutenticadoDesdeContexto retorna la persona que hace la solicitud si se autenticó con su token
+func ActorAutenticadoDesdeContexto(ctx context.Context) (*entidad.Usuario, bool) {
+	actor, existe := ctx.Value(claveActorAutenticado{}).(*entidad.Usuario)
+	return actor, existe && actor != nil
+}
+
+// ContextoConMotivoAcceso adjunta al contexto la justificación declarada para la consulta
+func ContextoConMotivoAcceso(ctx context.Context, motivo string) context.Context {
+	return context.WithValue(ctx, claveMotivoAcceso{}, motivo)
+}
+
+// MotivoAccesoDesdeContexto retorna la justificación declarada o vacío si no hay
+func MotivoAccesoDesdeContexto(ctx context.Context) string {
+	motivo, _ := ctx.Value(claveMotivoAcceso{}).(string)
+	return motivo
+}
@@ -0,0 +1,17 @@
+package servicio
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// HuellaUsuario calcula la referencia seudónima de un usuario en los datos anonimizados.
+// Es estable, así la analítica sigue agrupando por usuario, pero al usar un secreto no se
+// puede revertir enumerando IDs.
+func HuellaUsuario(secreto []byte, usuarioID uint) string {
+	mac := hmac.New(sha256.New, secreto)
+	mac.Write([]byte(strconv.FormatUint(uint64(usuarioID), 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,101 @@
+package servicio
+
+import (
+	"crypto/hmac"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// VerificacionCadena resume la verificación de un tramo del registro de accesos. HashInicial y
+// HashFinal permiten empalmar exportaciones sucesivas: el inicial de una debe ser el final de
+// la anterior, lo que también delata registros borrados entre ambas. HashInicial es el hash del
+// registro que precede al tramo, con el que debe enlazar el primero.
+type VerificacionCadena struct {
+	Valida    bool  `json:"valida"`
+	Registros int64 `json:"registros"`
+	// SinEncadenar cuenta los registros previos a la incorporación de la cadena
+	SinEncadenar int64 `json:"sin_encadenar"`
+	// SellosVerificados indica si se verificaron los sellos HMAC; requiere la clave de la cadena
+	SellosVerificados bool `json:"sellos_verificados"`
+	// SinSellar cuenta los registros encadenados antes de configurar la clave
+	SinSellar   int64  `json:"sin_sellar"`
+	PrimerID    uint   `json:"primer_id,omitempty"`
+	UltimoID    uint   `json:"ultimo_id,omitempty"`
+	HashInicial string `json:"hash_inicial"`
+	HashFinal   string `json:"hash_final"`
+	// FallaID es el primer registro alterado o desenlazado; los siguientes no se verifican
+	FallaID uint   `json:"falla_id,omitempty"`
+	Falla   string `json:"falla,omitempty"`
+}
+
+// VerificadorCadena verifica los registros a medida que se recorren, sin retenerlos en memoria
+type VerificadorCadena struct {
+	resultado VerificacionCadena
+	iniciada  bool
+	clave     []byte
+	sellada   bool
+}
+
+// NuevoVerificadorCadena crea un verificador para el tramo que sigue a previo, el último registro
+// encadenado antes del tramo, o nil si el tramo abre la cadena. Sin clave no verifica sellos.
+func NuevoVerificadorCadena(previo *entidad.AccesoNotificacion, clave []byte) *VerificadorCadena {
+	v := &VerificadorCadena{clave: clave}
+	v.resultado.SellosVerificados = len(clave) > 0
+	if previo != nil {
+		v.iniciada = true
+		v.sellada = previo.Sello != ""
+		v.resultado.HashInicial = previo.Hash
+		v.resultado.HashFinal = previo.Hash
+	}
+	return v
+}
+
+// Verificar comprueba el hash del acceso, su enlace con el anterior y su sello. Sin registro
+// previo, el primero encadenado debe abrir la cadena con el hash anterior vacío.
+func (v *VerificadorCadena) Verificar(acceso *entidad.AccesoNotificacion) {
+	r := &v.resultado
+	r.Registros++
+	if r.PrimerID == 0 {
+		r.PrimerID = acceso.ID
+	}
+	r.UltimoID = acceso.ID
+	if r.FallaID != 0 {
+		return
+	}
+
+	switch {
+	case acceso.Hash == "" && !v.iniciada:
+		r.SinEncadenar++
+		return
+	case acceso.Hash == "":
+		v.fallar(acceso, "registro sin hash dentro de la cadena")
+	case acceso.HashAnterior != r.HashFinal:
+		v.fallar(acceso, "el hash anterior no coincide con el registro previo")
+	case acceso.CalcularHash() != acceso.Hash:
+		v.fallar(acceso, "el contenido no coincide con su hash")
+	case len(v.clave) == 0:
+	case acceso.Sello == "" && v.sellada:
+		v.fallar(acceso, "registro sin sello después de uno sellado")
+	case acceso.Sello == "":
+		r.SinSellar++
+	case !hmac.Equal([]byte(acceso.Sello), []byte(acceso.CalcularSello(v.clave))):
+		v.fallar(acceso, "el sello no corresponde al hash")
+	default:
+		v.sellada = true
+	}
+
+	v.iniciada = true
+	r.HashFinal = acceso.Hash
+}
+
+// Resultado retorna el resumen de lo verificado hasta el momento
+func (v *VerificadorCadena) Resultado() VerificacionCadena {
+	resultado := v.resultado
+	resultado.Valida = resultado.FallaID == 0
+	return resultado
+}
+
+func (v *VerificadorCadena) fallar(acceso *entidad.AccesoNotificacion, motivo string) {
+	v.resultado.FallaID = acceso.ID
+	v.resultado.Falla = motivo
+}
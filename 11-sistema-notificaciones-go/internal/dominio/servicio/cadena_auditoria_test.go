@@ -0,0 +1,115 @@
+package servicio
+
+import (
+	"testing"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+var claveSelloPrueba = []byte("clave-de-sello-de-pruebas-de-32-bytes")
+
+// cadenaDePrueba encadena n accesos desde el ID 1 como lo hace el repositorio
+func cadenaDePrueba(n int, clave []byte) []*entidad.AccesoNotificacion {
+	fecha := time.Date(2026, time.March, 1, 9, 0, 0, 0, time.UTC)
+	accesos := make([]*entidad.AccesoNotificacion, n)
+	anterior := ""
+	for i := range accesos {
+		accesos[i] = &entidad.AccesoNotificacion{
+			ID:        uint(i + 1),
+			ActorID:   3,
+			UsuarioID: 7,
+			Operacion: entidad.OperacionAccesoDetalle,
+			Motivo:    "ticket de soporte",
+			Fecha:     fecha.Add(time.Duration(i) * time.Minute),
+		}
+		accesos[i].Encadenar(anterior, clave)
+		anterior = accesos[i].Hash
+	}
+	return accesos
+}
+
+func verificarTramo(previo *entidad.AccesoNotificacion, tramo []*entidad.AccesoNotificacion, clave []byte) VerificacionCadena {
+	verificador := NuevoVerificadorCadena(previo, clave)
+	for _, acceso := range tramo {
+		verificador.Verificar(acceso)
+	}
+	return verificador.Resultado()
+}
+
+func TestTramoEnlazadoConSuPrevioEsValido(t *testing.T) {
+	cadena := cadenaDePrueba(5, claveSelloPrueba)
+
+	resultado := verificarTramo(cadena[1], cadena[2:], claveSelloPrueba)
+	if !resultado.Valida || resultado.Registros != 3 || resultado.SinSellar != 0 {
+		t.Fatalf("resultado = %+v", resultado)
+	}
+	if resultado.HashInicial != cadena[1].Hash || resultado.HashFinal != cadena[4].Hash {
+		t.Errorf("hashes inicial y final = %s, %s", resultado.HashInicial, resultado.HashFinal)
+	}
+}
+
+func TestTramoQueNoEnlazaConSuPrevioFalla(t *testing.T) {
+	cadena := cadenaDePrueba(5, claveSelloPrueba)
+
+	// Borrar el registro 3 deja al 4 enlazado con un hash que ya no es el del previo
+	resultado := verificarTramo(cadena[1], cadena[3:], claveSelloPrueba)
+	if resultado.Valida || resultado.FallaID != 4 {
+		t.Errorf("resultado = %+v, se esperaba la falla en el registro 4", resultado)
+	}
+}
+
+func TestSinPrevioElPrimeroDebeAbrirLaCadena(t *testing.T) {
+	cadena := cadenaDePrueba(3, nil)
+
+	if resultado := verificarTramo(nil, cadena, nil); !resultado.Valida || resultado.HashInicial != "" {
+		t.Errorf("cadena completa: %+v", resultado)
+	}
+	// Sin los primeros registros, el tramo no abre la cadena
+	if resultado := verificarTramo(nil, cadena[1:], nil); resultado.Valida || resultado.FallaID != 2 {
+		t.Errorf("tramo sin previo: %+v", resultado)
+	}
+}
+
+func TestCadenaRecalculadaSinLaClaveFalla(t *testing.T) {
+	cadena := cadenaDePrueba(4, claveSelloPrueba)
+
+	// Quien altera un registro puede recalcular los hashes siguientes, pero no los sellos
+	cadena[2].Motivo = "motivo reescrito"
+	for i := 2; i < len(cadena); i++ {
+		sello := cadena[i].Sello
+		cadena[i].Encadenar(cadena[i-1].Hash, nil)
+		cadena[i].Sello = sello
+	}
+
+	if resultado := verificarTramo(nil, cadena, nil); !resultado.Valida || resultado.SellosVerificados {
+		t.Fatalf("sin clave solo se verifican los hashes: %+v", resultado)
+	}
+	if resultado := verificarTramo(nil, cadena, claveSelloPrueba); resultado.Valida || resultado.FallaID != 3 {
+		t.Errorf("resultado = %+v, se esperaba la falla en el registro 3", resultado)
+	}
+}
+
+func TestRegistroSinSelloTrasUnoSellado(t *testing.T) {
+	cadena := cadenaDePrueba(4, claveSelloPrueba)
+	cadena[2].Sello = ""
+
+	if resultado := verificarTramo(nil, cadena, claveSelloPrueba); resultado.Valida || resultado.FallaID != 3 {
+		t.Errorf("dentro del tramo: %+v", resultado)
+	}
+	// El previo sellado también cuenta, aunque quede fuera del tramo
+	if resultado := verificarTramo(cadena[1], cadena[2:], claveSelloPrueba); resultado.Valida || resultado.FallaID != 3 {
+		t.Errorf("tras el previo: %+v", resultado)
+	}
+}
+
+func TestRegistrosPreviosALaClaveSeCuentanSinSellar(t *testing.T) {
+	anteriores := cadenaDePrueba(2, nil)
+	siguiente := &entidad.AccesoNotificacion{ID: 3, Operacion: entidad.OperacionAccesoListado, Motivo: "auditoría", Fecha: time.Now()}
+	siguiente.Encadenar(anteriores[1].Hash, claveSelloPrueba)
+
+	resultado := verificarTramo(nil, append(anteriores, siguiente), claveSelloPrueba)
+	if !resultado.Valida || resultado.SinSellar != 2 || !resultado.SellosVerificados {
+		t.Errorf("resultado = %+v", resultado)
+	}
+}
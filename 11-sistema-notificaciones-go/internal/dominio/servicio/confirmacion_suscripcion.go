@@ -0,0 +1,53 @@
+package servicio
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// ConfirmacionSuscripcion son los datos firmados en el enlace de confirmación de una suscripción
+type ConfirmacionSuscripcion struct {
+	InquilinoID uint
+	UsuarioID   uint
+	CanalID     uint
+	Vence       time.Time
+}
+
+// FirmarConfirmacion arma el token del enlace: los datos en claro seguidos de su HMAC-SHA256.
+// Los datos no son secretos; la firma impide confirmar suscripciones ajenas.
+func FirmarConfirmacion(secreto []byte, confirmacion ConfirmacionSuscripcion) string {
+	datos := fmt.Sprintf("%d.%d.%d.%d", confirmacion.InquilinoID, confirmacion.UsuarioID, confirmacion.CanalID, confirmacion.Vence.Unix())
+	return datos + "." + firmar(secreto, datos)
+}
+
+// VerificarConfirmacion valida la firma y el vencimiento del token
+func VerificarConfirmacion(secreto []byte, token string, ahora time.Time) (*ConfirmacionSuscripcion, error) {
+	separador := strings.LastIndex(token, ".")
+	if separador < 0 || !hmac.Equal([]byte(token[separador+1:]), []byte(firmar(secreto, token[:separador]))) {
+		return nil, entidad.ErrEnlaceInvalido
+	}
+
+	var confirmacion ConfirmacionSuscripcion
+	var vence int64
+	_, err := fmt.Sscanf(token[:separador], "%d.%d.%d.%d", &confirmacion.InquilinoID, &confirmacion.UsuarioID, &confirmacion.CanalID, &vence)
+	if err != nil {
+		return nil, entidad.ErrEnlaceInvalido
+	}
+	confirmacion.Vence = time.Unix(vence, 0)
+	if ahora.After(confirmacion.Vence) {
+		return nil, entidad.ErrEnlaceInvalido
+	}
+	return &confirmacion, nil
+}
+
+func firmar(secreto []byte, datos string) string {
+	mac := hmac.New(sha256.New, secreto)
+	mac.Write([]byte(datos))
+	return hex.EncodeToString(mac.Sum(nil))
+}
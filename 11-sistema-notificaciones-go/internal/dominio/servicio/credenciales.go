@@ -0,0 +1,29 @@
+package servicio
+
+import "context"
+
+type claveCredenciales struct{}
+
+// Cifrador protege datos sensibles en reposo (credenciales de proveedores)
+type Cifrador interface {
+	Cifrar(texto []byte) ([]byte, error)
+	Descifrar(datos []byte) ([]byte, error)
+}
+
+// EnviadorConCredenciales es implementado por los enviadores que aceptan credenciales
+// propias del inquilino; Proveedor identifica el juego de credenciales (twilio, smtp, fcm...)
+type EnviadorConCredenciales interface {
+	Proveedor() string
+}
+
+// ContextoConCredenciales adjunta al contexto las credenciales del inquilino para el envío en curso
+func ContextoConCredenciales(ctx context.Context, credenciales map[string]string) context.Context {
+	return context.WithValue(ctx, claveCredenciales{}, credenciales)
+}
+
+// CredencialesDesdeContexto retorna las credenciales del inquilino; sin ellas el enviador
+// debe usar las credenciales por defecto de la plataforma
+func CredencialesDesdeContexto(ctx context.Context) (map[string]string, bool) {
+	credenciales, existe := ctx.Value(claveCredenciales{}).(map[string]string)
+	return credenciales, existe && len(credenciales) > 0
+}
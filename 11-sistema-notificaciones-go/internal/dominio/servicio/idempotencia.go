@@ -0,0 +1,23 @@
+package servicio
+
+import "context"
+
+type claveTokenIdempotencia struct{}
+
+// ContextoConTokenIdempotencia adjunta al contexto el token del intento de envío en curso
+func ContextoConTokenIdempotencia(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, claveTokenIdempotencia{}, token)
+}
+
+// TokenIdempotencia retorna el token del intento en curso; los proveedores que soportan
+// idempotencia (p. ej. cabecera Idempotency-Key) deben enviarlo en cada llamada.
+func TokenIdempotencia(ctx context.Context) (string, bool) {
+	token, existe := ctx.Value(claveTokenIdempotencia{}).(string)
+	return token, existe && token != ""
+}
+
+// EnviadorIdempotente es implementado por los enviadores que deduplican por token:
+// reenviar con el mismo token no produce un segundo mensaje.
+type EnviadorIdempotente interface {
+	SoportaIdempotencia() bool
+}
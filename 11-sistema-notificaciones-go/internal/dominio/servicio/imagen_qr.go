@@ -0,0 +1,40 @@
+package servicio
+
+import (
+	"crypto/hmac"
+	"fmt"
+	"strings"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// longitudFirmaQR acota la firma del token de la imagen a 128 bits para no alargar la URL
+const longitudFirmaQR = 32
+
+// FirmarImagenQR arma el token de la URL de la imagen del código QR de una notificación, válido
+// hasta vence
+func FirmarImagenQR(secreto []byte, inquilinoID, notificacionID uint, vence time.Time) string {
+	datos := fmt.Sprintf("%d.%d.%d", inquilinoID, notificacionID, vence.Unix())
+	return datos + "." + firmar(secreto, "qr."+datos)[:longitudFirmaQR]
+}
+
+// VerificarImagenQR valida la firma y la vigencia del token y retorna el inquilino y la notificación
+func VerificarImagenQR(secreto []byte, token string, ahora time.Time) (inquilinoID, notificacionID uint, err error) {
+	separador := strings.LastIndex(token, ".")
+	if separador < 0 {
+		return 0, 0, entidad.ErrImagenQRInvalida
+	}
+	firma := firmar(secreto, "qr."+token[:separador])[:longitudFirmaQR]
+	if !hmac.Equal([]byte(token[separador+1:]), []byte(firma)) {
+		return 0, 0, entidad.ErrImagenQRInvalida
+	}
+	var vence int64
+	if _, err := fmt.Sscanf(token[:separador], "%d.%d.%d", &inquilinoID, &notificacionID, &vence); err != nil || notificacionID == 0 {
+		return 0, 0, entidad.ErrImagenQRInvalida
+	}
+	if ahora.Unix() > vence {
+		return 0, 0, entidad.ErrImagenQRInvalida
+	}
+	return inquilinoID, notificacionID, nil
+}
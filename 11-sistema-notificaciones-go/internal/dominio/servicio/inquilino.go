@@ -0,0 +1,120 @@
+package servicio
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+)
+
+type claveInquilino struct{}
+
+type claveClaveAPI struct{}
+
+type claveEsquema struct{}
+
+type claveRegion struct{}
+
+// ContextoConInquilino adjunta al contexto el inquilino que origina la solicitud
+func ContextoConInquilino(ctx context.Context, inquilinoID uint) context.Context {
+	return context.WithValue(ctx, claveInquilino{}, inquilinoID)
+}
+
+// InquilinoDesdeContexto retorna el inquilino de la solicitud o 0 si no hay ninguno
+func InquilinoDesdeContexto(ctx context.Context) uint {
+	inquilinoID, _ := ctx.Value(claveInquilino{}).(uint)
+	return inquilinoID
+}
+
+// ContextoConClaveAPI adjunta al contexto la clave de API que autenticó la solicitud
+func ContextoConClaveAPI(ctx context.Context, clave *entidad.ClaveAPI) context.Context {
+	return context.WithValue(ctx, claveClaveAPI{}, clave)
+}
+
+// ClaveAPIDesdeContexto retorna la clave de API de la solicitud, si se autenticó con una
+func ClaveAPIDesdeContexto(ctx context.Context) (*entidad.ClaveAPI, bool) {
+	clave, existe := ctx.Value(claveClaveAPI{}).(*entidad.ClaveAPI)
+	return clave, existe && clave != nil
+}
+
+// ContextoConEsquema adjunta al contexto el esquema de base de datos del inquilino aislado
+func ContextoConEsquema(ctx context.Context, esquema string) context.Context {
+	return context.WithValue(ctx, claveEsquema{}, esquema)
+}
+
+// EsquemaDesdeContexto retorna el esquema del inquilino o vacío si usa las tablas comunes
+func EsquemaDesdeContexto(ctx context.Context) string {
+	esquema, _ := ctx.Value(claveEsquema{}).(string)
+	return esquema
+}
+
+// ContextoConRegion adjunta al contexto la región de residencia de los datos del inquilino
+func ContextoConRegion(ctx context.Context, region string) context.Context {
+	return context.WithValue(ctx, claveRegion{}, region)
+}
+
+// RegionDesdeContexto retorna la región de los datos o vacío si están en la base principal
+func RegionDesdeContexto(ctx context.Context) string {
+	region, _ := ctx.Value(claveRegion{}).(string)
+	return region
+}
+
+// ContextoDeInquilino prepara el contexto para operar con los datos del inquilino:
+// adjunta su ID, su región y, si está aislado, su esquema. Con inquilinoID 0 retorna el contexto sin cambios.
+func ContextoDeInquilino(ctx context.Context, repoInquilino repositorio.RepositorioInquilino, inquilinoID uint) (context.Context, error) {
+	if inquilinoID == 0 {
+		return ctx, nil
+	}
+	inquilino, err := repoInquilino.ObtenerPorID(ctx, inquilinoID)
+	if err != nil {
+		return nil, err
+	}
+	ctx = ContextoConInquilino(ctx, inquilinoID)
+	ctx = ContextoConRegion(ctx, inquilino.Region)
+	return ContextoConEsquema(ctx, inquilino.Esquema()), nil
+}
+
+// ContextosDeDatos retorna un contexto por cada ubicación de los datos de inquilinos: las tablas
+// comunes de la base principal, las de cada región con inquilinos y el esquema de cada aislado.
+// Los procesos que recorren los datos de todos los inquilinos deben pasar por cada uno.
+func ContextosDeDatos(ctx context.Context, repoInquilino repositorio.RepositorioInquilino) ([]context.Context, error) {
+	contextos := []context.Context{ctx}
+	ubicados, err := repoInquilino.ListarAislados(ctx)
+	if err != nil {
+		return contextos, err
+	}
+	regiones := make(map[string]bool)
+	for i := range ubicados {
+		inquilino := &ubicados[i]
+		if esquema := inquilino.Esquema(); esquema != "" {
+			ctxInquilino := ContextoConRegion(ContextoConInquilino(ctx, inquilino.ID), inquilino.Region)
+			contextos = append(contextos, ContextoConEsquema(ctxInquilino, esquema))
+		} else if !regiones[inquilino.Region] {
+			regiones[inquilino.Region] = true
+			contextos = append(contextos, ContextoConRegion(ctx, inquilino.Region))
+		}
+	}
+	return contextos, nil
+}
+
+// AutorizarInquilino verifica que un recurso pertenezca al inquilino de la solicitud.
+// Las solicitudes sin inquilino (plataforma) acceden a cualquier recurso.
+func AutorizarInquilino(ctx context.Context, inquilinoRecurso uint) error {
+	inquilinoID := InquilinoDesdeContexto(ctx)
+	if inquilinoID != 0 && inquilinoID != inquilinoRecurso {
+		return entidad.ErrAccesoDenegado
+	}
+	return nil
+}
+
+// AutorizarUsuario obtiene el usuario y verifica que pertenezca al inquilino de la solicitud
+func AutorizarUsuario(ctx context.Context, repoUsuario repositorio.RepositorioUsuario, usuarioID uint) (*entidad.Usuario, error) {
+	usuario, err := repoUsuario.ObtenerPorID(ctx, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	if err := AutorizarInquilino(ctx, usuario.InquilinoID); err != nil {
+		return nil, err
+	}
+	return usuario, nil
+}
@@ -0,0 +1,46 @@
+package servicio
+
+import (
+	"context"
+	"sync"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+type claveRegistroLimites struct{}
+
+// RegistroLimites acumula los límites de tasa evaluados durante una solicitud y conserva el
+// más restrictivo, que es el que se informa en la respuesta
+type RegistroLimites struct {
+	mu     sync.Mutex
+	estado *entidad.EstadoLimite
+}
+
+// ContextoConRegistroLimites adjunta al contexto un registro vacío y lo retorna
+func ContextoConRegistroLimites(ctx context.Context) (context.Context, *RegistroLimites) {
+	registro := &RegistroLimites{}
+	return context.WithValue(ctx, claveRegistroLimites{}, registro), registro
+}
+
+// RegistrarLimite informa un límite evaluado; sin registro en el contexto no hace nada
+func RegistrarLimite(ctx context.Context, estado entidad.EstadoLimite) {
+	registro, ok := ctx.Value(claveRegistroLimites{}).(*RegistroLimites)
+	if !ok {
+		return
+	}
+	registro.mu.Lock()
+	defer registro.mu.Unlock()
+	if registro.estado == nil || estado.MasRestrictivo(*registro.estado) {
+		registro.estado = &estado
+	}
+}
+
+// Estado retorna el límite más restrictivo registrado, si hubo alguno
+func (r *RegistroLimites) Estado() (entidad.EstadoLimite, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.estado == nil {
+		return entidad.EstadoLimite{}, false
+	}
+	return *r.estado, true
+}
@@ -0,0 +1,101 @@
+package servicio
+
+import (
+	"html/template"
+	"strings"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// ContenidoRenderizado es el resultado de renderizar una plantilla con la marca del inquilino.
+// Metadatos lleva lo que el canal necesita además del texto (HTML, remitente, estilos).
+type ContenidoRenderizado struct {
+	Asunto    string
+	Cuerpo    string
+	Metadatos map[string]interface{}
+}
+
+// diseñoCorreo envuelve el cuerpo del correo con el logo, los colores y el pie del inquilino
+var diseñoCorreo = template.Must(template.New("correo").Parse(`<!DOCTYPE html>
+<html><body style="margin:0;font-family:Arial,sans-serif;background:#f4f4f4">
+<table width="100%" cellpadding="0" cellspacing="0"><tr><td align="center">
+<table width="600" cellpadding="24" cellspacing="0" style="background:#ffffff">
+{{- if .Logo}}
+<tr><td style="background:{{.ColorPrimario}}"><img src="{{.Logo}}" alt="{{.Remitente}}" height="40"></td></tr>
+{{- end}}
+<tr><td style="color:#333333"><h2 style="color:{{.ColorPrimario}}">{{.Asunto}}</h2>{{range .Parrafos}}<p>{{.}}</p>{{end}}</td></tr>
+{{- if .PiePagina}}
+<tr><td style="font-size:12px;color:{{.ColorSecundario}}">{{.PiePagina}}</td></tr>
+{{- end}}
+</table></td></tr></table>
+</body></html>`))
+
+// colores usados cuando el inquilino no configuró los suyos
+const (
+	colorPrimarioPredeterminado   = "#333333"
+	colorSecundarioPredeterminado = "#777777"
+)
+
+// AplicarMarca adapta el asunto y cuerpo renderizados al canal aplicando la marca del inquilino.
+// El correo recibe un cuerpo HTML con logo, colores, remitente y pie; in-app recibe el pie
+// en el texto y los estilos en metadatos para que el cliente los muestre. Con marca nil no aplica nada.
+func AplicarMarca(tipo entidad.TipoNotificacion, asunto, cuerpo string, marca *entidad.MarcaInquilino) (*ContenidoRenderizado, error) {
+	contenido := &ContenidoRenderizado{Asunto: asunto, Cuerpo: cuerpo, Metadatos: map[string]interface{}{}}
+	if marca == nil {
+		return contenido, nil
+	}
+
+	switch tipo {
+	case entidad.TipoEmail:
+		html, err := renderizarCorreo(asunto, cuerpo, marca)
+		if err != nil {
+			return nil, err
+		}
+		contenido.Metadatos["html"] = html
+		if marca.NombreRemitente != "" {
+			contenido.Metadatos["remitente_nombre"] = marca.NombreRemitente
+		}
+		if marca.CorreoRemitente != "" {
+			contenido.Metadatos["remitente_correo"] = marca.CorreoRemitente
+		}
+	case entidad.TipoInApp:
+		if marca.PiePagina != "" {
+			contenido.Cuerpo = cuerpo + "\n\n" + marca.PiePagina
+		}
+		contenido.Metadatos["marca"] = map[string]interface{}{
+			"logo_url":         marca.LogoURL,
+			"color_primario":   marca.ColorPrimario,
+			"color_secundario": marca.ColorSecundario,
+		}
+	}
+	return contenido, nil
+}
+
+func renderizarCorreo(asunto, cuerpo string, marca *entidad.MarcaInquilino) (string, error) {
+	datos := struct {
+		Asunto, Logo, Remitente, PiePagina string
+		ColorPrimario, ColorSecundario     template.CSS
+		Parrafos                           []string
+	}{
+		Asunto:          asunto,
+		Logo:            marca.LogoURL,
+		Remitente:       marca.NombreRemitente,
+		PiePagina:       marca.PiePagina,
+		ColorPrimario:   template.CSS(colorPrimarioPredeterminado),
+		ColorSecundario: template.CSS(colorSecundarioPredeterminado),
+		Parrafos:        strings.Split(cuerpo, "\n"),
+	}
+	// Los colores ya se validaron como #RRGGBB, por eso pueden marcarse como CSS seguro
+	if marca.ColorPrimario != "" {
+		datos.ColorPrimario = template.CSS(marca.ColorPrimario)
+	}
+	if marca.ColorSecundario != "" {
+		datos.ColorSecundario = template.CSS(marca.ColorSecundario)
+	}
+
+	var html strings.Builder
+	if err := diseñoCorreo.Execute(&html, datos); err != nil {
+		return "", err
+	}
+	return html.String(), nil
+}
@@ -0,0 +1,19 @@
+package servicio
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// ProveedorNotificacion entrega las notificaciones de un tipo por un medio concreto (email,
+// SMS, push...). Puede implementar además EnviadorConCredenciales y EnviadorIdempotente.
+type ProveedorNotificacion interface {
+	Tipo() entidad.TipoNotificacion
+	Enviar(ctx context.Context, notificacion *entidad.Notificacion) error
+}
+
+// CatalogoProveedores retorna el proveedor registrado para cada tipo de notificación
+type CatalogoProveedores interface {
+	Proveedor(tipo entidad.TipoNotificacion) (ProveedorNotificacion, bool)
+}
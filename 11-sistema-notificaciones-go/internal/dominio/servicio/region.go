@@ -0,0 +1,33 @@
+package servicio
+
+import "context"
+
+type claveEndpointProveedor struct{}
+
+// BackendsRegionales es, por región, la URL base de cada proveedor habilitado en ella.
+// Los inquilinos de una región solo pueden enviar por los proveedores que figuran aquí.
+type BackendsRegionales map[string]map[string]string
+
+// Endpoint retorna la URL del proveedor en la región, si está habilitado allí
+func (b BackendsRegionales) Endpoint(region, proveedor string) (string, bool) {
+	endpoint, existe := b[region][proveedor]
+	return endpoint, existe && endpoint != ""
+}
+
+// EnviadorLocal es implementado por los enviadores que entregan sin pasar por un proveedor
+// externo, como WebSocket o la bandeja in-app, y por eso no necesitan backend regional
+type EnviadorLocal interface {
+	EntregaLocal() bool
+}
+
+// ContextoConEndpointProveedor adjunta al contexto la URL regional del proveedor para el envío en curso
+func ContextoConEndpointProveedor(ctx context.Context, endpoint string) context.Context {
+	return context.WithValue(ctx, claveEndpointProveedor{}, endpoint)
+}
+
+// EndpointProveedorDesdeContexto retorna la URL regional del proveedor; sin ella el enviador
+// debe usar su endpoint predeterminado
+func EndpointProveedorDesdeContexto(ctx context.Context) (string, bool) {
+	endpoint, existe := ctx.Value(claveEndpointProveedor{}).(string)
+	return endpoint, existe && endpoint != ""
+}
@@ -0,0 +1,59 @@
+package servicio
+
+import (
+	"crypto/hmac"
+	"fmt"
+	"strings"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// longitudFirmaRespuesta acota la firma del token para que la dirección no supere los 64
+// caracteres de la parte local; 80 bits bastan para que no se pueda adivinar
+const longitudFirmaRespuesta = 20
+
+// FirmarRespuesta arma el token de la dirección de respuesta de una notificación. Va en
+// minúsculas porque algunos servidores de correo no respetan mayúsculas en la parte local.
+func FirmarRespuesta(secreto []byte, inquilinoID, notificacionID uint) string {
+	datos := fmt.Sprintf("%d.%d", inquilinoID, notificacionID)
+	return datos + "." + firmar(secreto, datos)[:longitudFirmaRespuesta]
+}
+
+// VerificarRespuesta valida la firma del token y retorna el inquilino y la notificación
+func VerificarRespuesta(secreto []byte, token string) (inquilinoID, notificacionID uint, err error) {
+	token = strings.ToLower(token)
+	separador := strings.LastIndex(token, ".")
+	if separador < 0 {
+		return 0, 0, entidad.ErrRespuestaCorreoInvalida
+	}
+	firma := firmar(secreto, token[:separador])[:longitudFirmaRespuesta]
+	if !hmac.Equal([]byte(token[separador+1:]), []byte(firma)) {
+		return 0, 0, entidad.ErrRespuestaCorreoInvalida
+	}
+	if _, err := fmt.Sscanf(token[:separador], "%d.%d", &inquilinoID, &notificacionID); err != nil || notificacionID == 0 {
+		return 0, 0, entidad.ErrRespuestaCorreoInvalida
+	}
+	return inquilinoID, notificacionID, nil
+}
+
+// DireccionRespuesta agrega el token a la dirección del buzón de respuestas:
+// respuestas@ejemplo.com queda respuestas+<token>@ejemplo.com
+func DireccionRespuesta(buzon, token string) string {
+	local, dominio, _ := strings.Cut(buzon, "@")
+	return local + "+" + token + "@" + dominio
+}
+
+// TokenRespuesta busca entre las direcciones la del buzón de respuestas con token y lo retorna
+func TokenRespuesta(buzon string, direcciones []string) (string, bool) {
+	local, dominio, _ := strings.Cut(strings.ToLower(buzon), "@")
+	for _, direccion := range direcciones {
+		parteLocal, parteDominio, ok := strings.Cut(strings.ToLower(strings.TrimSpace(direccion)), "@")
+		if !ok || parteDominio != dominio {
+			continue
+		}
+		if token, ok := strings.CutPrefix(parteLocal, local+"+"); ok && token != "" {
+			return token, true
+		}
+	}
+	return "", false
+}
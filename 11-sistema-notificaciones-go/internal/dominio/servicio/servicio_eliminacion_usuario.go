@@ -0,0 +1,84 @@
+package servicio
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+)
+
+// ResultadoEliminacionUsuario resume qué se hizo con cada relación del usuario
+type ResultadoEliminacionUsuario struct {
+	UsuarioID      uint  `json:"usuario_id"`
+	Notificaciones int64 `json:"notificaciones_eliminadas"`
+	Preferencias   int64 `json:"preferencias_eliminadas"`
+	Canales        int64 `json:"canales_desvinculados"`
+	Dispositivos   int64 `json:"dispositivos_eliminados"`
+}
+
+// ServicioEliminacionUsuario define la cascada al eliminar un usuario:
+//   - notificaciones y preferencias: soft delete, conservan historial
+//   - suscripciones a canales: se desvinculan (el canal sigue existiendo)
+//   - tokens push: se borran, no deben volver a recibir envíos
+//   - usuario: soft delete, al final
+//
+// Todo ocurre en una unidad de trabajo: o se aplica completa o no se aplica nada.
+type ServicioEliminacionUsuario struct {
+	unidadTrabajo           repositorio.UnidadTrabajo
+	repositorioUsuario      repositorio.RepositorioUsuario
+	repositorioNotificacion repositorio.RepositorioNotificacion
+	repositorioPreferencia  repositorio.RepositorioPreferencia
+	repositorioCanal        repositorio.RepositorioCanal
+	repositorioDispositivo  repositorio.RepositorioDispositivo
+}
+
+// NuevoServicioEliminacionUsuario crea una nueva instancia del servicio
+func NuevoServicioEliminacionUsuario(
+	unidadTrabajo repositorio.UnidadTrabajo,
+	repositorioUsuario repositorio.RepositorioUsuario,
+	repositorioNotificacion repositorio.RepositorioNotificacion,
+	repositorioPreferencia repositorio.RepositorioPreferencia,
+	repositorioCanal repositorio.RepositorioCanal,
+	repositorioDispositivo repositorio.RepositorioDispositivo,
+) *ServicioEliminacionUsuario {
+	return &ServicioEliminacionUsuario{
+		unidadTrabajo:           unidadTrabajo,
+		repositorioUsuario:      repositorioUsuario,
+		repositorioNotificacion: repositorioNotificacion,
+		repositorioPreferencia:  repositorioPreferencia,
+		repositorioCanal:        repositorioCanal,
+		repositorioDispositivo:  repositorioDispositivo,
+	}
+}
+
+// EliminarUsuario aplica la cascada completa; retorna ErrUsuarioNoEncontrado si no existe
+func (s *ServicioEliminacionUsuario) EliminarUsuario(ctx context.Context, usuarioID uint) (*ResultadoEliminacionUsuario, error) {
+	resultado := &ResultadoEliminacionUsuario{UsuarioID: usuarioID}
+
+	err := s.unidadTrabajo.Ejecutar(ctx, func(ctx context.Context) error {
+		usuario, err := s.repositorioUsuario.ObtenerPorID(ctx, usuarioID)
+		if err != nil {
+			return err
+		}
+		if err := AutorizarInquilino(ctx, usuario.InquilinoID); err != nil {
+			return err
+		}
+
+		if resultado.Notificaciones, err = s.repositorioNotificacion.EliminarPorUsuario(ctx, usuarioID); err != nil {
+			return err
+		}
+		if resultado.Preferencias, err = s.repositorioPreferencia.EliminarPorUsuario(ctx, usuarioID); err != nil {
+			return err
+		}
+		if resultado.Canales, err = s.repositorioCanal.DesvincularUsuario(ctx, usuarioID); err != nil {
+			return err
+		}
+		if resultado.Dispositivos, err = s.repositorioDispositivo.EliminarPorUsuario(ctx, usuarioID); err != nil {
+			return err
+		}
+		return s.repositorioUsuario.Eliminar(ctx, usuarioID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resultado, nil
+}
@@ -0,0 +1,204 @@
+package servicio
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+)
+
+// almacenFalso guarda, por usuario, cuántas filas vivas tiene cada relación
+type almacenFalso struct {
+	usuarios       map[uint]bool
+	notificaciones map[uint]int64
+	preferencias   map[uint]int64
+	suscripciones  map[uint]int64
+	dispositivos   map[uint]int64
+	// fallarEn hace fallar la relación indicada para probar la atomicidad
+	fallarEn string
+}
+
+var errFalla = errors.New("falla simulada")
+
+func nuevoAlmacenFalso() *almacenFalso {
+	return &almacenFalso{
+		usuarios:       map[uint]bool{1: true, 2: true},
+		notificaciones: map[uint]int64{1: 3, 2: 5},
+		preferencias:   map[uint]int64{1: 2, 2: 1},
+		suscripciones:  map[uint]int64{1: 4, 2: 2},
+		dispositivos:   map[uint]int64{1: 2, 2: 1},
+	}
+}
+
+func (a *almacenFalso) eliminar(relacion string, filas map[uint]int64, usuarioID uint) (int64, error) {
+	if a.fallarEn == relacion {
+		return 0, errFalla
+	}
+	eliminadas := filas[usuarioID]
+	delete(filas, usuarioID)
+	return eliminadas, nil
+}
+
+// unidadTrabajoFalsa restaura el almacén si la operación falla
+type unidadTrabajoFalsa struct{ almacen *almacenFalso }
+
+func (u unidadTrabajoFalsa) Ejecutar(ctx context.Context, operacion func(ctx context.Context) error) error {
+	copia := *u.almacen
+	copia.usuarios = maps.Clone(u.almacen.usuarios)
+	copia.notificaciones = maps.Clone(u.almacen.notificaciones)
+	copia.preferencias = maps.Clone(u.almacen.preferencias)
+	copia.suscripciones = maps.Clone(u.almacen.suscripciones)
+	copia.dispositivos = maps.Clone(u.almacen.dispositivos)
+
+	if err := operacion(ctx); err != nil {
+		*u.almacen = copia
+		return err
+	}
+	return nil
+}
+
+type usuariosFalsos struct {
+	repositorio.RepositorioUsuario
+	almacen *almacenFalso
+}
+
+func (r usuariosFalsos) ObtenerPorID(_ context.Context, id uint) (*entidad.Usuario, error) {
+	if !r.almacen.usuarios[id] {
+		return nil, entidad.ErrUsuarioNoEncontrado
+	}
+	return &entidad.Usuario{ID: id}, nil
+}
+
+func (r usuariosFalsos) Eliminar(_ context.Context, id uint) error {
+	delete(r.almacen.usuarios, id)
+	return nil
+}
+
+type notificacionesFalsas struct {
+	repositorio.RepositorioNotificacion
+	almacen *almacenFalso
+}
+
+func (r notificacionesFalsas) EliminarPorUsuario(_ context.Context, usuarioID uint) (int64, error) {
+	return r.almacen.eliminar("notificaciones", r.almacen.notificaciones, usuarioID)
+}
+
+type preferenciasFalsas struct {
+	repositorio.RepositorioPreferencia
+	almacen *almacenFalso
+}
+
+func (r preferenciasFalsas) EliminarPorUsuario(_ context.Context, usuarioID uint) (int64, error) {
+	return r.almacen.eliminar("preferencias", r.almacen.preferencias, usuarioID)
+}
+
+type canalesFalsos struct {
+	repositorio.RepositorioCanal
+	almacen *almacenFalso
+}
+
+func (r canalesFalsos) DesvincularUsuario(_ context.Context, usuarioID uint) (int64, error) {
+	return r.almacen.eliminar("suscripciones", r.almacen.suscripciones, usuarioID)
+}
+
+type dispositivosFalsos struct {
+	repositorio.RepositorioDispositivo
+	almacen *almacenFalso
+}
+
+func (r dispositivosFalsos) EliminarPorUsuario(_ context.Context, usuarioID uint) (int64, error) {
+	return r.almacen.eliminar("dispositivos", r.almacen.dispositivos, usuarioID)
+}
+
+func nuevoServicioPrueba(almacen *almacenFalso) *ServicioEliminacionUsuario {
+	return NuevoServicioEliminacionUsuario(
+		unidadTrabajoFalsa{almacen},
+		usuariosFalsos{almacen: almacen},
+		notificacionesFalsas{almacen: almacen},
+		preferenciasFalsas{almacen: almacen},
+		canalesFalsos{almacen: almacen},
+		dispositivosFalsos{almacen: almacen},
+	)
+}
+
+func TestEliminarUsuarioCascadaPorRelacion(t *testing.T) {
+	casos := []struct {
+		relacion  string
+		filas     func(a *almacenFalso) map[uint]int64
+		resultado func(r *ResultadoEliminacionUsuario) int64
+		esperado  int64
+	}{
+		{"notificaciones", func(a *almacenFalso) map[uint]int64 { return a.notificaciones }, func(r *ResultadoEliminacionUsuario) int64 { return r.Notificaciones }, 3},
+		{"preferencias", func(a *almacenFalso) map[uint]int64 { return a.preferencias }, func(r *ResultadoEliminacionUsuario) int64 { return r.Preferencias }, 2},
+		{"suscripciones", func(a *almacenFalso) map[uint]int64 { return a.suscripciones }, func(r *ResultadoEliminacionUsuario) int64 { return r.Canales }, 4},
+		{"dispositivos", func(a *almacenFalso) map[uint]int64 { return a.dispositivos }, func(r *ResultadoEliminacionUsuario) int64 { return r.Dispositivos }, 2},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.relacion, func(t *testing.T) {
+			almacen := nuevoAlmacenFalso()
+			otroUsuario := caso.filas(almacen)[2]
+
+			resultado, err := nuevoServicioPrueba(almacen).EliminarUsuario(context.Background(), 1)
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			if obtenido := caso.resultado(resultado); obtenido != caso.esperado {
+				t.Errorf("resultado %s = %d, se esperaba %d", caso.relacion, obtenido, caso.esperado)
+			}
+			if restantes := caso.filas(almacen)[1]; restantes != 0 {
+				t.Errorf("quedaron %d filas de %s del usuario eliminado", restantes, caso.relacion)
+			}
+			if caso.filas(almacen)[2] != otroUsuario {
+				t.Errorf("se modificaron %s de otro usuario", caso.relacion)
+			}
+		})
+	}
+}
+
+func TestEliminarUsuarioEliminaElUsuario(t *testing.T) {
+	almacen := nuevoAlmacenFalso()
+
+	if _, err := nuevoServicioPrueba(almacen).EliminarUsuario(context.Background(), 1); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if almacen.usuarios[1] {
+		t.Error("el usuario sigue activo")
+	}
+	if !almacen.usuarios[2] {
+		t.Error("se eliminó otro usuario")
+	}
+}
+
+func TestEliminarUsuarioNoEncontrado(t *testing.T) {
+	almacen := nuevoAlmacenFalso()
+
+	_, err := nuevoServicioPrueba(almacen).EliminarUsuario(context.Background(), 99)
+	if !errors.Is(err, entidad.ErrUsuarioNoEncontrado) {
+		t.Fatalf("error = %v, se esperaba ErrUsuarioNoEncontrado", err)
+	}
+}
+
+func TestEliminarUsuarioFalloRevierteLaCascada(t *testing.T) {
+	for _, relacion := range []string{"notificaciones", "preferencias", "suscripciones", "dispositivos"} {
+		t.Run(relacion, func(t *testing.T) {
+			almacen := nuevoAlmacenFalso()
+			almacen.fallarEn = relacion
+
+			_, err := nuevoServicioPrueba(almacen).EliminarUsuario(context.Background(), 1)
+			if !errors.Is(err, errFalla) {
+				t.Fatalf("error = %v, se esperaba la falla simulada", err)
+			}
+			if !almacen.usuarios[1] {
+				t.Error("el usuario se eliminó pese a la falla")
+			}
+			if almacen.notificaciones[1] != 3 || almacen.preferencias[1] != 2 ||
+				almacen.suscripciones[1] != 4 || almacen.dispositivos[1] != 2 {
+				t.Error("la cascada quedó aplicada a medias")
+			}
+		})
+	}
+}
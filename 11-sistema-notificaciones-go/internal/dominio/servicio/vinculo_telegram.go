@@ -0,0 +1,42 @@
+package servicio
+
+import (
+	"crypto/hmac"
+	"fmt"
+	"strings"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// longitudFirmaTelegram acota la firma a 96 bits: el parámetro de /start admite hasta 64
+// caracteres entre letras, dígitos, _ y -
+const longitudFirmaTelegram = 24
+
+// FirmarVinculoTelegram arma el parámetro del enlace /start con que un usuario vincula su chat
+// de Telegram, válido hasta vence
+func FirmarVinculoTelegram(secreto []byte, inquilinoID, usuarioID uint, vence time.Time) string {
+	datos := fmt.Sprintf("%d-%d-%d", inquilinoID, usuarioID, vence.Unix())
+	return datos + "-" + firmar(secreto, "telegram."+datos)[:longitudFirmaTelegram]
+}
+
+// VerificarVinculoTelegram valida la firma y la vigencia del parámetro y retorna el inquilino y
+// el usuario que lo pidió
+func VerificarVinculoTelegram(secreto []byte, token string, ahora time.Time) (inquilinoID, usuarioID uint, err error) {
+	separador := strings.LastIndex(token, "-")
+	if separador < 0 {
+		return 0, 0, entidad.ErrEnlaceInvalido
+	}
+	firma := firmar(secreto, "telegram."+token[:separador])[:longitudFirmaTelegram]
+	if !hmac.Equal([]byte(token[separador+1:]), []byte(firma)) {
+		return 0, 0, entidad.ErrEnlaceInvalido
+	}
+	var vence int64
+	if _, err := fmt.Sscanf(token[:separador], "%d-%d-%d", &inquilinoID, &usuarioID, &vence); err != nil || usuarioID == 0 {
+		return 0, 0, entidad.ErrEnlaceInvalido
+	}
+	if ahora.Unix() > vence {
+		return 0, 0, entidad.ErrEnlaceInvalido
+	}
+	return inquilinoID, usuarioID, nil
+}
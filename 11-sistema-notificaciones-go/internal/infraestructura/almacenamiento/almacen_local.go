@@ -0,0 +1,75 @@
+package almacenamiento
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrArchivoNoEncontrado indica que el archivo no existe o aún no terminó de escribirse
+var ErrArchivoNoEncontrado = errors.New("archivo no encontrado")
+
+// AlmacenLocal implementa AlmacenArchivos sobre un directorio del disco local
+type AlmacenLocal struct {
+	directorio string
+}
+
+// NuevoAlmacenLocal crea el almacén, creando el directorio si no existe
+func NuevoAlmacenLocal(directorio string) (*AlmacenLocal, error) {
+	if err := os.MkdirAll(directorio, 0o750); err != nil {
+		return nil, fmt.Errorf("creando directorio de archivos: %w", err)
+	}
+	return &AlmacenLocal{directorio: directorio}, nil
+}
+
+// Crear escribe en un temporal que se renombra al cerrar, así nunca se descarga un archivo a medias
+func (a *AlmacenLocal) Crear(_ context.Context, nombre string) (io.WriteCloser, error) {
+	ruta, err := a.ruta(nombre)
+	if err != nil {
+		return nil, err
+	}
+	temporal, err := os.CreateTemp(a.directorio, ".tmp-*")
+	if err != nil {
+		return nil, err
+	}
+	return &archivoPendiente{File: temporal, destino: ruta}, nil
+}
+
+// Abrir abre un archivo ya completo
+func (a *AlmacenLocal) Abrir(_ context.Context, nombre string) (io.ReadCloser, error) {
+	ruta, err := a.ruta(nombre)
+	if err != nil {
+		return nil, err
+	}
+	archivo, err := os.Open(ruta)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrArchivoNoEncontrado
+	}
+	return archivo, err
+}
+
+// ruta evita que un nombre salga del directorio del almacén
+func (a *AlmacenLocal) ruta(nombre string) (string, error) {
+	if nombre == "" || strings.ContainsAny(nombre, `/\`) || strings.HasPrefix(nombre, ".") {
+		return "", fmt.Errorf("nombre de archivo inválido: %q", nombre)
+	}
+	return filepath.Join(a.directorio, nombre), nil
+}
+
+// archivoPendiente publica el archivo en su ruta final al cerrarlo
+type archivoPendiente struct {
+	*os.File
+	destino string
+}
+
+func (p *archivoPendiente) Close() error {
+	if err := p.File.Close(); err != nil {
+		_ = os.Remove(p.Name())
+		return err
+	}
+	return os.Rename(p.Name(), p.destino)
+}
@@ -0,0 +1,272 @@
+package almacenamiento
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+const (
+	// ProveedorS3 identifica al almacén de objetos en los clientes HTTP salientes
+	ProveedorS3 = "s3"
+
+	algoritmoFirma = "AWS4-HMAC-SHA256"
+	formatoFechaS3 = "20060102T150405Z"
+	// contenidoSinFirmar evita leer dos veces el archivo para firmar su hash; el transporte
+	// TLS ya protege su integridad
+	contenidoSinFirmar = "UNSIGNED-PAYLOAD"
+	// hashVacio es el SHA-256 del cuerpo vacío de las solicitudes GET y DELETE
+	hashVacio = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// AlmacenS3 implementa AlmacenObjetos sobre la API REST de S3 con firma AWS Signature V4;
+// funciona con AWS S3 y con servicios compatibles como MinIO
+type AlmacenS3 struct {
+	endpoint     *url.URL
+	region       string
+	bucket       string
+	claveAcceso  string
+	claveSecreta string
+	estiloRuta   bool
+	cliente      *http.Client
+	reloj        reloj.Reloj
+}
+
+// NuevoAlmacenS3 crea el almacén con el cliente HTTP del proveedor
+func NuevoAlmacenS3(config configuracion.ConfiguracionAdjuntos, cliente *http.Client, rel reloj.Reloj) (*AlmacenS3, error) {
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("endpoint S3 inválido: %w", err)
+	}
+	return &AlmacenS3{
+		endpoint:     endpoint,
+		region:       config.Region,
+		bucket:       config.Bucket,
+		claveAcceso:  config.ClaveAcceso,
+		claveSecreta: config.ClaveSecreta,
+		estiloRuta:   config.EstiloRuta,
+		cliente:      cliente,
+		reloj:        rel,
+	}, nil
+}
+
+// Subir guarda el objeto con PUT; tamano es obligatorio porque S3 no admite cuerpos por partes
+func (a *AlmacenS3) Subir(ctx context.Context, clave, tipoContenido string, contenido io.Reader, tamano int64) error {
+	solicitud, err := http.NewRequestWithContext(ctx, http.MethodPut, a.url(clave).String(), contenido)
+	if err != nil {
+		return err
+	}
+	solicitud.ContentLength = tamano
+	solicitud.Header.Set("Content-Type", tipoContenido)
+	respuesta, err := a.ejecutar(solicitud, contenidoSinFirmar)
+	if err != nil {
+		return err
+	}
+	return respuesta.Body.Close()
+}
+
+// Abrir descarga el objeto; el llamador debe cerrar el cuerpo
+func (a *AlmacenS3) Abrir(ctx context.Context, clave string) (io.ReadCloser, error) {
+	solicitud, err := http.NewRequestWithContext(ctx, http.MethodGet, a.url(clave).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	respuesta, err := a.ejecutar(solicitud, hashVacio)
+	if err != nil {
+		return nil, err
+	}
+	return respuesta.Body, nil
+}
+
+// Eliminar borra el objeto; S3 responde éxito también si no existía
+func (a *AlmacenS3) Eliminar(ctx context.Context, clave string) error {
+	solicitud, err := http.NewRequestWithContext(ctx, http.MethodDelete, a.url(clave).String(), nil)
+	if err != nil {
+		return err
+	}
+	respuesta, err := a.ejecutar(solicitud, hashVacio)
+	if err != nil {
+		return err
+	}
+	return respuesta.Body.Close()
+}
+
+// URLDescarga firma una URL GET en la consulta (presigned URL). S3 responde el archivo con el
+// nombre y el tipo indicados, así el navegador no lo interpreta como otra cosa.
+func (a *AlmacenS3) URLDescarga(clave, nombre, tipoContenido string, vigencia time.Duration) (string, error) {
+	direccion := a.url(clave)
+	ahora := a.reloj.Ahora().UTC()
+	fecha := ahora.Format(formatoFechaS3)
+
+	consulta := url.Values{}
+	consulta.Set("X-Amz-Algorithm", algoritmoFirma)
+	consulta.Set("X-Amz-Credential", a.claveAcceso+"/"+a.alcance(ahora))
+	consulta.Set("X-Amz-Date", fecha)
+	consulta.Set("X-Amz-Expires", strconv.Itoa(int(vigencia/time.Second)))
+	consulta.Set("X-Amz-SignedHeaders", "host")
+	consulta.Set("response-content-disposition", mime.FormatMediaType("attachment", map[string]string{"filename": nombre}))
+	consulta.Set("response-content-type", tipoContenido)
+
+	canonica := strings.Join([]string{
+		http.MethodGet,
+		rutaCanonica(direccion.Path),
+		consultaCanonica(consulta),
+		"host:" + direccion.Host + "\n",
+		"host",
+		contenidoSinFirmar,
+	}, "\n")
+	direccion.RawQuery = consultaCanonica(consulta) + "&X-Amz-Signature=" + a.firma(ahora, canonica)
+	return direccion.String(), nil
+}
+
+// url arma la dirección del objeto con el bucket en la ruta o como subdominio
+func (a *AlmacenS3) url(clave string) *url.URL {
+	direccion := *a.endpoint
+	if a.estiloRuta {
+		direccion.Path = strings.TrimRight(direccion.Path, "/") + "/" + a.bucket + "/" + clave
+	} else {
+		direccion.Host = a.bucket + "." + direccion.Host
+		direccion.Path = strings.TrimRight(direccion.Path, "/") + "/" + clave
+	}
+	return &direccion
+}
+
+// ejecutar firma la solicitud en el encabezado Authorization y la envía; una respuesta de error
+// se convierte en error con el código de S3
+func (a *AlmacenS3) ejecutar(solicitud *http.Request, hashContenido string) (*http.Response, error) {
+	ahora := a.reloj.Ahora().UTC()
+	solicitud.Header.Set("X-Amz-Date", ahora.Format(formatoFechaS3))
+	solicitud.Header.Set("X-Amz-Content-Sha256", hashContenido)
+
+	nombres := []string{"host"}
+	valores := map[string]string{"host": solicitud.URL.Host}
+	for nombre := range solicitud.Header {
+		minuscula := strings.ToLower(nombre)
+		if minuscula == "content-type" || strings.HasPrefix(minuscula, "x-amz-") {
+			nombres = append(nombres, minuscula)
+			valores[minuscula] = strings.TrimSpace(solicitud.Header.Get(nombre))
+		}
+	}
+	sort.Strings(nombres)
+	var encabezados strings.Builder
+	for _, nombre := range nombres {
+		encabezados.WriteString(nombre + ":" + valores[nombre] + "\n")
+	}
+	firmados := strings.Join(nombres, ";")
+
+	canonica := strings.Join([]string{
+		solicitud.Method,
+		rutaCanonica(solicitud.URL.Path),
+		consultaCanonica(solicitud.URL.Query()),
+		encabezados.String(),
+		firmados,
+		hashContenido,
+	}, "\n")
+	solicitud.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		algoritmoFirma, a.claveAcceso, a.alcance(ahora), firmados, a.firma(ahora, canonica)))
+
+	respuesta, err := a.cliente.Do(solicitud)
+	if err != nil {
+		return nil, err
+	}
+	if respuesta.StatusCode >= 200 && respuesta.StatusCode < 300 {
+		return respuesta, nil
+	}
+	defer respuesta.Body.Close()
+	detalle, _ := io.ReadAll(io.LimitReader(respuesta.Body, 1024))
+	if respuesta.StatusCode == http.StatusNotFound {
+		return nil, ErrArchivoNoEncontrado
+	}
+	return nil, fmt.Errorf("S3 respondió %d: %s", respuesta.StatusCode, codigoErrorS3(detalle))
+}
+
+// alcance es el ámbito de la credencial: día, región, servicio y terminador
+func (a *AlmacenS3) alcance(ahora time.Time) string {
+	return ahora.Format("20060102") + "/" + a.region + "/s3/aws4_request"
+}
+
+// firma deriva la clave del día y firma la cadena de la solicitud canónica
+func (a *AlmacenS3) firma(ahora time.Time, canonica string) string {
+	resumen := sha256.Sum256([]byte(canonica))
+	cadena := algoritmoFirma + "\n" + ahora.Format(formatoFechaS3) + "\n" + a.alcance(ahora) + "\n" + hex.EncodeToString(resumen[:])
+
+	clave := hmacSHA256([]byte("AWS4"+a.claveSecreta), ahora.Format("20060102"))
+	clave = hmacSHA256(clave, a.region)
+	clave = hmacSHA256(clave, "s3")
+	clave = hmacSHA256(clave, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(clave, cadena))
+}
+
+func hmacSHA256(clave []byte, datos string) []byte {
+	mac := hmac.New(sha256.New, clave)
+	mac.Write([]byte(datos))
+	return mac.Sum(nil)
+}
+
+// rutaCanonica codifica cada segmento de la ruta como exige la firma, conservando las barras
+func rutaCanonica(ruta string) string {
+	if ruta == "" {
+		return "/"
+	}
+	return codificarS3(ruta, true)
+}
+
+// consultaCanonica ordena los parámetros por nombre y los codifica como exige la firma
+func consultaCanonica(consulta url.Values) string {
+	nombres := make([]string, 0, len(consulta))
+	for nombre := range consulta {
+		nombres = append(nombres, nombre)
+	}
+	sort.Strings(nombres)
+	partes := make([]string, 0, len(nombres))
+	for _, nombre := range nombres {
+		valores := append([]string(nil), consulta[nombre]...)
+		sort.Strings(valores)
+		for _, valor := range valores {
+			partes = append(partes, codificarS3(nombre, false)+"="+codificarS3(valor, false))
+		}
+	}
+	return strings.Join(partes, "&")
+}
+
+// codificarS3 codifica en porcentaje todo salvo los caracteres no reservados de RFC 3986; a
+// diferencia de url.QueryEscape, el espacio va como %20
+func codificarS3(texto string, conservarBarras bool) string {
+	var resultado strings.Builder
+	for i := 0; i < len(texto); i++ {
+		c := texto[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			resultado.WriteByte(c)
+		case c == '/' && conservarBarras:
+			resultado.WriteByte(c)
+		default:
+			fmt.Fprintf(&resultado, "%%%02X", c)
+		}
+	}
+	return resultado.String()
+}
+
+// codigoErrorS3 extrae el código del documento XML de error de S3, o el cuerpo si no lo tiene
+func codigoErrorS3(detalle []byte) string {
+	inicio := bytes.Index(detalle, []byte("<Code>"))
+	fin := bytes.Index(detalle, []byte("</Code>"))
+	if inicio < 0 || fin < inicio {
+		return string(bytes.TrimSpace(detalle))
+	}
+	return string(detalle[inicio+len("<Code>") : fin])
+}
@@ -0,0 +1,91 @@
+package almacenamiento
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	// tamanoTrozoClamAV es el tamaño de cada trozo del comando INSTREAM
+	tamanoTrozoClamAV = 64 << 10
+	// tiempoMaximoAnalisis acota el análisis cuando el contexto no tiene plazo
+	tiempoMaximoAnalisis = time.Minute
+)
+
+// AnalizadorClamAV implementa AnalizadorArchivos con el demonio clamd por TCP
+type AnalizadorClamAV struct {
+	direccion string
+}
+
+// NuevoAnalizadorClamAV crea el analizador para clamd en host:puerto
+func NuevoAnalizadorClamAV(direccion string) *AnalizadorClamAV {
+	return &AnalizadorClamAV{direccion: direccion}
+}
+
+// Analizar envía el archivo con el comando INSTREAM: trozos precedidos por su largo de 4 bytes
+// y un trozo vacío al final. clamd responde "stream: OK" o "stream: <amenaza> FOUND".
+func (a *AnalizadorClamAV) Analizar(ctx context.Context, contenido io.Reader) (string, error) {
+	var dialer net.Dialer
+	conexion, err := dialer.DialContext(ctx, "tcp", a.direccion)
+	if err != nil {
+		return "", fmt.Errorf("conectando con clamd: %w", err)
+	}
+	defer conexion.Close()
+	plazo, ok := ctx.Deadline()
+	if !ok {
+		plazo = time.Now().Add(tiempoMaximoAnalisis)
+	}
+	if err := conexion.SetDeadline(plazo); err != nil {
+		return "", err
+	}
+
+	escritor := bufio.NewWriter(conexion)
+	if _, err := escritor.WriteString("zINSTREAM\x00"); err != nil {
+		return "", err
+	}
+	trozo := make([]byte, tamanoTrozoClamAV)
+	for {
+		n, err := io.ReadFull(contenido, trozo)
+		if n > 0 {
+			if err := binary.Write(escritor, binary.BigEndian, uint32(n)); err != nil {
+				return "", err
+			}
+			if _, err := escritor.Write(trozo[:n]); err != nil {
+				return "", err
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	if err := binary.Write(escritor, binary.BigEndian, uint32(0)); err != nil {
+		return "", err
+	}
+	if err := escritor.Flush(); err != nil {
+		return "", err
+	}
+
+	respuesta, err := bufio.NewReader(conexion).ReadBytes(0)
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("leyendo la respuesta de clamd: %w", err)
+	}
+	resultado := strings.TrimPrefix(string(bytes.TrimRight(respuesta, "\x00\n")), "stream: ")
+	switch {
+	case resultado == "OK":
+		return "", nil
+	case strings.HasSuffix(resultado, " FOUND"):
+		return strings.TrimSuffix(resultado, " FOUND"), nil
+	default:
+		return "", fmt.Errorf("clamd respondió %q", resultado)
+	}
+}
@@ -0,0 +1,173 @@
+// Package avisos entrega los avisos de lectura a los servicios que originaron las notificaciones
+package avisos
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/infraestructura/clienteHTTP"
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// EventoLeida es el evento que se envía cuando el usuario lee la notificación
+	EventoLeida = "notificacion.leida"
+	// EventoRespuestaCorreo es el evento que se envía cuando el usuario responde la notificación por correo
+	EventoRespuestaCorreo = "notificacion.respuesta_correo"
+)
+
+// prefijoTema es el prefijo del stream de Redis de cada tema, seguido del inquilino y el tema
+const prefijoTema = "avisos_lectura:"
+
+// ErrWebhooksDeshabilitados indica que el aviso pedía un webhook pero AVISOS_LECTURA_WEBHOOKS está apagado
+var ErrWebhooksDeshabilitados = errors.New("los webhooks de avisos de lectura están deshabilitados")
+
+// AvisoLectura es el cuerpo del webhook y del mensaje publicado en el tema
+type AvisoLectura struct {
+	Evento         string                   `json:"evento"`
+	NotificacionID uint                     `json:"notificacion_id"`
+	InquilinoID    uint                     `json:"inquilino_id"`
+	UsuarioID      uint                     `json:"usuario_id"`
+	Tipo           entidad.TipoNotificacion `json:"tipo"`
+	FechaLeida     *time.Time               `json:"fecha_leida"`
+	// Metadatos permiten al origen correlacionar el aviso, p. ej. con el ID de su alerta
+	Metadatos map[string]interface{} `json:"metadatos,omitempty"`
+}
+
+// AvisadorLectura envía el aviso por webhook (firmado con HMAC-SHA256) y lo agrega al stream de
+// Redis del tema en el espacio del inquilino, donde el origen lo consume con XREAD o grupos de consumo
+type AvisadorLectura struct {
+	config  configuracion.ConfiguracionAvisosLectura
+	cliente *http.Client
+	redis   *redis.Client
+}
+
+// NuevoAvisadorLectura crea el avisador con el cliente HTTP de los webhooks. El cliente debe
+// rechazar destinos internos salvo que la configuración permita la red interna.
+func NuevoAvisadorLectura(config configuracion.ConfiguracionAvisosLectura, cliente *http.Client, clienteRedis *redis.Client) *AvisadorLectura {
+	return &AvisadorLectura{config: config, cliente: cliente, redis: clienteRedis}
+}
+
+// ClienteWebhooks es el cliente de los webhooks de avisos: las URL las eligen los servicios de
+// origen, así que no puede conectar a la red interna salvo que la configuración lo permita
+func ClienteWebhooks(config configuracion.ConfiguracionAvisosLectura, fabrica *clienteHTTP.FabricaClientes) *http.Client {
+	if config.RedInterna {
+		return fabrica.Cliente("avisos_lectura")
+	}
+	return fabrica.ClientePublico("avisos_lectura")
+}
+
+// Avisar entrega el aviso a cada destino declarado; un destino que falla no impide el otro
+func (a *AvisadorLectura) Avisar(ctx context.Context, notificacion *entidad.Notificacion) error {
+	cuerpo, err := json.Marshal(AvisoLectura{
+		Evento:         EventoLeida,
+		NotificacionID: notificacion.ID,
+		InquilinoID:    notificacion.InquilinoID,
+		UsuarioID:      notificacion.UsuarioID,
+		Tipo:           notificacion.Tipo,
+		FechaLeida:     notificacion.FechaLeida,
+		Metadatos:      notificacion.Metadatos,
+	})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	if notificacion.AvisoLecturaWebhook != "" {
+		if err := a.enviarWebhook(ctx, notificacion.AvisoLecturaWebhook, EventoLeida, cuerpo); err != nil {
+			errs = append(errs, fmt.Errorf("webhook: %w", err))
+		}
+	}
+	if notificacion.AvisoLecturaTema != "" {
+		err := a.redis.XAdd(ctx, &redis.XAddArgs{
+			Stream: claveTema(notificacion.InquilinoID, notificacion.AvisoLecturaTema),
+			MaxLen: a.config.LongitudTema,
+			Approx: true,
+			Values: map[string]interface{}{"evento": EventoLeida, "aviso": cuerpo},
+		}).Err()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("tema %s: %w", notificacion.AvisoLecturaTema, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// AvisarRespuesta reenvía al webhook del canal la respuesta por correo, firmada y con los
+// mismos reintentos que los avisos de lectura
+func (a *AvisadorLectura) AvisarRespuesta(ctx context.Context, webhook string, respuesta *entidad.RespuestaCorreo) error {
+	respuesta.Evento = EventoRespuestaCorreo
+	cuerpo, err := json.Marshal(respuesta)
+	if err != nil {
+		return err
+	}
+	return a.enviarWebhook(ctx, webhook, EventoRespuestaCorreo, cuerpo)
+}
+
+// claveTema es el stream del tema dentro del espacio del inquilino: un inquilino no puede
+// publicar ni leer en los temas de otro eligiendo el mismo nombre
+func claveTema(inquilinoID uint, tema string) string {
+	return fmt.Sprintf("%s%d:%s", prefijoTema, inquilinoID, tema)
+}
+
+// enviarWebhook publica el aviso reintentando ante errores de red, 429 y 5xx. Reintentar es
+// seguro: el origen identifica el aviso por notificacion_id.
+func (a *AvisadorLectura) enviarWebhook(ctx context.Context, url, evento string, cuerpo []byte) error {
+	if !a.config.Webhooks {
+		return ErrWebhooksDeshabilitados
+	}
+	espera := a.config.Espera
+	var err error
+	for intento := 1; ; intento++ {
+		var reintentable bool
+		if reintentable, err = a.intentarWebhook(ctx, url, evento, cuerpo); err == nil || !reintentable || intento >= a.config.Intentos {
+			return err
+		}
+
+		temporizador := time.NewTimer(espera)
+		select {
+		case <-ctx.Done():
+			temporizador.Stop()
+			return ctx.Err()
+		case <-temporizador.C:
+		}
+		espera *= 2
+	}
+}
+
+func (a *AvisadorLectura) intentarWebhook(ctx context.Context, url, evento string, cuerpo []byte) (bool, error) {
+	peticion, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(cuerpo))
+	if err != nil {
+		return false, err
+	}
+	peticion.Header.Set("Content-Type", "application/json")
+	peticion.Header.Set("X-Notificaciones-Evento", evento)
+	// La configuración garantiza el secreto cuando los webhooks están habilitados
+	mac := hmac.New(sha256.New, []byte(a.config.Secreto))
+	mac.Write(cuerpo)
+	peticion.Header.Set("X-Notificaciones-Firma", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+
+	respuesta, err := a.cliente.Do(peticion)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	respuesta.Body.Close()
+	switch {
+	case respuesta.StatusCode >= 200 && respuesta.StatusCode < 300:
+		return false, nil
+	case respuesta.StatusCode == http.StatusTooManyRequests || respuesta.StatusCode >= 500:
+		return true, fmt.Errorf("respuesta %d", respuesta.StatusCode)
+	default:
+		return false, fmt.Errorf("respuesta %d", respuesta.StatusCode)
+	}
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// adquirirArrendamiento renueva el lease si ya es del titular o lo toma si está libre
+var adquirirArrendamiento = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return 1
+end
+return 0
+`)
+
+// liberarArrendamiento borra el lease solo si sigue siendo del titular
+var liberarArrendamiento = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// ArrendamientoRedis implementa Arrendamiento con una clave con expiración en Redis
+type ArrendamientoRedis struct {
+	redis *redis.Client
+}
+
+// NuevoArrendamientoRedis crea una nueva instancia de ArrendamientoRedis
+func NuevoArrendamientoRedis(cliente *redis.Client) *ArrendamientoRedis {
+	return &ArrendamientoRedis{redis: cliente}
+}
+
+func claveArrendamiento(clave string) string {
+	return "notificaciones:arrendamiento:" + clave
+}
+
+// Adquirir toma o renueva el lease de forma atómica
+func (a *ArrendamientoRedis) Adquirir(ctx context.Context, clave, titular string, duracion time.Duration) (bool, error) {
+	resultado, err := adquirirArrendamiento.Run(ctx, a.redis, []string{claveArrendamiento(clave)}, titular, duracion.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return resultado == 1, nil
+}
+
+// Liberar suelta el lease para que otra instancia lo tome sin esperar el vencimiento
+func (a *ArrendamientoRedis) Liberar(ctx context.Context, clave, titular string) error {
+	return liberarArrendamiento.Run(ctx, a.redis, []string{claveArrendamiento(clave)}, titular).Err()
+}
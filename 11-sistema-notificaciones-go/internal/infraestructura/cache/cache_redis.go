@@ -0,0 +1,142 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// canalInvalidacion es el canal pub/sub por el que las instancias se avisan invalidaciones
+const canalInvalidacion = "notificaciones:cache:invalidaciones"
+
+// NuevoClienteRedis crea un cliente de Redis a partir de la configuración
+func NuevoClienteRedis(config configuracion.ConfiguracionRedis) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:     config.Direccion(),
+		Password: config.Contrasena,
+		DB:       config.BaseDatos,
+	})
+}
+
+// CacheDosNiveles combina un LRU en proceso con Redis compartido entre instancias
+type CacheDosNiveles struct {
+	nombre string
+	local  *LRU[[]byte]
+	redis  *redis.Client
+	ttl    time.Duration
+	logger *logger.Logger
+}
+
+// NuevoCacheDosNiveles crea un cache con nombre (usado como prefijo de claves y en métricas)
+func NuevoCacheDosNiveles(nombre string, cliente *redis.Client, config configuracion.ConfiguracionCache, rel reloj.Reloj, log *logger.Logger) *CacheDosNiveles {
+	return &CacheDosNiveles{
+		nombre: nombre,
+		local:  NuevoLRU[[]byte](config.TamanoLocal, config.TTLLocal, rel),
+		redis:  cliente,
+		ttl:    config.TTL,
+		logger: log,
+	}
+}
+
+func (c *CacheDosNiveles) claveRedis(clave string) string {
+	return "notificaciones:cache:" + c.nombre + ":" + clave
+}
+
+// claveEnEsquema arma la clave de una entidad que vive en el esquema del inquilino:
+// los IDs se repiten entre esquemas, por lo que el esquema forma parte de la clave
+func claveEnEsquema(ctx context.Context, id uint) string {
+	clave := strconv.FormatUint(uint64(id), 10)
+	if esquema := servicio.EsquemaDesdeContexto(ctx); esquema != "" {
+		return esquema + ":" + clave
+	}
+	return clave
+}
+
+// enOtraRegion indica si el contexto opera con datos de una región de residencia: esos datos
+// no se copian al cache compartido, que vive en la región principal
+func enOtraRegion(ctx context.Context) bool {
+	return servicio.RegionDesdeContexto(ctx) != ""
+}
+
+// Obtener decodifica en destino el valor cacheado o, si no existe, el obtenido con cargar.
+// Un fallo de Redis degrada a la base de datos en lugar de fallar la operación.
+func (c *CacheDosNiveles) Obtener(ctx context.Context, clave string, destino any, cargar func(ctx context.Context) (any, error)) error {
+	if datos, existe := c.local.Obtener(clave); existe {
+		metricaAciertos.WithLabelValues(c.nombre, nivelLocal).Inc()
+		return json.Unmarshal(datos, destino)
+	}
+
+	datos, err := c.redis.Get(ctx, c.claveRedis(clave)).Bytes()
+	if err == nil {
+		metricaAciertos.WithLabelValues(c.nombre, nivelRedis).Inc()
+		c.local.Guardar(clave, datos)
+		return json.Unmarshal(datos, destino)
+	}
+	if !errors.Is(err, redis.Nil) {
+		c.logger.Warn("Error leyendo cache de Redis", "cache", c.nombre, "error", err)
+	}
+
+	metricaFallos.WithLabelValues(c.nombre).Inc()
+	valor, err := cargar(ctx)
+	if err != nil {
+		return err
+	}
+
+	datos, err = json.Marshal(valor)
+	if err != nil {
+		return err
+	}
+	c.local.Guardar(clave, datos)
+	if err := c.redis.Set(ctx, c.claveRedis(clave), datos, c.ttl).Err(); err != nil {
+		c.logger.Warn("Error escribiendo cache de Redis", "cache", c.nombre, "error", err)
+	}
+
+	return json.Unmarshal(datos, destino)
+}
+
+// Invalidar elimina la clave en Redis y avisa a todas las instancias que la quiten de su LRU
+func (c *CacheDosNiveles) Invalidar(ctx context.Context, clave string) error {
+	metricaInvalidaciones.WithLabelValues(c.nombre).Inc()
+	c.local.Eliminar(clave)
+
+	if err := c.redis.Del(ctx, c.claveRedis(clave)).Err(); err != nil {
+		return err
+	}
+	return c.redis.Publish(ctx, canalInvalidacion, c.nombre+":"+clave).Err()
+}
+
+// EscucharInvalidaciones procesa las invalidaciones publicadas por otras instancias hasta que ctx termine
+func EscucharInvalidaciones(ctx context.Context, cliente *redis.Client, caches ...*CacheDosNiveles) {
+	porNombre := make(map[string]*CacheDosNiveles, len(caches))
+	for _, cache := range caches {
+		porNombre[cache.nombre] = cache
+	}
+
+	suscripcion := cliente.Subscribe(ctx, canalInvalidacion)
+	defer suscripcion.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case mensaje, abierto := <-suscripcion.Channel():
+			if !abierto {
+				return
+			}
+			nombre, clave, valido := strings.Cut(mensaje.Payload, ":")
+			if cache, existe := porNombre[nombre]; valido && existe {
+				cache.local.Eliminar(clave)
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ContadorUsoRedis implementa ContadorUso con INCRBY, compartido entre instancias
+type ContadorUsoRedis struct {
+	redis *redis.Client
+}
+
+// NuevoContadorUsoRedis crea una nueva instancia de ContadorUsoRedis
+func NuevoContadorUsoRedis(cliente *redis.Client) *ContadorUsoRedis {
+	return &ContadorUsoRedis{redis: cliente}
+}
+
+func claveUso(clave string) string {
+	return "notificaciones:uso:" + clave
+}
+
+// Incrementar suma cantidad y fija la expiración solo al crear la clave
+func (c *ContadorUsoRedis) Incrementar(ctx context.Context, clave string, cantidad int64, expiracion time.Duration) (int64, error) {
+	clave = claveUso(clave)
+	tuberia := c.redis.TxPipeline()
+	incremento := tuberia.IncrBy(ctx, clave, cantidad)
+	tuberia.ExpireNX(ctx, clave, expiracion)
+	if _, err := tuberia.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return incremento.Val(), nil
+}
+
+// Obtener lee varias claves en una sola consulta
+func (c *ContadorUsoRedis) Obtener(ctx context.Context, claves ...string) ([]int64, error) {
+	if len(claves) == 0 {
+		return nil, nil
+	}
+	completas := make([]string, len(claves))
+	for i, clave := range claves {
+		completas[i] = claveUso(clave)
+	}
+
+	valores, err := c.redis.MGet(ctx, completas...).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+	resultado := make([]int64, len(claves))
+	for i, valor := range valores {
+		if texto, ok := valor.(string); ok {
+			resultado[i], _ = strconv.ParseInt(texto, 10, 64)
+		}
+	}
+	return resultado, nil
+}
@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// entradaLRU es un elemento almacenado en el LRU
+type entradaLRU[V any] struct {
+	clave  string
+	valor  V
+	expira time.Time
+}
+
+// LRU es un cache en memoria acotado por cantidad de entradas y con expiración por TTL
+type LRU[V any] struct {
+	mu        sync.Mutex
+	capacidad int
+	ttl       time.Duration
+	reloj     reloj.Reloj
+	orden     *list.List
+	entradas  map[string]*list.Element
+}
+
+// NuevoLRU crea un LRU con la capacidad y TTL indicados
+func NuevoLRU[V any](capacidad int, ttl time.Duration, rel reloj.Reloj) *LRU[V] {
+	return &LRU[V]{
+		capacidad: capacidad,
+		ttl:       ttl,
+		reloj:     rel,
+		orden:     list.New(),
+		entradas:  make(map[string]*list.Element),
+	}
+}
+
+// Obtener retorna el valor si existe y no expiró
+func (l *LRU[V]) Obtener(clave string) (V, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var vacio V
+	elemento, existe := l.entradas[clave]
+	if !existe {
+		return vacio, false
+	}
+
+	entrada := elemento.Value.(*entradaLRU[V])
+	if l.reloj.Ahora().After(entrada.expira) {
+		l.orden.Remove(elemento)
+		delete(l.entradas, clave)
+		return vacio, false
+	}
+
+	l.orden.MoveToFront(elemento)
+	return entrada.valor, true
+}
+
+// Guardar almacena el valor, desalojando la entrada menos usada si se excede la capacidad
+func (l *LRU[V]) Guardar(clave string, valor V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	expira := l.reloj.Ahora().Add(l.ttl)
+	if elemento, existe := l.entradas[clave]; existe {
+		entrada := elemento.Value.(*entradaLRU[V])
+		entrada.valor = valor
+		entrada.expira = expira
+		l.orden.MoveToFront(elemento)
+		return
+	}
+
+	l.entradas[clave] = l.orden.PushFront(&entradaLRU[V]{clave: clave, valor: valor, expira: expira})
+
+	for l.capacidad > 0 && l.orden.Len() > l.capacidad {
+		ultimo := l.orden.Back()
+		l.orden.Remove(ultimo)
+		delete(l.entradas, ultimo.Value.(*entradaLRU[V]).clave)
+	}
+}
+
+// Eliminar quita una entrada
+func (l *LRU[V]) Eliminar(clave string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if elemento, existe := l.entradas[clave]; existe {
+		l.orden.Remove(elemento)
+		delete(l.entradas, clave)
+	}
+}
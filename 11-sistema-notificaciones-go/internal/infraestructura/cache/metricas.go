@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Niveles de cache reportados en las métricas
+const (
+	nivelLocal = "local"
+	nivelRedis = "redis"
+)
+
+var (
+	metricaAciertos = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "notificaciones_cache_aciertos_total",
+		Help: "Aciertos de cache por nombre y nivel (local, redis)",
+	}, []string{"cache", "nivel"})
+
+	metricaFallos = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "notificaciones_cache_fallos_total",
+		Help: "Fallos de cache que requirieron consultar la base de datos",
+	}, []string{"cache"})
+
+	metricaInvalidaciones = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "notificaciones_cache_invalidaciones_total",
+		Help: "Invalidaciones explícitas de cache",
+	}, []string{"cache"})
+
+	metricaDeduplicadas = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "notificaciones_deduplicadas_total",
+		Help: "Notificaciones descartadas por repetir una huella dentro de la ventana de deduplicación",
+	})
+)
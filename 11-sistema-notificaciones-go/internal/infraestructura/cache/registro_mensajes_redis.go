@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RegistroMensajesRedis implementa RegistroMensajesProveedor con claves que vencen solas: los
+// acuses pueden llegar a cualquier instancia, no solo a la que envió el mensaje
+type RegistroMensajesRedis struct {
+	redis *redis.Client
+}
+
+// NuevoRegistroMensajesRedis crea una nueva instancia de RegistroMensajesRedis
+func NuevoRegistroMensajesRedis(cliente *redis.Client) *RegistroMensajesRedis {
+	return &RegistroMensajesRedis{redis: cliente}
+}
+
+func claveMensaje(proveedor, idMensaje string) string {
+	return "notificaciones:mensaje:" + proveedor + ":" + idMensaje
+}
+
+func claveEntregados(proveedor string, referencia entidad.ReferenciaMensaje) string {
+	return fmt.Sprintf("notificaciones:mensaje:entregados:%s:%d:%d", proveedor, referencia.InquilinoID, referencia.NotificacionID)
+}
+
+// Registrar guarda la referencia bajo cada ID de mensaje
+func (r *RegistroMensajesRedis) Registrar(ctx context.Context, proveedor string, idsMensaje []string, referencia entidad.ReferenciaMensaje, vigencia time.Duration) error {
+	valor, err := json.Marshal(referencia)
+	if err != nil {
+		return err
+	}
+	_, err = r.redis.Pipelined(ctx, func(tuberia redis.Pipeliner) error {
+		for _, id := range idsMensaje {
+			tuberia.Set(ctx, claveMensaje(proveedor, id), valor, vigencia)
+		}
+		return nil
+	})
+	return err
+}
+
+// Resolver lee la referencia del mensaje
+func (r *RegistroMensajesRedis) Resolver(ctx context.Context, proveedor, idMensaje string) (*entidad.ReferenciaMensaje, error) {
+	valor, err := r.redis.Get(ctx, claveMensaje(proveedor, idMensaje)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var referencia entidad.ReferenciaMensaje
+	if err := json.Unmarshal(valor, &referencia); err != nil {
+		return nil, err
+	}
+	return &referencia, nil
+}
+
+// MarcarEntregado agrega el mensaje al conjunto de partes entregadas de la notificación
+func (r *RegistroMensajesRedis) MarcarEntregado(ctx context.Context, proveedor, idMensaje string, referencia entidad.ReferenciaMensaje, vigencia time.Duration) (int64, error) {
+	clave := claveEntregados(proveedor, referencia)
+	var entregados *redis.IntCmd
+	_, err := r.redis.TxPipelined(ctx, func(tuberia redis.Pipeliner) error {
+		tuberia.SAdd(ctx, clave, idMensaje)
+		tuberia.Expire(ctx, clave, vigencia)
+		entregados = tuberia.SCard(ctx, clave)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return entregados.Val(), nil
+}
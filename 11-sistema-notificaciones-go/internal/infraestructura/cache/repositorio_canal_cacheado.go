@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+)
+
+// RepositorioCanalCacheado decora RepositorioCanal cacheando ObtenerPorID
+type RepositorioCanalCacheado struct {
+	repositorio.RepositorioCanal
+	cache *CacheDosNiveles
+}
+
+// NuevoRepositorioCanalCacheado crea el decorador
+func NuevoRepositorioCanalCacheado(base repositorio.RepositorioCanal, cache *CacheDosNiveles) *RepositorioCanalCacheado {
+	return &RepositorioCanalCacheado{RepositorioCanal: base, cache: cache}
+}
+
+// ObtenerPorID obtiene el canal desde cache o base de datos
+func (r *RepositorioCanalCacheado) ObtenerPorID(ctx context.Context, id uint) (*entidad.Canal, error) {
+	if enOtraRegion(ctx) {
+		return r.RepositorioCanal.ObtenerPorID(ctx, id)
+	}
+	var canal entidad.Canal
+	err := r.cache.Obtener(ctx, claveEnEsquema(ctx, id), &canal, func(ctx context.Context) (any, error) {
+		return r.RepositorioCanal.ObtenerPorID(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &canal, nil
+}
+
+// Actualizar persiste el canal e invalida su entrada
+func (r *RepositorioCanalCacheado) Actualizar(ctx context.Context, canal *entidad.Canal) error {
+	if err := r.RepositorioCanal.Actualizar(ctx, canal); err != nil {
+		return err
+	}
+	return r.cache.Invalidar(ctx, claveEnEsquema(ctx, canal.ID))
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+)
+
+// RepositorioCredencialPlataformaCacheado decora RepositorioCredencialPlataforma cacheando el
+// juego activo de cada proveedor, consultado en cada envío. Cada cambio lo invalida en todas las
+// instancias, así una rotación rige sin reiniciarlas.
+type RepositorioCredencialPlataformaCacheado struct {
+	repositorio.RepositorioCredencialPlataforma
+	cache *CacheDosNiveles
+}
+
+// NuevoRepositorioCredencialPlataformaCacheado crea el decorador
+func NuevoRepositorioCredencialPlataformaCacheado(base repositorio.RepositorioCredencialPlataforma, cache *CacheDosNiveles) *RepositorioCredencialPlataformaCacheado {
+	return &RepositorioCredencialPlataformaCacheado{RepositorioCredencialPlataforma: base, cache: cache}
+}
+
+// ObtenerActiva obtiene el juego activo desde cache o base de datos; se cachea cifrado y la
+// ausencia de juego activo también se cachea
+func (r *RepositorioCredencialPlataformaCacheado) ObtenerActiva(ctx context.Context, proveedor string) (*entidad.CredencialPlataforma, error) {
+	var credencial entidad.CredencialPlataforma
+	err := r.cache.Obtener(ctx, claveCredencialPlataforma(proveedor), &credencial, func(ctx context.Context) (any, error) {
+		return r.RepositorioCredencialPlataforma.ObtenerActiva(ctx, proveedor)
+	})
+	if err != nil || credencial.ID == 0 {
+		return nil, err
+	}
+	return &credencial, nil
+}
+
+// Guardar persiste el juego e invalida el activo del proveedor, por si era ese
+func (r *RepositorioCredencialPlataformaCacheado) Guardar(ctx context.Context, credencial *entidad.CredencialPlataforma) error {
+	if err := r.RepositorioCredencialPlataforma.Guardar(ctx, credencial); err != nil {
+		return err
+	}
+	return r.cache.Invalidar(ctx, claveCredencialPlataforma(credencial.Proveedor))
+}
+
+// Activar activa el juego e invalida el activo del proveedor
+func (r *RepositorioCredencialPlataformaCacheado) Activar(ctx context.Context, proveedor, nombre string, fecha time.Time) error {
+	if err := r.RepositorioCredencialPlataforma.Activar(ctx, proveedor, nombre, fecha); err != nil {
+		return err
+	}
+	return r.cache.Invalidar(ctx, claveCredencialPlataforma(proveedor))
+}
+
+func claveCredencialPlataforma(proveedor string) string {
+	return "credencial_plataforma:" + proveedor
+}
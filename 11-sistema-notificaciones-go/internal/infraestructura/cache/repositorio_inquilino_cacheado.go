@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+)
+
+// RepositorioInquilinoCacheado decora RepositorioInquilino cacheando el inquilino, sus cuotas y credenciales, consultados en cada envío
+type RepositorioInquilinoCacheado struct {
+	repositorio.RepositorioInquilino
+	cache *CacheDosNiveles
+}
+
+// NuevoRepositorioInquilinoCacheado crea el decorador
+func NuevoRepositorioInquilinoCacheado(base repositorio.RepositorioInquilino, cache *CacheDosNiveles) *RepositorioInquilinoCacheado {
+	return &RepositorioInquilinoCacheado{RepositorioInquilino: base, cache: cache}
+}
+
+// ObtenerPorID obtiene el inquilino desde cache o base de datos
+func (r *RepositorioInquilinoCacheado) ObtenerPorID(ctx context.Context, id uint) (*entidad.Inquilino, error) {
+	var inquilino entidad.Inquilino
+	err := r.cache.Obtener(ctx, "inquilino:"+strconv.FormatUint(uint64(id), 10), &inquilino, func(ctx context.Context) (any, error) {
+		return r.RepositorioInquilino.ObtenerPorID(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &inquilino, nil
+}
+
+// ListarCuotas obtiene las cuotas desde cache o base de datos
+func (r *RepositorioInquilinoCacheado) ListarCuotas(ctx context.Context, inquilinoID uint) ([]entidad.CuotaInquilino, error) {
+	var cuotas []entidad.CuotaInquilino
+	err := r.cache.Obtener(ctx, strconv.FormatUint(uint64(inquilinoID), 10), &cuotas, func(ctx context.Context) (any, error) {
+		return r.RepositorioInquilino.ListarCuotas(ctx, inquilinoID)
+	})
+	return cuotas, err
+}
+
+// GuardarCuota persiste la cuota e invalida las del inquilino
+func (r *RepositorioInquilinoCacheado) GuardarCuota(ctx context.Context, cuota *entidad.CuotaInquilino) error {
+	if err := r.RepositorioInquilino.GuardarCuota(ctx, cuota); err != nil {
+		return err
+	}
+	return r.cache.Invalidar(ctx, strconv.FormatUint(uint64(cuota.InquilinoID), 10))
+}
+
+// ListarCredenciales obtiene las credenciales desde cache o base de datos; se cachean cifradas
+func (r *RepositorioInquilinoCacheado) ListarCredenciales(ctx context.Context, inquilinoID uint) ([]entidad.CredencialProveedor, error) {
+	var credenciales []entidad.CredencialProveedor
+	err := r.cache.Obtener(ctx, claveCredenciales(inquilinoID), &credenciales, func(ctx context.Context) (any, error) {
+		return r.RepositorioInquilino.ListarCredenciales(ctx, inquilinoID)
+	})
+	return credenciales, err
+}
+
+// GuardarCredencial persiste la credencial e invalida las del inquilino
+func (r *RepositorioInquilinoCacheado) GuardarCredencial(ctx context.Context, credencial *entidad.CredencialProveedor) error {
+	if err := r.RepositorioInquilino.GuardarCredencial(ctx, credencial); err != nil {
+		return err
+	}
+	return r.cache.Invalidar(ctx, claveCredenciales(credencial.InquilinoID))
+}
+
+// EliminarCredencial elimina la credencial e invalida las del inquilino
+func (r *RepositorioInquilinoCacheado) EliminarCredencial(ctx context.Context, inquilinoID uint, proveedor string) error {
+	if err := r.RepositorioInquilino.EliminarCredencial(ctx, inquilinoID, proveedor); err != nil {
+		return err
+	}
+	return r.cache.Invalidar(ctx, claveCredenciales(inquilinoID))
+}
+
+// ObtenerMarca obtiene la marca desde cache o base de datos
+func (r *RepositorioInquilinoCacheado) ObtenerMarca(ctx context.Context, inquilinoID uint) (*entidad.MarcaInquilino, error) {
+	var marca entidad.MarcaInquilino
+	err := r.cache.Obtener(ctx, claveMarca(inquilinoID), &marca, func(ctx context.Context) (any, error) {
+		return r.RepositorioInquilino.ObtenerMarca(ctx, inquilinoID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &marca, nil
+}
+
+// GuardarMarca persiste la marca e invalida la del inquilino
+func (r *RepositorioInquilinoCacheado) GuardarMarca(ctx context.Context, marca *entidad.MarcaInquilino) error {
+	if err := r.RepositorioInquilino.GuardarMarca(ctx, marca); err != nil {
+		return err
+	}
+	return r.cache.Invalidar(ctx, claveMarca(marca.InquilinoID))
+}
+
+func claveMarca(inquilinoID uint) string {
+	return "marca:" + strconv.FormatUint(uint64(inquilinoID), 10)
+}
+
+func claveCredenciales(inquilinoID uint) string {
+	return "credenciales:" + strconv.FormatUint(uint64(inquilinoID), 10)
+}
@@ -0,0 +1,257 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// prefijoNoLeidas antecede a la clave del usuario (con su esquema) en cada contador
+const prefijoNoLeidas = "notificaciones:no_leidas:"
+
+// tamanoLoteReconciliacion es la cantidad de claves que pide cada SCAN de la reconciliación
+const tamanoLoteReconciliacion = 100
+
+// ajustarSiExiste suma al contador solo si ya existe: uno ausente se calcula completo en la
+// próxima lectura, así un incremento suelto nunca pasa por el total
+var ajustarSiExiste = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return redis.call('INCRBY', KEYS[1], ARGV[1])
+end
+return false`)
+
+// corregirSiNoCambio reemplaza el contador solo si sigue valiendo lo leído antes de contar en
+// la base: si cambió en el medio, se deja para la próxima pasada
+var corregirSiNoCambio = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	redis.call('SET', KEYS[1], ARGV[2], 'KEEPTTL')
+	return 1
+end
+return 0`)
+
+// RepositorioNotificacionNoLeidas decora RepositorioNotificacion con un contador de no leídas
+// por usuario en Redis, que se ajusta atómicamente al crear, leer o eliminar notificaciones en
+// lugar de contar en la base en cada consulta. Los contadores vencen a los ttl y Reconciliar
+// corrige la deriva de las operaciones masivas (retención) o de transacciones revertidas.
+type RepositorioNotificacionNoLeidas struct {
+	repositorio.RepositorioNotificacion
+	redis  *redis.Client
+	ttl    time.Duration
+	logger *logger.Logger
+}
+
+// NuevoRepositorioNotificacionNoLeidas crea el decorador
+func NuevoRepositorioNotificacionNoLeidas(base repositorio.RepositorioNotificacion, cliente *redis.Client, ttl time.Duration, log *logger.Logger) *RepositorioNotificacionNoLeidas {
+	return &RepositorioNotificacionNoLeidas{RepositorioNotificacion: base, redis: cliente, ttl: ttl, logger: log}
+}
+
+// ContarNoLeidas lee el contador o, si no existe, lo calcula en la base y lo guarda.
+// Un fallo de Redis degrada a la base de datos en lugar de fallar la consulta.
+func (r *RepositorioNotificacionNoLeidas) ContarNoLeidas(ctx context.Context, usuarioID uint) (int64, error) {
+	if enOtraRegion(ctx) {
+		return r.RepositorioNotificacion.ContarNoLeidas(ctx, usuarioID)
+	}
+	clave := claveNoLeidas(ctx, usuarioID)
+	total, err := r.redis.Get(ctx, clave).Int64()
+	if err == nil {
+		metricaAciertos.WithLabelValues("no_leidas", nivelRedis).Inc()
+		return max(total, 0), nil
+	}
+	if !errors.Is(err, redis.Nil) {
+		r.logger.Warn("Error leyendo contador de no leídas", "error", err)
+	}
+
+	metricaFallos.WithLabelValues("no_leidas").Inc()
+	total, err = r.RepositorioNotificacion.ContarNoLeidas(ctx, usuarioID)
+	if err != nil {
+		return 0, err
+	}
+	// NX: si otra instancia ya lo creó, su valor puede incluir ajustes posteriores a este conteo
+	if err := r.redis.SetNX(ctx, clave, total, r.ttl).Err(); err != nil {
+		r.logger.Warn("Error guardando contador de no leídas", "error", err)
+	}
+	return total, nil
+}
+
+// Crear inserta la notificación y la suma si ya llega como no leída
+func (r *RepositorioNotificacionNoLeidas) Crear(ctx context.Context, notificacion *entidad.Notificacion) error {
+	if err := r.RepositorioNotificacion.Crear(ctx, notificacion); err != nil {
+		return err
+	}
+	if notificacion.EstaNoLeida() {
+		r.ajustar(ctx, notificacion.UsuarioID, 1)
+	}
+	return nil
+}
+
+// CrearLote inserta las notificaciones y suma las no leídas de cada usuario
+func (r *RepositorioNotificacionNoLeidas) CrearLote(ctx context.Context, notificaciones []*entidad.Notificacion, tamanoLote int, progreso repositorio.FuncionProgreso) error {
+	if err := r.RepositorioNotificacion.CrearLote(ctx, notificaciones, tamanoLote, progreso); err != nil {
+		return err
+	}
+	porUsuario := make(map[uint]int64)
+	for _, notificacion := range notificaciones {
+		if notificacion.EstaNoLeida() {
+			porUsuario[notificacion.UsuarioID]++
+		}
+	}
+	for usuarioID, cantidad := range porUsuario {
+		r.ajustar(ctx, usuarioID, cantidad)
+	}
+	return nil
+}
+
+// Actualizar guarda la notificación y ajusta el contador si entró o salió de las no leídas,
+// p. ej. al enviarse o al leerse. Compara con el estado guardado: la actualización falla por
+// conflicto de versión si alguien lo cambió en el medio.
+func (r *RepositorioNotificacionNoLeidas) Actualizar(ctx context.Context, notificacion *entidad.Notificacion) error {
+	if enOtraRegion(ctx) {
+		return r.RepositorioNotificacion.Actualizar(ctx, notificacion)
+	}
+	anterior, err := r.RepositorioNotificacion.ObtenerPorID(ctx, notificacion.ID)
+	if err != nil && !errors.Is(err, entidad.ErrNotificacionNoEncontrada) {
+		return err
+	}
+	if err := r.RepositorioNotificacion.Actualizar(ctx, notificacion); err != nil {
+		return err
+	}
+	if anterior == nil {
+		return nil
+	}
+	switch {
+	case !anterior.EstaNoLeida() && notificacion.EstaNoLeida():
+		r.ajustar(ctx, notificacion.UsuarioID, 1)
+	case anterior.EstaNoLeida() && !notificacion.EstaNoLeida():
+		r.ajustar(ctx, anterior.UsuarioID, -1)
+	}
+	return nil
+}
+
+// Eliminar elimina la notificación y la descuenta si no estaba leída
+func (r *RepositorioNotificacionNoLeidas) Eliminar(ctx context.Context, id uint) error {
+	if enOtraRegion(ctx) {
+		return r.RepositorioNotificacion.Eliminar(ctx, id)
+	}
+	anterior, err := r.RepositorioNotificacion.ObtenerPorID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := r.RepositorioNotificacion.Eliminar(ctx, id); err != nil {
+		return err
+	}
+	if anterior.EstaNoLeida() {
+		r.ajustar(ctx, anterior.UsuarioID, -1)
+	}
+	return nil
+}
+
+// EliminarPorUsuario elimina las notificaciones del usuario y descarta su contador
+func (r *RepositorioNotificacionNoLeidas) EliminarPorUsuario(ctx context.Context, usuarioID uint) (int64, error) {
+	eliminadas, err := r.RepositorioNotificacion.EliminarPorUsuario(ctx, usuarioID)
+	if err != nil || enOtraRegion(ctx) {
+		return eliminadas, err
+	}
+	if err := r.redis.Del(ctx, claveNoLeidas(ctx, usuarioID)).Err(); err != nil {
+		r.logger.Warn("Error descartando contador de no leídas", "usuario_id", usuarioID, "error", err)
+	}
+	return eliminadas, nil
+}
+
+// Reconciliar recuenta en la base cada contador existente y corrige los que derivaron.
+// Retorna cuántos corrigió; los que cambian durante el recuento quedan para la próxima pasada.
+func (r *RepositorioNotificacionNoLeidas) Reconciliar(ctx context.Context) (int, error) {
+	var corregidos int
+	var errs []error
+	iterador := r.redis.Scan(ctx, 0, prefijoNoLeidas+"*", tamanoLoteReconciliacion).Iterator()
+	for iterador.Next(ctx) {
+		clave := iterador.Val()
+		corregido, err := r.reconciliar(ctx, clave)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", clave, err))
+			continue
+		}
+		if corregido {
+			corregidos++
+		}
+	}
+	if err := iterador.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return corregidos, errors.Join(errs...)
+}
+
+// reconciliar recuenta un contador en el esquema codificado en su clave
+func (r *RepositorioNotificacionNoLeidas) reconciliar(ctx context.Context, clave string) (bool, error) {
+	esquema, usuarioID, err := parsearClaveNoLeidas(clave)
+	if err != nil {
+		return false, err
+	}
+	actual, err := r.redis.Get(ctx, clave).Result()
+	if errors.Is(err, redis.Nil) {
+		// Venció durante la pasada: la próxima lectura lo recalcula
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	ctxEsquema := ctx
+	if esquema != "" {
+		ctxEsquema = servicio.ContextoConEsquema(ctx, esquema)
+	}
+	total, err := r.RepositorioNotificacion.ContarNoLeidas(ctxEsquema, usuarioID)
+	if err != nil {
+		return false, err
+	}
+	if strconv.FormatInt(total, 10) == actual {
+		return false, nil
+	}
+	corregido, err := corregirSiNoCambio.Run(ctx, r.redis, []string{clave}, actual, total).Int()
+	if err != nil {
+		return false, err
+	}
+	if corregido == 1 {
+		r.logger.Debug("Contador de no leídas corregido", "clave", clave, "anterior", actual, "total", total)
+	}
+	return corregido == 1, nil
+}
+
+// ajustar suma delta al contador del usuario si existe. Un fallo de Redis no falla la
+// operación: el contador queda desfasado hasta que vence o se reconcilia.
+func (r *RepositorioNotificacionNoLeidas) ajustar(ctx context.Context, usuarioID uint, delta int64) {
+	if enOtraRegion(ctx) {
+		return
+	}
+	err := ajustarSiExiste.Run(ctx, r.redis, []string{claveNoLeidas(ctx, usuarioID)}, delta).Err()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		r.logger.Warn("Error ajustando contador de no leídas", "usuario_id", usuarioID, "error", err)
+	}
+}
+
+func claveNoLeidas(ctx context.Context, usuarioID uint) string {
+	return prefijoNoLeidas + claveEnEsquema(ctx, usuarioID)
+}
+
+// parsearClaveNoLeidas recupera el esquema (vacío para las tablas comunes) y el usuario de la clave
+func parsearClaveNoLeidas(clave string) (string, uint, error) {
+	resto := strings.TrimPrefix(clave, prefijoNoLeidas)
+	esquema, id := "", resto
+	if i := strings.LastIndex(resto, ":"); i >= 0 {
+		esquema, id = resto[:i], resto[i+1:]
+	}
+	usuarioID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil || usuarioID == 0 {
+		return "", 0, fmt.Errorf("clave de contador inválida")
+	}
+	return esquema, uint(usuarioID), nil
+}
@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+)
+
+// RepositorioPreferenciaCacheado decora RepositorioPreferencia cacheando las preferencias por usuario
+type RepositorioPreferenciaCacheado struct {
+	base  repositorio.RepositorioPreferencia
+	cache *CacheDosNiveles
+}
+
+// NuevoRepositorioPreferenciaCacheado crea el decorador
+func NuevoRepositorioPreferenciaCacheado(base repositorio.RepositorioPreferencia, cache *CacheDosNiveles) *RepositorioPreferenciaCacheado {
+	return &RepositorioPreferenciaCacheado{base: base, cache: cache}
+}
+
+// ListarPorUsuario obtiene las preferencias desde cache o base de datos
+func (r *RepositorioPreferenciaCacheado) ListarPorUsuario(ctx context.Context, usuarioID uint) ([]entidad.PreferenciaNotificacion, error) {
+	if enOtraRegion(ctx) {
+		return r.base.ListarPorUsuario(ctx, usuarioID)
+	}
+	var preferencias []entidad.PreferenciaNotificacion
+	err := r.cache.Obtener(ctx, claveEnEsquema(ctx, usuarioID), &preferencias, func(ctx context.Context) (any, error) {
+		return r.base.ListarPorUsuario(ctx, usuarioID)
+	})
+	return preferencias, err
+}
+
+// Guardar persiste la preferencia e invalida las del usuario
+func (r *RepositorioPreferenciaCacheado) Guardar(ctx context.Context, preferencia *entidad.PreferenciaNotificacion) error {
+	if err := r.base.Guardar(ctx, preferencia); err != nil {
+		return err
+	}
+	return r.cache.Invalidar(ctx, claveEnEsquema(ctx, preferencia.UsuarioID))
+}
+
+// PurgarPorUsuario borra definitivamente las preferencias e invalida las del usuario
+func (r *RepositorioPreferenciaCacheado) PurgarPorUsuario(ctx context.Context, usuarioID uint) (int64, error) {
+	purgadas, err := r.base.PurgarPorUsuario(ctx, usuarioID)
+	if err != nil {
+		return 0, err
+	}
+	return purgadas, r.cache.Invalidar(ctx, claveEnEsquema(ctx, usuarioID))
+}
+
+// EliminarPorUsuario elimina las preferencias e invalida las del usuario
+func (r *RepositorioPreferenciaCacheado) EliminarPorUsuario(ctx context.Context, usuarioID uint) (int64, error) {
+	eliminadas, err := r.base.EliminarPorUsuario(ctx, usuarioID)
+	if err != nil {
+		return 0, err
+	}
+	return eliminadas, r.cache.Invalidar(ctx, claveEnEsquema(ctx, usuarioID))
+}
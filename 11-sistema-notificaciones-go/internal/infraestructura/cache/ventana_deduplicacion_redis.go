@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// liberarHuella borra la huella solo si sigue asociada al ID indicado
+var liberarHuella = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// VentanaDeduplicacionRedis implementa VentanaDeduplicacion con SET NX y expiración,
+// compartida por todas las instancias del servicio
+type VentanaDeduplicacionRedis struct {
+	redis  *redis.Client
+	logger *logger.Logger
+}
+
+// NuevaVentanaDeduplicacionRedis crea una nueva instancia de VentanaDeduplicacionRedis
+func NuevaVentanaDeduplicacionRedis(cliente *redis.Client, log *logger.Logger) *VentanaDeduplicacionRedis {
+	return &VentanaDeduplicacionRedis{redis: cliente, logger: log}
+}
+
+// claveDeduplicacion ubica la huella en el espacio del inquilino de la petición
+func claveDeduplicacion(ctx context.Context, huella string) string {
+	inquilinoID := strconv.FormatUint(uint64(servicio.InquilinoDesdeContexto(ctx)), 10)
+	return "notificaciones:dedup:" + inquilinoID + ":" + huella
+}
+
+// Reclamar guarda la huella, aún sin notificación, solo si no existe. Un fallo de Redis no
+// bloquea el envío: se reclama igual y se pierde la deduplicación de esa notificación.
+func (v *VentanaDeduplicacionRedis) Reclamar(ctx context.Context, huella string, ventana time.Duration) (uint, bool, error) {
+	clave := claveDeduplicacion(ctx, huella)
+	reclamada, err := v.redis.SetNX(ctx, clave, 0, ventana).Result()
+	if err != nil {
+		v.logger.Warn("Error reclamando huella de deduplicación", "error", err)
+		return 0, true, nil
+	}
+	if reclamada {
+		return 0, true, nil
+	}
+
+	valor, err := v.redis.Get(ctx, clave).Result()
+	if errors.Is(err, redis.Nil) {
+		// Expiró entre SETNX y GET: la ventana ya pasó
+		return 0, true, nil
+	}
+	if err != nil {
+		v.logger.Warn("Error leyendo huella de deduplicación", "error", err)
+		return 0, true, nil
+	}
+	originalID, err := strconv.ParseUint(valor, 10, 64)
+	if err != nil {
+		return 0, true, nil
+	}
+	metricaDeduplicadas.Inc()
+	return uint(originalID), false, nil
+}
+
+// Confirmar reemplaza la reserva por el ID conservando la expiración; si la huella ya venció no
+// la vuelve a crear. Un fallo se registra sin propagarse: la notificación ya existe y sus
+// duplicados la verán en creación hasta que venza la ventana.
+func (v *VentanaDeduplicacionRedis) Confirmar(ctx context.Context, huella string, notificacionID uint) error {
+	err := v.redis.SetArgs(ctx, claveDeduplicacion(ctx, huella), notificacionID, redis.SetArgs{Mode: "XX", KeepTTL: true}).Err()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		v.logger.Warn("Error confirmando huella de deduplicación", "notificacion_id", notificacionID, "error", err)
+	}
+	return nil
+}
+
+// Liberar elimina la huella de forma atómica solo si sigue asociada a la notificación: otra
+// solicitud pudo reclamarla después
+func (v *VentanaDeduplicacionRedis) Liberar(ctx context.Context, huella string, notificacionID uint) error {
+	return liberarHuella.Run(ctx, v.redis, []string{claveDeduplicacion(ctx, huella)}, strconv.FormatUint(uint64(notificacionID), 10)).Err()
+}
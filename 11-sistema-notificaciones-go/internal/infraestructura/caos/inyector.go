@@ -0,0 +1,120 @@
+package caos
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"gorm.io/gorm"
+)
+
+// ErrBaseDatosInyectado es el error transitorio que el caos agrega a las operaciones de base de datos
+var ErrBaseDatosInyectado = errors.New("caos: error transitorio de base de datos inyectado")
+
+var metricaFallasInyectadas = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "notificaciones_caos_fallas_inyectadas_total",
+	Help: "Fallas inyectadas por las pruebas de resiliencia, por punto de inyección",
+}, []string{"punto"})
+
+// Procesador es el consumidor de la cola de envíos que el caos envuelve
+type Procesador interface {
+	Procesar(ctx context.Context, notificacion *entidad.Notificacion) error
+}
+
+// Inyector introduce fallas aleatorias con las tasas configuradas para ensayar la resiliencia
+// del servicio (game days). Solo se construye fuera de producción.
+type Inyector struct {
+	config    configuracion.ConfiguracionCaos
+	mu        sync.Mutex
+	aleatorio *rand.Rand
+}
+
+// NuevoInyector crea un inyector con las tasas configuradas
+func NuevoInyector(config configuracion.ConfiguracionCaos) *Inyector {
+	return &Inyector{config: config, aleatorio: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+// Demorar retiene la operación una latencia aleatoria con la tasa configurada. Retorna el
+// error del contexto si se cancela durante la espera.
+func (i *Inyector) Demorar(ctx context.Context, punto string) error {
+	if !i.sortear(i.config.TasaLatencia) {
+		return nil
+	}
+	metricaFallasInyectadas.WithLabelValues("latencia_" + punto).Inc()
+
+	i.mu.Lock()
+	demora := time.Duration(i.aleatorio.Int63n(int64(i.config.LatenciaMaxima) + 1))
+	i.mu.Unlock()
+
+	temporizador := time.NewTimer(demora)
+	defer temporizador.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-temporizador.C:
+		return nil
+	}
+}
+
+// DescartarTrama indica si la próxima trama WebSocket debe perderse
+func (i *Inyector) DescartarTrama() bool {
+	if !i.sortear(i.config.TasaDescarteWebSocket) {
+		return false
+	}
+	metricaFallasInyectadas.WithLabelValues("descarte_ws").Inc()
+	return true
+}
+
+// RegistrarEnBaseDatos hace fallar las operaciones de la conexión con ErrBaseDatosInyectado
+// antes de llegar a la base, con la tasa configurada
+func (i *Inyector) RegistrarEnBaseDatos(db *gorm.DB) error {
+	inyectar := func(tx *gorm.DB) {
+		if i.sortear(i.config.TasaErrorBaseDatos) {
+			metricaFallasInyectadas.WithLabelValues("error_bd").Inc()
+			_ = tx.AddError(ErrBaseDatosInyectado)
+		}
+	}
+
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register("caos:create", inyectar),
+		callbacks.Query().Before("gorm:query").Register("caos:query", inyectar),
+		callbacks.Update().Before("gorm:update").Register("caos:update", inyectar),
+		callbacks.Delete().Before("gorm:delete").Register("caos:delete", inyectar),
+		callbacks.Row().Before("gorm:row").Register("caos:row", inyectar),
+		callbacks.Raw().Before("gorm:raw").Register("caos:raw", inyectar),
+	)
+}
+
+// EnvolverProcesador demora el procesamiento de la cola con la tasa de latencia configurada
+func (i *Inyector) EnvolverProcesador(procesador Procesador) Procesador {
+	return procesadorConCaos{procesador: procesador, inyector: i}
+}
+
+func (i *Inyector) sortear(tasa float64) bool {
+	if tasa <= 0 {
+		return false
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.aleatorio.Float64() < tasa
+}
+
+type procesadorConCaos struct {
+	procesador Procesador
+	inyector   *Inyector
+}
+
+func (p procesadorConCaos) Procesar(ctx context.Context, notificacion *entidad.Notificacion) error {
+	if err := p.inyector.Demorar(ctx, "trabajador"); err != nil {
+		return err
+	}
+	return p.procesador.Procesar(ctx, notificacion)
+}
@@ -0,0 +1,55 @@
+package clienteHTTP
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"syscall"
+)
+
+// ErrDestinoInterno indica que el destino de un webhook resuelve a una dirección de la red interna
+var ErrDestinoInterno = errors.New("el destino resuelve a una dirección interna")
+
+// rangosReservados son los rangos que no cubren los métodos de netip.Addr y tampoco son de Internet
+var rangosReservados = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// direccionPublica indica si la IP es enrutable en Internet: no es loopback, privada,
+// link-local, multicast ni de un rango reservado
+func direccionPublica(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsValid() || ip.IsUnspecified() || ip.IsLoopback() || ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, rango := range rangosReservados {
+		if rango.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// controlDestinoPublico rechaza la conexión si la dirección no es pública. Se evalúa con la IP
+// ya resuelta, justo antes de conectar, así un DNS que cambia de respuesta no la evita.
+func controlDestinoPublico(_, direccion string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(direccion)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !direccionPublica(ip) {
+		return fmt.Errorf("%w: %s", ErrDestinoInterno, ip)
+	}
+	return nil
+}
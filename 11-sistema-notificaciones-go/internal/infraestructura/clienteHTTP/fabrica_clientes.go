@@ -0,0 +1,156 @@
+package clienteHTTP
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+)
+
+// salida es la ruta de egreso de un proveedor: el proxy y la IP local desde la que se conecta.
+// Una salida pública solo conecta a direcciones de Internet.
+type salida struct {
+	proxy    string
+	ipOrigen string
+	publica  bool
+}
+
+// FabricaClientes entrega clientes HTTP por proveedor. Los proveedores con la misma salida
+// comparten un único pool de conexiones.
+type FabricaClientes struct {
+	config configuracion.ConfiguracionHTTP
+
+	mu          sync.Mutex
+	transportes map[salida]*http.Transport
+	clientes    map[string]*http.Client
+}
+
+// NuevaFabricaClientes crea la fábrica con transportes afinados para alto volumen
+func NuevaFabricaClientes(config configuracion.ConfiguracionHTTP) *FabricaClientes {
+	return &FabricaClientes{
+		config:      config,
+		transportes: make(map[salida]*http.Transport),
+		clientes:    make(map[string]*http.Client),
+	}
+}
+
+func nuevoTransporte(config configuracion.ConfiguracionHTTP, s salida) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   config.TimeoutConexion,
+		KeepAlive: 30 * time.Second,
+	}
+	if s.ipOrigen != "" {
+		// Con proxy, la IP de origen es la de la conexión al proxy
+		dialer.LocalAddr = &net.TCPAddr{IP: net.ParseIP(s.ipOrigen)}
+	}
+	proxy := funcionProxy(s.proxy)
+	if s.publica {
+		// Sin proxy: el proxy resolvería el destino y la verificación no lo vería
+		dialer.Control = controlDestinoPublico
+		proxy = nil
+	}
+
+	return &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          config.MaxInactivas,
+		MaxIdleConnsPerHost:   config.MaxInactivasPorHost,
+		MaxConnsPerHost:       config.MaxConexionesPorHost,
+		IdleConnTimeout:       config.TiempoInactividad,
+		TLSHandshakeTimeout:   config.TimeoutConexion,
+		ResponseHeaderTimeout: config.TimeoutRespuesta,
+		ExpectContinueTimeout: time.Second,
+	}
+}
+
+// funcionProxy elige el proxy del transporte: el del entorno si no hay uno configurado, ninguno
+// para ProxyDirecto, o la URL indicada (http, https o socks5)
+func funcionProxy(proxy string) func(*http.Request) (*url.URL, error) {
+	switch proxy {
+	case "":
+		return http.ProxyFromEnvironment
+	case configuracion.ProxyDirecto:
+		return nil
+	}
+	u, err := url.Parse(proxy)
+	if err != nil {
+		// La configuración ya validó la URL; si igual falla, las solicitudes fallan en vez de salir sin proxy
+		return func(*http.Request) (*url.URL, error) { return nil, err }
+	}
+	return http.ProxyURL(u)
+}
+
+// salidaDe retorna el proxy y la IP de origen del proveedor, o los predeterminados
+func (f *FabricaClientes) salidaDe(proveedor string) salida {
+	s := salida{proxy: f.config.Proxy, ipOrigen: f.config.IPOrigen}
+	if proxy, existe := f.config.ProxiesProveedor[proveedor]; existe {
+		s.proxy = proxy
+	}
+	if ip, existe := f.config.IPsOrigenProveedor[proveedor]; existe {
+		s.ipOrigen = ip
+	}
+	return s
+}
+
+// Cliente retorna el cliente del proveedor, con su timeout y su salida propios o los
+// predeterminados. Los clientes se reutilizan: no deben modificarse tras obtenerlos.
+func (f *FabricaClientes) Cliente(proveedor string) *http.Client {
+	return f.cliente(proveedor, false)
+}
+
+// ClientePublico retorna un cliente que solo conecta a direcciones de Internet, para URLs que
+// indica quien llama a la API (p. ej. webhooks): rechaza loopback, redes privadas y link-local
+// con ErrDestinoInterno. Sale directo, sin el proxy configurado, y con la IP de origen del proveedor.
+func (f *FabricaClientes) ClientePublico(proveedor string) *http.Client {
+	return f.cliente(proveedor, true)
+}
+
+func (f *FabricaClientes) cliente(proveedor string, publico bool) *http.Client {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	clave := proveedor
+	if publico {
+		clave += ":publico"
+	}
+	if cliente, existe := f.clientes[clave]; existe {
+		return cliente
+	}
+
+	timeout, existe := f.config.TimeoutsProveedor[proveedor]
+	if !existe {
+		timeout = f.config.TimeoutPredeterminado
+	}
+	s := f.salidaDe(proveedor)
+	s.publica = publico
+	transporte, existe := f.transportes[s]
+	if !existe {
+		transporte = nuevoTransporte(f.config, s)
+		f.transportes[s] = transporte
+	}
+
+	cliente := &http.Client{
+		Transport: transporte,
+		Timeout:   timeout,
+		// Los proveedores no deberían redirigir; seguir redirecciones en un POST puede duplicar envíos
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	f.clientes[clave] = cliente
+	return cliente
+}
+
+// Cerrar libera las conexiones inactivas de los pools
+func (f *FabricaClientes) Cerrar() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, transporte := range f.transportes {
+		transporte.CloseIdleConnections()
+	}
+}
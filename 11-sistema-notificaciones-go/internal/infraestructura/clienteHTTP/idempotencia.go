@@ -0,0 +1,19 @@
+package clienteHTTP
+
+import (
+	"net/http"
+
+	"sistema-notificaciones-go/internal/dominio/servicio"
+)
+
+// CabeceraIdempotencia lleva el token del intento de envío en las llamadas a los proveedores
+const CabeceraIdempotencia = "Idempotency-Key"
+
+// AgregarIdempotencia envía el token del intento en curso, si el contexto de la solicitud lo
+// tiene. Un reintento del mismo intento repite el token, así el proveedor, o la pasarela
+// delante de él, puede descartar el duplicado.
+func AgregarIdempotencia(solicitud *http.Request) {
+	if token, ok := servicio.TokenIdempotencia(solicitud.Context()); ok {
+		solicitud.Header.Set(CabeceraIdempotencia, token)
+	}
+}
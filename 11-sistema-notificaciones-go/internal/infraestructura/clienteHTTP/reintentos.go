@@ -0,0 +1,30 @@
+package clienteHTTP
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// EsReintentable indica si un fallo es seguro de reintentar sin riesgo de doble envío:
+// errores de conexión antes de enviar la petición, 429 y 503 (el proveedor no procesó el mensaje).
+func EsReintentable(respuesta *http.Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return false
+		}
+		var errorOp *net.OpError
+		if errors.As(err, &errorOp) && errorOp.Op == "dial" {
+			return true
+		}
+		return false
+	}
+
+	switch respuesta.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	default:
+		return false
+	}
+}
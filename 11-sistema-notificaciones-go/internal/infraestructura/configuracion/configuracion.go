@@ -0,0 +1,1704 @@
+package configuracion
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"github.com/joho/godotenv"
+)
+
+// Modos de ejecución soportados
+const (
+	ModoDesarrollo = "desarrollo"
+	ModoStaging    = "staging"
+	ModoProduccion = "produccion"
+)
+
+// Modos de migración del esquema de la base de datos
+const (
+	// MigracionAutomatica aplica las migraciones pendientes al arrancar (desarrollo)
+	MigracionAutomatica = "automatica"
+	// MigracionRequerida no arranca si hay migraciones pendientes: se aplican antes con "notificaciones migrar"
+	MigracionRequerida = "requerida"
+	// MigracionSoloLectura nunca altera el esquema y arranca aunque haya migraciones pendientes
+	MigracionSoloLectura = "solo_lectura"
+)
+
+// Modos de cifrado de la conexión SMTP
+const (
+	// TLSAutomatico usa STARTTLS si el servidor lo ofrece; MailHog y Mailpit no lo ofrecen
+	TLSAutomatico = "auto"
+	// TLSStartTLS exige STARTTLS y falla si el servidor no lo ofrece (puerto 587)
+	TLSStartTLS = "starttls"
+	// TLSImplicito cifra desde la conexión, sin STARTTLS (SMTPS, puerto 465)
+	TLSImplicito = "implicito"
+	// TLSNinguno nunca cifra; solo para servidores de la red interna
+	TLSNinguno = "ninguno"
+)
+
+// ProxyDirecto en HTTP_PROXIES_PROVEEDORES hace que un proveedor salga sin proxy
+const ProxyDirecto = "directo"
+
+// ConfiguracionBaseDatos contiene la configuración de PostgreSQL
+type ConfiguracionBaseDatos struct {
+	Host          string
+	Puerto        string
+	Nombre        string
+	Usuario       string
+	Contrasena    string
+	ModoSSL       string
+	MaxConexiones int
+	// SentenciasPreparadas habilita el cache de sentencias preparadas (desactivar detrás de PgBouncer en modo transacción)
+	SentenciasPreparadas bool
+	// Esquema fija el search_path de la conexión (vacío: el del servidor)
+	Esquema string
+	// MaxConexionesEsquema es el tamaño del pool de cada inquilino con esquema propio
+	MaxConexionesEsquema int
+	// Migracion es el modo de migración del esquema: automatica, requerida o solo_lectura
+	Migracion string
+}
+
+// DSN retorna la cadena de conexión para PostgreSQL
+func (c ConfiguracionBaseDatos) DSN() string {
+	dsn := fmt.Sprintf("host=%s port=%s dbname=%s user=%s password=%s sslmode=%s",
+		c.Host, c.Puerto, c.Nombre, c.Usuario, c.Contrasena, c.ModoSSL)
+	if c.Esquema != "" {
+		// public queda al final para resolver las tablas de plataforma
+		dsn += fmt.Sprintf(" search_path=%s,public", c.Esquema)
+	}
+	return dsn
+}
+
+// ConfiguracionRedis contiene la configuración de Redis
+type ConfiguracionRedis struct {
+	Host       string
+	Puerto     string
+	Contrasena string
+	BaseDatos  int
+}
+
+// Direccion retorna host:puerto de Redis
+func (c ConfiguracionRedis) Direccion() string {
+	return c.Host + ":" + c.Puerto
+}
+
+// ConfiguracionMongoDB contiene la configuración de MongoDB
+type ConfiguracionMongoDB struct {
+	Host       string
+	Puerto     string
+	BaseDatos  string
+	Usuario    string
+	Contrasena string
+}
+
+// ConfiguracionLog contiene los niveles de log iniciales
+type ConfiguracionLog struct {
+	Nivel string
+	// NivelesComponentes contiene asignaciones "componente=nivel"
+	NivelesComponentes []string
+}
+
+// ConfiguracionAdmin contiene la configuración de los endpoints administrativos
+type ConfiguracionAdmin struct {
+	Token string
+}
+
+// ConfiguracionEnvio contiene los parámetros de envío masivo
+type ConfiguracionEnvio struct {
+	// TamanoLote es la cantidad de filas por INSERT y de mensajes por publicación en cola
+	TamanoLote int
+	// VentanaDeduplicacion colapsa notificaciones idénticas creadas dentro de este plazo (0 la desactiva)
+	VentanaDeduplicacion time.Duration
+	// UmbralAprobacionDifusion exige un borrador aprobado para difundir a canales con más
+	// suscriptores que este valor (0 no exige aprobación)
+	UmbralAprobacionDifusion int
+	// RetencionDifusiones es cuánto se puede consultar el progreso de una difusión terminada
+	RetencionDifusiones time.Duration
+	// MaximoDifusiones acota las difusiones terminadas en memoria; se descartan las más viejas
+	MaximoDifusiones int
+}
+
+// ConfiguracionLimiteAPI contiene el límite de solicitudes a la API por cliente
+type ConfiguracionLimiteAPI struct {
+	// Solicitudes es el máximo por cliente en cada ventana (0 no limita)
+	Solicitudes int
+	Ventana     time.Duration
+}
+
+// ConfiguracionVersionesAPI contiene el calendario de retiro de /api/v1 para las rutas que
+// tienen reemplazo en /api/v2
+type ConfiguracionVersionesAPI struct {
+	// ObsoletaV1 es desde cuándo se anuncian obsoletas (cero no las anuncia)
+	ObsoletaV1 time.Time
+	// RetiroV1 es cuándo dejarán de responder, anunciado en Sunset (cero sin fecha)
+	RetiroV1 time.Time
+}
+
+// ConfiguracionHTTP contiene los parámetros de los clientes HTTP hacia proveedores
+type ConfiguracionHTTP struct {
+	TimeoutPredeterminado time.Duration
+	// TimeoutsProveedor sobrescribe el timeout total por nombre de proveedor
+	TimeoutsProveedor    map[string]time.Duration
+	TimeoutConexion      time.Duration
+	TimeoutRespuesta     time.Duration
+	MaxInactivas         int
+	MaxInactivasPorHost  int
+	MaxConexionesPorHost int
+	TiempoInactividad    time.Duration
+	// Proxy es el proxy de salida (http, https o socks5); vacío usa HTTP_PROXY y HTTPS_PROXY
+	Proxy string
+	// ProxiesProveedor sobrescribe el proxy por nombre de proveedor
+	ProxiesProveedor map[string]string
+	// IPOrigen es la dirección local desde la que se conecta, p. ej. la IP fija que los
+	// proveedores tienen en su lista de permitidos; vacía la elige el sistema
+	IPOrigen string
+	// IPsOrigenProveedor sobrescribe la IP de origen por nombre de proveedor
+	IPsOrigenProveedor map[string]string
+}
+
+// ConfiguracionCache contiene los parámetros del cache de preferencias y canales
+type ConfiguracionCache struct {
+	TamanoLocal int
+	TTLLocal    time.Duration
+	TTL         time.Duration
+}
+
+// ConfiguracionNoLeidas contiene los contadores de no leídas por usuario en Redis
+type ConfiguracionNoLeidas struct {
+	// TTL acota cuánto dura un contador antes de recalcularse en la base
+	TTL time.Duration
+	// IntervaloReconciliacion es cada cuánto se recuentan los contadores para corregir la deriva
+	IntervaloReconciliacion time.Duration
+}
+
+// ConfiguracionCompresion contiene los parámetros de compresión de respuestas
+type ConfiguracionCompresion struct {
+	// Grupos de rutas a los que se aplica la compresión HTTP (p. ej. notificaciones,admin)
+	Grupos      []string
+	UmbralBytes int
+	Nivel       int
+	Brotli      bool
+	// WebSocket habilita la negociación de permessage-deflate
+	WebSocket bool
+}
+
+// AplicaA indica si la compresión está habilitada para un grupo de rutas
+func (c ConfiguracionCompresion) AplicaA(grupo string) bool {
+	for _, nombre := range c.Grupos {
+		if nombre == grupo || nombre == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// ConfiguracionTrabajadores contiene la asignación de trabajadores por prioridad
+type ConfiguracionTrabajadores struct {
+	// PorPrioridad son los trabajadores dedicados a cada prioridad
+	PorPrioridad map[string]int
+	// Compartidos son trabajadores que toman de cualquier cola según Pesos
+	Compartidos int
+	Pesos       map[string]int
+	// CapacidadCola es el tamaño del buffer de cada prioridad
+	CapacidadCola int
+	// IntervaloCarga es cada cuánto se mide lo pendiente de despacho para el autoescalado
+	IntervaloCarga time.Duration
+}
+
+// ConfiguracionReintentos contiene los parámetros de reintento de notificaciones fallidas
+type ConfiguracionReintentos struct {
+	// EsperaBase es la espera antes del primer reintento; se duplica en cada intento hasta EsperaMaxima
+	EsperaBase   time.Duration
+	EsperaMaxima time.Duration
+	// Variacion es la fracción de la espera que se suma o resta al azar (jitter)
+	Variacion float64
+	// Intervalo es cada cuánto se buscan reintentos vencidos en la base de datos
+	Intervalo time.Duration
+	// PlazoReserva aplaza la fecha de reintento al encolar, para no encolar dos veces la misma
+	PlazoReserva time.Duration
+	TamanoLote   int
+}
+
+// Politica retorna la política de espera entre reintentos
+func (c ConfiguracionReintentos) Politica() entidad.PoliticaReintento {
+	return entidad.PoliticaReintento{EsperaBase: c.EsperaBase, EsperaMaxima: c.EsperaMaxima, Variacion: c.Variacion}
+}
+
+// ConfiguracionProgramadas contiene el despacho de las notificaciones programadas al llegar su fecha
+type ConfiguracionProgramadas struct {
+	// Intervalo es cada cuánto se buscan programadas vencidas en la base de datos
+	Intervalo time.Duration
+	// PlazoReserva es cuánto espera una programada encolada antes de volver a encolarse si no se procesó
+	PlazoReserva time.Duration
+	TamanoLote   int
+}
+
+// ConfiguracionBuzonSalida contiene el relevo del buzón de salida, que publica las notificaciones
+// que no pudieron encolarse al crearlas
+type ConfiguracionBuzonSalida struct {
+	// Intervalo es cada cuánto se buscan entradas en el buzón
+	Intervalo time.Duration
+	// Antiguedad es cuánto espera una entrada antes de que la tome el relevo; en ese plazo la
+	// publica y la borra la solicitud que la creó
+	Antiguedad time.Duration
+	TamanoLote int
+}
+
+// ConfiguracionRabbitMQ contiene el despacho por RabbitMQ: el servidor publica las notificaciones
+// y los procesos de cmd/trabajador las consumen y entregan. Sin URL todo se despacha en el pool
+// del servidor.
+type ConfiguracionRabbitMQ struct {
+	URL string
+	// Cola es la cola durable, con prioridades, donde se publican las notificaciones
+	Cola string
+	// Prefetch es cuántas notificaciones entrega en paralelo cada proceso trabajador
+	Prefetch int
+	// EsperaReconexion es la pausa entre intentos de reconectar con el broker
+	EsperaReconexion time.Duration
+	// MaxEntregas acota las entregas de un mensaje que falla por un error transitorio (p. ej. la
+	// base de datos no responde); EsperaReentrega es la pausa antes de devolverlo a la cola
+	MaxEntregas     int
+	EsperaReentrega time.Duration
+	// PuertoMetricas es el puerto del /metrics de los procesos trabajadores
+	PuertoMetricas string
+}
+
+// ConfiguracionRedisStreams contiene el despacho por Redis Streams, alternativa a RabbitMQ que
+// solo requiere el Redis que ya usa el sistema: el servidor agrega las notificaciones a un stream
+// por prioridad y los procesos de cmd/trabajador las leen en un consumer group.
+type ConfiguracionRedisStreams struct {
+	Habilitado bool
+	// Prefijo de los streams; cada prioridad usa "<prefijo>:<prioridad>"
+	Prefijo string
+	// Grupo es el consumer group que comparten los trabajadores
+	Grupo string
+	// Concurrencia es cuántas notificaciones entrega en paralelo cada proceso trabajador
+	Concurrencia int
+	// EsperaLectura es cuánto bloquea una lectura sin mensajes antes de volver a intentar
+	EsperaLectura time.Duration
+	// ReclamarTras es la inactividad tras la cual una entrada pendiente de un trabajador caído
+	// pasa a otro, y IntervaloReclamo cada cuánto se buscan
+	ReclamarTras     time.Duration
+	IntervaloReclamo time.Duration
+	// MaxEntregas acota las entregas de una entrada que falla por un error transitorio; al
+	// alcanzarlas pasa al stream "<prefijo>:muertas"
+	MaxEntregas int
+}
+
+// ConfiguracionKafka contiene la publicación de los eventos del ciclo de vida de las
+// notificaciones y la ingesta de solicitudes de envío desde Kafka. Sin brokers ambas quedan
+// deshabilitadas.
+type ConfiguracionKafka struct {
+	Brokers []string
+	// TemaEventos recibe los eventos creada, enviada, entregada, leida y fallida
+	TemaEventos string
+	// ConsumirSolicitudes habilita TemaSolicitudes como alternativa a la API REST
+	ConsumirSolicitudes bool
+	TemaSolicitudes     string
+	// Grupo es el consumer group que comparten las instancias
+	Grupo string
+	// Consumidores son los lectores del grupo en cada instancia
+	Consumidores int
+	// ReintentosSolicitud son los intentos ante un error transitorio antes de descartar la solicitud
+	ReintentosSolicitud int
+}
+
+// ConfiguracionWebSocket contiene los parámetros del hub de WebSocket
+type ConfiguracionWebSocket struct {
+	// Fragmentos es la cantidad de particiones del registro de conexiones
+	Fragmentos int
+	// BufferEnvio es la cantidad de frames pendientes por conexión antes de descartar
+	BufferEnvio int
+	// OrigenesPermitidos son los Origin (esquema y host) desde los que un navegador puede
+	// conectarse además del propio host del servidor
+	OrigenesPermitidos []string
+}
+
+// longitudMinimaSecretoJWT es el mínimo de bytes de la clave HMAC: 256 bits, lo que pide HS256
+const longitudMinimaSecretoJWT = 32
+
+// ConfiguracionJWT contiene la configuración de validación de tokens de usuario
+type ConfiguracionJWT struct {
+	Secreto string
+}
+
+// ConfiguracionCifrado contiene la clave de cifrado de datos sensibles en reposo
+type ConfiguracionCifrado struct {
+	// Clave AES-256 codificada en base64; vacía deshabilita las credenciales por inquilino
+	Clave string
+}
+
+// ConfiguracionAuditoria contiene la clave con que se sella la cadena del registro de accesos
+type ConfiguracionAuditoria struct {
+	// ClaveSello es la clave HMAC de los sellos; vacía deja la cadena verificable solo por hashes
+	ClaveSello string
+}
+
+// ConfiguracionSLA contiene los parámetros de evaluación de SLA por inquilino
+type ConfiguracionSLA struct {
+	// IntervaloEvaluacion es cada cuánto se recalcula el cumplimiento del mes en curso
+	IntervaloEvaluacion time.Duration
+}
+
+// ConfiguracionEscalamientos contiene los parámetros de la ejecución de escalamientos de guardia
+type ConfiguracionEscalamientos struct {
+	// Intervalo es cada cuánto se buscan pasos vencidos; acota el retraso de cada aviso
+	Intervalo time.Duration
+}
+
+// ConfiguracionLiderazgo contiene la elección de la instancia que ejecuta los trabajos únicos
+type ConfiguracionLiderazgo struct {
+	// Instancia identifica a esta instancia al disputar el liderazgo (por defecto host y proceso)
+	Instancia string
+	// Duracion es cuánto vale el arrendamiento sin renovarse: acota el tiempo sin líder si la actual cae
+	Duracion time.Duration
+}
+
+// ConfiguracionReportes contiene el envío programado del reporte operativo a los administradores
+type ConfiguracionReportes struct {
+	// Destinatarios son los IDs de los administradores de plataforma; vacío deshabilita el envío
+	Destinatarios []uint
+	// Frecuencias son los reportes que se envían: diario, semanal o ambos
+	Frecuencias []string
+	// Hora (UTC) a partir de la cual se envía el reporte del periodo cerrado
+	Hora int
+	// Tipo es el tipo de notificación por el que se envía
+	Tipo string
+	// Intervalo es cada cuánto se verifica si hay un reporte pendiente de envío
+	Intervalo time.Duration
+}
+
+// ConfiguracionExportacion contiene los parámetros de las exportaciones de datos de inquilinos
+type ConfiguracionExportacion struct {
+	// Directorio donde se guardan los archivos generados hasta su descarga
+	Directorio string
+}
+
+// ConfiguracionRetencion contiene los parámetros de la purga por políticas de retención
+type ConfiguracionRetencion struct {
+	// Intervalo es cada cuánto se purgan las notificaciones vencidas
+	Intervalo  time.Duration
+	TamanoLote int
+	// SecretoAnonimizacion firma la huella que reemplaza al usuario en las notificaciones
+	// anonimizadas; vacío deshabilita las políticas de anonimización
+	SecretoAnonimizacion string
+}
+
+// ConfiguracionSuscripciones contiene los parámetros del doble opt-in de canales de marketing
+// y de las invitaciones a canales privados
+type ConfiguracionSuscripciones struct {
+	// Secreto firma los enlaces de confirmación e invitación; vacío deshabilita ambos
+	Secreto string
+	// URLConfirmacion es la dirección pública a la que apunta el enlace (se le agrega ?token=)
+	URLConfirmacion string
+	// URLInvitacion es la dirección pública del enlace para aceptar una invitación
+	URLInvitacion string
+	// VigenciaEnlace es el plazo para confirmar antes de tener que suscribirse de nuevo
+	VigenciaEnlace time.Duration
+	// TipoConfirmacion es el tipo de notificación por el que se envía el enlace
+	TipoConfirmacion string
+}
+
+// ConfiguracionCorreo contiene el servidor SMTP por el que se envían las notificaciones de email
+type ConfiguracionCorreo struct {
+	// Host vacío deshabilita el envío de email salvo que se simule
+	Host   string
+	Puerto int
+	// TLS es el modo de cifrado: auto, starttls, implicito o ninguno
+	TLS       string
+	Usuario   string
+	Clave     string
+	Remitente string
+	// BuzonCaptura es la URL de la API de MailHog o Mailpit que captura los correos en
+	// desarrollo; habilita la consulta de los últimos capturados. No se admite en producción.
+	BuzonCaptura string
+	// DireccionRespuestas es el buzón (p. ej. respuestas@ejemplo.com) que recibe las respuestas
+	// de los destinatarios; cada correo lo lleva en Reply-To con un token firmado tras un "+".
+	// Vacía deshabilita el procesamiento de respuestas.
+	DireccionRespuestas string
+	// SecretoRespuestas firma el token de la dirección de respuesta
+	SecretoRespuestas string
+	// TokenEntrante autentica al proveedor que reenvía los correos recibidos al webhook
+	TokenEntrante string
+	// MaxBytesEntrante acota el tamaño de un correo recibido
+	MaxBytesEntrante int64
+}
+
+// ConfiguracionSendGrid contiene la cuenta de SendGrid por la que salen las notificaciones
+// email en lugar del servidor SMTP
+type ConfiguracionSendGrid struct {
+	// ClaveAPI vacía deshabilita SendGrid salvo que se simule
+	ClaveAPI  string
+	Remitente string
+	URLAPI    string
+	// ClaveWebhook es la clave pública (base64) con que SendGrid firma el Event Webhook;
+	// vacía deshabilita la recepción de eventos
+	ClaveWebhook string
+	// ToleranciaFirma es cuánto puede diferir del reloj la marca de tiempo firmada del Event
+	// Webhook; una entrega capturada no se acepta pasado ese plazo
+	ToleranciaFirma time.Duration
+	// SeguimientoAperturas pide a SendGrid el píxel de apertura; sin él no llegan eventos open
+	SeguimientoAperturas bool
+	// Idempotente indica que la API, o la pasarela delante de SendGrid, deduplica por Idempotency-Key
+	Idempotente bool
+}
+
+// ConfiguracionSES contiene la cuenta de Amazon SES por la que salen las notificaciones email
+// en lugar del servidor SMTP, y los temas SNS por los que SES informa rebotes y quejas
+type ConfiguracionSES struct {
+	// Region vacía deshabilita SES salvo que se simule
+	Region       string
+	ClaveAcceso  string
+	ClaveSecreta string
+	Remitente    string
+	// ConjuntoConfiguracion es el configuration set del envío; vacío usa el de la identidad
+	ConjuntoConfiguracion string
+	// URLAPI vacía usa el endpoint de la región, https://email.<región>.amazonaws.com
+	URLAPI string
+	// TemasSNS son los ARN de los temas cuyas notificaciones se aceptan: la firma de SNS solo
+	// prueba que el mensaje viene de SNS, no de qué cuenta. Vacío deshabilita el endpoint.
+	TemasSNS []string
+	// VigenciaMensajes es cuánto se recuerda a qué notificación pertenece cada correo; las
+	// quejas pueden llegar días después del envío
+	VigenciaMensajes time.Duration
+}
+
+// ConfiguracionMailgun contiene la clave con que Mailgun firma sus webhooks de eventos. Mailgun no
+// es un proveedor propio: sus correos salen por el relay SMTP de Mailgun configurado como
+// servidor de correo.
+type ConfiguracionMailgun struct {
+	// ClaveWebhook es la HTTP webhook signing key de la cuenta de la plataforma; vacía solo
+	// acepta los webhooks de inquilinos con clave_webhook propia
+	ClaveWebhook string
+	// ToleranciaFirma es la antigüedad máxima de la marca de tiempo firmada, contra reenvíos
+	ToleranciaFirma time.Duration
+}
+
+// ConfiguracionSMPP contiene la conexión directa por SMPP 3.4 con el SMSC de un operador, la
+// ruta de SMS alternativa a los agregadores HTTP
+type ConfiguracionSMPP struct {
+	// Host vacío deshabilita la ruta SMPP salvo que se simule
+	Host        string
+	Puerto      int
+	TLS         bool
+	SistemaID   string
+	Clave       string
+	TipoSistema string
+	// Remitente es el número (E.164) o el alfanumérico de hasta 11 caracteres de origen
+	Remitente string
+	// Ventana es cuántos submit_sm esperan respuesta a la vez en cada sesión
+	Ventana          int
+	TimeoutRespuesta time.Duration
+	// IntervaloEnlace es cada cuánto se verifica la sesión con enquire_link
+	IntervaloEnlace time.Duration
+	// Acuses pide acuses de entrega; VigenciaAcuses es cuánto se esperan
+	Acuses         bool
+	VigenciaAcuses time.Duration
+}
+
+// ConfiguracionTwilio contiene la cuenta de Twilio por la que se envían los SMS cuando no
+// hay ruta SMPP
+type ConfiguracionTwilio struct {
+	// SIDCuenta vacío deshabilita Twilio salvo que se simule
+	SIDCuenta          string
+	TokenAutenticacion string
+	// Remitente es el número E.164 de origen; ServicioMensajeria (MG...) lo reemplaza y deja
+	// que Twilio elija el número del servicio
+	Remitente          string
+	ServicioMensajeria string
+	URLAPI             string
+	// URLAcuses es la URL pública de /api/v1/twilio/acuses a la que Twilio informa el estado
+	// de cada mensaje; vacía no pide acuses. VigenciaAcuses es cuánto se esperan.
+	URLAcuses      string
+	VigenciaAcuses time.Duration
+	// RemitenteVoz es el número E.164 desde el que se llama en las notificaciones voz; vacío
+	// usa Remitente. VozTTS e IdiomaVoz eligen la voz con que se lee el mensaje, que se repite
+	// RepeticionesVoz veces; TimeoutLlamada es cuánto suena antes de darse por no atendida.
+	RemitenteVoz    string
+	VozTTS          string
+	IdiomaVoz       string
+	RepeticionesVoz int
+	TimeoutLlamada  time.Duration
+	// Idempotente declara que la API descarta los reenvíos con la misma Idempotency-Key (p. ej.
+	// una pasarela delante de Twilio); así un envío de resultado incierto se reintenta
+	Idempotente bool
+}
+
+// ConfiguracionSlack contiene el bot con que se publica en los canales de Slack por
+// chat.postMessage; los canales con incoming webhook no lo necesitan
+type ConfiguracionSlack struct {
+	// Token es el token del bot (xoxb-...); los inquilinos pueden usar el suyo
+	Token  string
+	URLAPI string
+	// Idempotente indica que SLACK_URL_API apunta a una pasarela que deduplica por Idempotency-Key
+	Idempotente bool
+}
+
+// ConfiguracionTelegram contiene el bot de Telegram con que se entregan las notificaciones
+// telegram a los usuarios que vincularon su chat con el enlace /start
+type ConfiguracionTelegram struct {
+	// Token es el token del bot que entrega BotFather; vacío deshabilita el canal
+	Token string
+	// Bot es el nombre de usuario del bot, sin @, con que se arma el enlace t.me
+	Bot string
+	// SecretoWebhook es el secret_token registrado con setWebhook; Telegram lo envía en
+	// X-Telegram-Bot-Api-Secret-Token en cada actualización
+	SecretoWebhook string
+	// SecretoEnlace firma los enlaces de vinculación, válidos durante VigenciaEnlace
+	SecretoEnlace  string
+	VigenciaEnlace time.Duration
+	URLAPI         string
+}
+
+// ConfiguracionWebPush contiene las claves VAPID con que el servicio se identifica ante los
+// servicios push de los navegadores; se generan con "notificaciones vapid"
+type ConfiguracionWebPush struct {
+	// ClavePublica (punto P-256 sin comprimir) y ClavePrivada (escalar) van en base64url;
+	// sin ClavePrivada el envío Web Push queda deshabilitado salvo que se simule
+	ClavePublica string
+	ClavePrivada string
+	// Sujeto es el contacto del operador (mailto: o https:) que los servicios push usan ante abusos
+	Sujeto string
+	// TTL es cuánto conserva el servicio push un mensaje para un navegador desconectado
+	TTL time.Duration
+}
+
+// ConfiguracionEnlacesCortos contiene el acortador de las URL de los SMS
+type ConfiguracionEnlacesCortos struct {
+	// URLBase es la dirección pública de la redirección, p. ej. https://s.ejemplo.com/e; cada
+	// enlace es URLBase/<slug>. Vacía deshabilita el acortador.
+	URLBase  string
+	Vigencia time.Duration
+	// LongitudSlug es la cantidad de caracteres alfanuméricos del identificador de cada enlace
+	LongitudSlug int
+}
+
+// ConfiguracionAdjuntos contiene el almacén compatible con S3 (AWS S3, MinIO) de los archivos
+// adjuntos a las notificaciones y los límites de lo que se puede subir
+type ConfiguracionAdjuntos struct {
+	// Endpoint es la URL del servicio, p. ej. https://s3.us-east-1.amazonaws.com o http://minio:9000
+	Endpoint string
+	Region   string
+	// Bucket vacío deshabilita los adjuntos
+	Bucket       string
+	ClaveAcceso  string
+	ClaveSecreta string
+	// EstiloRuta direcciona el bucket en la ruta (endpoint/bucket/clave), como requiere MinIO;
+	// sin él va como subdominio (bucket.endpoint/clave)
+	EstiloRuta bool
+	// MaxBytes acota el tamaño de cada archivo
+	MaxBytes int64
+	// Tipos son los tipos de contenido admitidos, detectados por el contenido y no por la extensión
+	Tipos []string
+	// VigenciaURL es cuánto vale cada URL de descarga firmada
+	VigenciaURL time.Duration
+	// ClamAV es la dirección host:puerto de clamd para analizar cada archivo antes de guardarlo;
+	// vacía no los analiza
+	ClamAV string
+}
+
+// ConfiguracionCodigosQR contiene la generación de códigos QR a partir de un metadato, p. ej.
+// para entradas o pases de acceso
+type ConfiguracionCodigosQR struct {
+	// Campo es el metadato cuyo valor se codifica; vacío deshabilita los códigos QR
+	Campo string
+	// URLBase es la dirección pública del servicio para las URL firmadas de la imagen, p. ej.
+	// https://api.ejemplo.com; vacía solo adjunta el código en los correos
+	URLBase  string
+	Secreto  string
+	Vigencia time.Duration
+	// Escala son los píxeles por módulo de la imagen
+	Escala int
+}
+
+// ConfiguracionAvisosLectura contiene la entrega de avisos de lectura al servicio de origen
+type ConfiguracionAvisosLectura struct {
+	// Webhooks habilita los avisos por webhook; sin ellos solo se publican en los temas
+	Webhooks bool
+	// Secreto firma el cuerpo de los webhooks con HMAC-SHA256; es obligatorio con Webhooks
+	Secreto string
+	// RedInterna permite webhooks a loopback y redes privadas, p. ej. al eco de desarrollo
+	RedInterna bool
+	// Intentos y Espera rigen los reintentos del webhook; la espera se duplica en cada intento
+	Intentos int
+	Espera   time.Duration
+	// LongitudTema acota los avisos retenidos en cada tema de la cola
+	LongitudTema int64
+}
+
+// ConfiguracionSimulacion contiene los proveedores simulados de los entornos de prueba
+type ConfiguracionSimulacion struct {
+	// Proveedores son los tipos de notificación que se envían por un proveedor simulado con
+	// fallas configurables en lugar del real; no se admite en producción
+	Proveedores []string
+	// Sandbox simula todos los tipos que salen a un proveedor externo y expone lo enviado en
+	// GET /api/v1/sandbox/envios, para pruebas de integración y staging
+	Sandbox bool
+}
+
+// tiposSandbox son los tipos de notificación que salen del servicio a un proveedor externo; el
+// WebSocket y la bandeja in-app se entregan igual en modo sandbox
+var tiposSandbox = []string{"email", "sms", "push", "slack", "telegram", "voz"}
+
+// ConfiguracionEco contiene el receptor de webhooks de prueba que los integradores registran
+// como destino durante el desarrollo; no se admite en producción
+type ConfiguracionEco struct {
+	Habilitado bool
+	// Capacidad es cuántas entregas se conservan por buzón; las más viejas se descartan
+	Capacidad int
+	// Buzones acota los buzones en memoria; se descarta el que lleva más tiempo sin entregas
+	Buzones int
+}
+
+// ConfiguracionCaos contiene las fallas aleatorias que se inyectan para ensayar la resiliencia
+// del servicio. Las tasas son probabilidades entre 0 y 1; no se admite en producción.
+type ConfiguracionCaos struct {
+	Habilitado bool
+	// TasaLatencia es la probabilidad de demorar una petición HTTP o un procesamiento de la cola
+	TasaLatencia float64
+	// LatenciaMaxima acota la demora inyectada, sorteada entre 0 y este valor
+	LatenciaMaxima        time.Duration
+	TasaDescarteWebSocket float64
+	TasaErrorBaseDatos    float64
+}
+
+// ConfiguracionRegion contiene los backends de una región de residencia de datos: los
+// inquilinos de la región guardan sus datos en BaseDatos y envían por los endpoints de Proveedores
+type ConfiguracionRegion struct {
+	BaseDatos ConfiguracionBaseDatos
+	// Proveedores es la URL base regional de cada proveedor; los que no figuran no pueden usarse
+	Proveedores map[string]string
+}
+
+// Configuracion representa la configuración completa del servicio
+type Configuracion struct {
+	Modo          string
+	Puerto        string
+	BaseDatos     ConfiguracionBaseDatos
+	Redis         ConfiguracionRedis
+	MongoDB       ConfiguracionMongoDB
+	Log           ConfiguracionLog
+	Admin         ConfiguracionAdmin
+	Envio         ConfiguracionEnvio
+	LimiteAPI     ConfiguracionLimiteAPI
+	VersionesAPI  ConfiguracionVersionesAPI
+	HTTP          ConfiguracionHTTP
+	Cache         ConfiguracionCache
+	NoLeidas      ConfiguracionNoLeidas
+	Compresion    ConfiguracionCompresion
+	Trabajadores  ConfiguracionTrabajadores
+	Reintentos    ConfiguracionReintentos
+	Programadas   ConfiguracionProgramadas
+	BuzonSalida   ConfiguracionBuzonSalida
+	RabbitMQ      ConfiguracionRabbitMQ
+	RedisStreams  ConfiguracionRedisStreams
+	Kafka         ConfiguracionKafka
+	WebSocket     ConfiguracionWebSocket
+	JWT           ConfiguracionJWT
+	Cifrado       ConfiguracionCifrado
+	Auditoria     ConfiguracionAuditoria
+	SLA           ConfiguracionSLA
+	Reportes      ConfiguracionReportes
+	Escalamientos ConfiguracionEscalamientos
+	Liderazgo     ConfiguracionLiderazgo
+	Exportacion   ConfiguracionExportacion
+	Retencion     ConfiguracionRetencion
+	Suscripciones ConfiguracionSuscripciones
+	Correo        ConfiguracionCorreo
+	WebPush       ConfiguracionWebPush
+	SendGrid      ConfiguracionSendGrid
+	SES           ConfiguracionSES
+	Mailgun       ConfiguracionMailgun
+	SMPP          ConfiguracionSMPP
+	Twilio        ConfiguracionTwilio
+	Slack         ConfiguracionSlack
+	Telegram      ConfiguracionTelegram
+	EnlacesCortos ConfiguracionEnlacesCortos
+	CodigosQR     ConfiguracionCodigosQR
+	Adjuntos      ConfiguracionAdjuntos
+	AvisosLectura ConfiguracionAvisosLectura
+	Simulacion    ConfiguracionSimulacion
+	Caos          ConfiguracionCaos
+	Eco           ConfiguracionEco
+	// Regiones son las regiones de residencia de datos habilitadas, por nombre
+	Regiones map[string]ConfiguracionRegion
+}
+
+// CargarConfiguracion carga la configuración del perfil seleccionado por MODO.
+// Prioridad: variables de entorno > perfil (con herencia) > valores por defecto.
+func CargarConfiguracion() (*Configuracion, error) {
+	// El archivo .env es opcional
+	_ = godotenv.Load()
+
+	modo := os.Getenv("MODO")
+	if modo == "" {
+		modo = ModoDesarrollo
+	}
+
+	perfiles, err := cargarPerfiles(os.Getenv("CONFIG_PERFILES"))
+	if err != nil {
+		return nil, err
+	}
+
+	valores, err := resolverPerfil(perfiles, modo)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &fuente{perfil: valores}
+
+	config := &Configuracion{
+		Modo:   modo,
+		Puerto: f.texto("PUERTO", "8080"),
+		BaseDatos: ConfiguracionBaseDatos{
+			Host:                 f.texto("DB_HOST", "localhost"),
+			Puerto:               f.texto("DB_PORT", "5432"),
+			Nombre:               f.texto("DB_NAME", "notificaciones"),
+			Usuario:              f.texto("DB_USER", ""),
+			Contrasena:           f.texto("DB_PASSWORD", ""),
+			ModoSSL:              f.texto("DB_SSLMODE", "disable"),
+			MaxConexiones:        f.entero("DB_MAX_CONEXIONES", 20),
+			SentenciasPreparadas: f.booleano("DB_SENTENCIAS_PREPARADAS", true),
+			MaxConexionesEsquema: f.entero("DB_MAX_CONEXIONES_ESQUEMA", 4),
+			Migracion:            f.texto("DB_MIGRACION", MigracionRequerida),
+		},
+		Redis: ConfiguracionRedis{
+			Host:       f.texto("REDIS_HOST", "localhost"),
+			Puerto:     f.texto("REDIS_PORT", "6379"),
+			Contrasena: f.texto("REDIS_PASSWORD", ""),
+			BaseDatos:  f.entero("REDIS_DB", 0),
+		},
+		MongoDB: ConfiguracionMongoDB{
+			Host:       f.texto("MONGODB_HOST", "localhost"),
+			Puerto:     f.texto("MONGODB_PORT", "27017"),
+			BaseDatos:  f.texto("MONGODB_DATABASE", "notificaciones"),
+			Usuario:    f.texto("MONGODB_USERNAME", ""),
+			Contrasena: f.texto("MONGODB_PASSWORD", ""),
+		},
+		Log: ConfiguracionLog{
+			Nivel:              f.texto("LOG_NIVEL", "info"),
+			NivelesComponentes: f.lista("LOG_NIVELES_COMPONENTES"),
+		},
+		Admin: ConfiguracionAdmin{
+			Token: f.texto("ADMIN_TOKEN", ""),
+		},
+		Envio: ConfiguracionEnvio{
+			TamanoLote:               f.entero("ENVIO_TAMANO_LOTE", 1000),
+			VentanaDeduplicacion:     f.duracion("ENVIO_VENTANA_DEDUPLICACION", 0),
+			UmbralAprobacionDifusion: f.entero("ENVIO_UMBRAL_APROBACION_DIFUSION", 0),
+			RetencionDifusiones:      f.duracion("ENVIO_RETENCION_DIFUSIONES", time.Hour),
+			MaximoDifusiones:         f.entero("ENVIO_MAXIMO_DIFUSIONES", 1000),
+		},
+		LimiteAPI: ConfiguracionLimiteAPI{
+			Solicitudes: f.entero("API_LIMITE_SOLICITUDES", 0),
+			Ventana:     f.duracion("API_LIMITE_VENTANA", time.Minute),
+		},
+		HTTP: ConfiguracionHTTP{
+			TimeoutPredeterminado: f.duracion("HTTP_TIMEOUT", 10*time.Second),
+			TimeoutsProveedor:     f.duraciones("HTTP_TIMEOUTS_PROVEEDORES"),
+			TimeoutConexion:       f.duracion("HTTP_TIMEOUT_CONEXION", 3*time.Second),
+			TimeoutRespuesta:      f.duracion("HTTP_TIMEOUT_RESPUESTA", 8*time.Second),
+			MaxInactivas:          f.entero("HTTP_MAX_INACTIVAS", 200),
+			MaxInactivasPorHost:   f.entero("HTTP_MAX_INACTIVAS_POR_HOST", 100),
+			MaxConexionesPorHost:  f.entero("HTTP_MAX_CONEXIONES_POR_HOST", 200),
+			TiempoInactividad:     f.duracion("HTTP_TIEMPO_INACTIVIDAD", 90*time.Second),
+			Proxy:                 f.texto("HTTP_PROXY_SALIDA", ""),
+			ProxiesProveedor:      f.textos("HTTP_PROXIES_PROVEEDORES"),
+			IPOrigen:              f.texto("HTTP_IP_ORIGEN", ""),
+			IPsOrigenProveedor:    f.textos("HTTP_IPS_ORIGEN_PROVEEDORES"),
+		},
+		Cache: ConfiguracionCache{
+			TamanoLocal: f.entero("CACHE_TAMANO_LOCAL", 10000),
+			TTLLocal:    f.duracion("CACHE_TTL_LOCAL", 30*time.Second),
+			TTL:         f.duracion("CACHE_TTL", 10*time.Minute),
+		},
+		NoLeidas: ConfiguracionNoLeidas{
+			TTL:                     f.duracion("NO_LEIDAS_TTL", 24*time.Hour),
+			IntervaloReconciliacion: f.duracion("NO_LEIDAS_INTERVALO_RECONCILIACION", 10*time.Minute),
+		},
+		Compresion: ConfiguracionCompresion{
+			Grupos:      f.lista("COMPRESION_GRUPOS"),
+			UmbralBytes: f.entero("COMPRESION_UMBRAL_BYTES", 1024),
+			Nivel:       f.entero("COMPRESION_NIVEL", 5),
+			Brotli:      f.booleano("COMPRESION_BROTLI", true),
+			WebSocket:   f.booleano("COMPRESION_WEBSOCKET", false),
+		},
+		Trabajadores: ConfiguracionTrabajadores{
+			PorPrioridad:   f.enteros("TRABAJADORES_POR_PRIORIDAD"),
+			Compartidos:    f.entero("TRABAJADORES_COMPARTIDOS", 4),
+			Pesos:          f.enteros("TRABAJADORES_PESOS"),
+			CapacidadCola:  f.entero("TRABAJADORES_CAPACIDAD_COLA", 10000),
+			IntervaloCarga: f.duracion("TRABAJADORES_INTERVALO_CARGA", 15*time.Second),
+		},
+		Reintentos: ConfiguracionReintentos{
+			EsperaBase:   f.duracion("REINTENTOS_ESPERA_BASE", 30*time.Second),
+			EsperaMaxima: f.duracion("REINTENTOS_ESPERA_MAXIMA", time.Hour),
+			Variacion:    f.decimal("REINTENTOS_VARIACION", 0.2),
+			Intervalo:    f.duracion("REINTENTOS_INTERVALO", 10*time.Second),
+			PlazoReserva: f.duracion("REINTENTOS_PLAZO_RESERVA", 5*time.Minute),
+			TamanoLote:   f.entero("REINTENTOS_TAMANO_LOTE", 500),
+		},
+		Programadas: ConfiguracionProgramadas{
+			Intervalo:    f.duracion("PROGRAMADAS_INTERVALO", 5*time.Second),
+			PlazoReserva: f.duracion("PROGRAMADAS_PLAZO_RESERVA", 5*time.Minute),
+			TamanoLote:   f.entero("PROGRAMADAS_TAMANO_LOTE", 500),
+		},
+		BuzonSalida: ConfiguracionBuzonSalida{
+			Intervalo:  f.duracion("BUZON_SALIDA_INTERVALO", 2*time.Second),
+			Antiguedad: f.duracion("BUZON_SALIDA_ANTIGUEDAD", 10*time.Second),
+			TamanoLote: f.entero("BUZON_SALIDA_TAMANO_LOTE", 500),
+		},
+		RabbitMQ: ConfiguracionRabbitMQ{
+			URL:              f.texto("RABBITMQ_URL", ""),
+			Cola:             f.texto("RABBITMQ_COLA", "notificaciones"),
+			Prefetch:         f.entero("RABBITMQ_PREFETCH", 32),
+			EsperaReconexion: f.duracion("RABBITMQ_ESPERA_RECONEXION", 5*time.Second),
+			MaxEntregas:      f.entero("RABBITMQ_MAX_ENTREGAS", 5),
+			EsperaReentrega:  f.duracion("RABBITMQ_ESPERA_REENTREGA", time.Second),
+			PuertoMetricas:   f.texto("TRABAJADOR_PUERTO_METRICAS", "9091"),
+		},
+		RedisStreams: ConfiguracionRedisStreams{
+			Habilitado:       f.booleano("REDIS_STREAMS_HABILITADO", false),
+			Prefijo:          f.texto("REDIS_STREAMS_PREFIJO", "notificaciones:cola"),
+			Grupo:            f.texto("REDIS_STREAMS_GRUPO", "trabajadores"),
+			Concurrencia:     f.entero("REDIS_STREAMS_CONCURRENCIA", 32),
+			EsperaLectura:    f.duracion("REDIS_STREAMS_ESPERA_LECTURA", 5*time.Second),
+			ReclamarTras:     f.duracion("REDIS_STREAMS_RECLAMAR_TRAS", time.Minute),
+			IntervaloReclamo: f.duracion("REDIS_STREAMS_INTERVALO_RECLAMO", 30*time.Second),
+			MaxEntregas:      f.entero("REDIS_STREAMS_MAX_ENTREGAS", 5),
+		},
+		Kafka: ConfiguracionKafka{
+			Brokers:             f.lista("KAFKA_BROKERS"),
+			TemaEventos:         f.texto("KAFKA_TEMA_EVENTOS", "notificaciones-eventos"),
+			ConsumirSolicitudes: f.booleano("KAFKA_CONSUMIR_SOLICITUDES", false),
+			TemaSolicitudes:     f.texto("KAFKA_TEMA_SOLICITUDES", "solicitudes-notificacion"),
+			Grupo:               f.texto("KAFKA_GRUPO", "sistema-notificaciones"),
+			Consumidores:        f.entero("KAFKA_CONSUMIDORES", 2),
+			ReintentosSolicitud: f.entero("KAFKA_REINTENTOS_SOLICITUD", 3),
+		},
+		WebSocket: ConfiguracionWebSocket{
+			Fragmentos:         f.entero("WS_FRAGMENTOS", 64),
+			BufferEnvio:        f.entero("WS_BUFFER_ENVIO", 64),
+			OrigenesPermitidos: f.lista("WS_ORIGENES_PERMITIDOS"),
+		},
+		JWT: ConfiguracionJWT{
+			Secreto: f.texto("JWT_SECRETO", ""),
+		},
+		Cifrado: ConfiguracionCifrado{
+			Clave: f.texto("CIFRADO_CLAVE", ""),
+		},
+		Auditoria: ConfiguracionAuditoria{
+			ClaveSello: f.texto("AUDITORIA_CLAVE_SELLO", ""),
+		},
+		SLA: ConfiguracionSLA{
+			IntervaloEvaluacion: f.duracion("SLA_INTERVALO_EVALUACION", time.Minute),
+		},
+		Reportes: ConfiguracionReportes{
+			Frecuencias: f.lista("REPORTES_FRECUENCIAS"),
+			Hora:        f.entero("REPORTES_HORA", 7),
+			Tipo:        f.texto("REPORTES_TIPO", "email"),
+			Intervalo:   f.duracion("REPORTES_INTERVALO", 15*time.Minute),
+		},
+		Escalamientos: ConfiguracionEscalamientos{
+			Intervalo: f.duracion("ESCALAMIENTOS_INTERVALO", 15*time.Second),
+		},
+		Liderazgo: ConfiguracionLiderazgo{
+			Instancia: f.texto("LIDER_INSTANCIA", instanciaPredeterminada()),
+			Duracion:  f.duracion("LIDER_DURACION", 15*time.Second),
+		},
+		Exportacion: ConfiguracionExportacion{
+			Directorio: f.texto("EXPORTACION_DIRECTORIO", "exportaciones"),
+		},
+		Retencion: ConfiguracionRetencion{
+			Intervalo:            f.duracion("RETENCION_INTERVALO", time.Hour),
+			TamanoLote:           f.entero("RETENCION_TAMANO_LOTE", 1000),
+			SecretoAnonimizacion: f.texto("RETENCION_SECRETO_ANONIMIZACION", ""),
+		},
+		Suscripciones: ConfiguracionSuscripciones{
+			Secreto:          f.texto("SUSCRIPCIONES_SECRETO", ""),
+			URLConfirmacion:  f.texto("SUSCRIPCIONES_URL_CONFIRMACION", ""),
+			URLInvitacion:    f.texto("SUSCRIPCIONES_URL_INVITACION", ""),
+			VigenciaEnlace:   f.duracion("SUSCRIPCIONES_VIGENCIA_ENLACE", 72*time.Hour),
+			TipoConfirmacion: f.texto("SUSCRIPCIONES_TIPO_CONFIRMACION", "email"),
+		},
+		Correo: ConfiguracionCorreo{
+			Host:         f.texto("SMTP_HOST", ""),
+			Puerto:       f.entero("SMTP_PUERTO", 587),
+			TLS:          f.texto("SMTP_TLS", TLSAutomatico),
+			Usuario:      f.texto("SMTP_USUARIO", ""),
+			Clave:        f.texto("SMTP_CLAVE", ""),
+			Remitente:    f.texto("SMTP_REMITENTE", ""),
+			BuzonCaptura: f.texto("CORREO_BUZON_CAPTURA", ""),
+
+			DireccionRespuestas: f.texto("CORREO_RESPUESTAS_DIRECCION", ""),
+			SecretoRespuestas:   f.texto("CORREO_RESPUESTAS_SECRETO", ""),
+			TokenEntrante:       f.texto("CORREO_ENTRANTE_TOKEN", ""),
+			MaxBytesEntrante:    int64(f.entero("CORREO_ENTRANTE_MAX_BYTES", 10<<20)),
+		},
+		Twilio: ConfiguracionTwilio{
+			SIDCuenta:          f.texto("TWILIO_SID_CUENTA", ""),
+			TokenAutenticacion: f.texto("TWILIO_TOKEN", ""),
+			Remitente:          f.texto("TWILIO_REMITENTE", ""),
+			ServicioMensajeria: f.texto("TWILIO_SERVICIO_MENSAJERIA", ""),
+			URLAPI:             f.texto("TWILIO_URL_API", "https://api.twilio.com"),
+			URLAcuses:          f.texto("TWILIO_URL_ACUSES", ""),
+			VigenciaAcuses:     f.duracion("TWILIO_VIGENCIA_ACUSES", 72*time.Hour),
+			RemitenteVoz:       f.texto("TWILIO_REMITENTE_VOZ", ""),
+			VozTTS:             f.texto("TWILIO_VOZ_TTS", "Polly.Lupe"),
+			IdiomaVoz:          f.texto("TWILIO_IDIOMA_VOZ", "es-US"),
+			RepeticionesVoz:    f.entero("TWILIO_REPETICIONES_VOZ", 2),
+			TimeoutLlamada:     f.duracion("TWILIO_TIMEOUT_LLAMADA", 30*time.Second),
+			Idempotente:        f.booleano("TWILIO_IDEMPOTENTE", false),
+		},
+		Slack: ConfiguracionSlack{
+			Token:       f.texto("SLACK_TOKEN", ""),
+			URLAPI:      f.texto("SLACK_URL_API", "https://slack.com/api"),
+			Idempotente: f.booleano("SLACK_IDEMPOTENTE", false),
+		},
+		SendGrid: ConfiguracionSendGrid{
+			ClaveAPI:             f.texto("SENDGRID_CLAVE_API", ""),
+			Remitente:            f.texto("SENDGRID_REMITENTE", ""),
+			URLAPI:               f.texto("SENDGRID_URL_API", "https://api.sendgrid.com"),
+			ClaveWebhook:         f.texto("SENDGRID_CLAVE_WEBHOOK", ""),
+			ToleranciaFirma:      f.duracion("SENDGRID_TOLERANCIA_FIRMA", 15*time.Minute),
+			SeguimientoAperturas: f.booleano("SENDGRID_SEGUIMIENTO_APERTURAS", false),
+			Idempotente:          f.booleano("SENDGRID_IDEMPOTENTE", false),
+		},
+		SES: ConfiguracionSES{
+			Region:                f.texto("SES_REGION", ""),
+			ClaveAcceso:           f.texto("SES_CLAVE_ACCESO", ""),
+			ClaveSecreta:          f.texto("SES_CLAVE_SECRETA", ""),
+			Remitente:             f.texto("SES_REMITENTE", ""),
+			ConjuntoConfiguracion: f.texto("SES_CONJUNTO_CONFIGURACION", ""),
+			URLAPI:                f.texto("SES_URL_API", ""),
+			TemasSNS:              f.lista("SES_TEMAS_SNS"),
+			VigenciaMensajes:      f.duracion("SES_VIGENCIA_MENSAJES", 7*24*time.Hour),
+		},
+		Mailgun: ConfiguracionMailgun{
+			ClaveWebhook:    f.texto("MAILGUN_CLAVE_WEBHOOK", ""),
+			ToleranciaFirma: f.duracion("MAILGUN_TOLERANCIA_FIRMA", 15*time.Minute),
+		},
+		Telegram: ConfiguracionTelegram{
+			Token:          f.texto("TELEGRAM_TOKEN", ""),
+			Bot:            strings.TrimPrefix(f.texto("TELEGRAM_BOT", ""), "@"),
+			SecretoWebhook: f.texto("TELEGRAM_SECRETO_WEBHOOK", ""),
+			SecretoEnlace:  f.texto("TELEGRAM_SECRETO_ENLACE", ""),
+			VigenciaEnlace: f.duracion("TELEGRAM_VIGENCIA_ENLACE", 24*time.Hour),
+			URLAPI:         f.texto("TELEGRAM_URL_API", "https://api.telegram.org"),
+		},
+		SMPP: ConfiguracionSMPP{
+			Host:             f.texto("SMPP_HOST", ""),
+			Puerto:           f.entero("SMPP_PUERTO", 2775),
+			TLS:              f.booleano("SMPP_TLS", false),
+			SistemaID:        f.texto("SMPP_SISTEMA_ID", ""),
+			Clave:            f.texto("SMPP_CLAVE", ""),
+			TipoSistema:      f.texto("SMPP_TIPO_SISTEMA", ""),
+			Remitente:        f.texto("SMPP_REMITENTE", ""),
+			Ventana:          f.entero("SMPP_VENTANA", 10),
+			TimeoutRespuesta: f.duracion("SMPP_TIMEOUT_RESPUESTA", 10*time.Second),
+			IntervaloEnlace:  f.duracion("SMPP_INTERVALO_ENLACE", 30*time.Second),
+			Acuses:           f.booleano("SMPP_ACUSES", true),
+			VigenciaAcuses:   f.duracion("SMPP_VIGENCIA_ACUSES", 72*time.Hour),
+		},
+		WebPush: ConfiguracionWebPush{
+			ClavePublica: f.texto("WEB_PUSH_VAPID_CLAVE_PUBLICA", ""),
+			ClavePrivada: f.texto("WEB_PUSH_VAPID_CLAVE_PRIVADA", ""),
+			Sujeto:       f.texto("WEB_PUSH_VAPID_SUJETO", ""),
+			TTL:          f.duracion("WEB_PUSH_TTL", 24*time.Hour),
+		},
+		EnlacesCortos: ConfiguracionEnlacesCortos{
+			URLBase:      strings.TrimRight(f.texto("ENLACES_CORTOS_URL_BASE", ""), "/"),
+			Vigencia:     f.duracion("ENLACES_CORTOS_VIGENCIA", 30*24*time.Hour),
+			LongitudSlug: f.entero("ENLACES_CORTOS_LONGITUD_SLUG", 7),
+		},
+		CodigosQR: ConfiguracionCodigosQR{
+			Campo:    f.texto("QR_CAMPO_METADATOS", "codigo_qr"),
+			URLBase:  strings.TrimRight(f.texto("QR_URL_BASE", ""), "/"),
+			Secreto:  f.texto("QR_SECRETO", ""),
+			Vigencia: f.duracion("QR_VIGENCIA", 30*24*time.Hour),
+			Escala:   f.entero("QR_ESCALA", 8),
+		},
+		Adjuntos: ConfiguracionAdjuntos{
+			Endpoint:     strings.TrimRight(f.texto("ADJUNTOS_S3_ENDPOINT", ""), "/"),
+			Region:       f.texto("ADJUNTOS_S3_REGION", "us-east-1"),
+			Bucket:       f.texto("ADJUNTOS_S3_BUCKET", ""),
+			ClaveAcceso:  f.texto("ADJUNTOS_S3_CLAVE_ACCESO", ""),
+			ClaveSecreta: f.texto("ADJUNTOS_S3_CLAVE_SECRETA", ""),
+			EstiloRuta:   f.booleano("ADJUNTOS_S3_ESTILO_RUTA", true),
+			MaxBytes:     int64(f.entero("ADJUNTOS_MAX_BYTES", 10<<20)),
+			Tipos:        f.lista("ADJUNTOS_TIPOS"),
+			VigenciaURL:  f.duracion("ADJUNTOS_VIGENCIA_URL", 15*time.Minute),
+			ClamAV:       f.texto("ADJUNTOS_CLAMAV", ""),
+		},
+		AvisosLectura: ConfiguracionAvisosLectura{
+			Webhooks:     f.booleano("AVISOS_LECTURA_WEBHOOKS", false),
+			Secreto:      f.texto("AVISOS_LECTURA_SECRETO", ""),
+			RedInterna:   f.booleano("AVISOS_LECTURA_RED_INTERNA", false),
+			Intentos:     f.entero("AVISOS_LECTURA_INTENTOS", 3),
+			Espera:       f.duracion("AVISOS_LECTURA_ESPERA", 2*time.Second),
+			LongitudTema: int64(f.entero("AVISOS_LECTURA_LONGITUD_TEMA", 10000)),
+		},
+		Simulacion: ConfiguracionSimulacion{
+			Proveedores: f.lista("PROVEEDORES_SIMULADOS"),
+			Sandbox:     f.booleano("MODO_SANDBOX", false),
+		},
+		Caos: ConfiguracionCaos{
+			Habilitado:            f.booleano("CAOS_HABILITADO", false),
+			TasaLatencia:          f.decimal("CAOS_TASA_LATENCIA", 0),
+			LatenciaMaxima:        f.duracion("CAOS_LATENCIA_MAXIMA", 2*time.Second),
+			TasaDescarteWebSocket: f.decimal("CAOS_TASA_DESCARTE_WS", 0),
+			TasaErrorBaseDatos:    f.decimal("CAOS_TASA_ERROR_BD", 0),
+		},
+		Eco: ConfiguracionEco{
+			Habilitado: f.booleano("ECO_WEBHOOK_HABILITADO", false),
+			Capacidad:  f.entero("ECO_WEBHOOK_CAPACIDAD", 20),
+			Buzones:    f.entero("ECO_WEBHOOK_BUZONES", 100),
+		},
+	}
+	if config.EsProduccion() && config.Simulacion.Sandbox {
+		return nil, fmt.Errorf("MODO_SANDBOX no se admite en modo %s", ModoProduccion)
+	}
+	if config.Simulacion.Sandbox {
+		for _, tipo := range tiposSandbox {
+			if !slices.Contains(config.Simulacion.Proveedores, tipo) {
+				config.Simulacion.Proveedores = append(config.Simulacion.Proveedores, tipo)
+			}
+		}
+	}
+	if config.EsProduccion() && len(config.Simulacion.Proveedores) > 0 {
+		return nil, fmt.Errorf("PROVEEDORES_SIMULADOS no se admite en modo %s", ModoProduccion)
+	}
+	if config.EsProduccion() && config.Correo.BuzonCaptura != "" {
+		return nil, fmt.Errorf("CORREO_BUZON_CAPTURA no se admite en modo %s", ModoProduccion)
+	}
+	if config.EsProduccion() && config.Eco.Habilitado {
+		return nil, fmt.Errorf("ECO_WEBHOOK_HABILITADO no se admite en modo %s", ModoProduccion)
+	}
+	if config.Eco.Habilitado && (config.Eco.Capacidad <= 0 || config.Eco.Buzones <= 0) {
+		return nil, fmt.Errorf("ECO_WEBHOOK_CAPACIDAD y ECO_WEBHOOK_BUZONES deben ser positivos")
+	}
+	if config.LimiteAPI.Solicitudes > 0 && config.LimiteAPI.Ventana < time.Second {
+		return nil, fmt.Errorf("API_LIMITE_VENTANA debe ser de al menos un segundo")
+	}
+	if config.Envio.RetencionDifusiones <= 0 || config.Envio.MaximoDifusiones <= 0 {
+		return nil, fmt.Errorf("ENVIO_RETENCION_DIFUSIONES y ENVIO_MAXIMO_DIFUSIONES deben ser positivos")
+	}
+	if config.Trabajadores.IntervaloCarga <= 0 {
+		return nil, fmt.Errorf("TRABAJADORES_INTERVALO_CARGA debe ser positivo")
+	}
+	if err := config.Reintentos.validar(); err != nil {
+		return nil, err
+	}
+	if config.Programadas.Intervalo <= 0 || config.Programadas.PlazoReserva <= 0 || config.Programadas.TamanoLote <= 0 {
+		return nil, fmt.Errorf("PROGRAMADAS_INTERVALO, PROGRAMADAS_PLAZO_RESERVA y PROGRAMADAS_TAMANO_LOTE deben ser positivos")
+	}
+	if config.BuzonSalida.Intervalo <= 0 || config.BuzonSalida.Antiguedad <= 0 || config.BuzonSalida.TamanoLote <= 0 {
+		return nil, fmt.Errorf("BUZON_SALIDA_INTERVALO, BUZON_SALIDA_ANTIGUEDAD y BUZON_SALIDA_TAMANO_LOTE deben ser positivos")
+	}
+	if err := config.RabbitMQ.validar(); err != nil {
+		return nil, err
+	}
+	if err := config.RedisStreams.validar(); err != nil {
+		return nil, err
+	}
+	if err := config.Kafka.validar(); err != nil {
+		return nil, err
+	}
+	if config.RabbitMQ.URL != "" && config.RedisStreams.Habilitado {
+		return nil, fmt.Errorf("REDIS_STREAMS_HABILITADO no se admite con RABBITMQ_URL: elija una sola cola")
+	}
+	if config.DespachoExterno() && config.Simulacion.Sandbox {
+		// El listado del sandbox lee la memoria del servidor, no la de los trabajadores
+		return nil, fmt.Errorf("MODO_SANDBOX no se admite con RABBITMQ_URL ni REDIS_STREAMS_HABILITADO")
+	}
+	if config.NoLeidas.TTL <= 0 || config.NoLeidas.IntervaloReconciliacion <= 0 {
+		return nil, fmt.Errorf("NO_LEIDAS_TTL y NO_LEIDAS_INTERVALO_RECONCILIACION deben ser positivos")
+	}
+	if config.WebSocket.Fragmentos <= 0 || config.WebSocket.BufferEnvio <= 0 {
+		// Sin buffer el envío a una conexión ocupada no puede encolar ni descartar
+		return nil, fmt.Errorf("WS_FRAGMENTOS y WS_BUFFER_ENVIO deben ser positivos")
+	}
+	if config.VersionesAPI, err = cargarVersionesAPI(f); err != nil {
+		return nil, err
+	}
+	if config.Correo.Host != "" && config.Correo.Remitente == "" {
+		return nil, fmt.Errorf("SMTP_HOST requiere SMTP_REMITENTE")
+	}
+	if err := config.Correo.validar(); err != nil {
+		return nil, err
+	}
+	if err := config.HTTP.validar(); err != nil {
+		return nil, err
+	}
+	if err := config.SMPP.validar(); err != nil {
+		return nil, err
+	}
+	if err := config.Twilio.validar(); err != nil {
+		return nil, err
+	}
+	if err := config.SendGrid.validar(); err != nil {
+		return nil, err
+	}
+	if err := config.SES.validar(); err != nil {
+		return nil, err
+	}
+	if config.Mailgun.ToleranciaFirma <= 0 {
+		return nil, fmt.Errorf("MAILGUN_TOLERANCIA_FIRMA debe ser positiva")
+	}
+	if err := config.Telegram.validar(); err != nil {
+		return nil, err
+	}
+	if err := config.EnlacesCortos.validar(); err != nil {
+		return nil, err
+	}
+	if err := config.CodigosQR.validar(); err != nil {
+		return nil, err
+	}
+	if len(config.Adjuntos.Tipos) == 0 {
+		config.Adjuntos.Tipos = []string{"image/png", "image/jpeg", "image/gif", "image/webp", "application/pdf", "text/plain"}
+	}
+	if err := config.Adjuntos.validar(); err != nil {
+		return nil, err
+	}
+	if err := config.WebPush.validar(); err != nil {
+		return nil, err
+	}
+	if config.Reportes.Destinatarios, err = cargarDestinatariosReportes(f); err != nil {
+		return nil, err
+	}
+	if len(config.Reportes.Frecuencias) == 0 {
+		config.Reportes.Frecuencias = []string{"diario"}
+	}
+	if err := config.Reportes.validar(); err != nil {
+		return nil, err
+	}
+	if err := config.Auditoria.validar(config.Modo); err != nil {
+		return nil, err
+	}
+	if err := config.Caos.validar(config.Modo); err != nil {
+		return nil, err
+	}
+	if err := config.AvisosLectura.validar(config.Modo); err != nil {
+		return nil, err
+	}
+	if config.Liderazgo.Duracion < 3*time.Second {
+		return nil, fmt.Errorf("LIDER_DURACION debe ser de al menos 3 segundos")
+	}
+	if err := config.BaseDatos.validarMigracion(); err != nil {
+		return nil, err
+	}
+	if config.Regiones, err = cargarRegiones(f, config.BaseDatos); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// cargarRegiones lee las regiones listadas en REGIONES. Cada una toma su base de datos de
+// DB_*_<REGION> y sus proveedores de PROVEEDORES_<REGION> con el formato "proveedor=url".
+// El host es obligatorio, para que una región no termine guardando en la base principal;
+// el resto de los parámetros se hereda de la principal.
+func cargarRegiones(f *fuente, principal ConfiguracionBaseDatos) (map[string]ConfiguracionRegion, error) {
+	regiones := make(map[string]ConfiguracionRegion)
+	for _, nombre := range f.lista("REGIONES") {
+		nombre = strings.ToLower(nombre)
+		sufijo := "_" + strings.ToUpper(strings.ReplaceAll(nombre, "-", "_"))
+
+		baseDatos := principal
+		if baseDatos.Host = f.texto("DB_HOST"+sufijo, ""); baseDatos.Host == "" {
+			return nil, fmt.Errorf("la región %s requiere DB_HOST%s", nombre, sufijo)
+		}
+		baseDatos.Puerto = f.texto("DB_PORT"+sufijo, principal.Puerto)
+		baseDatos.Nombre = f.texto("DB_NAME"+sufijo, principal.Nombre)
+		baseDatos.Usuario = f.texto("DB_USER"+sufijo, principal.Usuario)
+		baseDatos.Contrasena = f.texto("DB_PASSWORD"+sufijo, principal.Contrasena)
+		baseDatos.ModoSSL = f.texto("DB_SSLMODE"+sufijo, principal.ModoSSL)
+		baseDatos.MaxConexiones = f.entero("DB_MAX_CONEXIONES"+sufijo, principal.MaxConexiones)
+
+		regiones[nombre] = ConfiguracionRegion{BaseDatos: baseDatos, Proveedores: f.textos("PROVEEDORES" + sufijo)}
+	}
+	return regiones, nil
+}
+
+// cargarDestinatariosReportes lee los IDs de usuario de REPORTES_DESTINATARIOS
+func cargarDestinatariosReportes(f *fuente) ([]uint, error) {
+	var destinatarios []uint
+	for _, valor := range f.lista("REPORTES_DESTINATARIOS") {
+		id, err := strconv.ParseUint(valor, 10, 64)
+		if err != nil || id == 0 {
+			return nil, fmt.Errorf("REPORTES_DESTINATARIOS debe contener IDs de usuario: %q", valor)
+		}
+		destinatarios = append(destinatarios, uint(id))
+	}
+	return destinatarios, nil
+}
+
+// cargarVersionesAPI lee las fechas de obsolescencia y retiro de /api/v1 (AAAA-MM-DD, UTC)
+func cargarVersionesAPI(f *fuente) (ConfiguracionVersionesAPI, error) {
+	var versiones ConfiguracionVersionesAPI
+	fechas := []struct {
+		clave   string
+		destino *time.Time
+	}{
+		{"API_V1_OBSOLETA_DESDE", &versiones.ObsoletaV1},
+		{"API_V1_RETIRO", &versiones.RetiroV1},
+	}
+	for _, fecha := range fechas {
+		valor := f.texto(fecha.clave, "")
+		if valor == "" {
+			continue
+		}
+		t, err := time.Parse("2006-01-02", valor)
+		if err != nil {
+			return versiones, fmt.Errorf("%s debe tener formato AAAA-MM-DD: %q", fecha.clave, valor)
+		}
+		*fecha.destino = t
+	}
+	if !versiones.RetiroV1.IsZero() && !versiones.RetiroV1.After(versiones.ObsoletaV1) {
+		return versiones, fmt.Errorf("API_V1_RETIRO requiere API_V1_OBSOLETA_DESDE anterior")
+	}
+	return versiones, nil
+}
+
+// validar rechaza frecuencias desconocidas y horas fuera del día
+func (c ConfiguracionReportes) validar() error {
+	for _, frecuencia := range c.Frecuencias {
+		if frecuencia != "diario" && frecuencia != "semanal" {
+			return fmt.Errorf("REPORTES_FRECUENCIAS admite diario y semanal, no %q", frecuencia)
+		}
+	}
+	if c.Hora < 0 || c.Hora > 23 {
+		return fmt.Errorf("REPORTES_HORA debe estar entre 0 y 23")
+	}
+	if c.Intervalo <= 0 {
+		return fmt.Errorf("REPORTES_INTERVALO debe ser positivo")
+	}
+	return nil
+}
+
+// Validar exige una clave HMAC de al menos 256 bits: con una vacía o corta cualquiera puede
+// firmar tokens. Solo la llama el servidor, el único proceso que valida tokens.
+func (c ConfiguracionJWT) Validar() error {
+	if len(c.Secreto) < longitudMinimaSecretoJWT {
+		return fmt.Errorf("JWT_SECRETO es requerido y debe tener al menos %d caracteres", longitudMinimaSecretoJWT)
+	}
+	return nil
+}
+
+// validar exige esperas positivas, la máxima no menor que la base y una variación menor que la espera
+func (c ConfiguracionReintentos) validar() error {
+	if c.EsperaBase <= 0 || c.Intervalo <= 0 || c.PlazoReserva <= 0 || c.TamanoLote <= 0 {
+		return fmt.Errorf("REINTENTOS_ESPERA_BASE, REINTENTOS_INTERVALO, REINTENTOS_PLAZO_RESERVA y REINTENTOS_TAMANO_LOTE deben ser positivos")
+	}
+	if c.EsperaMaxima < c.EsperaBase {
+		return fmt.Errorf("REINTENTOS_ESPERA_MAXIMA no puede ser menor que REINTENTOS_ESPERA_BASE")
+	}
+	if c.Variacion < 0 || c.Variacion >= 1 {
+		return fmt.Errorf("REINTENTOS_VARIACION debe estar entre 0 y 1 (sin incluir 1)")
+	}
+	return nil
+}
+
+// validar exige la cola, un prefetch que quepa en AMQP y entregas acotadas cuando hay broker
+func (c ConfiguracionRabbitMQ) validar() error {
+	if c.URL == "" {
+		return nil
+	}
+	if c.Cola == "" {
+		return fmt.Errorf("RABBITMQ_URL requiere RABBITMQ_COLA")
+	}
+	// El prefetch de AMQP es de 16 bits
+	if c.Prefetch <= 0 || c.Prefetch > 65535 {
+		return fmt.Errorf("RABBITMQ_PREFETCH debe estar entre 1 y 65535")
+	}
+	if c.EsperaReconexion <= 0 {
+		return fmt.Errorf("RABBITMQ_ESPERA_RECONEXION debe ser positiva")
+	}
+	if c.MaxEntregas <= 0 || c.EsperaReentrega < 0 {
+		return fmt.Errorf("RABBITMQ_MAX_ENTREGAS debe ser positivo y RABBITMQ_ESPERA_REENTREGA no negativa")
+	}
+	return nil
+}
+
+// validar exige el prefijo, el grupo y tiempos positivos cuando Redis Streams está habilitado
+func (c ConfiguracionRedisStreams) validar() error {
+	if !c.Habilitado {
+		return nil
+	}
+	if c.Prefijo == "" || c.Grupo == "" {
+		return fmt.Errorf("REDIS_STREAMS_HABILITADO requiere REDIS_STREAMS_PREFIJO y REDIS_STREAMS_GRUPO")
+	}
+	if c.Concurrencia <= 0 {
+		return fmt.Errorf("REDIS_STREAMS_CONCURRENCIA debe ser positiva")
+	}
+	if c.EsperaLectura <= 0 || c.ReclamarTras <= 0 || c.IntervaloReclamo <= 0 {
+		return fmt.Errorf("REDIS_STREAMS_ESPERA_LECTURA, REDIS_STREAMS_RECLAMAR_TRAS y REDIS_STREAMS_INTERVALO_RECLAMO deben ser positivos")
+	}
+	if c.MaxEntregas <= 0 {
+		return fmt.Errorf("REDIS_STREAMS_MAX_ENTREGAS debe ser positivo")
+	}
+	return nil
+}
+
+// validar exige los temas y, para consumir solicitudes, brokers, grupo y lectores
+func (c ConfiguracionKafka) validar() error {
+	if c.ConsumirSolicitudes && len(c.Brokers) == 0 {
+		return fmt.Errorf("KAFKA_CONSUMIR_SOLICITUDES requiere KAFKA_BROKERS")
+	}
+	if len(c.Brokers) == 0 {
+		return nil
+	}
+	if c.TemaEventos == "" {
+		return fmt.Errorf("KAFKA_BROKERS requiere KAFKA_TEMA_EVENTOS")
+	}
+	if !c.ConsumirSolicitudes {
+		return nil
+	}
+	if c.TemaSolicitudes == "" || c.Grupo == "" {
+		return fmt.Errorf("KAFKA_CONSUMIR_SOLICITUDES requiere KAFKA_TEMA_SOLICITUDES y KAFKA_GRUPO")
+	}
+	if c.Consumidores <= 0 || c.ReintentosSolicitud <= 0 {
+		return fmt.Errorf("KAFKA_CONSUMIDORES y KAFKA_REINTENTOS_SOLICITUD deben ser positivos")
+	}
+	return nil
+}
+
+// validar exige un modo TLS conocido y que la dirección de respuestas sea una dirección sin
+// "+", que el token se agrega tras él, y que estén el secreto de firma y el token del webhook de entrada
+func (c ConfiguracionCorreo) validar() error {
+	switch c.TLS {
+	case TLSAutomatico, TLSStartTLS, TLSImplicito, TLSNinguno:
+	default:
+		return fmt.Errorf("SMTP_TLS debe ser auto, starttls, implicito o ninguno: %q", c.TLS)
+	}
+	if c.DireccionRespuestas == "" {
+		return nil
+	}
+	local, dominio, ok := strings.Cut(c.DireccionRespuestas, "@")
+	if !ok || local == "" || dominio == "" || strings.Contains(local, "+") {
+		return fmt.Errorf("CORREO_RESPUESTAS_DIRECCION debe ser una dirección sin \"+\": %q", c.DireccionRespuestas)
+	}
+	if c.SecretoRespuestas == "" || c.TokenEntrante == "" {
+		return fmt.Errorf("CORREO_RESPUESTAS_DIRECCION requiere CORREO_RESPUESTAS_SECRETO y CORREO_ENTRANTE_TOKEN")
+	}
+	if c.MaxBytesEntrante <= 0 {
+		return fmt.Errorf("CORREO_ENTRANTE_MAX_BYTES debe ser positivo")
+	}
+	return nil
+}
+
+// validar rechaza proxies que no sean URL http, https o socks5 con host, y direcciones de
+// origen que no sean una IP
+func (c ConfiguracionHTTP) validar() error {
+	proxies := map[string]string{"HTTP_PROXY_SALIDA": c.Proxy}
+	for proveedor, proxy := range c.ProxiesProveedor {
+		if proxy != ProxyDirecto {
+			proxies["HTTP_PROXIES_PROVEEDORES ("+proveedor+")"] = proxy
+		}
+	}
+	for clave, proxy := range proxies {
+		if proxy == "" {
+			continue
+		}
+		u, err := url.Parse(proxy)
+		if err != nil || u.Host == "" {
+			return fmt.Errorf("%s debe ser una URL de proxy: %q", clave, proxy)
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return fmt.Errorf("%s admite proxies http, https o socks5, no %q", clave, u.Scheme)
+		}
+	}
+
+	ips := map[string]string{"HTTP_IP_ORIGEN": c.IPOrigen}
+	for proveedor, ip := range c.IPsOrigenProveedor {
+		ips["HTTP_IPS_ORIGEN_PROVEEDORES ("+proveedor+")"] = ip
+	}
+	for clave, ip := range ips {
+		if ip != "" && net.ParseIP(ip) == nil {
+			return fmt.Errorf("%s debe ser una dirección IP: %q", clave, ip)
+		}
+	}
+	return nil
+}
+
+// validar exige una URL base http o https sin consulta y un slug que no se pueda recorrer
+func (c ConfiguracionEnlacesCortos) validar() error {
+	if c.URLBase == "" {
+		return nil
+	}
+	base, err := url.Parse(c.URLBase)
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" || base.RawQuery != "" || base.Fragment != "" {
+		return fmt.Errorf("ENLACES_CORTOS_URL_BASE debe ser una URL http o https sin consulta: %q", c.URLBase)
+	}
+	if c.Vigencia <= 0 {
+		return fmt.Errorf("ENLACES_CORTOS_VIGENCIA debe ser positiva")
+	}
+	if c.LongitudSlug < 6 || c.LongitudSlug > 16 {
+		return fmt.Errorf("ENLACES_CORTOS_LONGITUD_SLUG debe estar entre 6 y 16")
+	}
+	return nil
+}
+
+// validar exige el endpoint y las claves del almacén; S3 no firma URL de más de 7 días
+func (c ConfiguracionAdjuntos) validar() error {
+	if c.Bucket == "" {
+		return nil
+	}
+	endpoint, err := url.Parse(c.Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return fmt.Errorf("ADJUNTOS_S3_ENDPOINT debe ser una URL http o https: %q", c.Endpoint)
+	}
+	if c.Region == "" || c.ClaveAcceso == "" || c.ClaveSecreta == "" {
+		return fmt.Errorf("ADJUNTOS_S3_BUCKET requiere ADJUNTOS_S3_REGION, ADJUNTOS_S3_CLAVE_ACCESO y ADJUNTOS_S3_CLAVE_SECRETA")
+	}
+	if c.MaxBytes <= 0 {
+		return fmt.Errorf("ADJUNTOS_MAX_BYTES debe ser positivo")
+	}
+	if c.VigenciaURL < time.Second || c.VigenciaURL > 7*24*time.Hour {
+		return fmt.Errorf("ADJUNTOS_VIGENCIA_URL debe estar entre 1s y 168h")
+	}
+	return nil
+}
+
+// validar exige el secreto de las URL firmadas y una escala legible sin imágenes enormes
+func (c ConfiguracionCodigosQR) validar() error {
+	if c.Campo == "" {
+		return nil
+	}
+	if c.Escala < 1 || c.Escala > 32 {
+		return fmt.Errorf("QR_ESCALA debe estar entre 1 y 32")
+	}
+	if c.URLBase == "" {
+		return nil
+	}
+	base, err := url.Parse(c.URLBase)
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		return fmt.Errorf("QR_URL_BASE debe ser una URL http o https: %q", c.URLBase)
+	}
+	if c.Secreto == "" {
+		return fmt.Errorf("QR_URL_BASE requiere QR_SECRETO")
+	}
+	if c.Vigencia <= 0 {
+		return fmt.Errorf("QR_VIGENCIA debe ser positiva")
+	}
+	return nil
+}
+
+// validar exige la identidad de la cuenta y respeta los largos de campo de SMPP 3.4
+func (c ConfiguracionSMPP) validar() error {
+	if c.Host == "" {
+		return nil
+	}
+	if c.SistemaID == "" || len(c.SistemaID) > 15 {
+		return fmt.Errorf("SMPP_SISTEMA_ID es requerido y admite hasta 15 caracteres")
+	}
+	if len(c.Clave) > 8 || len(c.TipoSistema) > 12 {
+		return fmt.Errorf("SMPP_CLAVE admite hasta 8 caracteres y SMPP_TIPO_SISTEMA hasta 12")
+	}
+	numero := strings.TrimPrefix(c.Remitente, "+")
+	if c.Remitente == "" || len(numero) > 15 || (len(c.Remitente) > 11 && strings.Trim(numero, "0123456789") != "") {
+		return fmt.Errorf("SMPP_REMITENTE debe ser un número de hasta 15 dígitos o un alfanumérico de hasta 11 caracteres")
+	}
+	if c.Ventana <= 0 || c.TimeoutRespuesta <= 0 || c.IntervaloEnlace <= 0 || c.VigenciaAcuses <= 0 {
+		return fmt.Errorf("SMPP_VENTANA, SMPP_TIMEOUT_RESPUESTA, SMPP_INTERVALO_ENLACE y SMPP_VIGENCIA_ACUSES deben ser positivos")
+	}
+	return nil
+}
+
+// validar exige el token y un remitente o servicio de mensajería, y URL absolutas para la API
+// y los acuses: la de los acuses es parte de la firma que Twilio calcula
+func (c ConfiguracionTwilio) validar() error {
+	if c.SIDCuenta == "" {
+		return nil
+	}
+	if c.TokenAutenticacion == "" || (c.Remitente == "" && c.ServicioMensajeria == "") {
+		return fmt.Errorf("TWILIO_SID_CUENTA requiere TWILIO_TOKEN y TWILIO_REMITENTE o TWILIO_SERVICIO_MENSAJERIA")
+	}
+	for nombre, valor := range map[string]string{"TWILIO_URL_API": c.URLAPI, "TWILIO_URL_ACUSES": c.URLAcuses} {
+		if valor == "" && nombre == "TWILIO_URL_ACUSES" {
+			continue
+		}
+		direccion, err := url.Parse(valor)
+		if err != nil || (direccion.Scheme != "http" && direccion.Scheme != "https") || direccion.Host == "" {
+			return fmt.Errorf("%s debe ser una URL http o https: %q", nombre, valor)
+		}
+	}
+	if c.VigenciaAcuses <= 0 {
+		return fmt.Errorf("TWILIO_VIGENCIA_ACUSES debe ser positiva")
+	}
+	// Twilio admite de 5 a 600 segundos de timbre
+	if c.RepeticionesVoz < 1 || c.TimeoutLlamada < 5*time.Second || c.TimeoutLlamada > 10*time.Minute {
+		return fmt.Errorf("TWILIO_REPETICIONES_VOZ debe ser positivo y TWILIO_TIMEOUT_LLAMADA estar entre 5s y 10m")
+	}
+	return nil
+}
+
+// validar exige el remitente con la clave y una URL absoluta para la API; que la clave del
+// webhook sea una clave pública válida lo verifica el enviador al crearse
+func (c ConfiguracionSendGrid) validar() error {
+	if c.ClaveAPI == "" {
+		return nil
+	}
+	if c.ToleranciaFirma <= 0 {
+		return fmt.Errorf("SENDGRID_TOLERANCIA_FIRMA debe ser positiva")
+	}
+	if c.Remitente == "" {
+		return fmt.Errorf("SENDGRID_CLAVE_API requiere SENDGRID_REMITENTE")
+	}
+	direccion, err := url.Parse(c.URLAPI)
+	if err != nil || (direccion.Scheme != "http" && direccion.Scheme != "https") || direccion.Host == "" {
+		return fmt.Errorf("SENDGRID_URL_API debe ser una URL http o https: %q", c.URLAPI)
+	}
+	return nil
+}
+
+// validar exige las credenciales y el remitente con la región, una URL absoluta si se indica
+// la de la API y ARN de SNS en los temas
+func (c ConfiguracionSES) validar() error {
+	if c.Region == "" {
+		return nil
+	}
+	if c.ClaveAcceso == "" || c.ClaveSecreta == "" || c.Remitente == "" {
+		return fmt.Errorf("SES_REGION requiere SES_CLAVE_ACCESO, SES_CLAVE_SECRETA y SES_REMITENTE")
+	}
+	if c.URLAPI != "" {
+		direccion, err := url.Parse(c.URLAPI)
+		if err != nil || (direccion.Scheme != "http" && direccion.Scheme != "https") || direccion.Host == "" {
+			return fmt.Errorf("SES_URL_API debe ser una URL http o https: %q", c.URLAPI)
+		}
+	}
+	for _, tema := range c.TemasSNS {
+		if !strings.HasPrefix(tema, "arn:") || !strings.Contains(tema, ":sns:") {
+			return fmt.Errorf("SES_TEMAS_SNS debe listar ARN de temas SNS: %q", tema)
+		}
+	}
+	if c.VigenciaMensajes <= 0 {
+		return fmt.Errorf("SES_VIGENCIA_MENSAJES debe ser positiva")
+	}
+	return nil
+}
+
+// validar exige, con el token, el nombre del bot y los dos secretos. El secreto del webhook
+// solo admite los caracteres que Telegram acepta en secret_token.
+func (c ConfiguracionTelegram) validar() error {
+	if c.Token == "" {
+		return nil
+	}
+	if c.Bot == "" || c.SecretoWebhook == "" || c.SecretoEnlace == "" {
+		return fmt.Errorf("TELEGRAM_TOKEN requiere TELEGRAM_BOT, TELEGRAM_SECRETO_WEBHOOK y TELEGRAM_SECRETO_ENLACE")
+	}
+	if len(c.SecretoWebhook) > 256 || strings.Trim(c.SecretoWebhook, "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789_-") != "" {
+		return fmt.Errorf("TELEGRAM_SECRETO_WEBHOOK admite hasta 256 letras, dígitos, _ y -")
+	}
+	direccion, err := url.Parse(c.URLAPI)
+	if err != nil || (direccion.Scheme != "http" && direccion.Scheme != "https") || direccion.Host == "" {
+		return fmt.Errorf("TELEGRAM_URL_API debe ser una URL http o https: %q", c.URLAPI)
+	}
+	if c.VigenciaEnlace <= 0 {
+		return fmt.Errorf("TELEGRAM_VIGENCIA_ENLACE debe ser positiva")
+	}
+	return nil
+}
+
+// validar exige el par de claves y el sujeto juntos; que las claves formen un par lo verifica
+// el enviador al crearse
+func (c ConfiguracionWebPush) validar() error {
+	if c.ClavePrivada == "" && c.ClavePublica == "" {
+		return nil
+	}
+	if c.ClavePrivada == "" || c.ClavePublica == "" {
+		return fmt.Errorf("WEB_PUSH_VAPID_CLAVE_PUBLICA y WEB_PUSH_VAPID_CLAVE_PRIVADA van juntas")
+	}
+	if !strings.HasPrefix(c.Sujeto, "mailto:") && !strings.HasPrefix(c.Sujeto, "https://") {
+		return fmt.Errorf("WEB_PUSH_VAPID_SUJETO debe ser una URL mailto: o https:")
+	}
+	if c.TTL < 0 {
+		return fmt.Errorf("WEB_PUSH_TTL no puede ser negativo")
+	}
+	return nil
+}
+
+// validar exige el secreto de firma con webhooks y rechaza la red interna en producción
+func (c ConfiguracionAvisosLectura) validar(modo string) error {
+	if c.Webhooks && c.Secreto == "" {
+		return fmt.Errorf("AVISOS_LECTURA_WEBHOOKS requiere AVISOS_LECTURA_SECRETO: los webhooks no se envían sin firma")
+	}
+	if c.RedInterna && modo == ModoProduccion {
+		return fmt.Errorf("AVISOS_LECTURA_RED_INTERNA no se admite en modo %s", ModoProduccion)
+	}
+	return nil
+}
+
+// validar exige la clave en producción, donde sin sellos quien accede a la base puede reescribir
+// la cadena entera, y la misma longitud mínima que las claves HMAC de JWT
+func (c ConfiguracionAuditoria) validar(modo string) error {
+	if c.ClaveSello == "" && modo == ModoProduccion {
+		return fmt.Errorf("AUDITORIA_CLAVE_SELLO es requerida en modo %s", ModoProduccion)
+	}
+	if c.ClaveSello != "" && len(c.ClaveSello) < longitudMinimaSecretoJWT {
+		return fmt.Errorf("AUDITORIA_CLAVE_SELLO debe tener al menos %d caracteres", longitudMinimaSecretoJWT)
+	}
+	return nil
+}
+
+// validar rechaza el caos en producción y las tasas fuera de rango
+func (c ConfiguracionCaos) validar(modo string) error {
+	if !c.Habilitado {
+		return nil
+	}
+	if modo == ModoProduccion {
+		return fmt.Errorf("CAOS_HABILITADO no se admite en modo %s", ModoProduccion)
+	}
+	tasas := map[string]float64{
+		"CAOS_TASA_LATENCIA":    c.TasaLatencia,
+		"CAOS_TASA_DESCARTE_WS": c.TasaDescarteWebSocket,
+		"CAOS_TASA_ERROR_BD":    c.TasaErrorBaseDatos,
+	}
+	for clave, tasa := range tasas {
+		if tasa < 0 || tasa > 1 {
+			return fmt.Errorf("%s debe estar entre 0 y 1", clave)
+		}
+	}
+	if c.LatenciaMaxima < 0 {
+		return fmt.Errorf("CAOS_LATENCIA_MAXIMA no puede ser negativa")
+	}
+	return nil
+}
+
+// instanciaPredeterminada identifica a la instancia por su host y su proceso
+func instanciaPredeterminada() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "desconocido"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// validarMigracion rechaza los modos de migración desconocidos
+func (c ConfiguracionBaseDatos) validarMigracion() error {
+	switch c.Migracion {
+	case MigracionAutomatica, MigracionRequerida, MigracionSoloLectura:
+		return nil
+	}
+	return fmt.Errorf("DB_MIGRACION admite %s, %s o %s, no %q", MigracionAutomatica, MigracionRequerida, MigracionSoloLectura, c.Migracion)
+}
+
+// EsProduccion indica si el servicio corre en modo producción
+func (c *Configuracion) EsProduccion() bool {
+	return c.Modo == ModoProduccion
+}
+
+// DespachoExterno indica si los envíos los entregan los procesos de cmd/trabajador, por
+// RabbitMQ o Redis Streams, en lugar del pool del servidor
+func (c *Configuracion) DespachoExterno() bool {
+	return c.RabbitMQ.URL != "" || c.RedisStreams.Habilitado
+}
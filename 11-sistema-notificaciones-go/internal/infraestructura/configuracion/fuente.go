@@ -0,0 +1,122 @@
+package configuracion
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// fuente resuelve valores de configuración: variables de entorno primero, luego el perfil
+type fuente struct {
+	perfil map[string]string
+}
+
+// texto obtiene un valor como cadena
+func (f *fuente) texto(clave, predeterminado string) string {
+	if valor, existe := os.LookupEnv(clave); existe {
+		return valor
+	}
+	if valor, existe := f.perfil[clave]; existe {
+		return valor
+	}
+	return predeterminado
+}
+
+// entero obtiene un valor como entero
+func (f *fuente) entero(clave string, predeterminado int) int {
+	valor, err := strconv.Atoi(f.texto(clave, ""))
+	if err != nil {
+		return predeterminado
+	}
+	return valor
+}
+
+// booleano obtiene un valor como booleano
+func (f *fuente) booleano(clave string, predeterminado bool) bool {
+	valor, err := strconv.ParseBool(f.texto(clave, ""))
+	if err != nil {
+		return predeterminado
+	}
+	return valor
+}
+
+// decimal obtiene un valor como número de punto flotante
+func (f *fuente) decimal(clave string, predeterminado float64) float64 {
+	valor, err := strconv.ParseFloat(f.texto(clave, ""), 64)
+	if err != nil {
+		return predeterminado
+	}
+	return valor
+}
+
+// duracion obtiene un valor como duración (formato time.ParseDuration)
+func (f *fuente) duracion(clave string, predeterminado time.Duration) time.Duration {
+	valor, err := time.ParseDuration(f.texto(clave, ""))
+	if err != nil {
+		return predeterminado
+	}
+	return valor
+}
+
+// lista obtiene un valor separado por comas
+func (f *fuente) lista(clave string) []string {
+	valor := f.texto(clave, "")
+	if valor == "" {
+		return nil
+	}
+	var elementos []string
+	for _, elemento := range strings.Split(valor, ",") {
+		if elemento = strings.TrimSpace(elemento); elemento != "" {
+			elementos = append(elementos, elemento)
+		}
+	}
+	return elementos
+}
+
+// duraciones obtiene un mapa "nombre=duracion" separado por comas; ignora entradas inválidas
+func (f *fuente) duraciones(clave string) map[string]time.Duration {
+	resultado := make(map[string]time.Duration)
+	for _, asignacion := range f.lista(clave) {
+		partes := strings.SplitN(asignacion, "=", 2)
+		if len(partes) != 2 {
+			continue
+		}
+		duracion, err := time.ParseDuration(strings.TrimSpace(partes[1]))
+		if err != nil {
+			continue
+		}
+		resultado[strings.TrimSpace(partes[0])] = duracion
+	}
+	return resultado
+}
+
+// enteros obtiene un mapa "nombre=entero" separado por comas; ignora entradas inválidas
+func (f *fuente) enteros(clave string) map[string]int {
+	resultado := make(map[string]int)
+	for _, asignacion := range f.lista(clave) {
+		partes := strings.SplitN(asignacion, "=", 2)
+		if len(partes) != 2 {
+			continue
+		}
+		valor, err := strconv.Atoi(strings.TrimSpace(partes[1]))
+		if err != nil {
+			continue
+		}
+		resultado[strings.TrimSpace(partes[0])] = valor
+	}
+	return resultado
+}
+
+// textos obtiene un mapa "nombre=valor" separado por comas; ignora entradas inválidas
+func (f *fuente) textos(clave string) map[string]string {
+	resultado := make(map[string]string)
+	for _, asignacion := range f.lista(clave) {
+		partes := strings.SplitN(asignacion, "=", 2)
+		if len(partes) != 2 || strings.TrimSpace(partes[1]) == "" {
+			continue
+		}
+		resultado[strings.TrimSpace(partes[0])] = strings.TrimSpace(partes[1])
+	}
+	return resultado
+}
@@ -0,0 +1,92 @@
+package configuracion
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// perfilesPredeterminados contiene los perfiles incluidos en el binario
+//
+//go:embed perfiles.json
+var perfilesPredeterminados []byte
+
+// Perfil representa un conjunto de valores de configuración con herencia
+type Perfil struct {
+	Hereda  string            `json:"hereda"`
+	Valores map[string]string `json:"valores"`
+}
+
+// cargarPerfiles carga los perfiles embebidos y, si se indica, los del archivo externo
+func cargarPerfiles(rutaArchivo string) (map[string]Perfil, error) {
+	perfiles := make(map[string]Perfil)
+	if err := json.Unmarshal(perfilesPredeterminados, &perfiles); err != nil {
+		return nil, fmt.Errorf("perfiles predeterminados inválidos: %w", err)
+	}
+
+	if rutaArchivo == "" {
+		return perfiles, nil
+	}
+
+	contenido, err := os.ReadFile(rutaArchivo)
+	if err != nil {
+		return nil, fmt.Errorf("leyendo archivo de perfiles %s: %w", rutaArchivo, err)
+	}
+
+	externos := make(map[string]Perfil)
+	if err := json.Unmarshal(contenido, &externos); err != nil {
+		return nil, fmt.Errorf("archivo de perfiles %s inválido: %w", rutaArchivo, err)
+	}
+
+	// Los perfiles externos sobrescriben clave a clave a los embebidos
+	for nombre, externo := range externos {
+		perfil, existe := perfiles[nombre]
+		if !existe {
+			perfiles[nombre] = externo
+			continue
+		}
+		if externo.Hereda != "" {
+			perfil.Hereda = externo.Hereda
+		}
+		if perfil.Valores == nil {
+			perfil.Valores = make(map[string]string)
+		}
+		for clave, valor := range externo.Valores {
+			perfil.Valores[clave] = valor
+		}
+		perfiles[nombre] = perfil
+	}
+
+	return perfiles, nil
+}
+
+// resolverPerfil aplana la cadena de herencia del perfil indicado
+func resolverPerfil(perfiles map[string]Perfil, nombre string) (map[string]string, error) {
+	var cadena []Perfil
+	visitados := make(map[string]bool)
+
+	for actual := nombre; actual != ""; {
+		if visitados[actual] {
+			return nil, fmt.Errorf("herencia circular en el perfil %q", actual)
+		}
+		visitados[actual] = true
+
+		perfil, existe := perfiles[actual]
+		if !existe {
+			return nil, fmt.Errorf("perfil %q no definido", actual)
+		}
+		cadena = append(cadena, perfil)
+		actual = perfil.Hereda
+	}
+
+	// Aplicar desde el ancestro más lejano hacia el perfil solicitado
+	valores := make(map[string]string)
+	for i := len(cadena) - 1; i >= 0; i-- {
+		for clave, valor := range cadena[i].Valores {
+			valores[clave] = valor
+		}
+	}
+
+	return valores, nil
+}
@@ -0,0 +1,172 @@
+package correo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// errSinAPIMailpit indica que el buzón no expone la API de Mailpit (es MailHog)
+var errSinAPIMailpit = errors.New("el buzón no expone la API de Mailpit")
+
+// CorreoCapturado es un correo retenido por el buzón de desarrollo
+type CorreoCapturado struct {
+	ID     string    `json:"id"`
+	De     string    `json:"de"`
+	Para   []string  `json:"para"`
+	Asunto string    `json:"asunto"`
+	Cuerpo string    `json:"cuerpo"`
+	Fecha  time.Time `json:"fecha"`
+	// NotificacionID es el del encabezado X-Notificacion-ID, si el correo salió de este servicio
+	NotificacionID string `json:"notificacion_id,omitempty"`
+}
+
+// BuzonCaptura lee los correos capturados por MailHog o Mailpit, para pruebas de punta a punta
+type BuzonCaptura struct {
+	base    string
+	cliente *http.Client
+}
+
+// NuevoBuzonCaptura crea el lector para la API en base (p. ej. http://localhost:8025)
+func NuevoBuzonCaptura(base string, cliente *http.Client) *BuzonCaptura {
+	return &BuzonCaptura{base: strings.TrimRight(base, "/"), cliente: cliente}
+}
+
+// Ultimos retorna los últimos correos capturados, del más reciente al más antiguo. Prueba la
+// API de Mailpit y, si el servidor no la expone, usa la de MailHog.
+func (b *BuzonCaptura) Ultimos(ctx context.Context, limite int) ([]CorreoCapturado, error) {
+	correos, err := b.ultimosMailpit(ctx, limite)
+	if errors.Is(err, errSinAPIMailpit) {
+		return b.ultimosMailHog(ctx, limite)
+	}
+	return correos, err
+}
+
+func (b *BuzonCaptura) ultimosMailpit(ctx context.Context, limite int) ([]CorreoCapturado, error) {
+	var lista struct {
+		Messages []struct {
+			ID string `json:"ID"`
+		} `json:"messages"`
+	}
+	if err := b.obtenerJSON(ctx, fmt.Sprintf("/api/v1/messages?limit=%d", limite), &lista); err != nil {
+		return nil, err
+	}
+
+	correos := make([]CorreoCapturado, 0, len(lista.Messages))
+	for _, mensaje := range lista.Messages {
+		crudo, err := b.obtener(ctx, "/api/v1/message/"+url.PathEscape(mensaje.ID)+"/raw")
+		if err != nil {
+			return nil, err
+		}
+		correo, err := interpretar(mensaje.ID, crudo)
+		if err != nil {
+			return nil, err
+		}
+		correos = append(correos, correo)
+	}
+	return correos, nil
+}
+
+func (b *BuzonCaptura) ultimosMailHog(ctx context.Context, limite int) ([]CorreoCapturado, error) {
+	var lista struct {
+		Items []struct {
+			ID  string `json:"ID"`
+			Raw struct {
+				Data string `json:"Data"`
+			} `json:"Raw"`
+		} `json:"items"`
+	}
+	if err := b.obtenerJSON(ctx, fmt.Sprintf("/api/v2/messages?limit=%d", limite), &lista); err != nil {
+		return nil, err
+	}
+
+	correos := make([]CorreoCapturado, 0, len(lista.Items))
+	for _, item := range lista.Items {
+		correo, err := interpretar(item.ID, []byte(item.Raw.Data))
+		if err != nil {
+			return nil, err
+		}
+		correos = append(correos, correo)
+	}
+	return correos, nil
+}
+
+func (b *BuzonCaptura) obtenerJSON(ctx context.Context, ruta string, destino any) error {
+	cuerpo, err := b.obtener(ctx, ruta)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(cuerpo, destino); err != nil {
+		return fmt.Errorf("respuesta inválida del buzón de captura: %w", err)
+	}
+	return nil
+}
+
+func (b *BuzonCaptura) obtener(ctx context.Context, ruta string) ([]byte, error) {
+	solicitud, err := http.NewRequestWithContext(ctx, http.MethodGet, b.base+ruta, nil)
+	if err != nil {
+		return nil, err
+	}
+	respuesta, err := b.cliente.Do(solicitud)
+	if err != nil {
+		return nil, fmt.Errorf("consultando el buzón de captura: %w", err)
+	}
+	defer respuesta.Body.Close()
+
+	if respuesta.StatusCode == http.StatusNotFound && strings.HasPrefix(ruta, "/api/v1/") {
+		return nil, errSinAPIMailpit
+	}
+	if respuesta.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("el buzón de captura respondió %d", respuesta.StatusCode)
+	}
+	return io.ReadAll(respuesta.Body)
+}
+
+// interpretar extrae los encabezados y el cuerpo en texto de un mensaje crudo
+func interpretar(id string, crudo []byte) (CorreoCapturado, error) {
+	mensaje, err := mail.ReadMessage(bytes.NewReader(crudo))
+	if err != nil {
+		return CorreoCapturado{}, fmt.Errorf("correo capturado %s ilegible: %w", id, err)
+	}
+
+	var decodificador mime.WordDecoder
+	asunto, err := decodificador.DecodeHeader(mensaje.Header.Get("Subject"))
+	if err != nil {
+		asunto = mensaje.Header.Get("Subject")
+	}
+	correo := CorreoCapturado{
+		ID:             id,
+		De:             mensaje.Header.Get("From"),
+		Asunto:         asunto,
+		NotificacionID: mensaje.Header.Get(EncabezadoNotificacion),
+	}
+	if destinatarios, err := mensaje.Header.AddressList("To"); err == nil {
+		for _, destinatario := range destinatarios {
+			correo.Para = append(correo.Para, destinatario.Address)
+		}
+	}
+	if fecha, err := mensaje.Header.Date(); err == nil {
+		correo.Fecha = fecha
+	}
+
+	cuerpo := mensaje.Body
+	if strings.EqualFold(mensaje.Header.Get("Content-Transfer-Encoding"), "quoted-printable") {
+		cuerpo = quotedprintable.NewReader(cuerpo)
+	}
+	texto, err := io.ReadAll(cuerpo)
+	if err != nil {
+		return CorreoCapturado{}, fmt.Errorf("correo capturado %s ilegible: %w", id, err)
+	}
+	correo.Cuerpo = string(texto)
+	return correo, nil
+}
@@ -0,0 +1,238 @@
+package correo
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// encabezadosDestinatario son los encabezados donde puede llegar la dirección con el token. Los de
+// entrega cubren las copias ocultas y los reenvíos, donde To no es el buzón de respuestas.
+var encabezadosDestinatario = []string{"To", "Cc", "Delivered-To", "X-Original-To", "Envelope-To"}
+
+var (
+	// inicioCita es la línea con que los clientes presentan el mensaje citado, p. ej.
+	// "On Mon, 3 Jun 2024, Ana <ana@ejemplo.com> wrote:" o "El lun, 3 jun 2024, Ana escribió:"
+	inicioCita = regexp.MustCompile(`(?i)^(on|el|em|le|am)\s.*(wrote|escribió|escreveu|a écrit|schrieb)\s*:$`)
+	// separadorOriginal es el separador de Outlook y otros clientes antes del mensaje original
+	separadorOriginal = regexp.MustCompile(`(?i)^-{2,}\s*(original message|mensaje original|mensagem original)\s*-{2,}$`)
+	// encabezadoCitado es el bloque "From: …" / "De: …" que Outlook agrega sobre el mensaje original
+	encabezadoCitado = regexp.MustCompile(`(?i)^(from|de):\s.+`)
+	etiquetaHTML     = regexp.MustCompile(`(?s)<[^>]*>`)
+	bloqueHTML       = regexp.MustCompile(`(?is)<(style|script)[^>]*>.*?</(style|script)>|<br\s*/?>|</p>|</div>`)
+)
+
+// LeerCorreoEntrante interpreta un correo RFC 5322 recibido en el buzón de respuestas y extrae lo
+// que escribió el remitente. Usa la parte text/plain; sin ella, el HTML sin etiquetas.
+func LeerCorreoEntrante(r io.Reader) (*entidad.CorreoEntrante, error) {
+	mensaje, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, entidad.NewErrorValidacion("el correo recibido no es un mensaje RFC 5322 válido")
+	}
+	decodificador := new(mime.WordDecoder)
+
+	correo := &entidad.CorreoEntrante{
+		MessageID:  strings.Trim(strings.TrimSpace(mensaje.Header.Get("Message-ID")), "<>"),
+		Automatico: esAutomatico(mensaje.Header),
+	}
+	if remitente, err := mail.ParseAddress(mensaje.Header.Get("From")); err == nil {
+		correo.Remitente = remitente.Address
+	}
+	if correo.Remitente == "" {
+		return nil, entidad.NewErrorValidacion("el correo recibido no tiene remitente")
+	}
+	if asunto, err := decodificador.DecodeHeader(mensaje.Header.Get("Subject")); err == nil {
+		correo.Asunto = asunto
+	}
+	for _, encabezado := range encabezadosDestinatario {
+		for _, valor := range mensaje.Header[encabezado] {
+			correo.Destinatarios = append(correo.Destinatarios, direcciones(valor)...)
+		}
+	}
+
+	texto, esHTML, err := cuerpoTexto(mensaje.Header, mensaje.Body)
+	if err != nil {
+		return nil, entidad.NewErrorValidacion("no se pudo leer el cuerpo del correo recibido")
+	}
+	if esHTML {
+		texto = textoDeHTML(texto)
+	}
+	correo.Texto = QuitarCita(texto)
+	return correo, nil
+}
+
+// QuitarCita conserva solo lo que escribió quien responde: corta en la presentación del mensaje
+// citado o en la firma y descarta las líneas citadas con ">"
+func QuitarCita(texto string) string {
+	lineas := strings.Split(strings.ReplaceAll(texto, "\r\n", "\n"), "\n")
+	var conservadas []string
+	for i := 0; i < len(lineas); i++ {
+		linea := strings.TrimRight(lineas[i], " \t")
+		recortada := strings.TrimSpace(linea)
+		siguiente := ""
+		if i+1 < len(lineas) {
+			siguiente = strings.TrimSpace(lineas[i+1])
+		}
+		// Gmail parte en dos líneas la presentación de la cita cuando es larga
+		if inicioCita.MatchString(recortada) || inicioCita.MatchString(recortada+" "+siguiente) ||
+			separadorOriginal.MatchString(recortada) || lineas[i] == "-- " || recortada == "--" {
+			break
+		}
+		if encabezadoCitado.MatchString(recortada) && strings.Contains(siguiente, ":") && len(conservadas) > 0 {
+			break
+		}
+		if strings.HasPrefix(recortada, ">") {
+			continue
+		}
+		conservadas = append(conservadas, linea)
+	}
+	return strings.TrimSpace(strings.Join(conservadas, "\n"))
+}
+
+// encabezados es la vista mínima de los encabezados MIME de una parte o del mensaje
+type encabezados interface {
+	Get(clave string) string
+}
+
+// cuerpoTexto recorre las partes del mensaje y retorna la primera text/plain decodificada; si
+// no hay ninguna, la primera text/html
+func cuerpoTexto(h encabezados, cuerpo io.Reader) (string, bool, error) {
+	tipo, parametros, err := mime.ParseMediaType(h.Get("Content-Type"))
+	if err != nil {
+		tipo = "text/plain"
+	}
+	if strings.HasPrefix(tipo, "multipart/") {
+		partes := multipart.NewReader(cuerpo, parametros["boundary"])
+		var alternativa string
+		for {
+			parte, err := partes.NextRawPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return "", false, err
+			}
+			if strings.HasPrefix(strings.ToLower(parte.Header.Get("Content-Disposition")), "attachment") {
+				continue
+			}
+			texto, esHTML, err := cuerpoTexto(parte.Header, parte)
+			if err != nil {
+				return "", false, err
+			}
+			if !esHTML && texto != "" {
+				return texto, false, nil
+			}
+			if esHTML && alternativa == "" {
+				alternativa = texto
+			}
+		}
+		return alternativa, alternativa != "", nil
+	}
+	if tipo != "text/plain" && tipo != "text/html" {
+		return "", false, nil
+	}
+
+	datos, err := io.ReadAll(decodificarTransferencia(h.Get("Content-Transfer-Encoding"), cuerpo))
+	if err != nil {
+		return "", false, err
+	}
+	return aUTF8(parametros["charset"], datos), tipo == "text/html", nil
+}
+
+func decodificarTransferencia(codificacion string, cuerpo io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(codificacion)) {
+	case "quoted-printable":
+		return quotedprintable.NewReader(cuerpo)
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, sinSaltos{bufio.NewReader(cuerpo)})
+	default:
+		return cuerpo
+	}
+}
+
+// sinSaltos descarta los saltos de línea que parten el base64 en renglones de 76 caracteres
+type sinSaltos struct {
+	r io.ByteReader
+}
+
+func (s sinSaltos) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		b, err := s.r.ReadByte()
+		if err != nil {
+			return n, err
+		}
+		if b != '\r' && b != '\n' && b != ' ' && b != '\t' {
+			p[n] = b
+			n++
+		}
+	}
+	return n, nil
+}
+
+// aUTF8 convierte los juegos de caracteres de un byte más comunes en los clientes de correo; los
+// demás se asumen UTF-8
+func aUTF8(charset string, datos []byte) string {
+	switch strings.ToLower(charset) {
+	case "iso-8859-1", "latin1", "windows-1252", "cp1252":
+		runas := make([]rune, len(datos))
+		for i, b := range datos {
+			runas[i] = rune(b)
+		}
+		return string(runas)
+	default:
+		return string(bytes.ToValidUTF8(datos, []byte("�")))
+	}
+}
+
+// textoDeHTML deja el texto visible de un cuerpo HTML, con un salto por párrafo o <br>
+func textoDeHTML(contenido string) string {
+	contenido = bloqueHTML.ReplaceAllStringFunc(contenido, func(bloque string) string {
+		if strings.HasPrefix(bloque, "<s") || strings.HasPrefix(bloque, "<S") {
+			return ""
+		}
+		return "\n"
+	})
+	return html.UnescapeString(etiquetaHTML.ReplaceAllString(contenido, ""))
+}
+
+// direcciones extrae las direcciones de un encabezado; una lista mal formada se lee dirección
+// por dirección para no perder las válidas
+func direcciones(valor string) []string {
+	var resultado []string
+	lista, err := mail.ParseAddressList(valor)
+	if err == nil {
+		for _, direccion := range lista {
+			resultado = append(resultado, direccion.Address)
+		}
+		return resultado
+	}
+	for _, parte := range strings.Split(valor, ",") {
+		if direccion, err := mail.ParseAddress(strings.TrimSpace(parte)); err == nil {
+			resultado = append(resultado, direccion.Address)
+		} else if strings.Count(parte, "@") == 1 {
+			resultado = append(resultado, strings.Trim(strings.TrimSpace(parte), "<>"))
+		}
+	}
+	return resultado
+}
+
+// esAutomatico reconoce las respuestas automáticas (ausencia, rebotes), que no son una lectura
+func esAutomatico(h mail.Header) bool {
+	if automatico := strings.ToLower(h.Get("Auto-Submitted")); automatico != "" && automatico != "no" {
+		return true
+	}
+	precedencia := strings.ToLower(h.Get("Precedence"))
+	return h.Get("X-Autoreply") != "" || h.Get("X-Autorespond") != "" ||
+		precedencia == "bulk" || precedencia == "auto_reply" || precedencia == "junk"
+}
@@ -0,0 +1,314 @@
+package correo
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/internal/infraestructura/clienteHTTP"
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+const (
+	// ProveedorSendGrid identifica las credenciales propias de un inquilino y el cliente HTTP
+	// saliente de este enviador
+	ProveedorSendGrid = "sendgrid"
+
+	// MetadatoCategorias es el metadato, texto o lista de textos, con las categorías de SendGrid
+	MetadatoCategorias = "categorias"
+
+	// Argumentos propios con que SendGrid devuelve la notificación en cada evento
+	argumentoNotificacion = "notificacion_id"
+	argumentoInquilino    = "inquilino_id"
+
+	// Límites de SendGrid: 10 categorías de hasta 255 caracteres y 10.000 bytes de custom_args
+	maximoCategorias       = 10
+	largoMaximoCategoria   = 255
+	maximoBytesArgumentos  = 10000
+	largoMaximoCuerpoError = 4096
+)
+
+// ReceptorEventosCorreo aplica los eventos que informa el proveedor; lo implementa
+// casoUso.CasoUsoEventosCorreo
+type ReceptorEventosCorreo interface {
+	Aplicar(ctx context.Context, evento entidad.EventoCorreo) error
+}
+
+// ResolvedorCredenciales retorna las credenciales propias del inquilino, o nil si usa las de
+// la plataforma; lo implementa casoUso.CasoUsoCredencialesProveedor
+type ResolvedorCredenciales interface {
+	Resolver(ctx context.Context, inquilinoID uint, proveedor string) (map[string]string, error)
+}
+
+// EnviadorSendGrid entrega notificaciones TipoEmail por la API v3 de SendGrid y aplica los
+// eventos que SendGrid informa por su Event Webhook
+type EnviadorSendGrid struct {
+	config             configuracion.ConfiguracionSendGrid
+	correo             configuracion.ConfiguracionCorreo
+	claveWebhook       *ecdsa.PublicKey
+	repositorioUsuario repositorio.RepositorioUsuario
+	codigosQR          GeneradorQR
+	adjuntos           LectorAdjuntos
+	receptor           ReceptorEventosCorreo
+	credenciales       ResolvedorCredenciales
+	cliente            *http.Client
+	reloj              reloj.Reloj
+	logger             *logger.Logger
+}
+
+// NuevoEnviadorSendGrid crea una nueva instancia de EnviadorSendGrid. correo aporta la dirección
+// de respuestas; falla si la clave del webhook no es una clave pública ECDSA.
+func NuevoEnviadorSendGrid(
+	config configuracion.ConfiguracionSendGrid,
+	correo configuracion.ConfiguracionCorreo,
+	repositorioUsuario repositorio.RepositorioUsuario,
+	codigosQR GeneradorQR,
+	adjuntos LectorAdjuntos,
+	receptor ReceptorEventosCorreo,
+	credenciales ResolvedorCredenciales,
+	cliente *http.Client,
+	rel reloj.Reloj,
+	log *logger.Logger,
+) (*EnviadorSendGrid, error) {
+	e := &EnviadorSendGrid{
+		config:             config,
+		correo:             correo,
+		repositorioUsuario: repositorioUsuario,
+		codigosQR:          codigosQR,
+		adjuntos:           adjuntos,
+		receptor:           receptor,
+		credenciales:       credenciales,
+		cliente:            cliente,
+		reloj:              rel,
+		logger:             log.Componente(logger.ComponenteProveedores),
+	}
+	if config.ClaveWebhook != "" {
+		clave, err := ClavePublicaWebhook(config.ClaveWebhook)
+		if err != nil {
+			return nil, fmt.Errorf("SENDGRID_CLAVE_WEBHOOK: %w", err)
+		}
+		e.claveWebhook = clave
+	}
+	return e, nil
+}
+
+// Tipo implementa servicio.ProveedorNotificacion
+func (e *EnviadorSendGrid) Tipo() entidad.TipoNotificacion {
+	return entidad.TipoEmail
+}
+
+// Proveedor implementa servicio.EnviadorConCredenciales. Las credenciales del inquilino
+// admiten las claves clave_api, remitente y clave_webhook.
+func (e *EnviadorSendGrid) Proveedor() string {
+	return ProveedorSendGrid
+}
+
+// SoportaIdempotencia implementa servicio.EnviadorIdempotente según SENDGRID_IDEMPOTENTE
+func (e *EnviadorSendGrid) SoportaIdempotencia() bool {
+	return e.config.Idempotente
+}
+
+// Enviar envía el correo con su código QR y sus adjuntos. Las categorías y los custom_args
+// salen de los metadatos; los custom_args llevan además la notificación y su inquilino, que
+// SendGrid devuelve en cada evento.
+func (e *EnviadorSendGrid) Enviar(ctx context.Context, notificacion *entidad.Notificacion) error {
+	usuario, err := e.repositorioUsuario.ObtenerPorID(ctx, notificacion.UsuarioID)
+	if err != nil {
+		return err
+	}
+	if usuario.CorreoElectronico == "" {
+		return entidad.NewErrorValidacion("el usuario no tiene correo electrónico")
+	}
+	claveAPI, remitente := e.config.ClaveAPI, e.config.Remitente
+	if credenciales, ok := servicio.CredencialesDesdeContexto(ctx); ok {
+		if credenciales["clave_api"] != "" {
+			claveAPI = credenciales["clave_api"]
+		}
+		if credenciales["remitente"] != "" {
+			remitente = credenciales["remitente"]
+		}
+	}
+	if claveAPI == "" || remitente == "" {
+		return fmt.Errorf("cuenta de SendGrid no configurada")
+	}
+	partes, err := partesAdjuntas(ctx, e.codigosQR, e.adjuntos, notificacion)
+	if err != nil {
+		return err
+	}
+
+	cuerpo, err := json.Marshal(e.mensaje(remitente, usuario.CorreoElectronico, notificacion, partes))
+	if err != nil {
+		return err
+	}
+	base, err := e.urlAPI(ctx)
+	if err != nil {
+		return err
+	}
+	solicitud, err := http.NewRequestWithContext(ctx, http.MethodPost, base+"/v3/mail/send", bytes.NewReader(cuerpo))
+	if err != nil {
+		return err
+	}
+	solicitud.Header.Set("Authorization", "Bearer "+claveAPI)
+	solicitud.Header.Set("Content-Type", "application/json")
+	clienteHTTP.AgregarIdempotencia(solicitud)
+
+	respuesta, err := e.cliente.Do(solicitud)
+	if err != nil {
+		return err
+	}
+	defer respuesta.Body.Close()
+	detalle, _ := io.ReadAll(io.LimitReader(respuesta.Body, largoMaximoCuerpoError))
+	switch {
+	case respuesta.StatusCode >= 200 && respuesta.StatusCode < 300:
+		return nil
+	case respuesta.StatusCode == http.StatusBadRequest, respuesta.StatusCode == http.StatusRequestEntityTooLarge:
+		// Destinatario o remitente inválido, o un mensaje demasiado grande: reintentar no sirve
+		return entidad.NewErrorValidacion(fmt.Sprintf("SendGrid rechazó el correo (%d): %s", respuesta.StatusCode, bytes.TrimSpace(detalle)))
+	default:
+		return fmt.Errorf("SendGrid respondió %d: %s", respuesta.StatusCode, bytes.TrimSpace(detalle))
+	}
+}
+
+// mensaje arma el cuerpo de /v3/mail/send. Junto al texto va una versión HTML del mismo
+// mensaje: SendGrid solo puede medir aperturas en correos HTML.
+func (e *EnviadorSendGrid) mensaje(remitente, destinatario string, notificacion *entidad.Notificacion, partes []parteAdjunta) map[string]interface{} {
+	personalizacion := map[string]interface{}{
+		"to":          []map[string]string{{"email": destinatario}},
+		"custom_args": argumentosPropios(notificacion),
+	}
+	mensaje := map[string]interface{}{
+		"personalizations": []map[string]interface{}{personalizacion},
+		"from":             map[string]string{"email": remitente},
+		"subject":          notificacion.Titulo,
+		"content": []map[string]string{
+			{"type": "text/plain", "value": notificacion.Mensaje},
+			{"type": "text/html", "value": "<p>" + strings.ReplaceAll(html.EscapeString(notificacion.Mensaje), "\n", "<br>") + "</p>"},
+		},
+		"headers": map[string]string{EncabezadoNotificacion: strconv.FormatUint(uint64(notificacion.ID), 10)},
+	}
+	if direccion := responderA(e.correo, notificacion); direccion != "" {
+		mensaje["reply_to"] = map[string]string{"email": direccion}
+	}
+	if categorias := categorias(notificacion); len(categorias) > 0 {
+		mensaje["categories"] = categorias
+	}
+	if e.config.SeguimientoAperturas {
+		mensaje["tracking_settings"] = map[string]interface{}{"open_tracking": map[string]bool{"enable": true}}
+	}
+
+	var adjuntos []map[string]string
+	for i, parte := range partes {
+		adjunto := map[string]string{
+			"content":     base64.StdEncoding.EncodeToString(parte.contenido),
+			"type":        parte.tipo,
+			"filename":    parte.nombre,
+			"disposition": "attachment",
+		}
+		if parte.enLinea {
+			adjunto["disposition"] = "inline"
+			adjunto["content_id"] = fmt.Sprintf("parte-%d.%d", notificacion.ID, i)
+		}
+		adjuntos = append(adjuntos, adjunto)
+	}
+	if len(adjuntos) > 0 {
+		mensaje["attachments"] = adjuntos
+	}
+	return mensaje
+}
+
+// urlAPI es la URL de la API, o el endpoint regional (p. ej. https://api.eu.sendgrid.com) si
+// está en el contexto
+func (e *EnviadorSendGrid) urlAPI(ctx context.Context) (string, error) {
+	endpoint, ok := servicio.EndpointProveedorDesdeContexto(ctx)
+	if !ok {
+		return strings.TrimRight(e.config.URLAPI, "/"), nil
+	}
+	direccion, err := url.Parse(endpoint)
+	if err != nil || direccion.Host == "" {
+		return "", fmt.Errorf("endpoint SendGrid regional inválido: %q", endpoint)
+	}
+	return strings.TrimRight(endpoint, "/"), nil
+}
+
+// categorias lee el metadato de categorías, como texto o lista de textos, dentro de los
+// límites de SendGrid
+func categorias(notificacion *entidad.Notificacion) []string {
+	valor, existe := notificacion.Metadatos[MetadatoCategorias]
+	if !existe {
+		return nil
+	}
+	var candidatas []interface{}
+	switch v := valor.(type) {
+	case string:
+		candidatas = []interface{}{v}
+	case []interface{}:
+		candidatas = v
+	}
+	var resultado []string
+	for _, candidata := range candidatas {
+		texto, ok := candidata.(string)
+		if !ok || texto == "" || len(texto) > largoMaximoCategoria {
+			continue
+		}
+		resultado = append(resultado, texto)
+		if len(resultado) == maximoCategorias {
+			break
+		}
+	}
+	return resultado
+}
+
+// argumentosPropios convierte los metadatos de texto, número o booleano en custom_args, en
+// orden de clave hasta agotar el límite de SendGrid. La notificación y el inquilino van primero
+// y no pueden reemplazarse.
+func argumentosPropios(notificacion *entidad.Notificacion) map[string]string {
+	argumentos := map[string]string{
+		argumentoNotificacion: strconv.FormatUint(uint64(notificacion.ID), 10),
+		argumentoInquilino:    strconv.FormatUint(uint64(notificacion.InquilinoID), 10),
+	}
+	total := 0
+	for clave, valor := range argumentos {
+		total += len(clave) + len(valor)
+	}
+
+	claves := make([]string, 0, len(notificacion.Metadatos))
+	for clave := range notificacion.Metadatos {
+		claves = append(claves, clave)
+	}
+	sort.Strings(claves)
+	for _, clave := range claves {
+		if _, reservado := argumentos[clave]; reservado || clave == MetadatoCategorias {
+			continue
+		}
+		var texto string
+		switch v := notificacion.Metadatos[clave].(type) {
+		case string:
+			texto = v
+		case float64, int, int64, uint, bool:
+			texto = fmt.Sprint(v)
+		default:
+			continue
+		}
+		if total+len(clave)+len(texto) > maximoBytesArgumentos {
+			break
+		}
+		total += len(clave) + len(texto)
+		argumentos[clave] = texto
+	}
+	return argumentos
+}
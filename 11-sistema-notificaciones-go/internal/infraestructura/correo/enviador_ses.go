@@ -0,0 +1,273 @@
+package correo
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+const (
+	// ProveedorSES identifica las credenciales propias de un inquilino, el cliente HTTP saliente
+	// de este enviador y sus mensajes en el registro de mensajes
+	ProveedorSES = "ses"
+
+	rutaEnvioSES     = "/v2/email/outbound-emails"
+	algoritmoFirmaV4 = "AWS4-HMAC-SHA256"
+	formatoFechaV4   = "20060102T150405Z"
+)
+
+// EnviadorSES entrega notificaciones TipoEmail por la API v2 de Amazon SES y aplica los rebotes
+// y quejas que SES publica en un tema SNS
+type EnviadorSES struct {
+	config             configuracion.ConfiguracionSES
+	correo             configuracion.ConfiguracionCorreo
+	repositorioUsuario repositorio.RepositorioUsuario
+	codigosQR          GeneradorQR
+	adjuntos           LectorAdjuntos
+	registro           repositorio.RegistroMensajesProveedor
+	receptor           ReceptorEventosCorreo
+	cliente            *http.Client
+	reloj              reloj.Reloj
+	logger             *logger.Logger
+
+	// certificados guarda los certificados de firma de SNS ya descargados, por URL
+	mu           sync.Mutex
+	certificados map[string]*x509.Certificate
+}
+
+// NuevoEnviadorSES crea una nueva instancia de EnviadorSES; correo aporta la dirección de
+// respuestas
+func NuevoEnviadorSES(
+	config configuracion.ConfiguracionSES,
+	correo configuracion.ConfiguracionCorreo,
+	repositorioUsuario repositorio.RepositorioUsuario,
+	codigosQR GeneradorQR,
+	adjuntos LectorAdjuntos,
+	registro repositorio.RegistroMensajesProveedor,
+	receptor ReceptorEventosCorreo,
+	cliente *http.Client,
+	rel reloj.Reloj,
+	log *logger.Logger,
+) *EnviadorSES {
+	return &EnviadorSES{
+		config:             config,
+		correo:             correo,
+		repositorioUsuario: repositorioUsuario,
+		codigosQR:          codigosQR,
+		adjuntos:           adjuntos,
+		registro:           registro,
+		receptor:           receptor,
+		cliente:            cliente,
+		reloj:              rel,
+		logger:             log.Componente(logger.ComponenteProveedores),
+		certificados:       make(map[string]*x509.Certificate),
+	}
+}
+
+// Tipo implementa servicio.ProveedorNotificacion
+func (e *EnviadorSES) Tipo() entidad.TipoNotificacion {
+	return entidad.TipoEmail
+}
+
+// Proveedor implementa servicio.EnviadorConCredenciales. Las credenciales del inquilino
+// admiten las claves region, clave_acceso, clave_secreta, remitente y conjunto_configuracion.
+func (e *EnviadorSES) Proveedor() string {
+	return ProveedorSES
+}
+
+// cuentaSES son los datos de la cuenta con que sale un correo
+type cuentaSES struct {
+	region                string
+	claveAcceso           string
+	claveSecreta          string
+	remitente             string
+	conjuntoConfiguracion string
+}
+
+// Enviar compone el correo MIME, con el código QR y los adjuntos, y lo envía como mensaje crudo.
+// SES devuelve un ID de mensaje que se registra para reconocer sus rebotes y quejas; el correo
+// lleva además la notificación y su inquilino como etiquetas.
+func (e *EnviadorSES) Enviar(ctx context.Context, notificacion *entidad.Notificacion) error {
+	usuario, err := e.repositorioUsuario.ObtenerPorID(ctx, notificacion.UsuarioID)
+	if err != nil {
+		return err
+	}
+	if usuario.CorreoElectronico == "" {
+		return entidad.NewErrorValidacion("el usuario no tiene correo electrónico")
+	}
+	cuenta, err := e.cuenta(ctx)
+	if err != nil {
+		return err
+	}
+	partes, err := partesAdjuntas(ctx, e.codigosQR, e.adjuntos, notificacion)
+	if err != nil {
+		return err
+	}
+	ahora := e.reloj.Ahora()
+	mensaje, err := componer(cuenta.remitente, usuario.CorreoElectronico, responderA(e.correo, notificacion), notificacion, partes, ahora)
+	if err != nil {
+		return err
+	}
+
+	envio := map[string]interface{}{
+		"FromEmailAddress": cuenta.remitente,
+		"Destination":      map[string][]string{"ToAddresses": {usuario.CorreoElectronico}},
+		"Content":          map[string]interface{}{"Raw": map[string]string{"Data": base64.StdEncoding.EncodeToString(mensaje)}},
+		"EmailTags": []map[string]string{
+			{"Name": argumentoNotificacion, "Value": strconv.FormatUint(uint64(notificacion.ID), 10)},
+			{"Name": argumentoInquilino, "Value": strconv.FormatUint(uint64(notificacion.InquilinoID), 10)},
+		},
+	}
+	if cuenta.conjuntoConfiguracion != "" {
+		envio["ConfigurationSetName"] = cuenta.conjuntoConfiguracion
+	}
+	cuerpo, err := json.Marshal(envio)
+	if err != nil {
+		return err
+	}
+	base, err := e.urlAPI(ctx, cuenta.region)
+	if err != nil {
+		return err
+	}
+	solicitud, err := http.NewRequestWithContext(ctx, http.MethodPost, base+rutaEnvioSES, bytes.NewReader(cuerpo))
+	if err != nil {
+		return err
+	}
+	solicitud.Header.Set("Content-Type", "application/json")
+	firmarV4(solicitud, cuerpo, cuenta, ahora)
+
+	respuesta, err := e.cliente.Do(solicitud)
+	if err != nil {
+		return err
+	}
+	defer respuesta.Body.Close()
+	detalle, _ := io.ReadAll(io.LimitReader(respuesta.Body, largoMaximoCuerpoError))
+	switch {
+	case respuesta.StatusCode >= 200 && respuesta.StatusCode < 300:
+	case respuesta.StatusCode == http.StatusBadRequest:
+		// Mensaje rechazado, remitente sin verificar o destinatario inválido: reintentar no sirve
+		return entidad.NewErrorValidacion(fmt.Sprintf("SES rechazó el correo: %s", bytes.TrimSpace(detalle)))
+	default:
+		return fmt.Errorf("SES respondió %d: %s", respuesta.StatusCode, bytes.TrimSpace(detalle))
+	}
+
+	var aceptado struct {
+		IDMensaje string `json:"MessageId"`
+	}
+	if err := json.Unmarshal(detalle, &aceptado); err != nil || aceptado.IDMensaje == "" {
+		e.logger.Warn("SES aceptó el correo sin ID de mensaje", "notificacion_id", notificacion.ID)
+		return nil
+	}
+	referencia := entidad.ReferenciaMensaje{InquilinoID: notificacion.InquilinoID, NotificacionID: notificacion.ID, Partes: 1}
+	if err := e.registro.Registrar(ctx, ProveedorSES, []string{aceptado.IDMensaje}, referencia, e.config.VigenciaMensajes); err != nil {
+		// SES ya aceptó el correo: sin el registro solo se pierden sus rebotes y quejas
+		e.logger.Warn("No se pudo registrar el correo para sus rebotes", "notificacion_id", notificacion.ID, "error", err)
+	}
+	return nil
+}
+
+// cuenta resuelve la cuenta del envío: las credenciales del inquilino reemplazan a las de la
+// plataforma campo a campo
+func (e *EnviadorSES) cuenta(ctx context.Context) (cuentaSES, error) {
+	c := cuentaSES{
+		region:                e.config.Region,
+		claveAcceso:           e.config.ClaveAcceso,
+		claveSecreta:          e.config.ClaveSecreta,
+		remitente:             e.config.Remitente,
+		conjuntoConfiguracion: e.config.ConjuntoConfiguracion,
+	}
+	if credenciales, ok := servicio.CredencialesDesdeContexto(ctx); ok {
+		for clave, destino := range map[string]*string{
+			"region": &c.region, "clave_acceso": &c.claveAcceso, "clave_secreta": &c.claveSecreta,
+			"remitente": &c.remitente, "conjunto_configuracion": &c.conjuntoConfiguracion,
+		} {
+			if valor := credenciales[clave]; valor != "" {
+				*destino = valor
+			}
+		}
+	}
+
+	if c.region == "" || c.claveAcceso == "" || c.claveSecreta == "" || c.remitente == "" {
+		return c, fmt.Errorf("cuenta de SES no configurada")
+	}
+	return c, nil
+}
+
+// urlAPI es el endpoint regional del contexto, la URL configurada o el endpoint público de la
+// región de la cuenta
+func (e *EnviadorSES) urlAPI(ctx context.Context, region string) (string, error) {
+	endpoint, ok := servicio.EndpointProveedorDesdeContexto(ctx)
+	if !ok {
+		if e.config.URLAPI != "" && region == e.config.Region {
+			return strings.TrimRight(e.config.URLAPI, "/"), nil
+		}
+		return "https://email." + region + ".amazonaws.com", nil
+	}
+	direccion, err := url.Parse(endpoint)
+	if err != nil || direccion.Host == "" {
+		return "", fmt.Errorf("endpoint SES regional inválido: %q", endpoint)
+	}
+	return strings.TrimRight(endpoint, "/"), nil
+}
+
+// firmarV4 firma la solicitud con AWS Signature V4 para el servicio ses, como
+// almacenamiento.AlmacenS3 firma las de S3. La ruta de envío no necesita codificarse y la
+// solicitud no lleva consulta.
+func firmarV4(solicitud *http.Request, cuerpo []byte, cuenta cuentaSES, ahora time.Time) {
+	ahora = ahora.UTC()
+	resumenCuerpo := sha256.Sum256(cuerpo)
+	hashContenido := hex.EncodeToString(resumenCuerpo[:])
+	solicitud.Header.Set("X-Amz-Date", ahora.Format(formatoFechaV4))
+	solicitud.Header.Set("X-Amz-Content-Sha256", hashContenido)
+
+	firmados := "content-type;host;x-amz-content-sha256;x-amz-date"
+	canonica := strings.Join([]string{
+		solicitud.Method,
+		solicitud.URL.EscapedPath(),
+		"",
+		"content-type:" + solicitud.Header.Get("Content-Type"),
+		"host:" + solicitud.URL.Host,
+		"x-amz-content-sha256:" + hashContenido,
+		"x-amz-date:" + ahora.Format(formatoFechaV4),
+		"",
+		firmados,
+		hashContenido,
+	}, "\n")
+	dia := ahora.Format("20060102")
+	alcance := dia + "/" + cuenta.region + "/ses/aws4_request"
+	resumen := sha256.Sum256([]byte(canonica))
+	cadena := algoritmoFirmaV4 + "\n" + ahora.Format(formatoFechaV4) + "\n" + alcance + "\n" + hex.EncodeToString(resumen[:])
+
+	clave := hmacSHA256([]byte("AWS4"+cuenta.claveSecreta), dia)
+	clave = hmacSHA256(clave, cuenta.region)
+	clave = hmacSHA256(clave, "ses")
+	clave = hmacSHA256(clave, "aws4_request")
+	solicitud.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		algoritmoFirmaV4, cuenta.claveAcceso, alcance, firmados, hex.EncodeToString(hmacSHA256(clave, cadena))))
+}
+
+func hmacSHA256(clave []byte, datos string) []byte {
+	mac := hmac.New(sha256.New, clave)
+	mac.Write([]byte(datos))
+	return mac.Sum(nil)
+}
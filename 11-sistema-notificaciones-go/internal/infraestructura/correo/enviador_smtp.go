@@ -0,0 +1,368 @@
+package correo
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+const (
+	// ProveedorSMTP identifica las credenciales propias de un inquilino para este enviador
+	ProveedorSMTP = "smtp"
+	// EncabezadoNotificacion lleva el ID de la notificación en cada correo enviado
+	EncabezadoNotificacion = "X-Notificacion-ID"
+
+	// tiempoMaximoSesion acota la conversación SMTP cuando el contexto no tiene plazo
+	tiempoMaximoSesion = 30 * time.Second
+	// largoLineaBase64 es el largo máximo de línea de un adjunto, según RFC 2045
+	largoLineaBase64 = 76
+)
+
+// GeneradorQR genera la imagen del código QR de la notificación, si tiene; lo implementa
+// casoUso.CasoUsoCodigosQR
+type GeneradorQR interface {
+	ImagenQR(notificacion *entidad.Notificacion) ([]byte, bool, error)
+}
+
+// LectorAdjuntos lee los adjuntos de la notificación desde el almacén de objetos; lo implementa
+// casoUso.CasoUsoAdjuntos
+type LectorAdjuntos interface {
+	ListarDeNotificacion(ctx context.Context, notificacion *entidad.Notificacion) ([]entidad.Adjunto, error)
+	Abrir(ctx context.Context, adjunto *entidad.Adjunto) (io.ReadCloser, error)
+}
+
+// parteAdjunta es un archivo que viaja en el correo después del texto
+type parteAdjunta struct {
+	nombre    string
+	tipo      string
+	contenido []byte
+	// enLinea la muestra en el cuerpo, como el código QR, en vez de como archivo adjunto
+	enLinea bool
+}
+
+// servidorSMTP son los datos de conexión de un envío: los de la plataforma, o los del
+// inquilino y su región si están en el contexto
+type servidorSMTP struct {
+	host      string
+	puerto    int
+	tls       string
+	usuario   string
+	clave     string
+	remitente string
+}
+
+// EnviadorSMTP entrega notificaciones TipoEmail al correo electrónico del usuario
+type EnviadorSMTP struct {
+	config             configuracion.ConfiguracionCorreo
+	repositorioUsuario repositorio.RepositorioUsuario
+	codigosQR          GeneradorQR
+	adjuntos           LectorAdjuntos
+	reloj              reloj.Reloj
+}
+
+// NuevoEnviadorSMTP crea una nueva instancia de EnviadorSMTP
+func NuevoEnviadorSMTP(config configuracion.ConfiguracionCorreo, repositorioUsuario repositorio.RepositorioUsuario, codigosQR GeneradorQR, adjuntos LectorAdjuntos, rel reloj.Reloj) *EnviadorSMTP {
+	return &EnviadorSMTP{config: config, repositorioUsuario: repositorioUsuario, codigosQR: codigosQR, adjuntos: adjuntos, reloj: rel}
+}
+
+// Tipo implementa servicio.ProveedorNotificacion
+func (e *EnviadorSMTP) Tipo() entidad.TipoNotificacion {
+	return entidad.TipoEmail
+}
+
+// Proveedor implementa servicio.EnviadorConCredenciales. Las credenciales del inquilino
+// admiten las claves host, puerto, tls, usuario, clave y remitente.
+func (e *EnviadorSMTP) Proveedor() string {
+	return ProveedorSMTP
+}
+
+// Enviar compone el correo en texto plano, con el código QR y los adjuntos de la notificación,
+// y lo entrega al servidor SMTP
+func (e *EnviadorSMTP) Enviar(ctx context.Context, notificacion *entidad.Notificacion) error {
+	usuario, err := e.repositorioUsuario.ObtenerPorID(ctx, notificacion.UsuarioID)
+	if err != nil {
+		return err
+	}
+	if usuario.CorreoElectronico == "" {
+		return entidad.NewErrorValidacion("el usuario no tiene correo electrónico")
+	}
+
+	servidor, err := e.servidor(ctx)
+	if err != nil {
+		return err
+	}
+	partes, err := partesAdjuntas(ctx, e.codigosQR, e.adjuntos, notificacion)
+	if err != nil {
+		return err
+	}
+	mensaje, err := componer(servidor.remitente, usuario.CorreoElectronico, responderA(e.config, notificacion), notificacion, partes, e.reloj.Ahora())
+	if err != nil {
+		return err
+	}
+	if esRelayMailgun(servidor.host) {
+		mensaje = append(variablesMailgun(notificacion), mensaje...)
+	}
+	return entregar(ctx, servidor, usuario.CorreoElectronico, mensaje)
+}
+
+// servidor resuelve los datos de conexión: las credenciales del inquilino reemplazan a las de
+// la plataforma campo a campo y el endpoint regional (smtp://host:puerto) reemplaza al host
+func (e *EnviadorSMTP) servidor(ctx context.Context) (servidorSMTP, error) {
+	servidor := servidorSMTP{
+		host:      e.config.Host,
+		puerto:    e.config.Puerto,
+		tls:       e.config.TLS,
+		usuario:   e.config.Usuario,
+		clave:     e.config.Clave,
+		remitente: e.config.Remitente,
+	}
+
+	if endpoint, ok := servicio.EndpointProveedorDesdeContexto(ctx); ok {
+		direccion, err := url.Parse(endpoint)
+		if err != nil || direccion.Hostname() == "" {
+			return servidor, fmt.Errorf("endpoint SMTP regional inválido: %q", endpoint)
+		}
+		servidor.host = direccion.Hostname()
+		if puerto, err := strconv.Atoi(direccion.Port()); err == nil {
+			servidor.puerto = puerto
+		}
+	}
+
+	if credenciales, ok := servicio.CredencialesDesdeContexto(ctx); ok {
+		for clave, destino := range map[string]*string{
+			"host": &servidor.host, "tls": &servidor.tls, "usuario": &servidor.usuario, "clave": &servidor.clave, "remitente": &servidor.remitente,
+		} {
+			if valor := credenciales[clave]; valor != "" {
+				*destino = valor
+			}
+		}
+		if puerto, err := strconv.Atoi(credenciales["puerto"]); err == nil {
+			servidor.puerto = puerto
+		}
+	}
+
+	if servidor.host == "" || servidor.remitente == "" {
+		return servidor, fmt.Errorf("servidor SMTP no configurado")
+	}
+	return servidor, nil
+}
+
+// responderA es la dirección de respuesta firmada de la notificación, o vacía si no se procesan
+// respuestas por correo
+func responderA(config configuracion.ConfiguracionCorreo, notificacion *entidad.Notificacion) string {
+	if config.DireccionRespuestas == "" {
+		return ""
+	}
+	token := servicio.FirmarRespuesta([]byte(config.SecretoRespuestas), notificacion.InquilinoID, notificacion.ID)
+	return servicio.DireccionRespuesta(config.DireccionRespuestas, token)
+}
+
+// partesAdjuntas reúne el código QR y los adjuntos de la notificación, leídos del almacén
+func partesAdjuntas(ctx context.Context, codigosQR GeneradorQR, lector LectorAdjuntos, notificacion *entidad.Notificacion) ([]parteAdjunta, error) {
+	var partes []parteAdjunta
+	imagenQR, ok, err := codigosQR.ImagenQR(notificacion)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		partes = append(partes, parteAdjunta{nombre: "codigo-qr.png", tipo: "image/png", contenido: imagenQR, enLinea: true})
+	}
+
+	adjuntos, err := lector.ListarDeNotificacion(ctx, notificacion)
+	if err != nil {
+		return nil, err
+	}
+	for i := range adjuntos {
+		archivo, err := lector.Abrir(ctx, &adjuntos[i])
+		if err != nil {
+			return nil, fmt.Errorf("leyendo el adjunto %d: %w", adjuntos[i].ID, err)
+		}
+		contenido, err := io.ReadAll(archivo)
+		archivo.Close()
+		if err != nil {
+			return nil, fmt.Errorf("leyendo el adjunto %d: %w", adjuntos[i].ID, err)
+		}
+		partes = append(partes, parteAdjunta{nombre: adjuntos[i].Nombre, tipo: adjuntos[i].TipoContenido, contenido: contenido})
+	}
+	return partes, nil
+}
+
+// componer arma el mensaje RFC 5322. El asunto se codifica siempre que haga falta, así un
+// título con saltos de línea no puede inyectar encabezados. Con partes adjuntas el mensaje es
+// multipart/mixed: primero el texto y después cada archivo en base64.
+func componer(remitente, destinatario, responderA string, notificacion *entidad.Notificacion, adjuntas []parteAdjunta, ahora time.Time) ([]byte, error) {
+	dominio := "localhost"
+	if _, despues, ok := strings.Cut(remitente, "@"); ok {
+		dominio = despues
+	}
+
+	var mensaje bytes.Buffer
+	fmt.Fprintf(&mensaje, "From: %s\r\n", remitente)
+	fmt.Fprintf(&mensaje, "To: %s\r\n", destinatario)
+	if responderA != "" {
+		fmt.Fprintf(&mensaje, "Reply-To: %s\r\n", responderA)
+	}
+	fmt.Fprintf(&mensaje, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", notificacion.Titulo))
+	fmt.Fprintf(&mensaje, "Date: %s\r\n", ahora.Format(time.RFC1123Z))
+	fmt.Fprintf(&mensaje, "Message-ID: <notificacion-%d.%d@%s>\r\n", notificacion.ID, ahora.UnixNano(), dominio)
+	fmt.Fprintf(&mensaje, "%s: %d\r\n", EncabezadoNotificacion, notificacion.ID)
+	mensaje.WriteString("MIME-Version: 1.0\r\n")
+	if len(adjuntas) == 0 {
+		mensaje.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+		mensaje.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		if err := escribirTexto(&mensaje, notificacion.Mensaje); err != nil {
+			return nil, err
+		}
+		return mensaje.Bytes(), nil
+	}
+
+	partes := multipart.NewWriter(&mensaje)
+	fmt.Fprintf(&mensaje, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", partes.Boundary())
+	texto, err := partes.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := escribirTexto(texto, notificacion.Mensaje); err != nil {
+		return nil, err
+	}
+	for i, adjunta := range adjuntas {
+		tipo, parametros, err := mime.ParseMediaType(adjunta.tipo)
+		if err != nil {
+			tipo, parametros = "application/octet-stream", map[string]string{}
+		}
+		parametros["name"] = adjunta.nombre
+		encabezado := textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(tipo, parametros)},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": adjunta.nombre})},
+		}
+		if adjunta.enLinea {
+			encabezado.Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": adjunta.nombre}))
+			encabezado.Set("Content-ID", fmt.Sprintf("<parte-%d.%d@%s>", notificacion.ID, i, dominio))
+		}
+		parte, err := partes.CreatePart(encabezado)
+		if err != nil {
+			return nil, err
+		}
+		if err := escribirBase64(parte, adjunta.contenido); err != nil {
+			return nil, err
+		}
+	}
+	if err := partes.Close(); err != nil {
+		return nil, err
+	}
+	return mensaje.Bytes(), nil
+}
+
+// escribirBase64 escribe el contenido en base64 partido en líneas de 76 caracteres
+func escribirBase64(destino io.Writer, contenido []byte) error {
+	codificado := base64.StdEncoding.EncodeToString(contenido)
+	for len(codificado) > 0 {
+		linea := codificado[:min(largoLineaBase64, len(codificado))]
+		codificado = codificado[len(linea):]
+		if _, err := io.WriteString(destino, linea+"\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// escribirTexto escribe el cuerpo de texto en quoted-printable
+func escribirTexto(destino io.Writer, texto string) error {
+	cuerpo := quotedprintable.NewWriter(destino)
+	if _, err := cuerpo.Write([]byte(texto)); err != nil {
+		return err
+	}
+	return cuerpo.Close()
+}
+
+// entregar conversa con el servidor respetando el plazo del contexto y su modo TLS. En modo
+// auto usa STARTTLS si el servidor lo ofrece; MailHog y Mailpit no lo ofrecen y aceptan el
+// correo sin autenticar.
+func entregar(ctx context.Context, servidor servidorSMTP, destinatario string, mensaje []byte) error {
+	configTLS := &tls.Config{ServerName: servidor.host}
+	direccion := net.JoinHostPort(servidor.host, strconv.Itoa(servidor.puerto))
+	var dialer net.Dialer
+	var conexion net.Conn
+	var err error
+	switch servidor.tls {
+	case configuracion.TLSImplicito:
+		conexion, err = (&tls.Dialer{NetDialer: &dialer, Config: configTLS}).DialContext(ctx, "tcp", direccion)
+	case configuracion.TLSAutomatico, configuracion.TLSStartTLS, configuracion.TLSNinguno, "":
+		conexion, err = dialer.DialContext(ctx, "tcp", direccion)
+	default:
+		return fmt.Errorf("modo TLS de SMTP inválido: %q", servidor.tls)
+	}
+	if err != nil {
+		return err
+	}
+	plazo, ok := ctx.Deadline()
+	if !ok {
+		plazo = time.Now().Add(tiempoMaximoSesion)
+	}
+	if err := conexion.SetDeadline(plazo); err != nil {
+		conexion.Close()
+		return err
+	}
+
+	cliente, err := smtp.NewClient(conexion, servidor.host)
+	if err != nil {
+		conexion.Close()
+		return err
+	}
+	defer cliente.Close()
+
+	ofrece, _ := cliente.Extension("STARTTLS")
+	if servidor.tls == configuracion.TLSStartTLS && !ofrece {
+		return fmt.Errorf("el servidor SMTP %s no ofrece STARTTLS", servidor.host)
+	}
+	if ofrece && (servidor.tls == configuracion.TLSAutomatico || servidor.tls == configuracion.TLSStartTLS || servidor.tls == "") {
+		if err := cliente.StartTLS(configTLS); err != nil {
+			return err
+		}
+	}
+	if servidor.usuario != "" {
+		if err := cliente.Auth(smtp.PlainAuth("", servidor.usuario, servidor.clave, servidor.host)); err != nil {
+			return err
+		}
+	}
+	if err := cliente.Mail(servidor.remitente); err != nil {
+		return err
+	}
+	if err := cliente.Rcpt(destinatario); err != nil {
+		return err
+	}
+	escritor, err := cliente.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := escritor.Write(mensaje); err != nil {
+		return err
+	}
+	if err := escritor.Close(); err != nil {
+		return err
+	}
+	return cliente.Quit()
+}
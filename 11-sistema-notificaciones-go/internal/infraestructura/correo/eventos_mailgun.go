@@ -0,0 +1,189 @@
+package correo
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+const (
+	// ProveedorMailgun identifica las credenciales propias de un inquilino y los eventos de Mailgun
+	ProveedorMailgun = "mailgun"
+
+	// encabezadoVariablesMailgun lleva, en JSON, las variables que el relay de Mailgun devuelve
+	// en cada evento como user-variables
+	encabezadoVariablesMailgun = "X-Mailgun-Variables"
+)
+
+// esRelayMailgun indica si el servidor SMTP es el relay de Mailgun (smtp.mailgun.org o el de
+// la región europea)
+func esRelayMailgun(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	return host == "mailgun.org" || strings.HasSuffix(host, ".mailgun.org")
+}
+
+// variablesMailgun es el encabezado con la notificación y su inquilino, listo para anteponerse
+// al correo compuesto
+func variablesMailgun(notificacion *entidad.Notificacion) []byte {
+	variables, _ := json.Marshal(map[string]string{
+		argumentoNotificacion: strconv.FormatUint(uint64(notificacion.ID), 10),
+		argumentoInquilino:    strconv.FormatUint(uint64(notificacion.InquilinoID), 10),
+	})
+	return []byte(encabezadoVariablesMailgun + ": " + string(variables) + "\r\n")
+}
+
+// webhookMailgun es la parte que se usa de un webhook de eventos: la firma y un único evento
+type webhookMailgun struct {
+	Firma struct {
+		MarcaTiempo string `json:"timestamp"`
+		Token       string `json:"token"`
+		Firma       string `json:"signature"`
+	} `json:"signature"`
+	Evento struct {
+		Evento       string `json:"event"`
+		Severidad    string `json:"severity"`
+		Destinatario string `json:"recipient"`
+		Motivo       string `json:"reason"`
+		Estado       struct {
+			Descripcion string `json:"description"`
+			Mensaje     string `json:"message"`
+		} `json:"delivery-status"`
+		Variables map[string]interface{} `json:"user-variables"`
+	} `json:"event-data"`
+}
+
+// ReceptorMailgun aplica los eventos que Mailgun informa por webhook de los correos que salieron
+// por su relay SMTP
+type ReceptorMailgun struct {
+	config       configuracion.ConfiguracionMailgun
+	receptor     ReceptorEventosCorreo
+	credenciales ResolvedorCredenciales
+	reloj        reloj.Reloj
+}
+
+// NuevoReceptorMailgun crea una nueva instancia de ReceptorMailgun
+func NuevoReceptorMailgun(config configuracion.ConfiguracionMailgun, receptor ReceptorEventosCorreo, credenciales ResolvedorCredenciales, rel reloj.Reloj) *ReceptorMailgun {
+	return &ReceptorMailgun{
+		config:       config,
+		receptor:     receptor,
+		credenciales: credenciales,
+		reloj:        rel,
+	}
+}
+
+// RecibirWebhook verifica la firma del webhook, con la clave de la plataforma o la clave_webhook
+// del inquilino del correo, y aplica su evento. Solo los fallos permanentes son rebotes: los
+// temporales Mailgun los reintenta.
+func (r *ReceptorMailgun) RecibirWebhook(ctx context.Context, _ http.Header, cuerpo []byte) error {
+	var webhook webhookMailgun
+	if err := json.Unmarshal(cuerpo, &webhook); err != nil {
+		return entidad.NewErrorValidacion("el cuerpo no es un webhook de Mailgun")
+	}
+	notificacionID, errNotificacion := strconv.ParseUint(textoVariable(webhook.Evento.Variables[argumentoNotificacion]), 10, 0)
+	inquilinoID, errInquilino := strconv.ParseUint(textoVariable(webhook.Evento.Variables[argumentoInquilino]), 10, 0)
+	valida, err := r.firmaValida(ctx, webhook, uint(inquilinoID))
+	if err != nil {
+		return err
+	}
+	if !valida {
+		return entidad.ErrFirmaProveedorInvalida
+	}
+	if errNotificacion != nil || errInquilino != nil || notificacionID == 0 {
+		// Correos que no salieron de este servicio
+		return nil
+	}
+
+	evento := entidad.EventoCorreo{
+		Proveedor:      ProveedorMailgun,
+		InquilinoID:    uint(inquilinoID),
+		NotificacionID: uint(notificacionID),
+		Motivo:         motivoMailgun(webhook),
+	}
+	switch webhook.Evento.Evento {
+	case "delivered":
+		evento.Tipo = entidad.EventoCorreoEntregado
+	case "opened":
+		evento.Tipo = entidad.EventoCorreoAbierto
+	case "failed":
+		if webhook.Evento.Severidad != "permanent" {
+			return nil
+		}
+		evento.Tipo = entidad.EventoCorreoRebotado
+		evento.Destinatario = webhook.Evento.Destinatario
+	case "complained":
+		evento.Tipo = entidad.EventoCorreoQueja
+		evento.Destinatario = webhook.Evento.Destinatario
+	default:
+		// accepted, clicked y las bajas no cambian la notificación
+		return nil
+	}
+	return r.receptor.Aplicar(ctx, evento)
+}
+
+// firmaValida exige una marca de tiempo reciente y el HMAC-SHA256 de la marca de tiempo seguida
+// del token, con la clave de la plataforma o con la del inquilino
+func (r *ReceptorMailgun) firmaValida(ctx context.Context, webhook webhookMailgun, inquilinoID uint) (bool, error) {
+	segundos, err := strconv.ParseInt(webhook.Firma.MarcaTiempo, 10, 64)
+	if err != nil || webhook.Firma.Token == "" {
+		return false, nil
+	}
+	antiguedad := r.reloj.Ahora().Sub(time.Unix(segundos, 0))
+	if antiguedad > r.config.ToleranciaFirma || antiguedad < -r.config.ToleranciaFirma {
+		return false, nil
+	}
+	if r.config.ClaveWebhook != "" && FirmaMailgunValida(r.config.ClaveWebhook, webhook.Firma.MarcaTiempo, webhook.Firma.Token, webhook.Firma.Firma) {
+		return true, nil
+	}
+	if inquilinoID == 0 {
+		return false, nil
+	}
+	credenciales, err := r.credenciales.Resolver(ctx, inquilinoID, ProveedorMailgun)
+	if err != nil {
+		return false, err
+	}
+	if credenciales["clave_webhook"] == "" {
+		return false, nil
+	}
+	return FirmaMailgunValida(credenciales["clave_webhook"], webhook.Firma.MarcaTiempo, webhook.Firma.Token, webhook.Firma.Firma), nil
+}
+
+// FirmaMailgunValida verifica la firma hexadecimal del HMAC-SHA256 de la marca de tiempo seguida
+// del token
+func FirmaMailgunValida(clave, marcaTiempo, token, firma string) bool {
+	decodificada, err := hex.DecodeString(firma)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(hmacSHA256([]byte(clave), marcaTiempo+token), decodificada)
+}
+
+// motivoMailgun es la descripción del estado de entrega, o el motivo del evento si no la hay
+func motivoMailgun(webhook webhookMailgun) string {
+	for _, motivo := range []string{webhook.Evento.Estado.Descripcion, webhook.Evento.Estado.Mensaje, webhook.Evento.Motivo} {
+		if motivo = strings.TrimSpace(motivo); motivo != "" {
+			return motivo
+		}
+	}
+	return ""
+}
+
+// textoVariable lee una user-variable de texto o número
+func textoVariable(valor interface{}) string {
+	switch v := valor.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,184 @@
+package correo
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// Encabezados con que SendGrid firma cada entrega del Event Webhook
+const (
+	EncabezadoFirmaSendGrid       = "X-Twilio-Email-Event-Webhook-Signature"
+	EncabezadoMarcaTiempoSendGrid = "X-Twilio-Email-Event-Webhook-Timestamp"
+)
+
+// eventosSendGrid traduce los eventos de SendGrid que cambian la notificación; processed,
+// deferred, click y los de bajas no la cambian
+var eventosSendGrid = map[string]entidad.TipoEventoCorreo{
+	"delivered":  entidad.EventoCorreoEntregado,
+	"bounce":     entidad.EventoCorreoRebotado,
+	"dropped":    entidad.EventoCorreoRebotado,
+	"open":       entidad.EventoCorreoAbierto,
+	"spamreport": entidad.EventoCorreoQueja,
+}
+
+// eventoSendGrid es la parte que se usa de un evento; los custom_args llegan como campos
+// del propio evento
+type eventoSendGrid struct {
+	Evento string `json:"event"`
+	Motivo string `json:"reason"`
+	Correo string `json:"email"`
+	// TipoRebote distingue el rebote permanente (bounce) del bloqueo temporal (blocked)
+	TipoRebote   string `json:"type"`
+	Notificacion string `json:"notificacion_id"`
+	Inquilino    string `json:"inquilino_id"`
+	// AperturaAutomatica marca las aperturas que hace el cliente de correo al descargar el
+	// mensaje (Apple Mail Privacy Protection), no el usuario
+	AperturaAutomatica bool `json:"sg_machine_open"`
+}
+
+// RecibirEventos verifica la firma de una entrega del Event Webhook y aplica sus eventos. La
+// entrega viene de la cuenta de la plataforma, con eventos de cualquier inquilino, o de la
+// cuenta propia de un inquilino, firmada con su clave_webhook: en ese caso solo se aplican los
+// eventos de ese inquilino.
+func (e *EnviadorSendGrid) RecibirEventos(ctx context.Context, cuerpo []byte, firma, marcaTiempo string) error {
+	var eventos []eventoSendGrid
+	if err := json.Unmarshal(cuerpo, &eventos); err != nil {
+		return entidad.NewErrorValidacion("el cuerpo no es una lista de eventos de SendGrid")
+	}
+	// La marca de tiempo está firmada: una entrega vieja es una repetición, aunque la firma valga
+	if !e.marcaTiempoReciente(marcaTiempo) {
+		return entidad.ErrFirmaProveedorInvalida
+	}
+
+	// Firmada por la plataforma se aplican los eventos de todos los inquilinos
+	var soloInquilino *uint
+	if !(e.claveWebhook != nil && FirmaSendGridValida(e.claveWebhook, cuerpo, firma, marcaTiempo)) {
+		inquilinoID, valida, err := e.firmaDeInquilino(ctx, eventos, cuerpo, firma, marcaTiempo)
+		if err != nil {
+			return err
+		}
+		if !valida {
+			return entidad.ErrFirmaProveedorInvalida
+		}
+		soloInquilino = &inquilinoID
+	}
+
+	for _, evento := range eventos {
+		tipo, aplica := eventosSendGrid[evento.Evento]
+		if !aplica || (tipo == entidad.EventoCorreoAbierto && evento.AperturaAutomatica) {
+			continue
+		}
+		notificacionID, errNotificacion := strconv.ParseUint(evento.Notificacion, 10, 0)
+		inquilinoID, errInquilino := strconv.ParseUint(evento.Inquilino, 10, 0)
+		if errNotificacion != nil || errInquilino != nil || notificacionID == 0 {
+			// Correos que no salieron de este servicio
+			continue
+		}
+		if soloInquilino != nil && uint(inquilinoID) != *soloInquilino {
+			continue
+		}
+		err := e.receptor.Aplicar(ctx, entidad.EventoCorreo{
+			Proveedor:      ProveedorSendGrid,
+			InquilinoID:    uint(inquilinoID),
+			NotificacionID: uint(notificacionID),
+			Tipo:           tipo,
+			Motivo:         evento.Motivo,
+			Destinatario:   evento.destinatarioSuprimible(),
+		})
+		if err != nil {
+			// SendGrid reintenta la entrega completa; los eventos ya aplicados se descartan al repetirse
+			return err
+		}
+	}
+	return nil
+}
+
+// RecibirWebhook implementa controlador.ReceptorWebhookProveedor con la firma de los encabezados
+func (e *EnviadorSendGrid) RecibirWebhook(ctx context.Context, encabezados http.Header, cuerpo []byte) error {
+	return e.RecibirEventos(ctx, cuerpo, encabezados.Get(EncabezadoFirmaSendGrid), encabezados.Get(EncabezadoMarcaTiempoSendGrid))
+}
+
+// destinatarioSuprimible es la dirección que va a la lista de supresión: la de un rebote
+// permanente o una queja. Los dropped ya estaban suprimidos en SendGrid.
+func (e eventoSendGrid) destinatarioSuprimible() string {
+	switch {
+	case e.Evento == "spamreport", e.Evento == "bounce" && e.TipoRebote != "blocked":
+		return e.Correo
+	default:
+		return ""
+	}
+}
+
+// firmaDeInquilino verifica la entrega con la clave_webhook del inquilino del primer evento
+func (e *EnviadorSendGrid) firmaDeInquilino(ctx context.Context, eventos []eventoSendGrid, cuerpo []byte, firma, marcaTiempo string) (uint, bool, error) {
+	if len(eventos) == 0 {
+		return 0, false, nil
+	}
+	inquilinoID, err := strconv.ParseUint(eventos[0].Inquilino, 10, 0)
+	if err != nil || inquilinoID == 0 {
+		return 0, false, nil
+	}
+	credenciales, err := e.credenciales.Resolver(ctx, uint(inquilinoID), ProveedorSendGrid)
+	if err != nil {
+		return 0, false, err
+	}
+	if credenciales["clave_webhook"] == "" {
+		return 0, false, nil
+	}
+	clave, err := ClavePublicaWebhook(credenciales["clave_webhook"])
+	if err != nil {
+		e.logger.Warn("Clave del webhook de SendGrid inválida", "inquilino_id", inquilinoID, "error", err)
+		return 0, false, nil
+	}
+	return uint(inquilinoID), FirmaSendGridValida(clave, cuerpo, firma, marcaTiempo), nil
+}
+
+// marcaTiempoReciente indica si la marca de tiempo, en segundos Unix, no difiere del reloj más
+// que la tolerancia
+func (e *EnviadorSendGrid) marcaTiempoReciente(marcaTiempo string) bool {
+	segundos, err := strconv.ParseInt(marcaTiempo, 10, 64)
+	if err != nil {
+		return false
+	}
+	diferencia := e.reloj.Ahora().Sub(time.Unix(segundos, 0))
+	return diferencia <= e.config.ToleranciaFirma && diferencia >= -e.config.ToleranciaFirma
+}
+
+// ClavePublicaWebhook decodifica la clave de verificación que muestra SendGrid: una clave
+// pública ECDSA en DER y base64
+func ClavePublicaWebhook(claveBase64 string) (*ecdsa.PublicKey, error) {
+	der, err := base64.StdEncoding.DecodeString(claveBase64)
+	if err != nil {
+		return nil, fmt.Errorf("la clave no está en base64: %w", err)
+	}
+	clave, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+	publica, ok := clave.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("la clave no es ECDSA")
+	}
+	return publica, nil
+}
+
+// FirmaSendGridValida verifica la firma ECDSA, en ASN.1 y base64, del SHA-256 de la marca de
+// tiempo seguida del cuerpo tal como llegó
+func FirmaSendGridValida(clave *ecdsa.PublicKey, cuerpo []byte, firma, marcaTiempo string) bool {
+	decodificada, err := base64.StdEncoding.DecodeString(firma)
+	if err != nil || marcaTiempo == "" {
+		return false
+	}
+	resumen := sha256.Sum256(append([]byte(marcaTiempo), cuerpo...))
+	return ecdsa.VerifyASN1(clave, resumen[:], decodificada)
+}
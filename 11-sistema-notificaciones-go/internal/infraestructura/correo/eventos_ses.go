@@ -0,0 +1,328 @@
+package correo
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+const (
+	// maxBytesCertificadoSNS acota la descarga del certificado de firma de SNS
+	maxBytesCertificadoSNS = 64 << 10
+
+	rebotePermanente = "Permanent"
+)
+
+// hostSNS admite solo los endpoints de SNS de AWS para el certificado y la suscripción; de
+// otro modo cualquiera podría firmar con su propio certificado
+var hostSNS = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// mensajeSNS es un mensaje HTTP de SNS; Message lleva, en las notificaciones, el JSON de SES
+type mensajeSNS struct {
+	Tipo           string `json:"Type"`
+	IDMensaje      string `json:"MessageId"`
+	Token          string `json:"Token"`
+	Tema           string `json:"TopicArn"`
+	Asunto         string `json:"Subject"`
+	Mensaje        string `json:"Message"`
+	MarcaTiempo    string `json:"Timestamp"`
+	VersionFirma   string `json:"SignatureVersion"`
+	Firma          string `json:"Signature"`
+	URLCertificado string `json:"SigningCertURL"`
+	URLSuscripcion string `json:"SubscribeURL"`
+}
+
+// notificacionSES es la parte que se usa de una notificación de SES. Las notificaciones de la
+// identidad traen notificationType y las del configuration set eventType, con las etiquetas.
+type notificacionSES struct {
+	TipoNotificacion string `json:"notificationType"`
+	TipoEvento       string `json:"eventType"`
+	Correo           struct {
+		IDMensaje string              `json:"messageId"`
+		Etiquetas map[string][]string `json:"tags"`
+	} `json:"mail"`
+	Rebote struct {
+		Tipo          string `json:"bounceType"`
+		Subtipo       string `json:"bounceSubType"`
+		Destinatarios []struct {
+			Direccion   string `json:"emailAddress"`
+			Diagnostico string `json:"diagnosticCode"`
+		} `json:"bouncedRecipients"`
+	} `json:"bounce"`
+	Queja struct {
+		Tipo          string `json:"complaintFeedbackType"`
+		Destinatarios []struct {
+			Direccion string `json:"emailAddress"`
+		} `json:"complainedRecipients"`
+	} `json:"complaint"`
+}
+
+// RecibirNotificacionSNS verifica un mensaje de SNS de uno de los temas configurados y lo
+// aplica: confirma las suscripciones y traduce las entregas, rebotes y quejas de SES. Los
+// rebotes permanentes y las quejas suprimen al destinatario en la lista del inquilino.
+func (e *EnviadorSES) RecibirNotificacionSNS(ctx context.Context, cuerpo []byte) error {
+	var mensaje mensajeSNS
+	if err := json.Unmarshal(cuerpo, &mensaje); err != nil {
+		return entidad.NewErrorValidacion("el cuerpo no es un mensaje de SNS")
+	}
+	if !e.temaPermitido(mensaje.Tema) {
+		e.logger.Warn("Mensaje SNS de un tema no configurado", "tema", mensaje.Tema)
+		return entidad.ErrFirmaProveedorInvalida
+	}
+	if err := e.verificarFirmaSNS(ctx, mensaje); err != nil {
+		e.logger.Warn("Firma SNS inválida", "tema", mensaje.Tema, "error", err)
+		return entidad.ErrFirmaProveedorInvalida
+	}
+
+	switch mensaje.Tipo {
+	case "SubscriptionConfirmation":
+		return e.confirmarSuscripcion(ctx, mensaje)
+	case "Notification":
+		return e.aplicarNotificacion(ctx, mensaje.Mensaje)
+	default:
+		e.logger.Info("Mensaje SNS ignorado", "tipo", mensaje.Tipo, "tema", mensaje.Tema)
+		return nil
+	}
+}
+
+// RecibirWebhook implementa controlador.ReceptorWebhookProveedor; SNS firma dentro del cuerpo
+func (e *EnviadorSES) RecibirWebhook(ctx context.Context, _ http.Header, cuerpo []byte) error {
+	return e.RecibirNotificacionSNS(ctx, cuerpo)
+}
+
+func (e *EnviadorSES) temaPermitido(tema string) bool {
+	for _, permitido := range e.config.TemasSNS {
+		if tema == permitido {
+			return true
+		}
+	}
+	return false
+}
+
+// confirmarSuscripcion visita la URL de confirmación para que SNS empiece a publicar en el
+// endpoint
+func (e *EnviadorSES) confirmarSuscripcion(ctx context.Context, mensaje mensajeSNS) error {
+	if !urlSNSValida(mensaje.URLSuscripcion) {
+		return entidad.NewErrorValidacion("SubscribeURL no es de SNS")
+	}
+	solicitud, err := http.NewRequestWithContext(ctx, http.MethodGet, mensaje.URLSuscripcion, nil)
+	if err != nil {
+		return err
+	}
+	respuesta, err := e.cliente.Do(solicitud)
+	if err != nil {
+		return err
+	}
+	defer respuesta.Body.Close()
+	if respuesta.StatusCode != http.StatusOK {
+		return fmt.Errorf("SNS respondió %d a la confirmación de la suscripción", respuesta.StatusCode)
+	}
+	e.logger.Info("Suscripción SNS confirmada", "tema", mensaje.Tema)
+	return nil
+}
+
+// aplicarNotificacion traduce la notificación de SES en eventos de correo de la notificación a
+// la que pertenece el mensaje: la del registro de mensajes o, si ya venció, la de sus etiquetas
+func (e *EnviadorSES) aplicarNotificacion(ctx context.Context, contenido string) error {
+	var notificacion notificacionSES
+	if err := json.Unmarshal([]byte(contenido), &notificacion); err != nil {
+		return entidad.NewErrorValidacion("la notificación no es un evento de SES")
+	}
+	referencia, err := e.registro.Resolver(ctx, ProveedorSES, notificacion.Correo.IDMensaje)
+	if err != nil {
+		return err
+	}
+	if referencia == nil {
+		referencia = referenciaDeEtiquetas(notificacion.Correo.Etiquetas)
+	}
+	if referencia == nil {
+		// Correos que no salieron de este servicio o cuyo registro venció
+		e.logger.Debug("Notificación de SES de un mensaje desconocido", "id_mensaje", notificacion.Correo.IDMensaje)
+		return nil
+	}
+
+	tipo := notificacion.TipoNotificacion
+	if tipo == "" {
+		tipo = notificacion.TipoEvento
+	}
+	base := entidad.EventoCorreo{
+		Proveedor:      ProveedorSES,
+		InquilinoID:    referencia.InquilinoID,
+		NotificacionID: referencia.NotificacionID,
+	}
+	var eventos []entidad.EventoCorreo
+	switch tipo {
+	case "Delivery":
+		base.Tipo = entidad.EventoCorreoEntregado
+		eventos = append(eventos, base)
+	case "Bounce":
+		// Un rebote transitorio (buzón lleno, rechazo temporal) marca la notificación pero no
+		// suprime la dirección
+		base.Tipo = entidad.EventoCorreoRebotado
+		base.Motivo = notificacion.Rebote.Tipo + "/" + notificacion.Rebote.Subtipo
+		for _, destinatario := range notificacion.Rebote.Destinatarios {
+			evento := base
+			if destinatario.Diagnostico != "" {
+				evento.Motivo = destinatario.Diagnostico
+			}
+			if notificacion.Rebote.Tipo == rebotePermanente {
+				evento.Destinatario = destinatario.Direccion
+			}
+			eventos = append(eventos, evento)
+		}
+		if len(eventos) == 0 {
+			eventos = append(eventos, base)
+		}
+	case "Complaint":
+		base.Tipo = entidad.EventoCorreoQueja
+		base.Motivo = notificacion.Queja.Tipo
+		for _, destinatario := range notificacion.Queja.Destinatarios {
+			evento := base
+			evento.Destinatario = destinatario.Direccion
+			eventos = append(eventos, evento)
+		}
+	}
+
+	for _, evento := range eventos {
+		if err := e.receptor.Aplicar(ctx, evento); err != nil {
+			// SNS reintenta el mensaje completo; los eventos ya aplicados se descartan al repetirse
+			return err
+		}
+	}
+	return nil
+}
+
+// referenciaDeEtiquetas lee la notificación y el inquilino de las etiquetas del correo, que SES
+// solo incluye en los eventos de un configuration set
+func referenciaDeEtiquetas(etiquetas map[string][]string) *entidad.ReferenciaMensaje {
+	if len(etiquetas[argumentoNotificacion]) == 0 || len(etiquetas[argumentoInquilino]) == 0 {
+		return nil
+	}
+	notificacionID, errNotificacion := strconv.ParseUint(etiquetas[argumentoNotificacion][0], 10, 0)
+	inquilinoID, errInquilino := strconv.ParseUint(etiquetas[argumentoInquilino][0], 10, 0)
+	if errNotificacion != nil || errInquilino != nil || notificacionID == 0 {
+		return nil
+	}
+	return &entidad.ReferenciaMensaje{InquilinoID: uint(inquilinoID), NotificacionID: uint(notificacionID), Partes: 1}
+}
+
+// verificarFirmaSNS verifica la firma RSA del mensaje con el certificado de SNS: SHA1 en la
+// versión 1 y SHA256 en la 2
+func (e *EnviadorSES) verificarFirmaSNS(ctx context.Context, mensaje mensajeSNS) error {
+	var algoritmo crypto.Hash
+	var resumen []byte
+	cadena := []byte(cadenaFirmaSNS(mensaje))
+	switch mensaje.VersionFirma {
+	case "1":
+		suma := sha1.Sum(cadena)
+		algoritmo, resumen = crypto.SHA1, suma[:]
+	case "2":
+		suma := sha256.Sum256(cadena)
+		algoritmo, resumen = crypto.SHA256, suma[:]
+	default:
+		return fmt.Errorf("versión de firma %q no soportada", mensaje.VersionFirma)
+	}
+	firma, err := base64.StdEncoding.DecodeString(mensaje.Firma)
+	if err != nil {
+		return fmt.Errorf("la firma no está en base64: %w", err)
+	}
+	certificado, err := e.certificadoSNS(ctx, mensaje.URLCertificado)
+	if err != nil {
+		return err
+	}
+	clave, ok := certificado.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("el certificado de SNS no tiene una clave RSA")
+	}
+	return rsa.VerifyPKCS1v15(clave, algoritmo, resumen, firma)
+}
+
+// cadenaFirmaSNS arma el texto que SNS firma: pares nombre y valor, uno por línea, de los
+// campos de cada tipo de mensaje en orden alfabético
+func cadenaFirmaSNS(mensaje mensajeSNS) string {
+	campos := [][2]string{{"Message", mensaje.Mensaje}, {"MessageId", mensaje.IDMensaje}}
+	if mensaje.Tipo == "Notification" {
+		if mensaje.Asunto != "" {
+			campos = append(campos, [2]string{"Subject", mensaje.Asunto})
+		}
+		campos = append(campos, [2]string{"Timestamp", mensaje.MarcaTiempo})
+	} else {
+		campos = append(campos,
+			[2]string{"SubscribeURL", mensaje.URLSuscripcion},
+			[2]string{"Timestamp", mensaje.MarcaTiempo},
+			[2]string{"Token", mensaje.Token},
+		)
+	}
+	campos = append(campos, [2]string{"TopicArn", mensaje.Tema}, [2]string{"Type", mensaje.Tipo})
+
+	var cadena strings.Builder
+	for _, campo := range campos {
+		cadena.WriteString(campo[0] + "\n" + campo[1] + "\n")
+	}
+	return cadena.String()
+}
+
+// certificadoSNS descarga, o toma de los ya descargados, el certificado de firma; solo se
+// aceptan certificados .pem servidos por SNS
+func (e *EnviadorSES) certificadoSNS(ctx context.Context, direccion string) (*x509.Certificate, error) {
+	if !urlSNSValida(direccion) || !strings.HasSuffix(direccion, ".pem") {
+		return nil, fmt.Errorf("SigningCertURL no es de SNS: %q", direccion)
+	}
+	e.mu.Lock()
+	certificado, existe := e.certificados[direccion]
+	e.mu.Unlock()
+	if existe {
+		return certificado, nil
+	}
+
+	solicitud, err := http.NewRequestWithContext(ctx, http.MethodGet, direccion, nil)
+	if err != nil {
+		return nil, err
+	}
+	respuesta, err := e.cliente.Do(solicitud)
+	if err != nil {
+		return nil, err
+	}
+	defer respuesta.Body.Close()
+	if respuesta.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("SNS respondió %d al pedir el certificado", respuesta.StatusCode)
+	}
+	contenido, err := io.ReadAll(io.LimitReader(respuesta.Body, maxBytesCertificadoSNS))
+	if err != nil {
+		return nil, err
+	}
+	bloque, _ := pem.Decode(contenido)
+	if bloque == nil {
+		return nil, fmt.Errorf("el certificado de SNS no está en PEM")
+	}
+	certificado, err = x509.ParseCertificate(bloque.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	e.certificados[direccion] = certificado
+	e.mu.Unlock()
+	return certificado, nil
+}
+
+// urlSNSValida exige una URL https de un endpoint de SNS
+func urlSNSValida(direccion string) bool {
+	parseada, err := url.Parse(direccion)
+	return err == nil && parseada.Scheme == "https" && hostSNS.MatchString(parseada.Host)
+}
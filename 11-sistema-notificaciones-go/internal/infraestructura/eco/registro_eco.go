@@ -0,0 +1,102 @@
+// Package eco guarda en memoria las entregas recibidas por el receptor de webhooks de prueba
+package eco
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+)
+
+// Entrega es una petición recibida por un buzón, tal como llegó
+type Entrega struct {
+	Fecha     time.Time   `json:"fecha"`
+	Metodo    string      `json:"metodo"`
+	Consulta  string      `json:"consulta,omitempty"`
+	Origen    string      `json:"origen"`
+	Cabeceras http.Header `json:"cabeceras"`
+	// Cuerpo son los bytes exactos recibidos, necesarios para recalcular firmas
+	Cuerpo   []byte `json:"-"`
+	Tamano   int    `json:"tamano"`
+	Truncado bool   `json:"truncado"`
+}
+
+// buzon conserva las últimas entregas en un búfer circular
+type buzon struct {
+	entregas  []Entrega
+	siguiente int
+	lleno     bool
+	ultima    time.Time
+}
+
+// Registro conserva las últimas entregas de cada buzón. Los buzones se crean con la primera
+// entrega y, al superar el máximo, se descarta el que lleva más tiempo sin recibir.
+type Registro struct {
+	config  configuracion.ConfiguracionEco
+	mu      sync.Mutex
+	buzones map[string]*buzon
+}
+
+// NuevoRegistro crea un registro vacío con la capacidad configurada
+func NuevoRegistro(config configuracion.ConfiguracionEco) *Registro {
+	return &Registro{config: config, buzones: make(map[string]*buzon)}
+}
+
+// Registrar agrega la entrega al buzón, descartando la más vieja si está lleno
+func (r *Registro) Registrar(nombre string, entrega Entrega) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, existe := r.buzones[nombre]
+	if !existe {
+		if len(r.buzones) >= r.config.Buzones {
+			r.descartarMasViejo()
+		}
+		b = &buzon{entregas: make([]Entrega, r.config.Capacidad)}
+		r.buzones[nombre] = b
+	}
+	b.entregas[b.siguiente] = entrega
+	b.siguiente = (b.siguiente + 1) % len(b.entregas)
+	b.lleno = b.lleno || b.siguiente == 0
+	b.ultima = entrega.Fecha
+}
+
+// Ultimas retorna las entregas del buzón, la más reciente primero
+func (r *Registro) Ultimas(nombre string) []Entrega {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, existe := r.buzones[nombre]
+	if !existe {
+		return []Entrega{}
+	}
+	cantidad := b.siguiente
+	if b.lleno {
+		cantidad = len(b.entregas)
+	}
+	entregas := make([]Entrega, 0, cantidad)
+	for i := 1; i <= cantidad; i++ {
+		entregas = append(entregas, b.entregas[(b.siguiente-i+len(b.entregas))%len(b.entregas)])
+	}
+	return entregas
+}
+
+// Vaciar elimina el buzón y sus entregas
+func (r *Registro) Vaciar(nombre string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.buzones, nombre)
+}
+
+// descartarMasViejo elimina el buzón con la última entrega más antigua. Requiere el lock.
+func (r *Registro) descartarMasViejo() {
+	var candidato string
+	var masVieja time.Time
+	for nombre, b := range r.buzones {
+		if candidato == "" || b.ultima.Before(masVieja) {
+			candidato, masVieja = nombre, b.ultima
+		}
+	}
+	delete(r.buzones, candidato)
+}
@@ -0,0 +1,11 @@
+package kafka
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var metricaEventos = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "notificaciones_kafka_eventos_total",
+	Help: "Eventos del ciclo de vida publicados en Kafka por resultado (ok o error)",
+}, []string{"resultado"})
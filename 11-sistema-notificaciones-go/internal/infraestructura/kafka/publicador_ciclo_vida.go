@@ -0,0 +1,103 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
+
+	kafkago "github.com/segmentio/kafka-go"
+)
+
+// EventoCreada se publica al guardarse la notificación; los demás eventos llevan el nombre del
+// estado al que pasó
+const EventoCreada = "creada"
+
+// EventoNotificacion es el mensaje de un evento del ciclo de vida. No lleva el contenido de la
+// notificación, que puede tener datos personales: los consumidores lo piden a la API.
+type EventoNotificacion struct {
+	Evento         string                        `json:"evento"`
+	NotificacionID uint                          `json:"notificacion_id"`
+	InquilinoID    uint                          `json:"inquilino_id"`
+	UsuarioID      uint                          `json:"usuario_id"`
+	Tipo           entidad.TipoNotificacion      `json:"tipo"`
+	Prioridad      entidad.PrioridadNotificacion `json:"prioridad"`
+	CanalID        uint                          `json:"canal_id,omitempty"`
+	EnvioID        *uint                         `json:"envio_id,omitempty"`
+	Origen         string                        `json:"origen,omitempty"`
+	IntentosEnvio  int                           `json:"intentos_envio"`
+	Fecha          time.Time                     `json:"fecha"`
+}
+
+// PublicadorCicloVida publica los eventos del ciclo de vida en el tema de eventos, con el ID de
+// la notificación como clave: los de una misma notificación quedan en orden en su partición.
+// Publica sin bloquear al que cambia el estado; un evento que Kafka no acepta tras los
+// reintentos del productor se registra en el log y se pierde.
+type PublicadorCicloVida struct {
+	escritor *kafkago.Writer
+	reloj    reloj.Reloj
+}
+
+// NuevoPublicadorCicloVida crea una nueva instancia de PublicadorCicloVida
+func NuevoPublicadorCicloVida(config configuracion.ConfiguracionKafka, rel reloj.Reloj, log *logger.Logger) *PublicadorCicloVida {
+	escritor := &kafkago.Writer{
+		Addr:         kafkago.TCP(config.Brokers...),
+		Topic:        config.TemaEventos,
+		Balancer:     &kafkago.Hash{},
+		RequiredAcks: kafkago.RequireAll,
+		BatchTimeout: 50 * time.Millisecond,
+		Async:        true,
+		Completion: func(mensajes []kafkago.Message, err error) {
+			if err != nil {
+				metricaEventos.WithLabelValues("error").Add(float64(len(mensajes)))
+				log.Error("Error publicando eventos en Kafka", "tema", config.TemaEventos, "eventos", len(mensajes), "error", err)
+				return
+			}
+			metricaEventos.WithLabelValues("ok").Add(float64(len(mensajes)))
+		},
+	}
+	return &PublicadorCicloVida{escritor: escritor, reloj: rel}
+}
+
+// Publicar encola el evento de la notificación para su envío a Kafka
+func (p *PublicadorCicloVida) Publicar(ctx context.Context, evento string, notificacion *entidad.Notificacion) {
+	p.PublicarLote(ctx, evento, []*entidad.Notificacion{notificacion})
+}
+
+// PublicarLote encola el mismo evento de varias notificaciones
+func (p *PublicadorCicloVida) PublicarLote(ctx context.Context, evento string, notificaciones []*entidad.Notificacion) {
+	ahora := p.reloj.Ahora()
+	mensajes := make([]kafkago.Message, 0, len(notificaciones))
+	for _, notificacion := range notificaciones {
+		valor, _ := json.Marshal(EventoNotificacion{
+			Evento:         evento,
+			NotificacionID: notificacion.ID,
+			InquilinoID:    notificacion.InquilinoID,
+			UsuarioID:      notificacion.UsuarioID,
+			Tipo:           notificacion.Tipo,
+			Prioridad:      notificacion.Prioridad,
+			CanalID:        notificacion.CanalID,
+			EnvioID:        notificacion.EnvioID,
+			Origen:         notificacion.Origen,
+			IntentosEnvio:  notificacion.IntentosEnvio,
+			Fecha:          ahora,
+		})
+		mensajes = append(mensajes, kafkago.Message{
+			Key:     []byte(strconv.FormatUint(uint64(notificacion.ID), 10)),
+			Value:   valor,
+			Headers: []kafkago.Header{{Key: "evento", Value: []byte(evento)}},
+		})
+	}
+	// Con Async el escritor solo falla si ya se cerró
+	_ = p.escritor.WriteMessages(ctx, mensajes...)
+}
+
+// Cerrar envía los eventos pendientes y cierra el productor
+func (p *PublicadorCicloVida) Cerrar() error {
+	return p.escritor.Close()
+}
@@ -0,0 +1,71 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+)
+
+// estadosCicloVida son los estados cuyo ingreso se publica como evento
+var estadosCicloVida = []entidad.EstadoNotificacion{
+	entidad.EstadoEnviada,
+	entidad.EstadoEntregada,
+	entidad.EstadoLeida,
+	entidad.EstadoFallida,
+}
+
+// RepositorioNotificacionEventos decora RepositorioNotificacion publicando en Kafka los eventos
+// del ciclo de vida: creada al guardarse y el nuevo estado cuando una actualización lo cambia.
+// Se publican al terminar la escritura; si era parte de una transacción que luego se revierte,
+// el evento ya salió.
+type RepositorioNotificacionEventos struct {
+	repositorio.RepositorioNotificacion
+	publicador *PublicadorCicloVida
+}
+
+// NuevoRepositorioNotificacionEventos crea el decorador
+func NuevoRepositorioNotificacionEventos(base repositorio.RepositorioNotificacion, publicador *PublicadorCicloVida) *RepositorioNotificacionEventos {
+	return &RepositorioNotificacionEventos{RepositorioNotificacion: base, publicador: publicador}
+}
+
+// Crear guarda la notificación y publica su evento creada
+func (r *RepositorioNotificacionEventos) Crear(ctx context.Context, notificacion *entidad.Notificacion) error {
+	if err := r.RepositorioNotificacion.Crear(ctx, notificacion); err != nil {
+		return err
+	}
+	r.publicador.Publicar(ctx, EventoCreada, notificacion)
+	return nil
+}
+
+// CrearLote guarda las notificaciones y publica el evento creada de cada una
+func (r *RepositorioNotificacionEventos) CrearLote(ctx context.Context, notificaciones []*entidad.Notificacion, tamanoLote int, progreso repositorio.FuncionProgreso) error {
+	if err := r.RepositorioNotificacion.CrearLote(ctx, notificaciones, tamanoLote, progreso); err != nil {
+		return err
+	}
+	r.publicador.PublicarLote(ctx, EventoCreada, notificaciones)
+	return nil
+}
+
+// Actualizar guarda la notificación y, si pasó a un estado del ciclo de vida, publica su
+// evento. Solo entonces lee el estado guardado, así las actualizaciones que no cambian el
+// estado (p. ej. mover de carpeta) no agregan una lectura.
+func (r *RepositorioNotificacionEventos) Actualizar(ctx context.Context, notificacion *entidad.Notificacion) error {
+	if !slices.Contains(estadosCicloVida, notificacion.Estado) {
+		return r.RepositorioNotificacion.Actualizar(ctx, notificacion)
+	}
+	anterior, err := r.RepositorioNotificacion.ObtenerPorID(ctx, notificacion.ID)
+	if err != nil && !errors.Is(err, entidad.ErrNotificacionNoEncontrada) {
+		return err
+	}
+	if err := r.RepositorioNotificacion.Actualizar(ctx, notificacion); err != nil {
+		return err
+	}
+	// Una fallida que vuelve a fallar suma un intento: también se publica
+	if anterior == nil || anterior.Estado != notificacion.Estado || anterior.IntentosEnvio != notificacion.IntentosEnvio {
+		r.publicador.Publicar(ctx, string(notificacion.Estado), notificacion)
+	}
+	return nil
+}
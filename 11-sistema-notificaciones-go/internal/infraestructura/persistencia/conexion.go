@@ -0,0 +1,36 @@
+package persistencia
+
+import (
+	"fmt"
+
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// NuevaConexion abre la conexión a PostgreSQL y configura el pool.
+// Con SentenciasPreparadas, GORM cachea las sentencias por conexión y pgx usa el
+// protocolo extendido, de modo que Postgres reutiliza el plan de las consultas frecuentes.
+func NuevaConexion(config configuracion.ConfiguracionBaseDatos) (*gorm.DB, error) {
+	dialector := postgres.New(postgres.Config{
+		DSN:                  config.DSN(),
+		PreferSimpleProtocol: !config.SentenciasPreparadas,
+	})
+
+	db, err := gorm.Open(dialector, &gorm.Config{
+		PrepareStmt: config.SentenciasPreparadas,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("conectando a PostgreSQL: %w", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("obteniendo pool de conexiones: %w", err)
+	}
+	sqlDB.SetMaxOpenConns(config.MaxConexiones)
+	sqlDB.SetMaxIdleConns(config.MaxConexiones / 2)
+
+	return db, nil
+}
@@ -0,0 +1,197 @@
+package persistencia
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"sync/atomic"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+
+	"gorm.io/gorm"
+)
+
+// ModelosInquilino son las tablas que un inquilino aislado tiene en su propio esquema y las que
+// cada región tiene para sus inquilinos compartidos.
+// Las de plataforma (ModelosPlataforma) quedan en la base principal.
+var ModelosInquilino = []any{
+	&entidad.Usuario{},
+	&entidad.Canal{},
+	&entidad.Notificacion{},
+	&entidad.PreferenciaNotificacion{},
+	&entidad.DispositivoPush{},
+	&entidad.IntentoEnvio{},
+	&entidad.EnvioMultiCanal{},
+	&entidad.PasoEnvio{},
+	&entidad.Plantilla{},
+	&entidad.Consentimiento{},
+	&entidad.SuscripcionCanal{},
+	&entidad.PoliticaEscalamiento{},
+	&entidad.Escalamiento{},
+	&entidad.RotacionGuardia{},
+	&entidad.ReemplazoGuardia{},
+	&entidad.BorradorDifusion{},
+	&entidad.Silenciamiento{},
+	&entidad.EventoReaccion{},
+	&entidad.RespuestaNotificacion{},
+	&entidad.Adjunto{},
+	&entidad.EntradaBuzonSalida{},
+}
+
+// ModelosPlataforma son las tablas que solo existen en la base principal: inquilinos, cuotas,
+// credenciales, marca, SLA, claves, consumo, auditoría, retención, supresiones,
+// enlaces cortos y la cola de mensajes muertos
+var ModelosPlataforma = []any{
+	&entidad.Inquilino{},
+	&entidad.CuotaInquilino{},
+	&entidad.CredencialProveedor{},
+	&entidad.CredencialPlataforma{},
+	&entidad.MarcaInquilino{},
+	&entidad.ObjetivoSLA{},
+	&entidad.ClaveAPI{},
+	&entidad.RegistroConsumo{},
+	&entidad.AccesoNotificacion{},
+	&entidad.CertificadoEliminacion{},
+	&entidad.PoliticaRetencion{},
+	&entidad.EntradaSupresion{},
+	&entidad.EnlaceCorto{},
+	&entidad.EntradaColaMuerta{},
+}
+
+var nombreEsquemaValido = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,62}$`)
+
+// registroActivo es el registro usado por sesion para resolver la región y el esquema del contexto
+var registroActivo atomic.Pointer[RegistroEsquemas]
+
+// RegistroEsquemas mantiene un pool de conexiones por región y esquema de inquilino aislado.
+// Cada pool fija su search_path al abrir, así los repositorios no cambian sus consultas.
+type RegistroEsquemas struct {
+	config     configuracion.ConfiguracionBaseDatos
+	regiones   map[string]configuracion.ConfiguracionBaseDatos
+	mu         sync.Mutex
+	conexiones map[string]*gorm.DB
+	// alConectar configura cada pool al abrirlo, igual que la base principal
+	alConectar func(*gorm.DB) error
+}
+
+// NuevoRegistroEsquemas crea el registro con la base principal y la de cada región de
+// residencia; los pools se abren al primer uso de cada región y esquema
+func NuevoRegistroEsquemas(config configuracion.ConfiguracionBaseDatos, regiones map[string]configuracion.ConfiguracionBaseDatos) *RegistroEsquemas {
+	return &RegistroEsquemas{config: config, regiones: regiones, conexiones: make(map[string]*gorm.DB)}
+}
+
+// HabilitarAislamiento hace que los repositorios usen la región y el esquema del inquilino presentes en el contexto
+func HabilitarAislamiento(registro *RegistroEsquemas) {
+	registroActivo.Store(registro)
+}
+
+// AlConectar aplica configurar a los pools abiertos y a cada uno que se abra después, p. ej.
+// los callbacks que la base principal registra en main
+func (r *RegistroEsquemas) AlConectar(configurar func(*gorm.DB) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.alConectar = configurar
+	for clave, db := range r.conexiones {
+		if err := configurar(db); err != nil {
+			return fmt.Errorf("configurando %s: %w", clave, err)
+		}
+	}
+	return nil
+}
+
+// RegionConfigurada indica si la región tiene una base de datos propia
+func (r *RegistroEsquemas) RegionConfigurada(region string) bool {
+	_, existe := r.regiones[region]
+	return existe
+}
+
+// Conexion retorna el pool del esquema en la base de la región (vacía: la principal), abriéndolo
+// si es la primera vez. Sin esquema retorna las tablas comunes de la región.
+func (r *RegistroEsquemas) Conexion(region, esquema string) (*gorm.DB, error) {
+	if esquema != "" && !nombreEsquemaValido.MatchString(esquema) {
+		return nil, fmt.Errorf("esquema inválido: %q", esquema)
+	}
+	config := r.config
+	if region != "" {
+		regional, existe := r.regiones[region]
+		if !existe {
+			return nil, fmt.Errorf("%w: %q", entidad.ErrRegionNoConfigurada, region)
+		}
+		config = regional
+	} else if esquema == "" {
+		return nil, fmt.Errorf("la base principal no se resuelve desde el registro")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clave := region + "/" + esquema
+	if db, ok := r.conexiones[clave]; ok {
+		return db, nil
+	}
+	if esquema != "" {
+		config.MaxConexiones = config.MaxConexionesEsquema
+	}
+	config.Esquema = esquema
+	db, err := NuevaConexion(config)
+	if err != nil {
+		return nil, fmt.Errorf("conectando a %s: %w", clave, err)
+	}
+	if r.alConectar != nil {
+		if err := r.alConectar(db); err != nil {
+			if sqlDB, errPool := db.DB(); errPool == nil {
+				_ = sqlDB.Close()
+			}
+			return nil, fmt.Errorf("configurando %s: %w", clave, err)
+		}
+	}
+	r.conexiones[clave] = db
+	return db, nil
+}
+
+// Aprovisionar crea el esquema si no existe y migra en él las tablas del inquilino, en la base
+// de la región del contexto. Sin esquema migra las tablas comunes de la región.
+func (r *RegistroEsquemas) Aprovisionar(ctx context.Context, esquema string) error {
+	if r.config.Migracion == configuracion.MigracionSoloLectura {
+		return entidad.ErrEsquemaSoloLectura
+	}
+	db, err := r.Conexion(servicio.RegionDesdeContexto(ctx), esquema)
+	if err != nil {
+		return err
+	}
+	if esquema != "" {
+		// El nombre ya fue validado, por eso puede interpolarse en la sentencia
+		if err := db.WithContext(ctx).Exec(fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", esquema)).Error; err != nil {
+			return fmt.Errorf("creando esquema %s: %w", esquema, err)
+		}
+	}
+	if err := db.WithContext(ctx).AutoMigrate(ModelosInquilino...); err != nil {
+		return fmt.Errorf("migrando esquema %q: %w", esquema, err)
+	}
+	if err := eliminarRestriccionesObsoletas(db.WithContext(ctx)); err != nil {
+		return fmt.Errorf("migrando esquema %q: %w", esquema, err)
+	}
+	return registrarVersion(db.WithContext(ctx))
+}
+
+// MigrarEsquemas aplica las migraciones a las tablas comunes de cada región y a los esquemas
+// de todos los inquilinos aislados
+func (r *RegistroEsquemas) MigrarEsquemas(ctx context.Context, inquilinos []entidad.Inquilino) error {
+	for region := range r.regiones {
+		if err := r.Aprovisionar(servicio.ContextoConRegion(ctx, region), ""); err != nil {
+			return fmt.Errorf("región %s: %w", region, err)
+		}
+	}
+	for i := range inquilinos {
+		if esquema := inquilinos[i].Esquema(); esquema != "" {
+			if err := r.Aprovisionar(servicio.ContextoConRegion(ctx, inquilinos[i].Region), esquema); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
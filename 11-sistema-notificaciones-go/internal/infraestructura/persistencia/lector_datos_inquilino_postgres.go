@@ -0,0 +1,68 @@
+package persistencia
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"gorm.io/gorm"
+)
+
+// LectorDatosInquilinoPostgres implementa LectorDatosInquilino recorriendo cursores de PostgreSQL
+type LectorDatosInquilinoPostgres struct {
+	db *gorm.DB
+}
+
+// NuevoLectorDatosInquilinoPostgres crea una nueva instancia del lector
+func NuevoLectorDatosInquilinoPostgres(db *gorm.DB) *LectorDatosInquilinoPostgres {
+	return &LectorDatosInquilinoPostgres{db: db}
+}
+
+// RecorrerUsuarios recorre los usuarios del inquilino
+func (l *LectorDatosInquilinoPostgres) RecorrerUsuarios(ctx context.Context, inquilinoID uint, procesar func(*entidad.Usuario) error) error {
+	return recorrer(sesion(ctx, l.db).Model(&entidad.Usuario{}).Where("inquilino_id = ?", inquilinoID).Order("id"), procesar)
+}
+
+// RecorrerCanales recorre los canales del inquilino
+func (l *LectorDatosInquilinoPostgres) RecorrerCanales(ctx context.Context, inquilinoID uint, procesar func(*entidad.Canal) error) error {
+	return recorrer(sesion(ctx, l.db).Model(&entidad.Canal{}).Where("inquilino_id = ?", inquilinoID).Order("id"), procesar)
+}
+
+// RecorrerNotificaciones recorre las notificaciones del inquilino
+func (l *LectorDatosInquilinoPostgres) RecorrerNotificaciones(ctx context.Context, inquilinoID uint, procesar func(*entidad.Notificacion) error) error {
+	return recorrer(sesion(ctx, l.db).Model(&entidad.Notificacion{}).Where("inquilino_id = ?", inquilinoID).Order("id"), procesar)
+}
+
+// RecorrerPreferencias recorre las preferencias de los usuarios del inquilino
+func (l *LectorDatosInquilinoPostgres) RecorrerPreferencias(ctx context.Context, inquilinoID uint, procesar func(*entidad.PreferenciaNotificacion) error) error {
+	db := sesion(ctx, l.db)
+	usuarios := db.Session(&gorm.Session{NewDB: true}).Model(&entidad.Usuario{}).Select("id").Where("inquilino_id = ?", inquilinoID)
+	return recorrer(db.Model(&entidad.PreferenciaNotificacion{}).Where("usuario_id IN (?)", usuarios).Order("id"), procesar)
+}
+
+// RecorrerIntentos recorre los intentos de envío de las notificaciones del inquilino
+func (l *LectorDatosInquilinoPostgres) RecorrerIntentos(ctx context.Context, inquilinoID uint, procesar func(*entidad.IntentoEnvio) error) error {
+	db := sesion(ctx, l.db)
+	notificaciones := db.Session(&gorm.Session{NewDB: true}).Model(&entidad.Notificacion{}).Unscoped().Select("id").Where("inquilino_id = ?", inquilinoID)
+	return recorrer(db.Model(&entidad.IntentoEnvio{}).Where("notificacion_id IN (?)", notificaciones).Order("id"), procesar)
+}
+
+// recorrer itera las filas de la consulta manteniendo el uso de memoria constante
+func recorrer[T any](consulta *gorm.DB, procesar func(*T) error) error {
+	filas, err := consulta.Rows()
+	if err != nil {
+		return err
+	}
+	defer filas.Close()
+
+	for filas.Next() {
+		var registro T
+		if err := consulta.ScanRows(filas, &registro); err != nil {
+			return err
+		}
+		if err := procesar(&registro); err != nil {
+			return err
+		}
+	}
+	return filas.Err()
+}
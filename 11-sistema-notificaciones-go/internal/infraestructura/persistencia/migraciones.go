@@ -0,0 +1,321 @@
+package persistencia
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Tipos de migración pendiente
+const (
+	PendienteTabla   = "tabla"
+	PendienteColumna = "columna"
+	PendienteIndice  = "indice"
+)
+
+// versionEsquema registra cada versión del esquema aplicada en una base o esquema de inquilino
+type versionEsquema struct {
+	ID              uint      `gorm:"primaryKey"`
+	Version         string    `gorm:"size:16;not null"`
+	FechaAplicacion time.Time `gorm:"not null"`
+}
+
+// TableName fija el nombre de la tabla de versiones
+func (versionEsquema) TableName() string {
+	return "versiones_esquema"
+}
+
+// MigracionPendiente es una tabla, columna o índice que los modelos esperan y la base no tiene
+type MigracionPendiente struct {
+	Tipo   string `json:"tipo"`
+	Tabla  string `json:"tabla"`
+	Nombre string `json:"nombre,omitempty"`
+}
+
+// EstadoBase es la versión aplicada y lo pendiente en una base: la principal, las tablas comunes
+// de una región o el esquema de un inquilino aislado
+type EstadoBase struct {
+	Region          string               `json:"region,omitempty"`
+	Esquema         string               `json:"esquema,omitempty"`
+	Version         string               `json:"version"`
+	FechaAplicacion *time.Time           `json:"fecha_aplicacion,omitempty"`
+	Pendientes      []MigracionPendiente `json:"pendientes"`
+	AlDia           bool                 `json:"al_dia"`
+}
+
+// EstadoMigraciones compara el esquema que espera esta versión del servicio con el de cada base
+type EstadoMigraciones struct {
+	Modo            string       `json:"modo"`
+	VersionEsperada string       `json:"version_esperada"`
+	AlDia           bool         `json:"al_dia"`
+	Bases           []EstadoBase `json:"bases"`
+}
+
+// Migrador aplica y compara el esquema de la base principal, las regiones y los esquemas aislados
+type Migrador struct {
+	db                   *gorm.DB
+	registro             *RegistroEsquemas
+	repositorioInquilino repositorio.RepositorioInquilino
+	modo                 string
+}
+
+// NuevoMigrador crea el migrador en el modo configurado
+func NuevoMigrador(db *gorm.DB, registro *RegistroEsquemas, repositorioInquilino repositorio.RepositorioInquilino, modo string) *Migrador {
+	return &Migrador{db: db, registro: registro, repositorioInquilino: repositorioInquilino, modo: modo}
+}
+
+// Modo retorna el modo de migración configurado
+func (m *Migrador) Modo() string {
+	return m.modo
+}
+
+// Migrar aplica las migraciones pendientes en la base principal, en las regiones y en los
+// esquemas de los inquilinos aislados, y registra la versión aplicada en cada una
+func (m *Migrador) Migrar(ctx context.Context) error {
+	if m.modo == configuracion.MigracionSoloLectura {
+		return entidad.ErrEsquemaSoloLectura
+	}
+	db := m.db.WithContext(ctx)
+	if err := db.AutoMigrate(append(append([]any{}, ModelosPlataforma...), ModelosInquilino...)...); err != nil {
+		return fmt.Errorf("migrando la base principal: %w", err)
+	}
+	if err := eliminarRestriccionesObsoletas(db); err != nil {
+		return fmt.Errorf("migrando la base principal: %w", err)
+	}
+	if err := registrarVersion(db); err != nil {
+		return err
+	}
+	aislados, err := m.repositorioInquilino.ListarAislados(ctx)
+	if err != nil {
+		return fmt.Errorf("listando inquilinos aislados: %w", err)
+	}
+	return m.registro.MigrarEsquemas(ctx, aislados)
+}
+
+// Estado compara el esquema esperado con el de cada base sin modificarlas
+func (m *Migrador) Estado(ctx context.Context) (*EstadoMigraciones, error) {
+	esperada, err := VersionEsperada(m.db)
+	if err != nil {
+		return nil, err
+	}
+	estado := &EstadoMigraciones{Modo: m.modo, VersionEsperada: esperada, AlDia: true}
+
+	principal, err := estadoBase(m.db.WithContext(ctx), esperada, append(append([]any{}, ModelosPlataforma...), ModelosInquilino...))
+	if err != nil {
+		return nil, fmt.Errorf("base principal: %w", err)
+	}
+	estado.agregar(*principal)
+	if !m.db.WithContext(ctx).Migrator().HasTable(&entidad.Inquilino{}) {
+		// Sin la tabla de inquilinos todavía no puede haber regiones ni esquemas aislados migrados
+		return estado, nil
+	}
+
+	regiones := make([]string, 0, len(m.registro.regiones))
+	for region := range m.registro.regiones {
+		regiones = append(regiones, region)
+	}
+	sort.Strings(regiones)
+	for _, region := range regiones {
+		if err := m.agregarEstado(ctx, estado, region, "", esperada); err != nil {
+			return nil, err
+		}
+	}
+	aislados, err := m.repositorioInquilino.ListarAislados(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listando inquilinos aislados: %w", err)
+	}
+	for i := range aislados {
+		if esquema := aislados[i].Esquema(); esquema != "" {
+			if err := m.agregarEstado(ctx, estado, aislados[i].Region, esquema, esperada); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return estado, nil
+}
+
+// agregarEstado compara las tablas de inquilino en la región y el esquema indicados
+func (m *Migrador) agregarEstado(ctx context.Context, estado *EstadoMigraciones, region, esquema, esperada string) error {
+	db, err := m.registro.Conexion(region, esquema)
+	if err != nil {
+		return err
+	}
+	base, err := estadoBase(db.WithContext(ctx), esperada, ModelosInquilino)
+	if err != nil {
+		return fmt.Errorf("región %q, esquema %q: %w", region, esquema, err)
+	}
+	base.Region, base.Esquema = region, esquema
+	estado.agregar(*base)
+	return nil
+}
+
+func (e *EstadoMigraciones) agregar(base EstadoBase) {
+	e.Bases = append(e.Bases, base)
+	e.AlDia = e.AlDia && base.AlDia
+}
+
+// VersionEsperada identifica el esquema de los modelos de esta versión del servicio: cambia con
+// cualquier tabla, columna, tipo o índice nuevo o modificado
+func VersionEsperada(db *gorm.DB) (string, error) {
+	hash := sha256.New()
+	for _, modelo := range append(append([]any{}, ModelosPlataforma...), ModelosInquilino...) {
+		esquema, err := parsearModelo(db, modelo)
+		if err != nil {
+			return "", err
+		}
+		// Los índices van primero: analizarlos marca como únicas las columnas de un índice único
+		indices := indicesOrdenados(esquema)
+		fmt.Fprintln(hash, esquema.Table)
+		for _, campo := range camposMigrables(esquema) {
+			fmt.Fprintln(hash, campo.DBName, db.Migrator().FullDataTypeOf(campo).SQL)
+		}
+		for _, indice := range indices {
+			fmt.Fprintln(hash, indice.Name, indice.Class, indice.Where, len(indice.Fields))
+		}
+		for _, tabla := range tablasIntermedias(esquema) {
+			fmt.Fprintln(hash, tabla)
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:12], nil
+}
+
+// estadoBase lee la última versión registrada en la base y busca lo que le falta de los modelos
+func estadoBase(db *gorm.DB, esperada string, modelos []any) (*EstadoBase, error) {
+	base := &EstadoBase{Pendientes: []MigracionPendiente{}}
+	migrador := db.Migrator()
+	if migrador.HasTable(&versionEsquema{}) {
+		var ultima versionEsquema
+		err := db.Order("id DESC").Take(&ultima).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("leyendo la versión del esquema: %w", err)
+		}
+		if err == nil {
+			base.Version, base.FechaAplicacion = ultima.Version, &ultima.FechaAplicacion
+		}
+	}
+
+	for _, modelo := range modelos {
+		esquema, err := parsearModelo(db, modelo)
+		if err != nil {
+			return nil, err
+		}
+		if !migrador.HasTable(modelo) {
+			base.Pendientes = append(base.Pendientes, MigracionPendiente{Tipo: PendienteTabla, Tabla: esquema.Table})
+			continue
+		}
+		for _, campo := range camposMigrables(esquema) {
+			if !migrador.HasColumn(modelo, campo.DBName) {
+				base.Pendientes = append(base.Pendientes, MigracionPendiente{Tipo: PendienteColumna, Tabla: esquema.Table, Nombre: campo.DBName})
+			}
+		}
+		for _, indice := range indicesOrdenados(esquema) {
+			if !migrador.HasIndex(modelo, indice.Name) {
+				base.Pendientes = append(base.Pendientes, MigracionPendiente{Tipo: PendienteIndice, Tabla: esquema.Table, Nombre: indice.Name})
+			}
+		}
+		for _, tabla := range tablasIntermedias(esquema) {
+			if !migrador.HasTable(tabla) {
+				base.Pendientes = append(base.Pendientes, MigracionPendiente{Tipo: PendienteTabla, Tabla: tabla})
+			}
+		}
+	}
+	// Sin nada faltante la versión puede diferir igual, p. ej. por un tipo o tamaño de columna cambiado
+	base.AlDia = len(base.Pendientes) == 0 && base.Version == esperada
+	return base, nil
+}
+
+// registrarVersion crea la tabla de versiones si falta y agrega la versión esperada si no es la última
+func registrarVersion(db *gorm.DB) error {
+	version, err := VersionEsperada(db)
+	if err != nil {
+		return err
+	}
+	if err := db.AutoMigrate(&versionEsquema{}); err != nil {
+		return fmt.Errorf("migrando la tabla de versiones: %w", err)
+	}
+	var ultima versionEsquema
+	err = db.Order("id DESC").Take(&ultima).Error
+	if err == nil && ultima.Version == version {
+		return nil
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("leyendo la versión del esquema: %w", err)
+	}
+	return db.Create(&versionEsquema{Version: version, FechaAplicacion: time.Now().UTC()}).Error
+}
+
+// restriccionObsoleta es una restricción que crearon versiones anteriores y el modelo ya no declara
+type restriccionObsoleta struct {
+	modelo any
+	nombre string
+}
+
+// restriccionesObsoletas se eliminan al migrar, porque AutoMigrate no quita restricciones
+var restriccionesObsoletas = []restriccionObsoleta{
+	// Impedía anonimizar notificaciones por retención, que dejan usuario_id en 0
+	{modelo: &entidad.Notificacion{}, nombre: "fk_usuarios_notificaciones"},
+}
+
+// eliminarRestriccionesObsoletas quita de la base las restricciones obsoletas que aún tenga
+func eliminarRestriccionesObsoletas(db *gorm.DB) error {
+	migrador := db.Migrator()
+	for _, restriccion := range restriccionesObsoletas {
+		if !migrador.HasConstraint(restriccion.modelo, restriccion.nombre) {
+			continue
+		}
+		if err := migrador.DropConstraint(restriccion.modelo, restriccion.nombre); err != nil {
+			return fmt.Errorf("eliminando la restricción %s: %w", restriccion.nombre, err)
+		}
+	}
+	return nil
+}
+
+func parsearModelo(db *gorm.DB, modelo any) (*schema.Schema, error) {
+	sentencia := &gorm.Statement{DB: db}
+	if err := sentencia.Parse(modelo); err != nil {
+		return nil, fmt.Errorf("analizando el modelo %T: %w", modelo, err)
+	}
+	return sentencia.Schema, nil
+}
+
+func camposMigrables(esquema *schema.Schema) []*schema.Field {
+	campos := make([]*schema.Field, 0, len(esquema.Fields))
+	for _, campo := range esquema.Fields {
+		if campo.DBName != "" && !campo.IgnoreMigration {
+			campos = append(campos, campo)
+		}
+	}
+	return campos
+}
+
+func indicesOrdenados(esquema *schema.Schema) []schema.Index {
+	indices := make([]schema.Index, 0)
+	for _, indice := range esquema.ParseIndexes() {
+		indices = append(indices, indice)
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i].Name < indices[j].Name })
+	return indices
+}
+
+// tablasIntermedias son las tablas de las relaciones muchos a muchos del modelo
+func tablasIntermedias(esquema *schema.Schema) []string {
+	var tablas []string
+	for _, relacion := range esquema.Relationships.Relations {
+		if relacion.JoinTable != nil {
+			tablas = append(tablas, relacion.JoinTable.Table)
+		}
+	}
+	sort.Strings(tablas)
+	return tablas
+}
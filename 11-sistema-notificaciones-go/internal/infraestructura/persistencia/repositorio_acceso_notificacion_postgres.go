@@ -0,0 +1,151 @@
+package persistencia
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+
+	"gorm.io/gorm"
+)
+
+// bloqueoCadenaAccesos es la clave del advisory lock que serializa los registros de accesos
+const bloqueoCadenaAccesos = 20600
+
+// RepositorioAccesoNotificacionPostgres implementa RepositorioAccesoNotificacion con GORM
+type RepositorioAccesoNotificacionPostgres struct {
+	db *gorm.DB
+	// claveSello sella cada hash nuevo; vacía los deja sin sellar
+	claveSello []byte
+}
+
+// NuevoRepositorioAccesoNotificacionPostgres crea una nueva instancia del repositorio
+func NuevoRepositorioAccesoNotificacionPostgres(db *gorm.DB, claveSello []byte) *RepositorioAccesoNotificacionPostgres {
+	return &RepositorioAccesoNotificacionPostgres{db: db, claveSello: claveSello}
+}
+
+// Registrar agrega un acceso enlazado con el último. El bloqueo se mantiene hasta el commit para
+// que dos registros concurrentes no enlacen con el mismo anterior.
+func (r *RepositorioAccesoNotificacionPostgres) Registrar(ctx context.Context, acceso *entidad.AccesoNotificacion) error {
+	return sesionPlataforma(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", bloqueoCadenaAccesos).Error; err != nil {
+			return err
+		}
+		var ultimos []string
+		err := tx.Model(&entidad.AccesoNotificacion{}).
+			Where("hash <> ''").
+			Order("id DESC").
+			Limit(1).
+			Pluck("hash", &ultimos).Error
+		if err != nil {
+			return err
+		}
+
+		var anterior string
+		if len(ultimos) > 0 {
+			anterior = ultimos[0]
+		}
+		acceso.Encadenar(anterior, r.claveSello)
+		return tx.Create(acceso).Error
+	})
+}
+
+// Listar obtiene una página de accesos ordenada del más reciente al más antiguo
+func (r *RepositorioAccesoNotificacionPostgres) Listar(ctx context.Context, filtro repositorio.FiltroAccesos) ([]entidad.AccesoNotificacion, error) {
+	consulta := sesionPlataforma(ctx, r.db).Order("id DESC")
+	if filtro.InquilinoID != 0 {
+		consulta = consulta.Where("inquilino_id = ?", filtro.InquilinoID)
+	}
+	if filtro.ActorID != 0 {
+		consulta = consulta.Where("actor_id = ?", filtro.ActorID)
+	}
+	if filtro.UsuarioID != 0 {
+		consulta = consulta.Where("usuario_id = ?", filtro.UsuarioID)
+	}
+	if !filtro.Desde.IsZero() {
+		consulta = consulta.Where("fecha >= ?", filtro.Desde)
+	}
+	if !filtro.Hasta.IsZero() {
+		consulta = consulta.Where("fecha < ?", filtro.Hasta)
+	}
+	if filtro.DesdeID != 0 {
+		consulta = consulta.Where("id >= ?", filtro.DesdeID)
+	}
+	if filtro.HastaID != 0 {
+		consulta = consulta.Where("id <= ?", filtro.HastaID)
+	}
+	if filtro.Cursor != 0 {
+		consulta = consulta.Where("id < ?", filtro.Cursor)
+	}
+	if filtro.Limite > 0 {
+		consulta = consulta.Limit(filtro.Limite)
+	}
+
+	var accesos []entidad.AccesoNotificacion
+	err := consulta.Find(&accesos).Error
+	return accesos, err
+}
+
+// RangoIDs ubica los extremos del tramo. Las fechas no siguen el orden de la cadena cuando los
+// relojes de las instancias difieren, por eso solo sirven para ubicarlos.
+func (r *RepositorioAccesoNotificacionPostgres) RangoIDs(ctx context.Context, filtro repositorio.FiltroAccesos) (uint, uint, error) {
+	consulta := sesionPlataforma(ctx, r.db).Model(&entidad.AccesoNotificacion{})
+	if !filtro.Desde.IsZero() {
+		consulta = consulta.Where("fecha >= ?", filtro.Desde)
+	}
+	if !filtro.Hasta.IsZero() {
+		consulta = consulta.Where("fecha < ?", filtro.Hasta)
+	}
+	if filtro.DesdeID != 0 {
+		consulta = consulta.Where("id >= ?", filtro.DesdeID)
+	}
+	if filtro.HastaID != 0 {
+		consulta = consulta.Where("id <= ?", filtro.HastaID)
+	}
+
+	var rango struct {
+		Primero uint
+		Ultimo  uint
+	}
+	err := consulta.Select("COALESCE(MIN(id), 0) AS primero, COALESCE(MAX(id), 0) AS ultimo").Scan(&rango).Error
+	return rango.Primero, rango.Ultimo, err
+}
+
+// Previo obtiene el acceso con el que debe enlazar el primero del tramo que empieza en id
+func (r *RepositorioAccesoNotificacionPostgres) Previo(ctx context.Context, id uint) (*entidad.AccesoNotificacion, error) {
+	var previos []entidad.AccesoNotificacion
+	err := sesionPlataforma(ctx, r.db).
+		Where("id < ? AND hash <> ''", id).
+		Order("id DESC").
+		Limit(1).
+		Find(&previos).Error
+	if err != nil || len(previos) == 0 {
+		return nil, err
+	}
+	return &previos[0], nil
+}
+
+// Recorrer procesa el tramo por ID ascendente, que es el orden de la cadena, sin saltear
+// ningún acceso aunque su fecha quede fuera del rango pedido
+func (r *RepositorioAccesoNotificacionPostgres) Recorrer(ctx context.Context, primero, ultimo uint, procesar func(*entidad.AccesoNotificacion) error) error {
+	filas, err := sesionPlataforma(ctx, r.db).
+		Model(&entidad.AccesoNotificacion{}).
+		Where("id BETWEEN ? AND ?", primero, ultimo).
+		Order("id ASC").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer filas.Close()
+
+	for filas.Next() {
+		var acceso entidad.AccesoNotificacion
+		if err := r.db.ScanRows(filas, &acceso); err != nil {
+			return err
+		}
+		if err := procesar(&acceso); err != nil {
+			return err
+		}
+	}
+	return filas.Err()
+}
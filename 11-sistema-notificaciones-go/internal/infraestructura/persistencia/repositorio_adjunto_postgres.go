@@ -0,0 +1,73 @@
+package persistencia
+
+import (
+	"context"
+	"errors"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"gorm.io/gorm"
+)
+
+// RepositorioAdjuntoPostgres implementa RepositorioAdjunto con GORM
+type RepositorioAdjuntoPostgres struct {
+	db *gorm.DB
+}
+
+// NuevoRepositorioAdjuntoPostgres crea una nueva instancia del repositorio
+func NuevoRepositorioAdjuntoPostgres(db *gorm.DB) *RepositorioAdjuntoPostgres {
+	return &RepositorioAdjuntoPostgres{db: db}
+}
+
+// Crear guarda los datos del adjunto
+func (r *RepositorioAdjuntoPostgres) Crear(ctx context.Context, adjunto *entidad.Adjunto) error {
+	return sesion(ctx, r.db).Create(adjunto).Error
+}
+
+// ObtenerPorID obtiene el adjunto por su ID
+func (r *RepositorioAdjuntoPostgres) ObtenerPorID(ctx context.Context, id uint) (*entidad.Adjunto, error) {
+	var adjunto entidad.Adjunto
+	err := sesion(ctx, r.db).Take(&adjunto, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entidad.ErrAdjuntoNoEncontrado
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &adjunto, nil
+}
+
+// ListarPorIDs obtiene los adjuntos existentes entre los IDs indicados
+func (r *RepositorioAdjuntoPostgres) ListarPorIDs(ctx context.Context, ids []uint) ([]entidad.Adjunto, error) {
+	var adjuntos []entidad.Adjunto
+	err := sesion(ctx, r.db).Where("id IN ?", ids).Order("id").Find(&adjuntos).Error
+	return adjuntos, err
+}
+
+// ListarPorNotificacion obtiene los adjuntos de la notificación en el orden en que se subieron
+func (r *RepositorioAdjuntoPostgres) ListarPorNotificacion(ctx context.Context, notificacionID uint) ([]entidad.Adjunto, error) {
+	var adjuntos []entidad.Adjunto
+	err := sesion(ctx, r.db).Where("notificacion_id = ?", notificacionID).Order("id").Find(&adjuntos).Error
+	return adjuntos, err
+}
+
+// Vincular asigna la notificación con un UPDATE condicionado, así dos notificaciones no pueden
+// quedarse con el mismo adjunto
+func (r *RepositorioAdjuntoPostgres) Vincular(ctx context.Context, inquilinoID uint, ids []uint, notificacionID uint) (int64, error) {
+	resultado := sesion(ctx, r.db).Model(&entidad.Adjunto{}).
+		Where("id IN ? AND inquilino_id = ? AND notificacion_id IS NULL", ids, inquilinoID).
+		Update("notificacion_id", notificacionID)
+	return resultado.RowsAffected, resultado.Error
+}
+
+// Eliminar borra los datos del adjunto
+func (r *RepositorioAdjuntoPostgres) Eliminar(ctx context.Context, id uint) error {
+	resultado := sesion(ctx, r.db).Delete(&entidad.Adjunto{}, id)
+	if resultado.Error != nil {
+		return resultado.Error
+	}
+	if resultado.RowsAffected == 0 {
+		return entidad.ErrAdjuntoNoEncontrado
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package persistencia
+
+import (
+	"context"
+	"errors"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"gorm.io/gorm"
+)
+
+// RepositorioBorradorDifusionPostgres implementa RepositorioBorradorDifusion con GORM
+type RepositorioBorradorDifusionPostgres struct {
+	db *gorm.DB
+}
+
+// NuevoRepositorioBorradorDifusionPostgres crea una nueva instancia del repositorio
+func NuevoRepositorioBorradorDifusionPostgres(db *gorm.DB) *RepositorioBorradorDifusionPostgres {
+	return &RepositorioBorradorDifusionPostgres{db: db}
+}
+
+// Crear inserta un borrador
+func (r *RepositorioBorradorDifusionPostgres) Crear(ctx context.Context, borrador *entidad.BorradorDifusion) error {
+	return sesion(ctx, r.db).Create(borrador).Error
+}
+
+// ObtenerPorID obtiene un borrador por su ID
+func (r *RepositorioBorradorDifusionPostgres) ObtenerPorID(ctx context.Context, id uint) (*entidad.BorradorDifusion, error) {
+	var borrador entidad.BorradorDifusion
+	err := sesion(ctx, r.db).First(&borrador, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entidad.ErrBorradorDifusionNoEncontrado
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &borrador, nil
+}
+
+// ListarPorCanal obtiene los borradores del canal, los más recientes primero
+func (r *RepositorioBorradorDifusionPostgres) ListarPorCanal(ctx context.Context, canalID uint, estado entidad.EstadoBorradorDifusion) ([]entidad.BorradorDifusion, error) {
+	consulta := sesion(ctx, r.db).Where("canal_id = ?", canalID)
+	if estado != "" {
+		consulta = consulta.Where("estado = ?", estado)
+	}
+	var borradores []entidad.BorradorDifusion
+	err := consulta.Order("id DESC").Find(&borradores).Error
+	return borradores, err
+}
+
+// Actualizar guarda todos los campos del borrador si su estado no cambió desde que se leyó
+func (r *RepositorioBorradorDifusionPostgres) Actualizar(ctx context.Context, borrador *entidad.BorradorDifusion, estadoAnterior entidad.EstadoBorradorDifusion) (bool, error) {
+	resultado := sesion(ctx, r.db).Model(borrador).
+		Where("estado = ?", estadoAnterior).
+		Select("*").Omit("id", "inquilino_id", "canal_id", "fecha_creacion").
+		Updates(borrador)
+	return resultado.RowsAffected == 1, resultado.Error
+}
@@ -0,0 +1,58 @@
+package persistencia
+
+import (
+	"context"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RepositorioBuzonSalidaPostgres implementa RepositorioBuzonSalida con GORM
+type RepositorioBuzonSalidaPostgres struct {
+	db *gorm.DB
+}
+
+// NuevoRepositorioBuzonSalidaPostgres crea una nueva instancia del repositorio
+func NuevoRepositorioBuzonSalidaPostgres(db *gorm.DB) *RepositorioBuzonSalidaPostgres {
+	return &RepositorioBuzonSalidaPostgres{db: db}
+}
+
+// Agregar inserta las entradas; las que ya existían se conservan
+func (r *RepositorioBuzonSalidaPostgres) Agregar(ctx context.Context, entradas []*entidad.EntradaBuzonSalida) error {
+	if len(entradas) == 0 {
+		return nil
+	}
+	return sesion(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(entradas).Error
+}
+
+// ListarAnteriores obtiene las entradas escritas hasta la fecha en orden de escritura
+func (r *RepositorioBuzonSalidaPostgres) ListarAnteriores(ctx context.Context, hasta time.Time, limite int) ([]entidad.EntradaBuzonSalida, error) {
+	var entradas []entidad.EntradaBuzonSalida
+	err := sesion(ctx, r.db).
+		Where("fecha <= ?", hasta).
+		Order("fecha, notificacion_id").
+		Limit(limite).
+		Find(&entradas).Error
+	return entradas, err
+}
+
+// Eliminar borra las entradas de las notificaciones indicadas
+func (r *RepositorioBuzonSalidaPostgres) Eliminar(ctx context.Context, notificacionIDs []uint) error {
+	if len(notificacionIDs) == 0 {
+		return nil
+	}
+	return sesion(ctx, r.db).Where("notificacion_id IN ?", notificacionIDs).Delete(&entidad.EntradaBuzonSalida{}).Error
+}
+
+// Aplazar lleva las entradas de las notificaciones indicadas a la fecha dada
+func (r *RepositorioBuzonSalidaPostgres) Aplazar(ctx context.Context, notificacionIDs []uint, fecha time.Time) error {
+	if len(notificacionIDs) == 0 {
+		return nil
+	}
+	return sesion(ctx, r.db).Model(&entidad.EntradaBuzonSalida{}).
+		Where("notificacion_id IN ?", notificacionIDs).
+		Update("fecha", fecha).Error
+}
@@ -0,0 +1,123 @@
+package persistencia
+
+import (
+	"context"
+	"errors"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RepositorioCanalPostgres implementa RepositorioCanal con GORM
+type RepositorioCanalPostgres struct {
+	db *gorm.DB
+}
+
+// NuevoRepositorioCanalPostgres crea una nueva instancia del repositorio
+func NuevoRepositorioCanalPostgres(db *gorm.DB) *RepositorioCanalPostgres {
+	return &RepositorioCanalPostgres{db: db}
+}
+
+// Crear persiste un nuevo canal
+func (r *RepositorioCanalPostgres) Crear(ctx context.Context, canal *entidad.Canal) error {
+	return sesion(ctx, r.db).Omit(clause.Associations).Create(canal).Error
+}
+
+// ObtenerPorID obtiene un canal por su ID
+func (r *RepositorioCanalPostgres) ObtenerPorID(ctx context.Context, id uint) (*entidad.Canal, error) {
+	var canal entidad.Canal
+	err := sesion(ctx, r.db).First(&canal, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entidad.ErrCanalNoEncontrado
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &canal, nil
+}
+
+// Actualizar guarda los cambios de un canal
+func (r *RepositorioCanalPostgres) Actualizar(ctx context.Context, canal *entidad.Canal) error {
+	return sesion(ctx, r.db).Omit(clause.Associations).Save(canal).Error
+}
+
+// ListarIDsSuscriptores pagina los suscriptores confirmados por cursor de ID (sin OFFSET)
+func (r *RepositorioCanalPostgres) ListarIDsSuscriptores(ctx context.Context, canalID, desdeID uint, limite int) ([]uint, error) {
+	var ids []uint
+	err := sesion(ctx, r.db).
+		Table("usuario_canales").
+		Where("canal_id = ? AND usuario_id > ? AND estado = ?", canalID, desdeID, entidad.SuscripcionConfirmada).
+		Order("usuario_id").
+		Limit(limite).
+		Pluck("usuario_id", &ids).Error
+	return ids, err
+}
+
+// ContarSuscriptores cuenta los usuarios con suscripción confirmada a un canal
+func (r *RepositorioCanalPostgres) ContarSuscriptores(ctx context.Context, canalID uint) (int64, error) {
+	var total int64
+	err := sesion(ctx, r.db).
+		Table("usuario_canales").
+		Where("canal_id = ? AND estado = ?", canalID, entidad.SuscripcionConfirmada).
+		Count(&total).Error
+	return total, err
+}
+
+// DesvincularUsuario borra las filas de usuario_canales del usuario
+func (r *RepositorioCanalPostgres) DesvincularUsuario(ctx context.Context, usuarioID uint) (int64, error) {
+	resultado := sesion(ctx, r.db).Exec("DELETE FROM usuario_canales WHERE usuario_id = ?", usuarioID)
+	return resultado.RowsAffected, resultado.Error
+}
+
+// ObtenerSuscripcion obtiene la suscripción del usuario al canal
+func (r *RepositorioCanalPostgres) ObtenerSuscripcion(ctx context.Context, usuarioID, canalID uint) (*entidad.SuscripcionCanal, error) {
+	var suscripcion entidad.SuscripcionCanal
+	err := sesion(ctx, r.db).Where("usuario_id = ? AND canal_id = ?", usuarioID, canalID).First(&suscripcion).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entidad.ErrSuscripcionNoEncontrada
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &suscripcion, nil
+}
+
+// ListarSuscripciones obtiene las suscripciones del usuario, pendientes y confirmadas
+func (r *RepositorioCanalPostgres) ListarSuscripciones(ctx context.Context, usuarioID uint) ([]entidad.SuscripcionCanal, error) {
+	var suscripciones []entidad.SuscripcionCanal
+	err := sesion(ctx, r.db).Where("usuario_id = ?", usuarioID).Order("canal_id").Find(&suscripciones).Error
+	return suscripciones, err
+}
+
+// ListarMembresias obtiene las membresías del canal con usuario mayor a desdeID
+func (r *RepositorioCanalPostgres) ListarMembresias(ctx context.Context, canalID uint, estado entidad.EstadoSuscripcion, desdeID uint, limite int) ([]entidad.SuscripcionCanal, error) {
+	consulta := sesion(ctx, r.db).Where("canal_id = ? AND usuario_id > ?", canalID, desdeID)
+	if estado != "" {
+		consulta = consulta.Where("estado = ?", estado)
+	}
+	var membresias []entidad.SuscripcionCanal
+	err := consulta.Order("usuario_id").Limit(limite).Find(&membresias).Error
+	return membresias, err
+}
+
+// GuardarSuscripcion inserta la suscripción o actualiza su estado si ya existía
+func (r *RepositorioCanalPostgres) GuardarSuscripcion(ctx context.Context, suscripcion *entidad.SuscripcionCanal) error {
+	return sesion(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "usuario_id"}, {Name: "canal_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"estado", "fecha_confirmacion", "invitado_por", "resuelto_por", "fecha_resolucion"}),
+	}).Create(suscripcion).Error
+}
+
+// EliminarSuscripcion quita al usuario del canal
+func (r *RepositorioCanalPostgres) EliminarSuscripcion(ctx context.Context, usuarioID, canalID uint) error {
+	resultado := sesion(ctx, r.db).Where("usuario_id = ? AND canal_id = ?", usuarioID, canalID).Delete(&entidad.SuscripcionCanal{})
+	if resultado.Error != nil {
+		return resultado.Error
+	}
+	if resultado.RowsAffected == 0 {
+		return entidad.ErrSuscripcionNoEncontrada
+	}
+	return nil
+}
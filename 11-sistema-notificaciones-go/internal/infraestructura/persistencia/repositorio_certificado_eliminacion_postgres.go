@@ -0,0 +1,43 @@
+package persistencia
+
+import (
+	"context"
+	"errors"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"gorm.io/gorm"
+)
+
+// RepositorioCertificadoEliminacionPostgres implementa RepositorioCertificadoEliminacion con GORM
+type RepositorioCertificadoEliminacionPostgres struct {
+	db *gorm.DB
+}
+
+// NuevoRepositorioCertificadoEliminacionPostgres crea una nueva instancia del repositorio
+func NuevoRepositorioCertificadoEliminacionPostgres(db *gorm.DB) *RepositorioCertificadoEliminacionPostgres {
+	return &RepositorioCertificadoEliminacionPostgres{db: db}
+}
+
+// Crear persiste un nuevo certificado
+func (r *RepositorioCertificadoEliminacionPostgres) Crear(ctx context.Context, certificado *entidad.CertificadoEliminacion) error {
+	return sesionPlataforma(ctx, r.db).Create(certificado).Error
+}
+
+// Actualizar guarda el avance o el resultado del certificado
+func (r *RepositorioCertificadoEliminacionPostgres) Actualizar(ctx context.Context, certificado *entidad.CertificadoEliminacion) error {
+	return sesionPlataforma(ctx, r.db).Save(certificado).Error
+}
+
+// ObtenerUltimo obtiene el certificado más reciente del usuario
+func (r *RepositorioCertificadoEliminacionPostgres) ObtenerUltimo(ctx context.Context, usuarioID uint) (*entidad.CertificadoEliminacion, error) {
+	var certificado entidad.CertificadoEliminacion
+	err := sesionPlataforma(ctx, r.db).Where("usuario_id = ?", usuarioID).Order("id DESC").First(&certificado).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entidad.ErrCertificadoNoEncontrado
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &certificado, nil
+}
@@ -0,0 +1,63 @@
+package persistencia
+
+import (
+	"context"
+	"errors"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"gorm.io/gorm"
+)
+
+// RepositorioClaveAPIPostgres implementa RepositorioClaveAPI con GORM
+type RepositorioClaveAPIPostgres struct {
+	db *gorm.DB
+}
+
+// NuevoRepositorioClaveAPIPostgres crea una nueva instancia del repositorio
+func NuevoRepositorioClaveAPIPostgres(db *gorm.DB) *RepositorioClaveAPIPostgres {
+	return &RepositorioClaveAPIPostgres{db: db}
+}
+
+// Crear persiste una nueva clave
+func (r *RepositorioClaveAPIPostgres) Crear(ctx context.Context, clave *entidad.ClaveAPI) error {
+	return sesionPlataforma(ctx, r.db).Create(clave).Error
+}
+
+// ObtenerPorID obtiene una clave por su ID
+func (r *RepositorioClaveAPIPostgres) ObtenerPorID(ctx context.Context, id uint) (*entidad.ClaveAPI, error) {
+	return r.obtener(sesionPlataforma(ctx, r.db).Where("id = ?", id))
+}
+
+// ObtenerPorPrefijo obtiene la clave con el prefijo dado
+func (r *RepositorioClaveAPIPostgres) ObtenerPorPrefijo(ctx context.Context, prefijo string) (*entidad.ClaveAPI, error) {
+	return r.obtener(sesionPlataforma(ctx, r.db).Where("prefijo = ?", prefijo))
+}
+
+// Listar retorna las claves del inquilino, o todas si inquilinoID es 0
+func (r *RepositorioClaveAPIPostgres) Listar(ctx context.Context, inquilinoID uint) ([]entidad.ClaveAPI, error) {
+	consulta := sesionPlataforma(ctx, r.db).Order("id")
+	if inquilinoID != 0 {
+		consulta = consulta.Where("inquilino_id = ?", inquilinoID)
+	}
+	var claves []entidad.ClaveAPI
+	err := consulta.Find(&claves).Error
+	return claves, err
+}
+
+// Actualizar persiste los cambios de la clave
+func (r *RepositorioClaveAPIPostgres) Actualizar(ctx context.Context, clave *entidad.ClaveAPI) error {
+	return sesionPlataforma(ctx, r.db).Save(clave).Error
+}
+
+func (r *RepositorioClaveAPIPostgres) obtener(consulta *gorm.DB) (*entidad.ClaveAPI, error) {
+	var clave entidad.ClaveAPI
+	err := consulta.First(&clave).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entidad.ErrClaveAPINoEncontrada
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &clave, nil
+}
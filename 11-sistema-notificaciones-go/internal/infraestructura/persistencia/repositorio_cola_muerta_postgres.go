@@ -0,0 +1,69 @@
+package persistencia
+
+import (
+	"context"
+	"errors"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RepositorioColaMuertaPostgres implementa RepositorioColaMuerta con GORM
+type RepositorioColaMuertaPostgres struct {
+	db *gorm.DB
+}
+
+// NuevoRepositorioColaMuertaPostgres crea una nueva instancia del repositorio
+func NuevoRepositorioColaMuertaPostgres(db *gorm.DB) *RepositorioColaMuertaPostgres {
+	return &RepositorioColaMuertaPostgres{db: db}
+}
+
+// Agregar inserta la entrada o actualiza la del par (inquilino, notificación)
+func (r *RepositorioColaMuertaPostgres) Agregar(ctx context.Context, entrada *entidad.EntradaColaMuerta) error {
+	return sesionPlataforma(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "inquilino_id"}, {Name: "notificacion_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"intentos", "ultimo_error", "fecha"}),
+	}).Create(entrada).Error
+}
+
+// Listar obtiene una página de entradas ordenada de la más reciente a la más antigua
+func (r *RepositorioColaMuertaPostgres) Listar(ctx context.Context, filtro repositorio.FiltroColaMuerta) ([]entidad.EntradaColaMuerta, error) {
+	consulta := sesionPlataforma(ctx, r.db).Order("id DESC")
+	if filtro.InquilinoID != 0 {
+		consulta = consulta.Where("inquilino_id = ?", filtro.InquilinoID)
+	}
+	if filtro.Tipo != "" {
+		consulta = consulta.Where("tipo = ?", filtro.Tipo)
+	}
+	if filtro.Cursor != 0 {
+		consulta = consulta.Where("id < ?", filtro.Cursor)
+	}
+	if filtro.Limite > 0 {
+		consulta = consulta.Limit(filtro.Limite)
+	}
+
+	var entradas []entidad.EntradaColaMuerta
+	err := consulta.Find(&entradas).Error
+	return entradas, err
+}
+
+// ObtenerPorID obtiene una entrada por su ID
+func (r *RepositorioColaMuertaPostgres) ObtenerPorID(ctx context.Context, id uint) (*entidad.EntradaColaMuerta, error) {
+	var entrada entidad.EntradaColaMuerta
+	err := sesionPlataforma(ctx, r.db).First(&entrada, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entidad.ErrEntradaColaMuertaNoEncontrada
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entrada, nil
+}
+
+// Eliminar borra la entrada
+func (r *RepositorioColaMuertaPostgres) Eliminar(ctx context.Context, id uint) error {
+	return sesionPlataforma(ctx, r.db).Delete(&entidad.EntradaColaMuerta{}, id).Error
+}
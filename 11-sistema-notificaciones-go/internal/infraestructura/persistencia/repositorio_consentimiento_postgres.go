@@ -0,0 +1,48 @@
+package persistencia
+
+import (
+	"context"
+	"errors"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"gorm.io/gorm"
+)
+
+// RepositorioConsentimientoPostgres implementa RepositorioConsentimiento con GORM
+type RepositorioConsentimientoPostgres struct {
+	db *gorm.DB
+}
+
+// NuevoRepositorioConsentimientoPostgres crea una nueva instancia del repositorio
+func NuevoRepositorioConsentimientoPostgres(db *gorm.DB) *RepositorioConsentimientoPostgres {
+	return &RepositorioConsentimientoPostgres{db: db}
+}
+
+// Registrar agrega una decisión al historial
+func (r *RepositorioConsentimientoPostgres) Registrar(ctx context.Context, consentimiento *entidad.Consentimiento) error {
+	return sesion(ctx, r.db).Create(consentimiento).Error
+}
+
+// ObtenerVigente obtiene la decisión más reciente entre la del canal y la general del propósito
+func (r *RepositorioConsentimientoPostgres) ObtenerVigente(ctx context.Context, usuarioID uint, proposito entidad.PropositoConsentimiento, canalID uint) (*entidad.Consentimiento, error) {
+	var consentimiento entidad.Consentimiento
+	err := sesion(ctx, r.db).
+		Where("usuario_id = ? AND proposito = ? AND canal_id IN ?", usuarioID, proposito, []uint{0, canalID}).
+		Order("fecha DESC, id DESC").
+		First(&consentimiento).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &consentimiento, nil
+}
+
+// ListarPorUsuario lista el historial de consentimientos del usuario
+func (r *RepositorioConsentimientoPostgres) ListarPorUsuario(ctx context.Context, usuarioID uint) ([]entidad.Consentimiento, error) {
+	var consentimientos []entidad.Consentimiento
+	err := sesion(ctx, r.db).Where("usuario_id = ?", usuarioID).Order("fecha DESC, id DESC").Find(&consentimientos).Error
+	return consentimientos, err
+}
@@ -0,0 +1,46 @@
+package persistencia
+
+import (
+	"context"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RepositorioConsumoPostgres implementa RepositorioConsumo con GORM
+type RepositorioConsumoPostgres struct {
+	db *gorm.DB
+}
+
+// NuevoRepositorioConsumoPostgres crea una nueva instancia del repositorio
+func NuevoRepositorioConsumoPostgres(db *gorm.DB) *RepositorioConsumoPostgres {
+	return &RepositorioConsumoPostgres{db: db}
+}
+
+// Registrar inserta la medición ignorando duplicados por notificación
+func (r *RepositorioConsumoPostgres) Registrar(ctx context.Context, registro *entidad.RegistroConsumo) error {
+	return sesionPlataforma(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "notificacion_id"}},
+		DoNothing: true,
+	}).Create(registro).Error
+}
+
+// Resumir agrega envíos y unidades por inquilino, tipo y proveedor
+func (r *RepositorioConsumoPostgres) Resumir(ctx context.Context, desde, hasta time.Time, inquilinoID uint) ([]entidad.LineaFacturacion, error) {
+	consulta := sesionPlataforma(ctx, r.db).
+		Model(&entidad.RegistroConsumo{}).
+		Select("inquilino_id, tipo, proveedor, COUNT(*) AS envios, SUM(unidades) AS unidades").
+		Where("fecha >= ? AND fecha < ?", desde, hasta).
+		Group("inquilino_id, tipo, proveedor").
+		Order("inquilino_id, tipo, proveedor")
+	if inquilinoID != 0 {
+		consulta = consulta.Where("inquilino_id = ?", inquilinoID)
+	}
+
+	var lineas []entidad.LineaFacturacion
+	err := consulta.Scan(&lineas).Error
+	return lineas, err
+}
@@ -0,0 +1,89 @@
+package persistencia
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RepositorioCredencialPlataformaPostgres implementa RepositorioCredencialPlataforma con GORM
+type RepositorioCredencialPlataformaPostgres struct {
+	db *gorm.DB
+}
+
+// NuevoRepositorioCredencialPlataformaPostgres crea una nueva instancia del repositorio
+func NuevoRepositorioCredencialPlataformaPostgres(db *gorm.DB) *RepositorioCredencialPlataformaPostgres {
+	return &RepositorioCredencialPlataformaPostgres{db: db}
+}
+
+// Listar obtiene todos los juegos de credenciales
+func (r *RepositorioCredencialPlataformaPostgres) Listar(ctx context.Context) ([]entidad.CredencialPlataforma, error) {
+	var credenciales []entidad.CredencialPlataforma
+	err := sesionPlataforma(ctx, r.db).Order("proveedor, nombre").Find(&credenciales).Error
+	return credenciales, err
+}
+
+// ObtenerActiva obtiene el juego activo del proveedor
+func (r *RepositorioCredencialPlataformaPostgres) ObtenerActiva(ctx context.Context, proveedor string) (*entidad.CredencialPlataforma, error) {
+	var credencial entidad.CredencialPlataforma
+	err := sesionPlataforma(ctx, r.db).Where("proveedor = ? AND activa", proveedor).First(&credencial).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &credencial, nil
+}
+
+// Guardar inserta el juego o reemplaza los datos del par (proveedor, nombre) sin cambiar si está activo
+func (r *RepositorioCredencialPlataformaPostgres) Guardar(ctx context.Context, credencial *entidad.CredencialPlataforma) error {
+	return sesionPlataforma(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "proveedor"}, {Name: "nombre"}},
+		DoUpdates: clause.AssignmentColumns([]string{"datos_cifrados", "fecha_actualizacion"}),
+	}).Create(credencial).Error
+}
+
+// Activar desactiva el juego activo del proveedor y activa el indicado en una transacción; los
+// juegos del proveedor se bloquean para que dos rotaciones simultáneas no dejen dos activos
+func (r *RepositorioCredencialPlataformaPostgres) Activar(ctx context.Context, proveedor, nombre string, fecha time.Time) error {
+	return sesionPlataforma(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var credenciales []entidad.CredencialPlataforma
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("proveedor = ?", proveedor).Find(&credenciales).Error; err != nil {
+			return err
+		}
+		if !slices.ContainsFunc(credenciales, func(c entidad.CredencialPlataforma) bool { return c.Nombre == nombre }) {
+			return entidad.ErrCredencialPlataformaNoEncontrada
+		}
+		if err := tx.Model(&entidad.CredencialPlataforma{}).
+			Where("proveedor = ? AND nombre <> ? AND activa", proveedor, nombre).
+			Update("activa", false).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entidad.CredencialPlataforma{}).
+			Where("proveedor = ? AND nombre = ? AND NOT activa", proveedor, nombre).
+			Updates(map[string]any{"activa": true, "fecha_activacion": fecha}).Error
+	})
+}
+
+// Eliminar borra el juego si no está activo
+func (r *RepositorioCredencialPlataformaPostgres) Eliminar(ctx context.Context, proveedor, nombre string) error {
+	var credencial entidad.CredencialPlataforma
+	err := sesionPlataforma(ctx, r.db).Where("proveedor = ? AND nombre = ?", proveedor, nombre).First(&credencial).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entidad.ErrCredencialPlataformaNoEncontrada
+	}
+	if err != nil {
+		return err
+	}
+	if credencial.Activa {
+		return entidad.ErrCredencialPlataformaActiva
+	}
+	return sesionPlataforma(ctx, r.db).Where("id = ? AND NOT activa", credencial.ID).Delete(&entidad.CredencialPlataforma{}).Error
+}
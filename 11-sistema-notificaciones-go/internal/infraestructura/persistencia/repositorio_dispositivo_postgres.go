@@ -0,0 +1,54 @@
+package persistencia
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RepositorioDispositivoPostgres implementa RepositorioDispositivo con GORM
+type RepositorioDispositivoPostgres struct {
+	db *gorm.DB
+}
+
+// NuevoRepositorioDispositivoPostgres crea una nueva instancia del repositorio
+func NuevoRepositorioDispositivoPostgres(db *gorm.DB) *RepositorioDispositivoPostgres {
+	return &RepositorioDispositivoPostgres{db: db}
+}
+
+// ListarPorUsuario obtiene los tokens push de un usuario
+func (r *RepositorioDispositivoPostgres) ListarPorUsuario(ctx context.Context, usuarioID uint) ([]entidad.DispositivoPush, error) {
+	var dispositivos []entidad.DispositivoPush
+	err := sesion(ctx, r.db).Where("usuario_id = ?", usuarioID).Find(&dispositivos).Error
+	return dispositivos, err
+}
+
+// Guardar registra el token; uno existente pasa al usuario, p. ej. si otra persona inicia
+// sesión en el mismo navegador
+func (r *RepositorioDispositivoPostgres) Guardar(ctx context.Context, dispositivo *entidad.DispositivoPush) error {
+	return sesion(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "token"}},
+		DoUpdates: clause.AssignmentColumns([]string{"usuario_id", "plataforma", "clave_p256dh", "clave_auth", "fecha_actualizacion"}),
+	}).Create(dispositivo).Error
+}
+
+// EliminarPorToken borra el token si pertenece al usuario
+func (r *RepositorioDispositivoPostgres) EliminarPorToken(ctx context.Context, usuarioID uint, token string) error {
+	resultado := sesion(ctx, r.db).Where("usuario_id = ? AND token = ?", usuarioID, token).Delete(&entidad.DispositivoPush{})
+	if resultado.Error != nil {
+		return resultado.Error
+	}
+	if resultado.RowsAffected == 0 {
+		return entidad.ErrDispositivoNoEncontrado
+	}
+	return nil
+}
+
+// EliminarPorUsuario borra los tokens push del usuario
+func (r *RepositorioDispositivoPostgres) EliminarPorUsuario(ctx context.Context, usuarioID uint) (int64, error) {
+	resultado := sesion(ctx, r.db).Where("usuario_id = ?", usuarioID).Delete(&entidad.DispositivoPush{})
+	return resultado.RowsAffected, resultado.Error
+}
@@ -0,0 +1,63 @@
+package persistencia
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RepositorioEnlaceCortoPostgres implementa RepositorioEnlaceCorto con GORM en la base principal
+type RepositorioEnlaceCortoPostgres struct {
+	db *gorm.DB
+}
+
+// NuevoRepositorioEnlaceCortoPostgres crea una nueva instancia del repositorio
+func NuevoRepositorioEnlaceCortoPostgres(db *gorm.DB) *RepositorioEnlaceCortoPostgres {
+	return &RepositorioEnlaceCortoPostgres{db: db}
+}
+
+// Crear inserta el enlace ignorando el conflicto de slug, que el caso de uso resuelve con otro
+func (r *RepositorioEnlaceCortoPostgres) Crear(ctx context.Context, enlace *entidad.EnlaceCorto) (bool, error) {
+	resultado := sesionPlataforma(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "slug"}},
+		DoNothing: true,
+	}).Create(enlace)
+	return resultado.RowsAffected > 0, resultado.Error
+}
+
+// ObtenerPorSlug obtiene el enlace por su slug
+func (r *RepositorioEnlaceCortoPostgres) ObtenerPorSlug(ctx context.Context, slug string) (*entidad.EnlaceCorto, error) {
+	var enlace entidad.EnlaceCorto
+	err := sesionPlataforma(ctx, r.db).Where("slug = ?", slug).Take(&enlace).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entidad.ErrEnlaceCortoNoEncontrado
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &enlace, nil
+}
+
+// RegistrarClic incrementa el contador en la base, así los clics concurrentes no se pisan
+func (r *RepositorioEnlaceCortoPostgres) RegistrarClic(ctx context.Context, id uint, fecha time.Time) error {
+	return sesionPlataforma(ctx, r.db).Model(&entidad.EnlaceCorto{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"clics":             gorm.Expr("clics + 1"),
+		"fecha_primer_clic": gorm.Expr("COALESCE(fecha_primer_clic, ?)", fecha),
+		"fecha_ultimo_clic": fecha,
+	}).Error
+}
+
+// ListarPorNotificacion obtiene los enlaces de la notificación en el orden en que se crearon
+func (r *RepositorioEnlaceCortoPostgres) ListarPorNotificacion(ctx context.Context, inquilinoID, notificacionID uint) ([]entidad.EnlaceCorto, error) {
+	var enlaces []entidad.EnlaceCorto
+	err := sesionPlataforma(ctx, r.db).
+		Where("inquilino_id = ? AND notificacion_id = ?", inquilinoID, notificacionID).
+		Order("id").
+		Find(&enlaces).Error
+	return enlaces, err
+}
@@ -0,0 +1,50 @@
+package persistencia
+
+import (
+	"context"
+	"errors"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"gorm.io/gorm"
+)
+
+// RepositorioEnvioMultiCanalPostgres implementa RepositorioEnvioMultiCanal con GORM
+type RepositorioEnvioMultiCanalPostgres struct {
+	db *gorm.DB
+}
+
+// NuevoRepositorioEnvioMultiCanalPostgres crea una nueva instancia del repositorio
+func NuevoRepositorioEnvioMultiCanalPostgres(db *gorm.DB) *RepositorioEnvioMultiCanalPostgres {
+	return &RepositorioEnvioMultiCanalPostgres{db: db}
+}
+
+// Crear inserta el envío y sus pasos en una misma transacción
+func (r *RepositorioEnvioMultiCanalPostgres) Crear(ctx context.Context, envio *entidad.EnvioMultiCanal) error {
+	return sesion(ctx, r.db).Create(envio).Error
+}
+
+// ObtenerPorID obtiene un envío con sus pasos ordenados
+func (r *RepositorioEnvioMultiCanalPostgres) ObtenerPorID(ctx context.Context, id uint) (*entidad.EnvioMultiCanal, error) {
+	var envio entidad.EnvioMultiCanal
+	err := sesion(ctx, r.db).
+		Preload("Pasos", func(db *gorm.DB) *gorm.DB { return db.Order("orden") }).
+		First(&envio, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entidad.ErrEnvioNoEncontrado
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &envio, nil
+}
+
+// ActualizarPaso guarda el paso y el estado global de forma atómica
+func (r *RepositorioEnvioMultiCanalPostgres) ActualizarPaso(ctx context.Context, envio *entidad.EnvioMultiCanal, paso *entidad.PasoEnvio) error {
+	return sesion(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(paso).Update("estado", paso.Estado).Error; err != nil {
+			return err
+		}
+		return tx.Model(envio).Update("estado", envio.Estado).Error
+	})
+}
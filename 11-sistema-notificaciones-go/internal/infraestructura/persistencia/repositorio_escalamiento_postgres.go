@@ -0,0 +1,121 @@
+package persistencia
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RepositorioEscalamientoPostgres implementa RepositorioEscalamiento con GORM
+type RepositorioEscalamientoPostgres struct {
+	db *gorm.DB
+}
+
+// NuevoRepositorioEscalamientoPostgres crea una nueva instancia del repositorio
+func NuevoRepositorioEscalamientoPostgres(db *gorm.DB) *RepositorioEscalamientoPostgres {
+	return &RepositorioEscalamientoPostgres{db: db}
+}
+
+// ObtenerPolitica obtiene la política de escalamiento del canal
+func (r *RepositorioEscalamientoPostgres) ObtenerPolitica(ctx context.Context, canalID uint) (*entidad.PoliticaEscalamiento, error) {
+	var politica entidad.PoliticaEscalamiento
+	err := sesion(ctx, r.db).Where("canal_id = ?", canalID).First(&politica).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entidad.ErrSinPoliticaEscalamiento
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &politica, nil
+}
+
+// GuardarPolitica crea la política o reemplaza los pasos de la existente para el canal
+func (r *RepositorioEscalamientoPostgres) GuardarPolitica(ctx context.Context, politica *entidad.PoliticaEscalamiento) error {
+	return sesion(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "canal_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"pasos", "fecha_actualizacion"}),
+	}).Create(politica).Error
+}
+
+// EliminarPolitica elimina la política del canal; los escalamientos en curso siguen con sus pasos
+func (r *RepositorioEscalamientoPostgres) EliminarPolitica(ctx context.Context, canalID uint) error {
+	resultado := sesion(ctx, r.db).Where("canal_id = ?", canalID).Delete(&entidad.PoliticaEscalamiento{})
+	if resultado.Error != nil {
+		return resultado.Error
+	}
+	if resultado.RowsAffected == 0 {
+		return entidad.ErrSinPoliticaEscalamiento
+	}
+	return nil
+}
+
+// Crear inserta un escalamiento
+func (r *RepositorioEscalamientoPostgres) Crear(ctx context.Context, escalamiento *entidad.Escalamiento) error {
+	return sesion(ctx, r.db).Create(escalamiento).Error
+}
+
+// ObtenerPorID obtiene un escalamiento por su ID
+func (r *RepositorioEscalamientoPostgres) ObtenerPorID(ctx context.Context, id uint) (*entidad.Escalamiento, error) {
+	var escalamiento entidad.Escalamiento
+	err := sesion(ctx, r.db).First(&escalamiento, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entidad.ErrEscalamientoNoEncontrado
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &escalamiento, nil
+}
+
+// ListarVencidos obtiene los escalamientos activos con el próximo aviso vencido, el más atrasado primero
+func (r *RepositorioEscalamientoPostgres) ListarVencidos(ctx context.Context, hasta time.Time, limite int) ([]entidad.Escalamiento, error) {
+	var escalamientos []entidad.Escalamiento
+	err := sesion(ctx, r.db).
+		Where("estado = ? AND proximo_aviso <= ?", entidad.EstadoEscalamientoActivo, hasta).
+		Order("proximo_aviso").
+		Limit(limite).
+		Find(&escalamientos).Error
+	return escalamientos, err
+}
+
+// Avanzar guarda el siguiente paso condicionado al paso leído, como un bloqueo optimista
+func (r *RepositorioEscalamientoPostgres) Avanzar(ctx context.Context, escalamiento *entidad.Escalamiento, pasoAnterior int) (bool, error) {
+	resultado := sesion(ctx, r.db).Model(&entidad.Escalamiento{}).
+		Where("id = ? AND estado = ? AND siguiente_paso = ?", escalamiento.ID, entidad.EstadoEscalamientoActivo, pasoAnterior).
+		Updates(map[string]interface{}{
+			"siguiente_paso": escalamiento.SiguientePaso,
+			"estado":         escalamiento.Estado,
+			"proximo_aviso":  escalamiento.ProximoAviso,
+		})
+	return resultado.RowsAffected == 1, resultado.Error
+}
+
+// AgregarAviso concatena el aviso al arreglo jsonb en la misma sentencia
+func (r *RepositorioEscalamientoPostgres) AgregarAviso(ctx context.Context, escalamientoID uint, aviso entidad.AvisoEscalamiento) error {
+	valor, err := json.Marshal([]entidad.AvisoEscalamiento{aviso})
+	if err != nil {
+		return err
+	}
+	return sesion(ctx, r.db).Model(&entidad.Escalamiento{}).
+		Where("id = ?", escalamientoID).
+		Update("avisos", gorm.Expr("COALESCE(avisos, '[]'::jsonb) || ?::jsonb", string(valor))).Error
+}
+
+// Reconocer guarda el reconocimiento si el escalamiento sigue activo
+func (r *RepositorioEscalamientoPostgres) Reconocer(ctx context.Context, escalamiento *entidad.Escalamiento) (bool, error) {
+	resultado := sesion(ctx, r.db).Model(&entidad.Escalamiento{}).
+		Where("id = ? AND estado = ?", escalamiento.ID, entidad.EstadoEscalamientoActivo).
+		Updates(map[string]interface{}{
+			"estado":               escalamiento.Estado,
+			"reconocido_por":       escalamiento.ReconocidoPor,
+			"fecha_reconocimiento": escalamiento.FechaReconocimiento,
+			"proximo_aviso":        nil,
+		})
+	return resultado.RowsAffected == 1, resultado.Error
+}
@@ -0,0 +1,104 @@
+package persistencia
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RepositorioGuardiaPostgres implementa RepositorioGuardia con GORM
+type RepositorioGuardiaPostgres struct {
+	db *gorm.DB
+}
+
+// NuevoRepositorioGuardiaPostgres crea una nueva instancia del repositorio
+func NuevoRepositorioGuardiaPostgres(db *gorm.DB) *RepositorioGuardiaPostgres {
+	return &RepositorioGuardiaPostgres{db: db}
+}
+
+// ObtenerRotacion obtiene la rotación de guardia del canal
+func (r *RepositorioGuardiaPostgres) ObtenerRotacion(ctx context.Context, canalID uint) (*entidad.RotacionGuardia, error) {
+	var rotacion entidad.RotacionGuardia
+	err := sesion(ctx, r.db).Where("canal_id = ?", canalID).First(&rotacion).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entidad.ErrSinRotacionGuardia
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rotacion, nil
+}
+
+// GuardarRotacion crea la rotación o reemplaza la existente para el canal
+func (r *RepositorioGuardiaPostgres) GuardarRotacion(ctx context.Context, rotacion *entidad.RotacionGuardia) error {
+	return sesion(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "canal_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"participantes", "primer_relevo", "zona_horaria", "turno_dias", "fecha_actualizacion"}),
+	}).Create(rotacion).Error
+}
+
+// EliminarRotacion elimina la rotación y sus reemplazos en una misma transacción
+func (r *RepositorioGuardiaPostgres) EliminarRotacion(ctx context.Context, canalID uint) error {
+	return sesion(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var rotacion entidad.RotacionGuardia
+		err := tx.Where("canal_id = ?", canalID).First(&rotacion).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entidad.ErrSinRotacionGuardia
+		}
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("rotacion_id = ?", rotacion.ID).Delete(&entidad.ReemplazoGuardia{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&rotacion).Error
+	})
+}
+
+// CrearReemplazo inserta un reemplazo
+func (r *RepositorioGuardiaPostgres) CrearReemplazo(ctx context.Context, reemplazo *entidad.ReemplazoGuardia) error {
+	return sesion(ctx, r.db).Create(reemplazo).Error
+}
+
+// ListarReemplazos obtiene los reemplazos vigentes y futuros ordenados por inicio
+func (r *RepositorioGuardiaPostgres) ListarReemplazos(ctx context.Context, rotacionID uint, desde time.Time) ([]entidad.ReemplazoGuardia, error) {
+	var reemplazos []entidad.ReemplazoGuardia
+	err := sesion(ctx, r.db).
+		Where("rotacion_id = ? AND hasta > ?", rotacionID, desde).
+		Order("desde, id").
+		Find(&reemplazos).Error
+	return reemplazos, err
+}
+
+// ReemplazoVigente obtiene el reemplazo creado más recientemente que cubre el instante
+func (r *RepositorioGuardiaPostgres) ReemplazoVigente(ctx context.Context, rotacionID uint, instante time.Time) (*entidad.ReemplazoGuardia, error) {
+	var reemplazo entidad.ReemplazoGuardia
+	err := sesion(ctx, r.db).
+		Where("rotacion_id = ? AND desde <= ? AND hasta > ?", rotacionID, instante, instante).
+		Order("id DESC").
+		First(&reemplazo).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &reemplazo, nil
+}
+
+// EliminarReemplazo elimina un reemplazo de la rotación
+func (r *RepositorioGuardiaPostgres) EliminarReemplazo(ctx context.Context, rotacionID, id uint) error {
+	resultado := sesion(ctx, r.db).Where("rotacion_id = ?", rotacionID).Delete(&entidad.ReemplazoGuardia{}, id)
+	if resultado.Error != nil {
+		return resultado.Error
+	}
+	if resultado.RowsAffected == 0 {
+		return entidad.ErrReemplazoNoEncontrado
+	}
+	return nil
+}
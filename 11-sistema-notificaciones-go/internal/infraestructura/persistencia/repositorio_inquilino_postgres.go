@@ -0,0 +1,128 @@
+package persistencia
+
+import (
+	"context"
+	"errors"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RepositorioInquilinoPostgres implementa RepositorioInquilino con GORM
+type RepositorioInquilinoPostgres struct {
+	db *gorm.DB
+}
+
+// NuevoRepositorioInquilinoPostgres crea una nueva instancia del repositorio
+func NuevoRepositorioInquilinoPostgres(db *gorm.DB) *RepositorioInquilinoPostgres {
+	return &RepositorioInquilinoPostgres{db: db}
+}
+
+// Crear persiste un nuevo inquilino
+func (r *RepositorioInquilinoPostgres) Crear(ctx context.Context, inquilino *entidad.Inquilino) error {
+	return sesionPlataforma(ctx, r.db).Create(inquilino).Error
+}
+
+// ObtenerPorID obtiene un inquilino por su ID
+func (r *RepositorioInquilinoPostgres) ObtenerPorID(ctx context.Context, id uint) (*entidad.Inquilino, error) {
+	var inquilino entidad.Inquilino
+	err := sesionPlataforma(ctx, r.db).First(&inquilino, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entidad.ErrInquilinoNoEncontrado
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &inquilino, nil
+}
+
+// ListarAislados obtiene los inquilinos con esquema propio o de otra región
+func (r *RepositorioInquilinoPostgres) ListarAislados(ctx context.Context) ([]entidad.Inquilino, error) {
+	var inquilinos []entidad.Inquilino
+	err := sesionPlataforma(ctx, r.db).
+		Where("aislamiento = ? OR region <> ''", entidad.AislamientoEsquema).
+		Order("id").Find(&inquilinos).Error
+	return inquilinos, err
+}
+
+// ListarCuotas obtiene las cuotas configuradas del inquilino
+func (r *RepositorioInquilinoPostgres) ListarCuotas(ctx context.Context, inquilinoID uint) ([]entidad.CuotaInquilino, error) {
+	var cuotas []entidad.CuotaInquilino
+	err := sesionPlataforma(ctx, r.db).Where("inquilino_id = ?", inquilinoID).Order("tipo").Find(&cuotas).Error
+	return cuotas, err
+}
+
+// GuardarCuota inserta o actualiza la cuota por (inquilino_id, tipo)
+func (r *RepositorioInquilinoPostgres) GuardarCuota(ctx context.Context, cuota *entidad.CuotaInquilino) error {
+	return sesionPlataforma(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "inquilino_id"}, {Name: "tipo"}},
+		DoUpdates: clause.AssignmentColumns([]string{"limite_mensual", "limite_por_segundo", "fecha_actualizacion"}),
+	}).Create(cuota).Error
+}
+
+// ListarCredenciales obtiene las credenciales cifradas del inquilino
+func (r *RepositorioInquilinoPostgres) ListarCredenciales(ctx context.Context, inquilinoID uint) ([]entidad.CredencialProveedor, error) {
+	var credenciales []entidad.CredencialProveedor
+	err := sesionPlataforma(ctx, r.db).Where("inquilino_id = ?", inquilinoID).Order("proveedor").Find(&credenciales).Error
+	return credenciales, err
+}
+
+// GuardarCredencial inserta o reemplaza la credencial por (inquilino_id, proveedor)
+func (r *RepositorioInquilinoPostgres) GuardarCredencial(ctx context.Context, credencial *entidad.CredencialProveedor) error {
+	return sesionPlataforma(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "inquilino_id"}, {Name: "proveedor"}},
+		DoUpdates: clause.AssignmentColumns([]string{"datos_cifrados", "fecha_actualizacion"}),
+	}).Create(credencial).Error
+}
+
+// EliminarCredencial elimina la credencial; el inquilino vuelve a usar las de la plataforma
+func (r *RepositorioInquilinoPostgres) EliminarCredencial(ctx context.Context, inquilinoID uint, proveedor string) error {
+	return sesionPlataforma(ctx, r.db).
+		Where("inquilino_id = ? AND proveedor = ?", inquilinoID, proveedor).
+		Delete(&entidad.CredencialProveedor{}).Error
+}
+
+// ListarObjetivosSLA obtiene los objetivos del inquilino, o todos si inquilinoID es 0
+func (r *RepositorioInquilinoPostgres) ListarObjetivosSLA(ctx context.Context, inquilinoID uint) ([]entidad.ObjetivoSLA, error) {
+	consulta := sesionPlataforma(ctx, r.db).Order("inquilino_id, prioridad")
+	if inquilinoID != 0 {
+		consulta = consulta.Where("inquilino_id = ?", inquilinoID)
+	}
+	var objetivos []entidad.ObjetivoSLA
+	err := consulta.Find(&objetivos).Error
+	return objetivos, err
+}
+
+// GuardarObjetivoSLA inserta o actualiza el objetivo por (inquilino_id, prioridad)
+func (r *RepositorioInquilinoPostgres) GuardarObjetivoSLA(ctx context.Context, objetivo *entidad.ObjetivoSLA) error {
+	return sesionPlataforma(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "inquilino_id"}, {Name: "prioridad"}},
+		DoUpdates: clause.AssignmentColumns([]string{"umbral_segundos", "porcentaje_objetivo", "fecha_actualizacion"}),
+	}).Create(objetivo).Error
+}
+
+// ObtenerMarca obtiene la marca del inquilino
+func (r *RepositorioInquilinoPostgres) ObtenerMarca(ctx context.Context, inquilinoID uint) (*entidad.MarcaInquilino, error) {
+	var marca entidad.MarcaInquilino
+	err := sesionPlataforma(ctx, r.db).Where("inquilino_id = ?", inquilinoID).First(&marca).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entidad.ErrMarcaNoEncontrada
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &marca, nil
+}
+
+// GuardarMarca inserta o reemplaza la marca por inquilino_id
+func (r *RepositorioInquilinoPostgres) GuardarMarca(ctx context.Context, marca *entidad.MarcaInquilino) error {
+	return sesionPlataforma(ctx, r.db).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "inquilino_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"logo_url", "color_primario", "color_secundario",
+			"nombre_remitente", "correo_remitente", "pie_pagina", "fecha_actualizacion",
+		}),
+	}).Create(marca).Error
+}
@@ -0,0 +1,72 @@
+package persistencia
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"gorm.io/gorm"
+)
+
+// RepositorioIntentoEnvioPostgres implementa RepositorioIntentoEnvio con GORM
+type RepositorioIntentoEnvioPostgres struct {
+	db *gorm.DB
+}
+
+// NuevoRepositorioIntentoEnvioPostgres crea una nueva instancia del repositorio
+func NuevoRepositorioIntentoEnvioPostgres(db *gorm.DB) *RepositorioIntentoEnvioPostgres {
+	return &RepositorioIntentoEnvioPostgres{db: db}
+}
+
+// Crear registra un intento
+func (r *RepositorioIntentoEnvioPostgres) Crear(ctx context.Context, intento *entidad.IntentoEnvio) error {
+	return sesion(ctx, r.db).Create(intento).Error
+}
+
+// Actualizar guarda el resultado de un intento
+func (r *RepositorioIntentoEnvioPostgres) Actualizar(ctx context.Context, intento *entidad.IntentoEnvio) error {
+	return sesion(ctx, r.db).Save(intento).Error
+}
+
+// ObtenerUltimo retorna el intento con mayor número de la notificación
+func (r *RepositorioIntentoEnvioPostgres) ObtenerUltimo(ctx context.Context, notificacionID uint) (*entidad.IntentoEnvio, error) {
+	var intento entidad.IntentoEnvio
+	err := sesion(ctx, r.db).
+		Where("notificacion_id = ?", notificacionID).
+		Order("numero DESC").
+		First(&intento).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &intento, nil
+}
+
+// AnonimizarPorUsuario borra el detalle de error de los intentos de las notificaciones del usuario
+func (r *RepositorioIntentoEnvioPostgres) AnonimizarPorUsuario(ctx context.Context, usuarioID uint) (int64, error) {
+	db := sesion(ctx, r.db)
+	notificaciones := db.Session(&gorm.Session{NewDB: true}).Unscoped().Model(&entidad.Notificacion{}).
+		Select("id").
+		Where("usuario_id = ?", usuarioID)
+	resultado := db.Model(&entidad.IntentoEnvio{}).
+		Where("notificacion_id IN (?) AND error <> ''", notificaciones).
+		Update("error", "")
+	return resultado.RowsAffected, resultado.Error
+}
+
+// PrincipalesErrores retorna los errores más frecuentes de los intentos fallidos del periodo
+func (r *RepositorioIntentoEnvioPostgres) PrincipalesErrores(ctx context.Context, desde, hasta time.Time, limite int) ([]entidad.ConteoError, error) {
+	var errores []entidad.ConteoError
+	err := sesion(ctx, r.db).Model(&entidad.IntentoEnvio{}).
+		Select("error, count(*) AS cantidad").
+		Where("estado = ? AND fecha_inicio >= ? AND fecha_inicio < ?", entidad.EstadoIntentoFallido, desde, hasta).
+		Group("error").
+		Order("cantidad DESC, error").
+		Limit(limite).
+		Scan(&errores).Error
+	return errores, err
+}
@@ -0,0 +1,486 @@
+package persistencia
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// tamanoLotePredeterminado se usa cuando no se indica un tamaño de lote válido
+const tamanoLotePredeterminado = 1000
+
+// Consultas del camino crítico con texto SQL fijo: cada una se prepara una vez por
+// conexión y Postgres reutiliza su plan, sin variaciones según filtros opcionales.
+const (
+	consultaContarNoLeidas = `SELECT count(*) FROM notificaciones
+		WHERE usuario_id = $1 AND estado IN ('enviada', 'entregada') AND fecha_eliminacion IS NULL`
+
+	// Las creadas con una fecha ya pasada se encolaron al crearse; las reservadas ya están en la cola
+	consultaProgramadasVencidas = `SELECT * FROM notificaciones
+		WHERE estado = 'pendiente' AND fecha_programada IS NOT NULL AND fecha_programada <= $1
+		AND fecha_programada > fecha_creacion
+		AND (proxima_fecha_reintento IS NULL OR proxima_fecha_reintento <= $1)
+		AND fecha_eliminacion IS NULL
+		ORDER BY CASE prioridad WHEN 'critica' THEN 0 WHEN 'alta' THEN 1 WHEN 'normal' THEN 2 ELSE 3 END, fecha_programada
+		LIMIT $2`
+
+	consultaReintentosVencidos = `SELECT * FROM notificaciones
+		WHERE estado = 'fallida' AND proxima_fecha_reintento IS NOT NULL AND proxima_fecha_reintento <= $1
+		AND fecha_eliminacion IS NULL
+		ORDER BY proxima_fecha_reintento LIMIT $2`
+
+	// Las eliminadas también cuentan: la entrega ya ocurrió
+	consultaMedirEnvios = `SELECT count(*) AS enviadas,
+		count(*) FILTER (WHERE fecha_enviada - COALESCE(fecha_programada, fecha_creacion) <= make_interval(secs => $3)) AS dentro_umbral
+		FROM notificaciones
+		WHERE inquilino_id = $1 AND prioridad = $2 AND fecha_enviada >= $4 AND fecha_enviada < $5`
+)
+
+// tamanoLoteRecorrido es el tamaño de página al recorrer con relaciones precargadas
+const tamanoLoteRecorrido = 500
+
+// RepositorioNotificacionPostgres implementa RepositorioNotificacion con GORM
+type RepositorioNotificacionPostgres struct {
+	db *gorm.DB
+}
+
+// NuevoRepositorioNotificacionPostgres crea una nueva instancia del repositorio
+func NuevoRepositorioNotificacionPostgres(db *gorm.DB) *RepositorioNotificacionPostgres {
+	return &RepositorioNotificacionPostgres{db: db}
+}
+
+// Crear inserta una notificación
+func (r *RepositorioNotificacionPostgres) Crear(ctx context.Context, notificacion *entidad.Notificacion) error {
+	return sesion(ctx, r.db).Omit(clause.Associations).Create(notificacion).Error
+}
+
+// CrearLote inserta las notificaciones con un INSERT multi-fila por lote
+func (r *RepositorioNotificacionPostgres) CrearLote(ctx context.Context, notificaciones []*entidad.Notificacion, tamanoLote int, progreso repositorio.FuncionProgreso) error {
+	if tamanoLote <= 0 {
+		tamanoLote = tamanoLotePredeterminado
+	}
+
+	total := len(notificaciones)
+	for inicio := 0; inicio < total; inicio += tamanoLote {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		fin := inicio + tamanoLote
+		if fin > total {
+			fin = total
+		}
+
+		lote := notificaciones[inicio:fin]
+		if err := sesion(ctx, r.db).Omit(clause.Associations).Create(&lote).Error; err != nil {
+			return err
+		}
+
+		if progreso != nil {
+			progreso(fin, total)
+		}
+	}
+
+	return nil
+}
+
+// ObtenerPorID obtiene una notificación por su ID, precargando solo las relaciones pedidas
+func (r *RepositorioNotificacionPostgres) ObtenerPorID(ctx context.Context, id uint, incluir ...repositorio.RelacionNotificacion) (*entidad.Notificacion, error) {
+	var notificacion entidad.Notificacion
+	err := precargar(sesion(ctx, r.db), incluir).First(&notificacion, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entidad.ErrNotificacionNoEncontrada
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &notificacion, nil
+}
+
+// Actualizar guarda los cambios solo si nadie modificó la notificación desde que se leyó.
+// Retorna ErrConflictoVersion si la versión en base de datos ya no coincide.
+func (r *RepositorioNotificacionPostgres) Actualizar(ctx context.Context, notificacion *entidad.Notificacion) error {
+	versionLeida := notificacion.Version
+	notificacion.Version++
+
+	resultado := sesion(ctx, r.db).
+		Model(&entidad.Notificacion{}).
+		Where("id = ? AND version = ?", notificacion.ID, versionLeida).
+		Select("*").
+		Omit(clause.Associations, "ID", "FechaCreacion").
+		Updates(notificacion)
+
+	if resultado.Error != nil {
+		notificacion.Version = versionLeida
+		return resultado.Error
+	}
+	if resultado.RowsAffected == 0 {
+		notificacion.Version = versionLeida
+		return entidad.ErrConflictoVersion
+	}
+	return nil
+}
+
+// Eliminar elimina (soft delete) una notificación
+func (r *RepositorioNotificacionPostgres) Eliminar(ctx context.Context, id uint) error {
+	resultado := sesion(ctx, r.db).Delete(&entidad.Notificacion{}, id)
+	if resultado.Error != nil {
+		return resultado.Error
+	}
+	if resultado.RowsAffected == 0 {
+		return entidad.ErrNotificacionNoEncontrada
+	}
+	return nil
+}
+
+// EliminarPorUsuario aplica soft delete a las notificaciones del usuario
+func (r *RepositorioNotificacionPostgres) EliminarPorUsuario(ctx context.Context, usuarioID uint) (int64, error) {
+	resultado := sesion(ctx, r.db).Where("usuario_id = ?", usuarioID).Delete(&entidad.Notificacion{})
+	return resultado.RowsAffected, resultado.Error
+}
+
+// textoAnonimizado reemplaza título y mensaje de las notificaciones anonimizadas
+const textoAnonimizado = "[eliminado]"
+
+// AnonimizarPorUsuario anonimiza un lote de notificaciones del usuario, incluidas las eliminadas
+func (r *RepositorioNotificacionPostgres) AnonimizarPorUsuario(ctx context.Context, usuarioID uint, limite int) (int64, error) {
+	db := sesion(ctx, r.db)
+	lote := db.Session(&gorm.Session{NewDB: true}).Unscoped().Model(&entidad.Notificacion{}).
+		Select("id").
+		Where("usuario_id = ? AND (titulo <> ? OR mensaje <> ? OR metadatos IS NOT NULL)", usuarioID, textoAnonimizado, textoAnonimizado).
+		Limit(limite)
+	resultado := db.Unscoped().Model(&entidad.Notificacion{}).
+		Where("id IN (?)", lote).
+		Updates(map[string]interface{}{
+			"titulo":    textoAnonimizado,
+			"mensaje":   textoAnonimizado,
+			"metadatos": gorm.Expr("NULL"),
+			"version":   gorm.Expr("version + 1"),
+		})
+	return resultado.RowsAffected, resultado.Error
+}
+
+// PurgarPorRetencion borra un lote de notificaciones vencidas según la política y sus intentos
+func (r *RepositorioNotificacionPostgres) PurgarPorRetencion(ctx context.Context, politica *entidad.PoliticaRetencion, hasta time.Time, limite int) (int64, int64, error) {
+	db := sesion(ctx, r.db)
+	var ids []uint
+	if err := alcanceRetencion(db, politica, hasta).Order("id").Limit(limite).Pluck("id", &ids).Error; err != nil {
+		return 0, 0, err
+	}
+	if len(ids) == 0 {
+		return 0, 0, nil
+	}
+
+	intentos := db.Unscoped().Where("notificacion_id IN ?", ids).Delete(&entidad.IntentoEnvio{})
+	if intentos.Error != nil {
+		return 0, 0, intentos.Error
+	}
+	notificaciones := db.Unscoped().Where("id IN ?", ids).Delete(&entidad.Notificacion{})
+	return notificaciones.RowsAffected, intentos.RowsAffected, notificaciones.Error
+}
+
+// AnonimizarPorRetencion anonimiza un lote de notificaciones vencidas según la política
+func (r *RepositorioNotificacionPostgres) AnonimizarPorRetencion(ctx context.Context, politica *entidad.PoliticaRetencion, hasta time.Time, limite int, huella func(usuarioID uint) string) (int64, int64, error) {
+	db := sesion(ctx, r.db)
+	var lote []struct {
+		ID        uint
+		UsuarioID uint
+	}
+	err := alcanceRetencion(db, politica, hasta).
+		Select("id", "usuario_id").
+		Where("usuario_id <> 0").
+		Order("id").Limit(limite).
+		Find(&lote).Error
+	if err != nil || len(lote) == 0 {
+		return 0, 0, err
+	}
+
+	// La huella depende del usuario: se actualiza un grupo de filas por usuario
+	ids := make([]uint, 0, len(lote))
+	porUsuario := make(map[uint][]uint)
+	for _, fila := range lote {
+		ids = append(ids, fila.ID)
+		porUsuario[fila.UsuarioID] = append(porUsuario[fila.UsuarioID], fila.ID)
+	}
+
+	var notificaciones int64
+	for usuarioID, idsUsuario := range porUsuario {
+		resultado := db.Unscoped().Model(&entidad.Notificacion{}).
+			Where("id IN ?", idsUsuario).
+			Updates(map[string]interface{}{
+				"titulo":         textoAnonimizado,
+				"mensaje":        textoAnonimizado,
+				"metadatos":      gorm.Expr("NULL"),
+				"usuario_id":     0,
+				"huella_usuario": huella(usuarioID),
+				"version":        gorm.Expr("version + 1"),
+			})
+		if resultado.Error != nil {
+			return 0, 0, resultado.Error
+		}
+		notificaciones += resultado.RowsAffected
+	}
+
+	intentos := db.Model(&entidad.IntentoEnvio{}).
+		Where("notificacion_id IN ? AND error <> ''", ids).
+		Update("error", "")
+	return notificaciones, intentos.RowsAffected, intentos.Error
+}
+
+// alcanceRetencion selecciona las notificaciones alcanzadas por la política creadas antes de
+// hasta, incluidas las eliminadas
+func alcanceRetencion(db *gorm.DB, politica *entidad.PoliticaRetencion, hasta time.Time) *gorm.DB {
+	consulta := db.Session(&gorm.Session{NewDB: true}).Unscoped().Model(&entidad.Notificacion{}).
+		Where("fecha_creacion < ?", hasta)
+	if politica.InquilinoID != 0 {
+		consulta = consulta.Where("inquilino_id = ?", politica.InquilinoID)
+	}
+	if politica.Tipo != "" {
+		return consulta.Where("tipo = ?", politica.Tipo)
+	}
+	return consulta.Where("canal_id = ?", politica.CanalID)
+}
+
+// Listar obtiene una página de notificaciones ordenada por ID descendente
+func (r *RepositorioNotificacionPostgres) Listar(ctx context.Context, filtro repositorio.FiltroNotificaciones) ([]entidad.Notificacion, error) {
+	var notificaciones []entidad.Notificacion
+	err := precargar(r.aplicarFiltro(sesion(ctx, r.db), filtro), filtro.Incluir).Find(&notificaciones).Error
+	return notificaciones, err
+}
+
+// Recorrer itera las filas del cursor de PostgreSQL manteniendo el uso de memoria constante.
+// Con relaciones pedidas recorre por páginas para precargarlas con una consulta IN por página.
+func (r *RepositorioNotificacionPostgres) Recorrer(ctx context.Context, filtro repositorio.FiltroNotificaciones, procesar func(*entidad.Notificacion) error) error {
+	if len(filtro.Incluir) > 0 {
+		return r.recorrerPorPaginas(ctx, filtro, procesar)
+	}
+
+	filas, err := r.aplicarFiltro(sesion(ctx, r.db).Model(&entidad.Notificacion{}), filtro).Rows()
+	if err != nil {
+		return err
+	}
+	defer filas.Close()
+
+	for filas.Next() {
+		var notificacion entidad.Notificacion
+		if err := r.db.ScanRows(filas, &notificacion); err != nil {
+			return err
+		}
+		if err := procesar(&notificacion); err != nil {
+			return err
+		}
+	}
+
+	return filas.Err()
+}
+
+// VersionPagina agrega la página del filtro en la base sin transferir sus filas
+func (r *RepositorioNotificacionPostgres) VersionPagina(ctx context.Context, filtro repositorio.FiltroNotificaciones) (*repositorio.VersionPagina, error) {
+	pagina := r.aplicarFiltro(sesion(ctx, r.db).Model(&entidad.Notificacion{}).Select("id, version, fecha_actualizacion"), filtro)
+	var version repositorio.VersionPagina
+	err := sesion(ctx, r.db).Table("(?) AS pagina", pagina).
+		Select(`count(*) AS filas, coalesce(min(id), 0) AS primer_id, coalesce(max(id), 0) AS ultimo_id,
+			coalesce(sum(version), 0) AS suma_versiones, max(fecha_actualizacion) AS ultima_actualizacion`).
+		Scan(&version).Error
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+func (r *RepositorioNotificacionPostgres) recorrerPorPaginas(ctx context.Context, filtro repositorio.FiltroNotificaciones, procesar func(*entidad.Notificacion) error) error {
+	restantes := filtro.Limite
+	for {
+		pagina := filtro
+		pagina.Limite = tamanoLoteRecorrido
+		if restantes > 0 && restantes < tamanoLoteRecorrido {
+			pagina.Limite = restantes
+		}
+
+		notificaciones, err := r.Listar(ctx, pagina)
+		if err != nil {
+			return err
+		}
+		for i := range notificaciones {
+			if err := procesar(&notificaciones[i]); err != nil {
+				return err
+			}
+		}
+
+		if len(notificaciones) < pagina.Limite {
+			return nil
+		}
+		if filtro.Limite > 0 {
+			restantes -= len(notificaciones)
+			if restantes <= 0 {
+				return nil
+			}
+		}
+		filtro.Cursor = notificaciones[len(notificaciones)-1].ID
+	}
+}
+
+// ContarNoLeidas cuenta las no leídas con una consulta preparada estable
+func (r *RepositorioNotificacionPostgres) ContarNoLeidas(ctx context.Context, usuarioID uint) (int64, error) {
+	var total int64
+	err := sesion(ctx, r.db).Raw(consultaContarNoLeidas, usuarioID).Scan(&total).Error
+	return total, err
+}
+
+// ContarBandeja agrupa la bandeja por carpeta y destacada en una sola consulta y suma la vista
+// de destacadas a partir de los grupos
+func (r *RepositorioNotificacionPostgres) ContarBandeja(ctx context.Context, usuarioID uint) (map[entidad.CarpetaBandeja]entidad.ConteoCarpeta, error) {
+	var grupos []struct {
+		Carpeta   entidad.CarpetaBandeja
+		Destacada bool
+		Total     int64
+		NoLeidas  int64
+	}
+	err := sesion(ctx, r.db).Model(&entidad.Notificacion{}).
+		Select("carpeta, destacada, count(*) AS total, count(*) FILTER (WHERE estado IN ?) AS no_leidas",
+			[]entidad.EstadoNotificacion{entidad.EstadoEnviada, entidad.EstadoEntregada}).
+		Where("usuario_id = ? AND tipo = ? AND estado IN ?", usuarioID, entidad.TipoInApp, entidad.EstadosBandeja).
+		Group("carpeta, destacada").
+		Scan(&grupos).Error
+	if err != nil {
+		return nil, err
+	}
+
+	conteos := make(map[entidad.CarpetaBandeja]entidad.ConteoCarpeta, len(entidad.CarpetasBandeja))
+	for _, carpeta := range entidad.CarpetasBandeja {
+		conteos[carpeta] = entidad.ConteoCarpeta{}
+	}
+	sumar := func(carpeta entidad.CarpetaBandeja, total, noLeidas int64) {
+		conteo := conteos[carpeta]
+		conteo.Total += total
+		conteo.NoLeidas += noLeidas
+		conteos[carpeta] = conteo
+	}
+	for _, grupo := range grupos {
+		sumar(grupo.Carpeta, grupo.Total, grupo.NoLeidas)
+		if grupo.Destacada {
+			sumar(entidad.CarpetaDestacadas, grupo.Total, grupo.NoLeidas)
+		}
+	}
+	return conteos, nil
+}
+
+// ListarProgramadasVencidas obtiene las programadas vencidas con una consulta preparada estable
+func (r *RepositorioNotificacionPostgres) ListarProgramadasVencidas(ctx context.Context, hasta time.Time, limite int) ([]entidad.Notificacion, error) {
+	var notificaciones []entidad.Notificacion
+	err := sesion(ctx, r.db).Raw(consultaProgramadasVencidas, hasta, limite).Scan(&notificaciones).Error
+	return notificaciones, err
+}
+
+// ListarReintentosVencidos obtiene las fallidas con reintento vencido, las más antiguas primero
+func (r *RepositorioNotificacionPostgres) ListarReintentosVencidos(ctx context.Context, hasta time.Time, limite int) ([]entidad.Notificacion, error) {
+	var notificaciones []entidad.Notificacion
+	err := sesion(ctx, r.db).Raw(consultaReintentosVencidos, hasta, limite).Scan(&notificaciones).Error
+	return notificaciones, err
+}
+
+// ContarListasParaDespacho agrupa por prioridad las pendientes cuya fecha programada llegó y las
+// fallidas con el reintento vencido. Una pendiente espera desde su creación o su fecha programada
+// y una fallida desde su fecha de reintento.
+func (r *RepositorioNotificacionPostgres) ContarListasParaDespacho(ctx context.Context, hasta time.Time) ([]entidad.PendientesPrioridad, error) {
+	var pendientes []entidad.PendientesPrioridad
+	err := sesion(ctx, r.db).Model(&entidad.Notificacion{}).
+		Select(`prioridad, count(*) AS pendientes,
+			min(CASE WHEN estado = ? THEN proxima_fecha_reintento ELSE COALESCE(fecha_programada, fecha_creacion) END) AS mas_antigua`,
+			entidad.EstadoFallida).
+		Where("(estado = ? AND (fecha_programada IS NULL OR fecha_programada <= ?)) OR (estado = ? AND proxima_fecha_reintento <= ?)",
+			entidad.EstadoPendiente, hasta, entidad.EstadoFallida, hasta).
+		Group("prioridad").
+		Scan(&pendientes).Error
+	return pendientes, err
+}
+
+// MedirEnvios cuenta los envíos del periodo y los que cumplieron el umbral
+func (r *RepositorioNotificacionPostgres) MedirEnvios(ctx context.Context, inquilinoID uint, prioridad entidad.PrioridadNotificacion, umbral time.Duration, desde, hasta time.Time) (int64, int64, error) {
+	var medicion struct {
+		Enviadas     int64
+		DentroUmbral int64
+	}
+	err := sesion(ctx, r.db).Raw(consultaMedirEnvios, inquilinoID, prioridad, umbral.Seconds(), desde, hasta).Scan(&medicion).Error
+	return medicion.Enviadas, medicion.DentroUmbral, err
+}
+
+// ContarPorEstado cuenta las notificaciones creadas en el periodo por estado
+func (r *RepositorioNotificacionPostgres) ContarPorEstado(ctx context.Context, desde, hasta time.Time) (map[entidad.EstadoNotificacion]int64, error) {
+	var filas []struct {
+		Estado   entidad.EstadoNotificacion
+		Cantidad int64
+	}
+	err := sesion(ctx, r.db).Unscoped().Model(&entidad.Notificacion{}).
+		Select("estado, count(*) AS cantidad").
+		Where("fecha_creacion >= ? AND fecha_creacion < ?", desde, hasta).
+		Group("estado").
+		Scan(&filas).Error
+	if err != nil {
+		return nil, err
+	}
+	conteo := make(map[entidad.EstadoNotificacion]int64, len(filas))
+	for _, fila := range filas {
+		conteo[fila.Estado] = fila.Cantidad
+	}
+	return conteo, nil
+}
+
+// precargar agrega un Preload por relación pedida
+func precargar(consulta *gorm.DB, incluir []repositorio.RelacionNotificacion) *gorm.DB {
+	for _, relacion := range incluir {
+		consulta = consulta.Preload(relacion.Asociacion())
+	}
+	return consulta
+}
+
+func (r *RepositorioNotificacionPostgres) aplicarFiltro(consulta *gorm.DB, filtro repositorio.FiltroNotificaciones) *gorm.DB {
+	if filtro.InquilinoID != 0 {
+		consulta = consulta.Where("inquilino_id = ?", filtro.InquilinoID)
+	}
+	if filtro.UsuarioID != 0 {
+		consulta = consulta.Where("usuario_id = ?", filtro.UsuarioID)
+	}
+	if filtro.CanalID != 0 {
+		consulta = consulta.Where("canal_id = ?", filtro.CanalID)
+	}
+	if filtro.EnvioID != 0 {
+		consulta = consulta.Where("envio_id = ?", filtro.EnvioID)
+	}
+	if filtro.Estado != "" {
+		consulta = consulta.Where("estado = ?", filtro.Estado)
+	}
+	if filtro.Tipo != "" {
+		consulta = consulta.Where("tipo = ?", filtro.Tipo)
+	}
+	if filtro.Agotadas {
+		consulta = consulta.Where("intentos_envio >= max_intentos")
+	}
+	if filtro.Bandeja != "" {
+		consulta = consulta.Where("tipo = ? AND estado IN ?", entidad.TipoInApp, entidad.EstadosBandeja)
+		if filtro.Bandeja == entidad.CarpetaDestacadas {
+			consulta = consulta.Where("destacada")
+		} else {
+			consulta = consulta.Where("carpeta = ?", filtro.Bandeja)
+		}
+	}
+	if filtro.Desde != nil {
+		consulta = consulta.Where("fecha_creacion >= ?", *filtro.Desde)
+	}
+	if filtro.Hasta != nil {
+		consulta = consulta.Where("fecha_creacion < ?", *filtro.Hasta)
+	}
+	if filtro.Cursor != 0 {
+		consulta = consulta.Where("id < ?", filtro.Cursor)
+	}
+	if filtro.Limite > 0 {
+		consulta = consulta.Limit(filtro.Limite)
+	}
+	return consulta.Order("id DESC")
+}
@@ -0,0 +1,124 @@
+package persistencia
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// baseDePrueba abre PRUEBAS_POSTGRES_DSN (formato clave=valor de libpq) en un esquema nuevo con
+// las tablas de inquilino migradas, y lo elimina al terminar. Sin la variable la prueba se omite.
+func baseDePrueba(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("PRUEBAS_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("PRUEBAS_POSTGRES_DSN no está definida")
+	}
+
+	principal, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("conectando a PostgreSQL: %v", err)
+	}
+	esquema := fmt.Sprintf("prueba_%d", time.Now().UnixNano())
+	if err := principal.Exec("CREATE SCHEMA " + esquema).Error; err != nil {
+		t.Fatalf("creando el esquema: %v", err)
+	}
+	t.Cleanup(func() {
+		principal.Exec("DROP SCHEMA " + esquema + " CASCADE")
+		if sqlDB, err := principal.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	db, err := gorm.Open(postgres.Open(dsn+" search_path="+esquema), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("conectando al esquema: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	if err := db.AutoMigrate(ModelosInquilino...); err != nil {
+		t.Fatalf("migrando: %v", err)
+	}
+	if err := eliminarRestriccionesObsoletas(db); err != nil {
+		t.Fatalf("eliminando restricciones obsoletas: %v", err)
+	}
+	return db
+}
+
+func crearNotificacionVencida(t *testing.T, db *gorm.DB) (*entidad.Usuario, *entidad.Notificacion) {
+	t.Helper()
+	usuario := entidad.NuevoUsuario("ana", "ana@ejemplo.com", "Ana", "Pérez")
+	if err := db.Create(usuario).Error; err != nil {
+		t.Fatalf("creando usuario: %v", err)
+	}
+	notificacion := entidad.NuevaNotificacion(usuario.ID, "Hola", "Mensaje con datos personales", entidad.TipoEmail)
+	if err := db.Create(notificacion).Error; err != nil {
+		t.Fatalf("creando notificación: %v", err)
+	}
+	return usuario, notificacion
+}
+
+func anonimizarVencidas(t *testing.T, db *gorm.DB) int64 {
+	t.Helper()
+	repo := NuevoRepositorioNotificacionPostgres(db)
+	politica := &entidad.PoliticaRetencion{Tipo: entidad.TipoEmail, Accion: entidad.AccionAnonimizar, Dias: 1}
+	anonimizadas, _, err := repo.AnonimizarPorRetencion(context.Background(), politica, time.Now().Add(time.Hour), 100,
+		func(usuarioID uint) string { return fmt.Sprintf("huella-%d", usuarioID) })
+	if err != nil {
+		t.Fatalf("anonimizando: %v", err)
+	}
+	return anonimizadas
+}
+
+func TestAnonimizarPorRetencionConElEsquemaMigrado(t *testing.T) {
+	db := baseDePrueba(t)
+	usuario, notificacion := crearNotificacionVencida(t, db)
+
+	if anonimizadas := anonimizarVencidas(t, db); anonimizadas != 1 {
+		t.Fatalf("anonimizadas = %d, se esperaba 1", anonimizadas)
+	}
+
+	var guardada entidad.Notificacion
+	if err := db.Unscoped().First(&guardada, notificacion.ID).Error; err != nil {
+		t.Fatalf("leyendo la notificación: %v", err)
+	}
+	if guardada.UsuarioID != 0 {
+		t.Errorf("UsuarioID = %d, se esperaba 0", guardada.UsuarioID)
+	}
+	if esperada := fmt.Sprintf("huella-%d", usuario.ID); guardada.HuellaUsuario != esperada {
+		t.Errorf("HuellaUsuario = %q, se esperaba %q", guardada.HuellaUsuario, esperada)
+	}
+	if guardada.Mensaje == notificacion.Mensaje {
+		t.Error("el mensaje no se anonimizó")
+	}
+}
+
+func TestMigrarEliminaLaClaveForaneaDeVersionesAnteriores(t *testing.T) {
+	db := baseDePrueba(t)
+	// Así la creaba AutoMigrate a partir de Usuario.Notificaciones
+	err := db.Exec("ALTER TABLE notificaciones ADD CONSTRAINT fk_usuarios_notificaciones FOREIGN KEY (usuario_id) REFERENCES usuarios(id)").Error
+	if err != nil {
+		t.Fatalf("creando la restricción anterior: %v", err)
+	}
+	if err := eliminarRestriccionesObsoletas(db); err != nil {
+		t.Fatalf("eliminando restricciones obsoletas: %v", err)
+	}
+	if db.Migrator().HasConstraint(&entidad.Notificacion{}, "fk_usuarios_notificaciones") {
+		t.Fatal("la restricción sigue en la base")
+	}
+
+	crearNotificacionVencida(t, db)
+	if anonimizadas := anonimizarVencidas(t, db); anonimizadas != 1 {
+		t.Fatalf("anonimizadas = %d, se esperaba 1", anonimizadas)
+	}
+}
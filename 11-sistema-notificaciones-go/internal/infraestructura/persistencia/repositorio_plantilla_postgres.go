@@ -0,0 +1,45 @@
+package persistencia
+
+import (
+	"context"
+	"errors"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"gorm.io/gorm"
+)
+
+// RepositorioPlantillaPostgres implementa RepositorioPlantilla con GORM
+type RepositorioPlantillaPostgres struct {
+	db *gorm.DB
+}
+
+// NuevoRepositorioPlantillaPostgres crea una nueva instancia del repositorio
+func NuevoRepositorioPlantillaPostgres(db *gorm.DB) *RepositorioPlantillaPostgres {
+	return &RepositorioPlantillaPostgres{db: db}
+}
+
+// Crear persiste una nueva plantilla
+func (r *RepositorioPlantillaPostgres) Crear(ctx context.Context, plantilla *entidad.Plantilla) error {
+	return sesion(ctx, r.db).Create(plantilla).Error
+}
+
+// ListarPorInquilino obtiene las plantillas del inquilino ordenadas por nombre
+func (r *RepositorioPlantillaPostgres) ListarPorInquilino(ctx context.Context, inquilinoID uint) ([]entidad.Plantilla, error) {
+	var plantillas []entidad.Plantilla
+	err := sesion(ctx, r.db).Where("inquilino_id = ?", inquilinoID).Order("nombre").Find(&plantillas).Error
+	return plantillas, err
+}
+
+// ObtenerPorNombre obtiene una plantilla del inquilino por su nombre
+func (r *RepositorioPlantillaPostgres) ObtenerPorNombre(ctx context.Context, inquilinoID uint, nombre string) (*entidad.Plantilla, error) {
+	var plantilla entidad.Plantilla
+	err := sesion(ctx, r.db).Where("inquilino_id = ? AND nombre = ?", inquilinoID, nombre).First(&plantilla).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entidad.ErrPlantillaNoEncontrada
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &plantilla, nil
+}
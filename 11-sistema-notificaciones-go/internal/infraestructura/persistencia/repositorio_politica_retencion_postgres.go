@@ -0,0 +1,47 @@
+package persistencia
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RepositorioPoliticaRetencionPostgres implementa RepositorioPoliticaRetencion con GORM
+type RepositorioPoliticaRetencionPostgres struct {
+	db *gorm.DB
+}
+
+// NuevoRepositorioPoliticaRetencionPostgres crea una nueva instancia del repositorio
+func NuevoRepositorioPoliticaRetencionPostgres(db *gorm.DB) *RepositorioPoliticaRetencionPostgres {
+	return &RepositorioPoliticaRetencionPostgres{db: db}
+}
+
+// Listar obtiene todas las políticas
+func (r *RepositorioPoliticaRetencionPostgres) Listar(ctx context.Context) ([]entidad.PoliticaRetencion, error) {
+	var politicas []entidad.PoliticaRetencion
+	err := sesionPlataforma(ctx, r.db).Order("id").Find(&politicas).Error
+	return politicas, err
+}
+
+// Guardar crea la política o actualiza los días de la existente para el mismo alcance y acción
+func (r *RepositorioPoliticaRetencionPostgres) Guardar(ctx context.Context, politica *entidad.PoliticaRetencion) error {
+	return sesionPlataforma(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "inquilino_id"}, {Name: "tipo"}, {Name: "canal_id"}, {Name: "accion"}},
+		DoUpdates: clause.AssignmentColumns([]string{"dias", "fecha_actualizacion"}),
+	}).Create(politica).Error
+}
+
+// Eliminar elimina una política
+func (r *RepositorioPoliticaRetencionPostgres) Eliminar(ctx context.Context, id uint) error {
+	resultado := sesionPlataforma(ctx, r.db).Delete(&entidad.PoliticaRetencion{}, id)
+	if resultado.Error != nil {
+		return resultado.Error
+	}
+	if resultado.RowsAffected == 0 {
+		return entidad.ErrRetencionNoEncontrada
+	}
+	return nil
+}
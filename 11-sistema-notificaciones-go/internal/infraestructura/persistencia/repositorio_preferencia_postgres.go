@@ -0,0 +1,47 @@
+package persistencia
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RepositorioPreferenciaPostgres implementa RepositorioPreferencia con GORM
+type RepositorioPreferenciaPostgres struct {
+	db *gorm.DB
+}
+
+// NuevoRepositorioPreferenciaPostgres crea una nueva instancia del repositorio
+func NuevoRepositorioPreferenciaPostgres(db *gorm.DB) *RepositorioPreferenciaPostgres {
+	return &RepositorioPreferenciaPostgres{db: db}
+}
+
+// ListarPorUsuario obtiene todas las preferencias de un usuario
+func (r *RepositorioPreferenciaPostgres) ListarPorUsuario(ctx context.Context, usuarioID uint) ([]entidad.PreferenciaNotificacion, error) {
+	var preferencias []entidad.PreferenciaNotificacion
+	err := sesion(ctx, r.db).Where("usuario_id = ?", usuarioID).Find(&preferencias).Error
+	return preferencias, err
+}
+
+// EliminarPorUsuario aplica soft delete a las preferencias del usuario
+func (r *RepositorioPreferenciaPostgres) EliminarPorUsuario(ctx context.Context, usuarioID uint) (int64, error) {
+	resultado := sesion(ctx, r.db).Where("usuario_id = ?", usuarioID).Delete(&entidad.PreferenciaNotificacion{})
+	return resultado.RowsAffected, resultado.Error
+}
+
+// PurgarPorUsuario borra definitivamente las preferencias del usuario
+func (r *RepositorioPreferenciaPostgres) PurgarPorUsuario(ctx context.Context, usuarioID uint) (int64, error) {
+	resultado := sesion(ctx, r.db).Unscoped().Where("usuario_id = ?", usuarioID).Delete(&entidad.PreferenciaNotificacion{})
+	return resultado.RowsAffected, resultado.Error
+}
+
+// Guardar inserta o actualiza la preferencia por (usuario_id, tipo)
+func (r *RepositorioPreferenciaPostgres) Guardar(ctx context.Context, preferencia *entidad.PreferenciaNotificacion) error {
+	return sesion(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "usuario_id"}, {Name: "tipo"}},
+		DoUpdates: clause.AssignmentColumns([]string{"habilitada", "silencio_desde", "silencio_hasta", "zona_horaria", "fecha_actualizacion"}),
+	}).Create(preferencia).Error
+}
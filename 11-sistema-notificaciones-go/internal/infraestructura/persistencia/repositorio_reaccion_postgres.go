@@ -0,0 +1,68 @@
+package persistencia
+
+import (
+	"context"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"gorm.io/gorm"
+)
+
+// RepositorioReaccionPostgres implementa RepositorioReaccion con GORM
+type RepositorioReaccionPostgres struct {
+	db *gorm.DB
+}
+
+// NuevoRepositorioReaccionPostgres crea una nueva instancia del repositorio
+func NuevoRepositorioReaccionPostgres(db *gorm.DB) *RepositorioReaccionPostgres {
+	return &RepositorioReaccionPostgres{db: db}
+}
+
+// Registrar agrega el evento
+func (r *RepositorioReaccionPostgres) Registrar(ctx context.Context, evento *entidad.EventoReaccion) error {
+	return sesion(ctx, r.db).Create(evento).Error
+}
+
+// Listar obtiene los eventos de la notificación del más antiguo al más reciente
+func (r *RepositorioReaccionPostgres) Listar(ctx context.Context, notificacionID uint) ([]entidad.EventoReaccion, error) {
+	var eventos []entidad.EventoReaccion
+	err := sesion(ctx, r.db).Where("notificacion_id = ?", notificacionID).Order("id").Find(&eventos).Error
+	return eventos, err
+}
+
+// ContarPorCanal cuenta los destinatarios del periodo y, con el último evento de cada reacción
+// en cada notificación, los que la mantienen
+func (r *RepositorioReaccionPostgres) ContarPorCanal(ctx context.Context, canalID uint, desde, hasta time.Time) (int64, map[entidad.TipoReaccion]int64, error) {
+	delPeriodo := sesion(ctx, r.db).Model(&entidad.Notificacion{}).
+		Where("canal_id = ? AND fecha_creacion >= ? AND fecha_creacion < ?", canalID, desde, hasta).
+		Where("estado IN ?", []entidad.EstadoNotificacion{entidad.EstadoEnviada, entidad.EstadoEntregada, entidad.EstadoLeida})
+
+	var destinatarios int64
+	if err := delPeriodo.Session(&gorm.Session{}).Distinct("usuario_id").Count(&destinatarios).Error; err != nil {
+		return 0, nil, err
+	}
+
+	ultimos := sesion(ctx, r.db).Model(&entidad.EventoReaccion{}).
+		Select("DISTINCT ON (notificacion_id, reaccion) reaccion, usuario_id, activa").
+		Where("canal_id = ? AND notificacion_id IN (?)", canalID, delPeriodo.Session(&gorm.Session{}).Select("id")).
+		Order("notificacion_id, reaccion, id DESC")
+	var filas []struct {
+		Reaccion entidad.TipoReaccion
+		Usuarios int64
+	}
+	err := sesion(ctx, r.db).Table("(?) AS ultimos", ultimos).
+		Select("reaccion, count(DISTINCT usuario_id) AS usuarios").
+		Where("activa").
+		Group("reaccion").
+		Scan(&filas).Error
+	if err != nil {
+		return 0, nil, err
+	}
+
+	reacciones := make(map[entidad.TipoReaccion]int64, len(filas))
+	for _, fila := range filas {
+		reacciones[fila.Reaccion] = fila.Usuarios
+	}
+	return destinatarios, reacciones, nil
+}
@@ -0,0 +1,45 @@
+package persistencia
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"gorm.io/gorm"
+)
+
+// RepositorioRespuestaPostgres implementa RepositorioRespuesta con GORM
+type RepositorioRespuestaPostgres struct {
+	db *gorm.DB
+}
+
+// NuevoRepositorioRespuestaPostgres crea una nueva instancia del repositorio
+func NuevoRepositorioRespuestaPostgres(db *gorm.DB) *RepositorioRespuestaPostgres {
+	return &RepositorioRespuestaPostgres{db: db}
+}
+
+// Crear guarda la respuesta
+func (r *RepositorioRespuestaPostgres) Crear(ctx context.Context, respuesta *entidad.RespuestaNotificacion) error {
+	return sesion(ctx, r.db).Create(respuesta).Error
+}
+
+// Listar obtiene una página del hilo ordenada por ID
+func (r *RepositorioRespuestaPostgres) Listar(ctx context.Context, notificacionPadreID, desdeID uint, limite int) ([]entidad.RespuestaNotificacion, error) {
+	var respuestas []entidad.RespuestaNotificacion
+	err := sesion(ctx, r.db).
+		Where("notificacion_padre_id = ? AND id > ?", notificacionPadreID, desdeID).
+		Order("id").
+		Limit(limite).
+		Find(&respuestas).Error
+	return respuestas, err
+}
+
+// Autores obtiene los autores distintos del hilo
+func (r *RepositorioRespuestaPostgres) Autores(ctx context.Context, notificacionPadreID uint) ([]uint, error) {
+	var autores []uint
+	err := sesion(ctx, r.db).Model(&entidad.RespuestaNotificacion{}).
+		Where("notificacion_padre_id = ?", notificacionPadreID).
+		Distinct().
+		Pluck("autor_id", &autores).Error
+	return autores, err
+}
@@ -0,0 +1,73 @@
+package persistencia
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RepositorioSilenciamientoPostgres implementa RepositorioSilenciamiento con GORM
+type RepositorioSilenciamientoPostgres struct {
+	db *gorm.DB
+}
+
+// NuevoRepositorioSilenciamientoPostgres crea una nueva instancia del repositorio
+func NuevoRepositorioSilenciamientoPostgres(db *gorm.DB) *RepositorioSilenciamientoPostgres {
+	return &RepositorioSilenciamientoPostgres{db: db}
+}
+
+// Guardar inserta el silenciamiento o actualiza el del trío (usuario, canal, origen)
+func (r *RepositorioSilenciamientoPostgres) Guardar(ctx context.Context, silenciamiento *entidad.Silenciamiento) error {
+	return sesion(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "usuario_id"}, {Name: "canal_id"}, {Name: "origen"}},
+		DoUpdates: clause.AssignmentColumns([]string{"hasta"}),
+	}).Create(silenciamiento).Error
+}
+
+// ListarVigentes obtiene los silenciamientos en curso del usuario, del más reciente al más antiguo
+func (r *RepositorioSilenciamientoPostgres) ListarVigentes(ctx context.Context, usuarioID uint, ahora time.Time) ([]entidad.Silenciamiento, error) {
+	var silenciamientos []entidad.Silenciamiento
+	err := sesion(ctx, r.db).
+		Where("usuario_id = ? AND (hasta IS NULL OR hasta > ?)", usuarioID, ahora).
+		Order("id DESC").
+		Find(&silenciamientos).Error
+	return silenciamientos, err
+}
+
+// BuscarVigente obtiene el silenciamiento en curso del canal o del origen
+func (r *RepositorioSilenciamientoPostgres) BuscarVigente(ctx context.Context, usuarioID, canalID uint, origen string, ahora time.Time) (*entidad.Silenciamiento, error) {
+	fuente := sesion(ctx, r.db).Where("canal_id <> 0 AND canal_id = ?", canalID)
+	if origen != "" {
+		fuente = fuente.Or("canal_id = 0 AND origen = ?", origen)
+	}
+
+	var silenciamiento entidad.Silenciamiento
+	err := sesion(ctx, r.db).
+		Where("usuario_id = ? AND (hasta IS NULL OR hasta > ?)", usuarioID, ahora).
+		Where(fuente).
+		Take(&silenciamiento).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &silenciamiento, nil
+}
+
+// Eliminar borra el silenciamiento si pertenece al usuario
+func (r *RepositorioSilenciamientoPostgres) Eliminar(ctx context.Context, usuarioID, id uint) error {
+	resultado := sesion(ctx, r.db).Where("usuario_id = ?", usuarioID).Delete(&entidad.Silenciamiento{}, id)
+	if resultado.Error != nil {
+		return resultado.Error
+	}
+	if resultado.RowsAffected == 0 {
+		return entidad.ErrSilenciamientoNoEncontrado
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package persistencia
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RepositorioSupresionPostgres implementa RepositorioSupresion con GORM
+type RepositorioSupresionPostgres struct {
+	db *gorm.DB
+}
+
+// NuevoRepositorioSupresionPostgres crea una nueva instancia del repositorio
+func NuevoRepositorioSupresionPostgres(db *gorm.DB) *RepositorioSupresionPostgres {
+	return &RepositorioSupresionPostgres{db: db}
+}
+
+// Agregar inserta la entrada o actualiza la del par (inquilino, valor)
+func (r *RepositorioSupresionPostgres) Agregar(ctx context.Context, entrada *entidad.EntradaSupresion) error {
+	return sesionPlataforma(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "inquilino_id"}, {Name: "valor"}},
+		DoUpdates: clause.AssignmentColumns([]string{"motivo", "detalle", "fecha"}),
+	}).Create(entrada).Error
+}
+
+// AgregarLote inserta las entradas en un solo INSERT, ignorando las ya existentes
+func (r *RepositorioSupresionPostgres) AgregarLote(ctx context.Context, entradas []*entidad.EntradaSupresion) (int64, error) {
+	if len(entradas) == 0 {
+		return 0, nil
+	}
+	resultado := sesionPlataforma(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(&entradas)
+	return resultado.RowsAffected, resultado.Error
+}
+
+// ObtenerPorID obtiene una entrada por su ID
+func (r *RepositorioSupresionPostgres) ObtenerPorID(ctx context.Context, id uint) (*entidad.EntradaSupresion, error) {
+	var entrada entidad.EntradaSupresion
+	err := sesionPlataforma(ctx, r.db).First(&entrada, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entidad.ErrSupresionNoEncontrada
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entrada, nil
+}
+
+// Eliminar quita una entrada de la lista
+func (r *RepositorioSupresionPostgres) Eliminar(ctx context.Context, id uint) error {
+	resultado := sesionPlataforma(ctx, r.db).Delete(&entidad.EntradaSupresion{}, id)
+	if resultado.Error != nil {
+		return resultado.Error
+	}
+	if resultado.RowsAffected == 0 {
+		return entidad.ErrSupresionNoEncontrada
+	}
+	return nil
+}
+
+// Buscar obtiene una página de entradas ordenada de la más reciente a la más antigua
+func (r *RepositorioSupresionPostgres) Buscar(ctx context.Context, filtro repositorio.FiltroSupresion) ([]entidad.EntradaSupresion, error) {
+	consulta := sesionPlataforma(ctx, r.db).Order("id DESC")
+	if filtro.InquilinoID != 0 {
+		consulta = consulta.Where("inquilino_id = ?", filtro.InquilinoID)
+	}
+	if filtro.Valor != "" {
+		consulta = consulta.Where("valor LIKE ?", escaparLike(filtro.Valor)+"%")
+	}
+	if filtro.Motivo != "" {
+		consulta = consulta.Where("motivo = ?", filtro.Motivo)
+	}
+	if filtro.Cursor != 0 {
+		consulta = consulta.Where("id < ?", filtro.Cursor)
+	}
+	if filtro.Limite > 0 {
+		consulta = consulta.Limit(filtro.Limite)
+	}
+
+	var entradas []entidad.EntradaSupresion
+	err := consulta.Find(&entradas).Error
+	return entradas, err
+}
+
+// Coincidencias busca los valores en la lista del inquilino y en la de la plataforma
+func (r *RepositorioSupresionPostgres) Coincidencias(ctx context.Context, inquilinoID uint, valores []string) ([]entidad.EntradaSupresion, error) {
+	var entradas []entidad.EntradaSupresion
+	if len(valores) == 0 {
+		return entradas, nil
+	}
+	err := sesionPlataforma(ctx, r.db).
+		Where("inquilino_id IN (0, ?) AND valor IN ?", inquilinoID, valores).
+		Find(&entradas).Error
+	return entradas, err
+}
+
+// escaparLike evita que % y _ del valor buscado actúen como comodines
+func escaparLike(valor string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(valor)
+}
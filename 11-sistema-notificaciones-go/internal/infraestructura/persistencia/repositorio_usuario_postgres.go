@@ -0,0 +1,69 @@
+package persistencia
+
+import (
+	"context"
+	"errors"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RepositorioUsuarioPostgres implementa RepositorioUsuario con GORM
+type RepositorioUsuarioPostgres struct {
+	db *gorm.DB
+}
+
+// NuevoRepositorioUsuarioPostgres crea una nueva instancia del repositorio
+func NuevoRepositorioUsuarioPostgres(db *gorm.DB) *RepositorioUsuarioPostgres {
+	return &RepositorioUsuarioPostgres{db: db}
+}
+
+// Crear persiste un nuevo usuario
+func (r *RepositorioUsuarioPostgres) Crear(ctx context.Context, usuario *entidad.Usuario) error {
+	return sesion(ctx, r.db).Create(usuario).Error
+}
+
+// ObtenerPorID obtiene un usuario por su ID
+func (r *RepositorioUsuarioPostgres) ObtenerPorID(ctx context.Context, id uint) (*entidad.Usuario, error) {
+	var usuario entidad.Usuario
+	err := sesion(ctx, r.db).First(&usuario, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entidad.ErrUsuarioNoEncontrado
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &usuario, nil
+}
+
+// Eliminar aplica soft delete al usuario
+func (r *RepositorioUsuarioPostgres) Eliminar(ctx context.Context, id uint) error {
+	resultado := sesion(ctx, r.db).Delete(&entidad.Usuario{}, id)
+	if resultado.Error != nil {
+		return resultado.Error
+	}
+	if resultado.RowsAffected == 0 {
+		return entidad.ErrUsuarioNoEncontrado
+	}
+	return nil
+}
+
+// ObtenerIncluyendoEliminados obtiene un usuario por su ID aunque tenga soft delete
+func (r *RepositorioUsuarioPostgres) ObtenerIncluyendoEliminados(ctx context.Context, id uint) (*entidad.Usuario, error) {
+	var usuario entidad.Usuario
+	err := sesion(ctx, r.db).Unscoped().First(&usuario, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entidad.ErrUsuarioNoEncontrado
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &usuario, nil
+}
+
+// Actualizar guarda el usuario sin sus relaciones
+func (r *RepositorioUsuarioPostgres) Actualizar(ctx context.Context, usuario *entidad.Usuario) error {
+	return sesion(ctx, r.db).Unscoped().Omit(clause.Associations).Save(usuario).Error
+}
@@ -0,0 +1,82 @@
+package persistencia
+
+import (
+	"context"
+	"fmt"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+
+	"gorm.io/gorm"
+)
+
+type claveTransaccion struct{}
+
+// transaccion es la transacción en curso junto a la región y el esquema sobre los que se abrió
+type transaccion struct {
+	tx      *gorm.DB
+	region  string
+	esquema string
+}
+
+// UnidadTrabajoPostgres implementa UnidadTrabajo con transacciones de GORM
+type UnidadTrabajoPostgres struct {
+	db *gorm.DB
+}
+
+// NuevaUnidadTrabajoPostgres crea una nueva instancia de UnidadTrabajoPostgres
+func NuevaUnidadTrabajoPostgres(db *gorm.DB) *UnidadTrabajoPostgres {
+	return &UnidadTrabajoPostgres{db: db}
+}
+
+// Ejecutar abre una transacción (o un savepoint si ya hay una en curso) y la
+// propaga en el contexto a los repositorios usados dentro de la operación.
+// Si el contexto cambia de región o esquema, la transacción interna es independiente de la externa.
+func (u *UnidadTrabajoPostgres) Ejecutar(ctx context.Context, operacion func(ctx context.Context) error) error {
+	region, esquema := servicio.RegionDesdeContexto(ctx), servicio.EsquemaDesdeContexto(ctx)
+	return sesion(ctx, u.db).Transaction(func(tx *gorm.DB) error {
+		return operacion(context.WithValue(ctx, claveTransaccion{}, transaccion{tx: tx, region: region, esquema: esquema}))
+	})
+}
+
+// sesion retorna la transacción del contexto o, si no hay una sobre la misma región y esquema,
+// la conexión de la región y esquema del inquilino o la conexión base.
+// Los datos de una región nunca caen en la base principal: si la región no puede resolverse
+// la sesión queda con error.
+func sesion(ctx context.Context, db *gorm.DB) *gorm.DB {
+	region, esquema := servicio.RegionDesdeContexto(ctx), servicio.EsquemaDesdeContexto(ctx)
+	if actual, ok := ctx.Value(claveTransaccion{}).(transaccion); ok && actual.region == region && actual.esquema == esquema {
+		return actual.tx.WithContext(ctx)
+	}
+	if region == "" && esquema == "" {
+		return db.WithContext(ctx)
+	}
+
+	registro := registroActivo.Load()
+	if registro == nil {
+		if region == "" {
+			return db.WithContext(ctx)
+		}
+		return sesionConError(ctx, db, fmt.Errorf("%w: %q", entidad.ErrRegionNoConfigurada, region))
+	}
+	conexion, err := registro.Conexion(region, esquema)
+	if err != nil {
+		return sesionConError(ctx, db, err)
+	}
+	return conexion.WithContext(ctx)
+}
+
+// sesionPlataforma retorna la sesión de las tablas de plataforma (inquilinos, claves, consumo,
+// auditoría), que viven siempre en la base principal aunque el contexto sea de otra región
+func sesionPlataforma(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if servicio.RegionDesdeContexto(ctx) == "" {
+		return sesion(ctx, db)
+	}
+	return sesion(servicio.ContextoConEsquema(servicio.ContextoConRegion(ctx, ""), ""), db)
+}
+
+func sesionConError(ctx context.Context, db *gorm.DB, err error) *gorm.DB {
+	sesion := db.WithContext(ctx)
+	_ = sesion.AddError(err)
+	return sesion
+}
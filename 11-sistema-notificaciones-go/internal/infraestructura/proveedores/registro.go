@@ -0,0 +1,52 @@
+package proveedores
+
+import (
+	"sort"
+	"sync"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+)
+
+// RegistroProveedores guarda el proveedor de cada tipo de notificación; implementa
+// servicio.CatalogoProveedores. Cada canal se registra al iniciar el servidor, sin que el
+// despacho conozca sus implementaciones.
+type RegistroProveedores struct {
+	mu          sync.RWMutex
+	proveedores map[entidad.TipoNotificacion]servicio.ProveedorNotificacion
+}
+
+// NuevoRegistroProveedores crea un registro vacío
+func NuevoRegistroProveedores() *RegistroProveedores {
+	return &RegistroProveedores{proveedores: make(map[entidad.TipoNotificacion]servicio.ProveedorNotificacion)}
+}
+
+// Registrar agrega los proveedores bajo su tipo. Un proveedor del mismo tipo que uno ya
+// registrado lo reemplaza: el último registrado tiene prioridad.
+func (r *RegistroProveedores) Registrar(proveedores ...servicio.ProveedorNotificacion) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, proveedor := range proveedores {
+		r.proveedores[proveedor.Tipo()] = proveedor
+	}
+}
+
+// Proveedor retorna el proveedor del tipo, o false si ninguno lo entrega
+func (r *RegistroProveedores) Proveedor(tipo entidad.TipoNotificacion) (servicio.ProveedorNotificacion, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	proveedor, existe := r.proveedores[tipo]
+	return proveedor, existe
+}
+
+// Tipos retorna, ordenados, los tipos de notificación con proveedor registrado
+func (r *RegistroProveedores) Tipos() []entidad.TipoNotificacion {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tipos := make([]entidad.TipoNotificacion, 0, len(r.proveedores))
+	for tipo := range r.proveedores {
+		tipos = append(tipos, tipo)
+	}
+	sort.Slice(tipos, func(i, j int) bool { return tipos[i] < tipos[j] })
+	return tipos
+}
@@ -0,0 +1,205 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+	"sistema-notificaciones-go/pkg/logger"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// ErrPublicacionRechazada indica que el broker no confirmó la publicación
+var ErrPublicacionRechazada = errors.New("RabbitMQ no confirmó la publicación")
+
+// tiposLocales se entregan por las conexiones del hub de cada servidor, que los trabajadores no
+// tienen: se despachan en el pool del servidor
+var tiposLocales = []entidad.TipoNotificacion{entidad.TipoWebSocket, entidad.TipoInApp}
+
+// prioridadMaxima es el x-max-priority de la cola; RabbitMQ recomienda pocas prioridades
+const prioridadMaxima = 4
+
+// prioridadesAMQP traduce la prioridad de la notificación a la del mensaje
+var prioridadesAMQP = map[entidad.PrioridadNotificacion]uint8{
+	entidad.PrioridadBaja:    1,
+	entidad.PrioridadNormal:  2,
+	entidad.PrioridadAlta:    3,
+	entidad.PrioridadCritica: prioridadMaxima,
+}
+
+// ColaRabbitMQ publica las notificaciones en la cola de RabbitMQ que consumen los procesos de
+// cmd/trabajador, salvo las de tiposLocales, que pasan a la cola local del servidor. Cada
+// publicación espera la confirmación del broker, así una notificación aceptada no se pierde si
+// el broker cae.
+type ColaRabbitMQ struct {
+	config configuracion.ConfiguracionRabbitMQ
+	local  repositorio.ColaMensajes
+	logger *logger.Logger
+
+	// mutex protege la conexión, que se reabre al publicar si el broker la cerró
+	mutex    sync.Mutex
+	conexion *amqp.Connection
+	canal    *amqp.Channel
+}
+
+// NuevaColaRabbitMQ conecta con el broker y declara la cola; falla si no está disponible al
+// arrancar
+func NuevaColaRabbitMQ(config configuracion.ConfiguracionRabbitMQ, local repositorio.ColaMensajes, log *logger.Logger) (*ColaRabbitMQ, error) {
+	cola := &ColaRabbitMQ{
+		config: config,
+		local:  local,
+		logger: log.Componente(logger.ComponenteTrabajadores),
+	}
+	if _, err := cola.canalAbierto(); err != nil {
+		return nil, err
+	}
+	return cola, nil
+}
+
+// Publicar publica la notificación y espera la confirmación del broker
+func (c *ColaRabbitMQ) Publicar(ctx context.Context, notificacion *entidad.Notificacion) error {
+	if esLocal(notificacion) {
+		return c.local.Publicar(ctx, notificacion)
+	}
+	return c.publicar(ctx, []*entidad.Notificacion{notificacion})
+}
+
+// PublicarLote publica el lote y espera las confirmaciones juntas
+func (c *ColaRabbitMQ) PublicarLote(ctx context.Context, notificaciones []*entidad.Notificacion) error {
+	var locales, remotas []*entidad.Notificacion
+	for _, notificacion := range notificaciones {
+		if esLocal(notificacion) {
+			locales = append(locales, notificacion)
+		} else {
+			remotas = append(remotas, notificacion)
+		}
+	}
+	if len(locales) > 0 {
+		if err := c.local.PublicarLote(ctx, locales); err != nil {
+			return err
+		}
+	}
+	if len(remotas) == 0 {
+		return nil
+	}
+	return c.publicar(ctx, remotas)
+}
+
+func (c *ColaRabbitMQ) publicar(ctx context.Context, notificaciones []*entidad.Notificacion) error {
+	confirmaciones, err := c.enviar(ctx, notificaciones)
+	if err != nil {
+		return err
+	}
+	// Si el canal se cierra antes de confirmar, las confirmaciones pendientes quedan rechazadas
+	for _, confirmacion := range confirmaciones {
+		confirmada, err := confirmacion.WaitContext(ctx)
+		if err != nil {
+			return err
+		}
+		if !confirmada {
+			metricaPublicaciones.WithLabelValues("rechazada").Inc()
+			return ErrPublicacionRechazada
+		}
+	}
+	metricaPublicaciones.WithLabelValues("ok").Add(float64(len(notificaciones)))
+	return nil
+}
+
+// enviar publica sin esperar las confirmaciones; el mutex solo cubre la publicación, así los
+// publicadores concurrentes esperan sus confirmaciones en paralelo
+func (c *ColaRabbitMQ) enviar(ctx context.Context, notificaciones []*entidad.Notificacion) ([]*amqp.DeferredConfirmation, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	canal, err := c.canalAbierto()
+	if err != nil {
+		return nil, err
+	}
+	confirmaciones := make([]*amqp.DeferredConfirmation, 0, len(notificaciones))
+	for _, notificacion := range notificaciones {
+		mensaje, err := mensajeNotificacion(notificacion)
+		if err != nil {
+			return nil, err
+		}
+		confirmacion, err := canal.PublishWithDeferredConfirmWithContext(ctx, "", c.config.Cola, false, false, mensaje)
+		if err != nil {
+			metricaPublicaciones.WithLabelValues("error").Inc()
+			return nil, fmt.Errorf("publicando en RabbitMQ: %w", err)
+		}
+		confirmaciones = append(confirmaciones, confirmacion)
+	}
+	return confirmaciones, nil
+}
+
+// canalAbierto retorna el canal en modo confirmación, reconectando si el broker lo cerró.
+// Debe llamarse con el mutex tomado, salvo al construir la cola.
+func (c *ColaRabbitMQ) canalAbierto() (*amqp.Channel, error) {
+	if c.canal != nil && !c.canal.IsClosed() {
+		return c.canal, nil
+	}
+	if c.conexion != nil {
+		_ = c.conexion.Close()
+		c.logger.Warn("Reconectando con RabbitMQ")
+	}
+	conexion, canal, err := conectar(c.config)
+	if err != nil {
+		return nil, err
+	}
+	if err := canal.Confirm(false); err != nil {
+		_ = conexion.Close()
+		return nil, fmt.Errorf("habilitando confirmaciones de RabbitMQ: %w", err)
+	}
+	c.conexion, c.canal = conexion, canal
+	return canal, nil
+}
+
+// conectar abre una conexión y un canal y declara la cola durable con prioridades. La
+// declaración es idempotente: el servidor y los trabajadores la declaran igual.
+func conectar(config configuracion.ConfiguracionRabbitMQ) (*amqp.Connection, *amqp.Channel, error) {
+	conexion, err := amqp.Dial(config.URL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("conectando con RabbitMQ: %w", err)
+	}
+	canal, err := conexion.Channel()
+	if err != nil {
+		_ = conexion.Close()
+		return nil, nil, fmt.Errorf("abriendo canal de RabbitMQ: %w", err)
+	}
+	argumentos := amqp.Table{"x-max-priority": int32(prioridadMaxima)}
+	if _, err := canal.QueueDeclare(config.Cola, true, false, false, false, argumentos); err != nil {
+		_ = conexion.Close()
+		return nil, nil, fmt.Errorf("declarando la cola %s: %w", config.Cola, err)
+	}
+	return conexion, canal, nil
+}
+
+// mensajeNotificacion serializa la notificación como mensaje persistente con su prioridad
+func mensajeNotificacion(notificacion *entidad.Notificacion) (amqp.Publishing, error) {
+	cuerpo, err := json.Marshal(notificacion)
+	if err != nil {
+		return amqp.Publishing{}, fmt.Errorf("serializando la notificación %d: %w", notificacion.ID, err)
+	}
+	prioridad, existe := prioridadesAMQP[notificacion.Prioridad]
+	if !existe {
+		prioridad = prioridadesAMQP[entidad.PrioridadNormal]
+	}
+	return amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		Priority:     prioridad,
+		MessageId:    strconv.FormatUint(uint64(notificacion.ID), 10),
+		Body:         cuerpo,
+	}, nil
+}
+
+func esLocal(notificacion *entidad.Notificacion) bool {
+	return slices.Contains(tiposLocales, notificacion.Tipo)
+}
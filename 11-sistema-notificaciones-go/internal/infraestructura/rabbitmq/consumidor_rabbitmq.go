@@ -0,0 +1,214 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+	"sistema-notificaciones-go/internal/infraestructura/trabajador"
+	"sistema-notificaciones-go/pkg/logger"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// etiquetaConsumidor identifica al consumidor dentro de su canal, para cancelarlo al apagarse
+const etiquetaConsumidor = "trabajador"
+
+// cabeceraEntregas cuenta las entregas de un mensaje que se devolvió a la cola tras un error
+// transitorio; las colas clásicas no llevan la cuenta
+const cabeceraEntregas = "x-entregas"
+
+// ConsumidorRabbitMQ entrega las notificaciones de la cola de RabbitMQ con tantos trabajadores
+// como su prefetch; el broker reparte los mensajes entre los procesos y prioriza los críticos.
+// Cada mensaje se confirma al terminar de procesarlo, se haya enviado o no: los reintentos los
+// lleva la notificación en la base de datos. Si el procesamiento falla por un error transitorio
+// la notificación no registró nada, así que el mensaje vuelve a la cola hasta MaxEntregas veces.
+// Si el proceso cae antes, el broker devuelve el mensaje a la cola para otro trabajador.
+type ConsumidorRabbitMQ struct {
+	config     configuracion.ConfiguracionRabbitMQ
+	procesador trabajador.Procesador
+	logger     *logger.Logger
+
+	grupo sync.WaitGroup
+}
+
+// NuevoConsumidorRabbitMQ crea una nueva instancia de ConsumidorRabbitMQ
+func NuevoConsumidorRabbitMQ(config configuracion.ConfiguracionRabbitMQ, procesador trabajador.Procesador, log *logger.Logger) *ConsumidorRabbitMQ {
+	return &ConsumidorRabbitMQ{
+		config:     config,
+		procesador: procesador,
+		logger:     log.Componente(logger.ComponenteTrabajadores),
+	}
+}
+
+// Iniciar consume hasta que ctx termine, reconectando cada EsperaReconexion si el broker no
+// está disponible o cierra la conexión
+func (c *ConsumidorRabbitMQ) Iniciar(ctx context.Context) {
+	c.grupo.Add(1)
+	go func() {
+		defer c.grupo.Done()
+		for {
+			if err := c.consumir(ctx); err != nil {
+				c.logger.Error("Error consumiendo de RabbitMQ", "cola", c.config.Cola, "error", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(c.config.EsperaReconexion):
+			}
+		}
+	}()
+}
+
+// Esperar bloquea hasta que terminen las entregas en curso tras terminar el contexto de Iniciar
+func (c *ConsumidorRabbitMQ) Esperar() {
+	c.grupo.Wait()
+}
+
+// consumir atiende una conexión hasta que el broker la cierra o ctx termina. Al terminar ctx
+// deja de recibir y completa las entregas en curso; lo recibido sin procesar vuelve a la cola
+// al cerrar la conexión.
+func (c *ConsumidorRabbitMQ) consumir(ctx context.Context) error {
+	conexion, canal, err := conectar(c.config)
+	if err != nil {
+		return err
+	}
+	defer conexion.Close()
+	cierre := conexion.NotifyClose(make(chan *amqp.Error, 1))
+
+	if err := canal.Qos(c.config.Prefetch, 0, false); err != nil {
+		return fmt.Errorf("fijando el prefetch de RabbitMQ: %w", err)
+	}
+	// Las devoluciones a la cola esperan la confirmación antes de confirmar el original
+	if err := canal.Confirm(false); err != nil {
+		return fmt.Errorf("habilitando confirmaciones de RabbitMQ: %w", err)
+	}
+	mensajes, err := canal.Consume(c.config.Cola, etiquetaConsumidor, false, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("consumiendo la cola %s: %w", c.config.Cola, err)
+	}
+	c.logger.Info("Consumiendo de RabbitMQ", "cola", c.config.Cola, "prefetch", c.config.Prefetch)
+
+	// Las entregas en curso terminan aunque el proceso se esté apagando
+	ctxEntregas := context.WithoutCancel(ctx)
+	var entregas sync.WaitGroup
+	for i := 0; i < c.config.Prefetch; i++ {
+		entregas.Add(1)
+		go func() {
+			defer entregas.Done()
+			for mensaje := range mensajes {
+				c.entregar(ctxEntregas, canal, mensaje)
+			}
+		}()
+	}
+	defer entregas.Wait()
+
+	select {
+	case <-ctx.Done():
+		_ = canal.Cancel(etiquetaConsumidor, false)
+		return nil
+	case errCierre := <-cierre:
+		if errCierre == nil {
+			return fmt.Errorf("RabbitMQ cerró la conexión")
+		}
+		return errCierre
+	}
+}
+
+// entregar procesa un mensaje y lo confirma, o lo devuelve a la cola si falló por un error
+// transitorio. Un mensaje ilegible no se procesará nunca: se descarta en lugar de devolverlo.
+func (c *ConsumidorRabbitMQ) entregar(ctx context.Context, canal *amqp.Channel, mensaje amqp.Delivery) {
+	var notificacion entidad.Notificacion
+	if err := json.Unmarshal(mensaje.Body, &notificacion); err != nil {
+		c.logger.Error("Mensaje de RabbitMQ ilegible, se descarta", "message_id", mensaje.MessageId, "error", err)
+		metricaConsumidas.WithLabelValues("desconocida", "descartada").Inc()
+		_ = mensaje.Nack(false, false)
+		return
+	}
+
+	resultado := "ok"
+	if err := c.procesador.Procesar(ctx, &notificacion); err != nil {
+		if trabajador.Reentregable(err) {
+			c.reentregar(ctx, canal, mensaje, &notificacion, err)
+			return
+		}
+		resultado = "error"
+		c.logger.Warn("Error procesando notificación",
+			"notificacion_id", notificacion.ID,
+			"prioridad", notificacion.Prioridad,
+			"error", err,
+		)
+	}
+	metricaConsumidas.WithLabelValues(string(notificacion.Prioridad), resultado).Inc()
+	if err := mensaje.Ack(false); err != nil {
+		c.logger.Warn("No se pudo confirmar el mensaje, el broker lo volverá a entregar",
+			"notificacion_id", notificacion.ID,
+			"error", err,
+		)
+	}
+}
+
+// reentregar publica una copia del mensaje con una entrega más y confirma el original; al
+// llegar a MaxEntregas lo descarta. Si la copia no se confirma, el broker vuelve a entregar el
+// original, así el mensaje no se pierde por un error del propio broker.
+func (c *ConsumidorRabbitMQ) reentregar(ctx context.Context, canal *amqp.Channel, mensaje amqp.Delivery, notificacion *entidad.Notificacion, causa error) {
+	entregas := entregasDe(mensaje) + 1
+	if entregas >= c.config.MaxEntregas {
+		c.logger.Error("Notificación descartada tras agotar sus entregas",
+			"notificacion_id", notificacion.ID,
+			"entregas", entregas,
+			"error", causa,
+		)
+		metricaConsumidas.WithLabelValues(string(notificacion.Prioridad), "descartada").Inc()
+		_ = mensaje.Nack(false, false)
+		return
+	}
+	c.logger.Warn("Error transitorio procesando notificación, vuelve a la cola",
+		"notificacion_id", notificacion.ID,
+		"entregas", entregas,
+		"error", causa,
+	)
+	metricaConsumidas.WithLabelValues(string(notificacion.Prioridad), "reentregada").Inc()
+	time.Sleep(c.config.EsperaReentrega)
+
+	copia := amqp.Publishing{
+		ContentType:  mensaje.ContentType,
+		DeliveryMode: amqp.Persistent,
+		Priority:     mensaje.Priority,
+		MessageId:    mensaje.MessageId,
+		Headers:      amqp.Table{cabeceraEntregas: int32(entregas)},
+		Body:         mensaje.Body,
+	}
+	confirmacion, err := canal.PublishWithDeferredConfirmWithContext(ctx, "", c.config.Cola, false, false, copia)
+	if err == nil {
+		var confirmada bool
+		if confirmada, err = confirmacion.WaitContext(ctx); err == nil && !confirmada {
+			err = errors.New("el broker rechazó la copia")
+		}
+	}
+	if err != nil {
+		c.logger.Warn("No se pudo devolver el mensaje a la cola, el broker lo volverá a entregar",
+			"notificacion_id", notificacion.ID,
+			"error", err,
+		)
+		_ = mensaje.Nack(false, true)
+		return
+	}
+	_ = mensaje.Ack(false)
+}
+
+// entregasDe retorna cuántas veces se devolvió el mensaje a la cola
+func entregasDe(mensaje amqp.Delivery) int {
+	switch entregas := mensaje.Headers[cabeceraEntregas].(type) {
+	case int32:
+		return int(entregas)
+	case int64:
+		return int(entregas)
+	}
+	return 0
+}
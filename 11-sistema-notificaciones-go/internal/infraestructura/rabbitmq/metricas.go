@@ -0,0 +1,18 @@
+package rabbitmq
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricaPublicaciones = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "notificaciones_rabbitmq_publicadas_total",
+		Help: "Notificaciones publicadas en RabbitMQ por resultado (ok, rechazada o error)",
+	}, []string{"resultado"})
+
+	metricaConsumidas = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "notificaciones_rabbitmq_consumidas_total",
+		Help: "Notificaciones consumidas de RabbitMQ por prioridad y resultado",
+	}, []string{"prioridad", "resultado"})
+)
@@ -0,0 +1,140 @@
+package redisStreams
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+	"sistema-notificaciones-go/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// campoNotificacion es el campo de la entrada que lleva la notificación serializada
+const campoNotificacion = "notificacion"
+
+// tiposLocales se entregan por las conexiones del hub de cada servidor, que los trabajadores no
+// tienen: se despachan en el pool del servidor
+var tiposLocales = []entidad.TipoNotificacion{entidad.TipoWebSocket, entidad.TipoInApp}
+
+// prioridades en el orden en que los trabajadores leen sus streams
+var prioridades = []entidad.PrioridadNotificacion{
+	entidad.PrioridadCritica,
+	entidad.PrioridadAlta,
+	entidad.PrioridadNormal,
+	entidad.PrioridadBaja,
+}
+
+// ColaRedisStreams agrega las notificaciones al stream de su prioridad, que leen los procesos de
+// cmd/trabajador, salvo las de tiposLocales, que pasan a la cola local del servidor. Una
+// notificación aceptada queda en Redis hasta que un trabajador la procesa.
+type ColaRedisStreams struct {
+	config  configuracion.ConfiguracionRedisStreams
+	cliente *redis.Client
+	local   repositorio.ColaMensajes
+	logger  *logger.Logger
+}
+
+// NuevaColaRedisStreams crea los streams y el grupo de los trabajadores; falla si Redis no está
+// disponible al arrancar
+func NuevaColaRedisStreams(config configuracion.ConfiguracionRedisStreams, cliente *redis.Client, local repositorio.ColaMensajes, log *logger.Logger) (*ColaRedisStreams, error) {
+	if err := crearGrupos(context.Background(), cliente, config); err != nil {
+		return nil, err
+	}
+	return &ColaRedisStreams{
+		config:  config,
+		cliente: cliente,
+		local:   local,
+		logger:  log.Componente(logger.ComponenteTrabajadores),
+	}, nil
+}
+
+// Publicar agrega la notificación al stream de su prioridad
+func (c *ColaRedisStreams) Publicar(ctx context.Context, notificacion *entidad.Notificacion) error {
+	if esLocal(notificacion) {
+		return c.local.Publicar(ctx, notificacion)
+	}
+	return c.publicar(ctx, []*entidad.Notificacion{notificacion})
+}
+
+// PublicarLote agrega el lote en un solo viaje a Redis
+func (c *ColaRedisStreams) PublicarLote(ctx context.Context, notificaciones []*entidad.Notificacion) error {
+	var locales, remotas []*entidad.Notificacion
+	for _, notificacion := range notificaciones {
+		if esLocal(notificacion) {
+			locales = append(locales, notificacion)
+		} else {
+			remotas = append(remotas, notificacion)
+		}
+	}
+	if len(locales) > 0 {
+		if err := c.local.PublicarLote(ctx, locales); err != nil {
+			return err
+		}
+	}
+	if len(remotas) == 0 {
+		return nil
+	}
+	return c.publicar(ctx, remotas)
+}
+
+func (c *ColaRedisStreams) publicar(ctx context.Context, notificaciones []*entidad.Notificacion) error {
+	tuberia := c.cliente.Pipeline()
+	for _, notificacion := range notificaciones {
+		cuerpo, err := json.Marshal(notificacion)
+		if err != nil {
+			return fmt.Errorf("serializando la notificación %d: %w", notificacion.ID, err)
+		}
+		tuberia.XAdd(ctx, &redis.XAddArgs{
+			Stream: nombreStream(c.config, notificacion.Prioridad),
+			Values: []any{campoNotificacion, cuerpo},
+		})
+	}
+	if _, err := tuberia.Exec(ctx); err != nil {
+		metricaPublicaciones.WithLabelValues("error").Add(float64(len(notificaciones)))
+		return fmt.Errorf("publicando en Redis Streams: %w", err)
+	}
+	metricaPublicaciones.WithLabelValues("ok").Add(float64(len(notificaciones)))
+	return nil
+}
+
+// crearGrupos crea cada stream con el grupo de los trabajadores, desde el principio del stream.
+// Es idempotente: el servidor y los trabajadores lo crean igual.
+func crearGrupos(ctx context.Context, cliente *redis.Client, config configuracion.ConfiguracionRedisStreams) error {
+	for _, prioridad := range prioridades {
+		stream := nombreStream(config, prioridad)
+		err := cliente.XGroupCreateMkStream(ctx, stream, config.Grupo, "0").Err()
+		if err != nil && !esGrupoExistente(err) {
+			return fmt.Errorf("creando el grupo %s de %s: %w", config.Grupo, stream, err)
+		}
+	}
+	return nil
+}
+
+// nombreStream retorna el stream de la prioridad; una prioridad desconocida va a normal
+// streamMuertas recibe las entradas que agotaron sus entregas, para que un operador las revise
+func streamMuertas(config configuracion.ConfiguracionRedisStreams) string {
+	return config.Prefijo + ":muertas"
+}
+
+func nombreStream(config configuracion.ConfiguracionRedisStreams, prioridad entidad.PrioridadNotificacion) string {
+	if !slices.Contains(prioridades, prioridad) {
+		prioridad = entidad.PrioridadNormal
+	}
+	return config.Prefijo + ":" + string(prioridad)
+}
+
+func esGrupoExistente(err error) bool {
+	var errRedis redis.Error
+	return errors.As(err, &errRedis) && strings.HasPrefix(errRedis.Error(), "BUSYGROUP")
+}
+
+func esLocal(notificacion *entidad.Notificacion) bool {
+	return slices.Contains(tiposLocales, notificacion.Tipo)
+}
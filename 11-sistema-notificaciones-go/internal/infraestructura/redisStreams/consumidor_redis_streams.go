@@ -0,0 +1,316 @@
+package redisStreams
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+	"sistema-notificaciones-go/internal/infraestructura/trabajador"
+	"sistema-notificaciones-go/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// entrada es una entrada leída o reclamada de uno de los streams
+type entrada struct {
+	stream  string
+	mensaje redis.XMessage
+}
+
+// ConsumidorRedisStreams entrega las notificaciones de los streams con Concurrencia trabajadores,
+// que leen primero el stream de mayor prioridad. El consumer group reparte las entradas entre los
+// procesos; cada una se confirma y se borra al terminar de procesarla, se haya enviado o no: los
+// reintentos los lleva la notificación en la base de datos. Una entrada que falla por un error
+// transitorio queda pendiente, igual que las de un trabajador caído: se reclaman tras
+// ReclamarTras sin actividad y, al alcanzar MaxEntregas, pasan al stream de muertas.
+type ConsumidorRedisStreams struct {
+	config     configuracion.ConfiguracionRedisStreams
+	cliente    *redis.Client
+	procesador trabajador.Procesador
+	logger     *logger.Logger
+	// nombre identifica al proceso dentro del grupo
+	nombre string
+
+	grupo sync.WaitGroup
+}
+
+// NuevoConsumidorRedisStreams crea una nueva instancia de ConsumidorRedisStreams
+func NuevoConsumidorRedisStreams(config configuracion.ConfiguracionRedisStreams, cliente *redis.Client, procesador trabajador.Procesador, log *logger.Logger) *ConsumidorRedisStreams {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "trabajador"
+	}
+	return &ConsumidorRedisStreams{
+		config:     config,
+		cliente:    cliente,
+		procesador: procesador,
+		logger:     log.Componente(logger.ComponenteTrabajadores),
+		nombre:     fmt.Sprintf("%s-%d", host, os.Getpid()),
+	}
+}
+
+// Iniciar consume hasta que ctx termine. Una lectura sin entradas espera hasta EsperaLectura,
+// así al apagarse el proceso deja de leer en ese plazo.
+func (c *ConsumidorRedisStreams) Iniciar(ctx context.Context) {
+	c.logger.Info("Consumiendo de Redis Streams",
+		"prefijo", c.config.Prefijo,
+		"grupo", c.config.Grupo,
+		"consumidor", c.nombre,
+		"concurrencia", c.config.Concurrencia,
+	)
+	// Las entregas en curso terminan aunque el proceso se esté apagando
+	ctxEntregas := context.WithoutCancel(ctx)
+	for i := 0; i < c.config.Concurrencia; i++ {
+		c.grupo.Add(1)
+		go func() {
+			defer c.grupo.Done()
+			for ctx.Err() == nil {
+				entradas, err := c.siguientes(ctx)
+				if err != nil {
+					c.esperarTrasError(ctx, err)
+					continue
+				}
+				for _, e := range entradas {
+					c.entregar(ctxEntregas, e)
+				}
+			}
+		}()
+	}
+
+	c.grupo.Add(1)
+	go func() {
+		defer c.grupo.Done()
+		ticker := time.NewTicker(c.config.IntervaloReclamo)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := c.reclamar(ctx, ctxEntregas); err != nil && ctx.Err() == nil {
+					c.logger.Error("Error reclamando entradas pendientes de Redis Streams", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// Esperar bloquea hasta que terminen las entregas en curso tras terminar el contexto de Iniciar
+func (c *ConsumidorRedisStreams) Esperar() {
+	c.grupo.Wait()
+}
+
+// siguientes retorna las entradas nuevas del stream de mayor prioridad que tenga. Si todos están
+// vacíos bloquea sobre todos a la vez, que puede traer una por stream.
+func (c *ConsumidorRedisStreams) siguientes(ctx context.Context) ([]entrada, error) {
+	streams := make([]string, 0, len(prioridades))
+	for _, prioridad := range prioridades {
+		stream := nombreStream(c.config, prioridad)
+		streams = append(streams, stream)
+		entradas, err := c.leer(ctx, []string{stream}, -1)
+		if err != nil || len(entradas) > 0 {
+			return entradas, err
+		}
+	}
+	return c.leer(ctx, streams, c.config.EsperaLectura)
+}
+
+// leer lee hasta una entrada nueva de cada stream; espera negativa no bloquea
+func (c *ConsumidorRedisStreams) leer(ctx context.Context, streams []string, espera time.Duration) ([]entrada, error) {
+	argumentos := make([]string, 0, 2*len(streams))
+	argumentos = append(argumentos, streams...)
+	for range streams {
+		argumentos = append(argumentos, ">")
+	}
+	resultado, err := c.cliente.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    c.config.Grupo,
+		Consumer: c.nombre,
+		Streams:  argumentos,
+		Count:    1,
+		Block:    espera,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entradas []entrada
+	for _, stream := range resultado {
+		for _, mensaje := range stream.Messages {
+			entradas = append(entradas, entrada{stream: stream.Stream, mensaje: mensaje})
+		}
+	}
+	return entradas, nil
+}
+
+// reclamar entrega las entradas pendientes del grupo que nadie confirmó en ReclamarTras: las de
+// un trabajador caído y las que fallaron por un error transitorio. Reclama de a una y la entrega
+// en el acto, así una entrada reclamada no espera a un trabajador mientras su inactividad vuelve
+// a contar. Las que ya alcanzaron MaxEntregas pasan al stream de muertas.
+func (c *ConsumidorRedisStreams) reclamar(ctx, ctxEntregas context.Context) error {
+	for _, prioridad := range prioridades {
+		stream := nombreStream(c.config, prioridad)
+		for ctx.Err() == nil {
+			// Las ya reclamadas dejan de estar inactivas, así cada consulta trae otras
+			pendientes, err := c.cliente.XPendingExt(ctx, &redis.XPendingExtArgs{
+				Stream: stream,
+				Group:  c.config.Grupo,
+				Idle:   c.config.ReclamarTras,
+				Start:  "-",
+				End:    "+",
+				Count:  int64(c.config.Concurrencia),
+			}).Result()
+			if err != nil {
+				return err
+			}
+			if len(pendientes) == 0 {
+				break
+			}
+			for _, pendiente := range pendientes {
+				if ctx.Err() != nil {
+					// Sin reclamar sigue pendiente y otro proceso la reclamará
+					return nil
+				}
+				if err := c.reclamarEntrada(ctx, ctxEntregas, stream, pendiente); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// reclamarEntrada pasa la entrada a este proceso y la entrega, o la archiva si agotó sus entregas.
+// Si otro proceso la reclamó antes, XCLAIM no la retorna y se omite.
+func (c *ConsumidorRedisStreams) reclamarEntrada(ctx, ctxEntregas context.Context, stream string, pendiente redis.XPendingExt) error {
+	mensajes, err := c.cliente.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   stream,
+		Group:    c.config.Grupo,
+		Consumer: c.nombre,
+		MinIdle:  c.config.ReclamarTras,
+		Messages: []string{pendiente.ID},
+	}).Result()
+	if err != nil {
+		return err
+	}
+	if len(mensajes) == 0 {
+		return nil
+	}
+	metricaReclamadas.Inc()
+	e := entrada{stream: stream, mensaje: mensajes[0]}
+	// RetryCount cuenta las entregas previas a este reclamo
+	if pendiente.RetryCount >= int64(c.config.MaxEntregas) {
+		c.archivar(ctxEntregas, e, pendiente.RetryCount)
+		return nil
+	}
+	c.entregar(ctxEntregas, e)
+	return nil
+}
+
+// entregar procesa una entrada y la confirma. Si falla por un error transitorio queda pendiente
+// para reclamarla después. Una entrada ilegible no se procesará nunca: se descarta en lugar de
+// dejarla pendiente.
+func (c *ConsumidorRedisStreams) entregar(ctx context.Context, e entrada) {
+	var notificacion entidad.Notificacion
+	cuerpo, _ := e.mensaje.Values[campoNotificacion].(string)
+	if err := json.Unmarshal([]byte(cuerpo), &notificacion); err != nil {
+		c.logger.Error("Entrada de Redis Streams ilegible, se descarta", "stream", e.stream, "id", e.mensaje.ID, "error", err)
+		metricaConsumidas.WithLabelValues("desconocida", "descartada").Inc()
+		c.confirmar(ctx, e)
+		return
+	}
+
+	resultado := "ok"
+	if err := c.procesador.Procesar(ctx, &notificacion); err != nil {
+		if trabajador.Reentregable(err) {
+			c.logger.Warn("Error transitorio procesando notificación, queda pendiente para reclamarla",
+				"notificacion_id", notificacion.ID,
+				"stream", e.stream,
+				"id", e.mensaje.ID,
+				"error", err,
+			)
+			metricaConsumidas.WithLabelValues(string(notificacion.Prioridad), "pendiente").Inc()
+			return
+		}
+		resultado = "error"
+		c.logger.Warn("Error procesando notificación",
+			"notificacion_id", notificacion.ID,
+			"prioridad", notificacion.Prioridad,
+			"error", err,
+		)
+	}
+	metricaConsumidas.WithLabelValues(string(notificacion.Prioridad), resultado).Inc()
+	c.confirmar(ctx, e)
+}
+
+// archivar agrega la entrada al stream de muertas, con su origen y sus entregas, y la confirma
+// en la misma transacción
+func (c *ConsumidorRedisStreams) archivar(ctx context.Context, e entrada, entregas int64) {
+	cuerpo, _ := e.mensaje.Values[campoNotificacion].(string)
+	_, err := c.cliente.TxPipelined(ctx, func(tuberia redis.Pipeliner) error {
+		tuberia.XAdd(ctx, &redis.XAddArgs{
+			Stream: streamMuertas(c.config),
+			Values: []any{campoNotificacion, cuerpo, "stream", e.stream, "id", e.mensaje.ID, "entregas", entregas},
+		})
+		tuberia.XAck(ctx, e.stream, c.config.Grupo, e.mensaje.ID)
+		tuberia.XDel(ctx, e.stream, e.mensaje.ID)
+		return nil
+	})
+	if err != nil {
+		c.logger.Warn("No se pudo archivar la entrada, se volverá a reclamar",
+			"stream", e.stream,
+			"id", e.mensaje.ID,
+			"error", err,
+		)
+		return
+	}
+	c.logger.Error("Entrada de Redis Streams archivada tras agotar sus entregas",
+		"stream", e.stream,
+		"id", e.mensaje.ID,
+		"entregas", entregas,
+	)
+	metricaConsumidas.WithLabelValues("desconocida", "muerta").Inc()
+}
+
+// confirmar saca la entrada de las pendientes del grupo y la borra del stream, que así no crece
+// con lo ya entregado
+func (c *ConsumidorRedisStreams) confirmar(ctx context.Context, e entrada) {
+	_, err := c.cliente.TxPipelined(ctx, func(tuberia redis.Pipeliner) error {
+		tuberia.XAck(ctx, e.stream, c.config.Grupo, e.mensaje.ID)
+		tuberia.XDel(ctx, e.stream, e.mensaje.ID)
+		return nil
+	})
+	if err != nil {
+		c.logger.Warn("No se pudo confirmar la entrada, otro trabajador la reclamará",
+			"stream", e.stream,
+			"id", e.mensaje.ID,
+			"error", err,
+		)
+	}
+}
+
+// esperarTrasError pausa tras un error de lectura. Si Redis perdió los streams o el grupo (p. ej.
+// tras reiniciarse sin persistencia) los vuelve a crear.
+func (c *ConsumidorRedisStreams) esperarTrasError(ctx context.Context, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	c.logger.Error("Error leyendo de Redis Streams", "error", err)
+	if strings.HasPrefix(err.Error(), "NOGROUP") {
+		if err := crearGrupos(ctx, c.cliente, c.config); err != nil {
+			c.logger.Error("Error recreando los grupos de Redis Streams", "error", err)
+		}
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(c.config.EsperaLectura):
+	}
+}
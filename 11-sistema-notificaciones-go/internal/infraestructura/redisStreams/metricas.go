@@ -0,0 +1,23 @@
+package redisStreams
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricaPublicaciones = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "notificaciones_redis_streams_publicadas_total",
+		Help: "Notificaciones agregadas a Redis Streams por resultado (ok o error)",
+	}, []string{"resultado"})
+
+	metricaConsumidas = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "notificaciones_redis_streams_consumidas_total",
+		Help: "Notificaciones consumidas de Redis Streams por prioridad y resultado",
+	}, []string{"prioridad", "resultado"})
+
+	metricaReclamadas = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "notificaciones_redis_streams_reclamadas_total",
+		Help: "Entradas pendientes de trabajadores caídos reclamadas por otro trabajador",
+	})
+)
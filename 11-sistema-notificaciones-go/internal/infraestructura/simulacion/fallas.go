@@ -0,0 +1,166 @@
+package simulacion
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// ModoFalla define cómo falla un envío simulado, imitando las respuestas de un proveedor real
+type ModoFalla string
+
+const (
+	// FallaTiempoAgotado retiene el envío el tiempo configurado y falla sin respuesta
+	FallaTiempoAgotado ModoFalla = "tiempo_agotado"
+	// FallaErrorServidor responde 503, como un proveedor caído
+	FallaErrorServidor ModoFalla = "error_servidor"
+	// FallaTokenInvalido responde 401, como una credencial revocada
+	FallaTokenInvalido ModoFalla = "token_invalido"
+	// FallaLimiteTasa responde 429 con la espera indicada en Retry-After
+	FallaLimiteTasa ModoFalla = "limite_tasa"
+)
+
+// modosFalla fija el orden en que se sortean las tasas, para que una semilla dé siempre la misma secuencia
+var modosFalla = []ModoFalla{FallaTiempoAgotado, FallaErrorServidor, FallaTokenInvalido, FallaLimiteTasa}
+
+// ErrorProveedorSimulado es el error que retorna un proveedor simulado al fallar
+type ErrorProveedorSimulado struct {
+	Modo       ModoFalla
+	CodigoHTTP int
+	// ReintentarEn es la espera que pide el proveedor al limitar la tasa
+	ReintentarEn time.Duration
+}
+
+// NuevoErrorSimulado crea el error que produce el modo indicado
+func NuevoErrorSimulado(modo ModoFalla) *ErrorProveedorSimulado {
+	switch modo {
+	case FallaErrorServidor:
+		return &ErrorProveedorSimulado{Modo: modo, CodigoHTTP: http.StatusServiceUnavailable}
+	case FallaTokenInvalido:
+		return &ErrorProveedorSimulado{Modo: modo, CodigoHTTP: http.StatusUnauthorized}
+	case FallaLimiteTasa:
+		return &ErrorProveedorSimulado{Modo: modo, CodigoHTTP: http.StatusTooManyRequests, ReintentarEn: time.Second}
+	default:
+		return &ErrorProveedorSimulado{Modo: FallaTiempoAgotado}
+	}
+}
+
+func (e *ErrorProveedorSimulado) Error() string {
+	if e.CodigoHTTP == 0 {
+		return fmt.Sprintf("proveedor simulado: %s", e.Modo)
+	}
+	return fmt.Sprintf("proveedor simulado: %s (HTTP %d)", e.Modo, e.CodigoHTTP)
+}
+
+// Timeout indica si el envío falló por tiempo agotado
+func (e *ErrorProveedorSimulado) Timeout() bool {
+	return e.Modo == FallaTiempoAgotado
+}
+
+// Temporal indica si reintentar puede funcionar; un token inválido falla hasta que se corrija
+func (e *ErrorProveedorSimulado) Temporal() bool {
+	return e.Modo != FallaTokenInvalido
+}
+
+// DistribucionLatencia define cómo se sortea la latencia de cada envío simulado
+type DistribucionLatencia string
+
+const (
+	// LatenciaFija demora siempre Base
+	LatenciaFija DistribucionLatencia = "fija"
+	// LatenciaUniforme demora entre Base y Maxima con igual probabilidad
+	LatenciaUniforme DistribucionLatencia = "uniforme"
+	// LatenciaExponencial demora en promedio Base con cola larga, acotada por Maxima si no es 0
+	LatenciaExponencial DistribucionLatencia = "exponencial"
+)
+
+// Latencia describe la demora de cada envío; la distribución vacía no demora
+type Latencia struct {
+	Distribucion DistribucionLatencia
+	Base         time.Duration
+	Maxima       time.Duration
+}
+
+// Validar verifica que la latencia pueda sortearse
+func (l Latencia) Validar() error {
+	switch l.Distribucion {
+	case "", LatenciaFija, LatenciaExponencial:
+	case LatenciaUniforme:
+		if l.Maxima < l.Base {
+			return fmt.Errorf("la latencia máxima no puede ser menor que la base")
+		}
+	default:
+		return fmt.Errorf("distribución de latencia inválida: %q", l.Distribucion)
+	}
+	if l.Base < 0 || l.Maxima < 0 {
+		return fmt.Errorf("la latencia no puede ser negativa")
+	}
+	return nil
+}
+
+func (l Latencia) sortear(aleatorio *rand.Rand) time.Duration {
+	switch l.Distribucion {
+	case LatenciaFija:
+		return l.Base
+	case LatenciaUniforme:
+		return l.Base + time.Duration(aleatorio.Int63n(int64(l.Maxima-l.Base)+1))
+	case LatenciaExponencial:
+		demora := time.Duration(aleatorio.ExpFloat64() * float64(l.Base))
+		if l.Maxima > 0 {
+			demora = min(demora, l.Maxima)
+		}
+		return demora
+	default:
+		return 0
+	}
+}
+
+// ConfiguracionFallas define las fallas que inyecta un proveedor simulado en cada envío
+type ConfiguracionFallas struct {
+	// Tasas es la probabilidad, entre 0 y 1, de que un envío falle con cada modo
+	Tasas    map[ModoFalla]float64
+	Latencia Latencia
+	// TiempoAgotado es cuánto se retiene un envío antes de fallar por tiempo agotado;
+	// si el contexto vence antes, falla con el error del contexto
+	TiempoAgotado time.Duration
+}
+
+// Validar verifica los modos, las tasas y la latencia
+func (c ConfiguracionFallas) Validar() error {
+	suma := 0.0
+	for modo, tasa := range c.Tasas {
+		switch modo {
+		case FallaTiempoAgotado, FallaErrorServidor, FallaTokenInvalido, FallaLimiteTasa:
+		default:
+			return fmt.Errorf("modo de falla inválido: %q", modo)
+		}
+		if tasa < 0 || tasa > 1 || math.IsNaN(tasa) {
+			return fmt.Errorf("la tasa de %s debe estar entre 0 y 1", modo)
+		}
+		suma += tasa
+	}
+	if suma > 1 {
+		return fmt.Errorf("las tasas de falla suman %.2f, más que 1", suma)
+	}
+	if c.TiempoAgotado < 0 {
+		return fmt.Errorf("el tiempo agotado no puede ser negativo")
+	}
+	return c.Latencia.Validar()
+}
+
+// sortearFalla elige con un único sorteo el modo de falla del envío, o "" si no falla
+func (c ConfiguracionFallas) sortearFalla(aleatorio *rand.Rand) ModoFalla {
+	if len(c.Tasas) == 0 {
+		return ""
+	}
+	sorteo, acumulada := aleatorio.Float64(), 0.0
+	for _, modo := range modosFalla {
+		acumulada += c.Tasas[modo]
+		if sorteo < acumulada {
+			return modo
+		}
+	}
+	return ""
+}
@@ -0,0 +1,187 @@
+// Package simulacion provee los enviadores en memoria del modo sandbox y de los proveedores
+// simulados, con fallas y latencia configurables. pkg/pruebas los reexporta como dobles de prueba.
+package simulacion
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// maxEnviadasRegistradas acota la memoria de un proveedor que queda corriendo en un entorno de prueba
+const maxEnviadasRegistradas = 10000
+
+// ProveedorSimulado es un enviador en memoria que registra lo que se le pide enviar en lugar de
+// entregarlo. Por defecto todo envío es exitoso e inmediato: FallarCon programa errores puntuales y
+// ConfigurarFallas inyecta fallas y latencia aleatorias para ejercitar reintentos.
+type ProveedorSimulado struct {
+	tipo       entidad.TipoNotificacion
+	mu         sync.Mutex
+	enviadas   []entidad.Notificacion
+	fallos     []error
+	permanente error
+	fallas     ConfiguracionFallas
+	aleatorio  *rand.Rand
+	inyectadas map[ModoFalla]int64
+}
+
+// NuevoProveedorSimulado crea un proveedor del tipo sin envíos registrados
+func NuevoProveedorSimulado(tipo entidad.TipoNotificacion) *ProveedorSimulado {
+	return &ProveedorSimulado{
+		tipo:       tipo,
+		aleatorio:  rand.New(rand.NewSource(time.Now().UnixNano())),
+		inyectadas: make(map[ModoFalla]int64),
+	}
+}
+
+// Tipo implementa servicio.ProveedorNotificacion
+func (p *ProveedorSimulado) Tipo() entidad.TipoNotificacion {
+	return p.tipo
+}
+
+// EntregaLocal implementa servicio.EnviadorLocal: no envía a ningún proveedor externo
+func (p *ProveedorSimulado) EntregaLocal() bool {
+	return true
+}
+
+// Enviar registra la notificación o falla según lo programado: primero los errores de
+// FallarCon, luego el de FallarSiempre y por último las fallas aleatorias. Los envíos
+// fallidos no se registran, igual que un proveedor real que rechaza el mensaje.
+func (p *ProveedorSimulado) Enviar(ctx context.Context, notificacion *entidad.Notificacion) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	if len(p.fallos) > 0 {
+		err := p.fallos[0]
+		p.fallos = p.fallos[1:]
+		p.mu.Unlock()
+		return err
+	}
+	if p.permanente != nil {
+		err := p.permanente
+		p.mu.Unlock()
+		return err
+	}
+	demora := p.fallas.Latencia.sortear(p.aleatorio)
+	modo := p.fallas.sortearFalla(p.aleatorio)
+	if modo == FallaTiempoAgotado {
+		demora += p.fallas.TiempoAgotado
+	}
+	if modo != "" {
+		p.inyectadas[modo]++
+	}
+	p.mu.Unlock()
+
+	// La demora corre sin el bloqueo para no serializar los envíos concurrentes
+	if err := esperar(ctx, demora); err != nil {
+		return err
+	}
+	if modo != "" {
+		return NuevoErrorSimulado(modo)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.enviadas) == maxEnviadasRegistradas {
+		p.enviadas = append(p.enviadas[:0], p.enviadas[1:]...)
+	}
+	p.enviadas = append(p.enviadas, *notificacion)
+	return nil
+}
+
+// FallarCon hace fallar los próximos envíos, uno por error y en orden
+func (p *ProveedorSimulado) FallarCon(errores ...error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fallos = append(p.fallos, errores...)
+}
+
+// FallarSiempre hace fallar todo envío con el error indicado; nil lo restablece
+func (p *ProveedorSimulado) FallarSiempre(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.permanente = err
+}
+
+// ConfigurarFallas reemplaza las fallas y la latencia inyectadas en cada envío
+func (p *ProveedorSimulado) ConfigurarFallas(fallas ConfiguracionFallas) error {
+	if err := fallas.Validar(); err != nil {
+		return err
+	}
+	copia := fallas
+	copia.Tasas = make(map[ModoFalla]float64, len(fallas.Tasas))
+	for modo, tasa := range fallas.Tasas {
+		copia.Tasas[modo] = tasa
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fallas = copia
+	return nil
+}
+
+// Fallas retorna la configuración de fallas vigente
+func (p *ProveedorSimulado) Fallas() ConfiguracionFallas {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	copia := p.fallas
+	copia.Tasas = make(map[ModoFalla]float64, len(p.fallas.Tasas))
+	for modo, tasa := range p.fallas.Tasas {
+		copia.Tasas[modo] = tasa
+	}
+	return copia
+}
+
+// Inyectadas retorna cuántas fallas aleatorias se inyectaron por modo
+func (p *ProveedorSimulado) Inyectadas() map[ModoFalla]int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	copia := make(map[ModoFalla]int64, len(p.inyectadas))
+	for modo, cantidad := range p.inyectadas {
+		copia[modo] = cantidad
+	}
+	return copia
+}
+
+// Semilla fija la semilla de los sorteos, para que una prueba obtenga siempre la misma secuencia
+func (p *ProveedorSimulado) Semilla(semilla int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.aleatorio = rand.New(rand.NewSource(semilla))
+}
+
+// Enviadas retorna una copia de las notificaciones enviadas con éxito
+func (p *ProveedorSimulado) Enviadas() []entidad.Notificacion {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]entidad.Notificacion(nil), p.enviadas...)
+}
+
+// Reiniciar descarta los envíos registrados, los errores programados y las fallas configuradas
+func (p *ProveedorSimulado) Reiniciar() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.enviadas, p.fallos, p.permanente = nil, nil, nil
+	p.fallas = ConfiguracionFallas{}
+	p.inyectadas = make(map[ModoFalla]int64)
+}
+
+// esperar demora el envío respetando la cancelación del contexto
+func esperar(ctx context.Context, demora time.Duration) error {
+	if demora <= 0 {
+		return nil
+	}
+	temporizador := time.NewTimer(demora)
+	defer temporizador.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-temporizador.C:
+		return nil
+	}
+}
@@ -0,0 +1,145 @@
+package simulacion
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+func nuevaNotificacion() *entidad.Notificacion {
+	return entidad.NuevaNotificacion(1, "Título", "Mensaje", entidad.TipoEmail)
+}
+
+func TestEnviarAplicaPrimeroLosFallosProgramadosYLuegoElPermanente(t *testing.T) {
+	ctx := context.Background()
+	proveedor := NuevoProveedorSimulado(entidad.TipoEmail)
+	errUno, errDos, errSiempre := errors.New("uno"), errors.New("dos"), errors.New("siempre")
+	proveedor.FallarCon(errUno, errDos)
+	proveedor.FallarSiempre(errSiempre)
+	notificacion := nuevaNotificacion()
+	notificacion.ID = 1
+
+	for _, esperado := range []error{errUno, errDos, errSiempre, errSiempre} {
+		if err := proveedor.Enviar(ctx, notificacion); !errors.Is(err, esperado) {
+			t.Fatalf("err = %v, se esperaba %v", err, esperado)
+		}
+	}
+	proveedor.FallarSiempre(nil)
+	if err := proveedor.Enviar(ctx, notificacion); err != nil {
+		t.Fatalf("tras restablecer: %v", err)
+	}
+	if enviadas := proveedor.Enviadas(); len(enviadas) != 1 || enviadas[0].ID != 1 {
+		t.Errorf("enviadas = %d, solo debía registrarse el envío exitoso", len(enviadas))
+	}
+}
+
+func TestEnviarConElContextoCanceladoNoRegistra(t *testing.T) {
+	ctx, cancelar := context.WithCancel(context.Background())
+	cancelar()
+	proveedor := NuevoProveedorSimulado(entidad.TipoEmail)
+
+	if err := proveedor.Enviar(ctx, nuevaNotificacion()); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, se esperaba context.Canceled", err)
+	}
+	if len(proveedor.Enviadas()) != 0 {
+		t.Error("se registró un envío cancelado")
+	}
+}
+
+func TestLaMismaSemillaRepiteLaSecuenciaDeFallas(t *testing.T) {
+	fallas := ConfiguracionFallas{Tasas: map[ModoFalla]float64{FallaErrorServidor: 0.3, FallaLimiteTasa: 0.2}}
+	secuencia := func() []string {
+		proveedor := NuevoProveedorSimulado(entidad.TipoSMS)
+		if err := proveedor.ConfigurarFallas(fallas); err != nil {
+			t.Fatalf("configurando fallas: %v", err)
+		}
+		proveedor.Semilla(42)
+		resultados := make([]string, 50)
+		for i := range resultados {
+			if err := proveedor.Enviar(context.Background(), nuevaNotificacion()); err != nil {
+				resultados[i] = err.Error()
+			}
+		}
+		return resultados
+	}
+
+	primera, segunda := secuencia(), secuencia()
+	fallidas := 0
+	for i := range primera {
+		if primera[i] != segunda[i] {
+			t.Fatalf("envío %d: %q y %q difieren con la misma semilla", i, primera[i], segunda[i])
+		}
+		if primera[i] != "" {
+			fallidas++
+		}
+	}
+	if fallidas == 0 || fallidas == len(primera) {
+		t.Errorf("fallaron %d de %d envíos con una tasa total de 0.5", fallidas, len(primera))
+	}
+}
+
+func TestErrorSimuladoSegunElModo(t *testing.T) {
+	casos := []struct {
+		modo     ModoFalla
+		codigo   int
+		temporal bool
+		timeout  bool
+	}{
+		{FallaTiempoAgotado, 0, true, true},
+		{FallaErrorServidor, 503, true, false},
+		{FallaTokenInvalido, 401, false, false},
+		{FallaLimiteTasa, 429, true, false},
+	}
+	for _, caso := range casos {
+		err := NuevoErrorSimulado(caso.modo)
+		if err.CodigoHTTP != caso.codigo || err.Temporal() != caso.temporal || err.Timeout() != caso.timeout {
+			t.Errorf("%s: código %d, temporal %v, timeout %v", caso.modo, err.CodigoHTTP, err.Temporal(), err.Timeout())
+		}
+	}
+}
+
+func TestValidarConfiguracionFallas(t *testing.T) {
+	casos := []struct {
+		nombre string
+		fallas ConfiguracionFallas
+		valida bool
+	}{
+		{"vacía", ConfiguracionFallas{}, true},
+		{"tasas que suman 1", ConfiguracionFallas{Tasas: map[ModoFalla]float64{FallaErrorServidor: 0.5, FallaTiempoAgotado: 0.5}}, true},
+		{"modo desconocido", ConfiguracionFallas{Tasas: map[ModoFalla]float64{"caida": 0.1}}, false},
+		{"tasa negativa", ConfiguracionFallas{Tasas: map[ModoFalla]float64{FallaErrorServidor: -0.1}}, false},
+		{"tasas que suman más de 1", ConfiguracionFallas{Tasas: map[ModoFalla]float64{FallaErrorServidor: 0.6, FallaLimiteTasa: 0.6}}, false},
+		{"tiempo agotado negativo", ConfiguracionFallas{TiempoAgotado: -time.Second}, false},
+		{"uniforme con máxima menor", ConfiguracionFallas{Latencia: Latencia{Distribucion: LatenciaUniforme, Base: time.Second, Maxima: time.Millisecond}}, false},
+		{"distribución desconocida", ConfiguracionFallas{Latencia: Latencia{Distribucion: "normal"}}, false},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nombre, func(t *testing.T) {
+			if err := caso.fallas.Validar(); (err == nil) != caso.valida {
+				t.Errorf("Validar() = %v, se esperaba válida = %v", err, caso.valida)
+			}
+		})
+	}
+}
+
+func TestLatenciaSorteadaDentroDeLosLimites(t *testing.T) {
+	aleatorio := rand.New(rand.NewSource(1))
+	uniforme := Latencia{Distribucion: LatenciaUniforme, Base: 10 * time.Millisecond, Maxima: 20 * time.Millisecond}
+	exponencial := Latencia{Distribucion: LatenciaExponencial, Base: 10 * time.Millisecond, Maxima: 30 * time.Millisecond}
+
+	for i := 0; i < 1000; i++ {
+		if demora := uniforme.sortear(aleatorio); demora < uniforme.Base || demora > uniforme.Maxima {
+			t.Fatalf("uniforme: %v fuera de [%v, %v]", demora, uniforme.Base, uniforme.Maxima)
+		}
+		if demora := exponencial.sortear(aleatorio); demora < 0 || demora > exponencial.Maxima {
+			t.Fatalf("exponencial: %v fuera de [0, %v]", demora, exponencial.Maxima)
+		}
+	}
+	if demora := (Latencia{Distribucion: LatenciaFija, Base: time.Second}).sortear(aleatorio); demora != time.Second {
+		t.Errorf("fija: %v, se esperaba 1s", demora)
+	}
+}
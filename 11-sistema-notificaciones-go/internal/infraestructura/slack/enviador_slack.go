@@ -0,0 +1,171 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/internal/infraestructura/clienteHTTP"
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+)
+
+const (
+	// ProveedorSlack identifica las credenciales propias de un inquilino y el cliente HTTP
+	// saliente de este enviador
+	ProveedorSlack = "slack"
+
+	// Límites de Slack para el texto de un bloque header y de un bloque section
+	maximoEncabezado = 150
+	maximoSeccion    = 3000
+)
+
+// erroresPermanentes son los errores de Slack que reintentar no resuelve: el destino o el
+// token del canal están mal configurados
+var erroresPermanentes = map[string]bool{
+	"channel_not_found": true, "not_in_channel": true, "is_archived": true,
+	"invalid_auth": true, "not_authed": true, "account_inactive": true, "token_revoked": true,
+	"invalid_blocks": true, "invalid_payload": true, "no_service": true, "action_prohibited": true,
+}
+
+// escapeSlack escapa los caracteres de control del formato de texto de Slack
+var escapeSlack = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// EnviadorSlack entrega notificaciones TipoSlack en el canal de Slack configurado en el canal
+// de la notificación, por incoming webhook o por chat.postMessage
+type EnviadorSlack struct {
+	config           configuracion.ConfiguracionSlack
+	repositorioCanal repositorio.RepositorioCanal
+	cliente          *http.Client
+}
+
+// NuevoEnviadorSlack crea una nueva instancia de EnviadorSlack
+func NuevoEnviadorSlack(config configuracion.ConfiguracionSlack, repositorioCanal repositorio.RepositorioCanal, cliente *http.Client) *EnviadorSlack {
+	return &EnviadorSlack{config: config, repositorioCanal: repositorioCanal, cliente: cliente}
+}
+
+// Tipo implementa servicio.ProveedorNotificacion
+func (e *EnviadorSlack) Tipo() entidad.TipoNotificacion {
+	return entidad.TipoSlack
+}
+
+// Proveedor implementa servicio.EnviadorConCredenciales. Las credenciales del inquilino
+// admiten la clave token, el token de su propio bot.
+func (e *EnviadorSlack) Proveedor() string {
+	return ProveedorSlack
+}
+
+// SoportaIdempotencia implementa servicio.EnviadorIdempotente según SLACK_IDEMPOTENTE
+func (e *EnviadorSlack) SoportaIdempotencia() bool {
+	return e.config.Idempotente
+}
+
+// Enviar publica el título como encabezado y el mensaje como texto
+func (e *EnviadorSlack) Enviar(ctx context.Context, notificacion *entidad.Notificacion) error {
+	if notificacion.CanalID == 0 {
+		return entidad.NewErrorValidacion("las notificaciones slack requieren canal_id")
+	}
+	canal, err := e.repositorioCanal.ObtenerPorID(ctx, notificacion.CanalID)
+	if err != nil {
+		return err
+	}
+	destino, ok := canal.DestinoSlack()
+	if !ok {
+		return entidad.NewErrorValidacion(fmt.Sprintf("el canal %d no tiene destino de Slack", canal.ID))
+	}
+
+	encabezado, _ := entidad.TruncarTexto(notificacion.Titulo, maximoEncabezado)
+	seccion, _ := entidad.TruncarTexto(escapeSlack.Replace(notificacion.Mensaje), maximoSeccion)
+	mensaje := map[string]interface{}{
+		// text es lo que muestran las notificaciones del cliente de Slack
+		"text": escapeSlack.Replace(notificacion.Titulo + ": " + notificacion.Mensaje),
+		"blocks": []map[string]interface{}{
+			{"type": "header", "text": map[string]interface{}{"type": "plain_text", "text": encabezado}},
+			{"type": "section", "text": map[string]interface{}{"type": "mrkdwn", "text": seccion}},
+		},
+	}
+
+	if destino.WebhookURL != "" {
+		return e.publicarWebhook(ctx, destino.WebhookURL, mensaje)
+	}
+	mensaje["channel"] = destino.CanalSlack
+	return e.publicarAPI(ctx, mensaje)
+}
+
+// publicarWebhook envía al incoming webhook, que responde "ok" o el error en texto plano
+func (e *EnviadorSlack) publicarWebhook(ctx context.Context, webhook string, mensaje map[string]interface{}) error {
+	estado, detalle, err := e.publicar(ctx, webhook, "", mensaje)
+	if err != nil {
+		return err
+	}
+	if estado == http.StatusOK {
+		return nil
+	}
+	codigo := strings.TrimSpace(string(detalle))
+	if erroresPermanentes[codigo] {
+		return entidad.NewErrorValidacion("Slack rechazó el mensaje: " + codigo)
+	}
+	return fmt.Errorf("webhook de Slack respondió %d: %s", estado, codigo)
+}
+
+// publicarAPI llama a chat.postMessage con el token del inquilino o el de la plataforma;
+// Slack responde 200 también ante errores, con ok=false y el código en error
+func (e *EnviadorSlack) publicarAPI(ctx context.Context, mensaje map[string]interface{}) error {
+	token := e.config.Token
+	if credenciales, ok := servicio.CredencialesDesdeContexto(ctx); ok && credenciales["token"] != "" {
+		token = credenciales["token"]
+	}
+	if token == "" {
+		return fmt.Errorf("token de Slack no configurado")
+	}
+
+	estado, detalle, err := e.publicar(ctx, strings.TrimRight(e.config.URLAPI, "/")+"/chat.postMessage", token, mensaje)
+	if err != nil {
+		return err
+	}
+	var resultado struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if estado != http.StatusOK || json.Unmarshal(detalle, &resultado) != nil {
+		return fmt.Errorf("Slack respondió %d: %s", estado, bytes.TrimSpace(detalle))
+	}
+	switch {
+	case resultado.OK:
+		return nil
+	case erroresPermanentes[resultado.Error]:
+		return entidad.NewErrorValidacion("Slack rechazó el mensaje: " + resultado.Error)
+	default:
+		return fmt.Errorf("Slack respondió %s", resultado.Error)
+	}
+}
+
+func (e *EnviadorSlack) publicar(ctx context.Context, direccion, token string, mensaje map[string]interface{}) (int, []byte, error) {
+	cuerpo, err := json.Marshal(mensaje)
+	if err != nil {
+		return 0, nil, err
+	}
+	solicitud, err := http.NewRequestWithContext(ctx, http.MethodPost, direccion, bytes.NewReader(cuerpo))
+	if err != nil {
+		return 0, nil, err
+	}
+	solicitud.Header.Set("Content-Type", "application/json; charset=utf-8")
+	clienteHTTP.AgregarIdempotencia(solicitud)
+	if token != "" {
+		solicitud.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	respuesta, err := e.cliente.Do(solicitud)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer respuesta.Body.Close()
+	detalle, _ := io.ReadAll(io.LimitReader(respuesta.Body, 4096))
+	return respuesta.StatusCode, detalle, nil
+}
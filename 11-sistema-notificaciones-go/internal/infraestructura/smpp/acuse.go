@@ -0,0 +1,115 @@
+package smpp
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Parámetros opcionales (TLV) de los acuses de entrega
+const (
+	tlvIDMensajeAcusado uint16 = 0x001E
+	tlvEstadoMensaje    uint16 = 0x0427
+)
+
+// estadosMensaje traduce message_state al estado de texto del acuse
+var estadosMensaje = map[byte]string{
+	1: "ENROUTE", 2: "DELIVRD", 3: "EXPIRED", 4: "DELETED",
+	5: "UNDELIV", 6: "ACCEPTD", 7: "UNKNOWN", 8: "REJECTD",
+}
+
+// estadosFinales indica si cada estado final es una entrega; los estados ausentes son
+// intermedios (ENROUTE, ACCEPTD) y no cambian la notificación
+var estadosFinales = map[string]bool{
+	"DELIVRD": true,
+	"EXPIRED": false, "DELETED": false, "UNDELIV": false, "UNKNOWN": false, "REJECTD": false,
+}
+
+// patronCampoAcuse lee los campos del texto del acuse ("id:... sub:... stat:DELIVRD err:000 ...")
+var patronCampoAcuse = regexp.MustCompile(`(?i)\b(id|stat|err):(\S+)`)
+
+// acuse es un deliver_sm con el estado de un mensaje enviado
+type acuse struct {
+	idMensaje   string
+	estado      string
+	codigoError string
+}
+
+// final indica si el estado es definitivo y, en ese caso, si el mensaje se entregó
+func (a acuse) final() (entregado, final bool) {
+	entregado, final = estadosFinales[a.estado]
+	return entregado, final
+}
+
+// leerAcuse interpreta el cuerpo de un deliver_sm; retorna false si no es un acuse, p. ej. un
+// SMS entrante. Los TLV receipted_message_id y message_state prevalecen sobre el texto.
+func leerAcuse(cuerpo []byte) (acuse, bool, error) {
+	lector := &lectorCuerpo{datos: cuerpo}
+	// service_type, origen (ton, npi, dirección) y destino (ton, npi, dirección)
+	if _, err := lector.cadena(); err != nil {
+		return acuse{}, false, err
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := lector.octetos(2); err != nil {
+			return acuse{}, false, err
+		}
+		if _, err := lector.cadena(); err != nil {
+			return acuse{}, false, err
+		}
+	}
+	esm, err := lector.octeto()
+	if err != nil {
+		return acuse{}, false, err
+	}
+	// protocol_id y priority_flag; schedule_delivery_time y validity_period van vacíos
+	if _, err := lector.octetos(2); err != nil {
+		return acuse{}, false, err
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := lector.cadena(); err != nil {
+			return acuse{}, false, err
+		}
+	}
+	// registered_delivery, replace_if_present_flag, data_coding y sm_default_msg_id
+	if _, err := lector.octetos(4); err != nil {
+		return acuse{}, false, err
+	}
+	longitud, err := lector.octeto()
+	if err != nil {
+		return acuse{}, false, err
+	}
+	texto, err := lector.octetos(int(longitud))
+	if err != nil {
+		return acuse{}, false, err
+	}
+	if esm&esmMascaraTipo != esmAcuse {
+		return acuse{}, false, nil
+	}
+
+	var resultado acuse
+	for _, campo := range patronCampoAcuse.FindAllStringSubmatch(string(texto), -1) {
+		var destino *string
+		switch strings.ToLower(campo[1]) {
+		case "id":
+			destino = &resultado.idMensaje
+		case "stat":
+			destino = &resultado.estado
+		case "err":
+			destino = &resultado.codigoError
+		}
+		// El texto libre del final puede repetir claves: vale la primera aparición
+		if *destino == "" {
+			*destino = campo[2]
+		}
+	}
+	parametros := lector.parametrosOpcionales()
+	if id, existe := parametros[tlvIDMensajeAcusado]; existe {
+		resultado.idMensaje = strings.TrimRight(string(id), "\x00")
+	}
+	if estado, existe := parametros[tlvEstadoMensaje]; existe && len(estado) == 1 {
+		if nombre, conocido := estadosMensaje[estado[0]]; conocido {
+			resultado.estado = nombre
+		}
+	}
+	resultado.estado = strings.ToUpper(resultado.estado)
+	return resultado, true, nil
+}
@@ -0,0 +1,145 @@
+package smpp
+
+import (
+	"fmt"
+	"unicode/utf16"
+)
+
+// Codificaciones (data_coding) del mensaje
+const (
+	codificacionGSM  byte = 0x00
+	codificacionUCS2 byte = 0x08
+)
+
+// Capacidad de un mensaje en unidades de su codificación (septetos GSM o unidades UTF-16).
+// Las partes de un mensaje concatenado ceden lugar al encabezado UDH de 6 octetos.
+const (
+	septetosSimple = 160
+	septetosParte  = 153
+	unidadesSimple = 70
+	unidadesParte  = 67
+	partesMaximas  = 255
+	escapeGSM      = 0x1B
+	longitudUDH    = 6
+	// iEIConcatenado es el elemento UDH de concatenación con referencia de 8 bits
+	iEIConcatenado = 0x00
+)
+
+// tablaGSM es el alfabeto GSM 03.38 básico en el orden de sus códigos; la posición 0x1B es
+// el escape hacia la tabla de extensión
+const tablaGSM = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞ\x1bÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+
+// extensionGSM son los caracteres que se escriben con escape seguido de su código
+var extensionGSM = map[rune]byte{
+	'\f': 0x0A, '^': 0x14, '{': 0x28, '}': 0x29, '\\': 0x2F,
+	'[': 0x3C, '~': 0x3D, ']': 0x3E, '|': 0x40, '€': 0x65,
+}
+
+var basicoGSM = func() map[rune]byte {
+	basico := make(map[rune]byte, 128)
+	codigo := 0
+	for _, caracter := range tablaGSM {
+		if caracter != escapeGSM {
+			basico[caracter] = byte(codigo)
+		}
+		codigo++
+	}
+	return basico
+}()
+
+// mensajeCodificado es el texto listo para submit_sm, partido si no entra en un mensaje
+type mensajeCodificado struct {
+	codificacion byte
+	partes       [][]byte
+}
+
+// codificar usa GSM 7 bits (un septeto por octeto, sin empaquetar) si todos los caracteres
+// están en el alfabeto GSM y UCS-2 si no, y parte el texto con encabezados UDH de
+// concatenación si excede un mensaje. Coincide con el conteo de entidad.SegmentosSMS.
+func codificar(texto string, referencia byte) (*mensajeCodificado, error) {
+	var unidades [][]byte
+	codificacion, simple, porParte := codificacionGSM, septetosSimple, septetosParte
+	if septetos, ok := septetosGSM(texto); ok {
+		unidades = septetos
+	} else {
+		codificacion, simple, porParte = codificacionUCS2, unidadesSimple, unidadesParte
+		unidades = unidadesUCS2(texto)
+	}
+
+	total := 0
+	for _, unidad := range unidades {
+		total += longitudUnidad(unidad, codificacion)
+	}
+	if total <= simple {
+		return &mensajeCodificado{codificacion: codificacion, partes: [][]byte{concatenar(unidades)}}, nil
+	}
+
+	// Un carácter (escape y código, o un par sustituto) nunca se parte entre dos mensajes
+	var grupos [][][]byte
+	var actual [][]byte
+	ocupado := 0
+	for _, unidad := range unidades {
+		largo := longitudUnidad(unidad, codificacion)
+		if ocupado+largo > porParte {
+			grupos = append(grupos, actual)
+			actual, ocupado = nil, 0
+		}
+		actual = append(actual, unidad)
+		ocupado += largo
+	}
+	grupos = append(grupos, actual)
+	if len(grupos) > partesMaximas {
+		return nil, fmt.Errorf("el mensaje ocupa %d partes y SMPP admite %d", len(grupos), partesMaximas)
+	}
+
+	mensaje := &mensajeCodificado{codificacion: codificacion, partes: make([][]byte, len(grupos))}
+	for i, grupo := range grupos {
+		udh := []byte{longitudUDH - 1, iEIConcatenado, 3, referencia, byte(len(grupos)), byte(i + 1)}
+		mensaje.partes[i] = append(udh, concatenar(grupo)...)
+	}
+	return mensaje, nil
+}
+
+// septetosGSM retorna cada carácter como sus septetos, o false si alguno no es GSM
+func septetosGSM(texto string) ([][]byte, bool) {
+	var caracteres [][]byte
+	for _, caracter := range texto {
+		if codigo, existe := basicoGSM[caracter]; existe {
+			caracteres = append(caracteres, []byte{codigo})
+		} else if codigo, existe := extensionGSM[caracter]; existe {
+			caracteres = append(caracteres, []byte{escapeGSM, codigo})
+		} else {
+			return nil, false
+		}
+	}
+	return caracteres, true
+}
+
+// unidadesUCS2 retorna cada carácter en UTF-16 big endian; fuera del plano básico es un par sustituto
+func unidadesUCS2(texto string) [][]byte {
+	var caracteres [][]byte
+	for _, caracter := range texto {
+		var codificado []byte
+		for _, unidad := range utf16.Encode([]rune{caracter}) {
+			codificado = append(codificado, byte(unidad>>8), byte(unidad))
+		}
+		caracteres = append(caracteres, codificado)
+	}
+	return caracteres
+}
+
+func longitudUnidad(unidad []byte, codificacion byte) int {
+	if codificacion == codificacionUCS2 {
+		return len(unidad) / 2
+	}
+	return len(unidad)
+}
+
+func concatenar(unidades [][]byte) []byte {
+	var datos []byte
+	for _, unidad := range unidades {
+		datos = append(datos, unidad...)
+	}
+	return datos
+}
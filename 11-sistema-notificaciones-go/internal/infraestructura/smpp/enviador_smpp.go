@@ -0,0 +1,286 @@
+package smpp
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+	"sistema-notificaciones-go/pkg/logger"
+)
+
+// ProveedorSMPP identifica las credenciales propias de un inquilino para este enviador
+const ProveedorSMPP = "smpp"
+
+// Tipo (TON) y plan (NPI) de numeración de las direcciones
+const (
+	tonInternacional  byte = 0x01
+	tonAlfanumerico   byte = 0x05
+	npiDesconocido    byte = 0x00
+	npiISDN           byte = 0x01
+	acuseSolicitado   byte = 0x01
+	acuseNoSolicitado byte = 0x00
+)
+
+// ReceptorAcuses aplica los acuses de entrega que llegan por las sesiones
+type ReceptorAcuses interface {
+	Acusar(ctx context.Context, acuse entidad.AcuseEntrega) error
+}
+
+// AcortadorEnlaces reemplaza las URL largas del mensaje por enlaces cortos
+type AcortadorEnlaces interface {
+	Acortar(ctx context.Context, notificacion *entidad.Notificacion) (string, error)
+}
+
+// cuenta son los datos de conexión y de identidad ante el SMSC: los de la plataforma, o los
+// del inquilino y su región si están en el contexto
+type cuenta struct {
+	host        string
+	puerto      int
+	sistemaID   string
+	clave       string
+	tipoSistema string
+	remitente   string
+}
+
+// EnviadorSMPP entrega notificaciones TipoSMS al teléfono del usuario directamente al SMSC del
+// operador. Mantiene una sesión por cuenta, abierta al primer envío y reabierta si se cae.
+type EnviadorSMPP struct {
+	config             configuracion.ConfiguracionSMPP
+	repositorioUsuario repositorio.RepositorioUsuario
+	registro           repositorio.RegistroMensajesProveedor
+	receptor           ReceptorAcuses
+	acortador          AcortadorEnlaces
+	logger             *logger.Logger
+
+	// referencia numera los mensajes concatenados para que el teléfono no mezcle sus partes
+	referencia atomic.Uint32
+
+	mu       sync.Mutex
+	sesiones map[cuenta]*sesion
+}
+
+// NuevoEnviadorSMPP crea una nueva instancia de EnviadorSMPP
+func NuevoEnviadorSMPP(
+	config configuracion.ConfiguracionSMPP,
+	repositorioUsuario repositorio.RepositorioUsuario,
+	registro repositorio.RegistroMensajesProveedor,
+	receptor ReceptorAcuses,
+	acortador AcortadorEnlaces,
+	log *logger.Logger,
+) *EnviadorSMPP {
+	return &EnviadorSMPP{
+		config:             config,
+		repositorioUsuario: repositorioUsuario,
+		registro:           registro,
+		receptor:           receptor,
+		acortador:          acortador,
+		logger:             log.Componente(logger.ComponenteProveedores),
+		sesiones:           make(map[cuenta]*sesion),
+	}
+}
+
+// Tipo implementa servicio.ProveedorNotificacion
+func (e *EnviadorSMPP) Tipo() entidad.TipoNotificacion {
+	return entidad.TipoSMS
+}
+
+// Proveedor implementa servicio.EnviadorConCredenciales. Las credenciales del inquilino
+// admiten las claves host, puerto, sistema_id, clave, tipo_sistema y remitente.
+func (e *EnviadorSMPP) Proveedor() string {
+	return ProveedorSMPP
+}
+
+// Enviar entrega el mensaje de la notificación, con sus URL acortadas, en una o más partes y,
+// si se piden acuses, registra el ID de cada parte para aplicarlos cuando lleguen
+func (e *EnviadorSMPP) Enviar(ctx context.Context, notificacion *entidad.Notificacion) error {
+	usuario, err := e.repositorioUsuario.ObtenerPorID(ctx, notificacion.UsuarioID)
+	if err != nil {
+		return err
+	}
+	if usuario.Telefono == "" {
+		return entidad.NewErrorValidacion("el usuario no tiene teléfono")
+	}
+	c, err := e.cuenta(ctx)
+	if err != nil {
+		return err
+	}
+	texto, err := e.acortador.Acortar(ctx, notificacion)
+	if err != nil {
+		// Sin acortar el mensaje puede ocupar más segmentos, pero llega igual
+		e.logger.Warn("No se pudieron acortar los enlaces del SMS", "notificacion_id", notificacion.ID, "error", err)
+	}
+	mensaje, err := codificar(texto, byte(e.referencia.Add(1)))
+	if err != nil {
+		return entidad.NewErrorValidacion(err.Error())
+	}
+	s, err := e.sesion(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	esm := byte(0)
+	if len(mensaje.partes) > 1 {
+		esm = esmConUDH
+	}
+	destino := strings.TrimPrefix(usuario.Telefono, "+")
+	ids := make([]string, 0, len(mensaje.partes))
+	for _, parte := range mensaje.partes {
+		id, err := s.enviar(ctx, e.cuerpoSubmitSM(c.remitente, destino, esm, mensaje.codificacion, parte))
+		if err != nil {
+			return fmt.Errorf("submit_sm (parte %d de %d): %w", len(ids)+1, len(mensaje.partes), err)
+		}
+		ids = append(ids, id)
+	}
+
+	if e.config.Acuses {
+		referencia := entidad.ReferenciaMensaje{InquilinoID: notificacion.InquilinoID, NotificacionID: notificacion.ID, Partes: len(ids)}
+		if err := e.registro.Registrar(ctx, ProveedorSMPP, ids, referencia, e.config.VigenciaAcuses); err != nil {
+			// El SMSC ya aceptó el mensaje: sin el registro solo se pierde su acuse
+			e.logger.Warn("No se pudo registrar el mensaje para su acuse", "notificacion_id", notificacion.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// cuerpoSubmitSM arma el submit_sm de una parte del mensaje
+func (e *EnviadorSMPP) cuerpoSubmitSM(remitente, destino string, esm, codificacion byte, mensaje []byte) []byte {
+	tonOrigen, npiOrigen := tonAlfanumerico, npiDesconocido
+	if numero := strings.TrimPrefix(remitente, "+"); esNumerico(numero) {
+		tonOrigen, npiOrigen, remitente = tonInternacional, npiISDN, numero
+	}
+	acuse := acuseNoSolicitado
+	if e.config.Acuses {
+		acuse = acuseSolicitado
+	}
+
+	var cuerpo escritorCuerpo
+	cuerpo.cadena("") // service_type
+	cuerpo.WriteByte(tonOrigen)
+	cuerpo.WriteByte(npiOrigen)
+	cuerpo.cadena(remitente)
+	cuerpo.WriteByte(tonInternacional)
+	cuerpo.WriteByte(npiISDN)
+	cuerpo.cadena(destino)
+	cuerpo.WriteByte(esm)
+	cuerpo.WriteByte(0) // protocol_id
+	cuerpo.WriteByte(0) // priority_flag
+	cuerpo.cadena("")   // schedule_delivery_time
+	cuerpo.cadena("")   // validity_period
+	cuerpo.WriteByte(acuse)
+	cuerpo.WriteByte(0) // replace_if_present_flag
+	cuerpo.WriteByte(codificacion)
+	cuerpo.WriteByte(0) // sm_default_msg_id
+	cuerpo.WriteByte(byte(len(mensaje)))
+	cuerpo.Write(mensaje)
+	return cuerpo.Bytes()
+}
+
+// cuenta resuelve la cuenta del envío: las credenciales del inquilino reemplazan a las de la
+// plataforma campo a campo y el endpoint regional (smpp://host:puerto) reemplaza al host
+func (e *EnviadorSMPP) cuenta(ctx context.Context) (cuenta, error) {
+	c := cuenta{
+		host:        e.config.Host,
+		puerto:      e.config.Puerto,
+		sistemaID:   e.config.SistemaID,
+		clave:       e.config.Clave,
+		tipoSistema: e.config.TipoSistema,
+		remitente:   e.config.Remitente,
+	}
+
+	if endpoint, ok := servicio.EndpointProveedorDesdeContexto(ctx); ok {
+		direccion, err := url.Parse(endpoint)
+		if err != nil || direccion.Hostname() == "" {
+			return c, fmt.Errorf("endpoint SMPP regional inválido: %q", endpoint)
+		}
+		c.host = direccion.Hostname()
+		if puerto, err := strconv.Atoi(direccion.Port()); err == nil {
+			c.puerto = puerto
+		}
+	}
+
+	if credenciales, ok := servicio.CredencialesDesdeContexto(ctx); ok {
+		for clave, destino := range map[string]*string{
+			"host": &c.host, "sistema_id": &c.sistemaID, "clave": &c.clave,
+			"tipo_sistema": &c.tipoSistema, "remitente": &c.remitente,
+		} {
+			if valor := credenciales[clave]; valor != "" {
+				*destino = valor
+			}
+		}
+		if puerto, err := strconv.Atoi(credenciales["puerto"]); err == nil {
+			c.puerto = puerto
+		}
+	}
+
+	if c.host == "" || c.sistemaID == "" || c.remitente == "" {
+		return c, fmt.Errorf("cuenta SMPP no configurada")
+	}
+	return c, nil
+}
+
+// sesion retorna la sesión abierta de la cuenta o abre una nueva
+func (e *EnviadorSMPP) sesion(ctx context.Context, c cuenta) (*sesion, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if s, existe := e.sesiones[c]; existe && s.activa() {
+		return s, nil
+	}
+	s, err := abrirSesion(ctx, c, e.config, e.recibirAcuse, e.logger)
+	if err != nil {
+		return nil, fmt.Errorf("conectando con el SMSC %s: %w", c.host, err)
+	}
+	e.sesiones[c] = s
+	return s, nil
+}
+
+// recibirAcuse aplica los acuses con estado final; los intermedios (ENROUTE, ACCEPTD) se ignoran
+func (e *EnviadorSMPP) recibirAcuse(a acuse) {
+	entregado, final := a.final()
+	if !final {
+		return
+	}
+	ctx, cancelar := context.WithTimeout(context.Background(), e.config.TimeoutRespuesta)
+	defer cancelar()
+	err := e.receptor.Acusar(ctx, entidad.AcuseEntrega{
+		Proveedor:   ProveedorSMPP,
+		IDMensaje:   a.idMensaje,
+		Estado:      a.estado,
+		Entregado:   entregado,
+		CodigoError: a.codigoError,
+	})
+	if err != nil {
+		e.logger.Error("Error aplicando el acuse de entrega SMPP", "id_mensaje", a.idMensaje, "estado", a.estado, "error", err)
+	}
+}
+
+// Cerrar desvincula todas las sesiones abiertas
+func (e *EnviadorSMPP) Cerrar(ctx context.Context) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for c, s := range e.sesiones {
+		s.desvincular(ctx)
+		delete(e.sesiones, c)
+	}
+}
+
+func esNumerico(texto string) bool {
+	if texto == "" {
+		return false
+	}
+	for _, caracter := range texto {
+		if caracter < '0' || caracter > '9' {
+			return false
+		}
+	}
+	return true
+}
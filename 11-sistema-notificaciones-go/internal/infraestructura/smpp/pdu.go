@@ -0,0 +1,168 @@
+// Package smpp entrega SMS directamente al SMSC de un operador por SMPP 3.4: una sesión
+// transceptora por cuenta, mensajes largos concatenados con UDH y acuses de entrega
+package smpp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Comandos SMPP 3.4 usados por el cliente; las respuestas llevan el bit alto encendido
+const (
+	comandoNackGenerico       uint32 = 0x80000000
+	comandoBindTransceptor    uint32 = 0x00000009
+	comandoBindTransceptorRsp uint32 = 0x80000009
+	comandoSubmitSM           uint32 = 0x00000004
+	comandoSubmitSMRsp        uint32 = 0x80000004
+	comandoDeliverSM          uint32 = 0x00000005
+	comandoDeliverSMRsp       uint32 = 0x80000005
+	comandoUnbind             uint32 = 0x00000006
+	comandoUnbindRsp          uint32 = 0x80000006
+	comandoEnquireLink        uint32 = 0x00000015
+	comandoEnquireLinkRsp     uint32 = 0x80000015
+
+	esRespuesta uint32 = 0x80000000
+)
+
+// Estados de comando con significado propio para el enviador
+const (
+	estadoOK              uint32 = 0x00000000
+	estadoDestinoInvalido uint32 = 0x0000000B
+	estadoColaLlena       uint32 = 0x00000014
+	estadoLimitado        uint32 = 0x00000058
+)
+
+// Bits de esm_class
+const (
+	// esmConUDH indica que el mensaje empieza con un encabezado UDH
+	esmConUDH byte = 0x40
+	// esmAcuse en el tipo de mensaje (bits 2 a 5) indica un acuse de entrega del SMSC
+	esmAcuse       byte = 0x04
+	esmMascaraTipo byte = 0x3C
+)
+
+const (
+	// longitudEncabezado es el tamaño fijo del encabezado de toda PDU
+	longitudEncabezado = 16
+	// longitudMaxima acota una PDU recibida; el protocolo no fija un máximo y un largo
+	// corrupto no debe reservar memoria sin límite
+	longitudMaxima = 64 * 1024
+)
+
+// pdu es una unidad de datos del protocolo: encabezado y cuerpo sin interpretar
+type pdu struct {
+	comando   uint32
+	estado    uint32
+	secuencia uint32
+	cuerpo    []byte
+}
+
+// ErrorComando es un estado distinto de cero en la respuesta del SMSC
+type ErrorComando struct {
+	Comando uint32
+	Estado  uint32
+}
+
+func (e *ErrorComando) Error() string {
+	return fmt.Sprintf("SMSC respondió el comando 0x%08X con estado 0x%08X", e.Comando, e.Estado)
+}
+
+// Transitorio indica si el SMSC rechazó por carga y conviene reintentar más tarde
+func (e *ErrorComando) Transitorio() bool {
+	return e.Estado == estadoColaLlena || e.Estado == estadoLimitado
+}
+
+func leerPDU(lector io.Reader) (pdu, error) {
+	var encabezado [longitudEncabezado]byte
+	if _, err := io.ReadFull(lector, encabezado[:]); err != nil {
+		return pdu{}, err
+	}
+	longitud := binary.BigEndian.Uint32(encabezado[0:4])
+	if longitud < longitudEncabezado || longitud > longitudMaxima {
+		return pdu{}, fmt.Errorf("PDU SMPP con longitud inválida: %d", longitud)
+	}
+	p := pdu{
+		comando:   binary.BigEndian.Uint32(encabezado[4:8]),
+		estado:    binary.BigEndian.Uint32(encabezado[8:12]),
+		secuencia: binary.BigEndian.Uint32(encabezado[12:16]),
+		cuerpo:    make([]byte, longitud-longitudEncabezado),
+	}
+	if _, err := io.ReadFull(lector, p.cuerpo); err != nil {
+		return pdu{}, err
+	}
+	return p, nil
+}
+
+func (p pdu) bytes() []byte {
+	datos := make([]byte, longitudEncabezado, longitudEncabezado+len(p.cuerpo))
+	binary.BigEndian.PutUint32(datos[0:4], uint32(longitudEncabezado+len(p.cuerpo)))
+	binary.BigEndian.PutUint32(datos[4:8], p.comando)
+	binary.BigEndian.PutUint32(datos[8:12], p.estado)
+	binary.BigEndian.PutUint32(datos[12:16], p.secuencia)
+	return append(datos, p.cuerpo...)
+}
+
+// escritorCuerpo arma el cuerpo de una PDU campo a campo
+type escritorCuerpo struct {
+	bytes.Buffer
+}
+
+// cadena escribe una C-Octet String: el texto terminado en NUL
+func (e *escritorCuerpo) cadena(texto string) {
+	e.WriteString(texto)
+	e.WriteByte(0)
+}
+
+// errCuerpoCorto indica una PDU que termina antes de sus campos obligatorios
+var errCuerpoCorto = errors.New("PDU SMPP incompleta")
+
+// lectorCuerpo recorre el cuerpo de una PDU campo a campo
+type lectorCuerpo struct {
+	datos []byte
+}
+
+func (l *lectorCuerpo) cadena() (string, error) {
+	fin := bytes.IndexByte(l.datos, 0)
+	if fin < 0 {
+		return "", errCuerpoCorto
+	}
+	texto := string(l.datos[:fin])
+	l.datos = l.datos[fin+1:]
+	return texto, nil
+}
+
+func (l *lectorCuerpo) octeto() (byte, error) {
+	if len(l.datos) < 1 {
+		return 0, errCuerpoCorto
+	}
+	valor := l.datos[0]
+	l.datos = l.datos[1:]
+	return valor, nil
+}
+
+func (l *lectorCuerpo) octetos(n int) ([]byte, error) {
+	if len(l.datos) < n {
+		return nil, errCuerpoCorto
+	}
+	valor := l.datos[:n]
+	l.datos = l.datos[n:]
+	return valor, nil
+}
+
+// parametrosOpcionales lee los TLV que quedan al final del cuerpo
+func (l *lectorCuerpo) parametrosOpcionales() map[uint16][]byte {
+	parametros := make(map[uint16][]byte)
+	for len(l.datos) >= 4 {
+		etiqueta := binary.BigEndian.Uint16(l.datos[0:2])
+		longitud := int(binary.BigEndian.Uint16(l.datos[2:4]))
+		if len(l.datos) < 4+longitud {
+			break
+		}
+		parametros[etiqueta] = l.datos[4 : 4+longitud]
+		l.datos = l.datos[4+longitud:]
+	}
+	return parametros
+}
@@ -0,0 +1,269 @@
+package smpp
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+	"sistema-notificaciones-go/pkg/logger"
+)
+
+// versionInterfaz es SMPP 3.4 en bind_transceiver
+const versionInterfaz = 0x34
+
+// estadoComandoInvalido responde con generic_nack los comandos que el cliente no atiende
+const estadoComandoInvalido uint32 = 0x00000003
+
+var (
+	errSesionCerrada = errors.New("sesión SMPP cerrada")
+	errSinRespuesta  = errors.New("el SMSC no respondió a tiempo")
+)
+
+// sesion es una conexión transceptora con el SMSC: por ella salen los submit_sm y llegan los
+// acuses. Las solicitudes se emparejan con sus respuestas por número de secuencia.
+type sesion struct {
+	conexion net.Conn
+	timeout  time.Duration
+	ventana  chan struct{}
+	alAcusar func(acuse)
+	logger   *logger.Logger
+
+	escritura sync.Mutex
+	secuencia atomic.Uint32
+
+	mu         sync.Mutex
+	pendientes map[uint32]chan pdu
+	cerrada    chan struct{}
+	errCierre  error
+}
+
+// abrirSesion conecta con el SMSC de la cuenta, se vincula como transceptor y mantiene el
+// enlace con enquire_link hasta que la sesión se cierre
+func abrirSesion(ctx context.Context, c cuenta, config configuracion.ConfiguracionSMPP, alAcusar func(acuse), log *logger.Logger) (*sesion, error) {
+	direccion := net.JoinHostPort(c.host, strconv.Itoa(c.puerto))
+	dialer := &net.Dialer{Timeout: config.TimeoutRespuesta, KeepAlive: 30 * time.Second}
+	var conexion net.Conn
+	var err error
+	if config.TLS {
+		conexion, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: c.host}}).DialContext(ctx, "tcp", direccion)
+	} else {
+		conexion, err = dialer.DialContext(ctx, "tcp", direccion)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s := &sesion{
+		conexion:   conexion,
+		timeout:    config.TimeoutRespuesta,
+		ventana:    make(chan struct{}, config.Ventana),
+		alAcusar:   alAcusar,
+		logger:     log,
+		pendientes: make(map[uint32]chan pdu),
+		cerrada:    make(chan struct{}),
+	}
+	go s.leer()
+
+	var cuerpo escritorCuerpo
+	cuerpo.cadena(c.sistemaID)
+	cuerpo.cadena(c.clave)
+	cuerpo.cadena(c.tipoSistema)
+	cuerpo.WriteByte(versionInterfaz)
+	cuerpo.WriteByte(0) // addr_ton
+	cuerpo.WriteByte(0) // addr_npi
+	cuerpo.cadena("")   // address_range
+	if _, err := s.solicitar(ctx, comandoBindTransceptor, cuerpo.Bytes()); err != nil {
+		s.cerrar(err)
+		return nil, fmt.Errorf("bind_transceiver: %w", err)
+	}
+
+	go s.mantener(config.IntervaloEnlace)
+	return s, nil
+}
+
+// activa indica si la sesión sigue abierta
+func (s *sesion) activa() bool {
+	select {
+	case <-s.cerrada:
+		return false
+	default:
+		return true
+	}
+}
+
+// enviar entrega un submit_sm y retorna el ID que el SMSC asignó al mensaje
+func (s *sesion) enviar(ctx context.Context, cuerpo []byte) (string, error) {
+	select {
+	case s.ventana <- struct{}{}:
+		defer func() { <-s.ventana }()
+	case <-s.cerrada:
+		return "", s.errCierre
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+	respuesta, err := s.solicitar(ctx, comandoSubmitSM, cuerpo)
+	if err != nil {
+		return "", err
+	}
+	return (&lectorCuerpo{datos: respuesta.cuerpo}).cadena()
+}
+
+// solicitar escribe la PDU y espera su respuesta
+func (s *sesion) solicitar(ctx context.Context, comando uint32, cuerpo []byte) (pdu, error) {
+	secuencia := s.siguienteSecuencia()
+	respuesta := make(chan pdu, 1)
+	s.mu.Lock()
+	if s.errCierre != nil {
+		s.mu.Unlock()
+		return pdu{}, s.errCierre
+	}
+	s.pendientes[secuencia] = respuesta
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.pendientes, secuencia)
+		s.mu.Unlock()
+	}()
+
+	if err := s.escribir(pdu{comando: comando, secuencia: secuencia, cuerpo: cuerpo}); err != nil {
+		s.cerrar(err)
+		return pdu{}, err
+	}
+
+	espera := time.NewTimer(s.timeout)
+	defer espera.Stop()
+	select {
+	case r := <-respuesta:
+		if r.comando == comandoNackGenerico || r.estado != estadoOK {
+			return r, &ErrorComando{Comando: comando, Estado: r.estado}
+		}
+		return r, nil
+	case <-s.cerrada:
+		return pdu{}, s.errCierre
+	case <-ctx.Done():
+		return pdu{}, ctx.Err()
+	case <-espera.C:
+		return pdu{}, errSinRespuesta
+	}
+}
+
+// siguienteSecuencia retorna un número de secuencia en el rango válido 1 a 0x7FFFFFFF
+func (s *sesion) siguienteSecuencia() uint32 {
+	if secuencia := s.secuencia.Add(1) & 0x7FFFFFFF; secuencia != 0 {
+		return secuencia
+	}
+	return s.secuencia.Add(1) & 0x7FFFFFFF
+}
+
+func (s *sesion) escribir(p pdu) error {
+	s.escritura.Lock()
+	defer s.escritura.Unlock()
+	if err := s.conexion.SetWriteDeadline(time.Now().Add(s.timeout)); err != nil {
+		return err
+	}
+	_, err := s.conexion.Write(p.bytes())
+	return err
+}
+
+// responder contesta una PDU del SMSC con la misma secuencia
+func (s *sesion) responder(solicitud pdu, comando, estado uint32, cuerpo []byte) {
+	if err := s.escribir(pdu{comando: comando, estado: estado, secuencia: solicitud.secuencia, cuerpo: cuerpo}); err != nil {
+		s.cerrar(err)
+	}
+}
+
+// leer recibe las PDU hasta que la conexión se cierre: entrega las respuestas a quien las
+// espera y atiende las solicitudes del SMSC
+func (s *sesion) leer() {
+	for {
+		p, err := leerPDU(s.conexion)
+		if err != nil {
+			s.cerrar(err)
+			return
+		}
+		if p.comando&esRespuesta != 0 {
+			s.mu.Lock()
+			respuesta := s.pendientes[p.secuencia]
+			s.mu.Unlock()
+			if respuesta != nil {
+				respuesta <- p
+			}
+			continue
+		}
+
+		switch p.comando {
+		case comandoDeliverSM:
+			// Se confirma antes de procesar para no frenar la lectura; el SMSC no reenvía un
+			// acuse ya confirmado, así que uno que falle al aplicarse solo queda en el log
+			s.responder(p, comandoDeliverSMRsp, estadoOK, []byte{0})
+			s.recibir(p)
+		case comandoEnquireLink:
+			s.responder(p, comandoEnquireLinkRsp, estadoOK, nil)
+		case comandoUnbind:
+			s.responder(p, comandoUnbindRsp, estadoOK, nil)
+			s.cerrar(errSesionCerrada)
+			return
+		default:
+			s.responder(p, comandoNackGenerico, estadoComandoInvalido, nil)
+		}
+	}
+}
+
+// recibir interpreta un deliver_sm; los SMS entrantes se ignoran
+func (s *sesion) recibir(p pdu) {
+	a, esAcuse, err := leerAcuse(p.cuerpo)
+	if err != nil {
+		s.logger.Warn("deliver_sm SMPP inválido", "error", err)
+		return
+	}
+	if esAcuse {
+		go s.alAcusar(a)
+	}
+}
+
+// mantener envía enquire_link cada intervalo y cierra la sesión si el SMSC deja de responder
+func (s *sesion) mantener(intervalo time.Duration) {
+	ticker := time.NewTicker(intervalo)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.cerrada:
+			return
+		case <-ticker.C:
+			if _, err := s.solicitar(context.Background(), comandoEnquireLink, nil); err != nil {
+				s.cerrar(fmt.Errorf("enquire_link: %w", err))
+				return
+			}
+		}
+	}
+}
+
+// desvincular pide el unbind y cierra la conexión
+func (s *sesion) desvincular(ctx context.Context) {
+	if s.activa() {
+		if _, err := s.solicitar(ctx, comandoUnbind, nil); err != nil {
+			s.logger.Debug("unbind SMPP sin respuesta", "error", err)
+		}
+	}
+	s.cerrar(errSesionCerrada)
+}
+
+// cerrar termina la sesión una sola vez; las solicitudes en curso reciben err
+func (s *sesion) cerrar(err error) {
+	s.mu.Lock()
+	if s.errCierre != nil {
+		s.mu.Unlock()
+		return
+	}
+	s.errCierre = err
+	close(s.cerrada)
+	s.mu.Unlock()
+	s.conexion.Close()
+}
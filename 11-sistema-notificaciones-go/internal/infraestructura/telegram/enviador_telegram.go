@@ -0,0 +1,119 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+)
+
+const (
+	// ProveedorTelegram identifica el cliente HTTP saliente de este enviador
+	ProveedorTelegram = "telegram"
+
+	// EncabezadoSecreto lleva el secret_token con que Telegram autentica las actualizaciones
+	EncabezadoSecreto = "X-Telegram-Bot-Api-Secret-Token"
+
+	// maximoMensaje es el límite de Telegram para el texto de un mensaje
+	maximoMensaje = 4096
+)
+
+// escapeMarkdown escapa los caracteres reservados de MarkdownV2 fuera de las entidades
+var escapeMarkdown = strings.NewReplacer(
+	`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`, "~", `\~`, "`", "\\`",
+	">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`, "|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+)
+
+// respuestaTelegram es la envoltura de las respuestas de la Bot API
+type respuestaTelegram struct {
+	OK          bool   `json:"ok"`
+	Descripcion string `json:"description"`
+}
+
+// EnviadorTelegram entrega notificaciones TipoTelegram en el chat que el usuario vinculó con el
+// bot, por sendMessage de la Bot API
+type EnviadorTelegram struct {
+	config             configuracion.ConfiguracionTelegram
+	repositorioUsuario repositorio.RepositorioUsuario
+	cliente            *http.Client
+}
+
+// NuevoEnviadorTelegram crea una nueva instancia de EnviadorTelegram
+func NuevoEnviadorTelegram(config configuracion.ConfiguracionTelegram, repositorioUsuario repositorio.RepositorioUsuario, cliente *http.Client) *EnviadorTelegram {
+	return &EnviadorTelegram{config: config, repositorioUsuario: repositorioUsuario, cliente: cliente}
+}
+
+// Tipo implementa servicio.ProveedorNotificacion
+func (e *EnviadorTelegram) Tipo() entidad.TipoNotificacion {
+	return entidad.TipoTelegram
+}
+
+// Enviar publica el título en negrita seguido del mensaje, ambos escapados para MarkdownV2
+func (e *EnviadorTelegram) Enviar(ctx context.Context, notificacion *entidad.Notificacion) error {
+	usuario, err := e.repositorioUsuario.ObtenerPorID(ctx, notificacion.UsuarioID)
+	if err != nil {
+		return err
+	}
+	if usuario.TelegramChatID == 0 {
+		return entidad.NewErrorValidacion("el usuario no vinculó un chat de Telegram")
+	}
+
+	// Se trunca antes de escapar: el límite cuenta el texto ya interpretado. El título tiene
+	// hasta 255 caracteres.
+	mensaje, _ := entidad.TruncarTexto(notificacion.Mensaje, maximoMensaje-len([]rune(notificacion.Titulo))-2)
+	texto := "*" + escapeMarkdown.Replace(notificacion.Titulo) + "*\n\n" + escapeMarkdown.Replace(mensaje)
+	return e.enviarMensaje(ctx, map[string]interface{}{
+		"chat_id":    usuario.TelegramChatID,
+		"text":       texto,
+		"parse_mode": "MarkdownV2",
+	})
+}
+
+// Responder envía un texto plano al chat; el bot lo usa para contestar /start
+func (e *EnviadorTelegram) Responder(ctx context.Context, chatID int64, texto string) error {
+	return e.enviarMensaje(ctx, map[string]interface{}{"chat_id": chatID, "text": texto})
+}
+
+// enviarMensaje llama a sendMessage. 400 (chat inexistente, formato inválido) y 403 (el
+// usuario bloqueó al bot) no se resuelven reintentando.
+func (e *EnviadorTelegram) enviarMensaje(ctx context.Context, mensaje map[string]interface{}) error {
+	if e.config.Token == "" {
+		return fmt.Errorf("bot de Telegram no configurado")
+	}
+	cuerpo, err := json.Marshal(mensaje)
+	if err != nil {
+		return err
+	}
+	direccion := strings.TrimRight(e.config.URLAPI, "/") + "/bot" + e.config.Token + "/sendMessage"
+	solicitud, err := http.NewRequestWithContext(ctx, http.MethodPost, direccion, bytes.NewReader(cuerpo))
+	if err != nil {
+		return err
+	}
+	solicitud.Header.Set("Content-Type", "application/json")
+
+	respuesta, err := e.cliente.Do(solicitud)
+	if err != nil {
+		// El error de net/http incluye la URL, que lleva el token del bot
+		return fmt.Errorf("no se pudo llamar a la API de Telegram: %s", strings.ReplaceAll(err.Error(), e.config.Token, "<token>"))
+	}
+	defer respuesta.Body.Close()
+	detalle, _ := io.ReadAll(io.LimitReader(respuesta.Body, 4096))
+	var resultado respuestaTelegram
+	_ = json.Unmarshal(detalle, &resultado)
+
+	switch {
+	case respuesta.StatusCode == http.StatusOK && resultado.OK:
+		return nil
+	case respuesta.StatusCode == http.StatusBadRequest, respuesta.StatusCode == http.StatusForbidden:
+		return entidad.NewErrorValidacion("Telegram rechazó el mensaje: " + resultado.Descripcion)
+	default:
+		return fmt.Errorf("Telegram respondió %d: %s", respuesta.StatusCode, bytes.TrimSpace(detalle))
+	}
+}
@@ -0,0 +1,53 @@
+package trabajador
+
+import (
+	"context"
+	"time"
+
+	"sistema-notificaciones-go/pkg/logger"
+)
+
+// EjecucionEscalamientos avisa los pasos vencidos de los escalamientos activos
+type EjecucionEscalamientos interface {
+	EjecutarVencidos(ctx context.Context) error
+}
+
+// EjecutorEscalamientos avanza periódicamente los escalamientos de guardia hasta que se reconocen
+type EjecutorEscalamientos struct {
+	ejecucion EjecucionEscalamientos
+	intervalo time.Duration
+	logger    *logger.Logger
+}
+
+// NuevoEjecutorEscalamientos crea una nueva instancia de EjecutorEscalamientos
+func NuevoEjecutorEscalamientos(ejecucion EjecucionEscalamientos, intervalo time.Duration, log *logger.Logger) *EjecutorEscalamientos {
+	return &EjecutorEscalamientos{
+		ejecucion: ejecucion,
+		intervalo: intervalo,
+		logger:    log.Componente(logger.ComponenteTrabajadores),
+	}
+}
+
+// Iniciar hace una pasada inmediata y luego una por intervalo hasta que ctx termine
+func (e *EjecutorEscalamientos) Iniciar(ctx context.Context) {
+	go func() {
+		e.ejecutar(ctx)
+
+		ticker := time.NewTicker(e.intervalo)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				e.ejecutar(ctx)
+			}
+		}
+	}()
+}
+
+func (e *EjecutorEscalamientos) ejecutar(ctx context.Context) {
+	if err := e.ejecucion.EjecutarVencidos(ctx); err != nil {
+		e.logger.Error("Error ejecutando escalamientos", "error", err)
+	}
+}
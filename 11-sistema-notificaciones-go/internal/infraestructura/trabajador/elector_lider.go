@@ -0,0 +1,129 @@
+package trabajador
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// claveLider es el arrendamiento que disputan las instancias para ejecutar los trabajos únicos
+const claveLider = "lider_trabajos"
+
+// Lider indica si esta instancia es la que ejecuta los trabajos únicos
+type Lider interface {
+	EsLider() bool
+}
+
+// ElectorLider elige entre las instancias la que ejecuta los trabajos únicos (reintentos,
+// retención y SLA) por medio de un arrendamiento que la líder renueva cada tercio de su duración.
+// Si la líder cae deja de renovarlo y otra instancia lo toma al vencer.
+type ElectorLider struct {
+	arrendamiento repositorio.Arrendamiento
+	instancia     string
+	duracion      time.Duration
+	reloj         reloj.Reloj
+	logger        *logger.Logger
+
+	mu sync.Mutex
+	// vigenteHasta es el vencimiento del arrendamiento propio; cero si la instancia no es líder
+	vigenteHasta time.Time
+}
+
+// NuevoElectorLider crea una nueva instancia de ElectorLider
+func NuevoElectorLider(arrendamiento repositorio.Arrendamiento, config configuracion.ConfiguracionLiderazgo, rel reloj.Reloj, log *logger.Logger) *ElectorLider {
+	return &ElectorLider{
+		arrendamiento: arrendamiento,
+		instancia:     config.Instancia,
+		duracion:      config.Duracion,
+		reloj:         rel,
+		logger:        log.Componente(logger.ComponenteTrabajadores),
+	}
+}
+
+// Iniciar disputa el liderazgo al instante, para que los trabajos iniciados a continuación ya
+// sepan si les toca, y luego lo renueva o disputa periódicamente hasta que ctx termine
+func (e *ElectorLider) Iniciar(ctx context.Context) {
+	metricaLider.WithLabelValues(e.instancia).Set(0)
+	e.disputar(ctx)
+
+	go func() {
+		ticker := time.NewTicker(e.duracion / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				e.renunciar()
+				return
+			case <-ticker.C:
+				e.disputar(ctx)
+			}
+		}
+	}()
+}
+
+// EsLider indica si la instancia tiene el arrendamiento vigente
+func (e *ElectorLider) EsLider() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.reloj.Ahora().Before(e.vigenteHasta)
+}
+
+// Instancia retorna el identificador con que la instancia disputa el liderazgo
+func (e *ElectorLider) Instancia() string {
+	return e.instancia
+}
+
+func (e *ElectorLider) disputar(ctx context.Context) {
+	eraLider := e.EsLider()
+	// El vencimiento se cuenta desde antes de la solicitud: el propio nunca es posterior al de Redis
+	inicio := e.reloj.Ahora()
+	adquirido, err := e.arrendamiento.Adquirir(ctx, claveLider, e.instancia, e.duracion)
+	if err != nil {
+		// Sin respuesta no se sabe si se renovó: se sigue siendo líder solo hasta el vencimiento
+		// ya conocido, antes del cual ninguna otra instancia puede tomarlo
+		e.logger.Warn("No se pudo disputar el liderazgo", "instancia", e.instancia, "error", err)
+	} else {
+		e.mu.Lock()
+		if adquirido {
+			e.vigenteHasta = inicio.Add(e.duracion)
+		} else {
+			e.vigenteHasta = time.Time{}
+		}
+		e.mu.Unlock()
+	}
+
+	esLider := e.EsLider()
+	if esLider {
+		metricaLider.WithLabelValues(e.instancia).Set(1)
+	} else {
+		metricaLider.WithLabelValues(e.instancia).Set(0)
+	}
+	switch {
+	case esLider && !eraLider:
+		e.logger.Info("Instancia elegida líder de los trabajos únicos", "instancia", e.instancia)
+	case !esLider && eraLider:
+		e.logger.Warn("La instancia dejó de ser líder de los trabajos únicos", "instancia", e.instancia)
+	}
+}
+
+// renunciar libera el arrendamiento al apagarse, para que otra instancia no espere a que venza
+func (e *ElectorLider) renunciar() {
+	if !e.EsLider() {
+		return
+	}
+	e.mu.Lock()
+	e.vigenteHasta = time.Time{}
+	e.mu.Unlock()
+	metricaLider.WithLabelValues(e.instancia).Set(0)
+
+	ctx, cancelar := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelar()
+	if err := e.arrendamiento.Liberar(ctx, claveLider, e.instancia); err != nil {
+		e.logger.Warn("No se pudo liberar el liderazgo", "instancia", e.instancia, "error", err)
+	}
+}
@@ -0,0 +1,85 @@
+package trabajador
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/pkg/logger"
+)
+
+// EvaluadorCumplimiento calcula el cumplimiento de SLA del mes en curso
+type EvaluadorCumplimiento interface {
+	EvaluarPeriodoActual(ctx context.Context) ([]entidad.CumplimientoSLA, error)
+}
+
+// MonitorSLA evalúa periódicamente los SLA de los inquilinos, publica el porcentaje alcanzado
+// como métrica y alerta cuando un objetivo del mes en curso no se está cumpliendo. Solo evalúa la
+// instancia líder, así cada alerta sale una vez y las métricas no se duplican entre instancias.
+type MonitorSLA struct {
+	evaluador EvaluadorCumplimiento
+	lider     Lider
+	intervalo time.Duration
+	logger    *logger.Logger
+}
+
+// NuevoMonitorSLA crea una nueva instancia de MonitorSLA
+func NuevoMonitorSLA(evaluador EvaluadorCumplimiento, lider Lider, intervalo time.Duration, log *logger.Logger) *MonitorSLA {
+	return &MonitorSLA{
+		evaluador: evaluador,
+		lider:     lider,
+		intervalo: intervalo,
+		logger:    log.Componente(logger.ComponenteTrabajadores),
+	}
+}
+
+// Iniciar hace una evaluación inmediata y luego una por intervalo hasta que ctx termine
+func (m *MonitorSLA) Iniciar(ctx context.Context) {
+	go func() {
+		m.evaluar(ctx)
+
+		ticker := time.NewTicker(m.intervalo)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.evaluar(ctx)
+			}
+		}
+	}()
+}
+
+func (m *MonitorSLA) evaluar(ctx context.Context) {
+	if !m.lider.EsLider() {
+		// Una líder anterior deja de publicar valores que ya no actualiza
+		metricaCumplimientoSLA.Reset()
+		metricaObjetivoSLA.Reset()
+		return
+	}
+	cumplimientos, err := m.evaluador.EvaluarPeriodoActual(ctx)
+	if err != nil {
+		m.logger.Error("Error evaluando SLA", "error", err)
+		return
+	}
+
+	for _, cumplimiento := range cumplimientos {
+		objetivo := cumplimiento.Objetivo
+		inquilino := strconv.FormatUint(uint64(objetivo.InquilinoID), 10)
+		metricaCumplimientoSLA.WithLabelValues(inquilino, string(objetivo.Prioridad)).Set(cumplimiento.Porcentaje)
+		metricaObjetivoSLA.WithLabelValues(inquilino, string(objetivo.Prioridad)).Set(objetivo.PorcentajeObjetivo)
+
+		if !cumplimiento.Cumple {
+			m.logger.Warn("SLA de inquilino por debajo del objetivo",
+				"alerta", "sla_inquilino",
+				"inquilino_id", objetivo.InquilinoID,
+				"prioridad", objetivo.Prioridad,
+				"periodo", cumplimiento.Periodo,
+				"porcentaje", cumplimiento.Porcentaje,
+				"objetivo", objetivo.PorcentajeObjetivo,
+			)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package trabajador
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricaOcupados = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "notificaciones_pool_trabajadores_ocupados",
+		Help: "Trabajadores procesando una notificación, por prioridad atendida",
+	}, []string{"prioridad"})
+
+	metricaCapacidad = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "notificaciones_pool_trabajadores_dedicados",
+		Help: "Trabajadores dedicados por prioridad (la utilización es ocupados / dedicados)",
+	}, []string{"prioridad"})
+
+	metricaEnCola = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "notificaciones_pool_en_cola",
+		Help: "Notificaciones esperando trabajador, por prioridad",
+	}, []string{"prioridad"})
+
+	metricaProcesadas = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "notificaciones_pool_procesadas_total",
+		Help: "Notificaciones procesadas por prioridad, tipo de trabajador y resultado",
+	}, []string{"prioridad", "trabajador", "resultado"})
+
+	metricaPendientes = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "notificaciones_pendientes_despacho",
+		Help: "Notificaciones listas para despachar en todas las bases (pendientes y reintentos vencidos), por prioridad",
+	}, []string{"prioridad"})
+
+	metricaAntiguedadPendientes = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "notificaciones_pendientes_antiguedad_segundos",
+		Help: "Segundos que lleva esperando la notificación lista más antigua, por prioridad",
+	}, []string{"prioridad"})
+
+	metricaReintentosEncolados = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "notificaciones_reintentos_encolados_total",
+		Help: "Notificaciones fallidas devueltas a la cola por el planificador de reintentos",
+	})
+
+	metricaProgramadasEncoladas = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "notificaciones_programadas_encoladas_total",
+		Help: "Notificaciones programadas encoladas al llegar su fecha, por prioridad",
+	}, []string{"prioridad"})
+
+	metricaBuzonRelevadas = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "notificaciones_buzon_salida_relevadas_total",
+		Help: "Notificaciones que no pudieron encolarse al crearlas y publicó el relevo del buzón de salida",
+	})
+
+	metricaCumplimientoSLA = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "notificaciones_sla_cumplimiento_porcentaje",
+		Help: "Porcentaje de envíos al proveedor dentro del umbral en el mes en curso, por inquilino y prioridad",
+	}, []string{"inquilino_id", "prioridad"})
+
+	metricaObjetivoSLA = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "notificaciones_sla_objetivo_porcentaje",
+		Help: "Porcentaje comprometido en el SLA, por inquilino y prioridad",
+	}, []string{"inquilino_id", "prioridad"})
+
+	metricaLider = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "notificaciones_lider_trabajos",
+		Help: "1 si la instancia es la líder que ejecuta los trabajos únicos, 0 si no",
+	}, []string{"instancia"})
+)
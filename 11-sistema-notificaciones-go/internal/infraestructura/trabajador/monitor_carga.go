@@ -0,0 +1,70 @@
+package trabajador
+
+import (
+	"context"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/pkg/logger"
+)
+
+// MedidorCarga mide el atraso de despacho de todas las bases
+type MedidorCarga interface {
+	Medir(ctx context.Context) (*entidad.CargaDespacho, error)
+}
+
+// MonitorCarga publica como métricas lo que espera despacho y desde cuándo, la señal con la que
+// un autoescalador (HPA o KEDA) ajusta la cantidad de instancias. La carga es global: solo la
+// publica la líder, así sumar la métrica entre instancias no la multiplica.
+type MonitorCarga struct {
+	medidor   MedidorCarga
+	lider     Lider
+	intervalo time.Duration
+	logger    *logger.Logger
+}
+
+// NuevoMonitorCarga crea una nueva instancia de MonitorCarga
+func NuevoMonitorCarga(medidor MedidorCarga, lider Lider, intervalo time.Duration, log *logger.Logger) *MonitorCarga {
+	return &MonitorCarga{
+		medidor:   medidor,
+		lider:     lider,
+		intervalo: intervalo,
+		logger:    log.Componente(logger.ComponenteTrabajadores),
+	}
+}
+
+// Iniciar hace una medición inmediata y luego una por intervalo hasta que ctx termine
+func (m *MonitorCarga) Iniciar(ctx context.Context) {
+	go func() {
+		m.medir(ctx)
+
+		ticker := time.NewTicker(m.intervalo)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.medir(ctx)
+			}
+		}
+	}()
+}
+
+func (m *MonitorCarga) medir(ctx context.Context) {
+	if !m.lider.EsLider() {
+		metricaPendientes.Reset()
+		metricaAntiguedadPendientes.Reset()
+		return
+	}
+	carga, err := m.medidor.Medir(ctx)
+	if err != nil {
+		// Se conservan los últimos valores: bajarlos a cero haría reducir instancias con atraso
+		m.logger.Error("Error midiendo la carga de despacho", "error", err)
+		return
+	}
+	for _, prioridad := range carga.PorPrioridad {
+		metricaPendientes.WithLabelValues(string(prioridad.Prioridad)).Set(float64(prioridad.Pendientes))
+		metricaAntiguedadPendientes.WithLabelValues(string(prioridad.Prioridad)).Set(prioridad.AntiguedadSegundos)
+	}
+}
@@ -0,0 +1,130 @@
+package trabajador
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// PlanificadorProgramadas encola las notificaciones programadas cuando llega su fecha, las de
+// mayor prioridad primero. Como el de reintentos, reserva cada una antes de publicarla, recorre
+// las tablas comunes y el esquema de cada inquilino aislado y solo lo ejecuta la instancia líder.
+type PlanificadorProgramadas struct {
+	repositorio repositorio.RepositorioNotificacion
+	inquilinos  repositorio.RepositorioInquilino
+	cola        repositorio.ColaMensajes
+	lider       Lider
+	config      configuracion.ConfiguracionProgramadas
+	reloj       reloj.Reloj
+	logger      *logger.Logger
+}
+
+// NuevoPlanificadorProgramadas crea una nueva instancia de PlanificadorProgramadas
+func NuevoPlanificadorProgramadas(
+	repositorioNotificacion repositorio.RepositorioNotificacion,
+	repositorioInquilino repositorio.RepositorioInquilino,
+	cola repositorio.ColaMensajes,
+	lider Lider,
+	config configuracion.ConfiguracionProgramadas,
+	rel reloj.Reloj,
+	log *logger.Logger,
+) *PlanificadorProgramadas {
+	return &PlanificadorProgramadas{
+		repositorio: repositorioNotificacion,
+		inquilinos:  repositorioInquilino,
+		cola:        cola,
+		lider:       lider,
+		config:      config,
+		reloj:       rel,
+		logger:      log.Componente(logger.ComponenteTrabajadores),
+	}
+}
+
+// Iniciar hace una pasada inmediata, que encola lo que venció con el servicio detenido, y luego
+// una por intervalo hasta que ctx termine
+func (p *PlanificadorProgramadas) Iniciar(ctx context.Context) {
+	go func() {
+		p.pasada(ctx)
+
+		ticker := time.NewTicker(p.config.Intervalo)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.pasada(ctx)
+			}
+		}
+	}()
+}
+
+func (p *PlanificadorProgramadas) pasada(ctx context.Context) {
+	if !p.lider.EsLider() {
+		return
+	}
+	contextos, err := servicio.ContextosDeDatos(ctx, p.inquilinos)
+	if err != nil {
+		p.logger.Error("Error listando inquilinos aislados", "error", err)
+	}
+
+	encoladas := 0
+	for _, ctxEsquema := range contextos {
+		cantidad, err := p.encolarVencidas(ctxEsquema)
+		encoladas += cantidad
+		if err != nil {
+			p.logger.Error("Error encolando notificaciones programadas",
+				"region", servicio.RegionDesdeContexto(ctxEsquema), "esquema", servicio.EsquemaDesdeContexto(ctxEsquema), "error", err)
+		}
+	}
+	if encoladas > 0 {
+		p.logger.Info("Notificaciones programadas encoladas", "cantidad", encoladas)
+	}
+}
+
+// encolarVencidas reserva cada programada vencida y luego la publica. Si el proceso cae antes de
+// procesarla, la reserva expira y se vuelve a encolar.
+func (p *PlanificadorProgramadas) encolarVencidas(ctx context.Context) (int, error) {
+	encoladas := 0
+	for {
+		ahora := p.reloj.Ahora()
+		vencidas, err := p.repositorio.ListarProgramadasVencidas(ctx, ahora, p.config.TamanoLote)
+		if err != nil {
+			return encoladas, err
+		}
+
+		for i := range vencidas {
+			notificacion := &vencidas[i]
+			reserva := ahora.Add(p.config.PlazoReserva)
+			notificacion.ProximaFechaReintento = &reserva
+
+			if err := p.repositorio.Actualizar(ctx, notificacion); err != nil {
+				if errors.Is(err, entidad.ErrConflictoVersion) {
+					// Se canceló, reprogramó o reservó mientras tanto
+					continue
+				}
+				return encoladas, err
+			}
+			if err := p.cola.Publicar(ctx, notificacion); err != nil {
+				if errors.Is(err, ErrColaLlena) {
+					// Se reintentará cuando expire la reserva
+					return encoladas, nil
+				}
+				return encoladas, err
+			}
+			encoladas++
+			metricaProgramadasEncoladas.WithLabelValues(string(notificacion.Prioridad)).Inc()
+		}
+
+		if len(vencidas) < p.config.TamanoLote {
+			return encoladas, nil
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package trabajador
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// PlanificadorReintentos devuelve a la cola las notificaciones fallidas cuyo reintento venció.
+// El estado vive en proxima_fecha_reintento, por lo que tras un reinicio la primera pasada
+// reconstruye la cola de reintentos pendientes desde la base de datos.
+// Cada pasada recorre las tablas comunes y el esquema de cada inquilino aislado; solo la hace la
+// instancia líder.
+type PlanificadorReintentos struct {
+	repositorio repositorio.RepositorioNotificacion
+	inquilinos  repositorio.RepositorioInquilino
+	cola        repositorio.ColaMensajes
+	lider       Lider
+	config      configuracion.ConfiguracionReintentos
+	reloj       reloj.Reloj
+	logger      *logger.Logger
+}
+
+// NuevoPlanificadorReintentos crea una nueva instancia de PlanificadorReintentos
+func NuevoPlanificadorReintentos(
+	repositorioNotificacion repositorio.RepositorioNotificacion,
+	repositorioInquilino repositorio.RepositorioInquilino,
+	cola repositorio.ColaMensajes,
+	lider Lider,
+	config configuracion.ConfiguracionReintentos,
+	rel reloj.Reloj,
+	log *logger.Logger,
+) *PlanificadorReintentos {
+	return &PlanificadorReintentos{
+		repositorio: repositorioNotificacion,
+		inquilinos:  repositorioInquilino,
+		cola:        cola,
+		lider:       lider,
+		config:      config,
+		reloj:       rel,
+		logger:      log.Componente(logger.ComponenteTrabajadores),
+	}
+}
+
+// Iniciar hace una pasada inmediata y luego una por intervalo hasta que ctx termine
+func (p *PlanificadorReintentos) Iniciar(ctx context.Context) {
+	go func() {
+		p.pasada(ctx)
+
+		ticker := time.NewTicker(p.config.Intervalo)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.pasada(ctx)
+			}
+		}
+	}()
+}
+
+func (p *PlanificadorReintentos) pasada(ctx context.Context) {
+	if !p.lider.EsLider() {
+		return
+	}
+	contextos, err := servicio.ContextosDeDatos(ctx, p.inquilinos)
+	if err != nil {
+		p.logger.Error("Error listando inquilinos aislados", "error", err)
+	}
+
+	encoladas := 0
+	for _, ctxEsquema := range contextos {
+		cantidad, err := p.encolarVencidos(ctxEsquema)
+		encoladas += cantidad
+		if err != nil {
+			p.logger.Error("Error encolando reintentos vencidos",
+				"region", servicio.RegionDesdeContexto(ctxEsquema), "esquema", servicio.EsquemaDesdeContexto(ctxEsquema), "error", err)
+		}
+	}
+	if encoladas > 0 {
+		p.logger.Info("Reintentos encolados", "cantidad", encoladas)
+	}
+}
+
+// encolarVencidos reserva cada reintento vencido aplazando su fecha y luego lo publica.
+// Si el proceso cae antes de procesarlo, la reserva expira y se vuelve a encolar.
+func (p *PlanificadorReintentos) encolarVencidos(ctx context.Context) (int, error) {
+	encoladas := 0
+	for {
+		ahora := p.reloj.Ahora()
+		vencidas, err := p.repositorio.ListarReintentosVencidos(ctx, ahora, p.config.TamanoLote)
+		if err != nil {
+			return encoladas, err
+		}
+
+		for i := range vencidas {
+			notificacion := &vencidas[i]
+			reserva := ahora.Add(p.config.PlazoReserva)
+			notificacion.ProximaFechaReintento = &reserva
+
+			if err := p.repositorio.Actualizar(ctx, notificacion); err != nil {
+				if errors.Is(err, entidad.ErrConflictoVersion) {
+					// Otra instancia la reservó o cambió de estado
+					continue
+				}
+				return encoladas, err
+			}
+			if err := p.cola.Publicar(ctx, notificacion); err != nil {
+				if errors.Is(err, ErrColaLlena) {
+					// Se reintentará cuando expire la reserva
+					return encoladas, nil
+				}
+				return encoladas, err
+			}
+			encoladas++
+			metricaReintentosEncolados.Inc()
+		}
+
+		if len(vencidas) < p.config.TamanoLote {
+			return encoladas, nil
+		}
+	}
+}
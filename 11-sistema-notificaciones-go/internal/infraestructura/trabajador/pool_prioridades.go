@@ -0,0 +1,210 @@
+package trabajador
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+	"sistema-notificaciones-go/pkg/logger"
+)
+
+// ErrColaLlena indica que la cola de la prioridad no admite más notificaciones
+var ErrColaLlena = errors.New("cola de la prioridad llena")
+
+// prioridades en orden de importancia
+var prioridades = []entidad.PrioridadNotificacion{
+	entidad.PrioridadCritica,
+	entidad.PrioridadAlta,
+	entidad.PrioridadNormal,
+	entidad.PrioridadBaja,
+}
+
+// Procesador procesa una notificación tomada de la cola
+type Procesador interface {
+	Procesar(ctx context.Context, notificacion *entidad.Notificacion) error
+}
+
+// PoolPrioridades mantiene una cola y trabajadores dedicados por prioridad.
+// Los trabajadores dedicados solo atienden su cola, por lo que una avalancha de baja
+// prioridad nunca ocupa la capacidad reservada para crítica. Los trabajadores compartidos
+// reparten su tiempo entre colas con round-robin ponderado.
+type PoolPrioridades struct {
+	config     configuracion.ConfiguracionTrabajadores
+	procesador Procesador
+	logger     *logger.Logger
+
+	colas map[entidad.PrioridadNotificacion]chan *entidad.Notificacion
+	grupo sync.WaitGroup
+}
+
+// NuevoPoolPrioridades crea el pool; Iniciar lanza los trabajadores
+func NuevoPoolPrioridades(config configuracion.ConfiguracionTrabajadores, procesador Procesador, log *logger.Logger) *PoolPrioridades {
+	pool := &PoolPrioridades{
+		config:     config,
+		procesador: procesador,
+		logger:     log.Componente(logger.ComponenteTrabajadores),
+		colas:      make(map[entidad.PrioridadNotificacion]chan *entidad.Notificacion, len(prioridades)),
+	}
+	for _, prioridad := range prioridades {
+		pool.colas[prioridad] = make(chan *entidad.Notificacion, config.CapacidadCola)
+	}
+	return pool
+}
+
+// Iniciar lanza los trabajadores dedicados y compartidos hasta que ctx termine
+func (p *PoolPrioridades) Iniciar(ctx context.Context) {
+	for _, prioridad := range prioridades {
+		dedicados := p.config.PorPrioridad[string(prioridad)]
+		if dedicados <= 0 {
+			dedicados = 1
+		}
+		metricaCapacidad.WithLabelValues(string(prioridad)).Set(float64(dedicados))
+
+		for i := 0; i < dedicados; i++ {
+			p.grupo.Add(1)
+			go p.trabajadorDedicado(ctx, prioridad)
+		}
+	}
+
+	for i := 0; i < p.config.Compartidos; i++ {
+		p.grupo.Add(1)
+		go p.trabajadorCompartido(ctx)
+	}
+
+	p.logger.Info("Pool de trabajadores iniciado",
+		"por_prioridad", p.config.PorPrioridad,
+		"compartidos", p.config.Compartidos,
+	)
+}
+
+// Esperar bloquea hasta que todos los trabajadores terminen
+func (p *PoolPrioridades) Esperar() {
+	p.grupo.Wait()
+}
+
+// Publicar encola la notificación en la cola de su prioridad sin bloquear
+func (p *PoolPrioridades) Publicar(ctx context.Context, notificacion *entidad.Notificacion) error {
+	cola := p.cola(notificacion.Prioridad)
+	select {
+	case cola <- notificacion:
+		metricaEnCola.WithLabelValues(string(notificacion.Prioridad)).Inc()
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		return ErrColaLlena
+	}
+}
+
+// PublicarLote encola un lote, esperando espacio en la cola en lugar de descartar
+func (p *PoolPrioridades) PublicarLote(ctx context.Context, notificaciones []*entidad.Notificacion) error {
+	for _, notificacion := range notificaciones {
+		select {
+		case p.cola(notificacion.Prioridad) <- notificacion:
+			metricaEnCola.WithLabelValues(string(notificacion.Prioridad)).Inc()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// ProfundidadCola es la ocupación de la cola de una prioridad
+type ProfundidadCola struct {
+	Prioridad entidad.PrioridadNotificacion `json:"prioridad"`
+	EnCola    int                           `json:"en_cola"`
+	Capacidad int                           `json:"capacidad"`
+}
+
+// Profundidades retorna la ocupación de cada cola, en orden de importancia
+func (p *PoolPrioridades) Profundidades() []ProfundidadCola {
+	profundidades := make([]ProfundidadCola, 0, len(prioridades))
+	for _, prioridad := range prioridades {
+		cola := p.colas[prioridad]
+		profundidades = append(profundidades, ProfundidadCola{Prioridad: prioridad, EnCola: len(cola), Capacidad: cap(cola)})
+	}
+	return profundidades
+}
+
+func (p *PoolPrioridades) cola(prioridad entidad.PrioridadNotificacion) chan *entidad.Notificacion {
+	if cola, existe := p.colas[prioridad]; existe {
+		return cola
+	}
+	return p.colas[entidad.PrioridadNormal]
+}
+
+func (p *PoolPrioridades) trabajadorDedicado(ctx context.Context, prioridad entidad.PrioridadNotificacion) {
+	defer p.grupo.Done()
+
+	cola := p.colas[prioridad]
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case notificacion := <-cola:
+			p.procesar(ctx, prioridad, "dedicado", notificacion)
+		}
+	}
+}
+
+func (p *PoolPrioridades) trabajadorCompartido(ctx context.Context) {
+	defer p.grupo.Done()
+
+	turno := nuevoRoundRobinPonderado(p.config.Pesos)
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		// Intentar las colas en el orden ponderado sin bloquear
+		atendida := false
+		for _, prioridad := range turno.orden() {
+			select {
+			case notificacion := <-p.colas[prioridad]:
+				p.procesar(ctx, prioridad, "compartido", notificacion)
+				atendida = true
+			default:
+			}
+			if atendida {
+				break
+			}
+		}
+		if atendida {
+			continue
+		}
+
+		// Todas vacías: esperar la primera que reciba algo
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-p.colas[entidad.PrioridadCritica]:
+			p.procesar(ctx, entidad.PrioridadCritica, "compartido", n)
+		case n := <-p.colas[entidad.PrioridadAlta]:
+			p.procesar(ctx, entidad.PrioridadAlta, "compartido", n)
+		case n := <-p.colas[entidad.PrioridadNormal]:
+			p.procesar(ctx, entidad.PrioridadNormal, "compartido", n)
+		case n := <-p.colas[entidad.PrioridadBaja]:
+			p.procesar(ctx, entidad.PrioridadBaja, "compartido", n)
+		}
+	}
+}
+
+func (p *PoolPrioridades) procesar(ctx context.Context, prioridad entidad.PrioridadNotificacion, tipoTrabajador string, notificacion *entidad.Notificacion) {
+	etiqueta := string(prioridad)
+	metricaEnCola.WithLabelValues(etiqueta).Dec()
+	metricaOcupados.WithLabelValues(etiqueta).Inc()
+	defer metricaOcupados.WithLabelValues(etiqueta).Dec()
+
+	resultado := "ok"
+	if err := p.procesador.Procesar(ctx, notificacion); err != nil {
+		resultado = "error"
+		p.logger.Warn("Error procesando notificación",
+			"notificacion_id", notificacion.ID,
+			"prioridad", etiqueta,
+			"error", err,
+		)
+	}
+	metricaProcesadas.WithLabelValues(etiqueta, tipoTrabajador, resultado).Inc()
+}
@@ -0,0 +1,57 @@
+package trabajador
+
+import (
+	"context"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/pkg/logger"
+)
+
+// ProcesadorBuzonSalida borra la entrada del buzón de salida cuando termina el despacho de la
+// notificación. Envuelve al procesador del pool cuando la cola es en memoria: ahí la entrada
+// no puede borrarse al publicar porque la notificación encolada se pierde si el proceso cae.
+type ProcesadorBuzonSalida struct {
+	procesador Procesador
+	buzon      repositorio.RepositorioBuzonSalida
+	inquilinos repositorio.RepositorioInquilino
+	logger     *logger.Logger
+}
+
+// NuevoProcesadorBuzonSalida crea una nueva instancia de ProcesadorBuzonSalida
+func NuevoProcesadorBuzonSalida(
+	procesador Procesador,
+	repositorioBuzon repositorio.RepositorioBuzonSalida,
+	repositorioInquilino repositorio.RepositorioInquilino,
+	log *logger.Logger,
+) *ProcesadorBuzonSalida {
+	return &ProcesadorBuzonSalida{
+		procesador: procesador,
+		buzon:      repositorioBuzon,
+		inquilinos: repositorioInquilino,
+		logger:     log.Componente(logger.ComponenteTrabajadores),
+	}
+}
+
+// Procesar despacha la notificación y, si el despacho terminó, borra su entrada. Ante un error
+// la entrada queda y el relevo decide: la descarta si la notificación ya no está pendiente o la
+// vuelve a publicar.
+func (p *ProcesadorBuzonSalida) Procesar(ctx context.Context, notificacion *entidad.Notificacion) error {
+	if err := p.procesador.Procesar(ctx, notificacion); err != nil {
+		return err
+	}
+
+	ctxDatos, err := servicio.ContextoDeInquilino(ctx, p.inquilinos, notificacion.InquilinoID)
+	if err == nil {
+		err = p.buzon.Eliminar(ctxDatos, []uint{notificacion.ID})
+	}
+	if err != nil {
+		// La notificación ya se despachó: el relevo descartará la entrada
+		p.logger.Error("Error quitando la notificación despachada del buzón de salida",
+			"notificacion_id", notificacion.ID,
+			"error", err,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package trabajador
+
+import (
+	"context"
+	"time"
+
+	"sistema-notificaciones-go/pkg/logger"
+)
+
+// EnvioReportes envía los reportes operativos cuyo periodo ya cerró
+type EnvioReportes interface {
+	EnviarPendientes(ctx context.Context) error
+}
+
+// ProgramadorReportes verifica periódicamente si corresponde enviar el reporte operativo
+// diario o semanal a los administradores
+type ProgramadorReportes struct {
+	envio     EnvioReportes
+	intervalo time.Duration
+	logger    *logger.Logger
+}
+
+// NuevoProgramadorReportes crea una nueva instancia de ProgramadorReportes
+func NuevoProgramadorReportes(envio EnvioReportes, intervalo time.Duration, log *logger.Logger) *ProgramadorReportes {
+	return &ProgramadorReportes{
+		envio:     envio,
+		intervalo: intervalo,
+		logger:    log.Componente(logger.ComponenteTrabajadores),
+	}
+}
+
+// Iniciar hace una verificación inmediata y luego una por intervalo hasta que ctx termine
+func (p *ProgramadorReportes) Iniciar(ctx context.Context) {
+	go func() {
+		p.enviar(ctx)
+
+		ticker := time.NewTicker(p.intervalo)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.enviar(ctx)
+			}
+		}
+	}()
+}
+
+func (p *ProgramadorReportes) enviar(ctx context.Context) {
+	if err := p.envio.EnviarPendientes(ctx); err != nil {
+		p.logger.Error("Error enviando el reporte operativo", "error", err)
+	}
+}
@@ -0,0 +1,80 @@
+package trabajador
+
+import (
+	"context"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/pkg/logger"
+)
+
+// PurgaRetencion elimina o anonimiza las notificaciones cuyas políticas de retención vencieron
+type PurgaRetencion interface {
+	PurgarVencidas(ctx context.Context) ([]entidad.ResultadoPurga, error)
+}
+
+// PurgadorRetencion aplica periódicamente las políticas de retención y deja en el log
+// un registro por política y esquema con lo eliminado o anonimizado, como evidencia de cumplimiento.
+// Solo purga la instancia líder.
+type PurgadorRetencion struct {
+	purga     PurgaRetencion
+	lider     Lider
+	intervalo time.Duration
+	logger    *logger.Logger
+}
+
+// NuevoPurgadorRetencion crea una nueva instancia de PurgadorRetencion
+func NuevoPurgadorRetencion(purga PurgaRetencion, lider Lider, intervalo time.Duration, log *logger.Logger) *PurgadorRetencion {
+	return &PurgadorRetencion{
+		purga:     purga,
+		lider:     lider,
+		intervalo: intervalo,
+		logger:    log.Componente(logger.ComponenteTrabajadores),
+	}
+}
+
+// Iniciar hace una purga inmediata y luego una por intervalo hasta que ctx termine
+func (p *PurgadorRetencion) Iniciar(ctx context.Context) {
+	go func() {
+		p.purgar(ctx)
+
+		ticker := time.NewTicker(p.intervalo)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.purgar(ctx)
+			}
+		}
+	}()
+}
+
+func (p *PurgadorRetencion) purgar(ctx context.Context) {
+	if !p.lider.EsLider() {
+		return
+	}
+	resultados, err := p.purga.PurgarVencidas(ctx)
+	// Lo purgado se registra aunque otras políticas hayan fallado
+	for _, resultado := range resultados {
+		politica := resultado.Politica
+		p.logger.Info("Política de retención aplicada",
+			"auditoria", "retencion",
+			"politica_id", politica.ID,
+			"accion", politica.Accion,
+			"inquilino_id", politica.InquilinoID,
+			"tipo", politica.Tipo,
+			"canal_id", politica.CanalID,
+			"dias", politica.Dias,
+			"region", resultado.Region,
+			"esquema", resultado.Esquema,
+			"creadas_antes_de", resultado.Limite,
+			"notificaciones", resultado.Notificaciones,
+			"intentos", resultado.Intentos,
+		)
+	}
+	if err != nil {
+		p.logger.Error("Error aplicando políticas de retención", "error", err)
+	}
+}
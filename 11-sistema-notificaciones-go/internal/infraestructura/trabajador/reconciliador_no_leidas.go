@@ -0,0 +1,58 @@
+package trabajador
+
+import (
+	"context"
+	"time"
+
+	"sistema-notificaciones-go/pkg/logger"
+)
+
+// ReconciliacionNoLeidas recuenta en la base los contadores de no leídas y corrige la deriva
+type ReconciliacionNoLeidas interface {
+	Reconciliar(ctx context.Context) (int, error)
+}
+
+// ReconciliadorNoLeidas corrige periódicamente los contadores de no leídas contra la base de
+// datos, la fuente de verdad
+type ReconciliadorNoLeidas struct {
+	reconciliacion ReconciliacionNoLeidas
+	intervalo      time.Duration
+	logger         *logger.Logger
+}
+
+// NuevoReconciliadorNoLeidas crea una nueva instancia de ReconciliadorNoLeidas
+func NuevoReconciliadorNoLeidas(reconciliacion ReconciliacionNoLeidas, intervalo time.Duration, log *logger.Logger) *ReconciliadorNoLeidas {
+	return &ReconciliadorNoLeidas{
+		reconciliacion: reconciliacion,
+		intervalo:      intervalo,
+		logger:         log.Componente(logger.ComponenteTrabajadores),
+	}
+}
+
+// Iniciar reconcilia una vez por intervalo hasta que ctx termine; recién iniciado no hay
+// deriva que corregir
+func (r *ReconciliadorNoLeidas) Iniciar(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(r.intervalo)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.reconciliar(ctx)
+			}
+		}
+	}()
+}
+
+func (r *ReconciliadorNoLeidas) reconciliar(ctx context.Context) {
+	corregidos, err := r.reconciliacion.Reconciliar(ctx)
+	if err != nil {
+		r.logger.Error("Error reconciliando contadores de no leídas", "corregidos", corregidos, "error", err)
+		return
+	}
+	if corregidos > 0 {
+		r.logger.Info("Contadores de no leídas reconciliados", "corregidos", corregidos)
+	}
+}
@@ -0,0 +1,25 @@
+package trabajador
+
+import (
+	"errors"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// Reentregable indica si un mensaje cuyo procesamiento falló debe volver a entregarse. Los
+// envíos fallidos ya quedaron registrados en la notificación, que lleva sus reintentos, y los
+// errores de dominio se repetirían igual; el resto (p. ej. la base de datos no responde) dejó
+// la notificación sin tocar y se pierde si el mensaje se confirma.
+func Reentregable(err error) bool {
+	var errDominio *entidad.ErrorDominio
+	var errValidacion *entidad.ErrorValidacion
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, entidad.ErrEnvioFallido), errors.As(err, &errDominio), errors.As(err, &errValidacion):
+		return false
+	case errors.Is(err, entidad.ErrNotificacionNoEncontrada), errors.Is(err, entidad.ErrInquilinoNoEncontrado):
+		return false
+	}
+	return true
+}
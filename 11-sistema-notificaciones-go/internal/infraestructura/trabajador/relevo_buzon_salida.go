@@ -0,0 +1,162 @@
+package trabajador
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// RelevoBuzonSalida publica las notificaciones que quedaron en el buzón de salida porque la cola
+// no las aceptó al crearlas (p. ej. con el broker caído) o porque el proceso cayó antes de
+// publicarlas. Cada entrada se borra recién después de publicarla, así una notificación puede
+// publicarse dos veces pero nunca perderse; el despacho omite las que ya no están pendientes.
+// Con la cola en memoria la entrada de lo publicado no se borra sino que se aplaza: la borra el
+// trabajador al despacharla, y si el proceso cae antes el relevo la vuelve a publicar.
+// Recorre las tablas comunes y el esquema de cada inquilino aislado y solo lo ejecuta la
+// instancia líder.
+type RelevoBuzonSalida struct {
+	buzon          repositorio.RepositorioBuzonSalida
+	notificaciones repositorio.RepositorioNotificacion
+	inquilinos     repositorio.RepositorioInquilino
+	cola           repositorio.ColaMensajes
+	retener        bool
+	lider          Lider
+	config         configuracion.ConfiguracionBuzonSalida
+	reloj          reloj.Reloj
+	logger         *logger.Logger
+}
+
+// NuevoRelevoBuzonSalida crea una nueva instancia de RelevoBuzonSalida
+func NuevoRelevoBuzonSalida(
+	repositorioBuzon repositorio.RepositorioBuzonSalida,
+	repositorioNotificacion repositorio.RepositorioNotificacion,
+	repositorioInquilino repositorio.RepositorioInquilino,
+	cola repositorio.ColaMensajes,
+	retener bool,
+	lider Lider,
+	config configuracion.ConfiguracionBuzonSalida,
+	rel reloj.Reloj,
+	log *logger.Logger,
+) *RelevoBuzonSalida {
+	return &RelevoBuzonSalida{
+		buzon:          repositorioBuzon,
+		notificaciones: repositorioNotificacion,
+		inquilinos:     repositorioInquilino,
+		cola:           cola,
+		retener:        retener,
+		lider:          lider,
+		config:         config,
+		reloj:          rel,
+		logger:         log.Componente(logger.ComponenteTrabajadores),
+	}
+}
+
+// Iniciar hace una pasada inmediata, que publica lo que quedó en el buzón con el servicio
+// detenido, y luego una por intervalo hasta que ctx termine
+func (r *RelevoBuzonSalida) Iniciar(ctx context.Context) {
+	go func() {
+		r.pasada(ctx)
+
+		ticker := time.NewTicker(r.config.Intervalo)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.pasada(ctx)
+			}
+		}
+	}()
+}
+
+func (r *RelevoBuzonSalida) pasada(ctx context.Context) {
+	if !r.lider.EsLider() {
+		return
+	}
+	contextos, err := servicio.ContextosDeDatos(ctx, r.inquilinos)
+	if err != nil {
+		r.logger.Error("Error listando inquilinos aislados", "error", err)
+	}
+
+	relevadas := 0
+	for _, ctxEsquema := range contextos {
+		cantidad, err := r.relevar(ctxEsquema)
+		relevadas += cantidad
+		if err != nil {
+			r.logger.Error("Error publicando el buzón de salida",
+				"region", servicio.RegionDesdeContexto(ctxEsquema), "esquema", servicio.EsquemaDesdeContexto(ctxEsquema), "error", err)
+		}
+	}
+	if relevadas > 0 {
+		r.logger.Info("Notificaciones publicadas desde el buzón de salida", "cantidad", relevadas)
+	}
+}
+
+// relevar publica las entradas con más de Antiguedad, las más antiguas primero. Se detiene en el
+// primer error de la cola: las entradas que faltan se intentan en la próxima pasada.
+func (r *RelevoBuzonSalida) relevar(ctx context.Context) (int, error) {
+	relevadas := 0
+	for {
+		ahora := r.reloj.Ahora()
+		entradas, err := r.buzon.ListarAnteriores(ctx, ahora.Add(-r.config.Antiguedad), r.config.TamanoLote)
+		if err != nil {
+			return relevadas, err
+		}
+
+		resueltas := make([]uint, 0, len(entradas))
+		var aplazadas []uint
+		for i := range entradas {
+			publicada, err := r.publicar(ctx, entradas[i].NotificacionID, ahora)
+			if err != nil {
+				if errors.Is(err, ErrColaLlena) {
+					// Se reintentará en la próxima pasada
+					err = nil
+				}
+				return relevadas, errors.Join(err, r.buzon.Eliminar(ctx, resueltas), r.buzon.Aplazar(ctx, aplazadas, ahora))
+			}
+			if publicada {
+				relevadas++
+				metricaBuzonRelevadas.Inc()
+			}
+			if publicada && r.retener {
+				aplazadas = append(aplazadas, entradas[i].NotificacionID)
+			} else {
+				resueltas = append(resueltas, entradas[i].NotificacionID)
+			}
+		}
+		if err := errors.Join(r.buzon.Eliminar(ctx, resueltas), r.buzon.Aplazar(ctx, aplazadas, ahora)); err != nil {
+			return relevadas, err
+		}
+
+		if len(entradas) < r.config.TamanoLote {
+			return relevadas, nil
+		}
+	}
+}
+
+// publicar encola la notificación de la entrada si sigue pendiente. Las que se borraron, ya se
+// despacharon o cancelaron, o se reprogramaron, no se publican: la entrada solo se descarta.
+func (r *RelevoBuzonSalida) publicar(ctx context.Context, notificacionID uint, ahora time.Time) (bool, error) {
+	notificacion, err := r.notificaciones.ObtenerPorID(ctx, notificacionID)
+	if errors.Is(err, entidad.ErrNotificacionNoEncontrada) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if notificacion.Estado != entidad.EstadoPendiente || notificacion.EstaProgramada(ahora) {
+		return false, nil
+	}
+	if err := r.cola.Publicar(ctx, notificacion); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,63 @@
+package trabajador
+
+import (
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// pesosPredeterminados se usan para prioridades sin peso configurado
+var pesosPredeterminados = map[entidad.PrioridadNotificacion]int{
+	entidad.PrioridadCritica: 8,
+	entidad.PrioridadAlta:    4,
+	entidad.PrioridadNormal:  2,
+	entidad.PrioridadBaja:    1,
+}
+
+// roundRobinPonderado implementa el round-robin ponderado suave (estilo nginx):
+// cada prioridad recibe turnos proporcionales a su peso sin ráfagas consecutivas.
+type roundRobinPonderado struct {
+	pesos  map[entidad.PrioridadNotificacion]int
+	actual map[entidad.PrioridadNotificacion]int
+	total  int
+}
+
+func nuevoRoundRobinPonderado(configurados map[string]int) *roundRobinPonderado {
+	turno := &roundRobinPonderado{
+		pesos:  make(map[entidad.PrioridadNotificacion]int, len(prioridades)),
+		actual: make(map[entidad.PrioridadNotificacion]int, len(prioridades)),
+	}
+	for _, prioridad := range prioridades {
+		peso, existe := configurados[string(prioridad)]
+		if !existe || peso <= 0 {
+			peso = pesosPredeterminados[prioridad]
+		}
+		turno.pesos[prioridad] = peso
+		turno.total += peso
+	}
+	return turno
+}
+
+// siguiente elige la prioridad del próximo turno
+func (r *roundRobinPonderado) siguiente() entidad.PrioridadNotificacion {
+	var elegida entidad.PrioridadNotificacion
+	for _, prioridad := range prioridades {
+		r.actual[prioridad] += r.pesos[prioridad]
+		if elegida == "" || r.actual[prioridad] > r.actual[elegida] {
+			elegida = prioridad
+		}
+	}
+	r.actual[elegida] -= r.total
+	return elegida
+}
+
+// orden retorna la prioridad del turno seguida del resto por importancia
+func (r *roundRobinPonderado) orden() []entidad.PrioridadNotificacion {
+	primera := r.siguiente()
+	orden := make([]entidad.PrioridadNotificacion, 0, len(prioridades))
+	orden = append(orden, primera)
+	for _, prioridad := range prioridades {
+		if prioridad != primera {
+			orden = append(orden, prioridad)
+		}
+	}
+	return orden
+}
@@ -0,0 +1,99 @@
+package twilio
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// EncabezadoFirma lleva la firma con que Twilio autentica sus llamadas
+const EncabezadoFirma = "X-Twilio-Signature"
+
+// estadosFinales indica si cada estado final de MessageStatus o CallStatus es una entrega; los
+// estados ausentes son intermedios (queued, sending, sent, ringing) y no cambian la notificación
+var estadosFinales = map[string]bool{
+	"delivered": true, "read": true, "completed": true,
+	"undelivered": false, "failed": false, "canceled": false, "busy": false, "no-answer": false,
+}
+
+// RecibirWebhook implementa controlador.ReceptorWebhookProveedor con el formulario del cuerpo
+func (e *EnviadorTwilio) RecibirWebhook(ctx context.Context, encabezados http.Header, cuerpo []byte) error {
+	formulario, err := url.ParseQuery(string(cuerpo))
+	if err != nil {
+		return entidad.NewErrorValidacion("el cuerpo no es un formulario de Twilio")
+	}
+	return e.RecibirAcuse(ctx, formulario, encabezados.Get(EncabezadoFirma))
+}
+
+// RecibirAcuse verifica la firma del acuse que Twilio envió a la URL de acuses, de un mensaje o
+// de una llamada, y si informa un estado final lo aplica. La firma se verifica con el token de
+// la cuenta que envió el mensaje: la del inquilino si tiene credenciales propias o la de la
+// plataforma.
+func (e *EnviadorTwilio) RecibirAcuse(ctx context.Context, formulario url.Values, firma string) error {
+	sid, estado := formulario.Get("MessageSid"), formulario.Get("MessageStatus")
+	if sid == "" {
+		sid, estado = formulario.Get("CallSid"), formulario.Get("CallStatus")
+	}
+	referencia, err := e.registro.Resolver(ctx, ProveedorTwilio, sid)
+	if err != nil {
+		return err
+	}
+	token := e.config.TokenAutenticacion
+	if referencia != nil && referencia.InquilinoID != 0 {
+		credenciales, err := e.credenciales.Resolver(ctx, referencia.InquilinoID, ProveedorTwilio)
+		if err != nil {
+			return err
+		}
+		if credenciales["auth_token"] != "" {
+			token = credenciales["auth_token"]
+		}
+	}
+	if !FirmaValida(token, e.config.URLAcuses, formulario, firma) {
+		return entidad.ErrFirmaProveedorInvalida
+	}
+	if referencia == nil {
+		e.logger.Debug("Acuse de un mensaje sin registrar", "proveedor", ProveedorTwilio, "id_mensaje", sid)
+		return nil
+	}
+
+	entregado, final := estadosFinales[estado]
+	if !final {
+		return nil
+	}
+	return e.receptor.Acusar(ctx, entidad.AcuseEntrega{
+		Proveedor:   ProveedorTwilio,
+		IDMensaje:   sid,
+		Estado:      estado,
+		Entregado:   entregado,
+		CodigoError: formulario.Get("ErrorCode"),
+	})
+}
+
+// FirmaValida compara la firma con el HMAC-SHA1, en base64, de la URL seguida de cada
+// parámetro del formulario y su valor, ordenados por nombre y sin separadores
+func FirmaValida(token, direccion string, formulario url.Values, firma string) bool {
+	nombres := make([]string, 0, len(formulario))
+	for nombre := range formulario {
+		nombres = append(nombres, nombre)
+	}
+	sort.Strings(nombres)
+	var datos strings.Builder
+	datos.WriteString(direccion)
+	for _, nombre := range nombres {
+		for _, valor := range formulario[nombre] {
+			datos.WriteString(nombre + valor)
+		}
+	}
+
+	mac := hmac.New(sha1.New, []byte(token))
+	mac.Write([]byte(datos.String()))
+	esperada := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(esperada), []byte(firma))
+}
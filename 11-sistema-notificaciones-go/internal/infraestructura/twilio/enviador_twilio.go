@@ -0,0 +1,240 @@
+package twilio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/internal/infraestructura/clienteHTTP"
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+	"sistema-notificaciones-go/pkg/logger"
+)
+
+const (
+	// ProveedorTwilio identifica las credenciales propias de un inquilino, los acuses y el
+	// cliente HTTP saliente de este enviador
+	ProveedorTwilio = "twilio"
+
+	versionAPI = "2010-04-01"
+)
+
+// ReceptorAcuses aplica los acuses de entrega que informa Twilio
+type ReceptorAcuses interface {
+	Acusar(ctx context.Context, acuse entidad.AcuseEntrega) error
+}
+
+// ResolvedorCredenciales retorna las credenciales propias del inquilino, o nil si usa las de
+// la plataforma; lo implementa casoUso.CasoUsoCredencialesProveedor
+type ResolvedorCredenciales interface {
+	Resolver(ctx context.Context, inquilinoID uint, proveedor string) (map[string]string, error)
+}
+
+// AcortadorEnlaces reemplaza las URL largas del mensaje por enlaces cortos
+type AcortadorEnlaces interface {
+	Acortar(ctx context.Context, notificacion *entidad.Notificacion) (string, error)
+}
+
+// cuenta es la cuenta de Twilio del envío: la de la plataforma, o la del inquilino si está en el contexto
+type cuenta struct {
+	sid                string
+	token              string
+	remitente          string
+	servicioMensajeria string
+	remitenteVoz       string
+}
+
+// mensajeTwilio es la parte que se usa del recurso Message y de los errores de la API
+type mensajeTwilio struct {
+	SID     string `json:"sid"`
+	Codigo  int    `json:"code"`
+	Mensaje string `json:"message"`
+}
+
+// EnviadorTwilio entrega notificaciones TipoSMS al teléfono del usuario por la API REST de
+// Twilio y aplica los acuses que Twilio informa a la URL de acuses
+type EnviadorTwilio struct {
+	config             configuracion.ConfiguracionTwilio
+	repositorioUsuario repositorio.RepositorioUsuario
+	registro           repositorio.RegistroMensajesProveedor
+	receptor           ReceptorAcuses
+	credenciales       ResolvedorCredenciales
+	acortador          AcortadorEnlaces
+	cliente            *http.Client
+	logger             *logger.Logger
+}
+
+// NuevoEnviadorTwilio crea una nueva instancia de EnviadorTwilio
+func NuevoEnviadorTwilio(
+	config configuracion.ConfiguracionTwilio,
+	repositorioUsuario repositorio.RepositorioUsuario,
+	registro repositorio.RegistroMensajesProveedor,
+	receptor ReceptorAcuses,
+	credenciales ResolvedorCredenciales,
+	acortador AcortadorEnlaces,
+	cliente *http.Client,
+	log *logger.Logger,
+) *EnviadorTwilio {
+	return &EnviadorTwilio{
+		config:             config,
+		repositorioUsuario: repositorioUsuario,
+		registro:           registro,
+		receptor:           receptor,
+		credenciales:       credenciales,
+		acortador:          acortador,
+		cliente:            cliente,
+		logger:             log.Componente(logger.ComponenteProveedores),
+	}
+}
+
+// Tipo implementa servicio.ProveedorNotificacion
+func (e *EnviadorTwilio) Tipo() entidad.TipoNotificacion {
+	return entidad.TipoSMS
+}
+
+// Proveedor implementa servicio.EnviadorConCredenciales. Las credenciales del inquilino
+// admiten las claves account_sid, auth_token, remitente, servicio_mensajeria y remitente_voz.
+func (e *EnviadorTwilio) Proveedor() string {
+	return ProveedorTwilio
+}
+
+// SoportaIdempotencia implementa servicio.EnviadorIdempotente: cada llamada lleva el token del
+// intento en Idempotency-Key, pero solo se reintenta si la API está configurada para respetarlo
+func (e *EnviadorTwilio) SoportaIdempotencia() bool {
+	return e.config.Idempotente
+}
+
+// Enviar crea el mensaje con sus URL acortadas y, si se piden acuses, registra su SID para
+// aplicar el estado final cuando Twilio lo informe. Twilio parte el texto en segmentos.
+func (e *EnviadorTwilio) Enviar(ctx context.Context, notificacion *entidad.Notificacion) error {
+	usuario, err := e.repositorioUsuario.ObtenerPorID(ctx, notificacion.UsuarioID)
+	if err != nil {
+		return err
+	}
+	if usuario.Telefono == "" {
+		return entidad.NewErrorValidacion("el usuario no tiene teléfono")
+	}
+	c, err := e.cuenta(ctx)
+	if err != nil {
+		return err
+	}
+	texto, err := e.acortador.Acortar(ctx, notificacion)
+	if err != nil {
+		// Sin acortar el mensaje puede ocupar más segmentos, pero llega igual
+		e.logger.Warn("No se pudieron acortar los enlaces del SMS", "notificacion_id", notificacion.ID, "error", err)
+	}
+
+	formulario := url.Values{"To": {usuario.Telefono}, "Body": {texto}}
+	if c.servicioMensajeria != "" {
+		formulario.Set("MessagingServiceSid", c.servicioMensajeria)
+	} else {
+		formulario.Set("From", c.remitente)
+	}
+	if e.config.URLAcuses != "" {
+		formulario.Set("StatusCallback", e.config.URLAcuses)
+	}
+	mensaje, err := e.crear(ctx, c, "Messages.json", formulario, "el mensaje")
+	if err != nil {
+		return err
+	}
+	e.registrarAcuse(ctx, mensaje.SID, notificacion)
+	return nil
+}
+
+// crear hace el POST que crea el recurso (Messages.json, Calls.json) en la cuenta y retorna su
+// SID; descripcion nombra el recurso en el error de un rechazo
+func (e *EnviadorTwilio) crear(ctx context.Context, c cuenta, recurso string, formulario url.Values, descripcion string) (mensajeTwilio, error) {
+	var mensaje mensajeTwilio
+	base, err := e.urlAPI(ctx)
+	if err != nil {
+		return mensaje, err
+	}
+	direccion := base + "/" + versionAPI + "/Accounts/" + url.PathEscape(c.sid) + "/" + recurso
+	solicitud, err := http.NewRequestWithContext(ctx, http.MethodPost, direccion, strings.NewReader(formulario.Encode()))
+	if err != nil {
+		return mensaje, err
+	}
+	solicitud.SetBasicAuth(c.sid, c.token)
+	solicitud.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	clienteHTTP.AgregarIdempotencia(solicitud)
+
+	respuesta, err := e.cliente.Do(solicitud)
+	if err != nil {
+		return mensaje, err
+	}
+	defer respuesta.Body.Close()
+	detalle, _ := io.ReadAll(io.LimitReader(respuesta.Body, 4096))
+	_ = json.Unmarshal(detalle, &mensaje)
+
+	switch {
+	case respuesta.StatusCode >= 200 && respuesta.StatusCode < 300:
+		return mensaje, nil
+	case respuesta.StatusCode == http.StatusBadRequest:
+		// Número inválido, destinatario dado de baja (21610) o remitente no habilitado: reintentar no sirve
+		return mensaje, entidad.NewErrorValidacion(fmt.Sprintf("Twilio rechazó %s (%d): %s", descripcion, mensaje.Codigo, mensaje.Mensaje))
+	default:
+		return mensaje, fmt.Errorf("Twilio respondió %d: %s", respuesta.StatusCode, bytes.TrimSpace(detalle))
+	}
+}
+
+// registrarAcuse recuerda el SID, si se piden acuses, para aplicar el estado final cuando
+// Twilio lo informe
+func (e *EnviadorTwilio) registrarAcuse(ctx context.Context, sid string, notificacion *entidad.Notificacion) {
+	if e.config.URLAcuses == "" || sid == "" {
+		return
+	}
+	referencia := entidad.ReferenciaMensaje{InquilinoID: notificacion.InquilinoID, NotificacionID: notificacion.ID, Partes: 1}
+	if err := e.registro.Registrar(ctx, ProveedorTwilio, []string{sid}, referencia, e.config.VigenciaAcuses); err != nil {
+		// Twilio ya aceptó el mensaje: sin el registro solo se pierde su acuse
+		e.logger.Warn("No se pudo registrar el mensaje para su acuse", "notificacion_id", notificacion.ID, "error", err)
+	}
+}
+
+// cuenta resuelve la cuenta del envío: las credenciales del inquilino reemplazan a las de la
+// plataforma campo a campo
+func (e *EnviadorTwilio) cuenta(ctx context.Context) (cuenta, error) {
+	c := cuenta{
+		sid:                e.config.SIDCuenta,
+		token:              e.config.TokenAutenticacion,
+		remitente:          e.config.Remitente,
+		servicioMensajeria: e.config.ServicioMensajeria,
+		remitenteVoz:       e.config.RemitenteVoz,
+	}
+	if credenciales, ok := servicio.CredencialesDesdeContexto(ctx); ok {
+		for clave, destino := range map[string]*string{
+			"account_sid": &c.sid, "auth_token": &c.token,
+			"remitente": &c.remitente, "servicio_mensajeria": &c.servicioMensajeria,
+			"remitente_voz": &c.remitenteVoz,
+		} {
+			if valor := credenciales[clave]; valor != "" {
+				*destino = valor
+			}
+		}
+	}
+
+	if c.sid == "" || c.token == "" || (c.remitente == "" && c.servicioMensajeria == "") {
+		return c, fmt.Errorf("cuenta de Twilio no configurada")
+	}
+	return c, nil
+}
+
+// urlAPI es la URL de la API, o el endpoint regional (p. ej. https://api.dublin.ie1.twilio.com)
+// si está en el contexto
+func (e *EnviadorTwilio) urlAPI(ctx context.Context) (string, error) {
+	endpoint, ok := servicio.EndpointProveedorDesdeContexto(ctx)
+	if !ok {
+		return strings.TrimRight(e.config.URLAPI, "/"), nil
+	}
+	direccion, err := url.Parse(endpoint)
+	if err != nil || direccion.Host == "" {
+		return "", fmt.Errorf("endpoint Twilio regional inválido: %q", endpoint)
+	}
+	return strings.TrimRight(endpoint, "/"), nil
+}
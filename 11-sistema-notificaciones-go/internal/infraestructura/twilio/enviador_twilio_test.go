@@ -0,0 +1,85 @@
+package twilio
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/internal/infraestructura/clienteHTTP"
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+	"sistema-notificaciones-go/pkg/logger"
+)
+
+// servidorTwilio responde como la API de mensajes y guarda la clave de idempotencia de cada llamada
+func servidorTwilio(t *testing.T, claves *[]string) *httptest.Server {
+	t.Helper()
+	servidor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*claves = append(*claves, r.Header.Get(clienteHTTP.CabeceraIdempotencia))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"sid":"SM123"}`))
+	}))
+	t.Cleanup(servidor.Close)
+	return servidor
+}
+
+func nuevoEnviadorDePrueba(urlAPI string, idempotente bool, cliente *http.Client) *EnviadorTwilio {
+	config := configuracion.ConfiguracionTwilio{URLAPI: urlAPI, Idempotente: idempotente}
+	return NuevoEnviadorTwilio(config, nil, nil, nil, nil, nil, cliente, logger.NuevoLogger())
+}
+
+func crearMensaje(t *testing.T, enviador *EnviadorTwilio, ctx context.Context) {
+	t.Helper()
+	c := cuenta{sid: "AC123", token: "secreto", remitente: "+15550000000"}
+	formulario := url.Values{"To": {"+15551111111"}, "Body": {"Hola"}}
+	if _, err := enviador.crear(ctx, c, "Messages.json", formulario, "el mensaje"); err != nil {
+		t.Fatalf("creando el mensaje: %v", err)
+	}
+}
+
+func TestCrearEnviaElTokenDelIntentoComoClaveDeIdempotencia(t *testing.T) {
+	var claves []string
+	servidor := servidorTwilio(t, &claves)
+	enviador := nuevoEnviadorDePrueba(servidor.URL, true, servidor.Client())
+	intento := entidad.NuevoIntentoEnvio(7, 2, time.Now())
+	ctx := servicio.ContextoConTokenIdempotencia(context.Background(), intento.TokenIdempotencia)
+
+	// El reintento de un resultado incierto repite el intento y, con él, la clave
+	crearMensaje(t, enviador, ctx)
+	crearMensaje(t, enviador, ctx)
+
+	if len(claves) != 2 {
+		t.Fatalf("llamadas = %d, se esperaban 2", len(claves))
+	}
+	for i, clave := range claves {
+		if clave != intento.TokenIdempotencia {
+			t.Errorf("llamada %d: %s = %q, se esperaba %q", i+1, clienteHTTP.CabeceraIdempotencia, clave, intento.TokenIdempotencia)
+		}
+	}
+}
+
+func TestCrearSinTokenNoEnviaClaveDeIdempotencia(t *testing.T) {
+	var claves []string
+	servidor := servidorTwilio(t, &claves)
+	enviador := nuevoEnviadorDePrueba(servidor.URL, true, servidor.Client())
+
+	crearMensaje(t, enviador, context.Background())
+
+	if len(claves) != 1 || claves[0] != "" {
+		t.Fatalf("claves = %q, se esperaba una llamada sin clave", claves)
+	}
+}
+
+func TestSoportaIdempotenciaSegunLaConfiguracion(t *testing.T) {
+	for _, idempotente := range []bool{true, false} {
+		var enviador servicio.EnviadorIdempotente = nuevoEnviadorDePrueba("", idempotente, nil)
+		if enviador.SoportaIdempotencia() != idempotente {
+			t.Errorf("con TWILIO_IDEMPOTENTE=%v SoportaIdempotencia() = %v", idempotente, !idempotente)
+		}
+	}
+}
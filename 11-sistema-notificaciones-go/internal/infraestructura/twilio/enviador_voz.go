@@ -0,0 +1,113 @@
+package twilio
+
+import (
+	"context"
+	"encoding/xml"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// largoMaximoTwiML es el límite de Twilio para el TwiML enviado en el parámetro Twiml
+const largoMaximoTwiML = 4000
+
+// EnviadorVozTwilio entrega notificaciones TipoVoz con una llamada de Twilio Voice al teléfono
+// del usuario, que lee el título y el mensaje por síntesis de voz. Usa la cuenta, las
+// credenciales y los acuses del EnviadorTwilio de los SMS.
+type EnviadorVozTwilio struct {
+	twilio *EnviadorTwilio
+}
+
+// NuevoEnviadorVozTwilio crea una nueva instancia de EnviadorVozTwilio
+func NuevoEnviadorVozTwilio(twilio *EnviadorTwilio) *EnviadorVozTwilio {
+	return &EnviadorVozTwilio{twilio: twilio}
+}
+
+// Tipo implementa servicio.ProveedorNotificacion
+func (e *EnviadorVozTwilio) Tipo() entidad.TipoNotificacion {
+	return entidad.TipoVoz
+}
+
+// Proveedor implementa servicio.EnviadorConCredenciales con las credenciales de Twilio
+func (e *EnviadorVozTwilio) Proveedor() string {
+	return ProveedorTwilio
+}
+
+// Enviar inicia la llamada. Twilio la da por entregada al completarse; si no se atiende, da
+// ocupado o falla, su acuse deja la notificación fallida.
+func (e *EnviadorVozTwilio) Enviar(ctx context.Context, notificacion *entidad.Notificacion) error {
+	usuario, err := e.twilio.repositorioUsuario.ObtenerPorID(ctx, notificacion.UsuarioID)
+	if err != nil {
+		return err
+	}
+	if usuario.Telefono == "" {
+		return entidad.NewErrorValidacion("el usuario no tiene teléfono")
+	}
+	c, err := e.twilio.cuenta(ctx)
+	if err != nil {
+		return err
+	}
+	desde := c.remitenteVoz
+	if desde == "" {
+		desde = c.remitente
+	}
+	if !strings.HasPrefix(desde, "+") {
+		// Un remitente alfanumérico o un servicio de mensajería no pueden llamar
+		return entidad.NewErrorValidacion("la cuenta de Twilio no tiene un número para llamar")
+	}
+	twiml, err := e.twiml(notificacion)
+	if err != nil {
+		return err
+	}
+
+	config := e.twilio.config
+	formulario := url.Values{
+		"To":      {usuario.Telefono},
+		"From":    {desde},
+		"Twiml":   {twiml},
+		"Timeout": {strconv.Itoa(int(config.TimeoutLlamada.Seconds()))},
+	}
+	if config.URLAcuses != "" {
+		formulario.Set("StatusCallback", config.URLAcuses)
+	}
+	llamada, err := e.twilio.crear(ctx, c, "Calls.json", formulario, "la llamada")
+	if err != nil {
+		return err
+	}
+	e.twilio.registrarAcuse(ctx, llamada.SID, notificacion)
+	return nil
+}
+
+// twiml arma la respuesta que lee el título y el mensaje, con una pausa entre repeticiones
+func (e *EnviadorVozTwilio) twiml(notificacion *entidad.Notificacion) (string, error) {
+	var texto strings.Builder
+	if err := xml.EscapeText(&texto, []byte(notificacion.Titulo+". "+notificacion.Mensaje)); err != nil {
+		return "", err
+	}
+	config := e.twilio.config
+	var atributos strings.Builder
+	for _, atributo := range [][2]string{{"voice", config.VozTTS}, {"language", config.IdiomaVoz}} {
+		if atributo[1] == "" {
+			continue
+		}
+		atributos.WriteString(" " + atributo[0] + `="`)
+		_ = xml.EscapeText(&atributos, []byte(atributo[1]))
+		atributos.WriteString(`"`)
+	}
+
+	var twiml strings.Builder
+	twiml.WriteString("<Response>")
+	for i := 0; i < config.RepeticionesVoz; i++ {
+		if i > 0 {
+			twiml.WriteString(`<Pause length="1"/>`)
+		}
+		twiml.WriteString("<Say" + atributos.String() + ">" + texto.String() + "</Say>")
+	}
+	twiml.WriteString("</Response>")
+	if twiml.Len() > largoMaximoTwiML {
+		return "", entidad.NewErrorValidacion("el mensaje es demasiado largo para leerse en una llamada")
+	}
+	return twiml.String(), nil
+}
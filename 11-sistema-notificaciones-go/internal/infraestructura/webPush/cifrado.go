@@ -0,0 +1,91 @@
+package webPush
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	// tamanoRegistro es el único registro aes128gcm del mensaje; los servicios push aceptan
+	// hasta 4096 bytes de cuerpo
+	tamanoRegistro = 4096
+	// longitudEncabezado es sal (16) + tamaño de registro (4) + longitud del id (1) + clave (65)
+	longitudEncabezado = 16 + 4 + 1 + 65
+	// MaximoContenido es el mayor contenido en claro que cabe en un mensaje: se restan el
+	// encabezado, el delimitador de relleno y la etiqueta de autenticación de AES-GCM
+	MaximoContenido = tamanoRegistro - longitudEncabezado - 1 - 16
+)
+
+// cifrar cifra el contenido para la suscripción según RFC 8291 (aes128gcm, RFC 8188) con una
+// clave efímera por mensaje. p256dh es la clave pública del navegador y auth su secreto.
+func cifrar(contenido, p256dh, auth []byte) ([]byte, error) {
+	efimera, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	sal := make([]byte, 16)
+	if _, err := rand.Read(sal); err != nil {
+		return nil, err
+	}
+	return cifrarCon(contenido, p256dh, auth, efimera, sal)
+}
+
+// cifrarCon cifra con la clave efímera y la sal dadas; separado de cifrar para reproducir los
+// vectores de prueba del RFC
+func cifrarCon(contenido, p256dh, auth []byte, efimera *ecdh.PrivateKey, sal []byte) ([]byte, error) {
+	if len(contenido) > MaximoContenido {
+		return nil, fmt.Errorf("el contenido excede %d bytes", MaximoContenido)
+	}
+	clavePublicaNavegador, err := ecdh.P256().NewPublicKey(p256dh)
+	if err != nil {
+		return nil, fmt.Errorf("clave p256dh inválida: %w", err)
+	}
+	secreto, err := efimera.ECDH(clavePublicaNavegador)
+	if err != nil {
+		return nil, err
+	}
+
+	// IKM = HKDF(auth, secreto ECDH, "WebPush: info" || clave del navegador || clave efímera)
+	clavePublicaEfimera := efimera.PublicKey().Bytes()
+	info := append([]byte("WebPush: info\x00"), p256dh...)
+	info = append(info, clavePublicaEfimera...)
+	ikm := hkdf(auth, secreto, info, 32)
+
+	cek := hkdf(sal, ikm, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	nonce := hkdf(sal, ikm, []byte("Content-Encoding: nonce\x00"), 12)
+	bloque, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(bloque)
+	if err != nil {
+		return nil, err
+	}
+
+	mensaje := make([]byte, 0, longitudEncabezado+len(contenido)+1+gcm.Overhead())
+	mensaje = append(mensaje, sal...)
+	mensaje = binary.BigEndian.AppendUint32(mensaje, tamanoRegistro)
+	mensaje = append(mensaje, byte(len(clavePublicaEfimera)))
+	mensaje = append(mensaje, clavePublicaEfimera...)
+	// 0x02 marca el último (y único) registro, sin relleno adicional
+	registro := append(append(make([]byte, 0, len(contenido)+1), contenido...), 0x02)
+	return gcm.Seal(mensaje, nonce, registro, nil), nil
+}
+
+// hkdf deriva hasta 32 bytes (un solo bloque de HKDF-Expand con SHA-256)
+func hkdf(sal, ikm, info []byte, longitud int) []byte {
+	extraccion := hmac.New(sha256.New, sal)
+	extraccion.Write(ikm)
+	prk := extraccion.Sum(nil)
+
+	expansion := hmac.New(sha256.New, prk)
+	expansion.Write(info)
+	expansion.Write([]byte{1})
+	return expansion.Sum(nil)[:longitud]
+}
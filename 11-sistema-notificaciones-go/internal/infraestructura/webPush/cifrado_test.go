@@ -0,0 +1,69 @@
+package webPush
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"encoding/base64"
+	"testing"
+)
+
+// Vectores del apéndice A de RFC 8291
+const (
+	vectorContenido        = "When I grow up, I want to be a watermelon"
+	vectorPrivadaServidor  = "yfWPiYE-n46HLnH0KqZOF1fJJU3MYrct3AELtAQ-oRw"
+	vectorPublicaServidor  = "BP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A8"
+	vectorPublicaNavegador = "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4"
+	vectorSecretoAuth      = "BTBZMqHH6r4Tts7J_aSIgg"
+	vectorSal              = "DGv6ra1nlYgDCS1FRnbzlw"
+	vectorMensajeCifrado   = "DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A_yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN"
+)
+
+func decodificar(t *testing.T, valor string) []byte {
+	t.Helper()
+	bytesDecodificados, err := base64.RawURLEncoding.DecodeString(valor)
+	if err != nil {
+		t.Fatalf("decodificando %q: %v", valor, err)
+	}
+	return bytesDecodificados
+}
+
+func TestCifrarReproduceElVectorDelRFC8291(t *testing.T) {
+	efimera, err := ecdh.P256().NewPrivateKey(decodificar(t, vectorPrivadaServidor))
+	if err != nil {
+		t.Fatalf("clave del servidor: %v", err)
+	}
+	if !bytes.Equal(efimera.PublicKey().Bytes(), decodificar(t, vectorPublicaServidor)) {
+		t.Fatal("la clave pública del servidor no corresponde a la privada del vector")
+	}
+
+	mensaje, err := cifrarCon([]byte(vectorContenido), decodificar(t, vectorPublicaNavegador), decodificar(t, vectorSecretoAuth), efimera, decodificar(t, vectorSal))
+	if err != nil {
+		t.Fatalf("cifrando: %v", err)
+	}
+
+	if obtenido := base64.RawURLEncoding.EncodeToString(mensaje); obtenido != vectorMensajeCifrado {
+		t.Errorf("mensaje cifrado:\n obtenido %s\n esperado %s", obtenido, vectorMensajeCifrado)
+	}
+}
+
+func TestCifrarRechazaContenidoQueNoCabeEnUnRegistro(t *testing.T) {
+	navegador := decodificar(t, vectorPublicaNavegador)
+	auth := decodificar(t, vectorSecretoAuth)
+
+	mensaje, err := cifrar(make([]byte, MaximoContenido), navegador, auth)
+	if err != nil {
+		t.Fatalf("el máximo debería caber: %v", err)
+	}
+	if len(mensaje) != tamanoRegistro {
+		t.Errorf("el mensaje mide %d bytes, se esperaban %d", len(mensaje), tamanoRegistro)
+	}
+	if _, err := cifrar(make([]byte, MaximoContenido+1), navegador, auth); err == nil {
+		t.Fatal("se esperaba un error al exceder el máximo")
+	}
+}
+
+func TestCifrarRechazaUnaClaveDeNavegadorInvalida(t *testing.T) {
+	if _, err := cifrar([]byte("hola"), []byte{0x04, 1, 2, 3}, decodificar(t, vectorSecretoAuth)); err == nil {
+		t.Fatal("se esperaba un error con una clave p256dh inválida")
+	}
+}
@@ -0,0 +1,195 @@
+// Package webPush entrega notificaciones push a los navegadores por el protocolo Web Push
+// (RFC 8030), con mensajes cifrados (RFC 8291) y firmados con VAPID (RFC 8292)
+package webPush
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// ProveedorWebPush identifica al enviador en las métricas y en el pool de clientes HTTP
+const ProveedorWebPush = "web_push"
+
+// urgencias traduce la prioridad al encabezado Urgency: los navegadores en ahorro de energía
+// difieren los mensajes de baja urgencia
+var urgencias = map[entidad.PrioridadNotificacion]string{
+	entidad.PrioridadBaja:    "low",
+	entidad.PrioridadNormal:  "normal",
+	entidad.PrioridadAlta:    "high",
+	entidad.PrioridadCritica: "high",
+}
+
+// MensajeWebPush es el contenido que recibe el service worker en el evento push
+type MensajeWebPush struct {
+	ID        uint                          `json:"id"`
+	Titulo    string                        `json:"titulo"`
+	Mensaje   string                        `json:"mensaje"`
+	Prioridad entidad.PrioridadNotificacion `json:"prioridad"`
+	Metadatos map[string]interface{}        `json:"metadatos,omitempty"`
+	// URLCodigoQR es la imagen del código QR de la notificación, p. ej. para una entrada
+	URLCodigoQR string `json:"url_codigo_qr,omitempty"`
+}
+
+// EnviadorWebPush entrega notificaciones TipoPush a las suscripciones de navegador del usuario
+type EnviadorWebPush struct {
+	repositorioDispositivo repositorio.RepositorioDispositivo
+	firmante               *firmanteVAPID
+	ttl                    time.Duration
+	cliente                *http.Client
+	reloj                  reloj.Reloj
+	logger                 *logger.Logger
+}
+
+// NuevoEnviadorWebPush crea el enviador; falla si las claves VAPID no forman un par
+func NuevoEnviadorWebPush(config configuracion.ConfiguracionWebPush, repositorioDispositivo repositorio.RepositorioDispositivo, cliente *http.Client, rel reloj.Reloj, log *logger.Logger) (*EnviadorWebPush, error) {
+	firmante, err := nuevoFirmanteVAPID(config.ClavePublica, config.ClavePrivada, config.Sujeto)
+	if err != nil {
+		return nil, err
+	}
+	return &EnviadorWebPush{
+		repositorioDispositivo: repositorioDispositivo,
+		firmante:               firmante,
+		ttl:                    config.TTL,
+		cliente:                cliente,
+		reloj:                  rel,
+		logger:                 log.Componente(logger.ComponenteProveedores),
+	}, nil
+}
+
+// Tipo implementa servicio.ProveedorNotificacion
+func (e *EnviadorWebPush) Tipo() entidad.TipoNotificacion {
+	return entidad.TipoPush
+}
+
+// Enviar cifra la notificación para cada suscripción de navegador del usuario. Basta con que
+// un navegador la acepte; las suscripciones que el servicio push da por vencidas se eliminan.
+func (e *EnviadorWebPush) Enviar(ctx context.Context, notificacion *entidad.Notificacion) error {
+	dispositivos, err := e.repositorioDispositivo.ListarPorUsuario(ctx, notificacion.UsuarioID)
+	if err != nil {
+		return err
+	}
+	contenido, err := contenidoMensaje(notificacion)
+	if err != nil {
+		return err
+	}
+
+	var entregadas int
+	var errs []error
+	for i := range dispositivos {
+		dispositivo := &dispositivos[i]
+		if !dispositivo.EsWebPush() {
+			continue
+		}
+		err := e.enviarA(ctx, dispositivo, notificacion, contenido)
+		switch {
+		case err == nil:
+			entregadas++
+		case errors.Is(err, errSuscripcionVencida):
+			e.descartar(ctx, dispositivo)
+		default:
+			errs = append(errs, err)
+		}
+	}
+	if entregadas > 0 {
+		return nil
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return entidad.NewErrorValidacion("el usuario no tiene suscripciones Web Push")
+}
+
+// errSuscripcionVencida indica que el servicio push ya no reconoce el endpoint (404 o 410)
+var errSuscripcionVencida = errors.New("suscripción Web Push vencida")
+
+// enviarA cifra el contenido para el navegador y lo entrega a su servicio push
+func (e *EnviadorWebPush) enviarA(ctx context.Context, dispositivo *entidad.DispositivoPush, notificacion *entidad.Notificacion, contenido []byte) error {
+	p256dh, err := codificacion.DecodeString(dispositivo.ClaveP256dh)
+	if err != nil {
+		return fmt.Errorf("clave p256dh inválida: %w", err)
+	}
+	auth, err := codificacion.DecodeString(dispositivo.ClaveAuth)
+	if err != nil {
+		return fmt.Errorf("clave auth inválida: %w", err)
+	}
+	cuerpo, err := cifrar(contenido, p256dh, auth)
+	if err != nil {
+		return err
+	}
+	autorizacion, err := e.firmante.autorizacion(dispositivo.Token, e.reloj.Ahora())
+	if err != nil {
+		return err
+	}
+
+	solicitud, err := http.NewRequestWithContext(ctx, http.MethodPost, dispositivo.Token, bytes.NewReader(cuerpo))
+	if err != nil {
+		return err
+	}
+	solicitud.Header.Set("Authorization", autorizacion)
+	solicitud.Header.Set("Content-Encoding", "aes128gcm")
+	solicitud.Header.Set("Content-Type", "application/octet-stream")
+	solicitud.Header.Set("TTL", strconv.Itoa(int(e.ttl/time.Second)))
+	if urgencia, existe := urgencias[notificacion.Prioridad]; existe {
+		solicitud.Header.Set("Urgency", urgencia)
+	}
+
+	respuesta, err := e.cliente.Do(solicitud)
+	if err != nil {
+		return err
+	}
+	defer respuesta.Body.Close()
+	detalle, _ := io.ReadAll(io.LimitReader(respuesta.Body, 512))
+
+	switch {
+	case respuesta.StatusCode >= 200 && respuesta.StatusCode < 300:
+		return nil
+	case respuesta.StatusCode == http.StatusNotFound || respuesta.StatusCode == http.StatusGone:
+		return errSuscripcionVencida
+	default:
+		return fmt.Errorf("servicio push respondió %d: %s", respuesta.StatusCode, bytes.TrimSpace(detalle))
+	}
+}
+
+// descartar elimina la suscripción vencida; si falla se reintenta en el próximo envío
+func (e *EnviadorWebPush) descartar(ctx context.Context, dispositivo *entidad.DispositivoPush) {
+	if err := e.repositorioDispositivo.EliminarPorToken(ctx, dispositivo.UsuarioID, dispositivo.Token); err != nil && !errors.Is(err, entidad.ErrDispositivoNoEncontrado) {
+		e.logger.Warn("Error eliminando suscripción Web Push vencida", "dispositivo_id", dispositivo.ID, "error", err)
+		return
+	}
+	e.logger.Info("Suscripción Web Push vencida eliminada", "dispositivo_id", dispositivo.ID, "usuario_id", dispositivo.UsuarioID)
+}
+
+// contenidoMensaje serializa la notificación; si no cabe en un mensaje se omiten los
+// metadatos y, si aun así no cabe, el envío no puede reintentarse
+func contenidoMensaje(notificacion *entidad.Notificacion) ([]byte, error) {
+	mensaje := MensajeWebPush{
+		ID:          notificacion.ID,
+		Titulo:      notificacion.Titulo,
+		Mensaje:     notificacion.Mensaje,
+		Prioridad:   notificacion.Prioridad,
+		Metadatos:   notificacion.Metadatos,
+		URLCodigoQR: notificacion.URLCodigoQR,
+	}
+	contenido, err := json.Marshal(mensaje)
+	if err != nil || len(contenido) <= MaximoContenido {
+		return contenido, err
+	}
+	mensaje.Metadatos = nil
+	if contenido, err = json.Marshal(mensaje); err != nil || len(contenido) <= MaximoContenido {
+		return contenido, err
+	}
+	return nil, entidad.NewErrorValidacion(fmt.Sprintf("la notificación excede los %d bytes de un mensaje Web Push", MaximoContenido))
+}
@@ -0,0 +1,86 @@
+package webPush
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/url"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// vigenciaFirma es la validez del JWT VAPID; los servicios push rechazan más de 24 horas
+const vigenciaFirma = 12 * time.Hour
+
+// codificacion es base64url sin relleno, como las claves de la PushSubscription y de VAPID
+var codificacion = base64.RawURLEncoding
+
+// GenerarClavesVAPID crea un par de claves P-256 para VAPID (RFC 8292) en base64url: la pública
+// se entrega a los navegadores como applicationServerKey y la privada firma cada envío
+func GenerarClavesVAPID() (publica, privada string, err error) {
+	clave, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	return codificacion.EncodeToString(clave.PublicKey().Bytes()), codificacion.EncodeToString(clave.Bytes()), nil
+}
+
+// firmanteVAPID firma las solicitudes a los servicios push con la clave de la plataforma
+type firmanteVAPID struct {
+	clave        *ecdsa.PrivateKey
+	clavePublica string
+	sujeto       string
+}
+
+// nuevoFirmanteVAPID decodifica las claves y verifica que formen un par
+func nuevoFirmanteVAPID(publica, privada, sujeto string) (*firmanteVAPID, error) {
+	escalar, err := codificacion.DecodeString(privada)
+	if err != nil {
+		return nil, fmt.Errorf("clave privada VAPID inválida: %w", err)
+	}
+	clave, err := ecdh.P256().NewPrivateKey(escalar)
+	if err != nil {
+		return nil, fmt.Errorf("clave privada VAPID inválida: %w", err)
+	}
+	punto := clave.PublicKey().Bytes()
+	if codificacion.EncodeToString(punto) != publica {
+		return nil, fmt.Errorf("la clave pública VAPID no corresponde a la privada")
+	}
+
+	// ecdh no firma: se arma la clave ECDSA equivalente con el punto sin comprimir (0x04 || X || Y)
+	return &firmanteVAPID{
+		clave: &ecdsa.PrivateKey{
+			PublicKey: ecdsa.PublicKey{
+				Curve: elliptic.P256(),
+				X:     new(big.Int).SetBytes(punto[1:33]),
+				Y:     new(big.Int).SetBytes(punto[33:]),
+			},
+			D: new(big.Int).SetBytes(escalar),
+		},
+		clavePublica: publica,
+		sujeto:       sujeto,
+	}, nil
+}
+
+// autorizacion retorna el encabezado Authorization para el endpoint: un JWT ES256 cuya
+// audiencia es el origen del servicio push, junto con la clave pública
+func (f *firmanteVAPID) autorizacion(endpoint string, ahora time.Time) (string, error) {
+	destino, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud": destino.Scheme + "://" + destino.Host,
+		"exp": ahora.Add(vigenciaFirma).Unix(),
+		"sub": f.sujeto,
+	}).SignedString(f.clave)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("vapid t=%s, k=%s", token, f.clavePublica), nil
+}
@@ -0,0 +1,99 @@
+package websocket
+
+import (
+	"time"
+
+	"sistema-notificaciones-go/pkg/logger"
+
+	gorilla "github.com/gorilla/websocket"
+)
+
+const (
+	tiempoEscritura  = 10 * time.Second
+	tiempoPong       = 60 * time.Second
+	intervaloPing    = (tiempoPong * 9) / 10
+	tamanoMaxMensaje = 4096
+)
+
+// Conexion representa un cliente WebSocket de un usuario
+type Conexion struct {
+	destino destinatario
+	conn    *gorilla.Conn
+	// formato es la codificación de los eventos según el subprotocolo negociado
+	formato formatoFrame
+	envio   chan *gorilla.PreparedMessage
+	hub     *Hub
+	logger  *logger.Logger
+}
+
+// encolar agrega un frame; si el buffer está lleno descarta el más antiguo
+func (c *Conexion) encolar(frame *gorilla.PreparedMessage) {
+	for {
+		select {
+		case c.envio <- frame:
+			metricaFramesEnviados.Inc()
+			return
+		default:
+		}
+
+		select {
+		case <-c.envio:
+			metricaFramesDescartados.Inc()
+		default:
+		}
+	}
+}
+
+// leer consume los mensajes del cliente para procesar pings/pongs y detectar el cierre
+func (c *Conexion) leer() {
+	defer func() {
+		c.hub.Desregistrar(c)
+		c.conn.Close()
+	}()
+
+	c.conn.SetReadLimit(tamanoMaxMensaje)
+	_ = c.conn.SetReadDeadline(time.Now().Add(tiempoPong))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(tiempoPong))
+	})
+
+	for {
+		if _, _, err := c.conn.ReadMessage(); err != nil {
+			if gorilla.IsUnexpectedCloseError(err, gorilla.CloseGoingAway, gorilla.CloseNormalClosure) {
+				c.logger.Debug("Conexión WebSocket cerrada inesperadamente", "inquilino_id", c.destino.inquilinoID, "usuario_id", c.destino.usuarioID, "error", err)
+			}
+			return
+		}
+	}
+}
+
+// escribir envía los frames encolados y los pings de keep-alive
+func (c *Conexion) escribir() {
+	ticker := time.NewTicker(intervaloPing)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case frame, abierto := <-c.envio:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(tiempoEscritura))
+			if !abierto {
+				_ = c.conn.WriteMessage(gorilla.CloseMessage, []byte{})
+				return
+			}
+			if c.hub.descartarTrama != nil && c.hub.descartarTrama() {
+				continue
+			}
+			if err := c.conn.WritePreparedMessage(frame); err != nil {
+				return
+			}
+		case <-ticker.C:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(tiempoEscritura))
+			if err := c.conn.WriteMessage(gorilla.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
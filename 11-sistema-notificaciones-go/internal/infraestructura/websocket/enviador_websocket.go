@@ -0,0 +1,63 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// EnviadorWebSocket entrega notificaciones TipoWebSocket a las conexiones del usuario
+type EnviadorWebSocket struct {
+	hub *Hub
+}
+
+// NuevoEnviadorWebSocket crea una nueva instancia de EnviadorWebSocket
+func NuevoEnviadorWebSocket(hub *Hub) *EnviadorWebSocket {
+	return &EnviadorWebSocket{hub: hub}
+}
+
+// Tipo implementa servicio.ProveedorNotificacion
+func (e *EnviadorWebSocket) Tipo() entidad.TipoNotificacion {
+	return entidad.TipoWebSocket
+}
+
+// EntregaLocal implementa servicio.EnviadorLocal: el hub entrega sin proveedor externo
+func (e *EnviadorWebSocket) EntregaLocal() bool {
+	return true
+}
+
+// Enviar publica la notificación como evento "notificacion"
+func (e *EnviadorWebSocket) Enviar(ctx context.Context, notificacion *entidad.Notificacion) error {
+	return e.hub.EnviarAUsuario(notificacion.InquilinoID, notificacion.UsuarioID, Evento{Tipo: "notificacion", Datos: notificacion})
+}
+
+// EnviadorBandeja entrega notificaciones TipoInApp: quedan en la bandeja del usuario al
+// guardarse, y las conexiones abiertas reciben el evento "bandeja" para refrescarla
+type EnviadorBandeja struct {
+	hub *Hub
+}
+
+// NuevoEnviadorBandeja crea una nueva instancia de EnviadorBandeja
+func NuevoEnviadorBandeja(hub *Hub) *EnviadorBandeja {
+	return &EnviadorBandeja{hub: hub}
+}
+
+// Tipo implementa servicio.ProveedorNotificacion
+func (e *EnviadorBandeja) Tipo() entidad.TipoNotificacion {
+	return entidad.TipoInApp
+}
+
+// EntregaLocal implementa servicio.EnviadorLocal: la bandeja está en la base del inquilino
+func (e *EnviadorBandeja) EntregaLocal() bool {
+	return true
+}
+
+// Enviar avisa a las conexiones del usuario; sin conexiones la verá al abrir la bandeja
+func (e *EnviadorBandeja) Enviar(ctx context.Context, notificacion *entidad.Notificacion) error {
+	err := e.hub.EnviarAUsuario(notificacion.InquilinoID, notificacion.UsuarioID, Evento{Tipo: "bandeja", Datos: notificacion})
+	if errors.Is(err, ErrUsuarioSinConexion) {
+		return nil
+	}
+	return err
+}
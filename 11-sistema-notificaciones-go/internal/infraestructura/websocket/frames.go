@@ -0,0 +1,99 @@
+package websocket
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+
+	gorilla "github.com/gorilla/websocket"
+)
+
+// Subprotocolos que el cliente puede pedir en Sec-WebSocket-Protocol. Sin pedir ninguno los
+// eventos se envían en JSON.
+const (
+	SubprotocoloJSON     = "notificaciones.json"
+	SubprotocoloProtobuf = "notificaciones.protobuf"
+)
+
+// Subprotocolos son los subprotocolos aceptados, en orden de preferencia del servidor
+var Subprotocolos = []string{SubprotocoloProtobuf, SubprotocoloJSON}
+
+// formatoFrame es la codificación de los eventos de una conexión
+type formatoFrame int
+
+const (
+	formatoJSON formatoFrame = iota
+	formatoProtobuf
+	cantidadFormatos
+)
+
+// formatoDeSubprotocolo retorna el formato del subprotocolo negociado
+func formatoDeSubprotocolo(subprotocolo string) formatoFrame {
+	if subprotocolo == SubprotocoloProtobuf {
+		return formatoProtobuf
+	}
+	return formatoJSON
+}
+
+// buffersCodificacion reutiliza los buffers de serialización JSON entre eventos
+var buffersCodificacion = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// framesEvento serializa el evento una sola vez por formato, y solo en los formatos que usan
+// las conexiones destinatarias. No es seguro para uso concurrente.
+type framesEvento struct {
+	evento Evento
+	frames [cantidadFormatos]*gorilla.PreparedMessage
+}
+
+func nuevosFramesEvento(evento Evento) *framesEvento {
+	return &framesEvento{evento: evento}
+}
+
+// frame retorna el frame del formato, preparándolo la primera vez
+func (f *framesEvento) frame(formato formatoFrame) (*gorilla.PreparedMessage, error) {
+	if f.frames[formato] != nil {
+		return f.frames[formato], nil
+	}
+	var frame *gorilla.PreparedMessage
+	var err error
+	if formato == formatoProtobuf {
+		frame, err = prepararFrameProtobuf(f.evento)
+	} else {
+		frame, err = prepararFrame(f.evento)
+	}
+	if err != nil {
+		return nil, err
+	}
+	f.frames[formato] = frame
+	return frame, nil
+}
+
+// prepararFrame serializa el evento una sola vez y lo envuelve en un PreparedMessage,
+// que gorilla codifica (y comprime, si se negoció) una vez por variante de conexión
+// en lugar de una vez por socket.
+func prepararFrame(evento Evento) (*gorilla.PreparedMessage, error) {
+	buffer := buffersCodificacion.Get().(*bytes.Buffer)
+	buffer.Reset()
+	defer buffersCodificacion.Put(buffer)
+
+	if err := json.NewEncoder(buffer).Encode(evento); err != nil {
+		return nil, err
+	}
+
+	// PreparedMessage conserva el slice: se copia para poder devolver el buffer al pool
+	datos := make([]byte, buffer.Len())
+	copy(datos, buffer.Bytes())
+
+	return gorilla.NewPreparedMessage(gorilla.TextMessage, datos)
+}
+
+// prepararFrameProtobuf codifica el evento como mensaje binario del subprotocolo protobuf
+func prepararFrameProtobuf(evento Evento) (*gorilla.PreparedMessage, error) {
+	datos, err := codificarEventoProtobuf(evento)
+	if err != nil {
+		return nil, err
+	}
+	return gorilla.NewPreparedMessage(gorilla.BinaryMessage, datos)
+}
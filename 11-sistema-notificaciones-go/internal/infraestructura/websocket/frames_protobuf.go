@@ -0,0 +1,120 @@
+package websocket
+
+import (
+	"encoding/json"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Números de campo de eventos.proto
+const (
+	campoEventoTipo         protowire.Number = 1
+	campoEventoNotificacion protowire.Number = 2
+	campoEventoJSON         protowire.Number = 15
+
+	campoNotificacionID            protowire.Number = 1
+	campoNotificacionUsuarioID     protowire.Number = 2
+	campoNotificacionTitulo        protowire.Number = 3
+	campoNotificacionMensaje       protowire.Number = 4
+	campoNotificacionTipo          protowire.Number = 5
+	campoNotificacionEstado        protowire.Number = 6
+	campoNotificacionPrioridad     protowire.Number = 7
+	campoNotificacionCanalID       protowire.Number = 8
+	campoNotificacionMetadatos     protowire.Number = 9
+	campoNotificacionFechaCreacion protowire.Number = 10
+	campoNotificacionFechaEnviada  protowire.Number = 11
+	campoNotificacionInquilinoID   protowire.Number = 12
+	campoNotificacionVersion       protowire.Number = 13
+
+	campoTimestampSegundos protowire.Number = 1
+	campoTimestampNanos    protowire.Number = 2
+)
+
+// codificarEventoProtobuf codifica el evento como el mensaje Evento de eventos.proto. Las
+// notificaciones usan su mensaje propio; el resto de los datos viaja en JSON dentro del frame.
+func codificarEventoProtobuf(evento Evento) ([]byte, error) {
+	var b []byte
+	b = agregarTexto(b, campoEventoTipo, evento.Tipo)
+
+	if notificacion, ok := evento.Datos.(*entidad.Notificacion); ok {
+		mensaje, err := codificarNotificacionProtobuf(notificacion)
+		if err != nil {
+			return nil, err
+		}
+		b = protowire.AppendTag(b, campoEventoNotificacion, protowire.BytesType)
+		return protowire.AppendBytes(b, mensaje), nil
+	}
+
+	datos, err := json.Marshal(evento.Datos)
+	if err != nil {
+		return nil, err
+	}
+	b = protowire.AppendTag(b, campoEventoJSON, protowire.BytesType)
+	return protowire.AppendBytes(b, datos), nil
+}
+
+func codificarNotificacionProtobuf(n *entidad.Notificacion) ([]byte, error) {
+	var b []byte
+	b = agregarEntero(b, campoNotificacionID, uint64(n.ID))
+	b = agregarEntero(b, campoNotificacionUsuarioID, uint64(n.UsuarioID))
+	b = agregarTexto(b, campoNotificacionTitulo, n.Titulo)
+	b = agregarTexto(b, campoNotificacionMensaje, n.Mensaje)
+	b = agregarTexto(b, campoNotificacionTipo, string(n.Tipo))
+	b = agregarTexto(b, campoNotificacionEstado, string(n.Estado))
+	b = agregarTexto(b, campoNotificacionPrioridad, string(n.Prioridad))
+	b = agregarEntero(b, campoNotificacionCanalID, uint64(n.CanalID))
+	if len(n.Metadatos) > 0 {
+		metadatos, err := json.Marshal(n.Metadatos)
+		if err != nil {
+			return nil, err
+		}
+		b = protowire.AppendTag(b, campoNotificacionMetadatos, protowire.BytesType)
+		b = protowire.AppendBytes(b, metadatos)
+	}
+	b = agregarFecha(b, campoNotificacionFechaCreacion, n.FechaCreacion)
+	if n.FechaEnviada != nil {
+		b = agregarFecha(b, campoNotificacionFechaEnviada, *n.FechaEnviada)
+	}
+	b = agregarEntero(b, campoNotificacionInquilinoID, uint64(n.InquilinoID))
+	b = agregarEntero(b, campoNotificacionVersion, uint64(n.Version))
+	return b, nil
+}
+
+// agregarTexto omite el valor vacío, como proto3 con los valores por defecto
+func agregarTexto(b []byte, campo protowire.Number, valor string) []byte {
+	if valor == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, campo, protowire.BytesType)
+	return protowire.AppendString(b, valor)
+}
+
+// agregarEntero omite el cero, como proto3 con los valores por defecto
+func agregarEntero(b []byte, campo protowire.Number, valor uint64) []byte {
+	if valor == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, campo, protowire.VarintType)
+	return protowire.AppendVarint(b, valor)
+}
+
+// agregarFecha codifica la fecha como google.protobuf.Timestamp; la fecha cero se omite
+func agregarFecha(b []byte, campo protowire.Number, fecha time.Time) []byte {
+	if fecha.IsZero() {
+		return b
+	}
+	var timestamp []byte
+	if segundos := fecha.Unix(); segundos != 0 {
+		timestamp = protowire.AppendTag(timestamp, campoTimestampSegundos, protowire.VarintType)
+		timestamp = protowire.AppendVarint(timestamp, uint64(segundos))
+	}
+	if nanos := fecha.Nanosecond(); nanos != 0 {
+		timestamp = protowire.AppendTag(timestamp, campoTimestampNanos, protowire.VarintType)
+		timestamp = protowire.AppendVarint(timestamp, uint64(nanos))
+	}
+	b = protowire.AppendTag(b, campo, protowire.BytesType)
+	return protowire.AppendBytes(b, timestamp)
+}
@@ -0,0 +1,207 @@
+package websocket
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	patronPaquete = regexp.MustCompile(`^package\s+([\w.]+);`)
+	patronMensaje = regexp.MustCompile(`^message\s+(\w+)\s*\{`)
+	patronOneof   = regexp.MustCompile(`^oneof\s+(\w+)\s*\{`)
+	patronCampo   = regexp.MustCompile(`^([\w.]+)\s+(\w+)\s*=\s*(\d+);`)
+)
+
+var tiposEscalares = map[string]descriptorpb.FieldDescriptorProto_Type{
+	"string": descriptorpb.FieldDescriptorProto_TYPE_STRING,
+	"bytes":  descriptorpb.FieldDescriptorProto_TYPE_BYTES,
+	"uint64": descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+}
+
+// descriptorEventos arma el descriptor de eventos.proto leyendo el archivo, así la prueba
+// decodifica con los números de campo publicados a los clientes y no con una copia. Solo
+// entiende lo que usa el archivo: mensajes planos, un oneof y tipos escalares o de mensaje.
+func descriptorEventos(t *testing.T) protoreflect.FileDescriptor {
+	t.Helper()
+	archivo, err := os.Open("eventos.proto")
+	if err != nil {
+		t.Fatalf("abriendo eventos.proto: %v", err)
+	}
+	defer archivo.Close()
+
+	descriptor := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("eventos.proto"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/timestamp.proto"},
+	}
+	var mensaje *descriptorpb.DescriptorProto
+	var oneof *int32
+	lector := bufio.NewScanner(archivo)
+	for lector.Scan() {
+		linea, _, _ := strings.Cut(lector.Text(), "//")
+		linea = strings.TrimSpace(linea)
+		switch {
+		case patronPaquete.MatchString(linea):
+			descriptor.Package = proto.String(patronPaquete.FindStringSubmatch(linea)[1])
+		case patronMensaje.MatchString(linea):
+			mensaje = &descriptorpb.DescriptorProto{Name: proto.String(patronMensaje.FindStringSubmatch(linea)[1])}
+			descriptor.MessageType = append(descriptor.MessageType, mensaje)
+		case patronOneof.MatchString(linea):
+			mensaje.OneofDecl = append(mensaje.OneofDecl, &descriptorpb.OneofDescriptorProto{Name: proto.String(patronOneof.FindStringSubmatch(linea)[1])})
+			oneof = proto.Int32(int32(len(mensaje.OneofDecl) - 1))
+		case linea == "}" && oneof != nil:
+			oneof = nil
+		case patronCampo.MatchString(linea) && mensaje != nil:
+			partes := patronCampo.FindStringSubmatch(linea)
+			numero, _ := strconv.Atoi(partes[3])
+			campo := &descriptorpb.FieldDescriptorProto{
+				Name:       proto.String(partes[2]),
+				Number:     proto.Int32(int32(numero)),
+				Label:      descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				OneofIndex: oneof,
+			}
+			if escalar, ok := tiposEscalares[partes[1]]; ok {
+				campo.Type = escalar.Enum()
+			} else {
+				campo.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+				nombre := partes[1]
+				if !strings.Contains(nombre, ".") {
+					nombre = descriptor.GetPackage() + "." + nombre
+				}
+				campo.TypeName = proto.String("." + nombre)
+			}
+			mensaje.Field = append(mensaje.Field, campo)
+		}
+	}
+	if err := lector.Err(); err != nil {
+		t.Fatalf("leyendo eventos.proto: %v", err)
+	}
+
+	compilado, err := protodesc.NewFile(descriptor, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("construyendo el descriptor de eventos.proto: %v", err)
+	}
+	return compilado
+}
+
+// decodificarEvento decodifica el frame con el mensaje Evento y falla si quedan campos que
+// eventos.proto no declara
+func decodificarEvento(t *testing.T, frame []byte) *dynamicpb.Message {
+	t.Helper()
+	evento := dynamicpb.NewMessage(descriptorEventos(t).Messages().ByName("Evento"))
+	if err := proto.Unmarshal(frame, evento); err != nil {
+		t.Fatalf("decodificando el frame: %v", err)
+	}
+	verificarSinCamposDesconocidos(t, evento)
+	return evento
+}
+
+func verificarSinCamposDesconocidos(t *testing.T, mensaje protoreflect.Message) {
+	t.Helper()
+	if len(mensaje.GetUnknown()) > 0 {
+		t.Errorf("%s tiene campos que eventos.proto no declara", mensaje.Descriptor().FullName())
+	}
+	mensaje.Range(func(campo protoreflect.FieldDescriptor, valor protoreflect.Value) bool {
+		if campo.Kind() == protoreflect.MessageKind {
+			verificarSinCamposDesconocidos(t, valor.Message())
+		}
+		return true
+	})
+}
+
+func campo(mensaje protoreflect.Message, nombre string) protoreflect.Value {
+	return mensaje.Get(mensaje.Descriptor().Fields().ByName(protoreflect.Name(nombre)))
+}
+
+func fechaDe(t *testing.T, mensaje protoreflect.Message, nombre string) time.Time {
+	t.Helper()
+	timestamp := campo(mensaje, nombre).Message()
+	return time.Unix(campo(timestamp, "seconds").Int(), campo(timestamp, "nanos").Int()).UTC()
+}
+
+func TestNotificacionSeDecodificaConEventosProto(t *testing.T) {
+	creada := time.Date(2024, time.March, 5, 10, 30, 0, 123456789, time.UTC)
+	enviada := creada.Add(2 * time.Second)
+	notificacion := &entidad.Notificacion{
+		ID:            41,
+		UsuarioID:     7,
+		Titulo:        "Pedido enviado",
+		Mensaje:       "Su pedido salió del depósito",
+		Tipo:          entidad.TipoPush,
+		Estado:        entidad.EstadoEnviada,
+		Prioridad:     entidad.PrioridadAlta,
+		CanalID:       3,
+		Metadatos:     map[string]interface{}{"pedido": "A-1"},
+		FechaCreacion: creada,
+		FechaEnviada:  &enviada,
+		InquilinoID:   9,
+		Version:       4,
+	}
+	frame, err := codificarEventoProtobuf(Evento{Tipo: "notificacion", Datos: notificacion})
+	if err != nil {
+		t.Fatalf("codificando: %v", err)
+	}
+
+	evento := decodificarEvento(t, frame)
+	if tipo := campo(evento, "tipo").String(); tipo != "notificacion" {
+		t.Errorf("tipo = %q", tipo)
+	}
+	decodificada := campo(evento, "notificacion").Message()
+	enteros := map[string]uint64{"id": 41, "usuario_id": 7, "canal_id": 3, "inquilino_id": 9, "version": 4}
+	for nombre, esperado := range enteros {
+		if obtenido := campo(decodificada, nombre).Uint(); obtenido != esperado {
+			t.Errorf("%s = %d, se esperaba %d", nombre, obtenido, esperado)
+		}
+	}
+	textos := map[string]string{
+		"titulo": notificacion.Titulo, "mensaje": notificacion.Mensaje, "tipo": string(notificacion.Tipo),
+		"estado": string(notificacion.Estado), "prioridad": string(notificacion.Prioridad),
+	}
+	for nombre, esperado := range textos {
+		if obtenido := campo(decodificada, nombre).String(); obtenido != esperado {
+			t.Errorf("%s = %q, se esperaba %q", nombre, obtenido, esperado)
+		}
+	}
+	var metadatos map[string]interface{}
+	if err := json.Unmarshal(campo(decodificada, "metadatos").Bytes(), &metadatos); err != nil || metadatos["pedido"] != "A-1" {
+		t.Errorf("metadatos = %v (%v)", metadatos, err)
+	}
+	if fecha := fechaDe(t, decodificada, "fecha_creacion"); !fecha.Equal(creada) {
+		t.Errorf("fecha_creacion = %v, se esperaba %v", fecha, creada)
+	}
+	if fecha := fechaDe(t, decodificada, "fecha_enviada"); !fecha.Equal(enviada) {
+		t.Errorf("fecha_enviada = %v, se esperaba %v", fecha, enviada)
+	}
+}
+
+func TestOtrosEventosViajanEnJSON(t *testing.T) {
+	frame, err := codificarEventoProtobuf(Evento{Tipo: "no_leidas", Datos: map[string]int{"total": 3}})
+	if err != nil {
+		t.Fatalf("codificando: %v", err)
+	}
+
+	evento := decodificarEvento(t, frame)
+	if evento.Has(evento.Descriptor().Fields().ByName("notificacion")) {
+		t.Error("un evento sin notificación no debería llevar el mensaje Notificacion")
+	}
+	var datos map[string]int
+	if err := json.Unmarshal(campo(evento, "json").Bytes(), &datos); err != nil || datos["total"] != 3 {
+		t.Errorf("json = %s (%v)", campo(evento, "json").Bytes(), err)
+	}
+}
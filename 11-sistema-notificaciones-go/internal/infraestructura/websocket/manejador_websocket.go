@@ -0,0 +1,191 @@
+package websocket
+
+import (
+	"errors"
+	"sync"
+
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+	"sistema-notificaciones-go/pkg/logger"
+
+	gorilla "github.com/gorilla/websocket"
+)
+
+// ErrUsuarioSinConexion indica que el usuario no tiene conexiones WebSocket activas
+var ErrUsuarioSinConexion = errors.New("usuario sin conexiones WebSocket activas")
+
+// Evento es el mensaje que se envía a los clientes
+type Evento struct {
+	Tipo  string `json:"tipo"`
+	Datos any    `json:"datos"`
+}
+
+// destinatario identifica a un usuario dentro de su inquilino: los IDs de usuario se repiten
+// entre inquilinos con esquema propio, así que el ID solo no basta
+type destinatario struct {
+	inquilinoID uint
+	usuarioID   uint
+}
+
+// fragmento agrupa las conexiones de un subconjunto de usuarios bajo su propio lock
+type fragmento struct {
+	mu         sync.RWMutex
+	conexiones map[destinatario]map[*Conexion]struct{}
+}
+
+// Hub registra las conexiones por inquilino y usuario, fragmentadas por ID de usuario para
+// evitar un lock global
+type Hub struct {
+	fragmentos  []*fragmento
+	bufferEnvio int
+	logger      *logger.Logger
+	// descartarTrama, si está definida, decide qué tramas se pierden antes de escribirse
+	descartarTrama func() bool
+}
+
+// NuevoHub crea un hub con la cantidad de fragmentos configurada
+func NuevoHub(config configuracion.ConfiguracionWebSocket, log *logger.Logger) *Hub {
+	cantidad := config.Fragmentos
+	if cantidad <= 0 {
+		cantidad = 1
+	}
+
+	hub := &Hub{
+		fragmentos:  make([]*fragmento, cantidad),
+		bufferEnvio: config.BufferEnvio,
+		logger:      log.Componente(logger.ComponenteWebSocket),
+	}
+	for i := range hub.fragmentos {
+		hub.fragmentos[i] = &fragmento{conexiones: make(map[destinatario]map[*Conexion]struct{})}
+	}
+	return hub
+}
+
+// InyectarDescartes hace que las conexiones pierdan las tramas para las que descartar retorne
+// true, simulando una red inestable. Debe llamarse antes de registrar conexiones.
+func (h *Hub) InyectarDescartes(descartar func() bool) {
+	h.descartarTrama = descartar
+}
+
+func (h *Hub) fragmento(destino destinatario) *fragmento {
+	return h.fragmentos[(destino.usuarioID+destino.inquilinoID)%uint(len(h.fragmentos))]
+}
+
+// Registrar agrega la conexión del usuario del inquilino (0 para la plataforma) e inicia sus
+// goroutines de lectura y escritura
+func (h *Hub) Registrar(inquilinoID, usuarioID uint, conn *gorilla.Conn) *Conexion {
+	destino := destinatario{inquilinoID: inquilinoID, usuarioID: usuarioID}
+	conexion := &Conexion{
+		destino: destino,
+		conn:    conn,
+		formato: formatoDeSubprotocolo(conn.Subprotocol()),
+		envio:   make(chan *gorilla.PreparedMessage, h.bufferEnvio),
+		hub:     h,
+		logger:  h.logger,
+	}
+
+	f := h.fragmento(destino)
+	f.mu.Lock()
+	if f.conexiones[destino] == nil {
+		f.conexiones[destino] = make(map[*Conexion]struct{})
+	}
+	f.conexiones[destino][conexion] = struct{}{}
+	f.mu.Unlock()
+
+	metricaConexiones.Inc()
+	h.logger.Debug("Conexión WebSocket registrada", "inquilino_id", inquilinoID, "usuario_id", usuarioID)
+
+	go conexion.escribir()
+	go conexion.leer()
+
+	return conexion
+}
+
+// Desregistrar quita la conexión y cierra su canal de envío
+func (h *Hub) Desregistrar(conexion *Conexion) {
+	f := h.fragmento(conexion.destino)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	conexiones, existe := f.conexiones[conexion.destino]
+	if !existe {
+		return
+	}
+	if _, registrada := conexiones[conexion]; !registrada {
+		return
+	}
+
+	delete(conexiones, conexion)
+	if len(conexiones) == 0 {
+		delete(f.conexiones, conexion.destino)
+	}
+	close(conexion.envio)
+	metricaConexiones.Dec()
+}
+
+// EnviarAUsuario envía el evento a todas las conexiones del usuario del inquilino
+func (h *Hub) EnviarAUsuario(inquilinoID, usuarioID uint, evento Evento) error {
+	frames := nuevosFramesEvento(evento)
+
+	destino := destinatario{inquilinoID: inquilinoID, usuarioID: usuarioID}
+	f := h.fragmento(destino)
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	conexiones := f.conexiones[destino]
+	if len(conexiones) == 0 {
+		return ErrUsuarioSinConexion
+	}
+	for conexion := range conexiones {
+		frame, err := frames.frame(conexion.formato)
+		if err != nil {
+			return err
+		}
+		conexion.encolar(frame)
+	}
+	return nil
+}
+
+// Difundir envía el mismo evento a varios usuarios del inquilino serializándolo una sola vez
+// por formato. Los usuarios se agrupan por fragmento para tomar cada lock una única vez.
+// Retorna la cantidad de usuarios con al menos una conexión.
+func (h *Hub) Difundir(inquilinoID uint, usuarioIDs []uint, evento Evento) (int, error) {
+	frames := nuevosFramesEvento(evento)
+
+	porFragmento := make(map[*fragmento][]destinatario)
+	for _, usuarioID := range usuarioIDs {
+		destino := destinatario{inquilinoID: inquilinoID, usuarioID: usuarioID}
+		f := h.fragmento(destino)
+		porFragmento[f] = append(porFragmento[f], destino)
+	}
+
+	entregados := 0
+	for f, destinos := range porFragmento {
+		f.mu.RLock()
+		for _, destino := range destinos {
+			conexiones := f.conexiones[destino]
+			if len(conexiones) > 0 {
+				entregados++
+			}
+			for conexion := range conexiones {
+				frame, err := frames.frame(conexion.formato)
+				if err != nil {
+					f.mu.RUnlock()
+					return entregados, err
+				}
+				conexion.encolar(frame)
+			}
+		}
+		f.mu.RUnlock()
+	}
+
+	return entregados, nil
+}
+
+// EstaConectado indica si el usuario del inquilino tiene al menos una conexión activa
+func (h *Hub) EstaConectado(inquilinoID, usuarioID uint) bool {
+	destino := destinatario{inquilinoID: inquilinoID, usuarioID: usuarioID}
+	f := h.fragmento(destino)
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return len(f.conexiones[destino]) > 0
+}
@@ -0,0 +1,23 @@
+package websocket
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricaConexiones = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "notificaciones_ws_conexiones",
+		Help: "Conexiones WebSocket activas",
+	})
+
+	metricaFramesEnviados = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "notificaciones_ws_frames_enviados_total",
+		Help: "Frames encolados para envío a clientes WebSocket",
+	})
+
+	metricaFramesDescartados = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "notificaciones_ws_frames_descartados_total",
+		Help: "Frames descartados (el más antiguo) por clientes lentos con el buffer lleno",
+	})
+)
@@ -0,0 +1,28 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+
+	"sistema-notificaciones-go/internal/dominio/servicio"
+)
+
+// PublicadorEventos publica en el hub los eventos que no son notificaciones, p. ej. las reacciones
+type PublicadorEventos struct {
+	hub *Hub
+}
+
+// NuevoPublicadorEventos crea una nueva instancia de PublicadorEventos
+func NuevoPublicadorEventos(hub *Hub) *PublicadorEventos {
+	return &PublicadorEventos{hub: hub}
+}
+
+// Publicar envía el evento a las conexiones del usuario en el inquilino de la petición; sin
+// conexiones no hay a quién avisar
+func (p *PublicadorEventos) Publicar(ctx context.Context, usuarioID uint, tipo string, datos any) error {
+	err := p.hub.EnviarAUsuario(servicio.InquilinoDesdeContexto(ctx), usuarioID, Evento{Tipo: tipo, Datos: datos})
+	if errors.Is(err, ErrUsuarioSinConexion) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,131 @@
+package controlador
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/presentacion/flujo"
+	"sistema-notificaciones-go/internal/presentacion/problema"
+	"sistema-notificaciones-go/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorAcceso expone el registro de accesos del personal a notificaciones de usuarios
+type ControladorAcceso struct {
+	casoUso *casoUso.CasoUsoRegistrarAccesoPersonal
+	logger  *logger.Logger
+}
+
+// NuevoControladorAcceso crea una nueva instancia de ControladorAcceso
+func NuevoControladorAcceso(casoUsoAcceso *casoUso.CasoUsoRegistrarAccesoPersonal, log *logger.Logger) *ControladorAcceso {
+	return &ControladorAcceso{casoUso: casoUsoAcceso, logger: log}
+}
+
+// ListarAccesos retorna una página de accesos filtrada por inquilino_id, actor_id, usuario_id
+// y el rango [desde, hasta) en RFC 3339
+func (c *ControladorAcceso) ListarAccesos(ctx *gin.Context) {
+	filtro := repositorio.FiltroAccesos{Limite: limitePaginaPredeterminado}
+	if limite, err := strconv.Atoi(ctx.Query("limite")); err == nil && limite > 0 {
+		filtro.Limite = min(limite, limitePaginaMaximo)
+	}
+
+	numericos := map[string]*uint{
+		"inquilino_id": &filtro.InquilinoID,
+		"actor_id":     &filtro.ActorID,
+		"usuario_id":   &filtro.UsuarioID,
+		"cursor":       &filtro.Cursor,
+	}
+	for parametro, destino := range numericos {
+		if valor := ctx.Query(parametro); valor != "" {
+			numero, err := strconv.ParseUint(valor, 10, 64)
+			if err != nil {
+				problema.Responder(ctx, http.StatusBadRequest, "parametro_invalido", parametro+" inválido")
+				return
+			}
+			*destino = uint(numero)
+		}
+	}
+	fechas := map[string]*time.Time{"desde": &filtro.Desde, "hasta": &filtro.Hasta}
+	for parametro, destino := range fechas {
+		if valor := ctx.Query(parametro); valor != "" {
+			fecha, err := time.Parse(time.RFC3339, valor)
+			if err != nil {
+				problema.Responder(ctx, http.StatusBadRequest, "parametro_invalido", parametro+" inválido, formato RFC 3339")
+				return
+			}
+			*destino = fecha
+		}
+	}
+
+	accesos, err := c.casoUso.Listar(ctx.Request.Context(), filtro)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+
+	respuesta := gin.H{"accesos": accesos}
+	if len(accesos) == filtro.Limite {
+		respuesta["siguiente_cursor"] = accesos[len(accesos)-1].ID
+	}
+	ctx.JSON(http.StatusOK, respuesta)
+}
+
+// ExportarAccesos descarga el tramo del registro ubicado por el rango [desde, hasta) en RFC 3339
+// y por desde_id y hasta_id, con la verificación de su cadena de hashes al final. La verificación
+// se calcula sobre lo mismo que se entrega, así un auditor puede recalcularla sobre el archivo;
+// la siguiente exportación continúa desde ultimo_id + 1.
+func (c *ControladorAcceso) ExportarAccesos(ctx *gin.Context) {
+	var filtro repositorio.FiltroAccesos
+	numericos := map[string]*uint{"desde_id": &filtro.DesdeID, "hasta_id": &filtro.HastaID}
+	for parametro, destino := range numericos {
+		if valor := ctx.Query(parametro); valor != "" {
+			numero, err := strconv.ParseUint(valor, 10, 64)
+			if err != nil {
+				problema.Responder(ctx, http.StatusBadRequest, "parametro_invalido", parametro+" inválido")
+				return
+			}
+			*destino = uint(numero)
+		}
+	}
+	fechas := map[string]*time.Time{"desde": &filtro.Desde, "hasta": &filtro.Hasta}
+	for parametro, destino := range fechas {
+		if valor := ctx.Query(parametro); valor != "" {
+			fecha, err := time.Parse(time.RFC3339, valor)
+			if err != nil {
+				problema.Responder(ctx, http.StatusBadRequest, "parametro_invalido", parametro+" inválido, formato RFC 3339")
+				return
+			}
+			*destino = fecha
+		}
+	}
+
+	escritor, err := flujo.NuevoEscritorJSON(ctx, "accesos")
+	if err != nil {
+		c.logger.Error("Error iniciando exportación de accesos", "error", err)
+		return
+	}
+
+	verificacion, err := c.casoUso.Exportar(ctx.Request.Context(), filtro, func(acceso *entidad.AccesoNotificacion) error {
+		return escritor.Escribir(acceso)
+	})
+	if err != nil {
+		c.logger.Error("Error exportando accesos", "error", err)
+		ctx.Abort()
+		return
+	}
+	if !verificacion.Valida {
+		c.logger.Error("Cadena del registro de accesos alterada",
+			"falla_id", verificacion.FallaID,
+			"falla", verificacion.Falla,
+		)
+	}
+
+	if err := escritor.Cerrar(map[string]any{"verificacion": verificacion}); err != nil {
+		c.logger.Error("Error cerrando exportación de accesos", "error", err)
+	}
+}
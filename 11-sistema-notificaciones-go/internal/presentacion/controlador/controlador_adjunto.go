@@ -0,0 +1,102 @@
+package controlador
+
+import (
+	"io"
+	"net/http"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/presentacion/problema"
+
+	"github.com/gin-gonic/gin"
+)
+
+// margenFormulario es lo que ocupan los encabezados multipart además del archivo
+const margenFormulario = 64 << 10
+
+// ControladorAdjunto sube los archivos adjuntos de las notificaciones y entrega sus URL de descarga
+type ControladorAdjunto struct {
+	casoUso  *casoUso.CasoUsoAdjuntos
+	maxBytes int64
+}
+
+// NuevoControladorAdjunto crea el controlador con el tamaño máximo de cada archivo
+func NuevoControladorAdjunto(casoUsoAdjuntos *casoUso.CasoUsoAdjuntos, maxBytes int64) *ControladorAdjunto {
+	return &ControladorAdjunto{casoUso: casoUsoAdjuntos, maxBytes: maxBytes}
+}
+
+// Subir recibe un formulario multipart con el archivo en el campo archivo. El tipo se detecta
+// por el contenido; el que declara el cliente no se usa.
+func (c *ControladorAdjunto) Subir(ctx *gin.Context) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, c.maxBytes+margenFormulario)
+	encabezado, err := ctx.FormFile("archivo")
+	if err != nil {
+		problema.Responder(ctx, http.StatusBadRequest, "solicitud_invalida", "Solicitud inválida")
+		return
+	}
+	archivo, err := encabezado.Open()
+	if err != nil {
+		problema.Responder(ctx, http.StatusBadRequest, "cuerpo_ilegible", "no se pudo leer el cuerpo")
+		return
+	}
+	defer archivo.Close()
+
+	cabecera := make([]byte, 512)
+	n, err := io.ReadFull(archivo, cabecera)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		problema.Responder(ctx, http.StatusBadRequest, "cuerpo_ilegible", "no se pudo leer el cuerpo")
+		return
+	}
+	adjunto, err := c.casoUso.Subir(ctx.Request.Context(), dto.ArchivoSubido{
+		Nombre:        encabezado.Filename,
+		TipoContenido: http.DetectContentType(cabecera[:n]),
+		Tamano:        encabezado.Size,
+		Contenido:     archivo,
+	})
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, adjunto)
+}
+
+// Obtener retorna el adjunto con su URL de descarga firmada
+func (c *ControladorAdjunto) Obtener(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	adjunto, err := c.casoUso.Obtener(ctx.Request.Context(), id)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, adjunto)
+}
+
+// Eliminar borra un adjunto que todavía no pertenece a ninguna notificación
+func (c *ControladorAdjunto) Eliminar(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	if err := c.casoUso.Eliminar(ctx.Request.Context(), id); err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListarDeNotificacion retorna los adjuntos de la notificación con sus URL de descarga
+func (c *ControladorAdjunto) ListarDeNotificacion(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	adjuntos, err := c.casoUso.Listar(ctx.Request.Context(), id)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"adjuntos": adjuntos})
+}
@@ -0,0 +1,157 @@
+package controlador
+
+import (
+	"net/http"
+	"strconv"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/internal/presentacion/problema"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorBandeja maneja la bandeja in-app de los usuarios
+type ControladorBandeja struct {
+	casoUso *casoUso.CasoUsoBandeja
+}
+
+// NuevoControladorBandeja crea una nueva instancia de ControladorBandeja
+func NuevoControladorBandeja(casoUsoBandeja *casoUso.CasoUsoBandeja) *ControladorBandeja {
+	return &ControladorBandeja{casoUso: casoUsoBandeja}
+}
+
+// ListarBandeja retorna una página de la carpeta pedida (recibidas por defecto)
+func (c *ControladorBandeja) ListarBandeja(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	if respuesta, ok := c.pagina(ctx, id); ok {
+		ctx.JSON(http.StatusOK, respuesta)
+	}
+}
+
+// ObtenerInbox retorna una página de la bandeja junto con las insignias: las no leídas de
+// recibidas y los conteos de cada carpeta, así el cliente arma la vista con una solicitud. El
+// usuario es el de usuario_id o, sin él, el actor de X-Actor-ID.
+func (c *ControladorBandeja) ObtenerInbox(ctx *gin.Context) {
+	var usuarioID uint
+	if valor := ctx.Query("usuario_id"); valor != "" {
+		id, err := strconv.ParseUint(valor, 10, 64)
+		if err != nil || id == 0 {
+			problema.Responder(ctx, http.StatusBadRequest, "parametro_invalido", "usuario_id inválido")
+			return
+		}
+		usuarioID = uint(id)
+	} else if actor, ok := servicio.ActorDesdeContexto(ctx.Request.Context()); ok {
+		usuarioID = actor.ID
+	} else {
+		problema.Responder(ctx, http.StatusBadRequest, "parametro_requerido", "usuario_id o X-Actor-ID es requerido")
+		return
+	}
+
+	respuesta, ok := c.pagina(ctx, usuarioID)
+	if !ok {
+		return
+	}
+	conteos, err := c.casoUso.Contar(ctx.Request.Context(), usuarioID)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	respuesta["insignia"] = conteos[entidad.CarpetaRecibidas].NoLeidas
+	respuesta["carpetas"] = conteos
+	ctx.JSON(http.StatusOK, respuesta)
+}
+
+// pagina lista la carpeta de la consulta (recibidas por defecto) con su cursor y límite; si
+// falla ya respondió el error
+func (c *ControladorBandeja) pagina(ctx *gin.Context, usuarioID uint) (gin.H, bool) {
+	carpeta := entidad.CarpetaBandeja(ctx.DefaultQuery("carpeta", string(entidad.CarpetaRecibidas)))
+	var cursor uint64
+	if valor := ctx.Query("cursor"); valor != "" {
+		var err error
+		if cursor, err = strconv.ParseUint(valor, 10, 64); err != nil {
+			problema.Responder(ctx, http.StatusBadRequest, "parametro_invalido", "cursor inválido")
+			return nil, false
+		}
+	}
+	limite := limitePaginaPredeterminado
+	if valor, err := strconv.Atoi(ctx.Query("limite")); err == nil && valor > 0 {
+		limite = min(valor, limitePaginaMaximo)
+	}
+
+	notificaciones, err := c.casoUso.Listar(ctx.Request.Context(), usuarioID, carpeta, uint(cursor), limite)
+	if err != nil {
+		responderError(ctx, err)
+		return nil, false
+	}
+
+	respuesta := gin.H{"carpeta": carpeta, "notificaciones": notificaciones, "total": len(notificaciones)}
+	if len(notificaciones) == limite {
+		respuesta["siguiente_cursor"] = notificaciones[len(notificaciones)-1].ID
+	}
+	return respuesta, true
+}
+
+// ContarBandeja retorna el total y las no leídas de cada carpeta
+func (c *ControladorBandeja) ContarBandeja(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	conteos, err := c.casoUso.Contar(ctx.Request.Context(), id)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"carpetas": conteos})
+}
+
+// MoverNotificacion cambia la notificación de carpeta
+func (c *ControladorBandeja) MoverNotificacion(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var solicitud dto.SolicitudMoverBandeja
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	notificacion, err := c.casoUso.Mover(ctx.Request.Context(), id, solicitud.Carpeta)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, notificacion)
+}
+
+// DestacarNotificacion marca la notificación como destacada
+func (c *ControladorBandeja) DestacarNotificacion(ctx *gin.Context) {
+	c.destacar(ctx, true)
+}
+
+// QuitarDestacada desmarca la notificación como destacada
+func (c *ControladorBandeja) QuitarDestacada(ctx *gin.Context) {
+	c.destacar(ctx, false)
+}
+
+func (c *ControladorBandeja) destacar(ctx *gin.Context, destacada bool) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	notificacion, err := c.casoUso.Destacar(ctx.Request.Context(), id, destacada)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, notificacion)
+}
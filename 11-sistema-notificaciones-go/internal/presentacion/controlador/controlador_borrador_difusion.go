@@ -0,0 +1,173 @@
+package controlador
+
+import (
+	"net/http"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorBorradorDifusion expone el flujo de aprobación de las difusiones de un canal
+type ControladorBorradorDifusion struct {
+	casoUso *casoUso.CasoUsoAprobacionDifusion
+}
+
+// NuevoControladorBorradorDifusion crea una nueva instancia de ControladorBorradorDifusion
+func NuevoControladorBorradorDifusion(casoUsoAprobacion *casoUso.CasoUsoAprobacionDifusion) *ControladorBorradorDifusion {
+	return &ControladorBorradorDifusion{casoUso: casoUsoAprobacion}
+}
+
+// ConsultaBorradores son los parámetros del listado de borradores
+type ConsultaBorradores struct {
+	Estado entidad.EstadoBorradorDifusion `form:"estado" binding:"omitempty,oneof=borrador pendiente_aprobacion aprobada enviada pendiente_moderacion rechazada"`
+}
+
+// Crear guarda una difusión como borrador del canal
+func (c *ControladorBorradorDifusion) Crear(ctx *gin.Context) {
+	canalID, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	var solicitud dto.SolicitudDifusion
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	borrador, err := c.casoUso.Crear(ctx.Request.Context(), canalID, solicitud)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, borrador)
+}
+
+// Listar retorna los borradores del canal (?estado=pendiente_aprobacion)
+func (c *ControladorBorradorDifusion) Listar(ctx *gin.Context) {
+	canalID, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	var consulta ConsultaBorradores
+	if !vincularConsulta(ctx, &consulta) {
+		return
+	}
+
+	borradores, err := c.casoUso.Listar(ctx.Request.Context(), canalID, consulta.Estado)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"borradores": borradores})
+}
+
+// Obtener retorna un borrador del canal
+func (c *ControladorBorradorDifusion) Obtener(ctx *gin.Context) {
+	canalID, id, ok := parametrosBorrador(ctx)
+	if !ok {
+		return
+	}
+
+	borrador, err := c.casoUso.Obtener(ctx.Request.Context(), canalID, id)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, borrador)
+}
+
+// Editar reemplaza el contenido de un borrador que no está en aprobación
+func (c *ControladorBorradorDifusion) Editar(ctx *gin.Context) {
+	canalID, id, ok := parametrosBorrador(ctx)
+	if !ok {
+		return
+	}
+	var solicitud dto.SolicitudDifusion
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	borrador, err := c.casoUso.Editar(ctx.Request.Context(), canalID, id, solicitud)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, borrador)
+}
+
+// SolicitarAprobacion envía el borrador a aprobación en nombre de X-Actor-ID
+func (c *ControladorBorradorDifusion) SolicitarAprobacion(ctx *gin.Context) {
+	canalID, id, ok := parametrosBorrador(ctx)
+	if !ok {
+		return
+	}
+
+	borrador, err := c.casoUso.SolicitarAprobacion(ctx.Request.Context(), canalID, id)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, borrador)
+}
+
+// Aprobar aprueba el borrador en nombre de X-Actor-ID y responde 202 con la difusión iniciada
+func (c *ControladorBorradorDifusion) Aprobar(ctx *gin.Context) {
+	canalID, id, ok := parametrosBorrador(ctx)
+	if !ok {
+		return
+	}
+
+	borrador, progreso, err := c.casoUso.Aprobar(ctx.Request.Context(), canalID, id)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, gin.H{"borrador": borrador, "difusion": progreso})
+}
+
+// Rechazar devuelve el borrador a edición en nombre de X-Actor-ID
+func (c *ControladorBorradorDifusion) Rechazar(ctx *gin.Context) {
+	canalID, id, ok := parametrosBorrador(ctx)
+	if !ok {
+		return
+	}
+	var solicitud dto.SolicitudRechazarDifusion
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	borrador, err := c.casoUso.Rechazar(ctx.Request.Context(), canalID, id, solicitud.Motivo)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, borrador)
+}
+
+// Difundir envía un borrador aprobado, o uno en borrador si el canal no requiere aprobación
+func (c *ControladorBorradorDifusion) Difundir(ctx *gin.Context) {
+	canalID, id, ok := parametrosBorrador(ctx)
+	if !ok {
+		return
+	}
+
+	borrador, progreso, err := c.casoUso.Difundir(ctx.Request.Context(), canalID, id)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, gin.H{"borrador": borrador, "difusion": progreso})
+}
+
+// parametrosBorrador lee el canal y el borrador de la ruta
+func parametrosBorrador(ctx *gin.Context) (canalID, id uint, ok bool) {
+	if canalID, ok = parametroID(ctx, "id"); !ok {
+		return 0, 0, false
+	}
+	if id, ok = parametroID(ctx, "borradorId"); !ok {
+		return 0, 0, false
+	}
+	return canalID, id, true
+}
@@ -0,0 +1,69 @@
+package controlador
+
+import (
+	"net/http"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorCanal maneja la configuración de los canales
+type ControladorCanal struct {
+	esquemas *casoUso.CasoUsoEsquemaMetadatos
+}
+
+// NuevoControladorCanal crea una nueva instancia de ControladorCanal
+func NuevoControladorCanal(esquemas *casoUso.CasoUsoEsquemaMetadatos) *ControladorCanal {
+	return &ControladorCanal{esquemas: esquemas}
+}
+
+// ObtenerEsquemaMetadatos retorna el JSON Schema de los metadatos del canal
+func (c *ControladorCanal) ObtenerEsquemaMetadatos(ctx *gin.Context) {
+	canalID, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	esquema, err := c.esquemas.Obtener(ctx.Request.Context(), canalID)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"canal_id": canalID, "esquema": esquema})
+}
+
+// GuardarEsquemaMetadatos declara el JSON Schema que deben cumplir los metadatos de las
+// notificaciones enviadas al canal
+func (c *ControladorCanal) GuardarEsquemaMetadatos(ctx *gin.Context) {
+	canalID, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	var solicitud dto.SolicitudEsquemaMetadatos
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	canal, err := c.esquemas.Guardar(ctx.Request.Context(), canalID, solicitud.Esquema)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"canal_id": canal.ID, "esquema": canal.EsquemaMetadatos})
+}
+
+// EliminarEsquemaMetadatos quita el esquema; los metadatos del canal vuelven a ser libres
+func (c *ControladorCanal) EliminarEsquemaMetadatos(ctx *gin.Context) {
+	canalID, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	if err := c.esquemas.Eliminar(ctx.Request.Context(), canalID); err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,60 @@
+package controlador
+
+import (
+	"net/http"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorClaveAPI expone la administración de claves de API
+type ControladorClaveAPI struct {
+	casoUso *casoUso.CasoUsoClavesAPI
+}
+
+// NuevoControladorClaveAPI crea una nueva instancia de ControladorClaveAPI
+func NuevoControladorClaveAPI(casoUsoClaves *casoUso.CasoUsoClavesAPI) *ControladorClaveAPI {
+	return &ControladorClaveAPI{casoUso: casoUsoClaves}
+}
+
+// CrearClave emite una clave; el valor completo solo se incluye en esta respuesta
+func (c *ControladorClaveAPI) CrearClave(ctx *gin.Context) {
+	var solicitud dto.SolicitudClaveAPI
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	clave, valor, err := c.casoUso.Crear(ctx.Request.Context(), solicitud)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"clave": clave, "valor": valor})
+}
+
+// ListarClaves lista las claves visibles para quien consulta
+func (c *ControladorClaveAPI) ListarClaves(ctx *gin.Context) {
+	claves, err := c.casoUso.Listar(ctx.Request.Context())
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"claves": claves})
+}
+
+// RevocarClave invalida una clave de forma permanente
+func (c *ControladorClaveAPI) RevocarClave(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	clave, err := c.casoUso.Revocar(ctx.Request.Context(), id)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, clave)
+}
@@ -0,0 +1,31 @@
+package controlador
+
+import (
+	"net/http"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorCodigoQR sirve la imagen del código QR de las URL firmadas de las notificaciones
+type ControladorCodigoQR struct {
+	casoUso *casoUso.CasoUsoCodigosQR
+}
+
+// NuevoControladorCodigoQR crea una nueva instancia de ControladorCodigoQR
+func NuevoControladorCodigoQR(casoUsoCodigos *casoUso.CasoUsoCodigosQR) *ControladorCodigoQR {
+	return &ControladorCodigoQR{casoUso: casoUsoCodigos}
+}
+
+// Imagen responde el PNG del código QR; una URL vencida o adulterada responde 404
+func (c *ControladorCodigoQR) Imagen(ctx *gin.Context) {
+	imagen, err := c.casoUso.Imagen(ctx.Request.Context(), ctx.Param("token"))
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	// El código da acceso a la entrada: solo el navegador del destinatario puede guardarlo
+	ctx.Header("Cache-Control", "private, max-age=3600")
+	ctx.Data(http.StatusOK, "image/png", imagen)
+}
@@ -0,0 +1,77 @@
+package controlador
+
+import (
+	"net/http"
+	"strconv"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/presentacion/problema"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorColaMuerta expone la cola de mensajes muertos a los operadores
+type ControladorColaMuerta struct {
+	casoUso *casoUso.CasoUsoColaMuerta
+}
+
+// NuevoControladorColaMuerta crea una nueva instancia de ControladorColaMuerta
+func NuevoControladorColaMuerta(casoUsoColaMuerta *casoUso.CasoUsoColaMuerta) *ControladorColaMuerta {
+	return &ControladorColaMuerta{casoUso: casoUsoColaMuerta}
+}
+
+// ListarEntradas retorna una página de la cola filtrada por inquilino_id y tipo
+func (c *ControladorColaMuerta) ListarEntradas(ctx *gin.Context) {
+	filtro := repositorio.FiltroColaMuerta{
+		Tipo:   entidad.TipoNotificacion(ctx.Query("tipo")),
+		Limite: limitePaginaPredeterminado,
+	}
+	if limite, err := strconv.Atoi(ctx.Query("limite")); err == nil && limite > 0 {
+		filtro.Limite = min(limite, limitePaginaMaximo)
+	}
+
+	numericos := map[string]*uint{
+		"inquilino_id": &filtro.InquilinoID,
+		"cursor":       &filtro.Cursor,
+	}
+	for parametro, destino := range numericos {
+		if valor := ctx.Query(parametro); valor != "" {
+			numero, err := strconv.ParseUint(valor, 10, 64)
+			if err != nil {
+				problema.Responder(ctx, http.StatusBadRequest, "parametro_invalido", parametro+" inválido")
+				return
+			}
+			*destino = uint(numero)
+		}
+	}
+
+	entradas, err := c.casoUso.Listar(ctx.Request.Context(), filtro)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+
+	respuesta := gin.H{"entradas": entradas}
+	if len(entradas) == filtro.Limite {
+		respuesta["siguiente_cursor"] = entradas[len(entradas)-1].ID
+	}
+	ctx.JSON(http.StatusOK, respuesta)
+}
+
+// ReintentarEntrada concede nuevos intentos a la notificación de la entrada; el despacho ocurre
+// en la próxima pasada del planificador de reintentos
+func (c *ControladorColaMuerta) ReintentarEntrada(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	notificacion, err := c.casoUso.Reintentar(ctx.Request.Context(), id)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, notificacion)
+}
@@ -0,0 +1,66 @@
+package controlador
+
+import (
+	"context"
+	"net/http"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorConsentimiento maneja los consentimientos de los usuarios
+type ControladorConsentimiento struct {
+	casoUso *casoUso.CasoUsoConsentimiento
+}
+
+// NuevoControladorConsentimiento crea una nueva instancia de ControladorConsentimiento
+func NuevoControladorConsentimiento(casoUsoConsentimiento *casoUso.CasoUsoConsentimiento) *ControladorConsentimiento {
+	return &ControladorConsentimiento{casoUso: casoUsoConsentimiento}
+}
+
+// ListarConsentimientos retorna el historial de consentimientos del usuario
+func (c *ControladorConsentimiento) ListarConsentimientos(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	consentimientos, err := c.casoUso.Listar(ctx.Request.Context(), id)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"consentimientos": consentimientos})
+}
+
+// OtorgarConsentimiento registra que el usuario dio su consentimiento
+func (c *ControladorConsentimiento) OtorgarConsentimiento(ctx *gin.Context) {
+	c.registrar(ctx, c.casoUso.Otorgar)
+}
+
+// RevocarConsentimiento registra que el usuario retiró su consentimiento
+func (c *ControladorConsentimiento) RevocarConsentimiento(ctx *gin.Context) {
+	c.registrar(ctx, c.casoUso.Revocar)
+}
+
+func (c *ControladorConsentimiento) registrar(ctx *gin.Context, registrar func(context.Context, uint, dto.SolicitudConsentimiento) (*entidad.Consentimiento, error)) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var solicitud dto.SolicitudConsentimiento
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	consentimiento, err := registrar(ctx.Request.Context(), id, solicitud)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, consentimiento)
+}
@@ -0,0 +1,40 @@
+package controlador
+
+import (
+	"net/http"
+	"strconv"
+
+	"sistema-notificaciones-go/internal/infraestructura/correo"
+	"sistema-notificaciones-go/internal/presentacion/problema"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxCorreosCapturados acota cuántos correos capturados se leen por consulta
+const maxCorreosCapturados = 50
+
+// ControladorCorreo expone los correos retenidos por MailHog o Mailpit en desarrollo, para que
+// las pruebas de punta a punta verifiquen lo enviado sin abrir la interfaz del buzón
+type ControladorCorreo struct {
+	buzon *correo.BuzonCaptura
+}
+
+// NuevoControladorCorreo crea una nueva instancia de ControladorCorreo
+func NuevoControladorCorreo(buzon *correo.BuzonCaptura) *ControladorCorreo {
+	return &ControladorCorreo{buzon: buzon}
+}
+
+// ListarCapturados retorna los últimos correos capturados (?limite=N, 10 por defecto)
+func (c *ControladorCorreo) ListarCapturados(ctx *gin.Context) {
+	limite := 10
+	if valor, err := strconv.Atoi(ctx.Query("limite")); err == nil && valor > 0 {
+		limite = min(valor, maxCorreosCapturados)
+	}
+
+	correos, err := c.buzon.Ultimos(ctx.Request.Context(), limite)
+	if err != nil {
+		problema.Responder(ctx, http.StatusBadGateway, "buzon_no_disponible", err.Error())
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"correos": correos})
+}
@@ -0,0 +1,102 @@
+package controlador
+
+import (
+	"crypto/subtle"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/infraestructura/correo"
+	"sistema-notificaciones-go/internal/presentacion/problema"
+
+	"github.com/gin-gonic/gin"
+)
+
+// camposCorreoCrudo son los campos de formulario en que los proveedores de correo entrante
+// envían el mensaje completo: SendGrid Inbound Parse en modo crudo y Mailgun en sus rutas MIME
+var camposCorreoCrudo = []string{"email", "body-mime"}
+
+// ControladorCorreoEntrante recibe las respuestas por correo que reenvía el proveedor del buzón
+// de respuestas y configura a qué webhook las reenvía cada canal
+type ControladorCorreoEntrante struct {
+	casoUso  *casoUso.CasoUsoRespuestasCorreo
+	token    string
+	maxBytes int64
+}
+
+// NuevoControladorCorreoEntrante crea el controlador con el token que autentica al proveedor
+func NuevoControladorCorreoEntrante(casoUsoRespuestas *casoUso.CasoUsoRespuestasCorreo, token string, maxBytes int64) *ControladorCorreoEntrante {
+	return &ControladorCorreoEntrante{casoUso: casoUsoRespuestas, token: token, maxBytes: maxBytes}
+}
+
+// Recibir procesa un correo recibido. El proveedor se autentica con X-Correo-Token o, si solo
+// admite configurar una URL, con ?token=. El cuerpo es el mensaje RFC 5322 crudo o un
+// formulario multipart con él en el campo email o body-mime.
+func (c *ControladorCorreoEntrante) Recibir(ctx *gin.Context) {
+	recibido := ctx.GetHeader("X-Correo-Token")
+	if recibido == "" {
+		recibido = ctx.Query("token")
+	}
+	if subtle.ConstantTimeCompare([]byte(recibido), []byte(c.token)) != 1 {
+		problema.Responder(ctx, http.StatusUnauthorized, "no_autorizado", "No autorizado")
+		return
+	}
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, c.maxBytes)
+	mensaje, ok := c.mensaje(ctx)
+	if !ok {
+		return
+	}
+	leido, err := correo.LeerCorreoEntrante(mensaje)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	resultado, err := c.casoUso.Recibir(ctx.Request.Context(), leido)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, resultado)
+}
+
+// ConfigurarWebhook fija el webhook de respuestas por correo del canal; requiere un actor administrador
+func (c *ControladorCorreoEntrante) ConfigurarWebhook(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	var solicitud dto.SolicitudRespuestasCorreoCanal
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	canal, err := c.casoUso.ConfigurarWebhook(ctx.Request.Context(), id, solicitud.Webhook)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, canal)
+}
+
+// mensaje retorna el mensaje crudo del cuerpo o del campo del formulario que lo trae
+func (c *ControladorCorreoEntrante) mensaje(ctx *gin.Context) (io.Reader, bool) {
+	tipo, _, _ := mime.ParseMediaType(ctx.GetHeader("Content-Type"))
+	if tipo != "multipart/form-data" && tipo != "application/x-www-form-urlencoded" {
+		return ctx.Request.Body, true
+	}
+	if err := ctx.Request.ParseMultipartForm(c.maxBytes); err != nil && tipo == "multipart/form-data" {
+		problema.Responder(ctx, http.StatusBadRequest, "cuerpo_ilegible", "no se pudo leer el cuerpo")
+		return nil, false
+	}
+	for _, campo := range camposCorreoCrudo {
+		if valor := ctx.Request.PostFormValue(campo); valor != "" {
+			return strings.NewReader(valor), true
+		}
+	}
+	problema.Responder(ctx, http.StatusBadRequest, "solicitud_invalida", "Solicitud inválida")
+	return nil, false
+}
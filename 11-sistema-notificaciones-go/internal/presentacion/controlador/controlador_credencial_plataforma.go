@@ -0,0 +1,78 @@
+package controlador
+
+import (
+	"net/http"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorCredencialPlataforma administra los juegos de credenciales de la plataforma por
+// proveedor y su rotación
+type ControladorCredencialPlataforma struct {
+	casoUso *casoUso.CasoUsoCredencialesPlataforma
+}
+
+// NuevoControladorCredencialPlataforma crea una nueva instancia de ControladorCredencialPlataforma
+func NuevoControladorCredencialPlataforma(casoUsoCredenciales *casoUso.CasoUsoCredencialesPlataforma) *ControladorCredencialPlataforma {
+	return &ControladorCredencialPlataforma{casoUso: casoUsoCredenciales}
+}
+
+// Listar retorna los juegos de todos los proveedores sin revelar sus valores
+func (c *ControladorCredencialPlataforma) Listar(ctx *gin.Context) {
+	credenciales, err := c.casoUso.Listar(ctx.Request.Context())
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"credenciales": credenciales})
+}
+
+// Guardar cifra y guarda un juego sin activarlo, para activarlo después
+func (c *ControladorCredencialPlataforma) Guardar(ctx *gin.Context) {
+	var solicitud dto.SolicitudCredencial
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+	resumen, err := c.casoUso.Guardar(ctx.Request.Context(), ctx.Param("proveedor"), ctx.Param("nombre"), solicitud.Credenciales)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, resumen)
+}
+
+// Rotar guarda un juego y lo activa en lugar del activo; rige desde el próximo envío
+func (c *ControladorCredencialPlataforma) Rotar(ctx *gin.Context) {
+	var solicitud dto.SolicitudRotacionCredencial
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+	resumen, err := c.casoUso.Rotar(ctx.Request.Context(), ctx.Param("proveedor"), solicitud.Nombre, solicitud.Credenciales)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, resumen)
+}
+
+// Activar vuelve a activar un juego guardado, p. ej. el anterior a una rotación fallida
+func (c *ControladorCredencialPlataforma) Activar(ctx *gin.Context) {
+	resumen, err := c.casoUso.Activar(ctx.Request.Context(), ctx.Param("proveedor"), ctx.Param("nombre"))
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, resumen)
+}
+
+// Eliminar borra un juego inactivo
+func (c *ControladorCredencialPlataforma) Eliminar(ctx *gin.Context) {
+	if err := c.casoUso.Eliminar(ctx.Request.Context(), ctx.Param("proveedor"), ctx.Param("nombre")); err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
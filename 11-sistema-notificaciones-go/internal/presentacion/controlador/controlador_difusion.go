@@ -0,0 +1,54 @@
+package controlador
+
+import (
+	"net/http"
+	"strconv"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/presentacion/problema"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorDifusion expone la difusión masiva a los suscriptores de un canal
+type ControladorDifusion struct {
+	casoUso *casoUso.CasoUsoDifundirCanal
+}
+
+// NuevoControladorDifusion crea una nueva instancia de ControladorDifusion
+func NuevoControladorDifusion(casoUsoDifundir *casoUso.CasoUsoDifundirCanal) *ControladorDifusion {
+	return &ControladorDifusion{casoUso: casoUsoDifundir}
+}
+
+// Difundir inicia una difusión y responde 202 con su progreso inicial
+func (c *ControladorDifusion) Difundir(ctx *gin.Context) {
+	canalID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		problema.Responder(ctx, http.StatusBadRequest, "id_invalido", "ID de canal inválido")
+		return
+	}
+
+	var solicitud dto.SolicitudDifusion
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	progreso, err := c.casoUso.Iniciar(ctx.Request.Context(), uint(canalID), solicitud)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, progreso)
+}
+
+// ObtenerProgreso retorna el avance de una difusión
+func (c *ControladorDifusion) ObtenerProgreso(ctx *gin.Context) {
+	progreso, existe := c.casoUso.ObtenerProgreso(ctx.Request.Context(), ctx.Param("difusionId"))
+	if !existe {
+		problema.Responder(ctx, http.StatusNotFound, "difusion_no_encontrada", "Difusión no encontrada")
+		return
+	}
+	ctx.JSON(http.StatusOK, progreso)
+}
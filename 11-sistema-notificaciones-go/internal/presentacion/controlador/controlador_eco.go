@@ -0,0 +1,118 @@
+package controlador
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"regexp"
+
+	"sistema-notificaciones-go/internal/infraestructura/eco"
+	"sistema-notificaciones-go/internal/presentacion/problema"
+	"sistema-notificaciones-go/pkg/reloj"
+
+	"github.com/gin-gonic/gin"
+)
+
+// tamanoMaximoEntrega acota los bytes que se guardan de cada entrega recibida
+const tamanoMaximoEntrega = 256 << 10
+
+// nombreBuzonValido restringe los nombres de buzón a un identificador corto y seguro en URLs
+var nombreBuzonValido = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// ControladorEco es el receptor de webhooks de prueba: el integrador registra
+// /api/v1/eco/<buzon> como destino y consulta luego qué le llegó, con los bytes exactos
+// del cuerpo para depurar la verificación de firmas
+type ControladorEco struct {
+	registro *eco.Registro
+	reloj    reloj.Reloj
+}
+
+// NuevoControladorEco crea una nueva instancia de ControladorEco
+func NuevoControladorEco(registro *eco.Registro, reloj reloj.Reloj) *ControladorEco {
+	return &ControladorEco{registro: registro, reloj: reloj}
+}
+
+// EntregaEco es una entrega registrada junto a los datos para comparar firmas
+type EntregaEco struct {
+	eco.Entrega
+	Cuerpo       string `json:"cuerpo"`
+	SHA256Cuerpo string `json:"sha256_cuerpo"`
+	// FirmaHex y FirmaBase64 son el HMAC-SHA256 del cuerpo con el secreto de X-Eco-Secreto
+	FirmaHex    string `json:"firma_hmac_sha256_hex,omitempty"`
+	FirmaBase64 string `json:"firma_hmac_sha256_base64,omitempty"`
+}
+
+// RecibirEntrega registra la petición en el buzón y responde 200
+func (c *ControladorEco) RecibirEntrega(ctx *gin.Context) {
+	nombre, ok := c.buzon(ctx)
+	if !ok {
+		return
+	}
+	cuerpo, err := io.ReadAll(io.LimitReader(ctx.Request.Body, tamanoMaximoEntrega+1))
+	if err != nil {
+		problema.Responder(ctx, http.StatusBadRequest, "cuerpo_ilegible", "no se pudo leer el cuerpo")
+		return
+	}
+	entrega := eco.Entrega{
+		Fecha:     c.reloj.Ahora(),
+		Metodo:    ctx.Request.Method,
+		Consulta:  ctx.Request.URL.RawQuery,
+		Origen:    ctx.ClientIP(),
+		Cabeceras: ctx.Request.Header.Clone(),
+		Cuerpo:    cuerpo,
+		Tamano:    len(cuerpo),
+	}
+	if len(cuerpo) > tamanoMaximoEntrega {
+		entrega.Cuerpo, entrega.Truncado = cuerpo[:tamanoMaximoEntrega], true
+	}
+	c.registro.Registrar(nombre, entrega)
+	ctx.JSON(http.StatusOK, gin.H{"recibida": true})
+}
+
+// ListarEntregas retorna las últimas entregas del buzón, la más reciente primero. Con la
+// cabecera X-Eco-Secreto agrega la firma HMAC-SHA256 esperada de cada cuerpo.
+func (c *ControladorEco) ListarEntregas(ctx *gin.Context) {
+	nombre, ok := c.buzon(ctx)
+	if !ok {
+		return
+	}
+	secreto := ctx.GetHeader("X-Eco-Secreto")
+
+	entregas := c.registro.Ultimas(nombre)
+	respuesta := make([]EntregaEco, len(entregas))
+	for i, entrega := range entregas {
+		resumen := sha256.Sum256(entrega.Cuerpo)
+		respuesta[i] = EntregaEco{Entrega: entrega, Cuerpo: string(entrega.Cuerpo), SHA256Cuerpo: hex.EncodeToString(resumen[:])}
+		if secreto != "" {
+			mac := hmac.New(sha256.New, []byte(secreto))
+			mac.Write(entrega.Cuerpo)
+			firma := mac.Sum(nil)
+			respuesta[i].FirmaHex = hex.EncodeToString(firma)
+			respuesta[i].FirmaBase64 = base64.StdEncoding.EncodeToString(firma)
+		}
+	}
+	ctx.JSON(http.StatusOK, gin.H{"buzon": nombre, "entregas": respuesta})
+}
+
+// VaciarBuzon descarta las entregas registradas del buzón
+func (c *ControladorEco) VaciarBuzon(ctx *gin.Context) {
+	nombre, ok := c.buzon(ctx)
+	if !ok {
+		return
+	}
+	c.registro.Vaciar(nombre)
+	ctx.Status(http.StatusNoContent)
+}
+
+// buzon valida el nombre del buzón de la ruta
+func (c *ControladorEco) buzon(ctx *gin.Context) (string, bool) {
+	nombre := ctx.Param("buzon")
+	if !nombreBuzonValido.MatchString(nombre) {
+		problema.Responder(ctx, http.StatusBadRequest, "buzon_invalido", "nombre de buzón inválido: use letras, números, - o _ (hasta 64)")
+		return "", false
+	}
+	return nombre, true
+}
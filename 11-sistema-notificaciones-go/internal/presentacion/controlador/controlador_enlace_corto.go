@@ -0,0 +1,45 @@
+package controlador
+
+import (
+	"net/http"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorEnlaceCorto redirige los enlaces cortos de los SMS y muestra sus clics
+type ControladorEnlaceCorto struct {
+	casoUso *casoUso.CasoUsoEnlacesCortos
+}
+
+// NuevoControladorEnlaceCorto crea una nueva instancia de ControladorEnlaceCorto
+func NuevoControladorEnlaceCorto(casoUsoEnlaces *casoUso.CasoUsoEnlacesCortos) *ControladorEnlaceCorto {
+	return &ControladorEnlaceCorto{casoUso: casoUsoEnlaces}
+}
+
+// Redirigir responde 302 a la URL del enlace y cuenta el clic; uno vencido responde 410
+func (c *ControladorEnlaceCorto) Redirigir(ctx *gin.Context) {
+	url, err := c.casoUso.Resolver(ctx.Request.Context(), ctx.Param("slug"))
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	// Sin caché: cada apertura debe llegar al servicio para contar el clic
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Redirect(http.StatusFound, url)
+}
+
+// ListarEnlaces retorna los enlaces cortos de la notificación con sus clics
+func (c *ControladorEnlaceCorto) ListarEnlaces(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	enlaces, err := c.casoUso.Listar(ctx.Request.Context(), id)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"enlaces": enlaces})
+}
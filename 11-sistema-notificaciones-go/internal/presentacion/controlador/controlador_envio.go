@@ -0,0 +1,50 @@
+package controlador
+
+import (
+	"net/http"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorEnvio expone los envíos multicanal
+type ControladorEnvio struct {
+	casoUso *casoUso.CasoUsoEnviarMultiCanal
+}
+
+// NuevoControladorEnvio crea una nueva instancia de ControladorEnvio
+func NuevoControladorEnvio(casoUsoEnviar *casoUso.CasoUsoEnviarMultiCanal) *ControladorEnvio {
+	return &ControladorEnvio{casoUso: casoUsoEnviar}
+}
+
+// CrearEnvio registra un envío multicanal y responde con el estado de cada canal
+func (c *ControladorEnvio) CrearEnvio(ctx *gin.Context) {
+	var solicitud dto.SolicitudEnvioMultiCanal
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	envio, err := c.casoUso.Ejecutar(ctx.Request.Context(), solicitud)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, envio)
+}
+
+// ObtenerEnvio retorna el estado global y por canal de un envío
+func (c *ControladorEnvio) ObtenerEnvio(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	envio, err := c.casoUso.ObtenerEnvio(ctx.Request.Context(), id)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, envio)
+}
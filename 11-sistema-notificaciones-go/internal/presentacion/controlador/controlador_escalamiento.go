@@ -0,0 +1,126 @@
+package controlador
+
+import (
+	"net/http"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorEscalamiento maneja las políticas de escalamiento y los incidentes de los canales de seguridad
+type ControladorEscalamiento struct {
+	casoUso *casoUso.CasoUsoEscalamiento
+}
+
+// NuevoControladorEscalamiento crea una nueva instancia de ControladorEscalamiento
+func NuevoControladorEscalamiento(casoUsoEscalamiento *casoUso.CasoUsoEscalamiento) *ControladorEscalamiento {
+	return &ControladorEscalamiento{casoUso: casoUsoEscalamiento}
+}
+
+// ObtenerPolitica retorna la política de escalamiento del canal
+func (c *ControladorEscalamiento) ObtenerPolitica(ctx *gin.Context) {
+	canalID, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	politica, err := c.casoUso.ObtenerPolitica(ctx.Request.Context(), canalID)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, politica)
+}
+
+// GuardarPolitica reemplaza los pasos de la política de escalamiento del canal
+func (c *ControladorEscalamiento) GuardarPolitica(ctx *gin.Context) {
+	canalID, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	var solicitud dto.SolicitudPoliticaEscalamiento
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	pasos := make([]entidad.PasoEscalamiento, len(solicitud.Pasos))
+	for i, paso := range solicitud.Pasos {
+		pasos[i] = entidad.PasoEscalamiento{UsuarioID: paso.UsuarioID, Tipo: paso.Tipo, DemoraMinutos: paso.DemoraMinutos}
+	}
+	politica, err := c.casoUso.GuardarPolitica(ctx.Request.Context(), canalID, pasos)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, politica)
+}
+
+// EliminarPolitica quita la política de escalamiento del canal
+func (c *ControladorEscalamiento) EliminarPolitica(ctx *gin.Context) {
+	canalID, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	if err := c.casoUso.EliminarPolitica(ctx.Request.Context(), canalID); err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// IniciarEscalamiento dispara un incidente en el canal y avisa los pasos sin demora
+func (c *ControladorEscalamiento) IniciarEscalamiento(ctx *gin.Context) {
+	canalID, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	var solicitud dto.SolicitudIniciarEscalamiento
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	escalamiento, err := c.casoUso.Iniciar(ctx.Request.Context(), canalID, solicitud.Titulo, solicitud.Mensaje, solicitud.Metadatos)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, escalamiento)
+}
+
+// ObtenerEscalamiento retorna el estado del incidente y los avisos enviados
+func (c *ControladorEscalamiento) ObtenerEscalamiento(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	escalamiento, err := c.casoUso.Obtener(ctx.Request.Context(), id)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, escalamiento)
+}
+
+// ReconocerEscalamiento detiene los avisos pendientes del incidente
+func (c *ControladorEscalamiento) ReconocerEscalamiento(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	var solicitud dto.SolicitudReconocerEscalamiento
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	escalamiento, err := c.casoUso.Reconocer(ctx.Request.Context(), id, solicitud.UsuarioID)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, escalamiento)
+}
@@ -0,0 +1,74 @@
+package controlador
+
+import (
+	"io"
+	"net/http"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorExportacion expone la exportación de los datos de un inquilino
+type ControladorExportacion struct {
+	casoUso *casoUso.CasoUsoExportarInquilino
+	logger  *logger.Logger
+}
+
+// NuevoControladorExportacion crea una nueva instancia de ControladorExportacion
+func NuevoControladorExportacion(casoUsoExportar *casoUso.CasoUsoExportarInquilino, log *logger.Logger) *ControladorExportacion {
+	return &ControladorExportacion{casoUso: casoUsoExportar, logger: log}
+}
+
+// IniciarExportacion lanza la exportación y responde 202 con su estado inicial
+func (c *ControladorExportacion) IniciarExportacion(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	exportacion, err := c.casoUso.Iniciar(ctx.Request.Context(), id)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, exportacion)
+}
+
+// ObtenerExportacion retorna el avance de una exportación
+func (c *ControladorExportacion) ObtenerExportacion(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	exportacion, err := c.casoUso.Obtener(ctx.Request.Context(), id, ctx.Param("exportacionId"))
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, exportacion)
+}
+
+// DescargarExportacion emite el archivo ZIP de una exportación completada
+func (c *ControladorExportacion) DescargarExportacion(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	archivo, nombre, err := c.casoUso.Abrir(ctx.Request.Context(), id, ctx.Param("exportacionId"))
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	defer archivo.Close()
+
+	ctx.Header("Content-Type", "application/zip")
+	ctx.Header("Content-Disposition", `attachment; filename="`+nombre+`"`)
+	ctx.Status(http.StatusOK)
+	if _, err := io.Copy(ctx.Writer, archivo); err != nil {
+		c.logger.Error("Error enviando exportación", "archivo", nombre, "error", err)
+	}
+}
@@ -0,0 +1,72 @@
+package controlador
+
+import (
+	"net/http"
+	"strconv"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/presentacion/flujo"
+	"sistema-notificaciones-go/internal/presentacion/problema"
+	"sistema-notificaciones-go/pkg/logger"
+	"sistema-notificaciones-go/pkg/reloj"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorFacturacion expone el reporte mensual de consumo para el sistema de finanzas
+type ControladorFacturacion struct {
+	casoUso *casoUso.CasoUsoGenerarFacturacion
+	reloj   reloj.Reloj
+	logger  *logger.Logger
+}
+
+// NuevoControladorFacturacion crea una nueva instancia de ControladorFacturacion
+func NuevoControladorFacturacion(casoUsoFacturacion *casoUso.CasoUsoGenerarFacturacion, rel reloj.Reloj, log *logger.Logger) *ControladorFacturacion {
+	return &ControladorFacturacion{casoUso: casoUsoFacturacion, reloj: rel, logger: log}
+}
+
+// ExportarFacturacion responde el reporte de ?periodo=AAAA-MM (por defecto el mes anterior) como CSV o JSON
+func (c *ControladorFacturacion) ExportarFacturacion(ctx *gin.Context) {
+	periodo := ctx.DefaultQuery("periodo", c.reloj.Ahora().UTC().AddDate(0, -1, 0).Format("2006-01"))
+	formato := ctx.DefaultQuery("formato", "csv")
+	if formato != "csv" && formato != "json" {
+		problema.Responder(ctx, http.StatusBadRequest, "formato_invalido", "formato debe ser csv o json")
+		return
+	}
+
+	reporte, err := c.casoUso.Ejecutar(ctx.Request.Context(), periodo)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	if formato == "json" {
+		ctx.JSON(http.StatusOK, reporte)
+		return
+	}
+
+	escritor, err := flujo.NuevoEscritorCSV(ctx, "facturacion-"+periodo+".csv", []string{
+		"periodo", "inquilino_id", "tipo", "proveedor", "envios", "unidades",
+	})
+	if err != nil {
+		c.logger.Error("Error iniciando exportación CSV", "error", err)
+		return
+	}
+	for _, linea := range reporte.Lineas {
+		err := escritor.Escribir([]string{
+			reporte.Periodo,
+			strconv.FormatUint(uint64(linea.InquilinoID), 10),
+			string(linea.Tipo),
+			linea.Proveedor,
+			strconv.FormatInt(linea.Envios, 10),
+			strconv.FormatInt(linea.Unidades, 10),
+		})
+		if err != nil {
+			c.logger.Error("Error exportando facturación", "error", err)
+			ctx.Abort()
+			return
+		}
+	}
+	if err := escritor.Cerrar(); err != nil {
+		c.logger.Error("Error cerrando exportación CSV", "error", err)
+	}
+}
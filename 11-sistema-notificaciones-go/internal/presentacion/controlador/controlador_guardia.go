@@ -0,0 +1,160 @@
+package controlador
+
+import (
+	"net/http"
+	"time"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorGuardia maneja las rotaciones de guardia de los canales y sus reemplazos
+type ControladorGuardia struct {
+	casoUso *casoUso.CasoUsoRotacionGuardia
+}
+
+// NuevoControladorGuardia crea una nueva instancia de ControladorGuardia
+func NuevoControladorGuardia(casoUsoGuardia *casoUso.CasoUsoRotacionGuardia) *ControladorGuardia {
+	return &ControladorGuardia{casoUso: casoUsoGuardia}
+}
+
+// ConsultaGuardia son los parámetros de la consulta de guardia
+type ConsultaGuardia struct {
+	En *time.Time `form:"en" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// ObtenerRotacion retorna la rotación de guardia del canal
+func (c *ControladorGuardia) ObtenerRotacion(ctx *gin.Context) {
+	canalID, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	rotacion, err := c.casoUso.ObtenerRotacion(ctx.Request.Context(), canalID)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rotacion)
+}
+
+// GuardarRotacion crea o reemplaza la rotación de guardia del canal
+func (c *ControladorGuardia) GuardarRotacion(ctx *gin.Context) {
+	canalID, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	var solicitud dto.SolicitudRotacionGuardia
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	rotacion, err := c.casoUso.GuardarRotacion(ctx.Request.Context(), canalID, &entidad.RotacionGuardia{
+		Participantes: solicitud.Participantes,
+		PrimerRelevo:  solicitud.PrimerRelevo,
+		ZonaHoraria:   solicitud.ZonaHoraria,
+		TurnoDias:     solicitud.TurnoDias,
+	})
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rotacion)
+}
+
+// EliminarRotacion quita la rotación de guardia del canal y sus reemplazos
+func (c *ControladorGuardia) EliminarRotacion(ctx *gin.Context) {
+	canalID, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	if err := c.casoUso.EliminarRotacion(ctx.Request.Context(), canalID); err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ObtenerGuardia retorna quién está de guardia en el canal ahora o en ?en= (RFC 3339)
+func (c *ControladorGuardia) ObtenerGuardia(ctx *gin.Context) {
+	canalID, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	var consulta ConsultaGuardia
+	if !vincularConsulta(ctx, &consulta) {
+		return
+	}
+	var instante time.Time
+	if consulta.En != nil {
+		instante = *consulta.En
+	}
+
+	turno, err := c.casoUso.Guardia(ctx.Request.Context(), canalID, instante)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, turno)
+}
+
+// ListarReemplazos retorna los reemplazos en curso y futuros de la guardia del canal
+func (c *ControladorGuardia) ListarReemplazos(ctx *gin.Context) {
+	canalID, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	reemplazos, err := c.casoUso.ListarReemplazos(ctx.Request.Context(), canalID)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"reemplazos": reemplazos})
+}
+
+// CrearReemplazo asigna la guardia del canal a un usuario durante un intervalo
+func (c *ControladorGuardia) CrearReemplazo(ctx *gin.Context) {
+	canalID, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	var solicitud dto.SolicitudReemplazoGuardia
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	reemplazo, err := c.casoUso.CrearReemplazo(ctx.Request.Context(), canalID, &entidad.ReemplazoGuardia{
+		UsuarioID: solicitud.UsuarioID,
+		Desde:     solicitud.Desde,
+		Hasta:     solicitud.Hasta,
+		Motivo:    solicitud.Motivo,
+	})
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, reemplazo)
+}
+
+// EliminarReemplazo quita un reemplazo; la guardia vuelve a la rotación
+func (c *ControladorGuardia) EliminarReemplazo(ctx *gin.Context) {
+	canalID, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	id, ok := parametroID(ctx, "reemplazoId")
+	if !ok {
+		return
+	}
+
+	if err := c.casoUso.EliminarReemplazo(ctx.Request.Context(), canalID, id); err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
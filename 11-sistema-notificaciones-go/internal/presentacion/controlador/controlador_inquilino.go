@@ -0,0 +1,216 @@
+package controlador
+
+import (
+	"net/http"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorInquilino expone la administración de cuotas, credenciales, SLA, marca y el uso de los inquilinos
+type ControladorInquilino struct {
+	casoUsoCuotas       *casoUso.CasoUsoControlarCuotas
+	casoUsoCredenciales *casoUso.CasoUsoCredencialesProveedor
+	casoUsoSLA          *casoUso.CasoUsoEvaluarSLA
+	casoUsoAprovisionar *casoUso.CasoUsoAprovisionarInquilino
+	casoUsoMarca        *casoUso.CasoUsoMarcaInquilino
+}
+
+// NuevoControladorInquilino crea una nueva instancia de ControladorInquilino
+func NuevoControladorInquilino(
+	casoUsoCuotas *casoUso.CasoUsoControlarCuotas,
+	casoUsoCredenciales *casoUso.CasoUsoCredencialesProveedor,
+	casoUsoSLA *casoUso.CasoUsoEvaluarSLA,
+	casoUsoAprovisionar *casoUso.CasoUsoAprovisionarInquilino,
+	casoUsoMarca *casoUso.CasoUsoMarcaInquilino,
+) *ControladorInquilino {
+	return &ControladorInquilino{
+		casoUsoCuotas:       casoUsoCuotas,
+		casoUsoCredenciales: casoUsoCredenciales,
+		casoUsoSLA:          casoUsoSLA,
+		casoUsoAprovisionar: casoUsoAprovisionar,
+		casoUsoMarca:        casoUsoMarca,
+	}
+}
+
+// AprovisionarInquilino da de alta un inquilino completo y responde 201 con todo lo creado
+func (c *ControladorInquilino) AprovisionarInquilino(ctx *gin.Context) {
+	var solicitud dto.SolicitudAprovisionarInquilino
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	resultado, err := c.casoUsoAprovisionar.Ejecutar(ctx.Request.Context(), solicitud)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, resultado)
+}
+
+// ObtenerUso retorna el consumo del mes en curso por tipo de notificación
+func (c *ControladorInquilino) ObtenerUso(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	uso, err := c.casoUsoCuotas.ObtenerUso(ctx.Request.Context(), id)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, uso)
+}
+
+// GuardarCuota crea o actualiza los límites de un tipo de notificación
+func (c *ControladorInquilino) GuardarCuota(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var solicitud dto.SolicitudCuota
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	cuota := &entidad.CuotaInquilino{
+		InquilinoID:      id,
+		Tipo:             solicitud.Tipo,
+		LimiteMensual:    solicitud.LimiteMensual,
+		LimitePorSegundo: solicitud.LimitePorSegundo,
+	}
+	if err := c.casoUsoCuotas.GuardarCuota(ctx.Request.Context(), cuota); err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, cuota)
+}
+
+// ListarCredenciales lista los proveedores con credenciales propias, sin sus valores
+func (c *ControladorInquilino) ListarCredenciales(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	credenciales, err := c.casoUsoCredenciales.Listar(ctx.Request.Context(), id)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"credenciales": credenciales})
+}
+
+// GuardarCredencial cifra y guarda las credenciales del inquilino para un proveedor
+func (c *ControladorInquilino) GuardarCredencial(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var solicitud dto.SolicitudCredencial
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	resumen, err := c.casoUsoCredenciales.Guardar(ctx.Request.Context(), id, ctx.Param("proveedor"), solicitud.Credenciales)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, resumen)
+}
+
+// EliminarCredencial elimina las credenciales del proveedor; se vuelve a las de la plataforma
+func (c *ControladorInquilino) EliminarCredencial(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	if err := c.casoUsoCredenciales.Eliminar(ctx.Request.Context(), id, ctx.Param("proveedor")); err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ObtenerSLA retorna el cumplimiento de cada objetivo en ?periodo=AAAA-MM (por defecto el mes en curso)
+func (c *ControladorInquilino) ObtenerSLA(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	cumplimientos, err := c.casoUsoSLA.Cumplimiento(ctx.Request.Context(), id, ctx.Query("periodo"))
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"sla": cumplimientos})
+}
+
+// GuardarObjetivoSLA crea o actualiza el objetivo de envío al proveedor de una prioridad
+func (c *ControladorInquilino) GuardarObjetivoSLA(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var solicitud dto.SolicitudObjetivoSLA
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	objetivo := &entidad.ObjetivoSLA{
+		InquilinoID:        id,
+		Prioridad:          solicitud.Prioridad,
+		UmbralSegundos:     solicitud.UmbralSegundos,
+		PorcentajeObjetivo: solicitud.PorcentajeObjetivo,
+	}
+	if err := c.casoUsoSLA.GuardarObjetivo(ctx.Request.Context(), objetivo); err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, objetivo)
+}
+
+// ObtenerMarca retorna la marca configurada del inquilino
+func (c *ControladorInquilino) ObtenerMarca(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	marca, err := c.casoUsoMarca.Obtener(ctx.Request.Context(), id)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, marca)
+}
+
+// GuardarMarca crea o reemplaza la marca aplicada al contenido renderizado del inquilino
+func (c *ControladorInquilino) GuardarMarca(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var solicitud dto.SolicitudMarca
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	marca, err := c.casoUsoMarca.Guardar(ctx.Request.Context(), id, solicitud)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, marca)
+}
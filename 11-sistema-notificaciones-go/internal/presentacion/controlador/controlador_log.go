@@ -0,0 +1,97 @@
+package controlador
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"sistema-notificaciones-go/internal/presentacion/problema"
+	"sistema-notificaciones-go/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorLog permite consultar y cambiar niveles de log en tiempo de ejecución
+type ControladorLog struct {
+	niveles *logger.RegistroNiveles
+	logger  *logger.Logger
+}
+
+// NuevoControladorLog crea una nueva instancia de ControladorLog
+func NuevoControladorLog(log *logger.Logger) *ControladorLog {
+	return &ControladorLog{
+		niveles: log.Niveles(),
+		logger:  log,
+	}
+}
+
+// SolicitudNivelLog es el cuerpo para cambiar un nivel de log
+type SolicitudNivelLog struct {
+	// Componente vacío o "global" cambia el nivel global
+	Componente string `json:"componente"`
+	Nivel      string `json:"nivel" binding:"required,oneof=debug info warn warning error"`
+	// Duracion opcional (p. ej. "10m") tras la cual el componente vuelve al nivel global
+	Duracion string `json:"duracion" binding:"omitempty,duracion"`
+}
+
+// ObtenerNiveles retorna los niveles efectivos actuales
+func (c *ControladorLog) ObtenerNiveles(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"niveles": c.niveles.Instantanea()})
+}
+
+// ActualizarNivel cambia el nivel global o de un componente
+func (c *ControladorLog) ActualizarNivel(ctx *gin.Context) {
+	var solicitud SolicitudNivelLog
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	nivel, err := logger.ParsearNivel(solicitud.Nivel)
+	if err != nil {
+		problema.Responder(ctx, http.StatusBadRequest, "nivel_invalido", err.Error())
+		return
+	}
+
+	// El formato ya fue validado en el binding
+	duracion, _ := time.ParseDuration(solicitud.Duracion)
+
+	if solicitud.Componente == "" || solicitud.Componente == "global" {
+		if duracion > 0 {
+			problema.Responder(ctx, http.StatusBadRequest, "validacion", "La duración solo aplica a componentes")
+			return
+		}
+		c.niveles.EstablecerGlobal(nivel)
+	} else {
+		if !logger.EsComponente(solicitud.Componente) {
+			responderComponenteInvalido(ctx, solicitud.Componente)
+			return
+		}
+		c.niveles.EstablecerComponente(solicitud.Componente, nivel, duracion)
+	}
+
+	c.logger.Warn("Nivel de log modificado",
+		"componente", solicitud.Componente,
+		"nivel", logger.NombreNivel(nivel),
+		"duracion", solicitud.Duracion,
+		"ip", ctx.ClientIP(),
+	)
+
+	ctx.JSON(http.StatusOK, gin.H{"niveles": c.niveles.Instantanea()})
+}
+
+// RestablecerNivel hace que un componente vuelva a seguir el nivel global
+func (c *ControladorLog) RestablecerNivel(ctx *gin.Context) {
+	componente := ctx.Param("componente")
+	if !logger.EsComponente(componente) {
+		responderComponenteInvalido(ctx, componente)
+		return
+	}
+	c.niveles.RestablecerComponente(componente)
+	ctx.JSON(http.StatusOK, gin.H{"niveles": c.niveles.Instantanea()})
+}
+
+func responderComponenteInvalido(ctx *gin.Context, componente string) {
+	problema.Responder(ctx, http.StatusBadRequest, "componente_invalido",
+		fmt.Sprintf("Componente de log desconocido: %q (use %s, %s, %s o %s)", componente,
+			logger.ComponenteHTTP, logger.ComponenteWebSocket, logger.ComponenteProveedores, logger.ComponenteTrabajadores))
+}
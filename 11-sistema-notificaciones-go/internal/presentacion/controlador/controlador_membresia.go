@@ -0,0 +1,156 @@
+package controlador
+
+import (
+	"net/http"
+	"strconv"
+
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/presentacion/problema"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CambiarPrivacidad marca el canal como privado o público; requiere un actor administrador
+func (c *ControladorSuscripcion) CambiarPrivacidad(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var solicitud dto.SolicitudPrivacidadCanal
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	canal, err := c.casoUso.CambiarPrivacidad(ctx.Request.Context(), id, *solicitud.Privado)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, canal)
+}
+
+// Invitar invita a un usuario al canal privado; requiere un actor administrador
+func (c *ControladorSuscripcion) Invitar(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var solicitud dto.SolicitudInvitacionCanal
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	membresia, err := c.casoUso.Invitar(ctx.Request.Context(), id, solicitud)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	if !membresia.EstaConfirmada() {
+		ctx.JSON(http.StatusAccepted, membresia)
+		return
+	}
+	ctx.JSON(http.StatusOK, membresia)
+}
+
+// AceptarInvitacion confirma la membresía desde el enlace enviado al usuario
+func (c *ControladorSuscripcion) AceptarInvitacion(ctx *gin.Context) {
+	membresia, err := c.casoUso.AceptarInvitacion(ctx.Request.Context(), ctx.Query("token"))
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, membresia)
+}
+
+// SolicitarIngreso pide el ingreso del usuario a un canal privado
+func (c *ControladorSuscripcion) SolicitarIngreso(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var solicitud dto.SolicitudSuscripcion
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	membresia, err := c.casoUso.SolicitarIngreso(ctx.Request.Context(), id, solicitud)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	if !membresia.EstaConfirmada() {
+		ctx.JSON(http.StatusAccepted, membresia)
+		return
+	}
+	ctx.JSON(http.StatusOK, membresia)
+}
+
+// ListarMembresias retorna una página de las membresías del canal, filtrable por estado
+func (c *ControladorSuscripcion) ListarMembresias(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var cursor uint64
+	if valor := ctx.Query("cursor"); valor != "" {
+		var err error
+		if cursor, err = strconv.ParseUint(valor, 10, 64); err != nil {
+			problema.Responder(ctx, http.StatusBadRequest, "parametro_invalido", "cursor inválido")
+			return
+		}
+	}
+	limite := limitePaginaPredeterminado
+	if valor, err := strconv.Atoi(ctx.Query("limite")); err == nil && valor > 0 {
+		limite = min(valor, limitePaginaMaximo)
+	}
+
+	estado := entidad.EstadoSuscripcion(ctx.Query("estado"))
+	membresias, err := c.casoUso.ListarMembresias(ctx.Request.Context(), id, estado, uint(cursor), limite)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+
+	respuesta := gin.H{"membresias": membresias, "total": len(membresias)}
+	if len(membresias) == limite {
+		respuesta["siguiente_cursor"] = membresias[len(membresias)-1].UsuarioID
+	}
+	ctx.JSON(http.StatusOK, respuesta)
+}
+
+// AprobarIngreso admite el pedido de ingreso; requiere un actor moderador o administrador
+func (c *ControladorSuscripcion) AprobarIngreso(ctx *gin.Context) {
+	c.resolverIngreso(ctx, true)
+}
+
+// RechazarIngreso deniega el pedido de ingreso; requiere un actor moderador o administrador
+func (c *ControladorSuscripcion) RechazarIngreso(ctx *gin.Context) {
+	c.resolverIngreso(ctx, false)
+}
+
+func (c *ControladorSuscripcion) resolverIngreso(ctx *gin.Context, aprobar bool) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	usuarioID, ok := parametroID(ctx, "usuarioId")
+	if !ok {
+		return
+	}
+
+	resolver := c.casoUso.RechazarIngreso
+	if aprobar {
+		resolver = c.casoUso.AprobarIngreso
+	}
+	membresia, err := resolver(ctx.Request.Context(), id, usuarioID)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, membresia)
+}
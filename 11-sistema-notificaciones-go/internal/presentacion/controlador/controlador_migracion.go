@@ -0,0 +1,31 @@
+package controlador
+
+import (
+	"net/http"
+
+	"sistema-notificaciones-go/internal/infraestructura/persistencia"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorMigracion expone el estado del esquema de la base para que un despliegue verifique
+// que no quedan migraciones pendientes antes de avanzar
+type ControladorMigracion struct {
+	migrador *persistencia.Migrador
+}
+
+// NuevoControladorMigracion crea una nueva instancia de ControladorMigracion
+func NuevoControladorMigracion(migrador *persistencia.Migrador) *ControladorMigracion {
+	return &ControladorMigracion{migrador: migrador}
+}
+
+// ObtenerEstado retorna la versión esperada del esquema y, por cada base, la versión aplicada y
+// las tablas, columnas e índices pendientes
+func (c *ControladorMigracion) ObtenerEstado(ctx *gin.Context) {
+	estado, err := c.migrador.Estado(ctx.Request.Context())
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, estado)
+}
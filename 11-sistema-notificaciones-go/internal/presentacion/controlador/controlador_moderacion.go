@@ -0,0 +1,128 @@
+package controlador
+
+import (
+	"net/http"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorModeracion maneja las publicaciones de los miembros de un canal y su moderación
+type ControladorModeracion struct {
+	casoUso *casoUso.CasoUsoModeracionCanal
+}
+
+// NuevoControladorModeracion crea una nueva instancia de ControladorModeracion
+func NuevoControladorModeracion(casoUsoModeracion *casoUso.CasoUsoModeracionCanal) *ControladorModeracion {
+	return &ControladorModeracion{casoUso: casoUsoModeracion}
+}
+
+// ConsultaPublicaciones son los parámetros del listado de publicaciones
+type ConsultaPublicaciones struct {
+	Estado entidad.EstadoBorradorDifusion `form:"estado" binding:"omitempty,oneof=pendiente_moderacion rechazada"`
+}
+
+// CambiarPublicacion abre o cierra el canal a sus miembros; requiere un actor administrador
+func (c *ControladorModeracion) CambiarPublicacion(ctx *gin.Context) {
+	canalID, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	var solicitud dto.SolicitudPublicacionCanal
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	canal, err := c.casoUso.CambiarPublicacion(ctx.Request.Context(), canalID, *solicitud.PublicanMiembros)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, canal)
+}
+
+// Publicar deja la publicación de X-Actor-ID pendiente de moderación y responde 202
+func (c *ControladorModeracion) Publicar(ctx *gin.Context) {
+	canalID, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	var solicitud dto.SolicitudDifusion
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	publicacion, err := c.casoUso.Publicar(ctx.Request.Context(), canalID, solicitud)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, publicacion)
+}
+
+// Listar retorna la cola de moderación del canal (?estado=rechazada para las rechazadas)
+func (c *ControladorModeracion) Listar(ctx *gin.Context) {
+	canalID, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	var consulta ConsultaPublicaciones
+	if !vincularConsulta(ctx, &consulta) {
+		return
+	}
+
+	publicaciones, err := c.casoUso.Listar(ctx.Request.Context(), canalID, consulta.Estado)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"publicaciones": publicaciones})
+}
+
+// Aprobar aprueba la publicación en nombre de X-Actor-ID y responde 202 con la difusión iniciada
+func (c *ControladorModeracion) Aprobar(ctx *gin.Context) {
+	canalID, id, ok := parametrosPublicacion(ctx)
+	if !ok {
+		return
+	}
+
+	publicacion, progreso, err := c.casoUso.Aprobar(ctx.Request.Context(), canalID, id)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, gin.H{"publicacion": publicacion, "difusion": progreso})
+}
+
+// Rechazar rechaza la publicación con el motivo en nombre de X-Actor-ID
+func (c *ControladorModeracion) Rechazar(ctx *gin.Context) {
+	canalID, id, ok := parametrosPublicacion(ctx)
+	if !ok {
+		return
+	}
+	var solicitud dto.SolicitudRechazarPublicacion
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	publicacion, err := c.casoUso.Rechazar(ctx.Request.Context(), canalID, id, solicitud.Motivo)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, publicacion)
+}
+
+// parametrosPublicacion lee el canal y la publicación de la ruta
+func parametrosPublicacion(ctx *gin.Context) (canalID, id uint, ok bool) {
+	if canalID, ok = parametroID(ctx, "id"); !ok {
+		return 0, 0, false
+	}
+	if id, ok = parametroID(ctx, "publicacionId"); !ok {
+		return 0, 0, false
+	}
+	return canalID, id, true
+}
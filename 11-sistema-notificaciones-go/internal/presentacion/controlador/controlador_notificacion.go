@@ -0,0 +1,405 @@
+package controlador
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/internal/presentacion/flujo"
+	"sistema-notificaciones-go/internal/presentacion/problema"
+	"sistema-notificaciones-go/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	limitePaginaPredeterminado = 50
+	limitePaginaMaximo         = 500
+)
+
+// ControladorNotificacion maneja las peticiones HTTP de notificaciones
+type ControladorNotificacion struct {
+	casoUsoEnviar *casoUso.CasoUsoEnviarNotificacion
+	casoUsoEstado *casoUso.CasoUsoCambiarEstadoNotificacion
+	simular       *casoUso.CasoUsoSimularEnvio
+	accesos       *casoUso.CasoUsoRegistrarAccesoPersonal
+	repositorio   repositorio.RepositorioNotificacion
+	version       versionAPI
+	logger        *logger.Logger
+}
+
+// NuevoControladorNotificacion crea una nueva instancia de ControladorNotificacion
+func NuevoControladorNotificacion(
+	casoUsoEnviar *casoUso.CasoUsoEnviarNotificacion,
+	casoUsoEstado *casoUso.CasoUsoCambiarEstadoNotificacion,
+	simular *casoUso.CasoUsoSimularEnvio,
+	accesos *casoUso.CasoUsoRegistrarAccesoPersonal,
+	repositorioNotificacion repositorio.RepositorioNotificacion,
+	log *logger.Logger,
+) *ControladorNotificacion {
+	return &ControladorNotificacion{
+		casoUsoEnviar: casoUsoEnviar,
+		casoUsoEstado: casoUsoEstado,
+		simular:       simular,
+		accesos:       accesos,
+		repositorio:   repositorioNotificacion,
+		version:       versionV1,
+		logger:        log,
+	}
+}
+
+// V2 retorna el controlador con las solicitudes y respuestas de /api/v2
+func (c *ControladorNotificacion) V2() *ControladorNotificacion {
+	v2 := *c
+	v2.version = versionV2
+	return &v2
+}
+
+// EnviarNotificacion crea una nueva notificación
+func (c *ControladorNotificacion) EnviarNotificacion(ctx *gin.Context) {
+	solicitud, ok := c.version.vincularEnvio(ctx)
+	if !ok {
+		return
+	}
+
+	notificacion, nueva, err := c.casoUsoEnviar.Ejecutar(ctx.Request.Context(), solicitud)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+
+	if !nueva {
+		// Colapsada con una idéntica reciente: se responde con la original
+		ctx.Header("X-Notificacion-Duplicada", "true")
+		ctx.JSON(http.StatusOK, c.version.notificacion(notificacion))
+		return
+	}
+	ctx.JSON(http.StatusCreated, c.version.notificacion(notificacion))
+}
+
+// SimularNotificacion explica qué pasaría al enviar la notificación, sin enviarla ni guardarla
+func (c *ControladorNotificacion) SimularNotificacion(ctx *gin.Context) {
+	solicitud, ok := c.version.vincularEnvio(ctx)
+	if !ok {
+		return
+	}
+
+	resultado, err := c.simular.Simular(ctx.Request.Context(), solicitud)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, resultadoSimulacion{resultado, c.version.notificacion(resultado.Notificacion)})
+}
+
+// ObtenerNotificaciones emite en flujo una página de notificaciones de un usuario
+func (c *ControladorNotificacion) ObtenerNotificaciones(ctx *gin.Context) {
+	filtro, ok := c.leerFiltro(ctx)
+	if !ok {
+		return
+	}
+	if filtro.UsuarioID == 0 {
+		problema.Responder(ctx, http.StatusBadRequest, "parametro_requerido", "usuario_id es requerido")
+		return
+	}
+
+	filtro.Limite = limitePaginaPredeterminado
+	if limite, err := strconv.Atoi(ctx.Query("limite")); err == nil && limite > 0 {
+		filtro.Limite = min(limite, limitePaginaMaximo)
+	}
+
+	// Los clientes que consultan periódicamente reciben 304 si la página no cambió; como no se
+	// entregan datos, tampoco se registra el acceso
+	version, err := c.repositorio.VersionPagina(ctx.Request.Context(), filtro)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	if responderSiNoModificado(ctx, ctx.Request.URL.RawQuery, filtro.InquilinoID, version) {
+		return
+	}
+
+	if err := c.accesos.RegistrarLectura(ctx.Request.Context(), entidad.OperacionAccesoListado, filtro.UsuarioID, 0); err != nil {
+		responderError(ctx, err)
+		return
+	}
+
+	escritor, err := flujo.NuevoEscritorJSON(ctx, "notificaciones")
+	if err != nil {
+		c.logger.Error("Error iniciando respuesta en flujo", "error", err)
+		return
+	}
+
+	var ultimoID uint
+	err = c.repositorio.Recorrer(ctx.Request.Context(), filtro, func(notificacion *entidad.Notificacion) error {
+		ultimoID = notificacion.ID
+		return escritor.Escribir(c.version.notificacion(notificacion))
+	})
+	if err != nil {
+		// La cabecera ya fue enviada: solo queda cortar la respuesta
+		c.logger.Error("Error emitiendo notificaciones", "error", err)
+		ctx.Abort()
+		return
+	}
+
+	extras := map[string]any{"total": escritor.Filas()}
+	if escritor.Filas() == filtro.Limite {
+		extras["siguiente_cursor"] = ultimoID
+	}
+	if err := escritor.Cerrar(extras); err != nil {
+		c.logger.Error("Error cerrando respuesta en flujo", "error", err)
+	}
+}
+
+// ContarNoLeidas retorna la cantidad de notificaciones no leídas de un usuario
+func (c *ControladorNotificacion) ContarNoLeidas(ctx *gin.Context) {
+	usuarioID, err := strconv.ParseUint(ctx.Query("usuario_id"), 10, 64)
+	if err != nil || usuarioID == 0 {
+		problema.Responder(ctx, http.StatusBadRequest, "parametro_requerido", "usuario_id es requerido")
+		return
+	}
+
+	total, err := c.repositorio.ContarNoLeidas(ctx.Request.Context(), uint(usuarioID))
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+
+	if responderSiNoModificado(ctx, "no_leidas", servicio.InquilinoDesdeContexto(ctx.Request.Context()), usuarioID, total) {
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"no_leidas": total})
+}
+
+// ObtenerNotificacionPorID obtiene una notificación
+func (c *ControladorNotificacion) ObtenerNotificacionPorID(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	incluir, err := repositorio.ParsearRelaciones(ctx.Query("incluir"))
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+
+	notificacion, err := c.repositorio.ObtenerPorID(ctx.Request.Context(), id, incluir...)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	if err := c.accesos.RegistrarLectura(ctx.Request.Context(), entidad.OperacionAccesoDetalle, notificacion.UsuarioID, notificacion.ID); err != nil {
+		responderError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, c.version.notificacion(notificacion))
+}
+
+// MarcarComoLeida marca una notificación como leída
+func (c *ControladorNotificacion) MarcarComoLeida(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	notificacion, err := c.casoUsoEstado.MarcarComoLeida(ctx.Request.Context(), id)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, c.version.notificacion(notificacion))
+}
+
+// CancelarNotificacion cancela una notificación que aún no fue enviada
+func (c *ControladorNotificacion) CancelarNotificacion(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	notificacion, err := c.casoUsoEstado.Cancelar(ctx.Request.Context(), id)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, c.version.notificacion(notificacion))
+}
+
+// ReprogramarNotificacion cambia la fecha de una notificación programada que aún no se despachó
+func (c *ControladorNotificacion) ReprogramarNotificacion(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	fecha, ok := c.version.vincularReprogramacion(ctx)
+	if !ok {
+		return
+	}
+
+	notificacion, err := c.casoUsoEstado.Reprogramar(ctx.Request.Context(), id, fecha)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, c.version.notificacion(notificacion))
+}
+
+// EliminarNotificacion elimina una notificación
+func (c *ControladorNotificacion) EliminarNotificacion(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	if err := c.repositorio.Eliminar(ctx.Request.Context(), id); err != nil {
+		responderError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ExportarNotificaciones emite en flujo todas las notificaciones del filtro como CSV o JSON
+func (c *ControladorNotificacion) ExportarNotificaciones(ctx *gin.Context) {
+	filtro, ok := c.leerFiltro(ctx)
+	if !ok {
+		return
+	}
+
+	formato := ctx.DefaultQuery("formato", "csv")
+	if formato != "csv" && formato != "json" {
+		problema.Responder(ctx, http.StatusBadRequest, "formato_invalido", "formato debe ser csv o json")
+		return
+	}
+	if err := c.accesos.RegistrarExportacion(ctx.Request.Context(), filtro.UsuarioID); err != nil {
+		responderError(ctx, err)
+		return
+	}
+
+	if formato == "csv" {
+		c.exportarCSV(ctx, filtro)
+	} else {
+		c.exportarJSON(ctx, filtro)
+	}
+}
+
+func (c *ControladorNotificacion) exportarCSV(ctx *gin.Context, filtro repositorio.FiltroNotificaciones) {
+	escritor, err := flujo.NuevoEscritorCSV(ctx, "notificaciones.csv", []string{
+		"id", "usuario_id", "canal_id", "tipo", "estado", "prioridad", "titulo",
+		"intentos_envio", "fecha_creacion", "fecha_enviada", "fecha_leida",
+	})
+	if err != nil {
+		c.logger.Error("Error iniciando exportación CSV", "error", err)
+		return
+	}
+
+	err = c.repositorio.Recorrer(ctx.Request.Context(), filtro, func(n *entidad.Notificacion) error {
+		return escritor.Escribir([]string{
+			strconv.FormatUint(uint64(n.ID), 10),
+			strconv.FormatUint(uint64(n.UsuarioID), 10),
+			strconv.FormatUint(uint64(n.CanalID), 10),
+			string(n.Tipo),
+			string(n.Estado),
+			string(n.Prioridad),
+			n.Titulo,
+			strconv.Itoa(n.IntentosEnvio),
+			n.FechaCreacion.Format(time.RFC3339),
+			formatearFecha(n.FechaEnviada),
+			formatearFecha(n.FechaLeida),
+		})
+	})
+	if err != nil {
+		c.logger.Error("Error exportando notificaciones", "error", err)
+		ctx.Abort()
+		return
+	}
+
+	if err := escritor.Cerrar(); err != nil {
+		c.logger.Error("Error cerrando exportación CSV", "error", err)
+	}
+}
+
+func (c *ControladorNotificacion) exportarJSON(ctx *gin.Context, filtro repositorio.FiltroNotificaciones) {
+	escritor, err := flujo.NuevoEscritorJSON(ctx, "notificaciones")
+	if err != nil {
+		c.logger.Error("Error iniciando exportación JSON", "error", err)
+		return
+	}
+
+	err = c.repositorio.Recorrer(ctx.Request.Context(), filtro, func(n *entidad.Notificacion) error {
+		return escritor.Escribir(n)
+	})
+	if err != nil {
+		c.logger.Error("Error exportando notificaciones", "error", err)
+		ctx.Abort()
+		return
+	}
+
+	if err := escritor.Cerrar(map[string]any{"total": escritor.Filas()}); err != nil {
+		c.logger.Error("Error cerrando exportación JSON", "error", err)
+	}
+}
+
+// leerFiltro construye el filtro a partir de los parámetros de consulta
+func (c *ControladorNotificacion) leerFiltro(ctx *gin.Context) (repositorio.FiltroNotificaciones, bool) {
+	incluir, err := repositorio.ParsearRelaciones(ctx.Query("incluir"))
+	if err != nil {
+		responderError(ctx, err)
+		return repositorio.FiltroNotificaciones{}, false
+	}
+
+	filtro := repositorio.FiltroNotificaciones{
+		Estado:  entidad.EstadoNotificacion(ctx.Query("estado")),
+		Tipo:    entidad.TipoNotificacion(ctx.Query("tipo")),
+		Incluir: incluir,
+	}
+
+	numericos := map[string]*uint{
+		"usuario_id": &filtro.UsuarioID,
+		"canal_id":   &filtro.CanalID,
+		"cursor":     &filtro.Cursor,
+	}
+	for parametro, destino := range numericos {
+		if valor := ctx.Query(parametro); valor != "" {
+			numero, err := strconv.ParseUint(valor, 10, 64)
+			if err != nil {
+				problema.Responder(ctx, http.StatusBadRequest, "parametro_invalido", parametro+" inválido")
+				return filtro, false
+			}
+			*destino = uint(numero)
+		}
+	}
+
+	fechas := map[string]**time.Time{
+		"desde": &filtro.Desde,
+		"hasta": &filtro.Hasta,
+	}
+	for parametro, destino := range fechas {
+		if valor := ctx.Query(parametro); valor != "" {
+			fecha, err := time.Parse(time.RFC3339, valor)
+			if err != nil {
+				problema.Responder(ctx, http.StatusBadRequest, "parametro_invalido", parametro+" debe tener formato RFC3339")
+				return filtro, false
+			}
+			*destino = &fecha
+		}
+	}
+
+	return filtro, true
+}
+
+func formatearFecha(fecha *time.Time) string {
+	if fecha == nil {
+		return ""
+	}
+	return fecha.Format(time.RFC3339)
+}
@@ -0,0 +1,188 @@
+package controlador
+
+import (
+	"net/http"
+	"strconv"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/internal/infraestructura/trabajador"
+	"sistema-notificaciones-go/internal/presentacion/problema"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorPanel expone los datos del panel de administración: ocupación de las colas, carga
+// de despacho, fallidas recientes y agotadas, y vista previa de plantillas
+type ControladorPanel struct {
+	pool                    *trabajador.PoolPrioridades
+	repositorioNotificacion repositorio.RepositorioNotificacion
+	repositorioIntento      repositorio.RepositorioIntentoEnvio
+	marca                   *casoUso.CasoUsoMarcaInquilino
+	carga                   *casoUso.CasoUsoCargaDespacho
+}
+
+// NuevoControladorPanel crea una nueva instancia de ControladorPanel
+func NuevoControladorPanel(
+	pool *trabajador.PoolPrioridades,
+	repositorioNotificacion repositorio.RepositorioNotificacion,
+	repositorioIntento repositorio.RepositorioIntentoEnvio,
+	marca *casoUso.CasoUsoMarcaInquilino,
+	carga *casoUso.CasoUsoCargaDespacho,
+) *ControladorPanel {
+	return &ControladorPanel{
+		pool:                    pool,
+		repositorioNotificacion: repositorioNotificacion,
+		repositorioIntento:      repositorioIntento,
+		marca:                   marca,
+		carga:                   carga,
+	}
+}
+
+// SolicitudVistaPrevia es el cuerpo para renderizar una plantilla sin enviarla
+type SolicitudVistaPrevia struct {
+	Plantilla string                   `json:"plantilla" binding:"required,max=100"`
+	Tipo      entidad.TipoNotificacion `json:"tipo" binding:"required,tipo_notificacion"`
+	Datos     map[string]interface{}   `json:"datos"`
+	// InquilinoID solo lo usa la plataforma para ver la plantilla de un inquilino
+	InquilinoID uint `json:"inquilino_id"`
+}
+
+// SolicitudVistaPreviaCanales es el cuerpo para ver una plantilla renderizada en todos los canales
+type SolicitudVistaPreviaCanales struct {
+	Plantilla   string                 `json:"plantilla" binding:"required,max=100"`
+	Datos       map[string]interface{} `json:"datos"`
+	InquilinoID uint                   `json:"inquilino_id"`
+}
+
+// FallidaPanel es una notificación fallida junto al error de su último intento
+type FallidaPanel struct {
+	entidad.Notificacion
+	UltimoError string `json:"ultimo_error,omitempty"`
+}
+
+// ObtenerCola retorna la ocupación de la cola de cada prioridad
+func (c *ControladorPanel) ObtenerCola(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"colas": c.pool.Profundidades()})
+}
+
+// ObtenerCarga retorna lo listo para despachar en todas las bases y la espera de lo más antiguo,
+// junto a la ocupación de las colas de esta instancia. Es la fuente del escalador metrics-api de KEDA.
+func (c *ControladorPanel) ObtenerCarga(ctx *gin.Context) {
+	carga, err := c.carga.Medir(ctx.Request.Context())
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"carga": carga, "colas": c.pool.Profundidades()})
+}
+
+// ListarFallidas retorna una página de fallidas, las más recientes primero. Con agotadas=true
+// solo las que ya no se reintentan (la cola de mensajes muertos).
+func (c *ControladorPanel) ListarFallidas(ctx *gin.Context) {
+	filtro := repositorio.FiltroNotificaciones{
+		Estado:   entidad.EstadoFallida,
+		Tipo:     entidad.TipoNotificacion(ctx.Query("tipo")),
+		Agotadas: ctx.Query("agotadas") == "true",
+		Limite:   limitePaginaPredeterminado,
+	}
+	if limite, err := strconv.Atoi(ctx.Query("limite")); err == nil && limite > 0 {
+		filtro.Limite = min(limite, limitePaginaMaximo)
+	}
+	numericos := map[string]*uint{"inquilino_id": &filtro.InquilinoID, "cursor": &filtro.Cursor}
+	for parametro, destino := range numericos {
+		if valor := ctx.Query(parametro); valor != "" {
+			numero, err := strconv.ParseUint(valor, 10, 64)
+			if err != nil {
+				problema.Responder(ctx, http.StatusBadRequest, "parametro_invalido", parametro+" inválido")
+				return
+			}
+			*destino = uint(numero)
+		}
+	}
+	if inquilinoID := servicio.InquilinoDesdeContexto(ctx.Request.Context()); inquilinoID != 0 {
+		if filtro.InquilinoID != 0 && filtro.InquilinoID != inquilinoID {
+			responderError(ctx, entidad.ErrAccesoDenegado)
+			return
+		}
+		filtro.InquilinoID = inquilinoID
+	}
+
+	notificaciones, err := c.repositorioNotificacion.Listar(ctx.Request.Context(), filtro)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	fallidas := make([]FallidaPanel, len(notificaciones))
+	for i := range notificaciones {
+		fallidas[i].Notificacion = notificaciones[i]
+		intento, err := c.repositorioIntento.ObtenerUltimo(ctx.Request.Context(), notificaciones[i].ID)
+		if err != nil {
+			responderError(ctx, err)
+			return
+		}
+		if intento != nil {
+			fallidas[i].UltimoError = intento.Error
+		}
+	}
+
+	respuesta := gin.H{"fallidas": fallidas}
+	if len(notificaciones) == filtro.Limite {
+		respuesta["siguiente_cursor"] = notificaciones[len(notificaciones)-1].ID
+	}
+	ctx.JSON(http.StatusOK, respuesta)
+}
+
+// VistaPreviaPlantilla renderiza la plantilla con los datos y la marca del inquilino
+func (c *ControladorPanel) VistaPreviaPlantilla(ctx *gin.Context) {
+	var solicitud SolicitudVistaPrevia
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+	inquilinoID, ok := inquilinoVistaPrevia(ctx, solicitud.InquilinoID)
+	if !ok {
+		return
+	}
+
+	contenido, err := c.marca.RenderizarPlantilla(ctx.Request.Context(), inquilinoID, solicitud.Plantilla, solicitud.Tipo, solicitud.Datos)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"asunto": contenido.Asunto, "cuerpo": contenido.Cuerpo, "metadatos": contenido.Metadatos})
+}
+
+// VistaPreviaCanales muestra cómo se verá la plantilla en el correo, el SMS, las notificaciones
+// push de cada plataforma y la bandeja in-app, para detectar cortes antes de enviarla
+func (c *ControladorPanel) VistaPreviaCanales(ctx *gin.Context) {
+	var solicitud SolicitudVistaPreviaCanales
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+	inquilinoID, ok := inquilinoVistaPrevia(ctx, solicitud.InquilinoID)
+	if !ok {
+		return
+	}
+
+	vista, err := c.marca.VistaPreviaCanales(ctx.Request.Context(), inquilinoID, solicitud.Plantilla, solicitud.Datos)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, vista)
+}
+
+// inquilinoVistaPrevia retorna el inquilino de la credencial o, para la plataforma, el solicitado
+func inquilinoVistaPrevia(ctx *gin.Context, solicitado uint) (uint, bool) {
+	inquilinoID := servicio.InquilinoDesdeContexto(ctx.Request.Context())
+	if inquilinoID == 0 {
+		return solicitado, true
+	}
+	if solicitado != 0 && solicitado != inquilinoID {
+		responderError(ctx, entidad.ErrAccesoDenegado)
+		return 0, false
+	}
+	return inquilinoID, true
+}
@@ -0,0 +1,89 @@
+package controlador
+
+import (
+	"net/http"
+	"strconv"
+
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/internal/presentacion/problema"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorPreferencia maneja las preferencias de notificación de los usuarios
+type ControladorPreferencia struct {
+	repositorio        repositorio.RepositorioPreferencia
+	repositorioUsuario repositorio.RepositorioUsuario
+}
+
+// NuevoControladorPreferencia crea una nueva instancia de ControladorPreferencia
+func NuevoControladorPreferencia(repositorioPreferencia repositorio.RepositorioPreferencia, repositorioUsuario repositorio.RepositorioUsuario) *ControladorPreferencia {
+	return &ControladorPreferencia{
+		repositorio:        repositorioPreferencia,
+		repositorioUsuario: repositorioUsuario,
+	}
+}
+
+// ObtenerPreferencias lista las preferencias de un usuario
+func (c *ControladorPreferencia) ObtenerPreferencias(ctx *gin.Context) {
+	usuarioID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		problema.Responder(ctx, http.StatusBadRequest, "id_invalido", "ID de usuario inválido")
+		return
+	}
+	if _, err := servicio.AutorizarUsuario(ctx.Request.Context(), c.repositorioUsuario, uint(usuarioID)); err != nil {
+		responderError(ctx, err)
+		return
+	}
+
+	preferencias, err := c.repositorio.ListarPorUsuario(ctx.Request.Context(), uint(usuarioID))
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"preferencias": preferencias})
+}
+
+// GuardarPreferencia crea o actualiza la preferencia de un tipo de notificación
+func (c *ControladorPreferencia) GuardarPreferencia(ctx *gin.Context) {
+	usuarioID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		problema.Responder(ctx, http.StatusBadRequest, "id_invalido", "ID de usuario inválido")
+		return
+	}
+	if _, err := servicio.AutorizarUsuario(ctx.Request.Context(), c.repositorioUsuario, uint(usuarioID)); err != nil {
+		responderError(ctx, err)
+		return
+	}
+
+	var solicitud dto.SolicitudPreferencia
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	preferencia := entidad.NuevaPreferenciaNotificacion(uint(usuarioID), solicitud.Tipo)
+	if solicitud.Habilitada != nil {
+		preferencia.Habilitada = *solicitud.Habilitada
+	}
+	preferencia.SilencioDesde = solicitud.SilencioDesde
+	preferencia.SilencioHasta = solicitud.SilencioHasta
+	if solicitud.ZonaHoraria != "" {
+		preferencia.ZonaHoraria = solicitud.ZonaHoraria
+	}
+
+	if err := preferencia.Validar(); err != nil {
+		responderError(ctx, err)
+		return
+	}
+
+	if err := c.repositorio.Guardar(ctx.Request.Context(), preferencia); err != nil {
+		responderError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, preferencia)
+}
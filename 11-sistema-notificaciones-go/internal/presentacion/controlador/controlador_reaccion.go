@@ -0,0 +1,98 @@
+package controlador
+
+import (
+	"net/http"
+	"time"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorReaccion maneja las reacciones a las notificaciones y su resumen por canal
+type ControladorReaccion struct {
+	casoUso *casoUso.CasoUsoReacciones
+}
+
+// NuevoControladorReaccion crea una nueva instancia de ControladorReaccion
+func NuevoControladorReaccion(casoUsoReacciones *casoUso.CasoUsoReacciones) *ControladorReaccion {
+	return &ControladorReaccion{casoUso: casoUsoReacciones}
+}
+
+// ConsultaResumenReacciones es el periodo del resumen de reacciones de un canal
+type ConsultaResumenReacciones struct {
+	Desde *time.Time `form:"desde" time_format:"2006-01-02T15:04:05Z07:00"`
+	Hasta *time.Time `form:"hasta" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// Reaccionar agrega la reacción del destinatario a la notificación
+func (c *ControladorReaccion) Reaccionar(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var solicitud dto.SolicitudReaccion
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	reacciones, err := c.casoUso.Reaccionar(ctx.Request.Context(), id, solicitud.Reaccion, true)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, reacciones)
+}
+
+// QuitarReaccion quita la reacción indicada en la ruta
+func (c *ControladorReaccion) QuitarReaccion(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	reaccion := entidad.TipoReaccion(ctx.Param("reaccion"))
+	reacciones, err := c.casoUso.Reaccionar(ctx.Request.Context(), id, reaccion, false)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, reacciones)
+}
+
+// ListarReacciones retorna las reacciones vigentes de la notificación y su historial
+func (c *ControladorReaccion) ListarReacciones(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	reacciones, err := c.casoUso.Listar(ctx.Request.Context(), id)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, reacciones)
+}
+
+// ResumirCanal retorna cuántos destinatarios del canal reaccionaron, p. ej. la tasa de acuse de un incidente
+func (c *ControladorReaccion) ResumirCanal(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	var consulta ConsultaResumenReacciones
+	if !vincularConsulta(ctx, &consulta) {
+		return
+	}
+
+	resumen, err := c.casoUso.ResumirCanal(ctx.Request.Context(), id, consulta.Desde, consulta.Hasta)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, resumen)
+}
@@ -0,0 +1,35 @@
+package controlador
+
+import (
+	"net/http"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorReenvio maneja el reenvío de las notificaciones fallidas durante un incidente
+type ControladorReenvio struct {
+	casoUso *casoUso.CasoUsoReenviarFallidas
+}
+
+// NuevoControladorReenvio crea una nueva instancia de ControladorReenvio
+func NuevoControladorReenvio(casoUsoReenvio *casoUso.CasoUsoReenviarFallidas) *ControladorReenvio {
+	return &ControladorReenvio{casoUso: casoUsoReenvio}
+}
+
+// ReenviarFallidas copia y encola las fallidas de la ventana indicada; con "simular" solo las cuenta
+func (c *ControladorReenvio) ReenviarFallidas(ctx *gin.Context) {
+	var solicitud dto.SolicitudReenvio
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	resultado, err := c.casoUso.Reenviar(ctx.Request.Context(), solicitud)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, resultado)
+}
@@ -0,0 +1,41 @@
+package controlador
+
+import (
+	"net/http"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorReporte expone el reporte operativo que se envía a los administradores
+type ControladorReporte struct {
+	casoUso *casoUso.CasoUsoReporteOperativo
+}
+
+// NuevoControladorReporte crea una nueva instancia de ControladorReporte
+func NuevoControladorReporte(casoUsoReporte *casoUso.CasoUsoReporteOperativo) *ControladorReporte {
+	return &ControladorReporte{casoUso: casoUsoReporte}
+}
+
+// ConsultaReporte son los parámetros de la consulta del reporte operativo
+type ConsultaReporte struct {
+	Frecuencia entidad.FrecuenciaReporte `form:"frecuencia"`
+}
+
+// ObtenerReporteOperativo arma el reporte del último periodo cerrado (?frecuencia=diario o
+// semanal, diario por defecto) sin enviarlo
+func (c *ControladorReporte) ObtenerReporteOperativo(ctx *gin.Context) {
+	consulta := ConsultaReporte{Frecuencia: entidad.FrecuenciaDiaria}
+	if !vincularConsulta(ctx, &consulta) {
+		return
+	}
+
+	reporte, err := c.casoUso.Generar(ctx.Request.Context(), consulta.Frecuencia)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, reporte)
+}
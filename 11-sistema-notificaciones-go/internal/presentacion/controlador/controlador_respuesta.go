@@ -0,0 +1,94 @@
+package controlador
+
+import (
+	"net/http"
+	"strconv"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/presentacion/problema"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorRespuesta maneja los hilos de respuestas de las notificaciones in-app
+type ControladorRespuesta struct {
+	casoUso *casoUso.CasoUsoRespuestasNotificacion
+}
+
+// NuevoControladorRespuesta crea una nueva instancia de ControladorRespuesta
+func NuevoControladorRespuesta(casoUsoRespuestas *casoUso.CasoUsoRespuestasNotificacion) *ControladorRespuesta {
+	return &ControladorRespuesta{casoUso: casoUsoRespuestas}
+}
+
+// CambiarRespuestas habilita las respuestas en el canal; requiere un actor administrador
+func (c *ControladorRespuesta) CambiarRespuestas(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	var solicitud dto.SolicitudRespuestasCanal
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	canal, err := c.casoUso.CambiarRespuestas(ctx.Request.Context(), id, *solicitud.AdmiteRespuestas)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, canal)
+}
+
+// Responder agrega una respuesta al hilo de la notificación
+func (c *ControladorRespuesta) Responder(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	var solicitud dto.SolicitudRespuesta
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	respuesta, err := c.casoUso.Responder(ctx.Request.Context(), id, solicitud.Mensaje)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, respuesta)
+}
+
+// ObtenerHilo retorna la notificación con una página de sus respuestas, de la más antigua a la
+// más reciente
+func (c *ControladorRespuesta) ObtenerHilo(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var cursor uint64
+	if valor := ctx.Query("cursor"); valor != "" {
+		var err error
+		if cursor, err = strconv.ParseUint(valor, 10, 64); err != nil {
+			problema.Responder(ctx, http.StatusBadRequest, "parametro_invalido", "cursor inválido")
+			return
+		}
+	}
+	limite := limitePaginaPredeterminado
+	if valor, err := strconv.Atoi(ctx.Query("limite")); err == nil && valor > 0 {
+		limite = min(valor, limitePaginaMaximo)
+	}
+
+	hilo, err := c.casoUso.Hilo(ctx.Request.Context(), id, uint(cursor), limite)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+
+	respuesta := gin.H{"notificacion": hilo.Notificacion, "respuestas": hilo.Respuestas}
+	if len(hilo.Respuestas) == limite {
+		respuesta["siguiente_cursor"] = hilo.Respuestas[len(hilo.Respuestas)-1].ID
+	}
+	ctx.JSON(http.StatusOK, respuesta)
+}
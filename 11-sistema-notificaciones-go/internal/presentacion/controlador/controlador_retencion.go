@@ -0,0 +1,66 @@
+package controlador
+
+import (
+	"net/http"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorRetencion administra las políticas de retención de notificaciones
+type ControladorRetencion struct {
+	casoUso *casoUso.CasoUsoAplicarRetencion
+}
+
+// NuevoControladorRetencion crea una nueva instancia de ControladorRetencion
+func NuevoControladorRetencion(casoUsoRetencion *casoUso.CasoUsoAplicarRetencion) *ControladorRetencion {
+	return &ControladorRetencion{casoUso: casoUsoRetencion}
+}
+
+// ListarPoliticas retorna todas las políticas de retención
+func (c *ControladorRetencion) ListarPoliticas(ctx *gin.Context) {
+	politicas, err := c.casoUso.ListarPoliticas(ctx.Request.Context())
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"politicas": politicas})
+}
+
+// GuardarPolitica crea o actualiza la retención de un tipo o un canal para una acción
+func (c *ControladorRetencion) GuardarPolitica(ctx *gin.Context) {
+	var solicitud dto.SolicitudPoliticaRetencion
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	politica := &entidad.PoliticaRetencion{
+		InquilinoID: solicitud.InquilinoID,
+		Tipo:        solicitud.Tipo,
+		CanalID:     solicitud.CanalID,
+		Dias:        solicitud.Dias,
+		Accion:      solicitud.Accion,
+	}
+	if err := c.casoUso.GuardarPolitica(ctx.Request.Context(), politica); err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, politica)
+}
+
+// EliminarPolitica elimina una política de retención
+func (c *ControladorRetencion) EliminarPolitica(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	if err := c.casoUso.EliminarPolitica(ctx.Request.Context(), id); err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
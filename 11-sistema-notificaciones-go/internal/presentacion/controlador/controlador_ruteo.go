@@ -0,0 +1,56 @@
+package controlador
+
+import (
+	"net/http"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorRuteo expone la decisión de entrega efectiva de un usuario para el soporte
+type ControladorRuteo struct {
+	casoUso *casoUso.CasoUsoExplicarRuteo
+}
+
+// NuevoControladorRuteo crea una nueva instancia de ControladorRuteo
+func NuevoControladorRuteo(casoUsoRuteo *casoUso.CasoUsoExplicarRuteo) *ControladorRuteo {
+	return &ControladorRuteo{casoUso: casoUsoRuteo}
+}
+
+// ConsultaRuteo son los parámetros de la consulta de ruteo
+type ConsultaRuteo struct {
+	Tipo        entidad.TipoNotificacion      `form:"tipo" binding:"required,tipo_notificacion"`
+	InquilinoID uint                          `form:"inquilino_id"`
+	CanalID     uint                          `form:"canal_id"`
+	Origen      string                        `form:"origen"`
+	Prioridad   entidad.PrioridadNotificacion `form:"prioridad" binding:"omitempty,prioridad"`
+}
+
+// ExplicarRuteo retorna consentimiento, supresión, silenciamiento, preferencias, dispositivos y
+// proveedor que aplicarían a una notificación del tipo (?tipo=push) para el usuario, opcionalmente
+// de un canal_id, origen y prioridad. La plataforma puede indicar ?inquilino_id= para usuarios de
+// inquilinos con datos en otra región o esquema.
+func (c *ControladorRuteo) ExplicarRuteo(ctx *gin.Context) {
+	usuarioID, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	var consulta ConsultaRuteo
+	if !vincularConsulta(ctx, &consulta) {
+		return
+	}
+
+	decision, err := c.casoUso.Explicar(ctx.Request.Context(), usuarioID, consulta.InquilinoID, casoUso.EscenarioRuteo{
+		Tipo:      consulta.Tipo,
+		CanalID:   consulta.CanalID,
+		Origen:    consulta.Origen,
+		Prioridad: consulta.Prioridad,
+	})
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, decision)
+}
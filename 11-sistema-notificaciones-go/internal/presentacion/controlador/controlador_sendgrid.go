@@ -0,0 +1,42 @@
+package controlador
+
+import (
+	"io"
+	"net/http"
+
+	"sistema-notificaciones-go/internal/infraestructura/correo"
+	"sistema-notificaciones-go/internal/presentacion/problema"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxBytesEventosSendGrid acota una entrega del Event Webhook; SendGrid agrupa los eventos en
+// lotes bastante menores
+const maxBytesEventosSendGrid = 5 << 20
+
+// ControladorSendGrid recibe los eventos que SendGrid informa por cada correo enviado
+type ControladorSendGrid struct {
+	enviador *correo.EnviadorSendGrid
+}
+
+// NuevoControladorSendGrid crea una nueva instancia de ControladorSendGrid
+func NuevoControladorSendGrid(enviador *correo.EnviadorSendGrid) *ControladorSendGrid {
+	return &ControladorSendGrid{enviador: enviador}
+}
+
+// RecibirEventos aplica una entrega del Event Webhook. SendGrid la firma sobre el cuerpo crudo,
+// por eso se lee sin decodificar; una firma inválida responde 403.
+func (c *ControladorSendGrid) RecibirEventos(ctx *gin.Context) {
+	cuerpo, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxBytesEventosSendGrid))
+	if err != nil {
+		problema.Responder(ctx, http.StatusBadRequest, "cuerpo_ilegible", "no se pudo leer el cuerpo")
+		return
+	}
+	err = c.enviador.RecibirEventos(ctx.Request.Context(), cuerpo,
+		ctx.GetHeader(correo.EncabezadoFirmaSendGrid), ctx.GetHeader(correo.EncabezadoMarcaTiempoSendGrid))
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,39 @@
+package controlador
+
+import (
+	"io"
+	"net/http"
+
+	"sistema-notificaciones-go/internal/infraestructura/correo"
+	"sistema-notificaciones-go/internal/presentacion/problema"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxBytesMensajeSNS acota un mensaje de SNS, que no supera los 256 KB
+const maxBytesMensajeSNS = 512 << 10
+
+// ControladorSES recibe los mensajes que SNS publica con los rebotes y quejas de SES
+type ControladorSES struct {
+	enviador *correo.EnviadorSES
+}
+
+// NuevoControladorSES crea una nueva instancia de ControladorSES
+func NuevoControladorSES(enviador *correo.EnviadorSES) *ControladorSES {
+	return &ControladorSES{enviador: enviador}
+}
+
+// RecibirNotificacion aplica un mensaje de SNS. SNS lo envía como text/plain con la firma dentro
+// del propio JSON; un tema no configurado o una firma inválida responden 403.
+func (c *ControladorSES) RecibirNotificacion(ctx *gin.Context) {
+	cuerpo, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxBytesMensajeSNS))
+	if err != nil {
+		problema.Responder(ctx, http.StatusBadRequest, "cuerpo_ilegible", "no se pudo leer el cuerpo")
+		return
+	}
+	if err := c.enviador.RecibirNotificacionSNS(ctx.Request.Context(), cuerpo); err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
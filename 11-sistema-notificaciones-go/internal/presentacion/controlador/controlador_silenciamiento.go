@@ -0,0 +1,73 @@
+package controlador
+
+import (
+	"net/http"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorSilenciamiento maneja los canales y orígenes silenciados por cada usuario
+type ControladorSilenciamiento struct {
+	casoUso *casoUso.CasoUsoSilenciamiento
+}
+
+// NuevoControladorSilenciamiento crea una nueva instancia de ControladorSilenciamiento
+func NuevoControladorSilenciamiento(casoUsoSilenciamiento *casoUso.CasoUsoSilenciamiento) *ControladorSilenciamiento {
+	return &ControladorSilenciamiento{casoUso: casoUsoSilenciamiento}
+}
+
+// Listar retorna los silenciamientos en curso del usuario
+func (c *ControladorSilenciamiento) Listar(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	silenciamientos, err := c.casoUso.Listar(ctx.Request.Context(), id)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"silenciamientos": silenciamientos})
+}
+
+// Silenciar silencia un canal o un origen para el usuario
+func (c *ControladorSilenciamiento) Silenciar(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var solicitud dto.SolicitudSilenciamiento
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	silenciamiento, err := c.casoUso.Silenciar(ctx.Request.Context(), id, solicitud)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, silenciamiento)
+}
+
+// Eliminar reactiva las alertas de la fuente silenciada
+func (c *ControladorSilenciamiento) Eliminar(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	silenciamientoID, ok := parametroID(ctx, "silenciamientoId")
+	if !ok {
+		return
+	}
+
+	if err := c.casoUso.Eliminar(ctx.Request.Context(), id, silenciamientoID); err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
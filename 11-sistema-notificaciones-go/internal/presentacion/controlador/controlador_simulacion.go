@@ -0,0 +1,149 @@
+package controlador
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/internal/infraestructura/simulacion"
+	"sistema-notificaciones-go/internal/presentacion/problema"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorSimulacion permite inyectar fallas en los proveedores simulados de los entornos
+// de prueba, para validar reintentos sin depender de que un proveedor real falle
+type ControladorSimulacion struct {
+	proveedores map[entidad.TipoNotificacion]*simulacion.ProveedorSimulado
+}
+
+// NuevoControladorSimulacion crea una nueva instancia de ControladorSimulacion
+func NuevoControladorSimulacion(proveedores map[entidad.TipoNotificacion]*simulacion.ProveedorSimulado) *ControladorSimulacion {
+	return &ControladorSimulacion{proveedores: proveedores}
+}
+
+// LatenciaSimulada describe la demora de cada envío; las duraciones usan el formato de Go ("250ms")
+type LatenciaSimulada struct {
+	Distribucion string `json:"distribucion" binding:"omitempty,oneof=fija uniforme exponencial"`
+	Base         string `json:"base" binding:"omitempty,duracion"`
+	Maxima       string `json:"maxima" binding:"omitempty,duracion"`
+}
+
+// SolicitudFallasProveedor es el cuerpo para configurar las fallas de un proveedor simulado
+type SolicitudFallasProveedor struct {
+	// Tasas es la probabilidad de cada modo: tiempo_agotado, error_servidor, token_invalido, limite_tasa
+	Tasas         map[string]float64 `json:"tasas"`
+	Latencia      LatenciaSimulada   `json:"latencia"`
+	TiempoAgotado string             `json:"tiempo_agotado" binding:"omitempty,duracion"`
+}
+
+// ListarProveedores retorna las fallas configuradas y lo ocurrido en cada proveedor simulado
+func (c *ControladorSimulacion) ListarProveedores(ctx *gin.Context) {
+	proveedores := make([]gin.H, 0, len(c.proveedores))
+	for tipo, proveedor := range c.proveedores {
+		proveedores = append(proveedores, gin.H{
+			"tipo":       tipo,
+			"fallas":     fallasComoSolicitud(proveedor.Fallas()),
+			"inyectadas": proveedor.Inyectadas(),
+			"enviadas":   len(proveedor.Enviadas()),
+		})
+	}
+	ctx.JSON(http.StatusOK, gin.H{"proveedores": proveedores})
+}
+
+// ListarEnvios retorna, en modo sandbox, las notificaciones del inquilino que los proveedores
+// simulados registraron como enviadas, en orden de ID. Admite filtrar por tipo y usuario_id.
+func (c *ControladorSimulacion) ListarEnvios(ctx *gin.Context) {
+	var usuarioID uint64
+	if valor := ctx.Query("usuario_id"); valor != "" {
+		id, err := strconv.ParseUint(valor, 10, 64)
+		if err != nil || id == 0 {
+			problema.Responder(ctx, http.StatusBadRequest, "parametro_invalido", "usuario_id inválido")
+			return
+		}
+		usuarioID = id
+	}
+	tipo := entidad.TipoNotificacion(ctx.Query("tipo"))
+	inquilinoID := servicio.InquilinoDesdeContexto(ctx.Request.Context())
+
+	envios := make([]entidad.Notificacion, 0)
+	for tipoProveedor, proveedor := range c.proveedores {
+		if tipo != "" && tipo != tipoProveedor {
+			continue
+		}
+		for _, enviada := range proveedor.Enviadas() {
+			if (inquilinoID != 0 && enviada.InquilinoID != inquilinoID) || (usuarioID != 0 && uint64(enviada.UsuarioID) != usuarioID) {
+				continue
+			}
+			envios = append(envios, enviada)
+		}
+	}
+	sort.SliceStable(envios, func(i, j int) bool { return envios[i].ID < envios[j].ID })
+	ctx.JSON(http.StatusOK, gin.H{"envios": envios, "total": len(envios)})
+}
+
+// ConfigurarFallas reemplaza las fallas inyectadas por el proveedor simulado del tipo
+func (c *ControladorSimulacion) ConfigurarFallas(ctx *gin.Context) {
+	proveedor, ok := c.proveedor(ctx)
+	if !ok {
+		return
+	}
+	var solicitud SolicitudFallasProveedor
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	// Los formatos de duración ya fueron validados en el binding
+	fallas := simulacion.ConfiguracionFallas{Tasas: make(map[simulacion.ModoFalla]float64, len(solicitud.Tasas))}
+	for modo, tasa := range solicitud.Tasas {
+		fallas.Tasas[simulacion.ModoFalla(modo)] = tasa
+	}
+	fallas.Latencia.Distribucion = simulacion.DistribucionLatencia(solicitud.Latencia.Distribucion)
+	fallas.Latencia.Base, _ = time.ParseDuration(solicitud.Latencia.Base)
+	fallas.Latencia.Maxima, _ = time.ParseDuration(solicitud.Latencia.Maxima)
+	fallas.TiempoAgotado, _ = time.ParseDuration(solicitud.TiempoAgotado)
+
+	if err := proveedor.ConfigurarFallas(fallas); err != nil {
+		problema.Responder(ctx, http.StatusBadRequest, "validacion", err.Error())
+		return
+	}
+	ctx.JSON(http.StatusOK, fallasComoSolicitud(proveedor.Fallas()))
+}
+
+// ReiniciarProveedor quita las fallas y descarta lo registrado por el proveedor simulado
+func (c *ControladorSimulacion) ReiniciarProveedor(ctx *gin.Context) {
+	proveedor, ok := c.proveedor(ctx)
+	if !ok {
+		return
+	}
+	proveedor.Reiniciar()
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c *ControladorSimulacion) proveedor(ctx *gin.Context) (*simulacion.ProveedorSimulado, bool) {
+	proveedor, existe := c.proveedores[entidad.TipoNotificacion(ctx.Param("tipo"))]
+	if !existe {
+		problema.Responder(ctx, http.StatusNotFound, "proveedor_simulado_no_encontrado", "No hay un proveedor simulado para ese tipo")
+		return nil, false
+	}
+	return proveedor, true
+}
+
+func fallasComoSolicitud(fallas simulacion.ConfiguracionFallas) SolicitudFallasProveedor {
+	solicitud := SolicitudFallasProveedor{
+		Tasas: make(map[string]float64, len(fallas.Tasas)),
+		Latencia: LatenciaSimulada{
+			Distribucion: string(fallas.Latencia.Distribucion),
+			Base:         fallas.Latencia.Base.String(),
+			Maxima:       fallas.Latencia.Maxima.String(),
+		},
+		TiempoAgotado: fallas.TiempoAgotado.String(),
+	}
+	for modo, tasa := range fallas.Tasas {
+		solicitud.Tasas[string(modo)] = tasa
+	}
+	return solicitud
+}
@@ -0,0 +1,39 @@
+package controlador
+
+import (
+	"net/http"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorSlack configura el destino de Slack de los canales
+type ControladorSlack struct {
+	casoUso *casoUso.CasoUsoSlackCanal
+}
+
+// NuevoControladorSlack crea una nueva instancia de ControladorSlack
+func NuevoControladorSlack(casoUsoSlack *casoUso.CasoUsoSlackCanal) *ControladorSlack {
+	return &ControladorSlack{casoUso: casoUsoSlack}
+}
+
+// Configurar fija el destino de Slack del canal; requiere un actor administrador
+func (c *ControladorSlack) Configurar(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	var solicitud dto.SolicitudSlackCanal
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	canal, err := c.casoUso.Configurar(ctx.Request.Context(), id, solicitud)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, canal)
+}
@@ -0,0 +1,116 @@
+package controlador
+
+import (
+	"net/http"
+	"strconv"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/presentacion/problema"
+
+	"github.com/gin-gonic/gin"
+)
+
+// tamanoMaximoImportacion acota el CSV que se acepta en una importación
+const tamanoMaximoImportacion = 20 << 20
+
+// ControladorSupresion expone la administración de la lista de no contactar
+type ControladorSupresion struct {
+	casoUso *casoUso.CasoUsoListaSupresion
+}
+
+// NuevoControladorSupresion crea una nueva instancia de ControladorSupresion
+func NuevoControladorSupresion(casoUsoSupresion *casoUso.CasoUsoListaSupresion) *ControladorSupresion {
+	return &ControladorSupresion{casoUso: casoUsoSupresion}
+}
+
+// BuscarSupresiones retorna una página de entradas filtrada por inquilino_id, motivo y
+// valor (prefijo del correo o teléfono)
+func (c *ControladorSupresion) BuscarSupresiones(ctx *gin.Context) {
+	filtro := repositorio.FiltroSupresion{
+		Valor:  ctx.Query("valor"),
+		Motivo: entidad.MotivoSupresion(ctx.Query("motivo")),
+		Limite: limitePaginaPredeterminado,
+	}
+	if limite, err := strconv.Atoi(ctx.Query("limite")); err == nil && limite > 0 {
+		filtro.Limite = min(limite, limitePaginaMaximo)
+	}
+
+	numericos := map[string]*uint{"inquilino_id": &filtro.InquilinoID, "cursor": &filtro.Cursor}
+	for parametro, destino := range numericos {
+		if valor := ctx.Query(parametro); valor != "" {
+			numero, err := strconv.ParseUint(valor, 10, 64)
+			if err != nil {
+				problema.Responder(ctx, http.StatusBadRequest, "parametro_invalido", parametro+" inválido")
+				return
+			}
+			*destino = uint(numero)
+		}
+	}
+
+	entradas, err := c.casoUso.Buscar(ctx.Request.Context(), filtro)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+
+	respuesta := gin.H{"supresiones": entradas}
+	if len(entradas) == filtro.Limite {
+		respuesta["siguiente_cursor"] = entradas[len(entradas)-1].ID
+	}
+	ctx.JSON(http.StatusOK, respuesta)
+}
+
+// AgregarSupresion suprime un correo o teléfono
+func (c *ControladorSupresion) AgregarSupresion(ctx *gin.Context) {
+	var solicitud dto.SolicitudSupresion
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	entrada, err := c.casoUso.Agregar(ctx.Request.Context(), solicitud)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, entrada)
+}
+
+// EliminarSupresion quita una entrada de la lista
+func (c *ControladorSupresion) EliminarSupresion(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	if err := c.casoUso.Eliminar(ctx.Request.Context(), id); err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ImportarSupresiones carga un CSV (valor,motivo,detalle) enviado como cuerpo de la petición.
+// motivo en la query se usa para las líneas que no lo indican; por defecto es manual.
+func (c *ControladorSupresion) ImportarSupresiones(ctx *gin.Context) {
+	motivo := entidad.MotivoSupresion(ctx.DefaultQuery("motivo", string(entidad.MotivoManual)))
+	var inquilinoID uint
+	if valor := ctx.Query("inquilino_id"); valor != "" {
+		numero, err := strconv.ParseUint(valor, 10, 64)
+		if err != nil {
+			problema.Responder(ctx, http.StatusBadRequest, "parametro_invalido", "inquilino_id inválido")
+			return
+		}
+		inquilinoID = uint(numero)
+	}
+
+	archivo := http.MaxBytesReader(ctx.Writer, ctx.Request.Body, tamanoMaximoImportacion)
+	resultado, err := c.casoUso.Importar(ctx.Request.Context(), inquilinoID, archivo, motivo)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, resultado)
+}
@@ -0,0 +1,87 @@
+package controlador
+
+import (
+	"net/http"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorSuscripcion maneja las suscripciones de los usuarios a canales
+type ControladorSuscripcion struct {
+	casoUso *casoUso.CasoUsoSuscripcionCanal
+}
+
+// NuevoControladorSuscripcion crea una nueva instancia de ControladorSuscripcion
+func NuevoControladorSuscripcion(casoUsoSuscripcion *casoUso.CasoUsoSuscripcionCanal) *ControladorSuscripcion {
+	return &ControladorSuscripcion{casoUso: casoUsoSuscripcion}
+}
+
+// ListarSuscripciones retorna las suscripciones del usuario con su estado (pendiente o confirmada)
+func (c *ControladorSuscripcion) ListarSuscripciones(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	suscripciones, err := c.casoUso.Listar(ctx.Request.Context(), id)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"suscripciones": suscripciones})
+}
+
+// Suscribir suscribe al usuario a un canal; las pendientes de confirmar responden 202
+func (c *ControladorSuscripcion) Suscribir(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var solicitud dto.SolicitudSuscripcion
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	suscripcion, err := c.casoUso.Suscribir(ctx.Request.Context(), id, solicitud)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	if !suscripcion.EstaConfirmada() {
+		ctx.JSON(http.StatusAccepted, suscripcion)
+		return
+	}
+	ctx.JSON(http.StatusCreated, suscripcion)
+}
+
+// Desuscribir quita al usuario del canal
+func (c *ControladorSuscripcion) Desuscribir(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	canalID, ok := parametroID(ctx, "canalId")
+	if !ok {
+		return
+	}
+
+	if err := c.casoUso.Desuscribir(ctx.Request.Context(), id, canalID); err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ConfirmarSuscripcion activa la suscripción desde el enlace enviado al usuario
+func (c *ControladorSuscripcion) ConfirmarSuscripcion(ctx *gin.Context) {
+	suscripcion, err := c.casoUso.Confirmar(ctx.Request.Context(), ctx.Query("token"))
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, suscripcion)
+}
@@ -0,0 +1,88 @@
+package controlador
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/infraestructura/telegram"
+	"sistema-notificaciones-go/internal/presentacion/problema"
+
+	"github.com/gin-gonic/gin"
+)
+
+// actualizacionTelegram es la parte que se usa de un Update de la Bot API
+type actualizacionTelegram struct {
+	Mensaje *struct {
+		Texto string `json:"text"`
+		Chat  struct {
+			ID   int64  `json:"id"`
+			Tipo string `json:"type"`
+		} `json:"chat"`
+	} `json:"message"`
+}
+
+// ControladorTelegram atiende el webhook del bot de Telegram y los enlaces de vinculación de
+// los usuarios
+type ControladorTelegram struct {
+	casoUso        *casoUso.CasoUsoVinculoTelegram
+	secretoWebhook string
+}
+
+// NuevoControladorTelegram crea una nueva instancia de ControladorTelegram
+func NuevoControladorTelegram(casoUsoVinculo *casoUso.CasoUsoVinculoTelegram, secretoWebhook string) *ControladorTelegram {
+	return &ControladorTelegram{casoUso: casoUsoVinculo, secretoWebhook: secretoWebhook}
+}
+
+// RecibirActualizacion atiende los mensajes de los chats privados con el bot. Telegram se
+// autentica con el secret_token; las actualizaciones que no son mensajes privados se
+// confirman sin procesar para que Telegram no las reintente.
+func (c *ControladorTelegram) RecibirActualizacion(ctx *gin.Context) {
+	if subtle.ConstantTimeCompare([]byte(ctx.GetHeader(telegram.EncabezadoSecreto)), []byte(c.secretoWebhook)) != 1 {
+		problema.Responder(ctx, http.StatusUnauthorized, "no_autorizado", "No autorizado")
+		return
+	}
+	var actualizacion actualizacionTelegram
+	if err := ctx.ShouldBindJSON(&actualizacion); err != nil {
+		problema.Responder(ctx, http.StatusBadRequest, "cuerpo_ilegible", "no se pudo leer el cuerpo")
+		return
+	}
+	if actualizacion.Mensaje == nil || actualizacion.Mensaje.Chat.Tipo != "private" {
+		ctx.Status(http.StatusOK)
+		return
+	}
+
+	mensaje := actualizacion.Mensaje
+	if err := c.casoUso.Iniciar(ctx.Request.Context(), mensaje.Chat.ID, mensaje.Texto); err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusOK)
+}
+
+// CrearEnlace retorna el enlace t.me con que el usuario vincula su chat
+func (c *ControladorTelegram) CrearEnlace(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	enlace, err := c.casoUso.EnlaceVinculacion(ctx.Request.Context(), id)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, enlace)
+}
+
+// Desvincular olvida el chat de Telegram del usuario
+func (c *ControladorTelegram) Desvincular(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+	if err := c.casoUso.Desvincular(ctx.Request.Context(), id); err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
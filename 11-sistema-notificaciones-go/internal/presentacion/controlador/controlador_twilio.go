@@ -0,0 +1,35 @@
+package controlador
+
+import (
+	"net/http"
+
+	"sistema-notificaciones-go/internal/infraestructura/twilio"
+	"sistema-notificaciones-go/internal/presentacion/problema"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorTwilio recibe los acuses de entrega que Twilio informa por cada SMS enviado
+type ControladorTwilio struct {
+	enviador *twilio.EnviadorTwilio
+}
+
+// NuevoControladorTwilio crea una nueva instancia de ControladorTwilio
+func NuevoControladorTwilio(enviador *twilio.EnviadorTwilio) *ControladorTwilio {
+	return &ControladorTwilio{enviador: enviador}
+}
+
+// RecibirAcuse aplica el estado del mensaje. Twilio se autentica con la firma X-Twilio-Signature
+// sobre el formulario; una firma inválida responde 403 y Twilio no la reintenta.
+func (c *ControladorTwilio) RecibirAcuse(ctx *gin.Context) {
+	if err := ctx.Request.ParseForm(); err != nil {
+		problema.Responder(ctx, http.StatusBadRequest, "cuerpo_ilegible", "no se pudo leer el cuerpo")
+		return
+	}
+	err := c.enviador.RecibirAcuse(ctx.Request.Context(), ctx.Request.PostForm, ctx.GetHeader(twilio.EncabezadoFirma))
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,67 @@
+package controlador
+
+import (
+	"net/http"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorUsuario expone las operaciones sobre usuarios
+type ControladorUsuario struct {
+	servicioEliminacion *servicio.ServicioEliminacionUsuario
+	casoUsoOlvidar      *casoUso.CasoUsoOlvidarUsuario
+}
+
+// NuevoControladorUsuario crea una nueva instancia de ControladorUsuario
+func NuevoControladorUsuario(servicioEliminacion *servicio.ServicioEliminacionUsuario, casoUsoOlvidar *casoUso.CasoUsoOlvidarUsuario) *ControladorUsuario {
+	return &ControladorUsuario{servicioEliminacion: servicioEliminacion, casoUsoOlvidar: casoUsoOlvidar}
+}
+
+// EliminarUsuario elimina el usuario en cascada y responde con el resumen por relación
+func (c *ControladorUsuario) EliminarUsuario(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	resultado, err := c.servicioEliminacion.EliminarUsuario(ctx.Request.Context(), id)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, resultado)
+}
+
+// EliminarDatosPersonales lanza la eliminación irreversible de los datos personales del usuario
+// y responde 202 con el certificado, cuyo avance se consulta en ObtenerCertificadoEliminacion
+func (c *ControladorUsuario) EliminarDatosPersonales(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	certificado, err := c.casoUsoOlvidar.Solicitar(ctx.Request.Context(), id)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, certificado)
+}
+
+// ObtenerCertificadoEliminacion retorna el último certificado de eliminación del usuario
+func (c *ControladorUsuario) ObtenerCertificadoEliminacion(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	certificado, err := c.casoUsoOlvidar.ObtenerCertificado(ctx.Request.Context(), id)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, certificado)
+}
@@ -0,0 +1,69 @@
+package controlador
+
+import (
+	"net/http"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ControladorWebPush maneja las suscripciones Web Push de los navegadores
+type ControladorWebPush struct {
+	casoUso *casoUso.CasoUsoWebPush
+}
+
+// NuevoControladorWebPush crea una nueva instancia de ControladorWebPush
+func NuevoControladorWebPush(casoUsoWebPush *casoUso.CasoUsoWebPush) *ControladorWebPush {
+	return &ControladorWebPush{casoUso: casoUsoWebPush}
+}
+
+// ObtenerClavePublica retorna la clave VAPID para pushManager.subscribe
+func (c *ControladorWebPush) ObtenerClavePublica(ctx *gin.Context) {
+	clave, err := c.casoUso.ClavePublica()
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"clave_publica": clave})
+}
+
+// Suscribir registra la PushSubscription del navegador para el usuario
+func (c *ControladorWebPush) Suscribir(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var solicitud dto.SolicitudSuscripcionWebPush
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	dispositivo, err := c.casoUso.Registrar(ctx.Request.Context(), id, solicitud)
+	if err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, dispositivo)
+}
+
+// Desuscribir elimina la suscripción del navegador
+func (c *ControladorWebPush) Desuscribir(ctx *gin.Context) {
+	id, ok := parametroID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var solicitud dto.SolicitudBajaWebPush
+	if !vincularJSON(ctx, &solicitud) {
+		return
+	}
+
+	if err := c.casoUso.Eliminar(ctx.Request.Context(), id, solicitud.Endpoint); err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,53 @@
+package controlador
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"sistema-notificaciones-go/internal/presentacion/problema"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxBytesWebhookProveedor acota una llamada de proveedor; la mayor es un lote de eventos de
+// SendGrid
+const maxBytesWebhookProveedor = maxBytesEventosSendGrid
+
+// ReceptorWebhookProveedor verifica la firma de una llamada de un proveedor y aplica los estados
+// de entrega que informa; un error entidad.ErrFirmaProveedorInvalida responde 403
+type ReceptorWebhookProveedor interface {
+	RecibirWebhook(ctx context.Context, encabezados http.Header, cuerpo []byte) error
+}
+
+// ControladorWebhooksProveedores recibe en una sola ruta los acuses y eventos de entrega de los
+// proveedores configurados
+type ControladorWebhooksProveedores struct {
+	receptores map[string]ReceptorWebhookProveedor
+}
+
+// NuevoControladorWebhooksProveedores crea una nueva instancia de ControladorWebhooksProveedores
+// con el receptor de cada proveedor, por nombre
+func NuevoControladorWebhooksProveedores(receptores map[string]ReceptorWebhookProveedor) *ControladorWebhooksProveedores {
+	return &ControladorWebhooksProveedores{receptores: receptores}
+}
+
+// Recibir aplica la llamada del proveedor de la ruta. Los proveedores firman el cuerpo crudo, por
+// eso se lee sin decodificar; un proveedor no configurado responde 404.
+func (c *ControladorWebhooksProveedores) Recibir(ctx *gin.Context) {
+	receptor, existe := c.receptores[ctx.Param("proveedor")]
+	if !existe {
+		problema.Responder(ctx, http.StatusNotFound, "proveedor_no_configurado", "El proveedor no está configurado")
+		return
+	}
+	cuerpo, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxBytesWebhookProveedor))
+	if err != nil {
+		problema.Responder(ctx, http.StatusBadRequest, "cuerpo_ilegible", "no se pudo leer el cuerpo")
+		return
+	}
+	if err := receptor.RecibirWebhook(ctx.Request.Context(), ctx.Request.Header, cuerpo); err != nil {
+		responderError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
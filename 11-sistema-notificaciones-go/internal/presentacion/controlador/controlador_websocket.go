@@ -0,0 +1,78 @@
+package controlador
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+	"sistema-notificaciones-go/internal/infraestructura/websocket"
+	"sistema-notificaciones-go/internal/presentacion/middleware"
+	"sistema-notificaciones-go/internal/presentacion/problema"
+	"sistema-notificaciones-go/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	gorilla "github.com/gorilla/websocket"
+)
+
+// ControladorWebSocket acepta conexiones WebSocket autenticadas con JWT
+type ControladorWebSocket struct {
+	hub      *websocket.Hub
+	upgrader gorilla.Upgrader
+	secreto  []byte
+	logger   *logger.Logger
+}
+
+// NuevoControladorWebSocket crea una nueva instancia de ControladorWebSocket
+func NuevoControladorWebSocket(hub *websocket.Hub, config *configuracion.Configuracion, log *logger.Logger) *ControladorWebSocket {
+	return &ControladorWebSocket{
+		hub: hub,
+		upgrader: gorilla.Upgrader{
+			ReadBufferSize:    1024,
+			WriteBufferSize:   1024,
+			EnableCompression: config.Compresion.WebSocket,
+			// Sin subprotocolo pedido los eventos se envían en JSON
+			Subprotocols: websocket.Subprotocolos,
+			CheckOrigin:  origenPermitido(config.WebSocket.OrigenesPermitidos),
+		},
+		secreto: []byte(config.JWT.Secreto),
+		logger:  log.Componente(logger.ComponenteWebSocket),
+	}
+}
+
+// origenPermitido acepta las conexiones sin Origin (clientes que no son navegadores), las del
+// propio host del servidor y las de los orígenes configurados; así una página de otro sitio no
+// puede abrir una conexión con el token de un usuario
+func origenPermitido(permitidos []string) func(*http.Request) bool {
+	return func(r *http.Request) bool {
+		origen := r.Header.Get("Origin")
+		if origen == "" {
+			return true
+		}
+		if slices.ContainsFunc(permitidos, func(permitido string) bool {
+			return strings.EqualFold(strings.TrimSuffix(permitido, "/"), origen)
+		}) {
+			return true
+		}
+		u, err := url.Parse(origen)
+		return err == nil && strings.EqualFold(u.Host, r.Host)
+	}
+}
+
+// ManejarWebSocket valida el token (?token=) y registra la conexión en el hub
+func (c *ControladorWebSocket) ManejarWebSocket(ctx *gin.Context) {
+	inquilinoID, usuarioID, err := middleware.ValidarTokenUsuario(c.secreto, ctx.Query("token"))
+	if err != nil {
+		problema.Responder(ctx, http.StatusUnauthorized, "token_invalido", "Token inválido")
+		return
+	}
+
+	conn, err := c.upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		c.logger.Warn("Error actualizando a WebSocket", "error", err)
+		return
+	}
+
+	c.hub.Registrar(inquilinoID, usuarioID, conn)
+}
@@ -0,0 +1,40 @@
+package controlador
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// responderSiNoModificado fija un ETag débil calculado de las partes que determinan la
+// respuesta y, si el cliente ya tiene esa versión (If-None-Match), responde 304 sin cuerpo.
+// Retorna true si ya respondió.
+func responderSiNoModificado(ctx *gin.Context, partes ...any) bool {
+	// La ruta distingue las representaciones de cada versión de la API
+	suma := sha256.Sum256([]byte(ctx.FullPath() + fmt.Sprintln(partes...)))
+	etag := `W/"` + hex.EncodeToString(suma[:16]) + `"`
+	ctx.Header("ETag", etag)
+	// Cada consulta debe revalidarse, pero la revalidación sale barata
+	ctx.Header("Cache-Control", "private, no-cache")
+
+	if !coincideETag(ctx.GetHeader("If-None-Match"), etag) {
+		return false
+	}
+	ctx.Status(http.StatusNotModified)
+	return true
+}
+
+// coincideETag aplica la comparación débil de If-None-Match: basta con que coincida el valor
+func coincideETag(encabezado, etag string) bool {
+	for _, candidato := range strings.Split(encabezado, ",") {
+		candidato = strings.TrimSpace(candidato)
+		if candidato == "*" || strings.TrimPrefix(candidato, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
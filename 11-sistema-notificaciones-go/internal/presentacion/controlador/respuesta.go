@@ -0,0 +1,158 @@
+package controlador
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/presentacion/idioma"
+	"sistema-notificaciones-go/internal/presentacion/problema"
+	"sistema-notificaciones-go/internal/presentacion/validacion"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errorConocido asocia un error centinela del dominio con su estado HTTP y su código de problema
+type errorConocido struct {
+	err    error
+	estado int
+	codigo string
+}
+
+// erroresConocidos traduce los errores centinela; el código es estable y lo interpretan los clientes
+var erroresConocidos = []errorConocido{
+	{entidad.ErrEnlaceInvalido, http.StatusBadRequest, "enlace_invalido"},
+	{entidad.ErrRespuestaCorreoInvalida, http.StatusBadRequest, "respuesta_correo_invalida"},
+
+	{entidad.ErrNotificacionNoEncontrada, http.StatusNotFound, "notificacion_no_encontrada"},
+	{entidad.ErrUsuarioNoEncontrado, http.StatusNotFound, "usuario_no_encontrado"},
+	{entidad.ErrCanalNoEncontrado, http.StatusNotFound, "canal_no_encontrado"},
+	{entidad.ErrEnvioNoEncontrado, http.StatusNotFound, "envio_no_encontrado"},
+	{entidad.ErrInquilinoNoEncontrado, http.StatusNotFound, "inquilino_no_encontrado"},
+	{entidad.ErrClaveAPINoEncontrada, http.StatusNotFound, "clave_api_no_encontrada"},
+	{entidad.ErrPlantillaNoEncontrada, http.StatusNotFound, "plantilla_no_encontrada"},
+	{entidad.ErrMarcaNoEncontrada, http.StatusNotFound, "marca_no_encontrada"},
+	{entidad.ErrExportacionNoEncontrada, http.StatusNotFound, "exportacion_no_encontrada"},
+	{entidad.ErrCertificadoNoEncontrado, http.StatusNotFound, "certificado_no_encontrado"},
+	{entidad.ErrRetencionNoEncontrada, http.StatusNotFound, "retencion_no_encontrada"},
+	{entidad.ErrSuscripcionNoEncontrada, http.StatusNotFound, "suscripcion_no_encontrada"},
+	{entidad.ErrSupresionNoEncontrada, http.StatusNotFound, "supresion_no_encontrada"},
+	{entidad.ErrSinPoliticaEscalamiento, http.StatusNotFound, "sin_politica_escalamiento"},
+	{entidad.ErrEscalamientoNoEncontrado, http.StatusNotFound, "escalamiento_no_encontrado"},
+	{entidad.ErrSinRotacionGuardia, http.StatusNotFound, "sin_rotacion_guardia"},
+	{entidad.ErrReemplazoNoEncontrado, http.StatusNotFound, "reemplazo_no_encontrado"},
+	{entidad.ErrBorradorDifusionNoEncontrado, http.StatusNotFound, "borrador_difusion_no_encontrado"},
+	{entidad.ErrDispositivoNoEncontrado, http.StatusNotFound, "dispositivo_no_encontrado"},
+	{entidad.ErrSilenciamientoNoEncontrado, http.StatusNotFound, "silenciamiento_no_encontrado"},
+	{entidad.ErrEnlaceCortoNoEncontrado, http.StatusNotFound, "enlace_corto_no_encontrado"},
+	{entidad.ErrEnlaceCortoVencido, http.StatusGone, "enlace_corto_vencido"},
+	{entidad.ErrImagenQRInvalida, http.StatusNotFound, "imagen_qr_invalida"},
+	{entidad.ErrAdjuntoNoEncontrado, http.StatusNotFound, "adjunto_no_encontrado"},
+	{entidad.ErrCredencialPlataformaNoEncontrada, http.StatusNotFound, "credencial_plataforma_no_encontrada"},
+	{entidad.ErrEntradaColaMuertaNoEncontrada, http.StatusNotFound, "entrada_cola_muerta_no_encontrada"},
+
+	{entidad.ErrActorNoAutenticado, http.StatusUnauthorized, "actor_no_autenticado"},
+
+	{entidad.ErrAccesoDenegado, http.StatusForbidden, "acceso_denegado"},
+	{entidad.ErrActorRequerido, http.StatusForbidden, "actor_requerido"},
+	{entidad.ErrRolInsuficiente, http.StatusForbidden, "rol_insuficiente"},
+	{entidad.ErrCanalPrivado, http.StatusForbidden, "canal_privado"},
+	{entidad.ErrPublicacionNoPermitida, http.StatusForbidden, "publicacion_no_permitida"},
+	{entidad.ErrRespuestasNoPermitidas, http.StatusForbidden, "respuestas_no_permitidas"},
+	{entidad.ErrFirmaProveedorInvalida, http.StatusForbidden, "firma_proveedor_invalida"},
+	{entidad.ErrLimiteTasaExcedido, http.StatusTooManyRequests, "limite_tasa_excedido"},
+	{entidad.ErrLimiteSolicitudesExcedido, http.StatusTooManyRequests, "limite_solicitudes_excedido"},
+	{entidad.ErrCuotaMensualExcedida, http.StatusPaymentRequired, "cuota_mensual_excedida"},
+
+	{entidad.ErrCifradoNoConfigurado, http.StatusServiceUnavailable, "cifrado_no_configurado"},
+	{entidad.ErrSinSecretoAnonimizacion, http.StatusServiceUnavailable, "anonimizacion_no_configurada"},
+	{entidad.ErrRegionNoConfigurada, http.StatusServiceUnavailable, "region_no_configurada"},
+	{entidad.ErrDobleOptInNoConfigurado, http.StatusServiceUnavailable, "doble_opt_in_no_configurado"},
+	{entidad.ErrWebPushNoConfigurado, http.StatusServiceUnavailable, "web_push_no_configurado"},
+	{entidad.ErrEsquemaSoloLectura, http.StatusServiceUnavailable, "esquema_solo_lectura"},
+
+	{entidad.ErrUsuarioInactivo, http.StatusConflict, "usuario_inactivo"},
+	{entidad.ErrCanalInactivo, http.StatusConflict, "canal_inactivo"},
+	{entidad.ErrNotificacionYaEnviada, http.StatusConflict, "notificacion_ya_enviada"},
+	{entidad.ErrNotificacionCancelada, http.StatusConflict, "notificacion_cancelada"},
+	{entidad.ErrMaxIntentosExcedidos, http.StatusConflict, "max_intentos_excedidos"},
+	{entidad.ErrConflictoVersion, http.StatusConflict, "conflicto_version"},
+	{entidad.ErrNotificacionEnCreacion, http.StatusConflict, "notificacion_en_creacion"},
+	{entidad.ErrExportacionEnCurso, http.StatusConflict, "exportacion_en_curso"},
+	{entidad.ErrEscalamientoFinalizado, http.StatusConflict, "escalamiento_finalizado"},
+	{entidad.ErrDifusionRequiereAprobacion, http.StatusConflict, "difusion_requiere_aprobacion"},
+	{entidad.ErrEstadoMembresiaInvalido, http.StatusConflict, "estado_membresia_invalido"},
+	{entidad.ErrReaccionNoPermitida, http.StatusConflict, "reaccion_no_permitida"},
+	{entidad.ErrAdjuntoVinculado, http.StatusConflict, "adjunto_vinculado"},
+	{entidad.ErrCredencialPlataformaActiva, http.StatusConflict, "credencial_plataforma_activa"},
+
+	{entidad.ErrArchivoInfectado, http.StatusUnprocessableEntity, "archivo_infectado"},
+}
+
+// responderError traduce errores de dominio a respuestas application/problem+json
+func responderError(ctx *gin.Context, err error) {
+	var errorValidacion *entidad.ErrorValidacion
+	var errorDominio *entidad.ErrorDominio
+	var errorMetadatos *entidad.ErrorMetadatos
+
+	if errors.As(err, &errorMetadatos) {
+		problema.Escribir(ctx, problema.Nuevo(http.StatusBadRequest, "metadatos_invalidos",
+			"Los metadatos no cumplen el esquema del canal").ConCampos(errorMetadatos.Campos))
+		return
+	}
+	if errors.As(err, &errorValidacion) {
+		problema.Responder(ctx, http.StatusBadRequest, "validacion", err.Error())
+		return
+	}
+	for _, conocido := range erroresConocidos {
+		if !errors.Is(err, conocido.err) {
+			continue
+		}
+		if conocido.estado == http.StatusTooManyRequests && ctx.Writer.Header().Get("Retry-After") == "" {
+			ctx.Header("Retry-After", "1")
+		}
+		problema.Responder(ctx, conocido.estado, conocido.codigo, err.Error())
+		return
+	}
+	if errors.As(err, &errorDominio) {
+		problema.Responder(ctx, http.StatusConflict, "regla_dominio", err.Error())
+		return
+	}
+	problema.Responder(ctx, http.StatusInternalServerError, "error_interno", "Error interno del servidor")
+}
+
+// vincularJSON decodifica y valida el cuerpo; si falla responde 400 con cada campo inválido
+func vincularJSON(ctx *gin.Context, destino any) bool {
+	return responderVinculacion(ctx, ctx.ShouldBindJSON(destino))
+}
+
+// vincularConsulta decodifica y valida los parámetros de la URL como vincularJSON
+func vincularConsulta(ctx *gin.Context, destino any) bool {
+	return responderVinculacion(ctx, ctx.ShouldBindQuery(destino))
+}
+
+// responderVinculacion responde 400 con cada campo inválido si la vinculación falló
+func responderVinculacion(ctx *gin.Context, err error) bool {
+	if err == nil {
+		return true
+	}
+
+	campos, ok := validacion.Traducir(err, idioma.Negociar(ctx.GetHeader("Accept-Language")))
+	if !ok {
+		problema.Responder(ctx, http.StatusBadRequest, "solicitud_invalida", err.Error())
+		return false
+	}
+	problema.Escribir(ctx, problema.Nuevo(http.StatusBadRequest, "solicitud_invalida", "Solicitud inválida").ConCampos(campos))
+	return false
+}
+
+// parametroID lee un parámetro de ruta numérico
+func parametroID(ctx *gin.Context, nombre string) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param(nombre), 10, 64)
+	if err != nil || id == 0 {
+		problema.Responder(ctx, http.StatusBadRequest, "id_invalido", "ID inválido")
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,60 @@
+package controlador
+
+import (
+	"time"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+
+	"github.com/gin-gonic/gin"
+)
+
+// versionAPI traduce entre el formato de una versión de la API y el de los casos de uso, así
+// las versiones comparten controladores y servicios y solo difieren en los DTO
+type versionAPI struct {
+	// vincularEnvio decodifica y valida la solicitud de envío de la versión
+	vincularEnvio func(ctx *gin.Context) (dto.SolicitudEnviarNotificacion, bool)
+	// vincularReprogramacion decodifica y valida la nueva fecha programada de la versión
+	vincularReprogramacion func(ctx *gin.Context) (time.Time, bool)
+	// notificacion convierte la notificación al formato de respuesta de la versión
+	notificacion func(n *entidad.Notificacion) any
+}
+
+// versionV1 responde las entidades tal cual, como siempre lo hizo /api/v1
+var versionV1 = versionAPI{
+	vincularEnvio: func(ctx *gin.Context) (dto.SolicitudEnviarNotificacion, bool) {
+		var solicitud dto.SolicitudEnviarNotificacion
+		return solicitud, vincularJSON(ctx, &solicitud)
+	},
+	vincularReprogramacion: func(ctx *gin.Context) (time.Time, bool) {
+		var solicitud dto.SolicitudReprogramar
+		ok := vincularJSON(ctx, &solicitud)
+		return solicitud.FechaProgramada, ok
+	},
+	notificacion: func(n *entidad.Notificacion) any { return n },
+}
+
+// versionV2 usa los DTO de /api/v2
+var versionV2 = versionAPI{
+	vincularEnvio: func(ctx *gin.Context) (dto.SolicitudEnviarNotificacion, bool) {
+		var solicitud dto.SolicitudEnviarNotificacionV2
+		if !vincularJSON(ctx, &solicitud) {
+			return dto.SolicitudEnviarNotificacion{}, false
+		}
+		return solicitud.Solicitud(), true
+	},
+	vincularReprogramacion: func(ctx *gin.Context) (time.Time, bool) {
+		var solicitud dto.SolicitudReprogramarV2
+		ok := vincularJSON(ctx, &solicitud)
+		return solicitud.ProgramadaPara, ok
+	},
+	notificacion: func(n *entidad.Notificacion) any { return dto.NuevaRespuestaNotificacionV2(n) },
+}
+
+// resultadoSimulacion responde la simulación con la notificación en el formato de la versión
+type resultadoSimulacion struct {
+	*casoUso.ResultadoSimulacion
+	// Notificacion oculta la del resultado al serializar
+	Notificacion any `json:"notificacion"`
+}
@@ -0,0 +1,124 @@
+package flujo
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// filasPorVaciado define cada cuántas filas se envía el buffer al cliente
+const filasPorVaciado = 200
+
+// EscritorJSON escribe un objeto JSON con un arreglo que se emite elemento a elemento
+type EscritorJSON struct {
+	ctx         *gin.Context
+	codificador *json.Encoder
+	filas       int
+}
+
+// NuevoEscritorJSON inicia la respuesta {"<campo>":[ con codificación chunked
+func NuevoEscritorJSON(ctx *gin.Context, campo string) (*EscritorJSON, error) {
+	ctx.Header("Content-Type", "application/json; charset=utf-8")
+	ctx.Status(http.StatusOK)
+
+	nombre, err := json.Marshal(campo)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ctx.Writer.Write(append(append([]byte("{"), nombre...), []byte(":[")...)); err != nil {
+		return nil, err
+	}
+
+	return &EscritorJSON{ctx: ctx, codificador: json.NewEncoder(ctx.Writer)}, nil
+}
+
+// Escribir agrega un elemento al arreglo
+func (e *EscritorJSON) Escribir(elemento any) error {
+	if e.filas > 0 {
+		if _, err := e.ctx.Writer.Write([]byte(",")); err != nil {
+			return err
+		}
+	}
+	if err := e.codificador.Encode(elemento); err != nil {
+		return err
+	}
+
+	e.filas++
+	if e.filas%filasPorVaciado == 0 {
+		e.ctx.Writer.Flush()
+	}
+	return nil
+}
+
+// Cerrar termina el arreglo y agrega campos finales al objeto
+func (e *EscritorJSON) Cerrar(extras map[string]any) error {
+	if _, err := e.ctx.Writer.Write([]byte("]")); err != nil {
+		return err
+	}
+	for clave, valor := range extras {
+		datos, err := json.Marshal(map[string]any{clave: valor})
+		if err != nil {
+			return err
+		}
+		// Reutilizar el objeto serializado sin sus llaves externas
+		if _, err := e.ctx.Writer.Write(append([]byte(","), datos[1:len(datos)-1]...)); err != nil {
+			return err
+		}
+	}
+	if _, err := e.ctx.Writer.Write([]byte("}")); err != nil {
+		return err
+	}
+	e.ctx.Writer.Flush()
+	return nil
+}
+
+// Filas retorna la cantidad de elementos escritos
+func (e *EscritorJSON) Filas() int {
+	return e.filas
+}
+
+// EscritorCSV escribe filas CSV vaciando el buffer periódicamente
+type EscritorCSV struct {
+	ctx   *gin.Context
+	csv   *csv.Writer
+	filas int
+}
+
+// NuevoEscritorCSV inicia una descarga CSV con la cabecera indicada
+func NuevoEscritorCSV(ctx *gin.Context, nombreArchivo string, cabecera []string) (*EscritorCSV, error) {
+	ctx.Header("Content-Type", "text/csv; charset=utf-8")
+	ctx.Header("Content-Disposition", `attachment; filename="`+nombreArchivo+`"`)
+	ctx.Status(http.StatusOK)
+
+	escritor := &EscritorCSV{ctx: ctx, csv: csv.NewWriter(ctx.Writer)}
+	if err := escritor.csv.Write(cabecera); err != nil {
+		return nil, err
+	}
+	return escritor, nil
+}
+
+// Escribir agrega una fila
+func (e *EscritorCSV) Escribir(fila []string) error {
+	if err := e.csv.Write(fila); err != nil {
+		return err
+	}
+
+	e.filas++
+	if e.filas%filasPorVaciado == 0 {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+		e.ctx.Writer.Flush()
+	}
+	return nil
+}
+
+// Cerrar vacía lo pendiente
+func (e *EscritorCSV) Cerrar() error {
+	e.csv.Flush()
+	e.ctx.Writer.Flush()
+	return e.csv.Error()
+}
@@ -0,0 +1,98 @@
+package idioma
+
+import "sistema-notificaciones-go/internal/dominio/entidad"
+
+// catalogo traduce los mensajes de error por su texto en español. Los errores centinela usan su
+// propio mensaje como clave, así cambiarlo en el dominio no deja una traducción huérfana.
+var catalogo = map[string]map[Idioma]string{
+	// Solicitud y autenticación
+	"Solicitud inválida":                            {EN: "Invalid request", PT: "Solicitação inválida"},
+	"ID inválido":                                   {EN: "Invalid ID", PT: "ID inválido"},
+	"ID de canal inválido":                          {EN: "Invalid channel ID", PT: "ID de canal inválido"},
+	"ID de usuario inválido":                        {EN: "Invalid user ID", PT: "ID de usuário inválido"},
+	"cursor inválido":                               {EN: "Invalid cursor", PT: "Cursor inválido"},
+	"inquilino_id inválido":                         {EN: "Invalid inquilino_id", PT: "inquilino_id inválido"},
+	"usuario_id es requerido":                       {EN: "usuario_id is required", PT: "usuario_id é obrigatório"},
+	"no se pudo leer el cuerpo":                     {EN: "Could not read the request body", PT: "Não foi possível ler o corpo da solicitação"},
+	"formato debe ser csv o json":                   {EN: "formato must be csv or json", PT: "formato deve ser csv ou json"},
+	"Error interno del servidor":                    {EN: "Internal server error", PT: "Erro interno do servidor"},
+	"Ruta no encontrada":                            {EN: "Route not found", PT: "Rota não encontrada"},
+	"No autorizado":                                 {EN: "Unauthorized", PT: "Não autorizado"},
+	"Token inválido":                                {EN: "Invalid token", PT: "Token inválido"},
+	"Clave de API inválida":                         {EN: "Invalid API key", PT: "Chave de API inválida"},
+	"Requiere administrador de plataforma":          {EN: "Platform administrator required", PT: "Requer administrador da plataforma"},
+	"X-Inquilino-ID inválido":                       {EN: "Invalid X-Inquilino-ID", PT: "X-Inquilino-ID inválido"},
+	"X-Actor-ID inválido":                           {EN: "Invalid X-Actor-ID", PT: "X-Actor-ID inválido"},
+	"X-Motivo-Acceso demasiado largo":               {EN: "X-Motivo-Acceso is too long", PT: "X-Motivo-Acceso é longo demais"},
+	"Actor inválido":                                {EN: "Invalid actor", PT: "Ator inválido"},
+	"Difusión no encontrada":                        {EN: "Broadcast not found", PT: "Difusão não encontrada"},
+	"Los metadatos no cumplen el esquema del canal": {EN: "Metadata does not match the channel schema", PT: "Os metadados não cumprem o esquema do canal"},
+
+	// Recursos no encontrados
+	entidad.ErrNotificacionNoEncontrada.Error():         {EN: "notification not found", PT: "notificação não encontrada"},
+	entidad.ErrUsuarioNoEncontrado.Error():              {EN: "user not found", PT: "usuário não encontrado"},
+	entidad.ErrCanalNoEncontrado.Error():                {EN: "channel not found", PT: "canal não encontrado"},
+	entidad.ErrEnvioNoEncontrado.Error():                {EN: "delivery not found", PT: "envio não encontrado"},
+	entidad.ErrInquilinoNoEncontrado.Error():            {EN: "tenant not found", PT: "inquilino não encontrado"},
+	entidad.ErrClaveAPINoEncontrada.Error():             {EN: "API key not found", PT: "chave de API não encontrada"},
+	entidad.ErrPlantillaNoEncontrada.Error():            {EN: "template not found", PT: "modelo não encontrado"},
+	entidad.ErrMarcaNoEncontrada.Error():                {EN: "the tenant has no branding configured", PT: "o inquilino não tem marca configurada"},
+	entidad.ErrExportacionNoEncontrada.Error():          {EN: "export not found", PT: "exportação não encontrada"},
+	entidad.ErrCertificadoNoEncontrado.Error():          {EN: "there is no personal data deletion for the user", PT: "não há exclusão de dados pessoais para o usuário"},
+	entidad.ErrRetencionNoEncontrada.Error():            {EN: "retention policy not found", PT: "política de retenção não encontrada"},
+	entidad.ErrSuscripcionNoEncontrada.Error():          {EN: "subscription not found", PT: "assinatura não encontrada"},
+	entidad.ErrSupresionNoEncontrada.Error():            {EN: "suppression list entry not found", PT: "entrada da lista de supressão não encontrada"},
+	entidad.ErrSinPoliticaEscalamiento.Error():          {EN: "the channel has no escalation policy", PT: "o canal não tem política de escalonamento"},
+	entidad.ErrEscalamientoNoEncontrado.Error():         {EN: "escalation not found", PT: "escalonamento não encontrado"},
+	entidad.ErrSinRotacionGuardia.Error():               {EN: "the channel has no on-call rotation", PT: "o canal não tem rotação de plantão"},
+	entidad.ErrReemplazoNoEncontrado.Error():            {EN: "on-call override not found", PT: "substituição de plantão não encontrada"},
+	entidad.ErrBorradorDifusionNoEncontrado.Error():     {EN: "broadcast draft not found", PT: "rascunho de difusão não encontrado"},
+	entidad.ErrDispositivoNoEncontrado.Error():          {EN: "push subscription not found", PT: "assinatura push não encontrada"},
+	entidad.ErrSilenciamientoNoEncontrado.Error():       {EN: "mute not found", PT: "silenciamento não encontrado"},
+	entidad.ErrEnlaceCortoNoEncontrado.Error():          {EN: "short link not found", PT: "link curto não encontrado"},
+	entidad.ErrEnlaceCortoVencido.Error():               {EN: "the short link has expired", PT: "o link curto expirou"},
+	entidad.ErrImagenQRInvalida.Error():                 {EN: "the QR code URL is invalid or has expired", PT: "a URL do código QR é inválida ou expirou"},
+	entidad.ErrAdjuntoNoEncontrado.Error():              {EN: "attachment not found", PT: "anexo não encontrado"},
+	entidad.ErrCredencialPlataformaNoEncontrada.Error(): {EN: "credential set not found", PT: "conjunto de credenciais não encontrado"},
+	entidad.ErrEntradaColaMuertaNoEncontrada.Error():    {EN: "dead-letter queue entry not found", PT: "entrada da fila de mensagens mortas não encontrada"},
+
+	// Acceso, roles y límites
+	entidad.ErrEnlaceInvalido.Error():            {EN: "the confirmation link is invalid or has expired", PT: "o link de confirmação é inválido ou expirou"},
+	entidad.ErrRespuestaCorreoInvalida.Error():   {EN: "the email does not reply to a known notification", PT: "o e-mail não responde a uma notificação conhecida"},
+	entidad.ErrAccesoDenegado.Error():            {EN: "the resource belongs to another tenant", PT: "o recurso pertence a outro inquilino"},
+	entidad.ErrActorRequerido.Error():            {EN: "the X-Actor-ID header is required", PT: "o cabeçalho X-Actor-ID é obrigatório"},
+	entidad.ErrActorNoAutenticado.Error():        {EN: "an authenticated user with their own token (Authorization: Bearer) is required", PT: "é necessário um usuário autenticado com o próprio token (Authorization: Bearer)"},
+	entidad.ErrRolInsuficiente.Error():           {EN: "the actor lacks the role required for this action", PT: "o ator não tem o papel necessário para a ação"},
+	entidad.ErrCanalPrivado.Error():              {EN: "the channel is private: join by invitation or with an approved request", PT: "o canal é privado: entra-se por convite ou com uma solicitação aprovada"},
+	entidad.ErrPublicacionNoPermitida.Error():    {EN: "the channel does not accept posts from this user", PT: "o canal não aceita publicações deste usuário"},
+	entidad.ErrRespuestasNoPermitidas.Error():    {EN: "the notification does not accept replies", PT: "a notificação não aceita respostas"},
+	entidad.ErrFirmaProveedorInvalida.Error():    {EN: "the provider signature is not valid", PT: "a assinatura do provedor não é válida"},
+	entidad.ErrLimiteTasaExcedido.Error():        {EN: "per-second delivery limit exceeded", PT: "limite de envios por segundo excedido"},
+	entidad.ErrLimiteSolicitudesExcedido.Error(): {EN: "API request limit exceeded", PT: "limite de solicitações à API excedido"},
+	entidad.ErrCuotaMensualExcedida.Error():      {EN: "monthly delivery quota exceeded", PT: "cota mensal de envios excedida"},
+
+	// Configuración
+	entidad.ErrCifradoNoConfigurado.Error():    {EN: "credential encryption is not configured", PT: "a criptografia de credenciais não está configurada"},
+	entidad.ErrSinSecretoAnonimizacion.Error(): {EN: "anonymization is not configured", PT: "a anonimização não está configurada"},
+	entidad.ErrRegionNoConfigurada.Error():     {EN: "the data residency region is not configured", PT: "a região de residência de dados não está configurada"},
+	entidad.ErrDobleOptInNoConfigurado.Error(): {EN: "subscription confirmation is not configured", PT: "a confirmação de assinaturas não está configurada"},
+	entidad.ErrWebPushNoConfigurado.Error():    {EN: "Web Push delivery is not configured", PT: "o envio Web Push não está configurado"},
+	entidad.ErrEsquemaSoloLectura.Error():      {EN: "the database schema is read-only", PT: "o esquema do banco de dados é somente leitura"},
+
+	// Conflictos de estado
+	entidad.ErrNotificacionEnCreacion.Error():     {EN: "an identical notification is being created", PT: "uma notificação idêntica está sendo criada"},
+	entidad.ErrConflictoVersion.Error():           {EN: "the notification was modified by another process", PT: "a notificação foi modificada por outro processo"},
+	entidad.ErrNotificacionYaEnviada.Error():      {EN: "notification already sent", PT: "notificação já enviada"},
+	entidad.ErrNotificacionCancelada.Error():      {EN: "notification cancelled", PT: "notificação cancelada"},
+	entidad.ErrMaxIntentosExcedidos.Error():       {EN: "maximum attempts exceeded", PT: "máximo de tentativas excedido"},
+	entidad.ErrCanalInactivo.Error():              {EN: "inactive channel", PT: "canal inativo"},
+	entidad.ErrUsuarioInactivo.Error():            {EN: "inactive user", PT: "usuário inativo"},
+	entidad.ErrExportacionEnCurso.Error():         {EN: "the export has not finished yet", PT: "a exportação ainda não terminou"},
+	entidad.ErrEscalamientoFinalizado.Error():     {EN: "the escalation was already acknowledged or ran out of steps", PT: "o escalonamento já foi reconhecido ou esgotou seus passos"},
+	entidad.ErrDifusionRequiereAprobacion.Error(): {EN: "broadcasting to the channel requires approval", PT: "a difusão ao canal requer aprovação"},
+	entidad.ErrEstadoMembresiaInvalido.Error():    {EN: "the membership is not in a state that allows this action", PT: "a associação não está em um estado que permita a ação"},
+	entidad.ErrReaccionNoPermitida.Error():        {EN: "you can only react to notifications delivered to the recipient", PT: "só é possível reagir a notificações entregues ao destinatário"},
+	entidad.ErrAdjuntoVinculado.Error():           {EN: "the attachment already belongs to a notification", PT: "o anexo já pertence a uma notificação"},
+	entidad.ErrArchivoInfectado.Error():           {EN: "the file contains a threat", PT: "o arquivo contém uma ameaça"},
+	entidad.ErrCredencialPlataformaActiva.Error(): {EN: "the credential set is active", PT: "o conjunto de credenciais está ativo"},
+}
@@ -0,0 +1,66 @@
+package idioma
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Idioma es un idioma en que la API responde los mensajes de error
+type Idioma string
+
+const (
+	ES Idioma = "es"
+	EN Idioma = "en"
+	PT Idioma = "pt"
+)
+
+// Predeterminado es el idioma de los mensajes cuando el cliente no pide uno soportado
+const Predeterminado = ES
+
+// Soportados son los idiomas del catálogo
+var Soportados = []Idioma{ES, EN, PT}
+
+// Negociar elige el idioma soportado de mayor preferencia en un encabezado Accept-Language
+// (p. ej. "pt-BR,en;q=0.8"). Solo se compara el idioma principal: es-AR y es-ES son es.
+func Negociar(encabezado string) Idioma {
+	elegido, calidadElegida := Predeterminado, 0.0
+	for _, rango := range strings.Split(encabezado, ",") {
+		etiqueta, parametros, _ := strings.Cut(strings.TrimSpace(rango), ";")
+		calidad := 1.0
+		if valor, ok := strings.CutPrefix(strings.TrimSpace(parametros), "q="); ok {
+			var err error
+			if calidad, err = strconv.ParseFloat(valor, 64); err != nil {
+				continue
+			}
+		}
+		principal, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(etiqueta)), "-")
+		if principal == "*" {
+			principal = string(Predeterminado)
+		}
+		if !Idioma(principal).EsSoportado() || calidad <= calidadElegida {
+			continue
+		}
+		elegido, calidadElegida = Idioma(principal), calidad
+	}
+	return elegido
+}
+
+// EsSoportado indica si el catálogo tiene el idioma
+func (i Idioma) EsSoportado() bool {
+	return slices.Contains(Soportados, i)
+}
+
+// Traducir retorna el mensaje en el idioma. Los mensajes se escriben en español y el catálogo
+// los traduce por su texto; uno sin traducción, p. ej. con datos de la solicitud, queda en español.
+func Traducir(idioma Idioma, mensaje string) string {
+	if idioma == ES {
+		return mensaje
+	}
+	if traducciones, existe := catalogo[mensaje]; existe {
+		if traduccion := traducciones[idioma]; traduccion != "" {
+			return traduccion
+		}
+	}
+	return mensaje
+}
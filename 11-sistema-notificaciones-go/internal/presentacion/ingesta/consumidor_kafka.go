@@ -0,0 +1,205 @@
+// Package ingesta recibe solicitudes de envío por canales distintos de la API REST
+package ingesta
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/aplicacion/dto"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+	"sistema-notificaciones-go/internal/presentacion/idioma"
+	"sistema-notificaciones-go/internal/presentacion/validacion"
+	"sistema-notificaciones-go/pkg/logger"
+
+	"github.com/gin-gonic/gin/binding"
+	kafkago "github.com/segmentio/kafka-go"
+)
+
+const (
+	// encabezadoClaveAPI autentica cada solicitud con una clave de API, como en la API REST
+	encabezadoClaveAPI = "X-API-Key"
+
+	// esperaReintento es la espera base entre intentos de una solicitud; se duplica en cada uno
+	esperaReintento = time.Second
+)
+
+// ConsumidorSolicitudesKafka crea notificaciones desde el tema de solicitudes, una alternativa a la
+// API REST para productores de alto volumen. Cada mensaje es el cuerpo de POST
+// /api/v1/notificaciones con la clave de API en el encabezado X-API-Key, y pasa por las mismas
+// validaciones, cuotas y deduplicación. Cada lector procesa sus particiones en orden y confirma
+// el offset tras cada solicitud; una solicitud rechazada se registra en el log y se descarta.
+type ConsumidorSolicitudesKafka struct {
+	config               configuracion.ConfiguracionKafka
+	envio                *casoUso.CasoUsoEnviarNotificacion
+	claves               *casoUso.CasoUsoClavesAPI
+	repositorioInquilino repositorio.RepositorioInquilino
+	logger               *logger.Logger
+
+	grupo sync.WaitGroup
+}
+
+// NuevoConsumidorSolicitudesKafka crea una nueva instancia de ConsumidorSolicitudesKafka
+func NuevoConsumidorSolicitudesKafka(
+	config configuracion.ConfiguracionKafka,
+	envio *casoUso.CasoUsoEnviarNotificacion,
+	claves *casoUso.CasoUsoClavesAPI,
+	repoInquilino repositorio.RepositorioInquilino,
+	log *logger.Logger,
+) *ConsumidorSolicitudesKafka {
+	return &ConsumidorSolicitudesKafka{
+		config:               config,
+		envio:                envio,
+		claves:               claves,
+		repositorioInquilino: repoInquilino,
+		logger:               log,
+	}
+}
+
+// Iniciar lanza los lectores del grupo hasta que ctx termine
+func (c *ConsumidorSolicitudesKafka) Iniciar(ctx context.Context) {
+	for i := 0; i < c.config.Consumidores; i++ {
+		c.grupo.Add(1)
+		go c.leer(ctx)
+	}
+	c.logger.Info("Consumiendo solicitudes de Kafka",
+		"tema", c.config.TemaSolicitudes,
+		"grupo", c.config.Grupo,
+		"lectores", c.config.Consumidores,
+	)
+}
+
+// Esperar bloquea hasta que los lectores terminen
+func (c *ConsumidorSolicitudesKafka) Esperar() {
+	c.grupo.Wait()
+}
+
+func (c *ConsumidorSolicitudesKafka) leer(ctx context.Context) {
+	defer c.grupo.Done()
+
+	lector := kafkago.NewReader(kafkago.ReaderConfig{
+		Brokers:  c.config.Brokers,
+		GroupID:  c.config.Grupo,
+		Topic:    c.config.TemaSolicitudes,
+		MaxBytes: 10 << 20,
+	})
+	defer lector.Close()
+
+	for {
+		mensaje, err := lector.FetchMessage(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			c.logger.Error("Error leyendo solicitudes de Kafka", "tema", c.config.TemaSolicitudes, "error", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(esperaReintento):
+			}
+			continue
+		}
+		c.procesar(ctx, mensaje)
+		if err := lector.CommitMessages(ctx, mensaje); err != nil && ctx.Err() == nil {
+			c.logger.Warn("Error confirmando el offset de la solicitud", "particion", mensaje.Partition, "offset", mensaje.Offset, "error", err)
+		}
+	}
+}
+
+// procesar crea la notificación de la solicitud, reintentando los errores transitorios con
+// espera exponencial
+func (c *ConsumidorSolicitudesKafka) procesar(ctx context.Context, mensaje kafkago.Message) {
+	espera := esperaReintento
+	for intento := 1; ; intento++ {
+		err := c.crear(ctx, mensaje)
+		if err == nil {
+			metricaSolicitudes.WithLabelValues("creada").Inc()
+			return
+		}
+		if !esTransitorio(err) || intento >= c.config.ReintentosSolicitud {
+			metricaSolicitudes.WithLabelValues("rechazada").Inc()
+			c.logger.Warn("Solicitud de Kafka rechazada",
+				"particion", mensaje.Partition,
+				"offset", mensaje.Offset,
+				"intentos", intento,
+				"error", err,
+			)
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(espera):
+		}
+		espera *= 2
+	}
+}
+
+func (c *ConsumidorSolicitudesKafka) crear(ctx context.Context, mensaje kafkago.Message) error {
+	clave, err := c.claves.Autenticar(ctx, encabezado(mensaje, encabezadoClaveAPI))
+	if err != nil {
+		return err
+	}
+	ctx = servicio.ContextoConClaveAPI(ctx, clave)
+	if !clave.EsAdminPlataforma() {
+		if ctx, err = servicio.ContextoDeInquilino(ctx, c.repositorioInquilino, clave.InquilinoID); err != nil {
+			return err
+		}
+	}
+
+	// Las mismas reglas que el binding de la API REST
+	var solicitud dto.SolicitudEnviarNotificacion
+	err = json.Unmarshal(mensaje.Value, &solicitud)
+	if err == nil {
+		err = binding.Validator.ValidateStruct(&solicitud)
+	}
+	if err != nil {
+		return errorSolicitud(err)
+	}
+	_, _, err = c.envio.Ejecutar(ctx, solicitud)
+	return err
+}
+
+// esTransitorio indica si vale la pena reintentar: los errores de validación, de dominio y las
+// claves inválidas se repetirían igual
+func esTransitorio(err error) bool {
+	var errValidacion *entidad.ErrorValidacion
+	var errDominio *entidad.ErrorDominio
+	var errMetadatos *entidad.ErrorMetadatos
+	switch {
+	case errors.As(err, &errValidacion), errors.As(err, &errDominio), errors.As(err, &errMetadatos):
+		return false
+	case errors.Is(err, entidad.ErrClaveAPINoEncontrada), errors.Is(err, entidad.ErrCuotaMensualExcedida):
+		return false
+	}
+	return true
+}
+
+// errorSolicitud resume los campos inválidos de la solicitud en un error de validación
+func errorSolicitud(err error) error {
+	campos, ok := validacion.Traducir(err, idioma.Predeterminado)
+	if !ok {
+		return entidad.NewErrorValidacion("el mensaje no es una solicitud de envío en JSON")
+	}
+	mensajes := make([]string, 0, len(campos))
+	for _, campo := range campos {
+		mensajes = append(mensajes, campo.Mensaje)
+	}
+	return entidad.NewErrorValidacion(strings.Join(mensajes, "; "))
+}
+
+func encabezado(mensaje kafkago.Message, nombre string) string {
+	for _, h := range mensaje.Headers {
+		if h.Key == nombre {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
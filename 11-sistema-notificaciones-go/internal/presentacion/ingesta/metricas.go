@@ -0,0 +1,11 @@
+package ingesta
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var metricaSolicitudes = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "notificaciones_kafka_solicitudes_total",
+	Help: "Solicitudes de envío consumidas de Kafka por resultado (creada o rechazada)",
+}, []string{"resultado"})
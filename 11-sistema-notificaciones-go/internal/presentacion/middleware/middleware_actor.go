@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/internal/presentacion/problema"
+
+	"github.com/gin-gonic/gin"
+)
+
+// longitudMaximaMotivo acota la justificación que se guarda en el registro de accesos
+const longitudMaximaMotivo = 500
+
+// IdentificarActor asocia la petición a la persona del encabezado X-Actor-ID, que la
+// aplicación cliente envía cuando actúa en nombre de un usuario, y a la justificación de
+// X-Motivo-Acceso. Con Authorization: Bearer y el JWT del propio usuario el actor queda
+// autenticado, lo que exigen acciones como aprobar una difusión. El actor debe pertenecer al
+// inquilino de la petición. Debe ir después de AislarInquilino.
+func IdentificarActor(repoUsuario repositorio.RepositorioUsuario, secretoJWT string) gin.HandlerFunc {
+	secreto := []byte(secretoJWT)
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		if motivo := strings.TrimSpace(c.GetHeader("X-Motivo-Acceso")); motivo != "" {
+			if len(motivo) > longitudMaximaMotivo {
+				problema.Abortar(c, http.StatusBadRequest, "motivo_acceso_invalido", "X-Motivo-Acceso demasiado largo")
+				return
+			}
+			ctx = servicio.ContextoConMotivoAcceso(ctx, motivo)
+		}
+
+		var actorID uint
+		if valor := c.GetHeader("X-Actor-ID"); valor != "" {
+			id, err := strconv.ParseUint(valor, 10, 64)
+			if err != nil || id == 0 {
+				problema.Abortar(c, http.StatusBadRequest, "actor_invalido", "X-Actor-ID inválido")
+				return
+			}
+			actorID = uint(id)
+		}
+		autenticado := false
+		if token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok {
+			inquilinoID, usuarioID, err := ValidarTokenUsuario(secreto, strings.TrimSpace(token))
+			// El token vale solo en su inquilino y, con X-Actor-ID, para ese mismo usuario
+			if err != nil || inquilinoID != servicio.InquilinoDesdeContexto(ctx) || (actorID != 0 && actorID != usuarioID) {
+				problema.Abortar(c, http.StatusUnauthorized, "actor_invalido", "Actor inválido")
+				return
+			}
+			actorID, autenticado = usuarioID, true
+		}
+
+		if actorID != 0 {
+			actor, err := repoUsuario.ObtenerPorID(ctx, actorID)
+			if err == nil {
+				err = servicio.AutorizarInquilino(ctx, actor.InquilinoID)
+			}
+			switch {
+			case errors.Is(err, entidad.ErrUsuarioNoEncontrado), errors.Is(err, entidad.ErrAccesoDenegado):
+				problema.Abortar(c, http.StatusUnauthorized, "actor_invalido", "Actor inválido")
+				return
+			case err != nil:
+				problema.Abortar(c, http.StatusInternalServerError, "error_interno", "Error interno del servidor")
+				return
+			}
+			if autenticado {
+				ctx = servicio.ContextoConActorAutenticado(ctx, actor)
+			} else {
+				ctx = servicio.ContextoConActor(ctx, actor)
+			}
+		}
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/internal/presentacion/problema"
+
+	"github.com/gin-gonic/gin"
+)
+
+// tokenAdmin representa al token X-Admin-Token, que equivale a una clave de plataforma
+var tokenAdmin = &entidad.ClaveAPI{Nombre: "token-admin", Rol: entidad.RolAdminPlataforma}
+
+// AutenticacionAdmin protege rutas administrativas: acepta una clave de API ya autenticada
+// (ver AutenticacionClaveAPI) o el token X-Admin-Token
+func AutenticacionAdmin(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := servicio.ClaveAPIDesdeContexto(c.Request.Context()); ok {
+			c.Next()
+			return
+		}
+
+		recibido := c.GetHeader("X-Admin-Token")
+		if token == "" || subtle.ConstantTimeCompare([]byte(recibido), []byte(token)) != 1 {
+			problema.Abortar(c, http.StatusUnauthorized, "no_autorizado", "No autorizado")
+			return
+		}
+		c.Request = c.Request.WithContext(servicio.ContextoConClaveAPI(c.Request.Context(), tokenAdmin))
+		c.Next()
+	}
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"sistema-notificaciones-go/internal/infraestructura/caos"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Caos demora las peticiones con la tasa de latencia del inyector, para ensayar timeouts y
+// reintentos de los clientes. Si el cliente abandona durante la espera la petición no se procesa.
+func Caos(inyector *caos.Inyector) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if err := inyector.Demorar(ctx.Request.Context(), "http"); err != nil {
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
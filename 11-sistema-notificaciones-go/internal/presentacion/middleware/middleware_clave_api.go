@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/internal/presentacion/problema"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AutenticacionClaveAPI exige la cabecera X-API-Key o, en su lugar, el token X-Admin-Token, que
+// equivale a una clave de plataforma. Una clave de inquilino fija el inquilino de la solicitud;
+// una de plataforma no lo restringe. Sin credenciales la solicitud se rechaza.
+func AutenticacionClaveAPI(casoUsoClaves *casoUso.CasoUsoClavesAPI, token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		valor := c.GetHeader("X-API-Key")
+		if valor == "" {
+			recibido := c.GetHeader("X-Admin-Token")
+			if recibido == "" || token == "" || subtle.ConstantTimeCompare([]byte(recibido), []byte(token)) != 1 {
+				problema.Abortar(c, http.StatusUnauthorized, "clave_api_requerida", "Clave de API requerida")
+				return
+			}
+			c.Request = c.Request.WithContext(servicio.ContextoConClaveAPI(c.Request.Context(), tokenAdmin))
+			c.Next()
+			return
+		}
+
+		clave, err := casoUsoClaves.Autenticar(c.Request.Context(), valor)
+		switch {
+		case errors.Is(err, entidad.ErrClaveAPINoEncontrada):
+			problema.Abortar(c, http.StatusUnauthorized, "clave_api_invalida", "Clave de API inválida")
+			return
+		case err != nil:
+			problema.Abortar(c, http.StatusInternalServerError, "error_interno", "Error interno del servidor")
+			return
+		}
+
+		ctx := servicio.ContextoConClaveAPI(c.Request.Context(), clave)
+		if !clave.EsAdminPlataforma() {
+			ctx = servicio.ContextoConInquilino(ctx, clave.InquilinoID)
+		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// RequerirAdminPlataforma restringe la ruta a claves de plataforma (o al token de administración)
+func RequerirAdminPlataforma() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clave, ok := servicio.ClaveAPIDesdeContexto(c.Request.Context())
+		if !ok || !clave.EsAdminPlataforma() {
+			problema.Abortar(c, http.StatusForbidden, "requiere_admin_plataforma", "Requiere administrador de plataforma")
+			return
+		}
+		c.Next()
+	}
+}
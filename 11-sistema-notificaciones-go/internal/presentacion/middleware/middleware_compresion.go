@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"sistema-notificaciones-go/internal/infraestructura/configuracion"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+// tiposComprimibles son los prefijos de Content-Type que vale la pena comprimir
+var tiposComprimibles = []string{"application/json", "application/problem+json", "text/"}
+
+// Compresion comprime con brotli o gzip las respuestas que superan el umbral configurado.
+// Se aplica por grupo de rutas; las respuestas en flujo se comprimen desde el primer Flush.
+func Compresion(config configuracion.ConfiguracionCompresion) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		codificacion := negociarCodificacion(c.GetHeader("Accept-Encoding"), config.Brotli)
+		if codificacion == "" || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		escritor := &escritorCompresion{
+			ResponseWriter: c.Writer,
+			codificacion:   codificacion,
+			umbral:         config.UmbralBytes,
+			nivel:          config.Nivel,
+		}
+		c.Writer = escritor
+		c.Header("Vary", "Accept-Encoding")
+
+		c.Next()
+
+		escritor.finalizar()
+		c.Writer = escritor.ResponseWriter
+	}
+}
+
+// negociarCodificacion elige la codificación preferida soportada por el cliente
+func negociarCodificacion(aceptadas string, brotliHabilitado bool) string {
+	var gzipAceptado bool
+	for _, parte := range strings.Split(aceptadas, ",") {
+		nombre, parametros, _ := strings.Cut(strings.TrimSpace(parte), ";")
+		if calidad, definida := strings.CutPrefix(strings.TrimSpace(parametros), "q="); definida {
+			if valor, err := strconv.ParseFloat(calidad, 64); err == nil && valor == 0 {
+				continue
+			}
+		}
+		switch strings.TrimSpace(nombre) {
+		case "br":
+			if brotliHabilitado {
+				return "br"
+			}
+		case "gzip":
+			gzipAceptado = true
+		}
+	}
+	if gzipAceptado {
+		return "gzip"
+	}
+	return ""
+}
+
+// escritorCompresion acumula la respuesta hasta decidir si comprimirla
+type escritorCompresion struct {
+	gin.ResponseWriter
+	codificacion string
+	umbral       int
+	nivel        int
+
+	buffer      bytes.Buffer
+	decidido    bool
+	codificador io.WriteCloser
+}
+
+func (e *escritorCompresion) Write(datos []byte) (int, error) {
+	if e.decidido {
+		if e.codificador != nil {
+			return e.codificador.Write(datos)
+		}
+		return e.ResponseWriter.Write(datos)
+	}
+
+	e.buffer.Write(datos)
+	if e.buffer.Len() >= e.umbral {
+		if err := e.decidir(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(datos), nil
+}
+
+func (e *escritorCompresion) WriteString(datos string) (int, error) {
+	return e.Write([]byte(datos))
+}
+
+// Flush compromete la decisión (una respuesta en flujo se asume grande) y vacía el codificador
+func (e *escritorCompresion) Flush() {
+	if !e.decidido {
+		_ = e.decidir(true)
+	}
+	if flusher, ok := e.codificador.(interface{ Flush() error }); ok {
+		_ = flusher.Flush()
+	}
+	e.ResponseWriter.Flush()
+}
+
+// decidir fija si la respuesta se comprime y escribe lo acumulado
+func (e *escritorCompresion) decidir(superaUmbral bool) error {
+	e.decidido = true
+
+	if superaUmbral && e.comprimible() {
+		e.Header().Set("Content-Encoding", e.codificacion)
+		e.Header().Del("Content-Length")
+		e.codificador = e.nuevoCodificador()
+		_, err := e.codificador.Write(e.buffer.Bytes())
+		e.buffer.Reset()
+		return err
+	}
+
+	_, err := e.ResponseWriter.Write(e.buffer.Bytes())
+	e.buffer.Reset()
+	return err
+}
+
+func (e *escritorCompresion) comprimible() bool {
+	if e.Header().Get("Content-Encoding") != "" {
+		return false
+	}
+	estado := e.ResponseWriter.Status()
+	if estado == http.StatusNoContent || estado == http.StatusNotModified {
+		return false
+	}
+	tipo := e.Header().Get("Content-Type")
+	for _, prefijo := range tiposComprimibles {
+		if strings.HasPrefix(tipo, prefijo) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *escritorCompresion) nuevoCodificador() io.WriteCloser {
+	if e.codificacion == "br" {
+		return brotli.NewWriterLevel(e.ResponseWriter, e.nivel)
+	}
+	codificador, err := gzip.NewWriterLevel(e.ResponseWriter, e.nivel)
+	if err != nil {
+		codificador = gzip.NewWriter(e.ResponseWriter)
+	}
+	return codificador
+}
+
+// finalizar escribe la respuesta pequeña sin comprimir o cierra el codificador
+func (e *escritorCompresion) finalizar() {
+	if !e.decidido {
+		if e.buffer.Len() == 0 {
+			return
+		}
+		_ = e.decidir(false)
+		return
+	}
+	if e.codificador != nil {
+		_ = e.codificador.Close()
+	}
+}
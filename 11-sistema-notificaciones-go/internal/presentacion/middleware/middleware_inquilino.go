@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/repositorio"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/internal/presentacion/problema"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IdentificarInquilino fija el inquilino de la petición a partir de la credencial autenticada:
+// una clave de inquilino lo determina y el encabezado X-Inquilino-ID, si se envía, debe
+// coincidir con ella. Solo una clave de plataforma (o el token de administración) puede elegir
+// el inquilino con el encabezado; sin él la petición corresponde a la plataforma. Debe ir
+// después de AutenticacionClaveAPI.
+func IdentificarInquilino() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clave, ok := servicio.ClaveAPIDesdeContexto(c.Request.Context())
+		if !ok {
+			problema.Abortar(c, http.StatusUnauthorized, "clave_api_requerida", "Clave de API requerida")
+			return
+		}
+
+		valor := c.GetHeader("X-Inquilino-ID")
+		if valor == "" {
+			if !clave.EsAdminPlataforma() {
+				// Una clave de inquilino nunca opera como plataforma
+				c.Request = c.Request.WithContext(servicio.ContextoConInquilino(c.Request.Context(), clave.InquilinoID))
+			}
+			c.Next()
+			return
+		}
+
+		id, err := strconv.ParseUint(valor, 10, 64)
+		if err != nil || id == 0 {
+			problema.Abortar(c, http.StatusBadRequest, "inquilino_invalido", "X-Inquilino-ID inválido")
+			return
+		}
+		if !clave.EsAdminPlataforma() {
+			if clave.InquilinoID != uint(id) {
+				problema.Abortar(c, http.StatusForbidden, "acceso_denegado", entidad.ErrAccesoDenegado.Error())
+				return
+			}
+			c.Request = c.Request.WithContext(servicio.ContextoConInquilino(c.Request.Context(), clave.InquilinoID))
+			c.Next()
+			return
+		}
+		c.Request = c.Request.WithContext(servicio.ContextoConInquilino(c.Request.Context(), uint(id)))
+		c.Next()
+	}
+}
+
+// AislarInquilino dirige las consultas de la petición al esquema del inquilino cuando
+// este tiene aislamiento por esquema. Debe ir después de IdentificarInquilino.
+func AislarInquilino(repoInquilino repositorio.RepositorioInquilino) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, err := servicio.ContextoDeInquilino(c.Request.Context(), repoInquilino, servicio.InquilinoDesdeContexto(c.Request.Context()))
+		if errors.Is(err, entidad.ErrInquilinoNoEncontrado) {
+			problema.Abortar(c, http.StatusNotFound, "inquilino_no_encontrado", err.Error())
+			return
+		}
+		if err != nil {
+			problema.Abortar(c, http.StatusInternalServerError, "error_interno", "Error interno del servidor")
+			return
+		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
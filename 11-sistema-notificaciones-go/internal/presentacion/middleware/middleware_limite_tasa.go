@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"sistema-notificaciones-go/internal/aplicacion/casoUso"
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/dominio/servicio"
+	"sistema-notificaciones-go/internal/presentacion/problema"
+	"sistema-notificaciones-go/pkg/reloj"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LimitarSolicitudes aplica el límite de solicitudes por cliente (clave de API, inquilino o IP)
+// e informa en la respuesta X-RateLimit-Limit, X-RateLimit-Remaining y X-RateLimit-Reset
+// (segundos hasta la próxima ventana) del límite más restrictivo evaluado en la solicitud,
+// incluidos los límites de envío del inquilino. Debe ir después de IdentificarInquilino.
+func LimitarSolicitudes(casoUsoLimite *casoUso.CasoUsoLimitarSolicitudes, rel reloj.Reloj) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, registro := servicio.ContextoConRegistroLimites(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+		escritor := &escritorLimite{ResponseWriter: c.Writer, registro: registro, reloj: rel}
+		c.Writer = escritor
+
+		if err := casoUsoLimite.Consumir(ctx, clienteLimite(c)); errors.Is(err, entidad.ErrLimiteSolicitudesExcedido) {
+			if estado, ok := registro.Estado(); ok {
+				c.Header("Retry-After", strconv.FormatInt(segundosHasta(estado, rel), 10))
+			}
+			problema.Abortar(c, http.StatusTooManyRequests, "limite_solicitudes_excedido", err.Error())
+		} else {
+			c.Next()
+		}
+
+		// Las respuestas sin cuerpo escriben los encabezados al terminar la cadena
+		escritor.publicar()
+		c.Writer = escritor.ResponseWriter
+	}
+}
+
+// clienteLimite identifica a quién se le cuenta la solicitud
+func clienteLimite(c *gin.Context) string {
+	if clave, ok := servicio.ClaveAPIDesdeContexto(c.Request.Context()); ok {
+		return "clave:" + clave.Prefijo
+	}
+	if inquilinoID := servicio.InquilinoDesdeContexto(c.Request.Context()); inquilinoID != 0 {
+		return "inquilino:" + strconv.FormatUint(uint64(inquilinoID), 10)
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// escritorLimite agrega los encabezados del límite justo antes de escribir la respuesta,
+// cuando ya se evaluaron los límites de envío del caso de uso
+type escritorLimite struct {
+	gin.ResponseWriter
+	registro  *servicio.RegistroLimites
+	reloj     reloj.Reloj
+	publicado bool
+}
+
+func (e *escritorLimite) WriteHeaderNow() {
+	e.publicar()
+	e.ResponseWriter.WriteHeaderNow()
+}
+
+func (e *escritorLimite) Write(datos []byte) (int, error) {
+	e.publicar()
+	return e.ResponseWriter.Write(datos)
+}
+
+func (e *escritorLimite) WriteString(datos string) (int, error) {
+	e.publicar()
+	return e.ResponseWriter.WriteString(datos)
+}
+
+func (e *escritorLimite) Flush() {
+	e.publicar()
+	e.ResponseWriter.Flush()
+}
+
+// publicar fija los encabezados una sola vez y solo si la respuesta no se escribió todavía
+func (e *escritorLimite) publicar() {
+	if e.publicado || e.Written() {
+		return
+	}
+	e.publicado = true
+	estado, ok := e.registro.Estado()
+	if !ok {
+		return
+	}
+	encabezados := e.Header()
+	encabezados.Set("X-RateLimit-Limit", strconv.FormatInt(estado.Limite, 10))
+	encabezados.Set("X-RateLimit-Remaining", strconv.FormatInt(estado.Restantes, 10))
+	encabezados.Set("X-RateLimit-Reset", strconv.FormatInt(segundosHasta(estado, e.reloj), 10))
+}
+
+// segundosHasta redondea hacia arriba la espera hasta la próxima ventana del límite
+func segundosHasta(estado entidad.EstadoLimite, rel reloj.Reloj) int64 {
+	espera := estado.Reinicio.Sub(rel.Ahora()).Seconds()
+	if espera < 0 {
+		return 0
+	}
+	return int64(math.Ceil(espera))
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"sistema-notificaciones-go/internal/presentacion/problema"
+	"sistema-notificaciones-go/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Logger registra cada petición HTTP con el componente "http"
+func Logger(log *logger.Logger) gin.HandlerFunc {
+	logHTTP := log.Componente(logger.ComponenteHTTP)
+
+	return func(c *gin.Context) {
+		inicio := time.Now()
+		c.Next()
+
+		campos := []any{
+			"metodo", c.Request.Method,
+			"ruta", c.FullPath(),
+			"estado", c.Writer.Status(),
+			"duracion_ms", time.Since(inicio).Milliseconds(),
+			"ip", c.ClientIP(),
+		}
+
+		switch {
+		case c.Writer.Status() >= 500:
+			logHTTP.Error("Petición HTTP", campos...)
+		case c.Writer.Status() >= 400:
+			logHTTP.Warn("Petición HTTP", campos...)
+		default:
+			logHTTP.Debug("Petición HTTP", campos...)
+		}
+	}
+}
+
+// Recovery recupera pánicos en los handlers y responde 500
+func Recovery(log *logger.Logger) gin.HandlerFunc {
+	logHTTP := log.Componente(logger.ComponenteHTTP)
+
+	return func(c *gin.Context) {
+		defer func() {
+			if recuperado := recover(); recuperado != nil {
+				logHTTP.Error("Pánico en handler", "error", recuperado, "ruta", c.FullPath())
+				problema.Abortar(c, http.StatusInternalServerError, "error_interno", "Error interno del servidor")
+			}
+		}()
+		c.Next()
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Obsoleta anuncia en cada respuesta que la ruta está obsoleta desde la fecha (Deprecation,
+// RFC 9745), cuándo dejará de responder si hay retiro previsto (Sunset, RFC 8594) y la ruta
+// equivalente de la versión que la reemplaza (Link con rel="successor-version")
+func Obsoleta(desde, retiro time.Time, prefijo, prefijoReemplazo string) gin.HandlerFunc {
+	deprecacion := fmt.Sprintf("@%d", desde.Unix())
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecacion)
+		if !retiro.IsZero() {
+			c.Header("Sunset", retiro.UTC().Format(http.TimeFormat))
+		}
+		reemplazo := prefijoReemplazo + strings.TrimPrefix(c.Request.URL.Path, prefijo)
+		c.Header("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", reemplazo))
+		c.Next()
+	}
+}
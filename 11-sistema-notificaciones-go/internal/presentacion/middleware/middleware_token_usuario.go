@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// claimsUsuario son los claims del JWT de un usuario: "sub" es su ID e "inquilino_id" su
+// inquilino, que sin el claim es la plataforma
+type claimsUsuario struct {
+	jwt.RegisteredClaims
+	InquilinoID uint `json:"inquilino_id"`
+}
+
+// ValidarTokenUsuario valida el JWT HS256 de un usuario y retorna su inquilino y su ID
+func ValidarTokenUsuario(secreto []byte, token string) (uint, uint, error) {
+	claims := claimsUsuario{}
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return secreto, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	usuarioID, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil || usuarioID == 0 {
+		return 0, 0, jwt.ErrTokenInvalidSubject
+	}
+	return claims.InquilinoID, uint(usuarioID), nil
+}
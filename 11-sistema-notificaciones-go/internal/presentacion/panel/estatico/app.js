@@ -0,0 +1,207 @@
+"use strict";
+
+// El panel no guarda datos propios: todo sale de /api/v1/admin con el token de la sesión
+const API = "/api/v1/admin";
+const CLAVE_TOKEN = "panel.token";
+const CLAVE_CABECERA = "panel.cabecera";
+
+let cursorFallidas = null;
+
+function credenciales() {
+  return {
+    token: sessionStorage.getItem(CLAVE_TOKEN) || "",
+    cabecera: sessionStorage.getItem(CLAVE_CABECERA) || "X-Admin-Token",
+  };
+}
+
+function mostrarEstado(texto, esError) {
+  const estado = document.getElementById("estado");
+  estado.textContent = texto;
+  estado.classList.toggle("error", Boolean(esError));
+}
+
+async function llamar(ruta, opciones = {}) {
+  const { token, cabecera } = credenciales();
+  if (!token) {
+    throw new Error("Ingrese el token de administración");
+  }
+  const cabeceras = { [cabecera]: token };
+  if (opciones.cuerpo !== undefined) {
+    cabeceras["Content-Type"] = "application/json";
+  }
+  const respuesta = await fetch(API + ruta, {
+    method: opciones.metodo || "GET",
+    headers: cabeceras,
+    body: opciones.cuerpo !== undefined ? JSON.stringify(opciones.cuerpo) : undefined,
+    credentials: "omit",
+  });
+  const datos = await respuesta.json().catch(() => ({}));
+  if (!respuesta.ok) {
+    throw new Error(datos.error || `Error ${respuesta.status}`);
+  }
+  return datos;
+}
+
+function celda(fila, texto) {
+  const td = document.createElement("td");
+  td.textContent = texto === undefined || texto === null ? "" : String(texto);
+  fila.appendChild(td);
+  return td;
+}
+
+function leerJSON(texto) {
+  if (!texto.trim()) {
+    return undefined;
+  }
+  try {
+    return JSON.parse(texto);
+  } catch (e) {
+    throw new Error("Los datos no son JSON válido");
+  }
+}
+
+async function cargarColas() {
+  const { colas } = await llamar("/cola");
+  const cuerpo = document.querySelector("#colas tbody");
+  cuerpo.replaceChildren();
+  for (const cola of colas) {
+    const fila = document.createElement("tr");
+    celda(fila, cola.prioridad);
+    celda(fila, cola.en_cola);
+    celda(fila, cola.capacidad);
+    const barra = document.createElement("div");
+    barra.className = "barra";
+    const relleno = document.createElement("span");
+    const porcentaje = cola.capacidad > 0 ? Math.round((cola.en_cola / cola.capacidad) * 100) : 0;
+    relleno.style.width = porcentaje + "%";
+    barra.appendChild(relleno);
+    celda(fila, "").appendChild(barra);
+    cuerpo.appendChild(fila);
+  }
+}
+
+async function cargarFallidas(continuar) {
+  const parametros = new URLSearchParams();
+  if (document.getElementById("solo-agotadas").checked) {
+    parametros.set("agotadas", "true");
+  }
+  const tipo = document.getElementById("fallidas-tipo").value.trim();
+  if (tipo) {
+    parametros.set("tipo", tipo);
+  }
+  if (continuar && cursorFallidas) {
+    parametros.set("cursor", cursorFallidas);
+  }
+
+  const datos = await llamar("/notificaciones/fallidas?" + parametros.toString());
+  const cuerpo = document.querySelector("#fallidas tbody");
+  if (!continuar) {
+    cuerpo.replaceChildren();
+  }
+  for (const fallida of datos.fallidas) {
+    const fila = document.createElement("tr");
+    celda(fila, fallida.id);
+    celda(fila, fallida.inquilino_id);
+    celda(fila, fallida.usuario_id);
+    celda(fila, fallida.tipo);
+    celda(fila, fallida.prioridad);
+    celda(fila, `${fallida.intentos_envio}/${fallida.max_intentos}`);
+    celda(fila, new Date(fallida.fecha_creacion).toLocaleString());
+    celda(fila, fallida.ultimo_error).className = "error-envio";
+    cuerpo.appendChild(fila);
+  }
+  cursorFallidas = datos.siguiente_cursor || null;
+  document.getElementById("mas-fallidas").hidden = !cursorFallidas;
+}
+
+async function vistaPrevia(evento) {
+  evento.preventDefault();
+  const formulario = new FormData(evento.target);
+  const solicitud = {
+    plantilla: formulario.get("plantilla"),
+    tipo: formulario.get("tipo"),
+    datos: leerJSON(formulario.get("datos")),
+  };
+  const inquilino = Number(formulario.get("inquilino_id"));
+  if (inquilino) {
+    solicitud.inquilino_id = inquilino;
+  }
+
+  const contenido = await llamar("/plantillas/vista-previa", { metodo: "POST", cuerpo: solicitud });
+  const resultado = document.getElementById("resultado-vista-previa");
+  resultado.replaceChildren();
+  const asunto = document.createElement("strong");
+  asunto.textContent = contenido.asunto;
+  resultado.appendChild(asunto);
+  // El cuerpo puede ser HTML: se muestra en un iframe aislado, sin scripts
+  const marco = document.createElement("iframe");
+  marco.setAttribute("sandbox", "");
+  marco.srcdoc = contenido.cuerpo;
+  resultado.appendChild(marco);
+}
+
+async function envioManual(evento) {
+  evento.preventDefault();
+  const formulario = new FormData(evento.target);
+  const solicitud = {
+    usuario_id: Number(formulario.get("usuario_id")),
+    tipo: formulario.get("tipo"),
+    prioridad: formulario.get("prioridad"),
+  };
+  for (const campo of ["titulo", "mensaje", "plantilla"]) {
+    const valor = formulario.get(campo).trim();
+    if (valor) {
+      solicitud[campo] = valor;
+    }
+  }
+  const datos = leerJSON(formulario.get("datos"));
+  if (datos) {
+    solicitud.datos = datos;
+  }
+
+  const simular = evento.submitter && evento.submitter.value === "simular";
+  const ruta = simular ? "/notificaciones/simular" : "/notificaciones";
+  const respuesta = await llamar(ruta, { metodo: "POST", cuerpo: solicitud });
+  document.getElementById("resultado-envio").textContent = JSON.stringify(respuesta, null, 2);
+}
+
+// conError muestra en la barra de estado cualquier error de la acción
+function conError(accion) {
+  return async (...argumentos) => {
+    try {
+      await accion(...argumentos);
+      mostrarEstado("", false);
+    } catch (e) {
+      mostrarEstado(e.message, true);
+    }
+  };
+}
+
+async function refrescar() {
+  await cargarColas();
+  await cargarFallidas(false);
+}
+
+document.getElementById("acceso").addEventListener("submit", conError(async (evento) => {
+  evento.preventDefault();
+  sessionStorage.setItem(CLAVE_TOKEN, document.getElementById("token").value.trim());
+  sessionStorage.setItem(CLAVE_CABECERA, document.getElementById("tipo-token").value);
+  document.getElementById("token").value = "";
+  await refrescar();
+}));
+document.getElementById("salir").addEventListener("click", () => {
+  sessionStorage.removeItem(CLAVE_TOKEN);
+  sessionStorage.removeItem(CLAVE_CABECERA);
+  document.querySelector("#colas tbody").replaceChildren();
+  document.querySelector("#fallidas tbody").replaceChildren();
+  mostrarEstado("Sesión cerrada", false);
+});
+document.getElementById("actualizar-colas").addEventListener("click", conError(cargarColas));
+document.getElementById("buscar-fallidas").addEventListener("click", conError(() => cargarFallidas(false)));
+document.getElementById("mas-fallidas").addEventListener("click", conError(() => cargarFallidas(true)));
+document.getElementById("vista-previa").addEventListener("submit", conError(vistaPrevia));
+document.getElementById("envio-manual").addEventListener("submit", conError(envioManual));
+
+if (credenciales().token) {
+  conError(refrescar)();
+}
@@ -0,0 +1,37 @@
+// Package panel sirve el panel de administración embebido en el binario
+package panel
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed estatico
+var archivos embed.FS
+
+// Registrar sirve el panel en /admin. Los archivos son estáticos y no contienen datos: el
+// panel pide el token de administración (o una clave de API) y lo envía en cada llamada a
+// /api/v1/admin, donde se aplica la autenticación administrativa.
+func Registrar(router *gin.Engine) error {
+	estatico, err := fs.Sub(archivos, "estatico")
+	if err != nil {
+		return err
+	}
+	grupo := router.Group("/admin")
+	grupo.Use(cabecerasSeguridad)
+	grupo.StaticFS("/", http.FS(estatico))
+	return nil
+}
+
+// cabecerasSeguridad impide incrustar el panel en otros sitios y cargar scripts externos
+func cabecerasSeguridad(c *gin.Context) {
+	c.Header("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+	c.Header("X-Frame-Options", "DENY")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Referrer-Policy", "no-referrer")
+	c.Header("Cache-Control", "no-store")
+	c.Next()
+}
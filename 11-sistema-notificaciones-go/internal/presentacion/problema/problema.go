@@ -0,0 +1,69 @@
+package problema
+
+import (
+	"net/http"
+
+	"sistema-notificaciones-go/internal/presentacion/idioma"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TipoContenido es el media type de las respuestas de error (RFC 7807)
+const TipoContenido = "application/problem+json"
+
+// prefijoTipo forma el URI que identifica cada tipo de problema a partir de su código
+const prefijoTipo = "urn:sistema-notificaciones:problema:"
+
+// Problema es el cuerpo de toda respuesta de error. Codigo identifica el error de forma
+// estable para que los clientes lo interpreten; Detalle explica esta ocurrencia en particular
+// en el idioma que pidió el cliente.
+type Problema struct {
+	Tipo      string `json:"type"`
+	Titulo    string `json:"title"`
+	Estado    int    `json:"status"`
+	Detalle   string `json:"detail,omitempty"`
+	Instancia string `json:"instance,omitempty"`
+	Codigo    string `json:"codigo"`
+	// Campos detalla los campos inválidos de los errores de validación
+	Campos any `json:"campos,omitempty"`
+}
+
+// Nuevo crea el problema con el título estándar del estado HTTP
+func Nuevo(estado int, codigo, detalle string) *Problema {
+	return &Problema{
+		Tipo:    prefijoTipo + codigo,
+		Titulo:  http.StatusText(estado),
+		Estado:  estado,
+		Detalle: detalle,
+		Codigo:  codigo,
+	}
+}
+
+// ConCampos agrega los campos inválidos
+func (p *Problema) ConCampos(campos any) *Problema {
+	p.Campos = campos
+	return p
+}
+
+// Escribir responde el problema como application/problem+json con la ruta como instancia y el
+// detalle traducido según Accept-Language
+func Escribir(ctx *gin.Context, p *Problema) {
+	p.Instancia = ctx.Request.URL.Path
+	idiomaSolicitud := idioma.Negociar(ctx.GetHeader("Accept-Language"))
+	p.Detalle = idioma.Traducir(idiomaSolicitud, p.Detalle)
+	ctx.Header("Content-Language", string(idiomaSolicitud))
+	// ctx.JSON respeta el Content-Type ya fijado
+	ctx.Header("Content-Type", TipoContenido)
+	ctx.JSON(p.Estado, p)
+}
+
+// Responder responde un problema sin campos
+func Responder(ctx *gin.Context, estado int, codigo, detalle string) {
+	Escribir(ctx, Nuevo(estado, codigo, detalle))
+}
+
+// Abortar responde el problema y corta la cadena de middlewares
+func Abortar(ctx *gin.Context, estado int, codigo, detalle string) {
+	ctx.Abort()
+	Responder(ctx, estado, codigo, detalle)
+}
@@ -0,0 +1,237 @@
+package validacion
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/presentacion/idioma"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// ErrorCampo describe un campo inválido de la solicitud. Mensaje está en el idioma que pidió el
+// cliente; MensajeEN se mantiene siempre en inglés.
+type ErrorCampo struct {
+	Campo     string `json:"campo"`
+	Regla     string `json:"regla"`
+	Mensaje   string `json:"mensaje"`
+	MensajeEN string `json:"mensaje_en"`
+}
+
+// mensajes por regla en cada idioma; %s es el campo y %v el parámetro de la regla
+var mensajes = map[string]map[idioma.Idioma]string{
+	"required": {
+		idioma.ES: "El campo %s es obligatorio",
+		idioma.EN: "Field %s is required",
+		idioma.PT: "O campo %s é obrigatório",
+	},
+	"min": {
+		idioma.ES: "El campo %s debe tener al menos %v elementos o caracteres",
+		idioma.EN: "Field %s must have at least %v items or characters",
+		idioma.PT: "O campo %s deve ter pelo menos %v elementos ou caracteres",
+	},
+	"max": {
+		idioma.ES: "El campo %s admite como máximo %v elementos o caracteres",
+		idioma.EN: "Field %s allows at most %v items or characters",
+		idioma.PT: "O campo %s admite no máximo %v elementos ou caracteres",
+	},
+	"gte": {
+		idioma.ES: "El campo %s debe ser mayor o igual a %v",
+		idioma.EN: "Field %s must be greater than or equal to %v",
+		idioma.PT: "O campo %s deve ser maior ou igual a %v",
+	},
+	"lte": {
+		idioma.ES: "El campo %s debe ser menor o igual a %v",
+		idioma.EN: "Field %s must be less than or equal to %v",
+		idioma.PT: "O campo %s deve ser menor ou igual a %v",
+	},
+	"gt": {
+		idioma.ES: "El campo %s debe ser mayor a %v",
+		idioma.EN: "Field %s must be greater than %v",
+		idioma.PT: "O campo %s deve ser maior que %v",
+	},
+	"oneof": {
+		idioma.ES: "El campo %s debe ser uno de: %v",
+		idioma.EN: "Field %s must be one of: %v",
+		idioma.PT: "O campo %s deve ser um de: %v",
+	},
+	"email": {
+		idioma.ES: "El campo %s debe ser un correo electrónico válido",
+		idioma.EN: "Field %s must be a valid email address",
+		idioma.PT: "O campo %s deve ser um e-mail válido",
+	},
+	"url": {
+		idioma.ES: "El campo %s debe ser una URL válida",
+		idioma.EN: "Field %s must be a valid URL",
+		idioma.PT: "O campo %s deve ser uma URL válida",
+	},
+	"hexcolor": {
+		idioma.ES: "El campo %s debe ser un color con formato #RRGGBB",
+		idioma.EN: "Field %s must be a color in #RRGGBB format",
+		idioma.PT: "O campo %s deve ser uma cor no formato #RRGGBB",
+	},
+	"required_without": {
+		idioma.ES: "El campo %s es obligatorio si no se indica %v",
+		idioma.EN: "Field %s is required when %v is not present",
+		idioma.PT: "O campo %s é obrigatório quando %v não é informado",
+	},
+	"timezone": {
+		idioma.ES: "El campo %s debe ser una zona horaria IANA válida",
+		idioma.EN: "Field %s must be a valid IANA time zone",
+		idioma.PT: "O campo %s deve ser um fuso horário IANA válido",
+	},
+	"tipo_notificacion": {
+		idioma.ES: "El campo %s no es un tipo de notificación soportado",
+		idioma.EN: "Field %s is not a supported notification type",
+		idioma.PT: "O campo %s não é um tipo de notificação suportado",
+	},
+	"prioridad": {
+		idioma.ES: "El campo %s debe ser baja, normal, alta o critica",
+		idioma.EN: "Field %s must be baja, normal, alta or critica",
+		idioma.PT: "O campo %s deve ser baja, normal, alta ou critica",
+	},
+	"duracion": {
+		idioma.ES: "El campo %s debe ser una duración válida (p. ej. 10m)",
+		idioma.EN: "Field %s must be a valid duration (e.g. 10m)",
+		idioma.PT: "O campo %s deve ser uma duração válida (p. ex. 10m)",
+	},
+	"hora": {
+		idioma.ES: "El campo %s debe tener formato HH:MM",
+		idioma.EN: "Field %s must use the HH:MM format",
+		idioma.PT: "O campo %s deve ter o formato HH:MM",
+	},
+	"tipo_dato": {
+		idioma.ES: "El campo %s tiene un tipo de dato inválido",
+		idioma.EN: "Field %s has an invalid data type",
+		idioma.PT: "O campo %s tem um tipo de dado inválido",
+	},
+	"json": {
+		idioma.ES: "El cuerpo de la solicitud no es JSON válido",
+		idioma.EN: "Request body is not valid JSON",
+		idioma.PT: "O corpo da solicitação não é um JSON válido",
+	},
+}
+
+var tiposNotificacion = map[entidad.TipoNotificacion]bool{
+	entidad.TipoEmail:     true,
+	entidad.TipoSMS:       true,
+	entidad.TipoPush:      true,
+	entidad.TipoWebSocket: true,
+	entidad.TipoInApp:     true,
+	entidad.TipoSlack:     true,
+	entidad.TipoTelegram:  true,
+	entidad.TipoVoz:       true,
+}
+
+var prioridades = map[entidad.PrioridadNotificacion]bool{
+	entidad.PrioridadBaja:    true,
+	entidad.PrioridadNormal:  true,
+	entidad.PrioridadAlta:    true,
+	entidad.PrioridadCritica: true,
+}
+
+// Registrar configura el validador de gin: nombres de campo según su etiqueta json
+// y las reglas propias del dominio. Debe llamarse una vez al iniciar el servidor.
+func Registrar() error {
+	motor, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("el validador de gin no es go-playground/validator")
+	}
+
+	motor.RegisterTagNameFunc(func(campo reflect.StructField) string {
+		nombre, _, _ := strings.Cut(campo.Tag.Get("json"), ",")
+		if nombre == "-" {
+			return ""
+		}
+		return nombre
+	})
+
+	reglas := map[string]validator.Func{
+		"tipo_notificacion": func(fl validator.FieldLevel) bool {
+			return tiposNotificacion[entidad.TipoNotificacion(fl.Field().String())]
+		},
+		"prioridad": func(fl validator.FieldLevel) bool {
+			return prioridades[entidad.PrioridadNotificacion(fl.Field().String())]
+		},
+		"duracion": func(fl validator.FieldLevel) bool {
+			duracion, err := time.ParseDuration(fl.Field().String())
+			return err == nil && duracion >= 0
+		},
+		"hora": func(fl validator.FieldLevel) bool {
+			_, err := time.Parse("15:04", fl.Field().String())
+			return err == nil
+		},
+	}
+	for etiqueta, regla := range reglas {
+		if err := motor.RegisterValidation(etiqueta, regla); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Traducir convierte un error de binding en la lista de campos inválidos con sus mensajes en el
+// idioma dado. Retorna false si el error no proviene de la validación ni de la decodificación JSON.
+func Traducir(err error, idiomaMensajes idioma.Idioma) ([]ErrorCampo, bool) {
+	var errores validator.ValidationErrors
+	if errors.As(err, &errores) {
+		campos := make([]ErrorCampo, 0, len(errores))
+		for _, fe := range errores {
+			campos = append(campos, nuevoErrorCampo(rutaCampo(fe), fe.Tag(), fe.Param(), idiomaMensajes))
+		}
+		return campos, true
+	}
+
+	var errorTipo *json.UnmarshalTypeError
+	if errors.As(err, &errorTipo) {
+		return []ErrorCampo{nuevoErrorCampo(errorTipo.Field, "tipo_dato", "", idiomaMensajes)}, true
+	}
+
+	var errorSintaxis *json.SyntaxError
+	if errors.As(err, &errorSintaxis) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return []ErrorCampo{nuevoErrorCampo("", "json", "", idiomaMensajes)}, true
+	}
+	return nil, false
+}
+
+// rutaCampo retorna la ruta del campo sin el nombre del struct raíz (p. ej. pasos[0].tipo)
+func rutaCampo(fe validator.FieldError) string {
+	if _, ruta, ok := strings.Cut(fe.Namespace(), "."); ok {
+		return ruta
+	}
+	return fe.Field()
+}
+
+func nuevoErrorCampo(campo, regla, parametro string, idiomaMensajes idioma.Idioma) ErrorCampo {
+	plantillas, existe := mensajes[regla]
+	if !existe {
+		plantillas = map[idioma.Idioma]string{
+			idioma.ES: "El campo %s no cumple la regla " + regla,
+			idioma.EN: "Field %s fails the " + regla + " rule",
+			idioma.PT: "O campo %s não cumpre a regra " + regla,
+		}
+	}
+	return ErrorCampo{
+		Campo:     campo,
+		Regla:     regla,
+		Mensaje:   formatear(plantillas[idiomaMensajes], campo, parametro),
+		MensajeEN: formatear(plantillas[idioma.EN], campo, parametro),
+	}
+}
+
+func formatear(plantilla, campo, parametro string) string {
+	if strings.Count(plantilla, "%") == 2 {
+		return fmt.Sprintf(plantilla, campo, parametro)
+	}
+	if strings.Contains(plantilla, "%s") {
+		return fmt.Sprintf(plantilla, campo)
+	}
+	return plantilla
+}
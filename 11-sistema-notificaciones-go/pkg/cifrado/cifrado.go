@@ -0,0 +1,62 @@
+package cifrado
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrDatosInvalidos indica un texto cifrado truncado o alterado
+var ErrDatosInvalidos = errors.New("datos cifrados inválidos")
+
+// AESGCM cifra con AES-256-GCM; el nonce aleatorio se antepone al texto cifrado
+type AESGCM struct {
+	aead cipher.AEAD
+}
+
+// NuevoCifradorAES crea un cifrador a partir de una clave de 32 bytes codificada en base64
+func NuevoCifradorAES(claveBase64 string) (*AESGCM, error) {
+	clave, err := base64.StdEncoding.DecodeString(claveBase64)
+	if err != nil {
+		return nil, fmt.Errorf("clave de cifrado no es base64 válido: %w", err)
+	}
+	if len(clave) != 32 {
+		return nil, fmt.Errorf("clave de cifrado debe tener 32 bytes, tiene %d", len(clave))
+	}
+
+	bloque, err := aes.NewCipher(clave)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(bloque)
+	if err != nil {
+		return nil, err
+	}
+	return &AESGCM{aead: aead}, nil
+}
+
+// Cifrar retorna nonce || texto cifrado
+func (c *AESGCM) Cifrar(texto []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return c.aead.Seal(nonce, nonce, texto, nil), nil
+}
+
+// Descifrar revierte Cifrar y verifica la integridad
+func (c *AESGCM) Descifrar(datos []byte) ([]byte, error) {
+	tamanoNonce := c.aead.NonceSize()
+	if len(datos) < tamanoNonce {
+		return nil, ErrDatosInvalidos
+	}
+	texto, err := c.aead.Open(nil, datos[:tamanoNonce], datos[tamanoNonce:], nil)
+	if err != nil {
+		return nil, ErrDatosInvalidos
+	}
+	return texto, nil
+}
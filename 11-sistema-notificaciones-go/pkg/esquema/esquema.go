@@ -0,0 +1,354 @@
+// Package esquema valida documentos JSON decodificados contra un subconjunto de JSON Schema:
+// type, properties, required, additionalProperties, items, enum, const, minLength, maxLength,
+// pattern, minimum, maximum, exclusiveMinimum, exclusiveMaximum, minItems y maxItems.
+// Las palabras clave fuera del subconjunto se rechazan al compilar para no aceptar en silencio
+// un esquema que luego no se aplicaría.
+package esquema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Violacion describe un valor que no cumple el esquema
+type Violacion struct {
+	// Ruta es el campo inválido (p. ej. datos.sonido o destinos[2]); vacía para la raíz
+	Ruta    string `json:"campo"`
+	Regla   string `json:"regla"`
+	Mensaje string `json:"mensaje"`
+}
+
+// Esquema es un esquema compilado, listo para validar
+type Esquema struct {
+	tipos               []string
+	propiedades         map[string]*Esquema
+	requeridas          []string
+	adicionales         *Esquema
+	prohibirAdicionales bool
+	elementos           *Esquema
+	enum                []interface{}
+	constante           interface{}
+	tieneConstante      bool
+	patron              *regexp.Regexp
+	enteros             map[string]int
+	numeros             map[string]float64
+}
+
+// anotaciones son palabras clave sin efecto en la validación
+var anotaciones = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true, "title": true, "description": true, "default": true, "examples": true,
+}
+
+var tiposValidos = map[string]bool{
+	"object": true, "array": true, "string": true, "number": true, "integer": true, "boolean": true, "null": true,
+}
+
+// Compilar verifica la definición y la prepara para validar
+func Compilar(definicion map[string]interface{}) (*Esquema, error) {
+	return compilar(definicion, "")
+}
+
+func compilar(definicion map[string]interface{}, ruta string) (*Esquema, error) {
+	e := &Esquema{enteros: make(map[string]int), numeros: make(map[string]float64)}
+	claves := make([]string, 0, len(definicion))
+	for clave := range definicion {
+		claves = append(claves, clave)
+	}
+	sort.Strings(claves)
+
+	for _, clave := range claves {
+		valor := definicion[clave]
+		invalido := func(motivo string) error {
+			return fmt.Errorf("esquema inválido en %s: %s %s", rutaEsquema(ruta), clave, motivo)
+		}
+		switch clave {
+		case "type":
+			switch tipo := valor.(type) {
+			case string:
+				e.tipos = []string{tipo}
+			case []interface{}:
+				for _, t := range tipo {
+					nombre, ok := t.(string)
+					if !ok {
+						return nil, invalido("debe contener nombres de tipo")
+					}
+					e.tipos = append(e.tipos, nombre)
+				}
+			default:
+				return nil, invalido("debe ser un tipo o una lista de tipos")
+			}
+			for _, tipo := range e.tipos {
+				if !tiposValidos[tipo] {
+					return nil, invalido(fmt.Sprintf("tiene un tipo desconocido %q", tipo))
+				}
+			}
+		case "properties":
+			propiedades, ok := valor.(map[string]interface{})
+			if !ok {
+				return nil, invalido("debe ser un objeto")
+			}
+			e.propiedades = make(map[string]*Esquema, len(propiedades))
+			for nombre, definicionPropiedad := range propiedades {
+				subesquema, err := compilarSubesquema(definicionPropiedad, unir(ruta, nombre))
+				if err != nil {
+					return nil, err
+				}
+				e.propiedades[nombre] = subesquema
+			}
+		case "required":
+			lista, ok := valor.([]interface{})
+			if !ok {
+				return nil, invalido("debe ser una lista de nombres")
+			}
+			for _, nombre := range lista {
+				texto, ok := nombre.(string)
+				if !ok {
+					return nil, invalido("debe ser una lista de nombres")
+				}
+				e.requeridas = append(e.requeridas, texto)
+			}
+		case "additionalProperties":
+			if permitidas, ok := valor.(bool); ok {
+				e.prohibirAdicionales = !permitidas
+				continue
+			}
+			subesquema, err := compilarSubesquema(valor, unir(ruta, "*"))
+			if err != nil {
+				return nil, err
+			}
+			e.adicionales = subesquema
+		case "items":
+			subesquema, err := compilarSubesquema(valor, ruta+"[]")
+			if err != nil {
+				return nil, err
+			}
+			e.elementos = subesquema
+		case "enum":
+			lista, ok := valor.([]interface{})
+			if !ok || len(lista) == 0 {
+				return nil, invalido("debe ser una lista no vacía")
+			}
+			e.enum = lista
+		case "const":
+			e.constante, e.tieneConstante = valor, true
+		case "pattern":
+			texto, ok := valor.(string)
+			if !ok {
+				return nil, invalido("debe ser una expresión regular")
+			}
+			patron, err := regexp.Compile(texto)
+			if err != nil {
+				return nil, invalido("no es una expresión regular válida")
+			}
+			e.patron = patron
+		case "minLength", "maxLength", "minItems", "maxItems":
+			numero, ok := aNumero(valor)
+			if !ok || numero < 0 || numero != math.Trunc(numero) {
+				return nil, invalido("debe ser un entero no negativo")
+			}
+			e.enteros[clave] = int(numero)
+		case "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum":
+			numero, ok := aNumero(valor)
+			if !ok {
+				return nil, invalido("debe ser un número")
+			}
+			e.numeros[clave] = numero
+		default:
+			if !anotaciones[clave] {
+				return nil, fmt.Errorf("esquema inválido en %s: la palabra clave %s no está soportada", rutaEsquema(ruta), clave)
+			}
+		}
+	}
+	return e, nil
+}
+
+func compilarSubesquema(definicion interface{}, ruta string) (*Esquema, error) {
+	objeto, ok := definicion.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("esquema inválido en %s: debe ser un objeto", rutaEsquema(ruta))
+	}
+	return compilar(objeto, ruta)
+}
+
+// Validar retorna las violaciones del valor; vacía si lo cumple
+func (e *Esquema) Validar(valor interface{}) []Violacion {
+	var violaciones []Violacion
+	e.validar(valor, "", &violaciones)
+	return violaciones
+}
+
+func (e *Esquema) validar(valor interface{}, ruta string, violaciones *[]Violacion) {
+	agregar := func(regla, mensaje string, args ...interface{}) {
+		*violaciones = append(*violaciones, Violacion{Ruta: ruta, Regla: regla, Mensaje: fmt.Sprintf(mensaje, args...)})
+	}
+
+	if len(e.tipos) > 0 && !cumpleTipo(valor, e.tipos) {
+		agregar("type", "debe ser de tipo %s", strings.Join(e.tipos, " o "))
+		return
+	}
+	if e.tieneConstante && !iguales(valor, e.constante) {
+		agregar("const", "debe ser %v", e.constante)
+	}
+	if len(e.enum) > 0 && !contiene(e.enum, valor) {
+		agregar("enum", "debe ser uno de %v", e.enum)
+	}
+
+	switch v := valor.(type) {
+	case string:
+		longitud := utf8.RuneCountInString(v)
+		if minimo, ok := e.enteros["minLength"]; ok && longitud < minimo {
+			agregar("minLength", "debe tener al menos %d caracteres", minimo)
+		}
+		if maximo, ok := e.enteros["maxLength"]; ok && longitud > maximo {
+			agregar("maxLength", "admite como máximo %d caracteres", maximo)
+		}
+		if e.patron != nil && !e.patron.MatchString(v) {
+			agregar("pattern", "debe cumplir el patrón %s", e.patron.String())
+		}
+	case map[string]interface{}:
+		for _, nombre := range e.requeridas {
+			if _, existe := v[nombre]; !existe {
+				*violaciones = append(*violaciones, Violacion{Ruta: unir(ruta, nombre), Regla: "required", Mensaje: "es obligatorio"})
+			}
+		}
+		nombres := make([]string, 0, len(v))
+		for nombre := range v {
+			nombres = append(nombres, nombre)
+		}
+		sort.Strings(nombres)
+		for _, nombre := range nombres {
+			if propiedad, existe := e.propiedades[nombre]; existe {
+				propiedad.validar(v[nombre], unir(ruta, nombre), violaciones)
+			} else if e.adicionales != nil {
+				e.adicionales.validar(v[nombre], unir(ruta, nombre), violaciones)
+			} else if e.prohibirAdicionales {
+				*violaciones = append(*violaciones, Violacion{Ruta: unir(ruta, nombre), Regla: "additionalProperties", Mensaje: "no está permitido por el esquema"})
+			}
+		}
+	case []interface{}:
+		if minimo, ok := e.enteros["minItems"]; ok && len(v) < minimo {
+			agregar("minItems", "debe tener al menos %d elementos", minimo)
+		}
+		if maximo, ok := e.enteros["maxItems"]; ok && len(v) > maximo {
+			agregar("maxItems", "admite como máximo %d elementos", maximo)
+		}
+		if e.elementos != nil {
+			for i, elemento := range v {
+				e.elementos.validar(elemento, fmt.Sprintf("%s[%d]", ruta, i), violaciones)
+			}
+		}
+	default:
+		numero, ok := aNumero(valor)
+		if !ok {
+			return
+		}
+		if limite, ok := e.numeros["minimum"]; ok && numero < limite {
+			agregar("minimum", "debe ser mayor o igual a %v", limite)
+		}
+		if limite, ok := e.numeros["maximum"]; ok && numero > limite {
+			agregar("maximum", "debe ser menor o igual a %v", limite)
+		}
+		if limite, ok := e.numeros["exclusiveMinimum"]; ok && numero <= limite {
+			agregar("exclusiveMinimum", "debe ser mayor a %v", limite)
+		}
+		if limite, ok := e.numeros["exclusiveMaximum"]; ok && numero >= limite {
+			agregar("exclusiveMaximum", "debe ser menor a %v", limite)
+		}
+	}
+}
+
+func cumpleTipo(valor interface{}, tipos []string) bool {
+	for _, tipo := range tipos {
+		switch tipo {
+		case "object":
+			if _, ok := valor.(map[string]interface{}); ok {
+				return true
+			}
+		case "array":
+			if _, ok := valor.([]interface{}); ok {
+				return true
+			}
+		case "string":
+			if _, ok := valor.(string); ok {
+				return true
+			}
+		case "boolean":
+			if _, ok := valor.(bool); ok {
+				return true
+			}
+		case "null":
+			if valor == nil {
+				return true
+			}
+		case "number":
+			if _, ok := aNumero(valor); ok {
+				return true
+			}
+		case "integer":
+			if numero, ok := aNumero(valor); ok && numero == math.Trunc(numero) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// aNumero convierte los números decodificados de JSON (float64 o json.Number) y los enteros de Go
+func aNumero(valor interface{}) (float64, bool) {
+	switch v := valor.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case json.Number:
+		numero, err := v.Float64()
+		return numero, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func contiene(lista []interface{}, valor interface{}) bool {
+	for _, candidato := range lista {
+		if iguales(candidato, valor) {
+			return true
+		}
+	}
+	return false
+}
+
+// iguales compara valores JSON; los números se comparan por valor sin importar su tipo de Go
+func iguales(a, b interface{}) bool {
+	numeroA, esNumeroA := aNumero(a)
+	numeroB, esNumeroB := aNumero(b)
+	if esNumeroA || esNumeroB {
+		return esNumeroA && esNumeroB && numeroA == numeroB
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func unir(ruta, nombre string) string {
+	if ruta == "" {
+		return nombre
+	}
+	return ruta + "." + nombre
+}
+
+func rutaEsquema(ruta string) string {
+	if ruta == "" {
+		return "la raíz"
+	}
+	return ruta
+}
@@ -0,0 +1,130 @@
+package esquema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// decodificar arma la definición o el documento igual que llegan por la API
+func decodificar(t *testing.T, texto string) interface{} {
+	t.Helper()
+	var valor interface{}
+	if err := json.Unmarshal([]byte(texto), &valor); err != nil {
+		t.Fatalf("decodificando %s: %v", texto, err)
+	}
+	return valor
+}
+
+func compilarDe(t *testing.T, definicion string) *Esquema {
+	t.Helper()
+	compilado, err := Compilar(decodificar(t, definicion).(map[string]interface{}))
+	if err != nil {
+		t.Fatalf("compilando %s: %v", definicion, err)
+	}
+	return compilado
+}
+
+// resumen es "ruta:regla" de cada violación, en orden
+func resumen(violaciones []Violacion) string {
+	partes := make([]string, len(violaciones))
+	for i, violacion := range violaciones {
+		partes[i] = violacion.Ruta + ":" + violacion.Regla
+	}
+	return strings.Join(partes, ",")
+}
+
+func TestValidarPorPalabraClave(t *testing.T) {
+	casos := []struct {
+		nombre      string
+		esquema     string
+		documento   string
+		violaciones string
+	}{
+		{"type cumple", `{"type":"string"}`, `"hola"`, ""},
+		{"type no cumple", `{"type":"string"}`, `3`, ":type"},
+		{"type lista", `{"type":["string","null"]}`, `null`, ""},
+		{"type integer con decimales", `{"type":"integer"}`, `1.5`, ":type"},
+		{"type integer entero", `{"type":"integer"}`, `2`, ""},
+		{"type number", `{"type":"number"}`, `2.5`, ""},
+		{"type boolean", `{"type":"boolean"}`, `"true"`, ":type"},
+		{"type array", `{"type":"array"}`, `{}`, ":type"},
+		{"type object", `{"type":"object"}`, `[]`, ":type"},
+		{"required falta", `{"required":["sonido"]}`, `{}`, "sonido:required"},
+		{"required presente", `{"required":["sonido"]}`, `{"sonido":"campana"}`, ""},
+		{"properties anidadas", `{"properties":{"datos":{"properties":{"nivel":{"type":"integer"}}}}}`, `{"datos":{"nivel":"alto"}}`, "datos.nivel:type"},
+		{"additionalProperties false", `{"properties":{"a":{}},"additionalProperties":false}`, `{"a":1,"b":2}`, "b:additionalProperties"},
+		{"additionalProperties esquema", `{"additionalProperties":{"type":"string"}}`, `{"a":"x","b":2}`, "b:type"},
+		{"items", `{"items":{"type":"integer"}}`, `[1,"dos",3]`, "[1]:type"},
+		{"enum cumple", `{"enum":["alta","baja"]}`, `"alta"`, ""},
+		{"enum no cumple", `{"enum":["alta","baja"]}`, `"media"`, ":enum"},
+		{"enum numérico", `{"enum":[1,2]}`, `2.0`, ""},
+		{"const", `{"const":"v1"}`, `"v2"`, ":const"},
+		{"minLength cuenta runas", `{"minLength":3}`, `"año"`, ""},
+		{"minLength", `{"minLength":3}`, `"ab"`, ":minLength"},
+		{"maxLength", `{"maxLength":2}`, `"abc"`, ":maxLength"},
+		{"pattern cumple", `{"pattern":"^[a-z]+$"}`, `"abc"`, ""},
+		{"pattern no cumple", `{"pattern":"^[a-z]+$"}`, `"ABC"`, ":pattern"},
+		{"minimum en el límite", `{"minimum":1}`, `1`, ""},
+		{"minimum", `{"minimum":1}`, `0`, ":minimum"},
+		{"maximum", `{"maximum":10}`, `11`, ":maximum"},
+		{"exclusiveMinimum en el límite", `{"exclusiveMinimum":1}`, `1`, ":exclusiveMinimum"},
+		{"exclusiveMaximum en el límite", `{"exclusiveMaximum":10}`, `10`, ":exclusiveMaximum"},
+		{"minItems", `{"minItems":2}`, `[1]`, ":minItems"},
+		{"maxItems", `{"maxItems":1}`, `[1,2]`, ":maxItems"},
+		{"anotaciones sin efecto", `{"title":"T","description":"D","default":1,"$comment":"c"}`, `"x"`, ""},
+		{"reglas de texto no aplican a números", `{"minLength":3,"pattern":"^a"}`, `5`, ""},
+		{"varias violaciones en orden", `{"properties":{"b":{"type":"string"},"a":{"minimum":5}},"required":["c"]}`, `{"a":1,"b":2}`, "c:required,a:minimum,b:type"},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nombre, func(t *testing.T) {
+			violaciones := compilarDe(t, caso.esquema).Validar(decodificar(t, caso.documento))
+			if obtenidas := resumen(violaciones); obtenidas != caso.violaciones {
+				t.Errorf("violaciones = %q, se esperaba %q", obtenidas, caso.violaciones)
+			}
+		})
+	}
+}
+
+func TestValidarNumerosDeGo(t *testing.T) {
+	compilado := compilarDe(t, `{"properties":{"intentos":{"type":"integer","maximum":3}}}`)
+
+	for _, valor := range []interface{}{3, int64(3), uint(3), float32(3), json.Number("3")} {
+		if violaciones := compilado.Validar(map[string]interface{}{"intentos": valor}); len(violaciones) != 0 {
+			t.Errorf("%T: %v", valor, violaciones)
+		}
+	}
+	if violaciones := compilado.Validar(map[string]interface{}{"intentos": 4}); resumen(violaciones) != "intentos:maximum" {
+		t.Errorf("violaciones = %v", violaciones)
+	}
+}
+
+func TestCompilarRechazaDefinicionesInvalidas(t *testing.T) {
+	casos := []struct {
+		nombre   string
+		esquema  string
+		contiene string
+	}{
+		{"palabra clave no soportada", `{"oneOf":[]}`, "oneOf no está soportada"},
+		{"no soportada anidada", `{"properties":{"a":{"format":"email"}}}`, "en a:"},
+		{"tipo desconocido", `{"type":"texto"}`, "tipo desconocido"},
+		{"type no textual", `{"type":3}`, "type"},
+		{"properties no objeto", `{"properties":[]}`, "properties"},
+		{"subesquema no objeto", `{"properties":{"a":true}}`, "debe ser un objeto"},
+		{"required con números", `{"required":[1]}`, "required"},
+		{"enum vacío", `{"enum":[]}`, "enum"},
+		{"pattern inválido", `{"pattern":"("}`, "pattern"},
+		{"minLength negativo", `{"minLength":-1}`, "minLength"},
+		{"maxItems con decimales", `{"maxItems":1.5}`, "maxItems"},
+		{"minimum no numérico", `{"minimum":"1"}`, "minimum"},
+		{"items no objeto", `{"items":"string"}`, "[]"},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nombre, func(t *testing.T) {
+			_, err := Compilar(decodificar(t, caso.esquema).(map[string]interface{}))
+			if err == nil || !strings.Contains(err.Error(), caso.contiene) {
+				t.Errorf("err = %v, se esperaba que mencione %q", err, caso.contiene)
+			}
+		})
+	}
+}
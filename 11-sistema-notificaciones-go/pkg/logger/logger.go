@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Componentes con nivel de log configurable de forma independiente
+const (
+	ComponenteHTTP         = "http"
+	ComponenteWebSocket    = "ws"
+	ComponenteProveedores  = "proveedores"
+	ComponenteTrabajadores = "trabajadores"
+)
+
+var componentes = []string{ComponenteHTTP, ComponenteWebSocket, ComponenteProveedores, ComponenteTrabajadores}
+
+// EsComponente indica si el nombre es uno de los componentes con nivel propio
+func EsComponente(nombre string) bool {
+	return slices.Contains(componentes, nombre)
+}
+
+// Logger es un logger estructurado con niveles ajustables en tiempo de ejecución
+// y redacción de datos personales aplicada a todo lo que emite
+type Logger struct {
+	slog       *slog.Logger
+	niveles    *RegistroNiveles
+	politica   *PoliticaRedaccion
+	salida     io.Writer
+	componente string
+}
+
+// NuevoLogger crea un logger JSON en stdout con nivel inicial tomado de LOG_NIVEL y
+// política de redacción de LOG_REDACCION (enmascarar, hash o desactivada) más los
+// campos sensibles adicionales de LOG_REDACCION_CAMPOS (separados por comas)
+func NuevoLogger() *Logger {
+	niveles := NuevoRegistroNiveles()
+	if nivel, err := ParsearNivel(os.Getenv("LOG_NIVEL")); err == nil {
+		niveles.EstablecerGlobal(nivel)
+	}
+
+	var camposExtra []string
+	if campos := os.Getenv("LOG_REDACCION_CAMPOS"); campos != "" {
+		camposExtra = strings.Split(campos, ",")
+	}
+	politica, errPolitica := NuevaPoliticaRedaccion(ModoRedaccion(os.Getenv("LOG_REDACCION")), camposExtra...)
+	if errPolitica != nil {
+		// Ante un modo inválido se enmascara: nunca se cae a emitir datos personales
+		politica, _ = NuevaPoliticaRedaccion(RedaccionEnmascarar, camposExtra...)
+	}
+
+	logger := nuevoLoggerEn(os.Stdout, niveles, politica)
+	if errPolitica != nil {
+		logger.Warn("LOG_REDACCION inválido, se enmascaran los datos personales", "error", errPolitica)
+	}
+	return logger
+}
+
+func nuevoLoggerEn(salida io.Writer, niveles *RegistroNiveles, politica *PoliticaRedaccion) *Logger {
+	return &Logger{
+		slog:     nuevoSlog(salida, niveles.variableGlobal(), politica),
+		niveles:  niveles,
+		politica: politica,
+		salida:   salida,
+	}
+}
+
+// nuevoSlog emite JSON a través del handler de redacción, salvo con la redacción desactivada
+func nuevoSlog(salida io.Writer, nivel slog.Leveler, politica *PoliticaRedaccion) *slog.Logger {
+	var handler slog.Handler = slog.NewJSONHandler(salida, &slog.HandlerOptions{Level: nivel})
+	if politica.modo != RedaccionDesactivada {
+		handler = &manejadorRedaccion{siguiente: handler, politica: politica}
+	}
+	return slog.New(handler)
+}
+
+// Componente retorna un logger cuyo nivel se controla con el nombre de componente
+func (l *Logger) Componente(nombre string) *Logger {
+	return &Logger{
+		slog:       nuevoSlog(l.salida, l.niveles.variableComponente(nombre), l.politica).With("componente", nombre),
+		niveles:    l.niveles,
+		politica:   l.politica,
+		salida:     l.salida,
+		componente: nombre,
+	}
+}
+
+// Con retorna un logger con atributos adicionales
+func (l *Logger) Con(args ...any) *Logger {
+	return &Logger{
+		slog:       l.slog.With(args...),
+		niveles:    l.niveles,
+		politica:   l.politica,
+		salida:     l.salida,
+		componente: l.componente,
+	}
+}
+
+// Niveles retorna el registro de niveles compartido por todos los componentes
+func (l *Logger) Niveles() *RegistroNiveles {
+	return l.niveles
+}
+
+// Habilitado indica si el nivel dado se emitiría
+func (l *Logger) Habilitado(nivel slog.Level) bool {
+	return l.slog.Enabled(context.Background(), nivel)
+}
+
+// Debug registra un mensaje de depuración
+func (l *Logger) Debug(mensaje string, args ...any) {
+	l.slog.Debug(mensaje, args...)
+}
+
+// Info registra un mensaje informativo
+func (l *Logger) Info(mensaje string, args ...any) {
+	l.slog.Info(mensaje, args...)
+}
+
+// Warn registra una advertencia
+func (l *Logger) Warn(mensaje string, args ...any) {
+	l.slog.Warn(mensaje, args...)
+}
+
+// Error registra un error
+func (l *Logger) Error(mensaje string, args ...any) {
+	l.slog.Error(mensaje, args...)
+}
+
+// Fatal registra un error y termina el proceso
+func (l *Logger) Fatal(mensaje string, args ...any) {
+	l.slog.Error(mensaje, args...)
+	os.Exit(1)
+}
@@ -0,0 +1,180 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RegistroNiveles mantiene el nivel global y los niveles por componente
+type RegistroNiveles struct {
+	mu          sync.Mutex
+	global      *slog.LevelVar
+	componentes map[string]*slog.LevelVar
+	// sobrescritos contiene los componentes con nivel propio (no siguen al global)
+	sobrescritos map[string]bool
+	// temporizadores revierten cambios temporales
+	temporizadores map[string]*time.Timer
+}
+
+// NuevoRegistroNiveles crea un registro con nivel global info y los componentes conocidos
+func NuevoRegistroNiveles() *RegistroNiveles {
+	registro := &RegistroNiveles{
+		global:         new(slog.LevelVar),
+		componentes:    make(map[string]*slog.LevelVar),
+		sobrescritos:   make(map[string]bool),
+		temporizadores: make(map[string]*time.Timer),
+	}
+	for _, nombre := range componentes {
+		registro.variableComponente(nombre)
+	}
+	return registro
+}
+
+// ParsearNivel convierte debug/info/warn/error en un nivel de slog
+func ParsearNivel(nivel string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(nivel)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("nivel de log inválido: %q", nivel)
+	}
+}
+
+// NombreNivel convierte un nivel de slog a su nombre en minúsculas
+func NombreNivel(nivel slog.Level) string {
+	return strings.ToLower(nivel.String())
+}
+
+func (r *RegistroNiveles) variableGlobal() *slog.LevelVar {
+	return r.global
+}
+
+func (r *RegistroNiveles) variableComponente(nombre string) *slog.LevelVar {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	variable, existe := r.componentes[nombre]
+	if !existe {
+		variable = new(slog.LevelVar)
+		variable.Set(r.global.Level())
+		r.componentes[nombre] = variable
+	}
+	return variable
+}
+
+// EstablecerGlobal cambia el nivel global y el de los componentes sin nivel propio
+func (r *RegistroNiveles) EstablecerGlobal(nivel slog.Level) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.global.Set(nivel)
+	for nombre, variable := range r.componentes {
+		if !r.sobrescritos[nombre] {
+			variable.Set(nivel)
+		}
+	}
+}
+
+// EstablecerComponente fija el nivel de un componente.
+// Si duracion > 0, el componente vuelve a seguir el nivel global al expirar.
+func (r *RegistroNiveles) EstablecerComponente(nombre string, nivel slog.Level, duracion time.Duration) {
+	variable := r.variableComponente(nombre)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	variable.Set(nivel)
+	r.sobrescritos[nombre] = true
+
+	if temporizador, existe := r.temporizadores[nombre]; existe {
+		temporizador.Stop()
+		delete(r.temporizadores, nombre)
+	}
+	if duracion > 0 {
+		var temporizador *time.Timer
+		temporizador = time.AfterFunc(duracion, func() {
+			r.expirar(nombre, temporizador)
+		})
+		r.temporizadores[nombre] = temporizador
+	}
+}
+
+// expirar restablece el componente si temporizador sigue siendo el suyo. Uno que ya disparó
+// puede esperar el mutex mientras otro cambio lo reemplaza: ese no debe deshacer el cambio nuevo.
+func (r *RegistroNiveles) expirar(nombre string, temporizador *time.Timer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.temporizadores[nombre] == temporizador {
+		r.restablecer(nombre)
+	}
+}
+
+// RestablecerComponente hace que el componente vuelva a seguir el nivel global
+func (r *RegistroNiveles) RestablecerComponente(nombre string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.restablecer(nombre)
+}
+
+func (r *RegistroNiveles) restablecer(nombre string) {
+	delete(r.sobrescritos, nombre)
+	if temporizador, existe := r.temporizadores[nombre]; existe {
+		temporizador.Stop()
+		delete(r.temporizadores, nombre)
+	}
+	if variable, existe := r.componentes[nombre]; existe {
+		variable.Set(r.global.Level())
+	}
+}
+
+// Aplicar configura el nivel global y los niveles "componente=nivel" indicados
+func (r *RegistroNiveles) Aplicar(global string, porComponente []string) error {
+	if global != "" {
+		nivel, err := ParsearNivel(global)
+		if err != nil {
+			return err
+		}
+		r.EstablecerGlobal(nivel)
+	}
+
+	for _, asignacion := range porComponente {
+		partes := strings.SplitN(asignacion, "=", 2)
+		if len(partes) != 2 {
+			return fmt.Errorf("asignación de nivel inválida: %q", asignacion)
+		}
+		componente := strings.TrimSpace(partes[0])
+		if !EsComponente(componente) {
+			return fmt.Errorf("componente de log desconocido: %q", componente)
+		}
+		nivel, err := ParsearNivel(partes[1])
+		if err != nil {
+			return err
+		}
+		r.EstablecerComponente(componente, nivel, 0)
+	}
+
+	return nil
+}
+
+// Instantanea retorna el nivel global y el efectivo de cada componente conocido
+func (r *RegistroNiveles) Instantanea() map[string]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	niveles := map[string]string{"global": NombreNivel(r.global.Level())}
+	for nombre, variable := range r.componentes {
+		niveles[nombre] = NombreNivel(variable.Level())
+	}
+	return niveles
+}
@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestTemporizadorReemplazadoNoRestableceElCambioNuevo(t *testing.T) {
+	registro := NuevoRegistroNiveles()
+	registro.EstablecerComponente(ComponenteHTTP, slog.LevelDebug, time.Hour)
+	anterior := registro.temporizadores[ComponenteHTTP]
+
+	registro.EstablecerComponente(ComponenteHTTP, slog.LevelError, time.Hour)
+	// El temporizador anterior ya disparó y recién ahora obtiene el mutex
+	registro.expirar(ComponenteHTTP, anterior)
+
+	if nivel := registro.Instantanea()[ComponenteHTTP]; nivel != "error" {
+		t.Errorf("nivel = %s, se esperaba error", nivel)
+	}
+	if registro.temporizadores[ComponenteHTTP] == nil {
+		t.Error("se descartó el temporizador del cambio nuevo")
+	}
+
+	registro.expirar(ComponenteHTTP, registro.temporizadores[ComponenteHTTP])
+	if nivel := registro.Instantanea()[ComponenteHTTP]; nivel != "info" {
+		t.Errorf("al expirar nivel = %s, se esperaba el global info", nivel)
+	}
+}
+
+func TestAplicarRechazaComponentesDesconocidos(t *testing.T) {
+	registro := NuevoRegistroNiveles()
+
+	if err := registro.Aplicar("", []string{"htpp=debug"}); err == nil {
+		t.Fatal("se aceptó un componente desconocido")
+	}
+	if _, existe := registro.Instantanea()["htpp"]; existe {
+		t.Error("el componente desconocido quedó registrado")
+	}
+	if err := registro.Aplicar("warn", []string{"proveedores=debug"}); err != nil {
+		t.Fatalf("Aplicar: %v", err)
+	}
+}
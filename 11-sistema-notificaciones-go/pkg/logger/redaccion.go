@@ -0,0 +1,206 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// ModoRedaccion define cómo se ocultan los datos personales en los logs
+type ModoRedaccion string
+
+const (
+	// RedaccionEnmascarar reemplaza el valor por una máscara que conserva solo su forma
+	RedaccionEnmascarar ModoRedaccion = "enmascarar"
+	// RedaccionHash reemplaza el valor por un prefijo de su SHA-256, útil para correlacionar
+	RedaccionHash ModoRedaccion = "hash"
+	// RedaccionDesactivada emite los valores tal cual (solo para desarrollo local)
+	RedaccionDesactivada ModoRedaccion = "desactivada"
+)
+
+// camposSensibles son los atributos cuyo valor completo es un dato personal o contenido de mensajes
+var camposSensibles = []string{
+	"correo", "correo_electronico", "email", "telefono", "destinatario",
+	"titulo", "mensaje", "cuerpo", "asunto", "contenido", "token", "datos",
+}
+
+var (
+	patronCorreo   = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	patronTelefono = regexp.MustCompile(`\+\d[\d\s\-]{7,14}\d`)
+)
+
+// PoliticaRedaccion decide qué atributos se ocultan y cómo. Se aplica a todo lo que emite
+// el logger (incluidos los errores), así ningún llamador puede olvidarse de enmascarar.
+type PoliticaRedaccion struct {
+	modo   ModoRedaccion
+	campos map[string]bool
+}
+
+// NuevaPoliticaRedaccion crea la política; los campos extra se suman a los sensibles por defecto
+func NuevaPoliticaRedaccion(modo ModoRedaccion, camposExtra ...string) (*PoliticaRedaccion, error) {
+	switch modo {
+	case RedaccionEnmascarar, RedaccionHash, RedaccionDesactivada:
+	case "":
+		modo = RedaccionEnmascarar
+	default:
+		return nil, fmt.Errorf("modo de redacción inválido: %q", modo)
+	}
+
+	campos := make(map[string]bool, len(camposSensibles)+len(camposExtra))
+	for _, campo := range append(camposSensibles, camposExtra...) {
+		if campo = strings.ToLower(strings.TrimSpace(campo)); campo != "" {
+			campos[campo] = true
+		}
+	}
+	return &PoliticaRedaccion{modo: modo, campos: campos}, nil
+}
+
+// manejadorRedaccion aplica la política a cada registro antes de entregarlo al handler de
+// salida: al mensaje, a sus atributos y a los que se agregan con With
+type manejadorRedaccion struct {
+	siguiente slog.Handler
+	politica  *PoliticaRedaccion
+}
+
+func (m *manejadorRedaccion) Enabled(ctx context.Context, nivel slog.Level) bool {
+	return m.siguiente.Enabled(ctx, nivel)
+}
+
+func (m *manejadorRedaccion) Handle(ctx context.Context, registro slog.Record) error {
+	redactado := slog.NewRecord(registro.Time, registro.Level, m.politica.Redactar(registro.Message), registro.PC)
+	registro.Attrs(func(atributo slog.Attr) bool {
+		redactado.AddAttrs(m.politica.redactarAtributo(atributo))
+		return true
+	})
+	return m.siguiente.Handle(ctx, redactado)
+}
+
+func (m *manejadorRedaccion) WithAttrs(atributos []slog.Attr) slog.Handler {
+	redactados := make([]slog.Attr, len(atributos))
+	for i, atributo := range atributos {
+		redactados[i] = m.politica.redactarAtributo(atributo)
+	}
+	return &manejadorRedaccion{siguiente: m.siguiente.WithAttrs(redactados), politica: m.politica}
+}
+
+func (m *manejadorRedaccion) WithGroup(nombre string) slog.Handler {
+	return &manejadorRedaccion{siguiente: m.siguiente.WithGroup(nombre), politica: m.politica}
+}
+
+// redactarAtributo oculta el valor completo de los campos sensibles y, en el resto, los correos
+// y teléfonos embebidos. Recorre los grupos miembro a miembro.
+func (p *PoliticaRedaccion) redactarAtributo(atributo slog.Attr) slog.Attr {
+	valor := atributo.Value.Resolve()
+	switch {
+	case valor.Kind() == slog.KindGroup:
+		miembros := valor.Group()
+		redactados := make([]slog.Attr, len(miembros))
+		for i, miembro := range miembros {
+			redactados[i] = p.redactarAtributo(miembro)
+		}
+		return slog.Attr{Key: atributo.Key, Value: slog.GroupValue(redactados...)}
+	case p.campos[strings.ToLower(atributo.Key)]:
+		return slog.String(atributo.Key, p.ocultar(valor.String()))
+	case valor.Kind() == slog.KindString:
+		return slog.String(atributo.Key, p.Redactar(valor.String()))
+	case valor.Kind() == slog.KindAny:
+		return slog.Any(atributo.Key, p.redactarDato(valor.Any()))
+	}
+	return slog.Attr{Key: atributo.Key, Value: valor}
+}
+
+// redactarDato redacta un valor arbitrario (estructuras, mapas, listas) sobre su forma JSON,
+// que es la que emite el handler, así los campos se reconocen por el mismo nombre que se ve en
+// el log
+func (p *PoliticaRedaccion) redactarDato(dato any) any {
+	switch v := dato.(type) {
+	case nil:
+		return nil
+	case error:
+		return p.Redactar(v.Error())
+	}
+	codificado, err := json.Marshal(dato)
+	if err != nil {
+		return p.Redactar(fmt.Sprint(dato))
+	}
+	decodificador := json.NewDecoder(bytes.NewReader(codificado))
+	decodificador.UseNumber()
+	var arbol any
+	if err := decodificador.Decode(&arbol); err != nil {
+		return p.Redactar(string(codificado))
+	}
+	return p.redactarJSON(arbol)
+}
+
+func (p *PoliticaRedaccion) redactarJSON(nodo any) any {
+	switch v := nodo.(type) {
+	case map[string]any:
+		for clave, valor := range v {
+			if p.campos[strings.ToLower(clave)] {
+				v[clave] = p.ocultar(textoJSON(valor))
+			} else {
+				v[clave] = p.redactarJSON(valor)
+			}
+		}
+		return v
+	case []any:
+		for i, elemento := range v {
+			v[i] = p.redactarJSON(elemento)
+		}
+		return v
+	case string:
+		return p.Redactar(v)
+	}
+	return nodo
+}
+
+// textoJSON es el texto de un valor ya decodificado: el propio texto o su JSON
+func textoJSON(valor any) string {
+	if texto, ok := valor.(string); ok {
+		return texto
+	}
+	if valor == nil {
+		return ""
+	}
+	codificado, _ := json.Marshal(valor)
+	return string(codificado)
+}
+
+// Redactar oculta los correos y teléfonos embebidos en un texto libre
+func (p *PoliticaRedaccion) Redactar(texto string) string {
+	if p.modo == RedaccionDesactivada {
+		return texto
+	}
+	texto = patronCorreo.ReplaceAllStringFunc(texto, p.ocultarCorreo)
+	return patronTelefono.ReplaceAllStringFunc(texto, p.ocultar)
+}
+
+func (p *PoliticaRedaccion) ocultar(valor string) string {
+	if valor == "" {
+		return ""
+	}
+	if p.modo == RedaccionHash {
+		return huella(valor)
+	}
+	return fmt.Sprintf("[oculto:%d]", len([]rune(valor)))
+}
+
+// ocultarCorreo conserva el dominio, útil para diagnosticar problemas de entrega
+func (p *PoliticaRedaccion) ocultarCorreo(correo string) string {
+	if p.modo == RedaccionHash {
+		return huella(strings.ToLower(correo))
+	}
+	usuario, dominio, _ := strings.Cut(correo, "@")
+	return usuario[:1] + "***@" + dominio
+}
+
+func huella(valor string) string {
+	suma := sha256.Sum256([]byte(valor))
+	return "sha256:" + hex.EncodeToString(suma[:6])
+}
@@ -0,0 +1,152 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+const correoPrueba = "ana.perez@ejemplo.com"
+
+// loggerDePrueba escribe en un buffer con la política del modo dado
+func loggerDePrueba(t *testing.T, modo ModoRedaccion) (*Logger, *bytes.Buffer) {
+	t.Helper()
+	politica, err := NuevaPoliticaRedaccion(modo)
+	if err != nil {
+		t.Fatalf("creando la política: %v", err)
+	}
+	salida := &bytes.Buffer{}
+	return nuevoLoggerEn(salida, NuevoRegistroNiveles(), politica), salida
+}
+
+// registro decodifica la única línea emitida
+func registro(t *testing.T, salida *bytes.Buffer) map[string]any {
+	t.Helper()
+	var campos map[string]any
+	if err := json.Unmarshal(salida.Bytes(), &campos); err != nil {
+		t.Fatalf("decodificando %q: %v", salida.String(), err)
+	}
+	return campos
+}
+
+func sinCorreo(t *testing.T, salida *bytes.Buffer) {
+	t.Helper()
+	if strings.Contains(salida.String(), correoPrueba) {
+		t.Errorf("el correo aparece en el log: %s", salida.String())
+	}
+}
+
+func TestRedactaElMensajeYLosCamposSensibles(t *testing.T) {
+	logger, salida := loggerDePrueba(t, RedaccionEnmascarar)
+
+	logger.Info("Enviado a "+correoPrueba, "correo", correoPrueba, "detalle", "responder a "+correoPrueba+" o +54 911 2233 4455")
+
+	sinCorreo(t, salida)
+	campos := registro(t, salida)
+	if campos["correo"] != "[oculto:21]" {
+		t.Errorf("correo = %v, se esperaba [oculto:21]", campos["correo"])
+	}
+	if campos["msg"] != "Enviado a a***@ejemplo.com" {
+		t.Errorf("msg = %v", campos["msg"])
+	}
+	if detalle := campos["detalle"].(string); strings.Contains(detalle, "2233") {
+		t.Errorf("el teléfono aparece en el detalle: %s", detalle)
+	}
+}
+
+func TestRedactaDentroDeLosGrupos(t *testing.T) {
+	logger, salida := loggerDePrueba(t, RedaccionEnmascarar)
+
+	logger.Info("Usuario", slog.Group("usuario", "email", correoPrueba, slog.Group("contacto", "nota", "escribir a "+correoPrueba)))
+
+	sinCorreo(t, salida)
+	usuario := registro(t, salida)["usuario"].(map[string]any)
+	if usuario["email"] != "[oculto:21]" {
+		t.Errorf("usuario.email = %v", usuario["email"])
+	}
+	if nota := usuario["contacto"].(map[string]any)["nota"]; nota != "escribir a a***@ejemplo.com" {
+		t.Errorf("usuario.contacto.nota = %v", nota)
+	}
+}
+
+func TestRedactaEstructurasMapasYListas(t *testing.T) {
+	type destino struct {
+		Correo string `json:"correo"`
+		Notas  []string
+		Dias   int `json:"dias"`
+	}
+	logger, salida := loggerDePrueba(t, RedaccionEnmascarar)
+
+	logger.Info("Destinos",
+		"destino", destino{Correo: correoPrueba, Notas: []string{"copia a " + correoPrueba}, Dias: 3},
+		"extra", map[string]any{"Token": "abc123", "intentos": 2},
+	)
+
+	sinCorreo(t, salida)
+	campos := registro(t, salida)
+	destinoEmitido := campos["destino"].(map[string]any)
+	if destinoEmitido["correo"] != "[oculto:21]" {
+		t.Errorf("destino.correo = %v", destinoEmitido["correo"])
+	}
+	if nota := destinoEmitido["Notas"].([]any)[0]; nota != "copia a a***@ejemplo.com" {
+		t.Errorf("destino.Notas[0] = %v", nota)
+	}
+	if destinoEmitido["dias"] != float64(3) {
+		t.Errorf("destino.dias = %v, se esperaba 3", destinoEmitido["dias"])
+	}
+	extra := campos["extra"].(map[string]any)
+	if extra["Token"] != "[oculto:6]" || extra["intentos"] != float64(2) {
+		t.Errorf("extra = %v", extra)
+	}
+}
+
+func TestRedactaLosAtributosAgregadosConCon(t *testing.T) {
+	logger, salida := loggerDePrueba(t, RedaccionEnmascarar)
+
+	logger.Componente(ComponenteHTTP).Con("destinatario", correoPrueba, "ruta", "/usuarios/"+correoPrueba).Info("Petición")
+
+	sinCorreo(t, salida)
+	campos := registro(t, salida)
+	if campos["destinatario"] != "[oculto:21]" || campos["ruta"] != "/usuarios/a***@ejemplo.com" {
+		t.Errorf("campos = %v", campos)
+	}
+}
+
+func TestRedactaLosErrores(t *testing.T) {
+	logger, salida := loggerDePrueba(t, RedaccionEnmascarar)
+
+	logger.Error("Falló el envío", "error", errors.New("buzón lleno: "+correoPrueba))
+
+	sinCorreo(t, salida)
+	if err := registro(t, salida)["error"]; err != "buzón lleno: a***@ejemplo.com" {
+		t.Errorf("error = %v", err)
+	}
+}
+
+func TestRedaccionConHashPermiteCorrelacionar(t *testing.T) {
+	logger, salida := loggerDePrueba(t, RedaccionHash)
+
+	logger.Info("Envío", "correo", correoPrueba, "detalle", map[string]string{"email": correoPrueba})
+
+	sinCorreo(t, salida)
+	campos := registro(t, salida)
+	if huellaCampo := campos["correo"].(string); !strings.HasPrefix(huellaCampo, "sha256:") {
+		t.Fatalf("correo = %v, se esperaba una huella", huellaCampo)
+	}
+	if anidado := campos["detalle"].(map[string]any)["email"]; anidado != campos["correo"] {
+		t.Errorf("la huella anidada %v no coincide con %v", anidado, campos["correo"])
+	}
+}
+
+func TestRedaccionDesactivadaEmiteLosValores(t *testing.T) {
+	logger, salida := loggerDePrueba(t, RedaccionDesactivada)
+
+	logger.Info("Envío", "correo", correoPrueba)
+
+	if registro(t, salida)["correo"] != correoPrueba {
+		t.Errorf("se esperaba el correo sin redactar: %s", salida.String())
+	}
+}
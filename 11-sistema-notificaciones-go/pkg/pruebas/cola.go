@@ -0,0 +1,84 @@
+package pruebas
+
+import (
+	"context"
+	"sync"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// Procesador es quien consume la cola: el orquestador o el despachador de notificaciones
+type Procesador interface {
+	Procesar(ctx context.Context, notificacion *entidad.Notificacion) error
+}
+
+// ColaMemoria implementa ColaMensajes sin trabajadores: lo publicado queda retenido hasta que
+// la prueba lo procesa con Drenar, así el flujo completo corre en la goroutine de la prueba
+type ColaMemoria struct {
+	mu         sync.Mutex
+	pendientes []*entidad.Notificacion
+	publicadas int
+	err        error
+}
+
+// NuevaColaMemoria crea una cola vacía
+func NuevaColaMemoria() *ColaMemoria {
+	return &ColaMemoria{}
+}
+
+// Publicar encola la notificación
+func (c *ColaMemoria) Publicar(ctx context.Context, notificacion *entidad.Notificacion) error {
+	return c.PublicarLote(ctx, []*entidad.Notificacion{notificacion})
+}
+
+// PublicarLote encola las notificaciones en orden
+func (c *ColaMemoria) PublicarLote(_ context.Context, notificaciones []*entidad.Notificacion) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	c.pendientes = append(c.pendientes, notificaciones...)
+	c.publicadas += len(notificaciones)
+	return nil
+}
+
+// FallarCon hace fallar las publicaciones con el error indicado; nil lo restablece
+func (c *ColaMemoria) FallarCon(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.err = err
+}
+
+// Pendientes retorna las notificaciones publicadas que aún no se procesaron
+func (c *ColaMemoria) Pendientes() []*entidad.Notificacion {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*entidad.Notificacion(nil), c.pendientes...)
+}
+
+// Publicadas retorna cuántas notificaciones se publicaron en total
+func (c *ColaMemoria) Publicadas() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.publicadas
+}
+
+// Drenar procesa las pendientes en orden de publicación, incluidas las que se publiquen
+// mientras tanto, y se detiene en el primer error dejando el resto en la cola
+func (c *ColaMemoria) Drenar(ctx context.Context, procesador Procesador) error {
+	for {
+		c.mu.Lock()
+		if len(c.pendientes) == 0 {
+			c.mu.Unlock()
+			return nil
+		}
+		notificacion := c.pendientes[0]
+		c.pendientes = c.pendientes[1:]
+		c.mu.Unlock()
+
+		if err := procesador.Procesar(ctx, notificacion); err != nil {
+			return err
+		}
+	}
+}
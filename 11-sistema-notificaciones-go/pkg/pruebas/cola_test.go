@@ -0,0 +1,114 @@
+package pruebas
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// procesadorFalso registra lo procesado y puede publicar más o fallar en un ID
+type procesadorFalso struct {
+	procesadas []uint
+	alProcesar func(notificacion *entidad.Notificacion) error
+}
+
+func (p *procesadorFalso) Procesar(_ context.Context, notificacion *entidad.Notificacion) error {
+	p.procesadas = append(p.procesadas, notificacion.ID)
+	if p.alProcesar != nil {
+		return p.alProcesar(notificacion)
+	}
+	return nil
+}
+
+func TestDrenarProcesaEnOrdenIncluidasLasPublicadasMientrasTanto(t *testing.T) {
+	ctx := context.Background()
+	cola := NuevaColaMemoria()
+	cola.Publicar(ctx, UnaNotificacion().ConID(1).Construir())
+	cola.Publicar(ctx, UnaNotificacion().ConID(2).Construir())
+	procesador := &procesadorFalso{}
+	procesador.alProcesar = func(notificacion *entidad.Notificacion) error {
+		if notificacion.ID == 1 {
+			return cola.Publicar(ctx, UnaNotificacion().ConID(3).Construir())
+		}
+		return nil
+	}
+
+	if err := cola.Drenar(ctx, procesador); err != nil {
+		t.Fatalf("drenando: %v", err)
+	}
+
+	if len(procesador.procesadas) != 3 || procesador.procesadas[0] != 1 || procesador.procesadas[1] != 2 || procesador.procesadas[2] != 3 {
+		t.Errorf("procesadas = %v, se esperaba [1 2 3]", procesador.procesadas)
+	}
+	if cola.Publicadas() != 3 || len(cola.Pendientes()) != 0 {
+		t.Errorf("publicadas = %d, pendientes = %d", cola.Publicadas(), len(cola.Pendientes()))
+	}
+}
+
+func TestDrenarSeDetieneEnElPrimerError(t *testing.T) {
+	ctx := context.Background()
+	cola := NuevaColaMemoria()
+	for id := uint(1); id <= 3; id++ {
+		cola.Publicar(ctx, UnaNotificacion().ConID(id).Construir())
+	}
+	errProcesar := errors.New("falla al procesar")
+	procesador := &procesadorFalso{alProcesar: func(notificacion *entidad.Notificacion) error {
+		if notificacion.ID == 2 {
+			return errProcesar
+		}
+		return nil
+	}}
+
+	if err := cola.Drenar(ctx, procesador); !errors.Is(err, errProcesar) {
+		t.Fatalf("err = %v, se esperaba %v", err, errProcesar)
+	}
+	if pendientes := cola.Pendientes(); len(pendientes) != 1 || pendientes[0].ID != 3 {
+		t.Errorf("quedaron %d pendientes, se esperaba solo la 3", len(pendientes))
+	}
+}
+
+func TestColaQueFallaNoRetieneLoPublicado(t *testing.T) {
+	ctx := context.Background()
+	cola := NuevaColaMemoria()
+	errCola := errors.New("cola caída")
+	cola.FallarCon(errCola)
+
+	if err := cola.Publicar(ctx, UnaNotificacion().Construir()); !errors.Is(err, errCola) {
+		t.Fatalf("err = %v, se esperaba %v", err, errCola)
+	}
+	if cola.Publicadas() != 0 {
+		t.Errorf("publicadas = %d, se esperaba 0", cola.Publicadas())
+	}
+
+	cola.FallarCon(nil)
+	if err := cola.Publicar(ctx, UnaNotificacion().Construir()); err != nil {
+		t.Errorf("tras restablecer: %v", err)
+	}
+}
+
+func TestConstruirRetornaCopiasIndependientes(t *testing.T) {
+	constructor := UnaNotificacion().ConMetadato("origen", "prueba")
+	primera := constructor.Construir()
+	primera.EstablecerMetadato("origen", "modificada")
+	primera.Titulo = "Otro título"
+
+	segunda := constructor.Construir()
+	if segunda.Metadatos["origen"] != "prueba" || segunda.Titulo == "Otro título" {
+		t.Errorf("la segunda notificación comparte datos con la primera: %+v", segunda)
+	}
+	if err := segunda.Validar(); err != nil {
+		t.Errorf("la notificación por defecto no es válida: %v", err)
+	}
+}
+
+func TestUsuariosConIDsDistintosNoChocan(t *testing.T) {
+	uno := UnUsuario().ConID(1).Construir()
+	dos := UnUsuario().ConID(2).Construir()
+
+	if uno.NombreUsuario == dos.NombreUsuario || uno.CorreoElectronico == dos.CorreoElectronico {
+		t.Errorf("los usuarios comparten nombre o correo: %q/%q, %q/%q",
+			uno.NombreUsuario, dos.NombreUsuario, uno.CorreoElectronico, dos.CorreoElectronico)
+	}
+}
@@ -0,0 +1,196 @@
+package pruebas
+
+import (
+	"fmt"
+	"time"
+
+	"sistema-notificaciones-go/internal/dominio/entidad"
+)
+
+// ConstructorNotificacion arma notificaciones válidas cambiando solo lo que la prueba necesita
+type ConstructorNotificacion struct {
+	notificacion entidad.Notificacion
+}
+
+// UnaNotificacion inicia una notificación pendiente por correo para el usuario 1
+func UnaNotificacion() *ConstructorNotificacion {
+	return &ConstructorNotificacion{notificacion: *entidad.NuevaNotificacion(1, "Título de prueba", "Mensaje de prueba", entidad.TipoEmail)}
+}
+
+// ConID fija el ID, como si la notificación ya estuviera guardada
+func (c *ConstructorNotificacion) ConID(id uint) *ConstructorNotificacion {
+	c.notificacion.ID = id
+	return c
+}
+
+// DeInquilino fija el inquilino dueño de la notificación
+func (c *ConstructorNotificacion) DeInquilino(inquilinoID uint) *ConstructorNotificacion {
+	c.notificacion.InquilinoID = inquilinoID
+	return c
+}
+
+// ParaUsuario fija el destinatario
+func (c *ConstructorNotificacion) ParaUsuario(usuarioID uint) *ConstructorNotificacion {
+	c.notificacion.UsuarioID = usuarioID
+	return c
+}
+
+// DeTipo fija el tipo de notificación
+func (c *ConstructorNotificacion) DeTipo(tipo entidad.TipoNotificacion) *ConstructorNotificacion {
+	c.notificacion.Tipo = tipo
+	return c
+}
+
+// ConPrioridad fija la prioridad
+func (c *ConstructorNotificacion) ConPrioridad(prioridad entidad.PrioridadNotificacion) *ConstructorNotificacion {
+	c.notificacion.Prioridad = prioridad
+	return c
+}
+
+// ConEstado fija el estado sin pasar por las transiciones de la entidad
+func (c *ConstructorNotificacion) ConEstado(estado entidad.EstadoNotificacion) *ConstructorNotificacion {
+	c.notificacion.Estado = estado
+	return c
+}
+
+// EnCanal asocia la notificación a un canal
+func (c *ConstructorNotificacion) EnCanal(canalID uint) *ConstructorNotificacion {
+	c.notificacion.CanalID = canalID
+	return c
+}
+
+// ConContenido fija título y mensaje
+func (c *ConstructorNotificacion) ConContenido(titulo, mensaje string) *ConstructorNotificacion {
+	c.notificacion.Titulo, c.notificacion.Mensaje = titulo, mensaje
+	return c
+}
+
+// ConMetadato agrega un metadato
+func (c *ConstructorNotificacion) ConMetadato(clave string, valor interface{}) *ConstructorNotificacion {
+	c.notificacion.EstablecerMetadato(clave, valor)
+	return c
+}
+
+// ProgramadaPara programa la notificación para el instante indicado
+func (c *ConstructorNotificacion) ProgramadaPara(instante time.Time) *ConstructorNotificacion {
+	c.notificacion.FechaProgramada = &instante
+	return c
+}
+
+// Construir retorna una notificación nueva en cada llamada
+func (c *ConstructorNotificacion) Construir() *entidad.Notificacion {
+	notificacion := c.notificacion
+	notificacion.Metadatos = make(map[string]interface{}, len(c.notificacion.Metadatos))
+	for clave, valor := range c.notificacion.Metadatos {
+		notificacion.Metadatos[clave] = valor
+	}
+	return &notificacion
+}
+
+// ConstructorUsuario arma usuarios válidos cambiando solo lo que la prueba necesita
+type ConstructorUsuario struct {
+	usuario entidad.Usuario
+}
+
+// UnUsuario inicia un usuario activo con rol usuario y correo verificado
+func UnUsuario() *ConstructorUsuario {
+	usuario := entidad.NuevoUsuario("usuario_prueba", "usuario@ejemplo.com", "Usuario", "Prueba")
+	usuario.VerificarCorreo()
+	return &ConstructorUsuario{usuario: *usuario}
+}
+
+// ConID fija el ID y deriva de él el nombre de usuario y el correo, así varios usuarios
+// construidos con IDs distintos no chocan en los índices únicos
+func (c *ConstructorUsuario) ConID(id uint) *ConstructorUsuario {
+	c.usuario.ID = id
+	c.usuario.NombreUsuario = fmt.Sprintf("usuario_%d", id)
+	c.usuario.CorreoElectronico = fmt.Sprintf("usuario%d@ejemplo.com", id)
+	return c
+}
+
+// DeInquilino fija el inquilino del usuario
+func (c *ConstructorUsuario) DeInquilino(inquilinoID uint) *ConstructorUsuario {
+	c.usuario.InquilinoID = inquilinoID
+	return c
+}
+
+// ConCorreo fija el correo electrónico
+func (c *ConstructorUsuario) ConCorreo(correo string) *ConstructorUsuario {
+	c.usuario.CorreoElectronico = correo
+	return c
+}
+
+// ConTelefono fija un teléfono verificado
+func (c *ConstructorUsuario) ConTelefono(telefono string) *ConstructorUsuario {
+	c.usuario.Telefono = telefono
+	c.usuario.VerificarTelefono()
+	return c
+}
+
+// ConRol fija el rol
+func (c *ConstructorUsuario) ConRol(rol entidad.RolUsuario) *ConstructorUsuario {
+	c.usuario.CambiarRol(rol)
+	return c
+}
+
+// Inactivo desactiva al usuario
+func (c *ConstructorUsuario) Inactivo() *ConstructorUsuario {
+	c.usuario.Desactivar()
+	return c
+}
+
+// Construir retorna un usuario nuevo en cada llamada
+func (c *ConstructorUsuario) Construir() *entidad.Usuario {
+	usuario := c.usuario
+	return &usuario
+}
+
+// ConstructorCanal arma canales válidos cambiando solo lo que la prueba necesita
+type ConstructorCanal struct {
+	canal entidad.Canal
+}
+
+// UnCanal inicia un canal general activo de la plataforma
+func UnCanal() *ConstructorCanal {
+	return &ConstructorCanal{canal: *entidad.NuevoCanal("Canal de prueba", "Canal creado en pruebas", entidad.TipoCanalGeneral)}
+}
+
+// ConID fija el ID, como si el canal ya estuviera guardado
+func (c *ConstructorCanal) ConID(id uint) *ConstructorCanal {
+	c.canal.ID = id
+	return c
+}
+
+// DeInquilino fija el inquilino dueño del canal
+func (c *ConstructorCanal) DeInquilino(inquilinoID uint) *ConstructorCanal {
+	c.canal.InquilinoID = inquilinoID
+	return c
+}
+
+// DeTipo fija el tipo de canal
+func (c *ConstructorCanal) DeTipo(tipo entidad.TipoCanal) *ConstructorCanal {
+	c.canal.Tipo = tipo
+	return c
+}
+
+// ConNombre fija el nombre del canal
+func (c *ConstructorCanal) ConNombre(nombre string) *ConstructorCanal {
+	c.canal.Nombre = nombre
+	return c
+}
+
+// Inactivo desactiva el canal
+func (c *ConstructorCanal) Inactivo() *ConstructorCanal {
+	c.canal.Desactivar()
+	return c
+}
+
+// Construir retorna un canal nuevo en cada llamada
+func (c *ConstructorCanal) Construir() *entidad.Canal {
+	canal := c.canal
+	canal.Configuracion = make(map[string]interface{}, len(c.canal.Configuracion))
+	for clave, valor := range c.canal.Configuracion {
+		canal.Configuracion[clave] = valor
+	}
+	return &canal
+}
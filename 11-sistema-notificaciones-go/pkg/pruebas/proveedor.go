@@ -0,0 +1,39 @@
+package pruebas
+
+import (
+	"sistema-notificaciones-go/internal/dominio/entidad"
+	"sistema-notificaciones-go/internal/infraestructura/simulacion"
+)
+
+// ProveedorFalso es el proveedor en memoria del modo sandbox: registra los envíos y falla
+// según lo que programe la prueba
+type ProveedorFalso = simulacion.ProveedorSimulado
+
+// NuevoProveedorFalso crea un proveedor del tipo sin envíos registrados
+func NuevoProveedorFalso(tipo entidad.TipoNotificacion) *ProveedorFalso {
+	return simulacion.NuevoProveedorSimulado(tipo)
+}
+
+// Fallas y latencia que un ProveedorFalso puede inyectar en cada envío
+type (
+	ModoFalla              = simulacion.ModoFalla
+	ErrorProveedorSimulado = simulacion.ErrorProveedorSimulado
+	DistribucionLatencia   = simulacion.DistribucionLatencia
+	Latencia               = simulacion.Latencia
+	ConfiguracionFallas    = simulacion.ConfiguracionFallas
+)
+
+const (
+	FallaTiempoAgotado  = simulacion.FallaTiempoAgotado
+	FallaErrorServidor  = simulacion.FallaErrorServidor
+	FallaTokenInvalido  = simulacion.FallaTokenInvalido
+	FallaLimiteTasa     = simulacion.FallaLimiteTasa
+	LatenciaFija        = simulacion.LatenciaFija
+	LatenciaUniforme    = simulacion.LatenciaUniforme
+	LatenciaExponencial = simulacion.LatenciaExponencial
+)
+
+// NuevoErrorSimulado crea el error que produce el modo indicado
+func NuevoErrorSimulado(modo ModoFalla) *ErrorProveedorSimulado {
+	return simulacion.NuevoErrorSimulado(modo)
+}
@@ -0,0 +1,19 @@
+// Package pruebas reúne dobles de prueba para ejercitar los flujos de notificación sin
+// Docker: proveedores y cola en memoria, un reloj controlable y constructores de entidades
+// con valores válidos por defecto.
+package pruebas
+
+import (
+	"time"
+
+	"sistema-notificaciones-go/pkg/reloj"
+)
+
+// InstanteInicial es el instante en que arrancan los relojes de prueba; fijo para que los
+// resultados no dependan de la hora en que corren las pruebas
+var InstanteInicial = time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC)
+
+// NuevoReloj crea un reloj detenido en InstanteInicial que solo avanza cuando se le indica
+func NuevoReloj() *reloj.Controlable {
+	return reloj.NuevoRelojControlable(InstanteInicial)
+}
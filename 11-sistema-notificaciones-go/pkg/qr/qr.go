@@ -0,0 +1,475 @@
+// Package qr genera códigos QR (ISO/IEC 18004) en modo byte con corrección de errores de nivel
+// M, que recupera hasta el 15% del código: suficiente para una entrada impresa o en pantalla.
+package qr
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// margen es la zona silenciosa de cuatro módulos que exige la norma alrededor del código
+const margen = 4
+
+// ErrDatosDemasiadoLargos indica que el contenido no entra en un QR de versión 40
+var ErrDatosDemasiadoLargos = errors.New("el contenido no entra en un código QR")
+
+// bloquesM describe, por versión, los bloques de nivel M: códigos de corrección por bloque y
+// cantidad y tamaño de datos de los dos grupos de bloques
+var bloquesM = [41][5]int{
+	{},
+	{10, 1, 16, 0, 0}, {16, 1, 28, 0, 0}, {26, 1, 44, 0, 0}, {18, 2, 32, 0, 0}, {24, 2, 43, 0, 0},
+	{16, 4, 27, 0, 0}, {18, 4, 31, 0, 0}, {22, 2, 38, 2, 39}, {22, 3, 36, 2, 37}, {26, 4, 43, 1, 44},
+	{30, 1, 50, 4, 51}, {22, 6, 36, 2, 37}, {22, 8, 37, 1, 38}, {24, 4, 40, 5, 41}, {24, 5, 41, 5, 42},
+	{28, 7, 45, 3, 46}, {28, 10, 46, 1, 47}, {26, 9, 43, 4, 44}, {26, 3, 44, 11, 45}, {26, 3, 41, 13, 42},
+	{26, 17, 42, 0, 0}, {28, 17, 46, 0, 0}, {28, 4, 47, 14, 48}, {28, 6, 45, 14, 46}, {28, 8, 47, 13, 48},
+	{28, 19, 46, 4, 47}, {28, 22, 45, 3, 46}, {28, 3, 45, 23, 46}, {28, 21, 45, 7, 46}, {28, 19, 47, 10, 48},
+	{28, 2, 46, 29, 47}, {28, 10, 46, 23, 47}, {28, 14, 46, 21, 47}, {28, 14, 46, 23, 47}, {28, 12, 47, 26, 48},
+	{28, 6, 47, 34, 48}, {28, 29, 46, 14, 47}, {28, 13, 46, 32, 47}, {28, 40, 47, 7, 48}, {28, 18, 47, 31, 48},
+}
+
+// Codigo es la matriz de módulos de un código QR
+type Codigo struct {
+	version int
+	tamano  int
+	modulos [][]bool
+	funcion [][]bool
+}
+
+// Codificar genera el código QR más chico que contiene los datos
+func Codificar(datos []byte) (*Codigo, error) {
+	version := 0
+	for v := 1; v <= 40; v++ {
+		if bitsDatos(v, len(datos)) <= capacidadDatos(v)*8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrDatosDemasiadoLargos
+	}
+
+	c := &Codigo{version: version, tamano: version*4 + 17}
+	c.modulos, c.funcion = matriz(c.tamano), matriz(c.tamano)
+	c.dibujarPatrones()
+	c.dibujarCodigos(intercalar(version, segmento(version, datos)))
+
+	mejor, menor := 0, -1
+	for mascara := 0; mascara < 8; mascara++ {
+		c.aplicarMascara(mascara)
+		c.dibujarFormato(mascara)
+		if penalidad := c.penalidad(); menor < 0 || penalidad < menor {
+			mejor, menor = mascara, penalidad
+		}
+		// La máscara es un XOR: aplicarla otra vez la quita
+		c.aplicarMascara(mascara)
+	}
+	c.aplicarMascara(mejor)
+	c.dibujarFormato(mejor)
+	return c, nil
+}
+
+// Tamano es la cantidad de módulos por lado, sin la zona silenciosa
+func (c *Codigo) Tamano() int {
+	return c.tamano
+}
+
+// Oscuro indica si el módulo de la columna x y la fila y es oscuro
+func (c *Codigo) Oscuro(x, y int) bool {
+	return x >= 0 && y >= 0 && x < c.tamano && y < c.tamano && c.modulos[y][x]
+}
+
+// PNG dibuja el código en blanco y negro con escala píxeles por módulo y la zona silenciosa
+func (c *Codigo) PNG(escala int) ([]byte, error) {
+	lado := (c.tamano + 2*margen) * escala
+	imagen := image.NewPaletted(image.Rect(0, 0, lado, lado), color.Palette{color.White, color.Black})
+	for y := 0; y < lado; y++ {
+		for x := 0; x < lado; x++ {
+			if c.Oscuro(x/escala-margen, y/escala-margen) {
+				imagen.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+	var salida bytes.Buffer
+	if err := png.Encode(&salida, imagen); err != nil {
+		return nil, err
+	}
+	return salida.Bytes(), nil
+}
+
+func matriz(tamano int) [][]bool {
+	filas := make([][]bool, tamano)
+	for i := range filas {
+		filas[i] = make([]bool, tamano)
+	}
+	return filas
+}
+
+// bitsDatos son los bits del segmento en modo byte: indicador de modo, longitud y datos
+func bitsDatos(version, longitud int) int {
+	return 4 + bitsLongitud(version) + 8*longitud
+}
+
+func bitsLongitud(version int) int {
+	if version < 10 {
+		return 8
+	}
+	return 16
+}
+
+// modulosDatos son los módulos disponibles para datos y corrección, descontados los patrones
+func modulosDatos(version int) int {
+	resultado := (16*version+128)*version + 64
+	if version >= 2 {
+		alineacion := version/7 + 2
+		resultado -= (25*alineacion-10)*alineacion - 55
+		if version >= 7 {
+			resultado -= 36
+		}
+	}
+	return resultado
+}
+
+func capacidadDatos(version int) int {
+	b := bloquesM[version]
+	return b[1]*b[2] + b[3]*b[4]
+}
+
+// segmento arma los códigos de datos: modo byte, longitud, datos, terminador y relleno
+func segmento(version int, datos []byte) []byte {
+	var bits escritorBits
+	bits.escribir(0b0100, 4)
+	bits.escribir(len(datos), bitsLongitud(version))
+	for _, b := range datos {
+		bits.escribir(int(b), 8)
+	}
+	capacidad := capacidadDatos(version) * 8
+	bits.escribir(0, min(4, capacidad-bits.n))
+	bits.escribir(0, (8-bits.n%8)%8)
+	for relleno := 0xEC; bits.n < capacidad; relleno ^= 0xEC ^ 0x11 {
+		bits.escribir(relleno, 8)
+	}
+	return bits.datos
+}
+
+type escritorBits struct {
+	datos []byte
+	n     int
+}
+
+func (e *escritorBits) escribir(valor, cantidad int) {
+	for i := cantidad - 1; i >= 0; i-- {
+		if e.n%8 == 0 {
+			e.datos = append(e.datos, 0)
+		}
+		if (valor>>i)&1 == 1 {
+			e.datos[e.n/8] |= 0x80 >> (e.n % 8)
+		}
+		e.n++
+	}
+}
+
+// intercalar divide los datos en bloques, agrega a cada uno su corrección Reed-Solomon e
+// intercala los bloques byte a byte como pide la norma
+func intercalar(version int, datos []byte) []byte {
+	b := bloquesM[version]
+	divisor := divisorReedSolomon(b[0])
+	var bloques, correcciones [][]byte
+	for grupo, inicio := 0, 0; grupo < 2; grupo++ {
+		cantidad, largo := b[1+grupo*2], b[2+grupo*2]
+		for i := 0; i < cantidad; i++ {
+			bloque := datos[inicio : inicio+largo]
+			bloques = append(bloques, bloque)
+			correcciones = append(correcciones, restoReedSolomon(bloque, divisor))
+			inicio += largo
+		}
+	}
+
+	resultado := make([]byte, 0, modulosDatos(version)/8)
+	for i := 0; i < max(b[2], b[4]); i++ {
+		for _, bloque := range bloques {
+			if i < len(bloque) {
+				resultado = append(resultado, bloque[i])
+			}
+		}
+	}
+	for i := 0; i < b[0]; i++ {
+		for _, correccion := range correcciones {
+			resultado = append(resultado, correccion[i])
+		}
+	}
+	return resultado
+}
+
+// multiplicar multiplica en GF(2^8) con el polinomio x^8 + x^4 + x^3 + x^2 + 1
+func multiplicar(x, y byte) byte {
+	var z int
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>i)&1) * int(x)
+	}
+	return byte(z)
+}
+
+func divisorReedSolomon(grado int) []byte {
+	resultado := make([]byte, grado)
+	resultado[grado-1] = 1
+	raiz := byte(1)
+	for i := 0; i < grado; i++ {
+		for j := range resultado {
+			resultado[j] = multiplicar(resultado[j], raiz)
+			if j+1 < len(resultado) {
+				resultado[j] ^= resultado[j+1]
+			}
+		}
+		raiz = multiplicar(raiz, 0x02)
+	}
+	return resultado
+}
+
+func restoReedSolomon(datos, divisor []byte) []byte {
+	resultado := make([]byte, len(divisor))
+	for _, b := range datos {
+		factor := b ^ resultado[0]
+		copy(resultado, resultado[1:])
+		resultado[len(resultado)-1] = 0
+		for i := range resultado {
+			resultado[i] ^= multiplicar(divisor[i], factor)
+		}
+	}
+	return resultado
+}
+
+func (c *Codigo) fijar(x, y int, oscuro bool) {
+	c.modulos[y][x] = oscuro
+	c.funcion[y][x] = true
+}
+
+// dibujarPatrones dibuja los patrones fijos y reserva el lugar del formato y la versión
+func (c *Codigo) dibujarPatrones() {
+	for i := 0; i < c.tamano; i++ {
+		c.fijar(6, i, i%2 == 0)
+		c.fijar(i, 6, i%2 == 0)
+	}
+	for _, centro := range [][2]int{{3, 3}, {c.tamano - 4, 3}, {3, c.tamano - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := centro[0]+dx, centro[1]+dy
+				if x >= 0 && y >= 0 && x < c.tamano && y < c.tamano {
+					distancia := max(abs(dx), abs(dy))
+					c.fijar(x, y, distancia != 2 && distancia != 4)
+				}
+			}
+		}
+	}
+
+	posiciones := c.posicionesAlineacion()
+	for i, y := range posiciones {
+		for j, x := range posiciones {
+			// Las esquinas ocupadas por los patrones de búsqueda no llevan alineación
+			ultima := len(posiciones) - 1
+			if (i == 0 && j == 0) || (i == 0 && j == ultima) || (i == ultima && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					c.fijar(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	c.dibujarFormato(0)
+	c.dibujarVersion()
+}
+
+func (c *Codigo) posicionesAlineacion() []int {
+	if c.version == 1 {
+		return nil
+	}
+	cantidad := c.version/7 + 2
+	paso := (c.version*4 + cantidad*2 + 1) / (cantidad*2 - 2) * 2
+	if c.version == 32 {
+		paso = 26
+	}
+	posiciones := make([]int, cantidad)
+	posiciones[0] = 6
+	for i, pos := cantidad-1, c.tamano-7; i >= 1; i, pos = i-1, pos-paso {
+		posiciones[i] = pos
+	}
+	return posiciones
+}
+
+// dibujarFormato escribe el nivel de corrección (M) y la máscara con su código BCH, dos veces
+func (c *Codigo) dibujarFormato(mascara int) {
+	datos := mascara // el nivel M se codifica como 00
+	resto := datos
+	for i := 0; i < 10; i++ {
+		resto = (resto << 1) ^ ((resto >> 9) * 0x537)
+	}
+	bits := (datos<<10 | resto) ^ 0x5412
+	bit := func(i int) bool { return (bits>>i)&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		c.fijar(8, i, bit(i))
+	}
+	c.fijar(8, 7, bit(6))
+	c.fijar(8, 8, bit(7))
+	c.fijar(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		c.fijar(14-i, 8, bit(i))
+	}
+	for i := 0; i < 8; i++ {
+		c.fijar(c.tamano-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		c.fijar(8, c.tamano-15+i, bit(i))
+	}
+	c.fijar(8, c.tamano-8, true)
+}
+
+// dibujarVersion escribe la versión con su código BCH a partir de la versión 7
+func (c *Codigo) dibujarVersion() {
+	if c.version < 7 {
+		return
+	}
+	resto := c.version
+	for i := 0; i < 12; i++ {
+		resto = (resto << 1) ^ ((resto >> 11) * 0x1F25)
+	}
+	bits := c.version<<12 | resto
+	for i := 0; i < 18; i++ {
+		oscuro := (bits>>i)&1 == 1
+		a, b := c.tamano-11+i%3, i/3
+		c.fijar(a, b, oscuro)
+		c.fijar(b, a, oscuro)
+	}
+}
+
+// dibujarCodigos recorre la matriz en zigzag de a dos columnas, de derecha a izquierda,
+// salteando la columna del patrón de tiempo
+func (c *Codigo) dibujarCodigos(datos []byte) {
+	i := 0
+	for derecha := c.tamano - 1; derecha >= 1; derecha -= 2 {
+		if derecha == 6 {
+			derecha = 5
+		}
+		for vertical := 0; vertical < c.tamano; vertical++ {
+			for j := 0; j < 2; j++ {
+				x := derecha - j
+				y := vertical
+				if (derecha+1)&2 == 0 {
+					y = c.tamano - 1 - vertical
+				}
+				if !c.funcion[y][x] && i < len(datos)*8 {
+					c.modulos[y][x] = (datos[i>>3]>>(7-i&7))&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+func (c *Codigo) aplicarMascara(mascara int) {
+	for y := 0; y < c.tamano; y++ {
+		for x := 0; x < c.tamano; x++ {
+			if c.funcion[y][x] {
+				continue
+			}
+			var invertir bool
+			switch mascara {
+			case 0:
+				invertir = (x+y)%2 == 0
+			case 1:
+				invertir = y%2 == 0
+			case 2:
+				invertir = x%3 == 0
+			case 3:
+				invertir = (x+y)%3 == 0
+			case 4:
+				invertir = (x/3+y/2)%2 == 0
+			case 5:
+				invertir = x*y%2+x*y%3 == 0
+			case 6:
+				invertir = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invertir = ((x+y)%2+x*y%3)%2 == 0
+			}
+			c.modulos[y][x] = c.modulos[y][x] != invertir
+		}
+	}
+}
+
+// patronesBusqueda son la secuencia 1:1:3:1:1 con cuatro módulos claros de un lado, que un lector
+// podría confundir con un patrón de búsqueda
+var patronesBusqueda = [][]bool{
+	{true, false, true, true, true, false, true, false, false, false, false},
+	{false, false, false, false, true, false, true, true, true, false, true},
+}
+
+// penalidad puntúa la matriz con las cuatro reglas de la norma; gana la máscara de menor puntaje
+func (c *Codigo) penalidad() int {
+	resultado, oscuros := 0, 0
+	for _, horizontal := range []bool{true, false} {
+		for i := 0; i < c.tamano; i++ {
+			racha := 1
+			for j := 0; j < c.tamano; j++ {
+				modulo := c.en(horizontal, i, j)
+				if horizontal && modulo {
+					oscuros++
+				}
+				if j > 0 && modulo == c.en(horizontal, i, j-1) {
+					racha++
+				} else {
+					racha = 1
+				}
+				if racha == 5 {
+					resultado += 3
+				} else if racha > 5 {
+					resultado++
+				}
+				for _, patron := range patronesBusqueda {
+					if j+len(patron) <= c.tamano && c.coincide(horizontal, i, j, patron) {
+						resultado += 40
+					}
+				}
+			}
+		}
+	}
+	for y := 0; y+1 < c.tamano; y++ {
+		for x := 0; x+1 < c.tamano; x++ {
+			m := c.modulos[y][x]
+			if m == c.modulos[y][x+1] && m == c.modulos[y+1][x] && m == c.modulos[y+1][x+1] {
+				resultado += 3
+			}
+		}
+	}
+	total := c.tamano * c.tamano
+	k := (abs(oscuros*20-total*10)+total-1)/total - 1
+	return resultado + k*10
+}
+
+func (c *Codigo) en(horizontal bool, i, j int) bool {
+	if horizontal {
+		return c.modulos[i][j]
+	}
+	return c.modulos[j][i]
+}
+
+func (c *Codigo) coincide(horizontal bool, i, j int, patron []bool) bool {
+	for k, esperado := range patron {
+		if c.en(horizontal, i, j+k) != esperado {
+			return false
+		}
+	}
+	return true
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,57 @@
+package reloj
+
+import (
+	"sync"
+	"time"
+)
+
+// Reloj abstrae la hora actual para que la lógica dependiente del tiempo
+// (programación, TTLs, reintentos, horarios de silencio) pueda controlarse
+type Reloj interface {
+	Ahora() time.Time
+}
+
+// Sistema es el reloj real basado en time.Now
+type Sistema struct{}
+
+// NuevoRelojSistema crea un reloj que retorna la hora del sistema
+func NuevoRelojSistema() Sistema {
+	return Sistema{}
+}
+
+// Ahora retorna la hora actual del sistema
+func (Sistema) Ahora() time.Time {
+	return time.Now()
+}
+
+// Controlable es un reloj detenido que solo avanza cuando se le indica
+type Controlable struct {
+	mu    sync.RWMutex
+	ahora time.Time
+}
+
+// NuevoRelojControlable crea un reloj detenido en el instante indicado
+func NuevoRelojControlable(inicio time.Time) *Controlable {
+	return &Controlable{ahora: inicio}
+}
+
+// Ahora retorna el instante actual del reloj
+func (c *Controlable) Ahora() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ahora
+}
+
+// Avanzar mueve el reloj hacia adelante la duración indicada
+func (c *Controlable) Avanzar(duracion time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ahora = c.ahora.Add(duracion)
+}
+
+// Establecer fija el reloj en un instante concreto
+func (c *Controlable) Establecer(instante time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ahora = instante
+}